@@ -6,8 +6,9 @@ package docs
 //
 // Key Features:
 // - Custom Styles: Applies local stylesheets.
-// - Auto-Authorization: Intercepts the response from /api/tuya/auth, extracts the access_token,
-//   and programmatically triggers the Swagger UI authorization action with "Bearer <token>".
+// - Auto-Authorization: Intercepts the response from /api/tuya/auth, extracts the access_token
+//   (or session_id in session mode), and programmatically triggers the Swagger UI authorization
+//   action with "Bearer <token>".
 const CustomSwaggerHTML = `<!DOCTYPE html>
 <html lang="en">
   <head>
@@ -61,7 +62,7 @@ const CustomSwaggerHTML = `<!DOCTYPE html>
           layout: "StandaloneLayout",
           responseInterceptor: (response) => {
             // Check if this is the auth endpoint
-            if (response.url && response.url.indexOf("/api/tuya/auth") > -1 && response.status === 200) {
+            if (response.url && /\/api\/(v\d+\/)?tuya\/auth/.test(response.url) && response.status === 200) {
                 try {
                     console.log("Login detected, attempting to extract token...");
                     // Parse body if it isn't an object already
@@ -74,8 +75,8 @@ const CustomSwaggerHTML = `<!DOCTYPE html>
                     // Often response.obj is already populated by Swagger
                     const data = (body && body.data) || (response.obj && response.obj.data);
 
-                    if (data && data.access_token) {
-                        const token = data.access_token;
+                    if (data && (data.access_token || data.session_id)) {
+                        const token = data.access_token || data.session_id;
                         console.log("Token found:", token);
                         
                         // The security definition name in main.go is "BearerAuth"
@@ -24,29 +24,36 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/cache/flush": {
-            "delete": {
-                "security": [
-                    {
-                        "BearerAuth": []
-                    }
-                ],
-                "description": "Remove all data from the cache storage",
-                "consumes": [
-                    "application/json"
-                ],
+        "/api/admin/approval-rules": {
+            "get": {
+                "description": "Lists the devices whose commands require two-person approval.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "05. Flush"
+                    "08. Admin"
                 ],
-                "summary": "Flush all cache",
+                "summary": "List Approval Rules",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dtos.StandardResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.CommandApprovalRuleDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "500": {
@@ -55,17 +62,17 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/tuya/auth": {
-            "get": {
+                },
                 "security": [
                     {
                         "ApiKeyAuth": []
                     }
-                ],
-                "description": "Authenticates the user and retrieves a Tuya access token",
+                ]
+            }
+        },
+        "/api/admin/approval-rules/{device_id}": {
+            "put": {
+                "description": "Requires two-person approval for the listed DP codes of a device (every command when codes is empty). Matching commands are held as pending actions until another user approves them within ttl_seconds. IR remotes and hubs cannot be given rules, since IR commands are not held.",
                 "consumes": [
                     "application/json"
                 ],
@@ -73,9 +80,27 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "01. Auth"
+                    "08. Admin"
+                ],
+                "summary": "Set Approval Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Protected codes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SetCommandApprovalRuleRequestDTO"
+                        }
+                    }
                 ],
-                "summary": "Authenticate with Tuya",
                 "responses": {
                     "200": {
                         "description": "OK",
@@ -88,63 +113,96 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.TuyaAuthResponseDTO"
+                                            "$ref": "#/definitions/dtos.CommandApprovalRuleDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/tuya/devices": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "Retrieves a list of all devices. Response format depends on GET_ALL_DEVICES_RESPONSE_TYPE: 0 (Nested/Default), 1 (Flat), 2 (Merged). Sorted alphabetically by Name. For infrared_ac devices, the status array is populated with saved device state (power, temp, mode, wind) or default values if no state exists.",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            },
+            "delete": {
+                "description": "Sends the commands of a device directly again. Actions already pending can still be decided.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "02. Devices"
+                    "08. Admin"
                 ],
-                "summary": "Get All Devices",
+                "summary": "Delete Approval Rule",
                 "parameters": [
-                    {
-                        "type": "integer",
-                        "description": "Page number",
-                        "name": "page",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "description": "Items per page",
-                        "name": "limit",
-                        "in": "query"
-                    },
                     {
                         "type": "string",
-                        "description": "Filter by category",
-                        "name": "category",
-                        "in": "query"
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/archive/run": {
+            "post": {
+                "description": "Immediately exports sensor history and audit logs older than ARCHIVE_LOCAL_RETENTION to object storage as CSV, then deletes them locally. The export runs as a background job: poll GET /api/jobs/{job_id} for the ArchiveRunResultDTO, which is also kept on a failed job with what was exported before the failure.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Run Archive Export",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "allOf": [
                                 {
@@ -154,7 +212,7 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.TuyaDevicesResponseDTO"
+                                            "$ref": "#/definitions/dtos.ArchiveRunStartedDTO"
                                         }
                                     }
                                 }
@@ -166,40 +224,34 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
                     }
-                }
-            }
-        },
-        "/api/tuya/devices/{id}": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "Retrieves details of a specific device by its ID. Response includes last_commands field containing the last control commands sent to the device.",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/backup": {
+            "post": {
+                "description": "Builds a JSON archive of the persistent data of this deployment in a background job: device states, automations, scenes, schedules, metadata, webhooks and notification rules from BadgerDB, and the rooms of the SQL database. Cache data, sessions and cooldowns are left out. Poll GET /api/jobs/{job_id} until the job succeeded, then download the archive from GET /api/admin/backup/{job_id}. Archives are kept for JOB_RETENTION.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "02. Devices"
-                ],
-                "summary": "Get Device by ID",
-                "parameters": [
-                    {
-                        "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    }
+                    "08. Admin"
                 ],
+                "summary": "Start Backup",
                 "responses": {
-                    "200": {
-                        "description": "OK",
+                    "202": {
+                        "description": "Accepted",
                         "schema": {
                             "allOf": [
                                 {
@@ -209,73 +261,67 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.TuyaDeviceResponseDTO"
+                                            "$ref": "#/definitions/dtos.BackupExportStartedDTO"
                                         }
                                     }
                                 }
                             ]
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/tuya/devices/{id}/commands/ir": {
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "Sends an infrared command to an AC via a specific IR device",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/backup/{job_id}": {
+            "get": {
+                "description": "Downloads the archive built by a backup job started with POST /api/admin/backup. The archive is returned as is (not wrapped in the standard response) so it can be uploaded unchanged to POST /api/admin/restore.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "03. Device Control"
+                    "08. Admin"
                 ],
-                "summary": "Send IR AC Command",
+                "summary": "Download Backup",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Infrared Device ID",
-                        "name": "id",
+                        "description": "Backup job ID",
+                        "name": "job_id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "IR AC Command Payload",
-                        "name": "command",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.TuyaIRACCommandDTO"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BackupArchiveDTO"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "409": {
+                        "description": "Conflict",
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
@@ -286,50 +332,52 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/tuya/devices/{id}/commands/switch": {
-            "post": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "Sends a command to a specific Tuya device",
-                "consumes": [
-                    "application/json"
-                ],
+                ]
+            }
+        },
+        "/api/admin/claims": {
+            "get": {
+                "description": "Lists the claims of all tenants, newest first, optionally filtered by status.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "03. Device Control"
+                    "08. Admin"
                 ],
-                "summary": "Send Command to Device",
+                "summary": "List Device Claims",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device ID",
-                        "name": "id",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Command Payload",
-                        "name": "command",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dtos.TuyaCommandDTO"
-                        }
+                        "description": "pending, approved or rejected",
+                        "name": "status",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/dtos.StandardResponse"
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceClaimDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
                         }
                     },
                     "400": {
@@ -344,31 +392,74 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     }
-                }
-            }
-        },
-        "/api/tuya/devices/{id}/sensor": {
-            "get": {
+                },
                 "security": [
                     {
-                        "BearerAuth": []
+                        "ApiKeyAuth": []
                     }
-                ],
-                "description": "Retrieves sensor data (temperature, humidity, etc.) for a specific device",
-                "consumes": [
+                ]
+            }
+        },
+        "/api/admin/claims/devices/{id}": {
+            "delete": {
+                "description": "Removes the assignment of a device (e.g., when a tenant moves out) so it can be claimed again.",
+                "produces": [
                     "application/json"
                 ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Release Claimed Device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/claims/{id}/approve": {
+            "post": {
+                "description": "Assigns the device to the claiming tenant. Other pending claims on the device are rejected.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "04. Device Sensor"
+                    "08. Admin"
                 ],
-                "summary": "Get Sensor Data",
+                "summary": "Approve Device Claim",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Device ID",
+                        "description": "Claim ID",
                         "name": "id",
                         "in": "path",
                         "required": true
@@ -386,7 +477,82 @@ const docTemplate = `{
                                     "type": "object",
                                     "properties": {
                                         "data": {
-                                            "$ref": "#/definitions/dtos.SensorDataDTO"
+                                            "$ref": "#/definitions/dtos.DeviceClaimDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/claims/{id}/reject": {
+            "post": {
+                "description": "Rejects a pending claim. The reason is shown to the tenant.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Reject Device Claim",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Claim ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RejectDeviceClaimRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceClaimDTO"
                                         }
                                     }
                                 }
@@ -399,72 +565,14915 @@ const docTemplate = `{
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/command-cooldowns": {
+            "get": {
+                "description": "Lists devices (and IR hubs) that rejected commands sent in rapid sequence, with the gap learned between their commands. Commands to these devices are delayed until the cooldown passed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "List Command Cooldowns",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.CommandCooldownDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/command-cooldowns/{device_id}": {
+            "delete": {
+                "description": "Forgets the cooldown learned for a device, e.g. after a firmware update or replacing an IR hub.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Reset Command Cooldown",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device or IR hub ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/dtos.StandardResponse"
                         }
                     }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/config": {
+            "get": {
+                "description": "Lists every setting with its type, value and source (environment, .env or config.yaml). Secrets (credentials, tokens, API keys) are shown as ******** and passwords in URLs are masked. Also reports the problems the startup validation would find, e.g. after settings were written by setup.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Configuration",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.ConfigDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/device-categories": {
+            "get": {
+                "description": "Returns the categories allowed in and denied from device listings, and where the filter comes from (config or api).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Device Category Filter",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceCategoryFilterDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the device category filter. Denied categories are never listed; with an allow list, only the allowed categories are listed. Applies to nested collections too, immediately and without a redeploy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Update Device Category Filter",
+                "parameters": [
+                    {
+                        "description": "Category filter",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDeviceCategoryFilterRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceCategoryFilterDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes the filter set through the API, restoring DEVICE_CATEGORY_ALLOW and DEVICE_CATEGORY_DENY.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Reset Device Category Filter",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceCategoryFilterDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/feature-flags": {
+            "get": {
+                "description": "Lists every feature flag with its effective rollout and where it comes from (default, config or api).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "List Feature Flags",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.FeatureFlagDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/feature-flags/{name}": {
+            "put": {
+                "description": "Replaces the rollout of a feature flag: a percentage of devices, plus devices always enabled or always disabled. Applies immediately without a redeploy.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Update Feature Flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature flag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rollout",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateFeatureFlagRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.FeatureFlagDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes the rollout set through the API, restoring the FEATURE_FLAGS or default rollout.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Reset Feature Flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature flag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.FeatureFlagDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/feature-flags/{name}/devices/{device_id}": {
+            "get": {
+                "description": "Reports whether a feature flag is enabled for a device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Evaluate Feature Flag",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Feature flag name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "device_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.FeatureFlagEvaluationDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/health": {
+            "get": {
+                "description": "Reports the state of the database and the cache store. The cache is \"degraded\" when the configured store (CACHE_BACKEND) could not be opened, e.g. because the BadgerDB directory is locked or corrupted, and an in-memory store replaced it: the API keeps working, but data stored since the start is lost on restart and replication is paused. The overall status is the worst component state.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Health Details",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.HealthStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/keys": {
+            "get": {
+                "description": "Lists all managed API keys, including revoked ones. Only the key prefix is shown. The API_KEY from the environment is not listed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "List API Keys",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.APIKeyDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Issues an API key with a scope: read-only (GET requests only), control (device commands and changes) or admin (also the administrative endpoints). Optional device_ids and room_ids restrict the key to those devices and the devices in those rooms. The key is only returned here; the server stores its SHA-256 hash.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Create API Key",
+                "parameters": [
+                    {
+                        "description": "API key",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.APIKeyRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.APIKeyDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/keys/{id}": {
+            "delete": {
+                "description": "Revokes an API key. Requests presenting it are rejected immediately.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Revoke API Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.APIKeyDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/keys/{id}/grants": {
+            "put": {
+                "description": "Replaces the devices and rooms an API key is restricted to (e.g., a guest key only controlling the living room). Requests for other devices are rejected with 403, and device listings only show the granted devices. Empty lists lift the restriction.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Set API Key Grants",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "API key ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Granted devices and rooms",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.APIKeyGrantsDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.APIKeyDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/load": {
+            "get": {
+                "description": "Reports the load signals (requests in flight, queued jobs, moving average of Tuya API latency), the LOAD_SHED_* thresholds, and how many low-priority requests (history, charts, exports) were rejected with 503 while overloaded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Load Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LoadStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/mqtt-bridge": {
+            "get": {
+                "description": "Reports whether the Home Assistant MQTT bridge is enabled (MQTT_BROKER) and connected, how many devices and entities it published, and the latest error.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get MQTT Bridge Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.MQTTBridgeStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/mqtt-bridge/sync": {
+            "post": {
+                "description": "Republishes the Home Assistant discovery configs and states in the background, e.g. after devices were added or renamed. Entities of removed devices are deleted from Home Assistant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Republish Devices to MQTT",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/outbound/validate": {
+            "post": {
+                "description": "Checks a webhook or MQTT broker URL against OUTBOUND_ALLOWLIST and the private range rule (SSRF protection) and reports why it would be refused.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Validate Outbound Destination",
+                "parameters": [
+                    {
+                        "description": "Destination URL",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.OutboundValidationRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.OutboundValidationDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/permissions": {
+            "get": {
+                "description": "Reports whether the cloud project can call each Tuya API family the backend uses (token, device management, device status, IR control), with a hint for every missing subscription. Returns the latest periodic check unless refresh=true.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Tuya Permission Report",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Run the check now instead of returning the latest report",
+                        "name": "refresh",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PermissionReportDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/quota": {
+            "get": {
+                "description": "Reports the Tuya API calls of a UTC day per endpoint, with the projection for the whole day and the status against TUYA_QUOTA_DAILY_BUDGET and TUYA_QUOTA_ENDPOINT_BUDGETS (ok, warning or exceeded).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Tuya API Quota Usage",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "UTC day as YYYY-MM-DD (default: today)",
+                        "name": "date",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.QuotaUsageDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/replication": {
+            "get": {
+                "description": "Returns the last shipped and restored backup versions of this instance.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Replication Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.ReplicationStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/replication/backups": {
+            "post": {
+                "description": "Stores an incremental backup sent by the primary. Stored backups are replayed when this standby starts with REPLICATION_RESTORE_ON_START.",
+                "consumes": [
+                    "application/octet-stream"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Receive Backup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Backup file name (\u003csince\u003e-\u003cuntil\u003e.bak)",
+                        "name": "name",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/replication/ship": {
+            "post": {
+                "description": "Immediately ships the changes since the last backup to the replication target.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Ship Backup Now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/reports/ac-usage": {
+            "get": {
+                "description": "Reports the air conditioner runtime hours and energy per room for a month, to compare rooms and floors. Runtime is reconstructed from the power commands in the audit log; energy comes from recorded cur_power readings when the AC is metered, otherwise it is estimated with AC_RATED_WATTS. With format=csv the report is downloaded as CSV with one row per room and AC.",
+                "produces": [
+                    "application/json",
+                    "text/csv"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get AC Usage Report",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Month as YYYY-MM in the deployment time zone (default: current month)",
+                        "name": "month",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "json (default) or csv",
+                        "name": "format",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.ACUsageReportDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/restore": {
+            "post": {
+                "description": "Restores an archive downloaded from GET /api/admin/backup/{job_id}. In merge mode (default) archived entries and rooms overwrite existing ones with the same key or ID; in replace mode persistent entries and rooms missing from the archive are removed as well. Restart the server afterwards so every service reloads its configuration.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Restore Backup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "merge (default) or replace",
+                        "name": "mode",
+                        "in": "query"
+                    },
+                    {
+                        "description": "Backup archive",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.BackupArchiveDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.BackupRestoreResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/serialization": {
+            "get": {
+                "description": "Reports, per hot path (e.g., device_list), how many JSON encodes and decodes ran, their bytes and time, and how often a pre-marshaled payload was reused instead of re-serializing identical data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Serialization Stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SerializationStatsDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/admin/swagger/examples": {
+            "get": {
+                "description": "Returns the device-derived control examples currently served in the Swagger UI.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Get Swagger Examples",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SwaggerExamplesDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Generates example control payloads from a real device's specification (valid codes and value ranges) and serves them in the Swagger UI for the switch command endpoint.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Generate Swagger Examples",
+                "parameters": [
+                    {
+                        "description": "Device to derive examples from",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.GenerateSwaggerExamplesRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SwaggerExamplesDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/automations": {
+            "get": {
+                "description": "Lists all automation rules.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "List Automations",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.AutomationRuleDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates a rule such as \"if va_temperature \u003e 300 then set the AC to 22\". The actions run once when all conditions become true, and again only after a condition stopped matching and cooldown_seconds have passed. Rules are enabled unless enabled is false. Since actions are sent with the server-managed token, the rule requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access every device it references; the key becomes the owner of the rule, and actions on devices it can no longer access fail.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Create Automation",
+                "parameters": [
+                    {
+                        "description": "Rule definition",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AutomationRuleRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AutomationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/automations/{id}": {
+            "get": {
+                "description": "Returns an automation rule.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Get Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AutomationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the conditions, actions and cooldown of a rule. The enabled state is kept unless enabled is set. Requires the same API key as creating a rule; the caller becomes the owner of the rule.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Update Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule definition",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AutomationRuleRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AutomationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes an automation rule and its history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Delete Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/automations/{id}/disable": {
+            "post": {
+                "description": "Disables an automation rule. Disabled rules are not evaluated but can still be run manually.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Disable Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AutomationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/automations/{id}/enable": {
+            "post": {
+                "description": "Enables an automation rule. It fires on the next status report that matches all its conditions.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Enable Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AutomationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/automations/{id}/history": {
+            "get": {
+                "description": "Returns the latest runs of a rule (up to 100), newest first, with the outcome of each action.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Get Automation History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AutomationHistoryResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/automations/{id}/run": {
+            "post": {
+                "description": "Runs the actions of a rule immediately, regardless of its conditions. Requires an API key of control scope that may access the devices of the rule. The run is recorded in the history.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "11. Automations"
+                ],
+                "summary": "Run Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/bootstrap": {
+            "get": {
+                "description": "Returns everything the app needs on cold start in one round trip: session validity, device summary, rooms, favorite devices, active alarms and the house mode. Sections are assembled from cached data; sections that could not be assembled are listed in unavailable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "13. Bootstrap"
+                ],
+                "summary": "Get App Bootstrap",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.BootstrapDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/config": {
+            "get": {
+                "description": "Returns the TTL of device lists, device details, specifications and sensors, with their configured defaults.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Get cache TTLs",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheConfigDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Overrides TTLs by resource type (device_list, device_detail, specification, sensor), e.g. {\"ttls\": {\"device_list\": \"5m\", \"sensor\": \"default\"}}. \"default\" restores the TTL from the environment. Overrides survive restarts; entries already cached keep their TTL.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Update cache TTLs",
+                "parameters": [
+                    {
+                        "description": "TTL overrides",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateCacheConfigRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheConfigDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/flush": {
+            "delete": {
+                "description": "Remove all data from the cache storage",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Flush all cache",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/gc": {
+            "post": {
+                "description": "Rewrites BadgerDB value log files until none has at least BADGER_GC_DISCARD_RATIO stale data (overwritten, deleted or expired entries), reclaiming their disk space. Returns once the run is over; the sizes in GET /api/cache/stats reflect it within a minute. Not available with CACHE_BACKEND=redis.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Run cache garbage collection",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheGCRunDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/keys": {
+            "get": {
+                "description": "Lists BadgerDB keys starting with prefix, with value size and expiry. Covers both cache entries (\"cache:\") and persistent data.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "List cache keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key prefix (e.g., cache:)",
+                        "name": "prefix",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Maximum number of keys (default 1000, max 10000)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheKeysResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/keys/{key}": {
+            "get": {
+                "description": "Returns the value of a key with its expiry. JSON values are returned as JSON, other text as a string and binary values base64-encoded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Inspect cache key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key (e.g., cache:devices:...)",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheEntryDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes one key, leaving the rest of the cache intact.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Delete cache key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key",
+                        "name": "key",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheInvalidationDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/prefix/{prefix}": {
+            "delete": {
+                "description": "Removes every key starting with prefix (e.g., cache:devices: to drop cached device lists). The prefix must not be empty; use DELETE /api/cache/flush to clear all cache entries.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Invalidate cache prefix",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Key prefix",
+                        "name": "prefix",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheInvalidationDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/stats": {
+            "get": {
+                "description": "Returns the LSM tree and value log sizes of BadgerDB, the compaction state of every LSM level and the value log garbage collection schedule with its last run. Sizes are refreshed by BadgerDB once a minute. Not available with CACHE_BACKEND=redis.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "05. Flush"
+                ],
+                "summary": "Get cache storage stats",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheStatsDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/cache/warm": {
+            "post": {
+                "description": "Fetches the device list of TUYA_USER_ID and the specification of every device into the cache, e.g. after a deploy or a cache flush. The warm runs as a background job with the server-managed token: poll GET /api/jobs/{job_id} for progress and the CacheWarmResultDTO, or cancel it with POST /api/jobs/{job_id}/cancel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "08. Admin"
+                ],
+                "summary": "Warm Cache",
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CacheWarmStartedDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/favorites": {
+            "get": {
+                "description": "Returns the IDs of the user's favorite devices in display order.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get Favorite Devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.FavoritesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the user's favorite devices. The order of device_ids is the display order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Set Favorite Devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Favorite device IDs",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.FavoritesRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.FavoritesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/house-mode": {
+            "get": {
+                "description": "Returns the current house mode and the sensor poll interval it applies.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "12. House Mode"
+                ],
+                "summary": "Get House Mode",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.HouseModeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Switches the house mode. Automation rules with house_mode conditions are evaluated (e.g., an \"away\" rule turning off lights acts as an entry scene), the sensor poll interval follows HOUSE_MODE_POLL_INTERVALS, and a house_mode event is pushed to realtime subscribers.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "12. House Mode"
+                ],
+                "summary": "Set House Mode",
+                "parameters": [
+                    {
+                        "description": "New mode",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SetHouseModeRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.HouseModeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/identity/login": {
+            "post": {
+                "description": "Authenticates a user with a configured identity provider: \"ldap\" takes a username and password, \"oidc\" takes an ID token issued to OIDC_CLIENT_ID. The user's directory groups are mapped to a role (read-only, control or admin) with IDENTITY_GROUP_ROLES. The returned token is accepted instead of an X-API-KEY as \"Authorization: Bearer \u003ctoken\u003e\", with the role as scope.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "17. Identity"
+                ],
+                "summary": "Login With Identity Provider",
+                "parameters": [
+                    {
+                        "description": "Provider and credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.IdentityLoginRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IdentityTokenDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/identity/providers": {
+            "get": {
+                "description": "Lists the configured identity providers, so login screens can offer them.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "17. Identity"
+                ],
+                "summary": "List Identity Providers",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.IdentityProviderDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            }
+        },
+        "/api/intent": {
+            "post": {
+                "description": "Runs a short command such as \"turn off meeting room lights\", \"open the bedroom curtains\" or \"set desk lamp to 40%\". Rooms, devices and named switch channels are matched by fuzzy name; lights, fans, curtains and plugs narrow the match. The response lists what was done per device. Set dry_run to preview the match without sending anything.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Execute Typed Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Command text",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.IntentRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IntentResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/jobs": {
+            "get": {
+                "description": "Lists background jobs, newest first. Finished jobs are kept for JOB_RETENTION. Callers only see the jobs their own API key or identity enqueued; admin keys see every job.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "09. Jobs"
+                ],
+                "summary": "List Jobs",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Filter by status (queued, running, succeeded, failed, cancelled)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by job type",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.JobListResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/jobs/{id}": {
+            "get": {
+                "description": "Returns the status, progress and result of a background job. Jobs enqueued by other callers are reported as not found, except to admin keys.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "09. Jobs"
+                ],
+                "summary": "Get Job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.JobDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/jobs/{id}/cancel": {
+            "post": {
+                "description": "Cancels a queued or running job. Running jobs stop at their next cancellation check. Jobs enqueued by other callers are reported as not found, except to admin keys.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "09. Jobs"
+                ],
+                "summary": "Cancel Job",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Job ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.JobDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/notifications/rules": {
+            "get": {
+                "description": "Lists the caller's notification rules with the outcome of their latest delivery.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "20. Notifications"
+                ],
+                "summary": "List Notification Rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.NotificationRuleDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates an alert for devices offline for at least offline_minutes (all visible devices when device_ids is empty), delivered to webhook URLs, email addresses or Telegram chat IDs. Each offline episode is alerted once; alerts for the same device within cooldown_minutes (default 60) are suppressed. With notify_online, a recovery notice follows the alert. Requires an API key (or identity token) of control scope, which owns the rule: alerts are only sent for devices the key may access, and stop when it is revoked. Non-admin keys are only alerted about the devices claimed by their tenant (X-TUYA-UID if allowlisted for the key, else the default user).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "20. Notifications"
+                ],
+                "summary": "Create Notification Rule",
+                "parameters": [
+                    {
+                        "description": "Notification rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.NotificationRuleRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.NotificationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/notifications/rules/{id}": {
+            "get": {
+                "description": "Returns a notification rule of the caller.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "20. Notifications"
+                ],
+                "summary": "Get Notification Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.NotificationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the devices, thresholds and channels of a notification rule. Devices still offline are evaluated against the new thresholds.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "20. Notifications"
+                ],
+                "summary": "Update Notification Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Notification rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.NotificationRuleRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.NotificationRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes a notification rule. Notifications still queued for it are dropped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "20. Notifications"
+                ],
+                "summary": "Delete Notification Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/notifications/rules/{id}/test": {
+            "post": {
+                "description": "Sends a test notification to every channel of the rule right away, without retries or cooldown, and reports the outcome per channel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "20. Notifications"
+                ],
+                "summary": "Test Notification Rule",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Notification rule ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.NotificationTestResultDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/realtime/ticket": {
+            "post": {
+                "description": "Issues a single-use ticket valid for 30 seconds that authenticates one upgrade of /api/realtime/ws or /socket.io/ with the credentials of this request. Pass it as the ticket query parameter, since browsers cannot attach headers to websocket handshakes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "07. Realtime"
+                ],
+                "summary": "Issue websocket ticket",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RealtimeTicketDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/realtime/ws": {
+            "get": {
+                "description": "Upgrades the connection to a websocket streaming device state events. Events can be narrowed with comma-separated device_ids, rooms and categories query parameters. Sending a JSON SubscriptionFilterDTO message over the socket replaces the active filter. Browsers, which cannot set headers on websocket handshakes, authenticate with a ticket from POST /api/realtime/ticket. Handshakes from other origins than the API's host and REALTIME_ALLOWED_ORIGINS are rejected.",
+                "tags": [
+                    "07. Realtime"
+                ],
+                "summary": "Subscribe to device events",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated device IDs",
+                        "name": "device_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated room IDs",
+                        "name": "rooms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated device categories",
+                        "name": "categories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Single-use ticket from POST /api/realtime/ticket",
+                        "name": "ticket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DeviceEventDTO"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/rooms": {
+            "get": {
+                "description": "Lists all rooms with the IDs of their devices, ordered by name. With DEVICE_CLAIMS_ENABLED, tenants only see rooms containing their devices.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "10. Rooms"
+                ],
+                "summary": "List Rooms",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.RoomDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates a named room, optionally assigning devices to it. A device may belong to several rooms.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "10. Rooms"
+                ],
+                "summary": "Create Room",
+                "parameters": [
+                    {
+                        "description": "Room name and devices",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateRoomRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RoomDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/rooms/{id}": {
+            "get": {
+                "description": "Returns a room with the IDs of its devices.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "10. Rooms"
+                ],
+                "summary": "Get Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RoomDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Renames a room and/or replaces its devices. Omitted fields are left unchanged; an empty device list removes all devices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "10. Rooms"
+                ],
+                "summary": "Update Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name and/or devices",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateRoomRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RoomDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes a room. The devices themselves are not changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "10. Rooms"
+                ],
+                "summary": "Delete Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/rooms/{id}/commands": {
+            "post": {
+                "description": "Sends the same commands to every device in the room. A failure on one device does not stop the others; the response reports the outcome per device.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "10. Rooms"
+                ],
+                "summary": "Control Room",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Room ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Commands to send",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RoomCommandRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RoomCommandResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/setup": {
+            "get": {
+                "description": "Reports whether the install still needs setup, i.e. no admin API key exists yet.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "15. Setup"
+                ],
+                "summary": "Get Setup Status",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SetupStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Validates the Tuya credentials, issues the first admin API key and writes the configuration to the .env file. Only available while neither API_KEY nor a managed admin key exists, and only with the setup token printed to the server log at startup (or SETUP_TOKEN). The API key is only returned here.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "15. Setup"
+                ],
+                "summary": "Complete Setup",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Setup token",
+                        "name": "X-Setup-Token",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "description": "Tuya credentials",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SetupRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SetupResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tuya/approvals": {
+            "get": {
+                "description": "Lists commands held for two-person approval on the caller's devices, newest first. Decided and expired actions stay listed for 7 days.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Pending Actions",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "pending, executed, failed, rejected or expired",
+                        "name": "status",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.PendingActionDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/approvals/{id}": {
+            "get": {
+                "description": "Returns a command held for two-person approval.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Pending Action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pending action ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PendingActionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/approvals/{id}/approve": {
+            "post": {
+                "description": "Sends the held command with the approver's token. The approver needs an API key (X-API-KEY, or the key the session was created with) or identity token of control scope that may access the device, and must be another key or user than the requester; a second session of the requester does not count. The outcome (status executed or failed) is recorded in the audit log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Approve Pending Action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pending action ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PendingActionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/approvals/{id}/reject": {
+            "post": {
+                "description": "Discards a held command. The requester may reject their own action to withdraw it; anyone else needs the same credentials as an approver.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Reject Pending Action",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Pending action ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Reason",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RejectPendingActionRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PendingActionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/assets": {
+            "get": {
+                "description": "Lists the assets (buildings, floors, rooms) of an IoT Core project. IoT Core projects have no user UID to list devices by; their devices are listed per asset.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "List Assets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only list assets whose name contains it",
+                        "name": "name",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page (default 20, max 100)",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TuyaAssetsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/assets/{asset_id}/devices": {
+            "get": {
+                "description": "Lists the devices of an asset with their status and online state. With DEVICE_CLAIMS_ENABLED, callers without the admin X-API-KEY only see the devices assigned to their tenant.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "List Asset Devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Asset ID",
+                        "name": "asset_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TuyaAssetDevicesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/auth": {
+            "get": {
+                "description": "Authenticates the user and retrieves a Tuya access token. When AUTH_SESSION_MODE is enabled, an opaque session_id (TuyaSessionResponseDTO) is returned instead and the Tuya token stays server-side. When JWT_SECRET is set, the response also carries a signed app JWT (token) for that session, which is then the only accepted Bearer value.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "01. Auth"
+                ],
+                "summary": "Authenticate with Tuya",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TuyaAuthResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/auth/session": {
+            "delete": {
+                "description": "Revokes the server-side session used for the current request. Only applicable when authenticating with a session_id.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "01. Auth"
+                ],
+                "summary": "Revoke Session",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/circadian": {
+            "get": {
+                "description": "Returns the adaptive lighting configuration with the current target and manual-override state of each light.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Adaptive Lighting",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CircadianStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the adaptive lighting configuration. Curve points (\"HH:MM\", brightness and temperature percentages, temperature 0 = warmest) are interpolated over the day; each light may have its own curve or adjust temperature only.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Configure Adaptive Lighting",
+                "parameters": [
+                    {
+                        "description": "Adaptive lighting configuration",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CircadianConfigDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CircadianStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/circadian/dispatch": {
+            "post": {
+                "description": "Moves every enrolled light to its current target immediately instead of waiting for the next scheduled run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Run Adaptive Lighting Now",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.CircadianDispatchResultDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/circadian/lights/{id}/resume": {
+            "post": {
+                "description": "Clears a detected manual override so the light follows the curve again on the next run.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Resume Adaptive Lighting",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/claims": {
+            "get": {
+                "description": "Lists the claims submitted by the caller's tenant, newest first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "List My Device Claims",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceClaimDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Asks for a device (e.g., scanned from its QR code) to be assigned to the caller's tenant. The claim stays pending until an admin approves it. Submitting the same claim again returns the pending claim.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Claim Device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Device to claim",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SubmitDeviceClaimRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceClaimDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceClaimDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/commands": {
+            "post": {
+                "description": "Queues commands for a device. Requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access the device, since the commands are sent with the server-managed token; the key is checked again before every attempt. The commands are sent in the background and retried with exponential backoff on transient Tuya failures (timeouts, network errors, HTTP 5xx). Send an Idempotency-Key header to retry the request safely: a repeated key returns the command created the first time (200) instead of queueing it again, and reusing a key with a different body is rejected (409).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Queue Device Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Client-generated key deduplicating retries for 24h",
+                        "name": "Idempotency-Key",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Device and commands",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.QueueCommandRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CommandStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CommandStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/commands/{id}": {
+            "get": {
+                "description": "Returns the execution status of a queued command: queued, running, succeeded or failed, with the attempts made and the last error. Callers only see the commands they queued; admin keys see all.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Command Status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Command ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CommandStatusDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices": {
+            "get": {
+                "description": "Retrieves a list of all devices. Response format depends on GET_ALL_DEVICES_RESPONSE_TYPE: 0 (Nested/Default), 1 (Flat), 2 (Merged). Sorted alphabetically by Name unless sort asks for favorites (by their sort order), the custom order or online devices first. The response carries an ETag; sending it back in If-None-Match returns 304 Not Modified while the list is unchanged. With DEVICE_CLAIMS_ENABLED, callers without the admin X-API-KEY only see the devices assigned to their tenant. For infrared_ac devices, the status array is populated with saved device state (power, temp, mode, wind) or default values if no state exists.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get All Devices",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Page number",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Items per page",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Filter by category",
+                        "name": "category",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort order: name (default), favorite, custom or online",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return per device (e.g., id,name,online,status)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "API key used to validate the X-TUYA-UID override",
+                        "name": "X-API-KEY",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the list the client holds",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TuyaDevicesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/changes/log": {
+            "get": {
+                "description": "Lists devices added, removed, renamed or re-categorized in the Tuya account, as detected on each device list refresh. Newest entries first.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get Device Change Log",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceChangeLogResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/compare": {
+            "get": {
+                "description": "Compares two devices to troubleshoot why one of two identical devices behaves differently. Returns both devices and the fields that differ in their details, firmware versions, specification, current status and the command and sensor history of the last 7 days. Sections that cannot be read are listed as unavailable.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Compare Devices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Two comma-separated device IDs",
+                        "name": "ids",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceComparisonDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/order": {
+            "put": {
+                "description": "Saves a custom device order, used by GET /api/tuya/devices?sort=custom (and to order favorites). Devices not listed lose their position and follow in name order.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Set Device Order",
+                "parameters": [
+                    {
+                        "description": "Device IDs in display order",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DeviceOrderRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceOrderResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/status": {
+            "get": {
+                "description": "Returns only the ID, online state and key status values (switches, light and climate settings, common sensor readings) of all devices in one compact payload, for dashboards that refresh frequently. It is built from the same cached device list as GET /api/tuya/devices. The snapshot version is returned as ETag; sending it back in If-None-Match returns 304 Not Modified when nothing changed. With delta=true, only the devices that changed since that version are returned, plus the removed device IDs (a full snapshot is returned when the version is no longer known).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get Device Status Snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma-separated status codes to include instead of the key status codes",
+                        "name": "codes",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Return only the changes since the If-None-Match version",
+                        "name": "delta",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Snapshot version (ETag) the client holds",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceStatusSnapshotDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}": {
+            "get": {
+                "description": "Retrieves details of a specific device by its ID. Response includes last_commands field containing the last control commands sent to the device. The response carries an ETag; sending it back in If-None-Match returns 304 Not Modified while the device is unchanged.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get Device by ID",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated fields to return per device (e.g., id,name,online,status)",
+                        "name": "fields",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag of the device the client holds",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TuyaDeviceResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Renames a device in the Tuya cloud, so the new name also shows in the Smart Life app. Cached device details and lists are refreshed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Rename Device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RenameDeviceRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TuyaDeviceResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/alarm/test": {
+            "post": {
+                "description": "Publishes a simulated high-priority alarm event for a water leak (sj) or smoke (ywbj) sensor so clients can verify alarm handling. The device itself is not triggered.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "04. Device Sensor"
+                ],
+                "summary": "Test Alarm",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.AlarmTestResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/channels": {
+            "get": {
+                "description": "Lists the channels (gangs) of a multi-gang switch with their names and current values.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get Switch Channels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceChannelDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Names the channels of a multi-gang switch, e.g. {\"names\": {\"switch_1\": \"Porch\", \"switch_2\": \"Garden\"}}. An empty name restores the default.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Rename Switch Channels",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Channel names",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RenameChannelsRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceChannelDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/channels/{channel}/commands": {
+            "post": {
+                "description": "Switches a single channel on or off. The channel can be referenced by code (switch_2), index (2) or name.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Switch Channel",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Channel code, index or name",
+                        "name": "channel",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Channel state",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ChannelCommandRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/climate": {
+            "get": {
+                "description": "Returns power, mode, target/current temperature, fan speed and swing in one model for IR air conditioners (infrared_ac) and native Wi-Fi ACs and thermostats (kt, ktkzq, wk, wkf, qn), along with the settings the device accepts.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Climate State",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.ClimateStateDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Applies normalized climate settings. IR air conditioners receive IR commands through their hub; native devices receive the matching DPs, validated against their specification. Omitted fields are left unchanged; power off cannot be combined with other settings.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Control Climate",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Climate settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ClimateControlRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CategoryControlResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/commands/ir": {
+            "post": {
+                "description": "Sends an infrared command to an AC via a specific IR device. The remote must belong to the IR device in the path, and API keys restricted to devices must be granted the remote as well.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Send IR AC Command",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "IR AC Command Payload",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TuyaIRACCommandDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/commands/switch": {
+            "post": {
+                "description": "Sends a command to a specific Tuya device. Commands protected by an approval rule are not sent; they are held as a pending action (202) until another user approves it via /api/approvals/{id}/approve.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Send Command to Device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Command Payload",
+                        "name": "command",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PendingActionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/dimmer": {
+            "post": {
+                "description": "Sets power and brightness (1-100 percent) on dimmer (tgq) devices. Brightness is scaled to the device's raw range.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Control Dimmer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Dimmer settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DimmerControlRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CategoryControlResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/fan": {
+            "post": {
+                "description": "Sets power, speed level, oscillation and direction on fan (fs) and fan switch (fskg) devices. Values are validated against the device specification and translated to its DP codes.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Control Fan",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fan settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.FanControlRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CategoryControlResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/favorite": {
+            "post": {
+                "description": "Marks a device as favorite, or unmarks it with {\"favorite\": false}. Favorites come first in GET /api/tuya/devices?sort=favorite.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Set Favorite Device",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Favorite flag (default true)",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DeviceFavoriteRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceMetadataDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/ir/keys": {
+            "post": {
+                "description": "Saves a learned code as a named key. The key is added to remote_id, or a new custom remote named remote_name is created.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Save Learned IR Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Learned key",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveIRLearnedKeyRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SaveIRLearnedKeyResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/ir/learning": {
+            "post": {
+                "description": "Puts an IR hub into learning mode. Point the physical remote at the hub and press a button, then poll the learned code with the returned learning_time.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Start IR Learning",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRLearningSessionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Takes an IR hub out of learning mode.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Stop IR Learning",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/ir/learning/code": {
+            "get": {
+                "description": "Polls for the code captured since learning mode started. learned is false until a button has been pressed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Learned IR Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "learning_time returned when learning started",
+                        "name": "learning_time",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRLearnedCodeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/lock/records": {
+            "get": {
+                "description": "Returns the unlock records of a smart lock (ms, jtmspro, jtmsbh), newest first. The method tells how the lock was opened (e.g., unlock_fingerprint, unlock_temporary, unlock_app).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "18. Door Locks"
+                ],
+                "summary": "List Unlock Records",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start of the range (Unix seconds, default 7 days before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End of the range (Unix seconds, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page number (default 1)",
+                        "name": "page",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size, up to 100 (default 20)",
+                        "name": "page_size",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LockRecordsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/lock/temp-passwords": {
+            "get": {
+                "description": "Lists the temporary passwords of a smart lock with their validity period and phase (pending, active, frozen, deleted). The passwords themselves are never returned.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "18. Door Locks"
+                ],
+                "summary": "List Temporary Passwords",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TempPasswordsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates a numeric temporary password valid between effective_time (default now) and invalid_time. The password is encrypted with a one-off Tuya password ticket before it is sent, and is not stored. Locks apply it on their next sync; check the phase in the list.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "18. Door Locks"
+                ],
+                "summary": "Create Temporary Password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Temporary password",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.TempPasswordRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.TempPasswordDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/lock/temp-passwords/{password_id}": {
+            "delete": {
+                "description": "Deletes a temporary password from a smart lock.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "18. Door Locks"
+                ],
+                "summary": "Delete Temporary Password",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Lock ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Temporary password ID",
+                        "name": "password_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/macros": {
+            "get": {
+                "description": "Lists the command macros stored for a device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Device Macros",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceMacroDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/macros/{name}": {
+            "put": {
+                "description": "Creates or replaces a macro. Command values may reference parameters as \"${name}\": a value that is exactly one placeholder takes the argument's type (e.g., {\"code\": \"temp_set\", \"value\": \"${temp}\"}), placeholders inside longer strings are inserted as text.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Save Device Macro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Macro name (e.g., set_ac)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Parameters and command templates",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveDeviceMacroRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceMacroDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Expands a macro with the given arguments (e.g., {\"params\": {\"temp\": 24}} for set_ac) and sends the resulting commands to the device in one call. Parameters with a default may be omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Run Device Macro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Macro name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Macro arguments",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.RunDeviceMacroRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RunDeviceMacroResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes a macro from a device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Delete Device Macro",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Macro name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/metadata": {
+            "get": {
+                "description": "Returns the local metadata of a device: custom label, icon, sort order and favorite flag. The same metadata is included in device responses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Get Device Metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceMetadataDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Changes the local metadata of a device. Omitted fields are kept; an empty label or icon, or a negative sort_order, clears it. Metadata is stored by Teralux only and shared by all users.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "02. Devices"
+                ],
+                "summary": "Update Device Metadata",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Metadata changes",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.UpdateDeviceMetadataRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceMetadataDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/presets": {
+            "get": {
+                "description": "Lists the command presets stored for a device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Device Presets",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DevicePresetDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Stores a named command bundle for a device (e.g., {\"name\": \"AC Night Mode\", \"commands\": [{\"code\": \"switch\", \"value\": true}, {\"code\": \"temp_set\", \"value\": 26}, {\"code\": \"fan_speed_enum\", \"value\": \"1\"}]}). A preset with the same name, in any case, is replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Save Device Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preset name and commands",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveDevicePresetRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DevicePresetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DevicePresetDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/presets/{name}": {
+            "delete": {
+                "description": "Removes a preset from a device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Delete Device Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/presets/{name}/execute": {
+            "post": {
+                "description": "Sends the commands of a preset to the device in one call, so they are applied together or not at all.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Execute Device Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name (any case)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.ExecuteDevicePresetResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/sensor": {
+            "get": {
+                "description": "Retrieves sensor data (temperature, humidity, etc.) for a specific device",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "04. Device Sensor"
+                ],
+                "summary": "Get Sensor Data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SensorDataDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/sensor/chart": {
+            "get": {
+                "description": "Renders the recorded readings of a sensor as a chart image for embedding in e-mails or Telegram alerts. The line shows the average per interval and the shaded band the minimum and maximum; the interval widens with the range (1h for a day, 6h for a month). Returns the image itself, or a JSON error.",
+                "produces": [
+                    "image/png",
+                    "image/svg+xml"
+                ],
+                "tags": [
+                    "04. Device Sensor"
+                ],
+                "summary": "Get Sensor Chart",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "temperature (default), humidity or battery",
+                        "name": "metric",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "How far back the chart reaches in hours or days (e.g., 24h, 7d; default 24h, max 90d)",
+                        "name": "range",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "png (default) or svg",
+                        "name": "format",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone of the time labels (e.g., Asia/Jakarta; default TIMEZONE)",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "file"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/sensor/history": {
+            "get": {
+                "description": "Returns temperature, humidity and battery readings recorded by the background sampler, aggregated per interval (min, max, average, last). Sensors are enrolled on their first read through GET /api/tuya/devices/{id}/sensor. Data older than the local archive retention is only available in object storage.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "04. Device Sensor"
+                ],
+                "summary": "Get Sensor History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start of the range (Unix seconds, default 24 hours before to)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End of the range (Unix seconds, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Aggregation interval in whole hours (e.g., 1h, 6h, 24h; default 1h)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "IANA time zone whose midnight intervals are aligned to (e.g., Asia/Jakarta; default TIMEZONE)",
+                        "name": "tz",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SensorHistoryDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/snapshot": {
+            "get": {
+                "description": "Captures a picture with a camera (category sp) and returns its URL, valid for a few minutes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "19. Cameras"
+                ],
+                "summary": "Get Camera Snapshot",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Camera ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CameraSnapshotDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/state/history": {
+            "get": {
+                "description": "Returns the last known state of a device and the states it replaced, newest first. The number of kept states is set by DEVICE_STATE_HISTORY_SIZE.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Device State History",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceStateHistoryDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/state/undo": {
+            "post": {
+                "description": "Re-sends the settings of the newest previous state that differ from the current state, e.g. to revert an accidental change. Calling it again steps further back. Commands protected by an approval rule are held as a pending action (202).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Undo Device State Change",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.UndoDeviceStateResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.PendingActionDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/stream": {
+            "get": {
+                "description": "Allocates a live stream URL of a camera (category sp). The URL is valid for a few minutes; request a new one to reconnect.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "19. Cameras"
+                ],
+                "summary": "Get Camera Stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Camera ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Stream protocol: hls, rtsp, flv or rtmp (default hls)",
+                        "name": "type",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CameraStreamDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/devices/{id}/timer": {
+            "get": {
+                "description": "Lists the pending timers of a device with their mode (native or job) and remaining seconds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Device Timers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceTimerDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Turns a switch of the device on or off after a delay (e.g., {\"action\": \"off\", \"delay_seconds\": 1800}). The device's own countdown DP (countdown_1) is used when it has one and the switch is in the opposite state; otherwise the server sends the command later. Code selects the switch (defaults to switch_1, switch or switch_led). A pending timer of the same switch is replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Set Device Timer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Action and delay",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SetDeviceTimerRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.DeviceTimerDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Cancels the pending timers of a device, or only the timer of one switch. Native countdowns are reset to 0 on the device.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Cancel Device Timers",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only cancel the timer of this switch code",
+                        "name": "code",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.DeviceTimerDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/homes": {
+            "get": {
+                "description": "Lists the Smart Life homes of the user. Scenes and automations are listed per home.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "16. Cloud Scenes"
+                ],
+                "summary": "List Homes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Tuya user ID override (must be allowlisted for X-API-KEY)",
+                        "name": "X-TUYA-UID",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.TuyaHomeDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/homes/{home_id}/automations": {
+            "get": {
+                "description": "Lists the automations configured in the Smart Life app for a home.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "16. Cloud Scenes"
+                ],
+                "summary": "List Cloud Automations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Home ID",
+                        "name": "home_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CloudAutomationsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/homes/{home_id}/automations/{automation_id}/disable": {
+            "post": {
+                "description": "Disables an automation configured in the Smart Life app.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "16. Cloud Scenes"
+                ],
+                "summary": "Disable Cloud Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Home ID",
+                        "name": "home_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "automation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CloudSceneActionResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/homes/{home_id}/automations/{automation_id}/enable": {
+            "post": {
+                "description": "Enables an automation configured in the Smart Life app. Automations run on their own conditions and cannot be triggered directly.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "16. Cloud Scenes"
+                ],
+                "summary": "Enable Cloud Automation",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Home ID",
+                        "name": "home_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Automation ID",
+                        "name": "automation_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CloudSceneActionResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/homes/{home_id}/scenes": {
+            "get": {
+                "description": "Lists the tap-to-run scenes configured in the Smart Life app for a home.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "16. Cloud Scenes"
+                ],
+                "summary": "List Cloud Scenes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Home ID",
+                        "name": "home_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CloudScenesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/homes/{home_id}/scenes/{scene_id}/trigger": {
+            "post": {
+                "description": "Runs a tap-to-run scene configured in the Smart Life app. The trigger is recorded in the audit log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "16. Cloud Scenes"
+                ],
+                "summary": "Trigger Cloud Scene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Home ID",
+                        "name": "home_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "scene_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.CloudSceneActionResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/categories": {
+            "get": {
+                "description": "Lists the remote categories (TV, set-top box, fan, AC, ...) the IR hub can control. First step of adding a remote: pick a category, then a brand, then a remote index.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List IR Remote Categories",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRCategoriesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/categories/{category_id}/brands": {
+            "get": {
+                "description": "Lists the appliance brands of a remote category.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List IR Remote Brands",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRBrandsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/categories/{category_id}/brands/{brand_id}/remote-indexes": {
+            "get": {
+                "description": "Lists the code libraries of a brand. Models of one brand often use different libraries; try them in order until the appliance responds.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List IR Remote Indexes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Category ID",
+                        "name": "category_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Brand ID",
+                        "name": "brand_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRRemoteIndexesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/learned-codes": {
+            "get": {
+                "description": "Lists the raw IR codes saved by name on an IR hub.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Learned IR Codes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.IRNamedCodeDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Saves a raw IR code under a name. Start learning mode with POST /api/tuya/devices/{id}/ir/learning, press the button on the physical remote, then send {\"name\": \"Fireplace On\", \"learning_time\": \u003cfrom the learning session\u003e} to save the captured code (409 until the hub captured one), or send a known code directly in \"code\". A code with the same name, in any case, is replaced.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Save Learned IR Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Name and code or learning time",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveIRNamedCodeRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRNamedCodeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRNamedCodeDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/learned-codes/{name}": {
+            "delete": {
+                "description": "Removes a saved code from an IR hub.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Delete Learned IR Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/learned-codes/{name}/send": {
+            "post": {
+                "description": "Replays a saved raw code through the IR hub.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Send Learned IR Code",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Code name (any case)",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SendIRNamedCodeResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/remotes": {
+            "get": {
+                "description": "Lists the remotes (TV, fan, projector, AC, custom) configured on an IR hub.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List IR Remotes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRRemotesResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Adds a virtual remote from a code library to the IR hub, without the Tuya Smart app (e.g., {\"category_id\": 2, \"brand_id\": 33, \"remote_index\": 1234, \"remote_name\": \"Living Room TV\"}). brand_name is looked up when omitted.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Add IR Remote",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Code library and remote name",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.AddIRRemoteRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRRemoteDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/remotes/{remote_id}/command": {
+            "post": {
+                "description": "Presses a key of a remote, identified by key (e.g., \"power\", \"volume_up\") or key_id.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Send IR Remote Key",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remote ID",
+                        "name": "remote_id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Key to press",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.IRRemoteCommandRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRRemoteCommandResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/infrareds/{id}/remotes/{remote_id}/keys": {
+            "get": {
+                "description": "Returns the keys of a remote. Use key or key_id from this list to send key presses.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get IR Remote Keys",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Infrared Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Remote ID",
+                        "name": "remote_id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.IRRemoteKeysResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/light-groups": {
+            "get": {
+                "description": "Lists all light groups with their built-in and custom presets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Light Groups",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.LightGroupDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Creates a light group from light devices (dj, dd, xdd, fwd, dc, tgq, gyd). API keys restricted to devices or rooms can only group their granted devices.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Create Light Group",
+                "parameters": [
+                    {
+                        "description": "Group name and devices",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.CreateLightGroupRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LightGroupDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/light-groups/{id}": {
+            "get": {
+                "description": "Returns a light group with its built-in and custom presets.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Light Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Light group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LightGroupDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes a light group and its custom presets. The lights themselves are not changed.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Delete Light Group",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Light group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/light-groups/{id}/presets/{preset}": {
+            "put": {
+                "description": "Creates or replaces a custom preset. Brightness and temperature are percentages (temperature 0 = warmest); a colour switches lights to colour mode. A custom preset named like a built-in one (warm_evening, focus, party) overrides it.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Save Light Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Light group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "preset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Preset settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveLightPresetRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LightGroupDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Deletes a custom preset. Built-in presets cannot be deleted.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Delete Light Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Light group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "preset",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/light-groups/{id}/presets/{preset}/apply": {
+            "post": {
+                "description": "Applies a preset to every light in the group. Lights exposing a transition DP fade over transition_ms; others switch immediately. The result reports the outcome per light. API keys restricted to devices or rooms need access to every light in the group.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Apply Light Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Light group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "preset",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Transition override",
+                        "name": "request",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.ApplyLightPresetRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.ApplyLightPresetResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/light-groups/{id}/presets/{preset}/capture": {
+            "post": {
+                "description": "Saves the current brightness and colour of every light in the group as a custom preset. API keys restricted to devices or rooms need access to every light in the group.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Capture Light Preset",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Light group ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Preset name",
+                        "name": "preset",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LightGroupDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/rollouts": {
+            "post": {
+                "description": "Sends the same commands to many devices in waves, e.g. power cycling 100 plugs. Requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access every device, since the commands are sent with the server-managed token; the grants are checked again before each device. The first wave (canary_size devices, default wave_size) goes out first; each following wave waits wave_delay_seconds. The rollout aborts when the share of failed devices so far exceeds max_error_rate (default 0.2) and the remaining devices are skipped. The rollout runs as a background job: poll GET /api/jobs/{job_id} for progress and the per-device result, or cancel it with POST /api/jobs/{job_id}/cancel.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Start Staged Rollout",
+                "parameters": [
+                    {
+                        "description": "Devices, commands and wave settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StartRolloutRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.RolloutStartedDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/scene-switches/{id}/bindings": {
+            "get": {
+                "description": "Lists the actions bound to each button and press type of a wireless scene switch (wxkg).",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Get Scene Switch Bindings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene Switch Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SceneSwitchBindingsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the bindings of a wireless scene switch. Each button/press type (single_click, double_click, long_press) maps to device commands, a scene or an automation. Requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access the switch and every commanded device; button presses run the bound actions with the permissions of that key.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Set Scene Switch Bindings",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene Switch Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Bindings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SceneSwitchBindingsRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SceneSwitchBindingsResponseDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/scene-switches/{id}/events": {
+            "post": {
+                "description": "Feeds a button event from a scene switch (e.g., code switch1_value, value single_click) into the binding pipeline and runs the bound action. Requires an API key of control scope that may access the switch.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Report Scene Switch Event",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene Switch Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Button event",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SceneSwitchEventDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.SceneSwitchEventResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/scenes": {
+            "get": {
+                "description": "Lists the whole-house scenes stored by Teralux. Scenes configured in the Smart Life app are listed per home under /api/tuya/homes.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "21. Scenes"
+                ],
+                "summary": "List Scenes",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.LocalSceneDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Stores a whole-house scene: ordered steps, each sending commands or a device preset to one device, optionally delay_seconds after the previous step. With rollback_on_failure, a failed step stops the scene and the steps already done are reverted to the values their devices reported before; otherwise the remaining steps still run. Scenes run with the server-managed token, so an API key of control scope that may access every device of the scene is required.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "21. Scenes"
+                ],
+                "summary": "Create Scene",
+                "parameters": [
+                    {
+                        "description": "Scene name, rollback option and steps",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveLocalSceneRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LocalSceneDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/scenes/{id}": {
+            "get": {
+                "description": "Returns a whole-house scene with its steps.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "21. Scenes"
+                ],
+                "summary": "Get Scene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LocalSceneDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the name, rollback option and steps of a scene. Runs already started keep the old steps. Requires an API key of control scope that may access every device of the scene.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "21. Scenes"
+                ],
+                "summary": "Update Scene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Scene name, rollback option and steps",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.SaveLocalSceneRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LocalSceneDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes a whole-house scene. Runs already started are not stopped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "21. Scenes"
+                ],
+                "summary": "Delete Scene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/scenes/{id}/run": {
+            "post": {
+                "description": "Runs a whole-house scene as a background job on behalf of the caller, whose API key needs control scope and access to every device of the scene; the key is checked again before each step. Poll GET /api/jobs/{job_id} for progress and the per-step result, or cancel it with POST /api/jobs/{job_id}/cancel.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "21. Scenes"
+                ],
+                "summary": "Run Scene",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Scene ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.LocalSceneRunStartedDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/standby-killer": {
+            "get": {
+                "description": "Lists the standby killer rules of all metered plugs with their last power reading and timer state.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "List Standby Killer Rules",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.StandbyKillerRuleDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/tuya/standby-killer/{id}": {
+            "put": {
+                "description": "Switches a metered plug off when its power draw stays below threshold_watts for duration_minutes. The device must report cur_power.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Configure Standby Killer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Rule settings",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandbyKillerRuleRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.StandbyKillerRuleDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes the standby killer rule of a plug.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "03. Device Control"
+                ],
+                "summary": "Delete Standby Killer",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Device ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/webhooks": {
+            "get": {
+                "description": "Lists the caller's webhooks with the outcome of their latest delivery.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "14. Webhooks"
+                ],
+                "summary": "List Webhooks",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "type": "array",
+                                            "items": {
+                                                "$ref": "#/definitions/dtos.WebhookDTO"
+                                            }
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "post": {
+                "description": "Registers a callback URL for device events (device_offline, device_online, sensor_threshold, command_failed, alarm). Deliveries are JSON POSTs signed with X-Teralux-Signature: sha256=HMAC-SHA256(secret, X-Teralux-Timestamp + \".\" + body), retried with backoff on failure. The secret is only returned here. Requires an API key (or identity token) of control scope, which owns the webhook: events are only delivered for devices the key may access, and stop when it is revoked. Non-admin keys only receive events of the devices claimed by their tenant (X-TUYA-UID if allowlisted for the key, else the default user).",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "14. Webhooks"
+                ],
+                "summary": "Create Webhook",
+                "parameters": [
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.WebhookRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.WebhookDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/webhooks/{id}": {
+            "get": {
+                "description": "Returns a webhook of the caller.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "14. Webhooks"
+                ],
+                "summary": "Get Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.WebhookDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "put": {
+                "description": "Replaces the URL, events and filters of a webhook. The signing secret is kept.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "14. Webhooks"
+                ],
+                "summary": "Update Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Webhook",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dtos.WebhookRequestDTO"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.WebhookDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            },
+            "delete": {
+                "description": "Removes a webhook. Deliveries still queued for it are dropped.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "14. Webhooks"
+                ],
+                "summary": "Delete Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/api/webhooks/{id}/test": {
+            "post": {
+                "description": "Sends a signed ping event to the webhook right away, without retries, and reports the response.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "14. Webhooks"
+                ],
+                "summary": "Test Webhook",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Webhook ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "allOf": [
+                                {
+                                    "$ref": "#/definitions/dtos.StandardResponse"
+                                },
+                                {
+                                    "type": "object",
+                                    "properties": {
+                                        "data": {
+                                            "$ref": "#/definitions/dtos.WebhookTestResultDTO"
+                                        }
+                                    }
+                                }
+                            ]
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.StandardResponse"
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ]
+            }
+        },
+        "/health": {
+            "get": {
+                "description": "Check if the application and database are healthy. Answers \"Degraded\" (still 200) while the cache store could not be opened and an in-memory fallback is used; see GET /api/admin/health for details.",
+                "produces": [
+                    "text/plain"
+                ],
+                "tags": [
+                    "Health"
+                ],
+                "summary": "Health check endpoint",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "string"
+                        }
+                    }
+                }
+            }
+        },
+        "/socket.io/": {
+            "get": {
+                "description": "Streams the same device event payloads as /api/realtime/ws to Socket.IO clients (2.x, 3.x and 4.x) using the websocket transport only, e.g. io(url, {transports: [\"websocket\"], query: {ticket}}) with a ticket from POST /api/realtime/ticket. Handshakes from other origins than the API's host and REALTIME_ALLOWED_ORIGINS are rejected. Events are emitted under their type (\"device_state\", \"device_online\", ...) or under SOCKETIO_EVENT_NAME when configured. Narrow events with device_ids, rooms and categories query parameters, or emit \"subscribe\" with a SubscriptionFilterDTO.",
+                "tags": [
+                    "07. Realtime"
+                ],
+                "summary": "Socket.IO compatibility endpoint",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Engine.IO protocol version (3 or 4)",
+                        "name": "EIO",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Must be websocket",
+                        "name": "transport",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated device IDs",
+                        "name": "device_ids",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated room IDs",
+                        "name": "rooms",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma-separated device categories",
+                        "name": "categories",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Single-use ticket from POST /api/realtime/ticket",
+                        "name": "ticket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "$ref": "#/definitions/dtos.DeviceEventDTO"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    }
+                },
+                "security": [
+                    {
+                        "BearerAuth": []
+                    }
+                ]
+            }
+        }
+    },
+    "definitions": {
+        "dtos.ACDeviceUsageDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "energy_kwh": {
+                    "type": "number"
+                },
+                "energy_source": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "runtime_hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.ACRoomUsageDTO": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.ACDeviceUsageDTO"
+                    }
+                },
+                "energy_kwh": {
+                    "type": "number"
+                },
+                "room_id": {
+                    "type": "string"
+                },
+                "room_name": {
+                    "type": "string"
+                },
+                "runtime_hours": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.ACUsageReportDTO": {
+            "type": "object",
+            "properties": {
+                "energy_kwh": {
+                    "type": "number"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "month": {
+                    "type": "string"
+                },
+                "rated_watts": {
+                    "type": "number"
+                },
+                "rooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.ACRoomUsageDTO"
+                    }
+                },
+                "runtime_hours": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.APIKeyDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "revoked_at": {
+                    "type": "integer"
+                },
+                "room_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scope": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.APIKeyGrantsDTO": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "room_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.APIKeyRequestDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "scope"
+            ],
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Living room tablet"
+                },
+                "room_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "scope": {
+                    "type": "string",
+                    "example": "control"
+                }
+            }
+        },
+        "dtos.ActiveAlertDTO": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AddIRRemoteRequestDTO": {
+            "type": "object",
+            "required": [
+                "brand_id",
+                "category_id",
+                "remote_index",
+                "remote_name"
+            ],
+            "properties": {
+                "brand_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "brand_name": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "remote_index": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "remote_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AlarmTestResponseDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.ApplyLightPresetRequestDTO": {
+            "type": "object",
+            "properties": {
+                "transition_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.ApplyLightPresetResponseDTO": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.LightPresetDeviceResultDTO"
+                    }
+                },
+                "group_id": {
+                    "type": "string"
+                },
+                "preset": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.ArchiveRunStartedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AutomationActionDTO": {
+            "type": "object",
+            "required": [
+                "commands",
+                "device_id"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.AutomationActionResultDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.AutomationConditionDTO": {
+            "type": "object",
+            "required": [
+                "code",
+                "operator",
+                "value"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "operator": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.AutomationHistoryResponseDTO": {
+            "type": "object",
+            "properties": {
+                "rule_id": {
+                    "type": "string"
+                },
+                "runs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AutomationRunDTO"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.AutomationRuleDTO": {
+            "type": "object",
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AutomationActionDTO"
+                    }
+                },
+                "conditions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AutomationConditionDTO"
+                    }
+                },
+                "cooldown_seconds": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_triggered_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.AutomationRuleRequestDTO": {
+            "type": "object",
+            "required": [
+                "actions",
+                "conditions",
+                "name"
+            ],
+            "properties": {
+                "actions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.AutomationActionDTO"
+                    }
+                },
+                "conditions": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.AutomationConditionDTO"
+                    }
+                },
+                "cooldown_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dtos.AutomationRunDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.AutomationActionResultDTO"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "trigger": {
+                    "type": "string"
+                },
+                "triggered_at": {
+                    "type": "integer"
+                },
+                "value": {}
+            }
+        },
+        "dtos.BackupArchiveDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer",
+                    "example": 1767225600
+                },
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.BackupEntryDTO"
+                    }
+                },
+                "format": {
+                    "type": "string",
+                    "example": "teralux-backup"
+                },
+                "rooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.RoomDTO"
+                    }
+                },
+                "version": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dtos.BackupEntryDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "device_state:bf1234567890abcdef"
+                },
+                "value": {
+                    "type": "object"
+                },
+                "value_base64": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.BackupExportStartedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.BackupRestoreResultDTO": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "integer",
+                    "example": 42
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "merge"
+                },
+                "removed": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "restart_required": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "rooms": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dtos.BootstrapDTO": {
+            "type": "object",
+            "properties": {
+                "alerts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.ActiveAlertDTO"
+                    }
+                },
+                "favorites": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaDeviceDTO"
+                    }
+                },
+                "generated_at": {
+                    "type": "integer"
+                },
+                "house_mode": {
+                    "type": "string"
+                },
+                "rooms": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.RoomDTO"
+                    }
+                },
+                "session": {
+                    "$ref": "#/definitions/dtos.BootstrapSessionDTO"
+                },
+                "summary": {
+                    "$ref": "#/definitions/dtos.DeviceSummaryDTO"
+                },
+                "unavailable": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.BootstrapSessionDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.CacheConfigDTO": {
+            "type": "object",
+            "properties": {
+                "ttls": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CacheTTLDTO"
+                    }
+                }
+            }
+        },
+        "dtos.CacheEntryDTO": {
+            "type": "object",
+            "properties": {
+                "encoding": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "ttl_seconds": {
+                    "type": "integer"
+                },
+                "value": {}
+            }
+        },
+        "dtos.CacheGCRunDTO": {
+            "type": "object",
+            "properties": {
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "rewrites": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "integer"
+                },
+                "trigger": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CacheGCStatusDTO": {
+            "type": "object",
+            "properties": {
+                "discard_ratio": {
+                    "type": "number"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "last_run": {
+                    "$ref": "#/definitions/dtos.CacheGCRunDTO"
+                },
+                "running": {
+                    "type": "boolean"
+                },
+                "total_rewrites": {
+                    "type": "integer"
+                },
+                "total_runs": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CacheInvalidationDTO": {
+            "type": "object",
+            "properties": {
+                "deleted": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "prefix": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CacheKeyDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "ttl_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CacheKeysResponseDTO": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CacheKeyDTO"
+                    }
+                },
+                "prefix": {
+                    "type": "string"
+                },
+                "truncated": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.CacheLevelDTO": {
+            "type": "object",
+            "properties": {
+                "is_base_level": {
+                    "type": "boolean"
+                },
+                "level": {
+                    "type": "integer"
+                },
+                "score": {
+                    "type": "number"
+                },
+                "size": {
+                    "type": "integer"
+                },
+                "stale_data_size": {
+                    "type": "integer"
+                },
+                "tables": {
+                    "type": "integer"
+                },
+                "target_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CacheStatsDTO": {
+            "type": "object",
+            "properties": {
+                "gc": {
+                    "$ref": "#/definitions/dtos.CacheGCStatusDTO"
+                },
+                "levels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CacheLevelDTO"
+                    }
+                },
+                "lsm_size": {
+                    "type": "integer"
+                },
+                "total_size": {
+                    "type": "integer"
+                },
+                "vlog_size": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CacheTTLDTO": {
+            "type": "object",
+            "properties": {
+                "default": {
+                    "type": "string"
+                },
+                "overridden": {
+                    "type": "boolean"
+                },
+                "resource": {
+                    "type": "string"
+                },
+                "ttl": {
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CacheWarmStartedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CameraSnapshotDTO": {
+            "type": "object",
+            "properties": {
+                "captured_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CameraStreamDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "hls"
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://wework.ipc.tuyacn.com/live/xxx.m3u8"
+                }
+            }
+        },
+        "dtos.CategoryControlResponseDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.ChannelCommandRequestDTO": {
+            "type": "object",
+            "required": [
+                "value"
+            ],
+            "properties": {
+                "value": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.CircadianConfigDTO": {
+            "type": "object",
+            "properties": {
+                "curve": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CircadianPointDTO"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "lights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CircadianLightDTO"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CircadianDispatchResultDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CircadianLightDTO": {
+            "type": "object",
+            "required": [
+                "device_id"
+            ],
+            "properties": {
+                "curve": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CircadianPointDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "temperature_only": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.CircadianLightStatusDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "last_dispatch_at": {
+                    "type": "integer"
+                },
+                "overridden_until": {
+                    "type": "integer"
+                },
+                "target_brightness": {
+                    "type": "integer"
+                },
+                "target_temperature": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CircadianPointDTO": {
+            "type": "object",
+            "required": [
+                "time"
+            ],
+            "properties": {
+                "brightness": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "temperature": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                },
+                "time": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CircadianStatusDTO": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "$ref": "#/definitions/dtos.CircadianConfigDTO"
+                },
+                "lights": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CircadianLightStatusDTO"
+                    }
+                }
+            }
+        },
+        "dtos.ClimateCapabilityDTO": {
+            "type": "object",
+            "properties": {
+                "fan_speeds": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_temp": {
+                    "type": "number",
+                    "example": 30
+                },
+                "min_temp": {
+                    "type": "number",
+                    "example": 16
+                },
+                "modes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "swing": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.ClimateControlRequestDTO": {
+            "type": "object",
+            "properties": {
+                "fan_speed": {
+                    "type": "string",
+                    "enum": [
+                        "auto",
+                        "low",
+                        "medium",
+                        "high"
+                    ],
+                    "example": "auto"
+                },
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "cool",
+                        "heat",
+                        "auto",
+                        "fan",
+                        "dry"
+                    ],
+                    "example": "cool"
+                },
+                "power": {
+                    "type": "boolean"
+                },
+                "swing": {
+                    "type": "boolean"
+                },
+                "target_temp": {
+                    "type": "number",
+                    "example": 24
+                }
+            }
+        },
+        "dtos.ClimateStateDTO": {
+            "type": "object",
+            "properties": {
+                "current_temp": {
+                    "type": "number",
+                    "example": 27.5
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "fan_speed": {
+                    "type": "string",
+                    "example": "auto"
+                },
+                "mode": {
+                    "type": "string",
+                    "example": "cool"
+                },
+                "power": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string",
+                    "example": "ir"
+                },
+                "supports": {
+                    "$ref": "#/definitions/dtos.ClimateCapabilityDTO"
+                },
+                "swing": {
+                    "type": "boolean"
+                },
+                "target_temp": {
+                    "type": "number",
+                    "example": 24
+                }
+            }
+        },
+        "dtos.CloudAutomationDTO": {
+            "type": "object",
+            "properties": {
+                "action_count": {
+                    "type": "integer"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "background": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CloudAutomationsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "automations": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CloudAutomationDTO"
+                    }
+                },
+                "home_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CloudSceneActionResultDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "automation_id": {
+                    "type": "string"
+                },
+                "home_id": {
+                    "type": "string"
+                },
+                "scene_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CloudSceneDTO": {
+            "type": "object",
+            "properties": {
+                "action_count": {
+                    "type": "integer"
+                },
+                "background": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "scene_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CloudScenesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "home_id": {
+                    "type": "string"
+                },
+                "scenes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.CloudSceneDTO"
+                    }
+                }
+            }
+        },
+        "dtos.CommandApprovalRuleDTO": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "ttl_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CommandCooldownDTO": {
+            "type": "object",
+            "properties": {
+                "cooldown_ms": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "last_command_at": {
+                    "type": "integer"
+                },
+                "last_failure": {
+                    "type": "string"
+                },
+                "last_failure_at": {
+                    "type": "integer"
+                },
+                "last_failure_gap_ms": {
+                    "type": "integer"
+                },
+                "rapid_failures": {
+                    "type": "integer"
+                },
+                "success_streak": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.CommandStatusDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "idempotency_key": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.ConfigDTO": {
+            "type": "object",
+            "properties": {
+                "problems": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "settings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.ConfigSettingDTO"
+                    }
+                },
+                "valid": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.ConfigSettingDTO": {
+            "type": "object",
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "required": {
+                    "type": "boolean"
+                },
+                "secret": {
+                    "type": "boolean"
+                },
+                "set": {
+                    "type": "boolean"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateLightGroupRequestDTO": {
+            "type": "object",
+            "required": [
+                "device_ids",
+                "name"
+            ],
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.CreateRoomRequestDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100
+                }
+            }
+        },
+        "dtos.DeviceCategoryFilterDTO": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "deny": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceChangeDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "new_value": {
+                    "type": "string"
+                },
+                "old_value": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string",
+                    "example": "removed"
+                }
+            }
+        },
+        "dtos.DeviceChangeLogEntryDTO": {
+            "type": "object",
+            "properties": {
+                "changes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceChangeDTO"
+                    }
+                },
+                "detected_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceChangeLogResponseDTO": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceChangeLogEntryDTO"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceChannelDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "index": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.DeviceClaimDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "decided_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "uid": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DeviceComparisonDTO": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceComparisonSideDTO"
+                    }
+                },
+                "differences": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceDifferenceDTO"
+                    }
+                },
+                "same_product": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.DeviceComparisonSideDTO": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "commands": {
+                    "type": "integer"
+                },
+                "failed_commands": {
+                    "type": "integer"
+                },
+                "firmware": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "online": {
+                    "type": "boolean"
+                },
+                "product_id": {
+                    "type": "string"
+                },
+                "product_name": {
+                    "type": "string"
+                },
+                "unavailable": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.DeviceDifferenceDTO": {
+            "type": "object",
+            "properties": {
+                "a": {},
+                "b": {},
+                "field": {
+                    "type": "string"
+                },
+                "section": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DeviceEventDTO": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "priority": {
+                    "type": "string"
+                },
+                "simulated": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceEventStatusDTO"
+                    }
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DeviceEventStatusDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.DeviceFavoriteRequestDTO": {
+            "type": "object",
+            "properties": {
+                "favorite": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.DeviceMacroDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.MacroCommandDTO"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.MacroParameterDTO"
+                    }
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceMetadataDTO": {
+            "type": "object",
+            "properties": {
+                "favorite": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string",
+                    "example": "lamp"
+                },
+                "label": {
+                    "type": "string",
+                    "example": "Bedside lamp"
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceOrderRequestDTO": {
+            "type": "object",
+            "required": [
+                "device_ids"
+            ],
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.DeviceOrderResponseDTO": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.DevicePresetDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PresetCommandDTO"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceStateCommandDTO": {
+            "type": "object",
+            "required": [
+                "code",
+                "value"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.DeviceStateDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "last_commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceStateCommandDTO"
+                    }
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceStateHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "current": {
+                    "$ref": "#/definitions/dtos.DeviceStateDTO"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceStateDTO"
+                    }
+                }
+            }
+        },
+        "dtos.DeviceStatusEntryDTO": {
+            "type": "object",
+            "properties": {
+                "id": {
+                    "type": "string"
+                },
+                "online": {
+                    "type": "boolean"
+                },
+                "status": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "dtos.DeviceStatusSnapshotDTO": {
+            "type": "object",
+            "properties": {
+                "delta": {
+                    "type": "boolean"
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceStatusEntryDTO"
+                    }
+                },
+                "removed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "version": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.DeviceSummaryDTO": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                },
+                "offline": {
+                    "type": "integer"
+                },
+                "online": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DeviceTimerDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "countdown_code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "delay_seconds": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "fires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "remaining_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.DimmerControlRequestDTO": {
+            "type": "object",
+            "properties": {
+                "brightness": {
+                    "type": "integer"
+                },
+                "power": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.ExecuteDevicePresetResponseDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "preset": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.FanControlRequestDTO": {
+            "type": "object",
+            "properties": {
+                "direction": {
+                    "type": "string"
+                },
+                "oscillation": {
+                    "type": "boolean"
+                },
+                "power": {
+                    "type": "boolean"
+                },
+                "speed": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.FavoritesRequestDTO": {
+            "type": "object",
+            "required": [
+                "device_ids"
+            ],
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.FavoritesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.FeatureFlagDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excluded_devices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "percentage": {
+                    "type": "integer"
+                },
+                "source": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.FeatureFlagEvaluationDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.GenerateSwaggerExamplesRequestDTO": {
+            "type": "object",
+            "required": [
+                "device_id"
+            ],
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.HealthComponentDTO": {
+            "type": "object",
+            "properties": {
+                "backend": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.HealthStatusDTO": {
+            "type": "object",
+            "properties": {
+                "cache": {
+                    "$ref": "#/definitions/dtos.HealthComponentDTO"
+                },
+                "database": {
+                    "$ref": "#/definitions/dtos.HealthComponentDTO"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.HouseModeDTO": {
+            "type": "object",
+            "properties": {
+                "changed_at": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "poll_interval": {
+                    "type": "string"
+                },
+                "previous_mode": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRBrandDTO": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRBrandsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "brands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.IRBrandDTO"
+                    }
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "infrared_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRCategoriesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "categories": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.IRCategoryDTO"
+                    }
+                },
+                "infrared_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRCategoryDTO": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRLearnedCodeDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "learned": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.IRLearningSessionDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "infrared_id": {
+                    "type": "string"
+                },
+                "learning_time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.IRNamedCodeDTO": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "infrared_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.IRRemoteCommandRequestDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string",
+                    "example": "power"
+                },
+                "key_id": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.IRRemoteCommandResponseDTO": {
+            "type": "object",
+            "properties": {
+                "deduplicated": {
+                    "type": "boolean"
+                },
+                "key": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "integer"
+                },
+                "remote_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRRemoteDTO": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "integer"
+                },
+                "brand_name": {
+                    "type": "string"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "remote_id": {
+                    "type": "string"
+                },
+                "remote_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRRemoteIndexesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "brand_id": {
+                    "type": "integer"
+                },
+                "category_id": {
+                    "type": "integer"
+                },
+                "infrared_id": {
+                    "type": "string"
+                },
+                "remote_indexes": {
+                    "type": "array",
+                    "items": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "dtos.IRRemoteKeyDTO": {
+            "type": "object",
+            "properties": {
+                "key": {
+                    "type": "string"
+                },
+                "key_id": {
+                    "type": "integer"
+                },
+                "key_name": {
+                    "type": "string"
+                },
+                "standard_key": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.IRRemoteKeysResponseDTO": {
+            "type": "object",
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "infrared_id": {
+                    "type": "string"
+                },
+                "keys": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.IRRemoteKeyDTO"
+                    }
+                },
+                "remote_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.IRRemotesResponseDTO": {
+            "type": "object",
+            "properties": {
+                "infrared_id": {
+                    "type": "string"
+                },
+                "remotes": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.IRRemoteDTO"
+                    }
+                }
+            }
+        },
+        "dtos.IdentityLoginRequestDTO": {
+            "type": "object",
+            "required": [
+                "provider"
+            ],
+            "properties": {
+                "id_token": {
+                    "type": "string"
+                },
+                "password": {
+                    "type": "string"
+                },
+                "provider": {
+                    "type": "string",
+                    "example": "ldap"
+                },
+                "username": {
+                    "type": "string",
+                    "example": "jdoe"
+                }
+            }
+        },
+        "dtos.IdentityProviderDTO": {
+            "type": "object",
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "example": "oidc"
+                }
+            }
+        },
+        "dtos.IdentityTokenDTO": {
+            "type": "object",
+            "properties": {
+                "expires_at": {
+                    "type": "integer"
+                },
+                "groups": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "provider": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string",
+                    "example": "admin"
+                },
+                "subject": {
+                    "type": "string"
+                },
+                "token": {
+                    "type": "string"
+                },
+                "token_type": {
+                    "type": "string",
+                    "example": "Bearer"
+                }
+            }
+        },
+        "dtos.IntentDeviceResultDTO": {
+            "type": "object",
+            "properties": {
+                "channel": {
+                    "type": "string"
+                },
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.IntentRequestDTO": {
+            "type": "object",
+            "required": [
+                "text"
+            ],
+            "properties": {
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "text": {
+                    "type": "string",
+                    "example": "turn off meeting room lights"
+                }
+            }
+        },
+        "dtos.IntentResultDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.IntentDeviceResultDTO"
+                    }
+                },
+                "dry_run": {
+                    "type": "boolean"
+                },
+                "room_id": {
+                    "type": "string"
+                },
+                "room_name": {
+                    "type": "string"
+                },
+                "summary": {
+                    "type": "string"
+                },
+                "text": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.JobDTO": {
+            "type": "object",
+            "properties": {
+                "attempts": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "finished_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "max_attempts": {
+                    "type": "integer"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "progress": {
+                    "type": "integer"
+                },
+                "result": {},
+                "run_at": {
+                    "type": "integer"
+                },
+                "started_at": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.JobListResponseDTO": {
+            "type": "object",
+            "properties": {
+                "jobs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.JobDTO"
+                    }
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.LightColourDTO": {
+            "type": "object",
+            "properties": {
+                "hue": {
+                    "type": "integer",
+                    "maximum": 360,
+                    "minimum": 0
+                },
+                "saturation": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.LightGroupDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "presets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.LightPresetDTO"
+                    }
+                }
+            }
+        },
+        "dtos.LightPresetDTO": {
+            "type": "object",
+            "properties": {
+                "built_in": {
+                    "type": "boolean"
+                },
+                "devices": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dtos.LightSettingsDTO"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                },
+                "settings": {
+                    "$ref": "#/definitions/dtos.LightSettingsDTO"
+                },
+                "transition_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.LightPresetDeviceResultDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                },
+                "transition": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.LightSettingsDTO": {
+            "type": "object",
+            "required": [
+                "brightness"
+            ],
+            "properties": {
+                "brightness": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 1
+                },
+                "colour": {
+                    "$ref": "#/definitions/dtos.LightColourDTO"
+                },
+                "temperature": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.LoadStatusDTO": {
+            "type": "object",
+            "properties": {
+                "in_flight": {
+                    "type": "integer"
+                },
+                "low_priority_routes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_in_flight": {
+                    "type": "integer"
+                },
+                "max_queue_depth": {
+                    "type": "integer"
+                },
+                "max_upstream_latency_ms": {
+                    "type": "integer"
+                },
+                "overloaded": {
+                    "type": "boolean"
+                },
+                "queue_depth": {
+                    "type": "integer"
+                },
+                "reasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "retry_after_seconds": {
+                    "type": "integer"
+                },
+                "shed_by_route": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer",
+                        "format": "int64"
+                    }
+                },
+                "shed_total": {
+                    "type": "integer"
+                },
+                "upstream_latency_ms": {
+                    "type": "integer"
+                },
+                "upstream_sampled_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.LocalSceneDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "rollback_on_failure": {
+                    "type": "boolean"
+                },
+                "steps": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.LocalSceneStepDTO"
+                    }
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.LocalSceneRunStartedDTO": {
+            "type": "object",
+            "properties": {
+                "job_id": {
+                    "type": "string"
+                },
+                "scene_id": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "steps": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.LocalSceneStepDTO": {
+            "type": "object",
+            "required": [
+                "device_id"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PresetCommandDTO"
+                    }
+                },
+                "delay_seconds": {
+                    "type": "integer",
+                    "maximum": 3600,
+                    "minimum": 0
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "preset": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.LockRecordDTO": {
+            "type": "object",
+            "properties": {
+                "method": {
+                    "type": "string",
+                    "example": "unlock_fingerprint"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "user": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.LockRecordsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "page_size": {
+                    "type": "integer"
+                },
+                "records": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.LockRecordDTO"
+                    }
+                },
+                "to": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.MQTTBridgeStatusDTO": {
+            "type": "object",
+            "properties": {
+                "base_topic": {
+                    "type": "string"
+                },
+                "broker": {
+                    "type": "string"
+                },
+                "connected": {
+                    "type": "boolean"
+                },
+                "connected_at": {
+                    "type": "integer"
+                },
+                "devices": {
+                    "type": "integer"
+                },
+                "discovery_prefix": {
+                    "type": "string"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "entities": {
+                    "type": "integer"
+                },
+                "last_command_at": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_sync_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.MacroCommandDTO": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.MacroParameterDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "type"
+            ],
+            "properties": {
+                "default": {},
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "options": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "number",
+                        "integer",
+                        "boolean",
+                        "string"
+                    ]
+                }
+            }
+        },
+        "dtos.NotificationChannelDTO": {
+            "type": "object",
+            "required": [
+                "target",
+                "type"
+            ],
+            "properties": {
+                "target": {
+                    "type": "string",
+                    "maxLength": 500,
+                    "example": "123456789"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "webhook",
+                        "email",
+                        "telegram"
+                    ],
+                    "example": "telegram"
+                }
+            }
+        },
+        "dtos.NotificationRuleDTO": {
+            "type": "object",
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.NotificationChannelDTO"
+                    }
+                },
+                "cooldown_minutes": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_notified_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "notify_online": {
+                    "type": "boolean"
+                },
+                "offline_minutes": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.NotificationRuleRequestDTO": {
+            "type": "object",
+            "required": [
+                "channels"
+            ],
+            "properties": {
+                "channels": {
+                    "type": "array",
+                    "maxItems": 5,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.NotificationChannelDTO"
+                    }
+                },
+                "cooldown_minutes": {
+                    "type": "integer",
+                    "maximum": 10080,
+                    "minimum": 0,
+                    "example": 60
+                },
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "Freezer offline"
+                },
+                "notify_online": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "offline_minutes": {
+                    "type": "integer",
+                    "maximum": 10080,
+                    "minimum": 0,
+                    "example": 10
+                }
+            }
+        },
+        "dtos.NotificationTestResultDTO": {
+            "type": "object",
+            "properties": {
+                "delivered": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "target": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.OutboundValidationDTO": {
+            "type": "object",
+            "properties": {
+                "allowed": {
+                    "type": "boolean"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.OutboundValidationRequestDTO": {
+            "type": "object",
+            "required": [
+                "url"
+            ],
+            "properties": {
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.PendingActionDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "decided_at": {
+                    "type": "integer"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "requested_at": {
+                    "type": "integer"
+                },
+                "requested_by": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.PermissionCheckDTO": {
+            "type": "object",
+            "properties": {
+                "description": {
+                    "type": "string"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "hint": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tuya_code": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.PermissionReportDTO": {
+            "type": "object",
+            "properties": {
+                "checked_at": {
+                    "type": "integer"
+                },
+                "checks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.PermissionCheckDTO"
+                    }
+                },
+                "healthy": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.PresetCommandDTO": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.QueueCommandRequestDTO": {
+            "type": "object",
+            "required": [
+                "commands",
+                "device_id"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.QuotaEndpointDTO": {
+            "type": "object",
+            "properties": {
+                "budget": {
+                    "type": "integer"
+                },
+                "calls": {
+                    "type": "integer"
+                },
+                "endpoint": {
+                    "type": "string"
+                },
+                "projected": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.QuotaUsageDTO": {
+            "type": "object",
+            "properties": {
+                "daily_budget": {
+                    "type": "integer"
+                },
+                "date": {
+                    "type": "string"
+                },
+                "endpoints": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.QuotaEndpointDTO"
+                    }
+                },
+                "projected_total": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "total_calls": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.RealtimeTicketDTO": {
+            "type": "object",
+            "properties": {
+                "expires_in": {
+                    "type": "integer"
+                },
+                "ticket": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.RejectDeviceClaimRequestDTO": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "dtos.RejectPendingActionRequestDTO": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "dtos.RenameChannelsRequestDTO": {
+            "type": "object",
+            "required": [
+                "names"
+            ],
+            "properties": {
+                "names": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.RenameDeviceRequestDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "example": "Bedroom Light"
+                }
+            }
+        },
+        "dtos.ReplicationStatusDTO": {
+            "type": "object",
+            "properties": {
+                "last_error": {
+                    "type": "string"
+                },
+                "restored_at": {
+                    "type": "integer"
+                },
+                "restored_version": {
+                    "type": "integer"
+                },
+                "shipped_at": {
+                    "type": "integer"
+                },
+                "shipped_version": {
+                    "type": "integer"
+                },
+                "shipping": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.RolloutStartedDTO": {
+            "type": "object",
+            "properties": {
+                "canary_size": {
+                    "type": "integer"
+                },
+                "devices": {
+                    "type": "integer"
+                },
+                "job_id": {
+                    "type": "string"
+                },
+                "max_error_rate": {
+                    "type": "number"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "wave_size": {
+                    "type": "integer"
+                },
+                "waves": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.RoomCommandRequestDTO": {
+            "type": "object",
+            "required": [
+                "commands"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                }
+            }
+        },
+        "dtos.RoomCommandResponseDTO": {
+            "type": "object",
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.RoomDeviceResultDTO"
+                    }
+                },
+                "room_id": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.RoomDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.RoomDeviceResultDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.RunDeviceMacroRequestDTO": {
+            "type": "object",
+            "properties": {
+                "params": {
+                    "type": "object",
+                    "additionalProperties": true
+                }
+            }
+        },
+        "dtos.RunDeviceMacroResponseDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "macro": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.SaveDeviceMacroRequestDTO": {
+            "type": "object",
+            "required": [
+                "commands"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.MacroCommandDTO"
+                    }
+                },
+                "parameters": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.MacroParameterDTO"
+                    }
+                }
+            }
+        },
+        "dtos.SaveDevicePresetRequestDTO": {
+            "type": "object",
+            "required": [
+                "commands",
+                "name"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.PresetCommandDTO"
+                    }
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SaveIRLearnedKeyRequestDTO": {
+            "type": "object",
+            "required": [
+                "code",
+                "key_name"
+            ],
+            "properties": {
+                "category_id": {
+                    "type": "integer"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "key_name": {
+                    "type": "string"
+                },
+                "remote_id": {
+                    "type": "string"
+                },
+                "remote_name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SaveIRLearnedKeyResponseDTO": {
+            "type": "object",
+            "properties": {
+                "key_name": {
+                    "type": "string"
+                },
+                "remote_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SaveIRNamedCodeRequestDTO": {
+            "type": "object",
+            "required": [
+                "name"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "learning_time": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SaveLightPresetRequestDTO": {
+            "type": "object",
+            "required": [
+                "settings"
+            ],
+            "properties": {
+                "settings": {
+                    "$ref": "#/definitions/dtos.LightSettingsDTO"
+                },
+                "transition_ms": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.SaveLocalSceneRequestDTO": {
+            "type": "object",
+            "required": [
+                "name",
+                "steps"
+            ],
+            "properties": {
+                "name": {
+                    "type": "string"
+                },
+                "rollback_on_failure": {
+                    "type": "boolean"
+                },
+                "steps": {
+                    "type": "array",
+                    "maxItems": 50,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.LocalSceneStepDTO"
+                    }
+                }
+            }
+        },
+        "dtos.SceneSwitchActionDTO": {
+            "type": "object",
+            "required": [
+                "type"
+            ],
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "target_id": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SceneSwitchBindingDTO": {
+            "type": "object",
+            "required": [
+                "action",
+                "button",
+                "press_type"
+            ],
+            "properties": {
+                "action": {
+                    "$ref": "#/definitions/dtos.SceneSwitchActionDTO"
+                },
+                "button": {
+                    "type": "integer",
+                    "minimum": 1
+                },
+                "press_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SceneSwitchBindingsRequestDTO": {
+            "type": "object",
+            "properties": {
+                "bindings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SceneSwitchBindingDTO"
+                    }
+                }
+            }
+        },
+        "dtos.SceneSwitchBindingsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "bindings": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SceneSwitchBindingDTO"
+                    }
+                },
+                "switch_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SceneSwitchEventDTO": {
+            "type": "object",
+            "required": [
+                "code",
+                "value"
+            ],
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.SceneSwitchEventResultDTO": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "button": {
+                    "type": "integer"
+                },
+                "matched": {
+                    "type": "boolean"
+                },
+                "press_type": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SendIRNamedCodeResponseDTO": {
+            "type": "object",
+            "properties": {
+                "infrared_id": {
+                    "type": "string"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.SensorAlarmDTO": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "code": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "value": {}
+            }
+        },
+        "dtos.SensorDataDTO": {
+            "type": "object",
+            "properties": {
+                "alarm": {
+                    "$ref": "#/definitions/dtos.SensorAlarmDTO"
+                },
+                "battery_percentage": {
+                    "type": "integer"
+                },
+                "category": {
+                    "type": "string"
+                },
+                "humidity": {
+                    "type": "integer"
+                },
+                "status_text": {
+                    "type": "string"
+                },
+                "temp_unit": {
+                    "type": "string"
+                },
+                "temperature": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.SensorHistoryDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "interval": {
+                    "type": "integer"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SensorHistoryPointDTO"
+                    }
+                },
+                "timezone": {
+                    "type": "string"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.SensorHistoryPointDTO": {
+            "type": "object",
+            "properties": {
+                "battery": {
+                    "$ref": "#/definitions/dtos.SensorStatsDTO"
+                },
+                "humidity": {
+                    "$ref": "#/definitions/dtos.SensorStatsDTO"
+                },
+                "temperature": {
+                    "$ref": "#/definitions/dtos.SensorStatsDTO"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.SensorStatsDTO": {
+            "type": "object",
+            "properties": {
+                "avg": {
+                    "type": "number"
+                },
+                "last": {
+                    "type": "number"
+                },
+                "max": {
+                    "type": "number"
+                },
+                "min": {
+                    "type": "number"
+                },
+                "samples": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.SensorThresholdDTO": {
+            "type": "object",
+            "required": [
+                "code"
+            ],
+            "properties": {
+                "above": {
+                    "type": "number",
+                    "example": 300
+                },
+                "below": {
+                    "type": "number"
+                },
+                "code": {
+                    "type": "string",
+                    "example": "va_temperature"
+                },
+                "device_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SerializationPathDTO": {
+            "type": "object",
+            "properties": {
+                "marshal_bytes": {
+                    "type": "integer"
+                },
+                "marshal_calls": {
+                    "type": "integer"
+                },
+                "marshal_time_ms": {
+                    "type": "integer"
+                },
+                "path": {
+                    "type": "string"
+                },
+                "payload_bytes_saved": {
+                    "type": "integer"
+                },
+                "payload_cache_hits": {
+                    "type": "integer"
+                },
+                "payload_cache_misses": {
+                    "type": "integer"
+                },
+                "unmarshal_bytes": {
+                    "type": "integer"
+                },
+                "unmarshal_calls": {
+                    "type": "integer"
+                },
+                "unmarshal_time_ms": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.SerializationStatsDTO": {
+            "type": "object",
+            "properties": {
+                "paths": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SerializationPathDTO"
+                    }
+                }
+            }
+        },
+        "dtos.SetCommandApprovalRuleRequestDTO": {
+            "type": "object",
+            "properties": {
+                "codes": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "switch_1"
+                    ]
+                },
+                "ttl_seconds": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 30
+                }
+            }
+        },
+        "dtos.SetDeviceTimerRequestDTO": {
+            "type": "object",
+            "required": [
+                "action",
+                "delay_seconds"
+            ],
+            "properties": {
+                "action": {
+                    "type": "string",
+                    "enum": [
+                        "on",
+                        "off"
+                    ]
+                },
+                "code": {
+                    "type": "string"
+                },
+                "delay_seconds": {
+                    "type": "integer",
+                    "maximum": 86400,
+                    "minimum": 1
+                }
+            }
+        },
+        "dtos.SetHouseModeRequestDTO": {
+            "type": "object",
+            "required": [
+                "mode"
+            ],
+            "properties": {
+                "mode": {
+                    "type": "string",
+                    "enum": [
+                        "home",
+                        "away",
+                        "sleep",
+                        "holiday"
+                    ]
+                }
+            }
+        },
+        "dtos.SetupRequestDTO": {
+            "type": "object",
+            "properties": {
+                "tuya_access_secret": {
+                    "type": "string",
+                    "example": "your_tuya_access_secret"
+                },
+                "tuya_base_url": {
+                    "type": "string",
+                    "example": "https://openapi.tuyaus.com"
+                },
+                "tuya_client_id": {
+                    "type": "string",
+                    "example": "your_tuya_client_id"
+                },
+                "tuya_user_id": {
+                    "type": "string",
+                    "example": "your_tuya_user_id"
+                }
+            }
+        },
+        "dtos.SetupResultDTO": {
+            "type": "object",
+            "properties": {
+                "api_key": {
+                    "type": "string"
+                },
+                "env_file": {
+                    "type": "string"
+                },
+                "tuya_user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.SetupStatusDTO": {
+            "type": "object",
+            "properties": {
+                "setup_required": {
+                    "type": "boolean"
+                },
+                "tuya_configured": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.StandardResponse": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "meta": {},
+                "status": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.StandbyKillerRuleDTO": {
+            "type": "object",
+            "properties": {
+                "below_since": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "duration_minutes": {
+                    "type": "integer"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "last_checked_at": {
+                    "type": "integer"
+                },
+                "last_killed_at": {
+                    "type": "integer"
+                },
+                "last_watts": {
+                    "type": "number"
+                },
+                "switch_code": {
+                    "type": "string"
+                },
+                "threshold_watts": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.StandbyKillerRuleRequestDTO": {
+            "type": "object",
+            "required": [
+                "duration_minutes",
+                "threshold_watts"
+            ],
+            "properties": {
+                "duration_minutes": {
+                    "type": "integer",
+                    "maximum": 1440,
+                    "minimum": 1
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "switch_code": {
+                    "type": "string"
+                },
+                "threshold_watts": {
+                    "type": "number"
+                }
+            }
+        },
+        "dtos.StartRolloutRequestDTO": {
+            "type": "object",
+            "required": [
+                "commands",
+                "device_ids"
+            ],
+            "properties": {
+                "canary_size": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "commands": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_ids": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "max_error_rate": {
+                    "type": "number",
+                    "maximum": 1,
+                    "minimum": 0
+                },
+                "wave_delay_seconds": {
+                    "type": "integer",
+                    "minimum": 0
+                },
+                "wave_size": {
+                    "type": "integer",
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.SubmitDeviceClaimRequestDTO": {
+            "type": "object",
+            "required": [
+                "device_id"
+            ],
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string",
+                    "maxLength": 200
+                }
+            }
+        },
+        "dtos.SwaggerExamplesDTO": {
+            "type": "object",
+            "properties": {
+                "category": {
+                    "type": "string"
+                },
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "device_name": {
+                    "type": "string"
+                },
+                "generated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.TempPasswordDTO": {
+            "type": "object",
+            "properties": {
+                "effective_time": {
+                    "type": "integer"
+                },
+                "invalid_time": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "password_id": {
+                    "type": "integer"
+                },
+                "phase": {
+                    "type": "string",
+                    "example": "active"
+                },
+                "phone": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.TempPasswordRequestDTO": {
+            "type": "object",
+            "required": [
+                "invalid_time",
+                "name",
+                "password"
+            ],
+            "properties": {
+                "effective_time": {
+                    "type": "integer",
+                    "example": 1760600000
+                },
+                "invalid_time": {
+                    "type": "integer",
+                    "example": 1760686400
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "Cleaner"
+                },
+                "password": {
+                    "type": "string",
+                    "maxLength": 10,
+                    "minLength": 6,
+                    "example": "1234567"
+                },
+                "phone": {
+                    "type": "string",
+                    "example": "81234567890"
+                },
+                "time_zone": {
+                    "type": "string",
+                    "example": "+07:00"
                 }
             }
         },
-        "/health": {
-            "get": {
-                "description": "Check if the application and database are healthy",
-                "produces": [
-                    "text/plain"
-                ],
-                "tags": [
-                    "Health"
-                ],
-                "summary": "Health check endpoint",
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "type": "string"
-                        }
-                    },
-                    "503": {
-                        "description": "Service Unavailable",
-                        "schema": {
-                            "type": "string"
-                        }
+        "dtos.TempPasswordsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "device_id": {
+                    "type": "string"
+                },
+                "passwords": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TempPasswordDTO"
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "dtos.SensorDataDTO": {
+        },
+        "dtos.TuyaAssetDTO": {
             "type": "object",
             "properties": {
-                "battery_percentage": {
-                    "type": "integer"
-                },
-                "humidity": {
-                    "type": "integer"
-                },
-                "status_text": {
+                "asset_id": {
                     "type": "string"
                 },
-                "temp_unit": {
+                "name": {
                     "type": "string"
                 },
-                "temperature": {
-                    "type": "number"
+                "parent_asset_id": {
+                    "type": "string"
                 }
             }
         },
-        "dtos.StandardResponse": {
+        "dtos.TuyaAssetDevicesResponseDTO": {
             "type": "object",
             "properties": {
-                "data": {},
-                "message": {
+                "asset_id": {
                     "type": "string"
                 },
-                "status": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaDeviceDTO"
+                    }
+                },
+                "total_devices": {
+                    "type": "integer"
+                }
+            }
+        },
+        "dtos.TuyaAssetsResponseDTO": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.TuyaAssetDTO"
+                    }
+                },
+                "has_more": {
                     "type": "boolean"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "page": {
+                    "type": "integer"
+                },
+                "total": {
+                    "type": "integer"
                 }
             }
         },
@@ -504,6 +15513,12 @@ const docTemplate = `{
                 "category": {
                     "type": "string"
                 },
+                "channels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceChannelDTO"
+                    }
+                },
                 "collections": {
                     "type": "array",
                     "items": {
@@ -531,6 +15546,9 @@ const docTemplate = `{
                 "local_key": {
                     "type": "string"
                 },
+                "metadata": {
+                    "$ref": "#/definitions/dtos.DeviceMetadataDTO"
+                },
                 "model": {
                     "type": "string"
                 },
@@ -597,6 +15615,23 @@ const docTemplate = `{
                 }
             }
         },
+        "dtos.TuyaHomeDTO": {
+            "type": "object",
+            "properties": {
+                "geo_name": {
+                    "type": "string"
+                },
+                "home_id": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "role": {
+                    "type": "string"
+                }
+            }
+        },
         "dtos.TuyaIRACCommandDTO": {
             "type": "object",
             "required": [
@@ -614,6 +15649,242 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "dtos.UndoDeviceStateResponseDTO": {
+            "type": "object",
+            "properties": {
+                "commands": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.DeviceStateCommandDTO"
+                    }
+                },
+                "device_id": {
+                    "type": "string"
+                },
+                "restored_from": {
+                    "type": "integer"
+                },
+                "success": {
+                    "type": "boolean"
+                }
+            }
+        },
+        "dtos.UpdateCacheConfigRequestDTO": {
+            "type": "object",
+            "required": [
+                "ttls"
+            ],
+            "properties": {
+                "ttls": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "dtos.UpdateDeviceCategoryFilterRequestDTO": {
+            "type": "object",
+            "properties": {
+                "allow": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "dj",
+                        "kg",
+                        "infrared_ac"
+                    ]
+                },
+                "deny": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "sp"
+                    ]
+                }
+            }
+        },
+        "dtos.UpdateDeviceMetadataRequestDTO": {
+            "type": "object",
+            "properties": {
+                "favorite": {
+                    "type": "boolean"
+                },
+                "icon": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "example": "lamp"
+                },
+                "label": {
+                    "type": "string",
+                    "maxLength": 60,
+                    "example": "Bedside lamp"
+                },
+                "sort_order": {
+                    "type": "integer",
+                    "example": 1
+                }
+            }
+        },
+        "dtos.UpdateFeatureFlagRequestDTO": {
+            "type": "object",
+            "required": [
+                "percentage"
+            ],
+            "properties": {
+                "devices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "excluded_devices": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "percentage": {
+                    "type": "integer",
+                    "maximum": 100,
+                    "minimum": 0
+                }
+            }
+        },
+        "dtos.UpdateRoomRequestDTO": {
+            "type": "object",
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "minLength": 1
+                }
+            }
+        },
+        "dtos.WebhookDTO": {
+            "type": "object",
+            "properties": {
+                "created_at": {
+                    "type": "integer"
+                },
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_delivery_at": {
+                    "type": "integer"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "last_status_code": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "thresholds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SensorThresholdDTO"
+                    }
+                },
+                "updated_at": {
+                    "type": "integer"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "dtos.WebhookRequestDTO": {
+            "type": "object",
+            "required": [
+                "events",
+                "url"
+            ],
+            "properties": {
+                "device_ids": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "events": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "device_offline",
+                        "command_failed"
+                    ]
+                },
+                "name": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "n8n alerts"
+                },
+                "thresholds": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dtos.SensorThresholdDTO"
+                    }
+                },
+                "url": {
+                    "type": "string",
+                    "example": "https://n8n.example.com/webhook/teralux"
+                }
+            }
+        },
+        "dtos.WebhookTestResultDTO": {
+            "type": "object",
+            "properties": {
+                "delivered": {
+                    "type": "boolean"
+                },
+                "duration_ms": {
+                    "type": "integer"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                }
+            }
         }
     },
     "securityDefinitions": {
@@ -653,6 +15924,50 @@ const docTemplate = `{
         {
             "description": "Health check endpoints",
             "name": "06. Health"
+        },
+        {
+            "description": "Realtime device event subscriptions",
+            "name": "07. Realtime"
+        },
+        {
+            "description": "Administrative endpoints (API key)",
+            "name": "08. Admin"
+        },
+        {
+            "description": "Background job status and cancellation",
+            "name": "09. Jobs"
+        },
+        {
+            "description": "Rooms and room-wide device commands",
+            "name": "10. Rooms"
+        },
+        {
+            "description": "Automation rules triggered by device states",
+            "name": "11. Automations"
+        },
+        {
+            "description": "Global house mode (home, away, sleep, holiday)",
+            "name": "12. House Mode"
+        },
+        {
+            "description": "Cold start payload for the app",
+            "name": "13. Bootstrap"
+        },
+        {
+            "description": "Login through OIDC or LDAP identity providers",
+            "name": "17. Identity"
+        },
+        {
+            "description": "Unlock records and temporary passwords of smart locks",
+            "name": "18. Door Locks"
+        },
+        {
+            "description": "Live streams and snapshots of smart cameras",
+            "name": "19. Cameras"
+        },
+        {
+            "description": "Whole-house scenes composed across devices and stored by Teralux",
+            "name": "21. Scenes"
         }
     ]
 }`
@@ -673,4 +15988,4 @@ var SwaggerInfo = &swag.Spec{
 
 func init() {
 	swag.Register(SwaggerInfo.InstanceName(), SwaggerInfo)
-}
\ No newline at end of file
+}
@@ -0,0 +1,149 @@
+package controllers
+
+import (
+	"net/http"
+	apikey_dtos "teralux_app/domain/apikeys/dtos"
+	"teralux_app/domain/apikeys/usecases"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyController handles the administration of API keys
+type APIKeyController struct {
+	useCase *usecases.APIKeyUseCase
+}
+
+// NewAPIKeyController creates a new APIKeyController instance
+func NewAPIKeyController(useCase *usecases.APIKeyUseCase) *APIKeyController {
+	return &APIKeyController{
+		useCase: useCase,
+	}
+}
+
+// CreateKey handles POST /api/admin/keys endpoint
+// @Summary      Create API Key
+// @Description  Issues an API key with a scope: read-only (GET requests only), control (device commands and changes) or admin (also the administrative endpoints). Optional device_ids and room_ids restrict the key to those devices and the devices in those rooms. The key is only returned here; the server stores its SHA-256 hash.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body  apikey_dtos.APIKeyRequestDTO  true  "API key"
+// @Success      201  {object}  dtos.StandardResponse{data=apikey_dtos.APIKeyDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/keys [post]
+func (c *APIKeyController) CreateKey(ctx *gin.Context) {
+	var req apikey_dtos.APIKeyRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	key, err := c.useCase.CreateKey(req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "CreateKey", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "API key created. Store it now, it cannot be shown again",
+		Data:    key,
+	})
+}
+
+// ListKeys handles GET /api/admin/keys endpoint
+// @Summary      List API Keys
+// @Description  Lists all managed API keys, including revoked ones. Only the key prefix is shown. The API_KEY from the environment is not listed.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]apikey_dtos.APIKeyDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/keys [get]
+func (c *APIKeyController) ListKeys(ctx *gin.Context) {
+	keys, err := c.useCase.ListKeys()
+	if err != nil {
+		middlewares.AbortWithError(ctx, "ListKeys", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "API keys fetched successfully",
+		Data:    keys,
+	})
+}
+
+// SetGrants handles PUT /api/admin/keys/{id}/grants endpoint
+// @Summary      Set API Key Grants
+// @Description  Replaces the devices and rooms an API key is restricted to (e.g., a guest key only controlling the living room). Requests for other devices are rejected with 403, and device listings only show the granted devices. Empty lists lift the restriction.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                        true  "API key ID"
+// @Param        request  body  apikey_dtos.APIKeyGrantsDTO  true  "Granted devices and rooms"
+// @Success      200  {object}  dtos.StandardResponse{data=apikey_dtos.APIKeyDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/keys/{id}/grants [put]
+func (c *APIKeyController) SetGrants(ctx *gin.Context) {
+	var req apikey_dtos.APIKeyGrantsDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	key, err := c.useCase.SetGrants(ctx.Param("id"), req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "SetGrants", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "API key grants updated",
+		Data:    key,
+	})
+}
+
+// RevokeKey handles DELETE /api/admin/keys/{id} endpoint
+// @Summary      Revoke API Key
+// @Description  Revokes an API key. Requests presenting it are rejected immediately.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        id  path  string  true  "API key ID"
+// @Success      200  {object}  dtos.StandardResponse{data=apikey_dtos.APIKeyDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/keys/{id} [delete]
+func (c *APIKeyController) RevokeKey(ctx *gin.Context) {
+	key, err := c.useCase.RevokeKey(ctx.Param("id"))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "RevokeKey", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "API key revoked",
+		Data:    key,
+	})
+}
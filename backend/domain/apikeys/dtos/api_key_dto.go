@@ -0,0 +1,30 @@
+package dtos
+
+// APIKeyRequestDTO creates an API key. Scope is read-only, control or admin.
+// DeviceIDs and RoomIDs optionally restrict the key to those devices and rooms
+type APIKeyRequestDTO struct {
+	Name      string   `json:"name" binding:"required,max=100" example:"Living room tablet"`
+	Scope     string   `json:"scope" binding:"required" example:"control"`
+	DeviceIDs []string `json:"device_ids,omitempty"`
+	RoomIDs   []string `json:"room_ids,omitempty"`
+}
+
+// APIKeyGrantsDTO replaces the devices and rooms an API key is restricted to. Both empty lifts the restriction
+type APIKeyGrantsDTO struct {
+	DeviceIDs []string `json:"device_ids"`
+	RoomIDs   []string `json:"room_ids"`
+}
+
+// APIKeyDTO is a managed API key. Key is only returned when the key is created.
+// A key without device or room IDs may access every device
+type APIKeyDTO struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	Key       string   `json:"key,omitempty"`
+	Prefix    string   `json:"prefix"`
+	Scope     string   `json:"scope"`
+	DeviceIDs []string `json:"device_ids,omitempty"`
+	RoomIDs   []string `json:"room_ids,omitempty"`
+	CreatedAt int64    `json:"created_at"`
+	RevokedAt int64    `json:"revoked_at,omitempty"`
+}
@@ -0,0 +1,18 @@
+package entities
+
+// APIKey is a managed API key. Only the SHA-256 hash of the key is stored; the key itself is shown once
+// when it is created. Revoked keys are kept for auditing. API keys are stored in the SQL database.
+type APIKey struct {
+	ID        string `gorm:"primaryKey;size:32" json:"id"`
+	Name      string `gorm:"size:100;not null" json:"name"`
+	Prefix    string `gorm:"size:16;not null" json:"prefix"`
+	KeyHash   string `gorm:"size:64;not null;uniqueIndex" json:"-"`
+	Scope     string `gorm:"size:16;not null" json:"scope"`
+	CreatedAt int64  `gorm:"autoCreateTime:false" json:"created_at"`
+	RevokedAt int64  `gorm:"not null;default:0" json:"revoked_at"`
+}
+
+// TableName overrides the table name used by GORM.
+func (APIKey) TableName() string {
+	return "api_keys"
+}
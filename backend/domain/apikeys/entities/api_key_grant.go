@@ -0,0 +1,21 @@
+package entities
+
+const (
+	// APIKeyGrantDevice grants access to a single device.
+	APIKeyGrantDevice = "device"
+	// APIKeyGrantRoom grants access to every device in a room.
+	APIKeyGrantRoom = "room"
+)
+
+// APIKeyGrant restricts an API key to a device or a room. A key without grants may access every device;
+// a key with grants may only access the granted devices and the devices in the granted rooms.
+type APIKeyGrant struct {
+	APIKeyID string `gorm:"primaryKey;size:32" json:"api_key_id"`
+	Kind     string `gorm:"primaryKey;size:16" json:"kind"`
+	TargetID string `gorm:"primaryKey;size:64" json:"target_id"`
+}
+
+// TableName overrides the table name used by GORM.
+func (APIKeyGrant) TableName() string {
+	return "api_key_grants"
+}
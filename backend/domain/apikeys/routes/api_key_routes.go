@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/apikeys/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupAPIKeyRoutes registers the API key administration endpoints.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware with the admin scope).
+// param controller The controller handling API keys.
+func SetupAPIKeyRoutes(router gin.IRouter, controller *controllers.APIKeyController) {
+	utils.LogDebug("SetupAPIKeyRoutes initialized")
+	api := router.Group("/api/admin/keys")
+	{
+		// POST /api/admin/keys
+		// Issues a scoped API key; the key is only returned once.
+		api.POST("", controller.CreateKey)
+
+		// GET /api/admin/keys
+		// Lists API keys without their secrets.
+		api.GET("", controller.ListKeys)
+
+		// PUT /api/admin/keys/:id/grants
+		// Restricts an API key to devices and rooms.
+		api.PUT("/:id/grants", controller.SetGrants)
+
+		// DELETE /api/admin/keys/:id
+		// Revokes an API key.
+		api.DELETE("/:id", controller.RevokeKey)
+	}
+}
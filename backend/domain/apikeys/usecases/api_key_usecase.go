@@ -0,0 +1,486 @@
+package usecases
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"teralux_app/domain/apikeys/dtos"
+	"teralux_app/domain/apikeys/entities"
+	"teralux_app/domain/common/utils"
+	tuya_errors "teralux_app/domain/tuya/errors"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// apiKeyPrefix marks managed API keys, so leaked keys are easy to recognize.
+	apiKeyPrefix = "tlx_"
+	// apiKeySize is the entropy of a generated API key in bytes.
+	apiKeySize = 32
+	// apiKeyDisplayLength is how much of a key is kept in clear text to tell keys apart in listings.
+	apiKeyDisplayLength = len(apiKeyPrefix) + 6
+)
+
+var (
+	// ErrAPIKeyNotFound is returned when an API key does not exist.
+	ErrAPIKeyNotFound = tuya_errors.NotFound("api key not found")
+	// ErrAPIKeysUnavailable is returned when the SQL database API keys are stored in is not connected.
+	ErrAPIKeysUnavailable = tuya_errors.Unavailable("api keys unavailable: database not initialized")
+)
+
+// RoomResolver returns the room IDs a device belongs to.
+type RoomResolver func(deviceID string) []string
+
+// activeAPIKey is an active key as kept in memory. Empty devices and rooms mean the key is not restricted.
+type activeAPIKey struct {
+	id      string
+	scope   string
+	devices map[string]bool
+	rooms   map[string]bool
+}
+
+// restricted reports whether the key is limited to granted devices and rooms.
+func (k *activeAPIKey) restricted() bool {
+	return len(k.devices) > 0 || len(k.rooms) > 0
+}
+
+// APIKeyUseCase manages API keys with scopes (read-only, control, admin) stored hashed in the SQL database.
+// Keys may also be restricted to devices and rooms (e.g., a guest key only controlling the living room).
+// Active keys are also kept in memory, keyed by hash and by ID, so validating a key and checking device
+// access never need a database query.
+// The API_KEY from the environment stays valid as an unrestricted admin key, so existing installs and setup keep working.
+type APIKeyUseCase struct {
+	db    *gorm.DB
+	clock utils.Clock
+	ids   utils.IDGenerator
+
+	mu             sync.RWMutex
+	keys           map[string]*activeAPIKey
+	byID           map[string]*activeAPIKey
+	roomsForDevice RoomResolver
+}
+
+// NewAPIKeyUseCase initializes a new APIKeyUseCase.
+//
+// param db The GORM database API keys are stored in (nil when the database is unavailable).
+// param clock The Clock used to timestamp keys.
+// param ids The IDGenerator used for key IDs and secrets.
+// return *APIKeyUseCase A pointer to the initialized usecase.
+func NewAPIKeyUseCase(db *gorm.DB, clock utils.Clock, ids utils.IDGenerator) *APIKeyUseCase {
+	return &APIKeyUseCase{
+		db:    db,
+		clock: clock,
+		ids:   ids,
+		keys:  make(map[string]*activeAPIKey),
+		byID:  make(map[string]*activeAPIKey),
+	}
+}
+
+// SetRoomResolver registers the function used to resolve device rooms for room grants.
+//
+// param resolver The resolver function, or nil to ignore room grants.
+func (uc *APIKeyUseCase) SetRoomResolver(resolver RoomResolver) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.roomsForDevice = resolver
+}
+
+// Init loads the active keys. The API key tables are created by the database migrations.
+//
+// return error An error if the initial load fails.
+func (uc *APIKeyUseCase) Init() error {
+	if uc.db == nil {
+		return ErrAPIKeysUnavailable
+	}
+
+	var stored []entities.APIKey
+	if err := uc.db.Where("revoked_at = 0").Find(&stored).Error; err != nil {
+		return fmt.Errorf("failed to load api keys: %w", err)
+	}
+	grants, err := uc.loadGrants()
+	if err != nil {
+		return err
+	}
+	keys := make(map[string]*activeAPIKey, len(stored))
+	byID := make(map[string]*activeAPIKey, len(stored))
+	for _, key := range stored {
+		active := newActiveAPIKey(key.ID, key.Scope, grants[key.ID])
+		keys[key.KeyHash] = active
+		byID[key.ID] = active
+	}
+
+	uc.mu.Lock()
+	uc.keys = keys
+	uc.byID = byID
+	uc.mu.Unlock()
+	utils.LogInfo("APIKeyUseCase: Loaded %d active API keys", len(keys))
+	return nil
+}
+
+// ValidateKey checks an X-API-KEY value and returns its ID and scope.
+// It matches the middlewares.APIKeyValidator signature.
+//
+// param key The presented key.
+// return utils.APIKeyIdentity The ID and scope of the key (no ID for the API_KEY from the environment).
+// return bool False if the key is unknown or revoked.
+func (uc *APIKeyUseCase) ValidateKey(key string) (utils.APIKeyIdentity, bool) {
+	if key == "" {
+		return utils.APIKeyIdentity{}, false
+	}
+	if envKey := utils.GetConfig().ApiKey; envKey != "" && subtle.ConstantTimeCompare([]byte(key), []byte(envKey)) == 1 {
+		return utils.APIKeyIdentity{Scope: utils.APIKeyScopeAdmin}, true
+	}
+	if !strings.HasPrefix(key, apiKeyPrefix) {
+		return utils.APIKeyIdentity{}, false
+	}
+
+	uc.mu.RLock()
+	active, ok := uc.keys[hashAPIKey(key)]
+	uc.mu.RUnlock()
+	if !ok {
+		return utils.APIKeyIdentity{}, false
+	}
+	return utils.APIKeyIdentity{ID: active.id, Scope: active.scope}, true
+}
+
+// CurrentScope returns the scope the credentials of a request still hold, so background work started by the
+// request can be authorized again when it runs: a managed key keeps its scope while it is active, the API_KEY
+// from the environment is admin while it is set, and an identity provider user keeps the role of their login.
+//
+// param identity The credentials a request was authorized with.
+// return string The current scope.
+// return bool False if the identity is unverified, or the key was revoked or is no longer configured.
+func (uc *APIKeyUseCase) CurrentScope(identity utils.APIKeyIdentity) (string, bool) {
+	switch {
+	case identity.Scope == "":
+		return "", false
+	case identity.ID != "":
+		uc.mu.RLock()
+		defer uc.mu.RUnlock()
+		active, ok := uc.byID[identity.ID]
+		if !ok {
+			return "", false
+		}
+		return active.scope, true
+	case identity.Subject != "":
+		return identity.Scope, true
+	case utils.GetConfig().ApiKey != "":
+		return utils.APIKeyScopeAdmin, true
+	default:
+		return "", false
+	}
+}
+
+// HasAdminKey reports whether an active managed key of admin scope exists.
+//
+// return bool True if an admin key is active.
+func (uc *APIKeyUseCase) HasAdminKey() bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	for _, active := range uc.byID {
+		if active.scope == utils.APIKeyScopeAdmin {
+			return true
+		}
+	}
+	return false
+}
+
+// RestrictsDevices reports whether an API key is limited to granted devices and rooms.
+// It matches the middlewares.DeviceAccessChecker signature.
+//
+// param keyID The API key ID (empty for the API_KEY from the environment).
+// return bool True if the key has device or room grants.
+func (uc *APIKeyUseCase) RestrictsDevices(keyID string) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	active, ok := uc.byID[keyID]
+	return ok && active.restricted()
+}
+
+// CanAccessDevice reports whether an API key may access a device: it is unrestricted, the device is granted,
+// or the device is in a granted room.
+//
+// param keyID The API key ID.
+// param deviceID The device ID.
+// return bool True if the device may be accessed.
+func (uc *APIKeyUseCase) CanAccessDevice(keyID, deviceID string) bool {
+	uc.mu.RLock()
+	active, ok := uc.byID[keyID]
+	resolver := uc.roomsForDevice
+	uc.mu.RUnlock()
+	if !ok || !active.restricted() || active.devices[deviceID] {
+		return true
+	}
+	if resolver == nil {
+		return false
+	}
+	for _, roomID := range resolver(deviceID) {
+		if active.rooms[roomID] {
+			return true
+		}
+	}
+	return false
+}
+
+// CanAccessRoom reports whether an API key may access a room: it is unrestricted or the room is granted.
+//
+// param keyID The API key ID.
+// param roomID The room ID.
+// return bool True if the room may be accessed.
+func (uc *APIKeyUseCase) CanAccessRoom(keyID, roomID string) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	active, ok := uc.byID[keyID]
+	return !ok || !active.restricted() || active.rooms[roomID]
+}
+
+// CreateKey issues a new API key. The key is only returned by this call; afterwards only its hash is known.
+//
+// param req The name, scope and optional device and room grants of the key.
+// return *dtos.APIKeyDTO The key, including the secret.
+// return error ErrAPIKeysUnavailable, a bad request error for an unknown scope, or an error if the key cannot be stored.
+func (uc *APIKeyUseCase) CreateKey(req dtos.APIKeyRequestDTO) (*dtos.APIKeyDTO, error) {
+	if uc.db == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+	if !utils.IsAPIKeyScope(req.Scope) {
+		return nil, tuya_errors.BadRequest("scope must be one of %s, %s, %s", utils.APIKeyScopeReadOnly, utils.APIKeyScopeControl, utils.APIKeyScopeAdmin)
+	}
+
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key id: %w", err)
+	}
+	secret, err := uc.ids.NewID(apiKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate api key: %w", err)
+	}
+	key := apiKeyPrefix + secret
+	grants := buildGrants(id, req.DeviceIDs, req.RoomIDs)
+
+	stored := entities.APIKey{
+		ID:        id,
+		Name:      strings.TrimSpace(req.Name),
+		Prefix:    key[:apiKeyDisplayLength],
+		KeyHash:   hashAPIKey(key),
+		Scope:     req.Scope,
+		CreatedAt: uc.clock.Now().Unix(),
+	}
+	err = uc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&stored).Error; err != nil {
+			return err
+		}
+		if len(grants) > 0 {
+			return tx.Create(&grants).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save api key: %w", err)
+	}
+
+	active := newActiveAPIKey(stored.ID, stored.Scope, grants)
+	uc.mu.Lock()
+	uc.keys[stored.KeyHash] = active
+	uc.byID[stored.ID] = active
+	uc.mu.Unlock()
+
+	utils.LogInfo("APIKeyUseCase: Created %s API key %s (%s) with %d grants", stored.Scope, stored.ID, stored.Name, len(grants))
+	dto := apiKeyToDTO(&stored, grants)
+	dto.Key = key
+	return &dto, nil
+}
+
+// SetGrants replaces the devices and rooms an API key is restricted to. Sessions created with the key
+// follow the new grants immediately.
+//
+// param id The key ID.
+// param req The granted device and room IDs; both empty lifts the restriction.
+// return *dtos.APIKeyDTO The updated key.
+// return error ErrAPIKeyNotFound, ErrAPIKeysUnavailable, or an error if the grants cannot be stored.
+func (uc *APIKeyUseCase) SetGrants(id string, req dtos.APIKeyGrantsDTO) (*dtos.APIKeyDTO, error) {
+	if uc.db == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+
+	var stored entities.APIKey
+	if err := uc.db.First(&stored, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to load api key: %w", err)
+	}
+
+	grants := buildGrants(stored.ID, req.DeviceIDs, req.RoomIDs)
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("api_key_id = ?", stored.ID).Delete(&entities.APIKeyGrant{}).Error; err != nil {
+			return err
+		}
+		if len(grants) > 0 {
+			return tx.Create(&grants).Error
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save api key grants: %w", err)
+	}
+
+	if stored.RevokedAt == 0 {
+		active := newActiveAPIKey(stored.ID, stored.Scope, grants)
+		uc.mu.Lock()
+		uc.keys[stored.KeyHash] = active
+		uc.byID[stored.ID] = active
+		uc.mu.Unlock()
+	}
+
+	utils.LogInfo("APIKeyUseCase: Set %d grants on API key %s (%s)", len(grants), stored.ID, stored.Name)
+	dto := apiKeyToDTO(&stored, grants)
+	return &dto, nil
+}
+
+// ListKeys returns all API keys, including revoked ones, newest first. Secrets are never returned.
+//
+// return []dtos.APIKeyDTO The keys.
+// return error ErrAPIKeysUnavailable, or an error if the query fails.
+func (uc *APIKeyUseCase) ListKeys() ([]dtos.APIKeyDTO, error) {
+	if uc.db == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+
+	var stored []entities.APIKey
+	if err := uc.db.Order("created_at desc").Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to list api keys: %w", err)
+	}
+	grants, err := uc.loadGrants()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dtos.APIKeyDTO, len(stored))
+	for i := range stored {
+		result[i] = apiKeyToDTO(&stored[i], grants[stored[i].ID])
+	}
+	return result, nil
+}
+
+// RevokeKey revokes an API key. It is rejected immediately; revoking a revoked key is a no-op.
+//
+// param id The key ID.
+// return *dtos.APIKeyDTO The revoked key.
+// return error ErrAPIKeyNotFound, ErrAPIKeysUnavailable, or an error if the update fails.
+func (uc *APIKeyUseCase) RevokeKey(id string) (*dtos.APIKeyDTO, error) {
+	if uc.db == nil {
+		return nil, ErrAPIKeysUnavailable
+	}
+
+	var stored entities.APIKey
+	if err := uc.db.First(&stored, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAPIKeyNotFound
+		}
+		return nil, fmt.Errorf("failed to load api key: %w", err)
+	}
+
+	if stored.RevokedAt == 0 {
+		stored.RevokedAt = uc.clock.Now().Unix()
+		if err := uc.db.Model(&stored).Update("revoked_at", stored.RevokedAt).Error; err != nil {
+			return nil, fmt.Errorf("failed to revoke api key: %w", err)
+		}
+		utils.LogInfo("APIKeyUseCase: Revoked API key %s (%s)", stored.ID, stored.Name)
+	}
+
+	uc.mu.Lock()
+	delete(uc.keys, stored.KeyHash)
+	delete(uc.byID, stored.ID)
+	uc.mu.Unlock()
+
+	var grants []entities.APIKeyGrant
+	if err := uc.db.Where("api_key_id = ?", stored.ID).Find(&grants).Error; err != nil {
+		return nil, fmt.Errorf("failed to load api key grants: %w", err)
+	}
+	dto := apiKeyToDTO(&stored, grants)
+	return &dto, nil
+}
+
+// hashAPIKey returns the hex SHA-256 hash a key is stored under.
+// Keys are long random values, so a plain hash is enough to keep them unrecoverable from the database.
+func hashAPIKey(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(sum[:])
+}
+
+// loadGrants returns all stored grants, keyed by API key ID.
+func (uc *APIKeyUseCase) loadGrants() (map[string][]entities.APIKeyGrant, error) {
+	var stored []entities.APIKeyGrant
+	if err := uc.db.Order("kind, target_id").Find(&stored).Error; err != nil {
+		return nil, fmt.Errorf("failed to load api key grants: %w", err)
+	}
+	grants := make(map[string][]entities.APIKeyGrant)
+	for _, grant := range stored {
+		grants[grant.APIKeyID] = append(grants[grant.APIKeyID], grant)
+	}
+	return grants, nil
+}
+
+// buildGrants turns granted device and room IDs into grants, skipping blanks and duplicates.
+func buildGrants(keyID string, deviceIDs, roomIDs []string) []entities.APIKeyGrant {
+	var grants []entities.APIKeyGrant
+	seen := make(map[string]bool)
+	add := func(kind string, targets []string) {
+		for _, target := range targets {
+			target = strings.TrimSpace(target)
+			if target == "" || seen[kind+":"+target] {
+				continue
+			}
+			seen[kind+":"+target] = true
+			grants = append(grants, entities.APIKeyGrant{APIKeyID: keyID, Kind: kind, TargetID: target})
+		}
+	}
+	add(entities.APIKeyGrantDevice, deviceIDs)
+	add(entities.APIKeyGrantRoom, roomIDs)
+	return grants
+}
+
+// newActiveAPIKey builds the in-memory form of an active key and its grants.
+func newActiveAPIKey(id, scope string, grants []entities.APIKeyGrant) *activeAPIKey {
+	active := &activeAPIKey{
+		id:      id,
+		scope:   scope,
+		devices: make(map[string]bool),
+		rooms:   make(map[string]bool),
+	}
+	for _, grant := range grants {
+		switch grant.Kind {
+		case entities.APIKeyGrantDevice:
+			active.devices[grant.TargetID] = true
+		case entities.APIKeyGrantRoom:
+			active.rooms[grant.TargetID] = true
+		}
+	}
+	return active
+}
+
+// apiKeyToDTO converts a stored key and its grants without the key hash.
+func apiKeyToDTO(key *entities.APIKey, grants []entities.APIKeyGrant) dtos.APIKeyDTO {
+	dto := dtos.APIKeyDTO{
+		ID:        key.ID,
+		Name:      key.Name,
+		Prefix:    key.Prefix,
+		Scope:     key.Scope,
+		CreatedAt: key.CreatedAt,
+		RevokedAt: key.RevokedAt,
+	}
+	for _, grant := range grants {
+		switch grant.Kind {
+		case entities.APIKeyGrantDevice:
+			dto.DeviceIDs = append(dto.DeviceIDs, grant.TargetID)
+		case entities.APIKeyGrantRoom:
+			dto.RoomIDs = append(dto.RoomIDs, grant.TargetID)
+		}
+	}
+	return dto
+}
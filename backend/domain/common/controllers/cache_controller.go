@@ -1,22 +1,37 @@
 package controllers
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 	"teralux_app/domain/common/dtos"
 	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/middlewares"
 	"teralux_app/domain/common/utils"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 )
 
+const (
+	defaultCacheKeyLimit = 1000
+	maxCacheKeyLimit     = 10000
+)
+
 // CacheController handles cache-related operations
 type CacheController struct {
-	cache *persistence.BadgerService
+	cache       persistence.CacheStore
+	ttls        *persistence.CacheTTLPolicy
+	maintenance *persistence.BadgerMaintenanceService
 }
 
 // NewCacheController creates a new CacheController instance
-func NewCacheController(cache *persistence.BadgerService) *CacheController {
-	return &CacheController{cache: cache}
+func NewCacheController(cache persistence.CacheStore, ttls *persistence.CacheTTLPolicy, maintenance *persistence.BadgerMaintenanceService) *CacheController {
+	return &CacheController{cache: cache, ttls: ttls, maintenance: maintenance}
 }
 
 // FlushCache clears the entire cache
@@ -55,4 +70,464 @@ func (ctrl *CacheController) FlushCache(c *gin.Context) {
 		Message: "Cache flushed successfully",
 		Data:    nil,
 	})
-}
\ No newline at end of file
+}
+
+// ListKeys lists stored keys matching a prefix
+// @Summary List cache keys
+// @Description Lists BadgerDB keys starting with prefix, with value size and expiry. Covers both cache entries ("cache:") and persistent data.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Param prefix query string false "Key prefix (e.g., cache:)"
+// @Param limit query int false "Maximum number of keys (default 1000, max 10000)"
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheKeysResponseDTO}
+// @Failure 400 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/keys [get]
+func (ctrl *CacheController) ListKeys(c *gin.Context) {
+	if !ctrl.ensureCache(c) {
+		return
+	}
+
+	limit := defaultCacheKeyLimit
+	if value := c.Query("limit"); value != "" {
+		parsed, err := strconv.Atoi(value)
+		if err != nil || parsed < 1 || parsed > maxCacheKeyLimit {
+			c.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: "limit must be between 1 and " + strconv.Itoa(maxCacheKeyLimit),
+				Data:    nil,
+			})
+			return
+		}
+		limit = parsed
+	}
+
+	prefix := c.Query("prefix")
+	keys, truncated, err := ctrl.cache.ListKeys(prefix, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to list cache keys",
+			Data:    nil,
+		})
+		return
+	}
+
+	result := dtos.CacheKeysResponseDTO{
+		Prefix:    prefix,
+		Count:     len(keys),
+		Truncated: truncated,
+		Keys:      make([]dtos.CacheKeyDTO, 0, len(keys)),
+	}
+	for _, key := range keys {
+		result.Keys = append(result.Keys, toCacheKeyDTO(key.Key, key.Size, key.ExpiresAt))
+	}
+
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache keys fetched successfully",
+		Data:    result,
+	})
+}
+
+// GetKey returns the value and TTL of a stored key
+// @Summary Inspect cache key
+// @Description Returns the value of a key with its expiry. JSON values are returned as JSON, other text as a string and binary values base64-encoded.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Key (e.g., cache:devices:...)"
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheEntryDTO}
+// @Failure 404 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/keys/{key} [get]
+func (ctrl *CacheController) GetKey(c *gin.Context) {
+	if !ctrl.ensureCache(c) {
+		return
+	}
+
+	key, ok := cacheKeyParam(c)
+	if !ok {
+		return
+	}
+	value, expiresAt, err := ctrl.cache.GetWithExpiry(key)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to read cache key",
+			Data:    nil,
+		})
+		return
+	}
+	if value == nil {
+		c.JSON(http.StatusNotFound, dtos.StandardResponse{
+			Status:  false,
+			Message: "Key not found",
+			Data:    nil,
+		})
+		return
+	}
+
+	entry := dtos.CacheEntryDTO{CacheKeyDTO: toCacheKeyDTO(key, int64(len(value)), expiresAt)}
+	switch {
+	case json.Valid(value):
+		entry.Encoding = "json"
+		entry.Value = json.RawMessage(value)
+	case utf8.Valid(value):
+		entry.Encoding = "text"
+		entry.Value = string(value)
+	default:
+		entry.Encoding = "base64"
+		entry.Value = base64.StdEncoding.EncodeToString(value)
+	}
+
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache key fetched successfully",
+		Data:    entry,
+	})
+}
+
+// DeleteKey removes a single stored key
+// @Summary Delete cache key
+// @Description Removes one key, leaving the rest of the cache intact.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Param key path string true "Key"
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheInvalidationDTO}
+// @Failure 404 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/keys/{key} [delete]
+func (ctrl *CacheController) DeleteKey(c *gin.Context) {
+	if !ctrl.ensureCache(c) {
+		return
+	}
+
+	key, ok := cacheKeyParam(c)
+	if !ok {
+		return
+	}
+	value, err := ctrl.cache.Get(key)
+	if err == nil && value == nil {
+		c.JSON(http.StatusNotFound, dtos.StandardResponse{
+			Status:  false,
+			Message: "Key not found",
+			Data:    nil,
+		})
+		return
+	}
+	if err == nil {
+		err = ctrl.cache.Delete(key)
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to delete cache key",
+			Data:    nil,
+		})
+		return
+	}
+
+	utils.LogInfo("CacheController: Deleted key %s", key)
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache key deleted successfully",
+		Data:    dtos.CacheInvalidationDTO{Key: key, Deleted: 1},
+	})
+}
+
+// DeletePrefix removes all stored keys starting with a prefix
+// @Summary Invalidate cache prefix
+// @Description Removes every key starting with prefix (e.g., cache:devices: to drop cached device lists). The prefix must not be empty; use DELETE /api/cache/flush to clear all cache entries.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Param prefix path string true "Key prefix"
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheInvalidationDTO}
+// @Failure 400 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/prefix/{prefix} [delete]
+func (ctrl *CacheController) DeletePrefix(c *gin.Context) {
+	if !ctrl.ensureCache(c) {
+		return
+	}
+
+	prefix := strings.TrimPrefix(c.Param("prefix"), "/")
+	if prefix == "" {
+		c.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "prefix must not be empty",
+			Data:    nil,
+		})
+		return
+	}
+
+	keys, err := ctrl.cache.GetAllKeysWithPrefix(prefix)
+	if err == nil && len(keys) > 0 {
+		err = ctrl.cache.ClearWithPrefix(prefix)
+	}
+	if err != nil {
+		utils.LogError("Failed to invalidate cache prefix %s: %v", prefix, err)
+		c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to invalidate cache prefix",
+			Data:    nil,
+		})
+		return
+	}
+
+	utils.LogInfo("CacheController: Deleted %d keys with prefix %s", len(keys), prefix)
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache prefix invalidated successfully",
+		Data:    dtos.CacheInvalidationDTO{Prefix: prefix, Deleted: len(keys)},
+	})
+}
+
+// GetConfig returns the TTL of each cached resource type
+// @Summary Get cache TTLs
+// @Description Returns the TTL of device lists, device details, specifications and sensors, with their configured defaults.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheConfigDTO}
+// @Router /api/cache/config [get]
+func (ctrl *CacheController) GetConfig(c *gin.Context) {
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache configuration fetched successfully",
+		Data:    ctrl.cacheConfig(),
+	})
+}
+
+// UpdateConfig overrides the TTL of cached resource types at runtime
+// @Summary Update cache TTLs
+// @Description Overrides TTLs by resource type (device_list, device_detail, specification, sensor), e.g. {"ttls": {"device_list": "5m", "sensor": "default"}}. "default" restores the TTL from the environment. Overrides survive restarts; entries already cached keep their TTL.
+// @Tags 05. Flush
+// @Accept json
+// @Produce json
+// @Security ApiKeyAuth
+// @Param request body dtos.UpdateCacheConfigRequestDTO true "TTL overrides"
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheConfigDTO}
+// @Failure 400 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/config [put]
+func (ctrl *CacheController) UpdateConfig(c *gin.Context) {
+	var req dtos.UpdateCacheConfigRequestDTO
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	changes := make(map[string]time.Duration, len(req.TTLs))
+	for resource, value := range req.TTLs {
+		if value == "default" {
+			changes[resource] = 0
+			continue
+		}
+		ttl, err := time.ParseDuration(value)
+		if err != nil || ttl <= 0 {
+			c.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: "ttl of " + resource + " must be a positive duration (e.g., 10m) or \"default\"",
+				Data:    nil,
+			})
+			return
+		}
+		changes[resource] = ttl
+	}
+
+	if err := ctrl.ttls.Update(changes); err != nil {
+		middlewares.AbortWithError(c, "CacheController.UpdateConfig", err)
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache configuration updated successfully",
+		Data:    ctrl.cacheConfig(),
+	})
+}
+
+// GetStats reports storage usage and garbage collection
+// @Summary Get cache storage stats
+// @Description Returns the LSM tree and value log sizes of BadgerDB, the compaction state of every LSM level and the value log garbage collection schedule with its last run. Sizes are refreshed by BadgerDB once a minute. Not available with CACHE_BACKEND=redis.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheStatsDTO}
+// @Failure 409 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/stats [get]
+func (ctrl *CacheController) GetStats(c *gin.Context) {
+	if !ctrl.ensureCache(c) {
+		return
+	}
+
+	storage, err := ctrl.maintenance.StorageStats()
+	if errors.Is(err, persistence.ErrMaintenanceNotApplicable) {
+		c.JSON(http.StatusConflict, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	stats := dtos.CacheStatsDTO{
+		LSMSize:   storage.LSMSize,
+		VLogSize:  storage.VLogSize,
+		TotalSize: storage.LSMSize + storage.VLogSize,
+		Levels:    make([]dtos.CacheLevelDTO, 0, len(storage.Levels)),
+		GC:        toCacheGCStatusDTO(ctrl.maintenance.Status()),
+	}
+	for _, level := range storage.Levels {
+		stats.Levels = append(stats.Levels, dtos.CacheLevelDTO{
+			Level:         level.Level,
+			Tables:        level.NumTables,
+			Size:          level.Size,
+			TargetSize:    level.TargetSize,
+			Score:         level.Score,
+			IsBaseLevel:   level.IsBaseLevel,
+			StaleDataSize: level.StaleDataSize,
+		})
+	}
+
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache stats retrieved successfully",
+		Data:    stats,
+	})
+}
+
+// RunGC runs value log garbage collection on demand
+// @Summary Run cache garbage collection
+// @Description Rewrites BadgerDB value log files until none has at least BADGER_GC_DISCARD_RATIO stale data (overwritten, deleted or expired entries), reclaiming their disk space. Returns once the run is over; the sizes in GET /api/cache/stats reflect it within a minute. Not available with CACHE_BACKEND=redis.
+// @Tags 05. Flush
+// @Produce json
+// @Security ApiKeyAuth
+// @Success 200 {object} dtos.StandardResponse{data=dtos.CacheGCRunDTO}
+// @Failure 409 {object} dtos.StandardResponse
+// @Failure 500 {object} dtos.StandardResponse
+// @Router /api/cache/gc [post]
+func (ctrl *CacheController) RunGC(c *gin.Context) {
+	if !ctrl.ensureCache(c) {
+		return
+	}
+
+	run, err := ctrl.maintenance.RunGC(persistence.GCTriggerManual)
+	if errors.Is(err, persistence.ErrGCRunning) || errors.Is(err, persistence.ErrGCNotApplicable) || errors.Is(err, persistence.ErrMaintenanceNotApplicable) {
+		c.JSON(http.StatusConflict, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	if err != nil {
+		utils.LogError("Failed to run cache garbage collection: %v", err)
+		var data interface{}
+		if run != nil {
+			data = toCacheGCRunDTO(run)
+		}
+		c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to run cache garbage collection",
+			Data:    data,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache garbage collection completed",
+		Data:    toCacheGCRunDTO(run),
+	})
+}
+
+// cacheConfig describes the effective TTL of every resource type.
+func (ctrl *CacheController) cacheConfig() dtos.CacheConfigDTO {
+	config := dtos.CacheConfigDTO{TTLs: make([]dtos.CacheTTLDTO, 0, len(persistence.CacheResources))}
+	for _, resource := range persistence.CacheResources {
+		ttl := ctrl.ttls.TTL(resource)
+		config.TTLs = append(config.TTLs, dtos.CacheTTLDTO{
+			Resource:   resource,
+			TTL:        ttl.String(),
+			TTLSeconds: int64(ttl.Seconds()),
+			Default:    ctrl.ttls.Default(resource).String(),
+			Overridden: ctrl.ttls.IsOverridden(resource),
+		})
+	}
+	return config
+}
+
+// ensureCache writes an error response and returns false when the cache is not initialized.
+func (ctrl *CacheController) ensureCache(c *gin.Context) bool {
+	if ctrl.cache != nil {
+		return true
+	}
+	c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+		Status:  false,
+		Message: "Cache service not initialized",
+		Data:    nil,
+	})
+	return false
+}
+
+// cacheKeyParam reads the key path parameter, writing a bad request response when it is empty.
+func cacheKeyParam(c *gin.Context) (string, bool) {
+	key := strings.TrimPrefix(c.Param("key"), "/")
+	if key == "" {
+		c.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "key must not be empty",
+			Data:    nil,
+		})
+		return "", false
+	}
+	return key, true
+}
+
+// toCacheKeyDTO maps key metadata to its DTO.
+func toCacheKeyDTO(key string, size int64, expiresAt uint64) dtos.CacheKeyDTO {
+	result := dtos.CacheKeyDTO{Key: key, Size: size}
+	if expiresAt > 0 {
+		result.ExpiresAt = int64(expiresAt)
+		if ttl := time.Until(time.Unix(int64(expiresAt), 0)); ttl > 0 {
+			result.TTLSeconds = int64(ttl.Seconds())
+		}
+	}
+	return result
+}
+
+// toCacheGCStatusDTO maps the garbage collection status to its DTO.
+func toCacheGCStatusDTO(status persistence.GCStatus) dtos.CacheGCStatusDTO {
+	result := dtos.CacheGCStatusDTO{
+		Interval:      status.Interval,
+		DiscardRatio:  status.DiscardRatio,
+		Running:       status.Running,
+		TotalRuns:     status.TotalRuns,
+		TotalRewrites: status.TotalRewrites,
+	}
+	if status.LastRun != nil {
+		lastRun := toCacheGCRunDTO(status.LastRun)
+		result.LastRun = &lastRun
+	}
+	return result
+}
+
+// toCacheGCRunDTO maps a garbage collection run to its DTO.
+func toCacheGCRunDTO(run *persistence.GCRun) dtos.CacheGCRunDTO {
+	return dtos.CacheGCRunDTO{
+		Trigger:    run.Trigger,
+		StartedAt:  run.StartedAt,
+		DurationMs: run.DurationMs,
+		Rewrites:   run.Rewrites,
+		Error:      run.Error,
+	}
+}
@@ -0,0 +1,56 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ConfigController exposes the running configuration for diagnostics.
+type ConfigController struct{}
+
+// NewConfigController creates a new ConfigController instance.
+//
+// return *ConfigController A pointer to the initialized controller.
+func NewConfigController() *ConfigController {
+	return &ConfigController{}
+}
+
+// GetConfig handles GET /api/admin/config endpoint
+// @Summary      Get Configuration
+// @Description  Lists every setting with its type, value and source (environment, .env or config.yaml). Secrets (credentials, tokens, API keys) are shown as ******** and passwords in URLs are masked. Also reports the problems the startup validation would find, e.g. after settings were written by setup.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.ConfigDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/config [get]
+func (c *ConfigController) GetConfig(ctx *gin.Context) {
+	entries := utils.DescribeConfig()
+	settings := make([]dtos.ConfigSettingDTO, len(entries))
+	for i, entry := range entries {
+		settings[i] = dtos.ConfigSettingDTO{
+			Name:     entry.Name,
+			Kind:     string(entry.Kind),
+			Value:    entry.Value,
+			Set:      entry.Set,
+			Secret:   entry.Secret,
+			Required: entry.Required,
+			Source:   entry.Source,
+		}
+	}
+
+	result := dtos.ConfigDTO{Valid: true, Settings: settings}
+	message := "Configuration retrieved successfully"
+	if problems := utils.ConfigProblems(utils.GetConfig()); len(problems) > 0 {
+		result.Valid = false
+		result.Problems = problems
+		message = "Configuration has problems"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    result,
+	})
+}
@@ -2,22 +2,36 @@ package controllers
 
 import (
 	"net/http"
+	"teralux_app/domain/common/dtos"
 	"teralux_app/domain/common/infrastructure"
+	"teralux_app/domain/common/infrastructure/persistence"
 	"github.com/gin-gonic/gin"
 )
 
+// Health states reported by the health endpoints.
+const (
+	healthOK          = "ok"
+	healthDegraded    = "degraded"
+	healthUnavailable = "unavailable"
+)
+
 // HealthController handles health check requests
-type HealthController struct{}
+type HealthController struct {
+	cacheHealth persistence.CacheStoreHealth
+}
 
 // NewHealthController creates a new HealthController instance
-func NewHealthController() *HealthController {
-	return &HealthController{}
+//
+// param cacheHealth The configured cache backend and whether the in-memory fallback replaced it.
+// return *HealthController A pointer to the initialized controller.
+func NewHealthController(cacheHealth persistence.CacheStoreHealth) *HealthController {
+	return &HealthController{cacheHealth: cacheHealth}
 }
 
 
 // CheckHealth godoc
 // @Summary      Health check endpoint
-// @Description  Check if the application and database are healthy
+// @Description  Check if the application and database are healthy. Answers "Degraded" (still 200) while the cache store could not be opened and an in-memory fallback is used; see GET /api/admin/health for details.
 // @Tags         Health
 // @Produce      plain
 // @Success      200  {string}  string "OK"
@@ -30,5 +44,50 @@ func (h *HealthController) CheckHealth(c *gin.Context) {
 		return
 	}
 
+	// The API keeps working on the fallback store, so it stays in rotation
+	if h.cacheHealth.Fallback {
+		c.String(http.StatusOK, "Degraded")
+		return
+	}
+
 	c.String(http.StatusOK, "OK")
-}
\ No newline at end of file
+}
+
+// GetHealthDetails handles GET /api/admin/health endpoint
+// @Summary      Get Health Details
+// @Description  Reports the state of the database and the cache store. The cache is "degraded" when the configured store (CACHE_BACKEND) could not be opened, e.g. because the BadgerDB directory is locked or corrupted, and an in-memory store replaced it: the API keeps working, but data stored since the start is lost on restart and replication is paused. The overall status is the worst component state.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.HealthStatusDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/health [get]
+func (h *HealthController) GetHealthDetails(c *gin.Context) {
+	status := dtos.HealthStatusDTO{
+		Status:   healthOK,
+		Database: dtos.HealthComponentDTO{Status: healthOK},
+		Cache:    dtos.HealthComponentDTO{Status: healthOK, Backend: h.cacheHealth.Backend},
+	}
+	if h.cacheHealth.Fallback {
+		status.Cache.Status = healthDegraded
+		status.Cache.Error = h.cacheHealth.Error
+		status.Status = healthDegraded
+	}
+	if err := infrastructure.PingDB(); err != nil {
+		status.Database.Status = healthUnavailable
+		status.Database.Error = err.Error()
+		status.Status = healthUnavailable
+	}
+
+	message := "All components are healthy"
+	switch status.Status {
+	case healthDegraded:
+		message = "Cache store unavailable, running on an in-memory fallback"
+	case healthUnavailable:
+		message = "Database unavailable"
+	}
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    status,
+	})
+}
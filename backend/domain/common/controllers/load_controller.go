@@ -0,0 +1,43 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+
+	"github.com/gin-gonic/gin"
+)
+
+// LoadController exposes the load shedding metrics.
+type LoadController struct {
+	shedder *middlewares.LoadShedder
+}
+
+// NewLoadController creates a new LoadController instance.
+//
+// param shedder The LoadShedder whose signals and counters are reported.
+// return *LoadController A pointer to the initialized controller.
+func NewLoadController(shedder *middlewares.LoadShedder) *LoadController {
+	return &LoadController{shedder: shedder}
+}
+
+// GetLoadStatus handles GET /api/admin/load endpoint
+// @Summary      Get Load Status
+// @Description  Reports the load signals (requests in flight, queued jobs, moving average of Tuya API latency), the LOAD_SHED_* thresholds, and how many low-priority requests (history, charts, exports) were rejected with 503 while overloaded.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.LoadStatusDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/load [get]
+func (c *LoadController) GetLoadStatus(ctx *gin.Context) {
+	status := c.shedder.Status()
+	message := "Server load is normal"
+	if status.Overloaded {
+		message = "Server is overloaded, low-priority requests are shed"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    status,
+	})
+}
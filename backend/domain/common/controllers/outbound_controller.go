@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/infrastructure/outbound"
+
+	"github.com/gin-gonic/gin"
+)
+
+// outboundSchemes are the destination schemes admins can register (webhooks and MQTT brokers).
+var outboundSchemes = []string{"https", "http", "mqtts", "mqtt", "ssl", "tcp"}
+
+// OutboundController lets admins check outbound destinations before registering them.
+type OutboundController struct {
+	guard *outbound.Guard
+}
+
+// NewOutboundController creates a new OutboundController instance.
+//
+// param guard The Guard validating outbound destinations.
+// return *OutboundController A pointer to the initialized controller.
+func NewOutboundController(guard *outbound.Guard) *OutboundController {
+	return &OutboundController{guard: guard}
+}
+
+// ValidateDestination handles POST /api/admin/outbound/validate endpoint
+// @Summary      Validate Outbound Destination
+// @Description  Checks a webhook or MQTT broker URL against OUTBOUND_ALLOWLIST and the private range rule (SSRF protection) and reports why it would be refused.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      dtos.OutboundValidationRequestDTO  true  "Destination URL"
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.OutboundValidationDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/outbound/validate [post]
+func (c *OutboundController) ValidateDestination(ctx *gin.Context) {
+	var req dtos.OutboundValidationRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result := dtos.OutboundValidationDTO{URL: req.URL, Allowed: true}
+	if err := c.guard.ValidateURL(ctx.Request.Context(), req.URL, outboundSchemes...); err != nil {
+		result.Allowed = false
+		result.Reason = err.Error()
+	}
+
+	message := "Destination is allowed"
+	if !result.Allowed {
+		message = "Destination is not allowed"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    result,
+	})
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/middlewares"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReplicationController handles replication between a primary and its warm standby.
+type ReplicationController struct {
+	service *persistence.ReplicationService
+}
+
+// NewReplicationController creates a new ReplicationController instance.
+//
+// param service The ReplicationService shipping and receiving backups.
+// return *ReplicationController A pointer to the initialized controller.
+func NewReplicationController(service *persistence.ReplicationService) *ReplicationController {
+	return &ReplicationController{service: service}
+}
+
+// GetStatus handles GET /api/admin/replication endpoint
+// @Summary      Get Replication Status
+// @Description  Returns the last shipped and restored backup versions of this instance.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.ReplicationStatusDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/replication [get]
+func (c *ReplicationController) GetStatus(ctx *gin.Context) {
+	state, shipping := c.service.Status()
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Replication status retrieved successfully",
+		Data: dtos.ReplicationStatusDTO{
+			Shipping:        shipping,
+			ShippedVersion:  state.ShippedVersion,
+			ShippedAt:       state.ShippedAt,
+			RestoredVersion: state.RestoredVersion,
+			RestoredAt:      state.RestoredAt,
+			LastError:       state.LastError,
+		},
+	})
+}
+
+// ShipNow handles POST /api/admin/replication/ship endpoint
+// @Summary      Ship Backup Now
+// @Description  Immediately ships the changes since the last backup to the replication target.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/replication/ship [post]
+func (c *ReplicationController) ShipNow(ctx *gin.Context) {
+	if err := c.service.ShipNow(ctx.Request.Context()); err != nil {
+		utils.LogError("ReplicationController.ShipNow: %v", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to ship backup: " + err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Backup shipped successfully",
+		Data:    nil,
+	})
+}
+
+// ReceiveBackup handles POST /api/admin/replication/backups endpoint
+// @Summary      Receive Backup
+// @Description  Stores an incremental backup sent by the primary. Stored backups are replayed when this standby starts with REPLICATION_RESTORE_ON_START.
+// @Tags         08. Admin
+// @Accept       octet-stream
+// @Produce      json
+// @Param        name  query  string  true  "Backup file name (<since>-<until>.bak)"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/replication/backups [post]
+func (c *ReplicationController) ReceiveBackup(ctx *gin.Context) {
+	if err := c.service.ReceiveBackup(ctx.Query("name"), ctx.Request.Body); err != nil {
+		middlewares.AbortWithError(ctx, "ReplicationController.ReceiveBackup", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Backup stored successfully",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,51 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SerializationController exposes the serialization hot path metrics.
+type SerializationController struct{}
+
+// NewSerializationController creates a new SerializationController instance.
+//
+// return *SerializationController A pointer to the initialized controller.
+func NewSerializationController() *SerializationController {
+	return &SerializationController{}
+}
+
+// GetSerializationStats handles GET /api/admin/serialization endpoint
+// @Summary      Get Serialization Stats
+// @Description  Reports, per hot path (e.g., device_list), how many JSON encodes and decodes ran, their bytes and time, and how often a pre-marshaled payload was reused instead of re-serializing identical data.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.SerializationStatsDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/serialization [get]
+func (c *SerializationController) GetSerializationStats(ctx *gin.Context) {
+	stats := utils.SerializationStats()
+	paths := make([]dtos.SerializationPathDTO, len(stats))
+	for i, stat := range stats {
+		paths[i] = dtos.SerializationPathDTO{
+			Path:              stat.Path,
+			MarshalCalls:      stat.MarshalCalls,
+			MarshalBytes:      stat.MarshalBytes,
+			MarshalTimeMs:     stat.MarshalTime.Milliseconds(),
+			UnmarshalCalls:    stat.UnmarshalCalls,
+			UnmarshalBytes:    stat.UnmarshalBytes,
+			UnmarshalTimeMs:   stat.UnmarshalTime.Milliseconds(),
+			PayloadCacheHits:  stat.PayloadHits,
+			PayloadCacheMiss:  stat.PayloadMisses,
+			PayloadBytesSaved: stat.PayloadBytesHit,
+		}
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Serialization stats fetched successfully",
+		Data:    dtos.SerializationStatsDTO{Paths: paths},
+	})
+}
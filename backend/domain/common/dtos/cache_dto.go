@@ -0,0 +1,94 @@
+package dtos
+
+// CacheKeyDTO describes a stored key. ExpiresAt and TTLSeconds are 0 for persistent keys
+type CacheKeyDTO struct {
+	Key        string `json:"key"`
+	Size       int64  `json:"size"`
+	ExpiresAt  int64  `json:"expires_at"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+}
+
+// CacheKeysResponseDTO lists the keys matching a prefix
+type CacheKeysResponseDTO struct {
+	Prefix    string        `json:"prefix"`
+	Count     int           `json:"count"`
+	Truncated bool          `json:"truncated"`
+	Keys      []CacheKeyDTO `json:"keys"`
+}
+
+// CacheEntryDTO is a stored key with its value.
+// Encoding is "json" when the value is JSON, "text" for other UTF-8 values and "base64" for binary values
+type CacheEntryDTO struct {
+	CacheKeyDTO
+	Encoding string      `json:"encoding"`
+	Value    interface{} `json:"value"`
+}
+
+// CacheInvalidationDTO reports the keys removed by a selective invalidation
+type CacheInvalidationDTO struct {
+	Prefix  string `json:"prefix,omitempty"`
+	Key     string `json:"key,omitempty"`
+	Deleted int    `json:"deleted"`
+}
+
+// CacheTTLDTO is the TTL applied to one type of cached resource
+type CacheTTLDTO struct {
+	Resource   string `json:"resource"`
+	TTL        string `json:"ttl"`
+	TTLSeconds int64  `json:"ttl_seconds"`
+	Default    string `json:"default"`
+	Overridden bool   `json:"overridden"`
+}
+
+// CacheConfigDTO lists the TTL of every cached resource type
+type CacheConfigDTO struct {
+	TTLs []CacheTTLDTO `json:"ttls"`
+}
+
+// UpdateCacheConfigRequestDTO overrides TTLs by resource type (device_list, device_detail, specification, sensor).
+// Values are durations such as "10m"; "default" restores the configured TTL
+type UpdateCacheConfigRequestDTO struct {
+	TTLs map[string]string `json:"ttls" binding:"required"`
+}
+
+// CacheLevelDTO describes one level of the BadgerDB LSM tree.
+// Levels with a score above 1 are due for compaction
+type CacheLevelDTO struct {
+	Level         int     `json:"level"`
+	Tables        int     `json:"tables"`
+	Size          int64   `json:"size"`
+	TargetSize    int64   `json:"target_size"`
+	Score         float64 `json:"score"`
+	IsBaseLevel   bool    `json:"is_base_level"`
+	StaleDataSize int64   `json:"stale_data_size"`
+}
+
+// CacheGCRunDTO is the outcome of a value log garbage collection run.
+// Rewrites counts the value log files rewritten to reclaim stale data
+type CacheGCRunDTO struct {
+	Trigger    string `json:"trigger"`
+	StartedAt  int64  `json:"started_at"`
+	DurationMs int64  `json:"duration_ms"`
+	Rewrites   int    `json:"rewrites"`
+	Error      string `json:"error,omitempty"`
+}
+
+// CacheGCStatusDTO describes the value log garbage collection schedule. Interval is "off" when GC only runs on demand
+type CacheGCStatusDTO struct {
+	Interval      string         `json:"interval"`
+	DiscardRatio  float64        `json:"discard_ratio"`
+	Running       bool           `json:"running"`
+	TotalRuns     int64          `json:"total_runs"`
+	TotalRewrites int64          `json:"total_rewrites"`
+	LastRun       *CacheGCRunDTO `json:"last_run,omitempty"`
+}
+
+// CacheStatsDTO reports the disk usage, compaction state and garbage collection of BadgerDB.
+// Sizes are refreshed by BadgerDB once a minute
+type CacheStatsDTO struct {
+	LSMSize   int64            `json:"lsm_size"`
+	VLogSize  int64            `json:"vlog_size"`
+	TotalSize int64            `json:"total_size"`
+	Levels    []CacheLevelDTO  `json:"levels"`
+	GC        CacheGCStatusDTO `json:"gc"`
+}
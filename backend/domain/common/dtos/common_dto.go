@@ -1,10 +1,12 @@
 package dtos
 
-// StandardResponse represents the standardized API response structure
+// StandardResponse represents the standardized API response structure.
+// Meta is an extensible section for diagnostics such as request timing; it is only filled when the client opts in.
 type StandardResponse struct {
 	Status  bool        `json:"status"`
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
+	Meta    interface{} `json:"meta,omitempty"`
 }
 
 // SuccessResponseDTO is a simple DTO for operations returning a success boolean
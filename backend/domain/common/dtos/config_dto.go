@@ -0,0 +1,19 @@
+package dtos
+
+// ConfigSettingDTO is one setting of the running configuration; secrets are redacted
+type ConfigSettingDTO struct {
+	Name     string `json:"name"`
+	Kind     string `json:"kind"`
+	Value    string `json:"value,omitempty"`
+	Set      bool   `json:"set"`
+	Secret   bool   `json:"secret"`
+	Required bool   `json:"required"`
+	Source   string `json:"source,omitempty"`
+}
+
+// ConfigDTO reports the running configuration for diagnostics
+type ConfigDTO struct {
+	Valid    bool               `json:"valid"`
+	Problems []string           `json:"problems,omitempty"`
+	Settings []ConfigSettingDTO `json:"settings"`
+}
@@ -0,0 +1,16 @@
+package dtos
+
+// HealthComponentDTO reports the state of one dependency: "ok", "degraded" (working with reduced
+// guarantees) or "unavailable".
+type HealthComponentDTO struct {
+	Status  string `json:"status"`
+	Backend string `json:"backend,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// HealthStatusDTO reports the state of the server and its dependencies.
+type HealthStatusDTO struct {
+	Status   string             `json:"status"`
+	Database HealthComponentDTO `json:"database"`
+	Cache    HealthComponentDTO `json:"cache"`
+}
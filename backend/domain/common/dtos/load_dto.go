@@ -0,0 +1,18 @@
+package dtos
+
+// LoadStatusDTO reports the load signals, the shedding thresholds and how many requests were shed
+type LoadStatusDTO struct {
+	Overloaded         bool             `json:"overloaded"`
+	Reasons            []string         `json:"reasons,omitempty"`
+	InFlight           int64            `json:"in_flight"`
+	QueueDepth         int              `json:"queue_depth"`
+	UpstreamLatencyMs  int64            `json:"upstream_latency_ms"`
+	UpstreamSampledAt  int64            `json:"upstream_sampled_at,omitempty"`
+	MaxInFlight        int64            `json:"max_in_flight"`
+	MaxQueueDepth      int              `json:"max_queue_depth"`
+	MaxUpstreamLatency int64            `json:"max_upstream_latency_ms"`
+	RetryAfterSeconds  int              `json:"retry_after_seconds"`
+	LowPriorityRoutes  []string         `json:"low_priority_routes"`
+	ShedTotal          int64            `json:"shed_total"`
+	ShedByRoute        map[string]int64 `json:"shed_by_route"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+// OutboundValidationRequestDTO is a destination to check against the outbound rules
+type OutboundValidationRequestDTO struct {
+	URL string `json:"url" binding:"required"`
+}
+
+// OutboundValidationDTO reports whether a destination may be registered
+type OutboundValidationDTO struct {
+	URL     string `json:"url"`
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
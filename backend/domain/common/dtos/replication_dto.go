@@ -0,0 +1,11 @@
+package dtos
+
+// ReplicationStatusDTO reports the replication progress of this instance.
+type ReplicationStatusDTO struct {
+	Shipping        bool   `json:"shipping"`
+	ShippedVersion  uint64 `json:"shipped_version"`
+	ShippedAt       int64  `json:"shipped_at,omitempty"`
+	RestoredVersion uint64 `json:"restored_version"`
+	RestoredAt      int64  `json:"restored_at,omitempty"`
+	LastError       string `json:"last_error,omitempty"`
+}
@@ -0,0 +1,20 @@
+package dtos
+
+// SerializationPathDTO reports the JSON encoding and decoding work done on one hot path
+type SerializationPathDTO struct {
+	Path              string `json:"path"`
+	MarshalCalls      int64  `json:"marshal_calls"`
+	MarshalBytes      int64  `json:"marshal_bytes"`
+	MarshalTimeMs     int64  `json:"marshal_time_ms"`
+	UnmarshalCalls    int64  `json:"unmarshal_calls"`
+	UnmarshalBytes    int64  `json:"unmarshal_bytes"`
+	UnmarshalTimeMs   int64  `json:"unmarshal_time_ms"`
+	PayloadCacheHits  int64  `json:"payload_cache_hits"`
+	PayloadCacheMiss  int64  `json:"payload_cache_misses"`
+	PayloadBytesSaved int64  `json:"payload_bytes_saved"`
+}
+
+// SerializationStatsDTO lists the serialization counters of every hot path
+type SerializationStatsDTO struct {
+	Paths []SerializationPathDTO `json:"paths"`
+}
@@ -0,0 +1,228 @@
+package httpclient
+
+import (
+	"fmt"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"teralux_app/domain/common/infrastructure/tracing"
+	"teralux_app/domain/common/utils"
+	"time"
+)
+
+const (
+	defaultDialTimeout         = 5 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultMaxIdleConns        = 100
+	defaultMaxIdleConnsPerHost = 10
+	defaultRetryMaxAttempts    = 3
+	defaultRetryBackoff        = 200 * time.Millisecond
+	defaultRetryMaxBackoff     = 5 * time.Second
+)
+
+var (
+	sharedTransport     *http.Transport
+	sharedTransportOnce sync.Once
+)
+
+// New creates an HTTP client on the shared pooled transport. GET and HEAD requests are retried on network
+// errors, 429 and 5xx responses following the configured RetryPolicy; other methods are sent once.
+// Each attempt is traced as a client span of the request context's span.
+//
+// param timeout The overall request timeout, retries included (0 for none).
+// return *http.Client The client.
+func New(timeout time.Duration) *http.Client {
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: &retryTransport{base: &tracingTransport{base: Transport()}, policy: LoadRetryPolicy()},
+	}
+}
+
+// NewWithoutRetry creates an HTTP client on the shared pooled transport that sends every request once,
+// for callers that run their own retry loop.
+//
+// param timeout The overall request timeout (0 for none).
+// return *http.Client The client.
+func NewWithoutRetry(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &tracingTransport{base: Transport()}}
+}
+
+// Transport returns the transport shared by the clients of this package, so connections to the same host
+// are pooled across services.
+//
+// return *http.Transport The shared transport.
+func Transport() *http.Transport {
+	sharedTransportOnce.Do(func() {
+		sharedTransport = NewTransport()
+	})
+	return sharedTransport
+}
+
+// NewTransport creates a transport with the configured dial, TLS handshake and idle timeouts, idle
+// connection limits and proxy. HTTP_CLIENT_PROXY_URL takes precedence over HTTP_PROXY, HTTPS_PROXY and NO_PROXY.
+//
+// return *http.Transport The transport.
+func NewTransport() *http.Transport {
+	config := utils.GetConfig()
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   parseDuration(config.HTTPDialTimeout, defaultDialTimeout),
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = parseDuration(config.HTTPTLSHandshakeTimeout, defaultTLSHandshakeTimeout)
+	transport.IdleConnTimeout = parseDuration(config.HTTPIdleConnTimeout, defaultIdleConnTimeout)
+	transport.MaxIdleConns = parseInt(config.HTTPMaxIdleConns, defaultMaxIdleConns)
+	transport.MaxIdleConnsPerHost = parseInt(config.HTTPMaxIdleConnsPerHost, defaultMaxIdleConnsPerHost)
+
+	if config.HTTPProxyURL != "" {
+		proxyURL, err := url.Parse(config.HTTPProxyURL)
+		if err != nil || proxyURL.Host == "" {
+			utils.LogWarn("Invalid HTTP_CLIENT_PROXY_URL %q, using the proxy environment variables", config.HTTPProxyURL)
+		} else {
+			transport.Proxy = http.ProxyURL(proxyURL)
+		}
+	}
+	return transport
+}
+
+// RetryPolicy bounds how often a failed idempotent request is sent and how long to wait in between.
+type RetryPolicy struct {
+	MaxAttempts int
+	Backoff     time.Duration
+	MaxBackoff  time.Duration
+}
+
+// LoadRetryPolicy reads the retry policy from HTTP_CLIENT_RETRY_MAX_ATTEMPTS, HTTP_CLIENT_RETRY_BACKOFF and
+// HTTP_CLIENT_RETRY_MAX_BACKOFF, falling back to 3 attempts starting at 200ms and capped at 5s.
+//
+// return RetryPolicy The policy.
+func LoadRetryPolicy() RetryPolicy {
+	config := utils.GetConfig()
+	return RetryPolicy{
+		MaxAttempts: parseInt(config.HTTPRetryMaxAttempts, defaultRetryMaxAttempts),
+		Backoff:     parseDuration(config.HTTPRetryBackoff, defaultRetryBackoff),
+		MaxBackoff:  parseDuration(config.HTTPRetryMaxBackoff, defaultRetryMaxBackoff),
+	}
+}
+
+// Delay returns the wait before a retry: the backoff doubles per attempt up to MaxBackoff, and a random
+// jitter of up to half of it spreads retries of concurrent callers apart.
+//
+// param attempt The attempt that failed, starting at 1.
+// return time.Duration The wait.
+func (p RetryPolicy) Delay(attempt int) time.Duration {
+	delay := p.Backoff
+	for i := 1; i < attempt && delay < p.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if p.MaxBackoff > 0 && delay > p.MaxBackoff {
+		delay = p.MaxBackoff
+	}
+	if delay <= 0 {
+		return 0
+	}
+	half := delay / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// Retryable reports whether a response status is worth retrying: 429 and 5xx.
+//
+// param status The HTTP status.
+// return bool True when the request may succeed on a retry.
+func Retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
+}
+
+// retryTransport retries GET and HEAD requests on network errors and retryable statuses.
+type retryTransport struct {
+	base   http.RoundTripper
+	policy RetryPolicy
+}
+
+// RoundTrip sends the request, retrying idempotent methods while attempts remain and the context is live.
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if (req.Method != http.MethodGet && req.Method != http.MethodHead) || t.policy.MaxAttempts <= 1 {
+		return t.base.RoundTrip(req)
+	}
+
+	for attempt := 1; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		retry := (err != nil && req.Context().Err() == nil) || (err == nil && Retryable(resp.StatusCode))
+		if !retry || attempt >= t.policy.MaxAttempts {
+			return resp, err
+		}
+		if resp != nil {
+			resp.Body.Close()
+		}
+
+		delay := t.policy.Delay(attempt)
+		utils.LogWarn("HTTP client: %s %s failed (attempt %d/%d), retrying in %s", req.Method, req.URL.Host+req.URL.Path, attempt, t.policy.MaxAttempts, delay)
+		select {
+		case <-time.After(delay):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
+
+// tracingTransport records each request as a client span of the span in the request context and
+// propagates the trace with a traceparent header.
+type tracingTransport struct {
+	base http.RoundTripper
+}
+
+// RoundTrip sends the request inside a client span named after the method and path (the query is left out).
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := tracing.StartClient(req.Context(), req.Method+" "+req.URL.Path)
+	if span == nil {
+		return t.base.RoundTrip(req)
+	}
+	defer span.End()
+
+	req = req.Clone(ctx)
+	req.Header.Set("traceparent", span.TraceParent())
+	span.SetAttribute("http.request.method", req.Method)
+	span.SetAttribute("server.address", req.URL.Hostname())
+	span.SetAttribute("url.path", req.URL.Path)
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	span.SetAttribute("http.response.status_code", resp.StatusCode)
+	if resp.StatusCode >= http.StatusInternalServerError {
+		span.RecordError(fmt.Errorf("HTTP %d", resp.StatusCode))
+	}
+	return resp, nil
+}
+
+// parseDuration parses a duration setting, returning the fallback when it is empty or invalid.
+func parseDuration(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		utils.LogWarn("Invalid duration %q, using %s", value, fallback)
+		return fallback
+	}
+	return duration
+}
+
+// parseInt parses a positive integer setting, returning the fallback when it is empty or invalid.
+func parseInt(value string, fallback int) int {
+	if value == "" {
+		return fallback
+	}
+	number, err := strconv.Atoi(value)
+	if err != nil || number <= 0 {
+		utils.LogWarn("Invalid number %q, using %d", value, fallback)
+		return fallback
+	}
+	return number
+}
@@ -0,0 +1,517 @@
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// BER tags of the LDAPv3 operations used by the client (RFC 4511).
+const (
+	tagInteger      = 0x02
+	tagOctetString  = 0x04
+	tagBoolean      = 0x01
+	tagEnumerated   = 0x0a
+	tagSequence     = 0x30
+	tagBindRequest  = 0x60
+	tagBindResponse = 0x61
+	tagUnbind       = 0x42
+	tagSearch       = 0x63
+	tagSearchEntry  = 0x64
+	tagSearchDone   = 0x65
+	tagSimpleAuth   = 0x80
+	tagFilterAnd    = 0xa0
+	tagFilterOr     = 0xa1
+	tagFilterNot    = 0xa2
+	tagFilterEqual  = 0xa3
+	tagFilterHas    = 0x87
+
+	resultSuccess            = 0
+	resultInvalidCredentials = 49
+
+	// maxMessageSize bounds a single LDAP message, so a misbehaving server cannot exhaust memory.
+	maxMessageSize = 16 << 20
+)
+
+// ErrInvalidCredentials is returned when a bind is rejected for a wrong DN or password.
+var ErrInvalidCredentials = errors.New("ldap: invalid credentials")
+
+// Entry is a directory entry returned by a search.
+type Entry struct {
+	DN         string
+	Attributes map[string][]string
+}
+
+// Conn is a minimal LDAPv3 client: simple binds and subtree searches with equality, presence, and, or and
+// not filters, which is what authenticating users and reading their groups needs. Requests are sent one at
+// a time.
+type Conn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	timeout   time.Duration
+	messageID int
+}
+
+// Dial connects to a directory server. ldap:// connects in plain text, ldaps:// over TLS.
+//
+// param ctx The context bounding the dial.
+// param rawURL The server URL (e.g., ldaps://ldap.example.com:636).
+// param timeout The deadline of every request.
+// return *Conn A pointer to the connection.
+// return error An error if the URL is invalid or the server cannot be reached.
+func Dial(ctx context.Context, rawURL string, timeout time.Duration) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return nil, fmt.Errorf("invalid LDAP URL %q", rawURL)
+	}
+	host := u.Host
+	var conn net.Conn
+	switch u.Scheme {
+	case "ldap":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "389")
+		}
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ldaps":
+		if u.Port() == "" {
+			host = net.JoinHostPort(u.Hostname(), "636")
+		}
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: u.Hostname(), MinVersion: tls.VersionTLS12}}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("unsupported LDAP URL scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to LDAP server %s: %w", host, err)
+	}
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Conn{conn: conn, reader: bufio.NewReader(conn), timeout: timeout}, nil
+}
+
+// Bind authenticates the connection with a simple bind.
+// An empty password is an unauthenticated bind that servers accept for any DN, so it is rejected here.
+//
+// param dn The distinguished name to bind as.
+// param password The password.
+// return error ErrInvalidCredentials, or an error if the request fails.
+func (c *Conn) Bind(dn, password string) error {
+	if password == "" {
+		return ErrInvalidCredentials
+	}
+	request := berTLV(tagBindRequest, concat(
+		berInteger(tagInteger, 3),
+		berTLV(tagOctetString, []byte(dn)),
+		berTLV(tagSimpleAuth, []byte(password)),
+	))
+	response, err := c.roundTrip(request)
+	if err != nil {
+		return err
+	}
+	if response.tag != tagBindResponse {
+		return fmt.Errorf("ldap: unexpected response 0x%x to bind", response.tag)
+	}
+	code, message, err := ldapResult(response)
+	if err != nil {
+		return err
+	}
+	switch code {
+	case resultSuccess:
+		return nil
+	case resultInvalidCredentials:
+		return ErrInvalidCredentials
+	default:
+		return fmt.Errorf("ldap: bind failed with result %d: %s", code, message)
+	}
+}
+
+// Search runs a subtree search and returns the matching entries.
+//
+// param baseDN The DN to search below.
+// param filter The RFC 4515 filter; only equality, presence, and, or and not are supported.
+// param attributes The attributes to return (empty for all).
+// return []Entry The matching entries.
+// return error An error if the filter is invalid or the search fails.
+func (c *Conn) Search(baseDN, filter string, attributes []string) ([]Entry, error) {
+	encodedFilter, rest, err := encodeFilter(strings.TrimSpace(filter))
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(rest) != "" {
+		return nil, fmt.Errorf("ldap: unexpected %q after filter", rest)
+	}
+	var attributeList []byte
+	for _, attribute := range attributes {
+		attributeList = append(attributeList, berTLV(tagOctetString, []byte(attribute))...)
+	}
+	request := berTLV(tagSearch, concat(
+		berTLV(tagOctetString, []byte(baseDN)),
+		berInteger(tagEnumerated, 2), // wholeSubtree
+		berInteger(tagEnumerated, 0), // neverDerefAliases
+		berInteger(tagInteger, 0),    // no size limit
+		berInteger(tagInteger, int(c.timeout/time.Second)),
+		berTLV(tagBoolean, []byte{0}),
+		encodedFilter,
+		berTLV(tagSequence, attributeList),
+	))
+
+	c.messageID++
+	if err := c.send(c.messageID, request); err != nil {
+		return nil, err
+	}
+	var entries []Entry
+	for {
+		response, err := c.receive(c.messageID)
+		if err != nil {
+			return nil, err
+		}
+		switch response.tag {
+		case tagSearchEntry:
+			entry, err := parseEntry(response)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case tagSearchDone:
+			code, message, err := ldapResult(response)
+			if err != nil {
+				return nil, err
+			}
+			if code != resultSuccess {
+				return nil, fmt.Errorf("ldap: search failed with result %d: %s", code, message)
+			}
+			return entries, nil
+		default:
+			// Search result references (referrals) are not followed
+		}
+	}
+}
+
+// Close sends an unbind request and closes the connection.
+//
+// return error An error if closing the connection fails.
+func (c *Conn) Close() error {
+	c.messageID++
+	_ = c.send(c.messageID, []byte{tagUnbind, 0})
+	return c.conn.Close()
+}
+
+// EscapeFilter escapes a value for use in a filter, so user input cannot change the filter (RFC 4515).
+//
+// param value The raw value.
+// return string The escaped value.
+func EscapeFilter(value string) string {
+	var b strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch ch := value[i]; ch {
+		case '*', '(', ')', '\\', 0:
+			fmt.Fprintf(&b, "\\%02x", ch)
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+// roundTrip sends a request and returns its single response.
+func (c *Conn) roundTrip(request []byte) (*berElement, error) {
+	c.messageID++
+	if err := c.send(c.messageID, request); err != nil {
+		return nil, err
+	}
+	return c.receive(c.messageID)
+}
+
+// send writes an LDAPMessage carrying a protocol operation.
+func (c *Conn) send(messageID int, operation []byte) error {
+	_ = c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	message := berTLV(tagSequence, concat(berInteger(tagInteger, messageID), operation))
+	if _, err := c.conn.Write(message); err != nil {
+		return fmt.Errorf("ldap: failed to send request: %w", err)
+	}
+	return nil
+}
+
+// receive reads LDAPMessages until one for messageID arrives and returns its protocol operation.
+func (c *Conn) receive(messageID int) (*berElement, error) {
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+		message, err := readElement(c.reader)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: failed to read response: %w", err)
+		}
+		children, err := message.children()
+		if err != nil || len(children) < 2 {
+			return nil, fmt.Errorf("ldap: malformed response")
+		}
+		if children[0].integer() != messageID {
+			continue
+		}
+		return children[1], nil
+	}
+}
+
+// ldapResult extracts the result code and diagnostic message of an LDAPResult.
+func ldapResult(response *berElement) (int, string, error) {
+	fields, err := response.children()
+	if err != nil || len(fields) < 3 {
+		return 0, "", fmt.Errorf("ldap: malformed result")
+	}
+	return fields[0].integer(), string(fields[2].value), nil
+}
+
+// parseEntry decodes a SearchResultEntry.
+func parseEntry(response *berElement) (Entry, error) {
+	fields, err := response.children()
+	if err != nil || len(fields) < 2 {
+		return Entry{}, fmt.Errorf("ldap: malformed search entry")
+	}
+	entry := Entry{DN: string(fields[0].value), Attributes: make(map[string][]string)}
+	attributes, err := fields[1].children()
+	if err != nil {
+		return Entry{}, fmt.Errorf("ldap: malformed search entry attributes")
+	}
+	for _, attribute := range attributes {
+		parts, err := attribute.children()
+		if err != nil || len(parts) < 2 {
+			return Entry{}, fmt.Errorf("ldap: malformed search entry attribute")
+		}
+		values, err := parts[1].children()
+		if err != nil {
+			return Entry{}, fmt.Errorf("ldap: malformed search entry values")
+		}
+		name := string(parts[0].value)
+		for _, value := range values {
+			entry.Attributes[name] = append(entry.Attributes[name], string(value.value))
+		}
+	}
+	return entry, nil
+}
+
+// encodeFilter encodes the first filter of s and returns the remaining input.
+func encodeFilter(s string) ([]byte, string, error) {
+	if !strings.HasPrefix(s, "(") {
+		return nil, "", fmt.Errorf("ldap: filter must start with '(' at %q", s)
+	}
+	s = s[1:]
+	if s == "" {
+		return nil, "", fmt.Errorf("ldap: unterminated filter")
+	}
+
+	switch s[0] {
+	case '&', '|':
+		tag := byte(tagFilterAnd)
+		if s[0] == '|' {
+			tag = tagFilterOr
+		}
+		s = s[1:]
+		var content []byte
+		for strings.HasPrefix(s, "(") {
+			filter, rest, err := encodeFilter(s)
+			if err != nil {
+				return nil, "", err
+			}
+			content = append(content, filter...)
+			s = rest
+		}
+		if !strings.HasPrefix(s, ")") {
+			return nil, "", fmt.Errorf("ldap: unterminated filter")
+		}
+		return berTLV(tag, content), s[1:], nil
+	case '!':
+		filter, rest, err := encodeFilter(s[1:])
+		if err != nil {
+			return nil, "", err
+		}
+		if !strings.HasPrefix(rest, ")") {
+			return nil, "", fmt.Errorf("ldap: unterminated filter")
+		}
+		return berTLV(tagFilterNot, filter), rest[1:], nil
+	}
+
+	end := strings.IndexByte(s, ')')
+	if end < 0 {
+		return nil, "", fmt.Errorf("ldap: unterminated filter")
+	}
+	attribute, value, found := strings.Cut(s[:end], "=")
+	if !found || attribute == "" || strings.ContainsAny(attribute, "~<>:") {
+		return nil, "", fmt.Errorf("ldap: unsupported filter item %q", s[:end])
+	}
+	if value == "*" {
+		return berTLV(tagFilterHas, []byte(attribute)), s[end+1:], nil
+	}
+	if strings.Contains(value, "*") {
+		return nil, "", fmt.Errorf("ldap: substring filters are not supported")
+	}
+	unescaped, err := unescapeFilterValue(value)
+	if err != nil {
+		return nil, "", err
+	}
+	assertion := concat(berTLV(tagOctetString, []byte(attribute)), berTLV(tagOctetString, unescaped))
+	return berTLV(tagFilterEqual, assertion), s[end+1:], nil
+}
+
+// unescapeFilterValue decodes the \XX escapes of a filter value.
+func unescapeFilterValue(value string) ([]byte, error) {
+	var out []byte
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			out = append(out, value[i])
+			continue
+		}
+		if i+2 >= len(value) {
+			return nil, fmt.Errorf("ldap: invalid escape in filter value %q", value)
+		}
+		b, err := strconv.ParseUint(value[i+1:i+3], 16, 8)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: invalid escape in filter value %q", value)
+		}
+		out = append(out, byte(b))
+		i += 2
+	}
+	return out, nil
+}
+
+// berElement is a decoded BER element.
+type berElement struct {
+	tag   byte
+	value []byte
+}
+
+// children decodes the elements of a constructed element.
+func (e *berElement) children() ([]*berElement, error) {
+	var result []*berElement
+	data := e.value
+	for len(data) > 0 {
+		child, n, err := parseElement(data)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, child)
+		data = data[n:]
+	}
+	return result, nil
+}
+
+// integer decodes an INTEGER or ENUMERATED element.
+func (e *berElement) integer() int {
+	value := 0
+	for i, b := range e.value {
+		if i == 0 && b&0x80 != 0 {
+			value = -1
+		}
+		value = value<<8 | int(b)
+	}
+	return value
+}
+
+// readElement reads one BER element from a stream.
+func readElement(r *bufio.Reader) (*berElement, error) {
+	tag, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	first, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(first)
+	if first&0x80 != 0 {
+		count := int(first & 0x7f)
+		if count == 0 || count > 4 {
+			return nil, fmt.Errorf("unsupported BER length")
+		}
+		length = 0
+		for i := 0; i < count; i++ {
+			b, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = length<<8 | int(b)
+		}
+	}
+	if length > maxMessageSize {
+		return nil, fmt.Errorf("message of %d bytes is too large", length)
+	}
+	value := make([]byte, length)
+	if _, err := io.ReadFull(r, value); err != nil {
+		return nil, err
+	}
+	return &berElement{tag: tag, value: value}, nil
+}
+
+// parseElement decodes one BER element from a buffer and returns its encoded size.
+func parseElement(data []byte) (*berElement, int, error) {
+	if len(data) < 2 {
+		return nil, 0, fmt.Errorf("truncated BER element")
+	}
+	length := int(data[1])
+	offset := 2
+	if data[1]&0x80 != 0 {
+		count := int(data[1] & 0x7f)
+		if count == 0 || count > 4 || len(data) < 2+count {
+			return nil, 0, fmt.Errorf("unsupported BER length")
+		}
+		length = 0
+		for _, b := range data[2 : 2+count] {
+			length = length<<8 | int(b)
+		}
+		offset += count
+	}
+	if length < 0 || len(data) < offset+length {
+		return nil, 0, fmt.Errorf("truncated BER element")
+	}
+	return &berElement{tag: data[0], value: data[offset : offset+length]}, offset + length, nil
+}
+
+// berTLV encodes an element with a definite length.
+func berTLV(tag byte, content []byte) []byte {
+	length := len(content)
+	var header []byte
+	switch {
+	case length < 0x80:
+		header = []byte{tag, byte(length)}
+	case length <= 0xff:
+		header = []byte{tag, 0x81, byte(length)}
+	case length <= 0xffff:
+		header = []byte{tag, 0x82, byte(length >> 8), byte(length)}
+	default:
+		header = []byte{tag, 0x84, byte(length >> 24), byte(length >> 16), byte(length >> 8), byte(length)}
+	}
+	return append(header, content...)
+}
+
+// berInteger encodes a non-negative INTEGER or ENUMERATED in its shortest form.
+func berInteger(tag byte, value int) []byte {
+	var content []byte
+	for {
+		content = append([]byte{byte(value)}, content...)
+		value >>= 8
+		if value == 0 {
+			break
+		}
+	}
+	if content[0]&0x80 != 0 {
+		content = append([]byte{0}, content...)
+	}
+	return berTLV(tag, content)
+}
+
+// concat joins encoded elements.
+func concat(parts ...[]byte) []byte {
+	var out []byte
+	for _, part := range parts {
+		out = append(out, part...)
+	}
+	return out
+}
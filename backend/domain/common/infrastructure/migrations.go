@@ -0,0 +1,225 @@
+package infrastructure
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/utils"
+
+	"gorm.io/gorm"
+)
+
+const (
+	// schemaMigrationsTable records the applied schema version in the format of golang-migrate, so the
+	// migrate-* Makefile targets and the server agree on the version.
+	schemaMigrationsTable = "schema_migrations"
+	// migrationLockName serializes migrations of instances starting at the same time.
+	migrationLockName = "teralux_schema_migrations"
+	// migrationLockTimeoutSeconds is how long an instance waits for another one to finish migrating.
+	migrationLockTimeoutSeconds = 120
+)
+
+// migrationFilePattern matches "{version}_{name}.up.sql"; down migrations are only run by golang-migrate.
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+)\.up\.sql$`)
+
+// Migration is one schema version.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+}
+
+// LoadMigrations reads the up migrations of a directory, in version order.
+//
+// param files The directory holding "{version}_{name}.up.sql" files; other files are ignored.
+// return []Migration The migrations.
+// return error An error if a file cannot be read or two files share a version.
+func LoadMigrations(files fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list migrations: %w", err)
+	}
+
+	seen := make(map[int64]string)
+	var migrations []Migration
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", entry.Name(), err)
+		}
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("migrations %s and %s share version %d", other, entry.Name(), version)
+		}
+		seen[version] = entry.Name()
+
+		script, err := fs.ReadFile(files, entry.Name())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		migrations = append(migrations, Migration{Version: version, Name: match[2], Up: string(script)})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrateUp applies the migrations newer than the recorded schema version, in order, holding a database
+// lock so concurrently starting instances migrate once. MySQL cannot roll back schema changes, so the
+// version is marked dirty while a migration runs; a failed migration leaves it dirty until the schema is
+// repaired and the version forced (migrate -path domain/teralux/migrations -database ... force VERSION).
+//
+// param db The database to migrate.
+// param files The directory holding the migration files.
+// return int64 The schema version before migrating (0 for an empty database).
+// return int64 The schema version after migrating, up to the failed migration.
+// return error An error if the schema is dirty, the lock cannot be taken, or a migration fails.
+func MigrateUp(db *gorm.DB, files fs.FS) (int64, int64, error) {
+	migrations, err := LoadMigrations(files)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	sqlDB, err := db.DB()
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get database instance: %w", err)
+	}
+	ctx := context.Background()
+	// The lock belongs to a connection, so everything runs on the same one
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	var locked sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", migrationLockName, migrationLockTimeoutSeconds).Scan(&locked); err != nil {
+		return 0, 0, fmt.Errorf("failed to take the migration lock: %w", err)
+	}
+	if !locked.Valid || locked.Int64 != 1 {
+		return 0, 0, fmt.Errorf("timed out waiting for another instance to finish migrating")
+	}
+	defer func() {
+		var released sql.NullInt64
+		if err := conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", migrationLockName).Scan(&released); err != nil {
+			utils.LogWarn("Migrations: Failed to release the migration lock: %v", err)
+		}
+	}()
+
+	if _, err := conn.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+schemaMigrationsTable+" (version BIGINT NOT NULL PRIMARY KEY, dirty BOOLEAN NOT NULL)"); err != nil {
+		return 0, 0, fmt.Errorf("failed to create %s: %w", schemaMigrationsTable, err)
+	}
+
+	var current int64
+	var dirty bool
+	err = conn.QueryRowContext(ctx, "SELECT version, dirty FROM "+schemaMigrationsTable+" LIMIT 1").Scan(&current, &dirty)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, 0, fmt.Errorf("failed to read the schema version: %w", err)
+	}
+	if dirty {
+		return current, current, fmt.Errorf("schema version %d is dirty: a migration failed part way; repair the schema and force the version", current)
+	}
+	if len(migrations) > 0 && current > migrations[len(migrations)-1].Version {
+		utils.LogWarn("Migrations: Schema version %d is newer than this release knows (%d)", current, migrations[len(migrations)-1].Version)
+	}
+
+	from := current
+	for _, migration := range migrations {
+		if migration.Version <= current {
+			continue
+		}
+		if err := setSchemaVersion(ctx, conn, migration.Version, true); err != nil {
+			return from, current, err
+		}
+		for _, statement := range splitStatements(migration.Up) {
+			if _, err := conn.ExecContext(ctx, statement); err != nil {
+				return from, current, fmt.Errorf("migration %d_%s failed, schema version %d left dirty: %w", migration.Version, migration.Name, migration.Version, err)
+			}
+		}
+		if err := setSchemaVersion(ctx, conn, migration.Version, false); err != nil {
+			return from, current, err
+		}
+		current = migration.Version
+		utils.LogInfo("Migrations: Applied %d_%s", migration.Version, migration.Name)
+	}
+	return from, current, nil
+}
+
+// setSchemaVersion replaces the recorded schema version.
+func setSchemaVersion(ctx context.Context, conn *sql.Conn, version int64, dirty bool) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to record schema version %d: %w", version, err)
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, "DELETE FROM "+schemaMigrationsTable); err != nil {
+		return fmt.Errorf("failed to record schema version %d: %w", version, err)
+	}
+	if _, err := tx.ExecContext(ctx, "INSERT INTO "+schemaMigrationsTable+" (version, dirty) VALUES (?, ?)", version, dirty); err != nil {
+		return fmt.Errorf("failed to record schema version %d: %w", version, err)
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to record schema version %d: %w", version, err)
+	}
+	return nil
+}
+
+// splitStatements splits a SQL script at the semicolons outside quotes and comments, since the driver
+// runs one statement at a time. Comments are dropped and empty statements skipped.
+func splitStatements(script string) []string {
+	var statements []string
+	var current strings.Builder
+	flush := func() {
+		if statement := strings.TrimSpace(current.String()); statement != "" {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+		switch {
+		case c == '-' && i+1 < len(script) && script[i+1] == '-', c == '#':
+			for i < len(script) && script[i] != '\n' {
+				i++
+			}
+			current.WriteByte('\n')
+		case c == '/' && i+1 < len(script) && script[i+1] == '*':
+			end := strings.Index(script[i+2:], "*/")
+			if end < 0 {
+				i = len(script)
+			} else {
+				i += end + 3
+			}
+			current.WriteByte(' ')
+		case c == '\'' || c == '"' || c == '`':
+			current.WriteByte(c)
+			for i++; i < len(script); i++ {
+				current.WriteByte(script[i])
+				if script[i] == '\\' && c != '`' && i+1 < len(script) {
+					i++
+					current.WriteByte(script[i])
+					continue
+				}
+				if script[i] == c {
+					break
+				}
+			}
+		case c == ';':
+			flush()
+		default:
+			current.WriteByte(c)
+		}
+	}
+	flush()
+	return statements
+}
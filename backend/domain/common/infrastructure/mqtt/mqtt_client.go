@@ -0,0 +1,430 @@
+package mqtt
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// MQTT 3.1.1 control packet types (upper nibble of the fixed header).
+const (
+	packetConnect     = 1
+	packetConnAck     = 2
+	packetPublish     = 3
+	packetPubAck      = 4
+	packetSubscribe   = 8
+	packetSubAck      = 9
+	packetPingReq     = 12
+	packetPingResp    = 13
+	packetDisconnect  = 14
+	maxRemainingBytes = 268435455
+)
+
+// ErrClosed is returned when publishing on a client whose connection ended.
+var ErrClosed = errors.New("mqtt connection closed")
+
+// Message is an application message received on a subscribed topic.
+type Message struct {
+	Topic   string
+	Payload []byte
+}
+
+// MessageHandler receives messages on subscribed topics. It runs on the read loop and must not block.
+type MessageHandler func(Message)
+
+// Options configures a connection to a broker.
+type Options struct {
+	// Broker is the broker URL: tcp:// or mqtt:// for plain connections, ssl://, tls:// or mqtts:// for TLS.
+	Broker    string
+	ClientID  string
+	Username  string
+	Password  string
+	KeepAlive time.Duration
+	// WillTopic and WillPayload form the retained last will, published by the broker when the connection drops.
+	WillTopic   string
+	WillPayload []byte
+}
+
+// Client is a minimal MQTT 3.1.1 client: QoS 0 publishing (optionally retained) and QoS 0 subscriptions,
+// which is what Home Assistant discovery and command topics need.
+type Client struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	keepAlive time.Duration
+	handler   MessageHandler
+
+	writeMu  sync.Mutex
+	packetID uint32
+
+	done      chan struct{}
+	closeOnce sync.Once
+	err       error
+}
+
+// Connect dials the broker, performs the MQTT handshake and starts reading messages.
+//
+// param ctx The context bounding the dial and handshake.
+// param opts The broker and session options.
+// param handler The handler receiving messages on subscribed topics.
+// return *Client A pointer to the connected client.
+// return error An error if the broker cannot be reached or refuses the connection.
+func Connect(ctx context.Context, opts Options, handler MessageHandler) (*Client, error) {
+	address, useTLS, err := brokerAddress(opts.Broker)
+	if err != nil {
+		return nil, err
+	}
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = 30 * time.Second
+	}
+
+	var conn net.Conn
+	if useTLS {
+		host, _, _ := net.SplitHostPort(address)
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	} else {
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to MQTT broker %s: %w", address, err)
+	}
+
+	c := &Client{
+		conn:      conn,
+		reader:    bufio.NewReader(conn),
+		keepAlive: opts.KeepAlive,
+		handler:   handler,
+		done:      make(chan struct{}),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	} else {
+		_ = conn.SetDeadline(time.Now().Add(10 * time.Second))
+	}
+	if err := c.handshake(opts); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	_ = conn.SetDeadline(time.Time{})
+
+	go c.readLoop()
+	go c.pingLoop()
+	return c, nil
+}
+
+// Publish sends a QoS 0 message.
+//
+// param topic The topic name.
+// param payload The message payload.
+// param retain Whether the broker keeps the message for future subscribers.
+// return error ErrClosed, or a write error.
+func (c *Client) Publish(topic string, payload []byte, retain bool) error {
+	var body []byte
+	body = appendString(body, topic)
+	body = append(body, payload...)
+	flags := byte(0)
+	if retain {
+		flags = 0x01
+	}
+	return c.writePacket(packetPublish<<4|flags, body)
+}
+
+// Subscribe subscribes to topic filters with QoS 0. Wildcards (+, #) are allowed.
+//
+// param filters The topic filters.
+// return error ErrClosed, or a write error.
+func (c *Client) Subscribe(filters ...string) error {
+	body := binary.BigEndian.AppendUint16(nil, c.nextPacketID())
+	for _, filter := range filters {
+		body = appendString(body, filter)
+		body = append(body, 0)
+	}
+	return c.writePacket(packetSubscribe<<4|0x02, body)
+}
+
+// Done is closed when the connection ended.
+func (c *Client) Done() <-chan struct{} {
+	return c.done
+}
+
+// Err returns why the connection ended, once Done is closed.
+func (c *Client) Err() error {
+	select {
+	case <-c.done:
+		return c.err
+	default:
+		return nil
+	}
+}
+
+// Close disconnects cleanly; the broker does not publish the last will.
+func (c *Client) Close() error {
+	_ = c.writePacket(packetDisconnect<<4, nil)
+	c.shutdown(ErrClosed)
+	return nil
+}
+
+// handshake sends CONNECT and waits for CONNACK.
+func (c *Client) handshake(opts Options) error {
+	flags := byte(0x02) // clean session
+	var payload []byte
+	payload = appendString(payload, opts.ClientID)
+	if opts.WillTopic != "" {
+		flags |= 0x04 | 0x20 // will flag, will retain
+		payload = appendString(payload, opts.WillTopic)
+		payload = appendBytes(payload, opts.WillPayload)
+	}
+	if opts.Username != "" {
+		flags |= 0x80
+		payload = appendString(payload, opts.Username)
+		if opts.Password != "" {
+			flags |= 0x40
+			payload = appendString(payload, opts.Password)
+		}
+	}
+
+	var body []byte
+	body = appendString(body, "MQTT")
+	body = append(body, 4, flags)
+	body = binary.BigEndian.AppendUint16(body, uint16(opts.KeepAlive/time.Second))
+	body = append(body, payload...)
+	if err := c.writePacket(packetConnect<<4, body); err != nil {
+		return err
+	}
+
+	header, ack, err := c.readPacket()
+	if err != nil {
+		return fmt.Errorf("failed to read MQTT CONNACK: %w", err)
+	}
+	if header>>4 != packetConnAck || len(ack) != 2 {
+		return fmt.Errorf("unexpected MQTT packet %d during handshake", header>>4)
+	}
+	if ack[1] != 0 {
+		return fmt.Errorf("MQTT broker refused the connection: %s", connAckReason(ack[1]))
+	}
+	return nil
+}
+
+// readLoop dispatches incoming packets until the connection fails.
+func (c *Client) readLoop() {
+	for {
+		_ = c.conn.SetReadDeadline(time.Now().Add(c.keepAlive * 3 / 2))
+		header, body, err := c.readPacket()
+		if err != nil {
+			c.shutdown(err)
+			return
+		}
+
+		switch header >> 4 {
+		case packetPublish:
+			message, packetID, err := parsePublish(header, body)
+			if err != nil {
+				c.shutdown(err)
+				return
+			}
+			if packetID != 0 {
+				_ = c.writePacket(packetPubAck<<4, binary.BigEndian.AppendUint16(nil, packetID))
+			}
+			if c.handler != nil {
+				c.handler(message)
+			}
+		case packetSubAck:
+			for _, code := range body[min(2, len(body)):] {
+				if code == 0x80 {
+					c.shutdown(fmt.Errorf("MQTT broker rejected a subscription"))
+					return
+				}
+			}
+		case packetPingResp, packetPubAck:
+		default:
+			c.shutdown(fmt.Errorf("unexpected MQTT packet %d", header>>4))
+			return
+		}
+	}
+}
+
+// pingLoop keeps the session alive while the client is idle.
+func (c *Client) pingLoop() {
+	ticker := time.NewTicker(c.keepAlive / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if err := c.writePacket(packetPingReq<<4, nil); err != nil {
+				return
+			}
+		case <-c.done:
+			return
+		}
+	}
+}
+
+// writePacket writes a control packet with its fixed header.
+func (c *Client) writePacket(header byte, body []byte) error {
+	select {
+	case <-c.done:
+		return ErrClosed
+	default:
+	}
+	if len(body) > maxRemainingBytes {
+		return fmt.Errorf("MQTT packet too large (%d bytes)", len(body))
+	}
+
+	packet := append([]byte{header}, encodeRemainingLength(len(body))...)
+	packet = append(packet, body...)
+
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	_ = c.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := c.conn.Write(packet); err != nil {
+		c.shutdown(err)
+		return fmt.Errorf("failed to write MQTT packet: %w", err)
+	}
+	return nil
+}
+
+// readPacket reads one control packet.
+func (c *Client) readPacket() (byte, []byte, error) {
+	header, err := c.reader.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	length, multiplier := 0, 1
+	for i := 0; ; i++ {
+		if i == 4 {
+			return 0, nil, fmt.Errorf("malformed MQTT remaining length")
+		}
+		b, err := c.reader.ReadByte()
+		if err != nil {
+			return 0, nil, err
+		}
+		length += int(b&0x7f) * multiplier
+		if b&0x80 == 0 {
+			break
+		}
+		multiplier *= 128
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.reader, body); err != nil {
+		return 0, nil, err
+	}
+	return header, body, nil
+}
+
+// shutdown closes the connection once and records why.
+func (c *Client) shutdown(err error) {
+	c.closeOnce.Do(func() {
+		c.err = err
+		close(c.done)
+		c.conn.Close()
+	})
+}
+
+// nextPacketID returns a non-zero packet identifier.
+func (c *Client) nextPacketID() uint16 {
+	for {
+		if id := uint16(atomic.AddUint32(&c.packetID, 1)); id != 0 {
+			return id
+		}
+	}
+}
+
+// parsePublish decodes a PUBLISH packet, returning the packet ID for QoS 1 and 2 deliveries.
+func parsePublish(header byte, body []byte) (Message, uint16, error) {
+	if len(body) < 2 {
+		return Message{}, 0, fmt.Errorf("malformed MQTT PUBLISH")
+	}
+	topicLength := int(binary.BigEndian.Uint16(body))
+	offset := 2 + topicLength
+	if len(body) < offset {
+		return Message{}, 0, fmt.Errorf("malformed MQTT PUBLISH")
+	}
+	message := Message{Topic: string(body[2:offset])}
+	var packetID uint16
+	if (header>>1)&0x03 > 0 {
+		if len(body) < offset+2 {
+			return Message{}, 0, fmt.Errorf("malformed MQTT PUBLISH")
+		}
+		packetID = binary.BigEndian.Uint16(body[offset:])
+		offset += 2
+	}
+	message.Payload = body[offset:]
+	return message, packetID, nil
+}
+
+// brokerAddress converts a broker URL into a host:port address.
+func brokerAddress(broker string) (string, bool, error) {
+	parsed, err := url.Parse(broker)
+	if err != nil || parsed.Host == "" {
+		return "", false, fmt.Errorf("invalid MQTT broker URL %q", broker)
+	}
+	var useTLS bool
+	port := "1883"
+	switch parsed.Scheme {
+	case "tcp", "mqtt":
+	case "ssl", "tls", "mqtts":
+		useTLS = true
+		port = "8883"
+	default:
+		return "", false, fmt.Errorf("unsupported MQTT broker scheme %q (use tcp, mqtt, ssl, tls or mqtts)", parsed.Scheme)
+	}
+	if parsed.Port() != "" {
+		port = parsed.Port()
+	}
+	return net.JoinHostPort(parsed.Hostname(), port), useTLS, nil
+}
+
+// connAckReason describes a CONNACK return code.
+func connAckReason(code byte) string {
+	switch code {
+	case 1:
+		return "unacceptable protocol version"
+	case 2:
+		return "client identifier rejected"
+	case 3:
+		return "server unavailable"
+	case 4:
+		return "bad user name or password"
+	case 5:
+		return "not authorized"
+	default:
+		return fmt.Sprintf("return code %d", code)
+	}
+}
+
+// encodeRemainingLength encodes the variable-length remaining length of the fixed header.
+func encodeRemainingLength(length int) []byte {
+	var encoded []byte
+	for {
+		b := byte(length % 128)
+		length /= 128
+		if length > 0 {
+			b |= 0x80
+		}
+		encoded = append(encoded, b)
+		if length == 0 {
+			return encoded
+		}
+	}
+}
+
+// appendString appends a length-prefixed UTF-8 string.
+func appendString(b []byte, s string) []byte {
+	return appendBytes(b, []byte(s))
+}
+
+// appendBytes appends length-prefixed binary data.
+func appendBytes(b []byte, data []byte) []byte {
+	b = binary.BigEndian.AppendUint16(b, uint16(len(data)))
+	return append(b, data...)
+}
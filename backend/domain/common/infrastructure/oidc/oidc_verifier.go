@@ -0,0 +1,293 @@
+package oidc
+
+import (
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"time"
+)
+
+// jwksRefreshInterval limits how often the signing keys are refetched for tokens signed with an unknown key.
+const jwksRefreshInterval = time.Minute
+
+// ErrInvalidToken is returned for tokens that are malformed, wrongly signed, expired or meant for another client.
+var ErrInvalidToken = errors.New("oidc: invalid token")
+
+// Claims are the verified claims of a token.
+type Claims map[string]interface{}
+
+// String returns a string claim, or "" if it is missing or not a string.
+//
+// param name The claim name.
+// return string The claim value.
+func (c Claims) String(name string) string {
+	value, _ := c[name].(string)
+	return value
+}
+
+// Strings returns a claim holding a list of strings (or a single string).
+//
+// param name The claim name.
+// return []string The claim values.
+func (c Claims) Strings(name string) []string {
+	switch value := c[name].(type) {
+	case string:
+		return []string{value}
+	case []interface{}:
+		result := make([]string, 0, len(value))
+		for _, item := range value {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+// Verifier verifies ID tokens issued by an OpenID Connect provider. The signing keys are discovered from
+// the issuer's /.well-known/openid-configuration and cached; RS256 and ES256 signatures are supported.
+type Verifier struct {
+	issuer   string
+	clientID string
+	client   *http.Client
+
+	mu        sync.Mutex
+	jwksURI   string
+	keys      map[string]crypto.PublicKey
+	fetchedAt time.Time
+}
+
+// NewVerifier creates a Verifier for an issuer. Keys are fetched on first use.
+//
+// param issuer The issuer URL (e.g., https://login.example.com/realms/building).
+// param clientID The client ID tokens must be issued to (the aud claim).
+// param client The HTTP client used for discovery (nil for a pooled client with a 10s timeout).
+// return *Verifier A pointer to the verifier.
+func NewVerifier(issuer, clientID string, client *http.Client) *Verifier {
+	if client == nil {
+		client = httpclient.New(10 * time.Second)
+	}
+	return &Verifier{
+		issuer:   strings.TrimSuffix(issuer, "/"),
+		clientID: clientID,
+		client:   client,
+		keys:     make(map[string]crypto.PublicKey),
+	}
+}
+
+// Verify checks the signature, issuer, audience and expiry of a token and returns its claims.
+//
+// param ctx The context bounding key discovery.
+// param token The compact JWT.
+// param now The current time.
+// return Claims The verified claims.
+// return error ErrInvalidToken, or an error if the signing keys cannot be fetched.
+func (v *Verifier) Verify(ctx context.Context, token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, ErrInvalidToken
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	key, err := v.key(ctx, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	switch header.Alg {
+	case "RS256":
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok || rsa.VerifyPKCS1v15(rsaKey, crypto.SHA256, digest[:], signature) != nil {
+			return nil, ErrInvalidToken
+		}
+	case "ES256":
+		ecKey, ok := key.(*ecdsa.PublicKey)
+		if !ok || len(signature) != 64 {
+			return nil, ErrInvalidToken
+		}
+		r := new(big.Int).SetBytes(signature[:32])
+		s := new(big.Int).SetBytes(signature[32:])
+		if !ecdsa.Verify(ecKey, digest[:], r, s) {
+			return nil, ErrInvalidToken
+		}
+	default:
+		return nil, ErrInvalidToken
+	}
+
+	var claims Claims
+	if err := decodeSegment(parts[1], &claims); err != nil {
+		return nil, ErrInvalidToken
+	}
+	if strings.TrimSuffix(claims.String("iss"), "/") != v.issuer {
+		return nil, ErrInvalidToken
+	}
+	audienceOK := false
+	for _, audience := range claims.Strings("aud") {
+		if audience == v.clientID {
+			audienceOK = true
+		}
+	}
+	if !audienceOK {
+		return nil, ErrInvalidToken
+	}
+	exp, ok := claims["exp"].(float64)
+	if !ok || now.Unix() >= int64(exp) {
+		return nil, ErrInvalidToken
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now.Unix() < int64(nbf) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// key returns the signing key with the given ID, refetching the keys when it is unknown.
+func (v *Verifier) key(ctx context.Context, kid string) (crypto.PublicKey, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	if !v.fetchedAt.IsZero() && time.Since(v.fetchedAt) < jwksRefreshInterval {
+		return nil, ErrInvalidToken
+	}
+	if err := v.refresh(ctx); err != nil {
+		return nil, err
+	}
+	if key, ok := v.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, ErrInvalidToken
+}
+
+// lookup finds a key by ID; tokens without a key ID are accepted when the provider has a single key.
+// The caller must hold v.mu.
+func (v *Verifier) lookup(kid string) (crypto.PublicKey, bool) {
+	if key, ok := v.keys[kid]; ok {
+		return key, true
+	}
+	if kid == "" && len(v.keys) == 1 {
+		for _, key := range v.keys {
+			return key, true
+		}
+	}
+	return nil, false
+}
+
+// refresh discovers the JWKS URI (once) and fetches the signing keys. The caller must hold v.mu.
+func (v *Verifier) refresh(ctx context.Context) error {
+	v.fetchedAt = time.Now()
+	if v.jwksURI == "" {
+		var discovery struct {
+			Issuer  string `json:"issuer"`
+			JWKSURI string `json:"jwks_uri"`
+		}
+		if err := v.getJSON(ctx, v.issuer+"/.well-known/openid-configuration", &discovery); err != nil {
+			return fmt.Errorf("oidc: discovery failed: %w", err)
+		}
+		if discovery.JWKSURI == "" {
+			return fmt.Errorf("oidc: discovery document of %s has no jwks_uri", v.issuer)
+		}
+		v.jwksURI = discovery.JWKSURI
+	}
+
+	var jwks struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Use string `json:"use"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			Crv string `json:"crv"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}
+	if err := v.getJSON(ctx, v.jwksURI, &jwks); err != nil {
+		return fmt.Errorf("oidc: failed to fetch signing keys: %w", err)
+	}
+
+	keys := make(map[string]crypto.PublicKey)
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		switch jwk.Kty {
+		case "RSA":
+			n, errN := base64.RawURLEncoding.DecodeString(jwk.N)
+			e, errE := base64.RawURLEncoding.DecodeString(jwk.E)
+			if errN != nil || errE != nil || len(e) == 0 || len(e) > 4 {
+				continue
+			}
+			exponent := 0
+			for _, b := range e {
+				exponent = exponent<<8 | int(b)
+			}
+			keys[jwk.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}
+		case "EC":
+			if jwk.Crv != "P-256" {
+				continue
+			}
+			x, errX := base64.RawURLEncoding.DecodeString(jwk.X)
+			y, errY := base64.RawURLEncoding.DecodeString(jwk.Y)
+			if errX != nil || errY != nil {
+				continue
+			}
+			keys[jwk.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		}
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("oidc: no usable signing keys at %s", v.jwksURI)
+	}
+	v.keys = keys
+	return nil
+}
+
+// getJSON fetches and decodes a JSON document.
+func (v *Verifier) getJSON(ctx context.Context, url string, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+	resp, err := v.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("GET %s returned %d", url, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// decodeSegment decodes a base64url JSON segment of a JWT.
+func decodeSegment(segment string, out interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,271 @@
+package outbound
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"teralux_app/domain/common/utils"
+	"time"
+)
+
+// ErrDestinationNotAllowed is returned when an outbound destination fails validation.
+var ErrDestinationNotAllowed = errors.New("outbound destination not allowed")
+
+// blockedNetworks are ranges an admin-registered destination must not reach unless trusted:
+// loopback, private, link-local (including cloud metadata at 169.254.169.254), CGNAT and unique local addresses.
+var blockedNetworks = mustParseCIDRs(
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+)
+
+// Guard validates outbound destinations registered by admins (webhooks, MQTT brokers) against SSRF.
+// With OUTBOUND_ALLOWLIST set, only listed hosts, *.domain wildcards and CIDRs are reachable.
+// Private, loopback and link-local addresses are refused unless OUTBOUND_ALLOW_PRIVATE is true or the
+// destination is explicitly allow-listed, which is how trusted self-hosted targets are admitted.
+// Checks run on the resolved addresses, and the guarded dialer repeats them at connection time so a
+// hostname cannot be re-pointed at an internal address after validation (DNS rebinding).
+type Guard struct {
+	hosts        []string
+	networks     []*net.IPNet
+	allowPrivate bool
+	resolver     *net.Resolver
+}
+
+// NewGuard initializes a Guard from OUTBOUND_ALLOWLIST and OUTBOUND_ALLOW_PRIVATE.
+//
+// return *Guard A pointer to the initialized guard.
+func NewGuard() *Guard {
+	config := utils.GetConfig()
+	guard := &Guard{
+		allowPrivate: config.OutboundAllowPrivate,
+		resolver:     net.DefaultResolver,
+	}
+
+	for _, entry := range strings.Split(config.OutboundAllowlist, ",") {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if _, network, err := net.ParseCIDR(entry); err == nil {
+			guard.networks = append(guard.networks, network)
+			continue
+		}
+		if ip := net.ParseIP(entry); ip != nil {
+			guard.networks = append(guard.networks, singleIPNetwork(ip))
+			continue
+		}
+		guard.hosts = append(guard.hosts, entry)
+	}
+	return guard
+}
+
+// ValidateURL checks that a URL uses one of the allowed schemes and points at an allowed destination.
+//
+// param ctx The context bounding DNS resolution.
+// param rawURL The destination URL.
+// param schemes The accepted schemes (e.g., "https", "http", "mqtts").
+// return error An error wrapping ErrDestinationNotAllowed when the destination is refused.
+func (g *Guard) ValidateURL(ctx context.Context, rawURL string, schemes ...string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Host == "" {
+		return fmt.Errorf("%w: invalid URL %q", ErrDestinationNotAllowed, rawURL)
+	}
+	if parsed.User != nil {
+		return fmt.Errorf("%w: credentials in the URL are not allowed", ErrDestinationNotAllowed)
+	}
+	if !containsFold(schemes, parsed.Scheme) {
+		return fmt.Errorf("%w: scheme %q is not allowed (use %s)", ErrDestinationNotAllowed, parsed.Scheme, strings.Join(schemes, ", "))
+	}
+	return g.ValidateHost(ctx, parsed.Hostname())
+}
+
+// ValidateHost checks that a host name or IP address is an allowed destination.
+//
+// param ctx The context bounding DNS resolution.
+// param host The host name or IP address, without port.
+// return error An error wrapping ErrDestinationNotAllowed when the destination is refused.
+func (g *Guard) ValidateHost(ctx context.Context, host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "" {
+		return fmt.Errorf("%w: empty host", ErrDestinationNotAllowed)
+	}
+
+	ips, err := g.resolve(ctx, host)
+	if err != nil {
+		return fmt.Errorf("%w: cannot resolve %s: %v", ErrDestinationNotAllowed, host, err)
+	}
+	for _, ip := range ips {
+		if err := g.checkIP(host, ip); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DialContext connects like net.Dialer but refuses addresses that fail validation at connection time.
+// Use it as the DialContext of transports that talk to admin-registered destinations.
+//
+// param ctx The dial context.
+// param network The network (e.g., "tcp").
+// param address The host:port to connect to.
+// return net.Conn The established connection.
+// return error An error wrapping ErrDestinationNotAllowed when the destination is refused.
+func (g *Guard) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid address %q", ErrDestinationNotAllowed, address)
+	}
+
+	ips, err := g.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: 30 * time.Second}
+	var lastErr error
+	for _, ip := range ips {
+		if err := g.checkIP(strings.ToLower(host), ip); err != nil {
+			return nil, err
+		}
+		// Dial the validated address, not the name, so the check and the connection see the same IP
+		conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// HTTPClient returns an HTTP client whose connections go through the guarded dialer.
+// Redirects are followed only to destinations that pass validation.
+//
+// param timeout The overall request timeout.
+// return *http.Client The guarded client.
+func (g *Guard) HTTPClient(timeout time.Duration) *http.Client {
+	// A dedicated transport without proxy, so connections never bypass the guarded dialer
+	transport := httpclient.NewTransport()
+	transport.Proxy = nil
+	transport.DialContext = g.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+			return g.ValidateURL(req.Context(), req.URL.String(), "http", "https")
+		},
+	}
+}
+
+// checkIP applies the allow-list and the private range rule to one resolved address of host.
+func (g *Guard) checkIP(host string, ip net.IP) error {
+	allowListed := g.isAllowListed(host, ip)
+	if (len(g.hosts) > 0 || len(g.networks) > 0) && !allowListed {
+		return fmt.Errorf("%w: %s is not in OUTBOUND_ALLOWLIST", ErrDestinationNotAllowed, host)
+	}
+	if allowListed || g.allowPrivate {
+		return nil
+	}
+	if isBlockedIP(ip) {
+		return fmt.Errorf("%w: %s resolves to the private or reserved address %s", ErrDestinationNotAllowed, host, ip)
+	}
+	return nil
+}
+
+// isAllowListed reports whether a host name or its resolved address matches OUTBOUND_ALLOWLIST.
+func (g *Guard) isAllowListed(host string, ip net.IP) bool {
+	for _, allowed := range g.hosts {
+		if host == allowed {
+			return true
+		}
+		if strings.HasPrefix(allowed, "*.") && strings.HasSuffix(host, allowed[1:]) {
+			return true
+		}
+	}
+	for _, network := range g.networks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// resolve returns the addresses of a host, which may already be an IP literal.
+func (g *Guard) resolve(ctx context.Context, host string) ([]net.IP, error) {
+	if ip := net.ParseIP(host); ip != nil {
+		return []net.IP{ip}, nil
+	}
+	addrs, err := g.resolver.LookupIPAddr(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	ips := make([]net.IP, 0, len(addrs))
+	for _, addr := range addrs {
+		ips = append(ips, addr.IP)
+	}
+	if len(ips) == 0 {
+		return nil, fmt.Errorf("no addresses for %s", host)
+	}
+	return ips, nil
+}
+
+// isBlockedIP reports whether an address is unspecified, multicast or in a blocked range.
+func isBlockedIP(ip net.IP) bool {
+	if ip.IsUnspecified() || ip.IsMulticast() {
+		return true
+	}
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	for _, network := range blockedNetworks {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// singleIPNetwork returns the network containing only ip.
+func singleIPNetwork(ip net.IP) *net.IPNet {
+	if v4 := ip.To4(); v4 != nil {
+		return &net.IPNet{IP: v4, Mask: net.CIDRMask(32, 32)}
+	}
+	return &net.IPNet{IP: ip, Mask: net.CIDRMask(128, 128)}
+}
+
+// mustParseCIDRs parses constant CIDR ranges.
+func mustParseCIDRs(cidrs ...string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		networks = append(networks, network)
+	}
+	return networks
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,225 @@
+package persistence
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"time"
+)
+
+const (
+	defaultGCInterval     = 10 * time.Minute
+	defaultGCDiscardRatio = 0.5
+	// maxGCRewritesPerRun bounds the value log files rewritten by one run, so a run cannot monopolize the disk.
+	maxGCRewritesPerRun = 100
+)
+
+// ErrMaintenanceNotApplicable is returned when the cache store is not BadgerDB (e.g. Redis), whose server
+// manages its own storage.
+var ErrMaintenanceNotApplicable = errors.New("storage maintenance only applies to the badger cache backend")
+
+// GC run triggers.
+const (
+	GCTriggerScheduled = "scheduled"
+	GCTriggerManual    = "manual"
+)
+
+// GCRun is the outcome of a value log garbage collection run.
+type GCRun struct {
+	Trigger    string
+	StartedAt  int64
+	DurationMs int64
+	Rewrites   int
+	Error      string
+}
+
+// GCStatus reports the garbage collection schedule and its history.
+type GCStatus struct {
+	Interval      string
+	DiscardRatio  float64
+	Running       bool
+	TotalRuns     int64
+	TotalRewrites int64
+	LastRun       *GCRun
+}
+
+// BadgerMaintenanceService keeps the BadgerDB value log from growing unbounded. BadgerDB only reclaims
+// the space of overwritten, deleted and expired entries when value log garbage collection runs, so the
+// service runs it every BADGER_GC_INTERVAL, rewriting files with at least BADGER_GC_DISCARD_RATIO stale
+// data until none is left. Runs can also be requested on demand; only one runs at a time.
+type BadgerMaintenanceService struct {
+	db           *BadgerService
+	interval     time.Duration
+	discardRatio float64
+
+	mu            sync.Mutex
+	running       bool
+	totalRuns     int64
+	totalRewrites int64
+	lastRun       *GCRun
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewBadgerMaintenanceService initializes a new BadgerMaintenanceService from BADGER_GC_INTERVAL and
+// BADGER_GC_DISCARD_RATIO.
+//
+// param db The BadgerService to maintain (nil when persistence is unavailable or the cache backend is Redis).
+// return *BadgerMaintenanceService A pointer to the initialized service.
+func NewBadgerMaintenanceService(db *BadgerService) *BadgerMaintenanceService {
+	config := utils.GetConfig()
+
+	interval := defaultGCInterval
+	if config.BadgerGCInterval != "" {
+		parsed, err := time.ParseDuration(config.BadgerGCInterval)
+		if err != nil || parsed < 0 {
+			utils.LogWarn("Invalid BADGER_GC_INTERVAL %q, using %s", config.BadgerGCInterval, defaultGCInterval)
+		} else {
+			interval = parsed
+		}
+	}
+	discardRatio := defaultGCDiscardRatio
+	if config.BadgerGCDiscardRatio != "" {
+		parsed, err := strconv.ParseFloat(config.BadgerGCDiscardRatio, 64)
+		if err != nil || parsed <= 0 || parsed >= 1 {
+			utils.LogWarn("Invalid BADGER_GC_DISCARD_RATIO %q, using %g", config.BadgerGCDiscardRatio, defaultGCDiscardRatio)
+		} else {
+			discardRatio = parsed
+		}
+	}
+
+	return &BadgerMaintenanceService{
+		db:           db,
+		interval:     interval,
+		discardRatio: discardRatio,
+	}
+}
+
+// Start runs garbage collection in the background at BADGER_GC_INTERVAL.
+// It does nothing when the interval is 0 or persistence is unavailable.
+func (s *BadgerMaintenanceService) Start() {
+	if s.interval == 0 || s.db == nil {
+		return
+	}
+
+	s.startOnce.Do(func() {
+		s.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					run, err := s.RunGC(GCTriggerScheduled)
+					switch {
+					case errors.Is(err, ErrGCNotApplicable):
+						utils.LogInfo("BadgerMaintenanceService: In-memory database, stopping scheduled GC")
+						return
+					case errors.Is(err, ErrGCRunning):
+						utils.LogDebug("BadgerMaintenanceService: Skipping scheduled GC, a run is in progress")
+					case err != nil:
+						utils.LogError("BadgerMaintenanceService: GC failed: %v", err)
+					case run.Rewrites > 0:
+						utils.LogInfo("BadgerMaintenanceService: GC rewrote %d value log files in %dms", run.Rewrites, run.DurationMs)
+					}
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("BadgerMaintenanceService: Running value log GC every %s (discard ratio %g)", s.interval, s.discardRatio)
+	})
+}
+
+// Stop ends the schedule and waits for a run in progress to finish.
+func (s *BadgerMaintenanceService) Stop() {
+	s.workers.Stop()
+}
+
+// RunGC runs value log garbage collection until no file has enough stale data left.
+//
+// param trigger GCTriggerScheduled or GCTriggerManual.
+// return *GCRun The outcome of the run.
+// return error ErrGCRunning while another run is in progress, ErrGCNotApplicable for in-memory
+// databases, ErrMaintenanceNotApplicable without BadgerDB, or an error if a rewrite fails (the run is
+// still recorded).
+func (s *BadgerMaintenanceService) RunGC(trigger string) (*GCRun, error) {
+	if s.db == nil {
+		return nil, ErrMaintenanceNotApplicable
+	}
+
+	s.mu.Lock()
+	if s.running {
+		s.mu.Unlock()
+		return nil, ErrGCRunning
+	}
+	s.running = true
+	s.mu.Unlock()
+
+	start := time.Now()
+	run := &GCRun{Trigger: trigger, StartedAt: start.Unix()}
+	var runErr error
+	for run.Rewrites < maxGCRewritesPerRun {
+		rewritten, err := s.db.RunValueLogGC(s.discardRatio)
+		if err != nil {
+			runErr = err
+			break
+		}
+		if !rewritten {
+			break
+		}
+		run.Rewrites++
+	}
+	run.DurationMs = time.Since(start).Milliseconds()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.running = false
+	if errors.Is(runErr, ErrGCNotApplicable) || errors.Is(runErr, ErrGCRunning) {
+		return nil, runErr
+	}
+	if runErr != nil {
+		run.Error = runErr.Error()
+	}
+	s.totalRuns++
+	s.totalRewrites += int64(run.Rewrites)
+	s.lastRun = run
+	copied := *run
+	return &copied, runErr
+}
+
+// StorageStats returns the disk usage and compaction state of BadgerDB.
+//
+// return StorageStats The stats.
+// return error ErrMaintenanceNotApplicable without BadgerDB.
+func (s *BadgerMaintenanceService) StorageStats() (StorageStats, error) {
+	if s.db == nil {
+		return StorageStats{}, ErrMaintenanceNotApplicable
+	}
+	return s.db.StorageStats(), nil
+}
+
+// Status returns the schedule and history of garbage collection.
+//
+// return GCStatus The status.
+func (s *BadgerMaintenanceService) Status() GCStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := GCStatus{
+		Interval:      s.interval.String(),
+		DiscardRatio:  s.discardRatio,
+		Running:       s.running,
+		TotalRuns:     s.totalRuns,
+		TotalRewrites: s.totalRewrites,
+	}
+	if s.interval == 0 {
+		status.Interval = "off"
+	}
+	if s.lastRun != nil {
+		last := *s.lastRun
+		status.LastRun = &last
+	}
+	return status
+}
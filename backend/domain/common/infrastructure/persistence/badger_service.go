@@ -1,18 +1,23 @@
 package persistence
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"time"
 
 	"github.com/dgraph-io/badger/v3"
+	"teralux_app/domain/common/infrastructure/tracing"
 	"teralux_app/domain/common/utils"
 )
 
 // BadgerService handles BadgerDB operations for caching and data persistence.
 // It wraps the raw BadgerDB client to provide simplified methods for common operations.
 type BadgerService struct {
-	db         *badger.DB
-	defaultTTL time.Duration
+	db  *badger.DB
+	ctx context.Context
 }
 
 // NewBadgerService initializes a new BadgerService instance.
@@ -30,13 +35,24 @@ func NewBadgerService(dbPath string) (*BadgerService, error) {
 		return nil, fmt.Errorf("failed to open badger db: %w", err)
 	}
 
-	ttlStr := utils.AppConfig.CacheTTL
-	ttl, err := time.ParseDuration(ttlStr)
+	return &BadgerService{db: db}, nil
+}
+
+// NewInMemoryBadgerService initializes a BadgerService that keeps everything in memory.
+// Nothing is written to disk, so each instance starts empty; it is used by the integration test mode.
+//
+// return *BadgerService A pointer to the initialized service instance ready for use.
+// return error An error if the database cannot be opened.
+func NewInMemoryBadgerService() (*BadgerService, error) {
+	opts := badger.DefaultOptions("").WithInMemory(true)
+	opts.Logger = nil
+
+	db, err := badger.Open(opts)
 	if err != nil {
-		ttl = 1 * time.Hour // Default to 1 hour if invalid or not set
+		return nil, fmt.Errorf("failed to open in-memory badger db: %w", err)
 	}
 
-	return &BadgerService{db: db, defaultTTL: ttl}, nil
+	return &BadgerService{db: db}, nil
 }
 
 // Close terminates the database connection and ensures all data is flushed to disk.
@@ -50,24 +66,87 @@ func (s *BadgerService) Close() error {
 	return nil
 }
 
-// Set stores a key-value pair in the database using the configured default Time-To-Live (TTL).
+// WithContext returns a view of the service whose operations are traced as children of the span in ctx,
+// e.g. the cache lookups of a request. It shares the database with the service.
+//
+// param ctx The request context.
+// return CacheStore The traced view.
+func (s *BadgerService) WithContext(ctx context.Context) CacheStore {
+	if s == nil {
+		return nil
+	}
+	clone := *s
+	clone.ctx = ctx
+	return &clone
+}
+
+// startSpan starts the span of an operation when the service is bound to a request context.
+func (s *BadgerService) startSpan(operation, key string) *tracing.Span {
+	if s.ctx == nil {
+		return nil
+	}
+	_, span := tracing.Start(s.ctx, "badger."+operation)
+	span.SetAttribute("db.system", "badger")
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("db.key", key)
+	return span
+}
+
+// Set stores a key-value pair in the database with an explicit Time-To-Live (TTL).
+// Callers pick the TTL of their resource type (see CacheTTLPolicy); use SetPersistent for data without expiry.
 //
 // param key The unique identifier for the data.
 // param value The byte array data to store.
+// param ttl The duration after which the key expires.
 // return error An error if the write operation fails.
 // @throws error If the transaction fails to commit.
-func (s *BadgerService) Set(key string, value []byte) error {
+func (s *BadgerService) Set(key string, value []byte, ttl time.Duration) error {
+	span := s.startSpan("set", key)
+	defer span.End()
+
 	err := s.db.Update(func(txn *badger.Txn) error {
-		entry := badger.NewEntry([]byte(key), value).WithTTL(s.defaultTTL)
+		entry := badger.NewEntry([]byte(key), value).WithTTL(ttl)
 		return txn.SetEntry(entry)
 	})
 	if err != nil {
-		utils.LogError("BadgerService: failed to set key %s: %v", key, err)
+		utils.LogError("BadgerService: failed to set key %s with ttl %v: %v", key, ttl, err)
 		return err
 	}
 	return nil
 }
 
+// SetIfNotExists atomically stores a key with a TTL only if the key does not exist yet.
+// This is used for one-time markers such as request nonces.
+//
+// param key The unique identifier for the data.
+// param value The byte array data to store.
+// param ttl The duration after which the key expires.
+// return bool True if the key was stored, false if it already existed.
+// return error An error if the transaction fails.
+func (s *BadgerService) SetIfNotExists(key string, value []byte, ttl time.Duration) (bool, error) {
+	span := s.startSpan("set_if_not_exists", key)
+	defer span.End()
+
+	stored := false
+	err := s.db.Update(func(txn *badger.Txn) error {
+		_, err := txn.Get([]byte(key))
+		if err == nil {
+			return nil
+		}
+		if err != badger.ErrKeyNotFound {
+			return err
+		}
+		stored = true
+		entry := badger.NewEntry([]byte(key), value).WithTTL(ttl)
+		return txn.SetEntry(entry)
+	})
+	if err != nil {
+		utils.LogError("BadgerService: failed to set-if-not-exists key %s: %v", key, err)
+		return false, err
+	}
+	return stored, nil
+}
+
 // Get retrieves a value associated with the given key.
 // It handles the transaction view automatically.
 //
@@ -76,6 +155,9 @@ func (s *BadgerService) Set(key string, value []byte) error {
 // return error An error if the read operation fails (excluding KeyNotFound).
 // @throws error if an internal database error occurs during the view transaction.
 func (s *BadgerService) Get(key string) ([]byte, error) {
+	span := s.startSpan("get", key)
+	defer span.End()
+
 	var valCopy []byte
 	err := s.db.View(func(txn *badger.Txn) error {
 		item, err := txn.Get([]byte(key))
@@ -114,6 +196,9 @@ func (s *BadgerService) Get(key string) ([]byte, error) {
 // return error An error if the delete operation fails.
 // @throws error If the transaction fails to commit.
 func (s *BadgerService) Delete(key string) error {
+	span := s.startSpan("delete", key)
+	defer span.End()
+
 	err := s.db.Update(func(txn *badger.Txn) error {
 		return txn.Delete([]byte(key))
 	})
@@ -130,6 +215,9 @@ func (s *BadgerService) Delete(key string) error {
 // param prefix The string pattern to match at the beginning of keys.
 // return error An error if the bulk drop operation fails.
 func (s *BadgerService) ClearWithPrefix(prefix string) error {
+	span := s.startSpan("clear_prefix", prefix)
+	defer span.End()
+
 	return s.db.DropPrefix([]byte(prefix))
 }
 
@@ -141,6 +229,9 @@ func (s *BadgerService) ClearWithPrefix(prefix string) error {
 // return error An error if the write operation fails.
 // @throws error If the transaction fails to commit.
 func (s *BadgerService) SetPersistent(key string, value []byte) error {
+	span := s.startSpan("set", key)
+	defer span.End()
+
 	err := s.db.Update(func(txn *badger.Txn) error {
 		// No TTL - data persists indefinitely
 		return txn.Set([]byte(key), value)
@@ -160,6 +251,9 @@ func (s *BadgerService) SetPersistent(key string, value []byte) error {
 // return []string A slice of all matching keys.
 // return error An error if the iteration fails.
 func (s *BadgerService) GetAllKeysWithPrefix(prefix string) ([]string, error) {
+	span := s.startSpan("list_keys", prefix)
+	defer span.End()
+
 	var keys []string
 	err := s.db.View(func(txn *badger.Txn) error {
 		opts := badger.DefaultIteratorOptions
@@ -200,4 +294,284 @@ func (s *BadgerService) FlushAll() error {
 	}
 	utils.LogInfo("BadgerService: Flushed all cache data (preserved persistent data)")
 	return nil
-}
\ No newline at end of file
+}
+
+// Backup writes an incremental backup of all entries changed since the given version.
+// Keys starting with one of the excluded prefixes (e.g. "cache:") are skipped.
+//
+// param w The writer receiving the protobuf-encoded backup.
+// param since The first version to include; 0 produces a full backup.
+// param excludePrefixes Key prefixes that are not part of the backup.
+// return uint64 The highest version written, to be passed (incremented) as since for the next backup.
+// return error An error if the stream fails.
+func (s *BadgerService) Backup(w io.Writer, since uint64, excludePrefixes []string) (uint64, error) {
+	stream := s.db.NewStream()
+	stream.LogPrefix = "BadgerService.Backup"
+	stream.SinceTs = since
+	stream.ChooseKey = func(item *badger.Item) bool {
+		for _, prefix := range excludePrefixes {
+			if bytes.HasPrefix(item.Key(), []byte(prefix)) {
+				return false
+			}
+		}
+		return true
+	}
+
+	version, err := stream.Backup(w, since)
+	if err != nil {
+		utils.LogError("BadgerService: failed to back up since version %d: %v", since, err)
+		return 0, err
+	}
+	return version, nil
+}
+
+// Load applies a backup produced by Backup. It must run before other writers use the database.
+//
+// param r The reader providing the backup.
+// return error An error if the backup is malformed or cannot be written.
+func (s *BadgerService) Load(r io.Reader) error {
+	if err := s.db.Load(r, 256); err != nil {
+		utils.LogError("BadgerService: failed to load backup: %v", err)
+		return err
+	}
+	return nil
+}
+
+// KeyInfo describes a stored key without its value.
+type KeyInfo struct {
+	Key       string
+	Size      int64
+	ExpiresAt uint64 // Unix seconds; 0 means the key never expires
+}
+
+// ListKeys returns metadata of the keys starting with the specified prefix, in key order.
+//
+// param prefix The string pattern to match at the beginning of keys; empty lists all keys.
+// param limit The maximum number of keys returned.
+// return []KeyInfo The matching keys, at most limit.
+// return bool True if more keys match than were returned.
+// return error An error if the iteration fails.
+func (s *BadgerService) ListKeys(prefix string, limit int) ([]KeyInfo, bool, error) {
+	var keys []KeyInfo
+	truncated := false
+	err := s.db.View(func(txn *badger.Txn) error {
+		opts := badger.DefaultIteratorOptions
+		opts.PrefetchValues = false
+		it := txn.NewIterator(opts)
+		defer it.Close()
+
+		prefixBytes := []byte(prefix)
+		for it.Seek(prefixBytes); it.ValidForPrefix(prefixBytes); it.Next() {
+			if len(keys) >= limit {
+				truncated = true
+				break
+			}
+			item := it.Item()
+			keys = append(keys, KeyInfo{
+				Key:       string(item.KeyCopy(nil)),
+				Size:      item.ValueSize(),
+				ExpiresAt: item.ExpiresAt(),
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		utils.LogError("BadgerService: failed to list keys with prefix %s: %v", prefix, err)
+		return nil, false, err
+	}
+	return keys, truncated, nil
+}
+
+// GetWithExpiry retrieves the value of a key together with its expiry.
+//
+// param key The unique identifier to search for.
+// return []byte The value stored under the key, or nil if the key does not exist.
+// return uint64 The expiry as Unix seconds; 0 means the key never expires.
+// return error An error if the read operation fails (excluding KeyNotFound).
+func (s *BadgerService) GetWithExpiry(key string) ([]byte, uint64, error) {
+	span := s.startSpan("get", key)
+	defer span.End()
+
+	var valCopy []byte
+	var expiresAt uint64
+	err := s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get([]byte(key))
+		if err != nil {
+			return err
+		}
+		expiresAt = item.ExpiresAt()
+		valCopy, err = item.ValueCopy(nil)
+		return err
+	})
+	if err != nil {
+		if err == badger.ErrKeyNotFound {
+			return nil, 0, nil
+		}
+		utils.LogError("BadgerService: failed to get key %s: %v", key, err)
+		return nil, 0, err
+	}
+	return valCopy, expiresAt, nil
+}
+
+// KeyValue is a stored key with its value.
+type KeyValue struct {
+	Key   string
+	Value []byte
+}
+
+// ListPersistent returns every entry stored without a TTL, in key order. Entries with a TTL (cache data,
+// sessions, cooldowns) are transient and left out, as are keys starting with one of the excluded prefixes.
+//
+// param excludePrefixes Key prefixes that are not returned.
+// return []KeyValue The persistent entries.
+// return error An error if the iteration fails.
+func (s *BadgerService) ListPersistent(excludePrefixes []string) ([]KeyValue, error) {
+	span := s.startSpan("list_persistent", "")
+	defer span.End()
+
+	var entries []KeyValue
+	err := s.db.View(func(txn *badger.Txn) error {
+		it := txn.NewIterator(badger.DefaultIteratorOptions)
+		defer it.Close()
+
+	next:
+		for it.Rewind(); it.Valid(); it.Next() {
+			item := it.Item()
+			if item.ExpiresAt() != 0 {
+				continue
+			}
+			for _, prefix := range excludePrefixes {
+				if bytes.HasPrefix(item.Key(), []byte(prefix)) {
+					continue next
+				}
+			}
+			value, err := item.ValueCopy(nil)
+			if err != nil {
+				return err
+			}
+			entries = append(entries, KeyValue{Key: string(item.KeyCopy(nil)), Value: value})
+		}
+		return nil
+	})
+	if err != nil {
+		utils.LogError("BadgerService: failed to list persistent entries: %v", err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SetPersistentBatch stores many key-value pairs without a TTL in a single write batch.
+//
+// param entries The entries to store.
+// return error An error if the batch fails to commit; entries may then be partially written.
+func (s *BadgerService) SetPersistentBatch(entries []KeyValue) error {
+	span := s.startSpan("set_batch", "")
+	defer span.End()
+
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, entry := range entries {
+		if err := batch.Set([]byte(entry.Key), entry.Value); err != nil {
+			utils.LogError("BadgerService: failed to batch key %s: %v", entry.Key, err)
+			return err
+		}
+	}
+	if err := batch.Flush(); err != nil {
+		utils.LogError("BadgerService: failed to write %d entries: %v", len(entries), err)
+		return err
+	}
+	utils.LogDebug("BadgerService: Set %d persistent keys (no TTL)", len(entries))
+	return nil
+}
+
+// DeleteBatch removes many keys in a single write batch.
+//
+// param keys The keys to remove.
+// return error An error if the batch fails to commit; keys may then be partially removed.
+func (s *BadgerService) DeleteBatch(keys []string) error {
+	span := s.startSpan("delete_batch", "")
+	defer span.End()
+
+	batch := s.db.NewWriteBatch()
+	defer batch.Cancel()
+	for _, key := range keys {
+		if err := batch.Delete([]byte(key)); err != nil {
+			utils.LogError("BadgerService: failed to batch delete of key %s: %v", key, err)
+			return err
+		}
+	}
+	if err := batch.Flush(); err != nil {
+		utils.LogError("BadgerService: failed to delete %d keys: %v", len(keys), err)
+		return err
+	}
+	utils.LogDebug("BadgerService: Deleted %d keys", len(keys))
+	return nil
+}
+
+var (
+	// ErrGCRunning is returned when value log garbage collection is already running.
+	ErrGCRunning = errors.New("value log garbage collection already running")
+	// ErrGCNotApplicable is returned for in-memory databases, which have no value log.
+	ErrGCNotApplicable = errors.New("value log garbage collection does not apply to in-memory databases")
+)
+
+// RunValueLogGC rewrites at most one value log file whose share of stale data (overwritten, deleted or
+// expired entries) is at least discardRatio, reclaiming its disk space.
+//
+// param discardRatio The minimum share of stale data, between 0 and 1 exclusive.
+// return bool True if a file was rewritten, so another call may reclaim more.
+// return error ErrGCRunning, ErrGCNotApplicable, or an error if the rewrite fails.
+func (s *BadgerService) RunValueLogGC(discardRatio float64) (bool, error) {
+	err := s.db.RunValueLogGC(discardRatio)
+	switch {
+	case err == nil:
+		return true, nil
+	case errors.Is(err, badger.ErrNoRewrite):
+		return false, nil
+	case errors.Is(err, badger.ErrRejected):
+		return false, ErrGCRunning
+	case errors.Is(err, badger.ErrGCInMemoryMode):
+		return false, ErrGCNotApplicable
+	default:
+		utils.LogError("BadgerService: value log GC failed: %v", err)
+		return false, err
+	}
+}
+
+// LevelStats describes one level of the LSM tree.
+type LevelStats struct {
+	Level         int
+	NumTables     int
+	Size          int64
+	TargetSize    int64
+	Score         float64 // Compaction priority; levels above 1 are due for compaction
+	IsBaseLevel   bool
+	StaleDataSize int64
+}
+
+// StorageStats describes the disk usage and compaction state of the database.
+type StorageStats struct {
+	LSMSize  int64 // Bytes of LSM tree files; refreshed by BadgerDB once a minute
+	VLogSize int64 // Bytes of value log files; refreshed by BadgerDB once a minute
+	Levels   []LevelStats
+}
+
+// StorageStats returns the disk usage and the compaction state of every LSM level.
+//
+// return StorageStats The stats.
+func (s *BadgerService) StorageStats() StorageStats {
+	lsm, vlog := s.db.Size()
+	stats := StorageStats{LSMSize: lsm, VLogSize: vlog}
+	for _, level := range s.db.Levels() {
+		stats.Levels = append(stats.Levels, LevelStats{
+			Level:         level.Level,
+			NumTables:     level.NumTables,
+			Size:          level.Size,
+			TargetSize:    level.TargetSize,
+			Score:         level.Score,
+			IsBaseLevel:   level.IsBaseLevel,
+			StaleDataSize: level.StaleDatSize,
+		})
+	}
+	return stats
+}
@@ -0,0 +1,116 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"teralux_app/domain/common/utils"
+)
+
+// Cache backends selectable via CACHE_BACKEND.
+const (
+	CacheBackendBadger = "badger"
+	CacheBackendRedis  = "redis"
+)
+
+// CacheStore is the key-value store behind the cache and persistent application data (device states,
+// automations, sessions, ...). BadgerService keeps it on local disk; RedisService keeps it in a Redis
+// server, so several instances behind a load balancer share cache and device state.
+// Keys without a TTL are persistent; FlushAll only removes "cache:" keys.
+type CacheStore interface {
+	// WithContext returns a view of the store whose operations are traced as children of the span in ctx.
+	WithContext(ctx context.Context) CacheStore
+	// Set stores a value that expires after ttl.
+	Set(key string, value []byte, ttl time.Duration) error
+	// SetIfNotExists stores a value that expires after ttl unless the key exists, reporting whether it was stored.
+	SetIfNotExists(key string, value []byte, ttl time.Duration) (bool, error)
+	// Get returns the value of a key, or nil if the key does not exist.
+	Get(key string) ([]byte, error)
+	// Delete removes a key.
+	Delete(key string) error
+	// ClearWithPrefix removes every key starting with prefix.
+	ClearWithPrefix(prefix string) error
+	// SetPersistent stores a value without a TTL.
+	SetPersistent(key string, value []byte) error
+	// GetAllKeysWithPrefix returns the keys starting with prefix, in key order.
+	GetAllKeysWithPrefix(prefix string) ([]string, error)
+	// FlushAll removes all "cache:" keys, keeping persistent data.
+	FlushAll() error
+	// ListKeys returns metadata of at most limit keys starting with prefix, in key order, and whether more match.
+	ListKeys(prefix string, limit int) ([]KeyInfo, bool, error)
+	// GetWithExpiry returns the value of a key with its expiry in Unix seconds (0 = never).
+	GetWithExpiry(key string) ([]byte, uint64, error)
+	// ListPersistent returns every entry without a TTL, in key order, except keys with an excluded prefix.
+	ListPersistent(excludePrefixes []string) ([]KeyValue, error)
+	// SetPersistentBatch stores many values without a TTL.
+	SetPersistentBatch(entries []KeyValue) error
+	// DeleteBatch removes many keys.
+	DeleteBatch(keys []string) error
+	// Close releases the store.
+	Close() error
+}
+
+// OpenCacheStore opens the store selected by CACHE_BACKEND: BadgerDB under badgerPath (default), or the
+// Redis server at REDIS_URL.
+//
+// param badgerPath The directory of the BadgerDB store.
+// return CacheStore The opened store; nil when an error is returned.
+// return error An error if the backend is unknown or the store cannot be opened.
+func OpenCacheStore(badgerPath string) (CacheStore, error) {
+	config := utils.GetConfig()
+	switch backend := strings.ToLower(strings.TrimSpace(config.CacheBackend)); backend {
+	case "", CacheBackendBadger:
+		store, err := NewBadgerService(badgerPath)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	case CacheBackendRedis:
+		store, err := NewRedisService(config.RedisURL, config.RedisPoolSize)
+		if err != nil {
+			return nil, err
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown CACHE_BACKEND %q (supported: %s, %s)", backend, CacheBackendBadger, CacheBackendRedis)
+	}
+}
+
+// CacheStoreHealth describes the store the server runs on.
+type CacheStoreHealth struct {
+	Backend  string // The configured backend (CACHE_BACKEND, badger when unset)
+	Fallback bool   // Whether the in-memory fallback replaced the configured backend
+	Error    string // Why the configured backend could not be opened
+}
+
+// OpenCacheStoreWithFallback opens the store selected by CACHE_BACKEND like OpenCacheStore. When it cannot be
+// opened (e.g., the BadgerDB directory is locked by another process or corrupted, or Redis is unreachable),
+// an in-memory BadgerDB takes its place so the API keeps working: cached data is fetched again, and
+// persistent data (sessions, device states, ...) only lasts until the next restart.
+//
+// param badgerPath The directory of the BadgerDB store.
+// return CacheStore The opened store; nil when an error is returned.
+// return CacheStoreHealth The configured backend and whether the fallback is in use.
+// return error An error if neither the configured store nor the fallback can be opened.
+func OpenCacheStoreWithFallback(badgerPath string) (CacheStore, CacheStoreHealth, error) {
+	health := CacheStoreHealth{Backend: strings.ToLower(strings.TrimSpace(utils.GetConfig().CacheBackend))}
+	if health.Backend == "" {
+		health.Backend = CacheBackendBadger
+	}
+
+	store, err := OpenCacheStore(badgerPath)
+	if err == nil {
+		return store, health, nil
+	}
+	health.Fallback = true
+	health.Error = err.Error()
+	utils.LogError("CacheStore: Failed to open the %s store, falling back to an in-memory store (data is lost on restart): %v", health.Backend, err)
+
+	fallback, fallbackErr := NewInMemoryBadgerService()
+	if fallbackErr != nil {
+		return nil, health, fmt.Errorf("%w (in-memory fallback: %v)", err, fallbackErr)
+	}
+	return fallback, health, nil
+}
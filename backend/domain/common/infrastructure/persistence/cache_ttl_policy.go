@@ -0,0 +1,189 @@
+package persistence
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"teralux_app/domain/common/utils"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// Cache resource types with their own TTL.
+const (
+	CacheResourceDeviceList    = "device_list"
+	CacheResourceDeviceDetail  = "device_detail"
+	CacheResourceSpecification = "specification"
+	CacheResourceSensor        = "sensor"
+)
+
+const (
+	defaultCacheTTL = 1 * time.Hour
+	// cacheTTLOverridesKey persists runtime overrides outside the "cache:" prefix, so flushing the cache keeps them.
+	cacheTTLOverridesKey = "cache_config:ttl"
+)
+
+// CacheResources lists the resource types in display order.
+var CacheResources = []string{CacheResourceDeviceList, CacheResourceDeviceDetail, CacheResourceSpecification, CacheResourceSensor}
+
+// CacheTTLPolicy decides how long each type of cached resource lives.
+// Defaults come from CACHE_TTL_DEVICE_LIST, CACHE_TTL_DEVICE_DETAIL, CACHE_TTL_SPECIFICATION and CACHE_TTL_SENSOR,
+// falling back to CACHE_TTL and then one hour. Admins can override them at runtime; overrides are persisted.
+type CacheTTLPolicy struct {
+	store    CacheStore
+	defaults map[string]time.Duration
+
+	mu        sync.RWMutex
+	overrides map[string]time.Duration
+}
+
+// NewCacheTTLPolicy initializes a new CacheTTLPolicy and loads persisted overrides.
+//
+// param store The CacheStore persisting runtime overrides (optional).
+// return *CacheTTLPolicy A pointer to the initialized policy.
+func NewCacheTTLPolicy(store CacheStore) *CacheTTLPolicy {
+	config := utils.GetConfig()
+	fallback := parseCacheTTL("CACHE_TTL", config.CacheTTL, defaultCacheTTL)
+
+	p := &CacheTTLPolicy{
+		store: store,
+		defaults: map[string]time.Duration{
+			CacheResourceDeviceList:    parseCacheTTL("CACHE_TTL_DEVICE_LIST", config.CacheTTLDeviceList, fallback),
+			CacheResourceDeviceDetail:  parseCacheTTL("CACHE_TTL_DEVICE_DETAIL", config.CacheTTLDeviceDetail, fallback),
+			CacheResourceSpecification: parseCacheTTL("CACHE_TTL_SPECIFICATION", config.CacheTTLSpecification, fallback),
+			CacheResourceSensor:        parseCacheTTL("CACHE_TTL_SENSOR", config.CacheTTLSensor, fallback),
+		},
+		overrides: make(map[string]time.Duration),
+	}
+	p.loadOverrides()
+	return p
+}
+
+// TTL returns the lifetime of cached entries of a resource type.
+//
+// param resource One of the CacheResource constants.
+// return time.Duration The override if set, otherwise the configured default.
+func (p *CacheTTLPolicy) TTL(resource string) time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if ttl, ok := p.overrides[resource]; ok {
+		return ttl
+	}
+	if ttl, ok := p.defaults[resource]; ok {
+		return ttl
+	}
+	return defaultCacheTTL
+}
+
+// Default returns the configured TTL of a resource type, ignoring overrides.
+//
+// param resource One of the CacheResource constants.
+// return time.Duration The TTL from the environment.
+func (p *CacheTTLPolicy) Default(resource string) time.Duration {
+	if ttl, ok := p.defaults[resource]; ok {
+		return ttl
+	}
+	return defaultCacheTTL
+}
+
+// IsOverridden reports whether a resource type uses a runtime override.
+//
+// param resource One of the CacheResource constants.
+// return bool True if the TTL was changed at runtime.
+func (p *CacheTTLPolicy) IsOverridden(resource string) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	_, ok := p.overrides[resource]
+	return ok
+}
+
+// Update applies runtime overrides. A zero TTL removes the override, restoring the configured default.
+// Already cached entries keep the TTL they were stored with.
+//
+// param changes The new TTL per resource type.
+// return error A bad request error for unknown resources or negative TTLs, or an error if persisting fails.
+func (p *CacheTTLPolicy) Update(changes map[string]time.Duration) error {
+	for resource, ttl := range changes {
+		if _, ok := p.defaults[resource]; !ok {
+			return tuya_errors.BadRequest("unknown cache resource %q", resource)
+		}
+		if ttl < 0 {
+			return tuya_errors.BadRequest("TTL of %s must not be negative", resource)
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	for resource, ttl := range changes {
+		if ttl == 0 {
+			delete(p.overrides, resource)
+		} else {
+			p.overrides[resource] = ttl
+		}
+		utils.LogInfo("CacheTTLPolicy: TTL of %s set to %s", resource, p.ttlLocked(resource))
+	}
+	if err := p.saveOverridesLocked(); err != nil {
+		return fmt.Errorf("failed to save cache configuration: %w", err)
+	}
+	return nil
+}
+
+// ttlLocked returns the effective TTL; the caller holds mu.
+func (p *CacheTTLPolicy) ttlLocked(resource string) time.Duration {
+	if ttl, ok := p.overrides[resource]; ok {
+		return ttl
+	}
+	return p.defaults[resource]
+}
+
+// loadOverrides restores the overrides saved by an earlier run.
+func (p *CacheTTLPolicy) loadOverrides() {
+	if p.store == nil {
+		return
+	}
+	data, err := p.store.Get(cacheTTLOverridesKey)
+	if err != nil || data == nil {
+		return
+	}
+	var saved map[string]string
+	if err := json.Unmarshal(data, &saved); err != nil {
+		utils.LogWarn("CacheTTLPolicy: Ignoring unreadable TTL overrides: %v", err)
+		return
+	}
+	for resource, value := range saved {
+		ttl, err := time.ParseDuration(value)
+		if _, known := p.defaults[resource]; !known || err != nil || ttl <= 0 {
+			continue
+		}
+		p.overrides[resource] = ttl
+	}
+}
+
+// saveOverridesLocked persists the overrides; the caller holds mu.
+func (p *CacheTTLPolicy) saveOverridesLocked() error {
+	if p.store == nil {
+		return nil
+	}
+	saved := make(map[string]string, len(p.overrides))
+	for resource, ttl := range p.overrides {
+		saved[resource] = ttl.String()
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	return p.store.SetPersistent(cacheTTLOverridesKey, data)
+}
+
+// parseCacheTTL parses a TTL setting, using fallback when it is unset or invalid.
+func parseCacheTTL(name, value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	ttl, err := time.ParseDuration(value)
+	if err != nil || ttl <= 0 {
+		utils.LogWarn("Invalid %s %q, using %s", name, value, fallback)
+		return fallback
+	}
+	return ttl
+}
@@ -0,0 +1,470 @@
+package persistence
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"teralux_app/domain/common/infrastructure/redis"
+	"teralux_app/domain/common/infrastructure/tracing"
+	"teralux_app/domain/common/utils"
+)
+
+const (
+	defaultRedisURL = "redis://localhost:6379/0"
+	// redisBatchSize bounds the keys of one SCAN page, MSET, UNLINK or pipeline.
+	redisBatchSize = 500
+)
+
+// RedisService implements CacheStore on a Redis server, so several instances behind a load balancer share
+// cache and device state. Keys and values are stored as plain Redis strings; persistent keys have no TTL.
+type RedisService struct {
+	client *redis.Client
+	ctx    context.Context
+}
+
+// NewRedisService connects to a Redis server and checks that it answers.
+//
+// param rawURL The server URL (redis:// or rediss://); empty uses redis://localhost:6379/0.
+// param poolSize The maximum number of connections as a string; empty or invalid uses 10.
+// return *RedisService A pointer to the initialized service.
+// return error An error if the URL is invalid or the server does not answer.
+func NewRedisService(rawURL, poolSize string) (*RedisService, error) {
+	if rawURL == "" {
+		rawURL = defaultRedisURL
+	}
+	size := 0
+	if poolSize != "" {
+		parsed, err := strconv.Atoi(poolSize)
+		if err != nil || parsed < 1 {
+			utils.LogWarn("Invalid REDIS_POOL_SIZE %q, using the default", poolSize)
+		} else {
+			size = parsed
+		}
+	}
+
+	client, err := redis.NewClient(redis.Options{URL: rawURL, PoolSize: size})
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := client.Do(ctx, "PING"); err != nil {
+		client.Close()
+		return nil, fmt.Errorf("failed to reach redis: %w", err)
+	}
+
+	utils.LogInfo("RedisService: Connected to %s", client.Address())
+	return &RedisService{client: client}, nil
+}
+
+// Close closes the connections to the server.
+//
+// return error Always nil.
+func (s *RedisService) Close() error {
+	return s.client.Close()
+}
+
+// WithContext returns a view of the service whose commands are traced as children of the span in ctx and
+// bounded by its deadline. It shares the connections with the service.
+//
+// param ctx The request context.
+// return CacheStore The traced view.
+func (s *RedisService) WithContext(ctx context.Context) CacheStore {
+	clone := *s
+	clone.ctx = ctx
+	return &clone
+}
+
+// commandContext returns the context bounding commands.
+func (s *RedisService) commandContext() context.Context {
+	if s.ctx == nil {
+		return context.Background()
+	}
+	return s.ctx
+}
+
+// startSpan starts the span of an operation when the service is bound to a request context.
+func (s *RedisService) startSpan(operation, key string) *tracing.Span {
+	if s.ctx == nil {
+		return nil
+	}
+	_, span := tracing.Start(s.ctx, "redis."+operation)
+	span.SetAttribute("db.system", "redis")
+	span.SetAttribute("db.operation", operation)
+	span.SetAttribute("db.key", key)
+	return span
+}
+
+// Set stores a key-value pair that expires after ttl.
+//
+// param key The unique identifier for the data.
+// param value The byte array data to store.
+// param ttl The duration after which the key expires.
+// return error An error if the command fails.
+func (s *RedisService) Set(key string, value []byte, ttl time.Duration) error {
+	span := s.startSpan("set", key)
+	defer span.End()
+
+	if _, err := s.client.Do(s.commandContext(), "SET", key, value, "PX", ttlMillis(ttl)); err != nil {
+		utils.LogError("RedisService: failed to set key %s with ttl %v: %v", key, ttl, err)
+		return err
+	}
+	return nil
+}
+
+// SetIfNotExists atomically stores a key with a TTL only if the key does not exist yet.
+//
+// param key The unique identifier for the data.
+// param value The byte array data to store.
+// param ttl The duration after which the key expires.
+// return bool True if the key was stored, false if it already existed.
+// return error An error if the command fails.
+func (s *RedisService) SetIfNotExists(key string, value []byte, ttl time.Duration) (bool, error) {
+	span := s.startSpan("set_if_not_exists", key)
+	defer span.End()
+
+	reply, err := s.client.Do(s.commandContext(), "SET", key, value, "NX", "PX", ttlMillis(ttl))
+	if err != nil {
+		utils.LogError("RedisService: failed to set-if-not-exists key %s: %v", key, err)
+		return false, err
+	}
+	return reply != nil, nil
+}
+
+// Get retrieves the value associated with the given key.
+//
+// param key The unique identifier to search for.
+// return []byte The value stored under the key, or nil if the key does not exist.
+// return error An error if the command fails.
+func (s *RedisService) Get(key string) ([]byte, error) {
+	span := s.startSpan("get", key)
+	defer span.End()
+
+	reply, err := s.client.Do(s.commandContext(), "GET", key)
+	if err != nil {
+		utils.LogError("RedisService: failed to get key %s: %v", key, err)
+		return nil, err
+	}
+	value, _ := reply.([]byte)
+	return value, nil
+}
+
+// Delete removes a key and its value.
+//
+// param key The unique identifier to remove.
+// return error An error if the command fails.
+func (s *RedisService) Delete(key string) error {
+	span := s.startSpan("delete", key)
+	defer span.End()
+
+	if _, err := s.client.Do(s.commandContext(), "DEL", key); err != nil {
+		utils.LogError("RedisService: failed to delete key %s: %v", key, err)
+		return err
+	}
+	return nil
+}
+
+// ClearWithPrefix removes all keys that start with the specified prefix.
+//
+// param prefix The string pattern to match at the beginning of keys.
+// return error An error if scanning or removing fails.
+func (s *RedisService) ClearWithPrefix(prefix string) error {
+	span := s.startSpan("clear_prefix", prefix)
+	defer span.End()
+
+	keys, err := s.scan(prefix)
+	if err != nil {
+		utils.LogError("RedisService: failed to clear prefix %s: %v", prefix, err)
+		return err
+	}
+	return s.unlink(keys)
+}
+
+// SetPersistent stores a key-value pair WITHOUT a TTL, removing a TTL the key had.
+//
+// param key The unique identifier for the data.
+// param value The byte array data to store.
+// return error An error if the command fails.
+func (s *RedisService) SetPersistent(key string, value []byte) error {
+	span := s.startSpan("set", key)
+	defer span.End()
+
+	if _, err := s.client.Do(s.commandContext(), "SET", key, value); err != nil {
+		utils.LogError("RedisService: failed to set persistent key %s: %v", key, err)
+		return err
+	}
+	utils.LogDebug("RedisService: Set persistent key '%s' (no TTL)", key)
+	return nil
+}
+
+// GetAllKeysWithPrefix retrieves all keys that start with the specified prefix, in key order.
+//
+// param prefix The string pattern to match at the beginning of keys.
+// return []string A slice of all matching keys.
+// return error An error if scanning fails.
+func (s *RedisService) GetAllKeysWithPrefix(prefix string) ([]string, error) {
+	span := s.startSpan("list_keys", prefix)
+	defer span.End()
+
+	keys, err := s.scan(prefix)
+	if err != nil {
+		utils.LogError("RedisService: failed to get keys with prefix %s: %v", prefix, err)
+		return nil, err
+	}
+	utils.LogDebug("RedisService: Found %d keys with prefix '%s'", len(keys), prefix)
+	return keys, nil
+}
+
+// FlushAll removes all CACHE data (keys with "cache:" prefix), preserving persistent data.
+//
+// return error An error if scanning or removing fails.
+func (s *RedisService) FlushAll() error {
+	if err := s.ClearWithPrefix("cache:"); err != nil {
+		utils.LogError("RedisService: failed to flush cache: %v", err)
+		return err
+	}
+	utils.LogInfo("RedisService: Flushed all cache data (preserved persistent data)")
+	return nil
+}
+
+// ListKeys returns metadata of the keys starting with the specified prefix, in key order.
+//
+// param prefix The string pattern to match at the beginning of keys; empty lists all keys.
+// param limit The maximum number of keys returned.
+// return []KeyInfo The matching keys, at most limit.
+// return bool True if more keys match than were returned.
+// return error An error if scanning fails.
+func (s *RedisService) ListKeys(prefix string, limit int) ([]KeyInfo, bool, error) {
+	keys, err := s.scan(prefix)
+	if err != nil {
+		utils.LogError("RedisService: failed to list keys with prefix %s: %v", prefix, err)
+		return nil, false, err
+	}
+	truncated := len(keys) > limit
+	if truncated {
+		keys = keys[:limit]
+	}
+
+	now := time.Now()
+	infos := make([]KeyInfo, 0, len(keys))
+	for start := 0; start < len(keys); start += redisBatchSize {
+		batch := keys[start:min(start+redisBatchSize, len(keys))]
+		commands := make([][]interface{}, 0, 2*len(batch))
+		for _, key := range batch {
+			commands = append(commands, []interface{}{"STRLEN", key}, []interface{}{"PTTL", key})
+		}
+		replies, err := s.client.Pipeline(s.commandContext(), commands)
+		if err != nil {
+			utils.LogError("RedisService: failed to list keys with prefix %s: %v", prefix, err)
+			return nil, false, err
+		}
+		for i, key := range batch {
+			pttl, _ := replies[2*i+1].(int64)
+			if pttl == -2 {
+				continue // removed since the scan
+			}
+			size, _ := replies[2*i].(int64)
+			infos = append(infos, KeyInfo{Key: key, Size: size, ExpiresAt: expiresAt(now, pttl)})
+		}
+	}
+	return infos, truncated, nil
+}
+
+// GetWithExpiry retrieves the value of a key together with its expiry.
+//
+// param key The unique identifier to search for.
+// return []byte The value stored under the key, or nil if the key does not exist.
+// return uint64 The expiry as Unix seconds; 0 means the key never expires.
+// return error An error if the commands fail.
+func (s *RedisService) GetWithExpiry(key string) ([]byte, uint64, error) {
+	span := s.startSpan("get", key)
+	defer span.End()
+
+	now := time.Now()
+	replies, err := s.client.Pipeline(s.commandContext(), [][]interface{}{{"GET", key}, {"PTTL", key}})
+	if err == nil {
+		if replyErr, ok := replies[0].(redis.Error); ok {
+			err = replyErr
+		}
+	}
+	if err != nil {
+		utils.LogError("RedisService: failed to get key %s: %v", key, err)
+		return nil, 0, err
+	}
+	value, _ := replies[0].([]byte)
+	if value == nil {
+		return nil, 0, nil
+	}
+	pttl, _ := replies[1].(int64)
+	return value, expiresAt(now, pttl), nil
+}
+
+// ListPersistent returns every entry stored without a TTL, in key order. Entries with a TTL (cache data,
+// sessions, cooldowns) are transient and left out, as are keys starting with one of the excluded prefixes.
+//
+// param excludePrefixes Key prefixes that are not returned.
+// return []KeyValue The persistent entries.
+// return error An error if scanning or reading fails.
+func (s *RedisService) ListPersistent(excludePrefixes []string) ([]KeyValue, error) {
+	span := s.startSpan("list_persistent", "")
+	defer span.End()
+
+	scanned, err := s.scan("")
+	if err != nil {
+		utils.LogError("RedisService: failed to list persistent entries: %v", err)
+		return nil, err
+	}
+	keys := scanned[:0]
+next:
+	for _, key := range scanned {
+		for _, prefix := range excludePrefixes {
+			if strings.HasPrefix(key, prefix) {
+				continue next
+			}
+		}
+		keys = append(keys, key)
+	}
+
+	var entries []KeyValue
+	for start := 0; start < len(keys); start += redisBatchSize {
+		batch := keys[start:min(start+redisBatchSize, len(keys))]
+		commands := make([][]interface{}, 0, 2*len(batch))
+		for _, key := range batch {
+			commands = append(commands, []interface{}{"PTTL", key}, []interface{}{"GET", key})
+		}
+		replies, err := s.client.Pipeline(s.commandContext(), commands)
+		if err != nil {
+			utils.LogError("RedisService: failed to list persistent entries: %v", err)
+			return nil, err
+		}
+		for i, key := range batch {
+			if pttl, _ := replies[2*i].(int64); pttl != -1 {
+				continue
+			}
+			if value, ok := replies[2*i+1].([]byte); ok {
+				entries = append(entries, KeyValue{Key: key, Value: value})
+			}
+		}
+	}
+	return entries, nil
+}
+
+// SetPersistentBatch stores many key-value pairs without a TTL, in MSET commands of up to 500 keys.
+//
+// param entries The entries to store.
+// return error An error if a command fails; entries may then be partially written.
+func (s *RedisService) SetPersistentBatch(entries []KeyValue) error {
+	span := s.startSpan("set_batch", "")
+	defer span.End()
+
+	for start := 0; start < len(entries); start += redisBatchSize {
+		batch := entries[start:min(start+redisBatchSize, len(entries))]
+		args := make([]interface{}, 0, 1+2*len(batch))
+		args = append(args, "MSET")
+		for _, entry := range batch {
+			args = append(args, entry.Key, entry.Value)
+		}
+		if _, err := s.client.Do(s.commandContext(), args...); err != nil {
+			utils.LogError("RedisService: failed to write %d entries: %v", len(entries), err)
+			return err
+		}
+	}
+	utils.LogDebug("RedisService: Set %d persistent keys (no TTL)", len(entries))
+	return nil
+}
+
+// DeleteBatch removes many keys, in UNLINK commands of up to 500 keys.
+//
+// param keys The keys to remove.
+// return error An error if a command fails; keys may then be partially removed.
+func (s *RedisService) DeleteBatch(keys []string) error {
+	span := s.startSpan("delete_batch", "")
+	defer span.End()
+
+	if err := s.unlink(keys); err != nil {
+		utils.LogError("RedisService: failed to delete %d keys: %v", len(keys), err)
+		return err
+	}
+	utils.LogDebug("RedisService: Deleted %d keys", len(keys))
+	return nil
+}
+
+// scan returns the keys starting with prefix, in key order.
+func (s *RedisService) scan(prefix string) ([]string, error) {
+	pattern := escapeGlob(prefix) + "*"
+	seen := make(map[string]bool)
+	cursor := "0"
+	for {
+		reply, err := s.client.Do(s.commandContext(), "SCAN", cursor, "MATCH", pattern, "COUNT", redisBatchSize)
+		if err != nil {
+			return nil, err
+		}
+		page, ok := reply.([]interface{})
+		if !ok || len(page) != 2 {
+			return nil, fmt.Errorf("unexpected SCAN reply %T", reply)
+		}
+		next, _ := page[0].([]byte)
+		keys, _ := page[1].([]interface{})
+		for _, key := range keys {
+			if name, ok := key.([]byte); ok {
+				seen[string(name)] = true
+			}
+		}
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			break
+		}
+	}
+
+	keys := make([]string, 0, len(seen))
+	for key := range seen {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// unlink removes keys in batches; the server frees their memory in the background.
+func (s *RedisService) unlink(keys []string) error {
+	for start := 0; start < len(keys); start += redisBatchSize {
+		batch := keys[start:min(start+redisBatchSize, len(keys))]
+		args := make([]interface{}, 0, 1+len(batch))
+		args = append(args, "UNLINK")
+		for _, key := range batch {
+			args = append(args, key)
+		}
+		if _, err := s.client.Do(s.commandContext(), args...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ttlMillis converts a TTL to the milliseconds of PX; Redis refuses TTLs below one millisecond.
+func ttlMillis(ttl time.Duration) int64 {
+	return max(ttl.Milliseconds(), 1)
+}
+
+// expiresAt converts a PTTL reply to Unix seconds; 0 means the key never expires.
+func expiresAt(now time.Time, pttl int64) uint64 {
+	if pttl < 0 {
+		return 0
+	}
+	return uint64(now.Add(time.Duration(pttl) * time.Millisecond).Unix())
+}
+
+// escapeGlob escapes the characters of a key prefix that have a meaning in SCAN MATCH patterns.
+func escapeGlob(prefix string) string {
+	var escaped strings.Builder
+	for _, r := range prefix {
+		if strings.ContainsRune(`*?[]\`, r) {
+			escaped.WriteByte('\\')
+		}
+		escaped.WriteRune(r)
+	}
+	return escaped.String()
+}
@@ -0,0 +1,367 @@
+package persistence
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"teralux_app/domain/common/utils"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	defaultReplicationInterval = 5 * time.Minute
+	defaultReplicationSpoolDir = "./tmp/replication"
+
+	// replicationStateKey holds the local replication progress. It is excluded from backups.
+	replicationStateKey = "replication:state"
+)
+
+// replicationExcludedPrefixes are key prefixes that are never replicated: cache data can be refetched,
+// and replication progress is specific to each instance.
+var replicationExcludedPrefixes = []string{"cache:", "replication:"}
+
+// backupNamePattern matches backup file names: "<since>-<until>.bak" with zero-padded versions,
+// so lexical order is also replay order.
+var backupNamePattern = regexp.MustCompile(`^\d{20}-\d{20}\.bak$`)
+
+// ReplicationState is the replication progress persisted by each instance.
+type ReplicationState struct {
+	ShippedVersion  uint64 `json:"shipped_version"`
+	ShippedAt       int64  `json:"shipped_at"`
+	RestoredVersion uint64 `json:"restored_version"`
+	RestoredAt      int64  `json:"restored_at"`
+	LastError       string `json:"last_error,omitempty"`
+}
+
+// ReplicationService keeps a warm standby in sync with this instance's persistent data.
+// The primary periodically writes incremental Badger backups (cache data excluded) and ships them to
+// REPLICATION_TARGET: either a peer instance ("http(s)://") that spools them, or a directory ("file://")
+// such as mounted object storage. A standby started with REPLICATION_RESTORE_ON_START replays the spooled
+// backups before serving, so device states, scenes and schedules survive a failover.
+type ReplicationService struct {
+	db        *BadgerService
+	client    *http.Client
+	target    string
+	spoolDir  string
+	interval  time.Duration
+	mu        sync.Mutex
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewReplicationService initializes a new ReplicationService from the replication configuration.
+//
+// param db The BadgerService holding the data to replicate (nil when persistence is unavailable or the cache backend is Redis).
+// return *ReplicationService A pointer to the initialized service.
+func NewReplicationService(db *BadgerService) *ReplicationService {
+	config := utils.GetConfig()
+
+	interval, err := time.ParseDuration(config.ReplicationInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultReplicationInterval
+	}
+	spoolDir := config.ReplicationSpoolDir
+	if spoolDir == "" {
+		spoolDir = defaultReplicationSpoolDir
+	}
+
+	return &ReplicationService{
+		db:       db,
+		client:   httpclient.New(5 * time.Minute),
+		target:   strings.TrimRight(config.ReplicationTarget, "/"),
+		spoolDir: spoolDir,
+		interval: interval,
+	}
+}
+
+// Start ships incremental backups in the background at REPLICATION_INTERVAL.
+// It does nothing unless REPLICATION_TARGET is configured.
+func (s *ReplicationService) Start() {
+	if s.target == "" || s.db == nil {
+		return
+	}
+
+	s.startOnce.Do(func() {
+		s.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(s.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if err := s.ShipNow(context.Background()); err != nil {
+						utils.LogError("ReplicationService: Failed to ship backup: %v", err)
+					}
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("ReplicationService: Shipping backups every %s", s.interval)
+	})
+}
+
+// Stop ends the shipping loop and waits for a shipment in progress to finish.
+func (s *ReplicationService) Stop() {
+	s.workers.Stop()
+}
+
+// ShipNow writes a backup of everything changed since the last shipped version and sends it to the target.
+// Nothing is sent when no persistent data changed.
+//
+// param ctx The context bounding the upload.
+// return error An error if the backup or upload fails; the next attempt resends the same range.
+func (s *ReplicationService) ShipNow(ctx context.Context) error {
+	if s.target == "" {
+		return fmt.Errorf("replication target not configured")
+	}
+	if s.db == nil {
+		return fmt.Errorf("persistence not initialized")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	state := s.loadState()
+	since := uint64(0)
+	if state.ShippedVersion > 0 {
+		since = state.ShippedVersion + 1
+	}
+
+	var buf bytes.Buffer
+	version, err := s.db.Backup(&buf, since, replicationExcludedPrefixes)
+	if err != nil {
+		return s.fail(state, fmt.Errorf("failed to create backup: %w", err))
+	}
+	if version < since || buf.Len() == 0 {
+		utils.LogDebug("ReplicationService: No changes since version %d", since)
+		return nil
+	}
+
+	name := backupName(since, version)
+	if err := s.ship(ctx, name, buf.Bytes()); err != nil {
+		return s.fail(state, err)
+	}
+
+	state.ShippedVersion = version
+	state.ShippedAt = time.Now().Unix()
+	state.LastError = ""
+	s.saveState(state)
+	utils.LogInfo("ReplicationService: Shipped %s (%d bytes)", name, buf.Len())
+	return nil
+}
+
+// ReceiveBackup stores a backup sent by the primary in the spool directory, to be replayed on takeover.
+//
+// param name The backup file name; it must match the "<since>-<until>.bak" pattern.
+// param r The reader providing the backup.
+// return error A bad request error if the name is invalid, or an error if writing fails.
+func (s *ReplicationService) ReceiveBackup(name string, r io.Reader) error {
+	if !backupNamePattern.MatchString(name) {
+		return tuya_errors.BadRequest("invalid backup name %q", name)
+	}
+	if err := writeFileAtomic(s.spoolDir, name, r); err != nil {
+		return err
+	}
+	utils.LogInfo("ReplicationService: Received backup %s", name)
+	return nil
+}
+
+// RestoreOnStart replays spooled backups that have not been applied yet.
+// It runs only when REPLICATION_RESTORE_ON_START is enabled and must be called before any other use of the database.
+//
+// return int The number of backups applied.
+// return error An error if a backup cannot be read or loaded; later backups are not applied.
+func (s *ReplicationService) RestoreOnStart() (int, error) {
+	if !utils.GetConfig().ReplicationRestoreOnStart || s.db == nil {
+		return 0, nil
+	}
+
+	names, err := s.spooledBackups()
+	if err != nil {
+		return 0, err
+	}
+
+	state := s.loadState()
+	applied := 0
+	for _, name := range names {
+		since, until := backupRange(name)
+		if until <= state.RestoredVersion {
+			continue
+		}
+		if state.RestoredVersion > 0 && since > state.RestoredVersion+1 {
+			return applied, fmt.Errorf("backup %s does not follow restored version %d", name, state.RestoredVersion)
+		}
+
+		if err := s.loadFile(filepath.Join(s.spoolDir, name)); err != nil {
+			return applied, fmt.Errorf("failed to restore %s: %w", name, err)
+		}
+		state.RestoredVersion = until
+		state.RestoredAt = time.Now().Unix()
+		s.saveState(state)
+		applied++
+	}
+
+	if applied > 0 {
+		utils.LogInfo("ReplicationService: Restored %d backups up to version %d", applied, state.RestoredVersion)
+	}
+	return applied, nil
+}
+
+// Status returns the replication progress of this instance.
+//
+// return ReplicationState The persisted progress.
+// return bool True if this instance ships backups.
+func (s *ReplicationService) Status() (ReplicationState, bool) {
+	if s.db == nil {
+		return ReplicationState{}, false
+	}
+	return *s.loadState(), s.target != ""
+}
+
+// ship sends a backup to the configured target.
+func (s *ReplicationService) ship(ctx context.Context, name string, data []byte) error {
+	target, err := url.Parse(s.target)
+	if err != nil {
+		return fmt.Errorf("invalid replication target: %w", err)
+	}
+
+	switch target.Scheme {
+	case "file":
+		return writeFileAtomic(target.Path, name, bytes.NewReader(data))
+	case "http", "https":
+		endpoint := fmt.Sprintf("%s/api/admin/replication/backups?name=%s", s.target, url.QueryEscape(name))
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(data))
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		req.Header.Set("Content-Type", "application/octet-stream")
+		req.Header.Set("X-API-KEY", utils.GetConfig().ApiKey)
+
+		resp, err := s.client.Do(req)
+		if err != nil {
+			return fmt.Errorf("failed to send backup: %w", err)
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+			return fmt.Errorf("peer returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported replication target scheme %q", target.Scheme)
+	}
+}
+
+// spooledBackups lists the backup files in the spool directory in replay order.
+func (s *ReplicationService) spooledBackups() ([]string, error) {
+	entries, err := os.ReadDir(s.spoolDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read spool directory: %w", err)
+	}
+
+	var names []string
+	for _, entry := range entries {
+		if !entry.IsDir() && backupNamePattern.MatchString(entry.Name()) {
+			names = append(names, entry.Name())
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// loadFile applies one backup file.
+func (s *ReplicationService) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	return s.db.Load(file)
+}
+
+// fail records a shipping error in the state and returns it.
+func (s *ReplicationService) fail(state *ReplicationState, err error) error {
+	state.LastError = err.Error()
+	s.saveState(state)
+	return err
+}
+
+// loadState reads the persisted replication progress, returning an empty state when none exists.
+func (s *ReplicationService) loadState() *ReplicationState {
+	state := &ReplicationState{}
+	data, err := s.db.Get(replicationStateKey)
+	if err != nil || data == nil {
+		return state
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		utils.LogWarn("ReplicationService: Ignoring malformed state: %v", err)
+		return &ReplicationState{}
+	}
+	return state
+}
+
+// saveState persists the replication progress.
+func (s *ReplicationService) saveState(state *ReplicationState) {
+	data, err := json.Marshal(state)
+	if err != nil {
+		utils.LogWarn("ReplicationService: Failed to encode state: %v", err)
+		return
+	}
+	if err := s.db.SetPersistent(replicationStateKey, data); err != nil {
+		utils.LogWarn("ReplicationService: Failed to save state: %v", err)
+	}
+}
+
+// backupName builds the file name of a backup covering versions since..until.
+func backupName(since, until uint64) string {
+	return fmt.Sprintf("%020d-%020d.bak", since, until)
+}
+
+// backupRange parses the version range from a backup file name.
+func backupRange(name string) (uint64, uint64) {
+	parts := strings.SplitN(strings.TrimSuffix(name, ".bak"), "-", 2)
+	since, _ := strconv.ParseUint(parts[0], 10, 64)
+	until, _ := strconv.ParseUint(parts[1], 10, 64)
+	return since, until
+}
+
+// writeFileAtomic writes a file through a temporary file and rename, so readers never see partial backups.
+func writeFileAtomic(dir, name string, r io.Reader) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(dir, name+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(dir, name)); err != nil {
+		return fmt.Errorf("failed to store backup: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,379 @@
+package redis
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultPoolSize = 10
+	defaultTimeout  = 5 * time.Second
+	maxBulkLength   = 512 << 20
+)
+
+// ErrClosed is returned by commands on a closed client.
+var ErrClosed = errors.New("redis client closed")
+
+// Error is an error reply of the server, e.g. "WRONGTYPE Operation against a key holding the wrong kind of value".
+type Error string
+
+func (e Error) Error() string { return string(e) }
+
+// Options configures a client.
+type Options struct {
+	// URL is the server URL: redis://[[user]:password@]host[:port][/db], or rediss:// for TLS.
+	URL string
+	// PoolSize bounds the open connections (default 10).
+	PoolSize int
+	// Timeout bounds dialing and each command without a context deadline (default 5s).
+	Timeout time.Duration
+}
+
+// Client is a minimal RESP2 client with a connection pool: commands, pipelines and Lua scripts against a
+// single Redis server (Redis Cluster is not supported). Replies are decoded as string (simple strings),
+// int64 (integers), []byte (bulk strings), nil (null replies), []interface{} (arrays) and Error.
+type Client struct {
+	address  string
+	useTLS   bool
+	username string
+	password string
+	db       int
+	timeout  time.Duration
+
+	slots chan struct{}
+	idle  chan *conn
+
+	closeOnce sync.Once
+	closed    chan struct{}
+}
+
+// conn is a pooled connection.
+type conn struct {
+	netConn net.Conn
+	reader  *bufio.Reader
+	writer  *bufio.Writer
+}
+
+// NewClient parses the options. Connections are opened on demand.
+//
+// param opts The server and pool options.
+// return *Client A pointer to the client.
+// return error An error if the URL is invalid.
+func NewClient(opts Options) (*Client, error) {
+	parsed, err := url.Parse(opts.URL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	if parsed.Scheme != "redis" && parsed.Scheme != "rediss" {
+		return nil, fmt.Errorf("invalid redis URL scheme %q (supported: redis, rediss)", parsed.Scheme)
+	}
+	if parsed.Hostname() == "" {
+		return nil, fmt.Errorf("redis URL needs a host")
+	}
+
+	c := &Client{
+		address: parsed.Host,
+		useTLS:  parsed.Scheme == "rediss",
+		timeout: opts.Timeout,
+		closed:  make(chan struct{}),
+	}
+	if parsed.Port() == "" {
+		c.address = net.JoinHostPort(parsed.Hostname(), "6379")
+	}
+	if parsed.User != nil {
+		c.username = parsed.User.Username()
+		c.password, _ = parsed.User.Password()
+	}
+	if path := strings.Trim(parsed.Path, "/"); path != "" {
+		c.db, err = strconv.Atoi(path)
+		if err != nil || c.db < 0 {
+			return nil, fmt.Errorf("invalid redis database %q", path)
+		}
+	}
+	if c.timeout <= 0 {
+		c.timeout = defaultTimeout
+	}
+	poolSize := opts.PoolSize
+	if poolSize <= 0 {
+		poolSize = defaultPoolSize
+	}
+	c.slots = make(chan struct{}, poolSize)
+	c.idle = make(chan *conn, poolSize)
+	return c, nil
+}
+
+// Address returns the host and port of the server.
+//
+// return string The address.
+func (c *Client) Address() string {
+	return c.address
+}
+
+// Do sends one command and returns its reply. Error replies are returned as Error.
+//
+// param ctx The context bounding the command.
+// param args The command and its arguments (strings, []byte or integers).
+// return interface{} The reply.
+// return error An Error reply, ErrClosed, or a connection error.
+func (c *Client) Do(ctx context.Context, args ...interface{}) (interface{}, error) {
+	replies, err := c.Pipeline(ctx, [][]interface{}{args})
+	if err != nil {
+		return nil, err
+	}
+	if replyErr, ok := replies[0].(Error); ok {
+		return nil, replyErr
+	}
+	return replies[0], nil
+}
+
+// Pipeline sends several commands in one round trip and returns their replies in order.
+// Error replies of single commands are returned as Error values in the slice.
+//
+// param ctx The context bounding the pipeline.
+// param commands The commands with their arguments.
+// return []interface{} One reply per command.
+// return error ErrClosed or a connection error.
+func (c *Client) Pipeline(ctx context.Context, commands [][]interface{}) ([]interface{}, error) {
+	cn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(c.timeout)
+	}
+	_ = cn.netConn.SetDeadline(deadline)
+
+	replies, err := cn.roundTrip(commands)
+	c.put(cn, err != nil)
+	if err != nil {
+		return nil, fmt.Errorf("redis %s: %w", c.address, err)
+	}
+	return replies, nil
+}
+
+// Close closes the idle connections; connections in use are closed when they are returned.
+//
+// return error Always nil.
+func (c *Client) Close() error {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		for {
+			select {
+			case cn := <-c.idle:
+				cn.netConn.Close()
+			default:
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// get takes an idle connection or dials a new one once a pool slot is free.
+func (c *Client) get(ctx context.Context) (*conn, error) {
+	select {
+	case <-c.closed:
+		return nil, ErrClosed
+	default:
+	}
+	select {
+	case c.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, ErrClosed
+	}
+
+	select {
+	case cn := <-c.idle:
+		return cn, nil
+	default:
+	}
+	cn, err := c.dial(ctx)
+	if err != nil {
+		<-c.slots
+		return nil, err
+	}
+	return cn, nil
+}
+
+// put returns a connection to the pool, closing it when it is broken or the client is closed.
+func (c *Client) put(cn *conn, broken bool) {
+	defer func() { <-c.slots }()
+	select {
+	case <-c.closed:
+		broken = true
+	default:
+	}
+	if broken {
+		cn.netConn.Close()
+		return
+	}
+	select {
+	case c.idle <- cn:
+	default:
+		cn.netConn.Close()
+	}
+}
+
+// dial opens a connection, authenticates and selects the database.
+func (c *Client) dial(ctx context.Context) (*conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	var netConn net.Conn
+	var err error
+	if c.useTLS {
+		host, _, _ := net.SplitHostPort(c.address)
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: host, MinVersion: tls.VersionTLS12}}
+		netConn, err = dialer.DialContext(ctx, "tcp", c.address)
+	} else {
+		var dialer net.Dialer
+		netConn, err = dialer.DialContext(ctx, "tcp", c.address)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to redis %s: %w", c.address, err)
+	}
+
+	cn := &conn{netConn: netConn, reader: bufio.NewReader(netConn), writer: bufio.NewWriter(netConn)}
+	var setup [][]interface{}
+	if c.password != "" {
+		if c.username != "" {
+			setup = append(setup, []interface{}{"AUTH", c.username, c.password})
+		} else {
+			setup = append(setup, []interface{}{"AUTH", c.password})
+		}
+	}
+	if c.db != 0 {
+		setup = append(setup, []interface{}{"SELECT", c.db})
+	}
+	if len(setup) > 0 {
+		deadline, _ := ctx.Deadline()
+		_ = netConn.SetDeadline(deadline)
+		replies, err := cn.roundTrip(setup)
+		if err == nil {
+			for _, reply := range replies {
+				if replyErr, ok := reply.(Error); ok {
+					err = replyErr
+					break
+				}
+			}
+		}
+		if err != nil {
+			netConn.Close()
+			return nil, fmt.Errorf("redis %s refused the connection: %w", c.address, err)
+		}
+	}
+	return cn, nil
+}
+
+// roundTrip writes the commands and reads one reply per command.
+func (cn *conn) roundTrip(commands [][]interface{}) ([]interface{}, error) {
+	for _, args := range commands {
+		if err := cn.writeCommand(args); err != nil {
+			return nil, err
+		}
+	}
+	if err := cn.writer.Flush(); err != nil {
+		return nil, err
+	}
+
+	replies := make([]interface{}, len(commands))
+	for i := range commands {
+		reply, err := cn.readReply()
+		if err != nil {
+			return nil, err
+		}
+		replies[i] = reply
+	}
+	return replies, nil
+}
+
+// writeCommand encodes a command as a RESP array of bulk strings.
+func (cn *conn) writeCommand(args []interface{}) error {
+	cn.writer.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		var value []byte
+		switch v := arg.(type) {
+		case string:
+			value = []byte(v)
+		case []byte:
+			value = v
+		case int:
+			value = []byte(strconv.Itoa(v))
+		case int64:
+			value = []byte(strconv.FormatInt(v, 10))
+		default:
+			return fmt.Errorf("unsupported argument type %T", arg)
+		}
+		cn.writer.WriteString("$" + strconv.Itoa(len(value)) + "\r\n")
+		cn.writer.Write(value)
+		if _, err := cn.writer.WriteString("\r\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// readReply decodes one RESP2 reply.
+func (cn *conn) readReply() (interface{}, error) {
+	line, err := cn.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	if len(line) < 3 || line[len(line)-2] != '\r' {
+		return nil, fmt.Errorf("malformed reply %q", line)
+	}
+	kind, payload := line[0], line[1:len(line)-2]
+
+	switch kind {
+	case '+':
+		return payload, nil
+	case '-':
+		return Error(payload), nil
+	case ':':
+		return strconv.ParseInt(payload, 10, 64)
+	case '$':
+		length, err := strconv.Atoi(payload)
+		if err != nil || length > maxBulkLength {
+			return nil, fmt.Errorf("malformed bulk length %q", payload)
+		}
+		if length < 0 {
+			return nil, nil
+		}
+		value := make([]byte, length+2)
+		if _, err := io.ReadFull(cn.reader, value); err != nil {
+			return nil, err
+		}
+		return value[:length], nil
+	case '*':
+		count, err := strconv.Atoi(payload)
+		if err != nil {
+			return nil, fmt.Errorf("malformed array length %q", payload)
+		}
+		if count < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, count)
+		for i := range items {
+			if items[i], err = cn.readReply(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("unknown reply type %q", kind)
+	}
+}
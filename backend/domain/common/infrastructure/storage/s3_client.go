@@ -0,0 +1,221 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"time"
+)
+
+// S3Client writes objects to S3-compatible storage (AWS S3, MinIO, R2, ...) using path-style
+// requests signed with AWS Signature Version 4.
+type S3Client struct {
+	client    *http.Client
+	endpoint  string
+	region    string
+	bucket    string
+	accessKey string
+	secretKey string
+}
+
+// LifecycleRule is a bucket lifecycle rule applied to objects under a prefix.
+type LifecycleRule struct {
+	ID             string
+	Prefix         string
+	TransitionDays int
+	StorageClass   string
+	ExpirationDays int
+}
+
+// NewS3Client initializes a new S3Client.
+//
+// param endpoint The base URL of the storage service (e.g., https://s3.eu-west-1.amazonaws.com).
+// param region The signing region (e.g., eu-west-1; MinIO accepts us-east-1).
+// param bucket The bucket objects are written to.
+// param accessKey The access key ID.
+// param secretKey The secret access key.
+// return *S3Client A pointer to the initialized client.
+func NewS3Client(endpoint, region, bucket, accessKey, secretKey string) *S3Client {
+	if region == "" {
+		region = "us-east-1"
+	}
+	return &S3Client{
+		client:    httpclient.New(5 * time.Minute),
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		region:    region,
+		bucket:    bucket,
+		accessKey: accessKey,
+		secretKey: secretKey,
+	}
+}
+
+// PutObject uploads an object.
+//
+// param ctx The context bounding the upload.
+// param key The object key.
+// param contentType The MIME type stored with the object.
+// param body The object content.
+// return error An error if the request fails or the service rejects it.
+func (c *S3Client) PutObject(ctx context.Context, key, contentType string, body []byte) error {
+	headers := map[string]string{"Content-Type": contentType}
+	return c.do(ctx, http.MethodPut, "/"+c.bucket+"/"+key, "", headers, body)
+}
+
+// PutBucketLifecycle replaces the bucket lifecycle configuration.
+//
+// param ctx The context bounding the request.
+// param rules The rules to apply.
+// return error An error if the request fails or the service rejects it.
+func (c *S3Client) PutBucketLifecycle(ctx context.Context, rules []LifecycleRule) error {
+	type transition struct {
+		Days         int    `xml:"Days"`
+		StorageClass string `xml:"StorageClass"`
+	}
+	type expiration struct {
+		Days int `xml:"Days"`
+	}
+	type rule struct {
+		ID         string      `xml:"ID"`
+		Prefix     string      `xml:"Filter>Prefix"`
+		Status     string      `xml:"Status"`
+		Transition *transition `xml:"Transition,omitempty"`
+		Expiration *expiration `xml:"Expiration,omitempty"`
+	}
+	type configuration struct {
+		XMLName xml.Name `xml:"LifecycleConfiguration"`
+		Rules   []rule   `xml:"Rule"`
+	}
+
+	config := configuration{}
+	for _, r := range rules {
+		entry := rule{ID: r.ID, Prefix: r.Prefix, Status: "Enabled"}
+		if r.TransitionDays > 0 && r.StorageClass != "" {
+			entry.Transition = &transition{Days: r.TransitionDays, StorageClass: r.StorageClass}
+		}
+		if r.ExpirationDays > 0 {
+			entry.Expiration = &expiration{Days: r.ExpirationDays}
+		}
+		config.Rules = append(config.Rules, entry)
+	}
+
+	body, err := xml.Marshal(config)
+	if err != nil {
+		return fmt.Errorf("failed to encode lifecycle configuration: %w", err)
+	}
+	sum := md5.Sum(body)
+	headers := map[string]string{
+		"Content-Type": "application/xml",
+		"Content-MD5":  base64.StdEncoding.EncodeToString(sum[:]),
+	}
+	return c.do(ctx, http.MethodPut, "/"+c.bucket, "lifecycle=", headers, body)
+}
+
+// do signs and sends a request.
+func (c *S3Client) do(ctx context.Context, method, path, query string, headers map[string]string, body []byte) error {
+	endpoint, err := url.Parse(c.endpoint)
+	if err != nil {
+		return fmt.Errorf("invalid storage endpoint: %w", err)
+	}
+
+	canonicalPath := encodePath(path)
+	fullURL := c.endpoint + canonicalPath
+	if query != "" {
+		fullURL += "?" + query
+	}
+	req, err := http.NewRequestWithContext(ctx, method, fullURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	c.sign(req, endpoint.Host, canonicalPath, query, body, time.Now().UTC())
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("storage returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return nil
+}
+
+// sign adds the AWS Signature Version 4 headers to a request.
+func (c *S3Client) sign(req *http.Request, host, canonicalPath, query string, body []byte, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("Host", host)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	var names []string
+	for name := range req.Header {
+		names = append(names, strings.ToLower(name))
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + strings.TrimSpace(req.Header.Get(name)) + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalPath,
+		query,
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/s3/aws4_request", date, c.region)
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+c.secretKey), date)
+	key = hmacSHA256(key, c.region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		c.accessKey, scope, signedHeaders, signature))
+}
+
+// encodePath URI-encodes each path segment as required by Signature Version 4.
+func encodePath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		segments[i] = strings.ReplaceAll(url.PathEscape(segment), "+", "%2B")
+	}
+	return strings.Join(segments, "/")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,268 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"teralux_app/domain/common/utils"
+	"time"
+)
+
+const (
+	defaultServiceName  = "teralux-backend"
+	exportInterval      = 5 * time.Second
+	exportBatchSize     = 512
+	exportQueueSize     = 4096
+	exportTimeout       = 10 * time.Second
+	instrumentationName = "teralux_app"
+)
+
+var (
+	exporterMu     sync.RWMutex
+	activeExporter *exporter
+)
+
+// exporter batches ended spans and posts them to an OTLP/HTTP collector in the JSON encoding.
+type exporter struct {
+	url         string
+	headers     map[string]string
+	serviceName string
+	ratio       float64
+	client      *http.Client
+
+	queue   chan *Span
+	dropped atomic.Int64
+	stop    chan struct{}
+	done    chan struct{}
+}
+
+// Init enables tracing when OTEL_EXPORTER_OTLP_ENDPOINT is set: spans are sampled per
+// OTEL_TRACES_SAMPLER_ARG (a ratio, default 1), named after OTEL_SERVICE_NAME and posted to
+// <endpoint>/v1/traces with the OTEL_EXPORTER_OTLP_HEADERS. Without an endpoint tracing stays disabled
+// and every span is nil. A previous exporter is stopped first.
+func Init() {
+	Stop()
+	config := utils.GetConfig()
+	if config.OTelEndpoint == "" {
+		return
+	}
+
+	exp := &exporter{
+		url:         strings.TrimSuffix(config.OTelEndpoint, "/") + "/v1/traces",
+		headers:     parseHeaders(config.OTelHeaders),
+		serviceName: config.OTelServiceName,
+		ratio:       1,
+		client:      &http.Client{Timeout: exportTimeout},
+		queue:       make(chan *Span, exportQueueSize),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+	if exp.serviceName == "" {
+		exp.serviceName = defaultServiceName
+	}
+	if config.OTelSamplerArg != "" {
+		ratio, err := strconv.ParseFloat(config.OTelSamplerArg, 64)
+		if err != nil || ratio < 0 || ratio > 1 {
+			utils.LogWarn("Invalid OTEL_TRACES_SAMPLER_ARG %q, sampling every trace", config.OTelSamplerArg)
+		} else {
+			exp.ratio = ratio
+		}
+	}
+
+	exporterMu.Lock()
+	activeExporter = exp
+	exporterMu.Unlock()
+	go exp.run()
+	utils.LogInfo("Tracing: exporting spans of %s to %s (sample ratio %g)", exp.serviceName, exp.url, exp.ratio)
+}
+
+// Stop disables tracing and exports the queued spans.
+func Stop() {
+	exporterMu.Lock()
+	exp := activeExporter
+	activeExporter = nil
+	exporterMu.Unlock()
+
+	if exp != nil {
+		close(exp.stop)
+		<-exp.done
+	}
+}
+
+// Enabled reports whether spans are exported.
+//
+// return bool True once Init found an endpoint.
+func Enabled() bool {
+	return currentExporter() != nil
+}
+
+// currentExporter returns the active exporter, or nil while tracing is disabled.
+func currentExporter() *exporter {
+	exporterMu.RLock()
+	defer exporterMu.RUnlock()
+	return activeExporter
+}
+
+// sample decides whether a new trace is recorded.
+func (e *exporter) sample(traceID [16]byte) bool {
+	return e.ratio >= 1 || traceIDRatio(traceID) < e.ratio
+}
+
+// enqueue queues an ended span; spans are dropped while the queue is full so tracing never blocks requests.
+func (e *exporter) enqueue(span *Span) {
+	select {
+	case e.queue <- span:
+	default:
+		e.dropped.Add(1)
+	}
+}
+
+// run exports batches until Stop, then flushes what is left.
+func (e *exporter) run() {
+	defer close(e.done)
+	ticker := time.NewTicker(exportInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, exportBatchSize)
+	flush := func() {
+		if len(batch) > 0 {
+			e.export(batch)
+			batch = batch[:0]
+		}
+	}
+	for {
+		select {
+		case span := <-e.queue:
+			batch = append(batch, span)
+			if len(batch) >= exportBatchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		case <-e.stop:
+			for {
+				select {
+				case span := <-e.queue:
+					batch = append(batch, span)
+				default:
+					flush()
+					return
+				}
+			}
+		}
+	}
+}
+
+// export posts a batch of spans; failures are logged and the batch is discarded.
+func (e *exporter) export(spans []*Span) {
+	body, err := json.Marshal(e.encode(spans))
+	if err != nil {
+		utils.LogWarn("Tracing: failed to encode %d spans: %v", len(spans), err)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), exportTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url, bytes.NewReader(body))
+	if err != nil {
+		utils.LogWarn("Tracing: failed to create export request: %v", err)
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range e.headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		utils.LogWarn("Tracing: failed to export %d spans: %v", len(spans), err)
+		return
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		utils.LogWarn("Tracing: collector answered %d to %d spans", resp.StatusCode, len(spans))
+	}
+	if dropped := e.dropped.Swap(0); dropped > 0 {
+		utils.LogWarn("Tracing: dropped %d spans while the export queue was full", dropped)
+	}
+}
+
+// encode builds the OTLP/HTTP JSON request of a batch.
+func (e *exporter) encode(spans []*Span) map[string]interface{} {
+	encoded := make([]map[string]interface{}, 0, len(spans))
+	for _, span := range spans {
+		span.mu.Lock()
+		item := map[string]interface{}{
+			"traceId":           hex.EncodeToString(span.traceID[:]),
+			"spanId":            hex.EncodeToString(span.spanID[:]),
+			"name":              span.name,
+			"kind":              span.kind,
+			"startTimeUnixNano": strconv.FormatInt(span.start.UnixNano(), 10),
+			"endTimeUnixNano":   strconv.FormatInt(span.end.UnixNano(), 10),
+			"attributes":        encodeAttributes(span.attributes),
+		}
+		if span.parentID != [8]byte{} {
+			item["parentSpanId"] = hex.EncodeToString(span.parentID[:])
+		}
+		if span.status != statusUnset {
+			item["status"] = map[string]interface{}{"code": span.status, "message": span.message}
+		}
+		span.mu.Unlock()
+		encoded = append(encoded, item)
+	}
+
+	return map[string]interface{}{
+		"resourceSpans": []interface{}{map[string]interface{}{
+			"resource": map[string]interface{}{
+				"attributes": encodeAttributes(map[string]interface{}{"service.name": e.serviceName}),
+			},
+			"scopeSpans": []interface{}{map[string]interface{}{
+				"scope": map[string]interface{}{"name": instrumentationName},
+				"spans": encoded,
+			}},
+		}},
+	}
+}
+
+// encodeAttributes converts attributes into OTLP key/value pairs.
+func encodeAttributes(attributes map[string]interface{}) []interface{} {
+	encoded := make([]interface{}, 0, len(attributes))
+	for key, value := range attributes {
+		var encodedValue map[string]interface{}
+		switch v := value.(type) {
+		case string:
+			encodedValue = map[string]interface{}{"stringValue": v}
+		case bool:
+			encodedValue = map[string]interface{}{"boolValue": v}
+		case int:
+			encodedValue = map[string]interface{}{"intValue": strconv.Itoa(v)}
+		case int64:
+			encodedValue = map[string]interface{}{"intValue": strconv.FormatInt(v, 10)}
+		case float64:
+			encodedValue = map[string]interface{}{"doubleValue": v}
+		default:
+			encodedValue = map[string]interface{}{"stringValue": fmt.Sprint(v)}
+		}
+		encoded = append(encoded, map[string]interface{}{"key": key, "value": encodedValue})
+	}
+	return encoded
+}
+
+// parseHeaders parses OTEL_EXPORTER_OTLP_HEADERS ("key=value,key=value").
+func parseHeaders(raw string) map[string]string {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+		if found && key != "" {
+			headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+		}
+	}
+	return headers
+}
@@ -0,0 +1,229 @@
+package tracing
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Span kinds, numbered as in OTLP.
+const (
+	kindInternal = 1
+	kindServer   = 2
+	kindClient   = 3
+)
+
+// Status codes, numbered as in OTLP.
+const (
+	statusUnset = 0
+	statusError = 2
+)
+
+// Span is one timed operation of a trace. A nil *Span is valid and records nothing, so callers never check
+// whether tracing is enabled.
+type Span struct {
+	traceID  [16]byte
+	spanID   [8]byte
+	parentID [8]byte
+	sampled  bool
+	name     string
+	kind     int
+	start    time.Time
+
+	mu         sync.Mutex
+	end        time.Time
+	attributes map[string]interface{}
+	status     int
+	message    string
+}
+
+type spanKey struct{}
+
+// Start starts an internal span as a child of the span in ctx. Without a span in ctx (background work,
+// tracing disabled) nothing is traced and the returned span is nil.
+//
+// param ctx The context carrying the parent span.
+// param name The span name (e.g., "badger.get").
+// return context.Context The context carrying the new span.
+// return *Span The span; End it when the operation finishes.
+func Start(ctx context.Context, name string) (context.Context, *Span) {
+	return startChild(ctx, name, kindInternal)
+}
+
+// StartClient starts a span for an outgoing call as a child of the span in ctx, like Start.
+//
+// param ctx The context carrying the parent span.
+// param name The span name (e.g., "GET /v1.0/iot-03/devices/status").
+// return context.Context The context carrying the new span.
+// return *Span The span; End it when the call finishes.
+func StartClient(ctx context.Context, name string) (context.Context, *Span) {
+	return startChild(ctx, name, kindClient)
+}
+
+// StartServer starts the span of an incoming request. It continues the trace of a W3C traceparent header
+// when one is given, and otherwise starts a new trace sampled per OTEL_TRACES_SAMPLER_ARG.
+//
+// param ctx The request context.
+// param name The span name (e.g., "GET /api/tuya/devices").
+// param traceparent The traceparent header of the request ("" for none).
+// return context.Context The context carrying the span.
+// return *Span The span, or nil while tracing is disabled.
+func StartServer(ctx context.Context, name, traceparent string) (context.Context, *Span) {
+	exp := currentExporter()
+	if exp == nil {
+		return ctx, nil
+	}
+
+	span := &Span{name: name, kind: kindServer, start: time.Now()}
+	if traceID, parentID, sampled, ok := parseTraceParent(traceparent); ok {
+		span.traceID, span.parentID, span.sampled = traceID, parentID, sampled
+	} else {
+		_, _ = rand.Read(span.traceID[:])
+		span.sampled = exp.sample(span.traceID)
+	}
+	_, _ = rand.Read(span.spanID[:])
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// FromContext returns the span carried by ctx.
+//
+// param ctx The context.
+// return *Span The span, or nil.
+func FromContext(ctx context.Context) *Span {
+	span, _ := ctx.Value(spanKey{}).(*Span)
+	return span
+}
+
+// startChild starts a span under the span in ctx.
+func startChild(ctx context.Context, name string, kind int) (context.Context, *Span) {
+	parent := FromContext(ctx)
+	if parent == nil {
+		return ctx, nil
+	}
+	span := &Span{
+		traceID:  parent.traceID,
+		parentID: parent.spanID,
+		sampled:  parent.sampled,
+		name:     name,
+		kind:     kind,
+		start:    time.Now(),
+	}
+	_, _ = rand.Read(span.spanID[:])
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+// SetName renames the span, e.g. once the route of a request is known.
+//
+// param name The span name.
+func (s *Span) SetName(name string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.name = name
+	s.mu.Unlock()
+}
+
+// SetAttribute sets an attribute (string, bool, integer or float) of the span.
+//
+// param key The attribute key (e.g., "http.response.status_code").
+// param value The attribute value.
+func (s *Span) SetAttribute(key string, value interface{}) {
+	if s == nil || !s.sampled {
+		return
+	}
+	s.mu.Lock()
+	if s.attributes == nil {
+		s.attributes = make(map[string]interface{})
+	}
+	s.attributes[key] = value
+	s.mu.Unlock()
+}
+
+// RecordError marks the span as failed with the error message; nil errors are ignored.
+//
+// param err The error.
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.mu.Lock()
+	s.status = statusError
+	s.message = err.Error()
+	s.mu.Unlock()
+}
+
+// End finishes the span and queues it for export when it is sampled.
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	if !s.end.IsZero() {
+		s.mu.Unlock()
+		return
+	}
+	s.end = time.Now()
+	s.mu.Unlock()
+
+	if s.sampled {
+		if exp := currentExporter(); exp != nil {
+			exp.enqueue(s)
+		}
+	}
+}
+
+// TraceParent returns the W3C traceparent header that continues the trace in a downstream service.
+//
+// return string The header value, or "" for a nil span.
+func (s *Span) TraceParent() string {
+	if s == nil {
+		return ""
+	}
+	flags := "00"
+	if s.sampled {
+		flags = "01"
+	}
+	return fmt.Sprintf("00-%s-%s-%s", hex.EncodeToString(s.traceID[:]), hex.EncodeToString(s.spanID[:]), flags)
+}
+
+// TraceID returns the hex trace ID, e.g. to correlate logs with the trace.
+//
+// return string The trace ID, or "" for a nil span.
+func (s *Span) TraceID() string {
+	if s == nil {
+		return ""
+	}
+	return hex.EncodeToString(s.traceID[:])
+}
+
+// parseTraceParent parses a version 00 W3C traceparent header.
+func parseTraceParent(header string) ([16]byte, [8]byte, bool, bool) {
+	var traceID [16]byte
+	var parentID [8]byte
+	parts := strings.Split(strings.TrimSpace(header), "-")
+	if len(parts) != 4 || parts[0] != "00" || len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		return traceID, parentID, false, false
+	}
+	if _, err := hex.Decode(traceID[:], []byte(parts[1])); err != nil || traceID == [16]byte{} {
+		return traceID, parentID, false, false
+	}
+	if _, err := hex.Decode(parentID[:], []byte(parts[2])); err != nil || parentID == [8]byte{} {
+		return traceID, parentID, false, false
+	}
+	flags, err := hex.DecodeString(parts[3])
+	if err != nil {
+		return traceID, parentID, false, false
+	}
+	return traceID, parentID, flags[0]&0x01 == 1, true
+}
+
+// traceIDRatio maps a trace ID to [0, 1) for ratio sampling, so every service samples a trace alike.
+func traceIDRatio(traceID [16]byte) float64 {
+	return float64(binary.BigEndian.Uint64(traceID[8:])>>11) / float64(1<<53)
+}
@@ -2,48 +2,94 @@ package middlewares
 
 import (
 	"net/http"
+	"strings"
 	"teralux_app/domain/common/dtos"
 	"teralux_app/domain/common/utils"
 
 	"github.com/gin-gonic/gin"
 )
 
+// APIKeyValidator validates X-API-KEY values against the managed API keys.
+type APIKeyValidator interface {
+	ValidateKey(key string) (utils.APIKeyIdentity, bool)
+}
+
+// IdentityTokenValidator validates the bearer tokens issued to users of an identity provider (OIDC, LDAP).
+type IdentityTokenValidator interface {
+	ValidateIdentityToken(token string) (utils.APIKeyIdentity, string, bool)
+}
+
 // ApiKeyMiddleware validates the presence and correctness of the X-API-KEY header.
-// It ensures that only clients with the correct API key can access the protected endpoints.
+// It ensures that only clients with a valid API key of at least the required scope can access the protected
+// endpoints. The scope and ID of the key are stored in the context as "api_key_scope" and "api_key_id".
+// Without an X-API-KEY header, an identity token ("Authorization: Bearer") is accepted instead, with the
+// user's role as scope; the user is stored in the context as "identity_subject".
 //
-// @return gin.HandlerFunc The Gin middleware handler.
-// @throws 500 If the server API key configuration is missing.
+// param keys The APIKeyValidator checking managed keys (and the API_KEY from the environment).
+// param identities The IdentityTokenValidator checking identity tokens (nil to accept API keys only).
+// param scope The least privileged scope allowed (read-only, control or admin).
+// return gin.HandlerFunc The Gin middleware handler.
 // @throws 401 If the provided API key is invalid or missing.
-func ApiKeyMiddleware() gin.HandlerFunc {
+// @throws 403 If the API key's scope is not sufficient.
+func ApiKeyMiddleware(keys APIKeyValidator, identities IdentityTokenValidator, scope string) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		apiKey := c.GetHeader("X-API-KEY")
-		config := utils.GetConfig()
-		validApiKey := config.ApiKey
-
-		if validApiKey == "" {
-			utils.LogError("ApiKeyMiddleware: API_KEY is not set in server config!")
-			c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+		key := c.GetHeader("X-API-KEY")
+		identity, ok := keys.ValidateKey(key)
+		if !ok && key == "" && identities != nil {
+			bearer := strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer ")
+			var subject string
+			if identity, subject, ok = identities.ValidateIdentityToken(bearer); ok {
+				identity.Subject = subject
+			}
+		}
+		if !ok {
+			utils.LogWarn("ApiKeyMiddleware: Invalid API Key provided")
+			c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
 				Status:  false,
-				Message: "Server misconfiguration: API_KEY not set",
+				Message: "Invalid API Key",
 				Data:    nil,
 			})
 			c.Abort()
 			return
 		}
 
-		if apiKey != validApiKey {
-			utils.LogWarn("ApiKeyMiddleware: Invalid API Key provided")
-			c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
+		grantedScope := identity.Scope
+		if !utils.APIKeyScopeAllows(grantedScope, scope) {
+			utils.LogWarn("ApiKeyMiddleware: API key scope '%s' cannot access %s", grantedScope, c.FullPath())
+			c.JSON(http.StatusForbidden, dtos.StandardResponse{
 				Status:  false,
-				Message: "Invalid API Key",
+				Message: "API key scope '" + grantedScope + "' is not allowed to access this endpoint",
 				Data:    nil,
 			})
 			c.Abort()
 			return
 		}
-		
+
 		utils.LogDebug("ApiKeyMiddleware: Valid API Key")
+		setAPIKeyIdentity(c, identity)
 
 		c.Next()
 	}
-}
\ No newline at end of file
+}
+
+// scopeAllowsMethod reports whether a scope may use an HTTP method on the device endpoints.
+// Read-only keys may only read; an empty scope (no API key involved) is not restricted.
+func scopeAllowsMethod(scope, method string) bool {
+	if scope != utils.APIKeyScopeReadOnly {
+		return true
+	}
+	return method == http.MethodGet || method == http.MethodHead || method == http.MethodOptions
+}
+
+// setAPIKeyIdentity stores the scope and ID of the caller's API key (and the identity provider user) in the
+// context, and the whole identity in the request context for the usecases (see utils.APIKeyIdentityFromContext).
+func setAPIKeyIdentity(c *gin.Context, identity utils.APIKeyIdentity) {
+	c.Set("api_key_scope", identity.Scope)
+	if identity.ID != "" {
+		c.Set("api_key_id", identity.ID)
+	}
+	if identity.Subject != "" {
+		c.Set("identity_subject", identity.Subject)
+	}
+	c.Request = c.Request.WithContext(utils.ContextWithAPIKeyIdentity(c.Request.Context(), identity))
+}
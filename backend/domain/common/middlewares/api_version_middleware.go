@@ -0,0 +1,118 @@
+package middlewares
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// apiVersionContextKey is the request context key of the version taken from a /api/v{N}/ path.
+type apiVersionContextKey struct{}
+
+// apiVersionMediaTypePrefix is the vendor media type prefix for requesting a version via Accept
+// (e.g., application/vnd.teralux.v1+json).
+const apiVersionMediaTypePrefix = "application/vnd.teralux.v"
+
+// VersionedPaths serves /api/v{N}/... requests with the routes registered under /api/...
+// Routes are registered once, unversioned; this handler strips the version segment before Gin routes the
+// request, so /api/v1/tuya/devices and the legacy alias /api/tuya/devices reach the same handlers (and the
+// same route patterns, e.g. for DeviceAccessMiddleware). The version is kept for APIVersionMiddleware.
+// Requests for a version the server does not answer get 404.
+//
+// param next The router.
+// return http.Handler The wrapped handler.
+// @throws 404 If the path names an unsupported version.
+func VersionedPaths(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		version, legacyPath, ok := utils.SplitVersionedAPIPath(r.URL.Path)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if !utils.IsSupportedAPIVersion(version) {
+			w.Header().Set("Content-Type", "application/json; charset=utf-8")
+			w.WriteHeader(http.StatusNotFound)
+			_ = json.NewEncoder(w).Encode(dtos.StandardResponse{
+				Status:  false,
+				Message: "API version v" + strconv.Itoa(version) + " is not supported",
+				Data:    nil,
+			})
+			return
+		}
+
+		r = r.WithContext(context.WithValue(r.Context(), apiVersionContextKey{}, version))
+		r.URL.Path = legacyPath
+		if r.URL.RawPath != "" {
+			if _, legacyRawPath, ok := utils.SplitVersionedAPIPath(r.URL.RawPath); ok {
+				r.URL.RawPath = legacyRawPath
+			} else {
+				r.URL.RawPath = ""
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// APIVersionMiddleware negotiates the API version of /api requests and stores it in the context as
+// "api_version", so handlers can keep older response shapes for older clients.
+// The version comes from the /api/v{N}/ path, else from the X-API-Version header ("1" or "v1"), else from an
+// Accept media type like application/vnd.teralux.v1+json. Unversioned requests to the legacy /api/... aliases
+// are served as version 1 and marked with Deprecation and Link (successor-version) headers.
+// The negotiated version is returned in the X-API-Version response header.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+// @throws 406 If the headers request an unsupported version.
+func APIVersionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		path := c.Request.URL.Path
+		if path != "/api" && !strings.HasPrefix(path, "/api/") {
+			c.Next()
+			return
+		}
+
+		version, fromPath := c.Request.Context().Value(apiVersionContextKey{}).(int)
+		if !fromPath {
+			requested, ok := requestedAPIVersion(c.Request)
+			if ok && !utils.IsSupportedAPIVersion(requested) {
+				utils.LogWarn("APIVersionMiddleware: Unsupported API version %d requested for %s", requested, path)
+				c.JSON(http.StatusNotAcceptable, dtos.StandardResponse{
+					Status:  false,
+					Message: "API version v" + strconv.Itoa(requested) + " is not supported",
+					Data:    nil,
+				})
+				c.Abort()
+				return
+			}
+			version = 1
+			if ok {
+				version = requested
+			}
+			c.Header("Deprecation", "true")
+			c.Header("Link", "<"+utils.VersionedAPIPath(path, version)+">; rel=\"successor-version\"")
+		}
+
+		c.Set("api_version", version)
+		c.Header("X-API-Version", strconv.Itoa(version))
+		c.Next()
+	}
+}
+
+// requestedAPIVersion reads the version requested through headers.
+func requestedAPIVersion(r *http.Request) (int, bool) {
+	if header := r.Header.Get("X-API-Version"); header != "" {
+		return utils.ParseAPIVersion(header)
+	}
+	for _, mediaType := range strings.Split(r.Header.Get("Accept"), ",") {
+		mediaType = strings.TrimSpace(strings.SplitN(mediaType, ";", 2)[0])
+		if rest, ok := strings.CutPrefix(mediaType, apiVersionMediaTypePrefix); ok {
+			return utils.ParseAPIVersion(strings.TrimSuffix(rest, "+json"))
+		}
+	}
+	return 0, false
+}
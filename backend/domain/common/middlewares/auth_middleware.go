@@ -1,6 +1,7 @@
 package middlewares
 
 import (
+	"context"
 	"net/http"
 	"strings"
 	"teralux_app/domain/common/dtos"
@@ -9,15 +10,84 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// SessionResolver resolves opaque server-side session IDs into Tuya access tokens.
+// When app tokens are enabled, it also verifies the signed JWTs that wrap those session IDs.
+type SessionResolver interface {
+	IsSessionID(token string) bool
+	ResolveSession(sessionID string) (string, utils.APIKeyIdentity, error)
+	AppTokensEnabled() bool
+	VerifyAppToken(token string) (string, error)
+}
+
+// TicketRedeemer exchanges single-use websocket tickets for the credentials of the request they were issued to.
+type TicketRedeemer interface {
+	RedeemTicket(ticket string) (string, utils.APIKeyIdentity, error)
+}
+
+// ServerTokenProvider supplies the server-managed Tuya access token for requests without an Authorization header.
+type ServerTokenProvider interface {
+	ServerAccessToken(ctx context.Context) (string, error)
+}
+
 // AuthMiddleware processes the Authorization header to extract the Bearer token.
+// Bearer values that are session IDs are resolved server-side into the stored Tuya token.
+// When JWT_SECRET is set, only app JWTs issued by /api/tuya/auth are accepted: their signature and expiry
+// are verified and the session ID they carry is resolved; raw Tuya tokens and bare session IDs are rejected.
+// Otherwise, raw Tuya token pass-through still works but is flagged as deprecated when AUTH_SESSION_MODE is enabled.
 // It also optionally parses the "X-TUYA-UID" header and stores it in the context.
+// A UID override is only accepted when it is allowlisted for the caller's API key or identity (TUYA_UID_ALLOWLIST).
+// Websocket upgrade requests may authenticate with a single-use "ticket" query parameter issued by
+// /api/realtime/ticket instead, since browsers cannot attach custom headers to websocket handshakes.
+// Bearer tokens are never read from the query string, where they would end up in access logs.
+// When SERVER_MANAGED_TOKEN is enabled, requests without an Authorization header are accepted with a valid
+// X-API-KEY alone and use the server-managed Tuya token.
+// The caller's API key scope and ID come from the X-API-KEY header, or else from the key that created the session;
+// read-only callers may only send GET requests.
 //
-// @return gin.HandlerFunc The Gin middleware handler.
-// @throws 401 If the Authorization header is missing or malformed.
-func AuthMiddleware() gin.HandlerFunc {
+// param resolver The SessionResolver used for session IDs (may be nil to disable sessions).
+// param tokens The ServerTokenProvider used for API-key-only requests (may be nil to disable them).
+// param keys The APIKeyValidator checking X-API-KEY headers.
+// param tickets The TicketRedeemer used for websocket tickets (may be nil to disable them).
+// return gin.HandlerFunc The Gin middleware handler.
+// @throws 401 If the Authorization header is missing or malformed, the session or websocket ticket is invalid, or the X-API-KEY is invalid.
+// @throws 403 If the requested X-TUYA-UID is not allowlisted for the API key, or the scope is read-only.
+// @throws 503 If the server-managed token cannot be obtained.
+func AuthMiddleware(resolver SessionResolver, tokens ServerTokenProvider, keys APIKeyValidator, tickets TicketRedeemer) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		utils.LogDebug("AuthMiddleware: processing request")
+		if !redeemTicket(c, tickets) {
+			c.Abort()
+			return
+		}
+		if apiKey := c.GetHeader("X-API-KEY"); apiKey != "" {
+			identity, ok := keys.ValidateKey(apiKey)
+			if !ok {
+				utils.LogWarn("AuthMiddleware: invalid API key provided")
+				c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
+					Status:  false,
+					Message: "Invalid API Key",
+					Data:    nil,
+				})
+				c.Abort()
+				return
+			}
+			setAPIKeyIdentity(c, identity)
+		}
+
 		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && tokens != nil && utils.GetConfig().ServerManagedToken {
+			if !useServerToken(c, tokens) {
+				c.Abort()
+				return
+			}
+			if !applyUIDOverride(c) || !checkScope(c) {
+				c.Abort()
+				return
+			}
+			attachActor(c)
+			c.Next()
+			return
+		}
 		if authHeader == "" {
 			utils.LogWarn("AuthMiddleware: missing Authorization Header")
 			c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
@@ -45,14 +115,170 @@ func AuthMiddleware() gin.HandlerFunc {
 			c.Abort()
 			return
 		}
+
+		if resolver != nil && resolver.AppTokensEnabled() {
+			sessionID, err := resolver.VerifyAppToken(accessToken)
+			if err != nil {
+				message := "Invalid or expired token. Please login again"
+				if !utils.LooksLikeJWT(accessToken) {
+					message = "Raw Tuya tokens and session IDs are not accepted. Use the token issued by /api/tuya/auth"
+				}
+				utils.LogWarn("AuthMiddleware: rejected app token: %v", err)
+				c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
+					Status:  false,
+					Message: message,
+					Data:    nil,
+				})
+				c.Abort()
+				return
+			}
+			accessToken = sessionID
+		}
+
+		if resolver != nil && resolver.IsSessionID(accessToken) {
+			sessionID := accessToken
+			resolvedToken, sessionKey, err := resolver.ResolveSession(sessionID)
+			if err != nil {
+				utils.LogWarn("AuthMiddleware: failed to resolve session: %v", err)
+				c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
+					Status:  false,
+					Message: "Session expired or invalid. Please login again",
+					Data:    nil,
+				})
+				c.Abort()
+				return
+			}
+			accessToken = resolvedToken
+			c.Set("session_id", sessionID)
+			if c.GetString("api_key_scope") == "" && sessionKey.Scope != "" {
+				setAPIKeyIdentity(c, sessionKey)
+			}
+		} else if utils.GetConfig().AuthSessionMode {
+			utils.LogDebug("AuthMiddleware: raw Tuya token pass-through used while session mode is enabled")
+			c.Header("Deprecation", "true")
+		}
+
 		c.Set("access_token", accessToken)
 		utils.LogDebug("AuthMiddleware: token parsed successfully")
-	
-		tuyaUID := c.GetHeader("X-TUYA-UID") 
-		if tuyaUID != "" {
-			c.Set("tuya_uid", tuyaUID)
+
+		if !applyUIDOverride(c) || !checkScope(c) {
+			c.Abort()
+			return
 		}
 
+		attachActor(c)
 		c.Next()
 	}
+}
+
+// redeemTicket applies the credentials of the ticket of a websocket upgrade to the request.
+// It writes the error response and returns false when the ticket cannot be redeemed.
+func redeemTicket(c *gin.Context, tickets TicketRedeemer) bool {
+	ticket := c.Query("ticket")
+	if ticket == "" || tickets == nil || !strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		return true
+	}
+	authorization, identity, err := tickets.RedeemTicket(ticket)
+	if err != nil {
+		utils.LogWarn("AuthMiddleware: failed to redeem websocket ticket: %v", err)
+		c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
+			Status:  false,
+			Message: "Websocket ticket is invalid or expired",
+			Data:    nil,
+		})
+		return false
+	}
+	if authorization != "" {
+		c.Request.Header.Set("Authorization", authorization)
+	}
+	if identity.Scope != "" {
+		setAPIKeyIdentity(c, identity)
+	}
+	return true
+}
+
+// useServerToken authenticates an API-key-only request with the server-managed token.
+// It writes the error response and returns false when the request cannot proceed.
+func useServerToken(c *gin.Context, tokens ServerTokenProvider) bool {
+	if c.GetString("api_key_scope") == "" {
+		utils.LogWarn("AuthMiddleware: missing Authorization Header and no valid API key for the server-managed token")
+		c.JSON(http.StatusUnauthorized, dtos.StandardResponse{
+			Status:  false,
+			Message: "Authorization header or valid X-API-KEY is required",
+			Data:    nil,
+		})
+		return false
+	}
+
+	accessToken, err := tokens.ServerAccessToken(c.Request.Context())
+	if err != nil {
+		utils.LogError("AuthMiddleware: failed to obtain server-managed token: %v", err)
+		c.JSON(http.StatusServiceUnavailable, dtos.StandardResponse{
+			Status:  false,
+			Message: "Server-managed Tuya token is unavailable",
+			Data:    nil,
+		})
+		return false
+	}
+
+	c.Set("access_token", accessToken)
+	c.Set("server_token", true)
+	utils.LogDebug("AuthMiddleware: using server-managed token")
+	return true
+}
+
+// checkScope rejects requests a read-only API key may not send.
+// It writes the error response and returns false when the request is not allowed.
+func checkScope(c *gin.Context) bool {
+	scope := c.GetString("api_key_scope")
+	if scopeAllowsMethod(scope, c.Request.Method) {
+		return true
+	}
+	utils.LogWarn("AuthMiddleware: %s scope cannot send %s %s", scope, c.Request.Method, c.FullPath())
+	c.JSON(http.StatusForbidden, dtos.StandardResponse{
+		Status:  false,
+		Message: "API key scope '" + scope + "' is read-only",
+		Data:    nil,
+	})
+	return false
+}
+
+// applyUIDOverride stores an allowlisted X-TUYA-UID in the context.
+// It writes the error response and returns false when the override is not allowed.
+func applyUIDOverride(c *gin.Context) bool {
+	tuyaUID := c.GetHeader("X-TUYA-UID")
+	if tuyaUID == "" {
+		return true
+	}
+	if !utils.GetConfig().IsUIDAllowed(utils.APIKeyIdentityFromContext(c.Request.Context()), tuyaUID) {
+		utils.LogWarn("AuthMiddleware: UID override '%s' not allowed for the provided API key", tuyaUID)
+		c.JSON(http.StatusForbidden, dtos.StandardResponse{
+			Status:  false,
+			Message: "X-TUYA-UID is not allowed for this API key",
+			Data:    nil,
+		})
+		return false
+	}
+	c.Set("tuya_uid", tuyaUID)
+	c.Request = c.Request.WithContext(utils.ContextWithLogFields(c.Request.Context(), utils.Fields{"uid": tuyaUID}))
+	return true
+}
+
+
+// attachActor stores the caller identity in the request context, so usecases can tell users apart
+// (e.g., for two-person approval). Callers with verified credentials are identified by their API key ID or
+// identity subject (see utils.APIKeyIdentity.Actor), which stays the same across sessions. Unverified callers
+// are identified by their Tuya UID, or else by a hash of their session or token.
+func attachActor(c *gin.Context) {
+	actor := "server"
+	if verified := utils.APIKeyIdentityFromContext(c.Request.Context()).Actor(); verified != "" {
+		actor = verified
+	} else if uid := c.GetString("tuya_uid"); uid != "" {
+		actor = "uid:" + uid
+	} else if sessionID := c.GetString("session_id"); sessionID != "" {
+		actor = "session:" + utils.HashString(sessionID)[:12]
+	} else if !c.GetBool("server_token") {
+		actor = "token:" + utils.HashString(c.GetString("access_token"))[:12]
+	}
+	c.Request = c.Request.WithContext(utils.ContextWithActor(c.Request.Context(), actor))
 }
\ No newline at end of file
@@ -0,0 +1,167 @@
+package middlewares
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// compressionMinSize is the smallest body worth compressing; below it the gzip framing outweighs the savings.
+const compressionMinSize = 1024
+
+// gzipWriterPool recycles gzip writers, whose compression state is expensive to allocate per response.
+var gzipWriterPool = sync.Pool{
+	New: func() interface{} {
+		return gzip.NewWriter(io.Discard)
+	},
+}
+
+// compressionWriter buffers the start of the response body and switches to gzip once it is large enough.
+type compressionWriter struct {
+	gin.ResponseWriter
+	buffer  []byte
+	decided bool
+	gz      *gzip.Writer
+}
+
+// Write buffers the body until compressionMinSize bytes are seen, then streams it (compressed or not).
+//
+// param b The byte slice to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *compressionWriter) Write(b []byte) (int, error) {
+	if w.decided {
+		if w.gz != nil {
+			return w.gz.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+	w.buffer = append(w.buffer, b...)
+	if len(w.buffer) >= compressionMinSize {
+		if err := w.decide(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// WriteString writes a string body.
+//
+// param s The string to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *compressionWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Flush sends what has been written so far. Streamed responses (e.g., server-sent events) flush before the
+// body is complete and are therefore sent uncompressed unless compression already started.
+func (w *compressionWriter) Flush() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+// decide commits to compressing (when wanted and the response is compressible) and writes the buffered body.
+func (w *compressionWriter) decide(compress bool) error {
+	w.decided = true
+	buffered := w.buffer
+	w.buffer = nil
+
+	if compress && compressible(w.ResponseWriter.Status(), w.Header()) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Header().Add("Vary", "Accept-Encoding")
+		w.Header().Del("Content-Length")
+		w.gz = gzipWriterPool.Get().(*gzip.Writer)
+		w.gz.Reset(w.ResponseWriter)
+		_, err := w.gz.Write(buffered)
+		return err
+	}
+	if len(buffered) == 0 {
+		return nil
+	}
+	_, err := w.ResponseWriter.Write(buffered)
+	return err
+}
+
+// finish writes a body that stayed below compressionMinSize and closes the gzip stream.
+func (w *compressionWriter) finish() {
+	if !w.decided {
+		_ = w.decide(false)
+	}
+	if w.gz != nil {
+		_ = w.gz.Close()
+		gzipWriterPool.Put(w.gz)
+		w.gz = nil
+	}
+}
+
+// CompressionMiddleware gzips response bodies of at least 1 KiB for clients sending Accept-Encoding: gzip.
+// Only text-like content (JSON, text, XML, SVG, CSV) is compressed; images, already encoded bodies,
+// server-sent event streams, HEAD requests and websocket upgrades pass through untouched.
+// Brotli is not offered; clients asking only for br receive uncompressed responses.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+func CompressionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == http.MethodHead || !acceptsGzip(c.GetHeader("Accept-Encoding")) ||
+			strings.EqualFold(c.GetHeader("Upgrade"), "websocket") ||
+			strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		w := &compressionWriter{ResponseWriter: c.Writer}
+		c.Writer = w
+		defer w.finish()
+
+		c.Next()
+	}
+}
+
+// acceptsGzip reports whether an Accept-Encoding header allows gzip (explicitly or through *).
+func acceptsGzip(header string) bool {
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding != "gzip" && coding != "*" {
+			continue
+		}
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err == nil && value == 0 {
+				continue
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// compressible reports whether a response with this status and headers should be compressed.
+func compressible(status int, header http.Header) bool {
+	if status < http.StatusOK || status == http.StatusNoContent || status == http.StatusNotModified {
+		return false
+	}
+	if header.Get("Content-Encoding") != "" {
+		return false
+	}
+	contentType := strings.ToLower(header.Get("Content-Type"))
+	if strings.HasPrefix(contentType, "text/event-stream") {
+		return false
+	}
+	for _, prefix := range []string{"application/json", "text/", "application/xml", "application/javascript", "image/svg+xml"} {
+		if strings.HasPrefix(contentType, prefix) {
+			return true
+		}
+	}
+	return false
+}
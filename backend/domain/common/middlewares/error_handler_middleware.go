@@ -0,0 +1,52 @@
+package middlewares
+
+import (
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorResponder maps an error a handler recorded with c.Error to the HTTP status and body it is answered with.
+type ErrorResponder func(err error) (int, dtos.StandardResponse)
+
+// ErrorHandlerMiddleware answers requests whose handler recorded an error with c.Error instead of writing a
+// response, so typed errors are mapped to responses in one place. The last recorded error is answered; a
+// string meta set on it names the failed operation in the log.
+//
+// param respond The function that maps errors to responses.
+// return gin.HandlerFunc The Gin middleware handler.
+func ErrorHandlerMiddleware(respond ErrorResponder) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+		last := c.Errors.Last()
+		status, body := respond(last.Err)
+
+		operation, _ := last.Meta.(string)
+		if operation == "" {
+			operation = c.Request.Method + " " + c.FullPath()
+		}
+		if status >= 500 {
+			utils.LogError("%s failed: %v", operation, last.Err)
+		} else {
+			utils.LogWarn("%s failed: %v", operation, last.Err)
+		}
+
+		c.JSON(status, body)
+	}
+}
+
+// AbortWithError records the error of a failed operation with c.Error and stops the handler chain;
+// ErrorHandlerMiddleware answers it from the typed error (see domain/tuya/errors).
+//
+// param c The Gin context.
+// param operation The name of the failed operation, for the log.
+// param err The error.
+func AbortWithError(c *gin.Context, operation string, err error) {
+	_ = c.Error(err).SetMeta(operation)
+	c.Abort()
+}
@@ -0,0 +1,206 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLoadShedMaxInFlight        = 64
+	defaultLoadShedMaxQueueDepth      = 128
+	defaultLoadShedMaxUpstreamLatency = 3 * time.Second
+	defaultLoadShedRetryAfter         = 30 * time.Second
+	// upstreamLatencyStaleAfter ignores the upstream average once no call was made for a while,
+	// so a past slowdown cannot keep shedding requests that do not reach upstream.
+	upstreamLatencyStaleAfter = time.Minute
+)
+
+// defaultLowPriorityRoutes are the expensive read and export routes shed under overload.
+var defaultLowPriorityRoutes = []string{
+	"/api/tuya/devices/:id/sensor/history",
+	"/api/tuya/devices/:id/sensor/chart",
+	"/api/tuya/devices/changes/log",
+	"/api/automations/:id/history",
+	"/api/admin/archive/run",
+	"/api/admin/backup",
+	"/api/admin/backup/:job_id",
+	"/api/cache/gc",
+	"/api/cache/warm",
+}
+
+// LoadShedder rejects low-priority requests with 503 and Retry-After while the server is overloaded,
+// keeping capacity for control and status endpoints. The server counts as overloaded while any signal
+// exceeds its threshold: requests in flight (LOAD_SHED_MAX_IN_FLIGHT), queued background jobs
+// (LOAD_SHED_MAX_QUEUE_DEPTH) or the moving average of Tuya API latency (LOAD_SHED_MAX_UPSTREAM_LATENCY).
+// A threshold of 0 disables its signal. Low-priority routes are listed in LOAD_SHED_ROUTES.
+type LoadShedder struct {
+	maxInFlight        int64
+	maxQueueDepth      int
+	maxUpstreamLatency time.Duration
+	retryAfter         time.Duration
+	lowPriority        map[string]bool
+	queueDepth         func() int
+
+	inFlight atomic.Int64
+
+	mu          sync.Mutex
+	shedTotal   int64
+	shedByRoute map[string]int64
+}
+
+// NewLoadShedder initializes a LoadShedder from the LOAD_SHED_* configuration.
+//
+// return *LoadShedder A pointer to the initialized shedder.
+func NewLoadShedder() *LoadShedder {
+	config := utils.GetConfig()
+	shedder := &LoadShedder{
+		maxInFlight:        defaultLoadShedMaxInFlight,
+		maxQueueDepth:      defaultLoadShedMaxQueueDepth,
+		maxUpstreamLatency: defaultLoadShedMaxUpstreamLatency,
+		retryAfter:         defaultLoadShedRetryAfter,
+		lowPriority:        make(map[string]bool),
+		shedByRoute:        make(map[string]int64),
+	}
+	if value, err := strconv.ParseInt(config.LoadShedMaxInFlight, 10, 64); err == nil && value >= 0 {
+		shedder.maxInFlight = value
+	}
+	if value, err := strconv.Atoi(config.LoadShedMaxQueueDepth); err == nil && value >= 0 {
+		shedder.maxQueueDepth = value
+	}
+	if value, err := time.ParseDuration(config.LoadShedMaxUpstreamLatency); err == nil && value >= 0 {
+		shedder.maxUpstreamLatency = value
+	}
+	if value, err := time.ParseDuration(config.LoadShedRetryAfter); err == nil && value >= time.Second {
+		shedder.retryAfter = value
+	}
+
+	routes := defaultLowPriorityRoutes
+	if config.LoadShedRoutes != "" {
+		routes = strings.Split(config.LoadShedRoutes, ",")
+	}
+	for _, route := range routes {
+		if route = strings.TrimSpace(route); route != "" {
+			shedder.lowPriority[route] = true
+		}
+	}
+	return shedder
+}
+
+// SetQueueDepth sets the source of the background job queue depth.
+// It must be called before the server starts handling requests.
+//
+// param queueDepth Returns the number of queued background jobs.
+func (s *LoadShedder) SetQueueDepth(queueDepth func() int) {
+	s.queueDepth = queueDepth
+}
+
+// Middleware counts requests in flight and sheds low-priority routes while overloaded.
+// It must be installed on the router so every request is counted.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+// @throws 503 If the route is low priority and the server is overloaded.
+func (s *LoadShedder) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if s.lowPriority[route] {
+			if reasons := s.overloaded(); len(reasons) > 0 {
+				s.recordShed(route)
+				utils.LogWarn("LoadShedder: Shedding %s %s (%s)", c.Request.Method, route, strings.Join(reasons, ", "))
+				c.Header("Retry-After", strconv.Itoa(int(s.retryAfter/time.Second)))
+				c.JSON(http.StatusServiceUnavailable, dtos.StandardResponse{
+					Status:  false,
+					Message: "Server is under heavy load, please retry later",
+					Data:    nil,
+				})
+				c.Abort()
+				return
+			}
+		}
+
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+		c.Next()
+	}
+}
+
+// Status returns the current load signals, thresholds and shedding counters.
+//
+// return dtos.LoadStatusDTO The load status.
+func (s *LoadShedder) Status() dtos.LoadStatusDTO {
+	reasons := s.overloaded()
+	latency, sampledAt := utils.UpstreamLatency()
+	routes := make([]string, 0, len(s.lowPriority))
+	for route := range s.lowPriority {
+		routes = append(routes, route)
+	}
+	sort.Strings(routes)
+
+	status := dtos.LoadStatusDTO{
+		Overloaded:         len(reasons) > 0,
+		Reasons:            reasons,
+		InFlight:           s.inFlight.Load(),
+		QueueDepth:         s.currentQueueDepth(),
+		UpstreamLatencyMs:  latency.Milliseconds(),
+		MaxInFlight:        s.maxInFlight,
+		MaxQueueDepth:      s.maxQueueDepth,
+		MaxUpstreamLatency: s.maxUpstreamLatency.Milliseconds(),
+		RetryAfterSeconds:  int(s.retryAfter / time.Second),
+		LowPriorityRoutes:  routes,
+		ShedByRoute:        make(map[string]int64),
+	}
+	if !sampledAt.IsZero() {
+		status.UpstreamSampledAt = sampledAt.Unix()
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	status.ShedTotal = s.shedTotal
+	for route, count := range s.shedByRoute {
+		status.ShedByRoute[route] = count
+	}
+	return status
+}
+
+// overloaded returns the signals exceeding their thresholds.
+func (s *LoadShedder) overloaded() []string {
+	var reasons []string
+	if inFlight := s.inFlight.Load(); s.maxInFlight > 0 && inFlight >= s.maxInFlight {
+		reasons = append(reasons, fmt.Sprintf("%d requests in flight", inFlight))
+	}
+	if depth := s.currentQueueDepth(); s.maxQueueDepth > 0 && depth >= s.maxQueueDepth {
+		reasons = append(reasons, fmt.Sprintf("%d jobs queued", depth))
+	}
+	if s.maxUpstreamLatency > 0 {
+		latency, sampledAt := utils.UpstreamLatency()
+		if latency >= s.maxUpstreamLatency && time.Since(sampledAt) < upstreamLatencyStaleAfter {
+			reasons = append(reasons, fmt.Sprintf("upstream latency %s", latency.Round(time.Millisecond)))
+		}
+	}
+	return reasons
+}
+
+// currentQueueDepth returns the number of queued background jobs, or 0 without a source.
+func (s *LoadShedder) currentQueueDepth() int {
+	if s.queueDepth == nil {
+		return 0
+	}
+	return s.queueDepth()
+}
+
+// recordShed counts a shed request.
+func (s *LoadShedder) recordShed(route string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shedTotal++
+	s.shedByRoute[route]++
+}
@@ -0,0 +1,75 @@
+package middlewares
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader carries the correlation ID of a request. Clients and proxies may set it; otherwise one is generated.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied correlation IDs so they cannot bloat the logs.
+const maxRequestIDLength = 128
+
+// RequestIDMiddleware assigns every request a correlation ID and logs one access entry per request.
+// The ID is echoed in the X-Request-ID response header and attached to the request context, so every entry
+// logged through utils.LoggerFromContext while handling the request carries the same request_id field.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+func RequestIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if !isValidRequestID(requestID) {
+			requestID = newRequestID()
+		}
+		c.Set("request_id", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(utils.ContextWithLogFields(c.Request.Context(), utils.Fields{"request_id": requestID}))
+
+		start := time.Now()
+		c.Next()
+
+		logger := utils.LoggerFromContext(c.Request.Context()).WithFields(utils.Fields{
+			"method":     c.Request.Method,
+			"path":       c.Request.URL.Path,
+			"status":     c.Writer.Status(),
+			"latency_ms": time.Since(start).Milliseconds(),
+			"client_ip":  c.ClientIP(),
+		})
+		switch {
+		case c.Writer.Status() >= 500:
+			logger.Error("%s %s", c.Request.Method, c.Request.URL.Path)
+		case c.Request.URL.Path == "/health":
+			logger.Debug("%s %s", c.Request.Method, c.Request.URL.Path)
+		default:
+			logger.Info("%s %s", c.Request.Method, c.Request.URL.Path)
+		}
+	}
+}
+
+// isValidRequestID accepts non-empty IDs of printable ASCII characters up to maxRequestIDLength.
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newRequestID returns a random 128-bit hex correlation ID.
+func newRequestID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return hex.EncodeToString([]byte(time.Now().Format(time.RFC3339Nano)))
+	}
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,79 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseMetaHeader is the request header clients set to "true" to receive timing metadata.
+const ResponseMetaHeader = "X-Include-Meta"
+
+// responseMetaWriter buffers the response body so the meta section can be added before it is sent.
+type responseMetaWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write captures the response body bytes.
+//
+// param b The byte slice to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *responseMetaWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteString captures the response body string.
+//
+// param s The string to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *responseMetaWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ResponseMetaMiddleware adds request timing metadata to JSON responses when the client sends X-Include-Meta: true.
+// The meta section reports total_ms, upstream_ms, upstream_calls, cache and retries, so clients can tell
+// time spent in the backend apart from time spent waiting on Tuya. A Server-Timing header carries the same values.
+// Requests without the header, and websocket upgrades, pass through untouched.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+func ResponseMetaMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !strings.EqualFold(c.GetHeader(ResponseMetaHeader), "true") || strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+
+		ctx, meta := utils.WithRequestMeta(c.Request.Context())
+		c.Request = c.Request.WithContext(ctx)
+
+		w := &responseMetaWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		snapshot := meta.Snapshot()
+		c.Header("Server-Timing", fmt.Sprintf("total;dur=%d, upstream;dur=%d", snapshot["total_ms"], snapshot["upstream_ms"]))
+
+		var payload map[string]interface{}
+		if err := json.Unmarshal(w.body.Bytes(), &payload); err != nil || payload == nil {
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+
+		payload["meta"] = snapshot
+		patched, err := json.Marshal(payload)
+		if err != nil {
+			utils.LogWarn("ResponseMetaMiddleware: failed to encode response meta: %v", err)
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+		w.ResponseWriter.Write(patched)
+	}
+}
@@ -0,0 +1,163 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ResponseFieldsParam is the query parameter clients use to select the fields of each record in the response data.
+const ResponseFieldsParam = "fields"
+
+// responseTransformWriter buffers the response body so it can be pruned before it is sent.
+type responseTransformWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write captures the response body bytes.
+//
+// param b The byte slice to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *responseTransformWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteString captures the response body string.
+//
+// param s The string to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *responseTransformWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// ResponseTransformMiddleware prunes the data of JSON responses to the fields listed in ?fields=id,name,online,
+// so bandwidth-constrained clients can request slim payloads instead of full DTOs.
+// Field names are matched in snake_case (productName and product_name both select product_name), and the keys of
+// the transformed payload are normalized to snake_case so every endpoint answers with the same casing.
+// Selection applies to records: lists are pruned item by item, and wrapper objects (e.g., {"devices": [...],
+// "total_devices": 3}) that contain none of the requested fields are kept and searched for records instead.
+// Requests without the parameter, and websocket upgrades, pass through untouched.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+func ResponseTransformMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fields := parseResponseFields(c.Query(ResponseFieldsParam))
+		if len(fields) == 0 || strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+			c.Next()
+			return
+		}
+
+		w := &responseTransformWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		var payload map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(w.body.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err != nil || payload == nil {
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+
+		if data, ok := payload["data"]; ok && data != nil {
+			payload["data"] = selectResponseFields(normalizeResponseKeys(data), fields)
+		}
+
+		transformed, err := json.Marshal(payload)
+		if err != nil {
+			utils.LogWarn("ResponseTransformMiddleware: failed to encode transformed response: %v", err)
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+		w.ResponseWriter.Write(transformed)
+	}
+}
+
+// ResponseFields returns the snake_case fields a request selects with ?fields=, sorted (empty when it selects
+// whole records).
+//
+// param c The Gin context.
+// return []string The selected fields.
+func ResponseFields(c *gin.Context) []string {
+	fields := make([]string, 0)
+	for field := range parseResponseFields(c.Query(ResponseFieldsParam)) {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// parseResponseFields splits the fields parameter into a set of snake_case field names.
+func parseResponseFields(raw string) map[string]bool {
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(raw, ",") {
+		field = strings.TrimSpace(field)
+		if field != "" {
+			fields[utils.ToSnakeCase(field)] = true
+		}
+	}
+	return fields
+}
+
+// normalizeResponseKeys converts the object keys of a decoded payload to snake_case.
+func normalizeResponseKeys(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		normalized := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized[utils.ToSnakeCase(key)] = normalizeResponseKeys(item)
+		}
+		return normalized
+	case []interface{}:
+		for i, item := range v {
+			v[i] = normalizeResponseKeys(item)
+		}
+		return v
+	default:
+		return value
+	}
+}
+
+// selectResponseFields keeps only the selected fields of every record in a decoded payload.
+// An object holding at least one selected field is treated as a record; any other object is a wrapper whose
+// values are searched for records.
+func selectResponseFields(value interface{}, fields map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		isRecord := false
+		for key := range v {
+			if fields[key] {
+				isRecord = true
+				break
+			}
+		}
+		if isRecord {
+			selected := make(map[string]interface{}, len(fields))
+			for key, item := range v {
+				if fields[key] {
+					selected[key] = item
+				}
+			}
+			return selected
+		}
+		for key, item := range v {
+			v[key] = selectResponseFields(item, fields)
+		}
+		return v
+	case []interface{}:
+		for i, item := range v {
+			v[i] = selectResponseFields(item, fields)
+		}
+		return v
+	default:
+		return value
+	}
+}
@@ -0,0 +1,164 @@
+package middlewares
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Sensitive field modes (SENSITIVE_FIELDS_MODE).
+const (
+	// SensitiveFieldsModeOff returns sensitive fields to every caller.
+	SensitiveFieldsModeOff = "off"
+	// SensitiveFieldsModeRedact strips sensitive fields unless the caller uses an admin-scoped API key.
+	SensitiveFieldsModeRedact = "redact"
+	// SensitiveFieldsModeStrict strips sensitive fields for every caller, admins included.
+	SensitiveFieldsModeStrict = "strict"
+)
+
+// sensitiveFieldsModeKey is the context key SensitiveFieldsMiddleware stores its mode under, for SensitiveFieldsRedacted.
+const sensitiveFieldsModeKey = "sensitive_fields_mode"
+
+// sensitiveFields are the (snake_case) keys removed from response data: the local encryption key, network
+// address and hardware UUID of a device, which allow controlling it on the LAN, and its geolocation.
+var sensitiveFields = map[string]bool{
+	"local_key": true,
+	"ip":        true,
+	"uuid":      true,
+	"lat":       true,
+	"lon":       true,
+	"latitude":  true,
+	"longitude": true,
+}
+
+// sensitiveFieldsWriter buffers the response body so sensitive fields can be removed before it is sent.
+type sensitiveFieldsWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+// Write captures the response body bytes.
+//
+// param b The byte slice to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *sensitiveFieldsWriter) Write(b []byte) (int, error) {
+	return w.body.Write(b)
+}
+
+// WriteString captures the response body string.
+//
+// param s The string to write.
+// return int The number of bytes written.
+// return error An error if the write fails.
+func (w *sensitiveFieldsWriter) WriteString(s string) (int, error) {
+	return w.body.WriteString(s)
+}
+
+// SensitiveFieldsMiddleware removes local_key, ip, uuid and lat/lon from the data of JSON responses, at any
+// depth, so a bearer token alone does not reveal what is needed to control devices on the LAN.
+// In redact mode, callers authenticated with an admin-scoped API key still receive the fields; in strict mode
+// nobody does; in off mode (the default) responses pass through untouched. Websocket upgrades and
+// server-sent event streams are never buffered.
+//
+// param mode The SENSITIVE_FIELDS_MODE value: off, redact or strict.
+// return gin.HandlerFunc The Gin middleware handler.
+func SensitiveFieldsMiddleware(mode string) gin.HandlerFunc {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	if mode != SensitiveFieldsModeRedact && mode != SensitiveFieldsModeStrict {
+		if mode != "" && mode != SensitiveFieldsModeOff {
+			utils.LogWarn("SensitiveFieldsMiddleware: unknown SENSITIVE_FIELDS_MODE %q, sensitive fields are not redacted", mode)
+		}
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+
+	return func(c *gin.Context) {
+		if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") || strings.Contains(c.GetHeader("Accept"), "text/event-stream") {
+			c.Next()
+			return
+		}
+
+		c.Set(sensitiveFieldsModeKey, mode)
+		w := &sensitiveFieldsWriter{body: bytes.NewBufferString(""), ResponseWriter: c.Writer}
+		c.Writer = w
+
+		c.Next()
+
+		if !SensitiveFieldsRedacted(c) {
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+		if !strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+
+		var payload map[string]interface{}
+		decoder := json.NewDecoder(bytes.NewReader(w.body.Bytes()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&payload); err != nil || payload == nil {
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+		data, ok := payload["data"]
+		if !ok || !redactSensitiveFields(data) {
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+
+		redacted, err := json.Marshal(payload)
+		if err != nil {
+			utils.LogWarn("SensitiveFieldsMiddleware: failed to encode redacted response: %v", err)
+			w.ResponseWriter.Write(w.body.Bytes())
+			return
+		}
+		w.ResponseWriter.Write(redacted)
+	}
+}
+
+// SensitiveFieldsRedacted reports whether SensitiveFieldsMiddleware strips sensitive fields from the response to
+// a request, which depends on SENSITIVE_FIELDS_MODE and, in redact mode, on the scope of the caller's API key.
+// It is only reliable once authentication has run.
+//
+// param c The Gin context.
+// return bool True if sensitive fields are removed from the response.
+func SensitiveFieldsRedacted(c *gin.Context) bool {
+	switch c.GetString(sensitiveFieldsModeKey) {
+	case SensitiveFieldsModeStrict:
+		return true
+	case SensitiveFieldsModeRedact:
+		return c.GetString("api_key_scope") != utils.APIKeyScopeAdmin
+	default:
+		return false
+	}
+}
+
+// redactSensitiveFields removes sensitive keys from decoded JSON in place and reports whether any were found.
+func redactSensitiveFields(value interface{}) bool {
+	redacted := false
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			if sensitiveFields[utils.ToSnakeCase(key)] {
+				delete(v, key)
+				redacted = true
+				continue
+			}
+			if redactSensitiveFields(item) {
+				redacted = true
+			}
+		}
+	case []interface{}:
+		for _, item := range v {
+			if redactSensitiveFields(item) {
+				redacted = true
+			}
+		}
+	}
+	return redacted
+}
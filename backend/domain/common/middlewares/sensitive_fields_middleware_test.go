@@ -0,0 +1,151 @@
+package middlewares
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// sensitiveDevice is a device list response carrying every sensitive field, nested the way hubs group remotes.
+var sensitiveDevice = gin.H{
+	"status":  true,
+	"message": "Devices fetched successfully",
+	"data": gin.H{
+		"devices": []gin.H{{
+			"id":        "hub-1",
+			"name":      "IR Hub",
+			"local_key": "secret0001",
+			"ip":        "203.0.113.10",
+			"uuid":      "uuid-hub-1",
+			"lat":       "-6.2",
+			"lon":       "106.8",
+			"status":    []gin.H{{"code": "switch_1", "value": true}},
+			"collections": []gin.H{{
+				"id":       "ac-1",
+				"localKey": "secret0002",
+				"latitude": -6.2,
+			}},
+		}},
+		"total_devices": 1,
+	},
+}
+
+// serveSensitive runs one request through SensitiveFieldsMiddleware with the given mode and API key scope.
+func serveSensitive(t *testing.T, mode, scope string, handler gin.HandlerFunc) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(SensitiveFieldsMiddleware(mode))
+	router.GET("/devices", func(c *gin.Context) {
+		if scope != "" {
+			c.Set("api_key_scope", scope)
+		}
+		handler(c)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/devices", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d", w.Code)
+	}
+	return w.Body.String()
+}
+
+func deviceListHandler(c *gin.Context) {
+	c.JSON(http.StatusOK, sensitiveDevice)
+}
+
+func assertRedacted(t *testing.T, body string) {
+	t.Helper()
+	for _, field := range []string{"local_key", "localKey", `"ip"`, "uuid", `"lat"`, `"lon"`, "latitude", "secret000", "203.0.113.10"} {
+		if strings.Contains(body, field) {
+			t.Errorf("response still contains %s: %s", field, body)
+		}
+	}
+	for _, kept := range []string{`"id":"hub-1"`, `"name":"IR Hub"`, `"code":"switch_1"`, `"id":"ac-1"`, `"total_devices":1`} {
+		if !strings.Contains(body, kept) {
+			t.Errorf("response lost %s: %s", kept, body)
+		}
+	}
+}
+
+func assertExposed(t *testing.T, body string) {
+	t.Helper()
+	for _, field := range []string{`"local_key":"secret0001"`, `"ip":"203.0.113.10"`, `"uuid":"uuid-hub-1"`, `"localKey":"secret0002"`} {
+		if !strings.Contains(body, field) {
+			t.Errorf("response is missing %s: %s", field, body)
+		}
+	}
+}
+
+func TestSensitiveFieldsOffExposesFields(t *testing.T) {
+	for _, mode := range []string{"", SensitiveFieldsModeOff, "unknown"} {
+		assertExposed(t, serveSensitive(t, mode, "", deviceListHandler))
+	}
+}
+
+func TestSensitiveFieldsRedactStripsForNonAdmins(t *testing.T) {
+	for _, scope := range []string{"", utils.APIKeyScopeReadOnly} {
+		assertRedacted(t, serveSensitive(t, SensitiveFieldsModeRedact, scope, deviceListHandler))
+	}
+}
+
+func TestSensitiveFieldsRedactKeepsFieldsForAdmins(t *testing.T) {
+	assertExposed(t, serveSensitive(t, SensitiveFieldsModeRedact, utils.APIKeyScopeAdmin, deviceListHandler))
+}
+
+func TestSensitiveFieldsStrictStripsForAdmins(t *testing.T) {
+	assertRedacted(t, serveSensitive(t, " Strict ", utils.APIKeyScopeAdmin, deviceListHandler))
+}
+
+func TestSensitiveFieldsRedactsDetailResponses(t *testing.T) {
+	body := serveSensitive(t, SensitiveFieldsModeRedact, "", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": true, "data": gin.H{"device": gin.H{"id": "plug-1", "local_key": "secret0003", "ip": "203.0.113.12"}}})
+	})
+
+	var response struct {
+		Data struct {
+			Device map[string]interface{} `json:"device"`
+		} `json:"data"`
+	}
+	if err := json.Unmarshal([]byte(body), &response); err != nil {
+		t.Fatalf("redacted response is not JSON: %v", err)
+	}
+	if len(response.Data.Device) != 1 || response.Data.Device["id"] != "plug-1" {
+		t.Fatalf("expected only the device ID to remain, got %v", response.Data.Device)
+	}
+}
+
+func TestSensitiveFieldsLeavesUnaffectedBodiesUntouched(t *testing.T) {
+	cases := map[string]gin.HandlerFunc{
+		"text": func(c *gin.Context) {
+			c.String(http.StatusOK, "local_key=secret0004")
+		},
+		"no sensitive fields": func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(`{"status":true,"data":{"id":"plug-1","value":1.50}}`))
+		},
+		"outside data": func(c *gin.Context) {
+			c.Data(http.StatusOK, "application/json; charset=utf-8", []byte(`{"status":true,"ip":"203.0.113.13"}`))
+		},
+	}
+	expected := map[string]string{
+		"text":                "local_key=secret0004",
+		"no sensitive fields": `{"status":true,"data":{"id":"plug-1","value":1.50}}`,
+		"outside data":        `{"status":true,"ip":"203.0.113.13"}`,
+	}
+
+	for name, handler := range cases {
+		t.Run(name, func(t *testing.T) {
+			if body := serveSensitive(t, SensitiveFieldsModeStrict, "", handler); body != expected[name] {
+				t.Fatalf("expected %s, got %s", expected[name], body)
+			}
+		})
+	}
+}
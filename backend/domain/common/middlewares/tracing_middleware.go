@@ -0,0 +1,41 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"teralux_app/domain/common/infrastructure/tracing"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TracingMiddleware starts a server span for every request while tracing is enabled (OTEL_EXPORTER_OTLP_ENDPOINT),
+// continuing the caller's trace from a traceparent header. Spans are named after the route, and the
+// Tuya calls and BadgerDB operations of the request become its children through the request context.
+//
+// return gin.HandlerFunc The Gin middleware handler.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !tracing.Enabled() {
+			c.Next()
+			return
+		}
+
+		ctx, span := tracing.StartServer(c.Request.Context(), c.Request.Method, c.GetHeader("traceparent"))
+		c.Request = c.Request.WithContext(ctx)
+		span.SetAttribute("http.request.method", c.Request.Method)
+		span.SetAttribute("url.path", c.Request.URL.Path)
+
+		c.Next()
+
+		if route := c.FullPath(); route != "" {
+			span.SetName(c.Request.Method + " " + route)
+			span.SetAttribute("http.route", route)
+		}
+		status := c.Writer.Status()
+		span.SetAttribute("http.response.status_code", status)
+		if status >= http.StatusInternalServerError {
+			span.RecordError(fmt.Errorf("HTTP %d", status))
+		}
+		span.End()
+	}
+}
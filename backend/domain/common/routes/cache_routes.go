@@ -2,6 +2,7 @@ package routes
 
 import (
 	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
 
 	"github.com/gin-gonic/gin"
 )
@@ -14,7 +15,51 @@ func SetupCacheRoutes(rg *gin.RouterGroup, controller *controllers.CacheControll
 	cacheGroup := rg.Group("/api/cache")
 	{
 		// DELETE /api/cache/flush
-		// Clears all data from the application cache (BadgerDB).
+		// Clears all "cache:" keys from the cache store.
 		cacheGroup.DELETE("/flush", controller.FlushCache)
 	}
-}
\ No newline at end of file
+}
+
+// SetupCacheAdminRoutes registers endpoints for inspecting and selectively invalidating stored keys and for
+// maintaining the storage.
+// Values may hold tokens and sessions, so these routes belong behind the API key.
+//
+// param router The Gin router interface.
+// param controller The controller handling cache operations.
+func SetupCacheAdminRoutes(router gin.IRouter, controller *controllers.CacheController) {
+	utils.LogDebug("SetupCacheAdminRoutes initialized")
+	api := router.Group("/api/cache")
+	{
+		// GET /api/cache/config
+		// Returns the TTL of each cached resource type.
+		api.GET("/config", controller.GetConfig)
+
+		// PUT /api/cache/config
+		// Overrides TTLs per resource type at runtime.
+		api.PUT("/config", controller.UpdateConfig)
+
+		// GET /api/cache/stats
+		// Returns storage sizes, LSM compaction state and garbage collection status.
+		api.GET("/stats", controller.GetStats)
+
+		// POST /api/cache/gc
+		// Runs value log garbage collection on demand.
+		api.POST("/gc", controller.RunGC)
+
+		// GET /api/cache/keys
+		// Lists stored keys matching ?prefix= with size and expiry.
+		api.GET("/keys", controller.ListKeys)
+
+		// GET /api/cache/keys/:key
+		// Returns the value and TTL of a key.
+		api.GET("/keys/*key", controller.GetKey)
+
+		// DELETE /api/cache/keys/:key
+		// Removes a single key.
+		api.DELETE("/keys/*key", controller.DeleteKey)
+
+		// DELETE /api/cache/prefix/:prefix
+		// Removes all keys starting with a prefix.
+		api.DELETE("/prefix/*prefix", controller.DeletePrefix)
+	}
+}
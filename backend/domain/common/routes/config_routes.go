@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupConfigRoutes registers the configuration diagnostics endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller reporting the configuration.
+func SetupConfigRoutes(router gin.IRouter, controller *controllers.ConfigController) {
+	utils.LogDebug("SetupConfigRoutes initialized")
+	api := router.Group("/api/admin/config")
+	{
+		// GET /api/admin/config
+		// Lists the settings with secrets redacted, and any configuration problems.
+		api.GET("", controller.GetConfig)
+	}
+}
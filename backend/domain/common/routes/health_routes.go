@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupHealthRoutes registers the detailed health endpoint. The plain GET /health probe is public.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller reporting the health status.
+func SetupHealthRoutes(router gin.IRouter, controller *controllers.HealthController) {
+	utils.LogDebug("SetupHealthRoutes initialized")
+	api := router.Group("/api/admin/health")
+	{
+		// GET /api/admin/health
+		// Reports the state of the database and the cache store.
+		api.GET("", controller.GetHealthDetails)
+	}
+}
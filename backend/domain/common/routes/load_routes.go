@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupLoadRoutes registers the load shedding metrics endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller reporting the load status.
+func SetupLoadRoutes(router gin.IRouter, controller *controllers.LoadController) {
+	utils.LogDebug("SetupLoadRoutes initialized")
+	api := router.Group("/api/admin/load")
+	{
+		// GET /api/admin/load
+		// Reports the load signals, thresholds and shed request counters.
+		api.GET("", controller.GetLoadStatus)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupOutboundRoutes registers the outbound destination validation endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller validating destinations.
+func SetupOutboundRoutes(router gin.IRouter, controller *controllers.OutboundController) {
+	utils.LogDebug("SetupOutboundRoutes initialized")
+	api := router.Group("/api/admin/outbound")
+	{
+		// POST /api/admin/outbound/validate
+		// Checks a webhook or MQTT broker URL against the outbound rules.
+		api.POST("/validate", controller.ValidateDestination)
+	}
+}
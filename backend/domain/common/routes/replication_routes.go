@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupReplicationRoutes registers the replication endpoints used between a primary and its standby.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling replication.
+func SetupReplicationRoutes(router gin.IRouter, controller *controllers.ReplicationController) {
+	utils.LogDebug("SetupReplicationRoutes initialized")
+	api := router.Group("/api/admin/replication")
+	{
+		// GET /api/admin/replication
+		// Returns the replication progress of this instance.
+		api.GET("", controller.GetStatus)
+
+		// POST /api/admin/replication/ship
+		// Ships the changes since the last backup immediately.
+		api.POST("/ship", controller.ShipNow)
+
+		// POST /api/admin/replication/backups
+		// Receives an incremental backup from the primary.
+		api.POST("/backups", controller.ReceiveBackup)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/controllers"
+	"teralux_app/domain/common/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSerializationRoutes registers the serialization metrics endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller reporting the serialization stats.
+func SetupSerializationRoutes(router gin.IRouter, controller *controllers.SerializationController) {
+	utils.LogDebug("SetupSerializationRoutes initialized")
+	api := router.Group("/api/admin/serialization")
+	{
+		// GET /api/admin/serialization
+		// Reports JSON encode/decode counters and payload cache reuse per hot path.
+		api.GET("", controller.GetSerializationStats)
+	}
+}
@@ -0,0 +1,51 @@
+package utils
+
+import "context"
+
+// actorKey is the context key under which the caller identity is stored.
+type actorKey struct{}
+
+// apiKeyIdentityKey is the context key under which the verified credentials of the caller are stored.
+type apiKeyIdentityKey struct{}
+
+// ContextWithActor returns a child context identifying the caller of a request.
+//
+// param ctx The parent context.
+// param actor The caller identity (e.g., "uid:abc", "session:1f2e").
+// return context.Context The context carrying the identity.
+func ContextWithActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the caller identity attached to ctx.
+//
+// param ctx The request context.
+// return string The identity, or an empty string for background work.
+func ActorFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	actor, _ := ctx.Value(actorKey{}).(string)
+	return actor
+}
+
+// ContextWithAPIKeyIdentity returns a child context carrying the verified credentials of the caller.
+//
+// param ctx The parent context.
+// param identity The API key or identity provider user behind the request.
+// return context.Context The context carrying the credentials.
+func ContextWithAPIKeyIdentity(ctx context.Context, identity APIKeyIdentity) context.Context {
+	return context.WithValue(ctx, apiKeyIdentityKey{}, identity)
+}
+
+// APIKeyIdentityFromContext returns the verified credentials of the caller attached to ctx.
+//
+// param ctx The request context.
+// return APIKeyIdentity The credentials, or an empty identity for unverified callers and background work.
+func APIKeyIdentityFromContext(ctx context.Context) APIKeyIdentity {
+	if ctx == nil {
+		return APIKeyIdentity{}
+	}
+	identity, _ := ctx.Value(apiKeyIdentityKey{}).(APIKeyIdentity)
+	return identity
+}
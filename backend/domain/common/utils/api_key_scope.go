@@ -0,0 +1,63 @@
+package utils
+
+// API key scopes, from least to most privileged. Each scope includes the ones before it.
+const (
+	// APIKeyScopeReadOnly allows reading devices and state, but no commands or changes.
+	APIKeyScopeReadOnly = "read-only"
+	// APIKeyScopeControl additionally allows device commands and other changes.
+	APIKeyScopeControl = "control"
+	// APIKeyScopeAdmin additionally allows the administrative endpoints.
+	APIKeyScopeAdmin = "admin"
+)
+
+// apiKeyScopeRank orders the scopes by privilege.
+var apiKeyScopeRank = map[string]int{
+	APIKeyScopeReadOnly: 1,
+	APIKeyScopeControl:  2,
+	APIKeyScopeAdmin:    3,
+}
+
+// IsAPIKeyScope reports whether scope is a known API key scope.
+//
+// param scope The scope to check.
+// return bool True for read-only, control and admin.
+func IsAPIKeyScope(scope string) bool {
+	_, ok := apiKeyScopeRank[scope]
+	return ok
+}
+
+// APIKeyScopeAllows reports whether a key with the granted scope may use an endpoint requiring another scope.
+//
+// param granted The scope of the presented key.
+// param required The scope the endpoint requires.
+// return bool True if granted is at least as privileged as required.
+func APIKeyScopeAllows(granted, required string) bool {
+	return apiKeyScopeRank[granted] > 0 && apiKeyScopeRank[granted] >= apiKeyScopeRank[required]
+}
+
+// APIKeyIdentity identifies the verified credentials behind a request: a managed API key (ID), the API_KEY
+// from the environment (no ID), or a user of an identity provider (Subject, with the role as scope).
+// Requests authenticated only by a raw Tuya token have no scope. Background work started by a request keeps
+// the identity, so it can be authorized again when it runs.
+type APIKeyIdentity struct {
+	ID      string `json:"api_key_id,omitempty"`
+	Scope   string `json:"scope,omitempty"`
+	Subject string `json:"subject,omitempty"`
+}
+
+// Actor identifies the client or person behind the credentials, to tell callers apart: "user:{subject}" for
+// identity provider users, "key:{id}" for managed keys and "api-key" for the API_KEY from the environment.
+//
+// return string The actor, or an empty string for unverified callers.
+func (i APIKeyIdentity) Actor() string {
+	switch {
+	case i.Subject != "":
+		return "user:" + i.Subject
+	case i.ID != "":
+		return "key:" + i.ID
+	case i.Scope != "":
+		return "api-key"
+	default:
+		return ""
+	}
+}
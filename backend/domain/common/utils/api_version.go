@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+)
+
+// APIVersionLatest is the newest API version. Unversioned /api/... paths are legacy aliases of version 1.
+const APIVersionLatest = 1
+
+// supportedAPIVersions are the API versions the server answers.
+var supportedAPIVersions = map[int]bool{1: true}
+
+// IsSupportedAPIVersion reports whether the server answers an API version.
+//
+// param version The version number.
+// return bool True if the version is supported.
+func IsSupportedAPIVersion(version int) bool {
+	return supportedAPIVersions[version]
+}
+
+// ParseAPIVersion parses a version as written in paths and headers ("1" or "v1").
+//
+// param value The version string.
+// return int The version number.
+// return bool False if the value is not a version.
+func ParseAPIVersion(value string) (int, bool) {
+	value = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(value)), "v")
+	version, err := strconv.Atoi(value)
+	if err != nil || version <= 0 {
+		return 0, false
+	}
+	return version, true
+}
+
+// SplitVersionedAPIPath splits /api/v{N}/rest into N and the legacy path /api/rest.
+//
+// param path The request path.
+// return int The version number.
+// return string The path without the version segment.
+// return bool False if the path is not a versioned API path.
+func SplitVersionedAPIPath(path string) (int, string, bool) {
+	rest, ok := strings.CutPrefix(path, "/api/v")
+	if !ok {
+		return 0, "", false
+	}
+	segment, tail, _ := strings.Cut(rest, "/")
+	version, ok := ParseAPIVersion(segment)
+	if !ok {
+		return 0, "", false
+	}
+	legacy := "/api"
+	if tail != "" {
+		legacy += "/" + tail
+	}
+	return version, legacy, true
+}
+
+// VersionedAPIPath returns the path of an unversioned /api/... path under an API version.
+//
+// param path The legacy path (e.g., /api/tuya/devices).
+// param version The version number.
+// return string The versioned path (e.g., /api/v1/tuya/devices); other paths are returned unchanged.
+func VersionedAPIPath(path string, version int) string {
+	if path != "/api" && !strings.HasPrefix(path, "/api/") {
+		return path
+	}
+	return "/api/v" + strconv.Itoa(version) + strings.TrimPrefix(path, "/api")
+}
+
+// VersionSwaggerDoc rewrites a Swagger document to describe one API version: its /api/... paths are moved
+// under /api/v{N}/ and info.version is set, so typed clients generated from it target that version.
+//
+// param doc The Swagger JSON document.
+// param version The version number.
+// return string The rewritten document, or doc itself if it cannot be parsed.
+func VersionSwaggerDoc(doc string, version int) string {
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		LogWarn("VersionSwaggerDoc: Serving the unversioned document: %v", err)
+		return doc
+	}
+
+	if paths, ok := spec["paths"].(map[string]interface{}); ok {
+		versioned := make(map[string]interface{}, len(paths))
+		for path, item := range paths {
+			versioned[VersionedAPIPath(path, version)] = item
+		}
+		spec["paths"] = versioned
+	}
+	if info, ok := spec["info"].(map[string]interface{}); ok {
+		info["version"] = strconv.Itoa(version) + ".0"
+	}
+
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return doc
+	}
+	return string(data)
+}
@@ -0,0 +1,59 @@
+package utils
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+)
+
+// Clock provides the current time. Use cases take a Clock instead of calling time.Now directly,
+// so signature timestamps, TTLs and schedules can be driven by a fixed or offset clock.
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock is the Clock backed by the system time.
+type SystemClock struct{}
+
+// NewSystemClock creates a Clock that returns the system time.
+//
+// return Clock The system clock.
+func NewSystemClock() Clock {
+	return SystemClock{}
+}
+
+// Now returns the current system time.
+//
+// return time.Time The current time.
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// IDGenerator creates unique identifiers for sessions, jobs and stored entities.
+type IDGenerator interface {
+	// NewID returns a random identifier encoded as hex, carrying size bytes of entropy.
+	NewID(size int) (string, error)
+}
+
+// RandomIDGenerator is the IDGenerator backed by crypto/rand.
+type RandomIDGenerator struct{}
+
+// NewRandomIDGenerator creates an IDGenerator that reads from crypto/rand.
+//
+// return IDGenerator The random ID generator.
+func NewRandomIDGenerator() IDGenerator {
+	return RandomIDGenerator{}
+}
+
+// NewID returns a random hex identifier.
+//
+// param size The number of random bytes.
+// return string The hex-encoded identifier.
+// return error An error if the system random source fails.
+func (RandomIDGenerator) NewID(size int) (string, error) {
+	randomBytes := make([]byte, size)
+	if _, err := rand.Read(randomBytes); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(randomBytes), nil
+}
@@ -1,60 +1,356 @@
 package utils
 
 import (
-	"log"
 	"os"
-
-	"github.com/joho/godotenv"
+	"strings"
+	"sync"
+	"sync/atomic"
 )
 
 // Config holds the application's configuration parameters.
 // These are loaded from environment variables or a .env file.
 type Config struct {
-	TuyaClientID              string
-	TuyaClientSecret          string
-	TuyaBaseURL               string
-	TuyaUserID                string
-	ApiKey                    string
-	SwaggerBaseURL            string
-	GetAllDevicesResponseType string
-	CacheTTL                  string
+	TuyaClientID                string
+	TuyaClientSecret            string
+	TuyaBaseURL                 string
+	TuyaUserID                  string
+	TuyaProjectType             string
+	ApiKey                      string
+	SetupToken                  string
+	SwaggerBaseURL              string
+	GetAllDevicesResponseType   string
+	TuyaDeviceListPaging        bool
+	DeviceCategoryAllow         string
+	DeviceCategoryDeny          string
+	CacheTTL                    string
+	CacheTTLDeviceList          string
+	CacheTTLDeviceDetail        string
+	CacheTTLSpecification       string
+	CacheTTLSensor              string
+	BadgerGCInterval            string
+	BadgerGCDiscardRatio        string
+	CacheBackend                string
+	RedisURL                    string
+	RedisPoolSize               string
+	PersistenceBackend          string
+	DBMigrateOnStart            bool
+	TuyaUIDAllowlist            map[string][]string
+	DeviceClaimsEnabled         bool
+	AuthSessionMode             bool
+	SessionTTL                  string
+	JWTSecret                   string
+	OIDCIssuer                  string
+	OIDCClientID                string
+	OIDCGroupsClaim             string
+	LDAPURL                     string
+	LDAPBindDN                  string
+	LDAPBindPassword            string
+	LDAPBaseDN                  string
+	LDAPUserFilter              string
+	LDAPGroupAttribute          string
+	IdentityGroupRoles          string
+	IdentityTokenTTL            string
+	ServerManagedToken          bool
+	JobWorkers                  string
+	JobRetention                string
+	CommandQueueMaxAttempts     string
+	CircadianInterval           string
+	CircadianOverrideDuration   string
+	StandbyKillerInterval       string
+	ACRatedWatts                string
+	RestoreOnBootDevices        string
+	RestoreOnBootDelay          string
+	DeviceStateHistorySize      string
+	TuyaPulsarURL               string
+	TuyaPulsarEnv               string
+	TuyaHTTPTimeout             string
+	TuyaCommandTimeout          string
+	TuyaListTimeout             string
+	TuyaSpecConcurrency         string
+	SensorPollInterval          string
+	SensorSampleInterval        string
+	HouseModePollIntervals      string
+	FeatureFlags                string
+	ReplicationTarget           string
+	ReplicationInterval         string
+	ReplicationSpoolDir         string
+	ReplicationRestoreOnStart   bool
+	ArchiveS3Endpoint           string
+	ArchiveS3Region             string
+	ArchiveS3Bucket             string
+	ArchiveS3AccessKey          string
+	ArchiveS3SecretKey          string
+	ArchiveInterval             string
+	ArchiveLocalRetention       string
+	ArchiveTransitionDays       string
+	ArchiveStorageClass         string
+	ArchiveExpirationDays       string
+	ShutdownTimeout             string
+	IntegrationTestMode         bool
+	Timezone                    string
+	SocketIOEventName           string
+	RealtimeAllowedOrigins      string
+	TuyaQuotaDailyBudget        string
+	TuyaQuotaEndpointBudgets    string
+	TuyaPermissionCheckInterval string
+	OutboundAllowlist           string
+	OutboundAllowPrivate        bool
+	CommandApprovalTTL          string
+	CommandCooldownMax          string
+	IRDedupWindow               string
+	WebhookMaxAttempts          string
+	WebhookTimeout              string
+	NotificationCheckInterval   string
+	NotificationMaxAttempts     string
+	SMTPHost                    string
+	SMTPPort                    string
+	SMTPUsername                string
+	SMTPPassword                string
+	SMTPFrom                    string
+	TelegramBotToken            string
+	TelegramAPIURL              string
+	MQTTBroker                  string
+	MQTTUsername                string
+	MQTTPassword                string
+	MQTTClientID                string
+	MQTTDiscoveryPrefix         string
+	MQTTBaseTopic               string
+	MQTTSyncInterval            string
+	LoadShedMaxInFlight         string
+	LoadShedMaxQueueDepth       string
+	LoadShedMaxUpstreamLatency  string
+	LoadShedRetryAfter          string
+	LoadShedRoutes              string
+	SensitiveFieldsMode         string
+	HTTPDialTimeout             string
+	HTTPTLSHandshakeTimeout     string
+	HTTPIdleConnTimeout         string
+	HTTPMaxIdleConns            string
+	HTTPMaxIdleConnsPerHost     string
+	HTTPProxyURL                string
+	HTTPRetryMaxAttempts        string
+	HTTPRetryBackoff            string
+	HTTPRetryMaxBackoff         string
+	OTelEndpoint                string
+	OTelHeaders                 string
+	OTelServiceName             string
+	OTelSamplerArg              string
 }
 
-// AppConfig is the global configuration instance.
+// AppConfig is the configuration loaded at startup. Settings changed at runtime (see UpdateConfig) are only
+// visible through GetConfig.
 var AppConfig *Config
 
+// currentConfig is the configuration returned by GetConfig; it is replaced as a whole, never mutated.
+var currentConfig atomic.Pointer[Config]
+
+// configUpdateMu serializes UpdateConfig, so concurrent updates do not drop each other's changes.
+var configUpdateMu sync.Mutex
+
 // LoadConfig initializes the AppConfig by loading variables from the environment.
-// It searches for a .env file in the current and parent directories if not already set.
+// Variables not already set are read from a .env file, then from a config.yaml file (see loadConfigFiles).
+// The values are not checked here; main calls ValidateConfig to fail fast on an invalid configuration.
 // It also triggers an update of the log level based on the loaded configuration.
 func LoadConfig() {
-	envPath := findEnvFile()
-	if envPath == "" {
-		log.Println("Warning: .env file not found")
-	} else {
-		if err := godotenv.Load(envPath); err != nil {
-			log.Println("Warning: Error loading .env file")
-		}
-	}
+	loadConfigFiles()
 
 	AppConfig = &Config{
-		TuyaClientID:              os.Getenv("TUYA_CLIENT_ID"),
-		TuyaClientSecret:          os.Getenv("TUYA_ACCESS_SECRET"),
-		TuyaBaseURL:               os.Getenv("TUYA_BASE_URL"),
-		TuyaUserID:                os.Getenv("TUYA_USER_ID"),
-		ApiKey:                    os.Getenv("API_KEY"),
-		SwaggerBaseURL:            os.Getenv("SWAGGER_BASE_URL"),
-		GetAllDevicesResponseType: os.Getenv("GET_ALL_DEVICES_RESPONSE"),
-		CacheTTL:                  os.Getenv("CACHE_TTL"),
+		TuyaClientID:                os.Getenv("TUYA_CLIENT_ID"),
+		TuyaClientSecret:            os.Getenv("TUYA_ACCESS_SECRET"),
+		TuyaBaseURL:                 os.Getenv("TUYA_BASE_URL"),
+		TuyaUserID:                  os.Getenv("TUYA_USER_ID"),
+		TuyaProjectType:             os.Getenv("TUYA_PROJECT_TYPE"),
+		ApiKey:                      os.Getenv("API_KEY"),
+		SetupToken:                  os.Getenv("SETUP_TOKEN"),
+		SwaggerBaseURL:              os.Getenv("SWAGGER_BASE_URL"),
+		GetAllDevicesResponseType:   os.Getenv("GET_ALL_DEVICES_RESPONSE"),
+		TuyaDeviceListPaging:        os.Getenv("TUYA_DEVICE_LIST_PAGING") == "true",
+		DeviceCategoryAllow:         os.Getenv("DEVICE_CATEGORY_ALLOW"),
+		DeviceCategoryDeny:          os.Getenv("DEVICE_CATEGORY_DENY"),
+		CacheTTL:                    os.Getenv("CACHE_TTL"),
+		CacheTTLDeviceList:          os.Getenv("CACHE_TTL_DEVICE_LIST"),
+		CacheTTLDeviceDetail:        os.Getenv("CACHE_TTL_DEVICE_DETAIL"),
+		CacheTTLSpecification:       os.Getenv("CACHE_TTL_SPECIFICATION"),
+		CacheTTLSensor:              os.Getenv("CACHE_TTL_SENSOR"),
+		BadgerGCInterval:            os.Getenv("BADGER_GC_INTERVAL"),
+		BadgerGCDiscardRatio:        os.Getenv("BADGER_GC_DISCARD_RATIO"),
+		CacheBackend:                os.Getenv("CACHE_BACKEND"),
+		RedisURL:                    os.Getenv("REDIS_URL"),
+		RedisPoolSize:               os.Getenv("REDIS_POOL_SIZE"),
+		PersistenceBackend:          os.Getenv("PERSISTENCE_BACKEND"),
+		DBMigrateOnStart:            os.Getenv("DB_MIGRATE_ON_START") != "false",
+		TuyaUIDAllowlist:            parseUIDAllowlist(os.Getenv("TUYA_UID_ALLOWLIST")),
+		DeviceClaimsEnabled:         os.Getenv("DEVICE_CLAIMS_ENABLED") == "true",
+		AuthSessionMode:             os.Getenv("AUTH_SESSION_MODE") == "true",
+		SessionTTL:                  os.Getenv("SESSION_TTL"),
+		JWTSecret:                   os.Getenv("JWT_SECRET"),
+		OIDCIssuer:                  os.Getenv("OIDC_ISSUER"),
+		OIDCClientID:                os.Getenv("OIDC_CLIENT_ID"),
+		OIDCGroupsClaim:             os.Getenv("OIDC_GROUPS_CLAIM"),
+		LDAPURL:                     os.Getenv("LDAP_URL"),
+		LDAPBindDN:                  os.Getenv("LDAP_BIND_DN"),
+		LDAPBindPassword:            os.Getenv("LDAP_BIND_PASSWORD"),
+		LDAPBaseDN:                  os.Getenv("LDAP_BASE_DN"),
+		LDAPUserFilter:              os.Getenv("LDAP_USER_FILTER"),
+		LDAPGroupAttribute:          os.Getenv("LDAP_GROUP_ATTRIBUTE"),
+		IdentityGroupRoles:          os.Getenv("IDENTITY_GROUP_ROLES"),
+		IdentityTokenTTL:            os.Getenv("IDENTITY_TOKEN_TTL"),
+		ServerManagedToken:          os.Getenv("SERVER_MANAGED_TOKEN") == "true",
+		JobWorkers:                  os.Getenv("JOB_WORKERS"),
+		JobRetention:                os.Getenv("JOB_RETENTION"),
+		CommandQueueMaxAttempts:     os.Getenv("COMMAND_QUEUE_MAX_ATTEMPTS"),
+		CircadianInterval:           os.Getenv("CIRCADIAN_INTERVAL"),
+		CircadianOverrideDuration:   os.Getenv("CIRCADIAN_OVERRIDE_DURATION"),
+		StandbyKillerInterval:       os.Getenv("STANDBY_KILLER_INTERVAL"),
+		ACRatedWatts:                os.Getenv("AC_RATED_WATTS"),
+		RestoreOnBootDevices:        os.Getenv("RESTORE_ON_BOOT_DEVICES"),
+		RestoreOnBootDelay:          os.Getenv("RESTORE_ON_BOOT_DELAY"),
+		DeviceStateHistorySize:      os.Getenv("DEVICE_STATE_HISTORY_SIZE"),
+		TuyaPulsarURL:               os.Getenv("TUYA_PULSAR_URL"),
+		TuyaPulsarEnv:               os.Getenv("TUYA_PULSAR_ENV"),
+		TuyaHTTPTimeout:             os.Getenv("TUYA_HTTP_TIMEOUT"),
+		TuyaCommandTimeout:          os.Getenv("TUYA_COMMAND_TIMEOUT"),
+		TuyaListTimeout:             os.Getenv("TUYA_LIST_TIMEOUT"),
+		TuyaSpecConcurrency:         os.Getenv("TUYA_SPEC_CONCURRENCY"),
+		SensorPollInterval:          os.Getenv("SENSOR_POLL_INTERVAL"),
+		SensorSampleInterval:        os.Getenv("SENSOR_SAMPLE_INTERVAL"),
+		HouseModePollIntervals:      os.Getenv("HOUSE_MODE_POLL_INTERVALS"),
+		FeatureFlags:                os.Getenv("FEATURE_FLAGS"),
+		ReplicationTarget:           os.Getenv("REPLICATION_TARGET"),
+		ReplicationInterval:         os.Getenv("REPLICATION_INTERVAL"),
+		ReplicationSpoolDir:         os.Getenv("REPLICATION_SPOOL_DIR"),
+		ReplicationRestoreOnStart:   os.Getenv("REPLICATION_RESTORE_ON_START") == "true",
+		ArchiveS3Endpoint:           os.Getenv("ARCHIVE_S3_ENDPOINT"),
+		ArchiveS3Region:             os.Getenv("ARCHIVE_S3_REGION"),
+		ArchiveS3Bucket:             os.Getenv("ARCHIVE_S3_BUCKET"),
+		ArchiveS3AccessKey:          os.Getenv("ARCHIVE_S3_ACCESS_KEY"),
+		ArchiveS3SecretKey:          os.Getenv("ARCHIVE_S3_SECRET_KEY"),
+		ArchiveInterval:             os.Getenv("ARCHIVE_INTERVAL"),
+		ArchiveLocalRetention:       os.Getenv("ARCHIVE_LOCAL_RETENTION"),
+		ArchiveTransitionDays:       os.Getenv("ARCHIVE_TRANSITION_DAYS"),
+		ArchiveStorageClass:         os.Getenv("ARCHIVE_STORAGE_CLASS"),
+		ArchiveExpirationDays:       os.Getenv("ARCHIVE_EXPIRATION_DAYS"),
+		ShutdownTimeout:             os.Getenv("SHUTDOWN_TIMEOUT"),
+		IntegrationTestMode:         IntegrationTestModeAvailable && os.Getenv("INTEGRATION_TEST_MODE") == "true",
+		Timezone:                    os.Getenv("TIMEZONE"),
+		SocketIOEventName:           os.Getenv("SOCKETIO_EVENT_NAME"),
+		RealtimeAllowedOrigins:      os.Getenv("REALTIME_ALLOWED_ORIGINS"),
+		TuyaQuotaDailyBudget:        os.Getenv("TUYA_QUOTA_DAILY_BUDGET"),
+		TuyaQuotaEndpointBudgets:    os.Getenv("TUYA_QUOTA_ENDPOINT_BUDGETS"),
+		TuyaPermissionCheckInterval: os.Getenv("TUYA_PERMISSION_CHECK_INTERVAL"),
+		OutboundAllowlist:           os.Getenv("OUTBOUND_ALLOWLIST"),
+		OutboundAllowPrivate:        os.Getenv("OUTBOUND_ALLOW_PRIVATE") == "true",
+		CommandApprovalTTL:          os.Getenv("COMMAND_APPROVAL_TTL"),
+		CommandCooldownMax:          os.Getenv("COMMAND_COOLDOWN_MAX"),
+		IRDedupWindow:               os.Getenv("IR_DEDUP_WINDOW"),
+		WebhookMaxAttempts:          os.Getenv("WEBHOOK_MAX_ATTEMPTS"),
+		WebhookTimeout:              os.Getenv("WEBHOOK_TIMEOUT"),
+		NotificationCheckInterval:   os.Getenv("NOTIFICATION_CHECK_INTERVAL"),
+		NotificationMaxAttempts:     os.Getenv("NOTIFICATION_MAX_ATTEMPTS"),
+		SMTPHost:                    os.Getenv("SMTP_HOST"),
+		SMTPPort:                    os.Getenv("SMTP_PORT"),
+		SMTPUsername:                os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:                os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:                    os.Getenv("SMTP_FROM"),
+		TelegramBotToken:            os.Getenv("TELEGRAM_BOT_TOKEN"),
+		TelegramAPIURL:              os.Getenv("TELEGRAM_API_URL"),
+		MQTTBroker:                  os.Getenv("MQTT_BROKER"),
+		MQTTUsername:                os.Getenv("MQTT_USERNAME"),
+		MQTTPassword:                os.Getenv("MQTT_PASSWORD"),
+		MQTTClientID:                os.Getenv("MQTT_CLIENT_ID"),
+		MQTTDiscoveryPrefix:         os.Getenv("MQTT_DISCOVERY_PREFIX"),
+		MQTTBaseTopic:               os.Getenv("MQTT_BASE_TOPIC"),
+		MQTTSyncInterval:            os.Getenv("MQTT_SYNC_INTERVAL"),
+		LoadShedMaxInFlight:         os.Getenv("LOAD_SHED_MAX_IN_FLIGHT"),
+		LoadShedMaxQueueDepth:       os.Getenv("LOAD_SHED_MAX_QUEUE_DEPTH"),
+		LoadShedMaxUpstreamLatency:  os.Getenv("LOAD_SHED_MAX_UPSTREAM_LATENCY"),
+		LoadShedRetryAfter:          os.Getenv("LOAD_SHED_RETRY_AFTER"),
+		LoadShedRoutes:              os.Getenv("LOAD_SHED_ROUTES"),
+		SensitiveFieldsMode:         os.Getenv("SENSITIVE_FIELDS_MODE"),
+		HTTPDialTimeout:             os.Getenv("HTTP_CLIENT_DIAL_TIMEOUT"),
+		HTTPTLSHandshakeTimeout:     os.Getenv("HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT"),
+		HTTPIdleConnTimeout:         os.Getenv("HTTP_CLIENT_IDLE_CONN_TIMEOUT"),
+		HTTPMaxIdleConns:            os.Getenv("HTTP_CLIENT_MAX_IDLE_CONNS"),
+		HTTPMaxIdleConnsPerHost:     os.Getenv("HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST"),
+		HTTPProxyURL:                os.Getenv("HTTP_CLIENT_PROXY_URL"),
+		HTTPRetryMaxAttempts:        os.Getenv("HTTP_CLIENT_RETRY_MAX_ATTEMPTS"),
+		HTTPRetryBackoff:            os.Getenv("HTTP_CLIENT_RETRY_BACKOFF"),
+		HTTPRetryMaxBackoff:         os.Getenv("HTTP_CLIENT_RETRY_MAX_BACKOFF"),
+		OTelEndpoint:                os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT"),
+		OTelHeaders:                 os.Getenv("OTEL_EXPORTER_OTLP_HEADERS"),
+		OTelServiceName:             os.Getenv("OTEL_SERVICE_NAME"),
+		OTelSamplerArg:              os.Getenv("OTEL_TRACES_SAMPLER_ARG"),
 	}
+	currentConfig.Store(AppConfig)
 
 	UpdateLogLevel()
 }
 
+// parseUIDAllowlist parses the TUYA_UID_ALLOWLIST value into a map of caller to allowed Tuya UIDs.
+// Format: "<caller>=<uid>|<uid>;<caller>=<uid>", where a caller is an actor (see APIKeyIdentity.Actor) or
+// the API_KEY value. Malformed entries are skipped.
+//
+// param raw The raw environment value.
+// return map[string][]string The allowed UIDs keyed by caller.
+func parseUIDAllowlist(raw string) map[string][]string {
+	allowlist := make(map[string][]string)
+	for _, entry := range strings.Split(raw, ";") {
+		parts := strings.SplitN(strings.TrimSpace(entry), "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			continue
+		}
+		for _, uid := range strings.Split(parts[1], "|") {
+			if uid = strings.TrimSpace(uid); uid != "" {
+				allowlist[parts[0]] = append(allowlist[parts[0]], uid)
+			}
+		}
+	}
+	return allowlist
+}
+
+// IsUIDAllowed reports whether the caller may act on behalf of a Tuya UID. Callers are looked up by their
+// actor ("key:{id}" for managed keys, "user:{subject}" for identity provider users, "api-key" for the API_KEY),
+// so the same identity is restricted whether it sends its key, a session token or a websocket ticket.
+// The API_KEY may also be listed by its value. The default TUYA_USER_ID is always allowed.
+//
+// param identity The verified API key or identity of the caller.
+// param uid The requested Tuya UID.
+// return bool True if the UID override is permitted.
+func (c *Config) IsUIDAllowed(identity APIKeyIdentity, uid string) bool {
+	if uid == c.TuyaUserID {
+		return true
+	}
+	actor := identity.Actor()
+	if actor == "" {
+		return false
+	}
+	callers := []string{actor}
+	if actor == "api-key" && c.ApiKey != "" {
+		callers = append(callers, c.ApiKey)
+	}
+	for _, caller := range callers {
+		for _, allowed := range c.TuyaUIDAllowlist[caller] {
+			if allowed == uid {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // findEnvFile searches for the .env file in the current directory and up to three parent levels.
 //
 // return string The path to the .env file if found, otherwise an empty string.
 func findEnvFile() string {
-	path := ".env"
+	return findFile(".env")
+}
+
+// findFile searches for a file in the current directory and up to three parent levels.
+//
+// param name The file name.
+// return string The path to the file if found, otherwise an empty string.
+func findFile(name string) string {
+	path := name
 	if _, err := os.Stat(path); err == nil {
 		return path
 	}
@@ -69,13 +365,29 @@ func findEnvFile() string {
 	return ""
 }
 
-// GetConfig returns the singleton AppConfig instance.
-// If the config hasn't been loaded, it triggers LoadConfig first.
+// GetConfig returns the current configuration.
+// If the config hasn't been loaded, it triggers LoadConfig first. The returned Config must not be modified;
+// use UpdateConfig instead.
 //
 // return *Config The global configuration object.
 func GetConfig() *Config {
-	if AppConfig == nil {
-		LoadConfig()
+	if config := currentConfig.Load(); config != nil {
+		return config
 	}
-	return AppConfig
+	LoadConfig()
+	return currentConfig.Load()
+}
+
+// UpdateConfig changes settings of the running server. The change is applied to a copy of the current
+// configuration, which then replaces it atomically, so requests reading the configuration meanwhile see
+// either the old or the new settings, never a mix.
+//
+// param mutate The function changing the copy.
+func UpdateConfig(mutate func(config *Config)) {
+	configUpdateMu.Lock()
+	defer configUpdateMu.Unlock()
+
+	next := *GetConfig()
+	mutate(&next)
+	currentConfig.Store(&next)
 }
\ No newline at end of file
@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/joho/godotenv"
+	"go.yaml.in/yaml/v3"
+)
+
+// defaultConfigFile is the YAML alternative to the .env file, searched like the .env file.
+const defaultConfigFile = "config.yaml"
+
+// configLoadProblems are the errors of the last loadConfigFiles, reported by ValidateConfig.
+var configLoadProblems []string
+
+// loadConfigFiles sets the variables that are not in the environment from the .env file, then from the
+// YAML file named by CONFIG_FILE (default: config.yaml, if present), and records where each setting was read
+// from. The environment wins over .env, which wins over the YAML file.
+//
+// The YAML file is a flat mapping of the variable names (case-insensitive) to scalar values:
+//
+//	tuya_client_id: abc123
+//	cache_ttl: 1h
+//	device_claims_enabled: true
+func loadConfigFiles() {
+	configLoadProblems = nil
+	sources := make(map[string]string)
+	for _, setting := range ConfigSettings {
+		if _, ok := os.LookupEnv(setting.Name); ok {
+			// Settings applied from a file by an earlier load are in the environment now
+			source := configSources[setting.Name]
+			if source == "" {
+				source = ConfigSourceEnv
+			}
+			sources[setting.Name] = source
+		}
+	}
+
+	envPath := findEnvFile()
+	if envPath == "" {
+		log.Println("Warning: .env file not found")
+	} else if values, err := godotenv.Read(envPath); err != nil {
+		log.Println("Warning: Error loading .env file")
+	} else {
+		applyConfigValues(values, ConfigSourceDotEnv, sources)
+	}
+
+	yamlPath := os.Getenv("CONFIG_FILE")
+	if yamlPath == "" {
+		yamlPath = findFile(defaultConfigFile)
+	} else if _, err := os.Stat(yamlPath); err != nil {
+		configLoadProblems = append(configLoadProblems, fmt.Sprintf("CONFIG_FILE: %v", err))
+		yamlPath = ""
+	}
+	if yamlPath != "" {
+		values, err := readConfigYAML(yamlPath)
+		if err != nil {
+			configLoadProblems = append(configLoadProblems, err.Error())
+		} else {
+			applyConfigValues(values, ConfigSourceYAML, sources)
+		}
+	}
+
+	configSources = sources
+}
+
+// applyConfigValues sets the variables that are not set yet.
+func applyConfigValues(values map[string]string, source string, sources map[string]string) {
+	for name, value := range values {
+		if _, ok := os.LookupEnv(name); ok {
+			continue
+		}
+		if err := os.Setenv(name, value); err != nil {
+			log.Printf("Warning: Failed to set %s from %s: %v", name, source, err)
+			continue
+		}
+		sources[name] = source
+	}
+}
+
+// readConfigYAML reads a flat YAML mapping of variable names to scalar values.
+// Names are upper-cased; unknown names are kept but logged, since they are most likely typos.
+//
+// param path The YAML file.
+// return map[string]string The values keyed by variable name.
+// return error An error if the file cannot be read, is not a mapping, or holds a non-scalar value.
+func readConfigYAML(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	values := make(map[string]string, len(raw))
+	var unknown []string
+	for key, value := range raw {
+		name := strings.ToUpper(strings.TrimSpace(key))
+		switch value.(type) {
+		case nil:
+			values[name] = ""
+		case map[string]interface{}, []interface{}:
+			return nil, fmt.Errorf("%s: %s must be a single value", path, key)
+		default:
+			values[name] = fmt.Sprint(value)
+		}
+		if _, ok := lookupConfigSetting(name); !ok {
+			unknown = append(unknown, key)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		log.Printf("Warning: %s has unknown settings: %s", path, strings.Join(unknown, ", "))
+	}
+	return values, nil
+}
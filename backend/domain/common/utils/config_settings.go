@@ -0,0 +1,338 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigKind is the type a setting's value must parse as.
+type ConfigKind string
+
+const (
+	ConfigKindString   ConfigKind = "string"
+	ConfigKindDuration ConfigKind = "duration" // Go duration, e.g. 30s, 5m, 1h30m
+	ConfigKindInt      ConfigKind = "int"
+	ConfigKindFloat    ConfigKind = "float"
+	ConfigKindBool     ConfigKind = "bool" // true or false
+	ConfigKindURL      ConfigKind = "url"  // Absolute URL with a scheme and (except file://) a host
+	ConfigKindTimezone ConfigKind = "timezone"
+)
+
+// Sources a setting can be read from, in order of precedence.
+const (
+	ConfigSourceEnv    = "environment"
+	ConfigSourceDotEnv = ".env"
+	ConfigSourceYAML   = "config.yaml"
+)
+
+// redactedValue replaces the value of secret settings in diagnostics.
+const redactedValue = "********"
+
+// ConfigSetting describes one environment variable read by LoadConfig.
+type ConfigSetting struct {
+	Name       string
+	Kind       ConfigKind
+	Secret     bool     // Never shown in diagnostics
+	Required   bool     // Must be set once setup is completed
+	Values     []string // Allowed values; empty allows any value of Kind
+	IgnoreCase bool     // Values are compared case-insensitively
+}
+
+// ConfigSettings lists every setting with its type. Unset settings use the default of the code reading them.
+var ConfigSettings = []ConfigSetting{
+	{Name: "CONFIG_FILE", Kind: ConfigKindString},
+	{Name: "TUYA_CLIENT_ID", Kind: ConfigKindString, Required: true},
+	{Name: "TUYA_ACCESS_SECRET", Kind: ConfigKindString, Secret: true, Required: true},
+	{Name: "TUYA_BASE_URL", Kind: ConfigKindURL, Required: true},
+	{Name: "TUYA_USER_ID", Kind: ConfigKindString},
+	{Name: "TUYA_PROJECT_TYPE", Kind: ConfigKindString, Values: []string{"auto", "smart_home", "industry"}, IgnoreCase: true},
+	{Name: "TUYA_UID_ALLOWLIST", Kind: ConfigKindString, Secret: true},
+	{Name: "DEVICE_CLAIMS_ENABLED", Kind: ConfigKindBool},
+	{Name: "TUYA_PULSAR_URL", Kind: ConfigKindURL},
+	{Name: "TUYA_PULSAR_ENV", Kind: ConfigKindString, Values: []string{"event", "event-test"}},
+	{Name: "TUYA_HTTP_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "TUYA_COMMAND_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "TUYA_LIST_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "TUYA_SPEC_CONCURRENCY", Kind: ConfigKindInt},
+	{Name: "TUYA_QUOTA_DAILY_BUDGET", Kind: ConfigKindInt},
+	{Name: "TUYA_QUOTA_ENDPOINT_BUDGETS", Kind: ConfigKindString},
+	{Name: "TUYA_PERMISSION_CHECK_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "TUYA_DEVICE_LIST_PAGING", Kind: ConfigKindBool},
+	{Name: "SENSITIVE_FIELDS_MODE", Kind: ConfigKindString, Values: []string{"off", "redact", "strict"}},
+	{Name: "API_KEY", Kind: ConfigKindString, Secret: true},
+	{Name: "SETUP_TOKEN", Kind: ConfigKindString, Secret: true},
+	{Name: "AUTH_SESSION_MODE", Kind: ConfigKindBool},
+	{Name: "SESSION_TTL", Kind: ConfigKindDuration},
+	{Name: "SERVER_MANAGED_TOKEN", Kind: ConfigKindBool},
+	{Name: "JWT_SECRET", Kind: ConfigKindString, Secret: true},
+	{Name: "OIDC_ISSUER", Kind: ConfigKindURL},
+	{Name: "OIDC_CLIENT_ID", Kind: ConfigKindString},
+	{Name: "OIDC_GROUPS_CLAIM", Kind: ConfigKindString},
+	{Name: "LDAP_URL", Kind: ConfigKindURL},
+	{Name: "LDAP_BIND_DN", Kind: ConfigKindString},
+	{Name: "LDAP_BIND_PASSWORD", Kind: ConfigKindString, Secret: true},
+	{Name: "LDAP_BASE_DN", Kind: ConfigKindString},
+	{Name: "LDAP_USER_FILTER", Kind: ConfigKindString},
+	{Name: "LDAP_GROUP_ATTRIBUTE", Kind: ConfigKindString},
+	{Name: "IDENTITY_GROUP_ROLES", Kind: ConfigKindString},
+	{Name: "IDENTITY_TOKEN_TTL", Kind: ConfigKindDuration},
+	{Name: "SWAGGER_BASE_URL", Kind: ConfigKindURL},
+	{Name: "TIMEZONE", Kind: ConfigKindTimezone},
+	{Name: "SOCKETIO_EVENT_NAME", Kind: ConfigKindString},
+	{Name: "REALTIME_ALLOWED_ORIGINS", Kind: ConfigKindString},
+	{Name: "FEATURE_FLAGS", Kind: ConfigKindString},
+	{Name: "INTEGRATION_TEST_MODE", Kind: ConfigKindBool},
+	{Name: "SHUTDOWN_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "LOG_LEVEL", Kind: ConfigKindString, Values: []string{"DEBUG", "INFO", "WARN", "ERROR"}, IgnoreCase: true},
+	{Name: "LOG_FORMAT", Kind: ConfigKindString, Values: []string{LogFormatText, LogFormatJSON}, IgnoreCase: true},
+	{Name: "OTEL_EXPORTER_OTLP_ENDPOINT", Kind: ConfigKindURL},
+	{Name: "OTEL_EXPORTER_OTLP_HEADERS", Kind: ConfigKindString, Secret: true},
+	{Name: "OTEL_SERVICE_NAME", Kind: ConfigKindString},
+	{Name: "OTEL_TRACES_SAMPLER_ARG", Kind: ConfigKindFloat},
+	{Name: "GET_ALL_DEVICES_RESPONSE", Kind: ConfigKindString, Values: []string{"0", "1", "2"}},
+	{Name: "DEVICE_CATEGORY_ALLOW", Kind: ConfigKindString},
+	{Name: "DEVICE_CATEGORY_DENY", Kind: ConfigKindString},
+	{Name: "CACHE_TTL", Kind: ConfigKindDuration},
+	{Name: "CACHE_TTL_DEVICE_LIST", Kind: ConfigKindDuration},
+	{Name: "CACHE_TTL_DEVICE_DETAIL", Kind: ConfigKindDuration},
+	{Name: "CACHE_TTL_SPECIFICATION", Kind: ConfigKindDuration},
+	{Name: "CACHE_TTL_SENSOR", Kind: ConfigKindDuration},
+	{Name: "CACHE_BACKEND", Kind: ConfigKindString, Values: []string{"badger", "redis"}, IgnoreCase: true},
+	{Name: "REDIS_URL", Kind: ConfigKindURL},
+	{Name: "REDIS_POOL_SIZE", Kind: ConfigKindInt},
+	{Name: "PERSISTENCE_BACKEND", Kind: ConfigKindString, Values: []string{"cache", "sql"}, IgnoreCase: true},
+	{Name: "BADGER_GC_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "BADGER_GC_DISCARD_RATIO", Kind: ConfigKindFloat},
+	{Name: "COMMAND_APPROVAL_TTL", Kind: ConfigKindDuration},
+	{Name: "COMMAND_COOLDOWN_MAX", Kind: ConfigKindDuration},
+	{Name: "IR_DEDUP_WINDOW", Kind: ConfigKindDuration},
+	{Name: "JOB_WORKERS", Kind: ConfigKindInt},
+	{Name: "JOB_RETENTION", Kind: ConfigKindDuration},
+	{Name: "COMMAND_QUEUE_MAX_ATTEMPTS", Kind: ConfigKindInt},
+	{Name: "CIRCADIAN_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "CIRCADIAN_OVERRIDE_DURATION", Kind: ConfigKindDuration},
+	{Name: "STANDBY_KILLER_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "AC_RATED_WATTS", Kind: ConfigKindFloat},
+	{Name: "RESTORE_ON_BOOT_DEVICES", Kind: ConfigKindString},
+	{Name: "RESTORE_ON_BOOT_DELAY", Kind: ConfigKindDuration},
+	{Name: "DEVICE_STATE_HISTORY_SIZE", Kind: ConfigKindInt},
+	{Name: "SENSOR_POLL_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "SENSOR_SAMPLE_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "HOUSE_MODE_POLL_INTERVALS", Kind: ConfigKindString},
+	{Name: "REPLICATION_TARGET", Kind: ConfigKindURL},
+	{Name: "REPLICATION_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "REPLICATION_SPOOL_DIR", Kind: ConfigKindString},
+	{Name: "REPLICATION_RESTORE_ON_START", Kind: ConfigKindBool},
+	{Name: "ARCHIVE_S3_ENDPOINT", Kind: ConfigKindURL},
+	{Name: "ARCHIVE_S3_REGION", Kind: ConfigKindString},
+	{Name: "ARCHIVE_S3_BUCKET", Kind: ConfigKindString},
+	{Name: "ARCHIVE_S3_ACCESS_KEY", Kind: ConfigKindString, Secret: true},
+	{Name: "ARCHIVE_S3_SECRET_KEY", Kind: ConfigKindString, Secret: true},
+	{Name: "ARCHIVE_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "ARCHIVE_LOCAL_RETENTION", Kind: ConfigKindDuration},
+	{Name: "ARCHIVE_TRANSITION_DAYS", Kind: ConfigKindInt},
+	{Name: "ARCHIVE_STORAGE_CLASS", Kind: ConfigKindString},
+	{Name: "ARCHIVE_EXPIRATION_DAYS", Kind: ConfigKindInt},
+	{Name: "OUTBOUND_ALLOWLIST", Kind: ConfigKindString},
+	{Name: "OUTBOUND_ALLOW_PRIVATE", Kind: ConfigKindBool},
+	{Name: "WEBHOOK_MAX_ATTEMPTS", Kind: ConfigKindInt},
+	{Name: "WEBHOOK_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "NOTIFICATION_CHECK_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "NOTIFICATION_MAX_ATTEMPTS", Kind: ConfigKindInt},
+	{Name: "SMTP_HOST", Kind: ConfigKindString},
+	{Name: "SMTP_PORT", Kind: ConfigKindInt},
+	{Name: "SMTP_USERNAME", Kind: ConfigKindString},
+	{Name: "SMTP_PASSWORD", Kind: ConfigKindString, Secret: true},
+	{Name: "SMTP_FROM", Kind: ConfigKindString},
+	{Name: "TELEGRAM_BOT_TOKEN", Kind: ConfigKindString, Secret: true},
+	{Name: "TELEGRAM_API_URL", Kind: ConfigKindURL},
+	{Name: "MQTT_BROKER", Kind: ConfigKindURL},
+	{Name: "MQTT_USERNAME", Kind: ConfigKindString},
+	{Name: "MQTT_PASSWORD", Kind: ConfigKindString, Secret: true},
+	{Name: "MQTT_CLIENT_ID", Kind: ConfigKindString},
+	{Name: "MQTT_DISCOVERY_PREFIX", Kind: ConfigKindString},
+	{Name: "MQTT_BASE_TOPIC", Kind: ConfigKindString},
+	{Name: "MQTT_SYNC_INTERVAL", Kind: ConfigKindDuration},
+	{Name: "LOAD_SHED_MAX_IN_FLIGHT", Kind: ConfigKindInt},
+	{Name: "LOAD_SHED_MAX_QUEUE_DEPTH", Kind: ConfigKindInt},
+	{Name: "LOAD_SHED_MAX_UPSTREAM_LATENCY", Kind: ConfigKindDuration},
+	{Name: "LOAD_SHED_RETRY_AFTER", Kind: ConfigKindDuration},
+	{Name: "LOAD_SHED_ROUTES", Kind: ConfigKindString},
+	{Name: "HTTP_CLIENT_DIAL_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "HTTP_CLIENT_TLS_HANDSHAKE_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "HTTP_CLIENT_IDLE_CONN_TIMEOUT", Kind: ConfigKindDuration},
+	{Name: "HTTP_CLIENT_MAX_IDLE_CONNS", Kind: ConfigKindInt},
+	{Name: "HTTP_CLIENT_MAX_IDLE_CONNS_PER_HOST", Kind: ConfigKindInt},
+	{Name: "HTTP_CLIENT_PROXY_URL", Kind: ConfigKindURL},
+	{Name: "HTTP_CLIENT_RETRY_MAX_ATTEMPTS", Kind: ConfigKindInt},
+	{Name: "HTTP_CLIENT_RETRY_BACKOFF", Kind: ConfigKindDuration},
+	{Name: "HTTP_CLIENT_RETRY_MAX_BACKOFF", Kind: ConfigKindDuration},
+	{Name: "DB_HOST", Kind: ConfigKindString},
+	{Name: "DB_PORT", Kind: ConfigKindInt},
+	{Name: "DB_USER", Kind: ConfigKindString},
+	{Name: "DB_PASSWORD", Kind: ConfigKindString, Secret: true},
+	{Name: "DB_NAME", Kind: ConfigKindString},
+	{Name: "DB_MIGRATE_ON_START", Kind: ConfigKindBool},
+}
+
+// configSources records where each setting was read from by the last LoadConfig.
+var configSources = map[string]string{}
+
+// lookupConfigSetting returns the setting with the given name, compared case-insensitively.
+func lookupConfigSetting(name string) (ConfigSetting, bool) {
+	for _, setting := range ConfigSettings {
+		if strings.EqualFold(setting.Name, name) {
+			return setting, true
+		}
+	}
+	return ConfigSetting{}, false
+}
+
+// ValidateConfig checks the loaded configuration and reports every problem found by ConfigProblems.
+//
+// param c The loaded configuration.
+// return error An error listing every problem, one per line; nil if the configuration is valid.
+func ValidateConfig(c *Config) error {
+	problems := ConfigProblems(c)
+	if len(problems) == 0 {
+		return nil
+	}
+	return errors.New("invalid configuration:\n  " + strings.Join(problems, "\n  "))
+}
+
+// ConfigProblems checks the loaded configuration: the configuration files must be readable, every set value
+// must parse as the type of its setting, and the Tuya credentials must be set. The credentials are not
+// required while setup is still open (no API_KEY), since POST /api/setup supplies them, nor in integration
+// test mode.
+//
+// param c The loaded configuration.
+// return []string The problems, one per invalid setting; empty if the configuration is valid.
+func ConfigProblems(c *Config) []string {
+	problems := append([]string(nil), configLoadProblems...)
+	if c.ApiKey != "" && !c.IntegrationTestMode {
+		required := map[string]string{
+			"TUYA_CLIENT_ID":     c.TuyaClientID,
+			"TUYA_ACCESS_SECRET": c.TuyaClientSecret,
+			"TUYA_BASE_URL":      c.TuyaBaseURL,
+		}
+		for _, setting := range ConfigSettings {
+			if setting.Required && strings.TrimSpace(required[setting.Name]) == "" {
+				problems = append(problems, fmt.Sprintf("%s is required (the Tuya cloud project credentials)", setting.Name))
+			}
+		}
+	}
+
+	for _, setting := range ConfigSettings {
+		value := strings.TrimSpace(os.Getenv(setting.Name))
+		if value == "" {
+			continue
+		}
+		if err := setting.validate(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %v", setting.Name, err))
+		}
+	}
+	return problems
+}
+
+// validate checks that a value parses as the setting's type and is one of its allowed values.
+func (s ConfigSetting) validate(value string) error {
+	switch s.Kind {
+	case ConfigKindDuration:
+		if _, err := time.ParseDuration(value); err != nil {
+			return fmt.Errorf("%q is not a duration (e.g. 30s, 5m, 1h)", value)
+		}
+	case ConfigKindInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+	case ConfigKindFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+	case ConfigKindBool:
+		// The settings are read as == "true", so anything else would silently mean false
+		if value != "true" && value != "false" {
+			return fmt.Errorf("%q is not true or false", value)
+		}
+	case ConfigKindURL:
+		u, err := url.Parse(value)
+		if err != nil || u.Scheme == "" || (u.Host == "" && u.Scheme != "file") {
+			return fmt.Errorf("%q is not an absolute URL (scheme://host)", redactURL(value))
+		}
+	case ConfigKindTimezone:
+		if _, err := loadCachedLocation(value); err != nil {
+			return fmt.Errorf("%q is not an IANA time zone (e.g. Asia/Jakarta)", value)
+		}
+	}
+
+	if len(s.Values) == 0 {
+		return nil
+	}
+	for _, allowed := range s.Values {
+		if value == allowed || (s.IgnoreCase && strings.EqualFold(value, allowed)) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%q is not one of %s", value, strings.Join(s.Values, ", "))
+}
+
+// ConfigEntry is a setting as reported by the configuration diagnostics.
+type ConfigEntry struct {
+	Name     string
+	Kind     ConfigKind
+	Value    string // Redacted for secrets; URL passwords are masked
+	Set      bool
+	Secret   bool
+	Required bool
+	Source   string // ConfigSourceEnv, ConfigSourceDotEnv or ConfigSourceYAML; empty if unset
+}
+
+// DescribeConfig reports every setting with its redacted value and where it was read from, sorted by name.
+//
+// return []ConfigEntry The settings.
+func DescribeConfig() []ConfigEntry {
+	entries := make([]ConfigEntry, 0, len(ConfigSettings))
+	for _, setting := range ConfigSettings {
+		value, set := os.LookupEnv(setting.Name)
+		entry := ConfigEntry{
+			Name:     setting.Name,
+			Kind:     setting.Kind,
+			Set:      set && value != "",
+			Secret:   setting.Secret,
+			Required: setting.Required,
+		}
+		if entry.Set {
+			entry.Source = configSources[setting.Name]
+			switch {
+			case setting.Secret:
+				entry.Value = redactedValue
+			case setting.Kind == ConfigKindURL:
+				entry.Value = redactURL(value)
+			default:
+				entry.Value = value
+			}
+		}
+		entries = append(entries, entry)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	return entries
+}
+
+// redactURL masks the password of a URL's user info, e.g. redis://:secret@host.
+func redactURL(value string) string {
+	u, err := url.Parse(value)
+	if err != nil {
+		// The unparsable value might still contain credentials
+		if strings.Contains(value, "@") {
+			return redactedValue
+		}
+		return value
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,71 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// defaultEnvFile is where settings are written when no .env file exists yet.
+const defaultEnvFile = ".env"
+
+// WriteEnvValues stores settings in the .env file loaded at startup, creating it when missing.
+// Existing assignments of the keys are replaced in place; other lines and comments are kept.
+// The file is written with owner-only permissions since it holds secrets.
+//
+// param values The settings to store, keyed by environment variable name.
+// return string The path of the written file.
+// return error An error if the file cannot be read or written.
+func WriteEnvValues(values map[string]string) (string, error) {
+	path := findEnvFile()
+	if path == "" {
+		path = defaultEnvFile
+	}
+
+	var lines []string
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	written := make(map[string]bool, len(values))
+	for i, line := range lines {
+		key, _, found := strings.Cut(strings.TrimSpace(line), "=")
+		if !found || strings.HasPrefix(key, "#") {
+			continue
+		}
+		key = strings.TrimSpace(strings.TrimPrefix(key, "export "))
+		if value, ok := values[key]; ok {
+			lines[i] = key + "=" + quoteEnvValue(value)
+			written[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		lines = append(lines, key+"="+quoteEnvValue(values[key]))
+	}
+
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return path, nil
+}
+
+// quoteEnvValue quotes a value when it contains characters godotenv would otherwise interpret.
+func quoteEnvValue(value string) string {
+	if strings.ContainsAny(value, " #\"'\\$\t") {
+		return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "$", `\$`).Replace(value) + `"`
+	}
+	return value
+}
@@ -0,0 +1,7 @@
+//go:build !integration
+
+package utils
+
+// IntegrationTestModeAvailable reports whether INTEGRATION_TEST_MODE may be enabled. Only binaries built with
+// the integration build tag contain the fake Tuya OpenAPI, so production builds ignore the variable.
+const IntegrationTestModeAvailable = false
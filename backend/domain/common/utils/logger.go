@@ -1,9 +1,13 @@
 package utils
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -15,19 +19,46 @@ const (
 	LevelError
 )
 
+// Log output formats selected with LOG_FORMAT.
+const (
+	LogFormatText = "text"
+	LogFormatJSON = "json"
+)
+
 var (
-	currentLogLevel = LevelInfo // Default to INFO
-	levelNames      = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+	currentLogLevel  = LevelInfo // Default to INFO
+	currentLogFormat = LogFormatText
+	levelNames       = []string{"DEBUG", "INFO", "WARN", "ERROR"}
+	logMu            sync.Mutex
 )
 
+// Fields are structured key/value pairs attached to log entries (e.g., request_id, device_id, uid, tuya_code).
+type Fields map[string]interface{}
+
+// logFieldsKey is the context key under which request-scoped log fields are stored.
+type logFieldsKey struct{}
+
+// Logger writes log entries carrying a fixed set of fields.
+// The zero value logs without fields, like the package-level LogX functions.
+type Logger struct {
+	fields Fields
+}
+
 // init initializes the logger configuration on package startup.
 func init() {
 	UpdateLogLevel()
 }
 
-// UpdateLogLevel reads the 'LOG_LEVEL' environment variable and updates the current log level.
-// Valid values: DEBUG, INFO, WARN, ERROR. Defaults to INFO if invalid or unset.
+// UpdateLogLevel reads the 'LOG_LEVEL' and 'LOG_FORMAT' environment variables and updates the logger.
+// Valid levels: DEBUG, INFO, WARN, ERROR. Defaults to INFO if invalid or unset.
+// Valid formats: text, json. Defaults to text; json writes one object per line for log aggregation (Loki, ELK).
 func UpdateLogLevel() {
+	if strings.EqualFold(os.Getenv("LOG_FORMAT"), LogFormatJSON) {
+		currentLogFormat = LogFormatJSON
+	} else {
+		currentLogFormat = LogFormatText
+	}
+
 	envLevel := os.Getenv("LOG_LEVEL")
 	switch strings.ToUpper(envLevel) {
 	case "DEBUG":
@@ -51,6 +82,13 @@ func shouldLog(level int) bool {
 	return level >= currentLogLevel
 }
 
+// DebugEnabled reports whether DEBUG messages are logged, so callers can skip work that only feeds debug output.
+//
+// return bool True if LOG_LEVEL is DEBUG.
+func DebugEnabled() bool {
+	return shouldLog(LevelDebug)
+}
+
 // logMessage formats and prints a log message to stdout.
 // It includes a timestamp and the log level prefix.
 //
@@ -58,14 +96,131 @@ func shouldLog(level int) bool {
 // param format The format string (printf style).
 // param v The arguments for the format string.
 func logMessage(level int, format string, v ...interface{}) {
+	writeLog(level, nil, format, v...)
+}
+
+// writeLog prints a log entry with its fields to stdout in the configured format.
+// Text entries append the fields as sorted key=value pairs; JSON entries hold time, level, msg and the fields.
+//
+// param level The severity level of the message.
+// param fields The structured fields of the entry (may be nil).
+// param format The format string (printf style).
+// param v The arguments for the format string.
+func writeLog(level int, fields Fields, format string, v ...interface{}) {
 	if !shouldLog(level) {
 		return
 	}
 
 	msg := fmt.Sprintf(format, v...)
-	timestamp := time.Now().Format("2006/01/02 15:04:05")
-	prefix := levelNames[level]
-	fmt.Printf("%s %s: %s\n", timestamp, prefix, msg)
+	now := time.Now()
+
+	var line string
+	if currentLogFormat == LogFormatJSON {
+		entry := make(map[string]interface{}, len(fields)+3)
+		for key, value := range fields {
+			entry[key] = value
+		}
+		entry["time"] = now.Format(time.RFC3339Nano)
+		entry["level"] = strings.ToLower(levelNames[level])
+		entry["msg"] = msg
+		encoded, err := json.Marshal(entry)
+		if err != nil {
+			encoded, _ = json.Marshal(map[string]interface{}{"time": entry["time"], "level": entry["level"], "msg": msg})
+		}
+		line = string(encoded)
+	} else {
+		var b strings.Builder
+		fmt.Fprintf(&b, "%s %s: %s", now.Format("2006/01/02 15:04:05"), levelNames[level], msg)
+		keys := make([]string, 0, len(fields))
+		for key := range fields {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			fmt.Fprintf(&b, " %s=%v", key, fields[key])
+		}
+		line = b.String()
+	}
+
+	logMu.Lock()
+	defer logMu.Unlock()
+	fmt.Fprintln(os.Stdout, line)
+}
+
+// WithFields returns a Logger that adds fields to every entry.
+//
+// param fields The fields to attach.
+// return *Logger The logger carrying the fields.
+func WithFields(fields Fields) *Logger {
+	return (&Logger{}).WithFields(fields)
+}
+
+// LoggerFromContext returns a Logger carrying the request-scoped fields of ctx (e.g., request_id, uid).
+//
+// param ctx The request context; nil or a context without fields gives a logger without fields.
+// return *Logger The logger carrying the fields.
+func LoggerFromContext(ctx context.Context) *Logger {
+	if ctx == nil {
+		return &Logger{}
+	}
+	fields, _ := ctx.Value(logFieldsKey{}).(Fields)
+	return &Logger{fields: fields}
+}
+
+// ContextWithLogFields returns a child context whose loggers add the given fields to the ones already attached.
+//
+// param ctx The parent context.
+// param fields The fields to attach.
+// return context.Context The context carrying the merged fields.
+func ContextWithLogFields(ctx context.Context, fields Fields) context.Context {
+	return context.WithValue(ctx, logFieldsKey{}, LoggerFromContext(ctx).WithFields(fields).fields)
+}
+
+// WithFields returns a copy of the logger with additional fields. Later fields override earlier ones.
+//
+// param fields The fields to add.
+// return *Logger The logger carrying the merged fields.
+func (l *Logger) WithFields(fields Fields) *Logger {
+	merged := make(Fields, len(l.fields)+len(fields))
+	for key, value := range l.fields {
+		merged[key] = value
+	}
+	for key, value := range fields {
+		merged[key] = value
+	}
+	return &Logger{fields: merged}
+}
+
+// Debug logs a message at DEBUG level with the logger's fields.
+//
+// param format The format string.
+// param v The arguments.
+func (l *Logger) Debug(format string, v ...interface{}) {
+	writeLog(LevelDebug, l.fields, format, v...)
+}
+
+// Info logs a message at INFO level with the logger's fields.
+//
+// param format The format string.
+// param v The arguments.
+func (l *Logger) Info(format string, v ...interface{}) {
+	writeLog(LevelInfo, l.fields, format, v...)
+}
+
+// Warn logs a message at WARN level with the logger's fields.
+//
+// param format The format string.
+// param v The arguments.
+func (l *Logger) Warn(format string, v ...interface{}) {
+	writeLog(LevelWarn, l.fields, format, v...)
+}
+
+// Error logs a message at ERROR level with the logger's fields.
+//
+// param format The format string.
+// param v The arguments.
+func (l *Logger) Error(format string, v ...interface{}) {
+	writeLog(LevelError, l.fields, format, v...)
 }
 
 // LogDebug logs a message at DEBUG level.
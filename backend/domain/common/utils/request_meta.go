@@ -0,0 +1,101 @@
+package utils
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// requestMetaKey is the context key under which the RequestMeta collector is stored.
+type requestMetaKey struct{}
+
+// RequestMeta collects timing information for a single API request.
+// It is only attached when the client opts in, and all methods are safe to call on a nil receiver,
+// so instrumented code does not need to check whether collection is enabled.
+type RequestMeta struct {
+	mu            sync.Mutex
+	startedAt     time.Time
+	upstream      time.Duration
+	upstreamCalls int
+	cache         string
+	retries       int
+}
+
+// WithRequestMeta returns a child context carrying a new RequestMeta collector.
+//
+// param ctx The parent context.
+// return context.Context The context carrying the collector.
+// return *RequestMeta The collector attached to the context.
+func WithRequestMeta(ctx context.Context) (context.Context, *RequestMeta) {
+	meta := &RequestMeta{startedAt: time.Now()}
+	return context.WithValue(ctx, requestMetaKey{}, meta), meta
+}
+
+// RequestMetaFromContext returns the collector attached to ctx, or nil if none is attached.
+//
+// param ctx The request context.
+// return *RequestMeta The collector, or nil.
+func RequestMetaFromContext(ctx context.Context) *RequestMeta {
+	if ctx == nil {
+		return nil
+	}
+	meta, _ := ctx.Value(requestMetaKey{}).(*RequestMeta)
+	return meta
+}
+
+// AddUpstream records the duration of one call to the Tuya API.
+//
+// param d The elapsed time of the call.
+func (m *RequestMeta) AddUpstream(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.upstream += d
+	m.upstreamCalls++
+}
+
+// SetCache records how the request was served from cache ("hit" or "miss").
+//
+// param status The cache status.
+func (m *RequestMeta) SetCache(status string) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.cache = status
+}
+
+// AddRetry records one retry or fallback attempt against the Tuya API.
+func (m *RequestMeta) AddRetry() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.retries++
+}
+
+// Snapshot returns the collected values as a JSON-friendly map.
+//
+// return map[string]interface{} The timing metadata.
+func (m *RequestMeta) Snapshot() map[string]interface{} {
+	if m == nil {
+		return nil
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := map[string]interface{}{
+		"total_ms":       time.Since(m.startedAt).Milliseconds(),
+		"upstream_ms":    m.upstream.Milliseconds(),
+		"upstream_calls": m.upstreamCalls,
+		"retries":        m.retries,
+	}
+	if m.cache != "" {
+		snapshot["cache"] = m.cache
+	}
+	return snapshot
+}
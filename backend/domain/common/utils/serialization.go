@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// maxPooledBufferSize keeps unusually large buffers out of the pool, so one huge payload does not pin its memory.
+const maxPooledBufferSize = 1 << 20
+
+// maxPayloadCacheEntries bounds the number of pre-marshaled payloads kept in memory.
+const maxPayloadCacheEntries = 512
+
+// jsonBufferPool recycles the buffers used to encode JSON on hot paths.
+var jsonBufferPool = sync.Pool{
+	New: func() interface{} {
+		return new(bytes.Buffer)
+	},
+}
+
+// SerializationStat summarizes the JSON work done on one hot path.
+type SerializationStat struct {
+	Path            string
+	MarshalCalls    int64
+	MarshalBytes    int64
+	MarshalTime     time.Duration
+	UnmarshalCalls  int64
+	UnmarshalBytes  int64
+	UnmarshalTime   time.Duration
+	PayloadHits     int64
+	PayloadMisses   int64
+	PayloadBytesHit int64
+}
+
+// serializationStats holds the counters of every hot path, keyed by path name.
+var serializationStats struct {
+	mu    sync.Mutex
+	paths map[string]*SerializationStat
+}
+
+// statFor returns the counters of a path. The caller must hold serializationStats.mu.
+func statFor(path string) *SerializationStat {
+	if serializationStats.paths == nil {
+		serializationStats.paths = make(map[string]*SerializationStat)
+	}
+	stat, ok := serializationStats.paths[path]
+	if !ok {
+		stat = &SerializationStat{Path: path}
+		serializationStats.paths[path] = stat
+	}
+	return stat
+}
+
+// MarshalJSON encodes v like json.Marshal, using a pooled buffer, and records the work under path.
+//
+// param path The hot path name the call is counted under (e.g., "device_list").
+// param v The value to encode.
+// return []byte The encoded JSON, owned by the caller.
+// return error An error if v cannot be encoded.
+func MarshalJSON(path string, v interface{}) ([]byte, error) {
+	start := time.Now()
+	buf := jsonBufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	defer func() {
+		if buf.Cap() <= maxPooledBufferSize {
+			jsonBufferPool.Put(buf)
+		}
+	}()
+
+	if err := json.NewEncoder(buf).Encode(v); err != nil {
+		return nil, err
+	}
+	// Encode terminates the value with a newline that json.Marshal does not add
+	data := make([]byte, buf.Len()-1)
+	copy(data, buf.Bytes())
+
+	serializationStats.mu.Lock()
+	stat := statFor(path)
+	stat.MarshalCalls++
+	stat.MarshalBytes += int64(len(data))
+	stat.MarshalTime += time.Since(start)
+	serializationStats.mu.Unlock()
+	return data, nil
+}
+
+// UnmarshalJSON decodes data like json.Unmarshal and records the work under path.
+//
+// param path The hot path name the call is counted under.
+// param data The JSON to decode.
+// param v The destination.
+// return error An error if data cannot be decoded into v.
+func UnmarshalJSON(path string, data []byte, v interface{}) error {
+	start := time.Now()
+	err := json.Unmarshal(data, v)
+
+	serializationStats.mu.Lock()
+	stat := statFor(path)
+	stat.UnmarshalCalls++
+	stat.UnmarshalBytes += int64(len(data))
+	stat.UnmarshalTime += time.Since(start)
+	serializationStats.mu.Unlock()
+	return err
+}
+
+// SerializationStats returns a snapshot of the counters of every hot path, sorted by path name.
+//
+// return []SerializationStat The counters.
+func SerializationStats() []SerializationStat {
+	serializationStats.mu.Lock()
+	defer serializationStats.mu.Unlock()
+	stats := make([]SerializationStat, 0, len(serializationStats.paths))
+	for _, stat := range serializationStats.paths {
+		stats = append(stats, *stat)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].Path < stats[j].Path
+	})
+	return stats
+}
+
+// payloadEntry is a pre-marshaled response payload and the fingerprint of the data it was built from.
+type payloadEntry struct {
+	version uint64
+	payload []byte
+}
+
+// PayloadCache keeps pre-marshaled response payloads in memory, so polling clients asking for identical
+// data are answered without decoding and re-encoding it. Entries are validated against a version
+// (typically a fingerprint of the cached source bytes) instead of being invalidated explicitly.
+type PayloadCache struct {
+	path    string
+	mu      sync.RWMutex
+	entries map[string]payloadEntry
+}
+
+// NewPayloadCache creates an empty PayloadCache whose hits and misses are counted under path.
+//
+// param path The hot path name used in the serialization stats.
+// return *PayloadCache A pointer to the initialized cache.
+func NewPayloadCache(path string) *PayloadCache {
+	return &PayloadCache{
+		path:    path,
+		entries: make(map[string]payloadEntry),
+	}
+}
+
+// Get returns the payload stored for key when it was built from the given version.
+//
+// param key The payload key (e.g., cache key, response mode and query).
+// param version The version of the source data the payload must match.
+// return []byte The payload; callers must not modify it.
+// return bool False if no payload for this version is stored.
+func (pc *PayloadCache) Get(key string, version uint64) ([]byte, bool) {
+	pc.mu.RLock()
+	entry, ok := pc.entries[key]
+	pc.mu.RUnlock()
+	hit := ok && entry.version == version
+
+	serializationStats.mu.Lock()
+	stat := statFor(pc.path)
+	if hit {
+		stat.PayloadHits++
+		stat.PayloadBytesHit += int64(len(entry.payload))
+	} else {
+		stat.PayloadMisses++
+	}
+	serializationStats.mu.Unlock()
+
+	if !hit {
+		return nil, false
+	}
+	return entry.payload, true
+}
+
+// Set stores the payload built from the given version, evicting an arbitrary entry when the cache is full.
+//
+// param key The payload key.
+// param version The version of the source data.
+// param payload The pre-marshaled payload; it must not be modified afterwards.
+func (pc *PayloadCache) Set(key string, version uint64, payload []byte) {
+	pc.mu.Lock()
+	defer pc.mu.Unlock()
+	if _, ok := pc.entries[key]; !ok && len(pc.entries) >= maxPayloadCacheEntries {
+		for evict := range pc.entries {
+			delete(pc.entries, evict)
+			break
+		}
+	}
+	pc.entries[key] = payloadEntry{version: version, payload: payload}
+}
+
+// PayloadVersion fingerprints source bytes (and optional extra revisions) for use as a PayloadCache version.
+// Hashing the cached bytes is far cheaper than decoding them.
+//
+// param data The source bytes the payload is built from.
+// param revisions Extra counters that change the payload without changing data (e.g., metadata revisions).
+// return uint64 The fingerprint.
+func PayloadVersion(data []byte, revisions ...uint64) uint64 {
+	h := fnv.New64a()
+	h.Write(data)
+	for _, revision := range revisions {
+		var b [8]byte
+		for i := range b {
+			b[i] = byte(revision >> (8 * i))
+		}
+		h.Write(b[:])
+	}
+	return h.Sum64()
+}
+
+// PayloadETag returns a weak entity tag for a marshaled payload in one representation. It is weak because
+// middlewares may still re-encode the envelope (e.g., to add timing metadata) without changing the data; the
+// variant names the ways they do change the data (e.g., field selection), so each representation has its own tag.
+//
+// param payload The marshaled payload.
+// param variant The representation of the payload that is sent.
+// return string The entity tag, e.g. W/"9f86d081884c7d65".
+func PayloadETag(payload []byte, variant string) string {
+	return fmt.Sprintf(`W/"%016x"`, PayloadVersion(payload, PayloadVersion([]byte(variant))))
+}
+
+// ETagMatches reports whether an If-None-Match header matches an entity tag, using the weak comparison
+// required for GET requests. The header may list several tags or be "*".
+//
+// param ifNoneMatch The If-None-Match header value.
+// param etag The current entity tag.
+// return bool True if the client already holds the current representation.
+func ETagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" || etag == "" {
+		return false
+	}
+	current := strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == current {
+			return true
+		}
+	}
+	return false
+}
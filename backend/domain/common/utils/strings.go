@@ -4,6 +4,7 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"strings"
+	"unicode"
 )
 
 // JoinStrings concatenates a slice of strings into a single string with a given separator.
@@ -25,4 +26,25 @@ func HashString(s string) string {
 	h := sha256.New()
 	h.Write([]byte(s))
 	return hex.EncodeToString(h.Sum(nil))
-}
\ No newline at end of file
+}
+
+// ToSnakeCase converts a camelCase or PascalCase identifier to snake_case (e.g., "productName" to "product_name").
+// Identifiers that are already snake_case are returned unchanged.
+//
+// param s The identifier to convert.
+// return string The snake_case identifier.
+func ToSnakeCase(s string) string {
+	var b strings.Builder
+	runes := []rune(s)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && runes[i-1] != '_' && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1]))) {
+				b.WriteRune('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
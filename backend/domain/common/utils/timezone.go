@@ -0,0 +1,67 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+var (
+	locationMu    sync.Mutex
+	locationCache = map[string]*time.Location{}
+)
+
+// Location returns the deployment time zone configured by TIMEZONE (an IANA name such as "Asia/Jakarta").
+// Schedules, history intervals and reports use it for wall-clock times and day boundaries, so they follow
+// the building rather than the server. Without TIMEZONE, or if it is invalid, the server's local zone is used.
+//
+// return *time.Location The deployment time zone.
+func Location() *time.Location {
+	name := GetConfig().Timezone
+	if name == "" {
+		return time.Local
+	}
+	location, err := loadCachedLocation(name)
+	if err != nil {
+		LogWarn("Invalid TIMEZONE %q, using the server time zone: %v", name, err)
+		return time.Local
+	}
+	return location
+}
+
+// LoadLocation resolves a time zone override (e.g., the time zone of one schedule).
+// An empty name resolves to the deployment time zone.
+//
+// param name The IANA time zone name, or empty for the deployment time zone.
+// return *time.Location The time zone.
+// return error An error if the name is not a known time zone.
+func LoadLocation(name string) (*time.Location, error) {
+	if name == "" {
+		return Location(), nil
+	}
+	return loadCachedLocation(name)
+}
+
+// loadCachedLocation loads a time zone once; the zone database is read from disk on every time.LoadLocation call.
+func loadCachedLocation(name string) (*time.Location, error) {
+	locationMu.Lock()
+	defer locationMu.Unlock()
+	if location, ok := locationCache[name]; ok {
+		return location, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+	locationCache[name] = location
+	return location, nil
+}
+
+// StartOfDay returns midnight of the day containing t in the given time zone.
+//
+// param t The time.
+// param location The time zone whose day boundaries apply.
+// return time.Time Midnight of that day, in location.
+func StartOfDay(t time.Time, location *time.Location) time.Time {
+	t = t.In(location)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, location)
+}
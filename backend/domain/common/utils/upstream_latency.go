@@ -0,0 +1,41 @@
+package utils
+
+import (
+	"sync"
+	"time"
+)
+
+// upstreamLatencyWeight is the weight of a new sample in the moving average.
+const upstreamLatencyWeight = 0.2
+
+// upstreamLatency is an exponentially weighted moving average of upstream (Tuya API) call durations,
+// shared by request handlers and background workers.
+var upstreamLatency struct {
+	mu        sync.Mutex
+	average   time.Duration
+	sampledAt time.Time
+}
+
+// RecordUpstreamLatency adds the duration of an upstream call to the moving average.
+//
+// param d The duration of the call.
+func RecordUpstreamLatency(d time.Duration) {
+	upstreamLatency.mu.Lock()
+	defer upstreamLatency.mu.Unlock()
+	if upstreamLatency.sampledAt.IsZero() {
+		upstreamLatency.average = d
+	} else {
+		upstreamLatency.average += time.Duration(upstreamLatencyWeight * float64(d-upstreamLatency.average))
+	}
+	upstreamLatency.sampledAt = time.Now()
+}
+
+// UpstreamLatency returns the moving average of upstream call durations.
+//
+// return time.Duration The average, or 0 when no call was recorded.
+// return time.Time When the latest call finished.
+func UpstreamLatency() (time.Duration, time.Time) {
+	upstreamLatency.mu.Lock()
+	defer upstreamLatency.mu.Unlock()
+	return upstreamLatency.average, upstreamLatency.sampledAt
+}
@@ -0,0 +1,52 @@
+package utils
+
+import "sync"
+
+// WorkerGroup tracks the goroutines of a background worker so they can be stopped during shutdown.
+// The zero value is ready to use.
+type WorkerGroup struct {
+	mu      sync.Mutex
+	stop    chan struct{}
+	stopped bool
+	wg      sync.WaitGroup
+}
+
+// Go runs fn in a new goroutine. fn must return once the stop channel is closed.
+// Calls made after Stop are ignored.
+//
+// param fn The worker loop, receiving the channel that is closed on Stop.
+func (g *WorkerGroup) Go(fn func(stop <-chan struct{})) {
+	g.mu.Lock()
+	if g.stopped {
+		g.mu.Unlock()
+		return
+	}
+	stop := g.stopChannel()
+	g.wg.Add(1)
+	g.mu.Unlock()
+
+	go func() {
+		defer g.wg.Done()
+		fn(stop)
+	}()
+}
+
+// Stop signals every goroutine started with Go to return and waits until they have.
+// It is safe to call more than once, and on a group that never started a goroutine.
+func (g *WorkerGroup) Stop() {
+	g.mu.Lock()
+	if !g.stopped {
+		g.stopped = true
+		close(g.stopChannel())
+	}
+	g.mu.Unlock()
+	g.wg.Wait()
+}
+
+// stopChannel returns the stop channel, creating it on first use. The caller must hold mu.
+func (g *WorkerGroup) stopChannel() chan struct{} {
+	if g.stop == nil {
+		g.stop = make(chan struct{})
+	}
+	return g.stop
+}
@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+	identity_dtos "teralux_app/domain/identity/dtos"
+	"teralux_app/domain/identity/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// IdentityController handles logins through identity providers (OIDC, LDAP)
+type IdentityController struct {
+	useCase *usecases.IdentityUseCase
+}
+
+// NewIdentityController creates a new IdentityController instance
+func NewIdentityController(useCase *usecases.IdentityUseCase) *IdentityController {
+	return &IdentityController{
+		useCase: useCase,
+	}
+}
+
+// Login handles POST /api/identity/login endpoint
+// @Summary      Login With Identity Provider
+// @Description  Authenticates a user with a configured identity provider: "ldap" takes a username and password, "oidc" takes an ID token issued to OIDC_CLIENT_ID. The user's directory groups are mapped to a role (read-only, control or admin) with IDENTITY_GROUP_ROLES. The returned token is accepted instead of an X-API-KEY as "Authorization: Bearer <token>", with the role as scope.
+// @Tags         17. Identity
+// @Accept       json
+// @Produce      json
+// @Param        request  body  identity_dtos.IdentityLoginRequestDTO  true  "Provider and credentials"
+// @Success      200  {object}  dtos.StandardResponse{data=identity_dtos.IdentityTokenDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      401  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Router       /api/identity/login [post]
+func (c *IdentityController) Login(ctx *gin.Context) {
+	var req identity_dtos.IdentityLoginRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	token, err := c.useCase.Login(ctx.Request.Context(), req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "Login", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Login successful",
+		Data:    token,
+	})
+}
+
+// ListProviders handles GET /api/identity/providers endpoint
+// @Summary      List Identity Providers
+// @Description  Lists the configured identity providers, so login screens can offer them.
+// @Tags         17. Identity
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]identity_dtos.IdentityProviderDTO}
+// @Router       /api/identity/providers [get]
+func (c *IdentityController) ListProviders(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Identity providers retrieved successfully",
+		Data:    c.useCase.Providers(),
+	})
+}
@@ -0,0 +1,26 @@
+package dtos
+
+// IdentityLoginRequestDTO authenticates with an identity provider: a username and password for LDAP,
+// or an ID token issued to the configured client for OIDC
+type IdentityLoginRequestDTO struct {
+	Provider string `json:"provider" binding:"required" example:"ldap"`
+	Username string `json:"username,omitempty" example:"jdoe"`
+	Password string `json:"password,omitempty"`
+	IDToken  string `json:"id_token,omitempty"`
+}
+
+// IdentityTokenDTO is the Teralux token issued for a directory user. Role is the API key scope its groups map to
+type IdentityTokenDTO struct {
+	Token     string   `json:"token"`
+	TokenType string   `json:"token_type" example:"Bearer"`
+	ExpiresAt int64    `json:"expires_at"`
+	Provider  string   `json:"provider"`
+	Subject   string   `json:"subject"`
+	Role      string   `json:"role" example:"admin"`
+	Groups    []string `json:"groups,omitempty"`
+}
+
+// IdentityProviderDTO is a configured identity provider
+type IdentityProviderDTO struct {
+	Name string `json:"name" example:"oidc"`
+}
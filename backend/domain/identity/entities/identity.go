@@ -0,0 +1,10 @@
+package entities
+
+// Identity is a user authenticated by an external identity provider (OIDC or LDAP).
+// Groups are the directory groups the user belongs to; they are mapped to Teralux roles.
+type Identity struct {
+	Provider string   `json:"provider"`
+	Subject  string   `json:"subject"`
+	Name     string   `json:"name,omitempty"`
+	Groups   []string `json:"groups,omitempty"`
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/identity/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupIdentityRoutes registers the identity provider login endpoints.
+//
+// param router The Gin router interface (public: these endpoints issue credentials).
+// param controller The controller handling identity logins.
+func SetupIdentityRoutes(router gin.IRouter, controller *controllers.IdentityController) {
+	utils.LogDebug("SetupIdentityRoutes initialized")
+	api := router.Group("/api/identity")
+	{
+		// POST /api/identity/login
+		// Exchanges LDAP credentials or an OIDC ID token for an identity token.
+		api.POST("/login", controller.Login)
+
+		// GET /api/identity/providers
+		// Lists the configured identity providers.
+		api.GET("/providers", controller.ListProviders)
+	}
+}
@@ -0,0 +1,19 @@
+package services
+
+import (
+	"context"
+	"teralux_app/domain/identity/dtos"
+	"teralux_app/domain/identity/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// ErrInvalidCredentials is returned when an identity provider rejects the presented credentials.
+var ErrInvalidCredentials = tuya_errors.Unauthorized("invalid credentials")
+
+// AuthProvider authenticates users against an external directory.
+type AuthProvider interface {
+	// Name is the provider name clients select on login (e.g., "oidc", "ldap").
+	Name() string
+	// Authenticate verifies the credentials of a login request and returns the user with their groups.
+	Authenticate(ctx context.Context, req dtos.IdentityLoginRequestDTO) (*entities.Identity, error)
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/ldap"
+	"teralux_app/domain/identity/dtos"
+	"teralux_app/domain/identity/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// ldapTimeout bounds every request to the directory server.
+const ldapTimeout = 10 * time.Second
+
+// LDAPAuthProviderConfig configures an LDAPAuthProvider.
+type LDAPAuthProviderConfig struct {
+	// URL is the server URL, ldap:// or ldaps:// (LDAP_URL).
+	URL string
+	// BindDN and BindPassword are the service account used to find users; empty searches anonymously.
+	BindDN       string
+	BindPassword string
+	// BaseDN is where users are searched (LDAP_BASE_DN).
+	BaseDN string
+	// UserFilter finds a user by name; %s is replaced by the escaped username (default "(uid=%s)").
+	UserFilter string
+	// GroupAttribute lists the groups of a user (default "memberOf").
+	GroupAttribute string
+}
+
+// LDAPAuthProvider authenticates users with their directory password (e.g., Active Directory, OpenLDAP).
+// The user is searched with the service account, then the password is checked by binding as the user.
+// Groups are read from the group attribute of the user entry.
+type LDAPAuthProvider struct {
+	config LDAPAuthProviderConfig
+}
+
+// NewLDAPAuthProvider initializes a new LDAPAuthProvider.
+//
+// param config The directory server and search configuration.
+// return *LDAPAuthProvider A pointer to the initialized provider.
+func NewLDAPAuthProvider(config LDAPAuthProviderConfig) *LDAPAuthProvider {
+	if config.UserFilter == "" {
+		config.UserFilter = "(uid=%s)"
+	}
+	if config.GroupAttribute == "" {
+		config.GroupAttribute = "memberOf"
+	}
+	return &LDAPAuthProvider{config: config}
+}
+
+// Name returns "ldap".
+func (p *LDAPAuthProvider) Name() string {
+	return "ldap"
+}
+
+// Authenticate checks the username and password of a login request against the directory.
+//
+// param ctx The request context, bounding the connection.
+// param req The login request carrying username and password.
+// return *entities.Identity The user, identified by their DN, with the groups of the group attribute.
+// return error ErrInvalidCredentials, a bad request error without credentials, or a directory error.
+func (p *LDAPAuthProvider) Authenticate(ctx context.Context, req dtos.IdentityLoginRequestDTO) (*entities.Identity, error) {
+	if req.Username == "" || req.Password == "" {
+		return nil, tuya_errors.BadRequest("username and password are required for the ldap provider")
+	}
+
+	conn, err := ldap.Dial(ctx, p.config.URL, ldapTimeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if p.config.BindDN != "" {
+		if err := conn.Bind(p.config.BindDN, p.config.BindPassword); err != nil {
+			return nil, fmt.Errorf("failed to bind LDAP service account: %w", err)
+		}
+	}
+	filter := strings.ReplaceAll(p.config.UserFilter, "%s", ldap.EscapeFilter(req.Username))
+	entries, err := conn.Search(p.config.BaseDN, filter, []string{p.config.GroupAttribute, "cn"})
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) != 1 {
+		// Unknown and ambiguous users are reported like wrong passwords, so usernames cannot be probed
+		return nil, ErrInvalidCredentials
+	}
+	user := entries[0]
+
+	if err := conn.Bind(user.DN, req.Password); err != nil {
+		if errors.Is(err, ldap.ErrInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	name := req.Username
+	if cn := attributeValues(user, "cn"); len(cn) > 0 {
+		name = cn[0]
+	}
+	return &entities.Identity{
+		Provider: p.Name(),
+		Subject:  user.DN,
+		Name:     name,
+		Groups:   attributeValues(user, p.config.GroupAttribute),
+	}, nil
+}
+
+// attributeValues returns the values of an attribute; directories may return names in a different case.
+func attributeValues(entry ldap.Entry, name string) []string {
+	for attribute, values := range entry.Attributes {
+		if strings.EqualFold(attribute, name) {
+			return values
+		}
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"teralux_app/domain/common/infrastructure/oidc"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/identity/dtos"
+	"teralux_app/domain/identity/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// OIDCAuthProvider authenticates users with ID tokens issued by an OpenID Connect provider
+// (e.g., Keycloak, Azure AD, Google Workspace). Groups are read from a configurable claim.
+type OIDCAuthProvider struct {
+	verifier    *oidc.Verifier
+	groupsClaim string
+	clock       utils.Clock
+}
+
+// NewOIDCAuthProvider initializes a new OIDCAuthProvider.
+//
+// param issuer The issuer URL (OIDC_ISSUER).
+// param clientID The client ID tokens must be issued to (OIDC_CLIENT_ID).
+// param groupsClaim The claim holding the user's groups (OIDC_GROUPS_CLAIM, default "groups").
+// param clock The Clock used to check token expiry.
+// return *OIDCAuthProvider A pointer to the initialized provider.
+func NewOIDCAuthProvider(issuer, clientID, groupsClaim string, clock utils.Clock) *OIDCAuthProvider {
+	if groupsClaim == "" {
+		groupsClaim = "groups"
+	}
+	return &OIDCAuthProvider{
+		verifier:    oidc.NewVerifier(issuer, clientID, nil),
+		groupsClaim: groupsClaim,
+		clock:       clock,
+	}
+}
+
+// Name returns "oidc".
+func (p *OIDCAuthProvider) Name() string {
+	return "oidc"
+}
+
+// Authenticate verifies the ID token of a login request.
+//
+// param ctx The request context, bounding key discovery.
+// param req The login request carrying id_token.
+// return *entities.Identity The user, identified by the sub claim.
+// return error ErrInvalidCredentials, a bad request error without id_token, or a discovery error.
+func (p *OIDCAuthProvider) Authenticate(ctx context.Context, req dtos.IdentityLoginRequestDTO) (*entities.Identity, error) {
+	if req.IDToken == "" {
+		return nil, tuya_errors.BadRequest("id_token is required for the oidc provider")
+	}
+	claims, err := p.verifier.Verify(ctx, req.IDToken, p.clock.Now())
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidToken) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	name := claims.String("preferred_username")
+	if name == "" {
+		name = claims.String("email")
+	}
+	return &entities.Identity{
+		Provider: p.Name(),
+		Subject:  claims.String("sub"),
+		Name:     name,
+		Groups:   claims.Strings(p.groupsClaim),
+	}, nil
+}
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/identity/dtos"
+	"teralux_app/domain/identity/entities"
+	"teralux_app/domain/identity/services"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	// identityTokenIssuer is the iss claim of the tokens issued to directory users. It differs from the
+	// session tokens' issuer, so neither kind of token is accepted in place of the other.
+	identityTokenIssuer = "teralux-identity"
+	// defaultIdentityTokenTTL is the lifetime of identity tokens when IDENTITY_TOKEN_TTL is not set.
+	defaultIdentityTokenTTL = 8 * time.Hour
+)
+
+var (
+	// ErrIdentityProviderNotFound is returned when a login names a provider that is not configured.
+	ErrIdentityProviderNotFound = tuya_errors.NotFound("identity provider not found")
+	// ErrIdentityUnavailable is returned when identity tokens cannot be issued because JWT_SECRET is not set.
+	ErrIdentityUnavailable = tuya_errors.Unavailable("identity login unavailable: JWT_SECRET is not set")
+	// ErrNoRoleForIdentity is returned when none of the user's groups maps to a Teralux role.
+	ErrNoRoleForIdentity = tuya_errors.Forbidden("none of the user's groups is mapped to a role")
+)
+
+// IdentityUseCase delegates authentication to external directories (OIDC, LDAP), so building IT can manage
+// access in their existing directory instead of handing out static API keys. Directory groups are mapped
+// to Teralux roles (the API key scopes read-only, control and admin) with IDENTITY_GROUP_ROLES; a user gets
+// the most privileged role of their groups. Authenticated users receive a signed token that is accepted
+// wherever an X-API-KEY is, with their role as its scope.
+type IdentityUseCase struct {
+	providers  map[string]services.AuthProvider
+	groupRoles map[string]string
+	secret     []byte
+	ttl        time.Duration
+	clock      utils.Clock
+}
+
+// NewIdentityUseCase initializes a new IdentityUseCase from IDENTITY_GROUP_ROLES, IDENTITY_TOKEN_TTL and JWT_SECRET.
+//
+// param providers The configured identity providers.
+// param clock The Clock used to issue and verify tokens.
+// return *IdentityUseCase A pointer to the initialized usecase.
+func NewIdentityUseCase(providers []services.AuthProvider, clock utils.Clock) *IdentityUseCase {
+	config := utils.GetConfig()
+	ttl, err := time.ParseDuration(config.IdentityTokenTTL)
+	if err != nil || ttl <= 0 {
+		ttl = defaultIdentityTokenTTL
+	}
+	byName := make(map[string]services.AuthProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &IdentityUseCase{
+		providers:  byName,
+		groupRoles: parseGroupRoles(config.IdentityGroupRoles),
+		secret:     []byte(config.JWTSecret),
+		ttl:        ttl,
+		clock:      clock,
+	}
+}
+
+// Providers lists the configured identity providers.
+//
+// return []dtos.IdentityProviderDTO The providers, ordered by name.
+func (uc *IdentityUseCase) Providers() []dtos.IdentityProviderDTO {
+	result := make([]dtos.IdentityProviderDTO, 0, len(uc.providers))
+	for name := range uc.providers {
+		result = append(result, dtos.IdentityProviderDTO{Name: name})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result
+}
+
+// Login authenticates a user with an identity provider and issues a token carrying the role of their groups.
+//
+// param ctx The request context.
+// param req The provider and credentials.
+// return *dtos.IdentityTokenDTO The issued token.
+// return error ErrIdentityProviderNotFound, ErrIdentityUnavailable, services.ErrInvalidCredentials,
+// ErrNoRoleForIdentity, a bad request error for missing credentials, or a provider error.
+func (uc *IdentityUseCase) Login(ctx context.Context, req dtos.IdentityLoginRequestDTO) (*dtos.IdentityTokenDTO, error) {
+	provider, ok := uc.providers[req.Provider]
+	if !ok {
+		return nil, ErrIdentityProviderNotFound
+	}
+	if len(uc.secret) == 0 {
+		return nil, ErrIdentityUnavailable
+	}
+
+	identity, err := provider.Authenticate(ctx, req)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidCredentials) {
+			utils.LogWarn("IdentityUseCase: Rejected %s login for %q", req.Provider, req.Username)
+		}
+		return nil, err
+	}
+	role := uc.roleFor(identity)
+	if role == "" {
+		utils.LogWarn("IdentityUseCase: %s user %s has no mapped group (%v)", identity.Provider, identity.Subject, identity.Groups)
+		return nil, ErrNoRoleForIdentity
+	}
+
+	now := uc.clock.Now()
+	subject := identity.Provider + ":" + identity.Subject
+	expiresAt := now.Add(uc.ttl).Unix()
+	token, err := utils.SignJWT(utils.JWTClaims{
+		Issuer:    identityTokenIssuer,
+		Subject:   subject,
+		Scope:     role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt,
+	}, uc.secret)
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("IdentityUseCase: %s logged in as %s via %s", subject, role, identity.Provider)
+	return &dtos.IdentityTokenDTO{
+		Token:     token,
+		TokenType: "Bearer",
+		ExpiresAt: expiresAt,
+		Provider:  identity.Provider,
+		Subject:   identity.Subject,
+		Role:      role,
+		Groups:    identity.Groups,
+	}, nil
+}
+
+// ValidateIdentityToken verifies a token issued by Login and returns the role it grants.
+// It matches the middlewares.IdentityTokenValidator signature.
+//
+// param token The bearer token.
+// return utils.APIKeyIdentity The role of the user as scope (without an API key ID).
+// return string The user, as "provider:subject".
+// return bool False if the token is invalid, expired or not an identity token.
+func (uc *IdentityUseCase) ValidateIdentityToken(token string) (utils.APIKeyIdentity, string, bool) {
+	if len(uc.secret) == 0 || !utils.LooksLikeJWT(token) {
+		return utils.APIKeyIdentity{}, "", false
+	}
+	claims, err := utils.ParseJWT(token, uc.secret, uc.clock.Now())
+	if err != nil || claims.Issuer != identityTokenIssuer || !utils.IsAPIKeyScope(claims.Scope) {
+		return utils.APIKeyIdentity{}, "", false
+	}
+	return utils.APIKeyIdentity{Scope: claims.Scope}, claims.Subject, true
+}
+
+// roleFor returns the most privileged role mapped from the user's groups, or "" if none is mapped.
+// Groups match by full name (e.g., an LDAP DN) or by their first RDN value (e.g., "cn=admins,..." as "admins").
+func (uc *IdentityUseCase) roleFor(identity *entities.Identity) string {
+	role := ""
+	for _, group := range identity.Groups {
+		for _, name := range groupNames(group) {
+			mapped, ok := uc.groupRoles[strings.ToLower(name)]
+			if ok && (role == "" || utils.APIKeyScopeAllows(mapped, role)) {
+				role = mapped
+			}
+		}
+	}
+	return role
+}
+
+// groupNames returns the names a group can be mapped by.
+func groupNames(group string) []string {
+	names := []string{group}
+	if first, _, found := strings.Cut(group, ","); found || strings.Contains(first, "=") {
+		if _, value, ok := strings.Cut(first, "="); ok {
+			names = append(names, strings.TrimSpace(value))
+		}
+	}
+	return names
+}
+
+// parseGroupRoles parses "group=role" pairs separated by semicolons (group names may contain commas).
+// Group names are matched case-insensitively; entries with an unknown role are skipped.
+func parseGroupRoles(value string) map[string]string {
+	roles := make(map[string]string)
+	for _, pair := range strings.Split(value, ";") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		separator := strings.LastIndex(pair, "=")
+		if separator <= 0 {
+			utils.LogWarn("IdentityUseCase: Ignoring IDENTITY_GROUP_ROLES entry %q without a role", pair)
+			continue
+		}
+		group := strings.TrimSpace(pair[:separator])
+		role := strings.TrimSpace(pair[separator+1:])
+		if !utils.IsAPIKeyScope(role) {
+			utils.LogWarn("IdentityUseCase: Ignoring IDENTITY_GROUP_ROLES entry %q with unknown role %q", pair, role)
+			continue
+		}
+		roles[strings.ToLower(group)] = role
+	}
+	return roles
+}
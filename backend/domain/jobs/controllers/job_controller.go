@@ -0,0 +1,181 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+	job_dtos "teralux_app/domain/jobs/dtos"
+	"teralux_app/domain/jobs/entities"
+	"teralux_app/domain/jobs/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = job_dtos.JobDTO{}
+
+// JobController handles background job inspection and cancellation
+type JobController struct {
+	runner *services.JobRunnerService
+}
+
+// NewJobController creates a new JobController instance
+func NewJobController(runner *services.JobRunnerService) *JobController {
+	return &JobController{
+		runner: runner,
+	}
+}
+
+// ListJobs handles GET /api/jobs endpoint
+// @Summary      List Jobs
+// @Description  Lists background jobs, newest first. Finished jobs are kept for JOB_RETENTION. Callers only see the jobs their own API key or identity enqueued; admin keys see every job.
+// @Tags         09. Jobs
+// @Produce      json
+// @Param        status  query  string  false  "Filter by status (queued, running, succeeded, failed, cancelled)"
+// @Param        type    query  string  false  "Filter by job type"
+// @Success      200  {object}  dtos.StandardResponse{data=job_dtos.JobListResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/jobs [get]
+func (c *JobController) ListJobs(ctx *gin.Context) {
+	jobs, err := c.runner.List(ctx.Query("status"), ctx.Query("type"))
+	if err != nil {
+		utils.LogError("ListJobs failed: %v", err)
+		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	caller := utils.APIKeyIdentityFromContext(ctx.Request.Context())
+	jobDTOs := make([]job_dtos.JobDTO, 0, len(jobs))
+	for i := range jobs {
+		if jobs[i].VisibleTo(caller) {
+			jobDTOs = append(jobDTOs, toJobDTO(&jobs[i]))
+		}
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Jobs fetched successfully",
+		Data: job_dtos.JobListResponseDTO{
+			Jobs:  jobDTOs,
+			Total: len(jobDTOs),
+		},
+	})
+}
+
+// GetJob handles GET /api/jobs/{id} endpoint
+// @Summary      Get Job
+// @Description  Returns the status, progress and result of a background job. Jobs enqueued by other callers are reported as not found, except to admin keys.
+// @Tags         09. Jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  dtos.StandardResponse{data=job_dtos.JobDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/jobs/{id} [get]
+func (c *JobController) GetJob(ctx *gin.Context) {
+	job, err := c.visibleJob(ctx)
+	if err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Job fetched successfully",
+		Data:    toJobDTO(job),
+	})
+}
+
+// CancelJob handles POST /api/jobs/{id}/cancel endpoint
+// @Summary      Cancel Job
+// @Description  Cancels a queued or running job. Running jobs stop at their next cancellation check. Jobs enqueued by other callers are reported as not found, except to admin keys.
+// @Tags         09. Jobs
+// @Produce      json
+// @Param        id   path      string  true  "Job ID"
+// @Success      200  {object}  dtos.StandardResponse{data=job_dtos.JobDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/jobs/{id}/cancel [post]
+func (c *JobController) CancelJob(ctx *gin.Context) {
+	if _, err := c.visibleJob(ctx); err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+	job, err := c.runner.Cancel(ctx.Param("id"))
+	if err != nil {
+		c.writeError(ctx, err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Job cancelled successfully",
+		Data:    toJobDTO(job),
+	})
+}
+
+// visibleJob loads the job named in the route, hiding jobs the caller may not see as not found.
+func (c *JobController) visibleJob(ctx *gin.Context) (*entities.Job, error) {
+	job, err := c.runner.Get(ctx.Param("id"))
+	if err != nil {
+		return nil, err
+	}
+	if !job.VisibleTo(utils.APIKeyIdentityFromContext(ctx.Request.Context())) {
+		return nil, services.ErrJobNotFound
+	}
+	return job, nil
+}
+
+// writeError maps runner errors to HTTP status codes.
+func (c *JobController) writeError(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	switch {
+	case errors.Is(err, services.ErrJobNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, services.ErrJobFinished):
+		status = http.StatusConflict
+	default:
+		utils.LogError("JobController: %v", err)
+	}
+
+	ctx.JSON(status, dtos.StandardResponse{
+		Status:  false,
+		Message: err.Error(),
+		Data:    nil,
+	})
+}
+
+// toJobDTO converts a stored job into its API representation.
+func toJobDTO(job *entities.Job) job_dtos.JobDTO {
+	var result interface{}
+	if len(job.Result) > 0 {
+		_ = json.Unmarshal(job.Result, &result)
+	}
+
+	return job_dtos.JobDTO{
+		ID:          job.ID,
+		Type:        job.Type,
+		Status:      job.Status,
+		Progress:    job.Progress,
+		Message:     job.Message,
+		Error:       job.Error,
+		Result:      result,
+		Attempts:    job.Attempts,
+		MaxAttempts: job.MaxAttempts,
+		CreatedAt:   job.CreatedAt,
+		UpdatedAt:   job.UpdatedAt,
+		StartedAt:   job.StartedAt,
+		FinishedAt:  job.FinishedAt,
+		RunAt:       job.RunAt,
+	}
+}
@@ -0,0 +1,25 @@
+package dtos
+
+// JobDTO is the API representation of a background job
+type JobDTO struct {
+	ID          string      `json:"id"`
+	Type        string      `json:"type"`
+	Status      string      `json:"status"`
+	Progress    int         `json:"progress"`
+	Message     string      `json:"message,omitempty"`
+	Error       string      `json:"error,omitempty"`
+	Result      interface{} `json:"result,omitempty"`
+	Attempts    int         `json:"attempts"`
+	MaxAttempts int         `json:"max_attempts"`
+	CreatedAt   int64       `json:"created_at"`
+	UpdatedAt   int64       `json:"updated_at"`
+	StartedAt   int64       `json:"started_at,omitempty"`
+	FinishedAt  int64       `json:"finished_at,omitempty"`
+	RunAt       int64       `json:"run_at,omitempty"`
+}
+
+// JobListResponseDTO wraps a list of jobs
+type JobListResponseDTO struct {
+	Jobs  []JobDTO `json:"jobs"`
+	Total int      `json:"total"`
+}
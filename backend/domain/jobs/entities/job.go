@@ -0,0 +1,53 @@
+package entities
+
+import (
+	"encoding/json"
+	"teralux_app/domain/common/utils"
+)
+
+// Job statuses.
+const (
+	JobStatusQueued    = "queued"
+	JobStatusRunning   = "running"
+	JobStatusSucceeded = "succeeded"
+	JobStatusFailed    = "failed"
+	JobStatusCancelled = "cancelled"
+)
+
+// Job is a persisted background operation executed by the job runner. Owner is the verified caller whose
+// request enqueued the job, empty for jobs started by the server itself (deliveries, schedules, ...).
+type Job struct {
+	ID          string               `json:"id"`
+	Type        string               `json:"type"`
+	Owner       utils.APIKeyIdentity `json:"owner"`
+	Status      string               `json:"status"`
+	Payload     json.RawMessage      `json:"payload,omitempty"`
+	Result      json.RawMessage      `json:"result,omitempty"`
+	Progress    int                  `json:"progress"`
+	Message     string               `json:"message,omitempty"`
+	Error       string               `json:"error,omitempty"`
+	Attempts    int                  `json:"attempts"`
+	MaxAttempts int                  `json:"max_attempts"`
+	CreatedAt   int64                `json:"created_at"`
+	UpdatedAt   int64                `json:"updated_at"`
+	StartedAt   int64                `json:"started_at,omitempty"`
+	FinishedAt  int64                `json:"finished_at,omitempty"`
+	RunAt       int64                `json:"run_at,omitempty"`
+}
+
+// VisibleTo reports whether a caller may see and cancel the job: admins see every job, other callers
+// only the jobs their own requests enqueued.
+//
+// param caller The verified caller of the request.
+// return bool True if the caller may see the job.
+func (j *Job) VisibleTo(caller utils.APIKeyIdentity) bool {
+	if utils.APIKeyScopeAllows(caller.Scope, utils.APIKeyScopeAdmin) {
+		return true
+	}
+	return caller.Actor() != "" && caller.Actor() == j.Owner.Actor()
+}
+
+// IsFinished reports whether the job reached a terminal status.
+func (j *Job) IsFinished() bool {
+	return j.Status == JobStatusSucceeded || j.Status == JobStatusFailed || j.Status == JobStatusCancelled
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/jobs/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupJobRoutes registers the background job endpoints.
+//
+// param router The Gin router interface.
+// param controller The controller handling job inspection and cancellation.
+func SetupJobRoutes(router gin.IRouter, controller *controllers.JobController) {
+	utils.LogDebug("SetupJobRoutes initialized")
+	api := router.Group("/api/jobs")
+	{
+		// GET /api/jobs
+		// Lists background jobs with optional status and type filters.
+		api.GET("", controller.ListJobs)
+
+		// GET /api/jobs/:id
+		// Returns a single job with its progress and result.
+		api.GET("/:id", controller.GetJob)
+
+		// POST /api/jobs/:id/cancel
+		// Cancels a queued or running job.
+		api.POST("/:id/cancel", controller.CancelJob)
+	}
+}
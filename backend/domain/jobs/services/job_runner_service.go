@@ -0,0 +1,563 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/jobs/entities"
+	"time"
+)
+
+const (
+	// jobKeyPrefix is the storage prefix for persisted jobs. It is outside "cache:" so flushing the cache keeps job history.
+	jobKeyPrefix = "job:"
+
+	defaultJobWorkers   = 2
+	defaultJobRetention = 7 * 24 * time.Hour
+	defaultMaxAttempts  = 3
+
+	// maxRetryBackoff caps the exponential delay between attempts.
+	maxRetryBackoff = 5 * time.Minute
+)
+
+// ErrJobNotFound is returned when a job ID does not exist.
+var ErrJobNotFound = errors.New("job not found")
+
+// ErrJobFinished is returned when cancelling a job that already reached a terminal status.
+var ErrJobFinished = errors.New("job already finished")
+
+// JobHandler executes one attempt of a job. Returning an error schedules a retry until the attempt limit is reached,
+// unless the error is wrapped with Permanent. A result returned with the error of the final attempt is kept
+// on the failed job (e.g., partial progress). Handlers must stop when ctx is cancelled.
+type JobHandler func(ctx context.Context, job *JobContext) (interface{}, error)
+
+// permanentError marks a handler error that retrying cannot fix.
+type permanentError struct {
+	err error
+}
+
+// Error returns the message of the wrapped error.
+func (e *permanentError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error.
+func (e *permanentError) Unwrap() error {
+	return e.err
+}
+
+// Permanent wraps a handler error so the job fails immediately instead of being retried
+// (e.g., a rejected request, as opposed to a network timeout).
+//
+// param err The error to wrap.
+// return error The wrapped error.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// JobContext gives a running handler access to its payload and lets it report progress.
+type JobContext struct {
+	runner *JobRunnerService
+	job    *entities.Job
+}
+
+// ID returns the ID of the running job.
+//
+// return string The job ID.
+func (jc *JobContext) ID() string {
+	return jc.job.ID
+}
+
+// Attempt returns the 1-based number of the current attempt.
+//
+// return int The attempt number.
+func (jc *JobContext) Attempt() int {
+	return jc.job.Attempts
+}
+
+// DecodePayload unmarshals the job payload into target.
+//
+// param target A pointer to the value receiving the payload.
+// return error An error if the payload cannot be decoded.
+func (jc *JobContext) DecodePayload(target interface{}) error {
+	if len(jc.job.Payload) == 0 {
+		return nil
+	}
+	return json.Unmarshal(jc.job.Payload, target)
+}
+
+// ReportProgress records the completion percentage and an optional status message.
+//
+// param progress The completion percentage (0-100).
+// param message A short human-readable status.
+func (jc *JobContext) ReportProgress(progress int, message string) {
+	if progress < 0 {
+		progress = 0
+	}
+	if progress > 100 {
+		progress = 100
+	}
+	jc.runner.update(jc.job.ID, func(job *entities.Job) {
+		job.Progress = progress
+		job.Message = message
+	})
+}
+
+// JobRunnerService executes persisted background jobs with a fixed worker pool.
+// Jobs survive restarts: anything queued or running when the process stopped is re-queued on Start.
+type JobRunnerService struct {
+	store     persistence.CacheStore
+	handlers  map[string]JobHandler
+	queue     chan string
+	workers   int
+	retention time.Duration
+	clock     utils.Clock
+	ids       utils.IDGenerator
+
+	mu      sync.Mutex
+	running map[string]context.CancelFunc
+	started bool
+	pool    utils.WorkerGroup
+}
+
+// NewJobRunnerService initializes a new JobRunnerService.
+//
+// param store The CacheStore used to persist jobs.
+// param clock The Clock used for job timestamps.
+// param ids The IDGenerator used for job IDs.
+// return *JobRunnerService A pointer to the initialized runner.
+func NewJobRunnerService(store persistence.CacheStore, clock utils.Clock, ids utils.IDGenerator) *JobRunnerService {
+	config := utils.GetConfig()
+
+	workers, err := strconv.Atoi(config.JobWorkers)
+	if err != nil || workers <= 0 {
+		workers = defaultJobWorkers
+	}
+	retention, err := time.ParseDuration(config.JobRetention)
+	if err != nil || retention <= 0 {
+		retention = defaultJobRetention
+	}
+
+	return &JobRunnerService{
+		store:     store,
+		handlers:  make(map[string]JobHandler),
+		queue:     make(chan string, 256),
+		workers:   workers,
+		retention: retention,
+		running:   make(map[string]context.CancelFunc),
+		clock:     clock,
+		ids:       ids,
+	}
+}
+
+// Register associates a job type with its handler. It must be called before Start.
+//
+// param jobType The job type name (e.g., "cache_warm").
+// param handler The function executing the job.
+func (s *JobRunnerService) Register(jobType string, handler JobHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handlers[jobType] = handler
+}
+
+// Start launches the worker pool and re-queues jobs left unfinished by a previous run. Delayed jobs
+// that are not due yet keep waiting for the rest of their delay.
+func (s *JobRunnerService) Start() {
+	s.mu.Lock()
+	if s.started {
+		s.mu.Unlock()
+		return
+	}
+	s.started = true
+	s.mu.Unlock()
+
+	for i := 0; i < s.workers; i++ {
+		s.pool.Go(s.worker)
+	}
+
+	if s.store == nil {
+		utils.LogWarn("JobRunnerService: Storage not initialized, jobs are disabled")
+		return
+	}
+
+	jobs, err := s.List("", "")
+	if err != nil {
+		utils.LogError("JobRunnerService: Failed to load jobs on start: %v", err)
+		return
+	}
+	resumed := 0
+	for _, job := range jobs {
+		if job.IsFinished() {
+			continue
+		}
+		s.update(job.ID, func(j *entities.Job) {
+			j.Status = entities.JobStatusQueued
+		})
+		var delay time.Duration
+		if job.RunAt > 0 && job.Attempts == 0 {
+			delay = time.Unix(job.RunAt, 0).Sub(s.clock.Now())
+		}
+		s.dispatch(job.ID, delay)
+		resumed++
+	}
+	utils.LogInfo("JobRunnerService: Started %d workers (%d jobs resumed)", s.workers, resumed)
+}
+
+// Enqueue persists a new job and schedules it for execution. The verified caller in ctx becomes the owner
+// of the job, which restricts who can see and cancel it through the job endpoints.
+//
+// param ctx The request context carrying the caller (context.Background() for jobs started by the server).
+// param jobType The registered job type.
+// param payload The job input, stored as JSON.
+// param maxAttempts The number of attempts before the job fails (0 uses the default of 3).
+// return *entities.Job The queued job.
+// return error An error if the type is unknown or the job cannot be persisted.
+func (s *JobRunnerService) Enqueue(ctx context.Context, jobType string, payload interface{}, maxAttempts int) (*entities.Job, error) {
+	return s.enqueue(ctx, jobType, payload, maxAttempts, 0)
+}
+
+// EnqueueAfter persists a new job that runs once the delay has passed. The job stays queued until then
+// and can be cancelled like any other; a restart keeps the original due time.
+//
+// param ctx The request context carrying the caller (context.Background() for jobs started by the server).
+// param jobType The registered job type.
+// param payload The job input, stored as JSON.
+// param maxAttempts The number of attempts before the job fails (0 uses the default of 3).
+// param delay How long to wait before the first attempt.
+// return *entities.Job The queued job, with RunAt set to its due time.
+// return error An error if the type is unknown or the job cannot be persisted.
+func (s *JobRunnerService) EnqueueAfter(ctx context.Context, jobType string, payload interface{}, maxAttempts int, delay time.Duration) (*entities.Job, error) {
+	return s.enqueue(ctx, jobType, payload, maxAttempts, delay)
+}
+
+// enqueue persists a new job and dispatches it after the delay.
+func (s *JobRunnerService) enqueue(ctx context.Context, jobType string, payload interface{}, maxAttempts int, delay time.Duration) (*entities.Job, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("job storage not initialized")
+	}
+
+	s.mu.Lock()
+	_, ok := s.handlers[jobType]
+	s.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown job type: %s", jobType)
+	}
+
+	payloadData, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal job payload: %w", err)
+	}
+
+	randomID, err := s.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate job id: %w", err)
+	}
+
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+
+	now := s.clock.Now().Unix()
+	job := &entities.Job{
+		ID:          fmt.Sprintf("%d-%s", s.clock.Now().UnixMilli(), randomID),
+		Type:        jobType,
+		Owner:       utils.APIKeyIdentityFromContext(ctx),
+		Status:      entities.JobStatusQueued,
+		Payload:     payloadData,
+		MaxAttempts: maxAttempts,
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if delay > 0 {
+		job.RunAt = s.clock.Now().Add(delay).Unix()
+	}
+	if err := s.save(job); err != nil {
+		return nil, err
+	}
+
+	if delay > 0 {
+		utils.LogInfo("JobRunnerService: Enqueued %s job %s to run in %s", jobType, job.ID, delay)
+	} else {
+		utils.LogInfo("JobRunnerService: Enqueued %s job %s", jobType, job.ID)
+	}
+	s.dispatch(job.ID, delay)
+	return job, nil
+}
+
+// Get returns a job by ID.
+//
+// param id The job ID.
+// return *entities.Job The job.
+// return error ErrJobNotFound if the job does not exist.
+func (s *JobRunnerService) Get(id string) (*entities.Job, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("job storage not initialized")
+	}
+	jsonData, err := s.store.Get(jobKeyPrefix + id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get job: %w", err)
+	}
+	if jsonData == nil {
+		return nil, ErrJobNotFound
+	}
+	var job entities.Job
+	if err := json.Unmarshal(jsonData, &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job: %w", err)
+	}
+	return &job, nil
+}
+
+// List returns persisted jobs, newest first, optionally filtered by status and type.
+//
+// param status Only return jobs with this status (empty for all).
+// param jobType Only return jobs of this type (empty for all).
+// return []entities.Job The matching jobs.
+// return error An error if the jobs cannot be read.
+func (s *JobRunnerService) List(status, jobType string) ([]entities.Job, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("job storage not initialized")
+	}
+	keys, err := s.store.GetAllKeysWithPrefix(jobKeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jobs: %w", err)
+	}
+
+	jobs := make([]entities.Job, 0, len(keys))
+	for _, key := range keys {
+		job, err := s.Get(key[len(jobKeyPrefix):])
+		if err != nil {
+			continue
+		}
+		if status != "" && job.Status != status {
+			continue
+		}
+		if jobType != "" && job.Type != jobType {
+			continue
+		}
+		jobs = append(jobs, *job)
+	}
+
+	sort.Slice(jobs, func(i, j int) bool {
+		return jobs[i].CreatedAt > jobs[j].CreatedAt
+	})
+	return jobs, nil
+}
+
+// Cancel stops a queued or running job. Running handlers observe the cancellation through their context.
+//
+// param id The job ID.
+// return *entities.Job The job after cancellation.
+// return error ErrJobNotFound or ErrJobFinished if the job cannot be cancelled.
+func (s *JobRunnerService) Cancel(id string) (*entities.Job, error) {
+	job, err := s.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if job.IsFinished() {
+		return job, ErrJobFinished
+	}
+
+	s.mu.Lock()
+	cancel, running := s.running[id]
+	s.mu.Unlock()
+	if running {
+		cancel()
+	}
+
+	job = s.update(id, func(j *entities.Job) {
+		j.Status = entities.JobStatusCancelled
+		j.FinishedAt = s.clock.Now().Unix()
+	})
+	if job == nil {
+		return nil, fmt.Errorf("failed to cancel job %s", id)
+	}
+	utils.LogInfo("JobRunnerService: Cancelled job %s", id)
+	return job, nil
+}
+
+// dispatch hands a job to the worker pool after an optional delay.
+func (s *JobRunnerService) dispatch(id string, delay time.Duration) {
+	go func() {
+		if delay > 0 {
+			time.Sleep(delay)
+		}
+		s.queue <- id
+	}()
+}
+
+// QueueDepth returns the number of jobs waiting for a free worker.
+//
+// return int The number of queued jobs.
+func (s *JobRunnerService) QueueDepth() int {
+	return len(s.queue)
+}
+
+// Stop stops the workers from taking new jobs and waits for the attempts in progress to finish.
+// Jobs still queued are resumed by Start on the next run.
+func (s *JobRunnerService) Stop() {
+	s.pool.Stop()
+}
+
+// worker executes queued jobs until the runner is stopped.
+func (s *JobRunnerService) worker(stop <-chan struct{}) {
+	for {
+		select {
+		case id := <-s.queue:
+			s.execute(id)
+		case <-stop:
+			return
+		}
+	}
+}
+
+// execute runs a single attempt of a job and records the outcome.
+func (s *JobRunnerService) execute(id string) {
+	job, err := s.Get(id)
+	if err != nil {
+		utils.LogWarn("JobRunnerService: Skipping job %s: %v", id, err)
+		return
+	}
+	if job.Status != entities.JobStatusQueued {
+		return
+	}
+
+	s.mu.Lock()
+	handler, ok := s.handlers[job.Type]
+	s.mu.Unlock()
+	if !ok {
+		s.finish(id, entities.JobStatusFailed, nil, fmt.Errorf("no handler registered for job type %s", job.Type))
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	s.mu.Lock()
+	s.running[id] = cancel
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.running, id)
+		s.mu.Unlock()
+		cancel()
+	}()
+
+	job = s.update(id, func(j *entities.Job) {
+		j.Status = entities.JobStatusRunning
+		j.Attempts++
+		j.Error = ""
+		if j.StartedAt == 0 {
+			j.StartedAt = s.clock.Now().Unix()
+		}
+	})
+	utils.LogDebug("JobRunnerService: Running %s job %s (attempt %d/%d)", job.Type, id, job.Attempts, job.MaxAttempts)
+
+	result, runErr := s.runHandler(ctx, handler, &JobContext{runner: s, job: job})
+
+	if ctx.Err() != nil {
+		// Cancel already persisted the cancelled status
+		return
+	}
+	if runErr == nil {
+		s.finish(id, entities.JobStatusSucceeded, result, nil)
+		return
+	}
+
+	var permanent *permanentError
+	if job.Attempts < job.MaxAttempts && !errors.As(runErr, &permanent) {
+		backoff := time.Duration(1<<uint(job.Attempts)) * time.Second
+		if backoff > maxRetryBackoff {
+			backoff = maxRetryBackoff
+		}
+		utils.LogWarn("JobRunnerService: Job %s attempt %d failed, retrying in %s: %v", id, job.Attempts, backoff, runErr)
+		s.update(id, func(j *entities.Job) {
+			j.Status = entities.JobStatusQueued
+			j.Error = runErr.Error()
+		})
+		s.dispatch(id, backoff)
+		return
+	}
+
+	utils.LogError("JobRunnerService: Job %s failed after %d attempts: %v", id, job.Attempts, runErr)
+	s.finish(id, entities.JobStatusFailed, result, runErr)
+}
+
+// runHandler invokes a handler, converting panics into errors so one bad job cannot stop a worker.
+func (s *JobRunnerService) runHandler(ctx context.Context, handler JobHandler, jc *JobContext) (result interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("job panicked: %v", r)
+		}
+	}()
+	return handler(ctx, jc)
+}
+
+// finish records a terminal status. Finished jobs expire after the retention period.
+func (s *JobRunnerService) finish(id, status string, result interface{}, runErr error) {
+	var resultData json.RawMessage
+	if result != nil {
+		if data, err := json.Marshal(result); err == nil {
+			resultData = data
+		}
+	}
+	s.update(id, func(j *entities.Job) {
+		j.Status = status
+		j.Result = resultData
+		if runErr != nil {
+			j.Error = runErr.Error()
+		}
+		if status == entities.JobStatusSucceeded {
+			j.Progress = 100
+		}
+		j.FinishedAt = s.clock.Now().Unix()
+	})
+}
+
+// update applies a mutation to a stored job and persists it. Updates to jobs already cancelled are ignored,
+// so a handler that keeps reporting progress after cancellation cannot revive the job.
+func (s *JobRunnerService) update(id string, mutate func(job *entities.Job)) *entities.Job {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	job, err := s.Get(id)
+	if err != nil {
+		utils.LogWarn("JobRunnerService: Failed to update job %s: %v", id, err)
+		return nil
+	}
+	if job.Status == entities.JobStatusCancelled {
+		return job
+	}
+
+	mutate(job)
+	job.UpdatedAt = s.clock.Now().Unix()
+	if err := s.save(job); err != nil {
+		utils.LogError("JobRunnerService: %v", err)
+	}
+	return job
+}
+
+// save persists a job. Active jobs never expire; finished jobs are kept for the retention period.
+func (s *JobRunnerService) save(job *entities.Job) error {
+	jsonData, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job: %w", err)
+	}
+
+	key := jobKeyPrefix + job.ID
+	if job.IsFinished() {
+		err = s.store.Set(key, jsonData, s.retention)
+	} else {
+		err = s.store.SetPersistent(key, jsonData)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save job: %w", err)
+	}
+	return nil
+}
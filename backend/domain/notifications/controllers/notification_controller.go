@@ -0,0 +1,245 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+	"teralux_app/domain/common/utils"
+	notification_dtos "teralux_app/domain/notifications/dtos"
+	"teralux_app/domain/notifications/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController handles the notification rules of the caller
+type NotificationController struct {
+	useCase *usecases.NotificationUseCase
+}
+
+// NewNotificationController creates a new NotificationController instance
+func NewNotificationController(useCase *usecases.NotificationUseCase) *NotificationController {
+	return &NotificationController{
+		useCase: useCase,
+	}
+}
+
+// CreateRule handles POST /api/notifications/rules endpoint
+// @Summary      Create Notification Rule
+// @Description  Creates an alert for devices offline for at least offline_minutes (all visible devices when device_ids is empty), delivered to webhook URLs, email addresses or Telegram chat IDs. Each offline episode is alerted once; alerts for the same device within cooldown_minutes (default 60) are suppressed. With notify_online, a recovery notice follows the alert. Requires an API key (or identity token) of control scope, which owns the rule: alerts are only sent for devices the key may access, and stop when it is revoked. Non-admin keys are only alerted about the devices claimed by their tenant (X-TUYA-UID if allowlisted for the key, else the default user).
+// @Tags         20. Notifications
+// @Accept       json
+// @Produce      json
+// @Param        request  body  notification_dtos.NotificationRuleRequestDTO  true  "Notification rule"
+// @Success      201  {object}  dtos.StandardResponse{data=notification_dtos.NotificationRuleDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/notifications/rules [post]
+func (c *NotificationController) CreateRule(ctx *gin.Context) {
+	var req notification_dtos.NotificationRuleRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	uid, ok := resolveTenantUID(ctx)
+	if !ok {
+		return
+	}
+
+	rule, err := c.useCase.CreateRule(ctx.Request.Context(), uid, req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "CreateRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Notification rule created",
+		Data:    rule,
+	})
+}
+
+// ListRules handles GET /api/notifications/rules endpoint
+// @Summary      List Notification Rules
+// @Description  Lists the caller's notification rules with the outcome of their latest delivery.
+// @Tags         20. Notifications
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]notification_dtos.NotificationRuleDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/notifications/rules [get]
+func (c *NotificationController) ListRules(ctx *gin.Context) {
+	rules, err := c.useCase.ListRules(ruleOwner(ctx))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "ListRules", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Notification rules fetched successfully",
+		Data:    rules,
+	})
+}
+
+// GetRule handles GET /api/notifications/rules/{id} endpoint
+// @Summary      Get Notification Rule
+// @Description  Returns a notification rule of the caller.
+// @Tags         20. Notifications
+// @Produce      json
+// @Param        id  path  string  true  "Notification rule ID"
+// @Success      200  {object}  dtos.StandardResponse{data=notification_dtos.NotificationRuleDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/notifications/rules/{id} [get]
+func (c *NotificationController) GetRule(ctx *gin.Context) {
+	rule, err := c.useCase.GetRule(ruleOwner(ctx), ctx.Param("id"))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "GetRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Notification rule fetched successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateRule handles PUT /api/notifications/rules/{id} endpoint
+// @Summary      Update Notification Rule
+// @Description  Replaces the devices, thresholds and channels of a notification rule. Devices still offline are evaluated against the new thresholds.
+// @Tags         20. Notifications
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                                        true  "Notification rule ID"
+// @Param        request  body  notification_dtos.NotificationRuleRequestDTO  true  "Notification rule"
+// @Success      200  {object}  dtos.StandardResponse{data=notification_dtos.NotificationRuleDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/notifications/rules/{id} [put]
+func (c *NotificationController) UpdateRule(ctx *gin.Context) {
+	var req notification_dtos.NotificationRuleRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	rule, err := c.useCase.UpdateRule(ctx.Request.Context(), ruleOwner(ctx), ctx.Param("id"), req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "UpdateRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Notification rule updated",
+		Data:    rule,
+	})
+}
+
+// DeleteRule handles DELETE /api/notifications/rules/{id} endpoint
+// @Summary      Delete Notification Rule
+// @Description  Removes a notification rule. Notifications still queued for it are dropped.
+// @Tags         20. Notifications
+// @Produce      json
+// @Param        id  path  string  true  "Notification rule ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/notifications/rules/{id} [delete]
+func (c *NotificationController) DeleteRule(ctx *gin.Context) {
+	if err := c.useCase.DeleteRule(ruleOwner(ctx), ctx.Param("id")); err != nil {
+		middlewares.AbortWithError(ctx, "DeleteRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Notification rule deleted",
+		Data:    nil,
+	})
+}
+
+// TestRule handles POST /api/notifications/rules/{id}/test endpoint
+// @Summary      Test Notification Rule
+// @Description  Sends a test notification to every channel of the rule right away, without retries or cooldown, and reports the outcome per channel.
+// @Tags         20. Notifications
+// @Produce      json
+// @Param        id  path  string  true  "Notification rule ID"
+// @Success      200  {object}  dtos.StandardResponse{data=[]notification_dtos.NotificationTestResultDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/notifications/rules/{id}/test [post]
+func (c *NotificationController) TestRule(ctx *gin.Context) {
+	results, err := c.useCase.TestRule(ctx.Request.Context(), ruleOwner(ctx), ctx.Param("id"))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "TestRule", err)
+		return
+	}
+
+	delivered := true
+	for _, result := range results {
+		delivered = delivered && result.Delivered
+	}
+	message := "Test notification delivered"
+	if !delivered {
+		message = "Test notification could not be delivered to every channel"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  delivered,
+		Message: message,
+		Data:    results,
+	})
+}
+
+// ruleOwner returns the caller owning the notification rules of the request: the API key or identity provider
+// user verified by ApiKeyMiddleware.
+func ruleOwner(ctx *gin.Context) string {
+	return utils.APIKeyIdentityFromContext(ctx.Request.Context()).Actor()
+}
+
+// resolveTenantUID returns the Tuya UID whose claimed devices a new rule alerts about: none for admin keys,
+// which see every device, else the X-TUYA-UID allowlisted for the caller, or the default user.
+// It answers 403 for a UID that is not allowlisted, and 500 when no default user is configured.
+func resolveTenantUID(ctx *gin.Context) (string, bool) {
+	if ctx.GetString("api_key_scope") == utils.APIKeyScopeAdmin {
+		return "", true
+	}
+	if uid := ctx.GetHeader("X-TUYA-UID"); uid != "" {
+		if !utils.GetConfig().IsUIDAllowed(utils.APIKeyIdentityFromContext(ctx.Request.Context()), uid) {
+			ctx.JSON(http.StatusForbidden, dtos.StandardResponse{
+				Status:  false,
+				Message: "X-TUYA-UID is not allowed for this API key",
+				Data:    nil,
+			})
+			return "", false
+		}
+		return uid, true
+	}
+
+	uid := utils.GetConfig().TuyaUserID
+	if uid == "" {
+		utils.LogError("TUYA_USER_ID is not set in environment")
+		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Server configuration error: TUYA_USER_ID missing",
+			Data:    nil,
+		})
+		return "", false
+	}
+	return uid, true
+}
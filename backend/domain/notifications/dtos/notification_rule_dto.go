@@ -0,0 +1,58 @@
+package dtos
+
+// NotificationRuleRequestDTO creates or replaces a notification rule.
+// offline_minutes 0 alerts as soon as the device goes offline; cooldown_minutes defaults to 60
+type NotificationRuleRequestDTO struct {
+	Name            string                   `json:"name" binding:"max=100" example:"Freezer offline"`
+	DeviceIDs       []string                 `json:"device_ids"`
+	OfflineMinutes  int                      `json:"offline_minutes" binding:"min=0,max=10080" example:"10"`
+	NotifyOnline    bool                     `json:"notify_online" example:"true"`
+	CooldownMinutes *int                     `json:"cooldown_minutes" binding:"omitempty,min=0,max=10080" example:"60"`
+	Channels        []NotificationChannelDTO `json:"channels" binding:"required,min=1,max=5,dive"`
+	Enabled         *bool                    `json:"enabled"`
+}
+
+// NotificationChannelDTO is a delivery target: a webhook URL, an email address (needs SMTP_HOST) or a
+// Telegram chat ID (needs TELEGRAM_BOT_TOKEN)
+type NotificationChannelDTO struct {
+	Type   string `json:"type" binding:"required,oneof=webhook email telegram" example:"telegram"`
+	Target string `json:"target" binding:"required,max=500" example:"123456789"`
+}
+
+// NotificationRuleDTO is a notification rule
+type NotificationRuleDTO struct {
+	ID              string                   `json:"id"`
+	Name            string                   `json:"name"`
+	DeviceIDs       []string                 `json:"device_ids,omitempty"`
+	OfflineMinutes  int                      `json:"offline_minutes"`
+	NotifyOnline    bool                     `json:"notify_online"`
+	CooldownMinutes int                      `json:"cooldown_minutes"`
+	Channels        []NotificationChannelDTO `json:"channels"`
+	Enabled         bool                     `json:"enabled"`
+	CreatedAt       int64                    `json:"created_at"`
+	UpdatedAt       int64                    `json:"updated_at"`
+	LastNotifiedAt  int64                    `json:"last_notified_at,omitempty"`
+	LastError       string                   `json:"last_error,omitempty"`
+}
+
+// NotificationDTO is a notification as posted to webhook channels; email and Telegram channels receive
+// its message as text
+type NotificationDTO struct {
+	ID           string `json:"id"`
+	Event        string `json:"event"`
+	RuleID       string `json:"rule_id"`
+	RuleName     string `json:"rule_name,omitempty"`
+	DeviceID     string `json:"device_id,omitempty"`
+	Category     string `json:"category,omitempty"`
+	OfflineSince int64  `json:"offline_since,omitempty"`
+	Message      string `json:"message"`
+	Timestamp    int64  `json:"timestamp"`
+}
+
+// NotificationTestResultDTO is the outcome of a test notification on one channel
+type NotificationTestResultDTO struct {
+	Type      string `json:"type"`
+	Target    string `json:"target"`
+	Delivered bool   `json:"delivered"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,44 @@
+package entities
+
+import "teralux_app/domain/common/utils"
+
+// Notification channel types.
+const (
+	NotificationChannelWebhook  = "webhook"
+	NotificationChannelEmail    = "email"
+	NotificationChannelTelegram = "telegram"
+)
+
+// Notification events.
+const (
+	NotificationEventDeviceOffline = "device_offline"
+	NotificationEventDeviceOnline  = "device_online"
+	NotificationEventTest          = "test"
+)
+
+// NotificationRule alerts its channels when a device stays offline longer than OfflineMinutes, and
+// optionally when it comes back online. Alerts for the same device are at most one per CooldownMinutes.
+// Owner is the API key or user that created it; OwnerUID is the tenant whose claimed devices it alerts about
+// (empty for admins, who are alerted about every device).
+type NotificationRule struct {
+	ID              string                `json:"id"`
+	Owner           utils.APIKeyIdentity  `json:"owner"`
+	OwnerUID        string                `json:"owner_uid"`
+	Name            string                `json:"name"`
+	DeviceIDs       []string              `json:"device_ids,omitempty"`
+	OfflineMinutes  int                   `json:"offline_minutes"`
+	NotifyOnline    bool                  `json:"notify_online"`
+	CooldownMinutes int                   `json:"cooldown_minutes"`
+	Channels        []NotificationChannel `json:"channels"`
+	Enabled         bool                  `json:"enabled"`
+	CreatedAt       int64                 `json:"created_at"`
+	UpdatedAt       int64                 `json:"updated_at"`
+	LastNotifiedAt  int64                 `json:"last_notified_at,omitempty"`
+	LastError       string                `json:"last_error,omitempty"`
+}
+
+// NotificationChannel is where a rule delivers: a webhook URL, an email address or a Telegram chat ID.
+type NotificationChannel struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/notifications/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupNotificationRoutes registers endpoints for managing the caller's notification rules.
+//
+// param router The Gin router interface.
+// param controller The controller handling notification rules.
+func SetupNotificationRoutes(router gin.IRouter, controller *controllers.NotificationController) {
+	utils.LogDebug("SetupNotificationRoutes initialized")
+	api := router.Group("/api/notifications/rules")
+	{
+		// POST /api/notifications/rules
+		// Creates a device offline/online alert.
+		api.POST("", controller.CreateRule)
+
+		// GET /api/notifications/rules
+		// Lists the caller's notification rules.
+		api.GET("", controller.ListRules)
+
+		// GET /api/notifications/rules/:id
+		// Returns a notification rule.
+		api.GET("/:id", controller.GetRule)
+
+		// PUT /api/notifications/rules/:id
+		// Replaces the devices, thresholds and channels of a rule.
+		api.PUT("/:id", controller.UpdateRule)
+
+		// DELETE /api/notifications/rules/:id
+		// Removes a notification rule.
+		api.DELETE("/:id", controller.DeleteRule)
+
+		// POST /api/notifications/rules/:id/test
+		// Sends a test notification to every channel of a rule.
+		api.POST("/:id/test", controller.TestRule)
+	}
+}
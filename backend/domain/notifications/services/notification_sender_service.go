@@ -0,0 +1,254 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/url"
+	"strings"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"teralux_app/domain/common/infrastructure/outbound"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/notifications/dtos"
+	"teralux_app/domain/notifications/entities"
+	"time"
+)
+
+const (
+	// defaultSMTPPort is used when SMTP_PORT is not set.
+	defaultSMTPPort = "587"
+	// defaultTelegramAPIURL is used when TELEGRAM_API_URL is not set.
+	defaultTelegramAPIURL = "https://api.telegram.org"
+	// senderTimeout bounds a single delivery.
+	senderTimeout = 10 * time.Second
+)
+
+// NotificationSenderService delivers notifications to webhook URLs (through the outbound guard), to email
+// addresses over SMTP (SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD, SMTP_FROM) and to Telegram chats
+// through a bot (TELEGRAM_BOT_TOKEN, TELEGRAM_API_URL).
+type NotificationSenderService struct {
+	webhookClient  *http.Client
+	telegramClient *http.Client
+
+	smtpHost     string
+	smtpPort     string
+	smtpUsername string
+	smtpPassword string
+	smtpFrom     string
+
+	telegramToken  string
+	telegramAPIURL string
+}
+
+// NewNotificationSenderService initializes a new NotificationSenderService from the SMTP and Telegram settings.
+//
+// param guard The outbound Guard dialing webhook channels.
+// return *NotificationSenderService A pointer to the initialized service.
+func NewNotificationSenderService(guard *outbound.Guard) *NotificationSenderService {
+	config := utils.GetConfig()
+	s := &NotificationSenderService{
+		webhookClient:  guard.HTTPClient(senderTimeout),
+		telegramClient: httpclient.NewWithoutRetry(senderTimeout),
+		smtpHost:       strings.TrimSpace(config.SMTPHost),
+		smtpPort:       strings.TrimSpace(config.SMTPPort),
+		smtpUsername:   config.SMTPUsername,
+		smtpPassword:   config.SMTPPassword,
+		smtpFrom:       strings.TrimSpace(config.SMTPFrom),
+		telegramToken:  strings.TrimSpace(config.TelegramBotToken),
+		telegramAPIURL: strings.TrimSuffix(strings.TrimSpace(config.TelegramAPIURL), "/"),
+	}
+	if s.smtpPort == "" {
+		s.smtpPort = defaultSMTPPort
+	}
+	if s.smtpFrom == "" {
+		s.smtpFrom = s.smtpUsername
+	}
+	if s.telegramAPIURL == "" {
+		s.telegramAPIURL = defaultTelegramAPIURL
+	}
+	return s
+}
+
+// EmailEnabled reports whether email channels can be delivered.
+//
+// return bool True when SMTP_HOST and a sender address are configured.
+func (s *NotificationSenderService) EmailEnabled() bool {
+	return s.smtpHost != "" && s.smtpFrom != ""
+}
+
+// TelegramEnabled reports whether Telegram channels can be delivered.
+//
+// return bool True when TELEGRAM_BOT_TOKEN is configured.
+func (s *NotificationSenderService) TelegramEnabled() bool {
+	return s.telegramToken != ""
+}
+
+// Send delivers a notification to one channel.
+//
+// param ctx The context bounding the delivery.
+// param channel The channel type and target.
+// param notification The notification.
+// return int The HTTP status of webhook and Telegram deliveries (0 for email and network errors).
+// return error An error if the notification was not accepted.
+func (s *NotificationSenderService) Send(ctx context.Context, channel entities.NotificationChannel, notification dtos.NotificationDTO) (int, error) {
+	switch channel.Type {
+	case entities.NotificationChannelWebhook:
+		return s.sendWebhook(ctx, channel.Target, notification)
+	case entities.NotificationChannelEmail:
+		return 0, s.sendEmail(ctx, channel.Target, notification)
+	case entities.NotificationChannelTelegram:
+		return s.sendTelegram(ctx, channel.Target, notification)
+	default:
+		return 0, fmt.Errorf("unknown notification channel %q", channel.Type)
+	}
+}
+
+// sendWebhook posts the notification as JSON.
+func (s *NotificationSenderService) sendWebhook(ctx context.Context, url string, notification dtos.NotificationDTO) (int, error) {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create notification request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Teralux-Notification/1.0")
+	req.Header.Set("X-Teralux-Event", notification.Event)
+	req.Header.Set("X-Teralux-Delivery", notification.ID)
+
+	resp, err := s.webhookClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("notification webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("notification webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// sendEmail sends the notification as a plain-text email, upgrading to STARTTLS when the server offers it.
+func (s *NotificationSenderService) sendEmail(ctx context.Context, to string, notification dtos.NotificationDTO) error {
+	if !s.EmailEnabled() {
+		return fmt.Errorf("email notifications are not configured (SMTP_HOST, SMTP_FROM)")
+	}
+
+	dialCtx, cancel := context.WithTimeout(ctx, senderTimeout)
+	defer cancel()
+	conn, err := (&net.Dialer{}).DialContext(dialCtx, "tcp", net.JoinHostPort(s.smtpHost, s.smtpPort))
+	if err != nil {
+		return fmt.Errorf("failed to connect to SMTP server: %w", err)
+	}
+	deadline, _ := dialCtx.Deadline()
+	_ = conn.SetDeadline(deadline)
+
+	client, err := smtp.NewClient(conn, s.smtpHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("failed to start SMTP session: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.smtpHost}); err != nil {
+			return fmt.Errorf("SMTP STARTTLS failed: %w", err)
+		}
+	}
+	if s.smtpUsername != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.smtpUsername, s.smtpPassword, s.smtpHost)); err != nil {
+			return fmt.Errorf("SMTP authentication failed: %w", err)
+		}
+	}
+	if err := client.Mail(s.smtpFrom); err != nil {
+		return fmt.Errorf("SMTP server refused sender: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return fmt.Errorf("SMTP server refused recipient: %w", err)
+	}
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("SMTP DATA failed: %w", err)
+	}
+	if _, err := writer.Write(s.emailMessage(to, notification)); err != nil {
+		writer.Close()
+		return fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return fmt.Errorf("SMTP server refused email: %w", err)
+	}
+	return client.Quit()
+}
+
+// emailMessage builds the RFC 5322 message of a notification.
+func (s *NotificationSenderService) emailMessage(to string, notification dtos.NotificationDTO) []byte {
+	subject := "[Teralux] " + notification.Message
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", s.smtpFrom)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", time.Unix(notification.Timestamp, 0).UTC().Format(time.RFC1123Z))
+	message.WriteString("MIME-Version: 1.0\r\n")
+	message.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	message.WriteString(notification.Message + "\r\n\r\n")
+	if notification.DeviceID != "" {
+		fmt.Fprintf(&message, "Device: %s\r\n", notification.DeviceID)
+	}
+	if notification.RuleName != "" {
+		fmt.Fprintf(&message, "Rule: %s\r\n", notification.RuleName)
+	}
+	fmt.Fprintf(&message, "Time: %s\r\n", time.Unix(notification.Timestamp, 0).UTC().Format(time.RFC3339))
+	return message.Bytes()
+}
+
+// sendTelegram sends the notification message to a chat through the Bot API.
+func (s *NotificationSenderService) sendTelegram(ctx context.Context, chatID string, notification dtos.NotificationDTO) (int, error) {
+	if !s.TelegramEnabled() {
+		return 0, fmt.Errorf("telegram notifications are not configured (TELEGRAM_BOT_TOKEN)")
+	}
+
+	body, err := json.Marshal(map[string]string{"chat_id": chatID, "text": notification.Message})
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal telegram message: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.telegramAPIURL+"/bot"+s.telegramToken+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create telegram request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := s.telegramClient.Do(req)
+	if err != nil {
+		// The request URL carries the bot token, so the error is reported without it
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return 0, fmt.Errorf("telegram request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	_ = json.NewDecoder(io.LimitReader(resp.Body, 64<<10)).Decode(&result)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 || !result.OK {
+		if result.Description != "" {
+			return resp.StatusCode, fmt.Errorf("telegram responded with status %d: %s", resp.StatusCode, result.Description)
+		}
+		return resp.StatusCode, fmt.Errorf("telegram responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
@@ -0,0 +1,804 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/outbound"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/notifications/dtos"
+	"teralux_app/domain/notifications/entities"
+	"teralux_app/domain/notifications/services"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	tuya_usecases "teralux_app/domain/tuya/usecases"
+	"time"
+)
+
+const (
+	// NotificationDeliveryJobType is the job type of notification deliveries.
+	NotificationDeliveryJobType = "notification_delivery"
+
+	// notificationRulePrefix stores rules: "notification_rule:{id}".
+	notificationRulePrefix = "notification_rule:"
+	// notificationCooldownPrefix marks alerts within the cooldown window: "notification_cooldown:{rule_id}|{device_id}".
+	notificationCooldownPrefix = "notification_cooldown:"
+	// maxRulesPerOwner bounds the rules a single API key or user can create.
+	maxRulesPerOwner = 20
+	// defaultCooldownMinutes is used when a rule does not set cooldown_minutes.
+	defaultCooldownMinutes = 60
+	// defaultNotificationMaxAttempts is used when NOTIFICATION_MAX_ATTEMPTS is not set.
+	defaultNotificationMaxAttempts = 5
+	// defaultNotificationCheckInterval is used when NOTIFICATION_CHECK_INTERVAL is not set.
+	defaultNotificationCheckInterval = 30 * time.Second
+
+	// episodeAlerted is an offline episode the rule has alerted about.
+	episodeAlerted = "alerted"
+	// episodeSuppressed is an offline episode that reached the rule's threshold within the cooldown window.
+	episodeSuppressed = "suppressed"
+)
+
+// ErrNotificationRuleNotFound is returned when a rule does not exist or belongs to another user.
+var ErrNotificationRuleNotFound = tuya_errors.NotFound("notification rule not found")
+
+// notificationDeliveryPayload is the persisted input of a delivery job.
+type notificationDeliveryPayload struct {
+	RuleID       string                       `json:"rule_id"`
+	Channel      entities.NotificationChannel `json:"channel"`
+	Notification dtos.NotificationDTO         `json:"notification"`
+}
+
+// notificationDeliveryResult is the result stored on a delivered job.
+type notificationDeliveryResult struct {
+	StatusCode int `json:"status_code,omitempty"`
+}
+
+// offlineDevice is a device currently reported offline.
+type offlineDevice struct {
+	since    int64
+	category string
+}
+
+// NotificationUseCase lets users configure alerts such as "device X offline for more than N minutes",
+// delivered to webhook URLs, email addresses or Telegram chats. It listens on the realtime hub for the
+// device_offline/device_online events of the sensor poller, checks offline devices against the rules
+// every NOTIFICATION_CHECK_INTERVAL, and alerts once per offline episode. A cooldown window per rule and
+// device keeps flapping devices from spamming: alerts within it are suppressed, and so are their recovery
+// notices. Deliveries are background jobs retried with backoff. Rules belong to the API key or user that
+// created them, and only alert about devices that key may still access and that are visible to its tenant
+// (see DEVICE_CLAIMS_ENABLED).
+type NotificationUseCase struct {
+	cache         persistence.CacheStore
+	jobRunner     *job_services.JobRunnerService
+	realtimeHub   *realtime_services.RealtimeHubService
+	guard         *outbound.Guard
+	claimUC       *tuya_usecases.DeviceClaimUseCase
+	authz         tuya_usecases.CallerAuthorizer
+	sender        *services.NotificationSenderService
+	maxAttempts   int
+	checkInterval time.Duration
+	clock         utils.Clock
+	ids           utils.IDGenerator
+
+	mu      sync.Mutex
+	rules   map[string]*entities.NotificationRule
+	loaded  bool
+	offline map[string]offlineDevice
+	// episodes remembers the outcome of the current offline episode per rule and device: "{rule_id}|{device_id}".
+	episodes map[string]string
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewNotificationUseCase initializes a new NotificationUseCase and registers its job type.
+// Delivery attempts and the check interval are read from NOTIFICATION_MAX_ATTEMPTS and NOTIFICATION_CHECK_INTERVAL.
+//
+// param cache The CacheStore used to persist rules and cooldowns.
+// param jobRunner The JobRunnerService persisting and retrying deliveries.
+// param realtimeHub The RealtimeHubService the device events are read from.
+// param guard The outbound Guard validating webhook channels.
+// param claimUC The DeviceClaimUseCase limiting alerts to the devices of the owner (optional).
+// param authz The CallerAuthorizer limiting alerts to the devices the owner's API key may access.
+// param sender The NotificationSenderService delivering to the channels.
+// param clock The Clock used for offline durations and timestamps.
+// param ids The IDGenerator used for rule and notification IDs.
+// return *NotificationUseCase A pointer to the initialized usecase.
+func NewNotificationUseCase(cache persistence.CacheStore, jobRunner *job_services.JobRunnerService, realtimeHub *realtime_services.RealtimeHubService, guard *outbound.Guard, claimUC *tuya_usecases.DeviceClaimUseCase, authz tuya_usecases.CallerAuthorizer, sender *services.NotificationSenderService, clock utils.Clock, ids utils.IDGenerator) *NotificationUseCase {
+	config := utils.GetConfig()
+	maxAttempts, err := strconv.Atoi(config.NotificationMaxAttempts)
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = defaultNotificationMaxAttempts
+	}
+	checkInterval, err := time.ParseDuration(config.NotificationCheckInterval)
+	if err != nil || checkInterval <= 0 {
+		checkInterval = defaultNotificationCheckInterval
+	}
+
+	uc := &NotificationUseCase{
+		cache:         cache,
+		jobRunner:     jobRunner,
+		realtimeHub:   realtimeHub,
+		guard:         guard,
+		claimUC:       claimUC,
+		authz:         authz,
+		sender:        sender,
+		maxAttempts:   maxAttempts,
+		checkInterval: checkInterval,
+		clock:         clock,
+		ids:           ids,
+		rules:         make(map[string]*entities.NotificationRule),
+		offline:       make(map[string]offlineDevice),
+		episodes:      make(map[string]string),
+	}
+	jobRunner.Register(NotificationDeliveryJobType, uc.runDelivery)
+	return uc
+}
+
+// Start subscribes to the realtime hub and checks offline devices against the rules in the background.
+func (uc *NotificationUseCase) Start() {
+	uc.startOnce.Do(func() {
+		if err := uc.load(); err != nil {
+			utils.LogError("NotificationUseCase: Failed to load rules: %v", err)
+		}
+		client := uc.realtimeHub.Register(realtime_dtos.SubscriptionFilterDTO{})
+		uc.workers.Go(func(stop <-chan struct{}) {
+			defer uc.realtimeHub.Unregister(client)
+			ticker := time.NewTicker(uc.checkInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case payload, ok := <-client.Send:
+					if !ok {
+						return
+					}
+					var event realtime_dtos.DeviceEventDTO
+					if err := json.Unmarshal(payload, &event); err != nil {
+						utils.LogWarn("NotificationUseCase: Skipping unreadable event: %v", err)
+						continue
+					}
+					uc.HandleEvent(event)
+				case <-ticker.C:
+					uc.Evaluate()
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("NotificationUseCase: Started (checking every %s)", uc.checkInterval)
+	})
+}
+
+// Stop ends event handling and checks. Queued deliveries stay with the job runner.
+func (uc *NotificationUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// CreateRule creates a notification rule for the caller.
+//
+// param ctx The request context carrying the caller (see utils.APIKeyIdentityFromContext), bounding webhook URL validation.
+// param uid The Tuya UID of the tenant whose claimed devices the rule alerts about (empty for all devices).
+// param req The devices, thresholds and channels.
+// return *dtos.NotificationRuleDTO The rule.
+// return error A bad request error for invalid input or unavailable channels, a forbidden error without a caller,
+// or a storage error.
+func (uc *NotificationUseCase) CreateRule(ctx context.Context, uid string, req dtos.NotificationRuleRequestDTO) (*dtos.NotificationRuleDTO, error) {
+	owner := utils.APIKeyIdentityFromContext(ctx)
+	if owner.Actor() == "" {
+		return nil, tuya_errors.Forbidden("notification rules can only be created with an API key or identity token")
+	}
+	rule, err := uc.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	count := 0
+	for _, existing := range uc.rules {
+		if existing.Owner.Actor() == owner.Actor() {
+			count++
+		}
+	}
+	if count >= maxRulesPerOwner {
+		return nil, tuya_errors.BadRequest("at most %d notification rules can be created", maxRulesPerOwner)
+	}
+
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate notification rule ID: %w", err)
+	}
+	now := uc.clock.Now().Unix()
+	rule.ID = id
+	rule.Owner = owner
+	rule.OwnerUID = uid
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+	if err := uc.save(rule); err != nil {
+		return nil, err
+	}
+	utils.LogInfo("NotificationUseCase: Rule %s created by %s (%d channels)", id, owner.Actor(), len(rule.Channels))
+	dto := ruleToDTO(rule)
+	return &dto, nil
+}
+
+// ListRules returns the notification rules of the caller, newest first.
+//
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// return []dtos.NotificationRuleDTO The rules.
+// return error An error if the rules cannot be read.
+func (uc *NotificationUseCase) ListRules(owner string) ([]dtos.NotificationRuleDTO, error) {
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	result := make([]dtos.NotificationRuleDTO, 0)
+	for _, rule := range uc.rules {
+		if owner != "" && rule.Owner.Actor() == owner {
+			result = append(result, ruleToDTO(rule))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// GetRule returns a notification rule of the caller.
+//
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The rule ID.
+// return *dtos.NotificationRuleDTO The rule.
+// return error ErrNotificationRuleNotFound, or a storage error.
+func (uc *NotificationUseCase) GetRule(owner, id string) (*dtos.NotificationRuleDTO, error) {
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	rule, err := uc.owned(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	dto := ruleToDTO(rule)
+	return &dto, nil
+}
+
+// UpdateRule replaces the devices, thresholds and channels of a rule. Offline episodes it already
+// alerted about are forgotten, so devices still offline are evaluated against the new thresholds.
+//
+// param ctx The request context, bounding webhook URL validation.
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The rule ID.
+// param req The new devices, thresholds and channels.
+// return *dtos.NotificationRuleDTO The updated rule.
+// return error ErrNotificationRuleNotFound, a bad request error for invalid input, or a storage error.
+func (uc *NotificationUseCase) UpdateRule(ctx context.Context, owner, id string, req dtos.NotificationRuleRequestDTO) (*dtos.NotificationRuleDTO, error) {
+	updated, err := uc.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	rule, err := uc.owned(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	updated.ID = rule.ID
+	updated.Owner = rule.Owner
+	updated.OwnerUID = rule.OwnerUID
+	updated.CreatedAt = rule.CreatedAt
+	updated.UpdatedAt = uc.clock.Now().Unix()
+	updated.LastNotifiedAt = rule.LastNotifiedAt
+	updated.LastError = rule.LastError
+	if err := uc.save(updated); err != nil {
+		return nil, err
+	}
+	uc.resetEpisodes(id)
+	dto := ruleToDTO(updated)
+	return &dto, nil
+}
+
+// DeleteRule removes a notification rule of the caller. Queued deliveries of it are dropped.
+//
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The rule ID.
+// return error ErrNotificationRuleNotFound, or a storage error.
+func (uc *NotificationUseCase) DeleteRule(owner, id string) error {
+	if err := uc.load(); err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, err := uc.owned(owner, id); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(notificationRulePrefix + id); err != nil {
+		return fmt.Errorf("failed to delete notification rule: %w", err)
+	}
+	delete(uc.rules, id)
+	uc.resetEpisodes(id)
+	utils.LogInfo("NotificationUseCase: Rule %s deleted by %s", id, owner)
+	return nil
+}
+
+// TestRule sends a test notification to every channel of a rule right away, without retries or cooldown.
+//
+// param ctx The request context.
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The rule ID.
+// return []dtos.NotificationTestResultDTO The outcome per channel.
+// return error ErrNotificationRuleNotFound, or a storage error.
+func (uc *NotificationUseCase) TestRule(ctx context.Context, owner, id string) ([]dtos.NotificationTestResultDTO, error) {
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	rule, err := uc.owned(owner, id)
+	var copied entities.NotificationRule
+	if err == nil {
+		copied = *rule
+	}
+	uc.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	notification, err := uc.newNotification(&copied, entities.NotificationEventTest, "", "", 0, fmt.Sprintf("Test notification of rule %q from Teralux", ruleLabel(&copied)))
+	if err != nil {
+		return nil, err
+	}
+	results := make([]dtos.NotificationTestResultDTO, 0, len(copied.Channels))
+	var lastErr error
+	for _, channel := range copied.Channels {
+		_, sendErr := uc.sender.Send(ctx, channel, *notification)
+		result := dtos.NotificationTestResultDTO{Type: channel.Type, Target: channel.Target, Delivered: sendErr == nil}
+		if sendErr != nil {
+			result.Error = sendErr.Error()
+			lastErr = sendErr
+		}
+		results = append(results, result)
+	}
+	uc.recordDelivery(id, lastErr)
+	return results, nil
+}
+
+// HandleEvent tracks devices going offline and coming back online. Rules alerting right away
+// (offline_minutes 0) are evaluated immediately; a device coming back online sends the recovery notice
+// of rules that alerted about it and have notify_online set.
+//
+// param event The device event published on the realtime hub.
+func (uc *NotificationUseCase) HandleEvent(event realtime_dtos.DeviceEventDTO) {
+	if event.DeviceID == "" {
+		return
+	}
+
+	switch event.Type {
+	case entities.NotificationEventDeviceOffline:
+		uc.mu.Lock()
+		if _, ok := uc.offline[event.DeviceID]; !ok {
+			since := event.Timestamp
+			if since == 0 {
+				since = uc.clock.Now().Unix()
+			}
+			uc.offline[event.DeviceID] = offlineDevice{since: since, category: event.Category}
+		}
+		uc.mu.Unlock()
+		uc.Evaluate()
+	case entities.NotificationEventDeviceOnline:
+		uc.handleOnline(event)
+	}
+}
+
+// Evaluate alerts about devices that have been offline for at least the offline_minutes of a rule.
+// Each offline episode is alerted once per rule; within the cooldown window it is suppressed instead.
+func (uc *NotificationUseCase) Evaluate() {
+	now := uc.clock.Now().Unix()
+	var pending []notificationDeliveryPayload
+
+	uc.mu.Lock()
+	visibility := make(map[string]tuya_usecases.DeviceFilter)
+	for deviceID, device := range uc.offline {
+		for _, rule := range uc.rules {
+			if !rule.Enabled || !matchesDevice(rule, deviceID) || !uc.ownerMayAccess(rule.Owner, deviceID) {
+				continue
+			}
+			key := rule.ID + "|" + deviceID
+			if _, seen := uc.episodes[key]; seen {
+				continue
+			}
+			if now-device.since < int64(rule.OfflineMinutes)*60 {
+				continue
+			}
+			visible, ok := visibility[rule.OwnerUID]
+			if !ok && uc.claimUC != nil && rule.OwnerUID != "" {
+				visible = uc.claimUC.VisibleTo(rule.OwnerUID)
+				visibility[rule.OwnerUID] = visible
+			}
+			if visible != nil && !visible(deviceID) {
+				continue
+			}
+
+			if !uc.acquireCooldown(rule, key, now) {
+				uc.episodes[key] = episodeSuppressed
+				utils.LogDebug("NotificationUseCase: Rule %s suppressed offline alert for %s (cooldown)", rule.ID, deviceID)
+				continue
+			}
+			uc.episodes[key] = episodeAlerted
+			notification, err := uc.newNotification(rule, entities.NotificationEventDeviceOffline, deviceID, device.category, device.since, offlineMessage(deviceID, device.since, now))
+			if err != nil {
+				utils.LogWarn("NotificationUseCase: Failed to build offline notification of rule %s: %v", rule.ID, err)
+				continue
+			}
+			pending = append(pending, deliveries(rule, notification)...)
+		}
+	}
+	uc.mu.Unlock()
+
+	uc.enqueue(pending)
+}
+
+// handleOnline ends the offline episode of a device and queues the recovery notices.
+func (uc *NotificationUseCase) handleOnline(event realtime_dtos.DeviceEventDTO) {
+	now := uc.clock.Now().Unix()
+	var pending []notificationDeliveryPayload
+
+	uc.mu.Lock()
+	device, wasOffline := uc.offline[event.DeviceID]
+	delete(uc.offline, event.DeviceID)
+	for key, state := range uc.episodes {
+		ruleID, deviceID, _ := strings.Cut(key, "|")
+		if deviceID != event.DeviceID {
+			continue
+		}
+		delete(uc.episodes, key)
+		rule, ok := uc.rules[ruleID]
+		if !ok || !rule.Enabled || !rule.NotifyOnline || state != episodeAlerted || !wasOffline || !uc.ownerMayAccess(rule.Owner, event.DeviceID) {
+			continue
+		}
+		notification, err := uc.newNotification(rule, entities.NotificationEventDeviceOnline, event.DeviceID, device.category, device.since, onlineMessage(event.DeviceID, device.since, now))
+		if err != nil {
+			utils.LogWarn("NotificationUseCase: Failed to build online notification of rule %s: %v", rule.ID, err)
+			continue
+		}
+		pending = append(pending, deliveries(rule, notification)...)
+	}
+	uc.mu.Unlock()
+
+	uc.enqueue(pending)
+}
+
+// acquireCooldown starts the cooldown window of a rule and device, reporting false while one is running.
+// Storage errors do not hold alerts back. The caller holds uc.mu.
+func (uc *NotificationUseCase) acquireCooldown(rule *entities.NotificationRule, key string, now int64) bool {
+	if rule.CooldownMinutes <= 0 {
+		return true
+	}
+	acquired, err := uc.cache.SetIfNotExists(notificationCooldownPrefix+key, []byte(strconv.FormatInt(now, 10)), time.Duration(rule.CooldownMinutes)*time.Minute)
+	if err != nil {
+		utils.LogWarn("NotificationUseCase: Failed to check cooldown of %s: %v", key, err)
+		return true
+	}
+	return acquired
+}
+
+// enqueue queues deliveries as background jobs.
+func (uc *NotificationUseCase) enqueue(pending []notificationDeliveryPayload) {
+	for _, delivery := range pending {
+		if _, err := uc.jobRunner.Enqueue(context.Background(), NotificationDeliveryJobType, delivery, uc.maxAttempts); err != nil {
+			utils.LogError("NotificationUseCase: Failed to queue %s notification of rule %s: %v", delivery.Notification.Event, delivery.RuleID, err)
+		}
+	}
+}
+
+// runDelivery is the job handler sending one notification to one channel.
+func (uc *NotificationUseCase) runDelivery(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	var delivery notificationDeliveryPayload
+	if err := job.DecodePayload(&delivery); err != nil {
+		return nil, job_services.Permanent(err)
+	}
+
+	uc.mu.Lock()
+	rule, ok := uc.rules[delivery.RuleID]
+	current := ok && rule.Enabled && containsChannel(rule.Channels, delivery.Channel)
+	uc.mu.Unlock()
+	if !current {
+		return nil, job_services.Permanent(ErrNotificationRuleNotFound)
+	}
+
+	statusCode, err := uc.sender.Send(ctx, delivery.Channel, delivery.Notification)
+	uc.recordDelivery(delivery.RuleID, err)
+	if err != nil {
+		// Client errors other than timeouts and rate limits will not change on retry
+		if statusCode >= 400 && statusCode < 500 && statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests {
+			return notificationDeliveryResult{StatusCode: statusCode}, job_services.Permanent(err)
+		}
+		if errors.Is(err, outbound.ErrDestinationNotAllowed) {
+			return nil, job_services.Permanent(err)
+		}
+		return notificationDeliveryResult{StatusCode: statusCode}, err
+	}
+	return notificationDeliveryResult{StatusCode: statusCode}, nil
+}
+
+// recordDelivery stores the outcome of the latest delivery on the rule.
+func (uc *NotificationUseCase) recordDelivery(id string, deliverErr error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	rule, ok := uc.rules[id]
+	if !ok {
+		return
+	}
+	rule.LastNotifiedAt = uc.clock.Now().Unix()
+	rule.LastError = ""
+	if deliverErr != nil {
+		rule.LastError = deliverErr.Error()
+	}
+	if err := uc.save(rule); err != nil {
+		utils.LogWarn("NotificationUseCase: Failed to record delivery of rule %s: %v", id, err)
+	}
+}
+
+// newNotification builds a notification of a rule.
+func (uc *NotificationUseCase) newNotification(rule *entities.NotificationRule, event, deviceID, category string, offlineSince int64, message string) (*dtos.NotificationDTO, error) {
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate notification ID: %w", err)
+	}
+	return &dtos.NotificationDTO{
+		ID:           id,
+		Event:        event,
+		RuleID:       rule.ID,
+		RuleName:     rule.Name,
+		DeviceID:     deviceID,
+		Category:     category,
+		OfflineSince: offlineSince,
+		Message:      message,
+		Timestamp:    uc.clock.Now().Unix(),
+	}, nil
+}
+
+// validate checks a rule request and converts it into an entity without ID and owner.
+func (uc *NotificationUseCase) validate(ctx context.Context, req dtos.NotificationRuleRequestDTO) (*entities.NotificationRule, error) {
+	channels := make([]entities.NotificationChannel, 0, len(req.Channels))
+	for _, channel := range req.Channels {
+		target := strings.TrimSpace(channel.Target)
+		switch channel.Type {
+		case entities.NotificationChannelWebhook:
+			if err := uc.guard.ValidateURL(ctx, target, "https", "http"); err != nil {
+				return nil, tuya_errors.BadRequest("%w", err)
+			}
+		case entities.NotificationChannelEmail:
+			if !uc.sender.EmailEnabled() {
+				return nil, tuya_errors.BadRequest("email notifications are not configured on this server")
+			}
+			address, err := mail.ParseAddress(target)
+			if err != nil {
+				return nil, tuya_errors.BadRequest("invalid email address %q", target)
+			}
+			target = address.Address
+		case entities.NotificationChannelTelegram:
+			if !uc.sender.TelegramEnabled() {
+				return nil, tuya_errors.BadRequest("telegram notifications are not configured on this server")
+			}
+			if _, err := strconv.ParseInt(target, 10, 64); err != nil && !strings.HasPrefix(target, "@") {
+				return nil, tuya_errors.BadRequest("telegram target must be a chat ID or @channelusername, got %q", target)
+			}
+		default:
+			return nil, tuya_errors.BadRequest("unknown channel type %q (supported: webhook, email, telegram)", channel.Type)
+		}
+		normalized := entities.NotificationChannel{Type: channel.Type, Target: target}
+		if !containsChannel(channels, normalized) {
+			channels = append(channels, normalized)
+		}
+	}
+
+	deviceIDs := make([]string, 0, len(req.DeviceIDs))
+	for _, deviceID := range req.DeviceIDs {
+		deviceID = strings.TrimSpace(deviceID)
+		if deviceID != "" && !containsString(deviceIDs, deviceID) {
+			deviceIDs = append(deviceIDs, deviceID)
+		}
+	}
+
+	cooldown := defaultCooldownMinutes
+	if req.CooldownMinutes != nil {
+		cooldown = *req.CooldownMinutes
+	}
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return &entities.NotificationRule{
+		Name:            strings.TrimSpace(req.Name),
+		DeviceIDs:       deviceIDs,
+		OfflineMinutes:  req.OfflineMinutes,
+		NotifyOnline:    req.NotifyOnline,
+		CooldownMinutes: cooldown,
+		Channels:        channels,
+		Enabled:         enabled,
+	}, nil
+}
+
+// owned returns a rule of the caller. The caller holds uc.mu.
+func (uc *NotificationUseCase) owned(owner, id string) (*entities.NotificationRule, error) {
+	rule, ok := uc.rules[id]
+	if !ok || owner == "" || rule.Owner.Actor() != owner {
+		return nil, ErrNotificationRuleNotFound
+	}
+	return rule, nil
+}
+
+// ownerMayAccess reports whether the owner of a rule still holds control scope and may access a device.
+// Rules of revoked keys stop alerting.
+func (uc *NotificationUseCase) ownerMayAccess(owner utils.APIKeyIdentity, deviceID string) bool {
+	current, ok := uc.authz.CurrentScope(owner)
+	if !ok || !utils.APIKeyScopeAllows(current, utils.APIKeyScopeControl) {
+		return false
+	}
+	return owner.ID == "" || uc.authz.CanAccessDevice(owner.ID, deviceID)
+}
+
+// resetEpisodes forgets the offline episodes of a rule. The caller holds uc.mu.
+func (uc *NotificationUseCase) resetEpisodes(id string) {
+	for key := range uc.episodes {
+		if strings.HasPrefix(key, id+"|") {
+			delete(uc.episodes, key)
+		}
+	}
+}
+
+// load reads the stored rules once.
+func (uc *NotificationUseCase) load() error {
+	if uc.cache == nil {
+		return fmt.Errorf("notification storage not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.loaded {
+		return nil
+	}
+	keys, err := uc.cache.GetAllKeysWithPrefix(notificationRulePrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list notification rules: %w", err)
+	}
+	for _, key := range keys {
+		data, err := uc.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var rule entities.NotificationRule
+		if err := json.Unmarshal(data, &rule); err != nil {
+			utils.LogWarn("NotificationUseCase: Skipping unreadable rule %s: %v", key, err)
+			continue
+		}
+		uc.rules[rule.ID] = &rule
+	}
+	uc.loaded = true
+	return nil
+}
+
+// save persists a rule and updates the in-memory copy. The caller holds uc.mu.
+func (uc *NotificationUseCase) save(rule *entities.NotificationRule) error {
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification rule: %w", err)
+	}
+	if err := uc.cache.SetPersistent(notificationRulePrefix+rule.ID, data); err != nil {
+		return fmt.Errorf("failed to save notification rule: %w", err)
+	}
+	uc.rules[rule.ID] = rule
+	return nil
+}
+
+// deliveries returns one delivery of a notification per channel of the rule.
+func deliveries(rule *entities.NotificationRule, notification *dtos.NotificationDTO) []notificationDeliveryPayload {
+	result := make([]notificationDeliveryPayload, 0, len(rule.Channels))
+	for _, channel := range rule.Channels {
+		result = append(result, notificationDeliveryPayload{RuleID: rule.ID, Channel: channel, Notification: *notification})
+	}
+	return result
+}
+
+// offlineMessage describes a device that has been offline since a Unix time.
+func offlineMessage(deviceID string, since, now int64) string {
+	minutes := (now - since) / 60
+	if minutes <= 0 {
+		return fmt.Sprintf("Device %s went offline", deviceID)
+	}
+	return fmt.Sprintf("Device %s has been offline for %d minutes", deviceID, minutes)
+}
+
+// onlineMessage describes a device that came back online after being offline since a Unix time.
+func onlineMessage(deviceID string, since, now int64) string {
+	minutes := (now - since) / 60
+	if minutes <= 0 {
+		return fmt.Sprintf("Device %s is back online", deviceID)
+	}
+	return fmt.Sprintf("Device %s is back online after %d minutes offline", deviceID, minutes)
+}
+
+// ruleLabel returns the name of a rule, or its ID when unnamed.
+func ruleLabel(rule *entities.NotificationRule) string {
+	if rule.Name != "" {
+		return rule.Name
+	}
+	return rule.ID
+}
+
+// matchesDevice reports whether a rule watches a device.
+func matchesDevice(rule *entities.NotificationRule, deviceID string) bool {
+	return len(rule.DeviceIDs) == 0 || containsString(rule.DeviceIDs, deviceID)
+}
+
+// containsChannel reports whether channel is present in list.
+func containsChannel(list []entities.NotificationChannel, channel entities.NotificationChannel) bool {
+	for _, item := range list {
+		if item == channel {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether value is present in list.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ruleToDTO converts a rule into its API representation.
+func ruleToDTO(rule *entities.NotificationRule) dtos.NotificationRuleDTO {
+	channels := make([]dtos.NotificationChannelDTO, len(rule.Channels))
+	for i, channel := range rule.Channels {
+		channels[i] = dtos.NotificationChannelDTO{Type: channel.Type, Target: channel.Target}
+	}
+	return dtos.NotificationRuleDTO{
+		ID:              rule.ID,
+		Name:            rule.Name,
+		DeviceIDs:       rule.DeviceIDs,
+		OfflineMinutes:  rule.OfflineMinutes,
+		NotifyOnline:    rule.NotifyOnline,
+		CooldownMinutes: rule.CooldownMinutes,
+		Channels:        channels,
+		Enabled:         rule.Enabled,
+		CreatedAt:       rule.CreatedAt,
+		UpdatedAt:       rule.UpdatedAt,
+		LastNotifiedAt:  rule.LastNotifiedAt,
+		LastError:       rule.LastError,
+	}
+}
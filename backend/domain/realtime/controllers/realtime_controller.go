@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	"teralux_app/domain/realtime/services"
+	"teralux_app/domain/realtime/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// writeWait is the time allowed to write a message to the peer.
+	writeWait = 10 * time.Second
+	// pongWait is the time allowed to read the next pong message from the peer.
+	pongWait = 60 * time.Second
+	// pingPeriod sends pings to the peer with this period. Must be less than pongWait.
+	pingPeriod = (pongWait * 9) / 10
+)
+
+var upgrader = websocket.Upgrader{
+	ReadBufferSize:  1024,
+	WriteBufferSize: 1024,
+	CheckOrigin:     checkOrigin,
+}
+
+// checkOrigin accepts websocket handshakes without an Origin header (non-browser clients), from the host
+// serving the API, and from the origins listed in REALTIME_ALLOWED_ORIGINS. Any other site could otherwise
+// open a socket with the credentials of a visitor's browser.
+func checkOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+	if strings.EqualFold(u.Host, r.Host) {
+		return true
+	}
+	for _, allowed := range strings.Split(utils.GetConfig().RealtimeAllowedOrigins, ",") {
+		if allowed = strings.TrimSuffix(strings.TrimSpace(allowed), "/"); allowed != "" && strings.EqualFold(allowed, origin) {
+			return true
+		}
+	}
+	utils.LogWarn("RealtimeController: rejected websocket from origin %s", origin)
+	return false
+}
+
+// RealtimeController handles realtime websocket subscriptions
+type RealtimeController struct {
+	hub     *services.RealtimeHubService
+	tickets *usecases.RealtimeTicketUseCase
+}
+
+// NewRealtimeController creates a new RealtimeController instance
+func NewRealtimeController(hub *services.RealtimeHubService, tickets *usecases.RealtimeTicketUseCase) *RealtimeController {
+	return &RealtimeController{hub: hub, tickets: tickets}
+}
+
+// IssueTicket handles POST /api/realtime/ticket endpoint
+// @Summary      Issue websocket ticket
+// @Description  Issues a single-use ticket valid for 30 seconds that authenticates one upgrade of /api/realtime/ws or /socket.io/ with the credentials of this request. Pass it as the ticket query parameter, since browsers cannot attach headers to websocket handshakes.
+// @Tags         07. Realtime
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=realtime_dtos.RealtimeTicketDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/realtime/ticket [post]
+func (ctrl *RealtimeController) IssueTicket(c *gin.Context) {
+	ticket, err := ctrl.tickets.IssueTicket(c.GetHeader("Authorization"), utils.APIKeyIdentityFromContext(c.Request.Context()))
+	if err != nil {
+		utils.LogError("RealtimeController: failed to issue ticket: %v", err)
+		c.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Failed to issue websocket ticket",
+			Data:    nil,
+		})
+		return
+	}
+	c.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Websocket ticket issued",
+		Data:    ticket,
+	})
+}
+
+// Subscribe handles GET /api/realtime/ws endpoint
+// @Summary      Subscribe to device events
+// @Description  Upgrades the connection to a websocket streaming device state events. Events can be narrowed with comma-separated device_ids, rooms and categories query parameters. Sending a JSON SubscriptionFilterDTO message over the socket replaces the active filter. Browsers, which cannot set headers on websocket handshakes, authenticate with a ticket from POST /api/realtime/ticket. Handshakes from other origins than the API's host and REALTIME_ALLOWED_ORIGINS are rejected.
+// @Tags         07. Realtime
+// @Param        device_ids  query  string  false  "Comma-separated device IDs"
+// @Param        rooms       query  string  false  "Comma-separated room IDs"
+// @Param        categories  query  string  false  "Comma-separated device categories"
+// @Param        ticket      query  string  false  "Single-use ticket from POST /api/realtime/ticket"
+// @Success      101  {object}  realtime_dtos.DeviceEventDTO
+// @Failure      403  {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /api/realtime/ws [get]
+func (ctrl *RealtimeController) Subscribe(c *gin.Context) {
+	filter := realtime_dtos.SubscriptionFilterDTO{
+		DeviceIDs:  splitQueryList(c.Query("device_ids")),
+		Rooms:      splitQueryList(c.Query("rooms")),
+		Categories: splitQueryList(c.Query("categories")),
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		utils.LogError("RealtimeController: websocket upgrade failed: %v", err)
+		return
+	}
+
+	client := ctrl.hub.Register(filter)
+	utils.LogDebug("RealtimeController: subscribed with filter %+v", filter)
+
+	go ctrl.writePump(conn, client)
+	ctrl.readPump(conn, client)
+}
+
+// readPump consumes filter updates from the peer until the connection closes.
+func (ctrl *RealtimeController) readPump(conn *websocket.Conn, client *services.RealtimeClient) {
+	defer func() {
+		ctrl.hub.Unregister(client)
+		conn.Close()
+	}()
+
+	conn.SetReadDeadline(time.Now().Add(pongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(pongWait))
+	})
+
+	for {
+		var filter realtime_dtos.SubscriptionFilterDTO
+		if err := conn.ReadJSON(&filter); err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				utils.LogWarn("RealtimeController: read error: %v", err)
+			}
+			return
+		}
+		client.SetFilter(filter)
+		utils.LogDebug("RealtimeController: filter updated to %+v", filter)
+	}
+}
+
+// writePump forwards hub events to the peer and keeps the connection alive with pings.
+func (ctrl *RealtimeController) writePump(conn *websocket.Conn, client *services.RealtimeClient) {
+	ticker := time.NewTicker(pingPeriod)
+	defer func() {
+		ticker.Stop()
+		conn.Close()
+	}()
+
+	for {
+		select {
+		case payload, ok := <-client.Send:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if !ok {
+				conn.WriteMessage(websocket.CloseMessage, []byte{})
+				return
+			}
+			if err := conn.WriteMessage(websocket.TextMessage, payload); err != nil {
+				return
+			}
+		case <-ticker.C:
+			conn.SetWriteDeadline(time.Now().Add(writeWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// splitQueryList parses a comma-separated query value into a list, skipping blanks.
+func splitQueryList(raw string) []string {
+	if raw == "" {
+		return nil
+	}
+	var items []string
+	for _, item := range strings.Split(raw, ",") {
+		if trimmed := strings.TrimSpace(item); trimmed != "" {
+			items = append(items, trimmed)
+		}
+	}
+	return items
+}
@@ -0,0 +1,334 @@
+package controllers
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/realtime/dtos"
+	"teralux_app/domain/realtime/services"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// socketIOPingInterval and socketIOPingTimeout are announced in the handshake; a peer silent for
+	// their sum is considered gone.
+	socketIOPingInterval = 25 * time.Second
+	socketIOPingTimeout  = 20 * time.Second
+	socketIOMaxPayload   = 1000000
+	// socketIOOutboxSize buffers protocol replies (pongs, acknowledgements) queued by the read loop.
+	socketIOOutboxSize = 16
+)
+
+// Engine.IO packet types, the first byte of every websocket frame.
+const (
+	engineOpen    = '0'
+	engineClose   = '1'
+	enginePing    = '2'
+	enginePong    = '3'
+	engineMessage = '4'
+)
+
+// Socket.IO packet types, the first byte of an Engine.IO message.
+const (
+	socketConnect      = '0'
+	socketDisconnect   = '1'
+	socketEvent        = '2'
+	socketAck          = '3'
+	socketConnectError = '4'
+)
+
+// SocketIOController serves device events over the Socket.IO protocol for clients that cannot use the
+// plain websocket endpoint. Only the websocket transport is supported (clients must set
+// transports: ["websocket"]); Engine.IO 3 (Socket.IO 2.x clients) and Engine.IO 4 (Socket.IO 3.x/4.x)
+// are accepted on the default namespace.
+type SocketIOController struct {
+	hub       *services.RealtimeHubService
+	ids       utils.IDGenerator
+	eventName string
+}
+
+// NewSocketIOController creates a new SocketIOController instance.
+// Events are emitted under their type (e.g., "device_state") unless SOCKETIO_EVENT_NAME sets one name for all.
+func NewSocketIOController(hub *services.RealtimeHubService, ids utils.IDGenerator) *SocketIOController {
+	return &SocketIOController{
+		hub:       hub,
+		ids:       ids,
+		eventName: utils.GetConfig().SocketIOEventName,
+	}
+}
+
+// socketIOSession is one Socket.IO connection.
+type socketIOSession struct {
+	conn   *websocket.Conn
+	sid    string
+	legacy bool
+	outbox chan []byte
+}
+
+// Connect handles GET /socket.io/ endpoint
+// @Summary      Socket.IO compatibility endpoint
+// @Description  Streams the same device event payloads as /api/realtime/ws to Socket.IO clients (2.x, 3.x and 4.x) using the websocket transport only, e.g. io(url, {transports: ["websocket"], query: {ticket}}) with a ticket from POST /api/realtime/ticket. Handshakes from other origins than the API's host and REALTIME_ALLOWED_ORIGINS are rejected. Events are emitted under their type ("device_state", "device_online", ...) or under SOCKETIO_EVENT_NAME when configured. Narrow events with device_ids, rooms and categories query parameters, or emit "subscribe" with a SubscriptionFilterDTO.
+// @Tags         07. Realtime
+// @Param        EIO         query  string  true   "Engine.IO protocol version (3 or 4)"
+// @Param        transport   query  string  true   "Must be websocket"
+// @Param        device_ids  query  string  false  "Comma-separated device IDs"
+// @Param        rooms       query  string  false  "Comma-separated room IDs"
+// @Param        categories  query  string  false  "Comma-separated device categories"
+// @Param        ticket      query  string  false  "Single-use ticket from POST /api/realtime/ticket"
+// @Success      101  {object}  dtos.DeviceEventDTO
+// @Failure      400  {object}  map[string]interface{}
+// @Failure      403  {object}  map[string]interface{}
+// @Security     BearerAuth
+// @Router       /socket.io/ [get]
+func (ctrl *SocketIOController) Connect(c *gin.Context) {
+	version := c.Query("EIO")
+	if version != "3" && version != "4" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 5, "message": "Unsupported protocol version"})
+		return
+	}
+	if c.Query("transport") != "websocket" {
+		c.JSON(http.StatusBadRequest, gin.H{"code": 0, "message": "Transport unknown: only the websocket transport is supported"})
+		return
+	}
+
+	sid, err := ctrl.ids.NewID(10)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"code": 2, "message": "Failed to create session"})
+		return
+	}
+
+	filter := dtos.SubscriptionFilterDTO{
+		DeviceIDs:  splitQueryList(c.Query("device_ids")),
+		Rooms:      splitQueryList(c.Query("rooms")),
+		Categories: splitQueryList(c.Query("categories")),
+	}
+
+	conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		utils.LogError("SocketIOController: websocket upgrade failed: %v", err)
+		return
+	}
+
+	session := &socketIOSession{
+		conn:   conn,
+		sid:    sid,
+		legacy: version == "3",
+		outbox: make(chan []byte, socketIOOutboxSize),
+	}
+	handshake := map[string]interface{}{
+		"sid":          sid,
+		"upgrades":     []string{},
+		"pingInterval": socketIOPingInterval.Milliseconds(),
+		"pingTimeout":  socketIOPingTimeout.Milliseconds(),
+	}
+	if !session.legacy {
+		handshake["maxPayload"] = socketIOMaxPayload
+	}
+	open, _ := json.Marshal(handshake)
+	conn.SetWriteDeadline(time.Now().Add(writeWait))
+	if err := conn.WriteMessage(websocket.TextMessage, append([]byte{engineOpen}, open...)); err != nil {
+		conn.Close()
+		return
+	}
+	if session.legacy {
+		// Socket.IO 2.x joins the default namespace without a connect request
+		session.queue([]byte{engineMessage, socketConnect})
+	}
+
+	client := ctrl.hub.Register(filter)
+	utils.LogDebug("SocketIOController: session %s (EIO=%s) subscribed with filter %+v", sid, version, filter)
+
+	go ctrl.writePump(session, client)
+	ctrl.readPump(session, client)
+}
+
+// readPump handles Engine.IO and Socket.IO packets from the peer until the connection closes.
+func (ctrl *SocketIOController) readPump(session *socketIOSession, client *services.RealtimeClient) {
+	defer func() {
+		ctrl.hub.Unregister(client)
+		session.conn.Close()
+	}()
+
+	deadline := socketIOPingInterval + socketIOPingTimeout
+	session.conn.SetReadLimit(socketIOMaxPayload)
+	session.conn.SetReadDeadline(time.Now().Add(deadline))
+
+	for {
+		_, data, err := session.conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				utils.LogWarn("SocketIOController: read error on session %s: %v", session.sid, err)
+			}
+			return
+		}
+		session.conn.SetReadDeadline(time.Now().Add(deadline))
+		if len(data) == 0 {
+			continue
+		}
+
+		switch data[0] {
+		case enginePing:
+			// Engine.IO 3 clients drive the heartbeat; probes ("2probe") are echoed back as "3probe"
+			session.queue(append([]byte{enginePong}, data[1:]...))
+		case enginePong:
+			// Heartbeat answer of Engine.IO 4 clients; the read deadline was already extended
+		case engineClose:
+			return
+		case engineMessage:
+			if !ctrl.handlePacket(session, client, data[1:]) {
+				return
+			}
+		}
+	}
+}
+
+// writePump emits hub events and protocol replies to the peer, and sends heartbeats to Engine.IO 4 clients.
+func (ctrl *SocketIOController) writePump(session *socketIOSession, client *services.RealtimeClient) {
+	var heartbeat <-chan time.Time
+	if !session.legacy {
+		ticker := time.NewTicker(socketIOPingInterval)
+		defer ticker.Stop()
+		heartbeat = ticker.C
+	}
+	defer session.conn.Close()
+
+	write := func(frame []byte) bool {
+		session.conn.SetWriteDeadline(time.Now().Add(writeWait))
+		return session.conn.WriteMessage(websocket.TextMessage, frame) == nil
+	}
+
+	for {
+		select {
+		case payload, ok := <-client.Send:
+			if !ok {
+				write([]byte{engineMessage, socketDisconnect})
+				return
+			}
+			if !write(ctrl.eventFrame(payload)) {
+				return
+			}
+		case frame := <-session.outbox:
+			if !write(frame) {
+				return
+			}
+		case <-heartbeat:
+			if !write([]byte{enginePing}) {
+				return
+			}
+		}
+	}
+}
+
+// handlePacket processes a Socket.IO packet and reports whether the session stays open.
+func (ctrl *SocketIOController) handlePacket(session *socketIOSession, client *services.RealtimeClient, packet []byte) bool {
+	if len(packet) == 0 {
+		return true
+	}
+	packetType := packet[0]
+	namespace, ackID, payload := parseSocketIOPacket(packet[1:])
+
+	switch packetType {
+	case socketConnect:
+		if namespace != "/" {
+			message := `{"message":"Invalid namespace"}`
+			if session.legacy {
+				message = `"Invalid namespace"`
+			}
+			session.queue([]byte(fmt.Sprintf("%c%c%s,%s", engineMessage, socketConnectError, namespace, message)))
+			return true
+		}
+		if !session.legacy {
+			session.queue([]byte(fmt.Sprintf(`%c%c{"sid":"%s"}`, engineMessage, socketConnect, session.sid)))
+		}
+	case socketDisconnect:
+		return namespace != "/"
+	case socketEvent:
+		if namespace != "/" {
+			return true
+		}
+		var args []json.RawMessage
+		if err := json.Unmarshal(payload, &args); err != nil || len(args) == 0 {
+			return true
+		}
+		var name string
+		json.Unmarshal(args[0], &name)
+		if name == "subscribe" && len(args) > 1 {
+			var filter dtos.SubscriptionFilterDTO
+			if err := json.Unmarshal(args[1], &filter); err == nil {
+				client.SetFilter(filter)
+				utils.LogDebug("SocketIOController: session %s filter updated to %+v", session.sid, filter)
+			}
+		}
+		if ackID != "" {
+			session.queue([]byte(fmt.Sprintf("%c%c%s[]", engineMessage, socketAck, ackID)))
+		}
+	}
+	return true
+}
+
+// eventFrame wraps a hub event as a Socket.IO event packet: 42["<name>",<event>].
+func (ctrl *SocketIOController) eventFrame(payload []byte) []byte {
+	name := ctrl.eventName
+	if name == "" {
+		var event struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(payload, &event)
+		name = event.Type
+	}
+	if name == "" {
+		name = "device_event"
+	}
+	encodedName, _ := json.Marshal(name)
+
+	var frame bytes.Buffer
+	frame.Grow(len(payload) + len(encodedName) + 5)
+	frame.WriteByte(engineMessage)
+	frame.WriteByte(socketEvent)
+	frame.WriteByte('[')
+	frame.Write(encodedName)
+	frame.WriteByte(',')
+	frame.Write(payload)
+	frame.WriteByte(']')
+	return frame.Bytes()
+}
+
+// queue hands a frame to the write pump. Frames are dropped if the peer stopped reading.
+func (s *socketIOSession) queue(frame []byte) {
+	select {
+	case s.outbox <- frame:
+	default:
+	}
+}
+
+// parseSocketIOPacket splits the body of a Socket.IO packet into its namespace ("/" when omitted),
+// acknowledgement ID and JSON payload.
+func parseSocketIOPacket(body []byte) (string, string, []byte) {
+	namespace := "/"
+	if len(body) > 0 && body[0] == '/' {
+		end := bytes.IndexByte(body, ',')
+		if end < 0 {
+			return string(body), "", nil
+		}
+		namespace = string(body[:end])
+		body = body[end+1:]
+	}
+
+	digits := 0
+	for digits < len(body) && body[digits] >= '0' && body[digits] <= '9' {
+		digits++
+	}
+	ackID := ""
+	if digits > 0 {
+		if _, err := strconv.ParseUint(string(body[:digits]), 10, 64); err == nil {
+			ackID = string(body[:digits])
+		}
+	}
+	return namespace, ackID, body[digits:]
+}
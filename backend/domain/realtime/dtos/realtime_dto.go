@@ -0,0 +1,37 @@
+package dtos
+
+// EventPriorityHigh marks safety-relevant events (e.g., leak or smoke alarms).
+// High-priority events are never dropped for slow clients and must not be suppressed by quiet hours.
+const EventPriorityHigh = "high"
+
+// DeviceEventDTO represents a device state change pushed to realtime subscribers
+type DeviceEventDTO struct {
+	Type      string                 `json:"type"`
+	DeviceID  string                 `json:"device_id"`
+	Category  string                 `json:"category,omitempty"`
+	Priority  string                 `json:"priority,omitempty"`
+	Simulated bool                   `json:"simulated,omitempty"`
+	Status    []DeviceEventStatusDTO `json:"status,omitempty"`
+	Error     string                 `json:"error,omitempty"`
+	Timestamp int64                  `json:"timestamp"`
+}
+
+// DeviceEventStatusDTO represents a single status value carried by a device event
+type DeviceEventStatusDTO struct {
+	Code  string      `json:"code"`
+	Value interface{} `json:"value"`
+}
+
+// SubscriptionFilterDTO narrows the events a realtime client receives.
+// Empty lists mean "no restriction" for that dimension.
+type SubscriptionFilterDTO struct {
+	DeviceIDs  []string `json:"device_ids"`
+	Rooms      []string `json:"rooms"`
+	Categories []string `json:"categories"`
+}
+
+// RealtimeTicketDTO is a single-use ticket authenticating one websocket upgrade
+type RealtimeTicketDTO struct {
+	Ticket    string `json:"ticket"`
+	ExpiresIn int    `json:"expires_in"`
+}
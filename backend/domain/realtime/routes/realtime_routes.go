@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/realtime/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupRealtimeRoutes registers the realtime subscription endpoints.
+//
+// param router The Gin router interface.
+// param controller The controller handling websocket subscriptions.
+func SetupRealtimeRoutes(router gin.IRouter, controller *controllers.RealtimeController) {
+	utils.LogDebug("SetupRealtimeRoutes initialized")
+	api := router.Group("/api/realtime")
+	{
+		// GET /api/realtime/ws
+		// Opens a websocket streaming device events filtered by device, room or category.
+		api.GET("/ws", controller.Subscribe)
+
+		// POST /api/realtime/ticket
+		// Issues a single-use ticket authenticating one websocket upgrade.
+		api.POST("/ticket", controller.IssueTicket)
+	}
+}
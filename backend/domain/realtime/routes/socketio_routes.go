@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/realtime/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSocketIORoutes registers the Socket.IO compatibility endpoint for legacy frontends.
+//
+// param router The Gin router interface.
+// param controller The controller speaking the Socket.IO protocol.
+func SetupSocketIORoutes(router gin.IRouter, controller *controllers.SocketIOController) {
+	utils.LogDebug("SetupSocketIORoutes initialized")
+
+	// GET /socket.io/
+	// Streams device events to Socket.IO clients over the websocket transport.
+	router.GET("/socket.io/", controller.Connect)
+}
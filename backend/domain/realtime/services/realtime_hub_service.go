@@ -0,0 +1,190 @@
+package services
+
+import (
+	"encoding/json"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/realtime/dtos"
+)
+
+// clientSendBuffer is the number of pending events buffered per client before events are dropped.
+const clientSendBuffer = 64
+
+// RoomResolver returns the room IDs a device belongs to.
+// It is injected by the rooms subsystem so the hub can evaluate room filters.
+type RoomResolver func(deviceID string) []string
+
+// RealtimeClient represents a single realtime subscriber connection.
+type RealtimeClient struct {
+	Send   chan []byte
+	mu     sync.RWMutex
+	filter dtos.SubscriptionFilterDTO
+}
+
+// SetFilter replaces the subscription filter of the client.
+//
+// param filter The new filter to apply to subsequent events.
+func (c *RealtimeClient) SetFilter(filter dtos.SubscriptionFilterDTO) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.filter = filter
+}
+
+// Filter returns a copy of the current subscription filter.
+//
+// return dtos.SubscriptionFilterDTO The active filter.
+func (c *RealtimeClient) Filter() dtos.SubscriptionFilterDTO {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.filter
+}
+
+// RealtimeHubService fans out device events to connected realtime clients.
+// Each client only receives the events matching its subscription filter.
+type RealtimeHubService struct {
+	mu           sync.RWMutex
+	clients      map[*RealtimeClient]bool
+	roomResolver RoomResolver
+}
+
+// NewRealtimeHubService initializes a new RealtimeHubService.
+//
+// return *RealtimeHubService A pointer to the initialized hub.
+func NewRealtimeHubService() *RealtimeHubService {
+	return &RealtimeHubService{
+		clients: make(map[*RealtimeClient]bool),
+	}
+}
+
+// SetRoomResolver registers the function used to resolve device rooms for room filters.
+//
+// param resolver The resolver function, or nil to disable room matching.
+func (h *RealtimeHubService) SetRoomResolver(resolver RoomResolver) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.roomResolver = resolver
+}
+
+// Register adds a new client to the hub with the given initial filter.
+//
+// param filter The initial subscription filter.
+// return *RealtimeClient The registered client.
+func (h *RealtimeHubService) Register(filter dtos.SubscriptionFilterDTO) *RealtimeClient {
+	client := &RealtimeClient{
+		Send:   make(chan []byte, clientSendBuffer),
+		filter: filter,
+	}
+
+	h.mu.Lock()
+	h.clients[client] = true
+	total := len(h.clients)
+	h.mu.Unlock()
+
+	utils.LogDebug("RealtimeHub: client registered (total: %d)", total)
+	return client
+}
+
+// Unregister removes a client from the hub and closes its send channel.
+//
+// param client The client to remove.
+func (h *RealtimeHubService) Unregister(client *RealtimeClient) {
+	h.mu.Lock()
+	if _, ok := h.clients[client]; ok {
+		delete(h.clients, client)
+		close(client.Send)
+	}
+	total := len(h.clients)
+	h.mu.Unlock()
+
+	utils.LogDebug("RealtimeHub: client unregistered (total: %d)", total)
+}
+
+// Publish delivers an event to every client whose filter matches it.
+// Slow clients whose buffer is full miss the event instead of blocking the publisher,
+// except for high-priority events, which evict the oldest buffered event to make room.
+//
+// param event The device event to broadcast.
+func (h *RealtimeHubService) Publish(event dtos.DeviceEventDTO) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		utils.LogError("RealtimeHub: failed to marshal event for device %s: %v", event.DeviceID, err)
+		return
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var rooms []string
+	roomsResolved := false
+
+	for client := range h.clients {
+		filter := client.Filter()
+		if len(filter.Rooms) > 0 && !roomsResolved {
+			if h.roomResolver != nil {
+				rooms = h.roomResolver(event.DeviceID)
+			}
+			roomsResolved = true
+		}
+
+		if !matchesFilter(filter, event, rooms) {
+			continue
+		}
+
+		select {
+		case client.Send <- payload:
+		default:
+			if event.Priority == dtos.EventPriorityHigh && evictAndSend(client, payload) {
+				utils.LogWarn("RealtimeHub: evicted oldest event for slow client to deliver high-priority event (device %s)", event.DeviceID)
+				continue
+			}
+			utils.LogWarn("RealtimeHub: dropping event for slow client (device %s)", event.DeviceID)
+		}
+	}
+}
+
+// evictAndSend discards the oldest buffered event of a client and enqueues payload in its place.
+func evictAndSend(client *RealtimeClient, payload []byte) bool {
+	select {
+	case <-client.Send:
+	default:
+	}
+	select {
+	case client.Send <- payload:
+		return true
+	default:
+		return false
+	}
+}
+
+// matchesFilter reports whether an event passes every non-empty dimension of a filter.
+func matchesFilter(filter dtos.SubscriptionFilterDTO, event dtos.DeviceEventDTO, rooms []string) bool {
+	if len(filter.DeviceIDs) > 0 && !containsString(filter.DeviceIDs, event.DeviceID) {
+		return false
+	}
+	if len(filter.Categories) > 0 && !containsString(filter.Categories, event.Category) {
+		return false
+	}
+	if len(filter.Rooms) > 0 {
+		matched := false
+		for _, room := range rooms {
+			if containsString(filter.Rooms, room) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString reports whether value is present in list.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
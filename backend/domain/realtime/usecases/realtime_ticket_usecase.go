@@ -0,0 +1,123 @@
+package usecases
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/realtime/dtos"
+	"time"
+)
+
+const (
+	// realtimeTicketTTL is how long a ticket can be redeemed after it was issued.
+	realtimeTicketTTL = 30 * time.Second
+	// realtimeTicketKeyPrefix prefixes the cache keys of issued tickets.
+	realtimeTicketKeyPrefix = "realtime_ticket:"
+	// realtimeTicketRedeemedPrefix prefixes the markers claiming a ticket, so it is redeemed only once.
+	realtimeTicketRedeemedPrefix = "realtime_ticket_redeemed:"
+)
+
+// ErrInvalidTicket is returned when a ticket is unknown, expired, already redeemed, or its API key was revoked.
+var ErrInvalidTicket = errors.New("invalid or expired websocket ticket")
+
+// ScopeChecker returns the scope an API key or identity still holds. It is implemented by the APIKeyUseCase.
+type ScopeChecker interface {
+	CurrentScope(identity utils.APIKeyIdentity) (string, bool)
+}
+
+// realtimeTicket is the stored credentials of the request a ticket was issued to.
+type realtimeTicket struct {
+	Authorization string               `json:"authorization,omitempty"`
+	Identity      utils.APIKeyIdentity `json:"identity"`
+}
+
+// RealtimeTicketUseCase issues short-lived, single-use tickets that authenticate websocket upgrades.
+// Browsers cannot attach headers to websocket handshakes, and a bearer token in the query string would end up
+// in access logs and browser history; a ticket is useless once redeemed or after realtimeTicketTTL.
+type RealtimeTicketUseCase struct {
+	cache persistence.CacheStore
+	keys  ScopeChecker
+	ids   utils.IDGenerator
+}
+
+// NewRealtimeTicketUseCase initializes a new RealtimeTicketUseCase.
+//
+// param cache The CacheStore holding issued tickets.
+// param keys The ScopeChecker verifying the API key of a ticket when it is redeemed.
+// param ids The IDGenerator used for ticket values.
+// return *RealtimeTicketUseCase A pointer to the initialized usecase.
+func NewRealtimeTicketUseCase(cache persistence.CacheStore, keys ScopeChecker, ids utils.IDGenerator) *RealtimeTicketUseCase {
+	return &RealtimeTicketUseCase{
+		cache: cache,
+		keys:  keys,
+		ids:   ids,
+	}
+}
+
+// IssueTicket stores the credentials of an authenticated request under a new ticket.
+//
+// param authorization The Authorization header of the request (empty for API-key-only requests).
+// param identity The API key or identity of the request.
+// return *dtos.RealtimeTicketDTO The ticket and its lifetime.
+// return error An error if the ticket cannot be generated or stored.
+func (uc *RealtimeTicketUseCase) IssueTicket(authorization string, identity utils.APIKeyIdentity) (*dtos.RealtimeTicketDTO, error) {
+	ticket, err := uc.ids.NewID(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate ticket: %w", err)
+	}
+	jsonData, err := json.Marshal(realtimeTicket{Authorization: authorization, Identity: identity})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal ticket: %w", err)
+	}
+	if err := uc.cache.Set(realtimeTicketKeyPrefix+utils.HashString(ticket), jsonData, realtimeTicketTTL); err != nil {
+		return nil, fmt.Errorf("failed to store ticket: %w", err)
+	}
+	return &dtos.RealtimeTicketDTO{
+		Ticket:    ticket,
+		ExpiresIn: int(realtimeTicketTTL.Seconds()),
+	}, nil
+}
+
+// RedeemTicket consumes a ticket and returns the credentials it was issued to. The API key of the ticket
+// is checked again, so a key revoked after the ticket was issued cannot open a websocket.
+//
+// param ticket The ticket from the query string of the websocket upgrade.
+// return string The Authorization header of the issuing request.
+// return utils.APIKeyIdentity The API key or identity of the issuing request, with its current scope.
+// return error ErrInvalidTicket if the ticket cannot be redeemed.
+func (uc *RealtimeTicketUseCase) RedeemTicket(ticket string) (string, utils.APIKeyIdentity, error) {
+	key := utils.HashString(ticket)
+	claimed, err := uc.cache.SetIfNotExists(realtimeTicketRedeemedPrefix+key, []byte("1"), realtimeTicketTTL)
+	if err != nil {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("failed to claim ticket: %w", err)
+	}
+	if !claimed {
+		return "", utils.APIKeyIdentity{}, ErrInvalidTicket
+	}
+
+	jsonData, err := uc.cache.Get(realtimeTicketKeyPrefix + key)
+	if err != nil {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("failed to get ticket: %w", err)
+	}
+	if jsonData == nil {
+		return "", utils.APIKeyIdentity{}, ErrInvalidTicket
+	}
+	if err := uc.cache.Delete(realtimeTicketKeyPrefix + key); err != nil {
+		utils.LogWarn("RealtimeTicketUseCase: Failed to delete redeemed ticket: %v", err)
+	}
+
+	var stored realtimeTicket
+	if err := json.Unmarshal(jsonData, &stored); err != nil {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("failed to unmarshal ticket: %w", err)
+	}
+	if stored.Identity.Scope != "" {
+		scope, ok := uc.keys.CurrentScope(stored.Identity)
+		if !ok {
+			return "", utils.APIKeyIdentity{}, ErrInvalidTicket
+		}
+		stored.Identity.Scope = scope
+	}
+	return stored.Authorization, stored.Identity, nil
+}
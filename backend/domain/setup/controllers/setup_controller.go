@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+	setup_dtos "teralux_app/domain/setup/dtos"
+	"teralux_app/domain/setup/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupController handles the one-time setup of a fresh install
+type SetupController struct {
+	useCase *usecases.SetupUseCase
+}
+
+// NewSetupController creates a new SetupController instance
+func NewSetupController(useCase *usecases.SetupUseCase) *SetupController {
+	return &SetupController{
+		useCase: useCase,
+	}
+}
+
+// GetSetupStatus handles GET /api/setup endpoint
+// @Summary      Get Setup Status
+// @Description  Reports whether the install still needs setup, i.e. no admin API key exists yet.
+// @Tags         15. Setup
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=setup_dtos.SetupStatusDTO}
+// @Router       /api/setup [get]
+func (c *SetupController) GetSetupStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Setup status retrieved",
+		Data:    c.useCase.GetStatus(),
+	})
+}
+
+// CompleteSetup handles POST /api/setup endpoint
+// @Summary      Complete Setup
+// @Description  Validates the Tuya credentials, issues the first admin API key and writes the configuration to the .env file. Only available while neither API_KEY nor a managed admin key exists, and only with the setup token printed to the server log at startup (or SETUP_TOKEN). The API key is only returned here.
+// @Tags         15. Setup
+// @Accept       json
+// @Produce      json
+// @Param        X-Setup-Token  header  string                      true  "Setup token"
+// @Param        request        body    setup_dtos.SetupRequestDTO  true  "Tuya credentials"
+// @Success      201  {object}  dtos.StandardResponse{data=setup_dtos.SetupResultDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      401  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Router       /api/setup [post]
+func (c *SetupController) CompleteSetup(ctx *gin.Context) {
+	var req setup_dtos.SetupRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.CompleteSetup(ctx.Request.Context(), ctx.GetHeader("X-Setup-Token"), req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "CompleteSetup", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Setup completed. Store the API key now, it will not be shown again",
+		Data:    result,
+	})
+}
@@ -0,0 +1,23 @@
+package dtos
+
+// SetupStatusDTO reports whether the one-time setup is still open
+type SetupStatusDTO struct {
+	SetupRequired  bool `json:"setup_required"`
+	TuyaConfigured bool `json:"tuya_configured"`
+}
+
+// SetupRequestDTO carries the Tuya cloud project credentials of a fresh install.
+// Empty fields fall back to the values already set in the environment
+type SetupRequestDTO struct {
+	TuyaClientID     string `json:"tuya_client_id" example:"your_tuya_client_id"`
+	TuyaAccessSecret string `json:"tuya_access_secret" example:"your_tuya_access_secret"`
+	TuyaBaseURL      string `json:"tuya_base_url" binding:"omitempty,url" example:"https://openapi.tuyaus.com"`
+	TuyaUserID       string `json:"tuya_user_id" example:"your_tuya_user_id"`
+}
+
+// SetupResultDTO is returned once when setup completes. The API key is not shown again
+type SetupResultDTO struct {
+	ApiKey     string `json:"api_key"`
+	TuyaUserID string `json:"tuya_user_id"`
+	EnvFile    string `json:"env_file"`
+}
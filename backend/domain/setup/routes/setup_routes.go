@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/setup/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupSetupRoutes registers the one-time setup endpoints.
+// They are unauthenticated by design; the usecase guards them with the setup token.
+//
+// param router The Gin router interface.
+// param controller The controller handling setup.
+func SetupSetupRoutes(router gin.IRouter, controller *controllers.SetupController) {
+	utils.LogDebug("SetupSetupRoutes initialized")
+	api := router.Group("/api/setup")
+	{
+		// GET /api/setup
+		// Reports whether setup is still required.
+		api.GET("", controller.GetSetupStatus)
+
+		// POST /api/setup
+		// Validates Tuya credentials and issues the first admin API key.
+		api.POST("", controller.CompleteSetup)
+	}
+}
@@ -0,0 +1,182 @@
+package usecases
+
+import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/setup/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	tuya_services "teralux_app/domain/tuya/services"
+)
+
+// apiKeySize is the entropy of a generated admin API key in bytes.
+const apiKeySize = 32
+
+// AdminKeyChecker reports whether a managed admin API key exists. It is implemented by the APIKeyUseCase.
+type AdminKeyChecker interface {
+	HasAdminKey() bool
+}
+
+// ErrSetupCompleted is returned when setup is attempted on an install that already has an admin API key.
+var ErrSetupCompleted = tuya_errors.Conflict("setup already completed")
+
+// ErrInvalidSetupToken is returned when the setup token is missing or wrong.
+var ErrInvalidSetupToken = tuya_errors.Unauthorized("invalid setup token")
+
+// SetupUseCase runs the one-time setup of a fresh install. While no admin API key exists it accepts the
+// Tuya credentials, validates them by requesting a token, generates the first admin API key and writes
+// everything to the .env file, so nobody has to edit it by hand.
+// Setup is guarded by a setup token (SETUP_TOKEN, or a random one printed to the log at startup), since
+// the first caller becomes the admin.
+type SetupUseCase struct {
+	tuyaClient *tuya_services.TuyaClient
+	keys       AdminKeyChecker
+	ids        utils.IDGenerator
+	token      string
+	mu         sync.Mutex
+}
+
+// NewSetupUseCase initializes a new SetupUseCase.
+// When setup is required and SETUP_TOKEN is not set, a random setup token is generated.
+//
+// param tuyaClient The TuyaClient used to validate the submitted credentials.
+// param keys The AdminKeyChecker reporting managed admin keys.
+// param ids The IDGenerator used for the setup token and the API key.
+// return *SetupUseCase A pointer to the initialized usecase.
+func NewSetupUseCase(tuyaClient *tuya_services.TuyaClient, keys AdminKeyChecker, ids utils.IDGenerator) *SetupUseCase {
+	uc := &SetupUseCase{
+		tuyaClient: tuyaClient,
+		keys:       keys,
+		ids:        ids,
+		token:      utils.GetConfig().SetupToken,
+	}
+	if uc.token == "" && uc.Required() {
+		token, err := ids.NewID(16)
+		if err != nil {
+			utils.LogError("SetupUseCase: Failed to generate setup token: %v", err)
+		}
+		uc.token = token
+	}
+	return uc
+}
+
+// Required reports whether setup is still open, i.e. no admin API key is configured.
+//
+// return bool True if API_KEY is empty and no managed admin key exists.
+func (uc *SetupUseCase) Required() bool {
+	return utils.GetConfig().ApiKey == "" && !uc.keys.HasAdminKey()
+}
+
+// GeneratedToken reports whether the setup token was generated at startup rather than set in SETUP_TOKEN,
+// so it has to be shown to the operator once.
+//
+// return bool True if the token was generated.
+func (uc *SetupUseCase) GeneratedToken() bool {
+	return uc.token != "" && uc.token != utils.GetConfig().SetupToken
+}
+
+// SetupToken returns the token a caller must present to complete setup.
+//
+// return string The setup token; empty if none could be generated.
+func (uc *SetupUseCase) SetupToken() string {
+	return uc.token
+}
+
+// GetStatus reports whether setup is still open and whether Tuya credentials are configured.
+//
+// return dtos.SetupStatusDTO The setup status.
+func (uc *SetupUseCase) GetStatus() dtos.SetupStatusDTO {
+	config := utils.GetConfig()
+	return dtos.SetupStatusDTO{
+		SetupRequired:  uc.Required(),
+		TuyaConfigured: config.TuyaClientID != "" && config.TuyaClientSecret != "" && config.TuyaBaseURL != "",
+	}
+}
+
+// CompleteSetup validates the Tuya credentials, issues the first admin API key and saves the configuration.
+// The new settings apply immediately; no restart is needed.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param setupToken The setup token presented by the caller.
+// param req The Tuya credentials; empty fields fall back to the environment.
+// return *dtos.SetupResultDTO The issued API key and the written file.
+// return error ErrSetupCompleted, ErrInvalidSetupToken, a bad request error for rejected credentials,
+// or an error if the configuration cannot be written.
+func (uc *SetupUseCase) CompleteSetup(ctx context.Context, setupToken string, req dtos.SetupRequestDTO) (*dtos.SetupResultDTO, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if !uc.Required() {
+		return nil, ErrSetupCompleted
+	}
+	if uc.token == "" || subtle.ConstantTimeCompare([]byte(setupToken), []byte(uc.token)) != 1 {
+		return nil, ErrInvalidSetupToken
+	}
+
+	config := utils.GetConfig()
+	credentials := tuya_services.TuyaCredentials{
+		ClientID:     firstNonEmpty(req.TuyaClientID, config.TuyaClientID),
+		ClientSecret: firstNonEmpty(req.TuyaAccessSecret, config.TuyaClientSecret),
+		BaseURL:      strings.TrimRight(firstNonEmpty(req.TuyaBaseURL, config.TuyaBaseURL), "/"),
+	}
+	if credentials.ClientID == "" || credentials.ClientSecret == "" || credentials.BaseURL == "" {
+		return nil, tuya_errors.BadRequest("tuya_client_id, tuya_access_secret and tuya_base_url are required")
+	}
+
+	// Validate the credentials before anything is saved
+	authService := tuya_services.NewTuyaAuthService(uc.tuyaClient.WithCredentials(credentials))
+	authResponse, err := authService.FetchToken(ctx, "/v1.0/token?grant_type=1")
+	if err != nil {
+		return nil, tuya_errors.BadRequest("could not reach Tuya with these credentials: %v", err)
+	}
+	if !authResponse.Success {
+		return nil, tuya_errors.BadRequest("tuya rejected the credentials: %s (code: %d)", authResponse.Msg, authResponse.Code)
+	}
+
+	uid := firstNonEmpty(req.TuyaUserID, config.TuyaUserID, authResponse.Result.UID)
+	apiKey, err := uc.ids.NewID(apiKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate API key: %w", err)
+	}
+
+	envFile, err := utils.WriteEnvValues(map[string]string{
+		"TUYA_CLIENT_ID":     credentials.ClientID,
+		"TUYA_ACCESS_SECRET": credentials.ClientSecret,
+		"TUYA_BASE_URL":      credentials.BaseURL,
+		"TUYA_USER_ID":       uid,
+		"API_KEY":            apiKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Apply the settings to the running server in one swap, so requests never see them half applied
+	utils.UpdateConfig(func(config *utils.Config) {
+		config.TuyaClientID = credentials.ClientID
+		config.TuyaClientSecret = credentials.ClientSecret
+		config.TuyaBaseURL = credentials.BaseURL
+		config.TuyaUserID = uid
+		config.ApiKey = apiKey
+	})
+	uc.token = ""
+
+	utils.LogInfo("SetupUseCase: Setup completed, configuration written to %s", envFile)
+	return &dtos.SetupResultDTO{
+		ApiKey:     apiKey,
+		TuyaUserID: uid,
+		EnvFile:    envFile,
+	}, nil
+}
+
+// firstNonEmpty returns the first non-empty value.
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
@@ -0,0 +1,3 @@
+-- Drop room tables
+DROP TABLE IF EXISTS room_devices CASCADE;
+DROP TABLE IF EXISTS rooms CASCADE;
@@ -0,0 +1,25 @@
+-- Create rooms table
+CREATE TABLE IF NOT EXISTS rooms (
+    id VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    PRIMARY KEY (id),
+
+    -- Room names are unique
+    UNIQUE INDEX idx_rooms_name (name)
+);
+
+-- Create room_devices table
+CREATE TABLE IF NOT EXISTS room_devices (
+    room_id VARCHAR(32) NOT NULL,
+    device_id VARCHAR(64) NOT NULL,
+    PRIMARY KEY (room_id, device_id),
+    INDEX idx_room_devices_device_id (device_id),
+
+    -- Foreign key constraint with CASCADE delete
+    CONSTRAINT fk_rooms_devices
+        FOREIGN KEY (room_id)
+        REFERENCES rooms(id)
+        ON DELETE CASCADE
+);
@@ -0,0 +1,3 @@
+-- Drop api key tables
+DROP TABLE IF EXISTS api_key_grants CASCADE;
+DROP TABLE IF EXISTS api_keys CASCADE;
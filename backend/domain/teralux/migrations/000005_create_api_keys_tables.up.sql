@@ -0,0 +1,22 @@
+-- Create api_keys table
+CREATE TABLE IF NOT EXISTS api_keys (
+    id VARCHAR(32) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash VARCHAR(64) NOT NULL,
+    scope VARCHAR(16) NOT NULL,
+    created_at BIGINT,
+    revoked_at BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (id),
+
+    -- Keys are looked up by the hash of the presented key
+    UNIQUE INDEX idx_api_keys_key_hash (key_hash)
+);
+
+-- Create api_key_grants table
+CREATE TABLE IF NOT EXISTS api_key_grants (
+    api_key_id VARCHAR(32) NOT NULL,
+    kind VARCHAR(16) NOT NULL,
+    target_id VARCHAR(64) NOT NULL,
+    PRIMARY KEY (api_key_id, kind, target_id)
+);
@@ -0,0 +1,3 @@
+-- Drop device state tables
+DROP TABLE IF EXISTS device_state_history CASCADE;
+DROP TABLE IF EXISTS device_states CASCADE;
@@ -0,0 +1,18 @@
+-- Create device_states table (last commands stored as JSON)
+CREATE TABLE IF NOT EXISTS device_states (
+    device_id VARCHAR(64) NOT NULL,
+    last_commands TEXT NOT NULL,
+    updated_at BIGINT,
+    PRIMARY KEY (device_id)
+);
+
+-- Create device_state_history table (position 0 = oldest)
+CREATE TABLE IF NOT EXISTS device_state_history (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    device_id VARCHAR(64) NOT NULL,
+    position BIGINT NOT NULL,
+    last_commands TEXT NOT NULL,
+    updated_at BIGINT,
+    PRIMARY KEY (id),
+    INDEX idx_device_state_history_device_id (device_id)
+);
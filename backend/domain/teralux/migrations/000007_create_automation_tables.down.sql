@@ -0,0 +1,3 @@
+-- Drop automation tables
+DROP TABLE IF EXISTS automation_runs CASCADE;
+DROP TABLE IF EXISTS automation_rules CASCADE;
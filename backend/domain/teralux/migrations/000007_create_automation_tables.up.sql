@@ -0,0 +1,21 @@
+-- Create automation_rules table (the full rule stored as JSON in definition)
+CREATE TABLE IF NOT EXISTS automation_rules (
+    id VARCHAR(32) NOT NULL,
+    name VARCHAR(255) NOT NULL,
+    enabled BOOLEAN NOT NULL,
+    definition TEXT NOT NULL,
+    created_at BIGINT,
+    updated_at BIGINT,
+    PRIMARY KEY (id)
+);
+
+-- Create automation_runs table (position 0 = newest)
+CREATE TABLE IF NOT EXISTS automation_runs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    rule_id VARCHAR(32) NOT NULL,
+    position BIGINT NOT NULL,
+    triggered_at BIGINT NOT NULL,
+    run TEXT NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_automation_runs_rule_id (rule_id)
+);
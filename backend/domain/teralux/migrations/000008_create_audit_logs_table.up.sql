@@ -0,0 +1,15 @@
+-- Create audit_logs table (recorded_at in Unix nanoseconds orders the entries)
+CREATE TABLE IF NOT EXISTS audit_logs (
+    seq BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    id VARCHAR(32) NOT NULL,
+    recorded_at BIGINT NOT NULL,
+    `timestamp` BIGINT NOT NULL,
+    action VARCHAR(32) NOT NULL,
+    device_id VARCHAR(64) NOT NULL,
+    detail TEXT,
+    success BOOLEAN NOT NULL,
+    error TEXT,
+    PRIMARY KEY (seq),
+    INDEX idx_audit_logs_recorded_at (recorded_at),
+    INDEX idx_audit_logs_device_id (device_id)
+);
@@ -0,0 +1,11 @@
+// Package migrations embeds the SQL schema migrations, named "{version}_{name}.up.sql" and
+// "{version}_{name}.down.sql" as expected by golang-migrate (see the migrate-* Makefile targets).
+// The server applies pending up migrations on startup (see infrastructure.MigrateUp).
+package migrations
+
+import "embed"
+
+// Files holds the migration files.
+//
+//go:embed *.sql
+var Files embed.FS
@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// tokenExpiredMessage is the established message of responses to requests made with an expired Tuya token.
+const tokenExpiredMessage = "Token expired. Please login or refresh the token"
+
+// abortWithError records the error of a failed operation and stops the handler chain (see
+// middlewares.AbortWithError).
+//
+// param ctx The Gin context.
+// param operation The name of the failed operation, for the log.
+// param err The error.
+func abortWithError(ctx *gin.Context, operation string, err error) {
+	middlewares.AbortWithError(ctx, operation, err)
+}
+
+// TuyaErrorResponse maps an error to its response from the typed error (see domain/tuya/errors): its HTTP
+// status, message, machine-readable code, Tuya error code and remediation hint. Untyped errors answer 500.
+// Expired Tuya tokens keep the established "Token expired" message.
+//
+// param err The error.
+// return int The HTTP status.
+// return dtos.StandardResponse The response body.
+func TuyaErrorResponse(err error) (int, dtos.StandardResponse) {
+	typed := tuya_errors.Classify(err)
+	message := typed.Message
+	if typed.HTTPStatus == http.StatusUnauthorized && typed.TuyaCode != 0 {
+		message = tokenExpiredMessage
+	}
+
+	return typed.HTTPStatus, dtos.StandardResponse{
+		Status:  false,
+		Message: message,
+		Data: tuya_dtos.ErrorDetailDTO{
+			Code:     typed.Code,
+			TuyaCode: typed.TuyaCode,
+			Hint:     typed.Hint,
+		},
+	}
+}
@@ -0,0 +1,70 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.ACUsageReportDTO{}
+
+// TuyaACUsageReportController handles the monthly air conditioner usage report
+type TuyaACUsageReportController struct {
+	useCase *usecases.ACUsageReportUseCase
+}
+
+// NewTuyaACUsageReportController creates a new TuyaACUsageReportController instance
+func NewTuyaACUsageReportController(useCase *usecases.ACUsageReportUseCase) *TuyaACUsageReportController {
+	return &TuyaACUsageReportController{
+		useCase: useCase,
+	}
+}
+
+// GetACUsageReport handles GET /api/admin/reports/ac-usage endpoint
+// @Summary      Get AC Usage Report
+// @Description  Reports the air conditioner runtime hours and energy per room for a month, to compare rooms and floors. Runtime is reconstructed from the power commands in the audit log; energy comes from recorded cur_power readings when the AC is metered, otherwise it is estimated with AC_RATED_WATTS. With format=csv the report is downloaded as CSV with one row per room and AC.
+// @Tags         08. Admin
+// @Produce      json
+// @Produce      text/csv
+// @Param        month   query     string  false  "Month as YYYY-MM in the deployment time zone (default: current month)"
+// @Param        format  query     string  false  "json (default) or csv"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.ACUsageReportDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/reports/ac-usage [get]
+func (c *TuyaACUsageReportController) GetACUsageReport(ctx *gin.Context) {
+	switch ctx.DefaultQuery("format", "json") {
+	case "json":
+		report, err := c.useCase.GetReport(ctx.Request.Context(), ctx.Query("month"))
+		if err != nil {
+			abortWithError(ctx, "GetACUsageReport", err)
+			return
+		}
+		ctx.JSON(http.StatusOK, dtos.StandardResponse{
+			Status:  true,
+			Message: "AC usage report fetched successfully",
+			Data:    report,
+		})
+	case "csv":
+		file, err := c.useCase.GetReportCSV(ctx.Request.Context(), ctx.Query("month"))
+		if err != nil {
+			abortWithError(ctx, "GetACUsageReport", err)
+			return
+		}
+		ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", file.FileName))
+		ctx.Data(http.StatusOK, "text/csv; charset=utf-8", file.Data)
+	default:
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "format must be json or csv",
+			Data:    nil,
+		})
+	}
+}
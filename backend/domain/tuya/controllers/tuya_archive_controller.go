@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.ArchiveRunResultDTO{}
+
+// TuyaArchiveController handles exports of sensor history and audit logs to object storage.
+type TuyaArchiveController struct {
+	useCase *usecases.HistoryArchiveUseCase
+}
+
+// NewTuyaArchiveController creates a new TuyaArchiveController instance.
+//
+// param useCase The HistoryArchiveUseCase performing exports.
+// return *TuyaArchiveController A pointer to the initialized controller.
+func NewTuyaArchiveController(useCase *usecases.HistoryArchiveUseCase) *TuyaArchiveController {
+	return &TuyaArchiveController{useCase: useCase}
+}
+
+// Run handles POST /api/admin/archive/run endpoint
+// @Summary      Run Archive Export
+// @Description  Immediately exports sensor history and audit logs older than ARCHIVE_LOCAL_RETENTION to object storage as CSV, then deletes them locally. The export runs as a background job: poll GET /api/jobs/{job_id} for the ArchiveRunResultDTO, which is also kept on a failed job with what was exported before the failure.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.ArchiveRunStartedDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/archive/run [post]
+func (c *TuyaArchiveController) Run(ctx *gin.Context) {
+	run, err := c.useCase.StartRun(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, "Run", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "Archive export started",
+		Data:    run,
+	})
+}
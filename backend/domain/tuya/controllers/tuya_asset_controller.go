@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// defaultAssetPageSize is the page size of the asset list when no limit is given.
+const defaultAssetPageSize = 20
+
+// Force import for Swagger
+var _ = tuya_dtos.TuyaAssetsResponseDTO{}
+
+// TuyaAssetController handles the asset-based device listing of IoT Core projects
+type TuyaAssetController struct {
+	useCase *usecases.TuyaAssetUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaAssetController creates a new TuyaAssetController instance
+func NewTuyaAssetController(useCase *usecases.TuyaAssetUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaAssetController {
+	return &TuyaAssetController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// ListAssets handles GET /api/tuya/assets endpoint
+// @Summary      List Assets
+// @Description  Lists the assets (buildings, floors, rooms) of an IoT Core project. IoT Core projects have no user UID to list devices by; their devices are listed per asset.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        name   query  string  false  "Only list assets whose name contains it"
+// @Param        page   query  int     false  "Page number (default 1)"
+// @Param        limit  query  int     false  "Items per page (default 20, max 100)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TuyaAssetsResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/assets [get]
+func (c *TuyaAssetController) ListAssets(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	page, ok := queryInt(ctx, "page", 1)
+	if !ok {
+		return
+	}
+	limit, ok := queryInt(ctx, "limit", defaultAssetPageSize)
+	if !ok {
+		return
+	}
+
+	assets, err := c.useCase.ListAssets(ctx.Request.Context(), accessToken, ctx.Query("name"), page, limit)
+	if err != nil {
+		abortWithError(ctx, "ListAssets", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Assets fetched successfully",
+		Data:    assets,
+	})
+}
+
+// ListAssetDevices handles GET /api/tuya/assets/{asset_id}/devices endpoint
+// @Summary      List Asset Devices
+// @Description  Lists the devices of an asset with their status and online state. With DEVICE_CLAIMS_ENABLED, callers without the admin X-API-KEY only see the devices assigned to their tenant.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        asset_id  path  string  true  "Asset ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TuyaAssetDevicesResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/assets/{asset_id}/devices [get]
+func (c *TuyaAssetController) ListAssetDevices(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	devices, err := c.useCase.ListAssetDevices(ctx.Request.Context(), accessToken, ctx.Param("asset_id"), visibleDevices(ctx, c.claimUC))
+	if err != nil {
+		abortWithError(ctx, "ListAssetDevices", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Asset devices fetched successfully",
+		Data:    devices,
+	})
+}
+
+// queryInt reads an optional integer query parameter, answering 400 Bad Request if it is not a number.
+func queryInt(ctx *gin.Context, name string, fallback int) (int, bool) {
+	value := ctx.Query(name)
+	if value == "" {
+		return fallback, true
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: name + " must be a number",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return parsed, true
+}
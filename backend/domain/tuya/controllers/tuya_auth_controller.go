@@ -12,38 +12,72 @@ import (
 
 // Force import for Swagger
 var _ = tuya_dtos.TuyaAuthResponseDTO{}
+var _ = tuya_dtos.TuyaSessionResponseDTO{}
 
 // TuyaAuthController handles authentication requests for Tuya
 type TuyaAuthController struct {
-	useCase *usecases.TuyaAuthUseCase
+	useCase   *usecases.TuyaAuthUseCase
+	sessionUC *usecases.TuyaSessionUseCase
 }
 
 // NewTuyaAuthController creates a new TuyaAuthController instance
-func NewTuyaAuthController(useCase *usecases.TuyaAuthUseCase) *TuyaAuthController {
+func NewTuyaAuthController(useCase *usecases.TuyaAuthUseCase, sessionUC *usecases.TuyaSessionUseCase) *TuyaAuthController {
 	return &TuyaAuthController{
-		useCase: useCase,
+		useCase:   useCase,
+		sessionUC: sessionUC,
 	}
 }
 
 // Authenticate handles POST /api/tuya/auth endpoint
 // @Summary      Authenticate with Tuya
-// @Description  Authenticates the user and retrieves a Tuya access token
+// @Description  Authenticates the user and retrieves a Tuya access token. When AUTH_SESSION_MODE is enabled, an opaque session_id (TuyaSessionResponseDTO) is returned instead and the Tuya token stays server-side. When JWT_SECRET is set, the response also carries a signed app JWT (token) for that session, which is then the only accepted Bearer value.
 // @Tags         01. Auth
 // @Accept       json
 // @Produce      json
+// @Param        X-TUYA-UID  header  string  false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
 // @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TuyaAuthResponseDTO}
+// @Failure      403  {object}  dtos.StandardResponse
 // @Failure      500  {object}  dtos.StandardResponse
 // @Security     ApiKeyAuth
 // @Router       /api/tuya/auth [get]
 func (c *TuyaAuthController) Authenticate(ctx *gin.Context) {
 	utils.LogDebug("Authenticate request received")
-	token, err := c.useCase.Authenticate()																																																																									
+	token, err := c.useCase.Authenticate(ctx.Request.Context())																																																																									
 	if err != nil {
-		utils.LogError("Authenticate failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
-			Status:  false,
-			Message: err.Error(),
-			Data:    nil,
+		abortWithError(ctx, "Authenticate", err)
+		return
+	}
+
+	// Honor an allowlisted UID override so one backend can serve multiple Tuya user accounts
+	if requestedUID := ctx.GetHeader("X-TUYA-UID"); requestedUID != "" {
+		if !utils.GetConfig().IsUIDAllowed(utils.APIKeyIdentityFromContext(ctx.Request.Context()), requestedUID) {
+			utils.LogWarn("Authenticate: UID override '%s' not allowed for the provided API key", requestedUID)
+			ctx.JSON(http.StatusForbidden, dtos.StandardResponse{
+				Status:  false,
+				Message: "X-TUYA-UID is not allowed for this API key",
+				Data:    nil,
+			})
+			return
+		}
+		token.UID = requestedUID
+	}
+
+	if utils.GetConfig().AuthSessionMode || c.sessionUC.AppTokensEnabled() {
+		session, err := c.sessionUC.CreateSession(token, utils.APIKeyIdentity{
+			ID:      ctx.GetString("api_key_id"),
+			Scope:   ctx.GetString("api_key_scope"),
+			Subject: ctx.GetString("identity_subject"),
+		})
+		if err != nil {
+			abortWithError(ctx, "Authenticate", err)
+			return
+		}
+
+		utils.LogDebug("Authentication successful (session mode)")
+		ctx.JSON(http.StatusOK, dtos.StandardResponse{
+			Status:  true,
+			Message: "Authentication successful",
+			Data:    session,
 		})
 		return
 	}
@@ -54,4 +88,37 @@ func (c *TuyaAuthController) Authenticate(ctx *gin.Context) {
 		Message: "Authentication successful",
 		Data:    token,
 	})
+}
+
+// Logout handles DELETE /api/tuya/auth/session endpoint
+// @Summary      Revoke Session
+// @Description  Revokes the server-side session used for the current request. Only applicable when authenticating with a session_id.
+// @Tags         01. Auth
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/auth/session [delete]
+func (c *TuyaAuthController) Logout(ctx *gin.Context) {
+	sessionID := ctx.GetString("session_id")
+	if sessionID == "" {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "Request is not authenticated with a session",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := c.sessionUC.RevokeSession(sessionID); err != nil {
+		abortWithError(ctx, "Logout", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Session revoked successfully",
+		Data:    nil,
+	})
 }
\ No newline at end of file
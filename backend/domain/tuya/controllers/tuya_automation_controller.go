@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaAutomationController handles automation rules that run device commands when sensor values match
+type TuyaAutomationController struct {
+	useCase *usecases.AutomationUseCase
+}
+
+// NewTuyaAutomationController creates a new TuyaAutomationController instance
+func NewTuyaAutomationController(useCase *usecases.AutomationUseCase) *TuyaAutomationController {
+	return &TuyaAutomationController{
+		useCase: useCase,
+	}
+}
+
+// ListAutomations handles GET /api/automations endpoint
+// @Summary      List Automations
+// @Description  Lists all automation rules.
+// @Tags         11. Automations
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.AutomationRuleDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations [get]
+func (c *TuyaAutomationController) ListAutomations(ctx *gin.Context) {
+	rules, err := c.useCase.ListRules()
+	if err != nil {
+		abortWithError(ctx, "ListAutomations", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automations fetched successfully",
+		Data:    rules,
+	})
+}
+
+// CreateAutomation handles POST /api/automations endpoint
+// @Summary      Create Automation
+// @Description  Creates a rule such as "if va_temperature > 300 then set the AC to 22". The actions run once when all conditions become true, and again only after a condition stopped matching and cooldown_seconds have passed. Rules are enabled unless enabled is false. Since actions are sent with the server-managed token, the rule requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access every device it references; the key becomes the owner of the rule, and actions on devices it can no longer access fail.
+// @Tags         11. Automations
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.AutomationRuleRequestDTO  true  "Rule definition"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.AutomationRuleDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations [post]
+func (c *TuyaAutomationController) CreateAutomation(ctx *gin.Context) {
+	var req tuya_dtos.AutomationRuleRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	rule, err := c.useCase.CreateRule(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, "CreateAutomation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automation created successfully",
+		Data:    rule,
+	})
+}
+
+// GetAutomation handles GET /api/automations/{id} endpoint
+// @Summary      Get Automation
+// @Description  Returns an automation rule.
+// @Tags         11. Automations
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.AutomationRuleDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id} [get]
+func (c *TuyaAutomationController) GetAutomation(ctx *gin.Context) {
+	rule, err := c.useCase.GetRule(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetAutomation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automation fetched successfully",
+		Data:    rule,
+	})
+}
+
+// UpdateAutomation handles PUT /api/automations/{id} endpoint
+// @Summary      Update Automation
+// @Description  Replaces the conditions, actions and cooldown of a rule. The enabled state is kept unless enabled is set. Requires the same API key as creating a rule; the caller becomes the owner of the rule.
+// @Tags         11. Automations
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true  "Automation ID"
+// @Param        request  body      tuya_dtos.AutomationRuleRequestDTO  true  "Rule definition"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.AutomationRuleDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id} [put]
+func (c *TuyaAutomationController) UpdateAutomation(ctx *gin.Context) {
+	var req tuya_dtos.AutomationRuleRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	rule, err := c.useCase.UpdateRule(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "UpdateAutomation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automation updated successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteAutomation handles DELETE /api/automations/{id} endpoint
+// @Summary      Delete Automation
+// @Description  Deletes an automation rule and its history.
+// @Tags         11. Automations
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id} [delete]
+func (c *TuyaAutomationController) DeleteAutomation(ctx *gin.Context) {
+	if err := c.useCase.DeleteRule(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "DeleteAutomation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automation deleted successfully",
+		Data:    nil,
+	})
+}
+
+// EnableAutomation handles POST /api/automations/{id}/enable endpoint
+// @Summary      Enable Automation
+// @Description  Enables an automation rule. It fires on the next status report that matches all its conditions.
+// @Tags         11. Automations
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.AutomationRuleDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id}/enable [post]
+func (c *TuyaAutomationController) EnableAutomation(ctx *gin.Context) {
+	c.setEnabled(ctx, true)
+}
+
+// DisableAutomation handles POST /api/automations/{id}/disable endpoint
+// @Summary      Disable Automation
+// @Description  Disables an automation rule. Disabled rules are not evaluated but can still be run manually.
+// @Tags         11. Automations
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.AutomationRuleDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id}/disable [post]
+func (c *TuyaAutomationController) DisableAutomation(ctx *gin.Context) {
+	c.setEnabled(ctx, false)
+}
+
+// RunAutomation handles POST /api/automations/{id}/run endpoint
+// @Summary      Run Automation
+// @Description  Runs the actions of a rule immediately, regardless of its conditions. Requires an API key of control scope that may access the devices of the rule. The run is recorded in the history.
+// @Tags         11. Automations
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id}/run [post]
+func (c *TuyaAutomationController) RunAutomation(ctx *gin.Context) {
+	if err := c.useCase.RunRule(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		abortWithError(ctx, "RunAutomation", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automation run successfully",
+		Data:    nil,
+	})
+}
+
+// GetAutomationHistory handles GET /api/automations/{id}/history endpoint
+// @Summary      Get Automation History
+// @Description  Returns the latest runs of a rule (up to 100), newest first, with the outcome of each action.
+// @Tags         11. Automations
+// @Produce      json
+// @Param        id   path      string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.AutomationHistoryResponseDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/automations/{id}/history [get]
+func (c *TuyaAutomationController) GetAutomationHistory(ctx *gin.Context) {
+	history, err := c.useCase.GetHistory(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetAutomationHistory", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automation history fetched successfully",
+		Data:    history,
+	})
+}
+
+// setEnabled enables or disables the rule named in the path.
+func (c *TuyaAutomationController) setEnabled(ctx *gin.Context, enabled bool) {
+	rule, err := c.useCase.SetEnabled(ctx.Param("id"), enabled)
+	if err != nil {
+		abortWithError(ctx, "SetAutomationEnabled", err)
+		return
+	}
+
+	message := "Automation disabled successfully"
+	if enabled {
+		message = "Automation enabled successfully"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    rule,
+	})
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.BackupArchiveDTO{}
+
+// TuyaBackupController handles exports and restores of configuration and device state backups.
+type TuyaBackupController struct {
+	useCase *usecases.BackupUseCase
+}
+
+// NewTuyaBackupController creates a new TuyaBackupController instance.
+//
+// param useCase The BackupUseCase building and restoring archives.
+// return *TuyaBackupController A pointer to the initialized controller.
+func NewTuyaBackupController(useCase *usecases.BackupUseCase) *TuyaBackupController {
+	return &TuyaBackupController{useCase: useCase}
+}
+
+// Export handles POST /api/admin/backup endpoint
+// @Summary      Start Backup
+// @Description  Builds a JSON archive of the persistent data of this deployment in a background job: device states, automations, scenes, schedules, metadata, webhooks and notification rules from BadgerDB, and the rooms of the SQL database. Cache data, sessions and cooldowns are left out. Poll GET /api/jobs/{job_id} until the job succeeded, then download the archive from GET /api/admin/backup/{job_id}. Archives are kept for JOB_RETENTION.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.BackupExportStartedDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/backup [post]
+func (c *TuyaBackupController) Export(ctx *gin.Context) {
+	export, err := c.useCase.StartExport(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, "Export", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "Backup started",
+		Data:    export,
+	})
+}
+
+// Download handles GET /api/admin/backup/{job_id} endpoint
+// @Summary      Download Backup
+// @Description  Downloads the archive built by a backup job started with POST /api/admin/backup. The archive is returned as is (not wrapped in the standard response) so it can be uploaded unchanged to POST /api/admin/restore.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        job_id  path  string  true  "Backup job ID"
+// @Success      200  {object}  tuya_dtos.BackupArchiveDTO
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/backup/{job_id} [get]
+func (c *TuyaBackupController) Download(ctx *gin.Context) {
+	body, err := c.useCase.GetArchive(ctx.Param("job_id"))
+	if err != nil {
+		abortWithError(ctx, "Download", err)
+		return
+	}
+	var archive struct {
+		CreatedAt int64 `json:"created_at"`
+	}
+	if err := json.Unmarshal(body, &archive); err != nil {
+		abortWithError(ctx, "Download", fmt.Errorf("failed to decode backup: %w", err))
+		return
+	}
+
+	fileName := fmt.Sprintf("teralux-backup-%s.json", time.Unix(archive.CreatedAt, 0).UTC().Format("20060102-150405"))
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// Restore handles POST /api/admin/restore endpoint
+// @Summary      Restore Backup
+// @Description  Restores an archive downloaded from GET /api/admin/backup/{job_id}. In merge mode (default) archived entries and rooms overwrite existing ones with the same key or ID; in replace mode persistent entries and rooms missing from the archive are removed as well. Restart the server afterwards so every service reloads its configuration.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        mode     query  string                      false  "merge (default) or replace"
+// @Param        request  body   tuya_dtos.BackupArchiveDTO  true   "Backup archive"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.BackupRestoreResultDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/restore [post]
+func (c *TuyaBackupController) Restore(ctx *gin.Context) {
+	var archive tuya_dtos.BackupArchiveDTO
+	if err := ctx.ShouldBindJSON(&archive); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "Invalid backup archive: " + err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.Restore(archive, ctx.Query("mode"))
+	if err != nil {
+		abortWithError(ctx, "Restore", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Backup restored. Restart the server to reload every service",
+		Data:    result,
+	})
+}
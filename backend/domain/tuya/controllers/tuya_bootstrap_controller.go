@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaBootstrapController handles the app cold start payload and favorite devices
+type TuyaBootstrapController struct {
+	useCase    *usecases.BootstrapUseCase
+	favoriteUC *usecases.FavoriteUseCase
+	claimUC    *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaBootstrapController creates a new TuyaBootstrapController instance
+func NewTuyaBootstrapController(useCase *usecases.BootstrapUseCase, favoriteUC *usecases.FavoriteUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaBootstrapController {
+	return &TuyaBootstrapController{
+		useCase:    useCase,
+		favoriteUC: favoriteUC,
+		claimUC:    claimUC,
+	}
+}
+
+// GetBootstrap handles GET /api/bootstrap endpoint
+// @Summary      Get App Bootstrap
+// @Description  Returns everything the app needs on cold start in one round trip: session validity, device summary, rooms, favorite devices, active alarms and the house mode. Sections are assembled from cached data; sections that could not be assembled are listed in unavailable.
+// @Tags         13. Bootstrap
+// @Produce      json
+// @Param        X-TUYA-UID  header  string  false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.BootstrapDTO}
+// @Failure      401  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/bootstrap [get]
+func (c *TuyaBootstrapController) GetBootstrap(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	sessionType := usecases.BootstrapSessionTypeToken
+	sessionID := ctx.GetString("session_id")
+	if sessionID != "" {
+		sessionType = usecases.BootstrapSessionTypeSession
+	} else if ctx.GetBool("server_token") {
+		sessionType = usecases.BootstrapSessionTypeServer
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Bootstrap fetched successfully",
+		Data:    c.useCase.GetBootstrap(ctx.Request.Context(), accessToken, uid, sessionType, sessionID, visibleDevices(ctx, c.claimUC)),
+	})
+}
+
+// GetFavorites handles GET /api/favorites endpoint
+// @Summary      Get Favorite Devices
+// @Description  Returns the IDs of the user's favorite devices in display order.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        X-TUYA-UID  header  string  false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.FavoritesResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/favorites [get]
+func (c *TuyaBootstrapController) GetFavorites(ctx *gin.Context) {
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	deviceIDs, err := c.favoriteUC.GetFavorites(uid)
+	if err != nil {
+		abortWithError(ctx, "GetFavorites", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Favorites fetched successfully",
+		Data:    tuya_dtos.FavoritesResponseDTO{DeviceIDs: deviceIDs},
+	})
+}
+
+// SetFavorites handles PUT /api/favorites endpoint
+// @Summary      Set Favorite Devices
+// @Description  Replaces the user's favorite devices. The order of device_ids is the display order.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        X-TUYA-UID  header  string                         false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Param        request     body    tuya_dtos.FavoritesRequestDTO  true   "Favorite device IDs"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.FavoritesResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/favorites [put]
+func (c *TuyaBootstrapController) SetFavorites(ctx *gin.Context) {
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	var req tuya_dtos.FavoritesRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	deviceIDs, err := c.favoriteUC.SetFavorites(uid, req.DeviceIDs)
+	if err != nil {
+		abortWithError(ctx, "SetFavorites", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Favorites updated successfully",
+		Data:    tuya_dtos.FavoritesResponseDTO{DeviceIDs: deviceIDs},
+	})
+}
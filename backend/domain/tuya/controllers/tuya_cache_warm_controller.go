@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.CacheWarmResultDTO{}
+
+// TuyaCacheWarmController handles cache warms.
+type TuyaCacheWarmController struct {
+	useCase *usecases.CacheWarmUseCase
+}
+
+// NewTuyaCacheWarmController creates a new TuyaCacheWarmController instance.
+//
+// param useCase The CacheWarmUseCase queuing warms.
+// return *TuyaCacheWarmController A pointer to the initialized controller.
+func NewTuyaCacheWarmController(useCase *usecases.CacheWarmUseCase) *TuyaCacheWarmController {
+	return &TuyaCacheWarmController{useCase: useCase}
+}
+
+// StartWarm handles POST /api/cache/warm endpoint
+// @Summary      Warm Cache
+// @Description  Fetches the device list of TUYA_USER_ID and the specification of every device into the cache, e.g. after a deploy or a cache flush. The warm runs as a background job with the server-managed token: poll GET /api/jobs/{job_id} for progress and the CacheWarmResultDTO, or cancel it with POST /api/jobs/{job_id}/cancel.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.CacheWarmStartedDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/cache/warm [post]
+func (c *TuyaCacheWarmController) StartWarm(ctx *gin.Context) {
+	warm, err := c.useCase.StartWarm(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, "StartWarm", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "Cache warm started",
+		Data:    warm,
+	})
+}
@@ -0,0 +1,80 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.CameraStreamDTO{}
+
+// TuyaCameraController handles stream and snapshot requests of smart cameras
+type TuyaCameraController struct {
+	useCase *usecases.TuyaCameraUseCase
+}
+
+// NewTuyaCameraController creates a new TuyaCameraController instance
+func NewTuyaCameraController(useCase *usecases.TuyaCameraUseCase) *TuyaCameraController {
+	return &TuyaCameraController{
+		useCase: useCase,
+	}
+}
+
+// GetStream handles GET /api/tuya/devices/{id}/stream endpoint
+// @Summary      Get Camera Stream
+// @Description  Allocates a live stream URL of a camera (category sp). The URL is valid for a few minutes; request a new one to reconnect.
+// @Tags         19. Cameras
+// @Produce      json
+// @Param        id    path      string  true   "Camera ID"
+// @Param        type  query     string  false  "Stream protocol: hls, rtsp, flv or rtmp (default hls)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CameraStreamDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/stream [get]
+func (c *TuyaCameraController) GetStream(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	stream, err := c.useCase.GetStream(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Query("type"))
+	if err != nil {
+		abortWithError(ctx, "GetStream", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Stream allocated successfully",
+		Data:    stream,
+	})
+}
+
+// GetSnapshot handles GET /api/tuya/devices/{id}/snapshot endpoint
+// @Summary      Get Camera Snapshot
+// @Description  Captures a picture with a camera (category sp) and returns its URL, valid for a few minutes.
+// @Tags         19. Cameras
+// @Produce      json
+// @Param        id   path      string  true  "Camera ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CameraSnapshotDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/snapshot [get]
+func (c *TuyaCameraController) GetSnapshot(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	snapshot, err := c.useCase.GetSnapshot(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetSnapshot", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Snapshot captured successfully",
+		Data:    snapshot,
+	})
+}
@@ -0,0 +1,100 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaCategoryControlController handles normalized control requests for specific device categories
+type TuyaCategoryControlController struct {
+	useCase *usecases.TuyaCategoryControlUseCase
+}
+
+// NewTuyaCategoryControlController creates a new TuyaCategoryControlController instance
+func NewTuyaCategoryControlController(useCase *usecases.TuyaCategoryControlUseCase) *TuyaCategoryControlController {
+	return &TuyaCategoryControlController{
+		useCase: useCase,
+	}
+}
+
+// ControlFan handles POST /api/tuya/devices/{id}/fan endpoint
+// @Summary      Control Fan
+// @Description  Sets power, speed level, oscillation and direction on fan (fs) and fan switch (fskg) devices. Values are validated against the device specification and translated to its DP codes.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true  "Device ID"
+// @Param        request  body      tuya_dtos.FanControlRequestDTO   true  "Fan settings"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CategoryControlResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/fan [post]
+func (c *TuyaCategoryControlController) ControlFan(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.FanControlRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.ControlFan(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "ControlFan", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Fan command sent successfully",
+		Data:    result,
+	})
+}
+
+// ControlDimmer handles POST /api/tuya/devices/{id}/dimmer endpoint
+// @Summary      Control Dimmer
+// @Description  Sets power and brightness (1-100 percent) on dimmer (tgq) devices. Brightness is scaled to the device's raw range.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true  "Device ID"
+// @Param        request  body      tuya_dtos.DimmerControlRequestDTO   true  "Dimmer settings"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CategoryControlResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/dimmer [post]
+func (c *TuyaCategoryControlController) ControlDimmer(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.DimmerControlRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.ControlDimmer(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "ControlDimmer", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Dimmer command sent successfully",
+		Data:    result,
+	})
+}
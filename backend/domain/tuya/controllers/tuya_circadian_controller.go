@@ -0,0 +1,130 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaCircadianController handles adaptive lighting configuration and dispatch
+type TuyaCircadianController struct {
+	useCase *usecases.CircadianUseCase
+}
+
+// NewTuyaCircadianController creates a new TuyaCircadianController instance
+func NewTuyaCircadianController(useCase *usecases.CircadianUseCase) *TuyaCircadianController {
+	return &TuyaCircadianController{
+		useCase: useCase,
+	}
+}
+
+// GetStatus handles GET /api/tuya/circadian endpoint
+// @Summary      Get Adaptive Lighting
+// @Description  Returns the adaptive lighting configuration with the current target and manual-override state of each light.
+// @Tags         03. Device Control
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CircadianStatusDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/circadian [get]
+func (c *TuyaCircadianController) GetStatus(ctx *gin.Context) {
+	status, err := c.useCase.GetStatus()
+	if err != nil {
+		abortWithError(ctx, "GetStatus", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Adaptive lighting fetched successfully",
+		Data:    status,
+	})
+}
+
+// SetConfig handles PUT /api/tuya/circadian endpoint
+// @Summary      Configure Adaptive Lighting
+// @Description  Replaces the adaptive lighting configuration. Curve points ("HH:MM", brightness and temperature percentages, temperature 0 = warmest) are interpolated over the day; each light may have its own curve or adjust temperature only.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.CircadianConfigDTO  true  "Adaptive lighting configuration"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CircadianStatusDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/circadian [put]
+func (c *TuyaCircadianController) SetConfig(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.CircadianConfigDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	status, err := c.useCase.SetConfig(ctx.Request.Context(), accessToken, req)
+	if err != nil {
+		abortWithError(ctx, "SetConfig", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Adaptive lighting saved successfully",
+		Data:    status,
+	})
+}
+
+// Dispatch handles POST /api/tuya/circadian/dispatch endpoint
+// @Summary      Run Adaptive Lighting Now
+// @Description  Moves every enrolled light to its current target immediately instead of waiting for the next scheduled run.
+// @Tags         03. Device Control
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.CircadianDispatchResultDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/circadian/dispatch [post]
+func (c *TuyaCircadianController) Dispatch(ctx *gin.Context) {
+	results, err := c.useCase.Dispatch(ctx.Request.Context())
+	if err != nil {
+		abortWithError(ctx, "Dispatch", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Adaptive lighting dispatched successfully",
+		Data:    results,
+	})
+}
+
+// ResumeLight handles POST /api/tuya/circadian/lights/{id}/resume endpoint
+// @Summary      Resume Adaptive Lighting
+// @Description  Clears a detected manual override so the light follows the curve again on the next run.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/circadian/lights/{id}/resume [post]
+func (c *TuyaCircadianController) ResumeLight(ctx *gin.Context) {
+	if err := c.useCase.ResumeLight(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "ResumeLight", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Adaptive lighting resumed successfully",
+		Data:    nil,
+	})
+}
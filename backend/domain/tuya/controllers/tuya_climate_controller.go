@@ -0,0 +1,88 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaClimateController handles the normalized climate endpoints of IR and native air conditioners
+type TuyaClimateController struct {
+	useCase *usecases.TuyaClimateUseCase
+}
+
+// NewTuyaClimateController creates a new TuyaClimateController instance
+func NewTuyaClimateController(useCase *usecases.TuyaClimateUseCase) *TuyaClimateController {
+	return &TuyaClimateController{
+		useCase: useCase,
+	}
+}
+
+// GetClimate handles GET /api/tuya/devices/{id}/climate endpoint
+// @Summary      Get Climate State
+// @Description  Returns power, mode, target/current temperature, fan speed and swing in one model for IR air conditioners (infrared_ac) and native Wi-Fi ACs and thermostats (kt, ktkzq, wk, wkf, qn), along with the settings the device accepts.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.ClimateStateDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/climate [get]
+func (c *TuyaClimateController) GetClimate(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	state, err := c.useCase.GetClimate(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetClimate", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Climate state retrieved successfully",
+		Data:    state,
+	})
+}
+
+// ControlClimate handles POST /api/tuya/devices/{id}/climate endpoint
+// @Summary      Control Climate
+// @Description  Applies normalized climate settings. IR air conditioners receive IR commands through their hub; native devices receive the matching DPs, validated against their specification. Omitted fields are left unchanged; power off cannot be combined with other settings.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Device ID"
+// @Param        request  body      tuya_dtos.ClimateControlRequestDTO   true  "Climate settings"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CategoryControlResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/climate [post]
+func (c *TuyaClimateController) ControlClimate(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.ClimateControlRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.ControlClimate(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "ControlClimate", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Climate command sent successfully",
+		Data:    result,
+	})
+}
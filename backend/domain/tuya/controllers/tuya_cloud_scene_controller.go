@@ -0,0 +1,185 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.CloudScenesResponseDTO{}
+
+// TuyaCloudSceneController handles the scenes and automations configured in the Smart Life app
+type TuyaCloudSceneController struct {
+	useCase *usecases.TuyaCloudSceneUseCase
+}
+
+// NewTuyaCloudSceneController creates a new TuyaCloudSceneController instance
+func NewTuyaCloudSceneController(useCase *usecases.TuyaCloudSceneUseCase) *TuyaCloudSceneController {
+	return &TuyaCloudSceneController{
+		useCase: useCase,
+	}
+}
+
+// ListHomes handles GET /api/tuya/homes endpoint
+// @Summary      List Homes
+// @Description  Lists the Smart Life homes of the user. Scenes and automations are listed per home.
+// @Tags         16. Cloud Scenes
+// @Produce      json
+// @Param        X-TUYA-UID  header  string  false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.TuyaHomeDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/homes [get]
+func (c *TuyaCloudSceneController) ListHomes(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	homes, err := c.useCase.ListHomes(ctx.Request.Context(), accessToken, uid)
+	if err != nil {
+		abortWithError(ctx, "ListHomes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Homes fetched successfully",
+		Data:    homes,
+	})
+}
+
+// ListScenes handles GET /api/tuya/homes/{home_id}/scenes endpoint
+// @Summary      List Cloud Scenes
+// @Description  Lists the tap-to-run scenes configured in the Smart Life app for a home.
+// @Tags         16. Cloud Scenes
+// @Produce      json
+// @Param        home_id  path  string  true  "Home ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CloudScenesResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/homes/{home_id}/scenes [get]
+func (c *TuyaCloudSceneController) ListScenes(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	scenes, err := c.useCase.ListScenes(ctx.Request.Context(), accessToken, ctx.Param("home_id"))
+	if err != nil {
+		abortWithError(ctx, "ListScenes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scenes fetched successfully",
+		Data:    scenes,
+	})
+}
+
+// TriggerScene handles POST /api/tuya/homes/{home_id}/scenes/{scene_id}/trigger endpoint
+// @Summary      Trigger Cloud Scene
+// @Description  Runs a tap-to-run scene configured in the Smart Life app. The trigger is recorded in the audit log.
+// @Tags         16. Cloud Scenes
+// @Produce      json
+// @Param        home_id   path  string  true  "Home ID"
+// @Param        scene_id  path  string  true  "Scene ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CloudSceneActionResultDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/homes/{home_id}/scenes/{scene_id}/trigger [post]
+func (c *TuyaCloudSceneController) TriggerScene(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	result, err := c.useCase.TriggerScene(ctx.Request.Context(), accessToken, ctx.Param("home_id"), ctx.Param("scene_id"))
+	if err != nil {
+		abortWithError(ctx, "TriggerScene", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene triggered",
+		Data:    result,
+	})
+}
+
+// ListAutomations handles GET /api/tuya/homes/{home_id}/automations endpoint
+// @Summary      List Cloud Automations
+// @Description  Lists the automations configured in the Smart Life app for a home.
+// @Tags         16. Cloud Scenes
+// @Produce      json
+// @Param        home_id  path  string  true  "Home ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CloudAutomationsResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/homes/{home_id}/automations [get]
+func (c *TuyaCloudSceneController) ListAutomations(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	automations, err := c.useCase.ListAutomations(ctx.Request.Context(), accessToken, ctx.Param("home_id"))
+	if err != nil {
+		abortWithError(ctx, "ListAutomations", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Automations fetched successfully",
+		Data:    automations,
+	})
+}
+
+// EnableAutomation handles POST /api/tuya/homes/{home_id}/automations/{automation_id}/enable endpoint
+// @Summary      Enable Cloud Automation
+// @Description  Enables an automation configured in the Smart Life app. Automations run on their own conditions and cannot be triggered directly.
+// @Tags         16. Cloud Scenes
+// @Produce      json
+// @Param        home_id        path  string  true  "Home ID"
+// @Param        automation_id  path  string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CloudSceneActionResultDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/homes/{home_id}/automations/{automation_id}/enable [post]
+func (c *TuyaCloudSceneController) EnableAutomation(ctx *gin.Context) {
+	c.setAutomationEnabled(ctx, true)
+}
+
+// DisableAutomation handles POST /api/tuya/homes/{home_id}/automations/{automation_id}/disable endpoint
+// @Summary      Disable Cloud Automation
+// @Description  Disables an automation configured in the Smart Life app.
+// @Tags         16. Cloud Scenes
+// @Produce      json
+// @Param        home_id        path  string  true  "Home ID"
+// @Param        automation_id  path  string  true  "Automation ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CloudSceneActionResultDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/homes/{home_id}/automations/{automation_id}/disable [post]
+func (c *TuyaCloudSceneController) DisableAutomation(ctx *gin.Context) {
+	c.setAutomationEnabled(ctx, false)
+}
+
+// setAutomationEnabled implements EnableAutomation and DisableAutomation.
+func (c *TuyaCloudSceneController) setAutomationEnabled(ctx *gin.Context, enabled bool) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	result, err := c.useCase.SetAutomationEnabled(ctx.Request.Context(), accessToken, ctx.Param("home_id"), ctx.Param("automation_id"), enabled)
+	if err != nil {
+		abortWithError(ctx, "SetAutomationEnabled", err)
+		return
+	}
+
+	message := "Automation disabled"
+	if enabled {
+		message = "Automation enabled"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    result,
+	})
+}
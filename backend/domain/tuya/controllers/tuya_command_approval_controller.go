@@ -0,0 +1,277 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaCommandApprovalController handles two-person approval of protected commands
+type TuyaCommandApprovalController struct {
+	useCase *usecases.CommandApprovalUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaCommandApprovalController creates a new TuyaCommandApprovalController instance
+func NewTuyaCommandApprovalController(useCase *usecases.CommandApprovalUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaCommandApprovalController {
+	return &TuyaCommandApprovalController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// ListRules handles GET /api/admin/approval-rules endpoint
+// @Summary      List Approval Rules
+// @Description  Lists the devices whose commands require two-person approval.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.CommandApprovalRuleDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/approval-rules [get]
+func (c *TuyaCommandApprovalController) ListRules(ctx *gin.Context) {
+	rules, err := c.useCase.ListRules()
+	if err != nil {
+		abortWithError(ctx, "ListRules", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Approval rules fetched successfully",
+		Data:    rules,
+	})
+}
+
+// SetRule handles PUT /api/admin/approval-rules/{device_id} endpoint
+// @Summary      Set Approval Rule
+// @Description  Requires two-person approval for the listed DP codes of a device (every command when codes is empty). Matching commands are held as pending actions until another user approves them within ttl_seconds. IR remotes and hubs cannot be given rules, since IR commands are not held.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        device_id  path  string                                      true  "Device ID"
+// @Param        request    body  tuya_dtos.SetCommandApprovalRuleRequestDTO  true  "Protected codes"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CommandApprovalRuleDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/approval-rules/{device_id} [put]
+func (c *TuyaCommandApprovalController) SetRule(ctx *gin.Context) {
+	var req tuya_dtos.SetCommandApprovalRuleRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	rule, err := c.useCase.SetRule(ctx.Request.Context(), ctx.Param("device_id"), req)
+	if err != nil {
+		abortWithError(ctx, "SetRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Approval rule saved",
+		Data:    rule,
+	})
+}
+
+// DeleteRule handles DELETE /api/admin/approval-rules/{device_id} endpoint
+// @Summary      Delete Approval Rule
+// @Description  Sends the commands of a device directly again. Actions already pending can still be decided.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        device_id  path  string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/approval-rules/{device_id} [delete]
+func (c *TuyaCommandApprovalController) DeleteRule(ctx *gin.Context) {
+	if err := c.useCase.DeleteRule(ctx.Param("device_id")); err != nil {
+		abortWithError(ctx, "DeleteRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Approval rule deleted",
+		Data:    nil,
+	})
+}
+
+// ListPendingActions handles GET /api/tuya/approvals endpoint
+// @Summary      List Pending Actions
+// @Description  Lists commands held for two-person approval on the caller's devices, newest first. Decided and expired actions stay listed for 7 days.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        status  query  string  false  "pending, executed, failed, rejected or expired"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.PendingActionDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/approvals [get]
+func (c *TuyaCommandApprovalController) ListPendingActions(ctx *gin.Context) {
+	status := ctx.Query("status")
+	switch status {
+	case "", entities.PendingActionPending, entities.PendingActionExecuted, entities.PendingActionFailed,
+		entities.PendingActionRejected, entities.PendingActionExpired:
+	default:
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "status must be pending, executed, failed, rejected or expired",
+			Data:    nil,
+		})
+		return
+	}
+
+	actions, err := c.useCase.ListPendingActions(status)
+	if err != nil {
+		abortWithError(ctx, "ListPendingActions", err)
+		return
+	}
+	if visible := visibleDevices(ctx, c.claimUC); visible != nil {
+		filtered := make([]tuya_dtos.PendingActionDTO, 0, len(actions))
+		for _, action := range actions {
+			if visible(action.DeviceID) {
+				filtered = append(filtered, action)
+			}
+		}
+		actions = filtered
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Pending actions fetched successfully",
+		Data:    actions,
+	})
+}
+
+// GetPendingAction handles GET /api/tuya/approvals/{id} endpoint
+// @Summary      Get Pending Action
+// @Description  Returns a command held for two-person approval.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id  path  string  true  "Pending action ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.PendingActionDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/approvals/{id} [get]
+func (c *TuyaCommandApprovalController) GetPendingAction(ctx *gin.Context) {
+	action, ok := c.visibleAction(ctx, "GetPendingAction")
+	if !ok {
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Pending action fetched successfully",
+		Data:    action,
+	})
+}
+
+// ApprovePendingAction handles POST /api/tuya/approvals/{id}/approve endpoint
+// @Summary      Approve Pending Action
+// @Description  Sends the held command with the approver's token. The approver needs an API key (X-API-KEY, or the key the session was created with) or identity token of control scope that may access the device, and must be another key or user than the requester; a second session of the requester does not count. The outcome (status executed or failed) is recorded in the audit log.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id  path  string  true  "Pending action ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.PendingActionDTO}
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/approvals/{id}/approve [post]
+func (c *TuyaCommandApprovalController) ApprovePendingAction(ctx *gin.Context) {
+	if _, ok := c.visibleAction(ctx, "ApprovePendingAction"); !ok {
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	action, err := c.useCase.Approve(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ApprovePendingAction", err)
+		return
+	}
+
+	message := "Pending action approved and executed"
+	if action.Status == entities.PendingActionFailed {
+		message = "Pending action approved but the command failed"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  action.Status == entities.PendingActionExecuted,
+		Message: message,
+		Data:    action,
+	})
+}
+
+// RejectPendingAction handles POST /api/tuya/approvals/{id}/reject endpoint
+// @Summary      Reject Pending Action
+// @Description  Discards a held command. The requester may reject their own action to withdraw it; anyone else needs the same credentials as an approver.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                                   true   "Pending action ID"
+// @Param        request  body  tuya_dtos.RejectPendingActionRequestDTO  false  "Reason"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.PendingActionDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/approvals/{id}/reject [post]
+func (c *TuyaCommandApprovalController) RejectPendingAction(ctx *gin.Context) {
+	var req tuya_dtos.RejectPendingActionRequestDTO
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+	if _, ok := c.visibleAction(ctx, "RejectPendingAction"); !ok {
+		return
+	}
+
+	action, err := c.useCase.Reject(ctx.Request.Context(), ctx.Param("id"), req.Reason)
+	if err != nil {
+		abortWithError(ctx, "RejectPendingAction", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Pending action rejected",
+		Data:    action,
+	})
+}
+
+// visibleAction loads the pending action of the request, answering 404 when its device is hidden from the caller.
+func (c *TuyaCommandApprovalController) visibleAction(ctx *gin.Context, operation string) (*tuya_dtos.PendingActionDTO, bool) {
+	action, err := c.useCase.GetPendingAction(ctx.Param("id"))
+	if err == nil {
+		if visible := visibleDevices(ctx, c.claimUC); visible != nil && !visible(action.DeviceID) {
+			err = usecases.ErrPendingActionNotFound
+		}
+	}
+	if err != nil {
+		abortWithError(ctx, operation, err)
+		return nil, false
+	}
+	return action, true
+}
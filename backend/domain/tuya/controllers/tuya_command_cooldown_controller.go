@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.CommandCooldownDTO{}
+
+// TuyaCommandCooldownController exposes the command cooldowns learned per device.
+type TuyaCommandCooldownController struct {
+	useCase *usecases.CommandCooldownUseCase
+}
+
+// NewTuyaCommandCooldownController creates a new TuyaCommandCooldownController instance.
+//
+// param useCase The CommandCooldownUseCase learning cooldowns.
+// return *TuyaCommandCooldownController A pointer to the initialized controller.
+func NewTuyaCommandCooldownController(useCase *usecases.CommandCooldownUseCase) *TuyaCommandCooldownController {
+	return &TuyaCommandCooldownController{useCase: useCase}
+}
+
+// ListCooldowns handles GET /api/admin/command-cooldowns endpoint
+// @Summary      List Command Cooldowns
+// @Description  Lists devices (and IR hubs) that rejected commands sent in rapid sequence, with the gap learned between their commands. Commands to these devices are delayed until the cooldown passed.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.CommandCooldownDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/command-cooldowns [get]
+func (c *TuyaCommandCooldownController) ListCooldowns(ctx *gin.Context) {
+	cooldowns, err := c.useCase.ListCooldowns()
+	if err != nil {
+		abortWithError(ctx, "ListCooldowns", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Command cooldowns fetched successfully",
+		Data:    cooldowns,
+	})
+}
+
+// ResetCooldown handles DELETE /api/admin/command-cooldowns/{device_id} endpoint
+// @Summary      Reset Command Cooldown
+// @Description  Forgets the cooldown learned for a device, e.g. after a firmware update or replacing an IR hub.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        device_id  path  string  true  "Device or IR hub ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/command-cooldowns/{device_id} [delete]
+func (c *TuyaCommandCooldownController) ResetCooldown(ctx *gin.Context) {
+	if err := c.useCase.ResetCooldown(ctx.Param("device_id")); err != nil {
+		abortWithError(ctx, "ResetCooldown", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Command cooldown reset",
+		Data:    nil,
+	})
+}
@@ -0,0 +1,97 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaCommandQueueController handles queued device commands and their execution status
+type TuyaCommandQueueController struct {
+	useCase *usecases.TuyaCommandQueueUseCase
+}
+
+// NewTuyaCommandQueueController creates a new TuyaCommandQueueController instance
+func NewTuyaCommandQueueController(useCase *usecases.TuyaCommandQueueUseCase) *TuyaCommandQueueController {
+	return &TuyaCommandQueueController{
+		useCase: useCase,
+	}
+}
+
+// SubmitCommand handles POST /api/tuya/commands endpoint
+// @Summary      Queue Device Command
+// @Description  Queues commands for a device. Requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access the device, since the commands are sent with the server-managed token; the key is checked again before every attempt. The commands are sent in the background and retried with exponential backoff on transient Tuya failures (timeouts, network errors, HTTP 5xx). Send an Idempotency-Key header to retry the request safely: a repeated key returns the command created the first time (200) instead of queueing it again, and reusing a key with a different body is rejected (409).
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        Idempotency-Key  header    string                            false  "Client-generated key deduplicating retries for 24h"
+// @Param        request          body      tuya_dtos.QueueCommandRequestDTO  true   "Device and commands"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CommandStatusDTO}
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.CommandStatusDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/commands [post]
+func (c *TuyaCommandQueueController) SubmitCommand(ctx *gin.Context) {
+	var req tuya_dtos.QueueCommandRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	idempotencyKey := strings.TrimSpace(ctx.GetHeader("Idempotency-Key"))
+	command, created, err := c.useCase.Submit(ctx.Request.Context(), req.DeviceID, req.Commands, idempotencyKey)
+	if err != nil {
+		abortWithError(ctx, "SubmitCommand", err)
+		return
+	}
+
+	if !created {
+		ctx.JSON(http.StatusOK, dtos.StandardResponse{
+			Status:  true,
+			Message: "Command already queued with this Idempotency-Key",
+			Data:    command,
+		})
+		return
+	}
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "Command queued successfully",
+		Data:    command,
+	})
+}
+
+// GetCommand handles GET /api/tuya/commands/{id} endpoint
+// @Summary      Get Command Status
+// @Description  Returns the execution status of a queued command: queued, running, succeeded or failed, with the attempts made and the last error. Callers only see the commands they queued; admin keys see all.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Command ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.CommandStatusDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/commands/{id} [get]
+func (c *TuyaCommandQueueController) GetCommand(ctx *gin.Context) {
+	command, err := c.useCase.GetCommand(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetCommand", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Command fetched successfully",
+		Data:    command,
+	})
+}
@@ -0,0 +1,98 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDeviceCategoryFilterController handles the deployment-wide device category filter.
+type TuyaDeviceCategoryFilterController struct {
+	useCase *usecases.DeviceCategoryFilterUseCase
+}
+
+// NewTuyaDeviceCategoryFilterController creates a new TuyaDeviceCategoryFilterController instance.
+//
+// param useCase The DeviceCategoryFilterUseCase managing the filter.
+// return *TuyaDeviceCategoryFilterController A pointer to the initialized controller.
+func NewTuyaDeviceCategoryFilterController(useCase *usecases.DeviceCategoryFilterUseCase) *TuyaDeviceCategoryFilterController {
+	return &TuyaDeviceCategoryFilterController{useCase: useCase}
+}
+
+// GetFilter handles GET /api/admin/device-categories endpoint
+// @Summary      Get Device Category Filter
+// @Description  Returns the categories allowed in and denied from device listings, and where the filter comes from (config or api).
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceCategoryFilterDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/device-categories [get]
+func (c *TuyaDeviceCategoryFilterController) GetFilter(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device category filter fetched successfully",
+		Data:    c.useCase.GetFilter(),
+	})
+}
+
+// UpdateFilter handles PUT /api/admin/device-categories endpoint
+// @Summary      Update Device Category Filter
+// @Description  Replaces the device category filter. Denied categories are never listed; with an allow list, only the allowed categories are listed. Applies to nested collections too, immediately and without a redeploy.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.UpdateDeviceCategoryFilterRequestDTO  true  "Category filter"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceCategoryFilterDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/device-categories [put]
+func (c *TuyaDeviceCategoryFilterController) UpdateFilter(ctx *gin.Context) {
+	var req tuya_dtos.UpdateDeviceCategoryFilterRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	filter, err := c.useCase.UpdateFilter(req)
+	if err != nil {
+		abortWithError(ctx, "UpdateFilter", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device category filter updated successfully",
+		Data:    filter,
+	})
+}
+
+// ResetFilter handles DELETE /api/admin/device-categories endpoint
+// @Summary      Reset Device Category Filter
+// @Description  Removes the filter set through the API, restoring DEVICE_CATEGORY_ALLOW and DEVICE_CATEGORY_DENY.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceCategoryFilterDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/device-categories [delete]
+func (c *TuyaDeviceCategoryFilterController) ResetFilter(ctx *gin.Context) {
+	filter, err := c.useCase.ResetFilter()
+	if err != nil {
+		abortWithError(ctx, "ResetFilter", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device category filter reset successfully",
+		Data:    filter,
+	})
+}
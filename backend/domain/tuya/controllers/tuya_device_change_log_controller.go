@@ -0,0 +1,53 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.DeviceChangeLogResponseDTO{}
+
+// TuyaDeviceChangeLogController handles device discovery change log requests
+type TuyaDeviceChangeLogController struct {
+	useCase *usecases.DeviceChangeLogUseCase
+}
+
+// NewTuyaDeviceChangeLogController creates a new TuyaDeviceChangeLogController instance
+func NewTuyaDeviceChangeLogController(useCase *usecases.DeviceChangeLogUseCase) *TuyaDeviceChangeLogController {
+	return &TuyaDeviceChangeLogController{
+		useCase: useCase,
+	}
+}
+
+// GetChangeLog handles GET /api/tuya/devices/changes/log endpoint
+// @Summary      Get Device Change Log
+// @Description  Lists devices added, removed, renamed or re-categorized in the Tuya account, as detected on each device list refresh. Newest entries first.
+// @Tags         02. Devices
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceChangeLogResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/changes/log [get]
+func (c *TuyaDeviceChangeLogController) GetChangeLog(ctx *gin.Context) {
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	changeLog, err := c.useCase.GetChangeLog(uid)
+	if err != nil {
+		abortWithError(ctx, "GetChangeLog", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device change log fetched successfully",
+		Data:    changeLog,
+	})
+}
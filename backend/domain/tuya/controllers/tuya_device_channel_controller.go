@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDeviceChannelController handles channel metadata and control for multi-gang switches
+type TuyaDeviceChannelController struct {
+	useCase *usecases.TuyaDeviceChannelUseCase
+}
+
+// NewTuyaDeviceChannelController creates a new TuyaDeviceChannelController instance
+func NewTuyaDeviceChannelController(useCase *usecases.TuyaDeviceChannelUseCase) *TuyaDeviceChannelController {
+	return &TuyaDeviceChannelController{
+		useCase: useCase,
+	}
+}
+
+// GetChannels handles GET /api/tuya/devices/{id}/channels endpoint
+// @Summary      Get Switch Channels
+// @Description  Lists the channels (gangs) of a multi-gang switch with their names and current values.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceChannelDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/channels [get]
+func (c *TuyaDeviceChannelController) GetChannels(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	channels, err := c.useCase.GetChannels(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetChannels", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Channels fetched successfully",
+		Data:    channels,
+	})
+}
+
+// RenameChannels handles PUT /api/tuya/devices/{id}/channels endpoint
+// @Summary      Rename Switch Channels
+// @Description  Names the channels of a multi-gang switch, e.g. {"names": {"switch_1": "Porch", "switch_2": "Garden"}}. An empty name restores the default.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true  "Device ID"
+// @Param        request  body      tuya_dtos.RenameChannelsRequestDTO  true  "Channel names"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceChannelDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/channels [put]
+func (c *TuyaDeviceChannelController) RenameChannels(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.RenameChannelsRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	channels, err := c.useCase.RenameChannels(ctx.Request.Context(), accessToken, ctx.Param("id"), req.Names)
+	if err != nil {
+		abortWithError(ctx, "RenameChannels", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Channels renamed successfully",
+		Data:    channels,
+	})
+}
+
+// SendChannelCommand handles POST /api/tuya/devices/{id}/channels/{channel}/commands endpoint
+// @Summary      Switch Channel
+// @Description  Switches a single channel on or off. The channel can be referenced by code (switch_2), index (2) or name.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true  "Device ID"
+// @Param        channel  path      string                              true  "Channel code, index or name"
+// @Param        request  body      tuya_dtos.ChannelCommandRequestDTO  true  "Channel state"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/channels/{channel}/commands [post]
+func (c *TuyaDeviceChannelController) SendChannelCommand(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.ChannelCommandRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	success, err := c.useCase.SendChannelCommand(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("channel"), *req.Value)
+	if err != nil {
+		abortWithError(ctx, "SendChannelCommand", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Channel command sent successfully",
+		Data:    dtos.SuccessResponseDTO{Success: success},
+	})
+}
@@ -0,0 +1,259 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDeviceClaimController handles device claims of tenants and their approval by admins
+type TuyaDeviceClaimController struct {
+	useCase *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaDeviceClaimController creates a new TuyaDeviceClaimController instance
+func NewTuyaDeviceClaimController(useCase *usecases.DeviceClaimUseCase) *TuyaDeviceClaimController {
+	return &TuyaDeviceClaimController{
+		useCase: useCase,
+	}
+}
+
+// SubmitClaim handles POST /api/tuya/claims endpoint
+// @Summary      Claim Device
+// @Description  Asks for a device (e.g., scanned from its QR code) to be assigned to the caller's tenant. The claim stays pending until an admin approves it. Submitting the same claim again returns the pending claim.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        X-TUYA-UID  header  string                                 false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Param        request     body    tuya_dtos.SubmitDeviceClaimRequestDTO  true   "Device to claim"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceClaimDTO}
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceClaimDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/claims [post]
+func (c *TuyaDeviceClaimController) SubmitClaim(ctx *gin.Context) {
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	var req tuya_dtos.SubmitDeviceClaimRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	claim, created, err := c.useCase.SubmitClaim(ctx.Request.Context(), accessToken, uid, req)
+	if err != nil {
+		abortWithError(ctx, "SubmitClaim", err)
+		return
+	}
+
+	statusCode, message := http.StatusCreated, "Claim submitted for approval"
+	if !created {
+		statusCode, message = http.StatusOK, "Claim is already pending approval"
+	}
+	ctx.JSON(statusCode, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    claim,
+	})
+}
+
+// ListMyClaims handles GET /api/tuya/claims endpoint
+// @Summary      List My Device Claims
+// @Description  Lists the claims submitted by the caller's tenant, newest first.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        X-TUYA-UID  header  string  false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceClaimDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/claims [get]
+func (c *TuyaDeviceClaimController) ListMyClaims(ctx *gin.Context) {
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	claims, err := c.useCase.ListClaims(uid, "")
+	if err != nil {
+		abortWithError(ctx, "ListMyClaims", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Claims fetched successfully",
+		Data:    claims,
+	})
+}
+
+// ListClaims handles GET /api/admin/claims endpoint
+// @Summary      List Device Claims
+// @Description  Lists the claims of all tenants, newest first, optionally filtered by status.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        status  query  string  false  "pending, approved or rejected"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceClaimDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/claims [get]
+func (c *TuyaDeviceClaimController) ListClaims(ctx *gin.Context) {
+	status := ctx.Query("status")
+	switch status {
+	case "", entities.DeviceClaimPending, entities.DeviceClaimApproved, entities.DeviceClaimRejected:
+	default:
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "status must be pending, approved or rejected",
+			Data:    nil,
+		})
+		return
+	}
+
+	claims, err := c.useCase.ListClaims("", status)
+	if err != nil {
+		abortWithError(ctx, "ListClaims", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Claims fetched successfully",
+		Data:    claims,
+	})
+}
+
+// ApproveClaim handles POST /api/admin/claims/{id}/approve endpoint
+// @Summary      Approve Device Claim
+// @Description  Assigns the device to the claiming tenant. Other pending claims on the device are rejected.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        id  path  string  true  "Claim ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceClaimDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/claims/{id}/approve [post]
+func (c *TuyaDeviceClaimController) ApproveClaim(ctx *gin.Context) {
+	claim, err := c.useCase.ApproveClaim(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ApproveClaim", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Claim approved",
+		Data:    claim,
+	})
+}
+
+// RejectClaim handles POST /api/admin/claims/{id}/reject endpoint
+// @Summary      Reject Device Claim
+// @Description  Rejects a pending claim. The reason is shown to the tenant.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                                 true   "Claim ID"
+// @Param        request  body  tuya_dtos.RejectDeviceClaimRequestDTO  false  "Reason"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceClaimDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/claims/{id}/reject [post]
+func (c *TuyaDeviceClaimController) RejectClaim(ctx *gin.Context) {
+	var req tuya_dtos.RejectDeviceClaimRequestDTO
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	claim, err := c.useCase.RejectClaim(ctx.Param("id"), req.Reason)
+	if err != nil {
+		abortWithError(ctx, "RejectClaim", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Claim rejected",
+		Data:    claim,
+	})
+}
+
+// ReleaseDevice handles DELETE /api/admin/claims/devices/{id} endpoint
+// @Summary      Release Claimed Device
+// @Description  Removes the assignment of a device (e.g., when a tenant moves out) so it can be claimed again.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        id  path  string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/claims/devices/{id} [delete]
+func (c *TuyaDeviceClaimController) ReleaseDevice(ctx *gin.Context) {
+	if err := c.useCase.ReleaseDevice(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "ReleaseDevice", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device released",
+		Data:    nil,
+	})
+}
+
+// visibleDevices returns the device filter for the caller's tenant (X-TUYA-UID, or TUYA_USER_ID).
+// Requests presenting an admin-scoped API key are treated as admin requests and see every claimed device.
+// API keys restricted to devices or rooms (see DeviceAccessMiddleware) only see their granted devices.
+//
+// param ctx The Gin context.
+// param claimUC The DeviceClaimUseCase (may be nil).
+// return usecases.DeviceFilter The filter, or nil when every device is visible.
+func visibleDevices(ctx *gin.Context, claimUC *usecases.DeviceClaimUseCase) usecases.DeviceFilter {
+	var claimed usecases.DeviceFilter
+	if claimUC != nil && ctx.GetString("api_key_scope") != utils.APIKeyScopeAdmin {
+		uid := ctx.GetString("tuya_uid")
+		if uid == "" {
+			uid = utils.GetConfig().TuyaUserID
+		}
+		claimed = claimUC.VisibleTo(uid)
+	}
+
+	granted, ok := ctx.Value("device_access").(func(string) bool)
+	if !ok {
+		return claimed
+	}
+	if claimed == nil {
+		return granted
+	}
+	return func(deviceID string) bool {
+		return granted(deviceID) && claimed(deviceID)
+	}
+}
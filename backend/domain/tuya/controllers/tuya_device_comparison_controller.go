@@ -0,0 +1,75 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.DeviceComparisonDTO{}
+
+// TuyaDeviceComparisonController handles side-by-side comparisons of two devices
+type TuyaDeviceComparisonController struct {
+	useCase *usecases.DeviceComparisonUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaDeviceComparisonController creates a new TuyaDeviceComparisonController instance
+func NewTuyaDeviceComparisonController(useCase *usecases.DeviceComparisonUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaDeviceComparisonController {
+	return &TuyaDeviceComparisonController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// CompareDevices handles GET /api/tuya/devices/compare endpoint
+// @Summary      Compare Devices
+// @Description  Compares two devices to troubleshoot why one of two identical devices behaves differently. Returns both devices and the fields that differ in their details, firmware versions, specification, current status and the command and sensor history of the last 7 days. Sections that cannot be read are listed as unavailable.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        ids  query     string  true  "Two comma-separated device IDs"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceComparisonDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/compare [get]
+func (c *TuyaDeviceComparisonController) CompareDevices(ctx *gin.Context) {
+	var deviceIDs []string
+	for _, id := range strings.Split(ctx.Query("ids"), ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			deviceIDs = append(deviceIDs, id)
+		}
+	}
+
+	if visible := visibleDevices(ctx, c.claimUC); visible != nil {
+		for _, id := range deviceIDs {
+			if !visible(id) {
+				ctx.JSON(http.StatusNotFound, dtos.StandardResponse{
+					Status:  false,
+					Message: "device not found",
+					Data:    nil,
+				})
+				return
+			}
+		}
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	comparison, err := c.useCase.CompareDevices(ctx.Request.Context(), accessToken, deviceIDs)
+	if err != nil {
+		abortWithError(ctx, "CompareDevices", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Devices compared successfully",
+		Data:    comparison,
+	})
+}
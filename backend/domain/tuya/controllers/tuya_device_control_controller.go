@@ -1,9 +1,11 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"teralux_app/domain/common/dtos"
 	tuya_dtos "teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
 	"teralux_app/domain/tuya/usecases"
 	"teralux_app/domain/common/utils"
 
@@ -25,13 +27,14 @@ func NewTuyaDeviceControlController(useCase *usecases.TuyaDeviceControlUseCase)
 
 // SendCommand handles the request to send commands to a device
 // @Summary      Send Command to Device
-// @Description  Sends a command to a specific Tuya device
+// @Description  Sends a command to a specific Tuya device. Commands protected by an approval rule are not sent; they are held as a pending action (202) until another user approves it via /api/approvals/{id}/approve.
 // @Tags         03. Device Control
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string                 true  "Device ID"
 // @Param        command body      tuya_dtos.TuyaCommandDTO    true  "Command Payload"
 // @Success      200  {object}  dtos.StandardResponse
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.PendingActionDTO}
 // @Failure      400  {object}  dtos.StandardResponse
 // @Failure      500  {object}  dtos.StandardResponse
 // @Security     BearerAuth
@@ -53,24 +56,21 @@ func (ctrl *TuyaDeviceControlController) SendCommand(c *gin.Context) {
 	}
 
 	commands := []tuya_dtos.TuyaCommandDTO{req}
-	success, err := ctrl.useCase.SendCommand(accessToken, deviceID, commands)
-	if err != nil {
-		utils.LogError("SendCommand failed: %v", err)
-		
-		// Check if it's a bad request error (code 1106)
-		errorMsg := err.Error()
-		statusCode := http.StatusInternalServerError
-		if len(errorMsg) >= 12 && errorMsg[:12] == "bad request:" {
-			statusCode = http.StatusBadRequest
-		}
-		
-		c.JSON(statusCode, dtos.StandardResponse{
-			Status:  false,
-			Message: err.Error(),
-			Data:    nil,
+	success, err := ctrl.useCase.SendCommand(c.Request.Context(), accessToken, deviceID, commands)
+	var approvalErr *usecases.ApprovalRequiredError
+	if errors.As(err, &approvalErr) {
+		utils.LogInfo("SendCommand: command for device %s held for approval", deviceID)
+		c.JSON(http.StatusAccepted, dtos.StandardResponse{
+			Status:  true,
+			Message: "Command requires approval by another user",
+			Data:    approvalErr.Action,
 		})
 		return
 	}
+	if err != nil {
+		abortWithError(c, "SendCommand", err)
+		return
+	}
 
 	utils.LogDebug("SendCommand success")
 	c.JSON(http.StatusOK, dtos.StandardResponse{
@@ -82,7 +82,7 @@ func (ctrl *TuyaDeviceControlController) SendCommand(c *gin.Context) {
 
 // SendIRACCommand handles the request to send a command to an IR air conditioner
 // @Summary      Send IR AC Command
-// @Description  Sends an infrared command to an AC via a specific IR device
+// @Description  Sends an infrared command to an AC via a specific IR device. The remote must belong to the IR device in the path, and API keys restricted to devices must be granted the remote as well.
 // @Tags         03. Device Control
 // @Accept       json
 // @Produce      json
@@ -90,6 +90,7 @@ func (ctrl *TuyaDeviceControlController) SendCommand(c *gin.Context) {
 // @Param        command body      tuya_dtos.TuyaIRACCommandDTO true  "IR AC Command Payload"
 // @Success      200  {object}  dtos.StandardResponse
 // @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
 // @Failure      500  {object}  dtos.StandardResponse
 // @Security     BearerAuth
 // @Router       /api/tuya/devices/{id}/commands/ir [post]
@@ -108,24 +109,15 @@ func (ctrl *TuyaDeviceControlController) SendIRACCommand(c *gin.Context) {
 	}
 
 	infraredID := c.Param("id")
+	if visible := visibleDevices(c, nil); visible != nil && !visible(req.RemoteID) {
+		abortWithError(c, "SendIRACCommand", tuya_errors.Forbidden("API key is not allowed to access device %s", req.RemoteID))
+		return
+	}
 	utils.LogDebug("SendIRACCommand: sending to %s, remoteID: %s, code: %s", infraredID, req.RemoteID, req.Code)
 
-	success, err := ctrl.useCase.SendIRACCommand(accessToken, infraredID, req.RemoteID, req.Code, req.Value)
+	success, err := ctrl.useCase.SendIRACCommand(c.Request.Context(), accessToken, infraredID, req.RemoteID, req.Code, req.Value)
 	if err != nil {
-		utils.LogError("SendIRACCommand failed: %v", err)
-		
-		// Check if it's a bad request error (code 1106)
-		errorMsg := err.Error()
-		statusCode := http.StatusInternalServerError
-		if len(errorMsg) >= 12 && errorMsg[:12] == "bad request:" {
-			statusCode = http.StatusBadRequest
-		}
-		
-		c.JSON(statusCode, dtos.StandardResponse{
-			Status:  false,
-			Message: err.Error(),
-			Data:    nil,
-		})
+		abortWithError(c, "SendIRACCommand", err)
 		return
 	}
 
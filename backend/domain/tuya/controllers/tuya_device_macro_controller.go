@@ -0,0 +1,152 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDeviceMacroController handles parameterized command macros of devices
+type TuyaDeviceMacroController struct {
+	useCase *usecases.DeviceMacroUseCase
+}
+
+// NewTuyaDeviceMacroController creates a new TuyaDeviceMacroController instance
+func NewTuyaDeviceMacroController(useCase *usecases.DeviceMacroUseCase) *TuyaDeviceMacroController {
+	return &TuyaDeviceMacroController{
+		useCase: useCase,
+	}
+}
+
+// ListMacros handles GET /api/tuya/devices/{id}/macros endpoint
+// @Summary      List Device Macros
+// @Description  Lists the command macros stored for a device.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceMacroDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/macros [get]
+func (c *TuyaDeviceMacroController) ListMacros(ctx *gin.Context) {
+	macros, err := c.useCase.ListMacros(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListMacros", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Macros fetched successfully",
+		Data:    macros,
+	})
+}
+
+// SaveMacro handles PUT /api/tuya/devices/{id}/macros/{name} endpoint
+// @Summary      Save Device Macro
+// @Description  Creates or replaces a macro. Command values may reference parameters as "${name}": a value that is exactly one placeholder takes the argument's type (e.g., {"code": "temp_set", "value": "${temp}"}), placeholders inside longer strings are inserted as text.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Device ID"
+// @Param        name     path      string                               true  "Macro name (e.g., set_ac)"
+// @Param        request  body      tuya_dtos.SaveDeviceMacroRequestDTO  true  "Parameters and command templates"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceMacroDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/macros/{name} [put]
+func (c *TuyaDeviceMacroController) SaveMacro(ctx *gin.Context) {
+	var req tuya_dtos.SaveDeviceMacroRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	macro, err := c.useCase.SaveMacro(ctx.Param("id"), ctx.Param("name"), req)
+	if err != nil {
+		abortWithError(ctx, "SaveMacro", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Macro saved successfully",
+		Data:    macro,
+	})
+}
+
+// DeleteMacro handles DELETE /api/tuya/devices/{id}/macros/{name} endpoint
+// @Summary      Delete Device Macro
+// @Description  Removes a macro from a device.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id    path      string  true  "Device ID"
+// @Param        name  path      string  true  "Macro name"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/macros/{name} [delete]
+func (c *TuyaDeviceMacroController) DeleteMacro(ctx *gin.Context) {
+	if err := c.useCase.DeleteMacro(ctx.Param("id"), ctx.Param("name")); err != nil {
+		abortWithError(ctx, "DeleteMacro", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Macro deleted successfully",
+		Data:    nil,
+	})
+}
+
+// RunMacro handles POST /api/tuya/devices/{id}/macros/{name} endpoint
+// @Summary      Run Device Macro
+// @Description  Expands a macro with the given arguments (e.g., {"params": {"temp": 24}} for set_ac) and sends the resulting commands to the device in one call. Parameters with a default may be omitted.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true   "Device ID"
+// @Param        name     path      string                              true   "Macro name"
+// @Param        request  body      tuya_dtos.RunDeviceMacroRequestDTO  false  "Macro arguments"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.RunDeviceMacroResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/macros/{name} [post]
+func (c *TuyaDeviceMacroController) RunMacro(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.RunDeviceMacroRequestDTO
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	result, err := c.useCase.RunMacro(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("name"), req.Params)
+	if err != nil {
+		abortWithError(ctx, "RunMacro", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Macro executed successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDeviceMetadataController handles device renaming and local device metadata
+type TuyaDeviceMetadataController struct {
+	useCase *usecases.TuyaDeviceMetadataUseCase
+}
+
+// NewTuyaDeviceMetadataController creates a new TuyaDeviceMetadataController instance
+func NewTuyaDeviceMetadataController(useCase *usecases.TuyaDeviceMetadataUseCase) *TuyaDeviceMetadataController {
+	return &TuyaDeviceMetadataController{
+		useCase: useCase,
+	}
+}
+
+// RenameDevice handles PUT /api/tuya/devices/{id} endpoint
+// @Summary      Rename Device
+// @Description  Renames a device in the Tuya cloud, so the new name also shows in the Smart Life app. Cached device details and lists are refreshed.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                            true  "Device ID"
+// @Param        request  body      tuya_dtos.RenameDeviceRequestDTO  true  "New name"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TuyaDeviceResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id} [put]
+func (c *TuyaDeviceMetadataController) RenameDevice(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.RenameDeviceRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	device, err := c.useCase.RenameDevice(ctx.Request.Context(), accessToken, ctx.Param("id"), req.Name)
+	if err != nil {
+		abortWithError(ctx, "RenameDevice", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device renamed successfully",
+		Data:    tuya_dtos.TuyaDeviceResponseDTO{Device: *device},
+	})
+}
+
+// GetMetadata handles GET /api/tuya/devices/{id}/metadata endpoint
+// @Summary      Get Device Metadata
+// @Description  Returns the local metadata of a device: custom label, icon, sort order and favorite flag. The same metadata is included in device responses.
+// @Tags         02. Devices
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceMetadataDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/metadata [get]
+func (c *TuyaDeviceMetadataController) GetMetadata(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	metadata, err := c.useCase.GetMetadata(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetMetadata", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device metadata fetched successfully",
+		Data:    metadata,
+	})
+}
+
+// UpdateMetadata handles PUT /api/tuya/devices/{id}/metadata endpoint
+// @Summary      Update Device Metadata
+// @Description  Changes the local metadata of a device. Omitted fields are kept; an empty label or icon, or a negative sort_order, clears it. Metadata is stored by Teralux only and shared by all users.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                    true  "Device ID"
+// @Param        request  body      tuya_dtos.UpdateDeviceMetadataRequestDTO  true  "Metadata changes"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceMetadataDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/metadata [put]
+func (c *TuyaDeviceMetadataController) UpdateMetadata(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.UpdateDeviceMetadataRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	metadata, err := c.useCase.UpdateMetadata(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "UpdateMetadata", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device metadata updated successfully",
+		Data:    metadata,
+	})
+}
+
+// SetFavorite handles POST /api/tuya/devices/{id}/favorite endpoint
+// @Summary      Set Favorite Device
+// @Description  Marks a device as favorite, or unmarks it with {"favorite": false}. Favorites come first in GET /api/tuya/devices?sort=favorite.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true   "Device ID"
+// @Param        request  body      tuya_dtos.DeviceFavoriteRequestDTO  false  "Favorite flag (default true)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceMetadataDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/favorite [post]
+func (c *TuyaDeviceMetadataController) SetFavorite(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.DeviceFavoriteRequestDTO
+	if ctx.Request.ContentLength != 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+	favorite := req.Favorite == nil || *req.Favorite
+
+	metadata, err := c.useCase.SetFavorite(ctx.Request.Context(), accessToken, ctx.Param("id"), favorite)
+	if err != nil {
+		abortWithError(ctx, "SetFavorite", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Favorite updated successfully",
+		Data:    metadata,
+	})
+}
+
+// SetOrder handles PUT /api/tuya/devices/order endpoint
+// @Summary      Set Device Order
+// @Description  Saves a custom device order, used by GET /api/tuya/devices?sort=custom (and to order favorites). Devices not listed lose their position and follow in name order.
+// @Tags         02. Devices
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.DeviceOrderRequestDTO  true  "Device IDs in display order"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceOrderResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/order [put]
+func (c *TuyaDeviceMetadataController) SetOrder(ctx *gin.Context) {
+	var req tuya_dtos.DeviceOrderRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	order, err := c.useCase.SetOrder(req.DeviceIDs)
+	if err != nil {
+		abortWithError(ctx, "SetOrder", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device order saved successfully",
+		Data:    tuya_dtos.DeviceOrderResponseDTO{DeviceIDs: order},
+	})
+}
@@ -0,0 +1,141 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDevicePresetController handles named command presets of devices
+type TuyaDevicePresetController struct {
+	useCase *usecases.DevicePresetUseCase
+}
+
+// NewTuyaDevicePresetController creates a new TuyaDevicePresetController instance
+func NewTuyaDevicePresetController(useCase *usecases.DevicePresetUseCase) *TuyaDevicePresetController {
+	return &TuyaDevicePresetController{
+		useCase: useCase,
+	}
+}
+
+// ListPresets handles GET /api/tuya/devices/{id}/presets endpoint
+// @Summary      List Device Presets
+// @Description  Lists the command presets stored for a device.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DevicePresetDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/presets [get]
+func (c *TuyaDevicePresetController) ListPresets(ctx *gin.Context) {
+	presets, err := c.useCase.ListPresets(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListPresets", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Presets fetched successfully",
+		Data:    presets,
+	})
+}
+
+// SavePreset handles POST /api/tuya/devices/{id}/presets endpoint
+// @Summary      Save Device Preset
+// @Description  Stores a named command bundle for a device (e.g., {"name": "AC Night Mode", "commands": [{"code": "switch", "value": true}, {"code": "temp_set", "value": 26}, {"code": "fan_speed_enum", "value": "1"}]}). A preset with the same name, in any case, is replaced.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                true  "Device ID"
+// @Param        request  body      tuya_dtos.SaveDevicePresetRequestDTO  true  "Preset name and commands"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DevicePresetDTO}
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.DevicePresetDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/presets [post]
+func (c *TuyaDevicePresetController) SavePreset(ctx *gin.Context) {
+	var req tuya_dtos.SaveDevicePresetRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	preset, created, err := c.useCase.SavePreset(ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "SavePreset", err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, dtos.StandardResponse{
+		Status:  true,
+		Message: "Preset saved successfully",
+		Data:    preset,
+	})
+}
+
+// DeletePreset handles DELETE /api/tuya/devices/{id}/presets/{name} endpoint
+// @Summary      Delete Device Preset
+// @Description  Removes a preset from a device.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id    path      string  true  "Device ID"
+// @Param        name  path      string  true  "Preset name"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/presets/{name} [delete]
+func (c *TuyaDevicePresetController) DeletePreset(ctx *gin.Context) {
+	if err := c.useCase.DeletePreset(ctx.Param("id"), ctx.Param("name")); err != nil {
+		abortWithError(ctx, "DeletePreset", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Preset deleted successfully",
+		Data:    nil,
+	})
+}
+
+// ExecutePreset handles POST /api/tuya/devices/{id}/presets/{name}/execute endpoint
+// @Summary      Execute Device Preset
+// @Description  Sends the commands of a preset to the device in one call, so they are applied together or not at all.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id    path      string  true  "Device ID"
+// @Param        name  path      string  true  "Preset name (any case)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.ExecuteDevicePresetResponseDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/presets/{name}/execute [post]
+func (c *TuyaDevicePresetController) ExecutePreset(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	result, err := c.useCase.ExecutePreset(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("name"))
+	if err != nil {
+		abortWithError(ctx, "ExecutePreset", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Preset executed successfully",
+		Data:    result,
+	})
+}
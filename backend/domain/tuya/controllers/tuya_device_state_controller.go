@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/utils"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.DeviceStateHistoryDTO{}
+
+// TuyaDeviceStateController handles the state history of devices and undoing state changes
+type TuyaDeviceStateController struct {
+	useCase *usecases.DeviceStateUndoUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaDeviceStateController creates a new TuyaDeviceStateController instance
+func NewTuyaDeviceStateController(useCase *usecases.DeviceStateUndoUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaDeviceStateController {
+	return &TuyaDeviceStateController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// GetStateHistory handles GET /api/tuya/devices/{id}/state/history endpoint
+// @Summary      Get Device State History
+// @Description  Returns the last known state of a device and the states it replaced, newest first. The number of kept states is set by DEVICE_STATE_HISTORY_SIZE.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceStateHistoryDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/state/history [get]
+func (c *TuyaDeviceStateController) GetStateHistory(ctx *gin.Context) {
+	deviceID := ctx.Param("id")
+	if !c.deviceVisible(ctx, deviceID) {
+		return
+	}
+
+	history, err := c.useCase.GetHistory(deviceID)
+	if err != nil {
+		abortWithError(ctx, "GetStateHistory", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device state history fetched successfully",
+		Data:    history,
+	})
+}
+
+// UndoState handles POST /api/tuya/devices/{id}/state/undo endpoint
+// @Summary      Undo Device State Change
+// @Description  Re-sends the settings of the newest previous state that differ from the current state, e.g. to revert an accidental change. Calling it again steps further back. Commands protected by an approval rule are held as a pending action (202).
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.UndoDeviceStateResponseDTO}
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.PendingActionDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/state/undo [post]
+func (c *TuyaDeviceStateController) UndoState(ctx *gin.Context) {
+	deviceID := ctx.Param("id")
+	if !c.deviceVisible(ctx, deviceID) {
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	result, err := c.useCase.Undo(ctx.Request.Context(), accessToken, deviceID)
+	var approvalErr *usecases.ApprovalRequiredError
+	if errors.As(err, &approvalErr) {
+		utils.LogInfo("UndoState: undo for device %s held for approval", deviceID)
+		ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+			Status:  true,
+			Message: "Command requires approval by another user",
+			Data:    approvalErr.Action,
+		})
+		return
+	}
+	if err != nil {
+		abortWithError(ctx, "UndoState", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device state change undone successfully",
+		Data:    result,
+	})
+}
+
+// deviceVisible answers 404 for devices claimed by another user.
+func (c *TuyaDeviceStateController) deviceVisible(ctx *gin.Context, deviceID string) bool {
+	if visible := visibleDevices(ctx, c.claimUC); visible != nil && !visible(deviceID) {
+		ctx.JSON(http.StatusNotFound, dtos.StandardResponse{
+			Status:  false,
+			Message: "device not found",
+			Data:    nil,
+		})
+		return false
+	}
+	return true
+}
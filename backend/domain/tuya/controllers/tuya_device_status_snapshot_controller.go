@@ -0,0 +1,77 @@
+package controllers
+
+import (
+	"net/http"
+	"strings"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.DeviceStatusSnapshotDTO{}
+
+// TuyaDeviceStatusSnapshotController serves the compact status snapshot of all devices
+type TuyaDeviceStatusSnapshotController struct {
+	useCase *usecases.DeviceStatusSnapshotUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaDeviceStatusSnapshotController creates a new TuyaDeviceStatusSnapshotController instance
+func NewTuyaDeviceStatusSnapshotController(useCase *usecases.DeviceStatusSnapshotUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaDeviceStatusSnapshotController {
+	return &TuyaDeviceStatusSnapshotController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// GetStatusSnapshot handles GET /api/tuya/devices/status endpoint
+// @Summary      Get Device Status Snapshot
+// @Description  Returns only the ID, online state and key status values (switches, light and climate settings, common sensor readings) of all devices in one compact payload, for dashboards that refresh frequently. It is built from the same cached device list as GET /api/tuya/devices. The snapshot version is returned as ETag; sending it back in If-None-Match returns 304 Not Modified when nothing changed. With delta=true, only the devices that changed since that version are returned, plus the removed device IDs (a full snapshot is returned when the version is no longer known).
+// @Tags         02. Devices
+// @Produce      json
+// @Param        codes          query   string  false  "Comma-separated status codes to include instead of the key status codes"
+// @Param        delta          query   bool    false  "Return only the changes since the If-None-Match version"
+// @Param        If-None-Match  header  string  false  "Snapshot version (ETag) the client holds"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceStatusSnapshotDTO}
+// @Success      304  "Not Modified"
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/status [get]
+func (c *TuyaDeviceStatusSnapshotController) GetStatusSnapshot(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	var codes []string
+	for _, code := range strings.Split(ctx.Query("codes"), ",") {
+		if code = strings.TrimSpace(code); code != "" {
+			codes = append(codes, code)
+		}
+	}
+	since := strings.Trim(strings.TrimPrefix(ctx.GetHeader("If-None-Match"), "W/"), `"`)
+
+	snapshot, notModified, err := c.useCase.GetSnapshot(ctx.Request.Context(), accessToken, uid, visibleDevices(ctx, c.claimUC), codes, since, ctx.Query("delta") == "true")
+	if err != nil {
+		abortWithError(ctx, "GetStatusSnapshot", err)
+		return
+	}
+
+	ctx.Header("ETag", `"`+snapshot.Version+`"`)
+	ctx.Header("Cache-Control", "no-cache")
+	if notModified {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Device status fetched successfully",
+		Data:    snapshot,
+	})
+}
@@ -0,0 +1,112 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDeviceTimerController handles delayed on/off timers of devices
+type TuyaDeviceTimerController struct {
+	useCase *usecases.DeviceTimerUseCase
+}
+
+// NewTuyaDeviceTimerController creates a new TuyaDeviceTimerController instance
+func NewTuyaDeviceTimerController(useCase *usecases.DeviceTimerUseCase) *TuyaDeviceTimerController {
+	return &TuyaDeviceTimerController{
+		useCase: useCase,
+	}
+}
+
+// SetTimer handles POST /api/tuya/devices/{id}/timer endpoint
+// @Summary      Set Device Timer
+// @Description  Turns a switch of the device on or off after a delay (e.g., {"action": "off", "delay_seconds": 1800}). The device's own countdown DP (countdown_1) is used when it has one and the switch is in the opposite state; otherwise the server sends the command later. Code selects the switch (defaults to switch_1, switch or switch_led). A pending timer of the same switch is replaced.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true  "Device ID"
+// @Param        request  body      tuya_dtos.SetDeviceTimerRequestDTO  true  "Action and delay"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.DeviceTimerDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/timer [post]
+func (c *TuyaDeviceTimerController) SetTimer(ctx *gin.Context) {
+	var req tuya_dtos.SetDeviceTimerRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	timer, err := c.useCase.SetTimer(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "SetTimer", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Timer set successfully",
+		Data:    timer,
+	})
+}
+
+// ListTimers handles GET /api/tuya/devices/{id}/timer endpoint
+// @Summary      List Device Timers
+// @Description  Lists the pending timers of a device with their mode (native or job) and remaining seconds.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceTimerDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/timer [get]
+func (c *TuyaDeviceTimerController) ListTimers(ctx *gin.Context) {
+	timers, err := c.useCase.ListTimers(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListTimers", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Timers fetched successfully",
+		Data:    timers,
+	})
+}
+
+// CancelTimers handles DELETE /api/tuya/devices/{id}/timer endpoint
+// @Summary      Cancel Device Timers
+// @Description  Cancels the pending timers of a device, or only the timer of one switch. Native countdowns are reset to 0 on the device.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id    path      string  true   "Device ID"
+// @Param        code  query     string  false  "Only cancel the timer of this switch code"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.DeviceTimerDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/timer [delete]
+func (c *TuyaDeviceTimerController) CancelTimers(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	timers, err := c.useCase.CancelTimers(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Query("code"))
+	if err != nil {
+		abortWithError(ctx, "CancelTimers", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Timers cancelled successfully",
+		Data:    timers,
+	})
+}
@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaDoorLockController handles unlock records and temporary passwords of smart locks
+type TuyaDoorLockController struct {
+	useCase *usecases.TuyaDoorLockUseCase
+}
+
+// NewTuyaDoorLockController creates a new TuyaDoorLockController instance
+func NewTuyaDoorLockController(useCase *usecases.TuyaDoorLockUseCase) *TuyaDoorLockController {
+	return &TuyaDoorLockController{
+		useCase: useCase,
+	}
+}
+
+// ListRecords handles GET /api/tuya/devices/{id}/lock/records endpoint
+// @Summary      List Unlock Records
+// @Description  Returns the unlock records of a smart lock (ms, jtmspro, jtmsbh), newest first. The method tells how the lock was opened (e.g., unlock_fingerprint, unlock_temporary, unlock_app).
+// @Tags         18. Door Locks
+// @Produce      json
+// @Param        id         path      string  true   "Lock ID"
+// @Param        from       query     int     false  "Start of the range (Unix seconds, default 7 days before to)"
+// @Param        to         query     int     false  "End of the range (Unix seconds, default now)"
+// @Param        page       query     int     false  "Page number (default 1)"
+// @Param        page_size  query     int     false  "Page size, up to 100 (default 20)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.LockRecordsResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/lock/records [get]
+func (c *TuyaDoorLockController) ListRecords(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	values := map[string]int64{}
+	for _, name := range []string{"from", "to", "page", "page_size"} {
+		if value := ctx.Query(name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed < 0 {
+				ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+					Status:  false,
+					Message: name + " must be a non-negative integer",
+					Data:    nil,
+				})
+				return
+			}
+			values[name] = parsed
+		}
+	}
+
+	records, err := c.useCase.ListRecords(ctx.Request.Context(), accessToken, ctx.Param("id"), values["from"], values["to"], int(values["page"]), int(values["page_size"]))
+	if err != nil {
+		abortWithError(ctx, "ListRecords", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Unlock records fetched successfully",
+		Data:    records,
+	})
+}
+
+// ListTempPasswords handles GET /api/tuya/devices/{id}/lock/temp-passwords endpoint
+// @Summary      List Temporary Passwords
+// @Description  Lists the temporary passwords of a smart lock with their validity period and phase (pending, active, frozen, deleted). The passwords themselves are never returned.
+// @Tags         18. Door Locks
+// @Produce      json
+// @Param        id   path      string  true  "Lock ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TempPasswordsResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/lock/temp-passwords [get]
+func (c *TuyaDoorLockController) ListTempPasswords(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	passwords, err := c.useCase.ListTempPasswords(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListTempPasswords", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Temporary passwords fetched successfully",
+		Data:    passwords,
+	})
+}
+
+// CreateTempPassword handles POST /api/tuya/devices/{id}/lock/temp-passwords endpoint
+// @Summary      Create Temporary Password
+// @Description  Creates a numeric temporary password valid between effective_time (default now) and invalid_time. The password is encrypted with a one-off Tuya password ticket before it is sent, and is not stored. Locks apply it on their next sync; check the phase in the list.
+// @Tags         18. Door Locks
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                             true  "Lock ID"
+// @Param        request  body      tuya_dtos.TempPasswordRequestDTO   true  "Temporary password"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.TempPasswordDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/lock/temp-passwords [post]
+func (c *TuyaDoorLockController) CreateTempPassword(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.TempPasswordRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	password, err := c.useCase.CreateTempPassword(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "CreateTempPassword", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Temporary password created successfully",
+		Data:    password,
+	})
+}
+
+// DeleteTempPassword handles DELETE /api/tuya/devices/{id}/lock/temp-passwords/{password_id} endpoint
+// @Summary      Delete Temporary Password
+// @Description  Deletes a temporary password from a smart lock.
+// @Tags         18. Door Locks
+// @Produce      json
+// @Param        id           path      string  true  "Lock ID"
+// @Param        password_id  path      int     true  "Temporary password ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/lock/temp-passwords/{password_id} [delete]
+func (c *TuyaDoorLockController) DeleteTempPassword(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	passwordID, err := strconv.ParseInt(ctx.Param("password_id"), 10, 64)
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "password_id must be an integer",
+			Data:    nil,
+		})
+		return
+	}
+
+	if err := c.useCase.DeleteTempPassword(ctx.Request.Context(), accessToken, ctx.Param("id"), passwordID); err != nil {
+		abortWithError(ctx, "DeleteTempPassword", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Temporary password deleted successfully",
+		Data:    nil,
+	})
+}
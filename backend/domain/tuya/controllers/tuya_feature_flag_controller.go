@@ -0,0 +1,127 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaFeatureFlagController handles per-device feature flags for experimental control paths.
+type TuyaFeatureFlagController struct {
+	useCase *usecases.FeatureFlagUseCase
+}
+
+// NewTuyaFeatureFlagController creates a new TuyaFeatureFlagController instance.
+//
+// param useCase The FeatureFlagUseCase managing rollouts.
+// return *TuyaFeatureFlagController A pointer to the initialized controller.
+func NewTuyaFeatureFlagController(useCase *usecases.FeatureFlagUseCase) *TuyaFeatureFlagController {
+	return &TuyaFeatureFlagController{useCase: useCase}
+}
+
+// ListFlags handles GET /api/admin/feature-flags endpoint
+// @Summary      List Feature Flags
+// @Description  Lists every feature flag with its effective rollout and where it comes from (default, config or api).
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.FeatureFlagDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/feature-flags [get]
+func (c *TuyaFeatureFlagController) ListFlags(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Feature flags fetched successfully",
+		Data:    c.useCase.ListFlags(),
+	})
+}
+
+// UpdateFlag handles PUT /api/admin/feature-flags/{name} endpoint
+// @Summary      Update Feature Flag
+// @Description  Replaces the rollout of a feature flag: a percentage of devices, plus devices always enabled or always disabled. Applies immediately without a redeploy.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        name     path      string                                 true  "Feature flag name"
+// @Param        request  body      tuya_dtos.UpdateFeatureFlagRequestDTO  true  "Rollout"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.FeatureFlagDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/feature-flags/{name} [put]
+func (c *TuyaFeatureFlagController) UpdateFlag(ctx *gin.Context) {
+	var req tuya_dtos.UpdateFeatureFlagRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	flag, err := c.useCase.UpdateFlag(ctx.Param("name"), req)
+	if err != nil {
+		abortWithError(ctx, "UpdateFlag", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Feature flag updated successfully",
+		Data:    flag,
+	})
+}
+
+// ResetFlag handles DELETE /api/admin/feature-flags/{name} endpoint
+// @Summary      Reset Feature Flag
+// @Description  Removes the rollout set through the API, restoring the FEATURE_FLAGS or default rollout.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        name  path      string  true  "Feature flag name"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.FeatureFlagDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/feature-flags/{name} [delete]
+func (c *TuyaFeatureFlagController) ResetFlag(ctx *gin.Context) {
+	flag, err := c.useCase.ResetFlag(ctx.Param("name"))
+	if err != nil {
+		abortWithError(ctx, "ResetFlag", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Feature flag reset successfully",
+		Data:    flag,
+	})
+}
+
+// Evaluate handles GET /api/admin/feature-flags/{name}/devices/{device_id} endpoint
+// @Summary      Evaluate Feature Flag
+// @Description  Reports whether a feature flag is enabled for a device.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        name       path      string  true  "Feature flag name"
+// @Param        device_id  path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.FeatureFlagEvaluationDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/feature-flags/{name}/devices/{device_id} [get]
+func (c *TuyaFeatureFlagController) Evaluate(ctx *gin.Context) {
+	evaluation, err := c.useCase.Evaluate(ctx.Param("name"), ctx.Param("device_id"))
+	if err != nil {
+		abortWithError(ctx, "Evaluate", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Feature flag evaluated successfully",
+		Data:    evaluation,
+	})
+}
@@ -1,9 +1,13 @@
 package controllers
 
 import (
+	"encoding/json"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
 	tuya_dtos "teralux_app/domain/tuya/dtos"
 	"teralux_app/domain/tuya/usecases"
 	"teralux_app/domain/common/utils"
@@ -17,42 +21,44 @@ var _ = tuya_dtos.TuyaDevicesResponseDTO{}
 // TuyaGetAllDevicesController handles get all devices requests for Tuya
 type TuyaGetAllDevicesController struct {
 	useCase *usecases.TuyaGetAllDevicesUseCase
+	claimUC *usecases.DeviceClaimUseCase
 }
 
 // NewTuyaGetAllDevicesController creates a new TuyaGetAllDevicesController instance
-func NewTuyaGetAllDevicesController(useCase *usecases.TuyaGetAllDevicesUseCase) *TuyaGetAllDevicesController {
+func NewTuyaGetAllDevicesController(useCase *usecases.TuyaGetAllDevicesUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaGetAllDevicesController {
 	return &TuyaGetAllDevicesController{
 		useCase: useCase,
+		claimUC: claimUC,
 	}
 }
 
 // GetAllDevices handles GET /api/tuya/devices endpoint
 // @Summary      Get All Devices
-// @Description  Retrieves a list of all devices. Response format depends on GET_ALL_DEVICES_RESPONSE_TYPE: 0 (Nested/Default), 1 (Flat), 2 (Merged). Sorted alphabetically by Name. For infrared_ac devices, the status array is populated with saved device state (power, temp, mode, wind) or default values if no state exists.
+// @Description  Retrieves a list of all devices. Response format depends on GET_ALL_DEVICES_RESPONSE_TYPE: 0 (Nested/Default), 1 (Flat), 2 (Merged). Sorted alphabetically by Name unless sort asks for favorites (by their sort order), the custom order or online devices first. The response carries an ETag; sending it back in If-None-Match returns 304 Not Modified while the list is unchanged. With DEVICE_CLAIMS_ENABLED, callers without the admin X-API-KEY only see the devices assigned to their tenant. For infrared_ac devices, the status array is populated with saved device state (power, temp, mode, wind) or default values if no state exists.
 // @Tags         02. Devices
 // @Accept       json
 // @Produce      json
 // @Param        page      query  int     false  "Page number"
 // @Param        limit     query  int     false  "Items per page"
 // @Param        category  query  string  false  "Filter by category"
+// @Param        sort      query  string  false  "Sort order: name (default), favorite, custom or online"
+// @Param        fields    query  string  false  "Comma-separated fields to return per device (e.g., id,name,online,status)"
+// @Param        X-TUYA-UID  header  string  false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Param        X-API-KEY   header  string  false  "API key used to validate the X-TUYA-UID override"
+// @Param        If-None-Match  header  string  false  "ETag of the list the client holds"
 // @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TuyaDevicesResponseDTO}
+// @Success      304  "Not Modified"
+// @Failure      400  {object}  dtos.StandardResponse
 // @Failure      500  {object}  dtos.StandardResponse
 // @Security     BearerAuth
 // @Router       /api/tuya/devices [get]
 func (c *TuyaGetAllDevicesController) GetAllDevices(ctx *gin.Context) {
 	accessToken := ctx.MustGet("access_token").(string)
 
-	uid := utils.AppConfig.TuyaUserID
-	if uid == "" {
-		utils.LogError("TUYA_USER_ID is not set in environment")
-		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
-			Status:  false,
-			Message: "Server configuration error: TUYA_USER_ID missing",
-			Data:    nil,
-		})
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
 		return
 	}
-	utils.LogDebug("Using TUYA_USER_ID from env: '%s'", uid)
 
 	// Parse optional query parameters
 	pageStr := ctx.Query("page")
@@ -79,20 +85,63 @@ func (c *TuyaGetAllDevicesController) GetAllDevices(ctx *gin.Context) {
 		}
 	}
 
-	devices, err := c.useCase.GetAllDevices(accessToken, uid, page, limit, category)
+	devices, err := c.useCase.GetAllDevicesPayload(ctx.Request.Context(), accessToken, uid, page, limit, category, visibleDevices(ctx, c.claimUC), ctx.Query("sort"))
 	if err != nil {
-		utils.LogError("Error fetching devices: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
-			Status:  false,
-			Message: err.Error(),
-			Data:    nil,
-		})
+		abortWithError(ctx, "GetAllDevices", err)
+		return
+	}
+
+	writeConditionalPayload(ctx, "Devices fetched successfully", devices)
+}
+
+// writeConditionalPayload sends a pre-marshaled payload as response data with a weak ETag derived from it,
+// or 304 Not Modified when the client's If-None-Match already matches, so polling clients skip unchanged bodies.
+// The ETag also covers the ?fields= selection and sensitive field redaction applied to the payload on its way
+// out, and Vary names the headers that decide the caller's scope and tenant, so caches never mix representations.
+//
+// param ctx The Gin context.
+// param message The response message.
+// param payload The marshaled response data.
+func writeConditionalPayload(ctx *gin.Context, message string, payload json.RawMessage) {
+	variant := fmt.Sprintf("fields=%s;redacted=%t", strings.Join(middlewares.ResponseFields(ctx), ","), middlewares.SensitiveFieldsRedacted(ctx))
+	etag := utils.PayloadETag(payload, variant)
+	ctx.Header("ETag", etag)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Writer.Header().Add("Vary", "Authorization, X-API-KEY, X-TUYA-UID")
+	if utils.ETagMatches(ctx.GetHeader("If-None-Match"), etag) {
+		ctx.Status(http.StatusNotModified)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, dtos.StandardResponse{
 		Status:  true,
-		Message: "Devices fetched successfully",
-		Data:    devices,
+		Message: message,
+		Data:    payload,
 	})
+}
+
+// resolveTuyaUID returns the Tuya user ID for the request: the allowlisted X-TUYA-UID override
+// if present, otherwise TUYA_USER_ID from config. It writes an error response when neither is set.
+//
+// param ctx The Gin context.
+// return string The resolved UID.
+// return bool False if the UID could not be resolved and a response was already written.
+func resolveTuyaUID(ctx *gin.Context) (string, bool) {
+	if uid := ctx.GetString("tuya_uid"); uid != "" {
+		utils.LogDebug("Using X-TUYA-UID from request: '%s'", uid)
+		return uid, true
+	}
+
+	uid := utils.GetConfig().TuyaUserID
+	if uid == "" {
+		utils.LogError("TUYA_USER_ID is not set in environment")
+		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Server configuration error: TUYA_USER_ID missing",
+			Data:    nil,
+		})
+		return "", false
+	}
+	utils.LogDebug("Using TUYA_USER_ID from env: '%s'", uid)
+	return uid, true
 }
\ No newline at end of file
@@ -10,27 +10,36 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// Force import for Swagger
+var _ = tuya_dtos.TuyaDeviceResponseDTO{}
+
 // TuyaGetDeviceByIDController handles get device by ID requests for Tuya
 type TuyaGetDeviceByIDController struct {
 	useCase *usecases.TuyaGetDeviceByIDUseCase
+	claimUC *usecases.DeviceClaimUseCase
 }
 
 // NewTuyaGetDeviceByIDController creates a new TuyaGetDeviceByIDController instance
-func NewTuyaGetDeviceByIDController(useCase *usecases.TuyaGetDeviceByIDUseCase) *TuyaGetDeviceByIDController {
+func NewTuyaGetDeviceByIDController(useCase *usecases.TuyaGetDeviceByIDUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaGetDeviceByIDController {
 	return &TuyaGetDeviceByIDController{
 		useCase: useCase,
+		claimUC: claimUC,
 	}
 }
 
 // GetDeviceByID handles GET /api/tuya/devices/:id endpoint
 // @Summary      Get Device by ID
-// @Description  Retrieves details of a specific device by its ID. Response includes last_commands field containing the last control commands sent to the device.
+// @Description  Retrieves details of a specific device by its ID. Response includes last_commands field containing the last control commands sent to the device. The response carries an ETag; sending it back in If-None-Match returns 304 Not Modified while the device is unchanged.
 // @Tags         02. Devices
 // @Accept       json
 // @Produce      json
 // @Param        id   path      string                 true  "Device ID"
+// @Param        fields    query  string  false  "Comma-separated fields to return per device (e.g., id,name,online,status)"
+// @Param        If-None-Match  header  string  false  "ETag of the device the client holds"
 // @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.TuyaDeviceResponseDTO}
+// @Success      304  "Not Modified"
 // @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
 // @Failure      500  {object}  dtos.StandardResponse
 // @Security     BearerAuth
 // @Router       /api/tuya/devices/{id} [get]
@@ -45,23 +54,23 @@ func (c *TuyaGetDeviceByIDController) GetDeviceByID(ctx *gin.Context) {
 		return
 	}
 
-	accessToken := ctx.MustGet("access_token").(string)
-	utils.LogDebug("GetDeviceByID: requesting device %s", deviceID)
-	device, err := c.useCase.GetDeviceByID(accessToken, deviceID)
-	if err != nil {
-		utils.LogError("GetDeviceByID failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+	if visible := visibleDevices(ctx, c.claimUC); visible != nil && !visible(deviceID) {
+		ctx.JSON(http.StatusNotFound, dtos.StandardResponse{
 			Status:  false,
-			Message: err.Error(),
+			Message: "device not found",
 			Data:    nil,
 		})
 		return
 	}
 
+	accessToken := ctx.MustGet("access_token").(string)
+	utils.LogDebug("GetDeviceByID: requesting device %s", deviceID)
+	device, err := c.useCase.GetDeviceByIDPayload(ctx.Request.Context(), accessToken, deviceID)
+	if err != nil {
+		abortWithError(ctx, "GetDeviceByID", err)
+		return
+	}
+
 	utils.LogDebug("GetDeviceByID success")
-	ctx.JSON(http.StatusOK, dtos.StandardResponse{
-		Status:  true,
-		Message: "Device fetched successfully",
-		Data:    tuya_dtos.TuyaDeviceResponseDTO{Device: *device},
-	})
+	writeConditionalPayload(ctx, "Device fetched successfully", device)
 }
\ No newline at end of file
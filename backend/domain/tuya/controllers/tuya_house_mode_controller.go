@@ -0,0 +1,74 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaHouseModeController handles the global house mode (home, away, sleep, holiday)
+type TuyaHouseModeController struct {
+	useCase *usecases.HouseModeUseCase
+}
+
+// NewTuyaHouseModeController creates a new TuyaHouseModeController instance
+func NewTuyaHouseModeController(useCase *usecases.HouseModeUseCase) *TuyaHouseModeController {
+	return &TuyaHouseModeController{
+		useCase: useCase,
+	}
+}
+
+// GetHouseMode handles GET /api/house-mode endpoint
+// @Summary      Get House Mode
+// @Description  Returns the current house mode and the sensor poll interval it applies.
+// @Tags         12. House Mode
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.HouseModeDTO}
+// @Security     BearerAuth
+// @Router       /api/house-mode [get]
+func (c *TuyaHouseModeController) GetHouseMode(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "House mode fetched successfully",
+		Data:    c.useCase.GetMode(),
+	})
+}
+
+// SetHouseMode handles PUT /api/house-mode endpoint
+// @Summary      Set House Mode
+// @Description  Switches the house mode. Automation rules with house_mode conditions are evaluated (e.g., an "away" rule turning off lights acts as an entry scene), the sensor poll interval follows HOUSE_MODE_POLL_INTERVALS, and a house_mode event is pushed to realtime subscribers.
+// @Tags         12. House Mode
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.SetHouseModeRequestDTO  true  "New mode"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.HouseModeDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/house-mode [put]
+func (c *TuyaHouseModeController) SetHouseMode(ctx *gin.Context) {
+	var req tuya_dtos.SetHouseModeRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	mode, err := c.useCase.SetMode(req.Mode)
+	if err != nil {
+		abortWithError(ctx, "SetHouseMode", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "House mode updated successfully",
+		Data:    mode,
+	})
+}
@@ -0,0 +1,68 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaIntentController handles typed commands from search-bar style control UIs
+type TuyaIntentController struct {
+	useCase *usecases.IntentUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaIntentController creates a new TuyaIntentController instance
+func NewTuyaIntentController(useCase *usecases.IntentUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaIntentController {
+	return &TuyaIntentController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// ExecuteIntent handles POST /api/intent endpoint
+// @Summary      Execute Typed Command
+// @Description  Runs a short command such as "turn off meeting room lights", "open the bedroom curtains" or "set desk lamp to 40%". Rooms, devices and named switch channels are matched by fuzzy name; lights, fans, curtains and plugs narrow the match. The response lists what was done per device. Set dry_run to preview the match without sending anything.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        X-TUYA-UID  header  string                      false  "Tuya user ID override (must be allowlisted for X-API-KEY)"
+// @Param        request     body    tuya_dtos.IntentRequestDTO  true   "Command text"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IntentResultDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/intent [post]
+func (c *TuyaIntentController) ExecuteIntent(ctx *gin.Context) {
+	uid, ok := resolveTuyaUID(ctx)
+	if !ok {
+		return
+	}
+
+	var req tuya_dtos.IntentRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	result, err := c.useCase.Execute(ctx.Request.Context(), accessToken, uid, req, visibleDevices(ctx, c.claimUC))
+	if err != nil {
+		abortWithError(ctx, "ExecuteIntent", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: result.Summary,
+		Data:    result,
+	})
+}
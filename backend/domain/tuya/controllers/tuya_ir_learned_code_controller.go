@@ -0,0 +1,143 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaIRLearnedCodeController handles raw IR codes learned by IR hubs and saved under names
+type TuyaIRLearnedCodeController struct {
+	useCase *usecases.IRLearnedCodeUseCase
+}
+
+// NewTuyaIRLearnedCodeController creates a new TuyaIRLearnedCodeController instance
+func NewTuyaIRLearnedCodeController(useCase *usecases.IRLearnedCodeUseCase) *TuyaIRLearnedCodeController {
+	return &TuyaIRLearnedCodeController{
+		useCase: useCase,
+	}
+}
+
+// ListCodes handles GET /api/tuya/infrareds/{id}/learned-codes endpoint
+// @Summary      List Learned IR Codes
+// @Description  Lists the raw IR codes saved by name on an IR hub.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Infrared Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.IRNamedCodeDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/learned-codes [get]
+func (c *TuyaIRLearnedCodeController) ListCodes(ctx *gin.Context) {
+	codes, err := c.useCase.ListCodes(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListCodes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Learned codes fetched successfully",
+		Data:    codes,
+	})
+}
+
+// SaveCode handles POST /api/tuya/infrareds/{id}/learned-codes endpoint
+// @Summary      Save Learned IR Code
+// @Description  Saves a raw IR code under a name. Start learning mode with POST /api/tuya/devices/{id}/ir/learning, press the button on the physical remote, then send {"name": "Fireplace On", "learning_time": <from the learning session>} to save the captured code (409 until the hub captured one), or send a known code directly in "code". A code with the same name, in any case, is replaced.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Infrared Device ID"
+// @Param        request  body      tuya_dtos.SaveIRNamedCodeRequestDTO  true  "Name and code or learning time"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRNamedCodeDTO}
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.IRNamedCodeDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      409  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/learned-codes [post]
+func (c *TuyaIRLearnedCodeController) SaveCode(ctx *gin.Context) {
+	var req tuya_dtos.SaveIRNamedCodeRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	code, created, err := c.useCase.SaveCode(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "SaveCode", err)
+		return
+	}
+
+	status := http.StatusOK
+	if created {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, dtos.StandardResponse{
+		Status:  true,
+		Message: "Learned code saved successfully",
+		Data:    code,
+	})
+}
+
+// DeleteCode handles DELETE /api/tuya/infrareds/{id}/learned-codes/{name} endpoint
+// @Summary      Delete Learned IR Code
+// @Description  Removes a saved code from an IR hub.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id    path      string  true  "Infrared Device ID"
+// @Param        name  path      string  true  "Code name"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/learned-codes/{name} [delete]
+func (c *TuyaIRLearnedCodeController) DeleteCode(ctx *gin.Context) {
+	if err := c.useCase.DeleteCode(ctx.Param("id"), ctx.Param("name")); err != nil {
+		abortWithError(ctx, "DeleteCode", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Learned code deleted successfully",
+		Data:    nil,
+	})
+}
+
+// SendCode handles POST /api/tuya/infrareds/{id}/learned-codes/{name}/send endpoint
+// @Summary      Send Learned IR Code
+// @Description  Replays a saved raw code through the IR hub.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id    path      string  true  "Infrared Device ID"
+// @Param        name  path      string  true  "Code name (any case)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SendIRNamedCodeResponseDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/learned-codes/{name}/send [post]
+func (c *TuyaIRLearnedCodeController) SendCode(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	result, err := c.useCase.SendCode(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("name"))
+	if err != nil {
+		abortWithError(ctx, "SendCode", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Learned code sent successfully",
+		Data:    result,
+	})
+}
@@ -0,0 +1,158 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.IRLearningSessionDTO{}
+
+// TuyaIRLearningController handles IR code learning requests
+type TuyaIRLearningController struct {
+	useCase *usecases.TuyaIRLearningUseCase
+}
+
+// NewTuyaIRLearningController creates a new TuyaIRLearningController instance
+func NewTuyaIRLearningController(useCase *usecases.TuyaIRLearningUseCase) *TuyaIRLearningController {
+	return &TuyaIRLearningController{
+		useCase: useCase,
+	}
+}
+
+// StartLearning handles POST /api/tuya/devices/{id}/ir/learning endpoint
+// @Summary      Start IR Learning
+// @Description  Puts an IR hub into learning mode. Point the physical remote at the hub and press a button, then poll the learned code with the returned learning_time.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Infrared Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRLearningSessionDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/ir/learning [post]
+func (c *TuyaIRLearningController) StartLearning(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	session, err := c.useCase.StartLearning(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "StartLearning", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR learning mode started",
+		Data:    session,
+	})
+}
+
+// StopLearning handles DELETE /api/tuya/devices/{id}/ir/learning endpoint
+// @Summary      Stop IR Learning
+// @Description  Takes an IR hub out of learning mode.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Infrared Device ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/ir/learning [delete]
+func (c *TuyaIRLearningController) StopLearning(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	if err := c.useCase.StopLearning(ctx.Request.Context(), accessToken, ctx.Param("id")); err != nil {
+		abortWithError(ctx, "StopLearning", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR learning mode stopped",
+		Data:    nil,
+	})
+}
+
+// GetLearnedCode handles GET /api/tuya/devices/{id}/ir/learning/code endpoint
+// @Summary      Get Learned IR Code
+// @Description  Polls for the code captured since learning mode started. learned is false until a button has been pressed.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id             path      string  true  "Infrared Device ID"
+// @Param        learning_time  query     int     true  "learning_time returned when learning started"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRLearnedCodeDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/ir/learning/code [get]
+func (c *TuyaIRLearningController) GetLearnedCode(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	learningTime, err := strconv.ParseInt(ctx.Query("learning_time"), 10, 64)
+	if err != nil || learningTime <= 0 {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: "learning_time query parameter is required",
+			Data:    nil,
+		})
+		return
+	}
+
+	code, err := c.useCase.GetLearnedCode(ctx.Request.Context(), accessToken, ctx.Param("id"), learningTime)
+	if err != nil {
+		abortWithError(ctx, "GetLearnedCode", err)
+		return
+	}
+
+	message := "No code learned yet"
+	if code.Learned {
+		message = "IR code learned successfully"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    code,
+	})
+}
+
+// SaveLearnedKey handles POST /api/tuya/devices/{id}/ir/keys endpoint
+// @Summary      Save Learned IR Key
+// @Description  Saves a learned code as a named key. The key is added to remote_id, or a new custom remote named remote_name is created.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                 true  "Infrared Device ID"
+// @Param        request  body      tuya_dtos.SaveIRLearnedKeyRequestDTO   true  "Learned key"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SaveIRLearnedKeyResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/ir/keys [post]
+func (c *TuyaIRLearningController) SaveLearnedKey(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.SaveIRLearnedKeyRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	saved, err := c.useCase.SaveLearnedKey(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "SaveLearnedKey", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR key saved successfully",
+		Data:    saved,
+	})
+}
@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaIRRemoteController handles IR remote discovery and key presses for TVs, fans, projectors and other remotes
+type TuyaIRRemoteController struct {
+	useCase *usecases.TuyaIRRemoteUseCase
+}
+
+// NewTuyaIRRemoteController creates a new TuyaIRRemoteController instance
+func NewTuyaIRRemoteController(useCase *usecases.TuyaIRRemoteUseCase) *TuyaIRRemoteController {
+	return &TuyaIRRemoteController{
+		useCase: useCase,
+	}
+}
+
+// ListRemotes handles GET /api/tuya/infrareds/{id}/remotes endpoint
+// @Summary      List IR Remotes
+// @Description  Lists the remotes (TV, fan, projector, AC, custom) configured on an IR hub.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Infrared Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRRemotesResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/remotes [get]
+func (c *TuyaIRRemoteController) ListRemotes(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	remotes, err := c.useCase.ListRemotes(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListRemotes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR remotes fetched successfully",
+		Data:    remotes,
+	})
+}
+
+// GetRemoteKeys handles GET /api/tuya/infrareds/{id}/remotes/{remote_id}/keys endpoint
+// @Summary      Get IR Remote Keys
+// @Description  Returns the keys of a remote. Use key or key_id from this list to send key presses.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id         path      string  true  "Infrared Device ID"
+// @Param        remote_id  path      string  true  "Remote ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRRemoteKeysResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/remotes/{remote_id}/keys [get]
+func (c *TuyaIRRemoteController) GetRemoteKeys(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	keys, err := c.useCase.GetRemoteKeys(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("remote_id"))
+	if err != nil {
+		abortWithError(ctx, "GetRemoteKeys", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR remote keys fetched successfully",
+		Data:    keys,
+	})
+}
+
+// SendKey handles POST /api/tuya/infrareds/{id}/remotes/{remote_id}/command endpoint
+// @Summary      Send IR Remote Key
+// @Description  Presses a key of a remote, identified by key (e.g., "power", "volume_up") or key_id.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id         path      string                               true  "Infrared Device ID"
+// @Param        remote_id  path      string                               true  "Remote ID"
+// @Param        request    body      tuya_dtos.IRRemoteCommandRequestDTO  true  "Key to press"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRRemoteCommandResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/remotes/{remote_id}/command [post]
+func (c *TuyaIRRemoteController) SendKey(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.IRRemoteCommandRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	sent, err := c.useCase.SendKey(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("remote_id"), req)
+	if err != nil {
+		abortWithError(ctx, "SendKey", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR key sent successfully",
+		Data:    sent,
+	})
+}
+
+// ListCategories handles GET /api/tuya/infrareds/{id}/categories endpoint
+// @Summary      List IR Remote Categories
+// @Description  Lists the remote categories (TV, set-top box, fan, AC, ...) the IR hub can control. First step of adding a remote: pick a category, then a brand, then a remote index.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Infrared Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRCategoriesResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/categories [get]
+func (c *TuyaIRRemoteController) ListCategories(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	categories, err := c.useCase.ListCategories(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "ListCategories", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR remote categories fetched successfully",
+		Data:    categories,
+	})
+}
+
+// ListBrands handles GET /api/tuya/infrareds/{id}/categories/{category_id}/brands endpoint
+// @Summary      List IR Remote Brands
+// @Description  Lists the appliance brands of a remote category.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id           path      string  true  "Infrared Device ID"
+// @Param        category_id  path      int     true  "Category ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRBrandsResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/categories/{category_id}/brands [get]
+func (c *TuyaIRRemoteController) ListBrands(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+	categoryID, ok := pathInt(ctx, "category_id")
+	if !ok {
+		return
+	}
+
+	brands, err := c.useCase.ListBrands(ctx.Request.Context(), accessToken, ctx.Param("id"), categoryID)
+	if err != nil {
+		abortWithError(ctx, "ListBrands", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR remote brands fetched successfully",
+		Data:    brands,
+	})
+}
+
+// ListRemoteIndexes handles GET /api/tuya/infrareds/{id}/categories/{category_id}/brands/{brand_id}/remote-indexes endpoint
+// @Summary      List IR Remote Indexes
+// @Description  Lists the code libraries of a brand. Models of one brand often use different libraries; try them in order until the appliance responds.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id           path      string  true  "Infrared Device ID"
+// @Param        category_id  path      int     true  "Category ID"
+// @Param        brand_id     path      int     true  "Brand ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.IRRemoteIndexesResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/categories/{category_id}/brands/{brand_id}/remote-indexes [get]
+func (c *TuyaIRRemoteController) ListRemoteIndexes(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+	categoryID, ok := pathInt(ctx, "category_id")
+	if !ok {
+		return
+	}
+	brandID, ok := pathInt(ctx, "brand_id")
+	if !ok {
+		return
+	}
+
+	indexes, err := c.useCase.ListRemoteIndexes(ctx.Request.Context(), accessToken, ctx.Param("id"), categoryID, brandID)
+	if err != nil {
+		abortWithError(ctx, "ListRemoteIndexes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR remote indexes fetched successfully",
+		Data:    indexes,
+	})
+}
+
+// AddRemote handles POST /api/tuya/infrareds/{id}/remotes endpoint
+// @Summary      Add IR Remote
+// @Description  Adds a virtual remote from a code library to the IR hub, without the Tuya Smart app (e.g., {"category_id": 2, "brand_id": 33, "remote_index": 1234, "remote_name": "Living Room TV"}). brand_name is looked up when omitted.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true  "Infrared Device ID"
+// @Param        request  body      tuya_dtos.AddIRRemoteRequestDTO  true  "Code library and remote name"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.IRRemoteDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/infrareds/{id}/remotes [post]
+func (c *TuyaIRRemoteController) AddRemote(ctx *gin.Context) {
+	var req tuya_dtos.AddIRRemoteRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	accessToken := ctx.MustGet("access_token").(string)
+	remote, err := c.useCase.AddRemote(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "AddRemote", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "IR remote added successfully",
+		Data:    remote,
+	})
+}
+
+// pathInt parses a numeric path parameter, answering 400 when it is not a number.
+func pathInt(ctx *gin.Context, name string) (int, bool) {
+	parsed, err := strconv.Atoi(ctx.Param(name))
+	if err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: name + " must be a number",
+			Data:    nil,
+		})
+		return 0, false
+	}
+	return parsed, true
+}
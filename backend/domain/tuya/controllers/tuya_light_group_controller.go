@@ -0,0 +1,276 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaLightGroupController handles light groups and their colour scene presets
+type TuyaLightGroupController struct {
+	useCase *usecases.LightGroupUseCase
+}
+
+// NewTuyaLightGroupController creates a new TuyaLightGroupController instance
+func NewTuyaLightGroupController(useCase *usecases.LightGroupUseCase) *TuyaLightGroupController {
+	return &TuyaLightGroupController{
+		useCase: useCase,
+	}
+}
+
+// ListGroups handles GET /api/tuya/light-groups endpoint
+// @Summary      List Light Groups
+// @Description  Lists all light groups with their built-in and custom presets.
+// @Tags         03. Device Control
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.LightGroupDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups [get]
+func (c *TuyaLightGroupController) ListGroups(ctx *gin.Context) {
+	groups, err := c.useCase.ListGroups()
+	if err != nil {
+		abortWithError(ctx, "ListGroups", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light groups fetched successfully",
+		Data:    groups,
+	})
+}
+
+// CreateGroup handles POST /api/tuya/light-groups endpoint
+// @Summary      Create Light Group
+// @Description  Creates a light group from light devices (dj, dd, xdd, fwd, dc, tgq, gyd). API keys restricted to devices or rooms can only group their granted devices.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.CreateLightGroupRequestDTO  true  "Group name and devices"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.LightGroupDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups [post]
+func (c *TuyaLightGroupController) CreateGroup(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.CreateLightGroupRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	group, err := c.useCase.CreateGroup(ctx.Request.Context(), accessToken, req, visibleDevices(ctx, nil))
+	if err != nil {
+		abortWithError(ctx, "CreateGroup", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light group created successfully",
+		Data:    group,
+	})
+}
+
+// GetGroup handles GET /api/tuya/light-groups/{id} endpoint
+// @Summary      Get Light Group
+// @Description  Returns a light group with its built-in and custom presets.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Light group ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.LightGroupDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups/{id} [get]
+func (c *TuyaLightGroupController) GetGroup(ctx *gin.Context) {
+	group, err := c.useCase.GetGroup(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetGroup", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light group fetched successfully",
+		Data:    group,
+	})
+}
+
+// DeleteGroup handles DELETE /api/tuya/light-groups/{id} endpoint
+// @Summary      Delete Light Group
+// @Description  Deletes a light group and its custom presets. The lights themselves are not changed.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Light group ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups/{id} [delete]
+func (c *TuyaLightGroupController) DeleteGroup(ctx *gin.Context) {
+	if err := c.useCase.DeleteGroup(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "DeleteGroup", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light group deleted successfully",
+		Data:    nil,
+	})
+}
+
+// SavePreset handles PUT /api/tuya/light-groups/{id}/presets/{preset} endpoint
+// @Summary      Save Light Preset
+// @Description  Creates or replaces a custom preset. Brightness and temperature are percentages (temperature 0 = warmest); a colour switches lights to colour mode. A custom preset named like a built-in one (warm_evening, focus, party) overrides it.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                               true  "Light group ID"
+// @Param        preset   path      string                               true  "Preset name"
+// @Param        request  body      tuya_dtos.SaveLightPresetRequestDTO  true  "Preset settings"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.LightGroupDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups/{id}/presets/{preset} [put]
+func (c *TuyaLightGroupController) SavePreset(ctx *gin.Context) {
+	var req tuya_dtos.SaveLightPresetRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	group, err := c.useCase.SavePreset(ctx.Param("id"), ctx.Param("preset"), req)
+	if err != nil {
+		abortWithError(ctx, "SavePreset", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light preset saved successfully",
+		Data:    group,
+	})
+}
+
+// CapturePreset handles POST /api/tuya/light-groups/{id}/presets/{preset}/capture endpoint
+// @Summary      Capture Light Preset
+// @Description  Saves the current brightness and colour of every light in the group as a custom preset. API keys restricted to devices or rooms need access to every light in the group.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id      path      string  true  "Light group ID"
+// @Param        preset  path      string  true  "Preset name"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.LightGroupDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups/{id}/presets/{preset}/capture [post]
+func (c *TuyaLightGroupController) CapturePreset(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	group, err := c.useCase.CapturePreset(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("preset"), visibleDevices(ctx, nil))
+	if err != nil {
+		abortWithError(ctx, "CapturePreset", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light preset captured successfully",
+		Data:    group,
+	})
+}
+
+// DeletePreset handles DELETE /api/tuya/light-groups/{id}/presets/{preset} endpoint
+// @Summary      Delete Light Preset
+// @Description  Deletes a custom preset. Built-in presets cannot be deleted.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id      path      string  true  "Light group ID"
+// @Param        preset  path      string  true  "Preset name"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups/{id}/presets/{preset} [delete]
+func (c *TuyaLightGroupController) DeletePreset(ctx *gin.Context) {
+	if err := c.useCase.DeletePreset(ctx.Param("id"), ctx.Param("preset")); err != nil {
+		abortWithError(ctx, "DeletePreset", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Light preset deleted successfully",
+		Data:    nil,
+	})
+}
+
+// ApplyPreset handles POST /api/tuya/light-groups/{id}/presets/{preset}/apply endpoint
+// @Summary      Apply Light Preset
+// @Description  Applies a preset to every light in the group. Lights exposing a transition DP fade over transition_ms; others switch immediately. The result reports the outcome per light. API keys restricted to devices or rooms need access to every light in the group.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                true   "Light group ID"
+// @Param        preset   path      string                                true   "Preset name"
+// @Param        request  body      tuya_dtos.ApplyLightPresetRequestDTO  false  "Transition override"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.ApplyLightPresetResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/light-groups/{id}/presets/{preset}/apply [post]
+func (c *TuyaLightGroupController) ApplyPreset(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.ApplyLightPresetRequestDTO
+	if ctx.Request.ContentLength > 0 {
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+				Status:  false,
+				Message: err.Error(),
+				Data:    nil,
+			})
+			return
+		}
+	}
+
+	result, err := c.useCase.ApplyPreset(ctx.Request.Context(), accessToken, ctx.Param("id"), ctx.Param("preset"), req.TransitionMs, visibleDevices(ctx, nil))
+	if err != nil {
+		abortWithError(ctx, "ApplyPreset", err)
+		return
+	}
+
+	message := "Light preset applied successfully"
+	if !result.Success {
+		message = "Light preset applied with errors"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  result.Success,
+		Message: message,
+		Data:    result,
+	})
+}
@@ -0,0 +1,199 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.LocalSceneRunResultDTO{}
+
+// TuyaLocalSceneController handles whole-house scenes stored by Teralux
+type TuyaLocalSceneController struct {
+	useCase *usecases.LocalSceneUseCase
+}
+
+// NewTuyaLocalSceneController creates a new TuyaLocalSceneController instance
+func NewTuyaLocalSceneController(useCase *usecases.LocalSceneUseCase) *TuyaLocalSceneController {
+	return &TuyaLocalSceneController{
+		useCase: useCase,
+	}
+}
+
+// ListScenes handles GET /api/tuya/scenes endpoint
+// @Summary      List Scenes
+// @Description  Lists the whole-house scenes stored by Teralux. Scenes configured in the Smart Life app are listed per home under /api/tuya/homes.
+// @Tags         21. Scenes
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.LocalSceneDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scenes [get]
+func (c *TuyaLocalSceneController) ListScenes(ctx *gin.Context) {
+	scenes, err := c.useCase.ListScenes()
+	if err != nil {
+		abortWithError(ctx, "ListScenes", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scenes fetched successfully",
+		Data:    scenes,
+	})
+}
+
+// CreateScene handles POST /api/tuya/scenes endpoint
+// @Summary      Create Scene
+// @Description  Stores a whole-house scene: ordered steps, each sending commands or a device preset to one device, optionally delay_seconds after the previous step. With rollback_on_failure, a failed step stops the scene and the steps already done are reverted to the values their devices reported before; otherwise the remaining steps still run. Scenes run with the server-managed token, so an API key of control scope that may access every device of the scene is required.
+// @Tags         21. Scenes
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.SaveLocalSceneRequestDTO  true  "Scene name, rollback option and steps"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.LocalSceneDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scenes [post]
+func (c *TuyaLocalSceneController) CreateScene(ctx *gin.Context) {
+	var req tuya_dtos.SaveLocalSceneRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	scene, err := c.useCase.CreateScene(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, "CreateScene", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene created successfully",
+		Data:    scene,
+	})
+}
+
+// GetScene handles GET /api/tuya/scenes/{id} endpoint
+// @Summary      Get Scene
+// @Description  Returns a whole-house scene with its steps.
+// @Tags         21. Scenes
+// @Produce      json
+// @Param        id   path      string  true  "Scene ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.LocalSceneDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scenes/{id} [get]
+func (c *TuyaLocalSceneController) GetScene(ctx *gin.Context) {
+	scene, err := c.useCase.GetScene(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetScene", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene fetched successfully",
+		Data:    scene,
+	})
+}
+
+// UpdateScene handles PUT /api/tuya/scenes/{id} endpoint
+// @Summary      Update Scene
+// @Description  Replaces the name, rollback option and steps of a scene. Runs already started keep the old steps. Requires an API key of control scope that may access every device of the scene.
+// @Tags         21. Scenes
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                              true  "Scene ID"
+// @Param        request  body      tuya_dtos.SaveLocalSceneRequestDTO  true  "Scene name, rollback option and steps"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.LocalSceneDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scenes/{id} [put]
+func (c *TuyaLocalSceneController) UpdateScene(ctx *gin.Context) {
+	var req tuya_dtos.SaveLocalSceneRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	scene, err := c.useCase.UpdateScene(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "UpdateScene", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene updated successfully",
+		Data:    scene,
+	})
+}
+
+// DeleteScene handles DELETE /api/tuya/scenes/{id} endpoint
+// @Summary      Delete Scene
+// @Description  Removes a whole-house scene. Runs already started are not stopped.
+// @Tags         21. Scenes
+// @Produce      json
+// @Param        id   path      string  true  "Scene ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scenes/{id} [delete]
+func (c *TuyaLocalSceneController) DeleteScene(ctx *gin.Context) {
+	if err := c.useCase.DeleteScene(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "DeleteScene", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene deleted successfully",
+		Data:    nil,
+	})
+}
+
+// RunScene handles POST /api/tuya/scenes/{id}/run endpoint
+// @Summary      Run Scene
+// @Description  Runs a whole-house scene as a background job on behalf of the caller, whose API key needs control scope and access to every device of the scene; the key is checked again before each step. Poll GET /api/jobs/{job_id} for progress and the per-step result, or cancel it with POST /api/jobs/{job_id}/cancel.
+// @Tags         21. Scenes
+// @Produce      json
+// @Param        id   path      string  true  "Scene ID"
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.LocalSceneRunStartedDTO}
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scenes/{id}/run [post]
+func (c *TuyaLocalSceneController) RunScene(ctx *gin.Context) {
+	run, err := c.useCase.RunScene(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "RunScene", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene started",
+		Data:    run,
+	})
+}
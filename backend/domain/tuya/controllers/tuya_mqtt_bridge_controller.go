@@ -0,0 +1,64 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.MQTTBridgeStatusDTO{}
+
+// TuyaMQTTBridgeController reports and controls the Home Assistant MQTT bridge.
+type TuyaMQTTBridgeController struct {
+	useCase *usecases.MQTTBridgeUseCase
+}
+
+// NewTuyaMQTTBridgeController creates a new TuyaMQTTBridgeController instance.
+//
+// param useCase The MQTTBridgeUseCase publishing devices to the broker.
+// return *TuyaMQTTBridgeController A pointer to the initialized controller.
+func NewTuyaMQTTBridgeController(useCase *usecases.MQTTBridgeUseCase) *TuyaMQTTBridgeController {
+	return &TuyaMQTTBridgeController{useCase: useCase}
+}
+
+// GetStatus handles GET /api/admin/mqtt-bridge endpoint
+// @Summary      Get MQTT Bridge Status
+// @Description  Reports whether the Home Assistant MQTT bridge is enabled (MQTT_BROKER) and connected, how many devices and entities it published, and the latest error.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.MQTTBridgeStatusDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/mqtt-bridge [get]
+func (c *TuyaMQTTBridgeController) GetStatus(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "MQTT bridge status fetched successfully",
+		Data:    c.useCase.GetStatus(),
+	})
+}
+
+// Sync handles POST /api/admin/mqtt-bridge/sync endpoint
+// @Summary      Republish Devices to MQTT
+// @Description  Republishes the Home Assistant discovery configs and states in the background, e.g. after devices were added or renamed. Entities of removed devices are deleted from Home Assistant.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      202  {object}  dtos.StandardResponse
+// @Failure      400  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/mqtt-bridge/sync [post]
+func (c *TuyaMQTTBridgeController) Sync(ctx *gin.Context) {
+	if err := c.useCase.RequestSync(); err != nil {
+		abortWithError(ctx, "Sync", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "MQTT republish started",
+		Data:    nil,
+	})
+}
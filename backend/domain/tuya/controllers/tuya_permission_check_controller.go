@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.PermissionReportDTO{}
+
+// TuyaPermissionCheckController reports which Tuya cloud project permissions are missing.
+type TuyaPermissionCheckController struct {
+	useCase *usecases.TuyaPermissionCheckUseCase
+}
+
+// NewTuyaPermissionCheckController creates a new TuyaPermissionCheckController instance.
+//
+// param useCase The TuyaPermissionCheckUseCase probing the Tuya API families.
+// return *TuyaPermissionCheckController A pointer to the initialized controller.
+func NewTuyaPermissionCheckController(useCase *usecases.TuyaPermissionCheckUseCase) *TuyaPermissionCheckController {
+	return &TuyaPermissionCheckController{useCase: useCase}
+}
+
+// GetPermissions handles GET /api/admin/permissions endpoint
+// @Summary      Get Tuya Permission Report
+// @Description  Reports whether the cloud project can call each Tuya API family the backend uses (token, device management, device status, IR control), with a hint for every missing subscription. Returns the latest periodic check unless refresh=true.
+// @Tags         08. Admin
+// @Produce      json
+// @Param        refresh  query     bool  false  "Run the check now instead of returning the latest report"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.PermissionReportDTO}
+// @Security     ApiKeyAuth
+// @Router       /api/admin/permissions [get]
+func (c *TuyaPermissionCheckController) GetPermissions(ctx *gin.Context) {
+	var report *tuya_dtos.PermissionReportDTO
+	if ctx.Query("refresh") == "true" {
+		report = c.useCase.Check(ctx.Request.Context())
+	} else {
+		report = c.useCase.GetReport(ctx.Request.Context())
+	}
+
+	message := "All Tuya API permissions are in place"
+	if !report.Healthy {
+		message = "Some Tuya API permissions are missing"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    report,
+	})
+}
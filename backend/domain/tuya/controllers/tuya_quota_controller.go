@@ -0,0 +1,50 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Force import for Swagger
+var _ = tuya_dtos.QuotaUsageDTO{}
+
+// TuyaQuotaController reports Tuya API quota consumption.
+type TuyaQuotaController struct {
+	useCase *usecases.TuyaQuotaUseCase
+}
+
+// NewTuyaQuotaController creates a new TuyaQuotaController instance.
+//
+// param useCase The TuyaQuotaUseCase counting upstream calls.
+// return *TuyaQuotaController A pointer to the initialized controller.
+func NewTuyaQuotaController(useCase *usecases.TuyaQuotaUseCase) *TuyaQuotaController {
+	return &TuyaQuotaController{useCase: useCase}
+}
+
+// GetQuota handles GET /api/admin/quota endpoint
+// @Summary      Get Tuya API Quota Usage
+// @Description  Reports the Tuya API calls of a UTC day per endpoint, with the projection for the whole day and the status against TUYA_QUOTA_DAILY_BUDGET and TUYA_QUOTA_ENDPOINT_BUDGETS (ok, warning or exceeded).
+// @Tags         08. Admin
+// @Produce      json
+// @Param        date  query     string  false  "UTC day as YYYY-MM-DD (default: today)"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.QuotaUsageDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/quota [get]
+func (c *TuyaQuotaController) GetQuota(ctx *gin.Context) {
+	usage, err := c.useCase.GetUsage(ctx.Query("date"))
+	if err != nil {
+		abortWithError(ctx, "GetQuota", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Quota usage fetched successfully",
+		Data:    usage,
+	})
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaRolloutController handles staged rollouts of commands across many devices
+type TuyaRolloutController struct {
+	useCase *usecases.TuyaRolloutUseCase
+}
+
+// NewTuyaRolloutController creates a new TuyaRolloutController instance
+func NewTuyaRolloutController(useCase *usecases.TuyaRolloutUseCase) *TuyaRolloutController {
+	return &TuyaRolloutController{
+		useCase: useCase,
+	}
+}
+
+// StartRollout handles POST /api/tuya/rollouts endpoint
+// @Summary      Start Staged Rollout
+// @Description  Sends the same commands to many devices in waves, e.g. power cycling 100 plugs. Requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access every device, since the commands are sent with the server-managed token; the grants are checked again before each device. The first wave (canary_size devices, default wave_size) goes out first; each following wave waits wave_delay_seconds. The rollout aborts when the share of failed devices so far exceeds max_error_rate (default 0.2) and the remaining devices are skipped. The rollout runs as a background job: poll GET /api/jobs/{job_id} for progress and the per-device result, or cancel it with POST /api/jobs/{job_id}/cancel.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.StartRolloutRequestDTO  true  "Devices, commands and wave settings"
+// @Success      202  {object}  dtos.StandardResponse{data=tuya_dtos.RolloutStartedDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/rollouts [post]
+func (c *TuyaRolloutController) StartRollout(ctx *gin.Context) {
+	var req tuya_dtos.StartRolloutRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	rollout, err := c.useCase.StartRollout(ctx.Request.Context(), req)
+	if err != nil {
+		abortWithError(ctx, "StartRollout", err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, dtos.StandardResponse{
+		Status:  true,
+		Message: "Rollout started",
+		Data:    rollout,
+	})
+}
+
+// Force import for Swagger
+var _ = tuya_dtos.RolloutResultDTO{}
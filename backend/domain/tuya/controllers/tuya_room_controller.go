@@ -0,0 +1,219 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaRoomController handles rooms (named device groups) and room-wide commands
+type TuyaRoomController struct {
+	useCase *usecases.RoomUseCase
+	claimUC *usecases.DeviceClaimUseCase
+}
+
+// NewTuyaRoomController creates a new TuyaRoomController instance
+func NewTuyaRoomController(useCase *usecases.RoomUseCase, claimUC *usecases.DeviceClaimUseCase) *TuyaRoomController {
+	return &TuyaRoomController{
+		useCase: useCase,
+		claimUC: claimUC,
+	}
+}
+
+// ListRooms handles GET /api/rooms endpoint
+// @Summary      List Rooms
+// @Description  Lists all rooms with the IDs of their devices, ordered by name. With DEVICE_CLAIMS_ENABLED, tenants only see rooms containing their devices.
+// @Tags         10. Rooms
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.RoomDTO}
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/rooms [get]
+func (c *TuyaRoomController) ListRooms(ctx *gin.Context) {
+	rooms, err := c.useCase.ListRooms()
+	if err != nil {
+		abortWithError(ctx, "ListRooms", err)
+		return
+	}
+	rooms = usecases.FilterRooms(rooms, visibleDevices(ctx, c.claimUC))
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Rooms fetched successfully",
+		Data:    rooms,
+	})
+}
+
+// CreateRoom handles POST /api/rooms endpoint
+// @Summary      Create Room
+// @Description  Creates a named room, optionally assigning devices to it. A device may belong to several rooms.
+// @Tags         10. Rooms
+// @Accept       json
+// @Produce      json
+// @Param        request  body      tuya_dtos.CreateRoomRequestDTO  true  "Room name and devices"
+// @Success      201  {object}  dtos.StandardResponse{data=tuya_dtos.RoomDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/rooms [post]
+func (c *TuyaRoomController) CreateRoom(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.CreateRoomRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	room, err := c.useCase.CreateRoom(ctx.Request.Context(), accessToken, req)
+	if err != nil {
+		abortWithError(ctx, "CreateRoom", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Room created successfully",
+		Data:    room,
+	})
+}
+
+// GetRoom handles GET /api/rooms/{id} endpoint
+// @Summary      Get Room
+// @Description  Returns a room with the IDs of its devices.
+// @Tags         10. Rooms
+// @Produce      json
+// @Param        id   path      string  true  "Room ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.RoomDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/rooms/{id} [get]
+func (c *TuyaRoomController) GetRoom(ctx *gin.Context) {
+	room, err := c.useCase.GetRoom(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetRoom", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Room fetched successfully",
+		Data:    room,
+	})
+}
+
+// UpdateRoom handles PUT /api/rooms/{id} endpoint
+// @Summary      Update Room
+// @Description  Renames a room and/or replaces its devices. Omitted fields are left unchanged; an empty device list removes all devices.
+// @Tags         10. Rooms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                          true  "Room ID"
+// @Param        request  body      tuya_dtos.UpdateRoomRequestDTO  true  "New name and/or devices"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.RoomDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/rooms/{id} [put]
+func (c *TuyaRoomController) UpdateRoom(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.UpdateRoomRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	room, err := c.useCase.UpdateRoom(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "UpdateRoom", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Room updated successfully",
+		Data:    room,
+	})
+}
+
+// DeleteRoom handles DELETE /api/rooms/{id} endpoint
+// @Summary      Delete Room
+// @Description  Deletes a room. The devices themselves are not changed.
+// @Tags         10. Rooms
+// @Produce      json
+// @Param        id   path      string  true  "Room ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/rooms/{id} [delete]
+func (c *TuyaRoomController) DeleteRoom(ctx *gin.Context) {
+	if err := c.useCase.DeleteRoom(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "DeleteRoom", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Room deleted successfully",
+		Data:    nil,
+	})
+}
+
+// SendCommands handles POST /api/rooms/{id}/commands endpoint
+// @Summary      Control Room
+// @Description  Sends the same commands to every device in the room. A failure on one device does not stop the others; the response reports the outcome per device.
+// @Tags         10. Rooms
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                           true  "Room ID"
+// @Param        request  body      tuya_dtos.RoomCommandRequestDTO  true  "Commands to send"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.RoomCommandResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      503  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/rooms/{id}/commands [post]
+func (c *TuyaRoomController) SendCommands(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.RoomCommandRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.SendCommands(ctx.Request.Context(), accessToken, ctx.Param("id"), req.Commands)
+	if err != nil {
+		abortWithError(ctx, "SendCommands", err)
+		return
+	}
+
+	message := "Room commands sent successfully"
+	if !result.Success {
+		message = "Room commands failed on some devices"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  result.Success,
+		Message: message,
+		Data:    result,
+	})
+}
@@ -0,0 +1,128 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaSceneSwitchController handles scene switch binding and event requests
+type TuyaSceneSwitchController struct {
+	useCase *usecases.SceneSwitchUseCase
+}
+
+// NewTuyaSceneSwitchController creates a new TuyaSceneSwitchController instance
+func NewTuyaSceneSwitchController(useCase *usecases.SceneSwitchUseCase) *TuyaSceneSwitchController {
+	return &TuyaSceneSwitchController{
+		useCase: useCase,
+	}
+}
+
+// GetBindings handles GET /api/tuya/scene-switches/{id}/bindings endpoint
+// @Summary      Get Scene Switch Bindings
+// @Description  Lists the actions bound to each button and press type of a wireless scene switch (wxkg).
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Scene Switch Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SceneSwitchBindingsResponseDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scene-switches/{id}/bindings [get]
+func (c *TuyaSceneSwitchController) GetBindings(ctx *gin.Context) {
+	bindings, err := c.useCase.GetBindings(ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "GetBindings", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene switch bindings fetched successfully",
+		Data:    bindings,
+	})
+}
+
+// SetBindings handles PUT /api/tuya/scene-switches/{id}/bindings endpoint
+// @Summary      Set Scene Switch Bindings
+// @Description  Replaces the bindings of a wireless scene switch. Each button/press type (single_click, double_click, long_press) maps to device commands, a scene or an automation. Requires an API key (X-API-KEY, or the key the session was created with) of control scope that may access the switch and every commanded device; button presses run the bound actions with the permissions of that key.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                   true  "Scene Switch Device ID"
+// @Param        request  body      tuya_dtos.SceneSwitchBindingsRequestDTO  true  "Bindings"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SceneSwitchBindingsResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scene-switches/{id}/bindings [put]
+func (c *TuyaSceneSwitchController) SetBindings(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.SceneSwitchBindingsRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	bindings, err := c.useCase.SetBindings(ctx.Request.Context(), accessToken, ctx.Param("id"), req.Bindings)
+	if err != nil {
+		abortWithError(ctx, "SetBindings", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Scene switch bindings saved successfully",
+		Data:    bindings,
+	})
+}
+
+// HandleEvent handles POST /api/tuya/scene-switches/{id}/events endpoint
+// @Summary      Report Scene Switch Event
+// @Description  Feeds a button event from a scene switch (e.g., code switch1_value, value single_click) into the binding pipeline and runs the bound action. Requires an API key of control scope that may access the switch.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                         true  "Scene Switch Device ID"
+// @Param        request  body      tuya_dtos.SceneSwitchEventDTO  true  "Button event"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SceneSwitchEventResultDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/scene-switches/{id}/events [post]
+func (c *TuyaSceneSwitchController) HandleEvent(ctx *gin.Context) {
+	var req tuya_dtos.SceneSwitchEventDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	result, err := c.useCase.ReportEvent(ctx.Request.Context(), ctx.Param("id"), req.Code, req.Value)
+	if err != nil {
+		abortWithError(ctx, "HandleEvent", err)
+		return
+	}
+
+	message := "No binding matched the event"
+	if result.Matched {
+		message = "Scene switch action executed"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: message,
+		Data:    result,
+	})
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"net/http"
+	"strconv"
 	"teralux_app/domain/common/dtos"
 	"teralux_app/domain/tuya/usecases"
 	"teralux_app/domain/common/utils"
@@ -48,14 +49,9 @@ func (c *TuyaSensorController) GetSensorData(ctx *gin.Context) {
 	
 	utils.LogDebug("GetSensorData: requesting for device %s", deviceID)
 
-	data, err := c.useCase.GetSensorData(accessToken, deviceID)
+	data, err := c.useCase.GetSensorData(ctx.Request.Context(), accessToken, deviceID)
 	if err != nil {
-		utils.LogError("GetSensorData failed: %v", err)
-		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
-			Status:  false,
-			Message: err.Error(),
-			Data:    nil,
-		})
+		abortWithError(ctx, "GetSensorData", err)
 		return
 	}
 
@@ -64,4 +60,103 @@ func (c *TuyaSensorController) GetSensorData(ctx *gin.Context) {
 		Message: "Sensor data fetched successfully",
 		Data:    data,
 	})
+}
+
+// GetSensorHistory handles GET /api/tuya/devices/:id/sensor/history endpoint
+// @Summary      Get Sensor History
+// @Description  Returns temperature, humidity and battery readings recorded by the background sampler, aggregated per interval (min, max, average, last). Sensors are enrolled on their first read through GET /api/tuya/devices/{id}/sensor. Data older than the local archive retention is only available in object storage.
+// @Tags         04. Device Sensor
+// @Produce      json
+// @Param        id        path      string  true   "Device ID"
+// @Param        from      query     int     false  "Start of the range (Unix seconds, default 24 hours before to)"
+// @Param        to        query     int     false  "End of the range (Unix seconds, default now)"
+// @Param        interval  query     string  false  "Aggregation interval in whole hours (e.g., 1h, 6h, 24h; default 1h)"
+// @Param        tz        query     string  false  "IANA time zone whose midnight intervals are aligned to (e.g., Asia/Jakarta; default TIMEZONE)"
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.SensorHistoryDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/sensor/history [get]
+func (c *TuyaSensorController) GetSensorHistory(ctx *gin.Context) {
+	var from, to int64
+	for name, target := range map[string]*int64{"from": &from, "to": &to} {
+		if value := ctx.Query(name); value != "" {
+			parsed, err := strconv.ParseInt(value, 10, 64)
+			if err != nil || parsed < 0 {
+				ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+					Status:  false,
+					Message: name + " must be a Unix timestamp in seconds",
+					Data:    nil,
+				})
+				return
+			}
+			*target = parsed
+		}
+	}
+
+	history, err := c.useCase.GetSensorHistory(ctx.Param("id"), from, to, ctx.Query("interval"), ctx.Query("tz"))
+	if err != nil {
+		abortWithError(ctx, "GetSensorHistory", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Sensor history fetched successfully",
+		Data:    history,
+	})
+}
+
+// GetSensorChart handles GET /api/tuya/devices/:id/sensor/chart endpoint
+// @Summary      Get Sensor Chart
+// @Description  Renders the recorded readings of a sensor as a chart image for embedding in e-mails or Telegram alerts. The line shows the average per interval and the shaded band the minimum and maximum; the interval widens with the range (1h for a day, 6h for a month). Returns the image itself, or a JSON error.
+// @Tags         04. Device Sensor
+// @Produce      png
+// @Produce      image/svg+xml
+// @Param        id      path      string  true   "Device ID"
+// @Param        metric  query     string  false  "temperature (default), humidity or battery"
+// @Param        range   query     string  false  "How far back the chart reaches in hours or days (e.g., 24h, 7d; default 24h, max 90d)"
+// @Param        format  query     string  false  "png (default) or svg"
+// @Param        tz      query     string  false  "IANA time zone of the time labels (e.g., Asia/Jakarta; default TIMEZONE)"
+// @Success      200  {file}    file
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/sensor/chart [get]
+func (c *TuyaSensorController) GetSensorChart(ctx *gin.Context) {
+	chart, err := c.useCase.GetSensorChart(ctx.Param("id"), ctx.Query("metric"), ctx.Query("range"), ctx.Query("format"), ctx.Query("tz"))
+	if err != nil {
+		abortWithError(ctx, "GetSensorChart", err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Data(http.StatusOK, chart.ContentType, chart.Image)
+}
+
+// TestAlarm handles POST /api/tuya/devices/:id/alarm/test endpoint
+// @Summary      Test Alarm
+// @Description  Publishes a simulated high-priority alarm event for a water leak (sj) or smoke (ywbj) sensor so clients can verify alarm handling. The device itself is not triggered.
+// @Tags         04. Device Sensor
+// @Produce      json
+// @Param        id   path      string                 true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse{data=dtos.AlarmTestResponseDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/devices/{id}/alarm/test [post]
+func (c *TuyaSensorController) TestAlarm(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	result, err := c.useCase.TestAlarm(ctx.Request.Context(), accessToken, ctx.Param("id"))
+	if err != nil {
+		abortWithError(ctx, "TestAlarm", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Simulated alarm published",
+		Data:    result,
+	})
 }
\ No newline at end of file
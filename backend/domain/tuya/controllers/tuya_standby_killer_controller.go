@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaStandbyKillerController handles standby killer rules for metered plugs
+type TuyaStandbyKillerController struct {
+	useCase *usecases.StandbyKillerUseCase
+}
+
+// NewTuyaStandbyKillerController creates a new TuyaStandbyKillerController instance
+func NewTuyaStandbyKillerController(useCase *usecases.StandbyKillerUseCase) *TuyaStandbyKillerController {
+	return &TuyaStandbyKillerController{
+		useCase: useCase,
+	}
+}
+
+// ListRules handles GET /api/tuya/standby-killer endpoint
+// @Summary      List Standby Killer Rules
+// @Description  Lists the standby killer rules of all metered plugs with their last power reading and timer state.
+// @Tags         03. Device Control
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]tuya_dtos.StandbyKillerRuleDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/standby-killer [get]
+func (c *TuyaStandbyKillerController) ListRules(ctx *gin.Context) {
+	rules, err := c.useCase.ListRules()
+	if err != nil {
+		abortWithError(ctx, "ListRules", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Standby killer rules fetched successfully",
+		Data:    rules,
+	})
+}
+
+// SetRule handles PUT /api/tuya/standby-killer/{id} endpoint
+// @Summary      Configure Standby Killer
+// @Description  Switches a metered plug off when its power draw stays below threshold_watts for duration_minutes. The device must report cur_power.
+// @Tags         03. Device Control
+// @Accept       json
+// @Produce      json
+// @Param        id       path      string                                 true  "Device ID"
+// @Param        request  body      tuya_dtos.StandbyKillerRuleRequestDTO  true  "Rule settings"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.StandbyKillerRuleDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/standby-killer/{id} [put]
+func (c *TuyaStandbyKillerController) SetRule(ctx *gin.Context) {
+	accessToken := ctx.MustGet("access_token").(string)
+
+	var req tuya_dtos.StandbyKillerRuleRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	rule, err := c.useCase.SetRule(ctx.Request.Context(), accessToken, ctx.Param("id"), req)
+	if err != nil {
+		abortWithError(ctx, "SetRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Standby killer rule saved successfully",
+		Data:    rule,
+	})
+}
+
+// DeleteRule handles DELETE /api/tuya/standby-killer/{id} endpoint
+// @Summary      Delete Standby Killer
+// @Description  Removes the standby killer rule of a plug.
+// @Tags         03. Device Control
+// @Produce      json
+// @Param        id   path      string  true  "Device ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     BearerAuth
+// @Router       /api/tuya/standby-killer/{id} [delete]
+func (c *TuyaStandbyKillerController) DeleteRule(ctx *gin.Context) {
+	if err := c.useCase.DeleteRule(ctx.Param("id")); err != nil {
+		abortWithError(ctx, "DeleteRule", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Standby killer rule deleted successfully",
+		Data:    nil,
+	})
+}
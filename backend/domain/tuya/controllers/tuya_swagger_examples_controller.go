@@ -0,0 +1,90 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	tuya_dtos "teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TuyaSwaggerExamplesController handles admin requests for device-driven Swagger examples
+type TuyaSwaggerExamplesController struct {
+	useCase *usecases.TuyaSwaggerExamplesUseCase
+}
+
+// NewTuyaSwaggerExamplesController creates a new TuyaSwaggerExamplesController instance
+func NewTuyaSwaggerExamplesController(useCase *usecases.TuyaSwaggerExamplesUseCase) *TuyaSwaggerExamplesController {
+	return &TuyaSwaggerExamplesController{
+		useCase: useCase,
+	}
+}
+
+// GenerateExamples handles POST /api/admin/swagger/examples endpoint
+// @Summary      Generate Swagger Examples
+// @Description  Generates example control payloads from a real device's specification (valid codes and value ranges) and serves them in the Swagger UI for the switch command endpoint.
+// @Tags         08. Admin
+// @Accept       json
+// @Produce      json
+// @Param        request  body  tuya_dtos.GenerateSwaggerExamplesRequestDTO  true  "Device to derive examples from"
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SwaggerExamplesDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/swagger/examples [post]
+func (c *TuyaSwaggerExamplesController) GenerateExamples(ctx *gin.Context) {
+	var req tuya_dtos.GenerateSwaggerExamplesRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+
+	examples, err := c.useCase.GenerateExamples(ctx.Request.Context(), req.DeviceID)
+	if err != nil {
+		abortWithError(ctx, "GenerateExamples", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Swagger examples generated successfully",
+		Data:    examples,
+	})
+}
+
+// GetExamples handles GET /api/admin/swagger/examples endpoint
+// @Summary      Get Swagger Examples
+// @Description  Returns the device-derived control examples currently served in the Swagger UI.
+// @Tags         08. Admin
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=tuya_dtos.SwaggerExamplesDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/admin/swagger/examples [get]
+func (c *TuyaSwaggerExamplesController) GetExamples(ctx *gin.Context) {
+	examples, err := c.useCase.GetExamples()
+	if err != nil {
+		abortWithError(ctx, "GetExamples", err)
+		return
+	}
+	if examples == nil {
+		ctx.JSON(http.StatusNotFound, dtos.StandardResponse{
+			Status:  false,
+			Message: "No Swagger examples generated yet",
+			Data:    nil,
+		})
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Swagger examples fetched successfully",
+		Data:    examples,
+	})
+}
@@ -0,0 +1,38 @@
+package dtos
+
+// ACUsageReportDTO is the air conditioner runtime and energy of every room for one month
+type ACUsageReportDTO struct {
+	Month        string           `json:"month"`
+	From         int64            `json:"from"`
+	To           int64            `json:"to"`
+	RatedWatts   float64          `json:"rated_watts"`
+	RuntimeHours float64          `json:"runtime_hours"`
+	EnergyKWh    float64          `json:"energy_kwh"`
+	Rooms        []ACRoomUsageDTO `json:"rooms"`
+}
+
+// ACRoomUsageDTO sums the air conditioners of one room
+type ACRoomUsageDTO struct {
+	RoomID       string             `json:"room_id"`
+	RoomName     string             `json:"room_name"`
+	RuntimeHours float64            `json:"runtime_hours"`
+	EnergyKWh    float64            `json:"energy_kwh"`
+	Devices      []ACDeviceUsageDTO `json:"devices"`
+}
+
+// ACDeviceUsageDTO is the usage of one air conditioner.
+// energy_source is "metered" when the energy comes from recorded power readings, otherwise "estimated"
+// from the runtime and AC_RATED_WATTS
+type ACDeviceUsageDTO struct {
+	DeviceID     string  `json:"device_id"`
+	Name         string  `json:"name"`
+	RuntimeHours float64 `json:"runtime_hours"`
+	EnergyKWh    float64 `json:"energy_kwh"`
+	EnergySource string  `json:"energy_source"`
+}
+
+// ACUsageReportCSVDTO is a report rendered as CSV
+type ACUsageReportCSVDTO struct {
+	FileName string
+	Data     []byte
+}
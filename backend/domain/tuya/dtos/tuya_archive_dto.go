@@ -0,0 +1,15 @@
+package dtos
+
+// ArchiveRunResultDTO summarizes one export of sensor history and audit logs to object storage.
+type ArchiveRunResultDTO struct {
+	Cutoff               int64    `json:"cutoff"`
+	SensorHistoryBuckets int      `json:"sensor_history_buckets"`
+	AuditLogEntries      int      `json:"audit_log_entries"`
+	Objects              []string `json:"objects"`
+}
+
+// ArchiveRunStartedDTO identifies the job running an export; poll GET /api/jobs/{job_id} for the ArchiveRunResultDTO
+type ArchiveRunStartedDTO struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+// TuyaAssetDTO is an asset (a building, floor or room) of an IoT Core project
+type TuyaAssetDTO struct {
+	AssetID       string `json:"asset_id"`
+	Name          string `json:"name"`
+	ParentAssetID string `json:"parent_asset_id,omitempty"`
+}
+
+// TuyaAssetsResponseDTO lists one page of the project assets
+type TuyaAssetsResponseDTO struct {
+	Assets  []TuyaAssetDTO `json:"assets"`
+	Total   int            `json:"total"`
+	Page    int            `json:"page"`
+	Limit   int            `json:"limit"`
+	HasMore bool           `json:"has_more"`
+}
+
+// TuyaAssetDevicesResponseDTO lists the devices of an asset
+type TuyaAssetDevicesResponseDTO struct {
+	AssetID      string          `json:"asset_id"`
+	Devices      []TuyaDeviceDTO `json:"devices"`
+	TotalDevices int             `json:"total_devices"`
+}
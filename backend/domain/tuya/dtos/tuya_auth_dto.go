@@ -12,4 +12,14 @@ type TuyaAuthResponseDTO struct {
 type ErrorResponseDTO struct {
 	Error   string `json:"error"`
 	Message string `json:"message"`
-}
\ No newline at end of file
+}
+
+// TuyaSessionResponseDTO is returned by the auth endpoint when server-side session mode or app JWTs are enabled.
+// Token is the signed app JWT to send as the Bearer value; it is only set when JWT_SECRET is configured.
+type TuyaSessionResponseDTO struct {
+	SessionID string `json:"session_id"`
+	ExpiresAt int64  `json:"expires_at"`
+	UID       string `json:"uid"`
+	Token     string `json:"token,omitempty"`
+	TokenType string `json:"token_type,omitempty"`
+}
@@ -0,0 +1,65 @@
+package dtos
+
+// AutomationRuleRequestDTO creates or replaces an automation rule
+type AutomationRuleRequestDTO struct {
+	Name            string                   `json:"name" binding:"required,max=100"`
+	Enabled         *bool                    `json:"enabled,omitempty"`
+	Conditions      []AutomationConditionDTO `json:"conditions" binding:"required,min=1,dive"`
+	Actions         []AutomationActionDTO    `json:"actions" binding:"required,min=1,dive"`
+	CooldownSeconds int                      `json:"cooldown_seconds,omitempty" binding:"min=0"`
+}
+
+// AutomationConditionDTO compares a reported status value of a device, or the house mode when code is
+// "house_mode" and device_id is omitted.
+// Operators: ">", ">=", "<", "<=" (numbers), "==", "!=" (any value)
+type AutomationConditionDTO struct {
+	DeviceID string      `json:"device_id,omitempty" binding:"required_unless=Code house_mode"`
+	Code     string      `json:"code" binding:"required"`
+	Operator string      `json:"operator" binding:"required,oneof=> >= < <= == !="`
+	Value    interface{} `json:"value" binding:"required"`
+}
+
+// AutomationActionDTO sends commands to a device when a rule fires
+type AutomationActionDTO struct {
+	DeviceID string           `json:"device_id" binding:"required"`
+	Commands []TuyaCommandDTO `json:"commands" binding:"required,min=1"`
+}
+
+// AutomationRuleDTO is an automation rule
+type AutomationRuleDTO struct {
+	ID              string                   `json:"id"`
+	Name            string                   `json:"name"`
+	Enabled         bool                     `json:"enabled"`
+	Conditions      []AutomationConditionDTO `json:"conditions"`
+	Actions         []AutomationActionDTO    `json:"actions"`
+	CooldownSeconds int                      `json:"cooldown_seconds"`
+	CreatedAt       int64                    `json:"created_at"`
+	UpdatedAt       int64                    `json:"updated_at"`
+	LastTriggeredAt int64                    `json:"last_triggered_at,omitempty"`
+}
+
+// AutomationRunDTO records one execution of a rule.
+// Trigger is "condition" for evaluator runs and "manual" for runs requested through the API or a scene switch
+type AutomationRunDTO struct {
+	TriggeredAt int64                       `json:"triggered_at"`
+	Trigger     string                      `json:"trigger"`
+	DeviceID    string                      `json:"device_id,omitempty"`
+	Code        string                      `json:"code,omitempty"`
+	Value       interface{}                 `json:"value,omitempty"`
+	Success     bool                        `json:"success"`
+	Results     []AutomationActionResultDTO `json:"results"`
+}
+
+// AutomationActionResultDTO is the outcome of one action of a run
+type AutomationActionResultDTO struct {
+	DeviceID string `json:"device_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
+
+// AutomationHistoryResponseDTO lists the runs of a rule, newest first
+type AutomationHistoryResponseDTO struct {
+	RuleID string             `json:"rule_id"`
+	Runs   []AutomationRunDTO `json:"runs"`
+	Total  int                `json:"total"`
+}
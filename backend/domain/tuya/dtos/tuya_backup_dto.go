@@ -0,0 +1,38 @@
+package dtos
+
+import "encoding/json"
+
+// BackupArchiveDTO is a portable backup of the configuration and device state of a deployment: every
+// persistent BadgerDB entry (device states, automations, scenes, metadata, webhooks, ...) and the rooms
+// of the SQL database (null when it was unavailable). It is downloaded from GET /api/admin/backup/{job_id} and uploaded as is to POST /api/admin/restore
+type BackupArchiveDTO struct {
+	Format    string           `json:"format" example:"teralux-backup"`
+	Version   int              `json:"version" example:"1"`
+	CreatedAt int64            `json:"created_at" example:"1767225600"`
+	Entries   []BackupEntryDTO `json:"entries"`
+	Rooms     []RoomDTO        `json:"rooms"`
+}
+
+// BackupEntryDTO is a persistent BadgerDB entry. JSON values are embedded as is; other values are base64
+// encoded in value_base64
+type BackupEntryDTO struct {
+	Key         string          `json:"key" example:"device_state:bf1234567890abcdef"`
+	Value       json.RawMessage `json:"value,omitempty" swaggertype:"object"`
+	ValueBase64 string          `json:"value_base64,omitempty"`
+}
+
+// BackupRestoreResultDTO reports what a restore wrote
+type BackupRestoreResultDTO struct {
+	Mode            string `json:"mode" example:"merge"`
+	Entries         int    `json:"entries" example:"42"`
+	Removed         int    `json:"removed" example:"0"`
+	Rooms           int    `json:"rooms" example:"3"`
+	RestartRequired bool   `json:"restart_required" example:"true"`
+}
+
+// BackupExportStartedDTO identifies the job building a backup archive; poll GET /api/jobs/{job_id} until it
+// succeeded, then download the archive from GET /api/admin/backup/{job_id}
+type BackupExportStartedDTO struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
@@ -0,0 +1,48 @@
+package dtos
+
+// FavoritesRequestDTO replaces the favorite devices of the user
+type FavoritesRequestDTO struct {
+	DeviceIDs []string `json:"device_ids" binding:"required"`
+}
+
+// FavoritesResponseDTO lists the favorite devices of the user in display order
+type FavoritesResponseDTO struct {
+	DeviceIDs []string `json:"device_ids"`
+}
+
+// BootstrapDTO is everything the app needs on cold start.
+// Sections that could not be assembled are omitted and listed in Unavailable
+type BootstrapDTO struct {
+	Session     BootstrapSessionDTO `json:"session"`
+	Summary     *DeviceSummaryDTO   `json:"summary,omitempty"`
+	Rooms       []RoomDTO           `json:"rooms"`
+	Favorites   []TuyaDeviceDTO     `json:"favorites"`
+	Alerts      []ActiveAlertDTO    `json:"alerts"`
+	HouseMode   string              `json:"house_mode,omitempty"`
+	Unavailable []string            `json:"unavailable,omitempty"`
+	GeneratedAt int64               `json:"generated_at"`
+}
+
+// BootstrapSessionDTO describes how the request was authenticated.
+// Type is "session" (server-side session), "token" (raw Tuya token) or "server" (server-managed token)
+type BootstrapSessionDTO struct {
+	Valid     bool   `json:"valid"`
+	Type      string `json:"type"`
+	UID       string `json:"uid"`
+	ExpiresAt int64  `json:"expires_at,omitempty"`
+}
+
+// DeviceSummaryDTO counts the devices of the user
+type DeviceSummaryDTO struct {
+	Total      int            `json:"total"`
+	Online     int            `json:"online"`
+	Offline    int            `json:"offline"`
+	Categories map[string]int `json:"categories"`
+}
+
+// ActiveAlertDTO is a sensor whose leak or smoke alarm is currently active
+type ActiveAlertDTO struct {
+	DeviceID string `json:"device_id"`
+	Name     string `json:"name,omitempty"`
+	Category string `json:"category,omitempty"`
+}
@@ -0,0 +1,13 @@
+package dtos
+
+// CacheWarmStartedDTO identifies the job warming the cache; poll GET /api/jobs/{job_id} for the CacheWarmResultDTO
+type CacheWarmStartedDTO struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
+
+// CacheWarmResultDTO reports what a cache warm fetched, stored as the job result
+type CacheWarmResultDTO struct {
+	Devices        int `json:"devices" example:"42"`
+	Specifications int `json:"specifications" example:"40"`
+}
@@ -0,0 +1,17 @@
+package dtos
+
+// CameraStreamDTO is a time-limited stream URL of a camera
+type CameraStreamDTO struct {
+	DeviceID  string `json:"device_id"`
+	Type      string `json:"type" example:"hls"`
+	URL       string `json:"url" example:"https://wework.ipc.tuyacn.com/live/xxx.m3u8"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+// CameraSnapshotDTO is a time-limited URL of a picture just captured by a camera
+type CameraSnapshotDTO struct {
+	DeviceID   string `json:"device_id"`
+	URL        string `json:"url"`
+	CapturedAt int64  `json:"captured_at"`
+	ExpiresAt  int64  `json:"expires_at"`
+}
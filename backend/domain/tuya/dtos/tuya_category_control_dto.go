@@ -0,0 +1,23 @@
+package dtos
+
+// FanControlRequestDTO holds normalized settings for fan (fs) and fan switch (fskg) devices.
+// Omitted fields are left unchanged.
+type FanControlRequestDTO struct {
+	Power       *bool  `json:"power,omitempty"`
+	Speed       *int   `json:"speed,omitempty"`
+	Oscillation *bool  `json:"oscillation,omitempty"`
+	Direction   string `json:"direction,omitempty"`
+}
+
+// DimmerControlRequestDTO holds normalized settings for dimmer (tgq) devices.
+// Brightness is a percentage (1-100). Omitted fields are left unchanged.
+type DimmerControlRequestDTO struct {
+	Power      *bool `json:"power,omitempty"`
+	Brightness *int  `json:"brightness,omitempty"`
+}
+
+// CategoryControlResponseDTO reports the DP commands a normalized request was translated into
+type CategoryControlResponseDTO struct {
+	Success  bool             `json:"success"`
+	Commands []TuyaCommandDTO `json:"commands"`
+}
@@ -0,0 +1,49 @@
+package dtos
+
+// CircadianConfigDTO is the adaptive lighting configuration.
+// Curve points are interpolated linearly over the day; lights may override the curve individually.
+// Curve times are wall-clock times in Timezone, or in the deployment TIMEZONE when empty.
+type CircadianConfigDTO struct {
+	Enabled  bool                `json:"enabled"`
+	Timezone string              `json:"timezone,omitempty"`
+	Curve    []CircadianPointDTO `json:"curve" binding:"dive"`
+	Lights   []CircadianLightDTO `json:"lights" binding:"dive"`
+}
+
+// CircadianPointDTO is the target brightness and colour temperature (percentages, 0 = warmest) at a time of day ("HH:MM")
+type CircadianPointDTO struct {
+	Time        string `json:"time" binding:"required"`
+	Brightness  int    `json:"brightness" binding:"min=1,max=100"`
+	Temperature int    `json:"temperature" binding:"min=0,max=100"`
+}
+
+// CircadianLightDTO enrols a light in adaptive lighting.
+// Curve replaces the global curve for this light; temperature_only leaves brightness untouched.
+type CircadianLightDTO struct {
+	DeviceID        string              `json:"device_id" binding:"required"`
+	Curve           []CircadianPointDTO `json:"curve,omitempty" binding:"dive"`
+	TemperatureOnly bool                `json:"temperature_only,omitempty"`
+}
+
+// CircadianStatusDTO is the configuration together with the current target and state of each light
+type CircadianStatusDTO struct {
+	Config CircadianConfigDTO        `json:"config"`
+	Lights []CircadianLightStatusDTO `json:"lights"`
+}
+
+// CircadianLightStatusDTO reports the current target and manual-override state of one light
+type CircadianLightStatusDTO struct {
+	DeviceID          string `json:"device_id"`
+	TargetBrightness  int    `json:"target_brightness"`
+	TargetTemperature int    `json:"target_temperature"`
+	LastDispatchAt    int64  `json:"last_dispatch_at,omitempty"`
+	OverriddenUntil   int64  `json:"overridden_until,omitempty"`
+}
+
+// CircadianDispatchResultDTO reports what one dispatch run did to each light
+type CircadianDispatchResultDTO struct {
+	DeviceID string           `json:"device_id"`
+	Action   string           `json:"action"`
+	Commands []TuyaCommandDTO `json:"commands,omitempty"`
+	Error    string           `json:"error,omitempty"`
+}
@@ -0,0 +1,35 @@
+package dtos
+
+// ClimateStateDTO is the normalized climate model of an IR air conditioner or a native Wi-Fi AC/thermostat.
+// Modes are cool, heat, auto, fan or dry; fan speeds are auto, low, medium or high. Fields a device does not
+// report are omitted.
+type ClimateStateDTO struct {
+	DeviceID    string               `json:"device_id"`
+	Source      string               `json:"source" example:"ir"`
+	Power       bool                 `json:"power"`
+	Mode        string               `json:"mode,omitempty" example:"cool"`
+	TargetTemp  *float64             `json:"target_temp,omitempty" example:"24"`
+	CurrentTemp *float64             `json:"current_temp,omitempty" example:"27.5"`
+	FanSpeed    string               `json:"fan_speed,omitempty" example:"auto"`
+	Swing       *bool                `json:"swing,omitempty"`
+	Supports    ClimateCapabilityDTO `json:"supports"`
+}
+
+// ClimateCapabilityDTO lists the normalized settings a climate device accepts
+type ClimateCapabilityDTO struct {
+	Modes     []string `json:"modes"`
+	FanSpeeds []string `json:"fan_speeds"`
+	MinTemp   *float64 `json:"min_temp,omitempty" example:"16"`
+	MaxTemp   *float64 `json:"max_temp,omitempty" example:"30"`
+	Swing     bool     `json:"swing"`
+}
+
+// ClimateControlRequestDTO holds normalized climate settings. Omitted fields are left unchanged.
+// Turning a device off cannot be combined with other settings, since IR air conditioners switch on for any command.
+type ClimateControlRequestDTO struct {
+	Power      *bool    `json:"power,omitempty"`
+	Mode       string   `json:"mode,omitempty" binding:"omitempty,oneof=cool heat auto fan dry" example:"cool"`
+	TargetTemp *float64 `json:"target_temp,omitempty" example:"24"`
+	FanSpeed   string   `json:"fan_speed,omitempty" binding:"omitempty,oneof=auto low medium high" example:"auto"`
+	Swing      *bool    `json:"swing,omitempty"`
+}
@@ -0,0 +1,47 @@
+package dtos
+
+// TuyaHomeDTO is a home (family) of the Smart Life app
+type TuyaHomeDTO struct {
+	HomeID  int64  `json:"home_id"`
+	Name    string `json:"name"`
+	GeoName string `json:"geo_name,omitempty"`
+	Role    string `json:"role,omitempty"`
+}
+
+// CloudSceneDTO is a tap-to-run scene configured in the Smart Life app
+type CloudSceneDTO struct {
+	SceneID     string `json:"scene_id"`
+	Name        string `json:"name"`
+	Background  string `json:"background,omitempty"`
+	Enabled     bool   `json:"enabled"`
+	ActionCount int    `json:"action_count"`
+}
+
+// CloudScenesResponseDTO lists the tap-to-run scenes of a home
+type CloudScenesResponseDTO struct {
+	HomeID string          `json:"home_id"`
+	Scenes []CloudSceneDTO `json:"scenes"`
+}
+
+// CloudAutomationDTO is an automation configured in the Smart Life app
+type CloudAutomationDTO struct {
+	AutomationID string `json:"automation_id"`
+	Name         string `json:"name"`
+	Background   string `json:"background,omitempty"`
+	Enabled      bool   `json:"enabled"`
+	ActionCount  int    `json:"action_count"`
+}
+
+// CloudAutomationsResponseDTO lists the automations of a home
+type CloudAutomationsResponseDTO struct {
+	HomeID      string               `json:"home_id"`
+	Automations []CloudAutomationDTO `json:"automations"`
+}
+
+// CloudSceneActionResultDTO reports a scene trigger or an automation state change
+type CloudSceneActionResultDTO struct {
+	HomeID       string `json:"home_id"`
+	SceneID      string `json:"scene_id,omitempty"`
+	AutomationID string `json:"automation_id,omitempty"`
+	Action       string `json:"action"`
+}
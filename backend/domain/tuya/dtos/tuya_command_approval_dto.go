@@ -0,0 +1,37 @@
+package dtos
+
+// CommandApprovalRuleDTO marks a device, or some of its DP codes, as requiring two-person approval
+type CommandApprovalRuleDTO struct {
+	DeviceID   string   `json:"device_id"`
+	Codes      []string `json:"codes,omitempty"`
+	TTLSeconds int64    `json:"ttl_seconds"`
+	CreatedAt  int64    `json:"created_at"`
+}
+
+// SetCommandApprovalRuleRequestDTO protects the listed DP codes of a device (all commands when empty).
+// Pending actions expire after TTLSeconds (default COMMAND_APPROVAL_TTL)
+type SetCommandApprovalRuleRequestDTO struct {
+	Codes      []string `json:"codes" example:"switch_1"`
+	TTLSeconds int64    `json:"ttl_seconds" binding:"omitempty,min=30,max=86400"`
+}
+
+// PendingActionDTO is a protected command waiting for, or decided by, a second user.
+// Status is "pending", "executed", "failed", "rejected" or "expired"
+type PendingActionDTO struct {
+	ID          string           `json:"id"`
+	DeviceID    string           `json:"device_id"`
+	Commands    []TuyaCommandDTO `json:"commands"`
+	RequestedBy string           `json:"requested_by"`
+	RequestedAt int64            `json:"requested_at"`
+	ExpiresAt   int64            `json:"expires_at"`
+	Status      string           `json:"status"`
+	DecidedBy   string           `json:"decided_by,omitempty"`
+	DecidedAt   int64            `json:"decided_at,omitempty"`
+	Reason      string           `json:"reason,omitempty"`
+	Error       string           `json:"error,omitempty"`
+}
+
+// RejectPendingActionRequestDTO explains why a pending action was rejected
+type RejectPendingActionRequestDTO struct {
+	Reason string `json:"reason" binding:"max=200"`
+}
@@ -0,0 +1,15 @@
+package dtos
+
+// CommandCooldownDTO is the cooldown learned for a device (or IR hub) that rejects rapid command sequences.
+// Commands to the device are delayed until CooldownMs passed since the previous one
+type CommandCooldownDTO struct {
+	DeviceID       string `json:"device_id"`
+	CooldownMs     int64  `json:"cooldown_ms"`
+	RapidFailures  int    `json:"rapid_failures"`
+	SuccessStreak  int    `json:"success_streak"`
+	LastCommandAt  int64  `json:"last_command_at,omitempty"`
+	LastFailureAt  int64  `json:"last_failure_at,omitempty"`
+	LastFailure    string `json:"last_failure,omitempty"`
+	LastFailureGap int64  `json:"last_failure_gap_ms,omitempty"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
@@ -0,0 +1,22 @@
+package dtos
+
+// QueueCommandRequestDTO is a device command submitted to the command queue
+type QueueCommandRequestDTO struct {
+	DeviceID string           `json:"device_id" binding:"required"`
+	Commands []TuyaCommandDTO `json:"commands" binding:"required,min=1,dive"`
+}
+
+// CommandStatusDTO is the execution status of a queued device command
+type CommandStatusDTO struct {
+	ID             string           `json:"id"`
+	DeviceID       string           `json:"device_id"`
+	Commands       []TuyaCommandDTO `json:"commands"`
+	Status         string           `json:"status"`
+	Attempts       int              `json:"attempts"`
+	MaxAttempts    int              `json:"max_attempts"`
+	Error          string           `json:"error,omitempty"`
+	IdempotencyKey string           `json:"idempotency_key,omitempty"`
+	CreatedAt      int64            `json:"created_at"`
+	UpdatedAt      int64            `json:"updated_at"`
+	FinishedAt     int64            `json:"finished_at,omitempty"`
+}
@@ -0,0 +1,15 @@
+package dtos
+
+// DeviceCategoryFilterDTO is the effective device category filter and where it comes from (config or api)
+type DeviceCategoryFilterDTO struct {
+	Allow     []string `json:"allow"`
+	Deny      []string `json:"deny"`
+	Source    string   `json:"source"`
+	UpdatedAt int64    `json:"updated_at,omitempty"`
+}
+
+// UpdateDeviceCategoryFilterRequestDTO replaces the device category filter. Categories are Tuya category codes (e.g., sp, dj, wnykq)
+type UpdateDeviceCategoryFilterRequestDTO struct {
+	Allow []string `json:"allow" example:"dj,kg,infrared_ac"`
+	Deny  []string `json:"deny" example:"sp"`
+}
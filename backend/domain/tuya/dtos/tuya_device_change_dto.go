@@ -0,0 +1,22 @@
+package dtos
+
+// DeviceChangeDTO describes one device that was added, removed, renamed or re-categorized
+type DeviceChangeDTO struct {
+	Type     string `json:"type" example:"removed"`
+	DeviceID string `json:"device_id"`
+	Name     string `json:"name"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
+
+// DeviceChangeLogEntryDTO groups the changes detected by a single device list refresh
+type DeviceChangeLogEntryDTO struct {
+	DetectedAt int64             `json:"detected_at"`
+	Changes    []DeviceChangeDTO `json:"changes"`
+}
+
+// DeviceChangeLogResponseDTO represents the response for the device change log
+type DeviceChangeLogResponseDTO struct {
+	Entries []DeviceChangeLogEntryDTO `json:"entries"`
+	Total   int                       `json:"total"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+// SubmitDeviceClaimRequestDTO asks for a device to be assigned to the caller (e.g., after scanning its QR code)
+type SubmitDeviceClaimRequestDTO struct {
+	DeviceID string `json:"device_id" binding:"required"`
+	Note     string `json:"note" binding:"max=200"`
+}
+
+// DeviceClaimDTO is a claim on a device. Status is "pending", "approved" or "rejected"
+type DeviceClaimDTO struct {
+	ID        string `json:"id"`
+	DeviceID  string `json:"device_id"`
+	UID       string `json:"uid"`
+	Note      string `json:"note,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	DecidedAt int64  `json:"decided_at,omitempty"`
+}
+
+// RejectDeviceClaimRequestDTO explains why a claim was rejected
+type RejectDeviceClaimRequestDTO struct {
+	Reason string `json:"reason" binding:"max=200"`
+}
@@ -0,0 +1,33 @@
+package dtos
+
+// DeviceComparisonDTO compares two devices, e.g. two identical plugs of which one misbehaves.
+// Differences lists only the fields whose values differ; a missing side is null
+type DeviceComparisonDTO struct {
+	Devices     []DeviceComparisonSideDTO `json:"devices"`
+	SameProduct bool                      `json:"same_product"`
+	Differences []DeviceDifferenceDTO     `json:"differences"`
+}
+
+// DeviceComparisonSideDTO summarizes one of the compared devices
+type DeviceComparisonSideDTO struct {
+	ID             string            `json:"id"`
+	Name           string            `json:"name"`
+	ProductID      string            `json:"product_id"`
+	ProductName    string            `json:"product_name"`
+	Category       string            `json:"category"`
+	Online         bool              `json:"online"`
+	Firmware       map[string]string `json:"firmware"`
+	Commands       int               `json:"commands"`
+	FailedCommands int               `json:"failed_commands"`
+	LastError      string            `json:"last_error,omitempty"`
+	Unavailable    []string          `json:"unavailable,omitempty"`
+}
+
+// DeviceDifferenceDTO is a field that differs between the compared devices.
+// section is one of device, firmware, specification, status or history
+type DeviceDifferenceDTO struct {
+	Section string      `json:"section"`
+	Field   string      `json:"field"`
+	A       interface{} `json:"a"`
+	B       interface{} `json:"b"`
+}
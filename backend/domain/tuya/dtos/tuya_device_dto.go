@@ -20,6 +20,26 @@ type TuyaDeviceDTO struct {
 	CreateTime        int64                    `json:"create_time"`
 	UpdateTime        int64                    `json:"update_time"`
 	Collections       []TuyaDeviceDTO          `json:"collections,omitempty"`
+	Channels          []DeviceChannelDTO       `json:"channels,omitempty"`
+	Metadata          *DeviceMetadataDTO       `json:"metadata,omitempty"`
+}
+
+// DeviceChannelDTO represents one gang of a multi-gang switch as an addressable sub-entity
+type DeviceChannelDTO struct {
+	Code  string      `json:"code"`
+	Index int         `json:"index"`
+	Name  string      `json:"name"`
+	Value interface{} `json:"value"`
+}
+
+// RenameChannelsRequestDTO sets channel names keyed by channel code (e.g., {"switch_1": "Porch"})
+type RenameChannelsRequestDTO struct {
+	Names map[string]string `json:"names" binding:"required"`
+}
+
+// ChannelCommandRequestDTO switches a single channel
+type ChannelCommandRequestDTO struct {
+	Value *bool `json:"value" binding:"required"`
 }
 
 // TuyaCommandDTO represents a single command
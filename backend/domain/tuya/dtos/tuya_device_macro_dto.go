@@ -0,0 +1,49 @@
+package dtos
+
+// DeviceMacroDTO is a parameterized command macro stored for a device
+type DeviceMacroDTO struct {
+	DeviceID   string              `json:"device_id"`
+	Name       string              `json:"name"`
+	Parameters []MacroParameterDTO `json:"parameters"`
+	Commands   []MacroCommandDTO   `json:"commands"`
+	CreatedAt  int64               `json:"created_at"`
+	UpdatedAt  int64               `json:"updated_at"`
+}
+
+// MacroParameterDTO declares an argument of a macro.
+// Type is "number", "integer", "boolean" or "string"; Min/Max bound numbers and Options restricts strings.
+// Parameters without a default are required when the macro is invoked.
+type MacroParameterDTO struct {
+	Name    string      `json:"name" binding:"required"`
+	Type    string      `json:"type" binding:"required,oneof=number integer boolean string"`
+	Default interface{} `json:"default,omitempty"`
+	Min     *float64    `json:"min,omitempty"`
+	Max     *float64    `json:"max,omitempty"`
+	Options []string    `json:"options,omitempty"`
+}
+
+// MacroCommandDTO is a command template. A value of "${param}" is replaced by the argument with its type;
+// placeholders inside a longer string are substituted as text.
+type MacroCommandDTO struct {
+	Code  string      `json:"code" binding:"required"`
+	Value interface{} `json:"value"`
+}
+
+// SaveDeviceMacroRequestDTO creates or replaces a macro
+type SaveDeviceMacroRequestDTO struct {
+	Parameters []MacroParameterDTO `json:"parameters" binding:"dive"`
+	Commands   []MacroCommandDTO   `json:"commands" binding:"required,min=1,dive"`
+}
+
+// RunDeviceMacroRequestDTO holds the arguments of a macro invocation, keyed by parameter name
+type RunDeviceMacroRequestDTO struct {
+	Params map[string]interface{} `json:"params"`
+}
+
+// RunDeviceMacroResponseDTO reports the commands a macro expanded to and whether they were sent
+type RunDeviceMacroResponseDTO struct {
+	DeviceID string           `json:"device_id"`
+	Macro    string           `json:"macro"`
+	Success  bool             `json:"success"`
+	Commands []TuyaCommandDTO `json:"commands"`
+}
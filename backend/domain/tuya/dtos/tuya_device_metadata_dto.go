@@ -0,0 +1,39 @@
+package dtos
+
+// DeviceMetadataDTO is the local metadata of a device, merged into device responses
+type DeviceMetadataDTO struct {
+	Label     string `json:"label,omitempty" example:"Bedside lamp"`
+	Icon      string `json:"icon,omitempty" example:"lamp"`
+	SortOrder *int   `json:"sort_order,omitempty" example:"1"`
+	Favorite  bool   `json:"favorite"`
+	UpdatedAt int64  `json:"updated_at,omitempty"`
+}
+
+// UpdateDeviceMetadataRequestDTO changes the local metadata of a device. Omitted fields are kept;
+// an empty label or icon, or a negative sort_order, clears it.
+type UpdateDeviceMetadataRequestDTO struct {
+	Label     *string `json:"label,omitempty" binding:"omitempty,max=60" example:"Bedside lamp"`
+	Icon      *string `json:"icon,omitempty" binding:"omitempty,max=60" example:"lamp"`
+	SortOrder *int    `json:"sort_order,omitempty" example:"1"`
+	Favorite  *bool   `json:"favorite,omitempty"`
+}
+
+// RenameDeviceRequestDTO renames a device in the Tuya cloud
+type RenameDeviceRequestDTO struct {
+	Name string `json:"name" binding:"required,max=60" example:"Bedroom Light"`
+}
+
+// DeviceFavoriteRequestDTO marks or unmarks a device as favorite; an empty body marks it
+type DeviceFavoriteRequestDTO struct {
+	Favorite *bool `json:"favorite,omitempty"`
+}
+
+// DeviceOrderRequestDTO sets the custom device order; devices not listed lose their position
+type DeviceOrderRequestDTO struct {
+	DeviceIDs []string `json:"device_ids" binding:"required"`
+}
+
+// DeviceOrderResponseDTO is the saved custom device order
+type DeviceOrderResponseDTO struct {
+	DeviceIDs []string `json:"device_ids"`
+}
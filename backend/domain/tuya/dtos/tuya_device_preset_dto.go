@@ -0,0 +1,30 @@
+package dtos
+
+// DevicePresetDTO is a named command bundle stored for a device
+type DevicePresetDTO struct {
+	DeviceID  string             `json:"device_id"`
+	Name      string             `json:"name"`
+	Commands  []PresetCommandDTO `json:"commands"`
+	CreatedAt int64              `json:"created_at"`
+	UpdatedAt int64              `json:"updated_at"`
+}
+
+// PresetCommandDTO is one command of a preset. Unlike TuyaCommandDTO, false and 0 are valid values.
+type PresetCommandDTO struct {
+	Code  string      `json:"code" binding:"required"`
+	Value interface{} `json:"value"`
+}
+
+// SaveDevicePresetRequestDTO creates or replaces a preset
+type SaveDevicePresetRequestDTO struct {
+	Name     string             `json:"name" binding:"required"`
+	Commands []PresetCommandDTO `json:"commands" binding:"required,min=1,dive"`
+}
+
+// ExecuteDevicePresetResponseDTO reports the commands of an executed preset and whether they were sent
+type ExecuteDevicePresetResponseDTO struct {
+	DeviceID string           `json:"device_id"`
+	Preset   string           `json:"preset"`
+	Success  bool             `json:"success"`
+	Commands []TuyaCommandDTO `json:"commands"`
+}
@@ -0,0 +1,17 @@
+package dtos
+
+// DeviceStateHistoryDTO lists the current state of a device and the states it replaced, newest first
+type DeviceStateHistoryDTO struct {
+	DeviceID string           `json:"device_id"`
+	Current  *DeviceStateDTO  `json:"current"`
+	History  []DeviceStateDTO `json:"history"`
+}
+
+// UndoDeviceStateResponseDTO describes an undone state change.
+// RestoredFrom is the updated_at of the history entry that was restored; Commands are the settings sent to get back to it
+type UndoDeviceStateResponseDTO struct {
+	DeviceID     string                  `json:"device_id"`
+	RestoredFrom int64                   `json:"restored_from"`
+	Success      bool                    `json:"success"`
+	Commands     []DeviceStateCommandDTO `json:"commands"`
+}
@@ -0,0 +1,18 @@
+package dtos
+
+// DeviceStatusEntryDTO is the compact status of one device: its online state and key status values
+type DeviceStatusEntryDTO struct {
+	ID     string                 `json:"id"`
+	Online bool                   `json:"online"`
+	Status map[string]interface{} `json:"status"`
+}
+
+// DeviceStatusSnapshotDTO is the status of all devices in a single compact payload.
+// With a delta, Devices only holds the devices that changed since the version the client sent and Removed
+// lists the devices that disappeared.
+type DeviceStatusSnapshotDTO struct {
+	Version string                 `json:"version"`
+	Delta   bool                   `json:"delta"`
+	Devices []DeviceStatusEntryDTO `json:"devices"`
+	Removed []string               `json:"removed,omitempty"`
+}
@@ -0,0 +1,24 @@
+package dtos
+
+// SetDeviceTimerRequestDTO schedules a switch of a device to turn on or off after a delay
+type SetDeviceTimerRequestDTO struct {
+	Action       string `json:"action" binding:"required,oneof=on off"`
+	DelaySeconds int    `json:"delay_seconds" binding:"required,min=1,max=86400"`
+	Code         string `json:"code,omitempty"`
+}
+
+// DeviceTimerDTO is a pending timer of a device. Mode is "native" when the device counts down itself
+// and "job" when the command is sent later by the server
+type DeviceTimerDTO struct {
+	ID               string `json:"id"`
+	DeviceID         string `json:"device_id"`
+	Code             string `json:"code"`
+	Action           string `json:"action"`
+	Mode             string `json:"mode"`
+	CountdownCode    string `json:"countdown_code,omitempty"`
+	JobID            string `json:"job_id,omitempty"`
+	DelaySeconds     int    `json:"delay_seconds"`
+	RemainingSeconds int64  `json:"remaining_seconds"`
+	CreatedAt        int64  `json:"created_at"`
+	FiresAt          int64  `json:"fires_at"`
+}
@@ -0,0 +1,47 @@
+package dtos
+
+// LockRecordDTO is one unlock of a door lock
+type LockRecordDTO struct {
+	Method    string      `json:"method" example:"unlock_fingerprint"`
+	Value     interface{} `json:"value"`
+	User      string      `json:"user,omitempty"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// LockRecordsResponseDTO is one page of unlock records, newest first
+type LockRecordsResponseDTO struct {
+	DeviceID string          `json:"device_id"`
+	From     int64           `json:"from"`
+	To       int64           `json:"to"`
+	Page     int             `json:"page"`
+	PageSize int             `json:"page_size"`
+	Total    int             `json:"total"`
+	Records  []LockRecordDTO `json:"records"`
+}
+
+// TempPasswordRequestDTO creates a temporary password valid between two Unix timestamps.
+// The password is encrypted for the lock and never stored or returned.
+type TempPasswordRequestDTO struct {
+	Name          string `json:"name" binding:"required,max=20" example:"Cleaner"`
+	Password      string `json:"password" binding:"required,numeric,min=6,max=10" example:"1234567"`
+	EffectiveTime int64  `json:"effective_time" example:"1760600000"`
+	InvalidTime   int64  `json:"invalid_time" binding:"required" example:"1760686400"`
+	Phone         string `json:"phone,omitempty" example:"81234567890"`
+	TimeZone      string `json:"time_zone,omitempty" example:"+07:00"`
+}
+
+// TempPasswordDTO is a temporary password of a door lock; the password itself is never returned
+type TempPasswordDTO struct {
+	PasswordID    int64  `json:"password_id"`
+	Name          string `json:"name"`
+	Phone         string `json:"phone,omitempty"`
+	EffectiveTime int64  `json:"effective_time"`
+	InvalidTime   int64  `json:"invalid_time"`
+	Phase         string `json:"phase,omitempty" example:"active"`
+}
+
+// TempPasswordsResponseDTO lists the temporary passwords of a door lock
+type TempPasswordsResponseDTO struct {
+	DeviceID  string            `json:"device_id"`
+	Passwords []TempPasswordDTO `json:"passwords"`
+}
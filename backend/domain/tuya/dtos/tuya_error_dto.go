@@ -0,0 +1,9 @@
+package dtos
+
+// ErrorDetailDTO is the data of an error response: a machine-readable code, the Tuya error code it was
+// translated from (if any) and a hint on how to resolve it
+type ErrorDetailDTO struct {
+	Code     string `json:"code"`
+	TuyaCode int    `json:"tuya_code,omitempty"`
+	Hint     string `json:"hint,omitempty"`
+}
@@ -0,0 +1,26 @@
+package dtos
+
+// FeatureFlagDTO is a feature flag with its effective rollout
+type FeatureFlagDTO struct {
+	Name            string   `json:"name"`
+	Description     string   `json:"description"`
+	Percentage      int      `json:"percentage"`
+	Devices         []string `json:"devices"`
+	ExcludedDevices []string `json:"excluded_devices"`
+	Source          string   `json:"source"`
+	UpdatedAt       int64    `json:"updated_at,omitempty"`
+}
+
+// UpdateFeatureFlagRequestDTO replaces the rollout of a feature flag
+type UpdateFeatureFlagRequestDTO struct {
+	Percentage      *int     `json:"percentage" binding:"required,min=0,max=100"`
+	Devices         []string `json:"devices"`
+	ExcludedDevices []string `json:"excluded_devices"`
+}
+
+// FeatureFlagEvaluationDTO reports whether a feature flag is enabled for a device
+type FeatureFlagEvaluationDTO struct {
+	Name     string `json:"name"`
+	DeviceID string `json:"device_id"`
+	Enabled  bool   `json:"enabled"`
+}
@@ -0,0 +1,14 @@
+package dtos
+
+// SetHouseModeRequestDTO switches the house mode
+type SetHouseModeRequestDTO struct {
+	Mode string `json:"mode" binding:"required,oneof=home away sleep holiday"`
+}
+
+// HouseModeDTO is the current house mode and the sensor poll interval it applies
+type HouseModeDTO struct {
+	Mode         string `json:"mode"`
+	PreviousMode string `json:"previous_mode,omitempty"`
+	ChangedAt    int64  `json:"changed_at,omitempty"`
+	PollInterval string `json:"poll_interval"`
+}
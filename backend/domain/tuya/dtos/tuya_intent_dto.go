@@ -0,0 +1,29 @@
+package dtos
+
+// IntentRequestDTO is a typed command such as "turn off meeting room lights"
+type IntentRequestDTO struct {
+	Text   string `json:"text" binding:"required" example:"turn off meeting room lights"`
+	DryRun bool   `json:"dry_run"`
+}
+
+// IntentResultDTO reports how a command was understood and what was done
+type IntentResultDTO struct {
+	Text     string                  `json:"text"`
+	Action   string                  `json:"action"`
+	Value    *int                    `json:"value,omitempty"`
+	RoomID   string                  `json:"room_id,omitempty"`
+	RoomName string                  `json:"room_name,omitempty"`
+	DryRun   bool                    `json:"dry_run"`
+	Summary  string                  `json:"summary"`
+	Devices  []IntentDeviceResultDTO `json:"devices"`
+}
+
+// IntentDeviceResultDTO is the outcome of a command on one matched device (or channel of a multi-gang switch)
+type IntentDeviceResultDTO struct {
+	DeviceID string           `json:"device_id"`
+	Name     string           `json:"name"`
+	Channel  string           `json:"channel,omitempty"`
+	Commands []TuyaCommandDTO `json:"commands,omitempty"`
+	Success  bool             `json:"success"`
+	Error    string           `json:"error,omitempty"`
+}
@@ -0,0 +1,54 @@
+package dtos
+
+// IRLearningSessionDTO is returned when learning mode starts on an IR hub
+type IRLearningSessionDTO struct {
+	InfraredID   string `json:"infrared_id"`
+	LearningTime int64  `json:"learning_time"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+// IRLearnedCodeDTO is the result of polling for a learned code
+type IRLearnedCodeDTO struct {
+	Learned bool   `json:"learned"`
+	Code    string `json:"code,omitempty"`
+}
+
+// SaveIRLearnedKeyRequestDTO saves a learned code as a named key.
+// When RemoteID is empty a new custom remote named RemoteName is created.
+type SaveIRLearnedKeyRequestDTO struct {
+	RemoteID   string `json:"remote_id"`
+	RemoteName string `json:"remote_name"`
+	CategoryID int    `json:"category_id"`
+	KeyName    string `json:"key_name" binding:"required"`
+	Code       string `json:"code" binding:"required"`
+}
+
+// SaveIRLearnedKeyResponseDTO is returned after a learned key is saved
+type SaveIRLearnedKeyResponseDTO struct {
+	RemoteID string `json:"remote_id,omitempty"`
+	KeyName  string `json:"key_name"`
+}
+
+// IRNamedCodeDTO is a raw learned code saved under a name on an IR hub
+type IRNamedCodeDTO struct {
+	InfraredID string `json:"infrared_id"`
+	Name       string `json:"name"`
+	Code       string `json:"code"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
+
+// SaveIRNamedCodeRequestDTO saves a raw code under a name. Either Code is given, or LearningTime
+// (from starting learning mode) to save the code the hub captured since then
+type SaveIRNamedCodeRequestDTO struct {
+	Name         string `json:"name" binding:"required"`
+	Code         string `json:"code"`
+	LearningTime int64  `json:"learning_time"`
+}
+
+// SendIRNamedCodeResponseDTO is returned after a saved code is sent through the hub
+type SendIRNamedCodeResponseDTO struct {
+	InfraredID string `json:"infrared_id"`
+	Name       string `json:"name"`
+	Success    bool   `json:"success"`
+}
@@ -0,0 +1,90 @@
+package dtos
+
+// IRRemoteDTO is a remote (TV, fan, projector, AC, ...) configured on an IR hub
+type IRRemoteDTO struct {
+	RemoteID   string `json:"remote_id"`
+	RemoteName string `json:"remote_name"`
+	CategoryID int    `json:"category_id"`
+	BrandID    int    `json:"brand_id"`
+	BrandName  string `json:"brand_name"`
+}
+
+// IRRemotesResponseDTO lists the remotes configured on an IR hub
+type IRRemotesResponseDTO struct {
+	InfraredID string        `json:"infrared_id"`
+	Remotes    []IRRemoteDTO `json:"remotes"`
+}
+
+// IRRemoteKeyDTO is a key of a remote
+type IRRemoteKeyDTO struct {
+	Key         string `json:"key"`
+	KeyID       int    `json:"key_id"`
+	KeyName     string `json:"key_name"`
+	StandardKey bool   `json:"standard_key"`
+}
+
+// IRRemoteKeysResponseDTO lists the keys of a remote
+type IRRemoteKeysResponseDTO struct {
+	InfraredID string           `json:"infrared_id"`
+	RemoteID   string           `json:"remote_id"`
+	CategoryID int              `json:"category_id"`
+	Keys       []IRRemoteKeyDTO `json:"keys"`
+}
+
+// IRRemoteCommandRequestDTO presses a key of a remote, identified by key (e.g., "power") or key_id
+type IRRemoteCommandRequestDTO struct {
+	Key   string `json:"key" example:"power"`
+	KeyID int    `json:"key_id,omitempty"`
+}
+
+// IRRemoteCommandResponseDTO is returned after a key press was sent. Deduplicated is set when an identical
+// power key press from another client was sent instead
+type IRRemoteCommandResponseDTO struct {
+	RemoteID     string `json:"remote_id"`
+	Key          string `json:"key"`
+	KeyID        int    `json:"key_id"`
+	Deduplicated bool   `json:"deduplicated,omitempty"`
+}
+
+// IRCategoryDTO is a remote category an IR hub can control
+type IRCategoryDTO struct {
+	CategoryID int    `json:"category_id"`
+	Name       string `json:"name"`
+}
+
+// IRCategoriesResponseDTO lists the remote categories of an IR hub
+type IRCategoriesResponseDTO struct {
+	InfraredID string          `json:"infrared_id"`
+	Categories []IRCategoryDTO `json:"categories"`
+}
+
+// IRBrandDTO is an appliance brand of a remote category
+type IRBrandDTO struct {
+	BrandID int    `json:"brand_id"`
+	Name    string `json:"name"`
+}
+
+// IRBrandsResponseDTO lists the brands of a remote category
+type IRBrandsResponseDTO struct {
+	InfraredID string       `json:"infrared_id"`
+	CategoryID int          `json:"category_id"`
+	Brands     []IRBrandDTO `json:"brands"`
+}
+
+// IRRemoteIndexesResponseDTO lists the code libraries of a brand. Models of one brand often use different
+// libraries, so clients try the indexes in order until the appliance responds
+type IRRemoteIndexesResponseDTO struct {
+	InfraredID    string `json:"infrared_id"`
+	CategoryID    int    `json:"category_id"`
+	BrandID       int    `json:"brand_id"`
+	RemoteIndexes []int  `json:"remote_indexes"`
+}
+
+// AddIRRemoteRequestDTO adds a remote from a code library to an IR hub. BrandName is looked up when omitted
+type AddIRRemoteRequestDTO struct {
+	CategoryID  int    `json:"category_id" binding:"required,min=1"`
+	BrandID     int    `json:"brand_id" binding:"required,min=1"`
+	BrandName   string `json:"brand_name"`
+	RemoteIndex int    `json:"remote_index" binding:"required,min=1"`
+	RemoteName  string `json:"remote_name" binding:"required"`
+}
@@ -0,0 +1,68 @@
+package dtos
+
+// CreateLightGroupRequestDTO creates a light group from existing light devices
+type CreateLightGroupRequestDTO struct {
+	Name      string   `json:"name" binding:"required"`
+	DeviceIDs []string `json:"device_ids" binding:"required,min=1"`
+}
+
+// LightGroupDTO is a light group with its built-in and custom presets
+type LightGroupDTO struct {
+	ID        string           `json:"id"`
+	Name      string           `json:"name"`
+	DeviceIDs []string         `json:"device_ids"`
+	Presets   []LightPresetDTO `json:"presets"`
+	CreatedAt int64            `json:"created_at"`
+}
+
+// LightPresetDTO describes a preset of a light group
+type LightPresetDTO struct {
+	Name         string                      `json:"name"`
+	BuiltIn      bool                        `json:"built_in"`
+	Settings     LightSettingsDTO            `json:"settings"`
+	Devices      map[string]LightSettingsDTO `json:"devices,omitempty"`
+	TransitionMs int                         `json:"transition_ms,omitempty"`
+}
+
+// LightSettingsDTO holds normalized light settings.
+// Brightness and temperature are percentages (temperature 0 = warmest); colour switches the light to colour mode.
+type LightSettingsDTO struct {
+	Brightness  int             `json:"brightness" binding:"required,min=1,max=100"`
+	Temperature *int            `json:"temperature,omitempty" binding:"omitempty,min=0,max=100"`
+	Colour      *LightColourDTO `json:"colour,omitempty"`
+}
+
+// LightColourDTO is a hue (0-360) and saturation (0-100) pair
+type LightColourDTO struct {
+	Hue        int `json:"hue" binding:"min=0,max=360"`
+	Saturation int `json:"saturation" binding:"min=0,max=100"`
+}
+
+// SaveLightPresetRequestDTO creates or replaces a custom preset
+type SaveLightPresetRequestDTO struct {
+	Settings     LightSettingsDTO `json:"settings" binding:"required"`
+	TransitionMs int              `json:"transition_ms,omitempty" binding:"min=0"`
+}
+
+// ApplyLightPresetRequestDTO applies a preset, optionally overriding its transition time
+type ApplyLightPresetRequestDTO struct {
+	TransitionMs *int `json:"transition_ms,omitempty" binding:"omitempty,min=0"`
+}
+
+// ApplyLightPresetResponseDTO reports how a preset was applied to each light
+type ApplyLightPresetResponseDTO struct {
+	GroupID string                       `json:"group_id"`
+	Preset  string                       `json:"preset"`
+	Success bool                         `json:"success"`
+	Devices []LightPresetDeviceResultDTO `json:"devices"`
+}
+
+// LightPresetDeviceResultDTO is the outcome of applying a preset to one light.
+// Transition is true when the device exposes a transition DP and the fade was delegated to it.
+type LightPresetDeviceResultDTO struct {
+	DeviceID   string           `json:"device_id"`
+	Success    bool             `json:"success"`
+	Transition bool             `json:"transition"`
+	Commands   []TuyaCommandDTO `json:"commands,omitempty"`
+	Error      string           `json:"error,omitempty"`
+}
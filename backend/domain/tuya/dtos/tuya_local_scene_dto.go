@@ -0,0 +1,59 @@
+package dtos
+
+// LocalSceneDTO is a whole-house scene stored by Teralux
+type LocalSceneDTO struct {
+	ID                string              `json:"id"`
+	Name              string              `json:"name"`
+	RollbackOnFailure bool                `json:"rollback_on_failure"`
+	Steps             []LocalSceneStepDTO `json:"steps"`
+	CreatedAt         int64               `json:"created_at"`
+	UpdatedAt         int64               `json:"updated_at"`
+}
+
+// LocalSceneStepDTO sends either Commands or the named Preset of a device, DelaySeconds (0-3600) after the previous step
+type LocalSceneStepDTO struct {
+	DeviceID     string             `json:"device_id" binding:"required"`
+	Preset       string             `json:"preset,omitempty"`
+	Commands     []PresetCommandDTO `json:"commands,omitempty" binding:"dive"`
+	DelaySeconds int                `json:"delay_seconds,omitempty" binding:"min=0,max=3600"`
+}
+
+// SaveLocalSceneRequestDTO creates or replaces a scene.
+// With RollbackOnFailure, a failed step stops the scene and the steps already done are reverted;
+// otherwise the remaining steps still run.
+type SaveLocalSceneRequestDTO struct {
+	Name              string              `json:"name" binding:"required"`
+	RollbackOnFailure bool                `json:"rollback_on_failure"`
+	Steps             []LocalSceneStepDTO `json:"steps" binding:"required,min=1,max=50,dive"`
+}
+
+// LocalSceneRunStartedDTO identifies the job running a scene; poll GET /api/jobs/{job_id} for the step results
+type LocalSceneRunStartedDTO struct {
+	JobID   string `json:"job_id"`
+	Status  string `json:"status"`
+	SceneID string `json:"scene_id"`
+	Steps   int    `json:"steps"`
+}
+
+// LocalSceneRunResultDTO is the outcome of a scene run, stored as the job result
+type LocalSceneRunResultDTO struct {
+	SceneID    string                    `json:"scene_id"`
+	Name       string                    `json:"name"`
+	Succeeded  int                       `json:"succeeded"`
+	Failed     int                       `json:"failed"`
+	Skipped    int                       `json:"skipped"`
+	RolledBack bool                      `json:"rolled_back"`
+	Steps      []LocalSceneStepResultDTO `json:"steps"`
+}
+
+// LocalSceneStepResultDTO is the outcome of one step.
+// Status is "succeeded", "failed", "skipped" (after a failure with rollback) or "rolled_back".
+type LocalSceneStepResultDTO struct {
+	Step          int              `json:"step"`
+	DeviceID      string           `json:"device_id"`
+	Preset        string           `json:"preset,omitempty"`
+	Commands      []TuyaCommandDTO `json:"commands,omitempty"`
+	Status        string           `json:"status"`
+	Error         string           `json:"error,omitempty"`
+	RollbackError string           `json:"rollback_error,omitempty"`
+}
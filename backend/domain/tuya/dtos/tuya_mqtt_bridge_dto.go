@@ -0,0 +1,16 @@
+package dtos
+
+// MQTTBridgeStatusDTO reports the state of the Home Assistant MQTT bridge
+type MQTTBridgeStatusDTO struct {
+	Enabled         bool   `json:"enabled"`
+	Connected       bool   `json:"connected"`
+	Broker          string `json:"broker,omitempty"`
+	DiscoveryPrefix string `json:"discovery_prefix,omitempty"`
+	BaseTopic       string `json:"base_topic,omitempty"`
+	Devices         int    `json:"devices"`
+	Entities        int    `json:"entities"`
+	ConnectedAt     int64  `json:"connected_at,omitempty"`
+	LastSyncAt      int64  `json:"last_sync_at,omitempty"`
+	LastCommandAt   int64  `json:"last_command_at,omitempty"`
+	LastError       string `json:"last_error,omitempty"`
+}
@@ -0,0 +1,19 @@
+package dtos
+
+// PermissionReportDTO reports which Tuya API families the cloud project can call
+type PermissionReportDTO struct {
+	CheckedAt int64                `json:"checked_at"`
+	Healthy   bool                 `json:"healthy"`
+	Checks    []PermissionCheckDTO `json:"checks"`
+}
+
+// PermissionCheckDTO is the outcome of probing one Tuya API family
+type PermissionCheckDTO struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Endpoint    string `json:"endpoint"`
+	Status      string `json:"status"`
+	TuyaCode    int    `json:"tuya_code,omitempty"`
+	Message     string `json:"message,omitempty"`
+	Hint        string `json:"hint,omitempty"`
+}
@@ -0,0 +1,20 @@
+package dtos
+
+// QuotaUsageDTO reports Tuya API calls of one day against the configured budgets
+type QuotaUsageDTO struct {
+	Date           string             `json:"date"`
+	TotalCalls     int64              `json:"total_calls"`
+	DailyBudget    int64              `json:"daily_budget,omitempty"`
+	ProjectedTotal int64              `json:"projected_total"`
+	Status         string             `json:"status"`
+	Endpoints      []QuotaEndpointDTO `json:"endpoints"`
+}
+
+// QuotaEndpointDTO reports the calls made to one Tuya endpoint
+type QuotaEndpointDTO struct {
+	Endpoint  string `json:"endpoint"`
+	Calls     int64  `json:"calls"`
+	Budget    int64  `json:"budget,omitempty"`
+	Projected int64  `json:"projected"`
+	Status    string `json:"status"`
+}
@@ -0,0 +1,46 @@
+package dtos
+
+// StartRolloutRequestDTO sends the same commands to many devices in waves.
+// The first wave (CanarySize devices, default WaveSize) acts as the canary; the rollout aborts once the share of
+// failed devices exceeds MaxErrorRate (0-1, default 0.2).
+type StartRolloutRequestDTO struct {
+	DeviceIDs        []string         `json:"device_ids" binding:"required,min=1"`
+	Commands         []TuyaCommandDTO `json:"commands" binding:"required,min=1,dive"`
+	WaveSize         int              `json:"wave_size,omitempty" binding:"min=0"`
+	CanarySize       int              `json:"canary_size,omitempty" binding:"min=0"`
+	WaveDelaySeconds int              `json:"wave_delay_seconds,omitempty" binding:"min=0"`
+	MaxErrorRate     *float64         `json:"max_error_rate,omitempty" binding:"omitempty,min=0,max=1"`
+}
+
+// RolloutStartedDTO identifies the job executing a rollout; poll GET /api/jobs/{job_id} for progress
+type RolloutStartedDTO struct {
+	JobID        string  `json:"job_id"`
+	Status       string  `json:"status"`
+	Devices      int     `json:"devices"`
+	Waves        int     `json:"waves"`
+	WaveSize     int     `json:"wave_size"`
+	CanarySize   int     `json:"canary_size"`
+	MaxErrorRate float64 `json:"max_error_rate"`
+}
+
+// RolloutResultDTO is the outcome of a rollout, stored as the job result
+type RolloutResultDTO struct {
+	Total          int                      `json:"total"`
+	Succeeded      int                      `json:"succeeded"`
+	Failed         int                      `json:"failed"`
+	Skipped        int                      `json:"skipped"`
+	Waves          int                      `json:"waves"`
+	WavesCompleted int                      `json:"waves_completed"`
+	ErrorRate      float64                  `json:"error_rate"`
+	Aborted        bool                     `json:"aborted"`
+	AbortReason    string                   `json:"abort_reason,omitempty"`
+	Devices        []RolloutDeviceResultDTO `json:"devices"`
+}
+
+// RolloutDeviceResultDTO is the outcome of a rollout on one device
+type RolloutDeviceResultDTO struct {
+	DeviceID string `json:"device_id"`
+	Wave     int    `json:"wave"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
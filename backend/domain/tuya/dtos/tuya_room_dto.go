@@ -0,0 +1,41 @@
+package dtos
+
+// CreateRoomRequestDTO creates a room, optionally with its devices
+type CreateRoomRequestDTO struct {
+	Name      string   `json:"name" binding:"required,max=100"`
+	DeviceIDs []string `json:"device_ids"`
+}
+
+// UpdateRoomRequestDTO renames a room and/or replaces its devices; omitted fields are left unchanged
+type UpdateRoomRequestDTO struct {
+	Name      *string   `json:"name,omitempty" binding:"omitempty,min=1,max=100"`
+	DeviceIDs *[]string `json:"device_ids,omitempty"`
+}
+
+// RoomDTO is a room with the IDs of its devices
+type RoomDTO struct {
+	ID        string   `json:"id"`
+	Name      string   `json:"name"`
+	DeviceIDs []string `json:"device_ids"`
+	CreatedAt int64    `json:"created_at"`
+	UpdatedAt int64    `json:"updated_at"`
+}
+
+// RoomCommandRequestDTO sends the same commands to every device of a room
+type RoomCommandRequestDTO struct {
+	Commands []TuyaCommandDTO `json:"commands" binding:"required,min=1"`
+}
+
+// RoomCommandResponseDTO reports how a room command was applied to each device
+type RoomCommandResponseDTO struct {
+	RoomID  string                `json:"room_id"`
+	Success bool                  `json:"success"`
+	Devices []RoomDeviceResultDTO `json:"devices"`
+}
+
+// RoomDeviceResultDTO is the outcome of a room command on one device
+type RoomDeviceResultDTO struct {
+	DeviceID string `json:"device_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,42 @@
+package dtos
+
+// SceneSwitchBindingDTO maps a button and press type to an action
+type SceneSwitchBindingDTO struct {
+	Button    int                  `json:"button" binding:"required,min=1"`
+	PressType string               `json:"press_type" binding:"required"`
+	Action    SceneSwitchActionDTO `json:"action" binding:"required"`
+}
+
+// SceneSwitchActionDTO describes the action of a binding.
+// Type "device_commands" requires device_id and commands; "scene" and "automation" require target_id.
+type SceneSwitchActionDTO struct {
+	Type     string           `json:"type" binding:"required"`
+	TargetID string           `json:"target_id,omitempty"`
+	DeviceID string           `json:"device_id,omitempty"`
+	Commands []TuyaCommandDTO `json:"commands,omitempty"`
+}
+
+// SceneSwitchBindingsRequestDTO replaces all bindings of a scene switch
+type SceneSwitchBindingsRequestDTO struct {
+	Bindings []SceneSwitchBindingDTO `json:"bindings" binding:"dive"`
+}
+
+// SceneSwitchBindingsResponseDTO lists the bindings of a scene switch
+type SceneSwitchBindingsResponseDTO struct {
+	SwitchID string                  `json:"switch_id"`
+	Bindings []SceneSwitchBindingDTO `json:"bindings"`
+}
+
+// SceneSwitchEventDTO is a raw button event reported by a scene switch (e.g., code "switch1_value", value "single_click")
+type SceneSwitchEventDTO struct {
+	Code  string      `json:"code" binding:"required"`
+	Value interface{} `json:"value" binding:"required"`
+}
+
+// SceneSwitchEventResultDTO reports whether an event matched a binding and ran its action
+type SceneSwitchEventResultDTO struct {
+	Matched   bool   `json:"matched"`
+	Button    int    `json:"button,omitempty"`
+	PressType string `json:"press_type,omitempty"`
+	Action    string `json:"action,omitempty"`
+}
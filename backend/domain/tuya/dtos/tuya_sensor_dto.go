@@ -2,9 +2,58 @@ package dtos
 
 // SensorDataDTO represents the formatted sensor data
 type SensorDataDTO struct {
-	Temperature       float64 `json:"temperature"`
-	Humidity          int     `json:"humidity"`
-	BatteryPercentage int     `json:"battery_percentage"`
-	StatusText        string  `json:"status_text"`
-	TempUnit          string  `json:"temp_unit"`
-}
\ No newline at end of file
+	Temperature       float64         `json:"temperature"`
+	Humidity          int             `json:"humidity"`
+	BatteryPercentage int             `json:"battery_percentage"`
+	StatusText        string          `json:"status_text"`
+	TempUnit          string          `json:"temp_unit"`
+	Category          string          `json:"category,omitempty"`
+	Alarm             *SensorAlarmDTO `json:"alarm,omitempty"`
+}
+
+// SensorAlarmDTO represents the alarm state of a water leak (sj) or smoke (ywbj) sensor
+type SensorAlarmDTO struct {
+	Type   string      `json:"type"`
+	Active bool        `json:"active"`
+	Code   string      `json:"code"`
+	Value  interface{} `json:"value"`
+}
+
+// AlarmTestResponseDTO reports the simulated alarm event that was published
+type AlarmTestResponseDTO struct {
+	DeviceID string `json:"device_id"`
+	Type     string `json:"type"`
+}
+
+// SensorHistoryDTO is an aggregated time series of sensor readings
+type SensorHistoryDTO struct {
+	DeviceID string                  `json:"device_id"`
+	From     int64                   `json:"from"`
+	To       int64                   `json:"to"`
+	Interval int64                   `json:"interval"`
+	Timezone string                  `json:"timezone"`
+	Points   []SensorHistoryPointDTO `json:"points"`
+}
+
+// SensorHistoryPointDTO aggregates the readings of one interval starting at Timestamp
+type SensorHistoryPointDTO struct {
+	Timestamp   int64           `json:"timestamp"`
+	Temperature *SensorStatsDTO `json:"temperature,omitempty"`
+	Humidity    *SensorStatsDTO `json:"humidity,omitempty"`
+	Battery     *SensorStatsDTO `json:"battery,omitempty"`
+}
+
+// SensorStatsDTO summarizes the samples of one metric within an interval
+type SensorStatsDTO struct {
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+	Avg     float64 `json:"avg"`
+	Last    float64 `json:"last"`
+	Samples int64   `json:"samples"`
+}
+
+// SensorChartDTO is a rendered sensor chart image
+type SensorChartDTO struct {
+	ContentType string
+	Image       []byte
+}
@@ -0,0 +1,24 @@
+package dtos
+
+// StandbyKillerRuleRequestDTO configures the standby killer of a metered plug.
+// switch_code selects the channel to switch off on multi-outlet plugs (defaults to the first switch).
+type StandbyKillerRuleRequestDTO struct {
+	Enabled         *bool   `json:"enabled,omitempty"`
+	ThresholdWatts  float64 `json:"threshold_watts" binding:"required,gt=0"`
+	DurationMinutes int     `json:"duration_minutes" binding:"required,min=1,max=1440"`
+	SwitchCode      string  `json:"switch_code,omitempty"`
+}
+
+// StandbyKillerRuleDTO is a standby killer rule with its current tracking state
+type StandbyKillerRuleDTO struct {
+	DeviceID        string  `json:"device_id"`
+	Enabled         bool    `json:"enabled"`
+	ThresholdWatts  float64 `json:"threshold_watts"`
+	DurationMinutes int     `json:"duration_minutes"`
+	SwitchCode      string  `json:"switch_code"`
+	CreatedAt       int64   `json:"created_at"`
+	BelowSince      int64   `json:"below_since,omitempty"`
+	LastWatts       float64 `json:"last_watts"`
+	LastCheckedAt   int64   `json:"last_checked_at,omitempty"`
+	LastKilledAt    int64   `json:"last_killed_at,omitempty"`
+}
@@ -0,0 +1,15 @@
+package dtos
+
+// GenerateSwaggerExamplesRequestDTO represents the request body for generating Swagger examples
+type GenerateSwaggerExamplesRequestDTO struct {
+	DeviceID string `json:"device_id" binding:"required"`
+}
+
+// SwaggerExamplesDTO represents the control payload examples derived from a device specification
+type SwaggerExamplesDTO struct {
+	DeviceID    string           `json:"device_id"`
+	DeviceName  string           `json:"device_name"`
+	Category    string           `json:"category"`
+	Commands    []TuyaCommandDTO `json:"commands"`
+	GeneratedAt int64            `json:"generated_at"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+// AuditLogEntry records a control action sent to a device.
+type AuditLogEntry struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Action    string `json:"action"`
+	DeviceID  string `json:"device_id"`
+	Detail    string `json:"detail"`
+	Success   bool   `json:"success"`
+	Error     string `json:"error,omitempty"`
+}
@@ -0,0 +1,52 @@
+package entities
+
+import "teralux_app/domain/common/utils"
+
+// AutomationRule runs device commands when all of its conditions become true.
+// Rules fire on the transition from not matching to matching, at most once per cooldown.
+type AutomationRule struct {
+	ID              string                `json:"id"`
+	Name            string                `json:"name"`
+	Enabled         bool                  `json:"enabled"`
+	Conditions      []AutomationCondition `json:"conditions"`
+	Actions         []AutomationAction    `json:"actions"`
+	CooldownSeconds int                   `json:"cooldown_seconds,omitempty"`
+	CreatedAt       int64                 `json:"created_at"`
+	UpdatedAt       int64                 `json:"updated_at"`
+	LastTriggeredAt int64                 `json:"last_triggered_at,omitempty"`
+	// Owner is the API key or user that last saved the rule; actions only run on the devices it may control.
+	Owner utils.APIKeyIdentity `json:"owner"`
+}
+
+// AutomationCondition compares a reported status value of a device (e.g., va_temperature > 300),
+// or the house mode when Code is "house_mode" and DeviceID is empty
+type AutomationCondition struct {
+	DeviceID string      `json:"device_id,omitempty"`
+	Code     string      `json:"code"`
+	Operator string      `json:"operator"`
+	Value    interface{} `json:"value"`
+}
+
+// AutomationAction sends commands to a device when a rule fires
+type AutomationAction struct {
+	DeviceID string        `json:"device_id"`
+	Commands []TuyaCommand `json:"commands"`
+}
+
+// AutomationRun records one execution of a rule
+type AutomationRun struct {
+	TriggeredAt int64                    `json:"triggered_at"`
+	Trigger     string                   `json:"trigger"`
+	DeviceID    string                   `json:"device_id,omitempty"`
+	Code        string                   `json:"code,omitempty"`
+	Value       interface{}              `json:"value,omitempty"`
+	Success     bool                     `json:"success"`
+	Results     []AutomationActionResult `json:"results"`
+}
+
+// AutomationActionResult is the outcome of one action of a run
+type AutomationActionResult struct {
+	DeviceID string `json:"device_id"`
+	Success  bool   `json:"success"`
+	Error    string `json:"error,omitempty"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+// CircadianConfig is the adaptive lighting configuration.
+// Curve points are interpolated linearly over the day; lights may override the curve individually.
+// Curve times are wall-clock times in Timezone, or in the deployment TIMEZONE when empty.
+type CircadianConfig struct {
+	Enabled  bool             `json:"enabled"`
+	Timezone string           `json:"timezone,omitempty"`
+	Curve    []CircadianPoint `json:"curve"`
+	Lights   []CircadianLight `json:"lights"`
+}
+
+// CircadianPoint is the target brightness and colour temperature (percentages, 0 = warmest) at a time of day ("HH:MM")
+type CircadianPoint struct {
+	Time        string `json:"time"`
+	Brightness  int    `json:"brightness"`
+	Temperature int    `json:"temperature"`
+}
+
+// CircadianLight enrols a light in adaptive lighting.
+// Curve replaces the global curve for this light; TemperatureOnly leaves brightness untouched.
+type CircadianLight struct {
+	DeviceID        string           `json:"device_id"`
+	Curve           []CircadianPoint `json:"curve,omitempty"`
+	TemperatureOnly bool             `json:"temperature_only,omitempty"`
+}
+
+// CircadianLightState tracks what the dispatcher last sent to a light, used to detect manual changes
+type CircadianLightState struct {
+	Brightness      *int  `json:"brightness,omitempty"`
+	Temperature     *int  `json:"temperature,omitempty"`
+	LastDispatchAt  int64 `json:"last_dispatch_at"`
+	OverriddenUntil int64 `json:"overridden_until,omitempty"`
+}
@@ -0,0 +1,34 @@
+package entities
+
+// Pending action statuses.
+const (
+	PendingActionPending  = "pending"
+	PendingActionExecuted = "executed"
+	PendingActionFailed   = "failed"
+	PendingActionRejected = "rejected"
+	PendingActionExpired  = "expired"
+)
+
+// CommandApprovalRule marks a device, or some of its DP codes, as requiring two-person approval.
+// An empty Codes list protects every command of the device.
+type CommandApprovalRule struct {
+	DeviceID   string   `json:"device_id"`
+	Codes      []string `json:"codes,omitempty"`
+	TTLSeconds int64    `json:"ttl_seconds"`
+	CreatedAt  int64    `json:"created_at"`
+}
+
+// PendingAction is a protected command held back until a second user approves it.
+type PendingAction struct {
+	ID          string        `json:"id"`
+	DeviceID    string        `json:"device_id"`
+	Commands    []TuyaCommand `json:"commands"`
+	RequestedBy string        `json:"requested_by"`
+	RequestedAt int64         `json:"requested_at"`
+	ExpiresAt   int64         `json:"expires_at"`
+	Status      string        `json:"status"`
+	DecidedBy   string        `json:"decided_by,omitempty"`
+	DecidedAt   int64         `json:"decided_at,omitempty"`
+	Reason      string        `json:"reason,omitempty"`
+	Error       string        `json:"error,omitempty"`
+}
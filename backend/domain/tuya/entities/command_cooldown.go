@@ -0,0 +1,12 @@
+package entities
+
+// CommandCooldown is the minimum gap learned between two commands to the same device (or IR hub).
+type CommandCooldown struct {
+	DeviceID       string `json:"device_id"`
+	CooldownMs     int64  `json:"cooldown_ms"`
+	RapidFailures  int    `json:"rapid_failures"`
+	LastFailureAt  int64  `json:"last_failure_at,omitempty"`
+	LastFailure    string `json:"last_failure,omitempty"`
+	LastFailureGap int64  `json:"last_failure_gap_ms,omitempty"`
+	UpdatedAt      int64  `json:"updated_at"`
+}
@@ -0,0 +1,8 @@
+package entities
+
+// CommandDedupResult is the outcome of a command shared with identical commands arriving within the
+// deduplication window. Only successful outcomes are kept, so a failed command can be retried right away.
+type CommandDedupResult struct {
+	Result     []byte `json:"result"`
+	ExecutedAt int64  `json:"executed_at"` // Unix milliseconds
+}
@@ -0,0 +1,9 @@
+package entities
+
+// DeviceCategoryFilter decides which Tuya device categories are listed to clients.
+// Denied categories are never listed; when Allow is not empty, only the allowed categories are listed.
+type DeviceCategoryFilter struct {
+	Allow     []string `json:"allow,omitempty"`
+	Deny      []string `json:"deny,omitempty"`
+	UpdatedAt int64    `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+// DeviceSnapshotEntry is the minimal device identity kept between list refreshes for diffing.
+type DeviceSnapshotEntry struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category"`
+}
+
+// DeviceChangeLogEntry records the differences detected by a single device list refresh.
+type DeviceChangeLogEntry struct {
+	DetectedAt int64          `json:"detected_at"`
+	Changes    []DeviceChange `json:"changes"`
+}
+
+// DeviceChange describes one device that was added, removed, renamed or re-categorized.
+type DeviceChange struct {
+	Type     string `json:"type"`
+	DeviceID string `json:"device_id"`
+	Name     string `json:"name"`
+	OldValue string `json:"old_value,omitempty"`
+	NewValue string `json:"new_value,omitempty"`
+}
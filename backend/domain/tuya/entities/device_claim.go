@@ -0,0 +1,21 @@
+package entities
+
+// Device claim statuses.
+const (
+	DeviceClaimPending  = "pending"
+	DeviceClaimApproved = "approved"
+	DeviceClaimRejected = "rejected"
+)
+
+// DeviceClaim is a tenant's request to be assigned a device. An admin approves or rejects it;
+// approved claims make the tenant the device's owner.
+type DeviceClaim struct {
+	ID        string `json:"id"`
+	DeviceID  string `json:"device_id"`
+	UID       string `json:"uid"`
+	Note      string `json:"note,omitempty"`
+	Status    string `json:"status"`
+	Reason    string `json:"reason,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+	DecidedAt int64  `json:"decided_at,omitempty"`
+}
@@ -0,0 +1,23 @@
+package entities
+
+// DeviceMacro is a named, parameterized sequence of commands stored for one device
+// (e.g., set_ac(temp) expanding to power on, mode cool and the target temperature)
+type DeviceMacro struct {
+	DeviceID   string           `json:"device_id"`
+	Name       string           `json:"name"`
+	Parameters []MacroParameter `json:"parameters,omitempty"`
+	Commands   []TuyaCommand    `json:"commands"`
+	CreatedAt  int64            `json:"created_at"`
+	UpdatedAt  int64            `json:"updated_at"`
+}
+
+// MacroParameter declares an argument of a macro. Command values reference it as "${name}".
+// Parameters without a default are required when the macro is invoked.
+type MacroParameter struct {
+	Name    string      `json:"name"`
+	Type    string      `json:"type"`
+	Default interface{} `json:"default,omitempty"`
+	Min     *float64    `json:"min,omitempty"`
+	Max     *float64    `json:"max,omitempty"`
+	Options []string    `json:"options,omitempty"`
+}
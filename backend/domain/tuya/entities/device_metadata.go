@@ -0,0 +1,11 @@
+package entities
+
+// DeviceMetadata is local metadata of a device that Tuya does not store: a custom label and icon,
+// a sort order and a favorite flag. It is shared by every user of the deployment.
+type DeviceMetadata struct {
+	Label     string `json:"label,omitempty"`
+	Icon      string `json:"icon,omitempty"`
+	SortOrder *int   `json:"sort_order,omitempty"`
+	Favorite  bool   `json:"favorite,omitempty"`
+	UpdatedAt int64  `json:"updated_at"`
+}
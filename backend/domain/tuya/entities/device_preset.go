@@ -0,0 +1,11 @@
+package entities
+
+// DevicePreset is a named bundle of commands stored for one device (e.g., "AC Night Mode" = power on,
+// temperature 26, fan speed 1), sent to the device together when the preset is executed
+type DevicePreset struct {
+	DeviceID  string        `json:"device_id"`
+	Name      string        `json:"name"`
+	Commands  []TuyaCommand `json:"commands"`
+	CreatedAt int64         `json:"created_at"`
+	UpdatedAt int64         `json:"updated_at"`
+}
@@ -0,0 +1,16 @@
+package entities
+
+// DeviceTimer is a pending delayed on/off command for one switch of a device. Native timers run on the
+// device through its countdown DP; job timers are server-side delayed jobs
+type DeviceTimer struct {
+	ID            string `json:"id"`
+	DeviceID      string `json:"device_id"`
+	Code          string `json:"code"`
+	Action        string `json:"action"`
+	Mode          string `json:"mode"`
+	CountdownCode string `json:"countdown_code,omitempty"`
+	JobID         string `json:"job_id,omitempty"`
+	DelaySeconds  int    `json:"delay_seconds"`
+	CreatedAt     int64  `json:"created_at"`
+	FiresAt       int64  `json:"fires_at"`
+}
@@ -0,0 +1,12 @@
+package entities
+
+// FeatureFlag controls an experimental code path per device.
+// A device is enabled if it is listed in Devices, or if it is not listed in ExcludedDevices and falls
+// within the rollout Percentage.
+type FeatureFlag struct {
+	Name            string   `json:"name"`
+	Percentage      int      `json:"percentage"`
+	Devices         []string `json:"devices,omitempty"`
+	ExcludedDevices []string `json:"excluded_devices,omitempty"`
+	UpdatedAt       int64    `json:"updated_at,omitempty"`
+}
@@ -0,0 +1,8 @@
+package entities
+
+// HouseMode is the persisted mode of the whole house (home, away, sleep or holiday)
+type HouseMode struct {
+	Mode         string `json:"mode"`
+	PreviousMode string `json:"previous_mode,omitempty"`
+	ChangedAt    int64  `json:"changed_at"`
+}
@@ -0,0 +1,11 @@
+package entities
+
+// IRLearnedCode is a raw IR code captured by an IR hub and saved under a user-defined name, for
+// appliances without a standard remote index
+type IRLearnedCode struct {
+	InfraredID string `json:"infrared_id"`
+	Name       string `json:"name"`
+	Code       string `json:"code"`
+	CreatedAt  int64  `json:"created_at"`
+	UpdatedAt  int64  `json:"updated_at"`
+}
@@ -0,0 +1,33 @@
+package entities
+
+// LightGroup is a named set of lights that presets are applied to together
+type LightGroup struct {
+	ID        string        `json:"id"`
+	Name      string        `json:"name"`
+	DeviceIDs []string      `json:"device_ids"`
+	Presets   []LightPreset `json:"presets"`
+	CreatedAt int64         `json:"created_at"`
+}
+
+// LightPreset captures brightness and colour settings for a light group.
+// Settings apply to every light unless Devices holds captured per-device settings.
+type LightPreset struct {
+	Name         string                   `json:"name"`
+	Settings     LightSettings            `json:"settings"`
+	Devices      map[string]LightSettings `json:"devices,omitempty"`
+	TransitionMs int                      `json:"transition_ms,omitempty"`
+}
+
+// LightSettings holds normalized light settings.
+// Brightness and Temperature are percentages (Temperature 0 = warmest); Colour switches the light to colour mode.
+type LightSettings struct {
+	Brightness  int          `json:"brightness"`
+	Temperature *int         `json:"temperature,omitempty"`
+	Colour      *LightColour `json:"colour,omitempty"`
+}
+
+// LightColour is a hue (0-360) and saturation (0-100) pair
+type LightColour struct {
+	Hue        int `json:"hue"`
+	Saturation int `json:"saturation"`
+}
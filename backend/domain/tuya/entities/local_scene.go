@@ -0,0 +1,20 @@
+package entities
+
+// LocalScene is a whole-house scene stored by Teralux (not a Tuya cloud scene): ordered steps, each sending
+// commands or a device preset to one device, optionally after a delay
+type LocalScene struct {
+	ID                string           `json:"id"`
+	Name              string           `json:"name"`
+	RollbackOnFailure bool             `json:"rollback_on_failure"`
+	Steps             []LocalSceneStep `json:"steps"`
+	CreatedAt         int64            `json:"created_at"`
+	UpdatedAt         int64            `json:"updated_at"`
+}
+
+// LocalSceneStep sends either the commands or the named preset of a device, DelaySeconds after the previous step
+type LocalSceneStep struct {
+	DeviceID     string        `json:"device_id"`
+	Preset       string        `json:"preset,omitempty"`
+	Commands     []TuyaCommand `json:"commands,omitempty"`
+	DelaySeconds int           `json:"delay_seconds,omitempty"`
+}
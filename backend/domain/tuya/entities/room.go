@@ -0,0 +1,27 @@
+package entities
+
+// Room is a named group of devices, such as the devices of one physical room.
+// Rooms are stored in the SQL database.
+type Room struct {
+	ID        string       `gorm:"primaryKey;size:32" json:"id"`
+	Name      string       `gorm:"size:100;not null;uniqueIndex" json:"name"`
+	Devices   []RoomDevice `gorm:"foreignKey:RoomID;constraint:OnDelete:CASCADE" json:"devices"`
+	CreatedAt int64        `gorm:"autoCreateTime:false" json:"created_at"`
+	UpdatedAt int64        `gorm:"autoUpdateTime:false" json:"updated_at"`
+}
+
+// TableName overrides the table name used by GORM.
+func (Room) TableName() string {
+	return "rooms"
+}
+
+// RoomDevice assigns a device to a room. A device may belong to several rooms.
+type RoomDevice struct {
+	RoomID   string `gorm:"primaryKey;size:32" json:"room_id"`
+	DeviceID string `gorm:"primaryKey;size:64;index" json:"device_id"`
+}
+
+// TableName overrides the table name used by GORM.
+func (RoomDevice) TableName() string {
+	return "room_devices"
+}
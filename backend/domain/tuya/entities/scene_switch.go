@@ -0,0 +1,22 @@
+package entities
+
+import "teralux_app/domain/common/utils"
+
+// SceneSwitchBinding maps one button and press type of a wireless scene switch (wxkg) to an action
+type SceneSwitchBinding struct {
+	Button    int               `json:"button"`
+	PressType string            `json:"press_type"`
+	Action    SceneSwitchAction `json:"action"`
+	// Owner is the API key or user that saved the binding; the action runs with its permissions.
+	Owner utils.APIKeyIdentity `json:"owner"`
+}
+
+// SceneSwitchAction describes what runs when a bound button is pressed.
+// "device_commands" sends Commands to DeviceID; other types (e.g., "scene", "automation") run TargetID
+// through the handler registered for that type.
+type SceneSwitchAction struct {
+	Type     string        `json:"type"`
+	TargetID string        `json:"target_id,omitempty"`
+	DeviceID string        `json:"device_id,omitempty"`
+	Commands []TuyaCommand `json:"commands,omitempty"`
+}
@@ -0,0 +1,17 @@
+package entities
+
+// SensorHistoryBucket is the hourly rollup of the numeric values reported by a sensor.
+type SensorHistoryBucket struct {
+	DeviceID string                   `json:"device_id"`
+	Hour     int64                    `json:"hour"`
+	Codes    map[string]*SensorRollup `json:"codes"`
+}
+
+// SensorRollup aggregates the readings of one status code within a bucket.
+type SensorRollup struct {
+	Count int64   `json:"count"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Sum   float64 `json:"sum"`
+	Last  float64 `json:"last"`
+}
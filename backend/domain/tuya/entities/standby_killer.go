@@ -0,0 +1,20 @@
+package entities
+
+// StandbyKillerRule switches a metered plug off when its power draw stays below ThresholdWatts
+// for DurationMinutes, cutting the standby consumption of TVs, consoles and similar devices
+type StandbyKillerRule struct {
+	DeviceID        string  `json:"device_id"`
+	Enabled         bool    `json:"enabled"`
+	ThresholdWatts  float64 `json:"threshold_watts"`
+	DurationMinutes int     `json:"duration_minutes"`
+	SwitchCode      string  `json:"switch_code"`
+	CreatedAt       int64   `json:"created_at"`
+}
+
+// StandbyKillerState tracks how long a plug has been below its threshold
+type StandbyKillerState struct {
+	BelowSince    int64   `json:"below_since,omitempty"`
+	LastWatts     float64 `json:"last_watts"`
+	LastCheckedAt int64   `json:"last_checked_at,omitempty"`
+	LastKilledAt  int64   `json:"last_killed_at,omitempty"`
+}
@@ -0,0 +1,11 @@
+package entities
+
+// SwaggerExampleSet holds control payload examples generated from a real device's specification.
+// It is persisted so the Swagger UI keeps showing valid examples across restarts.
+type SwaggerExampleSet struct {
+	DeviceID    string        `json:"device_id"`
+	DeviceName  string        `json:"device_name"`
+	Category    string        `json:"category"`
+	Commands    []TuyaCommand `json:"commands"`
+	GeneratedAt int64         `json:"generated_at"`
+}
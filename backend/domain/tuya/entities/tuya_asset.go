@@ -0,0 +1,49 @@
+package entities
+
+// TuyaAssetsResponse represents the response for listing the assets of an IoT Core project
+type TuyaAssetsResponse struct {
+	Result  TuyaAssetPage `json:"result"`
+	Success bool          `json:"success"`
+	T       int64         `json:"t"`
+	Tid     string        `json:"tid"`
+	Code    int           `json:"code"`
+	Msg     string        `json:"msg"`
+}
+
+// TuyaAssetPage holds the assets of a page
+type TuyaAssetPage struct {
+	List    []TuyaAsset `json:"list"`
+	Total   int         `json:"total"`
+	HasMore bool        `json:"has_more"`
+}
+
+// TuyaAsset represents an asset (a building, floor or room) of an IoT Core project
+type TuyaAsset struct {
+	AssetID       string `json:"asset_id"`
+	AssetName     string `json:"asset_name"`
+	ParentAssetID string `json:"parent_asset_id"`
+}
+
+// TuyaAssetDevicesResponse represents the response for listing the devices of an asset
+type TuyaAssetDevicesResponse struct {
+	Result  TuyaAssetDevicePage `json:"result"`
+	Success bool                `json:"success"`
+	T       int64               `json:"t"`
+	Tid     string              `json:"tid"`
+	Code    int                 `json:"code"`
+	Msg     string              `json:"msg"`
+}
+
+// TuyaAssetDevicePage holds the device IDs of a page and the key to request the next page with
+type TuyaAssetDevicePage struct {
+	List       []TuyaAssetDevice `json:"list"`
+	HasNext    bool              `json:"has_next"`
+	LastRowKey string            `json:"last_row_key"`
+	PageSize   int               `json:"page_size"`
+}
+
+// TuyaAssetDevice links a device to an asset
+type TuyaAssetDevice struct {
+	AssetID  string `json:"asset_id"`
+	DeviceID string `json:"device_id"`
+}
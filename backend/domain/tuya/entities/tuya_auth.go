@@ -16,4 +16,13 @@ type TuyaAuthResult struct {
 	ExpireTime   int    `json:"expire_time"`
 	RefreshToken string `json:"refresh_token"`
 	UID          string `json:"uid"`
-}
\ No newline at end of file
+}
+
+// TuyaServerToken is the server-managed Tuya token persisted in BadgerDB
+type TuyaServerToken struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	UID          string `json:"uid"`
+	ExpiresAt    int64  `json:"expires_at"`
+	UpdatedAt    int64  `json:"updated_at"`
+}
@@ -0,0 +1,20 @@
+package entities
+
+// TuyaStreamAllocateRequest is the body for allocating a camera stream
+type TuyaStreamAllocateRequest struct {
+	Type string `json:"type"`
+}
+
+// TuyaMediaURLResponse represents the response for allocating a camera stream or capturing a snapshot
+type TuyaMediaURLResponse struct {
+	Result  TuyaMediaURL `json:"result"`
+	Success bool         `json:"success"`
+	T       int64        `json:"t"`
+	Code    int          `json:"code"`
+	Msg     string       `json:"msg"`
+}
+
+// TuyaMediaURL is a time-limited URL of a camera stream or snapshot
+type TuyaMediaURL struct {
+	URL string `json:"url"`
+}
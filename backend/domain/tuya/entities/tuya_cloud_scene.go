@@ -0,0 +1,64 @@
+package entities
+
+// TuyaHomesResponse represents the response for listing the homes of a user
+type TuyaHomesResponse struct {
+	Result  []TuyaHome `json:"result"`
+	Success bool       `json:"success"`
+	T       int64      `json:"t"`
+	Code    int        `json:"code"`
+	Msg     string     `json:"msg"`
+}
+
+// TuyaHome represents a home (family) of the Smart Life app
+type TuyaHome struct {
+	HomeID  int64   `json:"home_id"`
+	Name    string  `json:"name"`
+	GeoName string  `json:"geo_name"`
+	Lat     float64 `json:"lat"`
+	Lon     float64 `json:"lon"`
+	Role    string  `json:"role"`
+}
+
+// TuyaCloudScenesResponse represents the response for listing the tap-to-run scenes of a home
+type TuyaCloudScenesResponse struct {
+	Result  []TuyaCloudScene `json:"result"`
+	Success bool             `json:"success"`
+	T       int64            `json:"t"`
+	Code    int              `json:"code"`
+	Msg     string           `json:"msg"`
+}
+
+// TuyaCloudScene represents a tap-to-run scene configured in the Smart Life app
+type TuyaCloudScene struct {
+	SceneID    string                 `json:"scene_id"`
+	Name       string                 `json:"name"`
+	Background string                 `json:"background"`
+	Status     string                 `json:"status"`
+	Actions    []TuyaCloudSceneAction `json:"actions"`
+}
+
+// TuyaCloudSceneAction represents one action of a cloud scene or automation
+type TuyaCloudSceneAction struct {
+	ActionExecutor   string                 `json:"action_executor"`
+	EntityID         string                 `json:"entity_id"`
+	ExecutorProperty map[string]interface{} `json:"executor_property"`
+}
+
+// TuyaCloudAutomationsResponse represents the response for listing the automations of a home
+type TuyaCloudAutomationsResponse struct {
+	Result  []TuyaCloudAutomation `json:"result"`
+	Success bool                  `json:"success"`
+	T       int64                 `json:"t"`
+	Code    int                   `json:"code"`
+	Msg     string                `json:"msg"`
+}
+
+// TuyaCloudAutomation represents an automation configured in the Smart Life app
+type TuyaCloudAutomation struct {
+	AutomationID string                 `json:"automation_id"`
+	Name         string                 `json:"name"`
+	Background   string                 `json:"background"`
+	Enabled      bool                   `json:"enabled"`
+	MatchType    int                    `json:"match_type"`
+	Actions      []TuyaCloudSceneAction `json:"actions"`
+}
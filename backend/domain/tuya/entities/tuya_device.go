@@ -69,6 +69,42 @@ type TuyaDeviceFunction struct {
 	Values string `json:"values"` // Changed from map to string because spec API returns JSON string
 }
 
+// TuyaFirmwareResponse represents the firmware modules of a device (GET /v1.0/devices/{device_id}/upgrade-infos)
+type TuyaFirmwareResponse struct {
+	Result  []TuyaFirmwareModule `json:"result"`
+	Success bool                 `json:"success"`
+	T       int64                `json:"t"`
+	Code    int                  `json:"code"`
+	Msg     string               `json:"msg"`
+}
+
+// TuyaFirmwareModule is one firmware module of a device (e.g., the Wi-Fi module or the MCU)
+type TuyaFirmwareModule struct {
+	Type           int    `json:"type"`
+	TypeDesc       string `json:"type_desc"`
+	CurrentVersion string `json:"current_version"`
+	Version        string `json:"version"` // latest available version
+	UpgradeStatus  int    `json:"upgrade_status"`
+}
+
+// TuyaDevicePageResponse represents one page of the project device list (GET /v1.0/iot-03/devices)
+type TuyaDevicePageResponse struct {
+	Result  TuyaDevicePage `json:"result"`
+	Success bool           `json:"success"`
+	T       int64          `json:"t"`
+	Tid     string         `json:"tid"`
+	Code    int            `json:"code"`
+	Msg     string         `json:"msg"`
+}
+
+// TuyaDevicePage holds the devices of a page and the key to request the next page with
+type TuyaDevicePage struct {
+	List       []TuyaDevice `json:"list"`
+	Total      int          `json:"total"`
+	HasMore    bool         `json:"has_more"`
+	LastRowKey string       `json:"last_row_key"`
+}
+
 // TuyaBatchStatusResponse represents the response for batch device status
 type TuyaBatchStatusResponse struct {
 	Result  []TuyaDeviceStatusItem `json:"result"`
@@ -80,8 +116,9 @@ type TuyaBatchStatusResponse struct {
 
 // TuyaDeviceStatusItem represents a single device status in the batch response
 type TuyaDeviceStatusItem struct {
-	ID       string `json:"id"`
-	IsOnline bool   `json:"is_online"` // Tuya v2/iot-03 often uses is_online
+	ID       string             `json:"id"`
+	IsOnline bool               `json:"is_online"` // Tuya v2/iot-03 often uses is_online
+	Status   []TuyaDeviceStatus `json:"status"`
 }
 
 // TuyaCommandRequest represents the request body for sending commands
@@ -89,6 +126,11 @@ type TuyaCommandRequest struct {
 	Commands []TuyaCommand `json:"commands"`
 }
 
+// TuyaRenameDeviceRequest represents the request body for renaming a device
+type TuyaRenameDeviceRequest struct {
+	Name string `json:"name"`
+}
+
 // TuyaCommand represents a single command
 type TuyaCommand struct {
 	Code  string      `json:"code"`
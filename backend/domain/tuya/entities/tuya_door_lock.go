@@ -0,0 +1,86 @@
+package entities
+
+// TuyaLockOpenLogsResponse represents the response for querying the unlock records of a door lock
+type TuyaLockOpenLogsResponse struct {
+	Result  TuyaLockOpenLogs `json:"result"`
+	Success bool             `json:"success"`
+	T       int64            `json:"t"`
+	Code    int              `json:"code"`
+	Msg     string           `json:"msg"`
+}
+
+// TuyaLockOpenLogs is one page of unlock records
+type TuyaLockOpenLogs struct {
+	Logs  []TuyaLockOpenLog `json:"logs"`
+	Total int               `json:"total"`
+}
+
+// TuyaLockOpenLog represents one unlock of a door lock. The status code tells the unlock method
+// (e.g., unlock_fingerprint, unlock_password, unlock_temporary, unlock_app).
+type TuyaLockOpenLog struct {
+	Status     TuyaDeviceStatus `json:"status"`
+	UpdateTime int64            `json:"update_time"`
+	NickName   string           `json:"nick_name"`
+	UnlockName string           `json:"unlock_name"`
+	UserID     string           `json:"user_id"`
+}
+
+// TuyaPasswordTicketResponse represents the response for requesting a door lock password ticket
+type TuyaPasswordTicketResponse struct {
+	Result  TuyaPasswordTicket `json:"result"`
+	Success bool               `json:"success"`
+	T       int64              `json:"t"`
+	Code    int                `json:"code"`
+	Msg     string             `json:"msg"`
+}
+
+// TuyaPasswordTicket is a short-lived ticket whose key encrypts a door lock password
+type TuyaPasswordTicket struct {
+	TicketID   string `json:"ticket_id"`
+	TicketKey  string `json:"ticket_key"`
+	ExpireTime int64  `json:"expire_time"`
+}
+
+// TuyaTempPasswordRequest is the body for creating a temporary door lock password
+type TuyaTempPasswordRequest struct {
+	Name          string `json:"name"`
+	Password      string `json:"password"`
+	PasswordType  string `json:"password_type"`
+	TicketID      string `json:"ticket_id"`
+	EffectiveTime int64  `json:"effective_time"`
+	InvalidTime   int64  `json:"invalid_time"`
+	Phone         string `json:"phone,omitempty"`
+	TimeZone      string `json:"time_zone,omitempty"`
+}
+
+// TuyaTempPasswordCreateResponse represents the response for creating a temporary door lock password
+type TuyaTempPasswordCreateResponse struct {
+	Result struct {
+		ID int64 `json:"id"`
+	} `json:"result"`
+	Success bool   `json:"success"`
+	T       int64  `json:"t"`
+	Code    int    `json:"code"`
+	Msg     string `json:"msg"`
+}
+
+// TuyaTempPasswordsResponse represents the response for listing the temporary passwords of a door lock
+type TuyaTempPasswordsResponse struct {
+	Result  []TuyaTempPassword `json:"result"`
+	Success bool               `json:"success"`
+	T       int64              `json:"t"`
+	Code    int                `json:"code"`
+	Msg     string             `json:"msg"`
+}
+
+// TuyaTempPassword represents a temporary door lock password. Phase tells whether it is pending (1),
+// active (2), frozen (3) or deleted (4) on the lock.
+type TuyaTempPassword struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	Phone         string `json:"phone"`
+	EffectiveTime int64  `json:"effective_time"`
+	InvalidTime   int64  `json:"invalid_time"`
+	Phase         int    `json:"phase"`
+	TimeZone      string `json:"time_zone"`
+}
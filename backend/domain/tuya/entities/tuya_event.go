@@ -0,0 +1,42 @@
+package entities
+
+import "encoding/json"
+
+// Tuya message protocols delivered through the Pulsar message service.
+const (
+	TuyaEventProtocolStatus = 4  // Device status report
+	TuyaEventProtocolDevice = 20 // Device lifecycle events (online, offline, name update, ...)
+)
+
+// TuyaPulsarMessage is a message frame received from the Pulsar WebSocket consumer
+type TuyaPulsarMessage struct {
+	MessageID  string            `json:"messageId"`
+	Payload    string            `json:"payload"`
+	Properties map[string]string `json:"properties"`
+}
+
+// TuyaEventPayload is the decoded payload of a Pulsar message; Data is encrypted with the access secret
+type TuyaEventPayload struct {
+	Protocol int    `json:"protocol"`
+	PV       string `json:"pv"`
+	Data     string `json:"data"`
+	T        int64  `json:"t"`
+}
+
+// TuyaDeviceEvent is the decrypted content of a device message.
+// Status is set for status reports; BizCode and BizData for lifecycle events.
+type TuyaDeviceEvent struct {
+	Protocol   int               `json:"-"`
+	DevID      string            `json:"devId"`
+	ProductKey string            `json:"productKey"`
+	Status     []TuyaEventStatus `json:"status"`
+	BizCode    string            `json:"bizCode"`
+	BizData    json.RawMessage   `json:"bizData"`
+}
+
+// TuyaEventStatus is a single reported DP value
+type TuyaEventStatus struct {
+	Code  string      `json:"code"`
+	Value interface{} `json:"value"`
+	T     int64       `json:"t"`
+}
@@ -0,0 +1,49 @@
+package entities
+
+import "encoding/json"
+
+// TuyaIRLearnedCodeResponse represents the response for fetching a learned IR code
+type TuyaIRLearnedCodeResponse struct {
+	Result  TuyaIRLearnedCode `json:"result"`
+	Success bool              `json:"success"`
+	T       int64             `json:"t"`
+	Code    int               `json:"code"`
+	Msg     string            `json:"msg"`
+}
+
+// TuyaIRLearnedCode represents the code captured by an IR hub while in learning mode
+type TuyaIRLearnedCode struct {
+	Success bool   `json:"success"`
+	Code    string `json:"code"`
+}
+
+// TuyaIRLearningCode represents a single learned key to be saved under a custom remote
+type TuyaIRLearningCode struct {
+	CategoryID int    `json:"category_id"`
+	KeyName    string `json:"key_name"`
+	Key        string `json:"key"`
+	Code       string `json:"code"`
+}
+
+// TuyaIRSaveCodesRequest is the body for saving learned codes
+type TuyaIRSaveCodesRequest struct {
+	CategoryID int                  `json:"category_id"`
+	BrandName  string               `json:"brand_name,omitempty"`
+	RemoteName string               `json:"remote_name,omitempty"`
+	Codes      []TuyaIRLearningCode `json:"codes"`
+}
+
+// TuyaIRSendCodeRequest is the body for sending a raw learned code through an IR hub
+type TuyaIRSendCodeRequest struct {
+	Code string `json:"code"`
+}
+
+// TuyaIRSaveCodesResponse represents the response for saving learned codes.
+// Result is the new remote when a remote is created, or a boolean when keys are added to an existing one.
+type TuyaIRSaveCodesResponse struct {
+	Result  json.RawMessage `json:"result"`
+	Success bool            `json:"success"`
+	T       int64           `json:"t"`
+	Code    int             `json:"code"`
+	Msg     string          `json:"msg"`
+}
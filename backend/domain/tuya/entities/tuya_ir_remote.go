@@ -0,0 +1,119 @@
+package entities
+
+import "encoding/json"
+
+// TuyaIRRemotesResponse represents the response for listing the remotes configured on an IR hub
+type TuyaIRRemotesResponse struct {
+	Result  []TuyaIRRemote `json:"result"`
+	Success bool           `json:"success"`
+	T       int64          `json:"t"`
+	Code    int            `json:"code"`
+	Msg     string         `json:"msg"`
+}
+
+// TuyaIRRemote represents a remote (TV, fan, projector, AC, ...) configured on an IR hub
+type TuyaIRRemote struct {
+	RemoteID    string `json:"remote_id"`
+	RemoteName  string `json:"remote_name"`
+	CategoryID  int    `json:"category_id"`
+	BrandID     int    `json:"brand_id"`
+	BrandName   string `json:"brand_name"`
+	RemoteIndex int    `json:"remote_index"`
+}
+
+// TuyaIRRemoteKeysResponse represents the response for fetching the keys of a remote
+type TuyaIRRemoteKeysResponse struct {
+	Result  TuyaIRRemoteKeys `json:"result"`
+	Success bool             `json:"success"`
+	T       int64            `json:"t"`
+	Code    int              `json:"code"`
+	Msg     string           `json:"msg"`
+}
+
+// TuyaIRRemoteKeys represents the keys of a remote
+type TuyaIRRemoteKeys struct {
+	CategoryID     int             `json:"category_id"`
+	BrandID        int             `json:"brand_id"`
+	RemoteIndex    int             `json:"remote_index"`
+	SingleAir      bool            `json:"single_air"`
+	DuplicatePower bool            `json:"duplicate_power"`
+	KeyList        []TuyaIRKeyItem `json:"key_list"`
+}
+
+// TuyaIRKeyItem represents a single key of a remote
+type TuyaIRKeyItem struct {
+	Key         string `json:"key"`
+	KeyID       int    `json:"key_id"`
+	KeyName     string `json:"key_name"`
+	StandardKey bool   `json:"standard_key"`
+}
+
+// TuyaIRKeyCommandRequest is the body for sending a key press to a remote
+type TuyaIRKeyCommandRequest struct {
+	CategoryID int    `json:"category_id"`
+	KeyID      int    `json:"key_id"`
+	Key        string `json:"key"`
+}
+
+// TuyaIRCategoriesResponse represents the response for listing the remote categories an IR hub supports
+type TuyaIRCategoriesResponse struct {
+	Result  []TuyaIRCategory `json:"result"`
+	Success bool             `json:"success"`
+	T       int64            `json:"t"`
+	Code    int              `json:"code"`
+	Msg     string           `json:"msg"`
+}
+
+// TuyaIRCategory represents a remote category (TV, set-top box, fan, AC, ...). Tuya returns the ID as a string
+type TuyaIRCategory struct {
+	CategoryID   json.Number `json:"category_id"`
+	CategoryName string      `json:"category_name"`
+}
+
+// TuyaIRBrandsResponse represents the response for listing the brands of a remote category
+type TuyaIRBrandsResponse struct {
+	Result  []TuyaIRBrand `json:"result"`
+	Success bool          `json:"success"`
+	T       int64         `json:"t"`
+	Code    int           `json:"code"`
+	Msg     string        `json:"msg"`
+}
+
+// TuyaIRBrand represents an appliance brand of a remote category
+type TuyaIRBrand struct {
+	BrandID   json.Number `json:"brand_id"`
+	BrandName string      `json:"brand_name"`
+}
+
+// TuyaIRRemoteIndexesResponse represents the response for listing the code libraries of a brand
+type TuyaIRRemoteIndexesResponse struct {
+	Result  []TuyaIRRemoteIndex `json:"result"`
+	Success bool                `json:"success"`
+	T       int64               `json:"t"`
+	Code    int                 `json:"code"`
+	Msg     string              `json:"msg"`
+}
+
+// TuyaIRRemoteIndex represents a code library (remote index) of a brand
+type TuyaIRRemoteIndex struct {
+	RemoteIndex json.Number `json:"remote_index"`
+}
+
+// TuyaIRAddRemoteRequest is the body for adding a remote from a code library to an IR hub
+type TuyaIRAddRemoteRequest struct {
+	CategoryID  int    `json:"category_id"`
+	BrandID     int    `json:"brand_id"`
+	BrandName   string `json:"brand_name"`
+	RemoteIndex int    `json:"remote_index"`
+	RemoteName  string `json:"remote_name"`
+}
+
+// TuyaIRAddRemoteResponse represents the response for adding a remote.
+// Result carries the new remote ID; its shape varies between API versions
+type TuyaIRAddRemoteResponse struct {
+	Result  json.RawMessage `json:"result"`
+	Success bool            `json:"success"`
+	T       int64           `json:"t"`
+	Code    int             `json:"code"`
+	Msg     string          `json:"msg"`
+}
@@ -0,0 +1,7 @@
+package entities
+
+// TuyaQuotaDay is the persisted number of Tuya API calls per endpoint on one UTC day
+type TuyaQuotaDay struct {
+	Date  string           `json:"date"`
+	Calls map[string]int64 `json:"calls"`
+}
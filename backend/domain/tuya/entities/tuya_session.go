@@ -0,0 +1,17 @@
+package entities
+
+// TuyaSession represents a server-side session holding a Tuya token on behalf of a client.
+// Clients only receive the opaque session ID; the token never leaves the backend.
+// Scope and APIKeyID identify the API key that created the session; its scope and device grants limit what the session may do.
+// Subject is the identity provider user that created the session instead of an API key.
+type TuyaSession struct {
+	ID           string `json:"id"`
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	UID          string `json:"uid"`
+	Scope        string `json:"scope,omitempty"`
+	APIKeyID     string `json:"api_key_id,omitempty"`
+	Subject      string `json:"subject,omitempty"`
+	ExpiresAt    int64  `json:"expires_at"`
+	CreatedAt    int64  `json:"created_at"`
+}
@@ -0,0 +1,159 @@
+// Package errors defines the typed errors of the Tuya domain. Every error carries the HTTP status it is
+// answered with, a machine-readable code and, when useful, a remediation hint, so controllers write error
+// responses from the error itself instead of matching on its message. Tuya API failures are translated
+// through a catalog of Tuya error codes (see FromTuya).
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"net/http"
+)
+
+// Machine-readable codes of errors raised by the backend itself. Tuya API failures use the codes of the catalog.
+const (
+	CodeBadRequest      = "BAD_REQUEST"
+	CodeUnauthorized    = "UNAUTHORIZED"
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeForbidden       = "FORBIDDEN"
+	CodeUnavailable     = "SERVICE_UNAVAILABLE"
+	CodeUpstreamTimeout = "UPSTREAM_TIMEOUT"
+	CodeInternal        = "INTERNAL_ERROR"
+)
+
+// Error is a typed error with everything needed to answer it.
+type Error struct {
+	// Code is the machine-readable error code (e.g., NOT_FOUND or TUYA_TOKEN_INVALID).
+	Code string
+	// HTTPStatus is the status the error is answered with.
+	HTTPStatus int
+	// Message is the human-readable message.
+	Message string
+	// Hint tells the caller how to resolve the error (optional).
+	Hint string
+	// TuyaCode is the Tuya error code the error was translated from (0 for backend errors).
+	TuyaCode int
+
+	cause error
+}
+
+// Error returns the message.
+//
+// return string The error message.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the wrapped error, if the message was built with %w.
+//
+// return error The wrapped error, or nil.
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// newf builds an Error from a format string; a %w verb keeps the wrapped error reachable through errors.Is.
+func newf(code string, status int, format string, args ...interface{}) *Error {
+	err := fmt.Errorf(format, args...)
+	return &Error{Code: code, HTTPStatus: status, Message: err.Error(), cause: stderrors.Unwrap(err)}
+}
+
+// BadRequest returns an error for invalid input (400).
+//
+// param format The message format.
+// param args The format arguments.
+// return *Error The error.
+func BadRequest(format string, args ...interface{}) *Error {
+	return newf(CodeBadRequest, http.StatusBadRequest, format, args...)
+}
+
+// Unauthorized returns an error for missing or invalid credentials of the backend itself, such as a setup
+// token or an identity provider login (401).
+//
+// param format The message format.
+// param args The format arguments.
+// return *Error The error.
+func Unauthorized(format string, args ...interface{}) *Error {
+	return newf(CodeUnauthorized, http.StatusUnauthorized, format, args...)
+}
+
+// NotFound returns an error for a missing resource (404).
+//
+// param format The message format.
+// param args The format arguments.
+// return *Error The error.
+func NotFound(format string, args ...interface{}) *Error {
+	return newf(CodeNotFound, http.StatusNotFound, format, args...)
+}
+
+// Conflict returns an error for a request that conflicts with the current state (409).
+//
+// param format The message format.
+// param args The format arguments.
+// return *Error The error.
+func Conflict(format string, args ...interface{}) *Error {
+	return newf(CodeConflict, http.StatusConflict, format, args...)
+}
+
+// Forbidden returns an error for a request the caller may not make (403).
+//
+// param format The message format.
+// param args The format arguments.
+// return *Error The error.
+func Forbidden(format string, args ...interface{}) *Error {
+	return newf(CodeForbidden, http.StatusForbidden, format, args...)
+}
+
+// Unavailable returns an error for a feature that is not available right now, such as a missing database (503).
+//
+// param format The message format.
+// param args The format arguments.
+// return *Error The error.
+func Unavailable(format string, args ...interface{}) *Error {
+	return newf(CodeUnavailable, http.StatusServiceUnavailable, format, args...)
+}
+
+// WithHint returns a copy of the error with a remediation hint.
+//
+// param hint The hint.
+// return *Error The error with the hint.
+func (e *Error) WithHint(hint string) *Error {
+	copied := *e
+	copied.Hint = hint
+	return &copied
+}
+
+// HasCode reports whether err (or an error it wraps) is an *Error with the given machine-readable code.
+//
+// param err The error.
+// param code The machine-readable code (e.g., CodeBadRequest).
+// return bool True if the codes match.
+func HasCode(err error, code string) bool {
+	var typed *Error
+	return stderrors.As(err, &typed) && typed.Code == code
+}
+
+// Classify returns the typed error in err's chain. Untyped errors become INTERNAL_ERROR (500), except
+// deadlines, which become UPSTREAM_TIMEOUT (504) since they are almost always slow Tuya calls.
+//
+// param err The error (must not be nil).
+// return *Error The typed error; its Message is err.Error(), so context added by wrapping is kept.
+func Classify(err error) *Error {
+	var typed *Error
+	if stderrors.As(err, &typed) {
+		classified := *typed
+		classified.Message = err.Error()
+		return &classified
+	}
+	if stderrors.Is(err, context.DeadlineExceeded) {
+		return &Error{
+			Code:       CodeUpstreamTimeout,
+			HTTPStatus: http.StatusGatewayTimeout,
+			Message:    err.Error(),
+			Hint:       "The Tuya API did not answer in time; retry, or raise TUYA_COMMAND_TIMEOUT / TUYA_LIST_TIMEOUT.",
+			cause:      err,
+		}
+	}
+	return &Error{Code: CodeInternal, HTTPStatus: http.StatusInternalServerError, Message: err.Error(), cause: err}
+}
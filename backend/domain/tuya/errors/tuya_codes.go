@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// CodeTuyaUnknown is the machine-readable code of Tuya error codes missing from the catalog.
+const CodeTuyaUnknown = "TUYA_ERROR"
+
+// tuyaCode describes how a Tuya error code is answered.
+type tuyaCode struct {
+	code   string
+	status int
+	hint   string
+}
+
+// tuyaCodes is the catalog of Tuya error codes the backend runs into, with the HTTP status they are answered
+// with and how to resolve them. Codes caused by the backend's own configuration answer 502, since the
+// caller cannot fix them.
+var tuyaCodes = map[int]tuyaCode{
+	500: {"TUYA_SYSTEM_ERROR", http.StatusBadGateway,
+		"Tuya had an internal error; retry later."},
+	1004: {"TUYA_SIGNATURE_INVALID", http.StatusBadGateway,
+		"Check TUYA_CLIENT_ID and TUYA_ACCESS_SECRET."},
+	1005: {"TUYA_CLIENT_INVALID", http.StatusBadGateway,
+		"Check TUYA_CLIENT_ID and that the cloud project still exists."},
+	1010: {"TUYA_TOKEN_INVALID", http.StatusUnauthorized,
+		"The access token expired or was revoked; get a new one with GET /api/tuya/auth and retry."},
+	1011: {"TUYA_TOKEN_INVALID", http.StatusUnauthorized,
+		"The access token is no longer valid; get a new one with GET /api/tuya/auth and retry."},
+	1013: {"TUYA_REQUEST_TIME_INVALID", http.StatusBadGateway,
+		"The server clock is out of sync with Tuya; enable NTP on the host."},
+	1100: {"TUYA_INVALID_PARAMETER", http.StatusBadRequest,
+		"A required parameter is empty; check the request body."},
+	1101: {"TUYA_INVALID_PARAMETER", http.StatusBadRequest,
+		"A parameter is out of range; check the device specification for the allowed values."},
+	1102: {"TUYA_INVALID_PARAMETER", http.StatusBadRequest,
+		"A required parameter is missing; check the request body."},
+	1105: {"TUYA_INVALID_PARAMETER", http.StatusBadRequest,
+		"A required parameter is missing; check the request body."},
+	1106: {"TUYA_PERMISSION_DENIED", http.StatusForbidden,
+		"The cloud project may not access this device or API (link the app account to the project), or the command does not match the device's instruction set."},
+	1108: {"TUYA_API_NOT_FOUND", http.StatusBadGateway,
+		"Tuya does not know this API; check TUYA_BASE_URL points to the data center of the project."},
+	1109: {"TUYA_INVALID_PARAMETER", http.StatusBadRequest,
+		"A parameter is illegal; check the request body against the device specification."},
+	1110: {"TUYA_RATE_LIMITED", http.StatusTooManyRequests,
+		"Too many concurrent requests to Tuya; retry with backoff."},
+	2001: {"TUYA_DEVICE_OFFLINE", http.StatusServiceUnavailable,
+		"The device is offline; check its power and network connection."},
+	2002: {"TUYA_NO_DEVICES", http.StatusNotFound,
+		"The Tuya user has no devices; check TUYA_USER_ID or X-TUYA-UID."},
+	2003: {"TUYA_FUNCTION_NOT_SUPPORTED", http.StatusBadRequest,
+		"The device does not support this function; check its specification."},
+	2006: {"TUYA_USER_NOT_FOUND", http.StatusNotFound,
+		"The Tuya user does not exist; check TUYA_USER_ID or X-TUYA-UID."},
+	2008: {"TUYA_COMMAND_NOT_SUPPORTED", http.StatusBadRequest,
+		"The device does not support this command or value; check its specification for the codes and ranges it accepts."},
+	2009: {"TUYA_DEVICE_NOT_SUPPORTED", http.StatusBadRequest,
+		"This API does not support the device; use the endpoint of its category."},
+	30100: {"TUYA_IR_COMMAND_REJECTED", http.StatusBadRequest,
+		"The IR hub rejected the air conditioner command; enable the ir_standard_fallback feature flag for the remote or check its brand code library."},
+	28841101: {"TUYA_SUBSCRIPTION_EXPIRED", http.StatusBadGateway,
+		"The cloud development plan of the project expired; renew it on the Tuya IoT platform."},
+	28841105: {"TUYA_API_NOT_AUTHORIZED", http.StatusBadGateway,
+		"The cloud project is not authorized for this API; subscribe to the API service on the Tuya IoT platform."},
+}
+
+// FromTuya translates a failed Tuya response into a typed error using the catalog. The message keeps the
+// established "tuya API <action>: <msg> (code: <code>)" form.
+//
+// param action What failed (e.g., "failed to fetch device").
+// param code The Tuya error code.
+// param msg The Tuya error message.
+// return *Error The error; unknown codes are answered with 502 and TUYA_ERROR.
+func FromTuya(action string, code int, msg string) *Error {
+	entry, ok := tuyaCodes[code]
+	if !ok {
+		entry = tuyaCode{code: CodeTuyaUnknown, status: http.StatusBadGateway}
+	}
+	return &Error{
+		Code:       entry.code,
+		HTTPStatus: entry.status,
+		Message:    fmt.Sprintf("tuya API %s: %s (code: %d)", action, msg, code),
+		Hint:       entry.hint,
+		TuyaCode:   code,
+	}
+}
@@ -0,0 +1,194 @@
+package repositories
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/tuya/entities"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// auditLogPrefix is the key prefix of audit entries: "audit_log:{unix_nano}-{id}".
+const auditLogPrefix = "audit_log:"
+
+// AuditLogFilter selects audit entries by the time they were recorded.
+type AuditLogFilter struct {
+	Since    time.Time // Inclusive; zero starts at the oldest entry
+	Until    time.Time // Exclusive; zero ends at the newest entry
+	DeviceID string    // Empty selects every device
+}
+
+// AuditLogRecord is a stored audit entry with the reference that removes it.
+type AuditLogRecord struct {
+	Ref   string
+	Entry entities.AuditLogEntry
+}
+
+// AuditLogRepository stores the append-only audit log.
+type AuditLogRepository interface {
+	// Append stores an entry recorded at the given time.
+	Append(entry entities.AuditLogEntry, recordedAt time.Time) error
+	// List returns the entries matching the filter, oldest first.
+	List(filter AuditLogFilter) ([]AuditLogRecord, error)
+	// Delete removes entries by their record reference.
+	Delete(refs []string) error
+}
+
+// cacheAuditLogRepository keeps entries as persistent CacheStore keys whose zero-padded timestamp keeps
+// them in chronological order.
+type cacheAuditLogRepository struct {
+	cache persistence.CacheStore
+}
+
+// NewCacheAuditLogRepository initializes an AuditLogRepository on persistent CacheStore keys.
+//
+// param cache The CacheStore holding the entries.
+// return AuditLogRepository The repository.
+func NewCacheAuditLogRepository(cache persistence.CacheStore) AuditLogRepository {
+	return &cacheAuditLogRepository{cache: cache}
+}
+
+// Append stores an entry under "audit_log:{unix_nano}-{id}".
+func (r *cacheAuditLogRepository) Append(entry entities.AuditLogEntry, recordedAt time.Time) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit entry: %w", err)
+	}
+	return r.cache.SetPersistent(fmt.Sprintf("%s%020d-%s", auditLogPrefix, recordedAt.UnixNano(), entry.ID), data)
+}
+
+// List returns the entries matching the filter, oldest first. The record references are the keys.
+func (r *cacheAuditLogRepository) List(filter AuditLogFilter) ([]AuditLogRecord, error) {
+	keys, err := r.cache.GetAllKeysWithPrefix(auditLogPrefix)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []AuditLogRecord
+	for _, key := range keys {
+		// The zero-padded timestamp in the key allows skipping entries without reading them
+		if !filter.Since.IsZero() && key < fmt.Sprintf("%s%020d", auditLogPrefix, filter.Since.UnixNano()) {
+			continue
+		}
+		if !filter.Until.IsZero() && key >= fmt.Sprintf("%s%020d", auditLogPrefix, filter.Until.UnixNano()) {
+			break
+		}
+		data, err := r.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var entry entities.AuditLogEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			continue
+		}
+		if filter.DeviceID != "" && entry.DeviceID != filter.DeviceID {
+			continue
+		}
+		records = append(records, AuditLogRecord{Ref: key, Entry: entry})
+	}
+	return records, nil
+}
+
+// Delete removes entries by key.
+func (r *cacheAuditLogRepository) Delete(refs []string) error {
+	return r.cache.DeleteBatch(refs)
+}
+
+// auditLogRow is an entry in the "audit_logs" table. RecordedAt (Unix nanoseconds) orders the entries.
+type auditLogRow struct {
+	Seq        uint64 `gorm:"primaryKey;autoIncrement"`
+	ID         string `gorm:"size:32;not null"`
+	RecordedAt int64  `gorm:"not null;index"`
+	Timestamp  int64  `gorm:"not null"`
+	Action     string `gorm:"size:32;not null"`
+	DeviceID   string `gorm:"size:64;not null;index"`
+	Detail     string `gorm:"type:text"`
+	Success    bool   `gorm:"not null"`
+	Error      string `gorm:"type:text"`
+}
+
+// TableName overrides the table name used by GORM.
+func (auditLogRow) TableName() string {
+	return "audit_logs"
+}
+
+// sqlAuditLogRepository keeps entries in the "audit_logs" table.
+type sqlAuditLogRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLAuditLogRepository initializes an AuditLogRepository on the table created by the database migrations.
+//
+// param db The SQL database.
+// return AuditLogRepository The repository.
+func NewSQLAuditLogRepository(db *gorm.DB) AuditLogRepository {
+	return &sqlAuditLogRepository{db: db}
+}
+
+// Append stores an entry.
+func (r *sqlAuditLogRepository) Append(entry entities.AuditLogEntry, recordedAt time.Time) error {
+	return r.db.Create(&auditLogRow{
+		ID:         entry.ID,
+		RecordedAt: recordedAt.UnixNano(),
+		Timestamp:  entry.Timestamp,
+		Action:     entry.Action,
+		DeviceID:   entry.DeviceID,
+		Detail:     entry.Detail,
+		Success:    entry.Success,
+		Error:      entry.Error,
+	}).Error
+}
+
+// List returns the entries matching the filter, oldest first. The record references are the row sequence numbers.
+func (r *sqlAuditLogRepository) List(filter AuditLogFilter) ([]AuditLogRecord, error) {
+	query := r.db.Order("recorded_at, seq")
+	if !filter.Since.IsZero() {
+		query = query.Where("recorded_at >= ?", filter.Since.UnixNano())
+	}
+	if !filter.Until.IsZero() {
+		query = query.Where("recorded_at < ?", filter.Until.UnixNano())
+	}
+	if filter.DeviceID != "" {
+		query = query.Where("device_id = ?", filter.DeviceID)
+	}
+	var rows []auditLogRow
+	if err := query.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	records := make([]AuditLogRecord, len(rows))
+	for i, row := range rows {
+		records[i] = AuditLogRecord{
+			Ref: strconv.FormatUint(row.Seq, 10),
+			Entry: entities.AuditLogEntry{
+				ID:        row.ID,
+				Timestamp: row.Timestamp,
+				Action:    row.Action,
+				DeviceID:  row.DeviceID,
+				Detail:    row.Detail,
+				Success:   row.Success,
+				Error:     row.Error,
+			},
+		}
+	}
+	return records, nil
+}
+
+// Delete removes entries by sequence number.
+func (r *sqlAuditLogRepository) Delete(refs []string) error {
+	seqs := make([]uint64, 0, len(refs))
+	for _, ref := range refs {
+		seq, err := strconv.ParseUint(ref, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid audit record reference %q", ref)
+		}
+		seqs = append(seqs, seq)
+	}
+	if len(seqs) == 0 {
+		return nil
+	}
+	return r.db.Where("seq IN ?", seqs).Delete(&auditLogRow{}).Error
+}
@@ -0,0 +1,257 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/tuya/entities"
+
+	"gorm.io/gorm"
+)
+
+const (
+	automationPrefix        = "automation:"
+	automationHistoryPrefix = "automation_history:"
+)
+
+// AutomationRepository stores automation rules and their recorded runs.
+type AutomationRepository interface {
+	// List returns every rule, in ID order.
+	List() ([]entities.AutomationRule, error)
+	// Get returns a rule, or nil if it does not exist.
+	Get(id string) (*entities.AutomationRule, error)
+	// Save stores a rule, replacing the previous version.
+	Save(rule entities.AutomationRule) error
+	// Delete removes a rule and its runs.
+	Delete(id string) error
+	// GetHistory returns the recorded runs of a rule, newest first.
+	GetHistory(id string) ([]entities.AutomationRun, error)
+	// SaveHistory replaces the recorded runs of a rule, newest first.
+	SaveHistory(id string, runs []entities.AutomationRun) error
+}
+
+// cacheAutomationRepository keeps rules as persistent CacheStore keys:
+// "automation:{id}" and "automation_history:{id}".
+type cacheAutomationRepository struct {
+	cache persistence.CacheStore
+}
+
+// NewCacheAutomationRepository initializes an AutomationRepository on persistent CacheStore keys.
+//
+// param cache The CacheStore holding the rules.
+// return AutomationRepository The repository.
+func NewCacheAutomationRepository(cache persistence.CacheStore) AutomationRepository {
+	return &cacheAutomationRepository{cache: cache}
+}
+
+// List returns every rule, in ID order. Unreadable rules are skipped.
+func (r *cacheAutomationRepository) List() ([]entities.AutomationRule, error) {
+	keys, err := r.cache.GetAllKeysWithPrefix(automationPrefix)
+	if err != nil {
+		return nil, err
+	}
+	rules := make([]entities.AutomationRule, 0, len(keys))
+	for _, key := range keys {
+		rule, err := r.Get(strings.TrimPrefix(key, automationPrefix))
+		if err != nil || rule == nil {
+			continue
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, nil
+}
+
+// Get returns a rule, or nil if it does not exist.
+func (r *cacheAutomationRepository) Get(id string) (*entities.AutomationRule, error) {
+	jsonData, err := r.cache.Get(automationPrefix + id)
+	if err != nil || jsonData == nil {
+		return nil, err
+	}
+	var rule entities.AutomationRule
+	if err := json.Unmarshal(jsonData, &rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation: %w", err)
+	}
+	return &rule, nil
+}
+
+// Save stores a rule.
+func (r *cacheAutomationRepository) Save(rule entities.AutomationRule) error {
+	jsonData, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automation: %w", err)
+	}
+	return r.cache.SetPersistent(automationPrefix+rule.ID, jsonData)
+}
+
+// Delete removes a rule and its runs.
+func (r *cacheAutomationRepository) Delete(id string) error {
+	if err := r.cache.Delete(automationPrefix + id); err != nil {
+		return err
+	}
+	return r.cache.Delete(automationHistoryPrefix + id)
+}
+
+// GetHistory returns the recorded runs of a rule, newest first.
+func (r *cacheAutomationRepository) GetHistory(id string) ([]entities.AutomationRun, error) {
+	jsonData, err := r.cache.Get(automationHistoryPrefix + id)
+	if err != nil || jsonData == nil {
+		return nil, err
+	}
+	var runs []entities.AutomationRun
+	if err := json.Unmarshal(jsonData, &runs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation history: %w", err)
+	}
+	return runs, nil
+}
+
+// SaveHistory replaces the recorded runs of a rule.
+func (r *cacheAutomationRepository) SaveHistory(id string, runs []entities.AutomationRun) error {
+	jsonData, err := json.Marshal(runs)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automation history: %w", err)
+	}
+	return r.cache.SetPersistent(automationHistoryPrefix+id, jsonData)
+}
+
+// automationRuleRow is a rule in the "automation_rules" table. Conditions and actions are part of the rule
+// stored as JSON in Definition; the other columns are copies for SQL queries.
+type automationRuleRow struct {
+	ID         string `gorm:"primaryKey;size:32"`
+	Name       string `gorm:"size:255;not null"`
+	Enabled    bool   `gorm:"not null"`
+	Definition string `gorm:"type:text;not null"`
+	CreatedAt  int64  `gorm:"autoCreateTime:false"`
+	UpdatedAt  int64  `gorm:"autoUpdateTime:false"`
+}
+
+// TableName overrides the table name used by GORM.
+func (automationRuleRow) TableName() string {
+	return "automation_rules"
+}
+
+// automationRunRow is a recorded run in the "automation_runs" table, ordered by Position (0 = newest).
+type automationRunRow struct {
+	ID          uint64 `gorm:"primaryKey;autoIncrement"`
+	RuleID      string `gorm:"size:32;not null;index"`
+	Position    int    `gorm:"not null"`
+	TriggeredAt int64  `gorm:"not null"`
+	Run         string `gorm:"type:text;not null"`
+}
+
+// TableName overrides the table name used by GORM.
+func (automationRunRow) TableName() string {
+	return "automation_runs"
+}
+
+// sqlAutomationRepository keeps rules in the "automation_rules" and "automation_runs" tables.
+type sqlAutomationRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLAutomationRepository initializes an AutomationRepository on the tables created by the database migrations.
+//
+// param db The SQL database.
+// return AutomationRepository The repository.
+func NewSQLAutomationRepository(db *gorm.DB) AutomationRepository {
+	return &sqlAutomationRepository{db: db}
+}
+
+// List returns every rule, in ID order. Unreadable rules are skipped.
+func (r *sqlAutomationRepository) List() ([]entities.AutomationRule, error) {
+	var rows []automationRuleRow
+	if err := r.db.Order("id").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	rules := make([]entities.AutomationRule, 0, len(rows))
+	for _, row := range rows {
+		var rule entities.AutomationRule
+		if err := json.Unmarshal([]byte(row.Definition), &rule); err != nil {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Get returns a rule, or nil if it does not exist.
+func (r *sqlAutomationRepository) Get(id string) (*entities.AutomationRule, error) {
+	var row automationRuleRow
+	err := r.db.Where("id = ?", id).Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var rule entities.AutomationRule
+	if err := json.Unmarshal([]byte(row.Definition), &rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal automation: %w", err)
+	}
+	return &rule, nil
+}
+
+// Save stores a rule.
+func (r *sqlAutomationRepository) Save(rule entities.AutomationRule) error {
+	definition, err := json.Marshal(rule)
+	if err != nil {
+		return fmt.Errorf("failed to marshal automation: %w", err)
+	}
+	return r.db.Save(&automationRuleRow{
+		ID:         rule.ID,
+		Name:       rule.Name,
+		Enabled:    rule.Enabled,
+		Definition: string(definition),
+		CreatedAt:  rule.CreatedAt,
+		UpdatedAt:  rule.UpdatedAt,
+	}).Error
+}
+
+// Delete removes a rule and its runs.
+func (r *sqlAutomationRepository) Delete(id string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ?", id).Delete(&automationRuleRow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("rule_id = ?", id).Delete(&automationRunRow{}).Error
+	})
+}
+
+// GetHistory returns the recorded runs of a rule, newest first.
+func (r *sqlAutomationRepository) GetHistory(id string) ([]entities.AutomationRun, error) {
+	var rows []automationRunRow
+	if err := r.db.Where("rule_id = ?", id).Order("position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	runs := make([]entities.AutomationRun, 0, len(rows))
+	for _, row := range rows {
+		var run entities.AutomationRun
+		if err := json.Unmarshal([]byte(row.Run), &run); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal automation history: %w", err)
+		}
+		runs = append(runs, run)
+	}
+	return runs, nil
+}
+
+// SaveHistory replaces the recorded runs of a rule.
+func (r *sqlAutomationRepository) SaveHistory(id string, runs []entities.AutomationRun) error {
+	rows := make([]automationRunRow, len(runs))
+	for i, run := range runs {
+		encoded, err := json.Marshal(run)
+		if err != nil {
+			return fmt.Errorf("failed to marshal automation history: %w", err)
+		}
+		rows[i] = automationRunRow{RuleID: id, Position: i, TriggeredAt: run.TriggeredAt, Run: string(encoded)}
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", id).Delete(&automationRunRow{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
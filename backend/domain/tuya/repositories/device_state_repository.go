@@ -0,0 +1,243 @@
+package repositories
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/tuya/entities"
+
+	"gorm.io/gorm"
+)
+
+const (
+	deviceStatePrefix        = "device_state:"
+	deviceStateHistoryPrefix = "device_state_history:"
+)
+
+// DeviceStateRepository stores the last known control state of each device and the states it replaced.
+type DeviceStateRepository interface {
+	// Get returns the state of a device, or nil if none was saved.
+	Get(deviceID string) (*entities.DeviceState, error)
+	// Save stores the state of a device, replacing the previous one.
+	Save(state entities.DeviceState) error
+	// GetHistory returns the previous states of a device, oldest first.
+	GetHistory(deviceID string) ([]entities.DeviceState, error)
+	// SaveHistory replaces the previous states of a device; an empty history removes them.
+	SaveHistory(deviceID string, history []entities.DeviceState) error
+	// ListDeviceIDs returns the devices with a saved state.
+	ListDeviceIDs() ([]string, error)
+	// Delete removes the state and history of a device.
+	Delete(deviceID string) error
+}
+
+// cacheDeviceStateRepository keeps states as persistent CacheStore keys:
+// "device_state:{device_id}" and "device_state_history:{device_id}".
+type cacheDeviceStateRepository struct {
+	cache persistence.CacheStore
+}
+
+// NewCacheDeviceStateRepository initializes a DeviceStateRepository on persistent CacheStore keys.
+//
+// param cache The CacheStore holding the states.
+// return DeviceStateRepository The repository.
+func NewCacheDeviceStateRepository(cache persistence.CacheStore) DeviceStateRepository {
+	return &cacheDeviceStateRepository{cache: cache}
+}
+
+// Get returns the state of a device, or nil if none was saved.
+func (r *cacheDeviceStateRepository) Get(deviceID string) (*entities.DeviceState, error) {
+	jsonData, err := r.cache.Get(deviceStatePrefix + deviceID)
+	if err != nil || jsonData == nil {
+		return nil, err
+	}
+	var state entities.DeviceState
+	if err := json.Unmarshal(jsonData, &state); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device state: %w", err)
+	}
+	return &state, nil
+}
+
+// Save stores the state of a device.
+func (r *cacheDeviceStateRepository) Save(state entities.DeviceState) error {
+	jsonData, err := json.Marshal(state)
+	if err != nil {
+		return fmt.Errorf("failed to marshal device state: %w", err)
+	}
+	return r.cache.SetPersistent(deviceStatePrefix+state.DeviceID, jsonData)
+}
+
+// GetHistory returns the previous states of a device, oldest first.
+func (r *cacheDeviceStateRepository) GetHistory(deviceID string) ([]entities.DeviceState, error) {
+	jsonData, err := r.cache.Get(deviceStateHistoryPrefix + deviceID)
+	if err != nil || jsonData == nil {
+		return nil, err
+	}
+	var history []entities.DeviceState
+	if err := json.Unmarshal(jsonData, &history); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device state history: %w", err)
+	}
+	return history, nil
+}
+
+// SaveHistory replaces the previous states of a device.
+func (r *cacheDeviceStateRepository) SaveHistory(deviceID string, history []entities.DeviceState) error {
+	key := deviceStateHistoryPrefix + deviceID
+	if len(history) == 0 {
+		return r.cache.Delete(key)
+	}
+	jsonData, err := json.Marshal(history)
+	if err != nil {
+		return fmt.Errorf("failed to marshal device state history: %w", err)
+	}
+	return r.cache.SetPersistent(key, jsonData)
+}
+
+// ListDeviceIDs returns the devices with a saved state.
+func (r *cacheDeviceStateRepository) ListDeviceIDs() ([]string, error) {
+	keys, err := r.cache.GetAllKeysWithPrefix(deviceStatePrefix)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, len(keys))
+	for i, key := range keys {
+		ids[i] = strings.TrimPrefix(key, deviceStatePrefix)
+	}
+	return ids, nil
+}
+
+// Delete removes the state and history of a device.
+func (r *cacheDeviceStateRepository) Delete(deviceID string) error {
+	if err := r.cache.Delete(deviceStatePrefix + deviceID); err != nil {
+		return err
+	}
+	return r.cache.Delete(deviceStateHistoryPrefix + deviceID)
+}
+
+// deviceStateRow is a device state in the "device_states" table; commands are stored as JSON.
+type deviceStateRow struct {
+	DeviceID     string `gorm:"primaryKey;size:64"`
+	LastCommands string `gorm:"type:text;not null"`
+	UpdatedAt    int64  `gorm:"autoUpdateTime:false"`
+}
+
+// TableName overrides the table name used by GORM.
+func (deviceStateRow) TableName() string {
+	return "device_states"
+}
+
+// deviceStateHistoryRow is a previous device state in the "device_state_history" table, ordered by Position.
+type deviceStateHistoryRow struct {
+	ID           uint64 `gorm:"primaryKey;autoIncrement"`
+	DeviceID     string `gorm:"size:64;not null;index"`
+	Position     int    `gorm:"not null"`
+	LastCommands string `gorm:"type:text;not null"`
+	UpdatedAt    int64  `gorm:"autoUpdateTime:false"`
+}
+
+// TableName overrides the table name used by GORM.
+func (deviceStateHistoryRow) TableName() string {
+	return "device_state_history"
+}
+
+// sqlDeviceStateRepository keeps states in the "device_states" and "device_state_history" tables.
+type sqlDeviceStateRepository struct {
+	db *gorm.DB
+}
+
+// NewSQLDeviceStateRepository initializes a DeviceStateRepository on the tables created by the database migrations.
+//
+// param db The SQL database.
+// return DeviceStateRepository The repository.
+func NewSQLDeviceStateRepository(db *gorm.DB) DeviceStateRepository {
+	return &sqlDeviceStateRepository{db: db}
+}
+
+// Get returns the state of a device, or nil if none was saved.
+func (r *sqlDeviceStateRepository) Get(deviceID string) (*entities.DeviceState, error) {
+	var row deviceStateRow
+	err := r.db.Where("device_id = ?", deviceID).Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return decodeDeviceState(row.DeviceID, row.LastCommands, row.UpdatedAt)
+}
+
+// Save stores the state of a device.
+func (r *sqlDeviceStateRepository) Save(state entities.DeviceState) error {
+	commands, err := json.Marshal(state.LastCommands)
+	if err != nil {
+		return fmt.Errorf("failed to marshal device state: %w", err)
+	}
+	return r.db.Save(&deviceStateRow{DeviceID: state.DeviceID, LastCommands: string(commands), UpdatedAt: state.UpdatedAt}).Error
+}
+
+// GetHistory returns the previous states of a device, oldest first.
+func (r *sqlDeviceStateRepository) GetHistory(deviceID string) ([]entities.DeviceState, error) {
+	var rows []deviceStateHistoryRow
+	if err := r.db.Where("device_id = ?", deviceID).Order("position").Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	history := make([]entities.DeviceState, 0, len(rows))
+	for _, row := range rows {
+		state, err := decodeDeviceState(row.DeviceID, row.LastCommands, row.UpdatedAt)
+		if err != nil {
+			return nil, err
+		}
+		history = append(history, *state)
+	}
+	return history, nil
+}
+
+// SaveHistory replaces the previous states of a device.
+func (r *sqlDeviceStateRepository) SaveHistory(deviceID string, history []entities.DeviceState) error {
+	rows := make([]deviceStateHistoryRow, len(history))
+	for i, state := range history {
+		commands, err := json.Marshal(state.LastCommands)
+		if err != nil {
+			return fmt.Errorf("failed to marshal device state history: %w", err)
+		}
+		rows[i] = deviceStateHistoryRow{DeviceID: deviceID, Position: i, LastCommands: string(commands), UpdatedAt: state.UpdatedAt}
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", deviceID).Delete(&deviceStateHistoryRow{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// ListDeviceIDs returns the devices with a saved state.
+func (r *sqlDeviceStateRepository) ListDeviceIDs() ([]string, error) {
+	var ids []string
+	if err := r.db.Model(&deviceStateRow{}).Order("device_id").Pluck("device_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// Delete removes the state and history of a device.
+func (r *sqlDeviceStateRepository) Delete(deviceID string) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("device_id = ?", deviceID).Delete(&deviceStateRow{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("device_id = ?", deviceID).Delete(&deviceStateHistoryRow{}).Error
+	})
+}
+
+// decodeDeviceState rebuilds a device state from its stored columns.
+func decodeDeviceState(deviceID, commands string, updatedAt int64) (*entities.DeviceState, error) {
+	state := &entities.DeviceState{DeviceID: deviceID, UpdatedAt: updatedAt}
+	if err := json.Unmarshal([]byte(commands), &state.LastCommands); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device state: %w", err)
+	}
+	return state, nil
+}
@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+
+	"gorm.io/gorm"
+)
+
+// Persistence backends selectable via PERSISTENCE_BACKEND.
+const (
+	// BackendCache keeps the data as persistent keys of the CacheStore (BadgerDB or Redis).
+	BackendCache = "cache"
+	// BackendSQL keeps the data in tables of the SQL database, leaving the CacheStore to cached data.
+	BackendSQL = "sql"
+)
+
+// Repositories groups the stores of the durable application data: device states with their history,
+// automation rules (the schedules of a deployment) with their runs, and the audit log. Rooms always live
+// in the SQL database (see RoomUseCase). A nil repository means the data cannot be stored.
+type Repositories struct {
+	DeviceStates DeviceStateRepository
+	Automations  AutomationRepository
+	AuditLog     AuditLogRepository
+}
+
+// New opens the repositories selected by PERSISTENCE_BACKEND: persistent CacheStore keys (default), or the SQL
+// tables created by the database migrations.
+//
+// param cache The CacheStore used by the cache backend (nil when unavailable; the repositories are then nil).
+// param db The SQL database used by the sql backend.
+// return *Repositories The repositories.
+// return error An error if the backend is unknown or the database is unavailable.
+func New(cache persistence.CacheStore, db *gorm.DB) (*Repositories, error) {
+	switch backend := strings.ToLower(strings.TrimSpace(utils.GetConfig().PersistenceBackend)); backend {
+	case "", BackendCache:
+		if cache == nil {
+			return &Repositories{}, nil
+		}
+		return &Repositories{
+			DeviceStates: NewCacheDeviceStateRepository(cache),
+			Automations:  NewCacheAutomationRepository(cache),
+			AuditLog:     NewCacheAuditLogRepository(cache),
+		}, nil
+	case BackendSQL:
+		if db == nil {
+			return nil, fmt.Errorf("PERSISTENCE_BACKEND=sql needs the SQL database, which is unavailable")
+		}
+		utils.LogInfo("Repositories: Storing device states, automations and the audit log in the SQL database")
+		return &Repositories{
+			DeviceStates: NewSQLDeviceStateRepository(db),
+			Automations:  NewSQLAutomationRepository(db),
+			AuditLog:     NewSQLAuditLogRepository(db),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown PERSISTENCE_BACKEND %q (supported: %s, %s)", backend, BackendCache, BackendSQL)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaACUsageReportRoutes registers the air conditioner usage report endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller building the report.
+func SetupTuyaACUsageReportRoutes(router gin.IRouter, controller *controllers.TuyaACUsageReportController) {
+	utils.LogDebug("SetupTuyaACUsageReportRoutes initialized")
+	api := router.Group("/api/admin/reports")
+	{
+		// GET /api/admin/reports/ac-usage
+		// Reports AC runtime and energy per room for a month, as JSON or CSV.
+		api.GET("/ac-usage", controller.GetACUsageReport)
+	}
+}
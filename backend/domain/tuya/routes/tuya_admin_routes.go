@@ -0,0 +1,27 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaAdminRoutes registers administrative endpoints. These are protected by the API key
+// and use a server-side Tuya token, so no client token is required.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param swaggerExamplesController Controller for device-driven Swagger examples.
+func SetupTuyaAdminRoutes(router gin.IRouter, swaggerExamplesController *controllers.TuyaSwaggerExamplesController) {
+	utils.LogDebug("SetupTuyaAdminRoutes initialized")
+	api := router.Group("/api/admin")
+	{
+		// GET /api/admin/swagger/examples
+		// Returns the control examples currently served in the Swagger UI.
+		api.GET("/swagger/examples", swaggerExamplesController.GetExamples)
+
+		// POST /api/admin/swagger/examples
+		// Generates control examples from a selected device's specification.
+		api.POST("/swagger/examples", swaggerExamplesController.GenerateExamples)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaArchiveRoutes registers the sensor history and audit log archive endpoints.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling archive exports.
+func SetupTuyaArchiveRoutes(router gin.IRouter, controller *controllers.TuyaArchiveController) {
+	utils.LogDebug("SetupTuyaArchiveRoutes initialized")
+	api := router.Group("/api/admin/archive")
+	{
+		// POST /api/admin/archive/run
+		// Exports data older than the local retention immediately.
+		api.POST("/run", controller.Run)
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaAssetRoutes registers endpoints for listing the devices of IoT Core projects by asset.
+//
+// param router The Gin router interface.
+// param controller The controller handling asset requests.
+func SetupTuyaAssetRoutes(router gin.IRouter, controller *controllers.TuyaAssetController) {
+	utils.LogDebug("SetupTuyaAssetRoutes initialized")
+	api := router.Group("/api/tuya/assets")
+	{
+		// GET /api/tuya/assets
+		// Lists the assets of the project.
+		api.GET("", controller.ListAssets)
+
+		// GET /api/tuya/assets/:asset_id/devices
+		// Lists the devices of an asset.
+		api.GET("/:asset_id/devices", controller.ListAssetDevices)
+	}
+}
@@ -19,4 +19,18 @@ func SetupTuyaAuthRoutes(router *gin.RouterGroup, controller *controllers.TuyaAu
 		// Initiates the Tuya authentication process to retrieve an access token.
 		api.GET("/auth", controller.Authenticate)
 	}
+}
+
+// SetupTuyaSessionRoutes registers session management endpoints that require an authenticated request.
+//
+// param router The Gin router interface (protected by AuthMiddleware).
+// param controller The handler controller for authentication logic.
+func SetupTuyaSessionRoutes(router gin.IRouter, controller *controllers.TuyaAuthController) {
+	utils.LogDebug("SetupTuyaSessionRoutes initialized")
+	api := router.Group("/api/tuya")
+	{
+		// DELETE /api/tuya/auth/session
+		// Revokes the server-side session used to authenticate the request.
+		api.DELETE("/auth/session", controller.Logout)
+	}
 }
\ No newline at end of file
@@ -0,0 +1,54 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaAutomationRoutes registers endpoints for automation rules.
+//
+// param router The Gin router interface.
+// param controller The controller handling automation requests.
+func SetupTuyaAutomationRoutes(router gin.IRouter, controller *controllers.TuyaAutomationController) {
+	utils.LogDebug("SetupTuyaAutomationRoutes initialized")
+	api := router.Group("/api/automations")
+	{
+		// GET /api/automations
+		// Lists all automation rules.
+		api.GET("", controller.ListAutomations)
+
+		// POST /api/automations
+		// Creates an automation rule.
+		api.POST("", controller.CreateAutomation)
+
+		// GET /api/automations/:id
+		// Returns an automation rule.
+		api.GET("/:id", controller.GetAutomation)
+
+		// PUT /api/automations/:id
+		// Replaces the definition of an automation rule.
+		api.PUT("/:id", controller.UpdateAutomation)
+
+		// DELETE /api/automations/:id
+		// Deletes an automation rule and its history.
+		api.DELETE("/:id", controller.DeleteAutomation)
+
+		// POST /api/automations/:id/enable
+		// Enables an automation rule.
+		api.POST("/:id/enable", controller.EnableAutomation)
+
+		// POST /api/automations/:id/disable
+		// Disables an automation rule.
+		api.POST("/:id/disable", controller.DisableAutomation)
+
+		// POST /api/automations/:id/run
+		// Runs the actions of a rule immediately.
+		api.POST("/:id/run", controller.RunAutomation)
+
+		// GET /api/automations/:id/history
+		// Returns the latest runs of a rule.
+		api.GET("/:id/history", controller.GetAutomationHistory)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaBackupRoutes registers the backup export and restore endpoints.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling backups.
+func SetupTuyaBackupRoutes(router gin.IRouter, controller *controllers.TuyaBackupController) {
+	utils.LogDebug("SetupTuyaBackupRoutes initialized")
+	api := router.Group("/api/admin")
+	{
+		// POST /api/admin/backup
+		// Queues a job building a backup archive of the persistent data.
+		api.POST("/backup", controller.Export)
+
+		// GET /api/admin/backup/:job_id
+		// Downloads the archive built by a backup job.
+		api.GET("/backup/:job_id", controller.Download)
+
+		// POST /api/admin/restore
+		// Restores a backup archive.
+		api.POST("/restore", controller.Restore)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaBootstrapRoutes registers endpoints for the app cold start payload and favorite devices.
+//
+// param router The Gin router interface.
+// param controller The controller handling bootstrap and favorites requests.
+func SetupTuyaBootstrapRoutes(router gin.IRouter, controller *controllers.TuyaBootstrapController) {
+	utils.LogDebug("SetupTuyaBootstrapRoutes initialized")
+	api := router.Group("/api")
+	{
+		// GET /api/bootstrap
+		// Returns session, device summary, rooms, favorites and alerts in one response.
+		api.GET("/bootstrap", controller.GetBootstrap)
+
+		// GET /api/favorites
+		// Returns the favorite device IDs.
+		api.GET("/favorites", controller.GetFavorites)
+
+		// PUT /api/favorites
+		// Replaces the favorite device IDs.
+		api.PUT("/favorites", controller.SetFavorites)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCacheWarmRoutes registers the cache warm endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling cache warms.
+func SetupTuyaCacheWarmRoutes(router gin.IRouter, controller *controllers.TuyaCacheWarmController) {
+	utils.LogDebug("SetupTuyaCacheWarmRoutes initialized")
+	api := router.Group("/api/cache")
+	{
+		// POST /api/cache/warm
+		// Queues a job fetching device lists and specifications into the cache.
+		api.POST("/warm", controller.StartWarm)
+	}
+}
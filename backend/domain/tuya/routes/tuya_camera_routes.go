@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCameraRoutes registers stream and snapshot endpoints of smart cameras.
+//
+// param router The Gin router interface.
+// param controller The controller handling camera requests.
+func SetupTuyaCameraRoutes(router gin.IRouter, controller *controllers.TuyaCameraController) {
+	utils.LogDebug("SetupTuyaCameraRoutes initialized")
+	api := router.Group("/api/tuya/devices/:id")
+	{
+		// GET /api/tuya/devices/:id/stream
+		// Allocates a time-limited stream URL (hls, rtsp, flv, rtmp).
+		api.GET("/stream", controller.GetStream)
+
+		// GET /api/tuya/devices/:id/snapshot
+		// Captures a picture and returns its time-limited URL.
+		api.GET("/snapshot", controller.GetSnapshot)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCircadianRoutes registers endpoints for configuring adaptive (circadian) lighting.
+//
+// param router The Gin router interface.
+// param controller The controller handling adaptive lighting requests.
+func SetupTuyaCircadianRoutes(router gin.IRouter, controller *controllers.TuyaCircadianController) {
+	utils.LogDebug("SetupTuyaCircadianRoutes initialized")
+	api := router.Group("/api/tuya/circadian")
+	{
+		// GET /api/tuya/circadian
+		// Returns the configuration and per-light status.
+		api.GET("", controller.GetStatus)
+
+		// PUT /api/tuya/circadian
+		// Replaces the configuration.
+		api.PUT("", controller.SetConfig)
+
+		// POST /api/tuya/circadian/dispatch
+		// Adjusts all enrolled lights immediately.
+		api.POST("/dispatch", controller.Dispatch)
+
+		// POST /api/tuya/circadian/lights/:id/resume
+		// Clears a manual override.
+		api.POST("/lights/:id/resume", controller.ResumeLight)
+	}
+}
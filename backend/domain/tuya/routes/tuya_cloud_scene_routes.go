@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCloudSceneRoutes registers endpoints for the scenes and automations configured in the Smart Life app.
+//
+// param router The Gin router interface.
+// param controller The controller handling cloud scene requests.
+func SetupTuyaCloudSceneRoutes(router gin.IRouter, controller *controllers.TuyaCloudSceneController) {
+	utils.LogDebug("SetupTuyaCloudSceneRoutes initialized")
+	api := router.Group("/api/tuya/homes")
+	{
+		// GET /api/tuya/homes
+		// Lists the Smart Life homes of the user.
+		api.GET("", controller.ListHomes)
+
+		// GET /api/tuya/homes/:home_id/scenes
+		// Lists the tap-to-run scenes of a home.
+		api.GET("/:home_id/scenes", controller.ListScenes)
+
+		// POST /api/tuya/homes/:home_id/scenes/:scene_id/trigger
+		// Runs a tap-to-run scene.
+		api.POST("/:home_id/scenes/:scene_id/trigger", controller.TriggerScene)
+
+		// GET /api/tuya/homes/:home_id/automations
+		// Lists the automations of a home.
+		api.GET("/:home_id/automations", controller.ListAutomations)
+
+		// POST /api/tuya/homes/:home_id/automations/:automation_id/enable
+		// Enables an automation.
+		api.POST("/:home_id/automations/:automation_id/enable", controller.EnableAutomation)
+
+		// POST /api/tuya/homes/:home_id/automations/:automation_id/disable
+		// Disables an automation.
+		api.POST("/:home_id/automations/:automation_id/disable", controller.DisableAutomation)
+	}
+}
@@ -0,0 +1,56 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCommandApprovalRoutes registers endpoints for approving commands held for two-person approval.
+//
+// param router The Gin router interface.
+// param controller The controller handling command approvals.
+func SetupTuyaCommandApprovalRoutes(router gin.IRouter, controller *controllers.TuyaCommandApprovalController) {
+	utils.LogDebug("SetupTuyaCommandApprovalRoutes initialized")
+	api := router.Group("/api/tuya/approvals")
+	{
+		// GET /api/tuya/approvals
+		// Lists held commands.
+		api.GET("", controller.ListPendingActions)
+
+		// GET /api/tuya/approvals/:id
+		// Returns a held command.
+		api.GET("/:id", controller.GetPendingAction)
+
+		// POST /api/tuya/approvals/:id/approve
+		// Sends a held command on behalf of a second user.
+		api.POST("/:id/approve", controller.ApprovePendingAction)
+
+		// POST /api/tuya/approvals/:id/reject
+		// Discards a held command.
+		api.POST("/:id/reject", controller.RejectPendingAction)
+	}
+}
+
+// SetupCommandApprovalAdminRoutes registers endpoints for managing approval rules.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling command approvals.
+func SetupCommandApprovalAdminRoutes(router gin.IRouter, controller *controllers.TuyaCommandApprovalController) {
+	utils.LogDebug("SetupCommandApprovalAdminRoutes initialized")
+	api := router.Group("/api/admin/approval-rules")
+	{
+		// GET /api/admin/approval-rules
+		// Lists the devices requiring approval.
+		api.GET("", controller.ListRules)
+
+		// PUT /api/admin/approval-rules/:device_id
+		// Requires approval for commands of a device.
+		api.PUT("/:device_id", controller.SetRule)
+
+		// DELETE /api/admin/approval-rules/:device_id
+		// Removes the approval requirement of a device.
+		api.DELETE("/:device_id", controller.DeleteRule)
+	}
+}
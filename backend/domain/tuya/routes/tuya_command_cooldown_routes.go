@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCommandCooldownRoutes registers endpoints for inspecting learned command cooldowns.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling command cooldowns.
+func SetupTuyaCommandCooldownRoutes(router gin.IRouter, controller *controllers.TuyaCommandCooldownController) {
+	utils.LogDebug("SetupTuyaCommandCooldownRoutes initialized")
+	api := router.Group("/api/admin/command-cooldowns")
+	{
+		// GET /api/admin/command-cooldowns
+		// Lists learned cooldowns.
+		api.GET("", controller.ListCooldowns)
+
+		// DELETE /api/admin/command-cooldowns/:device_id
+		// Forgets the cooldown of a device.
+		api.DELETE("/:device_id", controller.ResetCooldown)
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaCommandQueueRoutes registers endpoints for queueing device commands and tracking their execution.
+//
+// param router The Gin router interface.
+// param controller The controller handling queued commands.
+func SetupTuyaCommandQueueRoutes(router gin.IRouter, controller *controllers.TuyaCommandQueueController) {
+	utils.LogDebug("SetupTuyaCommandQueueRoutes initialized")
+	api := router.Group("/api/tuya/commands")
+	{
+		// POST /api/tuya/commands
+		// Queues commands for a device, deduplicated by the Idempotency-Key header.
+		api.POST("", controller.SubmitCommand)
+
+		// GET /api/tuya/commands/:id
+		// Returns the execution status of a queued command.
+		api.GET("/:id", controller.GetCommand)
+	}
+}
@@ -24,4 +24,41 @@ func SetupTuyaControlRoutes(router gin.IRouter, controller *controllers.TuyaDevi
 		// Sends an infrared command (e.g., AC control) to an IR-enabled device.
 		api.POST("/devices/:id/commands/ir", controller.SendIRACCommand)
 	}
+}
+
+// SetupTuyaCategoryControlRoutes registers normalized control endpoints for specific device categories.
+// Clients send intent (speed level, brightness percent) and the backend translates it to the device's DP codes.
+//
+// param router The Gin router interface.
+// param controller The controller handling category-specific control requests.
+func SetupTuyaCategoryControlRoutes(router gin.IRouter, controller *controllers.TuyaCategoryControlController) {
+	utils.LogDebug("SetupTuyaCategoryControlRoutes initialized")
+	api := router.Group("/api/tuya")
+	{
+		// POST /api/tuya/devices/:id/fan
+		// Sets power, speed, oscillation and direction on fan devices (fs, fskg).
+		api.POST("/devices/:id/fan", controller.ControlFan)
+
+		// POST /api/tuya/devices/:id/dimmer
+		// Sets power and brightness on dimmer devices (tgq).
+		api.POST("/devices/:id/dimmer", controller.ControlDimmer)
+	}
+}
+
+// SetupTuyaClimateRoutes registers the normalized climate endpoints shared by IR and native air conditioners.
+//
+// param router The Gin router interface.
+// param controller The controller handling climate requests.
+func SetupTuyaClimateRoutes(router gin.IRouter, controller *controllers.TuyaClimateController) {
+	utils.LogDebug("SetupTuyaClimateRoutes initialized")
+	api := router.Group("/api/tuya")
+	{
+		// GET /api/tuya/devices/:id/climate
+		// Returns the normalized climate state (power, mode, temperatures, fan speed, swing).
+		api.GET("/devices/:id/climate", controller.GetClimate)
+
+		// POST /api/tuya/devices/:id/climate
+		// Applies normalized climate settings via IR commands or native DPs.
+		api.POST("/devices/:id/climate", controller.ControlClimate)
+	}
 }
\ No newline at end of file
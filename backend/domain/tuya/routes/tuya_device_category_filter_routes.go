@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceCategoryFilterRoutes registers the device category filter administration endpoints.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling the category filter.
+func SetupTuyaDeviceCategoryFilterRoutes(router gin.IRouter, controller *controllers.TuyaDeviceCategoryFilterController) {
+	utils.LogDebug("SetupTuyaDeviceCategoryFilterRoutes initialized")
+	api := router.Group("/api/admin/device-categories")
+	{
+		// GET /api/admin/device-categories
+		// Returns the effective category filter.
+		api.GET("", controller.GetFilter)
+
+		// PUT /api/admin/device-categories
+		// Replaces the category filter.
+		api.PUT("", controller.UpdateFilter)
+
+		// DELETE /api/admin/device-categories
+		// Restores the configured category filter.
+		api.DELETE("", controller.ResetFilter)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceChannelRoutes registers endpoints for naming and controlling the channels of multi-gang switches.
+//
+// param router The Gin router interface.
+// param controller The controller handling channel requests.
+func SetupTuyaDeviceChannelRoutes(router gin.IRouter, controller *controllers.TuyaDeviceChannelController) {
+	utils.LogDebug("SetupTuyaDeviceChannelRoutes initialized")
+	api := router.Group("/api/tuya")
+	{
+		// GET /api/tuya/devices/:id/channels
+		// Lists the channels of a multi-gang switch.
+		api.GET("/devices/:id/channels", controller.GetChannels)
+
+		// PUT /api/tuya/devices/:id/channels
+		// Names the channels of a multi-gang switch.
+		api.PUT("/devices/:id/channels", controller.RenameChannels)
+
+		// POST /api/tuya/devices/:id/channels/:channel/commands
+		// Switches a single channel on or off.
+		api.POST("/devices/:id/channels/:channel/commands", controller.SendChannelCommand)
+	}
+}
@@ -0,0 +1,52 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceClaimRoutes registers endpoints for tenants claiming devices.
+//
+// param router The Gin router interface.
+// param controller The controller handling device claims.
+func SetupTuyaDeviceClaimRoutes(router gin.IRouter, controller *controllers.TuyaDeviceClaimController) {
+	utils.LogDebug("SetupTuyaDeviceClaimRoutes initialized")
+	api := router.Group("/api/tuya/claims")
+	{
+		// POST /api/tuya/claims
+		// Submits a claim on a device for admin approval.
+		api.POST("", controller.SubmitClaim)
+
+		// GET /api/tuya/claims
+		// Lists the caller's claims.
+		api.GET("", controller.ListMyClaims)
+	}
+}
+
+// SetupDeviceClaimAdminRoutes registers endpoints for reviewing device claims.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling device claims.
+func SetupDeviceClaimAdminRoutes(router gin.IRouter, controller *controllers.TuyaDeviceClaimController) {
+	utils.LogDebug("SetupDeviceClaimAdminRoutes initialized")
+	api := router.Group("/api/admin/claims")
+	{
+		// GET /api/admin/claims
+		// Lists the claims of all tenants.
+		api.GET("", controller.ListClaims)
+
+		// POST /api/admin/claims/:id/approve
+		// Assigns the device to the claiming tenant.
+		api.POST("/:id/approve", controller.ApproveClaim)
+
+		// POST /api/admin/claims/:id/reject
+		// Rejects a pending claim.
+		api.POST("/:id/reject", controller.RejectClaim)
+
+		// DELETE /api/admin/claims/devices/:id
+		// Releases a device so it can be claimed again.
+		api.DELETE("/devices/:id", controller.ReleaseDevice)
+	}
+}
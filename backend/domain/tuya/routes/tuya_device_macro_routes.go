@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceMacroRoutes registers endpoints for storing and running device command macros.
+//
+// param router The Gin router interface.
+// param controller The controller handling macro requests.
+func SetupTuyaDeviceMacroRoutes(router gin.IRouter, controller *controllers.TuyaDeviceMacroController) {
+	utils.LogDebug("SetupTuyaDeviceMacroRoutes initialized")
+	api := router.Group("/api/tuya/devices")
+	{
+		// GET /api/tuya/devices/:id/macros
+		// Lists the macros of a device.
+		api.GET("/:id/macros", controller.ListMacros)
+
+		// PUT /api/tuya/devices/:id/macros/:name
+		// Creates or replaces a macro.
+		api.PUT("/:id/macros/:name", controller.SaveMacro)
+
+		// DELETE /api/tuya/devices/:id/macros/:name
+		// Removes a macro.
+		api.DELETE("/:id/macros/:name", controller.DeleteMacro)
+
+		// POST /api/tuya/devices/:id/macros/:name
+		// Expands a macro with its arguments and sends the commands.
+		api.POST("/:id/macros/:name", controller.RunMacro)
+	}
+}
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceMetadataRoutes registers endpoints for renaming devices and editing their local metadata,
+// favorites and custom order.
+//
+// param router The Gin router interface.
+// param controller The controller handling rename and metadata requests.
+func SetupTuyaDeviceMetadataRoutes(router gin.IRouter, controller *controllers.TuyaDeviceMetadataController) {
+	utils.LogDebug("SetupTuyaDeviceMetadataRoutes initialized")
+	api := router.Group("/api/tuya")
+	{
+		// PUT /api/tuya/devices/order
+		// Saves the custom device order.
+		api.PUT("/devices/order", controller.SetOrder)
+
+		// PUT /api/tuya/devices/:id
+		// Renames a device in the Tuya cloud.
+		api.PUT("/devices/:id", controller.RenameDevice)
+
+		// GET /api/tuya/devices/:id/metadata
+		// Returns the local label, icon, sort order and favorite flag of a device.
+		api.GET("/devices/:id/metadata", controller.GetMetadata)
+
+		// PUT /api/tuya/devices/:id/metadata
+		// Changes the local metadata of a device.
+		api.PUT("/devices/:id/metadata", controller.UpdateMetadata)
+
+		// POST /api/tuya/devices/:id/favorite
+		// Marks or unmarks a device as favorite.
+		api.POST("/devices/:id/favorite", controller.SetFavorite)
+	}
+}
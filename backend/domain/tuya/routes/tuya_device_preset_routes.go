@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDevicePresetRoutes registers endpoints for storing and executing device command presets.
+//
+// param router The Gin router interface.
+// param controller The controller handling preset requests.
+func SetupTuyaDevicePresetRoutes(router gin.IRouter, controller *controllers.TuyaDevicePresetController) {
+	utils.LogDebug("SetupTuyaDevicePresetRoutes initialized")
+	api := router.Group("/api/tuya/devices")
+	{
+		// GET /api/tuya/devices/:id/presets
+		// Lists the presets of a device.
+		api.GET("/:id/presets", controller.ListPresets)
+
+		// POST /api/tuya/devices/:id/presets
+		// Creates or replaces a preset.
+		api.POST("/:id/presets", controller.SavePreset)
+
+		// DELETE /api/tuya/devices/:id/presets/:name
+		// Removes a preset.
+		api.DELETE("/:id/presets/:name", controller.DeletePreset)
+
+		// POST /api/tuya/devices/:id/presets/:name/execute
+		// Sends the commands of a preset in one call.
+		api.POST("/:id/presets/:name/execute", controller.ExecutePreset)
+	}
+}
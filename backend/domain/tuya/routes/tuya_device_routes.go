@@ -14,11 +14,17 @@ import (
 // param getAllDevicesController Controller for listing all devices.
 // param getDeviceByIDController Controller for fetching a single device by ID.
 // param sensorController Controller for retrieving sensor status.
+// param changeLogController Controller for the device discovery change log.
+// param comparisonController Controller comparing two devices.
+// param statusSnapshotController Controller for the compact status snapshot of all devices.
 func SetupTuyaDeviceRoutes(
 	router gin.IRouter,
 	getAllDevicesController *controllers.TuyaGetAllDevicesController,
 	getDeviceByIDController *controllers.TuyaGetDeviceByIDController,
 	sensorController *controllers.TuyaSensorController,
+	changeLogController *controllers.TuyaDeviceChangeLogController,
+	comparisonController *controllers.TuyaDeviceComparisonController,
+	statusSnapshotController *controllers.TuyaDeviceStatusSnapshotController,
 ) {
 	utils.LogDebug("SetupTuyaDeviceRoutes initialized")
 	api := router.Group("/api/tuya")
@@ -27,6 +33,18 @@ func SetupTuyaDeviceRoutes(
 		// Retrieves a list of all devices associated with the user account.
 		api.GET("/devices", getAllDevicesController.GetAllDevices)
 
+		// GET /api/tuya/devices/changes/log
+		// Retrieves devices added, removed, renamed or re-categorized since earlier refreshes.
+		api.GET("/devices/changes/log", changeLogController.GetChangeLog)
+
+		// GET /api/tuya/devices/status
+		// Retrieves the online state and key status values of all devices in one compact payload.
+		api.GET("/devices/status", statusSnapshotController.GetStatusSnapshot)
+
+		// GET /api/tuya/devices/compare
+		// Diffs the specs, firmware, status and recent history of two devices.
+		api.GET("/devices/compare", comparisonController.CompareDevices)
+
 		// GET /api/tuya/devices/:id
 		// Retrieves detailed information for a specific device identified by ID.
 		api.GET("/devices/:id", getDeviceByIDController.GetDeviceByID)
@@ -34,5 +52,17 @@ func SetupTuyaDeviceRoutes(
 		// GET /api/tuya/devices/:id/sensor
 		// Retrieves formatted sensor data (temperature, humidity) for a specific device.
 		api.GET("/devices/:id/sensor", sensorController.GetSensorData)
+
+		// GET /api/tuya/devices/:id/sensor/history
+		// Retrieves recorded sensor readings aggregated into a time series for charting.
+		api.GET("/devices/:id/sensor/history", sensorController.GetSensorHistory)
+
+		// GET /api/tuya/devices/:id/sensor/chart
+		// Renders the sensor history as a PNG or SVG chart for notification channels.
+		api.GET("/devices/:id/sensor/chart", sensorController.GetSensorChart)
+
+		// POST /api/tuya/devices/:id/alarm/test
+		// Publishes a simulated alarm event for a water leak or smoke sensor.
+		api.POST("/devices/:id/alarm/test", sensorController.TestAlarm)
 	}
 }
\ No newline at end of file
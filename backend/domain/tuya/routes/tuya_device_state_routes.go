@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceStateRoutes registers endpoints for the state history of devices.
+//
+// param router The Gin router interface.
+// param controller The controller handling state history and undo requests.
+func SetupTuyaDeviceStateRoutes(router gin.IRouter, controller *controllers.TuyaDeviceStateController) {
+	utils.LogDebug("SetupTuyaDeviceStateRoutes initialized")
+	api := router.Group("/api/tuya/devices")
+	{
+		// GET /api/tuya/devices/:id/state/history
+		// Lists the last known state of a device and the states it replaced.
+		api.GET("/:id/state/history", controller.GetStateHistory)
+
+		// POST /api/tuya/devices/:id/state/undo
+		// Re-sends the previous settings of a device.
+		api.POST("/:id/state/undo", controller.UndoState)
+	}
+}
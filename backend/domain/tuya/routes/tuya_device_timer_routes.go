@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDeviceTimerRoutes registers endpoints for delayed on/off timers of devices.
+//
+// param router The Gin router interface.
+// param controller The controller handling timer requests.
+func SetupTuyaDeviceTimerRoutes(router gin.IRouter, controller *controllers.TuyaDeviceTimerController) {
+	utils.LogDebug("SetupTuyaDeviceTimerRoutes initialized")
+	api := router.Group("/api/tuya/devices")
+	{
+		// POST /api/tuya/devices/:id/timer
+		// Turns a switch on or off after a delay.
+		api.POST("/:id/timer", controller.SetTimer)
+
+		// GET /api/tuya/devices/:id/timer
+		// Lists the pending timers of a device.
+		api.GET("/:id/timer", controller.ListTimers)
+
+		// DELETE /api/tuya/devices/:id/timer
+		// Cancels pending timers.
+		api.DELETE("/:id/timer", controller.CancelTimers)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaDoorLockRoutes registers endpoints for unlock records and temporary passwords of smart locks.
+//
+// param router The Gin router interface.
+// param controller The controller handling door lock requests.
+func SetupTuyaDoorLockRoutes(router gin.IRouter, controller *controllers.TuyaDoorLockController) {
+	utils.LogDebug("SetupTuyaDoorLockRoutes initialized")
+	api := router.Group("/api/tuya/devices/:id/lock")
+	{
+		// GET /api/tuya/devices/:id/lock/records
+		// Lists the unlock records of a lock.
+		api.GET("/records", controller.ListRecords)
+
+		// GET /api/tuya/devices/:id/lock/temp-passwords
+		// Lists the temporary passwords of a lock.
+		api.GET("/temp-passwords", controller.ListTempPasswords)
+
+		// POST /api/tuya/devices/:id/lock/temp-passwords
+		// Creates a temporary password through the ticket encryption flow.
+		api.POST("/temp-passwords", controller.CreateTempPassword)
+
+		// DELETE /api/tuya/devices/:id/lock/temp-passwords/:password_id
+		// Deletes a temporary password.
+		api.DELETE("/temp-passwords/:password_id", controller.DeleteTempPassword)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaFeatureFlagRoutes registers the feature flag administration endpoints.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling feature flags.
+func SetupTuyaFeatureFlagRoutes(router gin.IRouter, controller *controllers.TuyaFeatureFlagController) {
+	utils.LogDebug("SetupTuyaFeatureFlagRoutes initialized")
+	api := router.Group("/api/admin/feature-flags")
+	{
+		// GET /api/admin/feature-flags
+		// Lists every feature flag with its effective rollout.
+		api.GET("", controller.ListFlags)
+
+		// PUT /api/admin/feature-flags/:name
+		// Replaces the rollout of a feature flag.
+		api.PUT("/:name", controller.UpdateFlag)
+
+		// DELETE /api/admin/feature-flags/:name
+		// Restores the configured or default rollout.
+		api.DELETE("/:name", controller.ResetFlag)
+
+		// GET /api/admin/feature-flags/:name/devices/:device_id
+		// Reports whether a feature flag is enabled for a device.
+		api.GET("/:name/devices/:device_id", controller.Evaluate)
+	}
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaHouseModeRoutes registers endpoints for the house mode.
+//
+// param router The Gin router interface.
+// param controller The controller handling house mode requests.
+func SetupTuyaHouseModeRoutes(router gin.IRouter, controller *controllers.TuyaHouseModeController) {
+	utils.LogDebug("SetupTuyaHouseModeRoutes initialized")
+	api := router.Group("/api/house-mode")
+	{
+		// GET /api/house-mode
+		// Returns the current house mode.
+		api.GET("", controller.GetHouseMode)
+
+		// PUT /api/house-mode
+		// Switches the house mode.
+		api.PUT("", controller.SetHouseMode)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaIntentRoutes registers the endpoint for typed commands.
+//
+// param router The Gin router interface.
+// param controller The controller handling typed commands.
+func SetupTuyaIntentRoutes(router gin.IRouter, controller *controllers.TuyaIntentController) {
+	utils.LogDebug("SetupTuyaIntentRoutes initialized")
+	api := router.Group("/api")
+	{
+		// POST /api/intent
+		// Resolves a command like "turn off meeting room lights" to devices and runs it.
+		api.POST("/intent", controller.ExecuteIntent)
+	}
+}
@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaIRLearnedCodeRoutes registers endpoints for saving and replaying learned raw IR codes.
+//
+// param router The Gin router interface.
+// param controller The controller handling learned code requests.
+func SetupTuyaIRLearnedCodeRoutes(router gin.IRouter, controller *controllers.TuyaIRLearnedCodeController) {
+	utils.LogDebug("SetupTuyaIRLearnedCodeRoutes initialized")
+	api := router.Group("/api/tuya/infrareds")
+	{
+		// GET /api/tuya/infrareds/:id/learned-codes
+		// Lists the codes saved on the IR hub.
+		api.GET("/:id/learned-codes", controller.ListCodes)
+
+		// POST /api/tuya/infrareds/:id/learned-codes
+		// Saves a learned code under a name.
+		api.POST("/:id/learned-codes", controller.SaveCode)
+
+		// DELETE /api/tuya/infrareds/:id/learned-codes/:name
+		// Removes a saved code.
+		api.DELETE("/:id/learned-codes/:name", controller.DeleteCode)
+
+		// POST /api/tuya/infrareds/:id/learned-codes/:name/send
+		// Replays a saved code through the hub.
+		api.POST("/:id/learned-codes/:name/send", controller.SendCode)
+	}
+}
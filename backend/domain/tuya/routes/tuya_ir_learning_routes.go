@@ -0,0 +1,34 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaIRLearningRoutes registers endpoints for learning IR codes and managing custom remotes.
+//
+// param router The Gin router interface.
+// param controller The controller handling the learning workflow.
+func SetupTuyaIRLearningRoutes(router gin.IRouter, controller *controllers.TuyaIRLearningController) {
+	utils.LogDebug("SetupTuyaIRLearningRoutes initialized")
+	api := router.Group("/api/tuya")
+	{
+		// POST /api/tuya/devices/:id/ir/learning
+		// Puts the IR hub into learning mode.
+		api.POST("/devices/:id/ir/learning", controller.StartLearning)
+
+		// DELETE /api/tuya/devices/:id/ir/learning
+		// Takes the IR hub out of learning mode.
+		api.DELETE("/devices/:id/ir/learning", controller.StopLearning)
+
+		// GET /api/tuya/devices/:id/ir/learning/code
+		// Polls for the code captured since learning started.
+		api.GET("/devices/:id/ir/learning/code", controller.GetLearnedCode)
+
+		// POST /api/tuya/devices/:id/ir/keys
+		// Saves a learned code as a named key under a custom remote.
+		api.POST("/devices/:id/ir/keys", controller.SaveLearnedKey)
+	}
+}
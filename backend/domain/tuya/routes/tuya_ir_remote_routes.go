@@ -0,0 +1,46 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaIRRemoteRoutes registers endpoints for discovering, adding and pressing the keys of IR remotes.
+//
+// param router The Gin router interface.
+// param controller The controller handling IR remote requests.
+func SetupTuyaIRRemoteRoutes(router gin.IRouter, controller *controllers.TuyaIRRemoteController) {
+	utils.LogDebug("SetupTuyaIRRemoteRoutes initialized")
+	api := router.Group("/api/tuya/infrareds")
+	{
+		// GET /api/tuya/infrareds/:id/remotes
+		// Lists the remotes configured on the IR hub.
+		api.GET("/:id/remotes", controller.ListRemotes)
+
+		// GET /api/tuya/infrareds/:id/remotes/:remote_id/keys
+		// Returns the keys of a remote.
+		api.GET("/:id/remotes/:remote_id/keys", controller.GetRemoteKeys)
+
+		// POST /api/tuya/infrareds/:id/remotes/:remote_id/command
+		// Presses a key of a remote.
+		api.POST("/:id/remotes/:remote_id/command", controller.SendKey)
+
+		// POST /api/tuya/infrareds/:id/remotes
+		// Adds a remote from a code library.
+		api.POST("/:id/remotes", controller.AddRemote)
+
+		// GET /api/tuya/infrareds/:id/categories
+		// Lists the remote categories of the IR hub.
+		api.GET("/:id/categories", controller.ListCategories)
+
+		// GET /api/tuya/infrareds/:id/categories/:category_id/brands
+		// Lists the brands of a category.
+		api.GET("/:id/categories/:category_id/brands", controller.ListBrands)
+
+		// GET /api/tuya/infrareds/:id/categories/:category_id/brands/:brand_id/remote-indexes
+		// Lists the code libraries of a brand.
+		api.GET("/:id/categories/:category_id/brands/:brand_id/remote-indexes", controller.ListRemoteIndexes)
+	}
+}
@@ -0,0 +1,50 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaLightGroupRoutes registers endpoints for light groups and their colour scene presets.
+//
+// param router The Gin router interface.
+// param controller The controller handling light group requests.
+func SetupTuyaLightGroupRoutes(router gin.IRouter, controller *controllers.TuyaLightGroupController) {
+	utils.LogDebug("SetupTuyaLightGroupRoutes initialized")
+	api := router.Group("/api/tuya/light-groups")
+	{
+		// GET /api/tuya/light-groups
+		// Lists all light groups.
+		api.GET("", controller.ListGroups)
+
+		// POST /api/tuya/light-groups
+		// Creates a light group.
+		api.POST("", controller.CreateGroup)
+
+		// GET /api/tuya/light-groups/:id
+		// Returns a light group with its presets.
+		api.GET("/:id", controller.GetGroup)
+
+		// DELETE /api/tuya/light-groups/:id
+		// Deletes a light group.
+		api.DELETE("/:id", controller.DeleteGroup)
+
+		// PUT /api/tuya/light-groups/:id/presets/:preset
+		// Creates or replaces a custom preset.
+		api.PUT("/:id/presets/:preset", controller.SavePreset)
+
+		// DELETE /api/tuya/light-groups/:id/presets/:preset
+		// Deletes a custom preset.
+		api.DELETE("/:id/presets/:preset", controller.DeletePreset)
+
+		// POST /api/tuya/light-groups/:id/presets/:preset/capture
+		// Saves the current state of the group's lights as a preset.
+		api.POST("/:id/presets/:preset/capture", controller.CapturePreset)
+
+		// POST /api/tuya/light-groups/:id/presets/:preset/apply
+		// Applies a preset to every light in the group.
+		api.POST("/:id/presets/:preset/apply", controller.ApplyPreset)
+	}
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaLocalSceneRoutes registers endpoints for whole-house scenes stored by Teralux.
+//
+// param router The Gin router interface.
+// param controller The controller handling scene requests.
+func SetupTuyaLocalSceneRoutes(router gin.IRouter, controller *controllers.TuyaLocalSceneController) {
+	utils.LogDebug("SetupTuyaLocalSceneRoutes initialized")
+	api := router.Group("/api/tuya/scenes")
+	{
+		// GET /api/tuya/scenes
+		// Lists all scenes.
+		api.GET("", controller.ListScenes)
+
+		// POST /api/tuya/scenes
+		// Creates a scene.
+		api.POST("", controller.CreateScene)
+
+		// GET /api/tuya/scenes/:id
+		// Returns a scene with its steps.
+		api.GET("/:id", controller.GetScene)
+
+		// PUT /api/tuya/scenes/:id
+		// Replaces a scene.
+		api.PUT("/:id", controller.UpdateScene)
+
+		// DELETE /api/tuya/scenes/:id
+		// Deletes a scene.
+		api.DELETE("/:id", controller.DeleteScene)
+
+		// POST /api/tuya/scenes/:id/run
+		// Runs a scene as a background job.
+		api.POST("/:id/run", controller.RunScene)
+	}
+}
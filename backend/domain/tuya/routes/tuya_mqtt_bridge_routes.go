@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaMQTTBridgeRoutes registers the Home Assistant MQTT bridge endpoints.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller handling the MQTT bridge.
+func SetupTuyaMQTTBridgeRoutes(router gin.IRouter, controller *controllers.TuyaMQTTBridgeController) {
+	utils.LogDebug("SetupTuyaMQTTBridgeRoutes initialized")
+	api := router.Group("/api/admin/mqtt-bridge")
+	{
+		// GET /api/admin/mqtt-bridge
+		// Reports the connection state of the bridge.
+		api.GET("", controller.GetStatus)
+
+		// POST /api/admin/mqtt-bridge/sync
+		// Republishes the devices to Home Assistant.
+		api.POST("/sync", controller.Sync)
+	}
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaPermissionCheckRoutes registers the Tuya permission self-check endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller reporting missing permissions.
+func SetupTuyaPermissionCheckRoutes(router gin.IRouter, controller *controllers.TuyaPermissionCheckController) {
+	utils.LogDebug("SetupTuyaPermissionCheckRoutes initialized")
+	api := router.Group("/api/admin/permissions")
+	{
+		// GET /api/admin/permissions
+		// Reports which Tuya API families the cloud project is not permitted to call.
+		api.GET("", controller.GetPermissions)
+	}
+}
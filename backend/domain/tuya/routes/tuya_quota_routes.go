@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaQuotaRoutes registers the Tuya API quota monitoring endpoint.
+//
+// param router The Gin router interface (protected by ApiKeyMiddleware).
+// param controller The controller reporting quota usage.
+func SetupTuyaQuotaRoutes(router gin.IRouter, controller *controllers.TuyaQuotaController) {
+	utils.LogDebug("SetupTuyaQuotaRoutes initialized")
+	api := router.Group("/api/admin/quota")
+	{
+		// GET /api/admin/quota
+		// Reports Tuya API calls per endpoint against the daily budgets.
+		api.GET("", controller.GetQuota)
+	}
+}
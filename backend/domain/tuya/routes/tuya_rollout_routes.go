@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaRolloutRoutes registers endpoints for staged rollouts of commands across many devices.
+//
+// param router The Gin router interface.
+// param controller The controller handling rollout requests.
+func SetupTuyaRolloutRoutes(router gin.IRouter, controller *controllers.TuyaRolloutController) {
+	utils.LogDebug("SetupTuyaRolloutRoutes initialized")
+	api := router.Group("/api/tuya/rollouts")
+	{
+		// POST /api/tuya/rollouts
+		// Starts a rollout executed in waves as a background job.
+		api.POST("", controller.StartRollout)
+	}
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaRoomRoutes registers endpoints for rooms and room-wide commands.
+//
+// param router The Gin router interface.
+// param controller The controller handling room requests.
+func SetupTuyaRoomRoutes(router gin.IRouter, controller *controllers.TuyaRoomController) {
+	utils.LogDebug("SetupTuyaRoomRoutes initialized")
+	api := router.Group("/api/rooms")
+	{
+		// GET /api/rooms
+		// Lists all rooms.
+		api.GET("", controller.ListRooms)
+
+		// POST /api/rooms
+		// Creates a room.
+		api.POST("", controller.CreateRoom)
+
+		// GET /api/rooms/:id
+		// Returns a room with its devices.
+		api.GET("/:id", controller.GetRoom)
+
+		// PUT /api/rooms/:id
+		// Renames a room and/or replaces its devices.
+		api.PUT("/:id", controller.UpdateRoom)
+
+		// DELETE /api/rooms/:id
+		// Deletes a room.
+		api.DELETE("/:id", controller.DeleteRoom)
+
+		// POST /api/rooms/:id/commands
+		// Sends the same commands to every device in the room.
+		api.POST("/:id/commands", controller.SendCommands)
+	}
+}
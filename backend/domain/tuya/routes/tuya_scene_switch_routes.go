@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaSceneSwitchRoutes registers endpoints for configuring wireless scene switches and ingesting their events.
+//
+// param router The Gin router interface.
+// param controller The controller handling scene switch bindings and events.
+func SetupTuyaSceneSwitchRoutes(router gin.IRouter, controller *controllers.TuyaSceneSwitchController) {
+	utils.LogDebug("SetupTuyaSceneSwitchRoutes initialized")
+	api := router.Group("/api/tuya/scene-switches")
+	{
+		// GET /api/tuya/scene-switches/:id/bindings
+		// Lists the button bindings of a scene switch.
+		api.GET("/:id/bindings", controller.GetBindings)
+
+		// PUT /api/tuya/scene-switches/:id/bindings
+		// Replaces the button bindings of a scene switch.
+		api.PUT("/:id/bindings", controller.SetBindings)
+
+		// POST /api/tuya/scene-switches/:id/events
+		// Runs the action bound to a reported button event.
+		api.POST("/:id/events", controller.HandleEvent)
+	}
+}
@@ -0,0 +1,30 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupTuyaStandbyKillerRoutes registers endpoints for configuring standby killer rules on metered plugs.
+//
+// param router The Gin router interface.
+// param controller The controller handling standby killer requests.
+func SetupTuyaStandbyKillerRoutes(router gin.IRouter, controller *controllers.TuyaStandbyKillerController) {
+	utils.LogDebug("SetupTuyaStandbyKillerRoutes initialized")
+	api := router.Group("/api/tuya/standby-killer")
+	{
+		// GET /api/tuya/standby-killer
+		// Lists all rules with their tracking state.
+		api.GET("", controller.ListRules)
+
+		// PUT /api/tuya/standby-killer/:id
+		// Creates or replaces the rule of a plug.
+		api.PUT("/:id", controller.SetRule)
+
+		// DELETE /api/tuya/standby-killer/:id
+		// Removes the rule of a plug.
+		api.DELETE("/:id", controller.DeleteRule)
+	}
+}
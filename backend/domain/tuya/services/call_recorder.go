@@ -0,0 +1,33 @@
+package services
+
+import "net/http"
+
+// UpstreamCallRecorder is notified of every request sent to the Tuya API (e.g., to track quota consumption).
+type UpstreamCallRecorder interface {
+	RecordCall(method, path string)
+}
+
+// recordingTransport reports each request to a recorder before sending it.
+type recordingTransport struct {
+	base     http.RoundTripper
+	recorder UpstreamCallRecorder
+}
+
+// RoundTrip records the request and sends it with the base transport.
+//
+// param req The outgoing request.
+// return *http.Response The upstream response.
+// return error An error if the request fails.
+func (t *recordingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.recorder.RecordCall(req.Method, req.URL.Path)
+	return t.base.RoundTrip(req)
+}
+
+// withCallRecorder makes a client report every request it sends to the recorder.
+func withCallRecorder(client *http.Client, recorder UpstreamCallRecorder) {
+	base := client.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	client.Transport = &recordingTransport{base: base, recorder: recorder}
+}
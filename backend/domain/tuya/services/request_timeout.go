@@ -0,0 +1,97 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"teralux_app/domain/common/utils"
+	"time"
+)
+
+const (
+	defaultTuyaHTTPTimeout    = 30 * time.Second
+	defaultTuyaCommandTimeout = 5 * time.Second
+	defaultTuyaListTimeout    = 10 * time.Second
+)
+
+// timeoutClass groups upstream calls that share a per-call deadline.
+type timeoutClass int
+
+const (
+	// timeoutDefault calls are only bounded by the HTTP client timeout.
+	timeoutDefault timeoutClass = iota
+	// timeoutCommand calls send commands a user is waiting on.
+	timeoutCommand
+	// timeoutList calls fetch device lists and batch status.
+	timeoutList
+)
+
+// requestTimeouts holds the resolved deadline of each timeout class.
+type requestTimeouts struct {
+	command time.Duration
+	list    time.Duration
+}
+
+// loadRequestTimeouts reads the per-call deadlines from the config, falling back to the defaults.
+func loadRequestTimeouts() requestTimeouts {
+	config := utils.GetConfig()
+	return requestTimeouts{
+		command: parseTimeout(config.TuyaCommandTimeout, defaultTuyaCommandTimeout),
+		list:    parseTimeout(config.TuyaListTimeout, defaultTuyaListTimeout),
+	}
+}
+
+// forClass returns the deadline of a class; zero means no per-call deadline.
+func (t requestTimeouts) forClass(class timeoutClass) time.Duration {
+	switch class {
+	case timeoutCommand:
+		return t.command
+	case timeoutList:
+		return t.list
+	default:
+		return 0
+	}
+}
+
+// newTuyaHTTPClient creates the HTTP client shared by a service on the pooled transport, using
+// TUYA_HTTP_TIMEOUT as the overall limit. TuyaClient retries itself, so the client sends each request once.
+func newTuyaHTTPClient() *http.Client {
+	return httpclient.NewWithoutRetry(parseTimeout(utils.GetConfig().TuyaHTTPTimeout, defaultTuyaHTTPTimeout))
+}
+
+// parseTimeout parses a duration setting, returning the fallback when it is empty or invalid.
+func parseTimeout(value string, fallback time.Duration) time.Duration {
+	if value == "" {
+		return fallback
+	}
+	timeout, err := time.ParseDuration(value)
+	if err != nil || timeout <= 0 {
+		utils.LogWarn("Invalid timeout %q, using %s", value, fallback)
+		return fallback
+	}
+	return timeout
+}
+
+// withDeadline applies a per-call deadline to a request. The returned cancel func must run once the
+// response body has been consumed, not when the request returns.
+func withDeadline(req *http.Request, timeout time.Duration) (*http.Request, context.CancelFunc) {
+	if timeout <= 0 {
+		return req, func() {}
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), timeout)
+	return req.WithContext(ctx), cancel
+}
+
+// cancelOnClose releases a per-call deadline when the response body is closed.
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close closes the body and releases the deadline.
+func (b *cancelOnClose) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
@@ -0,0 +1,25 @@
+package services
+
+import (
+	"net/http"
+	"teralux_app/domain/common/utils"
+	"time"
+)
+
+// doTimedRequest executes an upstream request and records its duration on the request's timing metadata, if any,
+// and in the upstream latency average used for load shedding.
+// A positive timeout bounds the call, including reading the response body.
+func doTimedRequest(client *http.Client, req *http.Request, timeout time.Duration) (*http.Response, error) {
+	req, cancel := withDeadline(req, timeout)
+	start := time.Now()
+	resp, err := client.Do(req)
+	elapsed := time.Since(start)
+	utils.RequestMetaFromContext(req.Context()).AddUpstream(elapsed)
+	utils.RecordUpstreamLatency(elapsed)
+	if err != nil {
+		cancel()
+		return resp, err
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
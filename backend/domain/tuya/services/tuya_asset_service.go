@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+)
+
+// TuyaAssetService manages interactions with Tuya's asset API endpoints. Assets group the devices of
+// IoT Core projects, which have no user to list devices by.
+type TuyaAssetService struct {
+	client *TuyaClient
+}
+
+// NewTuyaAssetService initializes a new instance of TuyaAssetService.
+//
+// param client The TuyaClient signing and sending the requests.
+// return *TuyaAssetService A pointer to the initialized service.
+func NewTuyaAssetService(client *TuyaClient) *TuyaAssetService {
+	return &TuyaAssetService{
+		client: client,
+	}
+}
+
+// FetchAssets retrieves one page of the project assets.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the asset list endpoint, including the query.
+// param accessToken The current access token.
+// return *entities.TuyaAssetsResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaAssetService) FetchAssets(ctx context.Context, path, accessToken string) (*entities.TuyaAssetsResponse, error) {
+	var assetsResponse entities.TuyaAssetsResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &assetsResponse); err != nil {
+		utils.LogError("FetchAssets: %v", err)
+		return nil, err
+	}
+
+	return &assetsResponse, nil
+}
+
+// FetchAssetDevices retrieves one page of the device IDs of an asset.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the asset device list endpoint, including the query.
+// param accessToken The current access token.
+// return *entities.TuyaAssetDevicesResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaAssetService) FetchAssetDevices(ctx context.Context, path, accessToken string) (*entities.TuyaAssetDevicesResponse, error) {
+	var devicesResponse entities.TuyaAssetDevicesResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &devicesResponse); err != nil {
+		utils.LogError("FetchAssetDevices: %v", err)
+		return nil, err
+	}
+
+	utils.LogDebug("FetchAssetDevices: Fetched %d device IDs", len(devicesResponse.Result.List))
+	return &devicesResponse, nil
+}
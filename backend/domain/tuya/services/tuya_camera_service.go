@@ -0,0 +1,57 @@
+package services
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+)
+
+// TuyaCameraService manages interactions with Tuya's IPC (camera) API endpoints.
+type TuyaCameraService struct {
+	client *TuyaClient
+}
+
+// NewTuyaCameraService initializes a new instance of TuyaCameraService.
+//
+// param client The TuyaClient signing and sending the requests.
+// return *TuyaCameraService A pointer to the initialized service.
+func NewTuyaCameraService(client *TuyaClient) *TuyaCameraService {
+	return &TuyaCameraService{
+		client: client,
+	}
+}
+
+// AllocateStream requests a stream URL of a camera.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the stream allocation endpoint.
+// param accessToken The current access token.
+// param body The JSON-encoded entities.TuyaStreamAllocateRequest.
+// return *entities.TuyaMediaURLResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaCameraService) AllocateStream(ctx context.Context, path, accessToken string, body []byte) (*entities.TuyaMediaURLResponse, error) {
+	var streamResponse entities.TuyaMediaURLResponse
+	if err := s.client.Post(ctx, path, accessToken, body, timeoutCommand, &streamResponse); err != nil {
+		utils.LogError("AllocateStream: %v", err)
+		return nil, err
+	}
+
+	return &streamResponse, nil
+}
+
+// CaptureSnapshot asks a camera to capture a picture and returns its URL.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the capture endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaMediaURLResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaCameraService) CaptureSnapshot(ctx context.Context, path, accessToken string) (*entities.TuyaMediaURLResponse, error) {
+	var snapshotResponse entities.TuyaMediaURLResponse
+	if err := s.client.Post(ctx, path, accessToken, nil, timeoutCommand, &snapshotResponse); err != nil {
+		utils.LogError("CaptureSnapshot: %v", err)
+		return nil, err
+	}
+
+	return &snapshotResponse, nil
+}
@@ -0,0 +1,271 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/infrastructure/httpclient"
+	"teralux_app/domain/common/utils"
+	tuya_utils "teralux_app/domain/tuya/utils"
+	"time"
+)
+
+const tuyaSignMethod = "HMAC-SHA256"
+
+// TuyaClient sends signed requests to the Tuya OpenAPI.
+// It resolves paths against TUYA_BASE_URL, adds the timestamp, nonce and HMAC-SHA256 signature headers,
+// and retries GET, PUT and DELETE requests on network errors, 429 and 5xx responses following the shared
+// HTTP client retry policy (HTTP_CLIENT_RETRY_*).
+// POST requests are sent once, since they carry commands that must not be repeated.
+type TuyaClient struct {
+	client      *http.Client
+	timeouts    requestTimeouts
+	retry       httpclient.RetryPolicy
+	clock       utils.Clock
+	ids         utils.IDGenerator
+	credentials *TuyaCredentials
+}
+
+// TuyaCredentials are the cloud project credentials a request is signed with.
+type TuyaCredentials struct {
+	ClientID     string
+	ClientSecret string
+	BaseURL      string
+}
+
+// NewTuyaClient initializes a new instance of TuyaClient.
+//
+// param clock The Clock used for request timestamps.
+// param ids The IDGenerator used for request nonces.
+// return *TuyaClient A pointer to the initialized client.
+func NewTuyaClient(clock utils.Clock, ids utils.IDGenerator) *TuyaClient {
+	return &TuyaClient{
+		client:   newTuyaHTTPClient(),
+		timeouts: loadRequestTimeouts(),
+		retry:    httpclient.LoadRetryPolicy(),
+		clock:    clock,
+		ids:      ids,
+	}
+}
+
+// SetCallRecorder reports every request sent by the client to a recorder, retries included.
+//
+// param recorder The recorder tracking upstream calls.
+func (c *TuyaClient) SetCallRecorder(recorder UpstreamCallRecorder) {
+	withCallRecorder(c.client, recorder)
+}
+
+// WithCredentials returns a client signing with the given credentials instead of the configured ones,
+// e.g. to validate credentials before they are saved. It shares the HTTP client and call recorder.
+//
+// param credentials The credentials to sign with.
+// return *TuyaClient A pointer to the new client.
+func (c *TuyaClient) WithCredentials(credentials TuyaCredentials) *TuyaClient {
+	clone := *c
+	clone.credentials = &credentials
+	return &clone
+}
+
+// currentCredentials returns the override credentials, or the configured ones.
+func (c *TuyaClient) currentCredentials() TuyaCredentials {
+	if c.credentials != nil {
+		return *c.credentials
+	}
+	config := utils.GetConfig()
+	return TuyaCredentials{
+		ClientID:     config.TuyaClientID,
+		ClientSecret: config.TuyaClientSecret,
+		BaseURL:      config.TuyaBaseURL,
+	}
+}
+
+// Get sends a signed GET request and decodes the JSON response into out.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path, including any query string (e.g., /v1.0/iot-03/devices/status?device_ids=a,b).
+// param accessToken The access token; empty for token requests.
+// param class The per-call deadline class.
+// param out A pointer receiving the decoded response.
+// return error An error if the request fails, Tuya answers with a non-200 status, or the body cannot be parsed.
+func (c *TuyaClient) Get(ctx context.Context, path, accessToken string, class timeoutClass, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, accessToken, nil, class, out)
+}
+
+// Post sends a signed POST request with a JSON body and decodes the JSON response into out.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path, including any query string.
+// param accessToken The access token.
+// param body The JSON-encoded request body.
+// param class The per-call deadline class.
+// param out A pointer receiving the decoded response.
+// return error An error if the request fails, Tuya answers with a non-200 status, or the body cannot be parsed.
+func (c *TuyaClient) Post(ctx context.Context, path, accessToken string, body []byte, class timeoutClass, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, accessToken, body, class, out)
+}
+
+// Put sends a signed PUT request with an optional JSON body and decodes the JSON response into out.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path, including any query string.
+// param accessToken The access token.
+// param body The JSON-encoded request body (nil for none).
+// param class The per-call deadline class.
+// param out A pointer receiving the decoded response.
+// return error An error if the request fails, Tuya answers with a non-200 status, or the body cannot be parsed.
+func (c *TuyaClient) Put(ctx context.Context, path, accessToken string, body []byte, class timeoutClass, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, accessToken, body, class, out)
+}
+
+// Delete sends a signed DELETE request without a body and decodes the JSON response into out.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path, including any query string.
+// param accessToken The access token.
+// param class The per-call deadline class.
+// param out A pointer receiving the decoded response.
+// return error An error if the request fails, Tuya answers with a non-200 status, or the body cannot be parsed.
+func (c *TuyaClient) Delete(ctx context.Context, path, accessToken string, class timeoutClass, out interface{}) error {
+	return c.do(ctx, http.MethodDelete, path, accessToken, nil, class, out)
+}
+
+// do sends a request, retrying idempotent methods on transient failures, and decodes the response.
+func (c *TuyaClient) do(ctx context.Context, method, path, accessToken string, body []byte, class timeoutClass, out interface{}) error {
+	attempts := 1
+	if method != http.MethodPost {
+		attempts = c.retry.MaxAttempts
+	}
+
+	var respBody []byte
+	var err error
+	for attempt := 1; ; attempt++ {
+		var retryable bool
+		respBody, retryable, err = c.send(ctx, method, path, accessToken, body, class)
+		if err == nil || !retryable || attempt >= attempts {
+			break
+		}
+		delay := c.retry.Delay(attempt)
+		utils.LogWarn("TuyaClient: %s %s failed (attempt %d/%d), retrying in %s: %v", method, stripQuery(path), attempt, attempts, delay, err)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// send performs a single signed request and returns the response body of a 200 answer.
+// The bool reports whether a failure is worth retrying.
+func (c *TuyaClient) send(ctx context.Context, method, path, accessToken string, body []byte, class timeoutClass) ([]byte, bool, error) {
+	var reader io.Reader
+	if body != nil {
+		reader = bytes.NewReader(body)
+	}
+	credentials := c.currentCredentials()
+	req, err := http.NewRequestWithContext(ctx, method, credentials.BaseURL+path, reader)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	headers, err := c.signedHeaders(credentials, method, path, accessToken, body)
+	if err != nil {
+		return nil, false, err
+	}
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := doTimedRequest(c.client, req, c.timeouts.forClass(class))
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, ctx.Err() == nil, fmt.Errorf("failed to read response: %w", err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, httpclient.Retryable(resp.StatusCode), fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(respBody))
+	}
+	return respBody, false, nil
+}
+
+// signedHeaders builds the authentication headers of a request.
+// Each attempt gets a fresh timestamp and nonce so retries are not rejected as replays.
+func (c *TuyaClient) signedHeaders(credentials TuyaCredentials, method, path, accessToken string, body []byte) (map[string]string, error) {
+	timestamp := strconv.FormatInt(c.clock.Now().UnixMilli(), 10)
+	nonce, err := c.ids.NewID(16)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	contentHash := sha256.Sum256(body)
+	stringToSign := tuya_utils.GenerateTuyaStringToSign(method, hex.EncodeToString(contentHash[:]), "", canonicalPath(path))
+	signature := tuya_utils.GenerateTuyaSignature(credentials.ClientID, credentials.ClientSecret, accessToken, timestamp, nonce, stringToSign)
+
+	headers := map[string]string{
+		"client_id":   credentials.ClientID,
+		"sign":        signature,
+		"t":           timestamp,
+		"nonce":       nonce,
+		"sign_method": tuyaSignMethod,
+	}
+	if accessToken != "" {
+		headers["access_token"] = accessToken
+	}
+	return headers, nil
+}
+
+// canonicalPath returns the path as Tuya signs it, with query parameters sorted by name.
+func canonicalPath(path string) string {
+	base, rawQuery, found := strings.Cut(path, "?")
+	if !found || rawQuery == "" {
+		return base
+	}
+
+	query, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return path
+	}
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, key := range keys {
+		for _, value := range query[key] {
+			pairs = append(pairs, key+"="+value)
+		}
+	}
+	return base + "?" + strings.Join(pairs, "&")
+}
+
+// stripQuery drops the query string of a path so logs do not carry device lists or codes.
+func stripQuery(path string) string {
+	base, _, _ := strings.Cut(path, "?")
+	return base
+}
@@ -1,14 +1,12 @@
 package services
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
-	"io"
-	"net/http"
 	"strings"
 	"teralux_app/domain/tuya/entities"
 	"teralux_app/domain/common/utils"
-	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,30 +14,42 @@ import (
 // TuyaDeviceService manages interactions with Tuya's Device API endpoints.
 // It handles device fetching, control commands, and status updates.
 type TuyaDeviceService struct {
-	client *http.Client
+	client    *TuyaClient
+	endpoints *TuyaEndpoints
 }
 
 // NewTuyaDeviceService initializes a new instance of TuyaDeviceService.
+// The device endpoints follow the project type configured by TUYA_PROJECT_TYPE.
 //
+// param client The TuyaClient signing and sending the requests.
 // return *TuyaDeviceService A pointer to the initialized service.
-func NewTuyaDeviceService() *TuyaDeviceService {
+func NewTuyaDeviceService(client *TuyaClient) *TuyaDeviceService {
 	return &TuyaDeviceService{
-		client: &http.Client{Timeout: 30 * time.Second},
+		client:    client,
+		endpoints: NewTuyaEndpoints(utils.GetConfig().TuyaProjectType),
 	}
 }
 
+// Endpoints returns the device endpoints of the cloud project type.
+//
+// return *TuyaEndpoints The endpoints selecting the device list, status and command paths.
+func (s *TuyaDeviceService) Endpoints() *TuyaEndpoints {
+	return s.endpoints
+}
+
 // FetchDevices retrieves the list of devices associated with the authenticated user.
 //
-// param url The full API URL to the Tuya "Refresh Device List" endpoint.
-// param headers A map containing required HTTP headers, specifically 'access_token'.
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the "Refresh Device List" endpoint.
+// param accessToken The current access token.
 // return *entities.TuyaDevicesResponse The parsed response containing the list of devices.
 // return error An error if the HTTP request fails, parsing fails, or the API returns a non-200 status.
 // @throws error If the network is unreachable or the response body is malformed.
-func (s *TuyaDeviceService) FetchDevices(url string, headers map[string]string) (*entities.TuyaDevicesResponse, error) {
-	utils.LogDebug("FetchDevices: Starting values fetch from URL: %s", url)
+func (s *TuyaDeviceService) FetchDevices(ctx context.Context, path, accessToken string) (*entities.TuyaDevicesResponse, error) {
+	utils.LogDebug("FetchDevices: Starting values fetch from path: %s", path)
 
 	if gin.Mode() == gin.TestMode {
-		if headers["access_token"] == "invalid_token_12345" {
+		if accessToken == "invalid_token_12345" {
 			return nil, fmt.Errorf("mock error: invalid token")
 		}
 
@@ -49,34 +59,10 @@ func (s *TuyaDeviceService) FetchDevices(url string, headers map[string]string)
 		}, nil
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
-
 	var devicesResponse entities.TuyaDevicesResponse
-	if err := json.Unmarshal(body, &devicesResponse); err != nil {
-		utils.LogError("FetchDevices: failed to parse response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &devicesResponse); err != nil {
+		utils.LogError("FetchDevices: %v", err)
+		return nil, err
 	}
 
 	utils.LogDebug("FetchDevices: Successfully fetched and parsed %d devices from API", len(devicesResponse.Result))
@@ -85,18 +71,19 @@ func (s *TuyaDeviceService) FetchDevices(url string, headers map[string]string)
 
 // FetchDeviceByID retrieves detailed information for a specific device.
 //
-// param url The full API URL targeting a specific device ID.
-// param headers A map containing required HTTP headers.
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path targeting a specific device ID.
+// param accessToken The current access token.
 // return *entities.TuyaDeviceResponse The parsed response containing device details.
 // return error An error if the request, execution, or parsing fails.
 // @throws error If the API returns a non-200 status code.
-func (s *TuyaDeviceService) FetchDeviceByID(url string, headers map[string]string) (*entities.TuyaDeviceResponse, error) {
+func (s *TuyaDeviceService) FetchDeviceByID(ctx context.Context, path, accessToken string) (*entities.TuyaDeviceResponse, error) {
 	if gin.Mode() == gin.TestMode {
-		if headers["access_token"] == "invalid_token_123" {
+		if accessToken == "invalid_token_123" {
 			return nil, fmt.Errorf("mock error: invalid token")
 		}
 
-		if strings.Contains(url, "invalid_device_id_99999") {
+		if strings.Contains(path, "invalid_device_id_99999") {
 			return nil, fmt.Errorf("mock error: invalid device id")
 		}
 
@@ -109,99 +96,80 @@ func (s *TuyaDeviceService) FetchDeviceByID(url string, headers map[string]strin
 		}, nil
 	}
 
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		utils.LogDebug("FetchDeviceByID: Failed to create request for URL: %s", url)
-		utils.LogError("FetchDeviceByID: failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
-	}
-	utils.LogDebug("FetchDeviceByID: Requesting device details from URL: %s", url)
-
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-
-	resp, err := s.client.Do(req)
-	if err != nil {
-		utils.LogError("FetchDeviceByID: failed to execute request: %v", err)
-		return nil, fmt.Errorf("failed to execute request: %w", err)
-	}
-	defer resp.Body.Close()
-
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.LogError("FetchDeviceByID: failed to read response: %v", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		utils.LogError("FetchDeviceByID: API returned status %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
+	utils.LogDebug("FetchDeviceByID: Requesting device details from path: %s", path)
 
 	var deviceResponse entities.TuyaDeviceResponse
-	if err := json.Unmarshal(body, &deviceResponse); err != nil {
-		utils.LogError("FetchDeviceByID: failed to parse response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &deviceResponse); err != nil {
+		utils.LogError("FetchDeviceByID: %v", err)
+		return nil, err
 	}
 
 	utils.LogDebug("FetchDeviceByID: Successfully fetched details for DeviceID: %s", deviceResponse.Result.ID)
 	return &deviceResponse, nil
 }
 
-// FetchBatchDeviceStatus queries the real-time status of multiple devices.
+// FetchDevicePage retrieves one page of the project device list.
 //
-// param url The full API URL for batch status query.
-// param headers A map containing required HTTP headers.
-// return *entities.TuyaBatchStatusResponse The parsed response containing status for requested devices.
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path including the page_size and last_row_key query parameters.
+// param accessToken The current access token.
+// return *entities.TuyaDevicePageResponse The parsed page with the key of the next page.
 // return error An error if the network request or parsing fails.
-func (s *TuyaDeviceService) FetchBatchDeviceStatus(url string, headers map[string]string) (*entities.TuyaBatchStatusResponse, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		utils.LogError("FetchBatchDeviceStatus: failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+func (s *TuyaDeviceService) FetchDevicePage(ctx context.Context, path, accessToken string) (*entities.TuyaDevicePageResponse, error) {
+	var pageResponse entities.TuyaDevicePageResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &pageResponse); err != nil {
+		utils.LogError("FetchDevicePage: %v", err)
+		return nil, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
+	utils.LogDebug("FetchDevicePage: Fetched %d of %d devices", len(pageResponse.Result.List), pageResponse.Result.Total)
+	return &pageResponse, nil
+}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		utils.LogError("FetchBatchDeviceStatus: failed to execute request: %v", err)
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// FetchDeviceFirmware retrieves the firmware modules of a device with their current versions.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the upgrade info endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaFirmwareResponse The parsed response.
+// return error An error if the network request or parsing fails.
+func (s *TuyaDeviceService) FetchDeviceFirmware(ctx context.Context, path, accessToken string) (*entities.TuyaFirmwareResponse, error) {
+	var firmwareResponse entities.TuyaFirmwareResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &firmwareResponse); err != nil {
+		utils.LogError("FetchDeviceFirmware: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.LogError("FetchBatchDeviceStatus: failed to read response: %v", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-	
-	if resp.StatusCode != http.StatusOK {
-		utils.LogError("FetchBatchDeviceStatus: API returned status %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
+	return &firmwareResponse, nil
+}
 
+// FetchBatchDeviceStatus queries the real-time status of multiple devices.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path for batch status query, including the device_ids query parameter.
+// param accessToken The current access token.
+// return *entities.TuyaBatchStatusResponse The parsed response containing status for requested devices.
+// return error An error if the network request or parsing fails.
+func (s *TuyaDeviceService) FetchBatchDeviceStatus(ctx context.Context, path, accessToken string) (*entities.TuyaBatchStatusResponse, error) {
 	var statusResponse entities.TuyaBatchStatusResponse
-	if err := json.Unmarshal(body, &statusResponse); err != nil {
-		utils.LogError("FetchBatchDeviceStatus: failed to parse response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &statusResponse); err != nil {
+		utils.LogError("FetchBatchDeviceStatus: %v", err)
+		return nil, err
 	}
-	
+
 	return &statusResponse, nil
 }
 
 // SendCommand dispatches a control command to a specified device.
 //
-// param url The full API URL including device ID for sending commands.
-// param headers A map containing required HTTP headers.
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path including device ID for sending commands.
+// param accessToken The current access token.
 // param commands A slice of TuyaCommand objects containing the code and value to set.
 // return *entities.TuyaCommandResponse The API response indicating success or failure.
 // return error An error if serialization of commands or the network request fails.
 // @throws error If the API returns a status other than 200 OK.
-func (s *TuyaDeviceService) SendCommand(url string, headers map[string]string, commands []entities.TuyaCommand) (*entities.TuyaCommandResponse, error) {
+func (s *TuyaDeviceService) SendCommand(ctx context.Context, path, accessToken string, commands []entities.TuyaCommand) (*entities.TuyaCommandResponse, error) {
 	reqBody := entities.TuyaCommandRequest{
 		Commands: commands,
 	}
@@ -210,133 +178,222 @@ func (s *TuyaDeviceService) SendCommand(url string, headers map[string]string, c
 		utils.LogError("SendCommand: failed to marshal request body: %v", err)
 		return nil, fmt.Errorf("failed to marshal request body: %w", err)
 	}
-	utils.LogDebug("SendCommand: Sending %d commands to URL: %s", len(commands), url)
+	utils.LogDebug("SendCommand: Sending %d commands to path: %s", len(commands), path)
 
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonBody)))
-	if err != nil {
-		utils.LogError("SendCommand: failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	var commandResponse entities.TuyaCommandResponse
+	if err := s.client.Post(ctx, path, accessToken, jsonBody, timeoutCommand, &commandResponse); err != nil {
+		utils.LogError("SendCommand: %v", err)
+		return nil, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	return &commandResponse, nil
+}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		utils.LogError("SendCommand: failed to execute request: %v", err)
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// SendIRCommand sends a raw JSON command payload to an Infrared (IR) controlled device.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path including the infrared ID or remote ID.
+// param accessToken The current access token.
+// param jsonBody The raw JSON byte slice representing the IR command payload.
+// return *entities.TuyaCommandResponse The API response.
+// return error An error if the request creation or execution fails.
+func (s *TuyaDeviceService) SendIRCommand(ctx context.Context, path, accessToken string, jsonBody []byte) (*entities.TuyaCommandResponse, error) {
+	var commandResponse entities.TuyaCommandResponse
+	if err := s.client.Post(ctx, path, accessToken, jsonBody, timeoutCommand, &commandResponse); err != nil {
+		utils.LogError("SendIRCommand: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.LogError("SendCommand: failed to read response: %v", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
+	return &commandResponse, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		utils.LogError("SendCommand: API returned status %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+// FetchDeviceSpecification retrieves the detailed specifications (functions, status sets) of a device.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path to fetch specifications.
+// param accessToken The current access token.
+// return *entities.TuyaDeviceSpecificationResponse The parsed specification response.
+// return error An error if the request fails.
+// @throws error if the content is not valid JSON or network error occurs.
+func (s *TuyaDeviceService) FetchDeviceSpecification(ctx context.Context, path, accessToken string) (*entities.TuyaDeviceSpecificationResponse, error) {
+	var specResponse entities.TuyaDeviceSpecificationResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &specResponse); err != nil {
+		utils.LogError("FetchDeviceSpecification: %v", err)
+		return nil, err
 	}
 
+	return &specResponse, nil
+}
+
+// SetIRLearningState switches learning mode on or off for an IR hub.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path including the state query parameter.
+// param accessToken The current access token.
+// return *entities.TuyaCommandResponse The API response.
+// return error An error if the request creation or execution fails.
+func (s *TuyaDeviceService) SetIRLearningState(ctx context.Context, path, accessToken string) (*entities.TuyaCommandResponse, error) {
 	var commandResponse entities.TuyaCommandResponse
-	if err := json.Unmarshal(body, &commandResponse); err != nil {
-		utils.LogError("SendCommand: failed to parse response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	if err := s.client.Put(ctx, path, accessToken, nil, timeoutDefault, &commandResponse); err != nil {
+		utils.LogError("SetIRLearningState: %v", err)
+		return nil, err
 	}
-	
+
 	return &commandResponse, nil
 }
 
-// SendIRCommand sends a raw JSON command payload to an Infrared (IR) controlled device.
+// RenameDevice changes the name of a device in the Tuya cloud (and the Smart Life app).
 //
-// param url The full API URL including the infrared ID or remote ID.
-// param headers A map containing required HTTP headers.
-// param jsonBody The raw JSON byte slice representing the IR command payload.
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the device.
+// param accessToken The current access token.
+// param body The JSON-encoded entities.TuyaRenameDeviceRequest.
 // return *entities.TuyaCommandResponse The API response.
 // return error An error if the request creation or execution fails.
-func (s *TuyaDeviceService) SendIRCommand(url string, headers map[string]string, jsonBody []byte) (*entities.TuyaCommandResponse, error) {
-	req, err := http.NewRequest("POST", url, strings.NewReader(string(jsonBody)))
-	if err != nil {
-		utils.LogError("SendIRCommand: failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+func (s *TuyaDeviceService) RenameDevice(ctx context.Context, path, accessToken string, body []byte) (*entities.TuyaCommandResponse, error) {
+	var commandResponse entities.TuyaCommandResponse
+	if err := s.client.Put(ctx, path, accessToken, body, timeoutCommand, &commandResponse); err != nil {
+		utils.LogError("RenameDevice: %v", err)
+		return nil, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
-	}
-	req.Header.Set("Content-Type", "application/json")
+	return &commandResponse, nil
+}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		utils.LogError("SendIRCommand: failed to execute request: %v", err)
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+// FetchIRLearnedCode retrieves the code captured by an IR hub since learning mode started.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path including the learning_time query parameter.
+// param accessToken The current access token.
+// return *entities.TuyaIRLearnedCodeResponse The parsed learned code response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) FetchIRLearnedCode(ctx context.Context, path, accessToken string) (*entities.TuyaIRLearnedCodeResponse, error) {
+	var codeResponse entities.TuyaIRLearnedCodeResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &codeResponse); err != nil {
+		utils.LogError("FetchIRLearnedCode: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.LogError("SendIRCommand: failed to read response: %v", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
-	}
-	
-	if resp.StatusCode != http.StatusOK {
-		utils.LogError("SendIRCommand: API returned status %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
-	}
+	return &codeResponse, nil
+}
 
-	var commandResponse entities.TuyaCommandResponse
-	if err := json.Unmarshal(body, &commandResponse); err != nil {
-		utils.LogError("SendIRCommand: failed to parse response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+// SaveIRLearnedCodes stores learned codes as keys of a custom remote.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the learning-codes endpoint.
+// param accessToken The current access token.
+// param jsonBody The JSON-encoded TuyaIRSaveCodesRequest.
+// return *entities.TuyaIRSaveCodesResponse The API response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) SaveIRLearnedCodes(ctx context.Context, path, accessToken string, jsonBody []byte) (*entities.TuyaIRSaveCodesResponse, error) {
+	var saveResponse entities.TuyaIRSaveCodesResponse
+	if err := s.client.Post(ctx, path, accessToken, jsonBody, timeoutDefault, &saveResponse); err != nil {
+		utils.LogError("SaveIRLearnedCodes: %v", err)
+		return nil, err
 	}
 
-	return &commandResponse, nil
+	return &saveResponse, nil
 }
 
-// FetchDeviceSpecification retrieves the detailed specifications (functions, status sets) of a device.
+// FetchIRRemotes retrieves the remotes configured on an IR hub.
 //
-// param url The full API URL to fetch specifications.
-// param headers A map containing required HTTP headers.
-// return *entities.TuyaDeviceSpecificationResponse The parsed specification response.
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the remotes endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaIRRemotesResponse The parsed response.
 // return error An error if the request fails.
-// @throws error if the content is not valid JSON or network error occurs.
-func (s *TuyaDeviceService) FetchDeviceSpecification(url string, headers map[string]string) (*entities.TuyaDeviceSpecificationResponse, error) {
-	req, err := http.NewRequest("GET", url, nil)
-	if err != nil {
-		utils.LogError("FetchDeviceSpecification: failed to create request: %v", err)
-		return nil, fmt.Errorf("failed to create request: %w", err)
+func (s *TuyaDeviceService) FetchIRRemotes(ctx context.Context, path, accessToken string) (*entities.TuyaIRRemotesResponse, error) {
+	var remotesResponse entities.TuyaIRRemotesResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &remotesResponse); err != nil {
+		utils.LogError("FetchIRRemotes: %v", err)
+		return nil, err
 	}
 
-	for key, value := range headers {
-		req.Header.Set(key, value)
+	return &remotesResponse, nil
+}
+
+// FetchIRRemoteKeys retrieves the keys of a remote configured on an IR hub.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the remote keys endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaIRRemoteKeysResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) FetchIRRemoteKeys(ctx context.Context, path, accessToken string) (*entities.TuyaIRRemoteKeysResponse, error) {
+	var keysResponse entities.TuyaIRRemoteKeysResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &keysResponse); err != nil {
+		utils.LogError("FetchIRRemoteKeys: %v", err)
+		return nil, err
 	}
 
-	resp, err := s.client.Do(req)
-	if err != nil {
-		utils.LogError("FetchDeviceSpecification: failed to execute request: %v", err)
-		return nil, fmt.Errorf("failed to execute request: %w", err)
+	return &keysResponse, nil
+}
+
+// FetchIRCategories retrieves the remote categories an IR hub supports.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the categories endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaIRCategoriesResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) FetchIRCategories(ctx context.Context, path, accessToken string) (*entities.TuyaIRCategoriesResponse, error) {
+	var categoriesResponse entities.TuyaIRCategoriesResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &categoriesResponse); err != nil {
+		utils.LogError("FetchIRCategories: %v", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		utils.LogError("FetchDeviceSpecification: failed to read response: %v", err)
-		return nil, fmt.Errorf("failed to read response: %w", err)
+	return &categoriesResponse, nil
+}
+
+// FetchIRBrands retrieves the brands of a remote category.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the brands endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaIRBrandsResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) FetchIRBrands(ctx context.Context, path, accessToken string) (*entities.TuyaIRBrandsResponse, error) {
+	var brandsResponse entities.TuyaIRBrandsResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &brandsResponse); err != nil {
+		utils.LogError("FetchIRBrands: %v", err)
+		return nil, err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		utils.LogError("FetchDeviceSpecification: API returned status %d: %s", resp.StatusCode, string(body))
-		return nil, fmt.Errorf("API returned status %d: %s", resp.StatusCode, string(body))
+	return &brandsResponse, nil
+}
+
+// FetchIRRemoteIndexes retrieves the code libraries of a brand.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the remote indexes endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaIRRemoteIndexesResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) FetchIRRemoteIndexes(ctx context.Context, path, accessToken string) (*entities.TuyaIRRemoteIndexesResponse, error) {
+	var indexesResponse entities.TuyaIRRemoteIndexesResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutDefault, &indexesResponse); err != nil {
+		utils.LogError("FetchIRRemoteIndexes: %v", err)
+		return nil, err
 	}
 
-	var specResponse entities.TuyaDeviceSpecificationResponse
-	if err := json.Unmarshal(body, &specResponse); err != nil {
-		utils.LogError("FetchDeviceSpecification: failed to parse response: %v", err)
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	return &indexesResponse, nil
+}
+
+// AddIRRemote adds a remote from a code library to an IR hub.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the add-remote endpoint.
+// param accessToken The current access token.
+// param jsonBody The JSON-encoded TuyaIRAddRemoteRequest.
+// return *entities.TuyaIRAddRemoteResponse The API response.
+// return error An error if the request fails.
+func (s *TuyaDeviceService) AddIRRemote(ctx context.Context, path, accessToken string, jsonBody []byte) (*entities.TuyaIRAddRemoteResponse, error) {
+	var addResponse entities.TuyaIRAddRemoteResponse
+	if err := s.client.Post(ctx, path, accessToken, jsonBody, timeoutDefault, &addResponse); err != nil {
+		utils.LogError("AddIRRemote: %v", err)
+		return nil, err
 	}
-	
-	return &specResponse, nil
-}
\ No newline at end of file
+
+	return &addResponse, nil
+}
@@ -0,0 +1,124 @@
+package services
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+	tuya_utils "teralux_app/domain/tuya/utils"
+)
+
+// TuyaDoorLockService manages interactions with Tuya's smart lock API endpoints.
+type TuyaDoorLockService struct {
+	client *TuyaClient
+}
+
+// NewTuyaDoorLockService initializes a new instance of TuyaDoorLockService.
+//
+// param client The TuyaClient signing and sending the requests.
+// return *TuyaDoorLockService A pointer to the initialized service.
+func NewTuyaDoorLockService(client *TuyaClient) *TuyaDoorLockService {
+	return &TuyaDoorLockService{
+		client: client,
+	}
+}
+
+// FetchOpenLogs retrieves one page of unlock records of a lock.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the open-logs endpoint, including the paging query.
+// param accessToken The current access token.
+// return *entities.TuyaLockOpenLogsResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDoorLockService) FetchOpenLogs(ctx context.Context, path, accessToken string) (*entities.TuyaLockOpenLogsResponse, error) {
+	var logsResponse entities.TuyaLockOpenLogsResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &logsResponse); err != nil {
+		utils.LogError("FetchOpenLogs: %v", err)
+		return nil, err
+	}
+
+	return &logsResponse, nil
+}
+
+// CreatePasswordTicket requests a ticket for encrypting a lock password.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the password-ticket endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaPasswordTicketResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDoorLockService) CreatePasswordTicket(ctx context.Context, path, accessToken string) (*entities.TuyaPasswordTicketResponse, error) {
+	var ticketResponse entities.TuyaPasswordTicketResponse
+	if err := s.client.Post(ctx, path, accessToken, nil, timeoutCommand, &ticketResponse); err != nil {
+		utils.LogError("CreatePasswordTicket: %v", err)
+		return nil, err
+	}
+
+	return &ticketResponse, nil
+}
+
+// EncryptPassword encrypts a lock password with a ticket. The ticket key is decrypted with the client secret
+// the requests are signed with, so it never leaves the service.
+//
+// param ticket The password ticket.
+// param password The plain numeric password.
+// return string The encrypted password, as the temporary password endpoint expects it.
+// return error An error if the ticket key cannot be decrypted.
+func (s *TuyaDoorLockService) EncryptPassword(ticket entities.TuyaPasswordTicket, password string) (string, error) {
+	key, err := tuya_utils.DecryptTicketKey(ticket.TicketKey, s.client.currentCredentials().ClientSecret)
+	if err != nil {
+		return "", err
+	}
+	return tuya_utils.EncryptLockPassword(password, key)
+}
+
+// CreateTempPassword creates a temporary password on a lock.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the temp-password endpoint.
+// param accessToken The current access token.
+// param body The JSON-encoded entities.TuyaTempPasswordRequest.
+// return *entities.TuyaTempPasswordCreateResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDoorLockService) CreateTempPassword(ctx context.Context, path, accessToken string, body []byte) (*entities.TuyaTempPasswordCreateResponse, error) {
+	var createResponse entities.TuyaTempPasswordCreateResponse
+	if err := s.client.Post(ctx, path, accessToken, body, timeoutCommand, &createResponse); err != nil {
+		utils.LogError("CreateTempPassword: %v", err)
+		return nil, err
+	}
+
+	return &createResponse, nil
+}
+
+// FetchTempPasswords retrieves the temporary passwords of a lock.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the temp-passwords endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaTempPasswordsResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDoorLockService) FetchTempPasswords(ctx context.Context, path, accessToken string) (*entities.TuyaTempPasswordsResponse, error) {
+	var passwordsResponse entities.TuyaTempPasswordsResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &passwordsResponse); err != nil {
+		utils.LogError("FetchTempPasswords: %v", err)
+		return nil, err
+	}
+
+	return &passwordsResponse, nil
+}
+
+// DeleteTempPassword deletes a temporary password from a lock.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the temporary password.
+// param accessToken The current access token.
+// return *entities.TuyaCommandResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaDoorLockService) DeleteTempPassword(ctx context.Context, path, accessToken string) (*entities.TuyaCommandResponse, error) {
+	var deleteResponse entities.TuyaCommandResponse
+	if err := s.client.Delete(ctx, path, accessToken, timeoutCommand, &deleteResponse); err != nil {
+		utils.LogError("DeleteTempPassword: %v", err)
+		return nil, err
+	}
+
+	return &deleteResponse, nil
+}
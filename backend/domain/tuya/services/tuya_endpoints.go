@@ -0,0 +1,244 @@
+package services
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+)
+
+// Tuya cloud project types selectable via TUYA_PROJECT_TYPE.
+const (
+	// ProjectTypeAuto lists devices per user and reads status and sends commands through the IoT Core
+	// (iot-03) endpoints, switching the device list to the IoT Core endpoint when the project may not
+	// list devices per user.
+	ProjectTypeAuto = "auto"
+	// ProjectTypeSmartHome uses the Smart Home PaaS endpoints (/v1.0/users/{uid}/devices, /v1.0/devices/...).
+	ProjectTypeSmartHome = "smart_home"
+	// ProjectTypeIndustry uses the IoT Core endpoints (/v1.0/iot-03/devices/...) of industry projects.
+	ProjectTypeIndustry = "industry"
+)
+
+// listingDeniedCodes are the Tuya codes answering a device list call the project is not allowed to make.
+var listingDeniedCodes = map[int]bool{
+	1106:     true, // permission deny
+	28841101: true, // API not subscribed
+	28841105: true, // project not authorized to call this API
+}
+
+// TuyaEndpointStrategy builds the paths of the device endpoints that differ between project types.
+type TuyaEndpointStrategy interface {
+	// ProjectType returns the project type the paths belong to.
+	ProjectType() string
+	// UserDeviceList reports whether a user's devices are listed in one call (UserDevicesPath);
+	// otherwise they are walked page by page (DevicePagePath).
+	UserDeviceList() bool
+	// PagedDeviceList reports whether the device list can be requested page by page (DevicePagePath).
+	PagedDeviceList() bool
+	// UserDevicesPath returns the path listing all devices of a user.
+	UserDevicesPath(uid string) string
+	// DevicePagePath returns the path of one page of a user's devices, starting after lastRowKey.
+	DevicePagePath(uid string, pageSize int, lastRowKey, category string) string
+	// BatchStatusPath returns the path reading the status of several devices.
+	BatchStatusPath(deviceIDs []string) string
+	// StatusReportsOnline reports whether the batch status includes the real-time online state.
+	StatusReportsOnline() bool
+	// CommandsPath returns the path sending commands to a device.
+	CommandsPath(deviceID string) string
+}
+
+// smartHomeEndpoints are the Smart Home PaaS endpoints. The user device list carries the online state,
+// and there is no paged list.
+type smartHomeEndpoints struct{}
+
+// ProjectType returns smart_home.
+func (smartHomeEndpoints) ProjectType() string {
+	return ProjectTypeSmartHome
+}
+
+// UserDeviceList returns true: GET /v1.0/users/{uid}/devices.
+func (smartHomeEndpoints) UserDeviceList() bool {
+	return true
+}
+
+// PagedDeviceList returns false.
+func (smartHomeEndpoints) PagedDeviceList() bool {
+	return false
+}
+
+// UserDevicesPath returns /v1.0/users/{uid}/devices.
+func (smartHomeEndpoints) UserDevicesPath(uid string) string {
+	return fmt.Sprintf("/v1.0/users/%s/devices", uid)
+}
+
+// DevicePagePath returns an empty path; Smart Home projects have no paged device list.
+func (smartHomeEndpoints) DevicePagePath(uid string, pageSize int, lastRowKey, category string) string {
+	return ""
+}
+
+// BatchStatusPath returns /v1.0/devices/status?device_ids={ids}.
+func (smartHomeEndpoints) BatchStatusPath(deviceIDs []string) string {
+	return "/v1.0/devices/status?device_ids=" + strings.Join(deviceIDs, ",")
+}
+
+// StatusReportsOnline returns false; the online state comes with the device list.
+func (smartHomeEndpoints) StatusReportsOnline() bool {
+	return false
+}
+
+// CommandsPath returns /v1.0/devices/{id}/commands.
+func (smartHomeEndpoints) CommandsPath(deviceID string) string {
+	return fmt.Sprintf("/v1.0/devices/%s/commands", deviceID)
+}
+
+// industryEndpoints are the IoT Core endpoints of industry projects, which list devices by page only.
+type industryEndpoints struct{}
+
+// ProjectType returns industry.
+func (industryEndpoints) ProjectType() string {
+	return ProjectTypeIndustry
+}
+
+// UserDeviceList returns false; the devices are walked page by page.
+func (industryEndpoints) UserDeviceList() bool {
+	return false
+}
+
+// PagedDeviceList returns true: GET /v1.0/iot-03/devices.
+func (industryEndpoints) PagedDeviceList() bool {
+	return true
+}
+
+// UserDevicesPath returns an empty path; industry projects have no per-user device list.
+func (industryEndpoints) UserDevicesPath(uid string) string {
+	return ""
+}
+
+// DevicePagePath returns /v1.0/iot-03/devices?source_type=tuyaUser&source_id={uid}&page_size={n}&last_row_key={key}.
+func (industryEndpoints) DevicePagePath(uid string, pageSize int, lastRowKey, category string) string {
+	query := url.Values{}
+	query.Set("source_type", "tuyaUser")
+	query.Set("source_id", uid)
+	query.Set("page_size", strconv.Itoa(pageSize))
+	if lastRowKey != "" {
+		query.Set("last_row_key", lastRowKey)
+	}
+	if category != "" {
+		query.Set("category", category)
+	}
+	return "/v1.0/iot-03/devices?" + query.Encode()
+}
+
+// BatchStatusPath returns /v1.0/iot-03/devices/status?device_ids={ids}.
+func (industryEndpoints) BatchStatusPath(deviceIDs []string) string {
+	return "/v1.0/iot-03/devices/status?device_ids=" + strings.Join(deviceIDs, ",")
+}
+
+// StatusReportsOnline returns true.
+func (industryEndpoints) StatusReportsOnline() bool {
+	return true
+}
+
+// CommandsPath returns /v1.0/iot-03/devices/{id}/commands.
+func (industryEndpoints) CommandsPath(deviceID string) string {
+	return fmt.Sprintf("/v1.0/iot-03/devices/%s/commands", deviceID)
+}
+
+// hybridEndpoints list devices per user like Smart Home projects and use the IoT Core endpoints for
+// status, paging and commands, which Smart Home projects usually have subscribed too.
+type hybridEndpoints struct {
+	industryEndpoints
+}
+
+// ProjectType returns auto.
+func (hybridEndpoints) ProjectType() string {
+	return ProjectTypeAuto
+}
+
+// UserDeviceList returns true: GET /v1.0/users/{uid}/devices.
+func (hybridEndpoints) UserDeviceList() bool {
+	return true
+}
+
+// UserDevicesPath returns /v1.0/users/{uid}/devices.
+func (hybridEndpoints) UserDevicesPath(uid string) string {
+	return smartHomeEndpoints{}.UserDevicesPath(uid)
+}
+
+// EndpointLabel describes the endpoint of a path for diagnostics, without its query string.
+//
+// param method The HTTP method.
+// param path A path built with placeholders, e.g. UserDevicesPath("{uid}").
+// return string The label, e.g. "GET /v1.0/users/{uid}/devices".
+func EndpointLabel(method, path string) string {
+	path, _, _ = strings.Cut(path, "?")
+	return method + " " + path
+}
+
+// TuyaEndpoints selects the device endpoints of the project type configured by TUYA_PROJECT_TYPE.
+// In auto mode it starts with the hybrid endpoints and switches to the industry ones for good once
+// Tuya denies the per-user device list (see ReportListingDenied).
+type TuyaEndpoints struct {
+	configured string
+	mu         sync.RWMutex
+	strategy   TuyaEndpointStrategy
+}
+
+// NewTuyaEndpoints initializes the endpoints of a project type. Unknown types fall back to auto.
+//
+// param projectType The project type: auto, smart_home or industry (empty for auto).
+// return *TuyaEndpoints A pointer to the initialized endpoints.
+func NewTuyaEndpoints(projectType string) *TuyaEndpoints {
+	e := &TuyaEndpoints{configured: strings.ToLower(strings.TrimSpace(projectType))}
+	switch e.configured {
+	case ProjectTypeSmartHome:
+		e.strategy = smartHomeEndpoints{}
+	case ProjectTypeIndustry:
+		e.strategy = industryEndpoints{}
+	case "", ProjectTypeAuto:
+		e.configured = ProjectTypeAuto
+		e.strategy = hybridEndpoints{}
+	default:
+		utils.LogWarn("TuyaEndpoints: Unknown TUYA_PROJECT_TYPE %q, using %s", projectType, ProjectTypeAuto)
+		e.configured = ProjectTypeAuto
+		e.strategy = hybridEndpoints{}
+	}
+	return e
+}
+
+// Configured returns the configured project type.
+//
+// return string auto, smart_home or industry.
+func (e *TuyaEndpoints) Configured() string {
+	return e.configured
+}
+
+// Strategy returns the endpoints in use.
+//
+// return TuyaEndpointStrategy The strategy building the paths.
+func (e *TuyaEndpoints) Strategy() TuyaEndpointStrategy {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.strategy
+}
+
+// ReportListingDenied switches auto mode to the industry endpoints when Tuya answered the per-user device
+// list with a permission error, as it does for industry projects.
+//
+// param code The Tuya error code of the failed device list call.
+// return bool True if the endpoints switched and the list should be requested again.
+func (e *TuyaEndpoints) ReportListingDenied(code int) bool {
+	if e.configured != ProjectTypeAuto || !listingDeniedCodes[code] {
+		return false
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.strategy.ProjectType() == ProjectTypeIndustry {
+		return false
+	}
+	utils.LogInfo("TuyaEndpoints: The project may not list devices per user (code %d), using the industry (IoT Core) endpoints", code)
+	e.strategy = industryEndpoints{}
+	return true
+}
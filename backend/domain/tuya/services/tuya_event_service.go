@@ -0,0 +1,244 @@
+package services
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultPulsarEnv = "event"
+
+	// pulsarReconnectMin and pulsarReconnectMax bound the backoff between reconnect attempts.
+	pulsarReconnectMin = time.Second
+	pulsarReconnectMax = time.Minute
+)
+
+// TuyaEventHandler receives decrypted device events from the Pulsar message service.
+type TuyaEventHandler func(event entities.TuyaDeviceEvent)
+
+// TuyaEventService subscribes to Tuya's Pulsar message service over WebSocket so device status
+// changes are pushed to the backend instead of being polled.
+// Messages are decrypted with the project's access secret and acknowledged after the handler returns.
+type TuyaEventService struct {
+	dialer    *websocket.Dialer
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+
+	mu      sync.Mutex
+	conn    *websocket.Conn
+	stopped bool
+}
+
+// NewTuyaEventService initializes a new instance of TuyaEventService.
+//
+// return *TuyaEventService A pointer to the initialized service.
+func NewTuyaEventService() *TuyaEventService {
+	return &TuyaEventService{
+		dialer: &websocket.Dialer{HandshakeTimeout: 30 * time.Second},
+	}
+}
+
+// Start connects to the Pulsar consumer in the background and keeps reconnecting with backoff.
+// It does nothing when TUYA_PULSAR_URL is not configured.
+//
+// param handler The function receiving each device event.
+func (s *TuyaEventService) Start(handler TuyaEventHandler) {
+	config := utils.GetConfig()
+	if config.TuyaPulsarURL == "" {
+		utils.LogInfo("TuyaEventService: TUYA_PULSAR_URL not set, device events are not pushed")
+		return
+	}
+
+	s.startOnce.Do(func() {
+		s.workers.Go(func(stop <-chan struct{}) {
+			backoff := pulsarReconnectMin
+			for {
+				connectedAt := time.Now()
+				err := s.consume(handler)
+				if time.Since(connectedAt) > pulsarReconnectMax {
+					backoff = pulsarReconnectMin
+				}
+				utils.LogWarn("TuyaEventService: Connection closed (%v), reconnecting in %s", err, backoff)
+				select {
+				case <-time.After(backoff):
+				case <-stop:
+					return
+				}
+				backoff *= 2
+				if backoff > pulsarReconnectMax {
+					backoff = pulsarReconnectMax
+				}
+			}
+		})
+	})
+}
+
+// Stop closes the consumer connection and waits for the event being handled to finish.
+func (s *TuyaEventService) Stop() {
+	// Closing the connection unblocks the pending read so the consumer loop can see the stop signal
+	s.mu.Lock()
+	s.stopped = true
+	if s.conn != nil {
+		s.conn.Close()
+	}
+	s.mu.Unlock()
+	s.workers.Stop()
+}
+
+// consume runs one consumer connection until it fails.
+func (s *TuyaEventService) consume(handler TuyaEventHandler) error {
+	config := utils.GetConfig()
+
+	header := http.Header{}
+	header.Set("username", config.TuyaClientID)
+	header.Set("password", pulsarPassword(config.TuyaClientID, config.TuyaClientSecret))
+
+	conn, _, err := s.dialer.Dial(pulsarTopicURL(config.TuyaPulsarURL, config.TuyaClientID, config.TuyaPulsarEnv), header)
+	if err != nil {
+		return fmt.Errorf("failed to connect: %w", err)
+	}
+	s.mu.Lock()
+	if s.stopped {
+		s.mu.Unlock()
+		conn.Close()
+		return fmt.Errorf("service stopped")
+	}
+	s.conn = conn
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		s.conn = nil
+		s.mu.Unlock()
+		conn.Close()
+	}()
+	utils.LogInfo("TuyaEventService: Subscribed to Tuya message service")
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return err
+		}
+
+		var message entities.TuyaPulsarMessage
+		if err := json.Unmarshal(data, &message); err != nil {
+			utils.LogWarn("TuyaEventService: Ignoring malformed frame: %v", err)
+			continue
+		}
+
+		event, err := decodeTuyaEvent(message, config.TuyaClientSecret)
+		if err != nil {
+			utils.LogWarn("TuyaEventService: Failed to decode message %s: %v", message.MessageID, err)
+		} else if event != nil {
+			handler(*event)
+		}
+
+		// Acknowledge even undecodable messages so they are not redelivered forever.
+		ack, _ := json.Marshal(map[string]string{"messageId": message.MessageID})
+		if err := conn.WriteMessage(websocket.TextMessage, ack); err != nil {
+			return fmt.Errorf("failed to acknowledge message: %w", err)
+		}
+	}
+}
+
+// decodeTuyaEvent decodes and decrypts a Pulsar message. Messages with unsupported protocols return nil.
+func decodeTuyaEvent(message entities.TuyaPulsarMessage, accessSecret string) (*entities.TuyaDeviceEvent, error) {
+	rawPayload, err := base64.StdEncoding.DecodeString(message.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("invalid payload encoding: %w", err)
+	}
+
+	var payload entities.TuyaEventPayload
+	if err := json.Unmarshal(rawPayload, &payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	if payload.Protocol != entities.TuyaEventProtocolStatus && payload.Protocol != entities.TuyaEventProtocolDevice {
+		utils.LogDebug("TuyaEventService: Skipping message with protocol %d", payload.Protocol)
+		return nil, nil
+	}
+
+	plaintext, err := decryptTuyaEventData(payload.Data, accessSecret, message.Properties["em"])
+	if err != nil {
+		return nil, err
+	}
+
+	var event entities.TuyaDeviceEvent
+	if err := json.Unmarshal(plaintext, &event); err != nil {
+		return nil, fmt.Errorf("invalid event data: %w", err)
+	}
+	event.Protocol = payload.Protocol
+	return &event, nil
+}
+
+// decryptTuyaEventData decrypts the data field with the middle 16 characters of the access secret.
+// Messages flagged "aes_gcm" use AES-GCM with a 12-byte nonce prefix; older messages use AES-ECB with PKCS#5 padding.
+func decryptTuyaEventData(data, accessSecret, encryptionMode string) ([]byte, error) {
+	if len(accessSecret) < 24 {
+		return nil, fmt.Errorf("access secret too short to derive message key")
+	}
+	key := []byte(accessSecret[8:24])
+
+	ciphertext, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid data encoding: %w", err)
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+
+	if strings.EqualFold(encryptionMode, "aes_gcm") {
+		gcm, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create GCM: %w", err)
+		}
+		if len(ciphertext) < gcm.NonceSize() {
+			return nil, fmt.Errorf("ciphertext too short")
+		}
+		nonce, sealed := ciphertext[:gcm.NonceSize()], ciphertext[gcm.NonceSize():]
+		return gcm.Open(nil, nonce, sealed, nil)
+	}
+
+	if len(ciphertext) == 0 || len(ciphertext)%aes.BlockSize != 0 {
+		return nil, fmt.Errorf("ciphertext is not a multiple of the block size")
+	}
+	plaintext := make([]byte, len(ciphertext))
+	for start := 0; start < len(ciphertext); start += aes.BlockSize {
+		block.Decrypt(plaintext[start:start+aes.BlockSize], ciphertext[start:start+aes.BlockSize])
+	}
+
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > aes.BlockSize || padding > len(plaintext) {
+		return nil, fmt.Errorf("invalid padding")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// pulsarTopicURL builds the consumer URL of the project's event topic.
+func pulsarTopicURL(baseURL, accessID, env string) string {
+	if env == "" {
+		env = defaultPulsarEnv
+	}
+	return fmt.Sprintf("%s/ws/v2/consumer/persistent/%s/out/%s/%s-sub?ackTimeoutMillis=3000&subscriptionType=Failover",
+		strings.TrimRight(baseURL, "/"), accessID, env, accessID)
+}
+
+// pulsarPassword derives the consumer password: the middle 16 hex characters of md5(accessID + md5(accessSecret)).
+func pulsarPassword(accessID, accessSecret string) string {
+	secretHash := md5.Sum([]byte(accessSecret))
+	hash := md5.Sum([]byte(accessID + hex.EncodeToString(secretHash[:])))
+	return hex.EncodeToString(hash[:])[8:24]
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+)
+
+// TuyaSceneService manages interactions with Tuya's home, scene and automation API endpoints.
+// Scenes and automations are the ones configured in the Smart Life app, not Teralux automations.
+type TuyaSceneService struct {
+	client *TuyaClient
+}
+
+// NewTuyaSceneService initializes a new instance of TuyaSceneService.
+//
+// param client The TuyaClient signing and sending the requests.
+// return *TuyaSceneService A pointer to the initialized service.
+func NewTuyaSceneService(client *TuyaClient) *TuyaSceneService {
+	return &TuyaSceneService{
+		client: client,
+	}
+}
+
+// FetchHomes retrieves the homes of a user.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the user homes endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaHomesResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaSceneService) FetchHomes(ctx context.Context, path, accessToken string) (*entities.TuyaHomesResponse, error) {
+	var homesResponse entities.TuyaHomesResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &homesResponse); err != nil {
+		utils.LogError("FetchHomes: %v", err)
+		return nil, err
+	}
+
+	return &homesResponse, nil
+}
+
+// FetchScenes retrieves the tap-to-run scenes of a home.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the home scenes endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaCloudScenesResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaSceneService) FetchScenes(ctx context.Context, path, accessToken string) (*entities.TuyaCloudScenesResponse, error) {
+	var scenesResponse entities.TuyaCloudScenesResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &scenesResponse); err != nil {
+		utils.LogError("FetchScenes: %v", err)
+		return nil, err
+	}
+
+	return &scenesResponse, nil
+}
+
+// TriggerScene runs a tap-to-run scene.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the scene trigger endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaCommandResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaSceneService) TriggerScene(ctx context.Context, path, accessToken string) (*entities.TuyaCommandResponse, error) {
+	var triggerResponse entities.TuyaCommandResponse
+	if err := s.client.Post(ctx, path, accessToken, nil, timeoutCommand, &triggerResponse); err != nil {
+		utils.LogError("TriggerScene: %v", err)
+		return nil, err
+	}
+
+	return &triggerResponse, nil
+}
+
+// FetchAutomations retrieves the automations of a home.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the home automations endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaCloudAutomationsResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaSceneService) FetchAutomations(ctx context.Context, path, accessToken string) (*entities.TuyaCloudAutomationsResponse, error) {
+	var automationsResponse entities.TuyaCloudAutomationsResponse
+	if err := s.client.Get(ctx, path, accessToken, timeoutList, &automationsResponse); err != nil {
+		utils.LogError("FetchAutomations: %v", err)
+		return nil, err
+	}
+
+	return &automationsResponse, nil
+}
+
+// SetAutomationState enables or disables an automation.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param path The API path of the automation enable or disable endpoint.
+// param accessToken The current access token.
+// return *entities.TuyaCommandResponse The parsed response.
+// return error An error if the request fails.
+func (s *TuyaSceneService) SetAutomationState(ctx context.Context, path, accessToken string) (*entities.TuyaCommandResponse, error) {
+	var stateResponse entities.TuyaCommandResponse
+	if err := s.client.Put(ctx, path, accessToken, nil, timeoutCommand, &stateResponse); err != nil {
+		utils.LogError("SetAutomationState: %v", err)
+		return nil, err
+	}
+
+	return &stateResponse, nil
+}
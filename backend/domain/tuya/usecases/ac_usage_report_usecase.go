@@ -0,0 +1,369 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// defaultACRatedWatts is the power draw assumed for air conditioners without a power meter.
+const defaultACRatedWatts = 900
+
+// acMeteredPowerCode is the status code of the current power draw recorded into the sensor history.
+const acMeteredPowerCode = "cur_power"
+
+// acCategories are the device categories counted as air conditioners.
+var acCategories = map[string]bool{
+	"kt":          true, // air conditioner
+	"ktkzq":       true, // air conditioner controller
+	"infrared_ac": true, // AC behind an IR blaster
+}
+
+// acPowerEvent is a power command sent to an air conditioner.
+type acPowerEvent struct {
+	at time.Time
+	on bool
+}
+
+// ACUsageReportUseCase builds monthly air conditioner reports per room. Runtime is reconstructed from the
+// power commands in the audit log (IR AC "power" and device "switch" commands that succeeded), so only
+// changes made through this server are seen, and only while the audit log is still kept locally
+// (see ARCHIVE_LOCAL_RETENTION). Energy comes from the recorded cur_power readings when the device is
+// metered (readings are sampled once the device has been read through the sensor endpoint), and is
+// otherwise estimated from the runtime and AC_RATED_WATTS.
+// A device assigned to several rooms is counted in each of them.
+type ACUsageReportUseCase struct {
+	auditUC       *AuditLogUseCase
+	roomUC        *RoomUseCase
+	getDeviceUC   *TuyaGetDeviceByIDUseCase
+	specUC        *DeviceSpecificationUseCase
+	deviceStateUC *DeviceStateUseCase
+	historyUC     *SensorHistoryUseCase
+	authUC        *TuyaAuthUseCase
+	ratedWatts    float64
+	clock         utils.Clock
+}
+
+// NewACUsageReportUseCase initializes a new ACUsageReportUseCase.
+//
+// param auditUC The usecase providing the audit log.
+// param roomUC The usecase listing rooms and their devices.
+// param getDeviceUC The usecase used to recognize air conditioners by category.
+// param specUC The usecase providing the scale of power readings.
+// param deviceStateUC The usecase providing the last known IR AC state.
+// param historyUC The usecase providing recorded power readings.
+// param authUC The usecase providing the server token.
+// param clock The Clock used to end the report of the current month.
+// return *ACUsageReportUseCase A pointer to the initialized usecase.
+func NewACUsageReportUseCase(auditUC *AuditLogUseCase, roomUC *RoomUseCase, getDeviceUC *TuyaGetDeviceByIDUseCase, specUC *DeviceSpecificationUseCase, deviceStateUC *DeviceStateUseCase, historyUC *SensorHistoryUseCase, authUC *TuyaAuthUseCase, clock utils.Clock) *ACUsageReportUseCase {
+	ratedWatts, err := strconv.ParseFloat(utils.GetConfig().ACRatedWatts, 64)
+	if err != nil || ratedWatts <= 0 {
+		ratedWatts = defaultACRatedWatts
+	}
+	return &ACUsageReportUseCase{
+		auditUC:       auditUC,
+		roomUC:        roomUC,
+		getDeviceUC:   getDeviceUC,
+		specUC:        specUC,
+		deviceStateUC: deviceStateUC,
+		historyUC:     historyUC,
+		authUC:        authUC,
+		ratedWatts:    ratedWatts,
+		clock:         clock,
+	}
+}
+
+// GetReport returns the AC runtime and energy of every room for a month.
+// Rooms without air conditioners are left out. The current month ends now.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param month The month as YYYY-MM in the deployment time zone; empty for the current month.
+// return *dtos.ACUsageReportDTO The report.
+// return error A bad request error for an invalid or future month, ErrRoomsUnavailable,
+// or an error if the audit log cannot be read.
+func (uc *ACUsageReportUseCase) GetReport(ctx context.Context, month string) (*dtos.ACUsageReportDTO, error) {
+	now := uc.clock.Now()
+	from, to, err := reportMonth(month, now)
+	if err != nil {
+		return nil, err
+	}
+
+	rooms, err := uc.roomUC.ListRooms()
+	if err != nil {
+		return nil, err
+	}
+	events, irACDevices, err := uc.loadPowerEvents(to)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain token: %w", err)
+	}
+
+	report := &dtos.ACUsageReportDTO{
+		Month:      from.Format("2006-01"),
+		From:       from.Unix(),
+		To:         to.Unix(),
+		RatedWatts: uc.ratedWatts,
+		Rooms:      []dtos.ACRoomUsageDTO{},
+	}
+	usage := make(map[string]*dtos.ACDeviceUsageDTO)
+	for _, room := range rooms {
+		roomUsage := dtos.ACRoomUsageDTO{RoomID: room.ID, RoomName: room.Name, Devices: []dtos.ACDeviceUsageDTO{}}
+		for _, deviceID := range room.DeviceIDs {
+			device, seen := usage[deviceID]
+			if !seen {
+				device = uc.deviceUsage(ctx, token.AccessToken, deviceID, irACDevices[deviceID], events[deviceID], from, to)
+				usage[deviceID] = device
+			}
+			if device == nil {
+				continue
+			}
+			roomUsage.Devices = append(roomUsage.Devices, *device)
+			roomUsage.RuntimeHours += device.RuntimeHours
+			roomUsage.EnergyKWh += device.EnergyKWh
+		}
+		if len(roomUsage.Devices) == 0 {
+			continue
+		}
+		roomUsage.RuntimeHours = roundTo(roomUsage.RuntimeHours, 2)
+		roomUsage.EnergyKWh = roundTo(roomUsage.EnergyKWh, 2)
+		report.Rooms = append(report.Rooms, roomUsage)
+	}
+
+	// Devices in several rooms count once in the totals
+	for _, device := range usage {
+		if device != nil {
+			report.RuntimeHours += device.RuntimeHours
+			report.EnergyKWh += device.EnergyKWh
+		}
+	}
+	report.RuntimeHours = roundTo(report.RuntimeHours, 2)
+	report.EnergyKWh = roundTo(report.EnergyKWh, 2)
+	return report, nil
+}
+
+// GetReportCSV returns the report of a month as CSV with one row per room and air conditioner.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param month The month as YYYY-MM in the deployment time zone; empty for the current month.
+// return *dtos.ACUsageReportCSVDTO The CSV file and its suggested name.
+// return error The errors of GetReport.
+func (uc *ACUsageReportUseCase) GetReportCSV(ctx context.Context, month string) (*dtos.ACUsageReportCSVDTO, error) {
+	report, err := uc.GetReport(ctx, month)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	_ = writer.Write([]string{"month", "room_id", "room_name", "device_id", "device_name", "runtime_hours", "energy_kwh", "energy_source"})
+	for _, room := range report.Rooms {
+		for _, device := range room.Devices {
+			_ = writer.Write([]string{
+				report.Month,
+				room.RoomID,
+				room.RoomName,
+				device.DeviceID,
+				device.Name,
+				strconv.FormatFloat(device.RuntimeHours, 'f', 2, 64),
+				strconv.FormatFloat(device.EnergyKWh, 'f', 2, 64),
+				device.EnergySource,
+			})
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, fmt.Errorf("failed to write CSV: %w", err)
+	}
+
+	return &dtos.ACUsageReportCSVDTO{
+		FileName: fmt.Sprintf("ac-usage-%s.csv", report.Month),
+		Data:     buf.Bytes(),
+	}, nil
+}
+
+// reportMonth resolves a YYYY-MM month to its range in the deployment time zone, ending at now for the current month.
+func reportMonth(month string, now time.Time) (time.Time, time.Time, error) {
+	location := utils.Location()
+	now = now.In(location)
+
+	var from time.Time
+	if month == "" {
+		from = time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, location)
+	} else {
+		parsed, err := time.ParseInLocation("2006-01", month, location)
+		if err != nil {
+			return time.Time{}, time.Time{}, tuya_errors.BadRequest("month must be formatted as YYYY-MM")
+		}
+		from = parsed
+	}
+	if from.After(now) {
+		return time.Time{}, time.Time{}, tuya_errors.BadRequest("month %s has not started yet", from.Format("2006-01"))
+	}
+
+	to := from.AddDate(0, 1, 0)
+	if to.After(now) {
+		to = now
+	}
+	return from, to, nil
+}
+
+// loadPowerEvents reads the successful power commands sent before the end of the report, oldest first,
+// and the devices that received IR AC commands.
+func (uc *ACUsageReportUseCase) loadPowerEvents(to time.Time) (map[string][]acPowerEvent, map[string]bool, error) {
+	events := make(map[string][]acPowerEvent)
+	irACDevices := make(map[string]bool)
+	if uc.auditUC == nil {
+		return events, irACDevices, nil
+	}
+
+	records, err := uc.auditUC.List(time.Time{}, to, "")
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, record := range records {
+		entry := record.Entry
+		if entry.Action == AuditActionIRACCommand {
+			irACDevices[entry.DeviceID] = true
+		}
+		if !entry.Success || (entry.Action != AuditActionIRACCommand && entry.Action != AuditActionDeviceCommand) {
+			continue
+		}
+
+		var commands []dtos.DeviceStateCommandDTO
+		if err := json.Unmarshal([]byte(entry.Detail), &commands); err != nil {
+			continue
+		}
+		if on, ok := acPowerState(commands); ok {
+			events[entry.DeviceID] = append(events[entry.DeviceID], acPowerEvent{at: time.Unix(entry.Timestamp, 0), on: on})
+		}
+	}
+	return events, irACDevices, nil
+}
+
+// acPowerState returns the power state set by a command batch, if it contains a power command.
+func acPowerState(commands []dtos.DeviceStateCommandDTO) (bool, bool) {
+	for _, cmd := range commands {
+		if cmd.Code != "power" && cmd.Code != "switch" {
+			continue
+		}
+		if value, ok := numericValue(cmd.Value); ok {
+			return value != 0, true
+		}
+	}
+	return false, false
+}
+
+// deviceUsage computes the usage of a room device, or returns nil if it is not an air conditioner.
+func (uc *ACUsageReportUseCase) deviceUsage(ctx context.Context, accessToken, deviceID string, irAC bool, events []acPowerEvent, from, to time.Time) *dtos.ACDeviceUsageDTO {
+	var name string
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		// Removed or unreachable devices are still reported when the audit log shows they are IR ACs
+		utils.LogWarn("ACUsageReportUseCase: Failed to get device %s: %v", deviceID, err)
+	} else {
+		name = device.Name
+		irAC = irAC || acCategories[device.Category] || device.RemoteCategory == "infrared_ac"
+	}
+	if !irAC {
+		return nil
+	}
+
+	runtime := uc.runtime(deviceID, events, from, to)
+	usage := &dtos.ACDeviceUsageDTO{
+		DeviceID:     deviceID,
+		Name:         name,
+		RuntimeHours: roundTo(runtime.Hours(), 2),
+		EnergySource: "estimated",
+	}
+	if energy, ok := uc.meteredEnergy(ctx, accessToken, deviceID, from, to); ok {
+		usage.EnergyKWh = roundTo(energy, 2)
+		usage.EnergySource = "metered"
+	} else {
+		usage.EnergyKWh = roundTo(runtime.Hours()*uc.ratedWatts/1000, 2)
+	}
+	return usage
+}
+
+// runtime sums the time an air conditioner was on within [from, to).
+// The state at the start is taken from the last power command before it; without any power command up to
+// the end, the last known IR AC state is assumed to have held throughout.
+func (uc *ACUsageReportUseCase) runtime(deviceID string, events []acPowerEvent, from, to time.Time) time.Duration {
+	on := false
+	if len(events) == 0 && uc.deviceStateUC != nil {
+		if state, err := uc.deviceStateUC.GetDeviceState(deviceID); err == nil && state != nil {
+			on, _ = acPowerState(state.LastCommands)
+		}
+	}
+
+	var total time.Duration
+	since := from
+	for _, event := range events {
+		if !event.at.After(from) {
+			on = event.on
+			continue
+		}
+		if on && !event.on {
+			total += event.at.Sub(since)
+		} else if !on && event.on {
+			since = event.at
+		}
+		on = event.on
+	}
+	if on {
+		total += to.Sub(since)
+	}
+	return total
+}
+
+// meteredEnergy sums the recorded power readings of a device in kWh. Each hourly bucket contributes its
+// average draw for one hour. It reports false when the device has no power readings in the range.
+func (uc *ACUsageReportUseCase) meteredEnergy(ctx context.Context, accessToken, deviceID string, from, to time.Time) (float64, bool) {
+	if uc.historyUC == nil {
+		return 0, false
+	}
+	buckets, err := uc.historyUC.GetBuckets(deviceID, from, to)
+	if err != nil {
+		utils.LogWarn("ACUsageReportUseCase: Failed to read power history of %s: %v", deviceID, err)
+		return 0, false
+	}
+
+	var wattHours float64
+	metered := false
+	for _, bucket := range buckets {
+		rollup, ok := bucket.Codes[acMeteredPowerCode]
+		if !ok || rollup.Count == 0 {
+			continue
+		}
+		wattHours += rollup.Sum / float64(rollup.Count)
+		metered = true
+	}
+	if !metered {
+		return 0, false
+	}
+
+	scale := 0.0
+	if spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID); err == nil {
+		if fn, ok := findStatusFunction(spec, []string{acMeteredPowerCode}); ok {
+			scale = valueOr(parseFunctionValues(fn).Scale, 0)
+		}
+	}
+	return wattHours / math.Pow(10, scale) / 1000, true
+}
+
+// roundTo rounds a value to the given number of decimals.
+func roundTo(value float64, decimals int) float64 {
+	factor := math.Pow(10, float64(decimals))
+	return math.Round(value*factor) / factor
+}
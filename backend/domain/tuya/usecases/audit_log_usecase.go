@@ -0,0 +1,117 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+	"teralux_app/domain/tuya/repositories"
+	"time"
+)
+
+// Audit actions recorded by the control usecases.
+const (
+	AuditActionDeviceCommand   = "device_command"
+	AuditActionIRACCommand     = "ir_ac_command"
+	AuditActionIRRemoteCommand = "ir_remote_command"
+	AuditActionIRLearnedCode   = "ir_learned_code"
+	AuditActionCommandApproval = "command_approval"
+	AuditActionStateRestore    = "state_restore"
+	AuditActionCloudScene      = "cloud_scene"
+	AuditActionDoorLock        = "door_lock"
+)
+
+// AuditLogUseCase keeps an append-only log of control actions.
+// Entries are persistent until the archiver exports them.
+type AuditLogUseCase struct {
+	entries repositories.AuditLogRepository
+	clock   utils.Clock
+	ids     utils.IDGenerator
+}
+
+// NewAuditLogUseCase initializes a new AuditLogUseCase.
+//
+// param entries The AuditLogRepository used to persist entries (nil when unavailable).
+// param clock The Clock used to timestamp entries.
+// param ids The IDGenerator used for entry IDs.
+// return *AuditLogUseCase A pointer to the initialized usecase.
+func NewAuditLogUseCase(entries repositories.AuditLogRepository, clock utils.Clock, ids utils.IDGenerator) *AuditLogUseCase {
+	return &AuditLogUseCase{
+		entries: entries,
+		clock:   clock,
+		ids:     ids,
+	}
+}
+
+// Record appends an entry to the audit log.
+//
+// param action The action performed (e.g., AuditActionDeviceCommand).
+// param deviceID The device the action targeted.
+// param detail The action payload, encoded as JSON.
+// param actionErr The error returned by the action, or nil if it succeeded.
+// return error An error if the entry cannot be saved.
+func (uc *AuditLogUseCase) Record(action, deviceID string, detail interface{}, actionErr error) error {
+	if uc.entries == nil {
+		return nil
+	}
+
+	id, err := uc.ids.NewID(4)
+	if err != nil {
+		return fmt.Errorf("failed to generate audit entry id: %w", err)
+	}
+	detailData, err := json.Marshal(detail)
+	if err != nil {
+		return fmt.Errorf("failed to marshal audit detail: %w", err)
+	}
+
+	now := uc.clock.Now()
+	entry := entities.AuditLogEntry{
+		ID:        id,
+		Timestamp: now.Unix(),
+		Action:    action,
+		DeviceID:  deviceID,
+		Detail:    string(detailData),
+		Success:   actionErr == nil,
+	}
+	if actionErr != nil {
+		entry.Error = actionErr.Error()
+	}
+
+	if err := uc.entries.Append(entry, now); err != nil {
+		return fmt.Errorf("failed to save audit entry: %w", err)
+	}
+	return nil
+}
+
+// List returns the entries recorded in a time range, oldest first.
+//
+// param since The start of the range (inclusive); zero for the oldest entry.
+// param until The end of the range (exclusive); zero for the newest entry.
+// param deviceID The device the entries targeted; empty for every device.
+// return []repositories.AuditLogRecord The entries with the references that remove them; empty if the
+// audit log is unavailable.
+// return error An error if the audit log cannot be read.
+func (uc *AuditLogUseCase) List(since, until time.Time, deviceID string) ([]repositories.AuditLogRecord, error) {
+	if uc.entries == nil {
+		return nil, nil
+	}
+	records, err := uc.entries.List(repositories.AuditLogFilter{Since: since, Until: until, DeviceID: deviceID})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit log: %w", err)
+	}
+	return records, nil
+}
+
+// Remove deletes entries, e.g., once they are archived.
+//
+// param refs The references of the entries, as returned by List.
+// return error An error if the entries cannot be deleted.
+func (uc *AuditLogUseCase) Remove(refs []string) error {
+	if uc.entries == nil || len(refs) == 0 {
+		return nil
+	}
+	if err := uc.entries.Delete(refs); err != nil {
+		return fmt.Errorf("failed to delete audit entries: %w", err)
+	}
+	return nil
+}
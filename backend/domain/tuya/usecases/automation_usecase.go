@@ -0,0 +1,661 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/repositories"
+	"time"
+)
+
+const (
+	// automationQueueSize bounds the number of status reports waiting for evaluation.
+	automationQueueSize = 256
+	// automationHistoryLimit is the number of runs kept per rule.
+	automationHistoryLimit = 100
+)
+
+// Triggers recorded in the automation history.
+const (
+	AutomationTriggerCondition = "condition"
+	AutomationTriggerManual    = "manual"
+)
+
+// AutomationHouseModeCode is the condition code matching the house mode. House mode conditions have no device_id.
+const AutomationHouseModeCode = "house_mode"
+
+// ErrAutomationNotFound is returned when an automation rule does not exist.
+var ErrAutomationNotFound = tuya_errors.NotFound("automation not found")
+
+// automationStatus is a status report queued for evaluation.
+type automationStatus struct {
+	deviceID string
+	status   []dtos.TuyaDeviceStatusDTO
+}
+
+// AutomationUseCase manages automation rules and runs them when reported device states match.
+// Status reports from the sensor poller and the event service, and house mode changes, are queued and
+// evaluated in the background against the latest known value of every code. A rule fires when all of its
+// conditions become true, and fires again only after a condition stopped matching and the cooldown has passed.
+// Actions are sent with the server-managed token, so rules can only be saved with an API key (or identity) of
+// control scope that may access every device they reference, and each action is checked against the grants
+// of the rule's owner again before it is sent.
+type AutomationUseCase struct {
+	rules     repositories.AutomationRepository
+	controlUC *TuyaDeviceControlUseCase
+	authUC    *TuyaAuthUseCase
+	authz     CallerAuthorizer
+	houseMode *HouseModeUseCase
+	queue     chan automationStatus
+	clock     utils.Clock
+	ids       utils.IDGenerator
+
+	mu     sync.Mutex
+	values map[string]map[string]interface{}
+	active map[string]bool
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewAutomationUseCase initializes a new AutomationUseCase.
+//
+// param rules The AutomationRepository used to persist rules and their history (nil when unavailable).
+// param controlUC The usecase used to send rule actions.
+// param authUC The TuyaAuthUseCase providing the server-managed token for actions.
+// param authz The CallerAuthorizer checking the API key of the rule owner.
+// param houseMode The usecase providing the house mode for house_mode conditions (optional).
+// param clock The Clock used for cooldowns and history timestamps.
+// param ids The IDGenerator used for rule IDs.
+// return *AutomationUseCase A pointer to the initialized usecase.
+func NewAutomationUseCase(rules repositories.AutomationRepository, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, authz CallerAuthorizer, houseMode *HouseModeUseCase, clock utils.Clock, ids utils.IDGenerator) *AutomationUseCase {
+	return &AutomationUseCase{
+		rules:     rules,
+		controlUC: controlUC,
+		authUC:    authUC,
+		authz:     authz,
+		houseMode: houseMode,
+		queue:     make(chan automationStatus, automationQueueSize),
+		clock:     clock,
+		ids:       ids,
+		values:    make(map[string]map[string]interface{}),
+		active:    make(map[string]bool),
+	}
+}
+
+// Start evaluates queued status reports in the background.
+func (uc *AutomationUseCase) Start() {
+	if uc.rules == nil {
+		return
+	}
+
+	uc.startOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			for {
+				select {
+				case report := <-uc.queue:
+					uc.evaluate(report)
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("AutomationUseCase: Evaluator started")
+	})
+}
+
+// Stop ends the evaluator and waits for the report being evaluated. Queued reports are dropped.
+func (uc *AutomationUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// HandleStatus queues a status report for evaluation. Reports are dropped when the queue is full.
+//
+// param deviceID The device that reported the values.
+// param status The reported values.
+func (uc *AutomationUseCase) HandleStatus(deviceID string, status []dtos.TuyaDeviceStatusDTO) {
+	if uc.rules == nil || len(status) == 0 {
+		return
+	}
+	select {
+	case uc.queue <- automationStatus{deviceID: deviceID, status: status}:
+	default:
+		utils.LogWarn("AutomationUseCase: Queue full, dropping status report for %s", deviceID)
+	}
+}
+
+// HandleHouseModeChange queues the evaluation of rules with house_mode conditions.
+// Its signature matches HouseModeListener.
+//
+// param previous The mode before the change.
+// param current The mode after the change.
+func (uc *AutomationUseCase) HandleHouseModeChange(previous, current string) {
+	uc.HandleStatus("", []dtos.TuyaDeviceStatusDTO{{Code: AutomationHouseModeCode, Value: current}})
+}
+
+// ListRules returns all automation rules.
+//
+// return []dtos.AutomationRuleDTO The rules, ordered by ID.
+// return error An error if the rules cannot be read.
+func (uc *AutomationUseCase) ListRules() ([]dtos.AutomationRuleDTO, error) {
+	rules, err := uc.loadRules()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dtos.AutomationRuleDTO, len(rules))
+	for i, rule := range rules {
+		result[i] = toAutomationRuleDTO(rule)
+	}
+	return result, nil
+}
+
+// GetRule returns an automation rule.
+//
+// param id The rule ID.
+// return *dtos.AutomationRuleDTO The rule.
+// return error ErrAutomationNotFound if the rule does not exist.
+func (uc *AutomationUseCase) GetRule(id string) (*dtos.AutomationRuleDTO, error) {
+	rule, err := uc.loadRule(id)
+	if err != nil {
+		return nil, err
+	}
+	result := toAutomationRuleDTO(*rule)
+	return &result, nil
+}
+
+// CreateRule validates and stores a new automation rule. Rules are enabled unless the request says otherwise.
+//
+// param ctx The request context carrying the caller, who becomes the owner of the rule.
+// param req The rule definition.
+// return *dtos.AutomationRuleDTO The created rule.
+// return error A bad request error for invalid input, or a forbidden error if the caller may not control every device.
+func (uc *AutomationUseCase) CreateRule(ctx context.Context, req dtos.AutomationRuleRequestDTO) (*dtos.AutomationRuleDTO, error) {
+	if uc.rules == nil {
+		return nil, fmt.Errorf("automation storage not initialized")
+	}
+	rule, err := toAutomationRule(req)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Owner, err = authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, ruleDeviceIDs(rule)...); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate automation id: %w", err)
+	}
+	now := uc.clock.Now().Unix()
+	rule.ID = id
+	rule.Enabled = req.Enabled == nil || *req.Enabled
+	rule.CreatedAt = now
+	rule.UpdatedAt = now
+
+	if err := uc.saveRule(rule); err != nil {
+		return nil, err
+	}
+	utils.LogInfo("AutomationUseCase: Created rule %s (%s)", rule.ID, rule.Name)
+	result := toAutomationRuleDTO(*rule)
+	return &result, nil
+}
+
+// UpdateRule replaces the definition of an automation rule. The enabled state is kept unless the request sets it.
+// The caller becomes the owner of the rule.
+//
+// param ctx The request context carrying the caller.
+// param id The rule ID.
+// param req The new rule definition.
+// return *dtos.AutomationRuleDTO The updated rule.
+// return error ErrAutomationNotFound, a bad request error for invalid input, or a forbidden error if the caller
+// may not control every device.
+func (uc *AutomationUseCase) UpdateRule(ctx context.Context, id string, req dtos.AutomationRuleRequestDTO) (*dtos.AutomationRuleDTO, error) {
+	existing, err := uc.loadRule(id)
+	if err != nil {
+		return nil, err
+	}
+	rule, err := toAutomationRule(req)
+	if err != nil {
+		return nil, err
+	}
+	if rule.Owner, err = authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, ruleDeviceIDs(rule)...); err != nil {
+		return nil, err
+	}
+
+	rule.ID = existing.ID
+	rule.Enabled = existing.Enabled
+	if req.Enabled != nil {
+		rule.Enabled = *req.Enabled
+	}
+	rule.CreatedAt = existing.CreatedAt
+	rule.UpdatedAt = uc.clock.Now().Unix()
+	rule.LastTriggeredAt = existing.LastTriggeredAt
+
+	if err := uc.saveRule(rule); err != nil {
+		return nil, err
+	}
+	uc.resetState(id)
+	result := toAutomationRuleDTO(*rule)
+	return &result, nil
+}
+
+// SetEnabled enables or disables an automation rule.
+//
+// param id The rule ID.
+// param enabled Whether the rule should be evaluated.
+// return *dtos.AutomationRuleDTO The updated rule.
+// return error ErrAutomationNotFound if the rule does not exist.
+func (uc *AutomationUseCase) SetEnabled(id string, enabled bool) (*dtos.AutomationRuleDTO, error) {
+	rule, err := uc.loadRule(id)
+	if err != nil {
+		return nil, err
+	}
+	rule.Enabled = enabled
+	rule.UpdatedAt = uc.clock.Now().Unix()
+	if err := uc.saveRule(rule); err != nil {
+		return nil, err
+	}
+	uc.resetState(id)
+	result := toAutomationRuleDTO(*rule)
+	return &result, nil
+}
+
+// DeleteRule removes an automation rule and its history.
+//
+// param id The rule ID.
+// return error ErrAutomationNotFound if the rule does not exist.
+func (uc *AutomationUseCase) DeleteRule(id string) error {
+	if _, err := uc.loadRule(id); err != nil {
+		return err
+	}
+	if err := uc.rules.Delete(id); err != nil {
+		return fmt.Errorf("failed to delete automation: %w", err)
+	}
+	uc.resetState(id)
+	utils.LogInfo("AutomationUseCase: Deleted rule %s", id)
+	return nil
+}
+
+// GetHistory returns the recorded runs of an automation rule, newest first.
+//
+// param id The rule ID.
+// return *dtos.AutomationHistoryResponseDTO The runs.
+// return error ErrAutomationNotFound if the rule does not exist.
+func (uc *AutomationUseCase) GetHistory(id string) (*dtos.AutomationHistoryResponseDTO, error) {
+	if _, err := uc.loadRule(id); err != nil {
+		return nil, err
+	}
+	runs, err := uc.loadHistory(id)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dtos.AutomationRunDTO, len(runs))
+	for i, run := range runs {
+		results := make([]dtos.AutomationActionResultDTO, len(run.Results))
+		for j, r := range run.Results {
+			results[j] = dtos.AutomationActionResultDTO{DeviceID: r.DeviceID, Success: r.Success, Error: r.Error}
+		}
+		result[i] = dtos.AutomationRunDTO{
+			TriggeredAt: run.TriggeredAt,
+			Trigger:     run.Trigger,
+			DeviceID:    run.DeviceID,
+			Code:        run.Code,
+			Value:       run.Value,
+			Success:     run.Success,
+			Results:     results,
+		}
+	}
+	return &dtos.AutomationHistoryResponseDTO{RuleID: id, Runs: result, Total: len(result)}, nil
+}
+
+// RunRule runs the actions of an automation rule immediately, regardless of its conditions and enabled state.
+// Its signature matches SceneSwitchActionHandler so rules can be bound to scene switch buttons.
+//
+// param ctx The context bounding the commands, carrying the caller (or the owner of the scene switch binding).
+// param id The rule ID.
+// return error ErrAutomationNotFound, a forbidden error if the caller may not control the devices of the rule,
+// or an error if any action failed.
+func (uc *AutomationUseCase) RunRule(ctx context.Context, id string) error {
+	rule, err := uc.loadRule(id)
+	if err != nil {
+		return err
+	}
+	if _, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, ruleDeviceIDs(rule)...); err != nil {
+		return err
+	}
+	run := uc.fire(ctx, rule, entities.AutomationRun{Trigger: AutomationTriggerManual})
+	if !run.Success {
+		return fmt.Errorf("automation %s failed on %d of %d actions", id, countFailedActions(run.Results), len(run.Results))
+	}
+	return nil
+}
+
+// evaluate applies a status report to the latest values and fires the rules it makes match.
+func (uc *AutomationUseCase) evaluate(report automationStatus) {
+	uc.mu.Lock()
+	values, ok := uc.values[report.deviceID]
+	if !ok {
+		values = make(map[string]interface{})
+		uc.values[report.deviceID] = values
+	}
+	for _, s := range report.status {
+		values[s.Code] = s.Value
+	}
+	uc.mu.Unlock()
+
+	rules, err := uc.loadRules()
+	if err != nil {
+		utils.LogWarn("AutomationUseCase: Failed to load rules: %v", err)
+		return
+	}
+
+	for i := range rules {
+		rule := &rules[i]
+		if !rule.Enabled || !referencesDevice(rule, report.deviceID) {
+			continue
+		}
+
+		uc.mu.Lock()
+		matched := uc.matches(rule)
+		wasActive := uc.active[rule.ID]
+		uc.active[rule.ID] = matched
+		uc.mu.Unlock()
+
+		if !matched || wasActive {
+			continue
+		}
+		if rule.CooldownSeconds > 0 && rule.LastTriggeredAt > 0 &&
+			uc.clock.Now().Sub(time.Unix(rule.LastTriggeredAt, 0)) < time.Duration(rule.CooldownSeconds)*time.Second {
+			utils.LogDebug("AutomationUseCase: Rule %s matched during cooldown", rule.ID)
+			continue
+		}
+
+		run := entities.AutomationRun{Trigger: AutomationTriggerCondition, DeviceID: report.deviceID}
+		for _, s := range report.status {
+			if conditionCode(rule, report.deviceID, s.Code) {
+				run.Code = s.Code
+				run.Value = s.Value
+				break
+			}
+		}
+		utils.LogInfo("AutomationUseCase: Rule %s (%s) triggered by %s", rule.ID, rule.Name, report.deviceID)
+		uc.fire(context.Background(), rule, run)
+	}
+}
+
+// matches reports whether all conditions of a rule hold for the latest values. Callers hold uc.mu.
+func (uc *AutomationUseCase) matches(rule *entities.AutomationRule) bool {
+	for _, condition := range rule.Conditions {
+		value, ok := uc.values[condition.DeviceID][condition.Code]
+		if isHouseModeCondition(condition) {
+			if uc.houseMode == nil {
+				return false
+			}
+			value, ok = uc.houseMode.Mode(), true
+		}
+		if !ok || !compareAutomationValue(value, condition.Operator, condition.Value) {
+			return false
+		}
+	}
+	return true
+}
+
+// fire sends the actions of a rule with the server-managed token and records the run. Actions on devices the
+// owner of the rule may no longer control fail without being sent.
+func (uc *AutomationUseCase) fire(ctx context.Context, rule *entities.AutomationRule, run entities.AutomationRun) entities.AutomationRun {
+	now := uc.clock.Now().Unix()
+	run.TriggeredAt = now
+	run.Success = true
+	run.Results = make([]entities.AutomationActionResult, 0, len(rule.Actions))
+
+	token, tokenErr := uc.authUC.GetServerToken(ctx)
+	for _, action := range rule.Actions {
+		result := entities.AutomationActionResult{DeviceID: action.DeviceID, Success: true}
+		err := tokenErr
+		if err == nil {
+			err = authorizeCaller(uc.authz, rule.Owner, utils.APIKeyScopeControl, action.DeviceID)
+		}
+		if err == nil {
+			commands := make([]dtos.TuyaCommandDTO, len(action.Commands))
+			for i, cmd := range action.Commands {
+				commands[i] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+			}
+			_, err = uc.controlUC.SendCommand(ctx, token.AccessToken, action.DeviceID, commands)
+		}
+		if err != nil {
+			utils.LogWarn("AutomationUseCase: Rule %s action on %s failed: %v", rule.ID, action.DeviceID, err)
+			result.Success = false
+			result.Error = err.Error()
+			run.Success = false
+		}
+		run.Results = append(run.Results, result)
+	}
+
+	// The rule is reloaded so a concurrent edit is not overwritten with the stale copy
+	if current, err := uc.loadRule(rule.ID); err == nil {
+		current.LastTriggeredAt = now
+		if err := uc.saveRule(current); err != nil {
+			utils.LogWarn("AutomationUseCase: Failed to update rule %s: %v", rule.ID, err)
+		}
+	}
+	uc.appendHistory(rule.ID, run)
+	return run
+}
+
+// resetState forgets whether a rule matched, so it is evaluated afresh on the next report.
+func (uc *AutomationUseCase) resetState(id string) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	delete(uc.active, id)
+}
+
+// loadRules reads all persisted rules.
+func (uc *AutomationUseCase) loadRules() ([]entities.AutomationRule, error) {
+	if uc.rules == nil {
+		return nil, fmt.Errorf("automation storage not initialized")
+	}
+	rules, err := uc.rules.List()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list automations: %w", err)
+	}
+	return rules, nil
+}
+
+// loadRule reads a persisted rule.
+func (uc *AutomationUseCase) loadRule(id string) (*entities.AutomationRule, error) {
+	if uc.rules == nil {
+		return nil, fmt.Errorf("automation storage not initialized")
+	}
+	rule, err := uc.rules.Get(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automation: %w", err)
+	}
+	if rule == nil {
+		return nil, ErrAutomationNotFound
+	}
+	return rule, nil
+}
+
+// saveRule persists a rule.
+func (uc *AutomationUseCase) saveRule(rule *entities.AutomationRule) error {
+	if err := uc.rules.Save(*rule); err != nil {
+		return fmt.Errorf("failed to save automation: %w", err)
+	}
+	return nil
+}
+
+// loadHistory reads the recorded runs of a rule, newest first.
+func (uc *AutomationUseCase) loadHistory(id string) ([]entities.AutomationRun, error) {
+	runs, err := uc.rules.GetHistory(id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get automation history: %w", err)
+	}
+	if runs == nil {
+		return []entities.AutomationRun{}, nil
+	}
+	return runs, nil
+}
+
+// appendHistory records a run, keeping the newest automationHistoryLimit runs.
+func (uc *AutomationUseCase) appendHistory(id string, run entities.AutomationRun) {
+	runs, err := uc.loadHistory(id)
+	if err != nil {
+		utils.LogWarn("AutomationUseCase: Failed to read history of %s: %v", id, err)
+		runs = nil
+	}
+	runs = append([]entities.AutomationRun{run}, runs...)
+	if len(runs) > automationHistoryLimit {
+		runs = runs[:automationHistoryLimit]
+	}
+
+	if err := uc.rules.SaveHistory(id, runs); err != nil {
+		utils.LogWarn("AutomationUseCase: Failed to save history of %s: %v", id, err)
+	}
+}
+
+// toAutomationRule validates a rule request and converts it into an entity without ID or timestamps.
+func toAutomationRule(req dtos.AutomationRuleRequestDTO) (*entities.AutomationRule, error) {
+	rule := &entities.AutomationRule{Name: req.Name, CooldownSeconds: req.CooldownSeconds}
+
+	for _, c := range req.Conditions {
+		if c.DeviceID == "" && c.Code == AutomationHouseModeCode {
+			if mode, _ := c.Value.(string); !IsHouseMode(mode) || (c.Operator != "==" && c.Operator != "!=") {
+				return nil, tuya_errors.BadRequest("house_mode conditions use == or != with home, away, sleep or holiday")
+			}
+		}
+		if _, numeric := numericValue(c.Value); !numeric && c.Operator != "==" && c.Operator != "!=" {
+			return nil, tuya_errors.BadRequest("operator %s on %s requires a numeric value", c.Operator, c.Code)
+		}
+		rule.Conditions = append(rule.Conditions, entities.AutomationCondition{
+			DeviceID: c.DeviceID,
+			Code:     c.Code,
+			Operator: c.Operator,
+			Value:    c.Value,
+		})
+	}
+
+	for _, a := range req.Actions {
+		commands := make([]entities.TuyaCommand, len(a.Commands))
+		for i, cmd := range a.Commands {
+			if cmd.Code == "" {
+				return nil, tuya_errors.BadRequest("commands for %s require a code", a.DeviceID)
+			}
+			commands[i] = entities.TuyaCommand{Code: cmd.Code, Value: cmd.Value}
+		}
+		rule.Actions = append(rule.Actions, entities.AutomationAction{DeviceID: a.DeviceID, Commands: commands})
+	}
+	return rule, nil
+}
+
+// toAutomationRuleDTO converts a stored rule into its DTO.
+func toAutomationRuleDTO(rule entities.AutomationRule) dtos.AutomationRuleDTO {
+	conditions := make([]dtos.AutomationConditionDTO, len(rule.Conditions))
+	for i, c := range rule.Conditions {
+		conditions[i] = dtos.AutomationConditionDTO{DeviceID: c.DeviceID, Code: c.Code, Operator: c.Operator, Value: c.Value}
+	}
+	actions := make([]dtos.AutomationActionDTO, len(rule.Actions))
+	for i, a := range rule.Actions {
+		commands := make([]dtos.TuyaCommandDTO, len(a.Commands))
+		for j, cmd := range a.Commands {
+			commands[j] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+		}
+		actions[i] = dtos.AutomationActionDTO{DeviceID: a.DeviceID, Commands: commands}
+	}
+	return dtos.AutomationRuleDTO{
+		ID:              rule.ID,
+		Name:            rule.Name,
+		Enabled:         rule.Enabled,
+		Conditions:      conditions,
+		Actions:         actions,
+		CooldownSeconds: rule.CooldownSeconds,
+		CreatedAt:       rule.CreatedAt,
+		UpdatedAt:       rule.UpdatedAt,
+		LastTriggeredAt: rule.LastTriggeredAt,
+	}
+}
+
+// ruleDeviceIDs returns the devices a rule watches or controls.
+func ruleDeviceIDs(rule *entities.AutomationRule) []string {
+	var deviceIDs []string
+	for _, c := range rule.Conditions {
+		if !isHouseModeCondition(c) {
+			deviceIDs = append(deviceIDs, c.DeviceID)
+		}
+	}
+	for _, a := range rule.Actions {
+		deviceIDs = append(deviceIDs, a.DeviceID)
+	}
+	return deviceIDs
+}
+
+// referencesDevice reports whether any condition of a rule watches a device.
+func referencesDevice(rule *entities.AutomationRule, deviceID string) bool {
+	for _, c := range rule.Conditions {
+		if c.DeviceID == deviceID {
+			return true
+		}
+	}
+	return false
+}
+
+// isHouseModeCondition reports whether a condition matches the house mode instead of a device value.
+func isHouseModeCondition(condition entities.AutomationCondition) bool {
+	return condition.DeviceID == "" && condition.Code == AutomationHouseModeCode
+}
+
+// conditionCode reports whether a rule has a condition on a code of a device.
+func conditionCode(rule *entities.AutomationRule, deviceID, code string) bool {
+	for _, c := range rule.Conditions {
+		if c.DeviceID == deviceID && c.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// compareAutomationValue applies a condition operator. Numbers and booleans are compared numerically;
+// other values only support "==" and "!=", compared by their string form.
+func compareAutomationValue(actual interface{}, operator string, expected interface{}) bool {
+	a, aNumeric := numericValue(actual)
+	e, eNumeric := numericValue(expected)
+	if aNumeric && eNumeric {
+		switch operator {
+		case ">":
+			return a > e
+		case ">=":
+			return a >= e
+		case "<":
+			return a < e
+		case "<=":
+			return a <= e
+		case "==":
+			return a == e
+		case "!=":
+			return a != e
+		}
+		return false
+	}
+
+	switch operator {
+	case "==":
+		return fmt.Sprint(actual) == fmt.Sprint(expected)
+	case "!=":
+		return fmt.Sprint(actual) != fmt.Sprint(expected)
+	}
+	return false
+}
+
+// countFailedActions counts the failed actions of a run.
+func countFailedActions(results []entities.AutomationActionResult) int {
+	failed := 0
+	for _, r := range results {
+		if !r.Success {
+			failed++
+		}
+	}
+	return failed
+}
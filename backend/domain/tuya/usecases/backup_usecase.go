@@ -0,0 +1,289 @@
+package usecases
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	job_entities "teralux_app/domain/jobs/entities"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+const (
+	// backupFormat identifies backup archives.
+	backupFormat = "teralux-backup"
+	// backupVersion is the archive layout written by export and the newest one Restore accepts.
+	backupVersion = 1
+)
+
+// BackupExportJobType is the job type of backup exports.
+const BackupExportJobType = "backup_export"
+
+// ErrBackupNotFound is returned when a backup export job does not exist or expired.
+var ErrBackupNotFound = tuya_errors.NotFound("backup not found")
+
+// Restore modes.
+const (
+	BackupRestoreModeMerge   = "merge"
+	BackupRestoreModeReplace = "replace"
+)
+
+// backupExcludedPrefixes are persistent keys that belong to one deployment and are neither exported nor
+// overwritten: replication progress, queued jobs and the Tuya server token.
+var backupExcludedPrefixes = []string{"replication:", "job:", serverTokenKey}
+
+// BackupUseCase exports the configuration and device state of a deployment into a portable JSON archive
+// and restores such archives, for migrating deployments and disaster recovery. The archive holds every
+// persistent BadgerDB entry (device states, automations, scenes, metadata, webhooks, notification rules, ...)
+// and the rooms of the SQL database. Entries with a TTL (cache data, sessions, cooldowns) are transient and
+// left out. Services that keep their configuration in memory pick up restored data after a restart.
+// Archives are built by background jobs and kept as the job result for JOB_RETENTION.
+type BackupUseCase struct {
+	cache     persistence.CacheStore
+	jobRunner *job_services.JobRunnerService
+	roomUC    *RoomUseCase
+	clock     utils.Clock
+}
+
+// NewBackupUseCase initializes a new BackupUseCase and registers its job type.
+//
+// param cache The CacheStore holding the persistent entries.
+// param jobRunner The JobRunnerService executing exports.
+// param roomUC The RoomUseCase exporting and importing rooms.
+// param clock The Clock used to timestamp archives.
+// return *BackupUseCase A pointer to the initialized usecase.
+func NewBackupUseCase(cache persistence.CacheStore, jobRunner *job_services.JobRunnerService, roomUC *RoomUseCase, clock utils.Clock) *BackupUseCase {
+	uc := &BackupUseCase{
+		cache:     cache,
+		jobRunner: jobRunner,
+		roomUC:    roomUC,
+		clock:     clock,
+	}
+	jobRunner.Register(BackupExportJobType, uc.runExport)
+	return uc
+}
+
+// StartExport queues the export of a backup archive.
+//
+// param ctx The request context carrying the caller.
+// return *dtos.BackupExportStartedDTO The job building the archive.
+// return error An unavailable error if persistence is not initialized, or an error if the job cannot be queued.
+func (uc *BackupUseCase) StartExport(ctx context.Context) (*dtos.BackupExportStartedDTO, error) {
+	if uc.cache == nil {
+		return nil, tuya_errors.Unavailable("backup unavailable: persistence not initialized")
+	}
+	job, err := uc.jobRunner.Enqueue(ctx, BackupExportJobType, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.BackupExportStartedDTO{JobID: job.ID, Status: job.Status}, nil
+}
+
+// GetArchive returns the archive built by a backup export job.
+//
+// param jobID The ID of the export job.
+// return json.RawMessage The archive, as produced by the job.
+// return error ErrBackupNotFound if the job does not exist or is not a backup export, or a conflict error
+// while the job has not succeeded.
+func (uc *BackupUseCase) GetArchive(jobID string) (json.RawMessage, error) {
+	job, err := uc.jobRunner.Get(jobID)
+	if errors.Is(err, job_services.ErrJobNotFound) {
+		return nil, ErrBackupNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	if job.Type != BackupExportJobType {
+		return nil, ErrBackupNotFound
+	}
+	switch job.Status {
+	case job_entities.JobStatusSucceeded:
+		return job.Result, nil
+	case job_entities.JobStatusFailed:
+		return nil, tuya_errors.Conflict("backup export failed: %s", job.Error)
+	case job_entities.JobStatusCancelled:
+		return nil, tuya_errors.Conflict("backup export was cancelled")
+	default:
+		return nil, tuya_errors.Conflict("backup export is %s", job.Status).WithHint("poll GET /api/jobs/{job_id} until the job succeeded")
+	}
+}
+
+// runExport is the job handler building a backup archive, kept as the job result.
+func (uc *BackupUseCase) runExport(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	if uc.cache == nil {
+		return nil, job_services.Permanent(fmt.Errorf("persistence not initialized"))
+	}
+	return uc.export()
+}
+
+// export builds a backup archive. Rooms are left out (with a warning) while the SQL database is unavailable.
+func (uc *BackupUseCase) export() (*dtos.BackupArchiveDTO, error) {
+	if uc.cache == nil {
+		return nil, tuya_errors.Unavailable("backup unavailable: persistence not initialized")
+	}
+
+	stored, err := uc.cache.ListPersistent(backupExcludedPrefixes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read persistent entries: %w", err)
+	}
+	entries := make([]dtos.BackupEntryDTO, len(stored))
+	for i, entry := range stored {
+		entries[i] = dtos.BackupEntryDTO{Key: entry.Key}
+		if json.Valid(entry.Value) {
+			entries[i].Value = json.RawMessage(entry.Value)
+		} else {
+			entries[i].ValueBase64 = base64.StdEncoding.EncodeToString(entry.Value)
+		}
+	}
+
+	rooms, err := uc.roomUC.ListRooms()
+	if errors.Is(err, ErrRoomsUnavailable) {
+		utils.LogWarn("BackupUseCase: Rooms are not part of the backup: %v", err)
+		rooms = nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("BackupUseCase: Exported %d entries and %d rooms", len(entries), len(rooms))
+	return &dtos.BackupArchiveDTO{
+		Format:    backupFormat,
+		Version:   backupVersion,
+		CreatedAt: uc.clock.Now().Unix(),
+		Entries:   entries,
+		Rooms:     rooms,
+	}, nil
+}
+
+// Restore writes a backup archive. In merge mode, entries and rooms of the archive overwrite existing ones
+// with the same key or ID and everything else is kept; in replace mode, persistent entries and rooms missing
+// from the archive are removed first. Archives exported while the SQL database was unavailable have no rooms
+// (null) and leave rooms untouched. The archive is validated completely before anything is written, and
+// rooms are written before entries, so a room conflict leaves the deployment untouched.
+//
+// param archive The archive built by a backup export job.
+// param mode BackupRestoreModeMerge (default when empty) or BackupRestoreModeReplace.
+// return *dtos.BackupRestoreResultDTO What was written.
+// return error A bad request error for an invalid archive or mode, a conflict error for rooms whose name is
+// taken, ErrRoomsUnavailable when the archive has rooms but the SQL database is unavailable, or a storage error.
+func (uc *BackupUseCase) Restore(archive dtos.BackupArchiveDTO, mode string) (*dtos.BackupRestoreResultDTO, error) {
+	if uc.cache == nil {
+		return nil, tuya_errors.Unavailable("restore unavailable: persistence not initialized")
+	}
+	if mode == "" {
+		mode = BackupRestoreModeMerge
+	}
+	if mode != BackupRestoreModeMerge && mode != BackupRestoreModeReplace {
+		return nil, tuya_errors.BadRequest("unknown restore mode %q (supported: merge, replace)", mode)
+	}
+	if archive.Format != backupFormat {
+		return nil, tuya_errors.BadRequest("not a backup archive: format is %q, expected %q", archive.Format, backupFormat)
+	}
+	if archive.Version < 1 || archive.Version > backupVersion {
+		return nil, tuya_errors.BadRequest("unsupported backup version %d (supported: 1 to %d)", archive.Version, backupVersion)
+	}
+
+	entries, err := decodeBackupEntries(archive.Entries)
+	if err != nil {
+		return nil, err
+	}
+	for _, room := range archive.Rooms {
+		if room.ID == "" || strings.TrimSpace(room.Name) == "" {
+			return nil, tuya_errors.BadRequest("backup room %q needs an id and a name", room.ID)
+		}
+	}
+
+	result := &dtos.BackupRestoreResultDTO{Mode: mode, RestartRequired: true}
+	if archive.Rooms != nil {
+		rooms, err := uc.roomUC.ImportRooms(archive.Rooms, mode == BackupRestoreModeReplace)
+		if errors.Is(err, ErrRoomsUnavailable) && len(archive.Rooms) == 0 {
+			err = nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		result.Rooms = rooms
+	}
+
+	if mode == BackupRestoreModeReplace {
+		removed, err := uc.removeMissing(entries)
+		if err != nil {
+			return nil, err
+		}
+		result.Removed = removed
+	}
+	if len(entries) > 0 {
+		if err := uc.cache.SetPersistentBatch(entries); err != nil {
+			return nil, fmt.Errorf("failed to write backup entries: %w", err)
+		}
+	}
+	result.Entries = len(entries)
+
+	utils.LogInfo("BackupUseCase: Restored %d entries and %d rooms (%s mode, %d entries removed)", result.Entries, result.Rooms, mode, result.Removed)
+	return result, nil
+}
+
+// removeMissing deletes the persistent entries that are not part of the restored archive.
+func (uc *BackupUseCase) removeMissing(entries []persistence.KeyValue) (int, error) {
+	restored := make(map[string]bool, len(entries))
+	for _, entry := range entries {
+		restored[entry.Key] = true
+	}
+	existing, err := uc.cache.ListPersistent(backupExcludedPrefixes)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read persistent entries: %w", err)
+	}
+	var missing []string
+	for _, entry := range existing {
+		if !restored[entry.Key] {
+			missing = append(missing, entry.Key)
+		}
+	}
+	if len(missing) == 0 {
+		return 0, nil
+	}
+	if err := uc.cache.DeleteBatch(missing); err != nil {
+		return 0, fmt.Errorf("failed to remove entries missing from the backup: %w", err)
+	}
+	return len(missing), nil
+}
+
+// decodeBackupEntries converts archive entries into key-value pairs, refusing excluded keys and entries
+// with both a value and a value_base64. Entries with neither restore an empty value.
+func decodeBackupEntries(archived []dtos.BackupEntryDTO) ([]persistence.KeyValue, error) {
+	entries := make([]persistence.KeyValue, 0, len(archived))
+	seen := make(map[string]bool, len(archived))
+	for _, entry := range archived {
+		if entry.Key == "" {
+			return nil, tuya_errors.BadRequest("backup entry without key")
+		}
+		if seen[entry.Key] {
+			return nil, tuya_errors.BadRequest("backup entry %s appears twice", entry.Key)
+		}
+		seen[entry.Key] = true
+		for _, prefix := range backupExcludedPrefixes {
+			if strings.HasPrefix(entry.Key, prefix) {
+				return nil, tuya_errors.BadRequest("backup entry %s cannot be restored", entry.Key)
+			}
+		}
+
+		if len(entry.Value) > 0 && entry.ValueBase64 != "" {
+			return nil, tuya_errors.BadRequest("backup entry %s has both value and value_base64", entry.Key)
+		}
+		value := []byte(entry.Value)
+		if entry.ValueBase64 != "" {
+			decoded, err := base64.StdEncoding.DecodeString(entry.ValueBase64)
+			if err != nil {
+				return nil, tuya_errors.BadRequest("backup entry %s has invalid value_base64: %v", entry.Key, err)
+			}
+			value = decoded
+		}
+		entries = append(entries, persistence.KeyValue{Key: entry.Key, Value: value})
+	}
+	return entries, nil
+}
@@ -0,0 +1,149 @@
+package usecases
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+)
+
+// Authentication types reported by the bootstrap endpoint.
+const (
+	BootstrapSessionTypeSession = "session"
+	BootstrapSessionTypeToken   = "token"
+	BootstrapSessionTypeServer  = "server"
+)
+
+// BootstrapUseCase assembles everything the app needs on cold start in a single response.
+// Every section is built from cached or persisted data and fails independently, so one unavailable
+// source (e.g., the rooms database) does not prevent the app from starting.
+type BootstrapUseCase struct {
+	getAllDevicesUC *TuyaGetAllDevicesUseCase
+	sessionUC       *TuyaSessionUseCase
+	roomUC          *RoomUseCase
+	favoriteUC      *FavoriteUseCase
+	sensorUC        *TuyaSensorUseCase
+	houseModeUC     *HouseModeUseCase
+	clock           utils.Clock
+}
+
+// NewBootstrapUseCase initializes a new BootstrapUseCase.
+//
+// param getAllDevicesUC The usecase providing the (cached) device list.
+// param sessionUC The usecase describing server-side sessions.
+// param roomUC The usecase providing rooms.
+// param favoriteUC The usecase providing favorite devices.
+// param sensorUC The usecase providing active sensor alarms.
+// param houseModeUC The usecase providing the house mode.
+// param clock The Clock used to timestamp the response.
+// return *BootstrapUseCase A pointer to the initialized usecase.
+func NewBootstrapUseCase(getAllDevicesUC *TuyaGetAllDevicesUseCase, sessionUC *TuyaSessionUseCase, roomUC *RoomUseCase, favoriteUC *FavoriteUseCase, sensorUC *TuyaSensorUseCase, houseModeUC *HouseModeUseCase, clock utils.Clock) *BootstrapUseCase {
+	return &BootstrapUseCase{
+		getAllDevicesUC: getAllDevicesUC,
+		sessionUC:       sessionUC,
+		roomUC:          roomUC,
+		favoriteUC:      favoriteUC,
+		sensorUC:        sensorUC,
+		houseModeUC:     houseModeUC,
+		clock:           clock,
+	}
+}
+
+// GetBootstrap builds the cold start payload for the authenticated caller.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The Tuya access token of the request.
+// param uid The Tuya User ID whose devices are summarized.
+// param sessionType How the request was authenticated (session, token or server).
+// param sessionID The session ID when sessionType is "session".
+// param visible Devices the caller may see (nil for all devices); rooms are narrowed to them as well.
+// return *dtos.BootstrapDTO The payload; sections that failed are listed in Unavailable.
+func (uc *BootstrapUseCase) GetBootstrap(ctx context.Context, accessToken, uid, sessionType, sessionID string, visible DeviceFilter) *dtos.BootstrapDTO {
+	result := &dtos.BootstrapDTO{
+		Session:     dtos.BootstrapSessionDTO{Valid: true, Type: sessionType, UID: uid},
+		Rooms:       []dtos.RoomDTO{},
+		Favorites:   []dtos.TuyaDeviceDTO{},
+		Alerts:      []dtos.ActiveAlertDTO{},
+		GeneratedAt: uc.clock.Now().Unix(),
+	}
+
+	if sessionType == BootstrapSessionTypeSession {
+		expiresAt, err := uc.sessionUC.SessionExpiry(sessionID)
+		if err != nil {
+			uc.unavailable(result, "session", err)
+		} else {
+			result.Session.ExpiresAt = expiresAt
+		}
+	}
+
+	devices := make(map[string]dtos.TuyaDeviceDTO)
+	response, err := uc.getAllDevicesUC.GetAllDevices(ctx, accessToken, uid, 0, 0, "", visible)
+	if err != nil {
+		uc.unavailable(result, "summary", err)
+	} else {
+		result.Summary = summarizeDevices(response.Devices, devices)
+	}
+
+	if rooms, err := uc.roomUC.ListRooms(); err != nil {
+		uc.unavailable(result, "rooms", err)
+	} else {
+		result.Rooms = FilterRooms(rooms, visible)
+	}
+
+	if favorites, err := uc.favoriteUC.GetFavorites(uid); err != nil {
+		uc.unavailable(result, "favorites", err)
+	} else {
+		for _, id := range favorites {
+			if device, ok := devices[id]; ok {
+				result.Favorites = append(result.Favorites, device)
+			}
+		}
+	}
+
+	if alarms, err := uc.sensorUC.ActiveAlarms(); err != nil {
+		uc.unavailable(result, "alerts", err)
+	} else {
+		for _, id := range alarms {
+			alert := dtos.ActiveAlertDTO{DeviceID: id}
+			if device, ok := devices[id]; ok {
+				alert.Name = device.Name
+				alert.Category = device.Category
+			}
+			result.Alerts = append(result.Alerts, alert)
+		}
+	}
+
+	if uc.houseModeUC != nil {
+		result.HouseMode = uc.houseModeUC.Mode()
+	}
+	return result
+}
+
+// unavailable records a section that could not be assembled.
+func (uc *BootstrapUseCase) unavailable(result *dtos.BootstrapDTO, section string, err error) {
+	utils.LogWarn("BootstrapUseCase: %s unavailable: %v", section, err)
+	result.Unavailable = append(result.Unavailable, section)
+}
+
+// summarizeDevices counts devices, including devices grouped under a hub, and indexes them by ID.
+func summarizeDevices(list []dtos.TuyaDeviceDTO, index map[string]dtos.TuyaDeviceDTO) *dtos.DeviceSummaryDTO {
+	summary := &dtos.DeviceSummaryDTO{Categories: make(map[string]int)}
+
+	var visit func(devices []dtos.TuyaDeviceDTO)
+	visit = func(devices []dtos.TuyaDeviceDTO) {
+		for _, device := range devices {
+			if _, seen := index[device.ID]; !seen {
+				index[device.ID] = device
+				summary.Total++
+				if device.Online {
+					summary.Online++
+				} else {
+					summary.Offline++
+				}
+				summary.Categories[device.Category]++
+			}
+			visit(device.Collections)
+		}
+	}
+	visit(list)
+	return summary
+}
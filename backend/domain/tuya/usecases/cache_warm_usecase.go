@@ -0,0 +1,88 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"teralux_app/domain/common/utils"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// CacheWarmJobType is the job type of cache warms.
+const CacheWarmJobType = "cache_warm"
+
+// CacheWarmUseCase fills the cache with the device list of TUYA_USER_ID and the specification of every
+// device, so the first clients after a deploy or a cache flush are not served by cold Tuya calls. Warms run
+// as background jobs with the server-managed token.
+type CacheWarmUseCase struct {
+	jobRunner       *job_services.JobRunnerService
+	getAllDevicesUC *TuyaGetAllDevicesUseCase
+	specUC          *DeviceSpecificationUseCase
+	authUC          *TuyaAuthUseCase
+}
+
+// NewCacheWarmUseCase initializes a new CacheWarmUseCase and registers its job type.
+//
+// param jobRunner The JobRunnerService executing warms.
+// param getAllDevicesUC The usecase fetching and caching the device list.
+// param specUC The usecase fetching and caching device specifications.
+// param authUC The TuyaAuthUseCase providing the server-managed token used by the job.
+// return *CacheWarmUseCase A pointer to the initialized usecase.
+func NewCacheWarmUseCase(jobRunner *job_services.JobRunnerService, getAllDevicesUC *TuyaGetAllDevicesUseCase, specUC *DeviceSpecificationUseCase, authUC *TuyaAuthUseCase) *CacheWarmUseCase {
+	uc := &CacheWarmUseCase{
+		jobRunner:       jobRunner,
+		getAllDevicesUC: getAllDevicesUC,
+		specUC:          specUC,
+		authUC:          authUC,
+	}
+	jobRunner.Register(CacheWarmJobType, uc.runWarm)
+	return uc
+}
+
+// StartWarm queues a cache warm.
+//
+// param ctx The request context carrying the caller.
+// return *dtos.CacheWarmStartedDTO The job warming the cache.
+// return error A bad request error if TUYA_USER_ID is not set, or an error if the job cannot be queued.
+func (uc *CacheWarmUseCase) StartWarm(ctx context.Context) (*dtos.CacheWarmStartedDTO, error) {
+	if utils.GetConfig().TuyaUserID == "" {
+		return nil, tuya_errors.BadRequest("TUYA_USER_ID is not set").WithHint("the cache is warmed with the devices of TUYA_USER_ID")
+	}
+	job, err := uc.jobRunner.Enqueue(ctx, CacheWarmJobType, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.CacheWarmStartedDTO{JobID: job.ID, Status: job.Status}, nil
+}
+
+// runWarm is the job handler fetching the device list and the specifications into the cache.
+func (uc *CacheWarmUseCase) runWarm(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	uid := utils.GetConfig().TuyaUserID
+	if uid == "" {
+		return nil, job_services.Permanent(fmt.Errorf("TUYA_USER_ID is not set"))
+	}
+	accessToken, err := uc.authUC.ServerAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	job.ReportProgress(0, "fetching device list")
+	response, err := uc.getAllDevicesUC.GetAllDevices(ctx, accessToken, uid, 0, 0, "", nil)
+	if err != nil {
+		return nil, err
+	}
+	deviceIDs := make([]string, len(response.Devices))
+	for i, device := range response.Devices {
+		deviceIDs[i] = device.ID
+	}
+
+	job.ReportProgress(50, fmt.Sprintf("fetching %d specifications", len(deviceIDs)))
+	specs := uc.specUC.GetSpecifications(ctx, accessToken, deviceIDs)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("CacheWarmUseCase: Warmed %d devices and %d specifications", len(deviceIDs), len(specs))
+	return dtos.CacheWarmResultDTO{Devices: len(deviceIDs), Specifications: len(specs)}, nil
+}
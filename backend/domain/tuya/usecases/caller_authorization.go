@@ -0,0 +1,92 @@
+package usecases
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// CallerAuthorizer checks the credentials behind a request, and again when background work started by the
+// request runs with the server-managed token: the key may have been revoked, or its scope and device grants
+// changed, since the work was queued. It is implemented by the APIKeyUseCase.
+type CallerAuthorizer interface {
+	CurrentScope(identity utils.APIKeyIdentity) (string, bool)
+	CanAccessDevice(keyID, deviceID string) bool
+}
+
+// authorizeRequest checks that the caller of a request may control the devices it names. Work that runs with
+// the server-managed token (queued commands, scenes, rollouts, ...) must never be accepted from a request
+// authenticated only by a raw Tuya token, since any bearer string passes as one.
+//
+// param ctx The request context carrying the caller (see utils.APIKeyIdentityFromContext).
+// param authz The CallerAuthorizer checking the credentials.
+// param scope The least privileged scope allowed.
+// param deviceIDs The devices the request controls.
+// return utils.APIKeyIdentity The caller, to store with the work it starts.
+// return error A forbidden error if the caller is unverified, lacks the scope, or may not access a device.
+func authorizeRequest(ctx context.Context, authz CallerAuthorizer, scope string, deviceIDs ...string) (utils.APIKeyIdentity, error) {
+	identity := utils.APIKeyIdentityFromContext(ctx)
+	if identity.Scope == "" {
+		return identity, tuya_errors.Forbidden("an API key or identity token of %s scope is required", scope).
+			WithHint("send an X-API-KEY header, or authenticate with a session created through /api/tuya/auth")
+	}
+	return identity, authorizeCaller(authz, identity, scope, deviceIDs...)
+}
+
+// authorizeCaller checks that stored credentials still hold the scope and may access the devices.
+//
+// param authz The CallerAuthorizer checking the credentials.
+// param identity The credentials the work was started with.
+// param scope The least privileged scope allowed.
+// param deviceIDs The devices the work controls.
+// return error A forbidden error if the credentials are no longer valid, lack the scope, or may not access a device.
+func authorizeCaller(authz CallerAuthorizer, identity utils.APIKeyIdentity, scope string, deviceIDs ...string) error {
+	current, ok := authz.CurrentScope(identity)
+	if !ok {
+		return tuya_errors.Forbidden("the API key or identity of the caller is no longer valid")
+	}
+	if !utils.APIKeyScopeAllows(current, scope) {
+		return tuya_errors.Forbidden("API key scope '%s' is not allowed to do this; %s scope is required", current, scope)
+	}
+	if identity.ID == "" {
+		return nil
+	}
+	for _, deviceID := range deviceIDs {
+		if !authz.CanAccessDevice(identity.ID, deviceID) {
+			return tuya_errors.Forbidden("API key is not allowed to access device %s", deviceID)
+		}
+	}
+	return nil
+}
+
+// authorizeDevices checks device IDs named in a request body against the devices the caller may access.
+// DeviceAccessMiddleware only checks the device in the route, so usecases acting on other devices check them here.
+//
+// param visible The devices the caller may access (nil allows all).
+// param deviceIDs The devices the request acts on.
+// return error A forbidden error for the first device the caller may not access.
+func authorizeDevices(visible DeviceFilter, deviceIDs ...string) error {
+	if visible == nil {
+		return nil
+	}
+	for _, deviceID := range deviceIDs {
+		if !visible(deviceID) {
+			return tuya_errors.Forbidden("API key is not allowed to access device %s", deviceID)
+		}
+	}
+	return nil
+}
+
+// isOwnerOrAdmin reports whether the caller of a request may see work started by owner: admins see all work,
+// other callers only their own.
+//
+// param ctx The request context carrying the caller.
+// param owner The credentials the work was started with.
+// return bool True if the caller may see the work.
+func isOwnerOrAdmin(ctx context.Context, owner utils.APIKeyIdentity) bool {
+	identity := utils.APIKeyIdentityFromContext(ctx)
+	if utils.APIKeyScopeAllows(identity.Scope, utils.APIKeyScopeAdmin) {
+		return true
+	}
+	return identity.Actor() != "" && identity.Actor() == owner.Actor()
+}
@@ -0,0 +1,577 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	defaultCircadianInterval         = 5 * time.Minute
+	defaultCircadianOverrideDuration = 2 * time.Hour
+
+	// circadianTolerance is how far (in percent) a light may drift from the last dispatched value
+	// before the change is treated as manual.
+	circadianTolerance = 3
+
+	circadianConfigKey = "circadian:config"
+)
+
+// Dispatch outcomes reported per light.
+const (
+	CircadianActionUpdated          = "updated"
+	CircadianActionUnchanged        = "unchanged"
+	CircadianActionSkippedOff       = "skipped_off"
+	CircadianActionSkippedOverride  = "skipped_override"
+	CircadianActionOverrideDetected = "override_detected"
+	CircadianActionError            = "error"
+)
+
+// defaultCircadianCurve is used until a curve is configured.
+var defaultCircadianCurve = []entities.CircadianPoint{
+	{Time: "06:00", Brightness: 40, Temperature: 20},
+	{Time: "12:00", Brightness: 100, Temperature: 80},
+	{Time: "18:00", Brightness: 80, Temperature: 40},
+	{Time: "22:00", Brightness: 30, Temperature: 5},
+}
+
+// CircadianUseCase gradually shifts the colour temperature and brightness of enrolled lights over the day.
+// A periodic dispatcher sends small steps along the configured curve. Lights that are off are left alone,
+// and a light whose state no longer matches what was last sent is treated as manually overridden and
+// skipped for the override duration.
+type CircadianUseCase struct {
+	cache       persistence.CacheStore
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	categoryUC  *TuyaCategoryControlUseCase
+	authUC      *TuyaAuthUseCase
+	clock       utils.Clock
+
+	interval         time.Duration
+	overrideDuration time.Duration
+
+	// dispatchMu prevents overlapping dispatch runs (ticker and manual runs).
+	dispatchMu sync.Mutex
+	startOnce  sync.Once
+	workers    utils.WorkerGroup
+}
+
+// NewCircadianUseCase initializes a new CircadianUseCase.
+// The dispatch interval and manual override duration are read from CIRCADIAN_INTERVAL and CIRCADIAN_OVERRIDE_DURATION.
+//
+// param cache The CacheStore used to persist the configuration and per-light state.
+// param getDeviceUC The usecase used to read the current state of lights.
+// param controlUC The usecase used to send commands to lights.
+// param categoryUC The usecase providing cached device specifications.
+// param authUC The TuyaAuthUseCase used to obtain a server-side token for background dispatches.
+// param clock The Clock used to place lights on the daily curve and time manual overrides.
+// return *CircadianUseCase A pointer to the initialized usecase.
+func NewCircadianUseCase(cache persistence.CacheStore, getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, categoryUC *TuyaCategoryControlUseCase, authUC *TuyaAuthUseCase, clock utils.Clock) *CircadianUseCase {
+	config := utils.GetConfig()
+
+	interval, err := time.ParseDuration(config.CircadianInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultCircadianInterval
+	}
+	overrideDuration, err := time.ParseDuration(config.CircadianOverrideDuration)
+	if err != nil || overrideDuration <= 0 {
+		overrideDuration = defaultCircadianOverrideDuration
+	}
+
+	return &CircadianUseCase{
+		cache:            cache,
+		getDeviceUC:      getDeviceUC,
+		controlUC:        controlUC,
+		categoryUC:       categoryUC,
+		authUC:           authUC,
+		interval:         interval,
+		overrideDuration: overrideDuration,
+		clock:            clock,
+	}
+}
+
+// Start launches the periodic dispatcher. Dispatches are no-ops while adaptive lighting is disabled.
+func (uc *CircadianUseCase) Start() {
+	uc.startOnce.Do(func() {
+		utils.LogInfo("CircadianUseCase: Dispatcher started (interval %s)", uc.interval)
+		uc.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(uc.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if _, err := uc.Dispatch(context.Background()); err != nil {
+						utils.LogWarn("CircadianUseCase: Dispatch failed: %v", err)
+					}
+				case <-stop:
+					return
+				}
+			}
+		})
+	})
+}
+
+// Stop ends the dispatcher and waits for a dispatch in progress to finish.
+func (uc *CircadianUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// GetStatus returns the configuration with the current target and override state of each light.
+//
+// return *dtos.CircadianStatusDTO The configuration and per-light status.
+// return error An error if the configuration cannot be read.
+func (uc *CircadianUseCase) GetStatus() (*dtos.CircadianStatusDTO, error) {
+	config, err := uc.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	now, err := circadianNow(uc.clock.Now(), config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &dtos.CircadianStatusDTO{
+		Config: toCircadianConfigDTO(config),
+		Lights: make([]dtos.CircadianLightStatusDTO, 0, len(config.Lights)),
+	}
+	for _, light := range config.Lights {
+		target := circadianTarget(lightCurve(config, light), now)
+		state := uc.loadLightState(light.DeviceID)
+		status.Lights = append(status.Lights, dtos.CircadianLightStatusDTO{
+			DeviceID:          light.DeviceID,
+			TargetBrightness:  target.Brightness,
+			TargetTemperature: target.Temperature,
+			LastDispatchAt:    state.LastDispatchAt,
+			OverriddenUntil:   state.OverriddenUntil,
+		})
+	}
+	return status, nil
+}
+
+// SetConfig validates and stores the adaptive lighting configuration.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param req The new configuration.
+// return *dtos.CircadianStatusDTO The stored configuration and per-light status.
+// return error A bad request error for invalid input.
+func (uc *CircadianUseCase) SetConfig(ctx context.Context, accessToken string, req dtos.CircadianConfigDTO) (*dtos.CircadianStatusDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("circadian storage not initialized")
+	}
+	if req.Timezone != "" {
+		if _, err := utils.LoadLocation(req.Timezone); err != nil {
+			return nil, tuya_errors.BadRequest("unknown timezone %q", req.Timezone)
+		}
+	}
+
+	config := &entities.CircadianConfig{
+		Enabled:  req.Enabled,
+		Timezone: req.Timezone,
+		Lights:   make([]entities.CircadianLight, 0, len(req.Lights)),
+	}
+
+	curve, err := toCircadianCurve(req.Curve)
+	if err != nil {
+		return nil, err
+	}
+	config.Curve = curve
+
+	seen := make(map[string]bool)
+	for _, light := range req.Lights {
+		if seen[light.DeviceID] {
+			return nil, tuya_errors.BadRequest("device %s is listed more than once", light.DeviceID)
+		}
+		seen[light.DeviceID] = true
+
+		spec, err := uc.categoryUC.getSpecification(ctx, accessToken, light.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := findFunction(spec, lightTempCodes); !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support colour temperature", light.DeviceID)
+		}
+
+		lightCurve, err := toCircadianCurve(light.Curve)
+		if err != nil {
+			return nil, err
+		}
+		config.Lights = append(config.Lights, entities.CircadianLight{
+			DeviceID:        light.DeviceID,
+			Curve:           lightCurve,
+			TemperatureOnly: light.TemperatureOnly,
+		})
+	}
+
+	jsonData, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal circadian config: %w", err)
+	}
+	if err := uc.cache.SetPersistent(circadianConfigKey, jsonData); err != nil {
+		return nil, fmt.Errorf("failed to save circadian config: %w", err)
+	}
+
+	utils.LogInfo("CircadianUseCase: Saved config (enabled: %t, lights: %d)", config.Enabled, len(config.Lights))
+	return uc.GetStatus()
+}
+
+// ResumeLight clears the manual override of a light so the next dispatch adjusts it again.
+//
+// param deviceID The light's device ID.
+// return error A bad request error if the light is not enrolled.
+func (uc *CircadianUseCase) ResumeLight(deviceID string) error {
+	config, err := uc.loadConfig()
+	if err != nil {
+		return err
+	}
+	if _, ok := findCircadianLight(config, deviceID); !ok {
+		return tuya_errors.BadRequest("device %s is not enrolled in adaptive lighting", deviceID)
+	}
+
+	state := uc.loadLightState(deviceID)
+	state.OverriddenUntil = 0
+	state.Brightness = nil
+	state.Temperature = nil
+	uc.saveLightState(deviceID, state)
+
+	utils.LogInfo("CircadianUseCase: Resumed adaptive lighting for %s", deviceID)
+	return nil
+}
+
+// Dispatch moves every enrolled light one step along its curve.
+// Nothing is sent while adaptive lighting is disabled.
+//
+// param ctx The context of the dispatch run.
+// return []dtos.CircadianDispatchResultDTO The outcome per light.
+// return error An error if the configuration or token cannot be obtained.
+func (uc *CircadianUseCase) Dispatch(ctx context.Context) ([]dtos.CircadianDispatchResultDTO, error) {
+	uc.dispatchMu.Lock()
+	defer uc.dispatchMu.Unlock()
+
+	config, err := uc.loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !config.Enabled || len(config.Lights) == 0 {
+		return []dtos.CircadianDispatchResultDTO{}, nil
+	}
+
+	now, err := circadianNow(uc.clock.Now(), config.Timezone)
+	if err != nil {
+		return nil, err
+	}
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain token: %w", err)
+	}
+
+	results := make([]dtos.CircadianDispatchResultDTO, 0, len(config.Lights))
+	for _, light := range config.Lights {
+		result := uc.dispatchLight(ctx, token.AccessToken, light, circadianTarget(lightCurve(config, light), now), now)
+		if result.Action == CircadianActionError {
+			utils.LogWarn("CircadianUseCase: Failed to adjust %s: %s", light.DeviceID, result.Error)
+		}
+		results = append(results, result)
+	}
+
+	utils.LogDebug("CircadianUseCase: Dispatched %d lights", len(results))
+	return results, nil
+}
+
+// dispatchLight compares a light's current state with what was last sent and moves it to the target.
+func (uc *CircadianUseCase) dispatchLight(ctx context.Context, accessToken string, light entities.CircadianLight, target entities.CircadianPoint, now time.Time) dtos.CircadianDispatchResultDTO {
+	result := dtos.CircadianDispatchResultDTO{DeviceID: light.DeviceID}
+	state := uc.loadLightState(light.DeviceID)
+
+	if state.OverriddenUntil > now.Unix() {
+		result.Action = CircadianActionSkippedOverride
+		return result
+	}
+
+	spec, err := uc.categoryUC.getSpecification(ctx, accessToken, light.DeviceID)
+	if err != nil {
+		result.Action, result.Error = CircadianActionError, err.Error()
+		return result
+	}
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, light.DeviceID)
+	if err != nil {
+		result.Action, result.Error = CircadianActionError, err.Error()
+		return result
+	}
+
+	status := make(map[string]interface{}, len(device.Status))
+	for _, s := range device.Status {
+		status[s.Code] = s.Value
+	}
+
+	// Lights that are off stay off; adaptive lighting never turns a light on.
+	if fn, ok := findFunction(spec, lightPowerCodes); ok {
+		if on, ok := status[fn.Code].(bool); ok && !on {
+			result.Action = CircadianActionSkippedOff
+			return result
+		}
+	}
+
+	brightFn, hasBright := findFunction(spec, lightBrightCodes)
+	tempFn, hasTemp := findFunction(spec, lightTempCodes)
+	current := func(fn entities.TuyaDeviceFunction) (int, bool) {
+		raw, ok := status[fn.Code].(float64)
+		if !ok {
+			return 0, false
+		}
+		values := parseFunctionValues(fn)
+		return toPercent(raw, valueOr(values.Min, 0), valueOr(values.Max, 100)), true
+	}
+
+	// A light switched to colour mode or moved away from the last dispatched values was changed by hand.
+	manual := status["work_mode"] == "colour"
+	if hasTemp && state.Temperature != nil {
+		if value, ok := current(tempFn); ok && absInt(value-*state.Temperature) > circadianTolerance {
+			manual = true
+		}
+	}
+	if hasBright && state.Brightness != nil && !light.TemperatureOnly {
+		if value, ok := current(brightFn); ok && absInt(value-*state.Brightness) > circadianTolerance {
+			manual = true
+		}
+	}
+	if manual {
+		state.OverriddenUntil = now.Add(uc.overrideDuration).Unix()
+		state.Brightness, state.Temperature = nil, nil
+		uc.saveLightState(light.DeviceID, state)
+		utils.LogInfo("CircadianUseCase: Manual change detected on %s, pausing until %s", light.DeviceID, time.Unix(state.OverriddenUntil, 0).Format(time.RFC3339))
+		result.Action = CircadianActionOverrideDetected
+		return result
+	}
+
+	var commands []dtos.TuyaCommandDTO
+	if hasTemp {
+		if value, ok := current(tempFn); !ok || absInt(value-target.Temperature) > 0 {
+			commands = appendWorkMode(commands, spec, "white")
+			commands = append(commands, dtos.TuyaCommandDTO{Code: tempFn.Code, Value: scalePercent(target.Temperature, parseFunctionValues(tempFn))})
+		}
+	}
+	if hasBright && !light.TemperatureOnly {
+		if value, ok := current(brightFn); !ok || absInt(value-target.Brightness) > 0 {
+			commands = append(commands, dtos.TuyaCommandDTO{Code: brightFn.Code, Value: scalePercent(target.Brightness, parseFunctionValues(brightFn))})
+		}
+	}
+
+	temperature := target.Temperature
+	state.Temperature = &temperature
+	if !light.TemperatureOnly {
+		brightness := target.Brightness
+		state.Brightness = &brightness
+	}
+
+	if len(commands) == 0 {
+		uc.saveLightState(light.DeviceID, state)
+		result.Action = CircadianActionUnchanged
+		return result
+	}
+
+	result.Commands = commands
+	if _, err := uc.controlUC.SendCommand(ctx, accessToken, light.DeviceID, commands); err != nil {
+		result.Action, result.Error = CircadianActionError, err.Error()
+		return result
+	}
+
+	state.LastDispatchAt = now.Unix()
+	uc.saveLightState(light.DeviceID, state)
+	result.Action = CircadianActionUpdated
+	return result
+}
+
+// loadConfig reads the stored configuration, falling back to a disabled configuration with the default curve.
+func (uc *CircadianUseCase) loadConfig() (*entities.CircadianConfig, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("circadian storage not initialized")
+	}
+	config := &entities.CircadianConfig{Curve: defaultCircadianCurve, Lights: []entities.CircadianLight{}}
+
+	jsonData, err := uc.cache.Get(circadianConfigKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get circadian config: %w", err)
+	}
+	if jsonData == nil {
+		return config, nil
+	}
+	if err := json.Unmarshal(jsonData, config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal circadian config: %w", err)
+	}
+	if len(config.Curve) == 0 {
+		config.Curve = defaultCircadianCurve
+	}
+	return config, nil
+}
+
+// loadLightState reads the dispatcher state of a light. Missing or unreadable state starts empty.
+func (uc *CircadianUseCase) loadLightState(deviceID string) entities.CircadianLightState {
+	var state entities.CircadianLightState
+	if uc.cache == nil {
+		return state
+	}
+	if jsonData, err := uc.cache.Get(circadianStateKey(deviceID)); err == nil && jsonData != nil {
+		_ = json.Unmarshal(jsonData, &state)
+	}
+	return state
+}
+
+// saveLightState persists the dispatcher state of a light. Failures are logged because a lost state only
+// costs one missed override detection.
+func (uc *CircadianUseCase) saveLightState(deviceID string, state entities.CircadianLightState) {
+	jsonData, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if err := uc.cache.SetPersistent(circadianStateKey(deviceID), jsonData); err != nil {
+		utils.LogWarn("CircadianUseCase: Failed to save state for %s: %v", deviceID, err)
+	}
+}
+
+// circadianStateKey builds the storage key of a light's dispatcher state.
+func circadianStateKey(deviceID string) string {
+	return fmt.Sprintf("circadian:state:%s", deviceID)
+}
+
+// circadianNow converts a time to the configured timezone (the deployment TIMEZONE when empty).
+func circadianNow(now time.Time, timezone string) (time.Time, error) {
+	location, err := utils.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid circadian timezone %q: %w", timezone, err)
+	}
+	return now.In(location), nil
+}
+
+// lightCurve returns the light's own curve, or the global curve when it has none.
+func lightCurve(config *entities.CircadianConfig, light entities.CircadianLight) []entities.CircadianPoint {
+	if len(light.Curve) > 0 {
+		return light.Curve
+	}
+	return config.Curve
+}
+
+// findCircadianLight looks up an enrolled light.
+func findCircadianLight(config *entities.CircadianConfig, deviceID string) (entities.CircadianLight, bool) {
+	for _, light := range config.Lights {
+		if light.DeviceID == deviceID {
+			return light, true
+		}
+	}
+	return entities.CircadianLight{}, false
+}
+
+// circadianTarget interpolates the curve at the given time, wrapping around midnight.
+// The curve must be sorted by time (see toCircadianCurve).
+func circadianTarget(curve []entities.CircadianPoint, now time.Time) entities.CircadianPoint {
+	if len(curve) == 1 {
+		return curve[0]
+	}
+	minute := now.Hour()*60 + now.Minute()
+
+	for i := range curve {
+		from := curve[i]
+		to := curve[(i+1)%len(curve)]
+		start := curveMinute(from.Time)
+		end := curveMinute(to.Time)
+		if end <= start {
+			end += 24 * 60
+		}
+
+		at := minute
+		if at < start {
+			at += 24 * 60
+		}
+		if at < start || at >= end {
+			continue
+		}
+
+		ratio := float64(at-start) / float64(end-start)
+		return entities.CircadianPoint{
+			Time:        fmt.Sprintf("%02d:%02d", now.Hour(), now.Minute()),
+			Brightness:  int(math.Round(float64(from.Brightness) + ratio*float64(to.Brightness-from.Brightness))),
+			Temperature: int(math.Round(float64(from.Temperature) + ratio*float64(to.Temperature-from.Temperature))),
+		}
+	}
+	return curve[0]
+}
+
+// curveMinute converts "HH:MM" into minutes since midnight. Times are validated when the curve is stored.
+func curveMinute(value string) int {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0
+	}
+	return t.Hour()*60 + t.Minute()
+}
+
+// toCircadianCurve validates curve points and sorts them by time.
+func toCircadianCurve(points []dtos.CircadianPointDTO) ([]entities.CircadianPoint, error) {
+	if len(points) == 0 {
+		return nil, nil
+	}
+
+	seen := make(map[string]bool)
+	curve := make([]entities.CircadianPoint, 0, len(points))
+	for _, point := range points {
+		if _, err := time.Parse("15:04", point.Time); err != nil {
+			return nil, tuya_errors.BadRequest("curve time %q must be HH:MM", point.Time)
+		}
+		if seen[point.Time] {
+			return nil, tuya_errors.BadRequest("curve time %s is listed more than once", point.Time)
+		}
+		seen[point.Time] = true
+		curve = append(curve, entities.CircadianPoint{Time: point.Time, Brightness: point.Brightness, Temperature: point.Temperature})
+	}
+
+	sort.Slice(curve, func(i, j int) bool {
+		return curveMinute(curve[i].Time) < curveMinute(curve[j].Time)
+	})
+	return curve, nil
+}
+
+// toCircadianConfigDTO converts the stored configuration into its response form.
+func toCircadianConfigDTO(config *entities.CircadianConfig) dtos.CircadianConfigDTO {
+	toPoints := func(curve []entities.CircadianPoint) []dtos.CircadianPointDTO {
+		if len(curve) == 0 {
+			return nil
+		}
+		points := make([]dtos.CircadianPointDTO, len(curve))
+		for i, p := range curve {
+			points[i] = dtos.CircadianPointDTO{Time: p.Time, Brightness: p.Brightness, Temperature: p.Temperature}
+		}
+		return points
+	}
+
+	lights := make([]dtos.CircadianLightDTO, len(config.Lights))
+	for i, light := range config.Lights {
+		lights[i] = dtos.CircadianLightDTO{
+			DeviceID:        light.DeviceID,
+			Curve:           toPoints(light.Curve),
+			TemperatureOnly: light.TemperatureOnly,
+		}
+	}
+	return dtos.CircadianConfigDTO{
+		Enabled:  config.Enabled,
+		Timezone: config.Timezone,
+		Curve:    toPoints(config.Curve),
+		Lights:   lights,
+	}
+}
+
+// absInt returns the absolute value of v.
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
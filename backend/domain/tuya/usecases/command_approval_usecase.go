@@ -0,0 +1,602 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+var (
+	// ErrPendingActionNotFound is returned when a pending action does not exist (or its retention ended).
+	ErrPendingActionNotFound = tuya_errors.NotFound("pending action not found")
+	// ErrPendingActionDecided is returned when approving or rejecting an action that is no longer pending.
+	ErrPendingActionDecided = tuya_errors.Conflict("pending action was already decided")
+	// ErrPendingActionExpired is returned when approving or rejecting an action after its approval window.
+	ErrPendingActionExpired = tuya_errors.Conflict("pending action expired")
+	// ErrSelfApproval is returned when the requester of an action tries to approve it.
+	ErrSelfApproval = tuya_errors.Forbidden("a pending action must be approved by another user")
+	// ErrRejectNotAllowed is returned when a caller who neither requested an action nor may approve it tries to reject it.
+	ErrRejectNotAllowed = tuya_errors.Forbidden("a pending action can only be rejected by its requester or an approver")
+	// ErrApprovalRuleNotFound is returned when a device has no approval rule.
+	ErrApprovalRuleNotFound = tuya_errors.NotFound("approval rule not found")
+)
+
+const (
+	// commandApprovalRulePrefix stores approval rules: "command_approval_rule:{device_id}".
+	commandApprovalRulePrefix = "command_approval_rule:"
+	// pendingActionPrefix stores pending actions: "pending_action:{id}".
+	pendingActionPrefix = "pending_action:"
+	// defaultCommandApprovalTTL is the approval window used when neither the rule nor COMMAND_APPROVAL_TTL sets one.
+	defaultCommandApprovalTTL = 10 * time.Minute
+	// pendingActionRetention is how long decided and expired actions stay listed.
+	pendingActionRetention = 7 * 24 * time.Hour
+	// systemActor identifies commands sent by background work (automations, schedules).
+	systemActor = "system"
+)
+
+// approvedCommandKey marks a context whose commands were approved and must not be held again.
+type approvedCommandKey struct{}
+
+// ApprovalRequiredError is returned by SendCommand when a protected command was held for approval.
+type ApprovalRequiredError struct {
+	Action *dtos.PendingActionDTO
+}
+
+// Error implements the error interface.
+func (e *ApprovalRequiredError) Error() string {
+	return fmt.Sprintf("approval required: command held as pending action %s until another user approves it", e.Action.ID)
+}
+
+// CommandApprovalUseCase implements two-person approval for high-impact commands (e.g., a main power contactor).
+// Commands matching an approval rule are not sent; they are held as a pending action which another user
+// must approve before it expires. The approved command is then sent with the approver's token and audited.
+// Only standard commands are held, so rules cannot be set on IR remotes and hubs.
+type CommandApprovalUseCase struct {
+	cache       persistence.CacheStore
+	controlUC   *TuyaDeviceControlUseCase
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	authUC      *TuyaAuthUseCase
+	auditLogUC  *AuditLogUseCase
+	authz       CallerAuthorizer
+	defaultTTL  time.Duration
+	clock       utils.Clock
+	ids         utils.IDGenerator
+
+	mu sync.Mutex
+	// approving holds the IDs of actions whose commands are being sent, so they are decided only once
+	// while uc.mu is released for the Tuya call.
+	approving map[string]bool
+}
+
+// NewCommandApprovalUseCase initializes a new CommandApprovalUseCase.
+// The default approval window is read from COMMAND_APPROVAL_TTL.
+//
+// param cache The CacheStore used to persist rules and pending actions.
+// param controlUC The usecase used to send approved commands.
+// param getDeviceUC The usecase used to look up the category of devices given a rule.
+// param authUC The TuyaAuthUseCase providing the server-managed token for device lookups.
+// param auditLogUC The AuditLogUseCase recording requests and decisions (optional).
+// param authz The CallerAuthorizer checking the API key of approvers.
+// param clock The Clock used for approval windows.
+// param ids The IDGenerator used for pending action IDs.
+// return *CommandApprovalUseCase A pointer to the initialized usecase.
+func NewCommandApprovalUseCase(cache persistence.CacheStore, controlUC *TuyaDeviceControlUseCase, getDeviceUC *TuyaGetDeviceByIDUseCase, authUC *TuyaAuthUseCase, auditLogUC *AuditLogUseCase, authz CallerAuthorizer, clock utils.Clock, ids utils.IDGenerator) *CommandApprovalUseCase {
+	ttl, err := time.ParseDuration(utils.GetConfig().CommandApprovalTTL)
+	if err != nil || ttl <= 0 {
+		ttl = defaultCommandApprovalTTL
+	}
+	return &CommandApprovalUseCase{
+		cache:       cache,
+		controlUC:   controlUC,
+		getDeviceUC: getDeviceUC,
+		authUC:      authUC,
+		auditLogUC:  auditLogUC,
+		authz:       authz,
+		defaultTTL:  ttl,
+		clock:       clock,
+		ids:         ids,
+		approving:   make(map[string]bool),
+	}
+}
+
+// ListRules returns the approval rules, ordered by device ID.
+//
+// return []dtos.CommandApprovalRuleDTO The rules.
+// return error An error if the rules cannot be read.
+func (uc *CommandApprovalUseCase) ListRules() ([]dtos.CommandApprovalRuleDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("approval storage not initialized")
+	}
+	keys, err := uc.cache.GetAllKeysWithPrefix(commandApprovalRulePrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list approval rules: %w", err)
+	}
+	rules := make([]dtos.CommandApprovalRuleDTO, 0, len(keys))
+	for _, key := range keys {
+		rule, err := uc.rule(strings.TrimPrefix(key, commandApprovalRulePrefix))
+		if err != nil || rule == nil {
+			continue
+		}
+		rules = append(rules, commandApprovalRuleToDTO(rule))
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].DeviceID < rules[j].DeviceID
+	})
+	return rules, nil
+}
+
+// SetRule requires approval for the listed DP codes of a device, or every command when no codes are given.
+// IR remotes and hubs are rejected: IR commands are sent through the IR API and would bypass the rule.
+//
+// param ctx The request context.
+// param deviceID The device ID.
+// param req The protected codes and the approval window.
+// return *dtos.CommandApprovalRuleDTO The stored rule.
+// return error A bad request error for invalid input or IR devices, an error if the device cannot be looked up,
+// or a storage error.
+func (uc *CommandApprovalUseCase) SetRule(ctx context.Context, deviceID string, req dtos.SetCommandApprovalRuleRequestDTO) (*dtos.CommandApprovalRuleDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("approval storage not initialized")
+	}
+	deviceID = strings.TrimSpace(deviceID)
+	if deviceID == "" {
+		return nil, tuya_errors.BadRequest("device_id must not be empty")
+	}
+
+	seen := make(map[string]bool, len(req.Codes))
+	codes := make([]string, 0, len(req.Codes))
+	for _, code := range req.Codes {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			return nil, tuya_errors.BadRequest("codes must not contain empty values")
+		}
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	accessToken, err := uc.authUC.ServerAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasPrefix(device.Category, "infrared_") || device.Category == irHubCategory {
+		return nil, tuya_errors.BadRequest("device %s is an IR device; IR commands cannot be held for approval", deviceID)
+	}
+
+	rule := &entities.CommandApprovalRule{
+		DeviceID:   deviceID,
+		Codes:      codes,
+		TTLSeconds: req.TTLSeconds,
+		CreatedAt:  uc.clock.Now().Unix(),
+	}
+	data, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal approval rule: %w", err)
+	}
+	if err := uc.cache.SetPersistent(commandApprovalRulePrefix+deviceID, data); err != nil {
+		return nil, fmt.Errorf("failed to save approval rule: %w", err)
+	}
+	utils.LogInfo("CommandApprovalUseCase: Commands %v of device %s now require approval", codes, deviceID)
+	dto := commandApprovalRuleToDTO(rule)
+	return &dto, nil
+}
+
+// DeleteRule removes the approval rule of a device. Pending actions already held stay decidable.
+//
+// param deviceID The device ID.
+// return error ErrApprovalRuleNotFound, or a storage error.
+func (uc *CommandApprovalUseCase) DeleteRule(deviceID string) error {
+	if uc.cache == nil {
+		return fmt.Errorf("approval storage not initialized")
+	}
+	rule, err := uc.rule(deviceID)
+	if err != nil {
+		return err
+	}
+	if rule == nil {
+		return ErrApprovalRuleNotFound
+	}
+	if err := uc.cache.Delete(commandApprovalRulePrefix + deviceID); err != nil {
+		return fmt.Errorf("failed to delete approval rule: %w", err)
+	}
+	utils.LogInfo("CommandApprovalUseCase: Approval rule of device %s removed", deviceID)
+	return nil
+}
+
+// Hold checks commands against the approval rule of the device and, when any of them is protected,
+// stores the whole set as a pending action instead of sending it. Requesting the same commands again
+// while they are pending returns the existing action.
+//
+// param ctx The request context carrying the requester (see utils.ActorFromContext).
+// param deviceID The device ID.
+// param commands The commands to be sent.
+// return *dtos.PendingActionDTO The pending action, or nil if the commands do not need approval.
+// return error An error if the rule or the action cannot be stored.
+func (uc *CommandApprovalUseCase) Hold(ctx context.Context, deviceID string, commands []dtos.TuyaCommandDTO) (*dtos.PendingActionDTO, error) {
+	if uc.cache == nil {
+		return nil, nil
+	}
+	if approved, _ := ctx.Value(approvedCommandKey{}).(bool); approved {
+		return nil, nil
+	}
+	rule, err := uc.rule(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil || !ruleProtects(rule, commands) {
+		return nil, nil
+	}
+
+	requester := utils.ActorFromContext(ctx)
+	if requester == "" {
+		requester = systemActor
+	}
+	entityCommands := make([]entities.TuyaCommand, len(commands))
+	for i, cmd := range commands {
+		entityCommands[i] = entities.TuyaCommand{Code: cmd.Code, Value: cmd.Value}
+	}
+	fingerprint, _ := json.Marshal(entityCommands)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	now := uc.clock.Now()
+	actions, err := uc.loadActions()
+	if err != nil {
+		return nil, err
+	}
+	for _, action := range actions {
+		if action.DeviceID != deviceID || action.RequestedBy != requester || action.Status != entities.PendingActionPending || now.Unix() >= action.ExpiresAt {
+			continue
+		}
+		if existing, _ := json.Marshal(action.Commands); string(existing) == string(fingerprint) {
+			dto := uc.pendingActionToDTO(action)
+			return &dto, nil
+		}
+	}
+
+	ttl := uc.defaultTTL
+	if rule.TTLSeconds > 0 {
+		ttl = time.Duration(rule.TTLSeconds) * time.Second
+	}
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate pending action ID: %w", err)
+	}
+	action := &entities.PendingAction{
+		ID:          id,
+		DeviceID:    deviceID,
+		Commands:    entityCommands,
+		RequestedBy: requester,
+		RequestedAt: now.Unix(),
+		ExpiresAt:   now.Add(ttl).Unix(),
+		Status:      entities.PendingActionPending,
+	}
+	if err := uc.saveAction(action); err != nil {
+		return nil, err
+	}
+	uc.audit("requested", action, nil)
+	utils.LogInfo("CommandApprovalUseCase: Command for device %s held as pending action %s (requested by %s)", deviceID, id, requester)
+	dto := uc.pendingActionToDTO(action)
+	return &dto, nil
+}
+
+// ListPendingActions returns pending actions, newest first.
+//
+// param status Only actions with this status (pending, executed, failed, rejected or expired), or empty for all.
+// return []dtos.PendingActionDTO The actions.
+// return error An error if the actions cannot be read.
+func (uc *CommandApprovalUseCase) ListPendingActions(status string) ([]dtos.PendingActionDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("approval storage not initialized")
+	}
+	actions, err := uc.loadActions()
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dtos.PendingActionDTO, 0, len(actions))
+	for _, action := range actions {
+		dto := uc.pendingActionToDTO(action)
+		if status != "" && dto.Status != status {
+			continue
+		}
+		result = append(result, dto)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].RequestedAt > result[j].RequestedAt
+	})
+	return result, nil
+}
+
+// GetPendingAction returns a single pending action.
+//
+// param id The pending action ID.
+// return *dtos.PendingActionDTO The action.
+// return error ErrPendingActionNotFound, or a storage error.
+func (uc *CommandApprovalUseCase) GetPendingAction(id string) (*dtos.PendingActionDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("approval storage not initialized")
+	}
+	action, err := uc.action(id)
+	if err != nil {
+		return nil, err
+	}
+	dto := uc.pendingActionToDTO(action)
+	return &dto, nil
+}
+
+// Approve sends the commands of a pending action with the approver's token and records the outcome.
+// The approver needs verified credentials (an API key or identity token) of control scope that may access
+// the device, and must be someone other than the requester; callers are compared by API key ID or identity
+// subject, so a second session of the requester does not count. Actions held by background work (requested
+// by "system") may be approved by any such approver.
+//
+// param ctx The request context carrying the approver (see utils.APIKeyIdentityFromContext).
+// param accessToken The approver's access token, used to send the commands.
+// param id The pending action ID.
+// return *dtos.PendingActionDTO The decided action, with status "executed" or "failed".
+// return error ErrPendingActionNotFound, ErrPendingActionDecided, ErrPendingActionExpired, ErrSelfApproval,
+// a forbidden error if the approver is unverified or may not control the device, or a storage error.
+func (uc *CommandApprovalUseCase) Approve(ctx context.Context, accessToken, id string) (*dtos.PendingActionDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("approval storage not initialized")
+	}
+
+	action, approver, err := uc.claimApproval(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// The commands are sent without holding uc.mu, so a slow Tuya call does not block other decisions
+	commands := make([]dtos.TuyaCommandDTO, len(action.Commands))
+	for i, cmd := range action.Commands {
+		commands[i] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+	}
+	_, sendErr := uc.controlUC.SendCommand(context.WithValue(ctx, approvedCommandKey{}, true), accessToken, action.DeviceID, commands)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	delete(uc.approving, id)
+
+	action.Status = entities.PendingActionExecuted
+	if sendErr != nil {
+		action.Status = entities.PendingActionFailed
+		action.Error = sendErr.Error()
+	}
+	action.DecidedBy = approver
+	action.DecidedAt = uc.clock.Now().Unix()
+	if err := uc.saveAction(action); err != nil {
+		return nil, err
+	}
+	uc.audit("approved", action, sendErr)
+	utils.LogInfo("CommandApprovalUseCase: Pending action %s approved by %s (%s)", id, approver, action.Status)
+	dto := uc.pendingActionToDTO(action)
+	return &dto, nil
+}
+
+// claimApproval checks that the caller may approve a pending action and marks it as being approved.
+func (uc *CommandApprovalUseCase) claimApproval(ctx context.Context, id string) (*entities.PendingAction, string, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	action, err := uc.pendingAction(id)
+	if err != nil {
+		return nil, "", err
+	}
+	identity, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, action.DeviceID)
+	if err != nil {
+		return nil, "", err
+	}
+	approver := identity.Actor()
+	if approver == action.RequestedBy {
+		return nil, "", ErrSelfApproval
+	}
+	uc.approving[id] = true
+	return action, approver, nil
+}
+
+// Reject discards a pending action. The requester may reject their own action to withdraw it; anyone else
+// needs the same credentials as an approver.
+//
+// param ctx The request context carrying the caller (see utils.ActorFromContext).
+// param id The pending action ID.
+// param reason An optional explanation.
+// return *dtos.PendingActionDTO The rejected action.
+// return error ErrPendingActionNotFound, ErrPendingActionDecided, ErrPendingActionExpired, ErrRejectNotAllowed,
+// or a storage error.
+func (uc *CommandApprovalUseCase) Reject(ctx context.Context, id, reason string) (*dtos.PendingActionDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("approval storage not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	action, err := uc.pendingAction(id)
+	if err != nil {
+		return nil, err
+	}
+	caller := utils.ActorFromContext(ctx)
+	if caller == "" || caller != action.RequestedBy {
+		if _, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, action.DeviceID); err != nil {
+			return nil, ErrRejectNotAllowed
+		}
+	}
+	action.Status = entities.PendingActionRejected
+	action.DecidedBy = caller
+	action.DecidedAt = uc.clock.Now().Unix()
+	action.Reason = strings.TrimSpace(reason)
+	if err := uc.saveAction(action); err != nil {
+		return nil, err
+	}
+	uc.audit("rejected", action, nil)
+	dto := uc.pendingActionToDTO(action)
+	return &dto, nil
+}
+
+// ruleProtects reports whether any of the commands is covered by the rule.
+func ruleProtects(rule *entities.CommandApprovalRule, commands []dtos.TuyaCommandDTO) bool {
+	if len(rule.Codes) == 0 {
+		return len(commands) > 0
+	}
+	for _, cmd := range commands {
+		if containsString(rule.Codes, cmd.Code) {
+			return true
+		}
+	}
+	return false
+}
+
+// rule loads the approval rule of a device, or nil if it has none.
+func (uc *CommandApprovalUseCase) rule(deviceID string) (*entities.CommandApprovalRule, error) {
+	data, err := uc.cache.Get(commandApprovalRulePrefix + deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read approval rule: %w", err)
+	}
+	if data == nil {
+		return nil, nil
+	}
+	var rule entities.CommandApprovalRule
+	if err := json.Unmarshal(data, &rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal approval rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// action loads a pending action in any status.
+func (uc *CommandApprovalUseCase) action(id string) (*entities.PendingAction, error) {
+	data, err := uc.cache.Get(pendingActionPrefix + id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending action: %w", err)
+	}
+	if data == nil {
+		return nil, ErrPendingActionNotFound
+	}
+	var action entities.PendingAction
+	if err := json.Unmarshal(data, &action); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal pending action: %w", err)
+	}
+	return &action, nil
+}
+
+// pendingAction loads an action that can still be decided. The caller must hold uc.mu.
+func (uc *CommandApprovalUseCase) pendingAction(id string) (*entities.PendingAction, error) {
+	action, err := uc.action(id)
+	if err != nil {
+		return nil, err
+	}
+	if action.Status != entities.PendingActionPending || uc.approving[id] {
+		return nil, ErrPendingActionDecided
+	}
+	if uc.clock.Now().Unix() >= action.ExpiresAt {
+		return nil, ErrPendingActionExpired
+	}
+	return action, nil
+}
+
+// loadActions reads all stored pending actions.
+func (uc *CommandApprovalUseCase) loadActions() ([]*entities.PendingAction, error) {
+	keys, err := uc.cache.GetAllKeysWithPrefix(pendingActionPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pending actions: %w", err)
+	}
+	actions := make([]*entities.PendingAction, 0, len(keys))
+	for _, key := range keys {
+		data, err := uc.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var action entities.PendingAction
+		if err := json.Unmarshal(data, &action); err != nil {
+			utils.LogWarn("CommandApprovalUseCase: Skipping unreadable pending action %s: %v", key, err)
+			continue
+		}
+		actions = append(actions, &action)
+	}
+	return actions, nil
+}
+
+// saveAction persists a pending action until its retention ends.
+func (uc *CommandApprovalUseCase) saveAction(action *entities.PendingAction) error {
+	data, err := json.Marshal(action)
+	if err != nil {
+		return fmt.Errorf("failed to marshal pending action: %w", err)
+	}
+	ttl := time.Unix(action.ExpiresAt, 0).Add(pendingActionRetention).Sub(uc.clock.Now())
+	if err := uc.cache.Set(pendingActionPrefix+action.ID, data, ttl); err != nil {
+		return fmt.Errorf("failed to save pending action: %w", err)
+	}
+	return nil
+}
+
+// audit records an approval step in the audit log.
+func (uc *CommandApprovalUseCase) audit(step string, action *entities.PendingAction, err error) {
+	if uc.auditLogUC == nil {
+		return
+	}
+	detail := map[string]interface{}{
+		"step":         step,
+		"action_id":    action.ID,
+		"commands":     action.Commands,
+		"requested_by": action.RequestedBy,
+	}
+	if action.DecidedBy != "" {
+		detail["decided_by"] = action.DecidedBy
+	}
+	if action.Reason != "" {
+		detail["reason"] = action.Reason
+	}
+	if auditErr := uc.auditLogUC.Record(AuditActionCommandApproval, action.DeviceID, detail, err); auditErr != nil {
+		utils.LogWarn("Failed to record audit entry for %s: %v", action.DeviceID, auditErr)
+	}
+}
+
+// pendingActionToDTO converts a pending action into its API representation, reporting lapsed actions as expired.
+func (uc *CommandApprovalUseCase) pendingActionToDTO(action *entities.PendingAction) dtos.PendingActionDTO {
+	status := action.Status
+	if status == entities.PendingActionPending && uc.clock.Now().Unix() >= action.ExpiresAt {
+		status = entities.PendingActionExpired
+	}
+	commands := make([]dtos.TuyaCommandDTO, len(action.Commands))
+	for i, cmd := range action.Commands {
+		commands[i] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+	}
+	return dtos.PendingActionDTO{
+		ID:          action.ID,
+		DeviceID:    action.DeviceID,
+		Commands:    commands,
+		RequestedBy: action.RequestedBy,
+		RequestedAt: action.RequestedAt,
+		ExpiresAt:   action.ExpiresAt,
+		Status:      status,
+		DecidedBy:   action.DecidedBy,
+		DecidedAt:   action.DecidedAt,
+		Reason:      action.Reason,
+		Error:       action.Error,
+	}
+}
+
+// commandApprovalRuleToDTO converts an approval rule into its API representation.
+func commandApprovalRuleToDTO(rule *entities.CommandApprovalRule) dtos.CommandApprovalRuleDTO {
+	return dtos.CommandApprovalRuleDTO{
+		DeviceID:   rule.DeviceID,
+		Codes:      rule.Codes,
+		TTLSeconds: rule.TTLSeconds,
+		CreatedAt:  rule.CreatedAt,
+	}
+}
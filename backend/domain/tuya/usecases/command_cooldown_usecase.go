@@ -0,0 +1,308 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// ErrCommandCooldownNotFound is returned when no cooldown was learned for a device.
+var ErrCommandCooldownNotFound = tuya_errors.NotFound("no cooldown learned for device")
+
+const (
+	// commandCooldownPrefix stores learned cooldowns: "command_cooldown:{device_id}".
+	commandCooldownPrefix = "command_cooldown:"
+	// rapidCommandWindow is the gap below which a failed command counts as rejected for following another too quickly.
+	rapidCommandWindow = 2 * time.Second
+	// cooldownLearnFailures is the number of consecutive rapid failures after which the cooldown grows.
+	cooldownLearnFailures = 2
+	// minCommandCooldown is the smallest cooldown applied; decayed cooldowns below it are dropped.
+	minCommandCooldown = 250 * time.Millisecond
+	// defaultMaxCommandCooldown is the largest cooldown learned when COMMAND_COOLDOWN_MAX is not set.
+	defaultMaxCommandCooldown = 5 * time.Second
+	// cooldownDecaySuccesses is the number of consecutive successes after which the cooldown shrinks by a quarter.
+	cooldownDecaySuccesses = 20
+)
+
+// commandCooldownState is the runtime state of one device next to its learned cooldown.
+type commandCooldownState struct {
+	learned       entities.CommandCooldown
+	lastCommand   time.Time
+	rapidStreak   int
+	successStreak int
+}
+
+// CommandCooldownUseCase learns how far apart commands to a device must be. Some devices, notably IR hubs,
+// drop or reject a command that follows the previous one within a second. When a device keeps failing
+// commands sent shortly after another one, a cooldown is learned (and doubled while failures continue);
+// later commands are delayed until the cooldown passed. Long runs of successes shrink it again.
+// Learned cooldowns persist, so they survive restarts.
+type CommandCooldownUseCase struct {
+	cache       persistence.CacheStore
+	maxCooldown time.Duration
+	clock       utils.Clock
+
+	mu     sync.Mutex
+	states map[string]*commandCooldownState
+}
+
+// NewCommandCooldownUseCase initializes a new CommandCooldownUseCase.
+// The largest learned cooldown is read from COMMAND_COOLDOWN_MAX.
+//
+// param cache The CacheStore used to persist learned cooldowns (optional).
+// param clock The Clock used to measure gaps between commands.
+// return *CommandCooldownUseCase A pointer to the initialized usecase.
+func NewCommandCooldownUseCase(cache persistence.CacheStore, clock utils.Clock) *CommandCooldownUseCase {
+	maxCooldown, err := time.ParseDuration(utils.GetConfig().CommandCooldownMax)
+	if err != nil || maxCooldown < minCommandCooldown {
+		maxCooldown = defaultMaxCommandCooldown
+	}
+	return &CommandCooldownUseCase{
+		cache:       cache,
+		maxCooldown: maxCooldown,
+		clock:       clock,
+		states:      make(map[string]*commandCooldownState),
+	}
+}
+
+// Throttle sends a command once the cooldown of the device passed and learns from its outcome.
+// A nil CommandCooldownUseCase sends immediately.
+//
+// param ctx The request context; waiting stops when it is cancelled.
+// param deviceID The device (or IR hub) receiving the command.
+// param send The function sending the command.
+// return error The error of send, or the context error if it was cancelled while waiting.
+func (uc *CommandCooldownUseCase) Throttle(ctx context.Context, deviceID string, send func() error) error {
+	if uc == nil {
+		return send()
+	}
+	gap, err := uc.Wait(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	err = send()
+	uc.Record(deviceID, gap, err)
+	return err
+}
+
+// Wait delays the caller until the cooldown of the device passed since its previous command,
+// and reserves the slot so concurrent commands to the same device are spaced out too.
+//
+// param ctx The request context; waiting stops when it is cancelled.
+// param deviceID The device (or IR hub) receiving the command.
+// return time.Duration The gap to the previous command, or -1 if there was none.
+// return error The context error if it was cancelled while waiting.
+func (uc *CommandCooldownUseCase) Wait(ctx context.Context, deviceID string) (time.Duration, error) {
+	uc.mu.Lock()
+	state := uc.state(deviceID)
+	now := uc.clock.Now()
+	slot := now
+	gap := time.Duration(-1)
+	if !state.lastCommand.IsZero() {
+		if earliest := state.lastCommand.Add(time.Duration(state.learned.CooldownMs) * time.Millisecond); earliest.After(now) {
+			slot = earliest
+		}
+		gap = slot.Sub(state.lastCommand)
+	}
+	state.lastCommand = slot
+	uc.mu.Unlock()
+
+	wait := slot.Sub(now)
+	if wait <= 0 {
+		return gap, nil
+	}
+	utils.LogDebug("CommandCooldownUseCase: Delaying command to %s by %s", deviceID, wait)
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return gap, nil
+	case <-ctx.Done():
+		return gap, ctx.Err()
+	}
+}
+
+// Record learns from the outcome of a command. Failures of commands sent within the rapid window of the
+// previous one grow the cooldown; validation errors and cancelled requests are ignored.
+//
+// param deviceID The device (or IR hub) that received the command.
+// param gap The gap returned by Wait.
+// param err The error of the command, or nil if it succeeded.
+func (uc *CommandCooldownUseCase) Record(deviceID string, gap time.Duration, err error) {
+	if err != nil && (errors.Is(err, context.Canceled) || tuya_errors.HasCode(err, tuya_errors.CodeBadRequest)) {
+		return
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	state := uc.state(deviceID)
+	cooldown := time.Duration(state.learned.CooldownMs) * time.Millisecond
+	if err == nil {
+		state.rapidStreak = 0
+		state.successStreak++
+		if cooldown > 0 && state.successStreak >= cooldownDecaySuccesses {
+			state.successStreak = 0
+			cooldown = cooldown * 3 / 4
+			if cooldown < minCommandCooldown {
+				cooldown = 0
+			}
+			state.learned.CooldownMs = cooldown.Milliseconds()
+			utils.LogInfo("CommandCooldownUseCase: Cooldown of %s relaxed to %s", deviceID, cooldown)
+			uc.save(state)
+		}
+		return
+	}
+
+	state.successStreak = 0
+	window := rapidCommandWindow
+	if 2*cooldown > window {
+		window = 2 * cooldown
+	}
+	if gap < 0 || gap >= window {
+		state.rapidStreak = 0
+		return
+	}
+
+	now := uc.clock.Now()
+	state.rapidStreak++
+	state.learned.RapidFailures++
+	state.learned.LastFailureAt = now.Unix()
+	state.learned.LastFailure = err.Error()
+	state.learned.LastFailureGap = gap.Milliseconds()
+	if state.rapidStreak >= cooldownLearnFailures {
+		state.rapidStreak = 0
+		learned := 2 * cooldown
+		if floor := (gap + minCommandCooldown).Round(time.Millisecond); learned < floor {
+			learned = floor
+		}
+		if learned > uc.maxCooldown {
+			learned = uc.maxCooldown
+		}
+		if learned > cooldown {
+			state.learned.CooldownMs = learned.Milliseconds()
+			utils.LogInfo("CommandCooldownUseCase: %s rejects rapid commands, cooldown raised to %s", deviceID, learned)
+		}
+	}
+	uc.save(state)
+}
+
+// ListCooldowns returns the devices with a learned cooldown or recorded rapid failures, longest cooldown first.
+//
+// return []dtos.CommandCooldownDTO The cooldowns.
+// return error An error if the persisted cooldowns cannot be listed.
+func (uc *CommandCooldownUseCase) ListCooldowns() ([]dtos.CommandCooldownDTO, error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.cache != nil {
+		keys, err := uc.cache.GetAllKeysWithPrefix(commandCooldownPrefix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list cooldowns: %w", err)
+		}
+		for _, key := range keys {
+			uc.state(strings.TrimPrefix(key, commandCooldownPrefix))
+		}
+	}
+
+	result := make([]dtos.CommandCooldownDTO, 0, len(uc.states))
+	for _, state := range uc.states {
+		if state.learned.CooldownMs == 0 && state.learned.RapidFailures == 0 {
+			continue
+		}
+		result = append(result, commandCooldownToDTO(state))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].CooldownMs != result[j].CooldownMs {
+			return result[i].CooldownMs > result[j].CooldownMs
+		}
+		return result[i].DeviceID < result[j].DeviceID
+	})
+	return result, nil
+}
+
+// ResetCooldown forgets what was learned for a device, e.g. after its firmware was updated.
+//
+// param deviceID The device ID.
+// return error ErrCommandCooldownNotFound, or a storage error.
+func (uc *CommandCooldownUseCase) ResetCooldown(deviceID string) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	state := uc.state(deviceID)
+	if state.learned.CooldownMs == 0 && state.learned.RapidFailures == 0 {
+		return ErrCommandCooldownNotFound
+	}
+	state.learned = entities.CommandCooldown{DeviceID: deviceID}
+	state.rapidStreak = 0
+	state.successStreak = 0
+	if uc.cache != nil {
+		if err := uc.cache.Delete(commandCooldownPrefix + deviceID); err != nil {
+			return fmt.Errorf("failed to delete cooldown: %w", err)
+		}
+	}
+	utils.LogInfo("CommandCooldownUseCase: Cooldown of %s reset", deviceID)
+	return nil
+}
+
+// state returns the state of a device, loading its persisted cooldown on first use. The caller holds uc.mu.
+func (uc *CommandCooldownUseCase) state(deviceID string) *commandCooldownState {
+	if state, ok := uc.states[deviceID]; ok {
+		return state
+	}
+	state := &commandCooldownState{learned: entities.CommandCooldown{DeviceID: deviceID}}
+	if uc.cache != nil {
+		if data, err := uc.cache.Get(commandCooldownPrefix + deviceID); err == nil && data != nil {
+			if err := json.Unmarshal(data, &state.learned); err != nil {
+				utils.LogWarn("CommandCooldownUseCase: Ignoring unreadable cooldown of %s: %v", deviceID, err)
+				state.learned = entities.CommandCooldown{DeviceID: deviceID}
+			}
+		}
+	}
+	uc.states[deviceID] = state
+	return state
+}
+
+// save persists the learned cooldown of a device. The caller holds uc.mu.
+func (uc *CommandCooldownUseCase) save(state *commandCooldownState) {
+	state.learned.UpdatedAt = uc.clock.Now().Unix()
+	if uc.cache == nil {
+		return
+	}
+	data, err := json.Marshal(state.learned)
+	if err != nil {
+		utils.LogWarn("CommandCooldownUseCase: Failed to marshal cooldown of %s: %v", state.learned.DeviceID, err)
+		return
+	}
+	if err := uc.cache.SetPersistent(commandCooldownPrefix+state.learned.DeviceID, data); err != nil {
+		utils.LogWarn("CommandCooldownUseCase: Failed to save cooldown of %s: %v", state.learned.DeviceID, err)
+	}
+}
+
+// commandCooldownToDTO converts the state of a device into its API representation.
+func commandCooldownToDTO(state *commandCooldownState) dtos.CommandCooldownDTO {
+	dto := dtos.CommandCooldownDTO{
+		DeviceID:       state.learned.DeviceID,
+		CooldownMs:     state.learned.CooldownMs,
+		RapidFailures:  state.learned.RapidFailures,
+		SuccessStreak:  state.successStreak,
+		LastFailureAt:  state.learned.LastFailureAt,
+		LastFailure:    state.learned.LastFailure,
+		LastFailureGap: state.learned.LastFailureGap,
+		UpdatedAt:      state.learned.UpdatedAt,
+	}
+	if !state.lastCommand.IsZero() {
+		dto.LastCommandAt = state.lastCommand.Unix()
+	}
+	return dto
+}
@@ -0,0 +1,170 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+	"time"
+)
+
+const (
+	// commandDedupPrefix stores the outcomes shared within the window: "command_dedup:{key}".
+	commandDedupPrefix = "command_dedup:"
+	// defaultCommandDedupWindow is the deduplication window when IR_DEDUP_WINDOW is not set.
+	defaultCommandDedupWindow = 3 * time.Second
+)
+
+// commandDedupCall is a command being executed; identical commands wait for it instead of executing again.
+type commandDedupCall struct {
+	done   chan struct{}
+	result []byte
+	err    error
+}
+
+// CommandDedupUseCase coalesces identical commands arriving within a short window from several clients
+// (e.g., the whole family tapping "off" on the AC at once). The first command is executed; identical ones
+// arriving while it runs wait for it, and those arriving shortly after it succeeded get the stored outcome.
+// IR power toggles need this most: two identical "power" presses turn the device off and on again.
+// Outcomes are stored in Badger for the window (IR_DEDUP_WINDOW), so they also survive a restart.
+type CommandDedupUseCase struct {
+	cache  persistence.CacheStore
+	window time.Duration
+	clock  utils.Clock
+
+	mu       sync.Mutex
+	inflight map[string]*commandDedupCall
+}
+
+// NewCommandDedupUseCase initializes a new CommandDedupUseCase.
+// The window is read from IR_DEDUP_WINDOW; "0" disables deduplication.
+//
+// param cache The CacheStore used to store outcomes within the window (optional).
+// param clock The Clock used to timestamp outcomes.
+// return *CommandDedupUseCase A pointer to the initialized usecase.
+func NewCommandDedupUseCase(cache persistence.CacheStore, clock utils.Clock) *CommandDedupUseCase {
+	window := defaultCommandDedupWindow
+	if raw := utils.GetConfig().IRDedupWindow; raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed >= 0 {
+			window = parsed
+		} else {
+			utils.LogWarn("CommandDedupUseCase: Invalid IR_DEDUP_WINDOW %q, using %s", raw, defaultCommandDedupWindow)
+		}
+	}
+	return &CommandDedupUseCase{
+		cache:    cache,
+		window:   window,
+		clock:    clock,
+		inflight: make(map[string]*commandDedupCall),
+	}
+}
+
+// Do executes a command once per key within the window and shares its outcome with identical commands.
+// A nil CommandDedupUseCase, or a zero window, executes every command.
+//
+// param ctx The request context; waiting for an identical command stops when it is cancelled.
+// param key Identifies identical commands (e.g., IR hub, remote, code and value).
+// param out Receives the outcome of execute, or the shared outcome (must be a pointer).
+// param execute The function executing the command and returning its outcome.
+// return bool True if the outcome was shared from an identical command instead of executing.
+// return error The error of execute (also shared with waiting commands), or a decoding or context error.
+func (uc *CommandDedupUseCase) Do(ctx context.Context, key string, out interface{}, execute func() (interface{}, error)) (bool, error) {
+	if uc == nil || uc.window <= 0 {
+		result, err := execute()
+		if err != nil {
+			return false, err
+		}
+		return false, copyDedupResult(result, out)
+	}
+
+	uc.mu.Lock()
+	if call, ok := uc.inflight[key]; ok {
+		uc.mu.Unlock()
+		select {
+		case <-call.done:
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+		if call.err != nil {
+			return true, call.err
+		}
+		utils.LogInfo("CommandDedupUseCase: Coalesced %s with the command in flight", key)
+		return true, json.Unmarshal(call.result, out)
+	}
+	if stored := uc.storedResult(key); stored != nil {
+		uc.mu.Unlock()
+		utils.LogInfo("CommandDedupUseCase: Coalesced %s with the command executed at %d", key, stored.ExecutedAt)
+		return true, json.Unmarshal(stored.Result, out)
+	}
+	call := &commandDedupCall{done: make(chan struct{})}
+	uc.inflight[key] = call
+	uc.mu.Unlock()
+
+	result, err := execute()
+	if err == nil {
+		call.result, err = json.Marshal(result)
+		if err != nil {
+			err = fmt.Errorf("failed to encode command result: %w", err)
+		}
+	}
+	call.err = err
+
+	uc.mu.Lock()
+	if err == nil {
+		uc.storeResult(key, call.result)
+	}
+	delete(uc.inflight, key)
+	uc.mu.Unlock()
+	close(call.done)
+
+	if err != nil {
+		return false, err
+	}
+	return false, json.Unmarshal(call.result, out)
+}
+
+// storedResult returns the outcome stored for key within the window, or nil. The caller must hold uc.mu.
+func (uc *CommandDedupUseCase) storedResult(key string) *entities.CommandDedupResult {
+	if uc.cache == nil {
+		return nil
+	}
+	data, err := uc.cache.Get(commandDedupPrefix + key)
+	if err != nil || data == nil {
+		return nil
+	}
+	var stored entities.CommandDedupResult
+	if err := json.Unmarshal(data, &stored); err != nil {
+		return nil
+	}
+	// Badger TTLs have a one second resolution, so the window is also checked here
+	if uc.clock.Now().Sub(time.UnixMilli(stored.ExecutedAt)) > uc.window {
+		return nil
+	}
+	return &stored
+}
+
+// storeResult keeps a successful outcome for the window. The caller must hold uc.mu.
+func (uc *CommandDedupUseCase) storeResult(key string, result []byte) {
+	if uc.cache == nil {
+		return
+	}
+	data, err := json.Marshal(entities.CommandDedupResult{Result: result, ExecutedAt: uc.clock.Now().UnixMilli()})
+	if err != nil {
+		return
+	}
+	if err := uc.cache.Set(commandDedupPrefix+key, data, uc.window); err != nil {
+		utils.LogWarn("CommandDedupUseCase: Failed to store outcome of %s: %v", key, err)
+	}
+}
+
+// copyDedupResult copies an outcome into out through JSON, as shared outcomes are.
+func copyDedupResult(result interface{}, out interface{}) error {
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to encode command result: %w", err)
+	}
+	return json.Unmarshal(data, out)
+}
@@ -0,0 +1,239 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+)
+
+// deviceCategoryFilterKey is the Badger key of the category filter set through the API.
+const deviceCategoryFilterKey = "settings:device_category_filter"
+
+// Sources of the effective device category filter.
+const (
+	DeviceCategoryFilterSourceConfig = "config"
+	DeviceCategoryFilterSourceAPI    = "api"
+)
+
+// DeviceCategoryFilterUseCase keeps irrelevant devices (e.g., a neighbour's shared cameras) out of device
+// listings with a deployment-wide category allow/deny list. The filter starts from DEVICE_CATEGORY_ALLOW and
+// DEVICE_CATEGORY_DENY and can be replaced at runtime through the admin API (persisted, so it survives restarts).
+// It is kept in memory, since it is consulted on every device listing.
+type DeviceCategoryFilterUseCase struct {
+	cache persistence.CacheStore
+	clock utils.Clock
+
+	mu       sync.RWMutex
+	filter   entities.DeviceCategoryFilter
+	source   string
+	allow    map[string]bool
+	deny     map[string]bool
+	revision uint64
+}
+
+// NewDeviceCategoryFilterUseCase initializes a new DeviceCategoryFilterUseCase with the filter set through
+// the API, or else the DEVICE_CATEGORY_ALLOW and DEVICE_CATEGORY_DENY configuration.
+//
+// param cache The CacheStore used to persist the filter set through the API.
+// param clock The Clock used to timestamp filter changes.
+// return *DeviceCategoryFilterUseCase A pointer to the initialized usecase.
+func NewDeviceCategoryFilterUseCase(cache persistence.CacheStore, clock utils.Clock) *DeviceCategoryFilterUseCase {
+	uc := &DeviceCategoryFilterUseCase{
+		cache: cache,
+		clock: clock,
+	}
+	uc.apply(configuredCategoryFilter(), DeviceCategoryFilterSourceConfig)
+
+	if cache != nil {
+		if data, err := cache.Get(deviceCategoryFilterKey); err == nil && data != nil {
+			var filter entities.DeviceCategoryFilter
+			if err := json.Unmarshal(data, &filter); err == nil {
+				uc.apply(filter, DeviceCategoryFilterSourceAPI)
+			} else {
+				utils.LogWarn("DeviceCategoryFilterUseCase: Ignoring malformed category filter: %v", err)
+			}
+		}
+	}
+	return uc
+}
+
+// Allows reports whether devices of a category may be listed.
+//
+// param category The Tuya category code.
+// return bool False if the category is denied, or not allowed while an allow list is set.
+func (uc *DeviceCategoryFilterUseCase) Allows(category string) bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.allows(category)
+}
+
+// Active reports whether any category is filtered out.
+//
+// return bool True if an allow or deny list is set.
+func (uc *DeviceCategoryFilterUseCase) Active() bool {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return len(uc.allow) > 0 || len(uc.deny) > 0
+}
+
+// Revision returns a counter that changes whenever the filter changes, so pre-marshaled device lists
+// built with an older filter are not reused.
+//
+// return uint64 The revision.
+func (uc *DeviceCategoryFilterUseCase) Revision() uint64 {
+	return atomic.LoadUint64(&uc.revision)
+}
+
+// FilterDevices removes the devices of filtered categories, including those nested in collections
+// (e.g., IR remotes grouped under their hub). Merged devices are kept when either their own or their
+// remote category is allowed; hiding a hub also hides the remotes grouped under it.
+//
+// param devices The devices.
+// return []dtos.TuyaDeviceDTO The devices whose category may be listed.
+func (uc *DeviceCategoryFilterUseCase) FilterDevices(devices []dtos.TuyaDeviceDTO) []dtos.TuyaDeviceDTO {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	if len(uc.allow) == 0 && len(uc.deny) == 0 {
+		return devices
+	}
+	return uc.filterDevices(devices)
+}
+
+// GetFilter returns the effective category filter.
+//
+// return *dtos.DeviceCategoryFilterDTO The filter and where it comes from.
+func (uc *DeviceCategoryFilterUseCase) GetFilter() *dtos.DeviceCategoryFilterDTO {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	allow := uc.filter.Allow
+	if allow == nil {
+		allow = []string{}
+	}
+	deny := uc.filter.Deny
+	if deny == nil {
+		deny = []string{}
+	}
+	return &dtos.DeviceCategoryFilterDTO{
+		Allow:     allow,
+		Deny:      deny,
+		Source:    uc.source,
+		UpdatedAt: uc.filter.UpdatedAt,
+	}
+}
+
+// UpdateFilter replaces the category filter. The change applies immediately and persists.
+//
+// param req The allowed and denied categories.
+// return *dtos.DeviceCategoryFilterDTO The updated filter.
+// return error An error if the filter cannot be saved.
+func (uc *DeviceCategoryFilterUseCase) UpdateFilter(req dtos.UpdateDeviceCategoryFilterRequestDTO) (*dtos.DeviceCategoryFilterDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("persistence not initialized")
+	}
+
+	filter := entities.DeviceCategoryFilter{
+		Allow:     normalizeCategories(req.Allow),
+		Deny:      normalizeCategories(req.Deny),
+		UpdatedAt: uc.clock.Now().Unix(),
+	}
+	data, err := json.Marshal(filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal category filter: %w", err)
+	}
+	if err := uc.cache.SetPersistent(deviceCategoryFilterKey, data); err != nil {
+		return nil, fmt.Errorf("failed to save category filter: %w", err)
+	}
+
+	uc.apply(filter, DeviceCategoryFilterSourceAPI)
+	utils.LogInfo("DeviceCategoryFilterUseCase: Filter set (allow %v, deny %v)", filter.Allow, filter.Deny)
+	return uc.GetFilter(), nil
+}
+
+// ResetFilter removes the filter set through the API, restoring the configured one.
+//
+// return *dtos.DeviceCategoryFilterDTO The filter after the reset.
+// return error An error if the filter cannot be removed.
+func (uc *DeviceCategoryFilterUseCase) ResetFilter() (*dtos.DeviceCategoryFilterDTO, error) {
+	if uc.cache != nil {
+		if err := uc.cache.Delete(deviceCategoryFilterKey); err != nil {
+			return nil, fmt.Errorf("failed to reset category filter: %w", err)
+		}
+	}
+
+	uc.apply(configuredCategoryFilter(), DeviceCategoryFilterSourceConfig)
+	utils.LogInfo("DeviceCategoryFilterUseCase: Filter reset")
+	return uc.GetFilter(), nil
+}
+
+// apply makes a filter effective and bumps the revision.
+func (uc *DeviceCategoryFilterUseCase) apply(filter entities.DeviceCategoryFilter, source string) {
+	allow := make(map[string]bool, len(filter.Allow))
+	for _, category := range filter.Allow {
+		allow[category] = true
+	}
+	deny := make(map[string]bool, len(filter.Deny))
+	for _, category := range filter.Deny {
+		deny[category] = true
+	}
+
+	uc.mu.Lock()
+	uc.filter = filter
+	uc.source = source
+	uc.allow = allow
+	uc.deny = deny
+	uc.mu.Unlock()
+	atomic.AddUint64(&uc.revision, 1)
+}
+
+// allows reports whether a category may be listed. The caller must hold uc.mu.
+func (uc *DeviceCategoryFilterUseCase) allows(category string) bool {
+	if uc.deny[category] {
+		return false
+	}
+	return len(uc.allow) == 0 || uc.allow[category]
+}
+
+// filterDevices filters devices and their collections recursively. The caller must hold uc.mu.
+func (uc *DeviceCategoryFilterUseCase) filterDevices(devices []dtos.TuyaDeviceDTO) []dtos.TuyaDeviceDTO {
+	result := make([]dtos.TuyaDeviceDTO, 0, len(devices))
+	for _, device := range devices {
+		if !uc.allows(device.Category) && (device.RemoteCategory == "" || !uc.allows(device.RemoteCategory)) {
+			continue
+		}
+		if len(device.Collections) > 0 {
+			device.Collections = uc.filterDevices(device.Collections)
+		}
+		result = append(result, device)
+	}
+	return result
+}
+
+// configuredCategoryFilter returns the filter from DEVICE_CATEGORY_ALLOW and DEVICE_CATEGORY_DENY.
+func configuredCategoryFilter() entities.DeviceCategoryFilter {
+	config := utils.GetConfig()
+	return entities.DeviceCategoryFilter{
+		Allow: normalizeCategories(strings.Split(config.DeviceCategoryAllow, ",")),
+		Deny:  normalizeCategories(strings.Split(config.DeviceCategoryDeny, ",")),
+	}
+}
+
+// normalizeCategories trims category codes, dropping blanks and duplicates.
+func normalizeCategories(categories []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, category := range categories {
+		category = strings.TrimSpace(category)
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		result = append(result, category)
+	}
+	return result
+}
@@ -0,0 +1,201 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+)
+
+// maxDeviceChangeLogEntries bounds how many refreshes with changes are kept per user.
+const maxDeviceChangeLogEntries = 100
+
+// Device change types recorded in the change log.
+const (
+	DeviceChangeAdded           = "added"
+	DeviceChangeRemoved         = "removed"
+	DeviceChangeRenamed         = "renamed"
+	DeviceChangeCategoryChanged = "category_changed"
+)
+
+// DeviceChangeLogUseCase detects device list differences between refreshes and keeps a bounded log.
+// Snapshots and logs are persistent so they survive cache flushes.
+type DeviceChangeLogUseCase struct {
+	cache persistence.CacheStore
+	clock utils.Clock
+}
+
+// NewDeviceChangeLogUseCase initializes a new DeviceChangeLogUseCase.
+//
+// param cache The CacheStore used to persist snapshots and the change log.
+// param clock The Clock used to timestamp detected changes.
+// return *DeviceChangeLogUseCase A pointer to the initialized usecase.
+func NewDeviceChangeLogUseCase(cache persistence.CacheStore, clock utils.Clock) *DeviceChangeLogUseCase {
+	return &DeviceChangeLogUseCase{
+		cache: cache,
+		clock: clock,
+	}
+}
+
+// RecordRefresh compares a freshly fetched device list against the previous snapshot.
+// Any differences are appended to the change log, and the snapshot is replaced.
+// The first refresh for a user only stores the baseline snapshot.
+//
+// param uid The Tuya User ID the list belongs to.
+// param devices The flat device list from the refresh (before grouping).
+// return []entities.DeviceChange The changes detected by this refresh.
+// return error An error if the snapshot or log cannot be persisted.
+func (uc *DeviceChangeLogUseCase) RecordRefresh(uid string, devices []dtos.TuyaDeviceDTO) ([]entities.DeviceChange, error) {
+	current := make([]entities.DeviceSnapshotEntry, len(devices))
+	for i, d := range devices {
+		current[i] = entities.DeviceSnapshotEntry{ID: d.ID, Name: d.Name, Category: d.Category}
+	}
+
+	snapshotKey := fmt.Sprintf("device_snapshot:%s", uid)
+	previousData, err := uc.cache.Get(snapshotKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device snapshot: %w", err)
+	}
+
+	var changes []entities.DeviceChange
+	if previousData != nil {
+		var previous []entities.DeviceSnapshotEntry
+		if err := json.Unmarshal(previousData, &previous); err != nil {
+			utils.LogWarn("DeviceChangeLogUseCase: Snapshot corrupted for uid %s, resetting baseline", uid)
+		} else {
+			changes = diffDeviceSnapshots(previous, current)
+		}
+	}
+
+	snapshotData, err := json.Marshal(current)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal device snapshot: %w", err)
+	}
+	if err := uc.cache.SetPersistent(snapshotKey, snapshotData); err != nil {
+		return nil, fmt.Errorf("failed to save device snapshot: %w", err)
+	}
+
+	if len(changes) == 0 {
+		return nil, nil
+	}
+
+	utils.LogInfo("DeviceChangeLogUseCase: Detected %d device changes for uid %s", len(changes), uid)
+	for _, change := range changes {
+		utils.LogInfo("  %s: %s (%s) %s -> %s", change.Type, change.DeviceID, change.Name, change.OldValue, change.NewValue)
+	}
+
+	entries, err := uc.loadLog(uid)
+	if err != nil {
+		utils.LogWarn("DeviceChangeLogUseCase: Failed to load change log for uid %s, starting new log: %v", uid, err)
+	}
+	entries = append([]entities.DeviceChangeLogEntry{{DetectedAt: uc.clock.Now().Unix(), Changes: changes}}, entries...)
+	if len(entries) > maxDeviceChangeLogEntries {
+		entries = entries[:maxDeviceChangeLogEntries]
+	}
+
+	logData, err := json.Marshal(entries)
+	if err != nil {
+		return changes, fmt.Errorf("failed to marshal device change log: %w", err)
+	}
+	if err := uc.cache.SetPersistent(fmt.Sprintf("device_changes:%s", uid), logData); err != nil {
+		return changes, fmt.Errorf("failed to save device change log: %w", err)
+	}
+
+	return changes, nil
+}
+
+// GetChangeLog returns the recorded device changes for a user, newest first.
+//
+// param uid The Tuya User ID.
+// return *dtos.DeviceChangeLogResponseDTO The change log.
+// return error An error if the log cannot be read.
+func (uc *DeviceChangeLogUseCase) GetChangeLog(uid string) (*dtos.DeviceChangeLogResponseDTO, error) {
+	entries, err := uc.loadLog(uid)
+	if err != nil {
+		return nil, err
+	}
+
+	entryDTOs := make([]dtos.DeviceChangeLogEntryDTO, len(entries))
+	for i, entry := range entries {
+		changeDTOs := make([]dtos.DeviceChangeDTO, len(entry.Changes))
+		for j, change := range entry.Changes {
+			changeDTOs[j] = dtos.DeviceChangeDTO{
+				Type:     change.Type,
+				DeviceID: change.DeviceID,
+				Name:     change.Name,
+				OldValue: change.OldValue,
+				NewValue: change.NewValue,
+			}
+		}
+		entryDTOs[i] = dtos.DeviceChangeLogEntryDTO{
+			DetectedAt: entry.DetectedAt,
+			Changes:    changeDTOs,
+		}
+	}
+
+	return &dtos.DeviceChangeLogResponseDTO{
+		Entries: entryDTOs,
+		Total:   len(entryDTOs),
+	}, nil
+}
+
+// loadLog reads the persisted change log for a user.
+func (uc *DeviceChangeLogUseCase) loadLog(uid string) ([]entities.DeviceChangeLogEntry, error) {
+	jsonData, err := uc.cache.Get(fmt.Sprintf("device_changes:%s", uid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device change log: %w", err)
+	}
+	if jsonData == nil {
+		return []entities.DeviceChangeLogEntry{}, nil
+	}
+
+	var entries []entities.DeviceChangeLogEntry
+	if err := json.Unmarshal(jsonData, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal device change log: %w", err)
+	}
+	return entries, nil
+}
+
+// diffDeviceSnapshots computes added, removed, renamed and re-categorized devices.
+// Results are sorted by device ID for stable output.
+func diffDeviceSnapshots(previous, current []entities.DeviceSnapshotEntry) []entities.DeviceChange {
+	previousMap := make(map[string]entities.DeviceSnapshotEntry, len(previous))
+	for _, d := range previous {
+		previousMap[d.ID] = d
+	}
+	currentMap := make(map[string]entities.DeviceSnapshotEntry, len(current))
+	for _, d := range current {
+		currentMap[d.ID] = d
+	}
+
+	var changes []entities.DeviceChange
+	for id, cur := range currentMap {
+		prev, existed := previousMap[id]
+		if !existed {
+			changes = append(changes, entities.DeviceChange{Type: DeviceChangeAdded, DeviceID: id, Name: cur.Name, NewValue: cur.Name})
+			continue
+		}
+		if prev.Name != cur.Name {
+			changes = append(changes, entities.DeviceChange{Type: DeviceChangeRenamed, DeviceID: id, Name: cur.Name, OldValue: prev.Name, NewValue: cur.Name})
+		}
+		if prev.Category != cur.Category {
+			changes = append(changes, entities.DeviceChange{Type: DeviceChangeCategoryChanged, DeviceID: id, Name: cur.Name, OldValue: prev.Category, NewValue: cur.Category})
+		}
+	}
+	for id, prev := range previousMap {
+		if _, exists := currentMap[id]; !exists {
+			changes = append(changes, entities.DeviceChange{Type: DeviceChangeRemoved, DeviceID: id, Name: prev.Name, OldValue: prev.Name})
+		}
+	}
+
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].DeviceID == changes[j].DeviceID {
+			return changes[i].Type < changes[j].Type
+		}
+		return changes[i].DeviceID < changes[j].DeviceID
+	})
+	return changes
+}
@@ -0,0 +1,168 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// channelCodePattern matches the per-gang switch codes of multi-gang switches (switch_1, switch_2, ...).
+var channelCodePattern = regexp.MustCompile(`^switch_(\d+)$`)
+
+// DeviceChannelUseCase manages channel names of multi-gang switches and exposes each gang as a sub-entity.
+// Names are persistent metadata and are applied on top of (cached) device DTOs, so renaming never requires a refresh.
+type DeviceChannelUseCase struct {
+	cache    persistence.CacheStore
+	revision uint64
+}
+
+// NewDeviceChannelUseCase initializes a new DeviceChannelUseCase.
+//
+// param cache The CacheStore used to persist channel names.
+// return *DeviceChannelUseCase A pointer to the initialized usecase.
+func NewDeviceChannelUseCase(cache persistence.CacheStore) *DeviceChannelUseCase {
+	return &DeviceChannelUseCase{
+		cache: cache,
+	}
+}
+
+// ApplyChannels fills the Channels field of a multi-gang switch from its status and stored names.
+// Devices with fewer than two switch channels are left untouched.
+//
+// param device The device DTO to enrich.
+func (uc *DeviceChannelUseCase) ApplyChannels(device *dtos.TuyaDeviceDTO) {
+	if device == nil {
+		return
+	}
+
+	var channels []dtos.DeviceChannelDTO
+	for _, status := range device.Status {
+		match := channelCodePattern.FindStringSubmatch(status.Code)
+		if match == nil {
+			continue
+		}
+		index, _ := strconv.Atoi(match[1])
+		channels = append(channels, dtos.DeviceChannelDTO{
+			Code:  status.Code,
+			Index: index,
+			Value: status.Value,
+		})
+	}
+	if len(channels) < 2 {
+		return
+	}
+
+	names := uc.loadNames(device.ID)
+	for i := range channels {
+		if name, ok := names[channels[i].Code]; ok {
+			channels[i].Name = name
+		} else {
+			channels[i].Name = fmt.Sprintf("Channel %d", channels[i].Index)
+		}
+	}
+	sort.Slice(channels, func(i, j int) bool {
+		return channels[i].Index < channels[j].Index
+	})
+	device.Channels = channels
+}
+
+// SaveChannelNames validates and stores channel names for a multi-gang switch.
+// Names for codes not in the map are kept; an empty name removes the custom name.
+//
+// param device The device the names belong to (used to validate the channel codes).
+// param names The channel names keyed by channel code.
+// return error A bad request error for unknown channels, or a storage error.
+func (uc *DeviceChannelUseCase) SaveChannelNames(device *dtos.TuyaDeviceDTO, names map[string]string) error {
+	if uc.cache == nil {
+		return fmt.Errorf("channel storage not initialized")
+	}
+
+	validCodes := make(map[string]bool)
+	for _, status := range device.Status {
+		if channelCodePattern.MatchString(status.Code) {
+			validCodes[status.Code] = true
+		}
+	}
+	if len(validCodes) < 2 {
+		return tuya_errors.BadRequest("device %s is not a multi-gang switch", device.ID)
+	}
+
+	stored := uc.loadNames(device.ID)
+	for code, name := range names {
+		if !validCodes[code] {
+			return tuya_errors.BadRequest("device %s has no channel %s", device.ID, code)
+		}
+		name = strings.TrimSpace(name)
+		if name == "" {
+			delete(stored, code)
+		} else {
+			stored[code] = name
+		}
+	}
+
+	jsonData, err := json.Marshal(stored)
+	if err != nil {
+		return fmt.Errorf("failed to marshal channel names: %w", err)
+	}
+	if err := uc.cache.SetPersistent(channelNamesKey(device.ID), jsonData); err != nil {
+		return fmt.Errorf("failed to save channel names: %w", err)
+	}
+
+	atomic.AddUint64(&uc.revision, 1)
+	utils.LogInfo("DeviceChannelUseCase: Saved %d channel names for device %s", len(stored), device.ID)
+	return nil
+}
+
+// Revision returns a counter that changes whenever channel names are saved.
+// Pre-marshaled device payloads include it in their version, since names are applied on top of cached lists.
+//
+// return uint64 The current revision.
+func (uc *DeviceChannelUseCase) Revision() uint64 {
+	return atomic.LoadUint64(&uc.revision)
+}
+
+// ResolveChannel finds the channel of a device by code (switch_2), index (2) or name (case-insensitive).
+//
+// param device The device DTO with channels applied.
+// param channel The channel reference.
+// return *dtos.DeviceChannelDTO The resolved channel.
+// return error A bad request error if no channel matches.
+func (uc *DeviceChannelUseCase) ResolveChannel(device *dtos.TuyaDeviceDTO, channel string) (*dtos.DeviceChannelDTO, error) {
+	for i := range device.Channels {
+		ch := &device.Channels[i]
+		if ch.Code == channel || strconv.Itoa(ch.Index) == channel || strings.EqualFold(ch.Name, channel) {
+			return ch, nil
+		}
+	}
+	return nil, tuya_errors.BadRequest("device %s has no channel %q", device.ID, channel)
+}
+
+// loadNames reads the stored channel names of a device, returning an empty map when none are stored.
+func (uc *DeviceChannelUseCase) loadNames(deviceID string) map[string]string {
+	names := make(map[string]string)
+	if uc.cache == nil {
+		return names
+	}
+	jsonData, err := uc.cache.Get(channelNamesKey(deviceID))
+	if err != nil || jsonData == nil {
+		return names
+	}
+	if err := json.Unmarshal(jsonData, &names); err != nil {
+		utils.LogWarn("DeviceChannelUseCase: Channel names corrupted for device %s", deviceID)
+		return make(map[string]string)
+	}
+	return names
+}
+
+// channelNamesKey builds the storage key for the channel names of a device.
+func channelNamesKey(deviceID string) string {
+	return fmt.Sprintf("device_channels:%s", deviceID)
+}
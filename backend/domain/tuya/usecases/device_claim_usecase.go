@@ -0,0 +1,420 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+var (
+	// ErrDeviceClaimNotFound is returned when a claim does not exist.
+	ErrDeviceClaimNotFound = tuya_errors.NotFound("device claim not found")
+	// ErrDeviceAlreadyClaimed is returned when a device is already assigned to a tenant.
+	ErrDeviceAlreadyClaimed = tuya_errors.Conflict("device is already assigned to a tenant")
+	// ErrDeviceNotAssigned is returned when releasing a device that is not assigned to a tenant.
+	ErrDeviceNotAssigned = tuya_errors.NotFound("device is not assigned to a tenant")
+	// ErrDeviceClaimDecided is returned when approving or rejecting a claim that is no longer pending.
+	ErrDeviceClaimDecided = tuya_errors.Conflict("device claim was already decided")
+)
+
+const (
+	// deviceClaimPrefix stores claims: "device_claim:{id}".
+	deviceClaimPrefix = "device_claim:"
+	// deviceOwnerPrefix stores the tenant a device is assigned to: "device_owner:{device_id}".
+	deviceOwnerPrefix = "device_owner:"
+)
+
+// DeviceFilter reports whether a device may be shown to the caller. A nil DeviceFilter shows every device.
+type DeviceFilter func(deviceID string) bool
+
+// DeviceClaimUseCase implements device ownership for shared buildings: a tenant claims a device by its ID,
+// an admin approves the claim, and the device is assigned to the tenant. Tenants are identified by their
+// Tuya UID, like favorites. With DEVICE_CLAIMS_ENABLED, device and room listings of non-admin callers only
+// contain the devices assigned to them; otherwise claims are recorded but listings are unchanged.
+type DeviceClaimUseCase struct {
+	cache       persistence.CacheStore
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	enabled     bool
+	clock       utils.Clock
+	ids         utils.IDGenerator
+
+	mu sync.Mutex
+}
+
+// NewDeviceClaimUseCase initializes a new DeviceClaimUseCase.
+//
+// param cache The CacheStore used to persist claims and owners.
+// param getDeviceUC The usecase used to check that a claimed device exists.
+// param clock The Clock used to timestamp claims.
+// param ids The IDGenerator used for claim IDs.
+// return *DeviceClaimUseCase A pointer to the initialized usecase.
+func NewDeviceClaimUseCase(cache persistence.CacheStore, getDeviceUC *TuyaGetDeviceByIDUseCase, clock utils.Clock, ids utils.IDGenerator) *DeviceClaimUseCase {
+	return &DeviceClaimUseCase{
+		cache:       cache,
+		getDeviceUC: getDeviceUC,
+		enabled:     utils.GetConfig().DeviceClaimsEnabled,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// SubmitClaim records a tenant's claim on a device. Submitting the same claim again returns the pending claim.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token, used to check that the device exists.
+// param uid The Tuya UID of the claiming tenant.
+// param req The device ID and an optional note for the admin.
+// return *dtos.DeviceClaimDTO The pending claim.
+// return bool True if a new claim was created.
+// return error ErrDeviceAlreadyClaimed, a bad request error for unknown devices, or a storage error.
+func (uc *DeviceClaimUseCase) SubmitClaim(ctx context.Context, accessToken, uid string, req dtos.SubmitDeviceClaimRequestDTO) (*dtos.DeviceClaimDTO, bool, error) {
+	if uc.cache == nil {
+		return nil, false, fmt.Errorf("claim storage not initialized")
+	}
+	deviceID := strings.TrimSpace(req.DeviceID)
+	if deviceID == "" {
+		return nil, false, tuya_errors.BadRequest("device_id must not be empty")
+	}
+	if _, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID); err != nil {
+		return nil, false, tuya_errors.BadRequest("device %s could not be found: %v", deviceID, err)
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	owner, err := uc.Owner(deviceID)
+	if err != nil {
+		return nil, false, err
+	}
+	if owner != "" {
+		return nil, false, ErrDeviceAlreadyClaimed
+	}
+
+	claims, err := uc.loadClaims()
+	if err != nil {
+		return nil, false, err
+	}
+	for _, claim := range claims {
+		if claim.DeviceID == deviceID && claim.UID == uid && claim.Status == entities.DeviceClaimPending {
+			dto := deviceClaimToDTO(claim)
+			return &dto, false, nil
+		}
+	}
+
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to generate claim ID: %w", err)
+	}
+	claim := &entities.DeviceClaim{
+		ID:        id,
+		DeviceID:  deviceID,
+		UID:       uid,
+		Note:      strings.TrimSpace(req.Note),
+		Status:    entities.DeviceClaimPending,
+		CreatedAt: uc.clock.Now().Unix(),
+	}
+	if err := uc.saveClaim(claim); err != nil {
+		return nil, false, err
+	}
+	utils.LogInfo("DeviceClaimUseCase: %s claimed device %s (claim %s)", uid, deviceID, id)
+	dto := deviceClaimToDTO(claim)
+	return &dto, true, nil
+}
+
+// ListClaims returns claims, newest first.
+//
+// param uid The tenant whose claims are listed, or empty for the claims of all tenants.
+// param status Only claims with this status (pending, approved or rejected), or empty for all.
+// return []dtos.DeviceClaimDTO The claims.
+// return error An error if the claims cannot be read.
+func (uc *DeviceClaimUseCase) ListClaims(uid, status string) ([]dtos.DeviceClaimDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("claim storage not initialized")
+	}
+	claims, err := uc.loadClaims()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dtos.DeviceClaimDTO, 0, len(claims))
+	for _, claim := range claims {
+		if (uid != "" && claim.UID != uid) || (status != "" && claim.Status != status) {
+			continue
+		}
+		result = append(result, deviceClaimToDTO(claim))
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// ApproveClaim assigns the device to the claiming tenant and rejects the other pending claims on it.
+//
+// param claimID The claim ID.
+// return *dtos.DeviceClaimDTO The approved claim.
+// return error ErrDeviceClaimNotFound, ErrDeviceClaimDecided, ErrDeviceAlreadyClaimed, or a storage error.
+func (uc *DeviceClaimUseCase) ApproveClaim(claimID string) (*dtos.DeviceClaimDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("claim storage not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	claim, err := uc.pendingClaim(claimID)
+	if err != nil {
+		return nil, err
+	}
+	owner, err := uc.Owner(claim.DeviceID)
+	if err != nil {
+		return nil, err
+	}
+	if owner != "" && owner != claim.UID {
+		return nil, ErrDeviceAlreadyClaimed
+	}
+
+	if err := uc.cache.SetPersistent(deviceOwnerPrefix+claim.DeviceID, []byte(claim.UID)); err != nil {
+		return nil, fmt.Errorf("failed to assign device: %w", err)
+	}
+	now := uc.clock.Now().Unix()
+	claim.Status = entities.DeviceClaimApproved
+	claim.DecidedAt = now
+	if err := uc.saveClaim(claim); err != nil {
+		return nil, err
+	}
+
+	others, err := uc.loadClaims()
+	if err != nil {
+		return nil, err
+	}
+	for _, other := range others {
+		if other.DeviceID != claim.DeviceID || other.ID == claim.ID || other.Status != entities.DeviceClaimPending {
+			continue
+		}
+		other.Status = entities.DeviceClaimRejected
+		other.Reason = "device was assigned to another tenant"
+		other.DecidedAt = now
+		if err := uc.saveClaim(other); err != nil {
+			utils.LogWarn("DeviceClaimUseCase: Failed to reject competing claim %s: %v", other.ID, err)
+		}
+	}
+
+	utils.LogInfo("DeviceClaimUseCase: Device %s assigned to %s (claim %s)", claim.DeviceID, claim.UID, claim.ID)
+	dto := deviceClaimToDTO(claim)
+	return &dto, nil
+}
+
+// RejectClaim rejects a pending claim.
+//
+// param claimID The claim ID.
+// param reason An optional explanation shown to the tenant.
+// return *dtos.DeviceClaimDTO The rejected claim.
+// return error ErrDeviceClaimNotFound, ErrDeviceClaimDecided, or a storage error.
+func (uc *DeviceClaimUseCase) RejectClaim(claimID, reason string) (*dtos.DeviceClaimDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("claim storage not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	claim, err := uc.pendingClaim(claimID)
+	if err != nil {
+		return nil, err
+	}
+	claim.Status = entities.DeviceClaimRejected
+	claim.Reason = strings.TrimSpace(reason)
+	claim.DecidedAt = uc.clock.Now().Unix()
+	if err := uc.saveClaim(claim); err != nil {
+		return nil, err
+	}
+	dto := deviceClaimToDTO(claim)
+	return &dto, nil
+}
+
+// ReleaseDevice removes the assignment of a device so it can be claimed again.
+//
+// param deviceID The device ID.
+// return error ErrDeviceNotAssigned if the device is not assigned, or a storage error.
+func (uc *DeviceClaimUseCase) ReleaseDevice(deviceID string) error {
+	if uc.cache == nil {
+		return fmt.Errorf("claim storage not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	owner, err := uc.Owner(deviceID)
+	if err != nil {
+		return err
+	}
+	if owner == "" {
+		return ErrDeviceNotAssigned
+	}
+	if err := uc.cache.Delete(deviceOwnerPrefix + deviceID); err != nil {
+		return fmt.Errorf("failed to release device: %w", err)
+	}
+	utils.LogInfo("DeviceClaimUseCase: Device %s released by admin (was assigned to %s)", deviceID, owner)
+	return nil
+}
+
+// Owner returns the tenant a device is assigned to.
+//
+// param deviceID The device ID.
+// return string The tenant's Tuya UID, or empty if the device is unclaimed.
+// return error An error if the assignment cannot be read.
+func (uc *DeviceClaimUseCase) Owner(deviceID string) (string, error) {
+	if uc.cache == nil {
+		return "", nil
+	}
+	data, err := uc.cache.Get(deviceOwnerPrefix + deviceID)
+	if err != nil {
+		return "", fmt.Errorf("failed to read device owner: %w", err)
+	}
+	return string(data), nil
+}
+
+// VisibleTo returns the filter applied to the device listings of a tenant.
+// It returns nil (every device is visible) when claims are disabled.
+//
+// param uid The tenant's Tuya UID.
+// return DeviceFilter The filter, or nil.
+func (uc *DeviceClaimUseCase) VisibleTo(uid string) DeviceFilter {
+	if !uc.enabled || uc.cache == nil {
+		return nil
+	}
+	owned := make(map[string]bool)
+	keys, err := uc.cache.GetAllKeysWithPrefix(deviceOwnerPrefix)
+	if err != nil {
+		utils.LogWarn("DeviceClaimUseCase: Failed to list device owners, hiding all devices: %v", err)
+	}
+	for _, key := range keys {
+		if data, err := uc.cache.Get(key); err == nil && string(data) == uid {
+			owned[strings.TrimPrefix(key, deviceOwnerPrefix)] = true
+		}
+	}
+	return func(deviceID string) bool {
+		return owned[deviceID]
+	}
+}
+
+// pendingClaim loads a claim that can still be decided.
+func (uc *DeviceClaimUseCase) pendingClaim(claimID string) (*entities.DeviceClaim, error) {
+	data, err := uc.cache.Get(deviceClaimPrefix + claimID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get claim: %w", err)
+	}
+	if data == nil {
+		return nil, ErrDeviceClaimNotFound
+	}
+	var claim entities.DeviceClaim
+	if err := json.Unmarshal(data, &claim); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal claim: %w", err)
+	}
+	if claim.Status != entities.DeviceClaimPending {
+		return nil, ErrDeviceClaimDecided
+	}
+	return &claim, nil
+}
+
+// loadClaims reads all stored claims.
+func (uc *DeviceClaimUseCase) loadClaims() ([]*entities.DeviceClaim, error) {
+	keys, err := uc.cache.GetAllKeysWithPrefix(deviceClaimPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list claims: %w", err)
+	}
+	claims := make([]*entities.DeviceClaim, 0, len(keys))
+	for _, key := range keys {
+		data, err := uc.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var claim entities.DeviceClaim
+		if err := json.Unmarshal(data, &claim); err != nil {
+			utils.LogWarn("DeviceClaimUseCase: Skipping unreadable claim %s: %v", key, err)
+			continue
+		}
+		claims = append(claims, &claim)
+	}
+	return claims, nil
+}
+
+// saveClaim persists a claim.
+func (uc *DeviceClaimUseCase) saveClaim(claim *entities.DeviceClaim) error {
+	data, err := json.Marshal(claim)
+	if err != nil {
+		return fmt.Errorf("failed to marshal claim: %w", err)
+	}
+	if err := uc.cache.SetPersistent(deviceClaimPrefix+claim.ID, data); err != nil {
+		return fmt.Errorf("failed to save claim: %w", err)
+	}
+	return nil
+}
+
+// FilterDevices keeps the visible devices of a listing. Devices grouped under a hub are filtered too,
+// and merged devices stay visible when either of their devices is.
+//
+// param devices The devices.
+// param visible The filter; nil keeps every device.
+// return []dtos.TuyaDeviceDTO The visible devices.
+func FilterDevices(devices []dtos.TuyaDeviceDTO, visible DeviceFilter) []dtos.TuyaDeviceDTO {
+	if visible == nil {
+		return devices
+	}
+	result := make([]dtos.TuyaDeviceDTO, 0, len(devices))
+	for _, device := range devices {
+		device.Collections = FilterDevices(device.Collections, visible)
+		if visible(device.ID) || (device.RemoteID != "" && visible(device.RemoteID)) || len(device.Collections) > 0 {
+			result = append(result, device)
+		}
+	}
+	return result
+}
+
+// FilterRooms keeps the rooms containing at least one visible device, listing only those devices.
+//
+// param rooms The rooms.
+// param visible The filter; nil keeps every room.
+// return []dtos.RoomDTO The visible rooms.
+func FilterRooms(rooms []dtos.RoomDTO, visible DeviceFilter) []dtos.RoomDTO {
+	if visible == nil {
+		return rooms
+	}
+	result := make([]dtos.RoomDTO, 0, len(rooms))
+	for _, room := range rooms {
+		deviceIDs := make([]string, 0, len(room.DeviceIDs))
+		for _, id := range room.DeviceIDs {
+			if visible(id) {
+				deviceIDs = append(deviceIDs, id)
+			}
+		}
+		if len(deviceIDs) > 0 {
+			room.DeviceIDs = deviceIDs
+			result = append(result, room)
+		}
+	}
+	return result
+}
+
+// deviceClaimToDTO converts a claim entity into its API representation.
+func deviceClaimToDTO(claim *entities.DeviceClaim) dtos.DeviceClaimDTO {
+	return dtos.DeviceClaimDTO{
+		ID:        claim.ID,
+		DeviceID:  claim.DeviceID,
+		UID:       claim.UID,
+		Note:      claim.Note,
+		Status:    claim.Status,
+		Reason:    claim.Reason,
+		CreatedAt: claim.CreatedAt,
+		DecidedAt: claim.DecidedAt,
+	}
+}
@@ -0,0 +1,252 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+	"time"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// deviceComparisonWindow is how far back command and sensor history are compared.
+const deviceComparisonWindow = 7 * 24 * time.Hour
+
+// deviceSnapshot collects everything compared about one device, flattened per section.
+type deviceSnapshot struct {
+	side     dtos.DeviceComparisonSideDTO
+	sections map[string]map[string]interface{}
+}
+
+// DeviceComparisonUseCase compares two devices side by side to troubleshoot "twins", such as two
+// identical plugs of which one behaves differently. It diffs the device details, firmware versions,
+// specification, current status and the command and sensor history of the last 7 days.
+// Details and firmware are read fresh from Tuya, since stale data defeats troubleshooting.
+type DeviceComparisonUseCase struct {
+	service   *services.TuyaDeviceService
+	specUC    *DeviceSpecificationUseCase
+	auditUC   *AuditLogUseCase
+	historyUC *SensorHistoryUseCase
+	clock     utils.Clock
+}
+
+// NewDeviceComparisonUseCase initializes a new DeviceComparisonUseCase.
+//
+// param service The TuyaDeviceService used to read device details and firmware.
+// param specUC The usecase providing device specifications.
+// param auditUC The usecase providing the audit log.
+// param historyUC The usecase providing recorded sensor readings.
+// param clock The Clock used to bound the compared history.
+// return *DeviceComparisonUseCase A pointer to the initialized usecase.
+func NewDeviceComparisonUseCase(service *services.TuyaDeviceService, specUC *DeviceSpecificationUseCase, auditUC *AuditLogUseCase, historyUC *SensorHistoryUseCase, clock utils.Clock) *DeviceComparisonUseCase {
+	return &DeviceComparisonUseCase{
+		service:   service,
+		specUC:    specUC,
+		auditUC:   auditUC,
+		historyUC: historyUC,
+		clock:     clock,
+	}
+}
+
+// CompareDevices returns a structured diff of two devices.
+// Firmware, specification and history that cannot be read are listed as unavailable instead of failing.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceIDs The IDs of the two devices.
+// return *dtos.DeviceComparisonDTO The devices and the fields that differ.
+// return error A bad request error unless exactly two different IDs are given, or an
+// error if the details of a device cannot be fetched.
+func (uc *DeviceComparisonUseCase) CompareDevices(ctx context.Context, accessToken string, deviceIDs []string) (*dtos.DeviceComparisonDTO, error) {
+	if len(deviceIDs) != 2 || deviceIDs[0] == "" || deviceIDs[1] == "" {
+		return nil, tuya_errors.BadRequest("exactly two device IDs are required")
+	}
+	if deviceIDs[0] == deviceIDs[1] {
+		return nil, tuya_errors.BadRequest("a device cannot be compared with itself")
+	}
+
+	snapshots := make([]*deviceSnapshot, 2)
+	group, groupCtx := errgroup.WithContext(ctx)
+	for i, deviceID := range deviceIDs {
+		group.Go(func() error {
+			snapshot, err := uc.snapshot(groupCtx, accessToken, deviceID)
+			snapshots[i] = snapshot
+			return err
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return nil, err
+	}
+
+	a, b := snapshots[0], snapshots[1]
+	return &dtos.DeviceComparisonDTO{
+		Devices:     []dtos.DeviceComparisonSideDTO{a.side, b.side},
+		SameProduct: a.side.ProductID != "" && a.side.ProductID == b.side.ProductID,
+		Differences: diffSnapshots(a, b),
+	}, nil
+}
+
+// snapshot reads everything compared about a device.
+func (uc *DeviceComparisonUseCase) snapshot(ctx context.Context, accessToken, deviceID string) (*deviceSnapshot, error) {
+	deviceResponse, err := uc.service.FetchDeviceByID(ctx, fmt.Sprintf("/v1.0/devices/%s", deviceID), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !deviceResponse.Success {
+		return nil, tuya_errors.FromTuya(fmt.Sprintf("failed to fetch device %s", deviceID), deviceResponse.Code, deviceResponse.Msg)
+	}
+	device := deviceResponse.Result
+
+	snapshot := &deviceSnapshot{
+		side: dtos.DeviceComparisonSideDTO{
+			ID:          device.ID,
+			Name:        device.Name,
+			ProductID:   device.ProductID,
+			ProductName: device.ProductName,
+			Category:    device.Category,
+			Online:      device.Online,
+			Firmware:    map[string]string{},
+		},
+		sections: map[string]map[string]interface{}{
+			"device": {
+				"product_id":   device.ProductID,
+				"product_name": device.ProductName,
+				"category":     device.Category,
+				"model":        device.Model,
+				"online":       device.Online,
+				"time_zone":    device.TimeZone,
+				"sub":          device.Sub,
+				"gateway_id":   device.GatewayID,
+			},
+			"firmware":      {},
+			"specification": {},
+			"status":        {},
+			"history":       {},
+		},
+	}
+	for _, status := range device.Status {
+		snapshot.sections["status"][status.Code] = status.Value
+	}
+
+	// Firmware: GET /v1.0/devices/{device_id}/upgrade-infos
+	firmwareResponse, err := uc.service.FetchDeviceFirmware(ctx, fmt.Sprintf("/v1.0/devices/%s/upgrade-infos", deviceID), accessToken)
+	if err != nil || !firmwareResponse.Success {
+		snapshot.side.Unavailable = append(snapshot.side.Unavailable, "firmware")
+	} else {
+		for _, module := range firmwareResponse.Result {
+			name := module.TypeDesc
+			if name == "" {
+				name = fmt.Sprintf("type_%d", module.Type)
+			}
+			snapshot.side.Firmware[name] = module.CurrentVersion
+			snapshot.sections["firmware"][name] = module.CurrentVersion
+			snapshot.sections["firmware"][name+".upgrade_status"] = module.UpgradeStatus
+		}
+	}
+
+	if spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID); err != nil {
+		snapshot.side.Unavailable = append(snapshot.side.Unavailable, "specification")
+	} else {
+		for _, fn := range spec.Functions {
+			snapshot.sections["specification"]["function."+fn.Code] = fn.Type + " " + fn.Values
+		}
+		for _, fn := range spec.Status {
+			snapshot.sections["specification"]["status."+fn.Code] = fn.Type + " " + fn.Values
+		}
+	}
+
+	if err := uc.addHistory(snapshot, deviceID); err != nil {
+		utils.LogWarn("DeviceComparisonUseCase: Failed to read history of %s: %v", deviceID, err)
+		snapshot.side.Unavailable = append(snapshot.side.Unavailable, "history")
+	}
+	return snapshot, nil
+}
+
+// addHistory adds the command outcomes from the audit log and the sensor averages of the comparison window.
+func (uc *DeviceComparisonUseCase) addHistory(snapshot *deviceSnapshot, deviceID string) error {
+	now := uc.clock.Now()
+	since := now.Add(-deviceComparisonWindow)
+	history := snapshot.sections["history"]
+
+	if uc.auditUC != nil {
+		records, err := uc.auditUC.List(since, time.Time{}, deviceID)
+		if err != nil {
+			return err
+		}
+		errorCounts := make(map[string]int)
+		for _, record := range records {
+			entry := record.Entry
+			snapshot.side.Commands++
+			if !entry.Success {
+				snapshot.side.FailedCommands++
+				snapshot.side.LastError = entry.Error
+				errorCounts[entry.Error]++
+			}
+		}
+		history["commands"] = snapshot.side.Commands
+		history["failed_commands"] = snapshot.side.FailedCommands
+		for message, count := range errorCounts {
+			history["error."+message] = count
+		}
+	}
+
+	if uc.historyUC != nil {
+		buckets, err := uc.historyUC.GetBuckets(deviceID, since, now)
+		if err != nil {
+			return err
+		}
+		sums := make(map[string]float64)
+		counts := make(map[string]int64)
+		for _, bucket := range buckets {
+			for code, rollup := range bucket.Codes {
+				sums[code] += rollup.Sum
+				counts[code] += rollup.Count
+			}
+		}
+		for code, count := range counts {
+			if count > 0 {
+				history["avg."+code] = roundTo(sums[code]/float64(count), 2)
+			}
+		}
+	}
+	return nil
+}
+
+// diffSnapshots lists the fields that differ between two devices, ordered by section and field.
+func diffSnapshots(a, b *deviceSnapshot) []dtos.DeviceDifferenceDTO {
+	differences := []dtos.DeviceDifferenceDTO{}
+	for _, section := range []string{"device", "firmware", "specification", "status", "history"} {
+		fieldSet := make(map[string]bool)
+		for field := range a.sections[section] {
+			fieldSet[field] = true
+		}
+		for field := range b.sections[section] {
+			fieldSet[field] = true
+		}
+		fields := make([]string, 0, len(fieldSet))
+		for field := range fieldSet {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		for _, field := range fields {
+			valueA, okA := a.sections[section][field]
+			valueB, okB := b.sections[section][field]
+			if okA && okB && reflect.DeepEqual(valueA, valueB) {
+				continue
+			}
+			differences = append(differences, dtos.DeviceDifferenceDTO{
+				Section: section,
+				Field:   field,
+				A:       valueA,
+				B:       valueB,
+			})
+		}
+	}
+	return differences
+}
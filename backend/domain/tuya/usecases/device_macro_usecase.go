@@ -0,0 +1,417 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// ErrDeviceMacroNotFound is returned when a device has no macro with the requested name.
+var ErrDeviceMacroNotFound = tuya_errors.NotFound("device macro not found")
+
+// Macro parameter types.
+const (
+	MacroParamNumber  = "number"
+	MacroParamInteger = "integer"
+	MacroParamBoolean = "boolean"
+	MacroParamString  = "string"
+)
+
+// deviceMacroPrefix is the key prefix of stored macros: "device_macro:{device_id}:{name}".
+const deviceMacroPrefix = "device_macro:"
+
+var (
+	// macroNamePattern restricts macro and parameter names so they are safe in URLs and placeholders.
+	macroNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+	// macroPlaceholderPattern matches "${param}" references in command values.
+	macroPlaceholderPattern = regexp.MustCompile(`\$\{([a-z][a-z0-9_]*)\}`)
+)
+
+// DeviceMacroUseCase stores parameterized command macros per device and expands them into Tuya commands.
+// A macro such as set_ac(temp) bundles the steps a client would otherwise send one by one
+// (power on, mode cool, target temperature) and is invoked with just its arguments.
+type DeviceMacroUseCase struct {
+	cache     persistence.CacheStore
+	controlUC *TuyaDeviceControlUseCase
+	clock     utils.Clock
+}
+
+// NewDeviceMacroUseCase initializes a new DeviceMacroUseCase.
+//
+// param cache The CacheStore used to persist macros.
+// param controlUC The usecase sending the expanded commands.
+// param clock The Clock used to timestamp macros.
+// return *DeviceMacroUseCase A pointer to the initialized usecase.
+func NewDeviceMacroUseCase(cache persistence.CacheStore, controlUC *TuyaDeviceControlUseCase, clock utils.Clock) *DeviceMacroUseCase {
+	return &DeviceMacroUseCase{
+		cache:     cache,
+		controlUC: controlUC,
+		clock:     clock,
+	}
+}
+
+// ListMacros returns the macros of a device ordered by name.
+//
+// param deviceID The device ID.
+// return []dtos.DeviceMacroDTO The macros of the device.
+// return error An error if the macros cannot be listed.
+func (uc *DeviceMacroUseCase) ListMacros(deviceID string) ([]dtos.DeviceMacroDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("macro storage not initialized")
+	}
+
+	keys, err := uc.cache.GetAllKeysWithPrefix(deviceMacroPrefix + deviceID + ":")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list macros: %w", err)
+	}
+	sort.Strings(keys)
+
+	macros := make([]dtos.DeviceMacroDTO, 0, len(keys))
+	for _, key := range keys {
+		jsonData, err := uc.cache.Get(key)
+		if err != nil || jsonData == nil {
+			continue
+		}
+		var macro entities.DeviceMacro
+		if err := json.Unmarshal(jsonData, &macro); err != nil {
+			utils.LogWarn("DeviceMacroUseCase: Skipping malformed macro %s: %v", key, err)
+			continue
+		}
+		macros = append(macros, toDeviceMacroDTO(&macro))
+	}
+	return macros, nil
+}
+
+// SaveMacro creates or replaces a macro of a device.
+//
+// param deviceID The device ID.
+// param name The macro name (lowercase letters, digits and underscores).
+// param req The parameters and command templates.
+// return *dtos.DeviceMacroDTO The stored macro.
+// return error A bad request error for an invalid macro, or a storage error.
+func (uc *DeviceMacroUseCase) SaveMacro(deviceID, name string, req dtos.SaveDeviceMacroRequestDTO) (*dtos.DeviceMacroDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("macro storage not initialized")
+	}
+	if !macroNamePattern.MatchString(name) {
+		return nil, tuya_errors.BadRequest("macro name %q must start with a lowercase letter and contain only lowercase letters, digits and underscores", name)
+	}
+
+	declared := make(map[string]bool, len(req.Parameters))
+	parameters := make([]entities.MacroParameter, 0, len(req.Parameters))
+	for _, p := range req.Parameters {
+		if !macroNamePattern.MatchString(p.Name) {
+			return nil, tuya_errors.BadRequest("parameter name %q must start with a lowercase letter and contain only lowercase letters, digits and underscores", p.Name)
+		}
+		if declared[p.Name] {
+			return nil, tuya_errors.BadRequest("parameter %s is declared twice", p.Name)
+		}
+		declared[p.Name] = true
+
+		parameter := entities.MacroParameter{
+			Name:    p.Name,
+			Type:    p.Type,
+			Min:     p.Min,
+			Max:     p.Max,
+			Options: p.Options,
+		}
+		if parameter.Min != nil && parameter.Max != nil && *parameter.Min > *parameter.Max {
+			return nil, tuya_errors.BadRequest("parameter %s has min greater than max", p.Name)
+		}
+		if p.Default != nil {
+			value, err := coerceMacroArgument(parameter, p.Default)
+			if err != nil {
+				return nil, tuya_errors.BadRequest("default of %w", err)
+			}
+			parameter.Default = value
+		}
+		parameters = append(parameters, parameter)
+	}
+
+	commands := make([]entities.TuyaCommand, 0, len(req.Commands))
+	for _, c := range req.Commands {
+		for _, reference := range macroPlaceholders(c.Value) {
+			if !declared[reference] {
+				return nil, tuya_errors.BadRequest("command %s references undeclared parameter %s", c.Code, reference)
+			}
+		}
+		commands = append(commands, entities.TuyaCommand{Code: c.Code, Value: c.Value})
+	}
+
+	now := uc.clock.Now().Unix()
+	macro := &entities.DeviceMacro{
+		DeviceID:   deviceID,
+		Name:       name,
+		Parameters: parameters,
+		Commands:   commands,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	if existing, err := uc.loadMacro(deviceID, name); err == nil {
+		macro.CreatedAt = existing.CreatedAt
+	} else if !errors.Is(err, ErrDeviceMacroNotFound) {
+		return nil, err
+	}
+
+	jsonData, err := json.Marshal(macro)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal macro: %w", err)
+	}
+	if err := uc.cache.SetPersistent(deviceMacroKey(deviceID, name), jsonData); err != nil {
+		return nil, fmt.Errorf("failed to save macro: %w", err)
+	}
+
+	utils.LogInfo("DeviceMacroUseCase: Saved macro %s for device %s", name, deviceID)
+	result := toDeviceMacroDTO(macro)
+	return &result, nil
+}
+
+// DeleteMacro removes a macro of a device.
+//
+// param deviceID The device ID.
+// param name The macro name.
+// return error ErrDeviceMacroNotFound if the macro does not exist, or a storage error.
+func (uc *DeviceMacroUseCase) DeleteMacro(deviceID, name string) error {
+	if _, err := uc.loadMacro(deviceID, name); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(deviceMacroKey(deviceID, name)); err != nil {
+		return fmt.Errorf("failed to delete macro: %w", err)
+	}
+	utils.LogInfo("DeviceMacroUseCase: Deleted macro %s of device %s", name, deviceID)
+	return nil
+}
+
+// RunMacro expands a macro with the given arguments and sends the resulting commands to the device in one call.
+//
+// param ctx The request context.
+// param accessToken The Tuya access token.
+// param deviceID The device ID.
+// param name The macro name.
+// param params The arguments keyed by parameter name; parameters with a default may be omitted.
+// return *dtos.RunDeviceMacroResponseDTO The expanded commands and the outcome.
+// return error ErrDeviceMacroNotFound, a bad request error for invalid arguments, or the command error.
+func (uc *DeviceMacroUseCase) RunMacro(ctx context.Context, accessToken, deviceID, name string, params map[string]interface{}) (*dtos.RunDeviceMacroResponseDTO, error) {
+	macro, err := uc.loadMacro(deviceID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	commands, err := expandDeviceMacro(macro, params)
+	if err != nil {
+		return nil, err
+	}
+
+	success, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.RunDeviceMacroResponseDTO{
+		DeviceID: deviceID,
+		Macro:    name,
+		Success:  success,
+		Commands: commands,
+	}, nil
+}
+
+// loadMacro reads a macro from storage.
+func (uc *DeviceMacroUseCase) loadMacro(deviceID, name string) (*entities.DeviceMacro, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("macro storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(deviceMacroKey(deviceID, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get macro: %w", err)
+	}
+	if jsonData == nil {
+		return nil, ErrDeviceMacroNotFound
+	}
+	var macro entities.DeviceMacro
+	if err := json.Unmarshal(jsonData, &macro); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal macro: %w", err)
+	}
+	return &macro, nil
+}
+
+// deviceMacroKey builds the storage key of a macro.
+func deviceMacroKey(deviceID, name string) string {
+	return fmt.Sprintf("%s%s:%s", deviceMacroPrefix, deviceID, name)
+}
+
+// expandDeviceMacro resolves the arguments of a macro and substitutes them into its commands.
+func expandDeviceMacro(macro *entities.DeviceMacro, params map[string]interface{}) ([]dtos.TuyaCommandDTO, error) {
+	declared := make(map[string]bool, len(macro.Parameters))
+	for _, p := range macro.Parameters {
+		declared[p.Name] = true
+	}
+	for name := range params {
+		if !declared[name] {
+			return nil, tuya_errors.BadRequest("macro %s has no parameter %s", macro.Name, name)
+		}
+	}
+
+	args := make(map[string]interface{}, len(macro.Parameters))
+	for _, p := range macro.Parameters {
+		raw, ok := params[p.Name]
+		if !ok || raw == nil {
+			if p.Default == nil {
+				return nil, tuya_errors.BadRequest("parameter %s is required", p.Name)
+			}
+			raw = p.Default
+		}
+		value, err := coerceMacroArgument(p, raw)
+		if err != nil {
+			return nil, tuya_errors.BadRequest("%w", err)
+		}
+		args[p.Name] = value
+	}
+
+	commands := make([]dtos.TuyaCommandDTO, 0, len(macro.Commands))
+	for _, c := range macro.Commands {
+		commands = append(commands, dtos.TuyaCommandDTO{Code: c.Code, Value: substituteMacroValue(c.Value, args)})
+	}
+	return commands, nil
+}
+
+// coerceMacroArgument checks an argument against its parameter declaration and normalizes it
+// (integers become int64 so Tuya receives whole numbers).
+func coerceMacroArgument(p entities.MacroParameter, raw interface{}) (interface{}, error) {
+	switch p.Type {
+	case MacroParamNumber, MacroParamInteger:
+		number, ok := macroNumber(raw)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s must be a number", p.Name)
+		}
+		if p.Type == MacroParamInteger && number != math.Trunc(number) {
+			return nil, fmt.Errorf("parameter %s must be a whole number", p.Name)
+		}
+		if p.Min != nil && number < *p.Min {
+			return nil, fmt.Errorf("parameter %s must be at least %v", p.Name, *p.Min)
+		}
+		if p.Max != nil && number > *p.Max {
+			return nil, fmt.Errorf("parameter %s must be at most %v", p.Name, *p.Max)
+		}
+		if p.Type == MacroParamInteger {
+			return int64(number), nil
+		}
+		return number, nil
+	case MacroParamBoolean:
+		value, ok := raw.(bool)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s must be true or false", p.Name)
+		}
+		return value, nil
+	case MacroParamString:
+		value, ok := raw.(string)
+		if !ok {
+			return nil, fmt.Errorf("parameter %s must be a string", p.Name)
+		}
+		if len(p.Options) > 0 && !containsString(p.Options, value) {
+			return nil, fmt.Errorf("parameter %s must be one of %s", p.Name, strings.Join(p.Options, ", "))
+		}
+		return value, nil
+	default:
+		return nil, fmt.Errorf("parameter %s has unknown type %q", p.Name, p.Type)
+	}
+}
+
+// macroNumber converts a decoded JSON number to float64.
+func macroNumber(raw interface{}) (float64, bool) {
+	switch v := raw.(type) {
+	case float64:
+		return v, true
+	case int64:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// substituteMacroValue replaces "${param}" placeholders in a command value, descending into objects and arrays.
+// A value that is exactly one placeholder takes the argument's type; otherwise arguments are inserted as text.
+func substituteMacroValue(value interface{}, args map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case string:
+		if match := macroPlaceholderPattern.FindStringSubmatch(v); match != nil && match[0] == v {
+			return args[match[1]]
+		}
+		return macroPlaceholderPattern.ReplaceAllStringFunc(v, func(placeholder string) string {
+			return fmt.Sprint(args[placeholder[2:len(placeholder)-1]])
+		})
+	case map[string]interface{}:
+		result := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			result[key] = substituteMacroValue(item, args)
+		}
+		return result
+	case []interface{}:
+		result := make([]interface{}, len(v))
+		for i, item := range v {
+			result[i] = substituteMacroValue(item, args)
+		}
+		return result
+	default:
+		return value
+	}
+}
+
+// macroPlaceholders lists the parameter names referenced by a command value.
+func macroPlaceholders(value interface{}) []string {
+	var names []string
+	switch v := value.(type) {
+	case string:
+		for _, match := range macroPlaceholderPattern.FindAllStringSubmatch(v, -1) {
+			names = append(names, match[1])
+		}
+	case map[string]interface{}:
+		for _, item := range v {
+			names = append(names, macroPlaceholders(item)...)
+		}
+	case []interface{}:
+		for _, item := range v {
+			names = append(names, macroPlaceholders(item)...)
+		}
+	}
+	return names
+}
+
+// toDeviceMacroDTO maps a stored macro to its DTO.
+func toDeviceMacroDTO(macro *entities.DeviceMacro) dtos.DeviceMacroDTO {
+	result := dtos.DeviceMacroDTO{
+		DeviceID:   macro.DeviceID,
+		Name:       macro.Name,
+		Parameters: make([]dtos.MacroParameterDTO, 0, len(macro.Parameters)),
+		Commands:   make([]dtos.MacroCommandDTO, 0, len(macro.Commands)),
+		CreatedAt:  macro.CreatedAt,
+		UpdatedAt:  macro.UpdatedAt,
+	}
+	for _, p := range macro.Parameters {
+		result.Parameters = append(result.Parameters, dtos.MacroParameterDTO{
+			Name:    p.Name,
+			Type:    p.Type,
+			Default: p.Default,
+			Min:     p.Min,
+			Max:     p.Max,
+			Options: p.Options,
+		})
+	}
+	for _, c := range macro.Commands {
+		result.Commands = append(result.Commands, dtos.MacroCommandDTO{Code: c.Code, Value: c.Value})
+	}
+	return result
+}
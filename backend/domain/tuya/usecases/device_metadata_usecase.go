@@ -0,0 +1,228 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// deviceMetadataPrefix is the key prefix of device metadata: "device_metadata:{device_id}".
+const deviceMetadataPrefix = "device_metadata:"
+
+// maxOrderedDevices bounds the number of devices in a custom order.
+const maxOrderedDevices = 1000
+
+// DeviceMetadataUseCase stores local device metadata (label, icon, sort order, favorite flag) and merges it
+// into device DTOs. Like channel names, metadata is persistent and applied on top of (cached) device DTOs,
+// so editing it never requires a refresh.
+type DeviceMetadataUseCase struct {
+	cache    persistence.CacheStore
+	clock    utils.Clock
+	revision uint64
+}
+
+// NewDeviceMetadataUseCase initializes a new DeviceMetadataUseCase.
+//
+// param cache The CacheStore used to persist metadata.
+// param clock The Clock used to timestamp changes.
+// return *DeviceMetadataUseCase A pointer to the initialized usecase.
+func NewDeviceMetadataUseCase(cache persistence.CacheStore, clock utils.Clock) *DeviceMetadataUseCase {
+	return &DeviceMetadataUseCase{
+		cache: cache,
+		clock: clock,
+	}
+}
+
+// ApplyMetadata fills the Metadata field of a device and its collections from the stored metadata.
+// Devices without metadata are left untouched.
+//
+// param device The device DTO to enrich.
+func (uc *DeviceMetadataUseCase) ApplyMetadata(device *dtos.TuyaDeviceDTO) {
+	if device == nil {
+		return
+	}
+	if metadata := uc.load(device.ID); metadata != nil {
+		device.Metadata = metadataDTO(metadata)
+	}
+	for i := range device.Collections {
+		uc.ApplyMetadata(&device.Collections[i])
+	}
+}
+
+// GetMetadata returns the metadata of a device.
+//
+// param deviceID The device ID.
+// return dtos.DeviceMetadataDTO The metadata; empty when none is stored.
+func (uc *DeviceMetadataUseCase) GetMetadata(deviceID string) dtos.DeviceMetadataDTO {
+	if metadata := uc.load(deviceID); metadata != nil {
+		return *metadataDTO(metadata)
+	}
+	return dtos.DeviceMetadataDTO{}
+}
+
+// UpdateMetadata applies changes to the metadata of a device. Metadata left empty is removed.
+//
+// param deviceID The device ID.
+// param req The changes; omitted fields are kept.
+// return dtos.DeviceMetadataDTO The updated metadata.
+// return error A storage error.
+func (uc *DeviceMetadataUseCase) UpdateMetadata(deviceID string, req dtos.UpdateDeviceMetadataRequestDTO) (dtos.DeviceMetadataDTO, error) {
+	if uc.cache == nil {
+		return dtos.DeviceMetadataDTO{}, fmt.Errorf("metadata storage not initialized")
+	}
+
+	metadata := uc.load(deviceID)
+	if metadata == nil {
+		metadata = &entities.DeviceMetadata{}
+	}
+	if req.Label != nil {
+		metadata.Label = strings.TrimSpace(*req.Label)
+	}
+	if req.Icon != nil {
+		metadata.Icon = strings.TrimSpace(*req.Icon)
+	}
+	if req.SortOrder != nil {
+		metadata.SortOrder = req.SortOrder
+		if *req.SortOrder < 0 {
+			metadata.SortOrder = nil
+		}
+	}
+	if req.Favorite != nil {
+		metadata.Favorite = *req.Favorite
+	}
+	metadata.UpdatedAt = uc.clock.Now().Unix()
+
+	if err := uc.save(deviceID, metadata); err != nil {
+		return dtos.DeviceMetadataDTO{}, err
+	}
+	utils.LogInfo("DeviceMetadataUseCase: Updated metadata of device %s", deviceID)
+	return *metadataDTO(metadata), nil
+}
+
+// SetOrder replaces the custom device order: listed devices get their position as sort order, and devices
+// that are no longer listed lose theirs. Duplicates are dropped, keeping the first position.
+//
+// param deviceIDs The device IDs in display order.
+// return []string The saved order.
+// return error A bad request error for invalid input, or a storage error.
+func (uc *DeviceMetadataUseCase) SetOrder(deviceIDs []string) ([]string, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("metadata storage not initialized")
+	}
+
+	positions := make(map[string]int)
+	order := make([]string, 0, len(deviceIDs))
+	for _, id := range deviceIDs {
+		if id == "" {
+			return nil, tuya_errors.BadRequest("device_ids must not contain empty values")
+		}
+		if _, ok := positions[id]; ok {
+			continue
+		}
+		positions[id] = len(order)
+		order = append(order, id)
+	}
+	if len(order) > maxOrderedDevices {
+		return nil, tuya_errors.BadRequest("at most %d devices can be ordered", maxOrderedDevices)
+	}
+
+	keys, err := uc.cache.GetAllKeysWithPrefix(deviceMetadataPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list metadata: %w", err)
+	}
+	now := uc.clock.Now().Unix()
+	for _, key := range keys {
+		deviceID := strings.TrimPrefix(key, deviceMetadataPrefix)
+		if _, listed := positions[deviceID]; listed {
+			continue
+		}
+		if metadata := uc.load(deviceID); metadata != nil && metadata.SortOrder != nil {
+			metadata.SortOrder = nil
+			metadata.UpdatedAt = now
+			if err := uc.save(deviceID, metadata); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for deviceID, position := range positions {
+		metadata := uc.load(deviceID)
+		if metadata == nil {
+			metadata = &entities.DeviceMetadata{}
+		}
+		position := position
+		metadata.SortOrder = &position
+		metadata.UpdatedAt = now
+		if err := uc.save(deviceID, metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	utils.LogInfo("DeviceMetadataUseCase: Saved custom order of %d devices", len(order))
+	return order, nil
+}
+
+// Revision returns a counter that changes whenever metadata is saved.
+// Pre-marshaled device payloads include it in their version, since metadata is applied on top of cached lists.
+//
+// return uint64 The current revision.
+func (uc *DeviceMetadataUseCase) Revision() uint64 {
+	return atomic.LoadUint64(&uc.revision)
+}
+
+// save stores the metadata of a device, deleting the key when nothing is left.
+func (uc *DeviceMetadataUseCase) save(deviceID string, metadata *entities.DeviceMetadata) error {
+	if metadata.Label == "" && metadata.Icon == "" && metadata.SortOrder == nil && !metadata.Favorite {
+		if err := uc.cache.Delete(deviceMetadataKey(deviceID)); err != nil {
+			return fmt.Errorf("failed to delete metadata: %w", err)
+		}
+	} else {
+		jsonData, err := json.Marshal(metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
+		}
+		if err := uc.cache.SetPersistent(deviceMetadataKey(deviceID), jsonData); err != nil {
+			return fmt.Errorf("failed to save metadata: %w", err)
+		}
+	}
+	atomic.AddUint64(&uc.revision, 1)
+	return nil
+}
+
+// load reads the stored metadata of a device, returning nil when none is stored.
+func (uc *DeviceMetadataUseCase) load(deviceID string) *entities.DeviceMetadata {
+	if uc.cache == nil {
+		return nil
+	}
+	jsonData, err := uc.cache.Get(deviceMetadataKey(deviceID))
+	if err != nil || jsonData == nil {
+		return nil
+	}
+	var metadata entities.DeviceMetadata
+	if err := json.Unmarshal(jsonData, &metadata); err != nil {
+		utils.LogWarn("DeviceMetadataUseCase: Metadata corrupted for device %s", deviceID)
+		return nil
+	}
+	return &metadata
+}
+
+// metadataDTO converts stored metadata.
+func metadataDTO(metadata *entities.DeviceMetadata) *dtos.DeviceMetadataDTO {
+	return &dtos.DeviceMetadataDTO{
+		Label:     metadata.Label,
+		Icon:      metadata.Icon,
+		SortOrder: metadata.SortOrder,
+		Favorite:  metadata.Favorite,
+		UpdatedAt: metadata.UpdatedAt,
+	}
+}
+
+// deviceMetadataKey builds the storage key for the metadata of a device.
+func deviceMetadataKey(deviceID string) string {
+	return deviceMetadataPrefix + deviceID
+}
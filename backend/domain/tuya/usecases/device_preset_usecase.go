@@ -0,0 +1,282 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// ErrDevicePresetNotFound is returned when a device has no preset with the requested name.
+var ErrDevicePresetNotFound = tuya_errors.NotFound("device preset not found")
+
+// devicePresetPrefix is the key prefix of stored presets: "device_preset:{device_id}:{lowercase name}".
+const devicePresetPrefix = "device_preset:"
+
+// maxStoredNameLength bounds the user-defined names of presets and learned IR codes, which appear in URLs.
+const maxStoredNameLength = 64
+
+// DevicePresetUseCase stores named command bundles per device, such as "AC Night Mode" = power on,
+// temperature 26, fan speed 1. Unlike macros, presets take no arguments: executing one sends its stored
+// commands to the device in a single call through the control usecase, so they apply together or not at all.
+// Names keep their spelling for display but are matched case-insensitively.
+type DevicePresetUseCase struct {
+	cache     persistence.CacheStore
+	controlUC *TuyaDeviceControlUseCase
+	clock     utils.Clock
+}
+
+// NewDevicePresetUseCase initializes a new DevicePresetUseCase.
+//
+// param cache The CacheStore used to persist presets.
+// param controlUC The usecase sending the commands of a preset.
+// param clock The Clock used to timestamp presets.
+// return *DevicePresetUseCase A pointer to the initialized usecase.
+func NewDevicePresetUseCase(cache persistence.CacheStore, controlUC *TuyaDeviceControlUseCase, clock utils.Clock) *DevicePresetUseCase {
+	return &DevicePresetUseCase{
+		cache:     cache,
+		controlUC: controlUC,
+		clock:     clock,
+	}
+}
+
+// ListPresets returns the presets of a device ordered by name.
+//
+// param deviceID The device ID.
+// return []dtos.DevicePresetDTO The presets of the device.
+// return error An error if the presets cannot be listed.
+func (uc *DevicePresetUseCase) ListPresets(deviceID string) ([]dtos.DevicePresetDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("preset storage not initialized")
+	}
+
+	keys, err := uc.cache.GetAllKeysWithPrefix(devicePresetPrefix + deviceID + ":")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list presets: %w", err)
+	}
+	sort.Strings(keys)
+
+	presets := make([]dtos.DevicePresetDTO, 0, len(keys))
+	for _, key := range keys {
+		jsonData, err := uc.cache.Get(key)
+		if err != nil || jsonData == nil {
+			continue
+		}
+		var preset entities.DevicePreset
+		if err := json.Unmarshal(jsonData, &preset); err != nil {
+			utils.LogWarn("DevicePresetUseCase: Skipping malformed preset %s: %v", key, err)
+			continue
+		}
+		presets = append(presets, toDevicePresetDTO(&preset))
+	}
+	return presets, nil
+}
+
+// SavePreset creates or replaces a preset of a device. A preset with the same name in another case is replaced.
+//
+// param deviceID The device ID.
+// param req The preset name and its commands.
+// return *dtos.DevicePresetDTO The stored preset.
+// return bool True if the preset was created, false if an existing one was replaced.
+// return error A bad request error for an invalid preset, or a storage error.
+func (uc *DevicePresetUseCase) SavePreset(deviceID string, req dtos.SaveDevicePresetRequestDTO) (*dtos.DevicePresetDTO, bool, error) {
+	if uc.cache == nil {
+		return nil, false, fmt.Errorf("preset storage not initialized")
+	}
+	name, err := normalizePresetName(req.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	commands, err := toPresetCommands(req.Commands)
+	if err != nil {
+		return nil, false, err
+	}
+
+	now := uc.clock.Now().Unix()
+	preset := &entities.DevicePreset{
+		DeviceID:  deviceID,
+		Name:      name,
+		Commands:  commands,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	created := true
+	if existing, err := uc.loadPreset(deviceID, name); err == nil {
+		preset.CreatedAt = existing.CreatedAt
+		created = false
+	} else if !errors.Is(err, ErrDevicePresetNotFound) {
+		return nil, false, err
+	}
+
+	jsonData, err := json.Marshal(preset)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal preset: %w", err)
+	}
+	if err := uc.cache.SetPersistent(devicePresetKey(deviceID, name), jsonData); err != nil {
+		return nil, false, fmt.Errorf("failed to save preset: %w", err)
+	}
+
+	utils.LogInfo("DevicePresetUseCase: Saved preset %q for device %s", name, deviceID)
+	result := toDevicePresetDTO(preset)
+	return &result, created, nil
+}
+
+// DeletePreset removes a preset of a device.
+//
+// param deviceID The device ID.
+// param name The preset name (any case).
+// return error ErrDevicePresetNotFound if the preset does not exist, or a storage error.
+func (uc *DevicePresetUseCase) DeletePreset(deviceID, name string) error {
+	if _, err := uc.loadPreset(deviceID, name); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(devicePresetKey(deviceID, name)); err != nil {
+		return fmt.Errorf("failed to delete preset: %w", err)
+	}
+	utils.LogInfo("DevicePresetUseCase: Deleted preset %q of device %s", name, deviceID)
+	return nil
+}
+
+// ExecutePreset sends the commands of a preset to the device in one call, so the device applies all of them
+// or none. The call goes through the control usecase and is subject to its cooldowns, approval rules and
+// audit log like any other command.
+//
+// param ctx The request context.
+// param accessToken The Tuya access token.
+// param deviceID The device ID.
+// param name The preset name (any case).
+// return *dtos.ExecuteDevicePresetResponseDTO The sent commands and the outcome.
+// return error ErrDevicePresetNotFound, or the command error.
+func (uc *DevicePresetUseCase) ExecutePreset(ctx context.Context, accessToken, deviceID, name string) (*dtos.ExecuteDevicePresetResponseDTO, error) {
+	preset, err := uc.loadPreset(deviceID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := presetCommandDTOs(preset.Commands)
+	success, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.ExecuteDevicePresetResponseDTO{
+		DeviceID: deviceID,
+		Preset:   preset.Name,
+		Success:  success,
+		Commands: commands,
+	}, nil
+}
+
+// PresetCommands returns the commands of a preset, for callers sending them as part of a larger sequence.
+//
+// param deviceID The device ID.
+// param name The preset name (any case).
+// return string The preset name as saved.
+// return []dtos.TuyaCommandDTO The commands of the preset.
+// return error ErrDevicePresetNotFound if the preset does not exist, or a storage error.
+func (uc *DevicePresetUseCase) PresetCommands(deviceID, name string) (string, []dtos.TuyaCommandDTO, error) {
+	preset, err := uc.loadPreset(deviceID, name)
+	if err != nil {
+		return "", nil, err
+	}
+	return preset.Name, presetCommandDTOs(preset.Commands), nil
+}
+
+// loadPreset reads a preset from storage.
+func (uc *DevicePresetUseCase) loadPreset(deviceID, name string) (*entities.DevicePreset, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("preset storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(devicePresetKey(deviceID, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get preset: %w", err)
+	}
+	if jsonData == nil {
+		return nil, ErrDevicePresetNotFound
+	}
+	var preset entities.DevicePreset
+	if err := json.Unmarshal(jsonData, &preset); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal preset: %w", err)
+	}
+	return &preset, nil
+}
+
+// toPresetCommands validates the commands of a preset: every command needs a code, and a code may be set only once.
+func toPresetCommands(commands []dtos.PresetCommandDTO) ([]entities.TuyaCommand, error) {
+	seen := make(map[string]bool, len(commands))
+	result := make([]entities.TuyaCommand, 0, len(commands))
+	for _, c := range commands {
+		code := strings.TrimSpace(c.Code)
+		if code == "" {
+			return nil, tuya_errors.BadRequest("command code is required")
+		}
+		if seen[code] {
+			return nil, tuya_errors.BadRequest("command %s is set twice", code)
+		}
+		seen[code] = true
+		result = append(result, entities.TuyaCommand{Code: code, Value: c.Value})
+	}
+	return result, nil
+}
+
+// presetCommandDTOs converts stored commands to the form sent through the control usecase.
+func presetCommandDTOs(commands []entities.TuyaCommand) []dtos.TuyaCommandDTO {
+	result := make([]dtos.TuyaCommandDTO, len(commands))
+	for i, c := range commands {
+		result[i] = dtos.TuyaCommandDTO{Code: c.Code, Value: c.Value}
+	}
+	return result
+}
+
+// normalizePresetName trims a preset name and checks it can be used in a URL path segment.
+func normalizePresetName(name string) (string, error) {
+	return normalizeStoredName("preset name", name)
+}
+
+// normalizeStoredName collapses the whitespace of a user-defined name and checks it can be used in a URL
+// path segment. Label names the field in error messages.
+func normalizeStoredName(label, name string) (string, error) {
+	name = strings.Join(strings.Fields(name), " ")
+	if name == "" {
+		return "", tuya_errors.BadRequest("%s is required", label)
+	}
+	if len(name) > maxStoredNameLength {
+		return "", tuya_errors.BadRequest("%s must be at most %d characters", label, maxStoredNameLength)
+	}
+	if strings.ContainsAny(name, "/:") {
+		return "", tuya_errors.BadRequest("%s must not contain '/' or ':'", label)
+	}
+	return name, nil
+}
+
+// storedNameKey returns the case-insensitive form of a user-defined name used in storage keys.
+func storedNameKey(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// devicePresetKey builds the storage key of a preset; names are matched case-insensitively.
+func devicePresetKey(deviceID, name string) string {
+	return fmt.Sprintf("%s%s:%s", devicePresetPrefix, deviceID, storedNameKey(name))
+}
+
+// toDevicePresetDTO maps a stored preset to its DTO.
+func toDevicePresetDTO(preset *entities.DevicePreset) dtos.DevicePresetDTO {
+	result := dtos.DevicePresetDTO{
+		DeviceID:  preset.DeviceID,
+		Name:      preset.Name,
+		Commands:  make([]dtos.PresetCommandDTO, 0, len(preset.Commands)),
+		CreatedAt: preset.CreatedAt,
+		UpdatedAt: preset.UpdatedAt,
+	}
+	for _, c := range preset.Commands {
+		result.Commands = append(result.Commands, dtos.PresetCommandDTO{Code: c.Code, Value: c.Value})
+	}
+	return result
+}
@@ -0,0 +1,124 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/infrastructure/tracing"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+
+	"golang.org/x/sync/errgroup"
+)
+
+// defaultSpecFetchConcurrency bounds the specification requests sent in parallel.
+const defaultSpecFetchConcurrency = 4
+
+// DeviceSpecificationUseCase fetches device specifications (functions and status sets) with a shared cache,
+// so category control, device listings and other callers do not request the same specification twice.
+type DeviceSpecificationUseCase struct {
+	service     *services.TuyaDeviceService
+	cache       persistence.CacheStore
+	ttls        *persistence.CacheTTLPolicy
+	concurrency int
+}
+
+// NewDeviceSpecificationUseCase initializes a new DeviceSpecificationUseCase.
+//
+// param service The TuyaDeviceService used to fetch specifications.
+// param cache The CacheStore used to cache specifications (optional).
+// param ttls The CacheTTLPolicy deciding how long specifications stay cached.
+// return *DeviceSpecificationUseCase A pointer to the initialized usecase.
+func NewDeviceSpecificationUseCase(service *services.TuyaDeviceService, cache persistence.CacheStore, ttls *persistence.CacheTTLPolicy) *DeviceSpecificationUseCase {
+	concurrency, err := strconv.Atoi(utils.GetConfig().TuyaSpecConcurrency)
+	if err != nil || concurrency <= 0 {
+		concurrency = defaultSpecFetchConcurrency
+	}
+	return &DeviceSpecificationUseCase{
+		service:     service,
+		cache:       cache,
+		ttls:        ttls,
+		concurrency: concurrency,
+	}
+}
+
+// GetSpecification returns the device specification, using the cache when possible.
+//
+// Tuya API Documentation (Get Device Specification):
+// URL: /v1.0/iot-03/devices/{device_id}/specification
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device whose specification is fetched.
+// return *entities.TuyaDeviceSpecification The device specification.
+// return error An error if the API call fails.
+func (uc *DeviceSpecificationUseCase) GetSpecification(ctx context.Context, accessToken, deviceID string) (*entities.TuyaDeviceSpecification, error) {
+	cacheKey := fmt.Sprintf("cache:tuya_spec:%s", deviceID)
+	if uc.cache != nil {
+		if cachedData, err := uc.cache.WithContext(ctx).Get(cacheKey); err == nil && cachedData != nil {
+			var spec entities.TuyaDeviceSpecification
+			if err := json.Unmarshal(cachedData, &spec); err == nil {
+				return &spec, nil
+			}
+		}
+	}
+
+	urlPath := fmt.Sprintf("/v1.0/iot-03/devices/%s/specification", deviceID)
+
+	specResp, err := uc.service.FetchDeviceSpecification(ctx, urlPath, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !specResp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch specification", specResp.Code, specResp.Msg)
+	}
+
+	if uc.cache != nil {
+		if jsonData, err := json.Marshal(specResp.Result); err == nil {
+			if err := uc.cache.WithContext(ctx).Set(cacheKey, jsonData, uc.ttls.TTL(persistence.CacheResourceSpecification)); err != nil {
+				utils.LogWarn("DeviceSpecification: Failed to cache specification for %s: %v", deviceID, err)
+			}
+		}
+	}
+	return &specResp.Result, nil
+}
+
+// GetSpecifications fetches the specifications of several devices concurrently, bounded by TUYA_SPEC_CONCURRENCY.
+// Devices whose specification cannot be fetched are logged and left out of the result.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceIDs The devices whose specifications are fetched.
+// return map[string]*entities.TuyaDeviceSpecification The specifications keyed by device ID.
+func (uc *DeviceSpecificationUseCase) GetSpecifications(ctx context.Context, accessToken string, deviceIDs []string) map[string]*entities.TuyaDeviceSpecification {
+	ctx, span := tracing.Start(ctx, "device_specification.fetch_all")
+	span.SetAttribute("device.count", len(deviceIDs))
+	defer span.End()
+
+	var mu sync.Mutex
+	specs := make(map[string]*entities.TuyaDeviceSpecification, len(deviceIDs))
+
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(uc.concurrency)
+	for _, deviceID := range deviceIDs {
+		group.Go(func() error {
+			spec, err := uc.GetSpecification(groupCtx, accessToken, deviceID)
+			if err != nil {
+				utils.LogWarn("DeviceSpecification: Failed to fetch specification for %s: %v", deviceID, err)
+				return nil
+			}
+			mu.Lock()
+			specs[deviceID] = spec
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = group.Wait()
+	return specs
+}
@@ -0,0 +1,186 @@
+package usecases
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// ErrNoStateToUndo is returned when a device has no earlier state that differs in its controllable settings.
+var ErrNoStateToUndo = tuya_errors.NotFound("no earlier state to undo")
+
+// DeviceStateUndoUseCase reverts the last change of a device using its state history.
+// Status reports such as sensor readings are part of the history too, so the newest entry whose
+// controllable settings differ from the current state is restored. Only those settings are sent,
+// through the control usecase, so cooldowns, approvals and the audit log apply as for any command.
+type DeviceStateUndoUseCase struct {
+	deviceStateUC *DeviceStateUseCase
+	getDeviceUC   *TuyaGetDeviceByIDUseCase
+	specUC        *DeviceSpecificationUseCase
+	controlUC     *TuyaDeviceControlUseCase
+}
+
+// NewDeviceStateUndoUseCase initializes a new DeviceStateUndoUseCase.
+//
+// param deviceStateUC The usecase holding the current and previous device states.
+// param getDeviceUC The usecase resolving the category and IR gateway of a device.
+// param specUC The usecase providing the writable codes of a device.
+// param controlUC The usecase sending the restored settings.
+// return *DeviceStateUndoUseCase A pointer to the initialized usecase.
+func NewDeviceStateUndoUseCase(deviceStateUC *DeviceStateUseCase, getDeviceUC *TuyaGetDeviceByIDUseCase, specUC *DeviceSpecificationUseCase, controlUC *TuyaDeviceControlUseCase) *DeviceStateUndoUseCase {
+	return &DeviceStateUndoUseCase{
+		deviceStateUC: deviceStateUC,
+		getDeviceUC:   getDeviceUC,
+		specUC:        specUC,
+		controlUC:     controlUC,
+	}
+}
+
+// GetHistory returns the current state of a device and its previous states, newest first.
+//
+// param deviceID The unique ID of the device.
+// return *dtos.DeviceStateHistoryDTO The current state (null if none was saved) and the history.
+// return error An error if the states cannot be read.
+func (uc *DeviceStateUndoUseCase) GetHistory(deviceID string) (*dtos.DeviceStateHistoryDTO, error) {
+	current, err := uc.deviceStateUC.GetDeviceState(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := uc.deviceStateUC.GetStateHistory(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.DeviceStateHistoryDTO{
+		DeviceID: deviceID,
+		Current:  current,
+		History:  history,
+	}, nil
+}
+
+// Undo sends the settings of the newest previous state that differ from the current state.
+// The restored entry and everything saved after it are removed from the history, so repeated
+// calls step further back instead of redoing the undone change.
+//
+// param ctx The request context.
+// param accessToken The Tuya access token.
+// param deviceID The unique ID of the device.
+// return *dtos.UndoDeviceStateResponseDTO The restored entry and the settings sent.
+// return error ErrNoStateToUndo, an *ApprovalRequiredError when the commands are held for approval, or the command error.
+func (uc *DeviceStateUndoUseCase) Undo(ctx context.Context, accessToken, deviceID string) (*dtos.UndoDeviceStateResponseDTO, error) {
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	current, err := uc.deviceStateUC.GetDeviceState(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if current == nil {
+		return nil, ErrNoStateToUndo
+	}
+	history, err := uc.deviceStateUC.GetStateHistory(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	isIRAC := device.Category == "infrared_ac"
+	writable := make(map[string]bool)
+	if isIRAC {
+		for _, code := range irACRestoreOrder {
+			writable[code] = true
+		}
+	} else {
+		spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		for _, fn := range spec.Functions {
+			writable[fn.Code] = true
+		}
+	}
+
+	currentValues := make(map[string]interface{}, len(current.LastCommands))
+	for _, cmd := range current.LastCommands {
+		currentValues[cmd.Code] = cmd.Value
+	}
+
+	for _, previous := range history {
+		changes := changedSettings(previous.LastCommands, currentValues, writable)
+		if len(changes) == 0 {
+			continue
+		}
+
+		var sent []dtos.DeviceStateCommandDTO
+		success := true
+		if isIRAC {
+			sent, err = uc.undoIRAC(ctx, accessToken, device.ID, device.GatewayID, changes)
+		} else {
+			commands := make([]dtos.TuyaCommandDTO, 0, len(changes))
+			for _, cmd := range changes {
+				commands = append(commands, dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value})
+			}
+			success, err = uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands)
+			sent = changes
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if err := uc.deviceStateUC.DiscardStateHistorySince(deviceID, previous.UpdatedAt); err != nil {
+			utils.LogWarn("DeviceStateUndoUseCase: Failed to trim state history of %s: %v", deviceID, err)
+		}
+		return &dtos.UndoDeviceStateResponseDTO{
+			DeviceID:     deviceID,
+			RestoredFrom: previous.UpdatedAt,
+			Success:      success,
+			Commands:     sent,
+		}, nil
+	}
+	return nil, ErrNoStateToUndo
+}
+
+// undoIRAC sends the changed IR AC settings in replay order; restoring "off" only sends the power command.
+func (uc *DeviceStateUndoUseCase) undoIRAC(ctx context.Context, accessToken, remoteID, infraredID string, changes []dtos.DeviceStateCommandDTO) ([]dtos.DeviceStateCommandDTO, error) {
+	values := make(map[string]int, len(changes))
+	for _, cmd := range changes {
+		if value, ok := numericValue(cmd.Value); ok {
+			values[cmd.Code] = int(value)
+		}
+	}
+	if infraredID == "" {
+		infraredID = remoteID
+	}
+
+	var sent []dtos.DeviceStateCommandDTO
+	for _, code := range irACRestoreOrder {
+		value, ok := values[code]
+		if !ok {
+			continue
+		}
+		if _, err := uc.controlUC.SendIRACCommand(ctx, accessToken, infraredID, remoteID, code, value); err != nil {
+			return sent, err
+		}
+		sent = append(sent, dtos.DeviceStateCommandDTO{Code: code, Value: value})
+		if code == "power" && value == 0 {
+			break
+		}
+	}
+	return sent, nil
+}
+
+// changedSettings returns the writable settings of a previous state whose values differ from the current ones.
+func changedSettings(previous []dtos.DeviceStateCommandDTO, current map[string]interface{}, writable map[string]bool) []dtos.DeviceStateCommandDTO {
+	var changes []dtos.DeviceStateCommandDTO
+	for _, cmd := range previous {
+		if !writable[cmd.Code] {
+			continue
+		}
+		if value, ok := current[cmd.Code]; ok && sameStateValue(value, cmd.Value) {
+			continue
+		}
+		changes = append(changes, cmd)
+	}
+	return changes
+}
@@ -3,37 +3,62 @@ package usecases
 import (
 	"encoding/json"
 	"fmt"
+	"strconv"
+	"sync"
 	"teralux_app/domain/tuya/dtos"
 	"teralux_app/domain/tuya/entities"
-	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/tuya/repositories"
 	"teralux_app/domain/common/utils"
-	"time"
 )
 
+// defaultDeviceStateHistorySize is the number of previous states kept per device.
+const defaultDeviceStateHistorySize = 20
+
 // DeviceStateUseCase handles business logic for device state persistence.
-// It manages saving, retrieving, and cleaning up device control states in BadgerDB.
+// It manages saving, retrieving, and cleaning up device control states in the DeviceStateRepository.
+// Every save that changes a device's state keeps the replaced state in a bounded history
+// (oldest first) so changes can be undone.
 type DeviceStateUseCase struct {
-	cache *persistence.BadgerService
+	states      repositories.DeviceStateRepository
+	clock       utils.Clock
+	historySize int
+
+	// mu serializes the read-merge-write of states and histories
+	mu sync.Mutex
 }
 
 // NewDeviceStateUseCase initializes a new DeviceStateUseCase.
+// The history size is read from DEVICE_STATE_HISTORY_SIZE.
 //
-// param cache The BadgerService used for persistent state storage.
+// param states The DeviceStateRepository used for persistent state storage (nil when unavailable).
+// param clock The Clock used to timestamp saved state.
 // return *DeviceStateUseCase A pointer to the initialized usecase.
-func NewDeviceStateUseCase(cache *persistence.BadgerService) *DeviceStateUseCase {
+func NewDeviceStateUseCase(states repositories.DeviceStateRepository, clock utils.Clock) *DeviceStateUseCase {
+	historySize, err := strconv.Atoi(utils.GetConfig().DeviceStateHistorySize)
+	if err != nil || historySize < 0 {
+		historySize = defaultDeviceStateHistorySize
+	}
 	return &DeviceStateUseCase{
-		cache: cache,
+		states:      states,
+		clock:       clock,
+		historySize: historySize,
 	}
 }
 
 // SaveDeviceState saves the last control state for a device to persistent storage.
-// The state is stored with key format: "device_state:{device_id}" without TTL.
 // This function merges new commands with existing state to preserve all device parameters.
+// When the merge changes the state, the replaced state is added to the device's history.
 //
 // param deviceID The unique ID of the device.
 // param commands A list of commands representing the device's current state.
 // return error An error if the save operation fails.
 func (uc *DeviceStateUseCase) SaveDeviceState(deviceID string, commands []dtos.DeviceStateCommandDTO) error {
+	if uc.states == nil {
+		return fmt.Errorf("device state storage not initialized")
+	}
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
 	// Retrieve existing state first
 	existingState, err := uc.GetDeviceState(deviceID)
 	if err != nil {
@@ -51,12 +76,24 @@ func (uc *DeviceStateUseCase) SaveDeviceState(deviceID string, commands []dtos.D
 		utils.LogDebug("DeviceStateUseCase: Loaded %d existing commands for device %s", len(existingState.LastCommands), deviceID)
 	}
 	
+	previousValues := make(map[string]interface{}, len(commandMap))
+	for code, value := range commandMap {
+		previousValues[code] = value
+	}
+
 	// Merge/update with new commands
 	for _, cmd := range commands {
 		commandMap[cmd.Code] = cmd.Value
 		utils.LogDebug("DeviceStateUseCase: Merging command: code=%s, value=%v", cmd.Code, cmd.Value)
 	}
 
+	// Keep the replaced state so the change can be undone
+	if existingState != nil && !sameStateValue(previousValues, commandMap) {
+		if err := uc.appendHistory(existingState); err != nil {
+			utils.LogWarn("DeviceStateUseCase: Failed to record state history for device %s: %v", deviceID, err)
+		}
+	}
+
 	// Convert map back to array
 	var mergedCommands []entities.DeviceStateCommand
 	for code, value := range commandMap {
@@ -70,26 +107,15 @@ func (uc *DeviceStateUseCase) SaveDeviceState(deviceID string, commands []dtos.D
 	state := entities.DeviceState{
 		DeviceID:     deviceID,
 		LastCommands: mergedCommands,
-		UpdatedAt:    time.Now().Unix(),
-	}
-
-	// Marshal to JSON
-	jsonData, err := json.Marshal(state)
-	if err != nil {
-		utils.LogError("DeviceStateUseCase: Failed to marshal state for device %s: %v", deviceID, err)
-		return fmt.Errorf("failed to marshal device state: %w", err)
+		UpdatedAt:    uc.clock.Now().Unix(),
 	}
 
-	// Save to BadgerDB with persistent key (no TTL)
-	key := fmt.Sprintf("device_state:%s", deviceID)
-	
 	utils.LogDebug("DeviceStateUseCase: Saving merged state for device %s with %d total commands", deviceID, len(mergedCommands))
 	for i, cmd := range mergedCommands {
 		utils.LogDebug("  MergedCommand[%d]: code=%s, value=%v (type=%T)", i, cmd.Code, cmd.Value, cmd.Value)
 	}
-	utils.LogDebug("  JSON payload: %s", string(jsonData))
 	
-	if err := uc.cache.SetPersistent(key, jsonData); err != nil {
+	if err := uc.states.Save(state); err != nil {
 		utils.LogError("DeviceStateUseCase: Failed to save state for device %s: %v", deviceID, err)
 		return fmt.Errorf("failed to save device state: %w", err)
 	}
@@ -104,28 +130,22 @@ func (uc *DeviceStateUseCase) SaveDeviceState(deviceID string, commands []dtos.D
 // return *dtos.DeviceStateDTO The device state, or nil if not found.
 // return error An error if the retrieval operation fails.
 func (uc *DeviceStateUseCase) GetDeviceState(deviceID string) (*dtos.DeviceStateDTO, error) {
-	key := fmt.Sprintf("device_state:%s", deviceID)
-	
-	// Retrieve from BadgerDB
-	jsonData, err := uc.cache.Get(key)
+	if uc.states == nil {
+		return nil, nil
+	}
+
+	state, err := uc.states.Get(deviceID)
 	if err != nil {
 		utils.LogError("DeviceStateUseCase: Failed to get state for device %s: %v", deviceID, err)
 		return nil, fmt.Errorf("failed to get device state: %w", err)
 	}
 
 	// Not found
-	if jsonData == nil {
+	if state == nil {
 		utils.LogDebug("DeviceStateUseCase: No state found for device %s", deviceID)
 		return nil, nil
 	}
 
-	// Unmarshal entity
-	var state entities.DeviceState
-	if err := json.Unmarshal(jsonData, &state); err != nil {
-		utils.LogError("DeviceStateUseCase: Failed to unmarshal state for device %s: %v", deviceID, err)
-		return nil, fmt.Errorf("failed to unmarshal device state: %w", err)
-	}
-
 	// Convert to DTO
 	var commandDTOs []dtos.DeviceStateCommandDTO
 	for _, cmd := range state.LastCommands {
@@ -142,21 +162,135 @@ func (uc *DeviceStateUseCase) GetDeviceState(deviceID string) (*dtos.DeviceState
 	}
 
 	utils.LogDebug("DeviceStateUseCase: Retrieved state for device %s with %d commands", deviceID, len(commandDTOs))
-	utils.LogDebug("  Raw JSON: %s", string(jsonData))
 	for i, cmd := range commandDTOs {
 		utils.LogDebug("  RetrievedCommand[%d]: code=%s, value=%v (type=%T)", i, cmd.Code, cmd.Value, cmd.Value)
 	}
 	return stateDTO, nil
 }
 
+// GetStateHistory retrieves the previous states of a device, newest first.
+//
+// param deviceID The unique ID of the device.
+// return []dtos.DeviceStateDTO The previous states; empty if none were recorded.
+// return error An error if the retrieval operation fails.
+func (uc *DeviceStateUseCase) GetStateHistory(deviceID string) ([]dtos.DeviceStateDTO, error) {
+	history, err := uc.loadHistory(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]dtos.DeviceStateDTO, 0, len(history))
+	for i := len(history) - 1; i >= 0; i-- {
+		state := history[i]
+		commandDTOs := make([]dtos.DeviceStateCommandDTO, 0, len(state.LastCommands))
+		for _, cmd := range state.LastCommands {
+			commandDTOs = append(commandDTOs, dtos.DeviceStateCommandDTO{
+				Code:  cmd.Code,
+				Value: cmd.Value,
+			})
+		}
+		result = append(result, dtos.DeviceStateDTO{
+			DeviceID:     state.DeviceID,
+			LastCommands: commandDTOs,
+			UpdatedAt:    state.UpdatedAt,
+		})
+	}
+	return result, nil
+}
+
+// DiscardStateHistorySince removes the history entries recorded at or after the given time.
+// It is used after an undo, so the undone states and those saved while undoing are not undone again.
+//
+// param deviceID The unique ID of the device.
+// param since The Unix timestamp of the oldest entry to remove.
+// return error An error if the history cannot be updated.
+func (uc *DeviceStateUseCase) DiscardStateHistorySince(deviceID string, since int64) error {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	history, err := uc.loadHistory(deviceID)
+	if err != nil {
+		return err
+	}
+	kept := history[:0]
+	for _, state := range history {
+		if state.UpdatedAt < since {
+			kept = append(kept, state)
+		}
+	}
+	return uc.saveHistory(deviceID, kept)
+}
+
+// appendHistory adds a replaced state to the history of its device, dropping the oldest entries beyond the history size.
+func (uc *DeviceStateUseCase) appendHistory(state *dtos.DeviceStateDTO) error {
+	if uc.historySize == 0 {
+		return nil
+	}
+	history, err := uc.loadHistory(state.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	entry := entities.DeviceState{
+		DeviceID:  state.DeviceID,
+		UpdatedAt: state.UpdatedAt,
+	}
+	for _, cmd := range state.LastCommands {
+		entry.LastCommands = append(entry.LastCommands, entities.DeviceStateCommand{
+			Code:  cmd.Code,
+			Value: cmd.Value,
+		})
+	}
+	history = append(history, entry)
+	if len(history) > uc.historySize {
+		history = history[len(history)-uc.historySize:]
+	}
+	return uc.saveHistory(state.DeviceID, history)
+}
+
+// loadHistory reads the history of a device, oldest first.
+func (uc *DeviceStateUseCase) loadHistory(deviceID string) ([]entities.DeviceState, error) {
+	if uc.states == nil {
+		return nil, nil
+	}
+	history, err := uc.states.GetHistory(deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get device state history: %w", err)
+	}
+	return history, nil
+}
+
+// saveHistory writes the history of a device; an empty history removes it.
+func (uc *DeviceStateUseCase) saveHistory(deviceID string, history []entities.DeviceState) error {
+	if uc.states == nil {
+		return fmt.Errorf("device state storage not initialized")
+	}
+	if err := uc.states.SaveHistory(deviceID, history); err != nil {
+		return fmt.Errorf("failed to save device state history: %w", err)
+	}
+	return nil
+}
+
+// sameStateValue reports whether two state values, or code-to-value maps, are equal.
+// Values are compared by their JSON encoding, since stored values decode as float64 while new ones may be ints.
+func sameStateValue(a, b interface{}) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
 // CleanupOrphanedStates removes device states for devices that no longer exist.
 // This is called after fetching the device list from Tuya API.
 //
 // param validDeviceIDs A list of all currently valid device IDs from Tuya.
 // return error An error if the cleanup operation fails.
 func (uc *DeviceStateUseCase) CleanupOrphanedStates(validDeviceIDs []string) error {
-	// Get all device state keys
-	allStateKeys, err := uc.cache.GetAllKeysWithPrefix("device_state:")
+	if uc.states == nil {
+		return nil
+	}
+
+	// Get all devices with a saved state
+	storedDeviceIDs, err := uc.states.ListDeviceIDs()
 	if err != nil {
 		utils.LogError("DeviceStateUseCase: Failed to get state keys for cleanup: %v", err)
 		return fmt.Errorf("failed to get state keys: %w", err)
@@ -168,15 +302,12 @@ func (uc *DeviceStateUseCase) CleanupOrphanedStates(validDeviceIDs []string) err
 		validIDMap[id] = true
 	}
 
-	// Check each state key
+	// Check each stored device
 	deletedCount := 0
-	for _, key := range allStateKeys {
-		// Extract device ID from key "device_state:{device_id}"
-		deviceID := key[len("device_state:"):]
-		
-		// If device ID is not in valid list, delete the state
+	for _, deviceID := range storedDeviceIDs {
+		// If device ID is not in valid list, delete the state and its history
 		if !validIDMap[deviceID] {
-			if err := uc.cache.Delete(key); err != nil {
+			if err := uc.states.Delete(deviceID); err != nil {
 				utils.LogWarn("DeviceStateUseCase: Failed to delete orphaned state for device %s: %v", deviceID, err)
 				continue
 			}
@@ -0,0 +1,167 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+)
+
+// maxStatusSnapshotVersions bounds the snapshots kept in memory to answer delta requests.
+const maxStatusSnapshotVersions = 32
+
+// keyStatusCodes are the status codes included in a snapshot by default: switches, light and climate
+// settings, and common sensor readings. Codes starting with "switch" are always included.
+var keyStatusCodes = map[string]bool{
+	"power":               true,
+	"mode":                true,
+	"temp":                true,
+	"wind":                true,
+	"temp_set":            true,
+	"temp_current":        true,
+	"bright_value":        true,
+	"bright_value_v2":     true,
+	"work_mode":           true,
+	"percent_control":     true,
+	"percent_state":       true,
+	"cur_power":           true,
+	"va_temperature":      true,
+	"va_humidity":         true,
+	"humidity_value":      true,
+	"doorcontact_state":   true,
+	"pir":                 true,
+	"watersensor_state":   true,
+	"smoke_sensor_status": true,
+	"battery_percentage":  true,
+}
+
+// DeviceStatusSnapshotUseCase serves the status of all devices as a compact snapshot for dashboards that
+// refresh frequently. Each snapshot has a version; a client sending back the version it holds gets nothing
+// when nothing changed, or, when asking for a delta, only the devices that changed since.
+// Snapshots are built from the cached device list, so they cost no Tuya calls while the list is cached.
+type DeviceStatusSnapshotUseCase struct {
+	devicesUC *TuyaGetAllDevicesUseCase
+
+	mu       sync.Mutex
+	versions map[string]map[string]dtos.DeviceStatusEntryDTO
+	order    []string
+}
+
+// NewDeviceStatusSnapshotUseCase initializes a new DeviceStatusSnapshotUseCase.
+//
+// param devicesUC The usecase providing the (cached) device list.
+// return *DeviceStatusSnapshotUseCase A pointer to the initialized usecase.
+func NewDeviceStatusSnapshotUseCase(devicesUC *TuyaGetAllDevicesUseCase) *DeviceStatusSnapshotUseCase {
+	return &DeviceStatusSnapshotUseCase{
+		devicesUC: devicesUC,
+		versions:  make(map[string]map[string]dtos.DeviceStatusEntryDTO),
+	}
+}
+
+// GetSnapshot returns the status of all devices the caller may see.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param uid The Tuya User ID whose devices are listed.
+// param visible Devices the caller may see (optional, nil for all devices).
+// param codes The status codes to include (optional, empty for the key status codes).
+// param since The version the client holds (optional).
+// param delta Whether to return only the changes since the given version, when it is still known.
+// return *dtos.DeviceStatusSnapshotDTO The snapshot.
+// return bool True if the snapshot has the version the client holds, so nothing needs to be sent.
+// return error An error if the device list cannot be fetched.
+func (uc *DeviceStatusSnapshotUseCase) GetSnapshot(ctx context.Context, accessToken, uid string, visible DeviceFilter, codes []string, since string, delta bool) (*dtos.DeviceStatusSnapshotDTO, bool, error) {
+	list, err := uc.devicesUC.GetAllDevices(ctx, accessToken, uid, 0, 0, "", visible)
+	if err != nil {
+		return nil, false, err
+	}
+
+	include := func(code string) bool {
+		return strings.HasPrefix(code, "switch") || keyStatusCodes[code]
+	}
+	if len(codes) > 0 {
+		requested := make(map[string]bool, len(codes))
+		for _, code := range codes {
+			requested[code] = true
+		}
+		include = func(code string) bool { return requested[code] }
+	}
+
+	entries := make(map[string]dtos.DeviceStatusEntryDTO)
+	collectStatusEntries(list.Devices, include, entries)
+	devices := make([]dtos.DeviceStatusEntryDTO, 0, len(entries))
+	for _, entry := range entries {
+		devices = append(devices, entry)
+	}
+	sort.Slice(devices, func(i, j int) bool { return devices[i].ID < devices[j].ID })
+
+	encoded, err := json.Marshal(devices)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to encode status snapshot: %w", err)
+	}
+	version := fmt.Sprintf("%016x", utils.PayloadVersion(encoded))
+	snapshot := &dtos.DeviceStatusSnapshotDTO{Version: version, Devices: devices}
+	if since == version {
+		return snapshot, true, nil
+	}
+
+	previous := uc.remember(version, entries, since)
+	if delta && previous != nil {
+		snapshot.Delta = true
+		snapshot.Devices = []dtos.DeviceStatusEntryDTO{}
+		for _, entry := range devices {
+			if old, ok := previous[entry.ID]; !ok || !reflect.DeepEqual(old, entry) {
+				snapshot.Devices = append(snapshot.Devices, entry)
+			}
+		}
+		for id := range previous {
+			// Versions are shared between callers, so only report removals of devices the caller may see
+			if _, ok := entries[id]; !ok && (visible == nil || visible(id)) {
+				snapshot.Removed = append(snapshot.Removed, id)
+			}
+		}
+		sort.Strings(snapshot.Removed)
+	}
+	return snapshot, false, nil
+}
+
+// remember stores the entries of a version and returns the entries of the version the client holds, or nil
+// if that version is unknown. The oldest versions are forgotten first.
+func (uc *DeviceStatusSnapshotUseCase) remember(version string, entries map[string]dtos.DeviceStatusEntryDTO, since string) map[string]dtos.DeviceStatusEntryDTO {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, ok := uc.versions[version]; !ok {
+		if len(uc.order) >= maxStatusSnapshotVersions {
+			delete(uc.versions, uc.order[0])
+			uc.order = uc.order[1:]
+		}
+		uc.versions[version] = entries
+		uc.order = append(uc.order, version)
+	}
+	if since == "" {
+		return nil
+	}
+	return uc.versions[since]
+}
+
+// collectStatusEntries flattens devices (including grouped collections) into compact status entries.
+func collectStatusEntries(devices []dtos.TuyaDeviceDTO, include func(string) bool, entries map[string]dtos.DeviceStatusEntryDTO) {
+	for _, device := range devices {
+		if device.ID != "" {
+			status := make(map[string]interface{})
+			for _, item := range device.Status {
+				if include(item.Code) {
+					status[item.Code] = item.Value
+				}
+			}
+			entries[device.ID] = dtos.DeviceStatusEntryDTO{ID: device.ID, Online: device.Online, Status: status}
+		}
+		collectStatusEntries(device.Collections, include, entries)
+	}
+}
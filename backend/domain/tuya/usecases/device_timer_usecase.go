@@ -0,0 +1,418 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// ErrDeviceTimerNotFound is returned when a device has no pending timer to cancel.
+var ErrDeviceTimerNotFound = tuya_errors.NotFound("no pending timer for this device")
+
+const (
+	// DeviceTimerJobType is the job type of server-side timers.
+	DeviceTimerJobType = "device_timer"
+
+	// Timer modes reported by DeviceTimerDTO.
+	DeviceTimerModeNative = "native"
+	DeviceTimerModeJob    = "job"
+
+	// deviceTimerPrefix is the key prefix of stored timers: "device_timer:{device_id}:{switch code}".
+	deviceTimerPrefix = "device_timer:"
+	// deviceTimerRetention keeps a timer record a while past its due time, so a job retrying a failed
+	// command can still find it; stale records then expire on their own.
+	deviceTimerRetention = time.Hour
+)
+
+// timerSwitchCodes are the switch codes a timer controls when the request names none, in order of preference.
+var timerSwitchCodes = []string{"switch_1", "switch", "switch_led", "switch_led_1"}
+
+// deviceTimerPayload is the job payload of a server-side timer.
+type deviceTimerPayload struct {
+	TimerID  string `json:"timer_id"`
+	DeviceID string `json:"device_id"`
+	Code     string `json:"code"`
+	Action   string `json:"action"`
+}
+
+// DeviceTimerUseCase turns a switch of a device on or off after a delay. When the device exposes a
+// writable countdown DP for the switch (countdown_1 for switch_1) and the switch is currently in the
+// opposite state, the device counts down itself and the timer survives server downtime; a countdown
+// toggles the switch, so it cannot be used to turn off a switch that is already off. Otherwise the
+// command is sent by a delayed background job. Each switch has at most one pending timer: setting a
+// new one cancels the previous.
+type DeviceTimerUseCase struct {
+	cache       persistence.CacheStore
+	jobRunner   *job_services.JobRunnerService
+	specUC      *DeviceSpecificationUseCase
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	authUC      *TuyaAuthUseCase
+	clock       utils.Clock
+	ids         utils.IDGenerator
+}
+
+// NewDeviceTimerUseCase initializes a new DeviceTimerUseCase and registers its job type.
+//
+// param cache The CacheStore used to persist pending timers.
+// param jobRunner The JobRunnerService running server-side timers.
+// param specUC The usecase providing the device specification, to find the countdown DP.
+// param getDeviceUC The usecase reading the current switch state.
+// param controlUC The usecase sending countdown and switch commands.
+// param authUC The TuyaAuthUseCase providing the server-managed token used by the job.
+// param clock The Clock used to compute due times.
+// param ids The IDGenerator used for timer IDs.
+// return *DeviceTimerUseCase A pointer to the initialized usecase.
+func NewDeviceTimerUseCase(cache persistence.CacheStore, jobRunner *job_services.JobRunnerService, specUC *DeviceSpecificationUseCase, getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, clock utils.Clock, ids utils.IDGenerator) *DeviceTimerUseCase {
+	uc := &DeviceTimerUseCase{
+		cache:       cache,
+		jobRunner:   jobRunner,
+		specUC:      specUC,
+		getDeviceUC: getDeviceUC,
+		controlUC:   controlUC,
+		authUC:      authUC,
+		clock:       clock,
+		ids:         ids,
+	}
+	jobRunner.Register(DeviceTimerJobType, uc.runTimer)
+	return uc
+}
+
+// SetTimer schedules a switch of a device to turn on or off after a delay, replacing a pending timer of the same switch.
+//
+// param ctx The request context.
+// param accessToken The Tuya access token.
+// param deviceID The device ID.
+// param req The action, the delay and optionally the switch code.
+// return *dtos.DeviceTimerDTO The scheduled timer.
+// return error A bad request error if the device has no usable switch, or the command or storage error.
+func (uc *DeviceTimerUseCase) SetTimer(ctx context.Context, accessToken, deviceID string, req dtos.SetDeviceTimerRequestDTO) (*dtos.DeviceTimerDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("timer storage not initialized")
+	}
+
+	spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	code, err := timerSwitchCode(spec, strings.TrimSpace(req.Code))
+	if err != nil {
+		return nil, err
+	}
+
+	if existing, err := uc.loadTimer(deviceID, code); err != nil {
+		return nil, err
+	} else if existing != nil && uc.isPending(existing) {
+		if err := uc.cancelTimer(ctx, accessToken, existing); err != nil {
+			return nil, err
+		}
+	}
+
+	id, err := uc.ids.NewID(6)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate timer id: %w", err)
+	}
+	now := uc.clock.Now()
+	timer := &entities.DeviceTimer{
+		ID:           "tm-" + id,
+		DeviceID:     deviceID,
+		Code:         code,
+		Action:       req.Action,
+		DelaySeconds: req.DelaySeconds,
+		CreatedAt:    now.Unix(),
+		FiresAt:      now.Unix() + int64(req.DelaySeconds),
+	}
+
+	if countdownCode, ok := uc.nativeCountdown(ctx, accessToken, spec, deviceID, code, req); ok {
+		timer.Mode = DeviceTimerModeNative
+		timer.CountdownCode = countdownCode
+		commands := []dtos.TuyaCommandDTO{{Code: countdownCode, Value: req.DelaySeconds}}
+		if _, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands); err != nil {
+			return nil, err
+		}
+	} else {
+		timer.Mode = DeviceTimerModeJob
+		payload := deviceTimerPayload{TimerID: timer.ID, DeviceID: deviceID, Code: code, Action: req.Action}
+		job, err := uc.jobRunner.EnqueueAfter(ctx, DeviceTimerJobType, payload, 0, time.Duration(req.DelaySeconds)*time.Second)
+		if err != nil {
+			return nil, err
+		}
+		timer.JobID = job.ID
+	}
+
+	if err := uc.saveTimer(timer); err != nil {
+		return nil, err
+	}
+	utils.LogInfo("DeviceTimerUseCase: Device %s %s turns %s in %ds (%s)", deviceID, code, req.Action, req.DelaySeconds, timer.Mode)
+	result := uc.toDeviceTimerDTO(timer)
+	return &result, nil
+}
+
+// ListTimers returns the pending timers of a device ordered by switch code.
+//
+// param deviceID The device ID.
+// return []dtos.DeviceTimerDTO The pending timers.
+// return error An error if the timers cannot be listed.
+func (uc *DeviceTimerUseCase) ListTimers(deviceID string) ([]dtos.DeviceTimerDTO, error) {
+	timers, err := uc.pendingTimers(deviceID)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]dtos.DeviceTimerDTO, 0, len(timers))
+	for _, timer := range timers {
+		result = append(result, uc.toDeviceTimerDTO(timer))
+	}
+	return result, nil
+}
+
+// CancelTimers cancels the pending timers of a device. Native countdowns are reset on the device and
+// server-side jobs are cancelled.
+//
+// param ctx The request context.
+// param accessToken The Tuya access token.
+// param deviceID The device ID.
+// param code Only cancel the timer of this switch code (empty for all).
+// return []dtos.DeviceTimerDTO The cancelled timers.
+// return error ErrDeviceTimerNotFound if nothing is pending, or the command error.
+func (uc *DeviceTimerUseCase) CancelTimers(ctx context.Context, accessToken, deviceID, code string) ([]dtos.DeviceTimerDTO, error) {
+	timers, err := uc.pendingTimers(deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	code = strings.TrimSpace(code)
+	var cancelled []dtos.DeviceTimerDTO
+	for _, timer := range timers {
+		if code != "" && timer.Code != code {
+			continue
+		}
+		if err := uc.cancelTimer(ctx, accessToken, timer); err != nil {
+			return nil, err
+		}
+		cancelled = append(cancelled, uc.toDeviceTimerDTO(timer))
+	}
+	if len(cancelled) == 0 {
+		return nil, ErrDeviceTimerNotFound
+	}
+	return cancelled, nil
+}
+
+// nativeCountdown returns the countdown code to use for a timer, or false when the timer must run as a job:
+// the device has no writable countdown for the switch, the delay exceeds its range, or the switch is not
+// in the opposite state of the action.
+func (uc *DeviceTimerUseCase) nativeCountdown(ctx context.Context, accessToken string, spec *entities.TuyaDeviceSpecification, deviceID, code string, req dtos.SetDeviceTimerRequestDTO) (string, bool) {
+	fn, ok := findFunction(spec, countdownCodes(code))
+	if !ok {
+		return "", false
+	}
+	values := parseFunctionValues(fn)
+	if values.Max != nil && float64(req.DelaySeconds) > *values.Max {
+		return "", false
+	}
+
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		utils.LogWarn("DeviceTimerUseCase: Failed to read %s state, using a server-side timer: %v", deviceID, err)
+		return "", false
+	}
+	for _, s := range device.Status {
+		if s.Code != code {
+			continue
+		}
+		on, isBool := s.Value.(bool)
+		if !isBool || on == (req.Action == "on") {
+			return "", false
+		}
+		return fn.Code, true
+	}
+	return "", false
+}
+
+// cancelTimer stops a pending timer and removes its record.
+func (uc *DeviceTimerUseCase) cancelTimer(ctx context.Context, accessToken string, timer *entities.DeviceTimer) error {
+	switch timer.Mode {
+	case DeviceTimerModeNative:
+		commands := []dtos.TuyaCommandDTO{{Code: timer.CountdownCode, Value: 0}}
+		if _, err := uc.controlUC.SendCommand(ctx, accessToken, timer.DeviceID, commands); err != nil {
+			return err
+		}
+	case DeviceTimerModeJob:
+		if _, err := uc.jobRunner.Cancel(timer.JobID); err != nil && !errors.Is(err, job_services.ErrJobFinished) && !errors.Is(err, job_services.ErrJobNotFound) {
+			return err
+		}
+	}
+	if err := uc.cache.Delete(deviceTimerKey(timer.DeviceID, timer.Code)); err != nil {
+		return fmt.Errorf("failed to delete timer: %w", err)
+	}
+	utils.LogInfo("DeviceTimerUseCase: Cancelled timer %s of device %s", timer.ID, timer.DeviceID)
+	return nil
+}
+
+// runTimer is the job handler of server-side timers: it sends the switch command once the delay has passed.
+func (uc *DeviceTimerUseCase) runTimer(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	var payload deviceTimerPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return nil, job_services.Permanent(fmt.Errorf("invalid timer payload: %w", err))
+	}
+
+	// A timer replaced or cancelled while its job was already due must not fire
+	timer, err := uc.loadTimer(payload.DeviceID, payload.Code)
+	if err != nil {
+		return nil, err
+	}
+	if timer == nil || timer.ID != payload.TimerID {
+		return nil, job_services.Permanent(fmt.Errorf("timer %s is no longer pending", payload.TimerID))
+	}
+
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	commands := []dtos.TuyaCommandDTO{{Code: payload.Code, Value: payload.Action == "on"}}
+	if _, err := uc.controlUC.SendCommand(ctx, token.AccessToken, payload.DeviceID, commands); err != nil {
+		return nil, err
+	}
+
+	if err := uc.cache.Delete(deviceTimerKey(payload.DeviceID, payload.Code)); err != nil {
+		utils.LogWarn("DeviceTimerUseCase: Failed to remove fired timer %s: %v", payload.TimerID, err)
+	}
+	utils.LogInfo("DeviceTimerUseCase: Timer %s turned device %s %s %s", payload.TimerID, payload.DeviceID, payload.Code, payload.Action)
+	return uc.toDeviceTimerDTO(timer), nil
+}
+
+// pendingTimers returns the timers of a device that have not fired yet. A native timer is pending until
+// its due time; a job timer until its job finishes, which may be later when the command is retried.
+func (uc *DeviceTimerUseCase) pendingTimers(deviceID string) ([]*entities.DeviceTimer, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("timer storage not initialized")
+	}
+	keys, err := uc.cache.GetAllKeysWithPrefix(deviceTimerPrefix + deviceID + ":")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list timers: %w", err)
+	}
+	sort.Strings(keys)
+
+	var timers []*entities.DeviceTimer
+	for _, key := range keys {
+		jsonData, err := uc.cache.Get(key)
+		if err != nil || jsonData == nil {
+			continue
+		}
+		var timer entities.DeviceTimer
+		if err := json.Unmarshal(jsonData, &timer); err != nil {
+			utils.LogWarn("DeviceTimerUseCase: Skipping malformed timer %s: %v", key, err)
+			continue
+		}
+		if uc.isPending(&timer) {
+			timers = append(timers, &timer)
+		}
+	}
+	return timers, nil
+}
+
+// isPending reports whether a timer has yet to fire.
+func (uc *DeviceTimerUseCase) isPending(timer *entities.DeviceTimer) bool {
+	if timer.Mode == DeviceTimerModeJob {
+		job, err := uc.jobRunner.Get(timer.JobID)
+		return err == nil && !job.IsFinished()
+	}
+	return timer.FiresAt > uc.clock.Now().Unix()
+}
+
+// loadTimer reads the timer of a switch, or nil when there is none.
+func (uc *DeviceTimerUseCase) loadTimer(deviceID, code string) (*entities.DeviceTimer, error) {
+	jsonData, err := uc.cache.Get(deviceTimerKey(deviceID, code))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timer: %w", err)
+	}
+	if jsonData == nil {
+		return nil, nil
+	}
+	var timer entities.DeviceTimer
+	if err := json.Unmarshal(jsonData, &timer); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal timer: %w", err)
+	}
+	return &timer, nil
+}
+
+// saveTimer stores a timer until a while after its due time.
+func (uc *DeviceTimerUseCase) saveTimer(timer *entities.DeviceTimer) error {
+	jsonData, err := json.Marshal(timer)
+	if err != nil {
+		return fmt.Errorf("failed to marshal timer: %w", err)
+	}
+	ttl := time.Duration(timer.DelaySeconds)*time.Second + deviceTimerRetention
+	if err := uc.cache.Set(deviceTimerKey(timer.DeviceID, timer.Code), jsonData, ttl); err != nil {
+		return fmt.Errorf("failed to save timer: %w", err)
+	}
+	return nil
+}
+
+// toDeviceTimerDTO maps a stored timer to its DTO.
+func (uc *DeviceTimerUseCase) toDeviceTimerDTO(timer *entities.DeviceTimer) dtos.DeviceTimerDTO {
+	remaining := timer.FiresAt - uc.clock.Now().Unix()
+	if remaining < 0 {
+		remaining = 0
+	}
+	return dtos.DeviceTimerDTO{
+		ID:               timer.ID,
+		DeviceID:         timer.DeviceID,
+		Code:             timer.Code,
+		Action:           timer.Action,
+		Mode:             timer.Mode,
+		CountdownCode:    timer.CountdownCode,
+		JobID:            timer.JobID,
+		DelaySeconds:     timer.DelaySeconds,
+		RemainingSeconds: remaining,
+		CreatedAt:        timer.CreatedAt,
+		FiresAt:          timer.FiresAt,
+	}
+}
+
+// timerSwitchCode picks the switch a timer controls: the requested code, which must be a writable boolean,
+// or the first default switch code the device exposes.
+func timerSwitchCode(spec *entities.TuyaDeviceSpecification, code string) (string, error) {
+	if code == "" {
+		fn, ok := findFunction(spec, timerSwitchCodes)
+		if !ok {
+			return "", tuya_errors.BadRequest("device has no switch to set a timer on")
+		}
+		return fn.Code, nil
+	}
+	fn, ok := findFunction(spec, []string{code})
+	if !ok || !strings.EqualFold(fn.Type, "boolean") {
+		return "", tuya_errors.BadRequest("%s is not a switch of this device", code)
+	}
+	return fn.Code, nil
+}
+
+// countdownCodes returns the countdown DP candidates of a switch code: switch_2 counts down with
+// countdown_2, a single switch with countdown or countdown_1.
+func countdownCodes(code string) []string {
+	switch {
+	case code == "switch":
+		return []string{"countdown", "countdown_1"}
+	case code == "switch_led":
+		return []string{"countdown_1", "countdown"}
+	case strings.HasPrefix(code, "switch_led_"):
+		return []string{"countdown_" + strings.TrimPrefix(code, "switch_led_")}
+	case strings.HasPrefix(code, "switch_"):
+		return []string{"countdown_" + strings.TrimPrefix(code, "switch_")}
+	}
+	return nil
+}
+
+// deviceTimerKey builds the storage key of the timer of a switch.
+func deviceTimerKey(deviceID, code string) string {
+	return fmt.Sprintf("%s%s:%s", deviceTimerPrefix, deviceID, code)
+}
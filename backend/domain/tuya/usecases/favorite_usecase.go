@@ -0,0 +1,93 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// maxFavorites bounds the number of favorite devices per user.
+const maxFavorites = 100
+
+// FavoriteUseCase stores the ordered list of favorite devices of each Tuya user.
+type FavoriteUseCase struct {
+	cache persistence.CacheStore
+}
+
+// NewFavoriteUseCase initializes a new FavoriteUseCase.
+//
+// param cache The CacheStore used to persist favorites.
+// return *FavoriteUseCase A pointer to the initialized usecase.
+func NewFavoriteUseCase(cache persistence.CacheStore) *FavoriteUseCase {
+	return &FavoriteUseCase{
+		cache: cache,
+	}
+}
+
+// GetFavorites returns the favorite device IDs of a user in display order.
+//
+// param uid The Tuya User ID.
+// return []string The device IDs; empty when none are set.
+// return error An error if the favorites cannot be read.
+func (uc *FavoriteUseCase) GetFavorites(uid string) ([]string, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("favorite storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(favoritesKey(uid))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get favorites: %w", err)
+	}
+	if jsonData == nil {
+		return []string{}, nil
+	}
+	var deviceIDs []string
+	if err := json.Unmarshal(jsonData, &deviceIDs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal favorites: %w", err)
+	}
+	return deviceIDs, nil
+}
+
+// SetFavorites replaces the favorite devices of a user. Duplicates are dropped, keeping the first position.
+//
+// param uid The Tuya User ID.
+// param deviceIDs The device IDs in display order.
+// return []string The saved device IDs.
+// return error A bad request error for invalid input, or a storage error.
+func (uc *FavoriteUseCase) SetFavorites(uid string, deviceIDs []string) ([]string, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("favorite storage not initialized")
+	}
+
+	seen := make(map[string]bool)
+	favorites := make([]string, 0, len(deviceIDs))
+	for _, id := range deviceIDs {
+		if id == "" {
+			return nil, tuya_errors.BadRequest("device_ids must not contain empty values")
+		}
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		favorites = append(favorites, id)
+	}
+	if len(favorites) > maxFavorites {
+		return nil, tuya_errors.BadRequest("at most %d favorites are allowed", maxFavorites)
+	}
+
+	jsonData, err := json.Marshal(favorites)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal favorites: %w", err)
+	}
+	if err := uc.cache.SetPersistent(favoritesKey(uid), jsonData); err != nil {
+		return nil, fmt.Errorf("failed to save favorites: %w", err)
+	}
+	utils.LogInfo("FavoriteUseCase: Saved %d favorites for uid %s", len(favorites), uid)
+	return favorites, nil
+}
+
+// favoritesKey builds the storage key for the favorites of a user.
+func favoritesKey(uid string) string {
+	return fmt.Sprintf("favorites:%s", uid)
+}
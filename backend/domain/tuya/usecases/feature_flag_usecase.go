@@ -0,0 +1,303 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"sort"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// Feature flags consulted by the control usecases.
+const (
+	// FlagSwitchCodeRetry retries commands rejected with code 2008 on the legacy endpoint with "switch_N" renamed to "switchN".
+	FlagSwitchCodeRetry = "switch_code_retry"
+	// FlagIRStandardFallback falls back to standard device control when the IR API rejects an air conditioner command.
+	FlagIRStandardFallback = "ir_standard_fallback"
+	// FlagIRACFullState sends IR air conditioner commands as the whole power/mode/temp/wind state, merged with the saved state.
+	FlagIRACFullState = "ir_ac_full_state"
+)
+
+// Sources of the effective rollout of a feature flag.
+const (
+	FeatureFlagSourceDefault = "default"
+	FeatureFlagSourceConfig  = "config"
+	FeatureFlagSourceAPI     = "api"
+)
+
+// featureFlagDefinition declares a feature flag and its default rollout percentage.
+type featureFlagDefinition struct {
+	description string
+	percentage  int
+}
+
+// featureFlagDefinitions lists every feature flag. Flags guarding existing behaviour default to 100.
+var featureFlagDefinitions = map[string]featureFlagDefinition{
+	FlagSwitchCodeRetry:    {description: "Retry commands rejected with code 2008 on the legacy endpoint with switch_N renamed to switchN", percentage: 100},
+	FlagIRStandardFallback: {description: "Fall back to standard device control when the IR API rejects an air conditioner command", percentage: 100},
+	FlagIRACFullState:      {description: "Send IR air conditioner commands as the whole power/mode/temp/wind state, for models that reset unset settings", percentage: 0},
+}
+
+// ErrFeatureFlagNotFound is returned when a feature flag is not defined.
+var ErrFeatureFlagNotFound = tuya_errors.NotFound("feature flag not found")
+
+// FeatureFlagUseCase decides per device whether experimental code paths are enabled.
+// Rollouts start from the defined percentage, can be overridden with FEATURE_FLAGS, and can be changed at
+// runtime through the admin API (persisted, so they survive restarts). Percentage rollouts hash the flag
+// name with the device ID, so a device keeps its assignment as the percentage grows.
+type FeatureFlagUseCase struct {
+	cache     persistence.CacheStore
+	overrides map[string]int
+	clock     utils.Clock
+}
+
+// NewFeatureFlagUseCase initializes a new FeatureFlagUseCase from the FEATURE_FLAGS configuration.
+//
+// param cache The CacheStore used to persist rollouts set through the API.
+// param clock The Clock used to timestamp rollout changes.
+// return *FeatureFlagUseCase A pointer to the initialized usecase.
+func NewFeatureFlagUseCase(cache persistence.CacheStore, clock utils.Clock) *FeatureFlagUseCase {
+	return &FeatureFlagUseCase{
+		cache:     cache,
+		overrides: parseFeatureFlagConfig(utils.GetConfig().FeatureFlags),
+		clock:     clock,
+	}
+}
+
+// IsEnabled reports whether a feature flag is enabled for a device.
+// Unknown flags are disabled.
+//
+// param name The feature flag name.
+// param deviceID The device the code path would run for.
+// return bool True if the code path should run.
+func (uc *FeatureFlagUseCase) IsEnabled(name, deviceID string) bool {
+	flag, _, err := uc.resolve(name)
+	if err != nil {
+		return false
+	}
+	return featureFlagEnabled(flag, deviceID)
+}
+
+// ListFlags returns every feature flag with its effective rollout, ordered by name.
+//
+// return []dtos.FeatureFlagDTO The feature flags.
+func (uc *FeatureFlagUseCase) ListFlags() []dtos.FeatureFlagDTO {
+	names := make([]string, 0, len(featureFlagDefinitions))
+	for name := range featureFlagDefinitions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	flags := make([]dtos.FeatureFlagDTO, 0, len(names))
+	for _, name := range names {
+		flag, source, _ := uc.resolve(name)
+		flags = append(flags, featureFlagToDTO(flag, source))
+	}
+	return flags
+}
+
+// GetFlag returns a feature flag with its effective rollout.
+//
+// param name The feature flag name.
+// return *dtos.FeatureFlagDTO The feature flag.
+// return error ErrFeatureFlagNotFound if the flag is not defined.
+func (uc *FeatureFlagUseCase) GetFlag(name string) (*dtos.FeatureFlagDTO, error) {
+	flag, source, err := uc.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	dto := featureFlagToDTO(flag, source)
+	return &dto, nil
+}
+
+// Evaluate reports whether a feature flag is enabled for a device.
+//
+// param name The feature flag name.
+// param deviceID The device ID.
+// return *dtos.FeatureFlagEvaluationDTO The evaluation.
+// return error ErrFeatureFlagNotFound if the flag is not defined.
+func (uc *FeatureFlagUseCase) Evaluate(name, deviceID string) (*dtos.FeatureFlagEvaluationDTO, error) {
+	flag, _, err := uc.resolve(name)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.FeatureFlagEvaluationDTO{
+		Name:     name,
+		DeviceID: deviceID,
+		Enabled:  featureFlagEnabled(flag, deviceID),
+	}, nil
+}
+
+// UpdateFlag replaces the rollout of a feature flag. The change applies immediately and persists.
+//
+// param name The feature flag name.
+// param req The new rollout percentage and device lists.
+// return *dtos.FeatureFlagDTO The updated flag.
+// return error ErrFeatureFlagNotFound, or an error if the rollout cannot be saved.
+func (uc *FeatureFlagUseCase) UpdateFlag(name string, req dtos.UpdateFeatureFlagRequestDTO) (*dtos.FeatureFlagDTO, error) {
+	if _, ok := featureFlagDefinitions[name]; !ok {
+		return nil, ErrFeatureFlagNotFound
+	}
+	if uc.cache == nil {
+		return nil, fmt.Errorf("persistence not initialized")
+	}
+
+	flag := &entities.FeatureFlag{
+		Name:            name,
+		Percentage:      *req.Percentage,
+		Devices:         req.Devices,
+		ExcludedDevices: req.ExcludedDevices,
+		UpdatedAt:       uc.clock.Now().Unix(),
+	}
+	data, err := json.Marshal(flag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feature flag: %w", err)
+	}
+	if err := uc.cache.SetPersistent(featureFlagKey(name), data); err != nil {
+		return nil, fmt.Errorf("failed to save feature flag: %w", err)
+	}
+
+	utils.LogInfo("FeatureFlagUseCase: %s set to %d%% (+%d devices, -%d devices)", name, flag.Percentage, len(flag.Devices), len(flag.ExcludedDevices))
+	dto := featureFlagToDTO(flag, FeatureFlagSourceAPI)
+	return &dto, nil
+}
+
+// ResetFlag removes the rollout set through the API, restoring the configured or default rollout.
+//
+// param name The feature flag name.
+// return *dtos.FeatureFlagDTO The flag after the reset.
+// return error ErrFeatureFlagNotFound, or an error if the rollout cannot be removed.
+func (uc *FeatureFlagUseCase) ResetFlag(name string) (*dtos.FeatureFlagDTO, error) {
+	if _, ok := featureFlagDefinitions[name]; !ok {
+		return nil, ErrFeatureFlagNotFound
+	}
+	if uc.cache != nil {
+		if err := uc.cache.Delete(featureFlagKey(name)); err != nil {
+			return nil, fmt.Errorf("failed to reset feature flag: %w", err)
+		}
+	}
+
+	utils.LogInfo("FeatureFlagUseCase: %s reset", name)
+	return uc.GetFlag(name)
+}
+
+// resolve returns the effective rollout of a flag: API override, then FEATURE_FLAGS, then the default.
+func (uc *FeatureFlagUseCase) resolve(name string) (*entities.FeatureFlag, string, error) {
+	definition, ok := featureFlagDefinitions[name]
+	if !ok {
+		return nil, "", ErrFeatureFlagNotFound
+	}
+
+	if uc.cache != nil {
+		if data, err := uc.cache.Get(featureFlagKey(name)); err == nil && data != nil {
+			var flag entities.FeatureFlag
+			if err := json.Unmarshal(data, &flag); err == nil {
+				return &flag, FeatureFlagSourceAPI, nil
+			}
+			utils.LogWarn("FeatureFlagUseCase: Ignoring malformed rollout for %s", name)
+		}
+	}
+	if percentage, ok := uc.overrides[name]; ok {
+		return &entities.FeatureFlag{Name: name, Percentage: percentage}, FeatureFlagSourceConfig, nil
+	}
+	return &entities.FeatureFlag{Name: name, Percentage: definition.percentage}, FeatureFlagSourceDefault, nil
+}
+
+// featureFlagEnabled evaluates a rollout for a device.
+func featureFlagEnabled(flag *entities.FeatureFlag, deviceID string) bool {
+	for _, id := range flag.Devices {
+		if id == deviceID {
+			return true
+		}
+	}
+	for _, id := range flag.ExcludedDevices {
+		if id == deviceID {
+			return false
+		}
+	}
+	if flag.Percentage >= 100 {
+		return true
+	}
+	if flag.Percentage <= 0 {
+		return false
+	}
+	return featureFlagBucket(flag.Name, deviceID) < flag.Percentage
+}
+
+// featureFlagBucket places a device in one of 100 buckets for a flag.
+func featureFlagBucket(name, deviceID string) int {
+	h := fnv.New32a()
+	h.Write([]byte(name + ":" + deviceID))
+	return int(h.Sum32() % 100)
+}
+
+// parseFeatureFlagConfig parses "name=percentage" pairs separated by commas. "on" and "off" mean 100 and 0.
+func parseFeatureFlagConfig(value string) map[string]int {
+	overrides := make(map[string]int)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, raw, found := strings.Cut(pair, "=")
+		name = strings.TrimSpace(name)
+		if !found {
+			utils.LogWarn("FeatureFlagUseCase: Ignoring FEATURE_FLAGS entry %q without a value", pair)
+			continue
+		}
+		if _, ok := featureFlagDefinitions[name]; !ok {
+			utils.LogWarn("FeatureFlagUseCase: Ignoring unknown feature flag %q", name)
+			continue
+		}
+
+		raw = strings.TrimSuffix(strings.TrimSpace(raw), "%")
+		var percentage int
+		switch raw {
+		case "on", "true":
+			percentage = 100
+		case "off", "false":
+			percentage = 0
+		default:
+			parsed, err := strconv.Atoi(raw)
+			if err != nil || parsed < 0 || parsed > 100 {
+				utils.LogWarn("FeatureFlagUseCase: Ignoring invalid rollout %q for %s", raw, name)
+				continue
+			}
+			percentage = parsed
+		}
+		overrides[name] = percentage
+	}
+	return overrides
+}
+
+// featureFlagKey builds the persistent key of a rollout set through the API.
+func featureFlagKey(name string) string {
+	return "feature_flag:" + name
+}
+
+// featureFlagToDTO converts a rollout to its DTO.
+func featureFlagToDTO(flag *entities.FeatureFlag, source string) dtos.FeatureFlagDTO {
+	devices := flag.Devices
+	if devices == nil {
+		devices = []string{}
+	}
+	excluded := flag.ExcludedDevices
+	if excluded == nil {
+		excluded = []string{}
+	}
+	return dtos.FeatureFlagDTO{
+		Name:            flag.Name,
+		Description:     featureFlagDefinitions[flag.Name].description,
+		Percentage:      flag.Percentage,
+		Devices:         devices,
+		ExcludedDevices: excluded,
+		Source:          source,
+		UpdatedAt:       flag.UpdatedAt,
+	}
+}
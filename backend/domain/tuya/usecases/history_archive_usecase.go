@@ -0,0 +1,354 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/infrastructure/storage"
+	"teralux_app/domain/common/utils"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	defaultArchiveInterval       = 24 * time.Hour
+	defaultArchiveLocalRetention = 7 * 24 * time.Hour
+
+	sensorHistoryArchivePrefix = "sensor-history/"
+	auditLogArchivePrefix      = "audit-log/"
+)
+
+// HistoryArchiveJobType is the job type of archive exports.
+const HistoryArchiveJobType = "history_archive"
+
+// archiveObject is the content of one archive file, accumulated before upload.
+// keys are the references of the exported local entries (CacheStore keys or audit log references).
+type archiveObject struct {
+	rows [][]string
+	keys []string
+}
+
+// HistoryArchiveUseCase exports sensor history and audit logs to S3-compatible storage as CSV.
+// Only whole days (in the deployment TIMEZONE) older than ARCHIVE_LOCAL_RETENTION are exported, one object per day (per device for
+// sensor history), and local entries are deleted once their object is uploaded, so the local store stays
+// small. Re-running after a partial failure rewrites the same objects. The bucket lifecycle configuration
+// moves and expires old archives. Exports run as background jobs, both on demand and at ARCHIVE_INTERVAL.
+type HistoryArchiveUseCase struct {
+	cache          persistence.CacheStore
+	jobRunner      *job_services.JobRunnerService
+	auditUC        *AuditLogUseCase
+	client         *storage.S3Client
+	interval       time.Duration
+	retention      time.Duration
+	lifecycleRules []storage.LifecycleRule
+	clock          utils.Clock
+	mu             sync.Mutex
+	startOnce      sync.Once
+	workers        utils.WorkerGroup
+}
+
+// NewHistoryArchiveUseCase initializes a new HistoryArchiveUseCase from the archive configuration and
+// registers its job type.
+//
+// param cache The CacheStore holding sensor history.
+// param jobRunner The JobRunnerService executing exports.
+// param auditUC The usecase providing the audit log.
+// param clock The Clock used to compute the export cutoff.
+// return *HistoryArchiveUseCase A pointer to the initialized usecase.
+func NewHistoryArchiveUseCase(cache persistence.CacheStore, jobRunner *job_services.JobRunnerService, auditUC *AuditLogUseCase, clock utils.Clock) *HistoryArchiveUseCase {
+	config := utils.GetConfig()
+
+	interval, err := time.ParseDuration(config.ArchiveInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultArchiveInterval
+	}
+	retention, err := time.ParseDuration(config.ArchiveLocalRetention)
+	if err != nil || retention <= 0 {
+		retention = defaultArchiveLocalRetention
+	}
+
+	var client *storage.S3Client
+	if config.ArchiveS3Bucket != "" && config.ArchiveS3Endpoint != "" {
+		client = storage.NewS3Client(config.ArchiveS3Endpoint, config.ArchiveS3Region, config.ArchiveS3Bucket, config.ArchiveS3AccessKey, config.ArchiveS3SecretKey)
+	}
+
+	transitionDays, _ := strconv.Atoi(config.ArchiveTransitionDays)
+	expirationDays, _ := strconv.Atoi(config.ArchiveExpirationDays)
+	var rules []storage.LifecycleRule
+	if transitionDays > 0 || expirationDays > 0 {
+		for _, prefix := range []string{sensorHistoryArchivePrefix, auditLogArchivePrefix} {
+			rules = append(rules, storage.LifecycleRule{
+				ID:             strings.TrimSuffix(prefix, "/"),
+				Prefix:         prefix,
+				TransitionDays: transitionDays,
+				StorageClass:   config.ArchiveStorageClass,
+				ExpirationDays: expirationDays,
+			})
+		}
+	}
+
+	uc := &HistoryArchiveUseCase{
+		cache:          cache,
+		jobRunner:      jobRunner,
+		auditUC:        auditUC,
+		client:         client,
+		interval:       interval,
+		retention:      retention,
+		lifecycleRules: rules,
+		clock:          clock,
+	}
+	jobRunner.Register(HistoryArchiveJobType, uc.runArchive)
+	return uc
+}
+
+// Start applies the bucket lifecycle configuration and queues an export at ARCHIVE_INTERVAL.
+// It does nothing unless ARCHIVE_S3_ENDPOINT and ARCHIVE_S3_BUCKET are configured.
+func (uc *HistoryArchiveUseCase) Start() {
+	if uc.client == nil || uc.cache == nil {
+		return
+	}
+
+	uc.startOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			if len(uc.lifecycleRules) > 0 {
+				if err := uc.client.PutBucketLifecycle(context.Background(), uc.lifecycleRules); err != nil {
+					utils.LogWarn("HistoryArchiveUseCase: Failed to apply bucket lifecycle: %v", err)
+				}
+			}
+
+			ticker := time.NewTicker(uc.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					if _, err := uc.jobRunner.Enqueue(context.Background(), HistoryArchiveJobType, nil, 0); err != nil {
+						utils.LogError("HistoryArchiveUseCase: Failed to queue export: %v", err)
+					}
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("HistoryArchiveUseCase: Exporting every %s, keeping %s locally", uc.interval, uc.retention)
+	})
+}
+
+// Stop ends the export schedule. Exports in progress are jobs, stopped with the job runner.
+func (uc *HistoryArchiveUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// StartRun queues an export immediately.
+//
+// param ctx The request context carrying the caller.
+// return *dtos.ArchiveRunStartedDTO The job running the export.
+// return error An unavailable error if archiving is not configured, or an error if the job cannot be queued.
+func (uc *HistoryArchiveUseCase) StartRun(ctx context.Context) (*dtos.ArchiveRunStartedDTO, error) {
+	if uc.client == nil {
+		return nil, tuya_errors.Unavailable("archive storage not configured").WithHint("set ARCHIVE_S3_ENDPOINT and ARCHIVE_S3_BUCKET")
+	}
+	job, err := uc.jobRunner.Enqueue(ctx, HistoryArchiveJobType, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.ArchiveRunStartedDTO{JobID: job.ID, Status: job.Status}, nil
+}
+
+// runArchive is the job handler running one export. A failed upload is retried; objects uploaded before the
+// failure are rewritten by the next attempt.
+func (uc *HistoryArchiveUseCase) runArchive(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	if uc.client == nil || uc.cache == nil {
+		return nil, job_services.Permanent(fmt.Errorf("archive storage not configured"))
+	}
+	return uc.run(ctx)
+}
+
+// run exports all whole days older than the local retention and deletes the exported entries.
+// It returns the first export failure; entries of objects uploaded before the failure are still deleted.
+func (uc *HistoryArchiveUseCase) run(ctx context.Context) (*dtos.ArchiveRunResultDTO, error) {
+	if uc.client == nil {
+		return nil, fmt.Errorf("archive storage not configured")
+	}
+	if uc.cache == nil {
+		return nil, fmt.Errorf("persistence not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	cutoff := utils.StartOfDay(uc.clock.Now().Add(-uc.retention), utils.Location())
+	result := &dtos.ArchiveRunResultDTO{Cutoff: cutoff.Unix(), Objects: []string{}}
+
+	sensorObjects, err := uc.collectSensorHistory(cutoff)
+	if err != nil {
+		return nil, err
+	}
+	count, err := uc.export(ctx, sensorObjects, sensorHistoryHeader, uc.deleteKeys, result)
+	result.SensorHistoryBuckets = count
+	if err != nil {
+		return result, err
+	}
+
+	auditObjects, err := uc.collectAuditLog(cutoff)
+	if err != nil {
+		return result, err
+	}
+	count, err = uc.export(ctx, auditObjects, auditLogHeader, uc.auditUC.Remove, result)
+	result.AuditLogEntries = count
+	if err != nil {
+		return result, err
+	}
+
+	if len(result.Objects) > 0 {
+		utils.LogInfo("HistoryArchiveUseCase: Exported %d sensor buckets and %d audit entries in %d objects",
+			result.SensorHistoryBuckets, result.AuditLogEntries, len(result.Objects))
+	}
+	return result, nil
+}
+
+var sensorHistoryHeader = []string{"device_id", "hour", "code", "count", "min", "max", "avg", "last"}
+
+var auditLogHeader = []string{"id", "timestamp", "action", "device_id", "success", "error", "detail"}
+
+// collectSensorHistory groups the sensor buckets older than the cutoff by day and device.
+func (uc *HistoryArchiveUseCase) collectSensorHistory(cutoff time.Time) (map[string]*archiveObject, error) {
+	keys, err := uc.cache.GetAllKeysWithPrefix(sensorHistoryPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sensor history: %w", err)
+	}
+
+	objects := make(map[string]*archiveObject)
+	for _, key := range keys {
+		sep := strings.LastIndex(key, ":")
+		hour, err := strconv.ParseInt(key[sep+1:], 10, 64)
+		if err != nil || hour >= cutoff.Unix() {
+			continue
+		}
+		data, err := uc.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var bucket entities.SensorHistoryBucket
+		if err := json.Unmarshal(data, &bucket); err != nil {
+			utils.LogWarn("HistoryArchiveUseCase: Skipping malformed bucket %s: %v", key, err)
+			continue
+		}
+
+		name := fmt.Sprintf("%s%s/%s.csv", sensorHistoryArchivePrefix, time.Unix(hour, 0).In(cutoff.Location()).Format("2006/01/02"), bucket.DeviceID)
+		object := objects[name]
+		if object == nil {
+			object = &archiveObject{}
+			objects[name] = object
+		}
+		object.keys = append(object.keys, key)
+
+		codes := make([]string, 0, len(bucket.Codes))
+		for code := range bucket.Codes {
+			codes = append(codes, code)
+		}
+		sort.Strings(codes)
+		for _, code := range codes {
+			rollup := bucket.Codes[code]
+			avg := 0.0
+			if rollup.Count > 0 {
+				avg = rollup.Sum / float64(rollup.Count)
+			}
+			object.rows = append(object.rows, []string{
+				bucket.DeviceID,
+				time.Unix(bucket.Hour, 0).In(cutoff.Location()).Format(time.RFC3339),
+				code,
+				strconv.FormatInt(rollup.Count, 10),
+				formatFloat(rollup.Min),
+				formatFloat(rollup.Max),
+				formatFloat(avg),
+				formatFloat(rollup.Last),
+			})
+		}
+	}
+	return objects, nil
+}
+
+// collectAuditLog groups the audit entries older than the cutoff by day.
+func (uc *HistoryArchiveUseCase) collectAuditLog(cutoff time.Time) (map[string]*archiveObject, error) {
+	records, err := uc.auditUC.List(time.Time{}, cutoff, "")
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make(map[string]*archiveObject)
+	for _, record := range records {
+		entry := record.Entry
+
+		timestamp := time.Unix(entry.Timestamp, 0).In(cutoff.Location())
+		name := fmt.Sprintf("%s%s.csv", auditLogArchivePrefix, timestamp.Format("2006/01/02"))
+		object := objects[name]
+		if object == nil {
+			object = &archiveObject{}
+			objects[name] = object
+		}
+		object.keys = append(object.keys, record.Ref)
+		object.rows = append(object.rows, []string{
+			entry.ID,
+			timestamp.Format(time.RFC3339),
+			entry.Action,
+			entry.DeviceID,
+			strconv.FormatBool(entry.Success),
+			entry.Error,
+			entry.Detail,
+		})
+	}
+	return objects, nil
+}
+
+// export uploads the objects as CSV and deletes their local entries with remove.
+// It returns the number of local entries exported.
+func (uc *HistoryArchiveUseCase) export(ctx context.Context, objects map[string]*archiveObject, header []string, remove func(keys []string) error, result *dtos.ArchiveRunResultDTO) (int, error) {
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	exported := 0
+	for _, name := range names {
+		object := objects[name]
+
+		var buf bytes.Buffer
+		writer := csv.NewWriter(&buf)
+		writer.Write(header)
+		writer.WriteAll(object.rows)
+		if err := writer.Error(); err != nil {
+			return exported, fmt.Errorf("failed to encode %s: %w", name, err)
+		}
+
+		if err := uc.client.PutObject(ctx, name, "text/csv", buf.Bytes()); err != nil {
+			return exported, fmt.Errorf("failed to upload %s: %w", name, err)
+		}
+		if err := remove(object.keys); err != nil {
+			utils.LogWarn("HistoryArchiveUseCase: Failed to delete exported entries of %s: %v", name, err)
+		}
+		exported += len(object.keys)
+		result.Objects = append(result.Objects, name)
+	}
+	return exported, nil
+}
+
+// deleteKeys deletes exported sensor history buckets.
+func (uc *HistoryArchiveUseCase) deleteKeys(keys []string) error {
+	return uc.cache.DeleteBatch(keys)
+}
+
+// formatFloat formats a value with the shortest exact representation.
+func formatFloat(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
@@ -0,0 +1,224 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// House modes.
+const (
+	HouseModeHome    = "home"
+	HouseModeAway    = "away"
+	HouseModeSleep   = "sleep"
+	HouseModeHoliday = "holiday"
+)
+
+// houseModeKey holds the persisted house mode.
+const houseModeKey = "house_mode"
+
+// HouseModeListener is notified after the house mode changed.
+type HouseModeListener func(previous, current string)
+
+// HouseModeUseCase keeps the global house mode. Switching modes notifies listeners (automation rules with
+// house_mode conditions, the sensor poller) and realtime subscribers, so clients can adjust how they alert.
+type HouseModeUseCase struct {
+	cache         persistence.CacheStore
+	realtimeHub   *realtime_services.RealtimeHubService
+	pollIntervals map[string]time.Duration
+	clock         utils.Clock
+
+	mu        sync.Mutex
+	current   entities.HouseMode
+	listeners []HouseModeListener
+}
+
+// NewHouseModeUseCase initializes a new HouseModeUseCase, restoring the persisted mode.
+//
+// param cache The CacheStore used to persist the mode.
+// param realtimeHub The hub used to notify realtime subscribers of mode changes (optional).
+// param clock The Clock used to timestamp mode changes.
+// return *HouseModeUseCase A pointer to the initialized usecase.
+func NewHouseModeUseCase(cache persistence.CacheStore, realtimeHub *realtime_services.RealtimeHubService, clock utils.Clock) *HouseModeUseCase {
+	uc := &HouseModeUseCase{
+		cache:         cache,
+		realtimeHub:   realtimeHub,
+		pollIntervals: parseHouseModePollIntervals(utils.GetConfig().HouseModePollIntervals),
+		clock:         clock,
+		current:       entities.HouseMode{Mode: HouseModeHome},
+	}
+	uc.load()
+	return uc
+}
+
+// OnChange registers a listener called after every mode change.
+//
+// param listener The function to notify.
+func (uc *HouseModeUseCase) OnChange(listener HouseModeListener) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.listeners = append(uc.listeners, listener)
+}
+
+// Mode returns the current house mode.
+//
+// return string The mode; "home" until another mode is set.
+func (uc *HouseModeUseCase) Mode() string {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.current.Mode
+}
+
+// PollInterval returns the sensor poll interval configured for a mode in HOUSE_MODE_POLL_INTERVALS.
+//
+// param mode The house mode.
+// return time.Duration The interval, or zero if the mode uses SENSOR_POLL_INTERVAL.
+func (uc *HouseModeUseCase) PollInterval(mode string) time.Duration {
+	return uc.pollIntervals[mode]
+}
+
+// GetMode returns the current house mode.
+//
+// return *dtos.HouseModeDTO The mode, when it was set and the poll interval it applies.
+func (uc *HouseModeUseCase) GetMode() *dtos.HouseModeDTO {
+	uc.mu.Lock()
+	current := uc.current
+	uc.mu.Unlock()
+	return uc.toDTO(current)
+}
+
+// SetMode switches the house mode and notifies listeners. Setting the current mode again changes nothing.
+//
+// param mode The new mode (home, away, sleep or holiday).
+// return *dtos.HouseModeDTO The mode after the change.
+// return error A bad request error for unknown modes, or an error if the mode cannot be saved.
+func (uc *HouseModeUseCase) SetMode(mode string) (*dtos.HouseModeDTO, error) {
+	if !IsHouseMode(mode) {
+		return nil, tuya_errors.BadRequest("unsupported house mode %q (use home, away, sleep or holiday)", mode)
+	}
+
+	uc.mu.Lock()
+	previous := uc.current
+	if previous.Mode == mode {
+		uc.mu.Unlock()
+		return uc.toDTO(previous), nil
+	}
+	next := entities.HouseMode{Mode: mode, PreviousMode: previous.Mode, ChangedAt: uc.clock.Now().Unix()}
+	if err := uc.save(next); err != nil {
+		uc.mu.Unlock()
+		return nil, err
+	}
+	uc.current = next
+	listeners := append([]HouseModeListener(nil), uc.listeners...)
+	uc.mu.Unlock()
+
+	utils.LogInfo("HouseModeUseCase: Mode changed from %s to %s", previous.Mode, mode)
+	for _, listener := range listeners {
+		listener(previous.Mode, mode)
+	}
+	uc.publish(next)
+	return uc.toDTO(next), nil
+}
+
+// IsHouseMode reports whether a value is a known house mode.
+//
+// param mode The value to check.
+// return bool True for home, away, sleep and holiday.
+func IsHouseMode(mode string) bool {
+	switch mode {
+	case HouseModeHome, HouseModeAway, HouseModeSleep, HouseModeHoliday:
+		return true
+	default:
+		return false
+	}
+}
+
+// publish notifies realtime subscribers of a mode change.
+func (uc *HouseModeUseCase) publish(mode entities.HouseMode) {
+	if uc.realtimeHub == nil {
+		return
+	}
+	uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+		Type: "house_mode",
+		Status: []realtime_dtos.DeviceEventStatusDTO{
+			{Code: "mode", Value: mode.Mode},
+			{Code: "previous_mode", Value: mode.PreviousMode},
+		},
+		Timestamp: mode.ChangedAt,
+	})
+}
+
+// load restores the persisted mode.
+func (uc *HouseModeUseCase) load() {
+	if uc.cache == nil {
+		return
+	}
+	jsonData, err := uc.cache.Get(houseModeKey)
+	if err != nil || jsonData == nil {
+		return
+	}
+	var mode entities.HouseMode
+	if err := json.Unmarshal(jsonData, &mode); err != nil || !IsHouseMode(mode.Mode) {
+		utils.LogWarn("HouseModeUseCase: Ignoring malformed house mode")
+		return
+	}
+	uc.current = mode
+}
+
+// save persists the mode.
+func (uc *HouseModeUseCase) save(mode entities.HouseMode) error {
+	if uc.cache == nil {
+		return fmt.Errorf("house mode storage not initialized")
+	}
+	jsonData, err := json.Marshal(mode)
+	if err != nil {
+		return fmt.Errorf("failed to marshal house mode: %w", err)
+	}
+	if err := uc.cache.SetPersistent(houseModeKey, jsonData); err != nil {
+		return fmt.Errorf("failed to save house mode: %w", err)
+	}
+	return nil
+}
+
+// toDTO converts a mode into its DTO.
+func (uc *HouseModeUseCase) toDTO(mode entities.HouseMode) *dtos.HouseModeDTO {
+	pollInterval := "default"
+	if interval := uc.pollIntervals[mode.Mode]; interval > 0 {
+		pollInterval = interval.String()
+	}
+	return &dtos.HouseModeDTO{
+		Mode:         mode.Mode,
+		PreviousMode: mode.PreviousMode,
+		ChangedAt:    mode.ChangedAt,
+		PollInterval: pollInterval,
+	}
+}
+
+// parseHouseModePollIntervals parses HOUSE_MODE_POLL_INTERVALS ("away=10s,sleep=2m"). Invalid entries are skipped.
+func parseHouseModePollIntervals(value string) map[string]time.Duration {
+	intervals := make(map[string]time.Duration)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		mode, raw, _ := strings.Cut(pair, "=")
+		mode = strings.TrimSpace(mode)
+		interval, err := time.ParseDuration(strings.TrimSpace(raw))
+		if !IsHouseMode(mode) || err != nil || interval <= 0 {
+			utils.LogWarn("HouseModeUseCase: Ignoring invalid HOUSE_MODE_POLL_INTERVALS entry %q", pair)
+			continue
+		}
+		intervals[mode] = interval
+	}
+	return intervals
+}
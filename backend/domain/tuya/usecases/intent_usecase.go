@@ -0,0 +1,585 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"unicode"
+)
+
+// Actions an intent can resolve to.
+const (
+	IntentActionTurnOn  = "turn_on"
+	IntentActionTurnOff = "turn_off"
+	IntentActionOpen    = "open"
+	IntentActionClose   = "close"
+	IntentActionSet     = "set"
+)
+
+// ErrIntentNoMatch is returned when a command names no known device or room.
+var ErrIntentNoMatch = tuya_errors.NotFound("no device or room matches the command")
+
+// intentTypeWords narrow a command to kinds of devices, e.g. "lights" in "turn off meeting room lights".
+// Plurals are matched by their singular form.
+var intentTypeWords = map[string][]string{
+	"light":   lightCategories,
+	"lamp":    lightCategories,
+	"fan":     fanCategories,
+	"curtain": curtainCategories,
+	"blind":   curtainCategories,
+	"shade":   curtainCategories,
+	"plug":    plugCategories,
+	"socket":  plugCategories,
+	"outlet":  plugCategories,
+}
+
+// Categories of the device kinds that are not defined by other usecases.
+var (
+	curtainCategories = []string{"cl"}
+	plugCategories    = []string{"cz", "pc"}
+)
+
+// DP code candidates used by intents, in order of preference.
+var (
+	intentPowerCodes    = []string{"switch_led", "switch", "fan_switch", "switch_fan"}
+	intentBrightCodes   = []string{"bright_value_v2", "bright_value", "bright_value_1"}
+	intentCurtainCodes  = []string{"control"}
+	intentPositionCodes = []string{"percent_control", "position"}
+	// intentGangCode matches the switches of multi-gang devices, which are all switched when no channel is named.
+	intentGangCode = regexp.MustCompile(`^switch_(led_)?\d+$`)
+)
+
+// intentFillerWords carry no target information and are dropped before names are matched.
+var intentFillerWords = map[string]bool{
+	"turn": true, "switch": true, "set": true, "dim": true, "make": true, "put": true, "please": true,
+	"the": true, "a": true, "an": true, "all": true, "every": true, "everything": true, "my": true, "our": true,
+	"to": true, "at": true, "in": true, "of": true, "and": true, "for": true, "brightness": true, "level": true,
+	"position": true, "percent": true, "%": true,
+}
+
+// intentGenericRoomWords may be left out when naming a room ("meeting lights" for "Meeting Room").
+var intentGenericRoomWords = map[string]bool{"room": true}
+
+// parsedIntent is a command split into its action and the words naming its targets.
+type parsedIntent struct {
+	action     string
+	value      *int
+	words      []string
+	everything bool
+}
+
+// intentUnsupportedError reports that a device cannot perform the requested action.
+type intentUnsupportedError string
+
+// Error returns the reason.
+func (e intentUnsupportedError) Error() string {
+	return string(e)
+}
+
+// intentTarget is a matched device, or one channel of a multi-gang switch.
+type intentTarget struct {
+	device  dtos.TuyaDeviceDTO
+	channel *dtos.DeviceChannelDTO
+}
+
+// IntentUseCase executes short typed commands ("turn off meeting room lights", "set desk lamp to 40%")
+// for search-bar style control UIs. Rooms, devices and named switch channels are matched by fuzzy name,
+// so small typos and plurals still resolve; type words (lights, fans, curtains, plugs) narrow the match.
+type IntentUseCase struct {
+	getAllDevicesUC *TuyaGetAllDevicesUseCase
+	roomUC          *RoomUseCase
+	categoryUC      *TuyaCategoryControlUseCase
+	controlUC       *TuyaDeviceControlUseCase
+}
+
+// NewIntentUseCase initializes a new IntentUseCase.
+//
+// param getAllDevicesUC The usecase providing the devices to match.
+// param roomUC The usecase providing the rooms to match (optional).
+// param categoryUC The usecase providing device specifications.
+// param controlUC The usecase sending the resolved commands.
+// return *IntentUseCase A pointer to the initialized usecase.
+func NewIntentUseCase(getAllDevicesUC *TuyaGetAllDevicesUseCase, roomUC *RoomUseCase, categoryUC *TuyaCategoryControlUseCase, controlUC *TuyaDeviceControlUseCase) *IntentUseCase {
+	return &IntentUseCase{
+		getAllDevicesUC: getAllDevicesUC,
+		roomUC:          roomUC,
+		categoryUC:      categoryUC,
+		controlUC:       controlUC,
+	}
+}
+
+// Execute resolves a typed command to devices and sends the matching commands.
+// A device that cannot perform the action is reported with an error and does not stop the others.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param uid The Tuya User ID whose devices are matched.
+// param req The command text; with DryRun set nothing is sent.
+// param visible Devices the caller may control (optional, nil for all devices).
+// return *dtos.IntentResultDTO How the command was understood and the outcome per device.
+// return error A bad request error if no action is recognized, or ErrIntentNoMatch.
+func (uc *IntentUseCase) Execute(ctx context.Context, accessToken, uid string, req dtos.IntentRequestDTO, visible DeviceFilter) (*dtos.IntentResultDTO, error) {
+	parsed, err := parseIntent(req.Text)
+	if err != nil {
+		return nil, err
+	}
+
+	room, targets, broad, err := uc.resolve(ctx, accessToken, uid, parsed, visible)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &dtos.IntentResultDTO{
+		Text:    req.Text,
+		Action:  parsed.action,
+		Value:   parsed.value,
+		DryRun:  req.DryRun,
+		Devices: make([]dtos.IntentDeviceResultDTO, 0, len(targets)),
+	}
+	if room != nil {
+		result.RoomID = room.ID
+		result.RoomName = room.Name
+	}
+
+	failed := 0
+	for _, target := range targets {
+		deviceResult := dtos.IntentDeviceResultDTO{DeviceID: target.device.ID, Name: intentDeviceName(target.device)}
+		if target.channel != nil {
+			deviceResult.Channel = target.channel.Code
+			if target.channel.Name != "" {
+				deviceResult.Name += " - " + target.channel.Name
+			}
+		}
+
+		commands, err := uc.commandsFor(ctx, accessToken, target, parsed)
+		var unsupported intentUnsupportedError
+		if broad && errors.As(err, &unsupported) {
+			// "close everything" only concerns the devices that can be closed
+			continue
+		}
+		deviceResult.Commands = commands
+		switch {
+		case err != nil:
+			deviceResult.Error = err.Error()
+		case req.DryRun:
+			deviceResult.Success = true
+		default:
+			if _, err := uc.controlUC.SendCommand(ctx, accessToken, target.device.ID, commands); err != nil {
+				deviceResult.Error = err.Error()
+			} else {
+				deviceResult.Success = true
+			}
+		}
+		if !deviceResult.Success {
+			failed++
+		}
+		result.Devices = append(result.Devices, deviceResult)
+	}
+
+	if len(result.Devices) == 0 {
+		return nil, fmt.Errorf("%w: none of the matched devices can %s", ErrIntentNoMatch, strings.ReplaceAll(parsed.action, "_", " "))
+	}
+
+	result.Summary = intentSummary(result, failed)
+	utils.LogInfo("IntentUseCase: %q -> %s", req.Text, result.Summary)
+	return result, nil
+}
+
+// resolve finds the room and devices a command refers to. The match is broad when no device was named,
+// e.g. "turn off meeting room" or "close all curtains".
+func (uc *IntentUseCase) resolve(ctx context.Context, accessToken, uid string, parsed parsedIntent, visible DeviceFilter) (*dtos.RoomDTO, []intentTarget, bool, error) {
+	response, err := uc.getAllDevicesUC.GetAllDevices(ctx, accessToken, uid, 0, 0, "", visible)
+	if err != nil {
+		return nil, nil, false, err
+	}
+	var rooms []dtos.RoomDTO
+	if uc.roomUC != nil {
+		if list, err := uc.roomUC.ListRooms(); err == nil {
+			rooms = FilterRooms(list, visible)
+		} else if !errors.Is(err, ErrRoomsUnavailable) {
+			return nil, nil, false, err
+		}
+	}
+
+	// Type words narrow the devices instead of naming them
+	var words, typeWords []string
+	var kinds []string
+	for _, word := range parsed.words {
+		if categories, ok := intentTypeWords[singularWord(word)]; ok {
+			kinds = append(kinds, categories...)
+			typeWords = append(typeWords, singularWord(word))
+			continue
+		}
+		words = append(words, word)
+	}
+
+	var room *dtos.RoomDTO
+	bestMatched := 0
+	var roomWords []string
+	for i := range rooms {
+		matched, remaining, ok := matchIntentName(words, rooms[i].Name, intentGenericRoomWords)
+		if ok && matched > bestMatched {
+			room, bestMatched, roomWords = &rooms[i], matched, remaining
+		}
+	}
+	if room != nil {
+		words = words[:0]
+		for _, word := range roomWords {
+			if !intentGenericRoomWords[word] {
+				words = append(words, word)
+			}
+		}
+	}
+
+	var candidates []dtos.TuyaDeviceDTO
+	for _, device := range response.Devices {
+		if visible != nil && !visible(device.ID) {
+			continue
+		}
+		if room != nil && !containsString(room.DeviceIDs, device.ID) {
+			continue
+		}
+		if len(kinds) > 0 && !containsString(kinds, device.Category) && !nameContainsAny(intentDeviceName(device), typeWords) {
+			continue
+		}
+		candidates = append(candidates, device)
+	}
+
+	if len(words) == 0 {
+		if room == nil && len(kinds) == 0 && !parsed.everything {
+			return nil, nil, false, fmt.Errorf("%w: say which device or room to control", ErrIntentNoMatch)
+		}
+		if len(candidates) == 0 {
+			return nil, nil, false, fmt.Errorf("%w: no matching devices found", ErrIntentNoMatch)
+		}
+		targets := make([]intentTarget, 0, len(candidates))
+		for _, device := range candidates {
+			targets = append(targets, intentTarget{device: device})
+		}
+		return room, targets, true, nil
+	}
+
+	// Score devices and named channels by the number of words their names match
+	best := 0
+	var targets []intentTarget
+	consider := func(target intentTarget, score int) {
+		if score == 0 || score < best {
+			return
+		}
+		if score > best {
+			best, targets = score, nil
+		}
+		targets = append(targets, target)
+	}
+	for _, device := range candidates {
+		deviceScore := countIntentMatches(words, intentDeviceName(device))
+		if device.CustomName != "" && device.CustomName != device.Name {
+			deviceScore = max(deviceScore, countIntentMatches(words, device.Name))
+		}
+		channelScore := 0
+		var channels []intentTarget
+		for i := range device.Channels {
+			if device.Channels[i].Name == "" {
+				continue
+			}
+			score := countIntentMatches(words, device.Channels[i].Name)
+			if score > channelScore {
+				channelScore, channels = score, nil
+			}
+			if score == channelScore && score > 0 {
+				channels = append(channels, intentTarget{device: device, channel: &device.Channels[i]})
+			}
+		}
+		if channelScore > deviceScore {
+			for _, channel := range channels {
+				consider(channel, channelScore)
+			}
+			continue
+		}
+		consider(intentTarget{device: device}, deviceScore)
+	}
+
+	if len(targets) == 0 || best*2 < len(words) {
+		return nil, nil, false, fmt.Errorf("%w: nothing is named %q", ErrIntentNoMatch, strings.Join(words, " "))
+	}
+	return room, targets, false, nil
+}
+
+// commandsFor translates the action into the DP commands of one target.
+func (uc *IntentUseCase) commandsFor(ctx context.Context, accessToken string, target intentTarget, parsed parsedIntent) ([]dtos.TuyaCommandDTO, error) {
+	if target.channel != nil {
+		if parsed.action != IntentActionTurnOn && parsed.action != IntentActionTurnOff {
+			return nil, intentUnsupportedError("a switch channel can only be turned on or off")
+		}
+		return []dtos.TuyaCommandDTO{{Code: target.channel.Code, Value: parsed.action == IntentActionTurnOn}}, nil
+	}
+
+	spec, err := uc.categoryUC.getSpecification(ctx, accessToken, target.device.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch parsed.action {
+	case IntentActionTurnOn, IntentActionTurnOff:
+		commands := intentPowerCommands(spec, parsed.action == IntentActionTurnOn)
+		if len(commands) == 0 {
+			return nil, intentUnsupportedError("device cannot be turned on or off")
+		}
+		return commands, nil
+	case IntentActionOpen, IntentActionClose:
+		fn, ok := findFunction(spec, intentCurtainCodes)
+		if !ok || !containsString(parseFunctionValues(fn).Range, parsed.action) {
+			return nil, intentUnsupportedError("device cannot be opened or closed")
+		}
+		return []dtos.TuyaCommandDTO{{Code: fn.Code, Value: parsed.action}}, nil
+	default:
+		if fn, ok := findFunction(spec, intentBrightCodes); ok {
+			commands := intentPowerCommands(spec, true)
+			return append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: scalePercent(max(*parsed.value, 1), parseFunctionValues(fn))}), nil
+		}
+		if fn, ok := findFunction(spec, intentPositionCodes); ok {
+			return []dtos.TuyaCommandDTO{{Code: fn.Code, Value: scalePercent(*parsed.value, parseFunctionValues(fn))}}, nil
+		}
+		return nil, intentUnsupportedError("device has no brightness or position to set")
+	}
+}
+
+// intentPowerCommands returns the commands switching a device: its main switch, or every gang of a multi-gang switch.
+func intentPowerCommands(spec *entities.TuyaDeviceSpecification, on bool) []dtos.TuyaCommandDTO {
+	if fn, ok := findFunction(spec, intentPowerCodes); ok {
+		return []dtos.TuyaCommandDTO{{Code: fn.Code, Value: on}}
+	}
+	var commands []dtos.TuyaCommandDTO
+	for _, fn := range spec.Functions {
+		if intentGangCode.MatchString(fn.Code) {
+			commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: on})
+		}
+	}
+	return commands
+}
+
+// parseIntent splits a command into its action and the words naming its targets.
+func parseIntent(text string) (parsedIntent, error) {
+	tokens := intentTokens(text)
+	var parsed parsedIntent
+	var on, off, open, shut bool
+
+	for i, token := range tokens {
+		switch token {
+		case "on":
+			on = true
+			continue
+		case "off":
+			off = true
+			continue
+		case "open":
+			open = true
+			continue
+		case "close", "shut":
+			shut = true
+			continue
+		case "everything":
+			parsed.everything = true
+		}
+		if number, err := strconv.Atoi(token); err == nil && parsed.value == nil {
+			previous, next := "", ""
+			if i > 0 {
+				previous = tokens[i-1]
+			}
+			if i+1 < len(tokens) {
+				next = tokens[i+1]
+			}
+			// "50%", "50 percent", "to 50" and "at 50" are levels; other numbers belong to names ("lamp 2")
+			if next == "%" || next == "percent" || previous == "to" || previous == "at" {
+				if number > 100 {
+					return parsed, tuya_errors.BadRequest("level must be between 0 and 100 percent")
+				}
+				parsed.value = &number
+				continue
+			}
+		}
+		if !intentFillerWords[token] {
+			parsed.words = append(parsed.words, token)
+		}
+	}
+
+	switch {
+	case parsed.value != nil:
+		parsed.action = IntentActionSet
+	case on && off, open && shut:
+		return parsed, tuya_errors.BadRequest("the command asks for opposite actions")
+	case off:
+		parsed.action = IntentActionTurnOff
+	case on:
+		parsed.action = IntentActionTurnOn
+	case open:
+		parsed.action = IntentActionOpen
+	case shut:
+		parsed.action = IntentActionClose
+	default:
+		return parsed, tuya_errors.BadRequest(`no action recognized in %q (try "turn on", "turn off", "open", "close" or "set ... to 50%%")`, text)
+	}
+	return parsed, nil
+}
+
+// intentTokens lower-cases text and splits it into words, numbers and percent signs.
+func intentTokens(text string) []string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case r == '%':
+			b.WriteString(" % ")
+		default:
+			b.WriteRune(' ')
+		}
+	}
+	return strings.Fields(b.String())
+}
+
+// matchIntentName checks whether every word of a name occurs in words (optional words may be missing),
+// returning the number of matched words and the words left over.
+func matchIntentName(words []string, name string, optional map[string]bool) (int, []string, bool) {
+	used := make([]bool, len(words))
+	matched := 0
+	for _, part := range intentTokens(name) {
+		found := false
+		for i, word := range words {
+			if !used[i] && similarWords(word, part) {
+				used[i], found = true, true
+				matched++
+				break
+			}
+		}
+		if !found && !optional[part] {
+			return 0, nil, false
+		}
+	}
+	remaining := make([]string, 0, len(words))
+	for i, word := range words {
+		if !used[i] {
+			remaining = append(remaining, word)
+		}
+	}
+	return matched, remaining, matched > 0
+}
+
+// countIntentMatches returns how many words occur in a name.
+func countIntentMatches(words []string, name string) int {
+	parts := intentTokens(name)
+	count := 0
+	for _, word := range words {
+		for _, part := range parts {
+			if similarWords(word, part) {
+				count++
+				break
+			}
+		}
+	}
+	return count
+}
+
+// nameContainsAny reports whether a name contains one of the words, e.g. a plug named "Desk Lamp" for "lamp".
+func nameContainsAny(name string, words []string) bool {
+	for _, part := range intentTokens(name) {
+		for _, word := range words {
+			if similarWords(part, word) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// similarWords compares words leniently: plurals match, and longer words tolerate a typo or two.
+func similarWords(a, b string) bool {
+	a, b = singularWord(a), singularWord(b)
+	if a == b {
+		return true
+	}
+	shorter := min(len(a), len(b))
+	switch {
+	case shorter >= 8:
+		return editDistance(a, b) <= 2
+	case shorter >= 4:
+		return editDistance(a, b) <= 1
+	}
+	return false
+}
+
+// singularWord strips a plural "s" from words longer than three letters.
+func singularWord(word string) string {
+	if len(word) > 3 && strings.HasSuffix(word, "s") && !strings.HasSuffix(word, "ss") {
+		return word[:len(word)-1]
+	}
+	return word
+}
+
+// editDistance returns the Levenshtein distance between two words.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	previous := make([]int, len(br)+1)
+	current := make([]int, len(br)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		current[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(br)]
+}
+
+// intentDeviceName returns the name users know a device by.
+func intentDeviceName(device dtos.TuyaDeviceDTO) string {
+	if device.CustomName != "" {
+		return device.CustomName
+	}
+	return device.Name
+}
+
+// intentSummary describes what a command did, e.g. "Turned off 3 devices in Meeting Room".
+func intentSummary(result *dtos.IntentResultDTO, failed int) string {
+	verbs := map[string][2]string{
+		IntentActionTurnOn:  {"Turned on", "Would turn on"},
+		IntentActionTurnOff: {"Turned off", "Would turn off"},
+		IntentActionOpen:    {"Opened", "Would open"},
+		IntentActionClose:   {"Closed", "Would close"},
+		IntentActionSet:     {"Set", "Would set"},
+	}
+	verb := verbs[result.Action][0]
+	if result.DryRun {
+		verb = verbs[result.Action][1]
+	}
+
+	target := fmt.Sprintf("%d devices", len(result.Devices))
+	if len(result.Devices) == 1 {
+		target = result.Devices[0].Name
+	}
+	summary := verb + " " + target
+	if result.RoomName != "" {
+		summary += " in " + result.RoomName
+	}
+	if result.Value != nil {
+		summary += fmt.Sprintf(" to %d%%", *result.Value)
+	}
+	if failed > 0 {
+		summary += fmt.Sprintf(" (%d of %d failed)", failed, len(result.Devices))
+	}
+	return summary
+}
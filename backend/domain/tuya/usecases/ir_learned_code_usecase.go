@@ -0,0 +1,264 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+)
+
+// ErrIRLearnedCodeNotFound is returned when an IR hub has no learned code with the requested name.
+var ErrIRLearnedCodeNotFound = tuya_errors.NotFound("learned code not found")
+
+// irLearnedCodePrefix is the key prefix of saved codes: "ir_learned_code:{infrared_id}:{lowercase name}".
+const irLearnedCodePrefix = "ir_learned_code:"
+
+// IRLearnedCodeUseCase saves raw IR codes captured in learning mode under user-defined names and replays
+// them through the hub. Unlike SaveLearnedKey, the codes are kept by Teralux rather than added to a Tuya
+// custom remote, so appliances without any remote index can be driven by name. Names are matched
+// case-insensitively, like device presets.
+type IRLearnedCodeUseCase struct {
+	service    *services.TuyaDeviceService
+	learningUC *TuyaIRLearningUseCase
+	cache      persistence.CacheStore
+	auditLogUC *AuditLogUseCase
+	cooldowns  *CommandCooldownUseCase
+	clock      utils.Clock
+}
+
+// NewIRLearnedCodeUseCase initializes a new IRLearnedCodeUseCase.
+//
+// param service The TuyaDeviceService used to send codes.
+// param learningUC The usecase reading the code captured in learning mode.
+// param cache The CacheStore used to persist codes.
+// param auditLogUC The usecase recording sent codes (optional).
+// param cooldowns The CommandCooldownUseCase spacing out codes sent to IR hubs that drop rapid sequences (optional).
+// param clock The Clock used to timestamp codes.
+// return *IRLearnedCodeUseCase A pointer to the initialized usecase.
+func NewIRLearnedCodeUseCase(service *services.TuyaDeviceService, learningUC *TuyaIRLearningUseCase, cache persistence.CacheStore, auditLogUC *AuditLogUseCase, cooldowns *CommandCooldownUseCase, clock utils.Clock) *IRLearnedCodeUseCase {
+	return &IRLearnedCodeUseCase{
+		service:    service,
+		learningUC: learningUC,
+		cache:      cache,
+		auditLogUC: auditLogUC,
+		cooldowns:  cooldowns,
+		clock:      clock,
+	}
+}
+
+// ListCodes returns the learned codes of an IR hub ordered by name.
+//
+// param infraredID The ID of the IR hub.
+// return []dtos.IRNamedCodeDTO The learned codes.
+// return error An error if the codes cannot be listed.
+func (uc *IRLearnedCodeUseCase) ListCodes(infraredID string) ([]dtos.IRNamedCodeDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("learned code storage not initialized")
+	}
+
+	keys, err := uc.cache.GetAllKeysWithPrefix(irLearnedCodePrefix + infraredID + ":")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list learned codes: %w", err)
+	}
+	sort.Strings(keys)
+
+	codes := make([]dtos.IRNamedCodeDTO, 0, len(keys))
+	for _, key := range keys {
+		jsonData, err := uc.cache.Get(key)
+		if err != nil || jsonData == nil {
+			continue
+		}
+		var code entities.IRLearnedCode
+		if err := json.Unmarshal(jsonData, &code); err != nil {
+			utils.LogWarn("IRLearnedCodeUseCase: Skipping malformed code %s: %v", key, err)
+			continue
+		}
+		codes = append(codes, toIRNamedCodeDTO(&code))
+	}
+	return codes, nil
+}
+
+// SaveCode saves a raw code under a name, replacing a code with the same name in any case. Without a code
+// in the request, the code the hub captured since the given learning time is saved and learning mode is
+// stopped.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param infraredID The ID of the IR hub.
+// param req The name, and the code or the learning time.
+// return *dtos.IRNamedCodeDTO The saved code.
+// return bool True if the code was created, false if an existing one was replaced.
+// return error A bad request error for an invalid request, a conflict while no code was captured yet, or a storage error.
+func (uc *IRLearnedCodeUseCase) SaveCode(ctx context.Context, accessToken, infraredID string, req dtos.SaveIRNamedCodeRequestDTO) (*dtos.IRNamedCodeDTO, bool, error) {
+	if uc.cache == nil {
+		return nil, false, fmt.Errorf("learned code storage not initialized")
+	}
+	name, err := normalizeStoredName("name", req.Name)
+	if err != nil {
+		return nil, false, err
+	}
+
+	rawCode := req.Code
+	if rawCode == "" {
+		if req.LearningTime <= 0 {
+			return nil, false, tuya_errors.BadRequest("code or learning_time is required")
+		}
+		learned, err := uc.learningUC.GetLearnedCode(ctx, accessToken, infraredID, req.LearningTime)
+		if err != nil {
+			return nil, false, err
+		}
+		if !learned.Learned {
+			return nil, false, tuya_errors.Conflict("no code learned yet").WithHint("press the button on the physical remote while the hub is in learning mode, then retry")
+		}
+		rawCode = learned.Code
+		if err := uc.learningUC.StopLearning(ctx, accessToken, infraredID); err != nil {
+			utils.LogWarn("IRLearnedCodeUseCase: Failed to stop learning mode on %s: %v", infraredID, err)
+		}
+	}
+
+	now := uc.clock.Now().Unix()
+	code := &entities.IRLearnedCode{
+		InfraredID: infraredID,
+		Name:       name,
+		Code:       rawCode,
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	created := true
+	if existing, err := uc.loadCode(infraredID, name); err == nil {
+		code.CreatedAt = existing.CreatedAt
+		created = false
+	} else if !errors.Is(err, ErrIRLearnedCodeNotFound) {
+		return nil, false, err
+	}
+
+	jsonData, err := json.Marshal(code)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to marshal learned code: %w", err)
+	}
+	if err := uc.cache.SetPersistent(irLearnedCodeKey(infraredID, name), jsonData); err != nil {
+		return nil, false, fmt.Errorf("failed to save learned code: %w", err)
+	}
+
+	utils.LogInfo("IRLearnedCodeUseCase: Saved code %q on IR hub %s", name, infraredID)
+	result := toIRNamedCodeDTO(code)
+	return &result, created, nil
+}
+
+// DeleteCode removes a learned code of an IR hub.
+//
+// param infraredID The ID of the IR hub.
+// param name The code name (any case).
+// return error ErrIRLearnedCodeNotFound if the code does not exist, or a storage error.
+func (uc *IRLearnedCodeUseCase) DeleteCode(infraredID, name string) error {
+	if _, err := uc.loadCode(infraredID, name); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(irLearnedCodeKey(infraredID, name)); err != nil {
+		return fmt.Errorf("failed to delete learned code: %w", err)
+	}
+	utils.LogInfo("IRLearnedCodeUseCase: Deleted code %q of IR hub %s", name, infraredID)
+	return nil
+}
+
+// SendCode replays a learned code through the IR hub, once the cooldown of the hub passed.
+//
+// Tuya API Documentation (Send Learning Code):
+// URL: /v1.0/infrareds/{infrared_id}/learning-codes
+// Method: POST
+// Body: {"code": "..."}
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param infraredID The ID of the IR hub.
+// param name The code name (any case).
+// return *dtos.SendIRNamedCodeResponseDTO The sent code and the outcome.
+// return error ErrIRLearnedCodeNotFound, or the API error.
+func (uc *IRLearnedCodeUseCase) SendCode(ctx context.Context, accessToken, infraredID, name string) (*dtos.SendIRNamedCodeResponseDTO, error) {
+	code, err := uc.loadCode(infraredID, name)
+	if err != nil {
+		return nil, err
+	}
+
+	var success bool
+	err = uc.cooldowns.Throttle(ctx, infraredID, func() error {
+		var sendErr error
+		success, sendErr = uc.sendCode(ctx, accessToken, infraredID, code.Code)
+		return sendErr
+	})
+	if uc.auditLogUC != nil {
+		if auditErr := uc.auditLogUC.Record(AuditActionIRLearnedCode, infraredID, map[string]string{"name": code.Name}, err); auditErr != nil {
+			utils.LogWarn("Failed to record audit entry for %s: %v", infraredID, auditErr)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("IRLearnedCodeUseCase: Sent code %q through IR hub %s", code.Name, infraredID)
+	return &dtos.SendIRNamedCodeResponseDTO{
+		InfraredID: infraredID,
+		Name:       code.Name,
+		Success:    success,
+	}, nil
+}
+
+// sendCode posts a raw code to the hub.
+func (uc *IRLearnedCodeUseCase) sendCode(ctx context.Context, accessToken, infraredID, rawCode string) (bool, error) {
+	jsonBody, err := json.Marshal(entities.TuyaIRSendCodeRequest{Code: rawCode})
+	if err != nil {
+		return false, fmt.Errorf("failed to marshal learned code: %w", err)
+	}
+	urlPath := fmt.Sprintf("/v1.0/infrareds/%s/learning-codes", infraredID)
+
+	resp, err := uc.service.SendIRCommand(ctx, urlPath, accessToken, jsonBody)
+	if err != nil {
+		return false, err
+	}
+	if !resp.Success {
+		return false, tuya_errors.FromTuya("failed to send learned code", resp.Code, resp.Msg)
+	}
+	return resp.Result, nil
+}
+
+// loadCode reads a learned code from storage.
+func (uc *IRLearnedCodeUseCase) loadCode(infraredID, name string) (*entities.IRLearnedCode, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("learned code storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(irLearnedCodeKey(infraredID, name))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get learned code: %w", err)
+	}
+	if jsonData == nil {
+		return nil, ErrIRLearnedCodeNotFound
+	}
+	var code entities.IRLearnedCode
+	if err := json.Unmarshal(jsonData, &code); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal learned code: %w", err)
+	}
+	return &code, nil
+}
+
+// irLearnedCodeKey builds the storage key of a learned code; names are matched case-insensitively.
+func irLearnedCodeKey(infraredID, name string) string {
+	return fmt.Sprintf("%s%s:%s", irLearnedCodePrefix, infraredID, storedNameKey(name))
+}
+
+// toIRNamedCodeDTO maps a stored code to its DTO.
+func toIRNamedCodeDTO(code *entities.IRLearnedCode) dtos.IRNamedCodeDTO {
+	return dtos.IRNamedCodeDTO{
+		InfraredID: code.InfraredID,
+		Name:       code.Name,
+		Code:       code.Code,
+		CreatedAt:  code.CreatedAt,
+		UpdatedAt:  code.UpdatedAt,
+	}
+}
@@ -0,0 +1,685 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+var (
+	// ErrLightGroupNotFound is returned when a light group does not exist.
+	ErrLightGroupNotFound = tuya_errors.NotFound("light group not found")
+
+	// ErrLightPresetNotFound is returned when a preset does not exist in a light group.
+	ErrLightPresetNotFound = tuya_errors.NotFound("light preset not found")
+)
+
+// lightCategories are the Tuya categories accepted in light groups.
+var lightCategories = []string{"dj", "dd", "xdd", "fwd", "dc", "tgq", "gyd"}
+
+// DP code candidates for light capabilities, in order of preference.
+var (
+	lightPowerCodes      = []string{"switch_led", "switch_led_1", "switch_1"}
+	lightModeCodes       = []string{"work_mode"}
+	lightBrightCodes     = []string{"bright_value_v2", "bright_value", "bright_value_1"}
+	lightTempCodes       = []string{"temp_value_v2", "temp_value"}
+	lightColourCodes     = []string{"colour_data_v2", "colour_data"}
+	lightTransitionCodes = []string{"gradient_time", "transition_time", "fade_time"}
+)
+
+// builtInLightPresets are available in every light group. A custom preset with the same name overrides them.
+var builtInLightPresets = []entities.LightPreset{
+	{Name: "warm_evening", Settings: entities.LightSettings{Brightness: 40, Temperature: intPtr(10)}, TransitionMs: 3000},
+	{Name: "focus", Settings: entities.LightSettings{Brightness: 100, Temperature: intPtr(80)}, TransitionMs: 1000},
+	{Name: "party", Settings: entities.LightSettings{Brightness: 80, Colour: &entities.LightColour{Hue: 300, Saturation: 100}}},
+}
+
+// LightGroupUseCase manages light groups and their colour scene presets.
+// Presets are stored as normalized settings and translated to each light's DP codes when applied.
+type LightGroupUseCase struct {
+	cache       persistence.CacheStore
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	categoryUC  *TuyaCategoryControlUseCase
+	clock       utils.Clock
+	ids         utils.IDGenerator
+}
+
+// NewLightGroupUseCase initializes a new LightGroupUseCase.
+//
+// param cache The CacheStore used to persist light groups.
+// param getDeviceUC The usecase used to read the current state of lights when capturing presets.
+// param controlUC The usecase used to send commands to lights.
+// param categoryUC The usecase providing cached device specifications.
+// param clock The Clock used to timestamp new groups.
+// param ids The IDGenerator used for light group IDs.
+// return *LightGroupUseCase A pointer to the initialized usecase.
+func NewLightGroupUseCase(cache persistence.CacheStore, getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, categoryUC *TuyaCategoryControlUseCase, clock utils.Clock, ids utils.IDGenerator) *LightGroupUseCase {
+	return &LightGroupUseCase{
+		cache:       cache,
+		getDeviceUC: getDeviceUC,
+		controlUC:   controlUC,
+		categoryUC:  categoryUC,
+		clock:       clock,
+		ids:         ids,
+	}
+}
+
+// ListGroups returns all light groups ordered by creation time.
+//
+// return []dtos.LightGroupDTO The light groups.
+// return error An error if the groups cannot be read.
+func (uc *LightGroupUseCase) ListGroups() ([]dtos.LightGroupDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("light group storage not initialized")
+	}
+
+	keys, err := uc.cache.GetAllKeysWithPrefix("light_group:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list light groups: %w", err)
+	}
+
+	groups := make([]dtos.LightGroupDTO, 0, len(keys))
+	for _, key := range keys {
+		group, err := uc.loadGroup(strings.TrimPrefix(key, "light_group:"))
+		if err != nil {
+			utils.LogWarn("LightGroupUseCase: Skipping unreadable group %s: %v", key, err)
+			continue
+		}
+		groups = append(groups, toLightGroupDTO(group))
+	}
+
+	sort.Slice(groups, func(i, j int) bool {
+		return groups[i].CreatedAt < groups[j].CreatedAt
+	})
+	return groups, nil
+}
+
+// GetGroup returns a light group with its presets.
+//
+// param groupID The light group ID.
+// return *dtos.LightGroupDTO The light group.
+// return error ErrLightGroupNotFound if the group does not exist.
+func (uc *LightGroupUseCase) GetGroup(groupID string) (*dtos.LightGroupDTO, error) {
+	group, err := uc.loadGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	groupDTO := toLightGroupDTO(group)
+	return &groupDTO, nil
+}
+
+// CreateGroup creates a light group after checking that every device is a light the caller may access.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param req The group name and device IDs.
+// param visible The devices the caller may access (nil allows all).
+// return *dtos.LightGroupDTO The created group.
+// return error A bad request error for invalid input, or a forbidden error if the caller may not access a device.
+func (uc *LightGroupUseCase) CreateGroup(ctx context.Context, accessToken string, req dtos.CreateLightGroupRequestDTO, visible DeviceFilter) (*dtos.LightGroupDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("light group storage not initialized")
+	}
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, tuya_errors.BadRequest("name is required")
+	}
+
+	seen := make(map[string]bool)
+	deviceIDs := make([]string, 0, len(req.DeviceIDs))
+	for _, deviceID := range req.DeviceIDs {
+		if deviceID == "" || seen[deviceID] {
+			continue
+		}
+		seen[deviceID] = true
+		if err := authorizeDevices(visible, deviceID); err != nil {
+			return nil, err
+		}
+
+		spec, err := uc.categoryUC.getSpecification(ctx, accessToken, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		if !containsString(lightCategories, spec.Category) {
+			return nil, tuya_errors.BadRequest("device %s is category %s, not a light", deviceID, spec.Category)
+		}
+		deviceIDs = append(deviceIDs, deviceID)
+	}
+	if len(deviceIDs) == 0 {
+		return nil, tuya_errors.BadRequest("device_ids must contain at least one device")
+	}
+
+	randomID, err := uc.ids.NewID(6)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate light group id: %w", err)
+	}
+
+	group := &entities.LightGroup{
+		ID:        fmt.Sprintf("lg-%s", randomID),
+		Name:      name,
+		DeviceIDs: deviceIDs,
+		Presets:   []entities.LightPreset{},
+		CreatedAt: uc.clock.Now().Unix(),
+	}
+	if err := uc.saveGroup(group); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("LightGroupUseCase: Created group %s (%s) with %d lights", group.ID, group.Name, len(deviceIDs))
+	groupDTO := toLightGroupDTO(group)
+	return &groupDTO, nil
+}
+
+// DeleteGroup removes a light group and its custom presets.
+//
+// param groupID The light group ID.
+// return error ErrLightGroupNotFound if the group does not exist.
+func (uc *LightGroupUseCase) DeleteGroup(groupID string) error {
+	if _, err := uc.loadGroup(groupID); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(lightGroupKey(groupID)); err != nil {
+		return fmt.Errorf("failed to delete light group: %w", err)
+	}
+	utils.LogInfo("LightGroupUseCase: Deleted group %s", groupID)
+	return nil
+}
+
+// SavePreset creates or replaces a custom preset from explicit settings.
+//
+// param groupID The light group ID.
+// param name The preset name.
+// param req The preset settings.
+// return *dtos.LightGroupDTO The updated group.
+// return error A bad request error for invalid input, or ErrLightGroupNotFound.
+func (uc *LightGroupUseCase) SavePreset(groupID, name string, req dtos.SaveLightPresetRequestDTO) (*dtos.LightGroupDTO, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, tuya_errors.BadRequest("preset name is required")
+	}
+
+	group, err := uc.loadGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	upsertLightPreset(group, entities.LightPreset{
+		Name:         name,
+		Settings:     toLightSettings(req.Settings),
+		TransitionMs: req.TransitionMs,
+	})
+	if err := uc.saveGroup(group); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("LightGroupUseCase: Saved preset %s for group %s", name, groupID)
+	groupDTO := toLightGroupDTO(group)
+	return &groupDTO, nil
+}
+
+// CapturePreset stores the current brightness and colour of every light in the group as a custom preset.
+// Lights that cannot be read are skipped; at least one light must be captured. The caller must have access to
+// every light in the group.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param groupID The light group ID.
+// param name The preset name.
+// param visible The devices the caller may access (nil allows all).
+// return *dtos.LightGroupDTO The updated group.
+// return error A bad request error if nothing could be captured, a forbidden error if the caller may not access
+// a light, or ErrLightGroupNotFound.
+func (uc *LightGroupUseCase) CapturePreset(ctx context.Context, accessToken, groupID, name string, visible DeviceFilter) (*dtos.LightGroupDTO, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, tuya_errors.BadRequest("preset name is required")
+	}
+
+	group, err := uc.loadGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeDevices(visible, group.DeviceIDs...); err != nil {
+		return nil, err
+	}
+
+	devices := make(map[string]entities.LightSettings)
+	for _, deviceID := range group.DeviceIDs {
+		settings, err := uc.captureSettings(ctx, accessToken, deviceID)
+		if err != nil {
+			utils.LogWarn("LightGroupUseCase: Failed to capture %s for preset %s: %v", deviceID, name, err)
+			continue
+		}
+		devices[deviceID] = *settings
+	}
+	if len(devices) == 0 {
+		return nil, tuya_errors.BadRequest("no lights in group %s could be captured", groupID)
+	}
+
+	// The first captured light doubles as the group-wide default for lights added later.
+	var fallback entities.LightSettings
+	for _, deviceID := range group.DeviceIDs {
+		if settings, ok := devices[deviceID]; ok {
+			fallback = settings
+			break
+		}
+	}
+
+	upsertLightPreset(group, entities.LightPreset{
+		Name:     name,
+		Settings: fallback,
+		Devices:  devices,
+	})
+	if err := uc.saveGroup(group); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("LightGroupUseCase: Captured preset %s for group %s from %d lights", name, groupID, len(devices))
+	groupDTO := toLightGroupDTO(group)
+	return &groupDTO, nil
+}
+
+// DeletePreset removes a custom preset. Built-in presets cannot be deleted, but deleting a custom
+// preset that overrides one restores the built-in version.
+//
+// param groupID The light group ID.
+// param name The preset name.
+// return error ErrLightGroupNotFound, ErrLightPresetNotFound, or a bad request error for built-in presets.
+func (uc *LightGroupUseCase) DeletePreset(groupID, name string) error {
+	group, err := uc.loadGroup(groupID)
+	if err != nil {
+		return err
+	}
+
+	for i, preset := range group.Presets {
+		if preset.Name == name {
+			group.Presets = append(group.Presets[:i], group.Presets[i+1:]...)
+			return uc.saveGroup(group)
+		}
+	}
+
+	for _, preset := range builtInLightPresets {
+		if preset.Name == name {
+			return tuya_errors.BadRequest("built-in preset %s cannot be deleted", name)
+		}
+	}
+	return ErrLightPresetNotFound
+}
+
+// ApplyPreset applies a preset to every light in the group.
+// Lights exposing a transition DP fade to the new settings; others switch immediately.
+// A failure on one light does not stop the others. The caller must have access to every light in the group.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param groupID The light group ID.
+// param name The preset name.
+// param transitionMs Optional override of the preset's transition time in milliseconds.
+// param visible The devices the caller may access (nil allows all).
+// return *dtos.ApplyLightPresetResponseDTO The outcome per light.
+// return error ErrLightGroupNotFound, ErrLightPresetNotFound, or a forbidden error if the caller may not access a light.
+func (uc *LightGroupUseCase) ApplyPreset(ctx context.Context, accessToken, groupID, name string, transitionMs *int, visible DeviceFilter) (*dtos.ApplyLightPresetResponseDTO, error) {
+	group, err := uc.loadGroup(groupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeDevices(visible, group.DeviceIDs...); err != nil {
+		return nil, err
+	}
+
+	preset, ok := findLightPreset(group, name)
+	if !ok {
+		return nil, ErrLightPresetNotFound
+	}
+	transition := preset.TransitionMs
+	if transitionMs != nil {
+		transition = *transitionMs
+	}
+
+	result := &dtos.ApplyLightPresetResponseDTO{
+		GroupID: groupID,
+		Preset:  preset.Name,
+		Success: true,
+		Devices: make([]dtos.LightPresetDeviceResultDTO, 0, len(group.DeviceIDs)),
+	}
+	for _, deviceID := range group.DeviceIDs {
+		settings := preset.Settings
+		if captured, ok := preset.Devices[deviceID]; ok {
+			settings = captured
+		}
+
+		deviceResult := uc.applySettings(ctx, accessToken, deviceID, settings, transition)
+		if !deviceResult.Success {
+			result.Success = false
+		}
+		result.Devices = append(result.Devices, deviceResult)
+	}
+
+	utils.LogInfo("LightGroupUseCase: Applied preset %s to group %s (success: %t)", preset.Name, groupID, result.Success)
+	return result, nil
+}
+
+// applySettings translates settings into the DP codes of one light and sends them.
+func (uc *LightGroupUseCase) applySettings(ctx context.Context, accessToken, deviceID string, settings entities.LightSettings, transitionMs int) dtos.LightPresetDeviceResultDTO {
+	result := dtos.LightPresetDeviceResultDTO{DeviceID: deviceID}
+
+	spec, err := uc.categoryUC.getSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+
+	var commands []dtos.TuyaCommandDTO
+	if fn, ok := findFunction(spec, lightPowerCodes); ok {
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: true})
+	}
+	if transitionMs > 0 {
+		if fn, ok := findFunction(spec, lightTransitionCodes); ok {
+			commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: transitionValue(fn, transitionMs)})
+			result.Transition = true
+		}
+	}
+
+	colourFn, hasColour := findFunction(spec, lightColourCodes)
+	if settings.Colour != nil && hasColour {
+		commands = appendWorkMode(commands, spec, "colour")
+		commands = append(commands, dtos.TuyaCommandDTO{Code: colourFn.Code, Value: colourValue(colourFn, *settings.Colour, settings.Brightness)})
+	} else {
+		commands = appendWorkMode(commands, spec, "white")
+		if fn, ok := findFunction(spec, lightBrightCodes); ok {
+			commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: scalePercent(settings.Brightness, parseFunctionValues(fn))})
+		}
+		if settings.Temperature != nil {
+			if fn, ok := findFunction(spec, lightTempCodes); ok {
+				commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: scalePercent(*settings.Temperature, parseFunctionValues(fn))})
+			}
+		}
+	}
+
+	result.Commands = commands
+	if _, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands); err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Success = true
+	return result
+}
+
+// captureSettings reads the current brightness and colour of a light as normalized settings.
+func (uc *LightGroupUseCase) captureSettings(ctx context.Context, accessToken, deviceID string) (*entities.LightSettings, error) {
+	spec, err := uc.categoryUC.getSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := make(map[string]interface{}, len(device.Status))
+	for _, s := range device.Status {
+		status[s.Code] = s.Value
+	}
+
+	settings := &entities.LightSettings{Brightness: 100}
+	if fn, ok := findFunction(spec, lightColourCodes); ok && status["work_mode"] == "colour" {
+		if h, s, v, ok := parseColourStatus(status[fn.Code]); ok {
+			max := colourChannelMax(fn)
+			settings.Colour = &entities.LightColour{Hue: h, Saturation: toPercent(s, 0, max)}
+			settings.Brightness = clampPercent(toPercent(v, 0, max))
+			return settings, nil
+		}
+	}
+
+	if fn, ok := findFunction(spec, lightBrightCodes); ok {
+		if raw, ok := status[fn.Code].(float64); ok {
+			values := parseFunctionValues(fn)
+			settings.Brightness = clampPercent(toPercent(raw, valueOr(values.Min, 0), valueOr(values.Max, 100)))
+		}
+	}
+	if fn, ok := findFunction(spec, lightTempCodes); ok {
+		if raw, ok := status[fn.Code].(float64); ok {
+			values := parseFunctionValues(fn)
+			temperature := toPercent(raw, valueOr(values.Min, 0), valueOr(values.Max, 100))
+			settings.Temperature = &temperature
+		}
+	}
+	return settings, nil
+}
+
+// loadGroup reads a light group from storage.
+func (uc *LightGroupUseCase) loadGroup(groupID string) (*entities.LightGroup, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("light group storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(lightGroupKey(groupID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get light group: %w", err)
+	}
+	if jsonData == nil {
+		return nil, ErrLightGroupNotFound
+	}
+	var group entities.LightGroup
+	if err := json.Unmarshal(jsonData, &group); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal light group: %w", err)
+	}
+	return &group, nil
+}
+
+// saveGroup persists a light group.
+func (uc *LightGroupUseCase) saveGroup(group *entities.LightGroup) error {
+	jsonData, err := json.Marshal(group)
+	if err != nil {
+		return fmt.Errorf("failed to marshal light group: %w", err)
+	}
+	if err := uc.cache.SetPersistent(lightGroupKey(group.ID), jsonData); err != nil {
+		return fmt.Errorf("failed to save light group: %w", err)
+	}
+	return nil
+}
+
+// lightGroupKey builds the storage key of a light group.
+func lightGroupKey(groupID string) string {
+	return fmt.Sprintf("light_group:%s", groupID)
+}
+
+// findLightPreset looks up a preset by name, preferring custom presets over built-in ones.
+func findLightPreset(group *entities.LightGroup, name string) (entities.LightPreset, bool) {
+	for _, preset := range group.Presets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	for _, preset := range builtInLightPresets {
+		if preset.Name == name {
+			return preset, true
+		}
+	}
+	return entities.LightPreset{}, false
+}
+
+// upsertLightPreset replaces the custom preset with the same name or appends a new one.
+func upsertLightPreset(group *entities.LightGroup, preset entities.LightPreset) {
+	for i := range group.Presets {
+		if group.Presets[i].Name == preset.Name {
+			group.Presets[i] = preset
+			return
+		}
+	}
+	group.Presets = append(group.Presets, preset)
+}
+
+// appendWorkMode switches the light to the given mode when it exposes a work_mode DP supporting it.
+func appendWorkMode(commands []dtos.TuyaCommandDTO, spec *entities.TuyaDeviceSpecification, mode string) []dtos.TuyaCommandDTO {
+	fn, ok := findFunction(spec, lightModeCodes)
+	if !ok || !containsString(parseFunctionValues(fn).Range, mode) {
+		return commands
+	}
+	return append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: mode})
+}
+
+// colourValue builds the HSV value of a colour DP. colour_data_v2 uses 0-1000 for saturation and value, colour_data uses 0-255.
+func colourValue(fn entities.TuyaDeviceFunction, colour entities.LightColour, brightness int) map[string]int {
+	max := colourChannelMax(fn)
+	return map[string]int{
+		"h": colour.Hue,
+		"s": int(math.Round(float64(colour.Saturation) * max / 100)),
+		"v": int(math.Round(float64(brightness) * max / 100)),
+	}
+}
+
+// colourChannelMax returns the maximum saturation/value of a colour DP.
+func colourChannelMax(fn entities.TuyaDeviceFunction) float64 {
+	if strings.HasSuffix(fn.Code, "_v2") {
+		return 1000
+	}
+	return 255
+}
+
+// parseColourStatus decodes a colour DP status, which devices report either as a JSON string or an object.
+func parseColourStatus(value interface{}) (int, float64, float64, bool) {
+	var hsv struct {
+		H float64 `json:"h"`
+		S float64 `json:"s"`
+		V float64 `json:"v"`
+	}
+	switch v := value.(type) {
+	case string:
+		if err := json.Unmarshal([]byte(v), &hsv); err != nil {
+			return 0, 0, 0, false
+		}
+	case map[string]interface{}:
+		data, _ := json.Marshal(v)
+		if err := json.Unmarshal(data, &hsv); err != nil {
+			return 0, 0, 0, false
+		}
+	default:
+		return 0, 0, 0, false
+	}
+	return int(hsv.H), hsv.S, hsv.V, true
+}
+
+// transitionValue converts milliseconds into the unit of a transition DP.
+// Functions with a maximum below 1000 are treated as seconds.
+func transitionValue(fn entities.TuyaDeviceFunction, transitionMs int) int {
+	values := parseFunctionValues(fn)
+	value := float64(transitionMs)
+	if values.Max != nil && *values.Max < 1000 {
+		value = math.Ceil(value / 1000)
+	}
+	if values.Min != nil {
+		value = math.Max(value, *values.Min)
+	}
+	if values.Max != nil {
+		value = math.Min(value, *values.Max)
+	}
+	return int(value)
+}
+
+// toPercent maps a raw value within min/max onto 0-100.
+func toPercent(raw, min, max float64) int {
+	if max <= min {
+		return 0
+	}
+	return int(math.Round((raw - min) / (max - min) * 100))
+}
+
+// clampPercent keeps a brightness percentage within 1-100.
+func clampPercent(percent int) int {
+	if percent < 1 {
+		return 1
+	}
+	if percent > 100 {
+		return 100
+	}
+	return percent
+}
+
+// valueOr dereferences value or returns fallback when it is nil.
+func valueOr(value *float64, fallback float64) float64 {
+	if value == nil {
+		return fallback
+	}
+	return *value
+}
+
+// intPtr returns a pointer to v.
+func intPtr(v int) *int {
+	return &v
+}
+
+// toLightSettings converts request settings into the stored form.
+func toLightSettings(settings dtos.LightSettingsDTO) entities.LightSettings {
+	result := entities.LightSettings{
+		Brightness:  settings.Brightness,
+		Temperature: settings.Temperature,
+	}
+	if settings.Colour != nil {
+		result.Colour = &entities.LightColour{Hue: settings.Colour.Hue, Saturation: settings.Colour.Saturation}
+	}
+	return result
+}
+
+// toLightSettingsDTO converts stored settings into the response form.
+func toLightSettingsDTO(settings entities.LightSettings) dtos.LightSettingsDTO {
+	result := dtos.LightSettingsDTO{
+		Brightness:  settings.Brightness,
+		Temperature: settings.Temperature,
+	}
+	if settings.Colour != nil {
+		result.Colour = &dtos.LightColourDTO{Hue: settings.Colour.Hue, Saturation: settings.Colour.Saturation}
+	}
+	return result
+}
+
+// toLightGroupDTO converts a light group into its response form, listing built-in presets that are not overridden.
+func toLightGroupDTO(group *entities.LightGroup) dtos.LightGroupDTO {
+	presets := make([]dtos.LightPresetDTO, 0, len(builtInLightPresets)+len(group.Presets))
+	custom := make(map[string]bool, len(group.Presets))
+	for _, preset := range group.Presets {
+		custom[preset.Name] = true
+	}
+
+	for _, preset := range builtInLightPresets {
+		if custom[preset.Name] {
+			continue
+		}
+		presets = append(presets, dtos.LightPresetDTO{
+			Name:         preset.Name,
+			BuiltIn:      true,
+			Settings:     toLightSettingsDTO(preset.Settings),
+			TransitionMs: preset.TransitionMs,
+		})
+	}
+	for _, preset := range group.Presets {
+		presetDTO := dtos.LightPresetDTO{
+			Name:         preset.Name,
+			Settings:     toLightSettingsDTO(preset.Settings),
+			TransitionMs: preset.TransitionMs,
+		}
+		if len(preset.Devices) > 0 {
+			presetDTO.Devices = make(map[string]dtos.LightSettingsDTO, len(preset.Devices))
+			for deviceID, settings := range preset.Devices {
+				presetDTO.Devices[deviceID] = toLightSettingsDTO(settings)
+			}
+		}
+		presets = append(presets, presetDTO)
+	}
+
+	return dtos.LightGroupDTO{
+		ID:        group.ID,
+		Name:      group.Name,
+		DeviceIDs: group.DeviceIDs,
+		Presets:   presets,
+		CreatedAt: group.CreatedAt,
+	}
+}
@@ -0,0 +1,810 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/mqtt"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	// defaultMQTTClientID is used when MQTT_CLIENT_ID is not set.
+	defaultMQTTClientID = "teralux"
+	// defaultMQTTDiscoveryPrefix is the discovery prefix Home Assistant listens on by default.
+	defaultMQTTDiscoveryPrefix = "homeassistant"
+	// defaultMQTTBaseTopic is used when MQTT_BASE_TOPIC is not set.
+	defaultMQTTBaseTopic = "teralux"
+	// defaultMQTTSyncInterval is used when MQTT_SYNC_INTERVAL is not set.
+	defaultMQTTSyncInterval = time.Hour
+	// mqttSyncTimeout bounds fetching and publishing the device list.
+	mqttSyncTimeout = time.Minute
+	// mqttCommandTimeout bounds one command received from Home Assistant.
+	mqttCommandTimeout = 30 * time.Second
+	// mqttMinReconnectDelay and mqttMaxReconnectDelay bound the backoff between connection attempts.
+	mqttMinReconnectDelay = 5 * time.Second
+	mqttMaxReconnectDelay = time.Minute
+	// mqttMinACTemp and mqttMaxACTemp bound the target temperature of IR air conditioners.
+	mqttMinACTemp = 16
+	mqttMaxACTemp = 30
+)
+
+// mqttSensorSpec describes how a numeric DP code is exposed as a Home Assistant sensor.
+type mqttSensorSpec struct {
+	name        string
+	deviceClass string
+	unit        string
+	scale       float64
+}
+
+// mqttSensorSpecs lists the DP codes exposed as sensors.
+var mqttSensorSpecs = map[string]mqttSensorSpec{
+	"va_temperature":     {name: "Temperature", deviceClass: "temperature", unit: "°C", scale: 10},
+	"va_humidity":        {name: "Humidity", deviceClass: "humidity", unit: "%", scale: 1},
+	"humidity_value":     {name: "Humidity", deviceClass: "humidity", unit: "%", scale: 1},
+	"battery_percentage": {name: "Battery", deviceClass: "battery", unit: "%", scale: 1},
+	"cur_power":          {name: "Power", deviceClass: "power", unit: "W", scale: 10},
+	"cur_voltage":        {name: "Voltage", deviceClass: "voltage", unit: "V", scale: 10},
+	"cur_current":        {name: "Current", deviceClass: "current", unit: "mA", scale: 1},
+}
+
+// irACModes are the Home Assistant HVAC modes, indexed by the Tuya infrared_ac "mode" value.
+var irACModes = []string{"cool", "heat", "auto", "fan_only", "dry"}
+
+// irACFanModes are the Home Assistant fan modes, indexed by the Tuya infrared_ac "wind" value.
+var irACFanModes = []string{"auto", "low", "medium", "high"}
+
+// mqttEntity is a Home Assistant entity published for a device.
+type mqttEntity struct {
+	component string
+	objectID  string
+	// deviceID receives the commands and reports the state (the remote for IR air conditioners).
+	deviceID string
+	// infraredID is the IR hub sending the commands of an IR air conditioner.
+	infraredID string
+	// availabilityID is the device whose online state the entity follows.
+	availabilityID string
+	code           string
+	sensor         mqttSensorSpec
+	config         map[string]interface{}
+}
+
+// MQTTBridgeUseCase publishes the Tuya devices to an MQTT broker using Home Assistant MQTT discovery,
+// so they appear in Home Assistant without custom integrations: switch DPs as switches, numeric DPs
+// (temperature, humidity, power, ...) as sensors and IR air conditioners as climate entities.
+// States follow the realtime hub; commands received on the command topics are sent through the
+// device control usecase, so approval rules, cooldowns and auditing apply to them.
+// The bridge is disabled unless MQTT_BROKER is set.
+type MQTTBridgeUseCase struct {
+	getAllDevicesUC *TuyaGetAllDevicesUseCase
+	controlUC       *TuyaDeviceControlUseCase
+	authUC          *TuyaAuthUseCase
+	realtimeHub     *realtime_services.RealtimeHubService
+	options         mqtt.Options
+	discoveryPrefix string
+	baseTopic       string
+	syncInterval    time.Duration
+	clock           utils.Clock
+
+	mu        sync.Mutex
+	client    *mqtt.Client
+	entities  map[string][]*mqttEntity
+	published map[string]string
+	climate   map[string]map[string]int
+	status    dtos.MQTTBridgeStatusDTO
+
+	commands     chan mqtt.Message
+	syncRequests chan struct{}
+	startOnce    sync.Once
+	workers      utils.WorkerGroup
+}
+
+// NewMQTTBridgeUseCase initializes a new MQTTBridgeUseCase.
+// The broker and topics are read from MQTT_BROKER, MQTT_USERNAME, MQTT_PASSWORD, MQTT_CLIENT_ID,
+// MQTT_DISCOVERY_PREFIX, MQTT_BASE_TOPIC and MQTT_SYNC_INTERVAL.
+//
+// param getAllDevicesUC The TuyaGetAllDevicesUseCase listing the devices to publish.
+// param controlUC The TuyaDeviceControlUseCase sending commands received from Home Assistant.
+// param authUC The TuyaAuthUseCase providing the server-managed token.
+// param realtimeHub The RealtimeHubService the device states are read from.
+// param clock The Clock used for status timestamps.
+// return *MQTTBridgeUseCase A pointer to the initialized usecase.
+func NewMQTTBridgeUseCase(getAllDevicesUC *TuyaGetAllDevicesUseCase, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, realtimeHub *realtime_services.RealtimeHubService, clock utils.Clock) *MQTTBridgeUseCase {
+	config := utils.GetConfig()
+	clientID := config.MQTTClientID
+	if clientID == "" {
+		clientID = defaultMQTTClientID
+	}
+	discoveryPrefix := strings.Trim(config.MQTTDiscoveryPrefix, "/")
+	if discoveryPrefix == "" {
+		discoveryPrefix = defaultMQTTDiscoveryPrefix
+	}
+	baseTopic := strings.Trim(config.MQTTBaseTopic, "/")
+	if baseTopic == "" {
+		baseTopic = defaultMQTTBaseTopic
+	}
+	syncInterval, err := time.ParseDuration(config.MQTTSyncInterval)
+	if err != nil || syncInterval <= 0 {
+		syncInterval = defaultMQTTSyncInterval
+	}
+
+	return &MQTTBridgeUseCase{
+		getAllDevicesUC: getAllDevicesUC,
+		controlUC:       controlUC,
+		authUC:          authUC,
+		realtimeHub:     realtimeHub,
+		options: mqtt.Options{
+			Broker:      config.MQTTBroker,
+			ClientID:    clientID,
+			Username:    config.MQTTUsername,
+			Password:    config.MQTTPassword,
+			KeepAlive:   30 * time.Second,
+			WillTopic:   baseTopic + "/status",
+			WillPayload: []byte("offline"),
+		},
+		discoveryPrefix: discoveryPrefix,
+		baseTopic:       baseTopic,
+		syncInterval:    syncInterval,
+		clock:           clock,
+		entities:        make(map[string][]*mqttEntity),
+		published:       make(map[string]string),
+		climate:         make(map[string]map[string]int),
+		status: dtos.MQTTBridgeStatusDTO{
+			Enabled:         config.MQTTBroker != "",
+			Broker:          config.MQTTBroker,
+			DiscoveryPrefix: discoveryPrefix,
+			BaseTopic:       baseTopic,
+		},
+		commands:     make(chan mqtt.Message, 32),
+		syncRequests: make(chan struct{}, 1),
+	}
+}
+
+// Start connects to the broker in the background, reconnecting with backoff, and keeps the published
+// devices and states current. It does nothing when MQTT_BROKER is not set.
+func (uc *MQTTBridgeUseCase) Start() {
+	if uc.options.Broker == "" {
+		utils.LogInfo("MQTTBridgeUseCase: MQTT_BROKER not set, Home Assistant bridge disabled")
+		return
+	}
+	uc.startOnce.Do(func() {
+		uc.workers.Go(uc.connectionLoop)
+		uc.workers.Go(uc.commandLoop)
+		uc.workers.Go(uc.eventLoop)
+		utils.LogInfo("MQTTBridgeUseCase: Bridging devices to %s (discovery prefix %s)", uc.options.Broker, uc.discoveryPrefix)
+	})
+}
+
+// Stop marks the bridge offline, disconnects and waits for the workers to finish.
+func (uc *MQTTBridgeUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// GetStatus returns the connection state and the number of published entities.
+//
+// return dtos.MQTTBridgeStatusDTO The bridge status.
+func (uc *MQTTBridgeUseCase) GetStatus() dtos.MQTTBridgeStatusDTO {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.status
+}
+
+// RequestSync republishes the devices in the background, e.g. after devices were renamed.
+//
+// return error A bad request error when the bridge is disabled or not connected.
+func (uc *MQTTBridgeUseCase) RequestSync() error {
+	status := uc.GetStatus()
+	if !status.Enabled {
+		return tuya_errors.BadRequest("the MQTT bridge is disabled (set MQTT_BROKER)")
+	}
+	if !status.Connected {
+		return tuya_errors.BadRequest("the MQTT bridge is not connected")
+	}
+	select {
+	case uc.syncRequests <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// connectionLoop keeps a connection to the broker and republishes the devices periodically.
+func (uc *MQTTBridgeUseCase) connectionLoop(stop <-chan struct{}) {
+	delay := mqttMinReconnectDelay
+	for {
+		client, err := uc.connect(stop)
+		if err != nil {
+			uc.recordError(err)
+			utils.LogWarn("MQTTBridgeUseCase: %v; retrying in %s", err, delay)
+			select {
+			case <-time.After(delay):
+			case <-stop:
+				return
+			}
+			delay = min(2*delay, mqttMaxReconnectDelay)
+			continue
+		}
+		delay = mqttMinReconnectDelay
+		uc.sync(client)
+
+		ticker := time.NewTicker(uc.syncInterval)
+	session:
+		for {
+			select {
+			case <-ticker.C:
+				uc.sync(client)
+			case <-uc.syncRequests:
+				uc.sync(client)
+			case <-client.Done():
+				break session
+			case <-stop:
+				ticker.Stop()
+				_ = client.Publish(uc.baseTopic+"/status", []byte("offline"), true)
+				client.Close()
+				uc.setClient(nil, nil)
+				return
+			}
+		}
+		ticker.Stop()
+		uc.setClient(nil, client.Err())
+		utils.LogWarn("MQTTBridgeUseCase: Connection to %s lost: %v", uc.options.Broker, client.Err())
+	}
+}
+
+// connect opens a session, subscribes to the command topics and announces the bridge as online.
+func (uc *MQTTBridgeUseCase) connect(stop <-chan struct{}) (*mqtt.Client, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	go func() {
+		select {
+		case <-stop:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+
+	client, err := mqtt.Connect(ctx, uc.options, uc.handleMessage)
+	if err != nil {
+		return nil, err
+	}
+	if err := client.Subscribe(uc.baseTopic+"/+/+/set", uc.discoveryPrefix+"/status"); err != nil {
+		client.Close()
+		return nil, err
+	}
+	if err := client.Publish(uc.baseTopic+"/status", []byte("online"), true); err != nil {
+		client.Close()
+		return nil, err
+	}
+	uc.setClient(client, nil)
+	utils.LogInfo("MQTTBridgeUseCase: Connected to %s", uc.options.Broker)
+	return client, nil
+}
+
+// handleMessage runs on the MQTT read loop and hands messages to the workers without blocking.
+func (uc *MQTTBridgeUseCase) handleMessage(message mqtt.Message) {
+	// Home Assistant announces "online" after a restart and expects discovery to be sent again
+	if message.Topic == uc.discoveryPrefix+"/status" {
+		if string(message.Payload) == "online" {
+			select {
+			case uc.syncRequests <- struct{}{}:
+			default:
+			}
+		}
+		return
+	}
+	select {
+	case uc.commands <- message:
+	default:
+		utils.LogWarn("MQTTBridgeUseCase: Dropping command on %s, too many commands pending", message.Topic)
+	}
+}
+
+// sync publishes discovery configs, availability and states for every device, and removes
+// the entities of devices that are gone.
+func (uc *MQTTBridgeUseCase) sync(client *mqtt.Client) {
+	ctx, cancel := context.WithTimeout(context.Background(), mqttSyncTimeout)
+	defer cancel()
+
+	devices, err := uc.fetchDevices(ctx)
+	if err != nil {
+		uc.recordError(fmt.Errorf("failed to list devices: %w", err))
+		utils.LogWarn("MQTTBridgeUseCase: Failed to list devices: %v", err)
+		return
+	}
+
+	entities := make(map[string][]*mqttEntity)
+	availability := make(map[string]bool)
+	var states []realtime_dtos.DeviceEventDTO
+	for _, device := range devices {
+		built := uc.buildEntities(device)
+		if len(built) == 0 {
+			continue
+		}
+		for _, entity := range built {
+			entities[entity.deviceID] = append(entities[entity.deviceID], entity)
+			availability[entity.availabilityID] = availability[entity.availabilityID] || device.Online
+		}
+		event := realtime_dtos.DeviceEventDTO{Type: "device_state", DeviceID: built[0].deviceID}
+		for _, status := range device.Status {
+			event.Status = append(event.Status, realtime_dtos.DeviceEventStatusDTO{Code: status.Code, Value: status.Value})
+		}
+		states = append(states, event)
+	}
+
+	uc.mu.Lock()
+	previous := uc.published
+	uc.entities = entities
+	uc.published = make(map[string]string)
+	uc.mu.Unlock()
+
+	count := 0
+	for _, list := range entities {
+		for _, entity := range list {
+			topic := uc.discoveryTopic(entity)
+			payload, err := json.Marshal(entity.config)
+			if err != nil {
+				continue
+			}
+			if err := client.Publish(topic, payload, true); err != nil {
+				uc.recordError(err)
+				return
+			}
+			uc.mu.Lock()
+			uc.published[entity.objectID] = topic
+			uc.mu.Unlock()
+			delete(previous, entity.objectID)
+			count++
+		}
+	}
+	for objectID, topic := range previous {
+		// An empty retained config removes the entity from Home Assistant
+		_ = client.Publish(topic, nil, true)
+		utils.LogInfo("MQTTBridgeUseCase: Removed entity %s", objectID)
+	}
+	for deviceID, online := range availability {
+		uc.publishAvailability(client, deviceID, online)
+	}
+	for _, event := range states {
+		uc.publishStates(client, event)
+	}
+
+	uc.mu.Lock()
+	uc.status.Devices = len(availability)
+	uc.status.Entities = count
+	uc.status.LastSyncAt = uc.clock.Now().Unix()
+	uc.status.LastError = ""
+	uc.mu.Unlock()
+	utils.LogInfo("MQTTBridgeUseCase: Published %d entities of %d devices", count, len(availability))
+}
+
+// fetchDevices lists the devices of TUYA_USER_ID, with IR remotes nested in hubs flattened.
+func (uc *MQTTBridgeUseCase) fetchDevices(ctx context.Context) ([]dtos.TuyaDeviceDTO, error) {
+	uid := utils.GetConfig().TuyaUserID
+	if uid == "" {
+		return nil, fmt.Errorf("TUYA_USER_ID is not set")
+	}
+	accessToken, err := uc.authUC.ServerAccessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	response, err := uc.getAllDevicesUC.GetAllDevices(ctx, accessToken, uid, 0, 0, "", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var devices []dtos.TuyaDeviceDTO
+	for _, device := range response.Devices {
+		devices = append(devices, device)
+		for _, nested := range device.Collections {
+			if nested.GatewayID == "" {
+				nested.GatewayID = device.ID
+			}
+			devices = append(devices, nested)
+		}
+	}
+	return devices, nil
+}
+
+// buildEntities maps a device to its Home Assistant entities.
+func (uc *MQTTBridgeUseCase) buildEntities(device dtos.TuyaDeviceDTO) []*mqttEntity {
+	name := device.Name
+	if device.CustomName != "" {
+		name = device.CustomName
+	}
+
+	// IR air conditioners are listed nested (remote with gateway_id) or merged into their hub (remote_id)
+	switch {
+	case device.RemoteCategory == "infrared_ac" && device.RemoteID != "":
+		return []*mqttEntity{uc.climateEntity(device.RemoteID, device.ID, name, device.RemoteProductName)}
+	case device.Category == "infrared_ac":
+		if device.GatewayID == "" {
+			return nil
+		}
+		return []*mqttEntity{uc.climateEntity(device.ID, device.GatewayID, name, device.ProductName)}
+	}
+
+	var entities []*mqttEntity
+	for _, status := range device.Status {
+		if _, ok := status.Value.(bool); ok && isSwitchCode(status.Code) {
+			entity := &mqttEntity{
+				component:      "switch",
+				objectID:       device.ID + "_" + status.Code,
+				deviceID:       device.ID,
+				availabilityID: device.ID,
+				code:           status.Code,
+			}
+			entity.config = uc.baseConfig(entity, name, device.ProductName, switchEntityName(status.Code))
+			entity.config["command_topic"] = uc.entityTopic(device.ID, status.Code, "set")
+			entity.config["state_topic"] = uc.entityTopic(device.ID, status.Code, "state")
+			entity.config["payload_on"] = "ON"
+			entity.config["payload_off"] = "OFF"
+			entities = append(entities, entity)
+			continue
+		}
+
+		spec, ok := mqttSensorSpecs[status.Code]
+		if !ok {
+			continue
+		}
+		if _, ok := numericValue(status.Value); !ok {
+			continue
+		}
+		entity := &mqttEntity{
+			component:      "sensor",
+			objectID:       device.ID + "_" + status.Code,
+			deviceID:       device.ID,
+			availabilityID: device.ID,
+			code:           status.Code,
+			sensor:         spec,
+		}
+		entity.config = uc.baseConfig(entity, name, device.ProductName, spec.name)
+		entity.config["state_topic"] = uc.entityTopic(device.ID, status.Code, "state")
+		entity.config["device_class"] = spec.deviceClass
+		entity.config["unit_of_measurement"] = spec.unit
+		entity.config["state_class"] = "measurement"
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// climateEntity builds the climate entity of an IR air conditioner.
+func (uc *MQTTBridgeUseCase) climateEntity(remoteID, infraredID, name, model string) *mqttEntity {
+	entity := &mqttEntity{
+		component:      "climate",
+		objectID:       remoteID + "_climate",
+		deviceID:       remoteID,
+		infraredID:     infraredID,
+		availabilityID: infraredID,
+	}
+	entity.config = uc.baseConfig(entity, name, model, nil)
+	entity.config["modes"] = append([]string{"off"}, irACModes...)
+	entity.config["fan_modes"] = irACFanModes
+	entity.config["mode_command_topic"] = uc.entityTopic(remoteID, "mode", "set")
+	entity.config["mode_state_topic"] = uc.entityTopic(remoteID, "mode", "state")
+	entity.config["temperature_command_topic"] = uc.entityTopic(remoteID, "temperature", "set")
+	entity.config["temperature_state_topic"] = uc.entityTopic(remoteID, "temperature", "state")
+	entity.config["fan_mode_command_topic"] = uc.entityTopic(remoteID, "fan_mode", "set")
+	entity.config["fan_mode_state_topic"] = uc.entityTopic(remoteID, "fan_mode", "state")
+	entity.config["min_temp"] = mqttMinACTemp
+	entity.config["max_temp"] = mqttMaxACTemp
+	entity.config["temp_step"] = 1
+	entity.config["precision"] = 1.0
+	entity.config["temperature_unit"] = "C"
+	return entity
+}
+
+// baseConfig returns the discovery fields shared by all entities. A nil name names the entity after its device.
+func (uc *MQTTBridgeUseCase) baseConfig(entity *mqttEntity, deviceName, model string, name interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"name":      name,
+		"unique_id": uc.baseTopic + "_" + entity.objectID,
+		"availability": []map[string]string{
+			{"topic": uc.baseTopic + "/status"},
+			{"topic": uc.entityTopic(entity.availabilityID, "availability", "")},
+		},
+		"availability_mode": "all",
+		"device": map[string]interface{}{
+			"identifiers":  []string{uc.baseTopic + "_" + entity.deviceID},
+			"name":         deviceName,
+			"manufacturer": "Tuya",
+			"model":        model,
+		},
+	}
+}
+
+// eventLoop publishes device states and availability from the realtime hub.
+func (uc *MQTTBridgeUseCase) eventLoop(stop <-chan struct{}) {
+	subscription := uc.realtimeHub.Register(realtime_dtos.SubscriptionFilterDTO{})
+	defer uc.realtimeHub.Unregister(subscription)
+	for {
+		select {
+		case payload, ok := <-subscription.Send:
+			if !ok {
+				return
+			}
+			uc.mu.Lock()
+			client := uc.client
+			uc.mu.Unlock()
+			if client == nil {
+				continue
+			}
+			var event realtime_dtos.DeviceEventDTO
+			if err := json.Unmarshal(payload, &event); err != nil {
+				continue
+			}
+			switch event.Type {
+			case "device_state":
+				uc.publishStates(client, event)
+			case "device_online", "device_offline":
+				uc.publishAvailability(client, event.DeviceID, event.Type == "device_online")
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// publishStates publishes the reported values of a device to the state topics of its entities.
+func (uc *MQTTBridgeUseCase) publishStates(client *mqtt.Client, event realtime_dtos.DeviceEventDTO) {
+	uc.mu.Lock()
+	entities := uc.entities[event.DeviceID]
+	uc.mu.Unlock()
+
+	for _, entity := range entities {
+		switch entity.component {
+		case "switch":
+			for _, status := range event.Status {
+				if on, ok := status.Value.(bool); ok && status.Code == entity.code {
+					_ = client.Publish(uc.entityTopic(entity.deviceID, entity.code, "state"), []byte(onOff(on)), true)
+				}
+			}
+		case "sensor":
+			for _, status := range event.Status {
+				if value, ok := numericValue(status.Value); ok && status.Code == entity.code {
+					scaled := strconv.FormatFloat(value/entity.sensor.scale, 'f', -1, 64)
+					_ = client.Publish(uc.entityTopic(entity.deviceID, entity.code, "state"), []byte(scaled), true)
+				}
+			}
+		case "climate":
+			uc.publishClimate(client, entity, event.Status)
+		}
+	}
+}
+
+// publishClimate merges reported IR air conditioner values into the last known state and publishes it.
+func (uc *MQTTBridgeUseCase) publishClimate(client *mqtt.Client, entity *mqttEntity, statuses []realtime_dtos.DeviceEventStatusDTO) {
+	uc.mu.Lock()
+	state, ok := uc.climate[entity.deviceID]
+	if !ok {
+		state = map[string]int{"power": 0, "mode": 0, "temp": 24, "wind": 0}
+		uc.climate[entity.deviceID] = state
+	}
+	changed := !ok
+	for _, status := range statuses {
+		if _, known := state[status.Code]; !known {
+			continue
+		}
+		if value, ok := numericValue(status.Value); ok {
+			state[status.Code] = int(value)
+			changed = true
+		}
+	}
+	power, mode, temp, wind := state["power"], state["mode"], state["temp"], state["wind"]
+	uc.mu.Unlock()
+	if !changed {
+		return
+	}
+
+	hvacMode := "off"
+	if power != 0 && mode >= 0 && mode < len(irACModes) {
+		hvacMode = irACModes[mode]
+	}
+	fanMode := irACFanModes[0]
+	if wind >= 0 && wind < len(irACFanModes) {
+		fanMode = irACFanModes[wind]
+	}
+	_ = client.Publish(uc.entityTopic(entity.deviceID, "mode", "state"), []byte(hvacMode), true)
+	_ = client.Publish(uc.entityTopic(entity.deviceID, "temperature", "state"), []byte(strconv.Itoa(temp)), true)
+	_ = client.Publish(uc.entityTopic(entity.deviceID, "fan_mode", "state"), []byte(fanMode), true)
+}
+
+// publishAvailability publishes whether a device is reachable.
+func (uc *MQTTBridgeUseCase) publishAvailability(client *mqtt.Client, deviceID string, online bool) {
+	payload := "offline"
+	if online {
+		payload = "online"
+	}
+	_ = client.Publish(uc.entityTopic(deviceID, "availability", ""), []byte(payload), true)
+}
+
+// commandLoop executes commands received from Home Assistant one at a time.
+func (uc *MQTTBridgeUseCase) commandLoop(stop <-chan struct{}) {
+	for {
+		select {
+		case message := <-uc.commands:
+			ctx, cancel := context.WithTimeout(utils.ContextWithActor(context.Background(), "mqtt"), mqttCommandTimeout)
+			err := uc.executeCommand(ctx, message)
+			cancel()
+
+			var approvalErr *ApprovalRequiredError
+			switch {
+			case errors.As(err, &approvalErr):
+				utils.LogInfo("MQTTBridgeUseCase: Command on %s held for approval as pending action %s", message.Topic, approvalErr.Action.ID)
+			case err != nil:
+				utils.LogWarn("MQTTBridgeUseCase: Command on %s failed: %v", message.Topic, err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// executeCommand translates a message on "{base}/{device_id}/{attribute}/set" into a device command.
+func (uc *MQTTBridgeUseCase) executeCommand(ctx context.Context, message mqtt.Message) error {
+	parts := strings.Split(strings.TrimPrefix(message.Topic, uc.baseTopic+"/"), "/")
+	if len(parts) != 3 || parts[2] != "set" {
+		return fmt.Errorf("unexpected command topic")
+	}
+	deviceID, attribute, payload := parts[0], parts[1], strings.TrimSpace(string(message.Payload))
+
+	uc.mu.Lock()
+	entities := uc.entities[deviceID]
+	uc.status.LastCommandAt = uc.clock.Now().Unix()
+	uc.mu.Unlock()
+
+	for _, entity := range entities {
+		switch {
+		case entity.component == "switch" && entity.code == attribute:
+			if payload != "ON" && payload != "OFF" {
+				return fmt.Errorf("unexpected payload %q", payload)
+			}
+			accessToken, err := uc.authUC.ServerAccessToken(ctx)
+			if err != nil {
+				return err
+			}
+			_, err = uc.controlUC.SendCommand(ctx, accessToken, deviceID, []dtos.TuyaCommandDTO{{Code: entity.code, Value: payload == "ON"}})
+			return err
+		case entity.component == "climate":
+			return uc.executeClimateCommand(ctx, entity, attribute, payload)
+		}
+	}
+	return fmt.Errorf("unknown entity")
+}
+
+// executeClimateCommand sends the IR commands for a climate mode, temperature or fan mode change.
+func (uc *MQTTBridgeUseCase) executeClimateCommand(ctx context.Context, entity *mqttEntity, attribute, payload string) error {
+	var commands [][2]interface{}
+	switch attribute {
+	case "mode":
+		if payload == "off" {
+			commands = append(commands, [2]interface{}{"power", 0})
+			break
+		}
+		mode := indexOf(irACModes, payload)
+		if mode < 0 {
+			return fmt.Errorf("unsupported mode %q", payload)
+		}
+		uc.mu.Lock()
+		powered := uc.climate[entity.deviceID]["power"] != 0
+		uc.mu.Unlock()
+		if !powered {
+			commands = append(commands, [2]interface{}{"power", 1})
+		}
+		commands = append(commands, [2]interface{}{"mode", mode})
+	case "temperature":
+		value, err := strconv.ParseFloat(payload, 64)
+		if err != nil {
+			return fmt.Errorf("unexpected temperature %q", payload)
+		}
+		temp := int(math.Round(value))
+		temp = max(mqttMinACTemp, min(mqttMaxACTemp, temp))
+		commands = append(commands, [2]interface{}{"temp", temp})
+	case "fan_mode":
+		wind := indexOf(irACFanModes, payload)
+		if wind < 0 {
+			return fmt.Errorf("unsupported fan mode %q", payload)
+		}
+		commands = append(commands, [2]interface{}{"wind", wind})
+	default:
+		return fmt.Errorf("unknown climate attribute %q", attribute)
+	}
+
+	accessToken, err := uc.authUC.ServerAccessToken(ctx)
+	if err != nil {
+		return err
+	}
+	for _, command := range commands {
+		if _, err := uc.controlUC.SendIRACCommand(ctx, accessToken, entity.infraredID, entity.deviceID, command[0].(string), command[1].(int)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setClient records the active connection; a nil client marks the bridge disconnected.
+func (uc *MQTTBridgeUseCase) setClient(client *mqtt.Client, err error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.client = client
+	uc.status.Connected = client != nil
+	if client != nil {
+		uc.status.ConnectedAt = uc.clock.Now().Unix()
+		uc.status.LastError = ""
+	} else if err != nil {
+		uc.status.LastError = err.Error()
+	}
+}
+
+// recordError stores the latest error for the status endpoint.
+func (uc *MQTTBridgeUseCase) recordError(err error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.status.LastError = err.Error()
+}
+
+// discoveryTopic returns the retained config topic of an entity.
+func (uc *MQTTBridgeUseCase) discoveryTopic(entity *mqttEntity) string {
+	return fmt.Sprintf("%s/%s/%s/%s/config", uc.discoveryPrefix, entity.component, uc.baseTopic, entity.objectID)
+}
+
+// entityTopic returns "{base}/{device_id}/{attribute}[/{suffix}]".
+func (uc *MQTTBridgeUseCase) entityTopic(deviceID, attribute, suffix string) string {
+	topic := uc.baseTopic + "/" + deviceID + "/" + attribute
+	if suffix != "" {
+		topic += "/" + suffix
+	}
+	return topic
+}
+
+// isSwitchCode reports whether a DP code is an on/off switch (switch, switch_led, switch_1, ...).
+func isSwitchCode(code string) bool {
+	return code == "switch" || code == "switch_led" || strings.HasPrefix(code, "switch_") && isDigits(strings.TrimPrefix(code, "switch_"))
+}
+
+// switchEntityName names the entity of a switch code; nil names it after its device.
+func switchEntityName(code string) interface{} {
+	if code == "switch" || code == "switch_led" {
+		return nil
+	}
+	return "Switch " + strings.TrimPrefix(code, "switch_")
+}
+
+// isDigits reports whether s is a non-empty string of decimal digits.
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// indexOf returns the index of value in list, or -1.
+func indexOf(list []string, value string) int {
+	for i, item := range list {
+		if item == value {
+			return i
+		}
+	}
+	return -1
+}
+
+// onOff converts a switch state into its MQTT payload.
+func onOff(on bool) string {
+	if on {
+		return "ON"
+	}
+	return "OFF"
+}
@@ -0,0 +1,434 @@
+package usecases
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRoomNotFound is returned when a room does not exist.
+	ErrRoomNotFound = tuya_errors.NotFound("room not found")
+	// ErrRoomsUnavailable is returned when the SQL database rooms are stored in is not connected.
+	ErrRoomsUnavailable = tuya_errors.Unavailable("rooms unavailable: database not initialized")
+)
+
+// RoomUseCase manages rooms (named device groups) stored in the SQL database and controls all devices
+// of a room with one call. Room membership is also kept in memory so realtime subscribers can filter
+// events by room without a database query per event.
+type RoomUseCase struct {
+	db          *gorm.DB
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	clock       utils.Clock
+	ids         utils.IDGenerator
+
+	mu          sync.RWMutex
+	deviceRooms map[string][]string
+}
+
+// NewRoomUseCase initializes a new RoomUseCase.
+//
+// param db The GORM database rooms are stored in (nil when the database is unavailable).
+// param getDeviceUC The usecase used to check that assigned devices exist.
+// param controlUC The usecase used to send room commands.
+// param clock The Clock used to timestamp rooms.
+// param ids The IDGenerator used for room IDs.
+// return *RoomUseCase A pointer to the initialized usecase.
+func NewRoomUseCase(db *gorm.DB, getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, clock utils.Clock, ids utils.IDGenerator) *RoomUseCase {
+	return &RoomUseCase{
+		db:          db,
+		getDeviceUC: getDeviceUC,
+		controlUC:   controlUC,
+		clock:       clock,
+		ids:         ids,
+		deviceRooms: make(map[string][]string),
+	}
+}
+
+// Init loads room membership. The room tables are created by the database migrations.
+//
+// return error An error if the initial load fails.
+func (uc *RoomUseCase) Init() error {
+	if uc.db == nil {
+		return ErrRoomsUnavailable
+	}
+	return uc.reloadMembership()
+}
+
+// RoomsForDevice returns the IDs of the rooms a device belongs to.
+// It matches the realtime RoomResolver signature.
+//
+// param deviceID The device ID.
+// return []string The room IDs, or nil if the device is in no room.
+func (uc *RoomUseCase) RoomsForDevice(deviceID string) []string {
+	uc.mu.RLock()
+	defer uc.mu.RUnlock()
+	return uc.deviceRooms[deviceID]
+}
+
+// ListRooms returns all rooms ordered by name.
+//
+// return []dtos.RoomDTO The rooms.
+// return error ErrRoomsUnavailable, or an error if the query fails.
+func (uc *RoomUseCase) ListRooms() ([]dtos.RoomDTO, error) {
+	if uc.db == nil {
+		return nil, ErrRoomsUnavailable
+	}
+
+	var rooms []entities.Room
+	if err := uc.db.Preload("Devices").Order("name").Find(&rooms).Error; err != nil {
+		return nil, fmt.Errorf("failed to list rooms: %w", err)
+	}
+
+	result := make([]dtos.RoomDTO, len(rooms))
+	for i := range rooms {
+		result[i] = roomToDTO(&rooms[i])
+	}
+	return result, nil
+}
+
+// GetRoom returns a room with its devices.
+//
+// param roomID The room ID.
+// return *dtos.RoomDTO The room.
+// return error ErrRoomNotFound if the room does not exist, or ErrRoomsUnavailable.
+func (uc *RoomUseCase) GetRoom(roomID string) (*dtos.RoomDTO, error) {
+	room, err := uc.loadRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	dto := roomToDTO(room)
+	return &dto, nil
+}
+
+// CreateRoom creates a room after checking that every device exists.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param req The room name and optional device IDs.
+// return *dtos.RoomDTO The created room.
+// return error A bad request error for invalid input, or ErrRoomsUnavailable.
+func (uc *RoomUseCase) CreateRoom(ctx context.Context, accessToken string, req dtos.CreateRoomRequestDTO) (*dtos.RoomDTO, error) {
+	if uc.db == nil {
+		return nil, ErrRoomsUnavailable
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return nil, tuya_errors.BadRequest("room name is required")
+	}
+	deviceIDs, err := uc.validateDevices(ctx, accessToken, req.DeviceIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkNameAvailable(name, ""); err != nil {
+		return nil, err
+	}
+
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate room id: %w", err)
+	}
+	now := uc.clock.Now().Unix()
+	room := &entities.Room{
+		ID:        id,
+		Name:      name,
+		Devices:   roomDevices(id, deviceIDs),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := uc.db.Create(room).Error; err != nil {
+		return nil, fmt.Errorf("failed to create room: %w", err)
+	}
+
+	uc.refreshMembership()
+	utils.LogInfo("RoomUseCase: Created room %s (%s) with %d devices", room.ID, room.Name, len(deviceIDs))
+	dto := roomToDTO(room)
+	return &dto, nil
+}
+
+// UpdateRoom renames a room and/or replaces its devices.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param roomID The room ID.
+// param req The new name and/or device IDs.
+// return *dtos.RoomDTO The updated room.
+// return error ErrRoomNotFound, ErrRoomsUnavailable, or a bad request error for invalid input.
+func (uc *RoomUseCase) UpdateRoom(ctx context.Context, accessToken, roomID string, req dtos.UpdateRoomRequestDTO) (*dtos.RoomDTO, error) {
+	room, err := uc.loadRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		name := strings.TrimSpace(*req.Name)
+		if name == "" {
+			return nil, tuya_errors.BadRequest("room name cannot be empty")
+		}
+		if err := uc.checkNameAvailable(name, roomID); err != nil {
+			return nil, err
+		}
+		room.Name = name
+	}
+	var deviceIDs []string
+	if req.DeviceIDs != nil {
+		if deviceIDs, err = uc.validateDevices(ctx, accessToken, *req.DeviceIDs); err != nil {
+			return nil, err
+		}
+	}
+	room.UpdatedAt = uc.clock.Now().Unix()
+
+	err = uc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&entities.Room{}).Where("id = ?", roomID).
+			Updates(map[string]interface{}{"name": room.Name, "updated_at": room.UpdatedAt}).Error; err != nil {
+			return err
+		}
+		if req.DeviceIDs == nil {
+			return nil
+		}
+		if err := tx.Where("room_id = ?", roomID).Delete(&entities.RoomDevice{}).Error; err != nil {
+			return err
+		}
+		room.Devices = roomDevices(roomID, deviceIDs)
+		if len(room.Devices) == 0 {
+			return nil
+		}
+		return tx.Create(&room.Devices).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update room: %w", err)
+	}
+
+	uc.refreshMembership()
+	dto := roomToDTO(room)
+	return &dto, nil
+}
+
+// DeleteRoom removes a room. The devices themselves are not changed.
+//
+// param roomID The room ID.
+// return error ErrRoomNotFound if the room does not exist, or ErrRoomsUnavailable.
+func (uc *RoomUseCase) DeleteRoom(roomID string) error {
+	if _, err := uc.loadRoom(roomID); err != nil {
+		return err
+	}
+
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("room_id = ?", roomID).Delete(&entities.RoomDevice{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", roomID).Delete(&entities.Room{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete room: %w", err)
+	}
+
+	uc.refreshMembership()
+	utils.LogInfo("RoomUseCase: Deleted room %s", roomID)
+	return nil
+}
+
+// ImportRooms writes rooms from a backup, keeping their IDs and timestamps. Rooms with the same ID are
+// overwritten including their devices; with replace, rooms missing from the backup are removed as well.
+// Devices are not checked against Tuya, as a restore may run before the devices are reachable.
+//
+// param rooms The rooms to write.
+// param replace Whether existing rooms missing from rooms are removed.
+// return int The number of rooms written.
+// return error ErrRoomsUnavailable, a conflict error when a name is used by another room, or a database error.
+func (uc *RoomUseCase) ImportRooms(rooms []dtos.RoomDTO, replace bool) (int, error) {
+	if uc.db == nil {
+		return 0, ErrRoomsUnavailable
+	}
+
+	err := uc.db.Transaction(func(tx *gorm.DB) error {
+		if replace {
+			if err := tx.Where("1 = 1").Delete(&entities.RoomDevice{}).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("1 = 1").Delete(&entities.Room{}).Error; err != nil {
+				return err
+			}
+		}
+		for _, room := range rooms {
+			var clash entities.Room
+			err := tx.Where("name = ? AND id <> ?", room.Name, room.ID).First(&clash).Error
+			if err == nil {
+				return tuya_errors.Conflict("room name %q of backup room %s is used by room %s", room.Name, room.ID, clash.ID)
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				return err
+			}
+
+			entity := entities.Room{ID: room.ID, Name: room.Name, CreatedAt: room.CreatedAt, UpdatedAt: room.UpdatedAt}
+			if err := tx.Save(&entity).Error; err != nil {
+				return err
+			}
+			if err := tx.Where("room_id = ?", room.ID).Delete(&entities.RoomDevice{}).Error; err != nil {
+				return err
+			}
+			if devices := roomDevices(room.ID, room.DeviceIDs); len(devices) > 0 {
+				if err := tx.Create(&devices).Error; err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to import rooms: %w", err)
+	}
+
+	uc.refreshMembership()
+	utils.LogInfo("RoomUseCase: Imported %d rooms", len(rooms))
+	return len(rooms), nil
+}
+
+// SendCommands sends the same commands to every device of a room.
+// A failure on one device does not stop the others.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param roomID The room ID.
+// param commands The commands to send.
+// return *dtos.RoomCommandResponseDTO The outcome per device.
+// return error ErrRoomNotFound, ErrRoomsUnavailable, or a bad request error for an empty room.
+func (uc *RoomUseCase) SendCommands(ctx context.Context, accessToken, roomID string, commands []dtos.TuyaCommandDTO) (*dtos.RoomCommandResponseDTO, error) {
+	room, err := uc.loadRoom(roomID)
+	if err != nil {
+		return nil, err
+	}
+	if len(room.Devices) == 0 {
+		return nil, tuya_errors.BadRequest("room %s has no devices", room.Name)
+	}
+
+	result := &dtos.RoomCommandResponseDTO{
+		RoomID:  roomID,
+		Success: true,
+		Devices: make([]dtos.RoomDeviceResultDTO, 0, len(room.Devices)),
+	}
+	for _, device := range room.Devices {
+		deviceResult := dtos.RoomDeviceResultDTO{DeviceID: device.DeviceID}
+		if _, err := uc.controlUC.SendCommand(ctx, accessToken, device.DeviceID, commands); err != nil {
+			deviceResult.Error = err.Error()
+			result.Success = false
+		} else {
+			deviceResult.Success = true
+		}
+		result.Devices = append(result.Devices, deviceResult)
+	}
+
+	utils.LogInfo("RoomUseCase: Sent %d commands to room %s (success: %t)", len(commands), room.Name, result.Success)
+	return result, nil
+}
+
+// loadRoom reads a room with its devices.
+func (uc *RoomUseCase) loadRoom(roomID string) (*entities.Room, error) {
+	if uc.db == nil {
+		return nil, ErrRoomsUnavailable
+	}
+
+	var room entities.Room
+	err := uc.db.Preload("Devices").Where("id = ?", roomID).First(&room).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRoomNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get room: %w", err)
+	}
+	return &room, nil
+}
+
+// checkNameAvailable rejects a name already used by another room.
+func (uc *RoomUseCase) checkNameAvailable(name, exceptID string) error {
+	var count int64
+	query := uc.db.Model(&entities.Room{}).Where("name = ?", name)
+	if exceptID != "" {
+		query = query.Where("id <> ?", exceptID)
+	}
+	if err := query.Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to check room name: %w", err)
+	}
+	if count > 0 {
+		return tuya_errors.BadRequest("a room named %s already exists", name)
+	}
+	return nil
+}
+
+// validateDevices removes duplicates and checks that every device exists.
+func (uc *RoomUseCase) validateDevices(ctx context.Context, accessToken string, deviceIDs []string) ([]string, error) {
+	seen := make(map[string]bool, len(deviceIDs))
+	unique := make([]string, 0, len(deviceIDs))
+	for _, deviceID := range deviceIDs {
+		if deviceID == "" || seen[deviceID] {
+			continue
+		}
+		seen[deviceID] = true
+		if _, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID); err != nil {
+			return nil, tuya_errors.BadRequest("device %s cannot be read: %v", deviceID, err)
+		}
+		unique = append(unique, deviceID)
+	}
+	return unique, nil
+}
+
+// refreshMembership reloads room membership after a change, logging failures.
+func (uc *RoomUseCase) refreshMembership() {
+	if err := uc.reloadMembership(); err != nil {
+		utils.LogWarn("RoomUseCase: Failed to reload room membership: %v", err)
+	}
+}
+
+// reloadMembership rebuilds the device-to-rooms index from the database.
+func (uc *RoomUseCase) reloadMembership() error {
+	var assignments []entities.RoomDevice
+	if err := uc.db.Find(&assignments).Error; err != nil {
+		return fmt.Errorf("failed to load room membership: %w", err)
+	}
+
+	deviceRooms := make(map[string][]string)
+	for _, assignment := range assignments {
+		deviceRooms[assignment.DeviceID] = append(deviceRooms[assignment.DeviceID], assignment.RoomID)
+	}
+
+	uc.mu.Lock()
+	uc.deviceRooms = deviceRooms
+	uc.mu.Unlock()
+	return nil
+}
+
+// roomDevices builds the assignments of a room.
+func roomDevices(roomID string, deviceIDs []string) []entities.RoomDevice {
+	devices := make([]entities.RoomDevice, len(deviceIDs))
+	for i, deviceID := range deviceIDs {
+		devices[i] = entities.RoomDevice{RoomID: roomID, DeviceID: deviceID}
+	}
+	return devices
+}
+
+// roomToDTO converts a room entity to its DTO with device IDs in a stable order.
+func roomToDTO(room *entities.Room) dtos.RoomDTO {
+	deviceIDs := make([]string, len(room.Devices))
+	for i, device := range room.Devices {
+		deviceIDs[i] = device.DeviceID
+	}
+	sort.Strings(deviceIDs)
+	return dtos.RoomDTO{
+		ID:        room.ID,
+		Name:      room.Name,
+		DeviceIDs: deviceIDs,
+		CreatedAt: room.CreatedAt,
+		UpdatedAt: room.UpdatedAt,
+	}
+}
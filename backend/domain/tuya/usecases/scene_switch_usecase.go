@@ -0,0 +1,336 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// sceneSwitchCategory is the Tuya category of wireless scene switches.
+const sceneSwitchCategory = "wxkg"
+
+// SceneSwitchActionDeviceCommands is the built-in action type that sends commands to a device.
+const SceneSwitchActionDeviceCommands = "device_commands"
+
+// sceneSwitchButtonPattern extracts the button number from scene switch DP codes (switch1_value, switch_type_2, ...).
+var sceneSwitchButtonPattern = regexp.MustCompile(`^switch_?(?:type_|mode)?(\d+)(?:_value)?$`)
+
+// SceneSwitchActionHandler runs a non-device action (e.g., a scene or automation) identified by targetID.
+// The context carries the owner of the binding as the caller (see utils.APIKeyIdentityFromContext).
+type SceneSwitchActionHandler func(ctx context.Context, targetID string) error
+
+// SceneSwitchUseCase maps buttons and press types of wireless scene switches to actions and runs them on events.
+// Bindings are persistent so they survive cache flushes. Button presses are not tied to a client request, so
+// bindings record the API key (or identity) that saved them and their actions run with its permissions.
+type SceneSwitchUseCase struct {
+	cache       persistence.CacheStore
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	authUC      *TuyaAuthUseCase
+	authz       CallerAuthorizer
+
+	mu       sync.RWMutex
+	handlers map[string]SceneSwitchActionHandler
+}
+
+// NewSceneSwitchUseCase initializes a new SceneSwitchUseCase.
+//
+// param cache The CacheStore used to persist bindings.
+// param getDeviceUC The usecase used to verify that a device is a scene switch.
+// param controlUC The usecase used to run device command actions.
+// param authUC The TuyaAuthUseCase used to obtain a server-side token when events arrive outside a request.
+// param authz The CallerAuthorizer checking the API key of the binding owner.
+// return *SceneSwitchUseCase A pointer to the initialized usecase.
+func NewSceneSwitchUseCase(cache persistence.CacheStore, getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, authz CallerAuthorizer) *SceneSwitchUseCase {
+	return &SceneSwitchUseCase{
+		cache:       cache,
+		getDeviceUC: getDeviceUC,
+		controlUC:   controlUC,
+		authUC:      authUC,
+		authz:       authz,
+		handlers:    make(map[string]SceneSwitchActionHandler),
+	}
+}
+
+// RegisterActionHandler makes an action type (e.g., "scene", "automation") available to bindings.
+//
+// param actionType The action type name.
+// param handler The function running the action.
+func (uc *SceneSwitchUseCase) RegisterActionHandler(actionType string, handler SceneSwitchActionHandler) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.handlers[actionType] = handler
+}
+
+// GetBindings returns the bindings configured for a scene switch.
+//
+// param switchID The scene switch device ID.
+// return *dtos.SceneSwitchBindingsResponseDTO The configured bindings.
+// return error An error if the bindings cannot be read.
+func (uc *SceneSwitchUseCase) GetBindings(switchID string) (*dtos.SceneSwitchBindingsResponseDTO, error) {
+	bindings, err := uc.loadBindings(switchID)
+	if err != nil {
+		return nil, err
+	}
+	return toSceneSwitchBindingsDTO(switchID, bindings), nil
+}
+
+// SetBindings replaces all bindings of a scene switch after validating the device and each action.
+// The caller must hold control scope and may access the switch and every device commanded by the bindings;
+// the bindings then run with the caller's permissions.
+//
+// param ctx The request context carrying the caller.
+// param accessToken The valid OAuth 2.0 access token.
+// param switchID The scene switch device ID.
+// param bindings The new bindings.
+// return *dtos.SceneSwitchBindingsResponseDTO The saved bindings.
+// return error A bad request error for invalid input, or a forbidden error if the caller may not access a device.
+func (uc *SceneSwitchUseCase) SetBindings(ctx context.Context, accessToken, switchID string, bindings []dtos.SceneSwitchBindingDTO) (*dtos.SceneSwitchBindingsResponseDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("binding storage not initialized")
+	}
+	deviceIDs := []string{switchID}
+	for _, b := range bindings {
+		if b.Action.Type == SceneSwitchActionDeviceCommands {
+			deviceIDs = append(deviceIDs, b.Action.DeviceID)
+		}
+	}
+	owner, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, deviceIDs...)
+	if err != nil {
+		return nil, err
+	}
+
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, switchID)
+	if err != nil {
+		return nil, err
+	}
+	if device.Category != sceneSwitchCategory {
+		return nil, tuya_errors.BadRequest("device %s is category %s, not a scene switch", switchID, device.Category)
+	}
+
+	seen := make(map[string]bool)
+	entityBindings := make([]entities.SceneSwitchBinding, 0, len(bindings))
+	for _, b := range bindings {
+		pressType := normalizePressType(b.PressType)
+		if pressType == "" {
+			return nil, tuya_errors.BadRequest("unsupported press_type %q (use single_click, double_click or long_press)", b.PressType)
+		}
+		key := fmt.Sprintf("%d:%s", b.Button, pressType)
+		if seen[key] {
+			return nil, tuya_errors.BadRequest("duplicate binding for button %d %s", b.Button, pressType)
+		}
+		seen[key] = true
+
+		if err := uc.validateAction(b.Action); err != nil {
+			return nil, err
+		}
+
+		commands := make([]entities.TuyaCommand, len(b.Action.Commands))
+		for i, cmd := range b.Action.Commands {
+			commands[i] = entities.TuyaCommand{Code: cmd.Code, Value: cmd.Value}
+		}
+		entityBindings = append(entityBindings, entities.SceneSwitchBinding{
+			Button:    b.Button,
+			PressType: pressType,
+			Action: entities.SceneSwitchAction{
+				Type:     b.Action.Type,
+				TargetID: b.Action.TargetID,
+				DeviceID: b.Action.DeviceID,
+				Commands: commands,
+			},
+			Owner: owner,
+		})
+	}
+
+	jsonData, err := json.Marshal(entityBindings)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal scene switch bindings: %w", err)
+	}
+	if err := uc.cache.SetPersistent(sceneSwitchKey(switchID), jsonData); err != nil {
+		return nil, fmt.Errorf("failed to save scene switch bindings: %w", err)
+	}
+
+	utils.LogInfo("SceneSwitchUseCase: Saved %d bindings for switch %s", len(entityBindings), switchID)
+	return toSceneSwitchBindingsDTO(switchID, entityBindings), nil
+}
+
+// ReportEvent runs the action bound to a button event reported through the API. Since the action runs with the
+// permissions of the binding owner, the reporter must hold control scope and may access the switch.
+//
+// param ctx The request context carrying the caller.
+// param switchID The scene switch device ID.
+// param code The DP code of the event (e.g., "switch1_value").
+// param value The press type reported by the switch (e.g., "single_click").
+// return *dtos.SceneSwitchEventResultDTO Whether a binding matched.
+// return error A forbidden error if the caller may not access the switch, or an error if the bound action fails.
+func (uc *SceneSwitchUseCase) ReportEvent(ctx context.Context, switchID, code string, value interface{}) (*dtos.SceneSwitchEventResultDTO, error) {
+	if _, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, switchID); err != nil {
+		return nil, err
+	}
+	return uc.HandleEvent(ctx, switchID, code, value)
+}
+
+// HandleEvent runs the action bound to a scene switch button event.
+// Events without a matching binding are ignored.
+//
+// param ctx The context of the event source.
+// param switchID The scene switch device ID.
+// param code The DP code of the event (e.g., "switch1_value").
+// param value The press type reported by the switch (e.g., "single_click").
+// return *dtos.SceneSwitchEventResultDTO Whether a binding matched.
+// return error An error if the bound action fails.
+func (uc *SceneSwitchUseCase) HandleEvent(ctx context.Context, switchID, code string, value interface{}) (*dtos.SceneSwitchEventResultDTO, error) {
+	match := sceneSwitchButtonPattern.FindStringSubmatch(code)
+	if match == nil {
+		return &dtos.SceneSwitchEventResultDTO{Matched: false}, nil
+	}
+	button, _ := strconv.Atoi(match[1])
+	pressType := normalizePressType(fmt.Sprint(value))
+
+	bindings, err := uc.loadBindings(switchID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, b := range bindings {
+		if b.Button != button || b.PressType != pressType {
+			continue
+		}
+
+		utils.LogInfo("SceneSwitchUseCase: Switch %s button %d %s -> %s", switchID, button, pressType, b.Action.Type)
+		if err := uc.runAction(ctx, b); err != nil {
+			return nil, fmt.Errorf("failed to run %s action: %w", b.Action.Type, err)
+		}
+		return &dtos.SceneSwitchEventResultDTO{
+			Matched:   true,
+			Button:    button,
+			PressType: pressType,
+			Action:    b.Action.Type,
+		}, nil
+	}
+
+	utils.LogDebug("SceneSwitchUseCase: No binding for switch %s button %d %s", switchID, button, pressType)
+	return &dtos.SceneSwitchEventResultDTO{Matched: false, Button: button, PressType: pressType}, nil
+}
+
+// validateAction checks that an action is complete and its type is available.
+func (uc *SceneSwitchUseCase) validateAction(action dtos.SceneSwitchActionDTO) error {
+	if action.Type == SceneSwitchActionDeviceCommands {
+		if action.DeviceID == "" || len(action.Commands) == 0 {
+			return tuya_errors.BadRequest("device_commands actions require device_id and commands")
+		}
+		return nil
+	}
+
+	uc.mu.RLock()
+	_, ok := uc.handlers[action.Type]
+	uc.mu.RUnlock()
+	if !ok {
+		return tuya_errors.BadRequest("unsupported action type %q", action.Type)
+	}
+	if action.TargetID == "" {
+		return tuya_errors.BadRequest("%s actions require target_id", action.Type)
+	}
+	return nil
+}
+
+// runAction executes a bound action on behalf of the owner of the binding. Device commands use a server-side
+// token because events are not tied to a client request, once the owner is checked to still control the device.
+func (uc *SceneSwitchUseCase) runAction(ctx context.Context, binding entities.SceneSwitchBinding) error {
+	action := binding.Action
+	if action.Type == SceneSwitchActionDeviceCommands {
+		if err := authorizeCaller(uc.authz, binding.Owner, utils.APIKeyScopeControl, action.DeviceID); err != nil {
+			return err
+		}
+		token, err := uc.authUC.GetServerToken(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to obtain token: %w", err)
+		}
+		commands := make([]dtos.TuyaCommandDTO, len(action.Commands))
+		for i, cmd := range action.Commands {
+			commands[i] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+		}
+		_, err = uc.controlUC.SendCommand(ctx, token.AccessToken, action.DeviceID, commands)
+		return err
+	}
+
+	uc.mu.RLock()
+	handler, ok := uc.handlers[action.Type]
+	uc.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("no handler registered for action type %s", action.Type)
+	}
+	return handler(utils.ContextWithAPIKeyIdentity(ctx, binding.Owner), action.TargetID)
+}
+
+// loadBindings reads the persisted bindings of a scene switch.
+func (uc *SceneSwitchUseCase) loadBindings(switchID string) ([]entities.SceneSwitchBinding, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("binding storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(sceneSwitchKey(switchID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get scene switch bindings: %w", err)
+	}
+	if jsonData == nil {
+		return []entities.SceneSwitchBinding{}, nil
+	}
+	var bindings []entities.SceneSwitchBinding
+	if err := json.Unmarshal(jsonData, &bindings); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal scene switch bindings: %w", err)
+	}
+	return bindings, nil
+}
+
+// sceneSwitchKey builds the storage key for the bindings of a scene switch.
+func sceneSwitchKey(switchID string) string {
+	return fmt.Sprintf("scene_switch:%s", switchID)
+}
+
+// normalizePressType maps the press values reported by different switch models onto
+// single_click, double_click and long_press. Unknown values return an empty string.
+func normalizePressType(value string) string {
+	switch value {
+	case "single_click", "click", "single", "0":
+		return "single_click"
+	case "double_click", "double", "1":
+		return "double_click"
+	case "long_press", "press", "hold", "2":
+		return "long_press"
+	default:
+		return ""
+	}
+}
+
+// toSceneSwitchBindingsDTO converts stored bindings into the response DTO.
+func toSceneSwitchBindingsDTO(switchID string, bindings []entities.SceneSwitchBinding) *dtos.SceneSwitchBindingsResponseDTO {
+	bindingDTOs := make([]dtos.SceneSwitchBindingDTO, len(bindings))
+	for i, b := range bindings {
+		commands := make([]dtos.TuyaCommandDTO, len(b.Action.Commands))
+		for j, cmd := range b.Action.Commands {
+			commands[j] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+		}
+		bindingDTOs[i] = dtos.SceneSwitchBindingDTO{
+			Button:    b.Button,
+			PressType: b.PressType,
+			Action: dtos.SceneSwitchActionDTO{
+				Type:     b.Action.Type,
+				TargetID: b.Action.TargetID,
+				DeviceID: b.Action.DeviceID,
+				Commands: commands,
+			},
+		}
+	}
+	return &dtos.SceneSwitchBindingsResponseDTO{
+		SwitchID: switchID,
+		Bindings: bindingDTOs,
+	}
+}
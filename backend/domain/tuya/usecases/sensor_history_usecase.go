@@ -0,0 +1,163 @@
+package usecases
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	"time"
+)
+
+// sensorHistoryPrefix is the key prefix of hourly sensor rollups: "sensor_history:{device_id}:{hour}".
+const sensorHistoryPrefix = "sensor_history:"
+
+// SensorHistoryUseCase rolls sensor readings up into hourly buckets.
+// Only numeric status values are kept; buckets are persistent until the archiver exports them.
+type SensorHistoryUseCase struct {
+	cache persistence.CacheStore
+	clock utils.Clock
+	mu    sync.Mutex
+}
+
+// NewSensorHistoryUseCase initializes a new SensorHistoryUseCase.
+//
+// param cache The CacheStore used to persist the rollups.
+// param clock The Clock used to pick the bucket of a reading.
+// return *SensorHistoryUseCase A pointer to the initialized usecase.
+func NewSensorHistoryUseCase(cache persistence.CacheStore, clock utils.Clock) *SensorHistoryUseCase {
+	return &SensorHistoryUseCase{
+		cache: cache,
+		clock: clock,
+	}
+}
+
+// Record adds a sensor reading to the bucket of the current hour.
+//
+// param deviceID The device ID of the sensor.
+// param status The reported status values; non-numeric values are ignored.
+// return error An error if the bucket cannot be read or saved.
+func (uc *SensorHistoryUseCase) Record(deviceID string, status []dtos.TuyaDeviceStatusDTO) error {
+	if uc.cache == nil {
+		return nil
+	}
+
+	hour := uc.clock.Now().UTC().Truncate(time.Hour).Unix()
+	key := sensorHistoryKey(deviceID, hour)
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	bucket := &entities.SensorHistoryBucket{DeviceID: deviceID, Hour: hour, Codes: map[string]*entities.SensorRollup{}}
+	data, err := uc.cache.Get(key)
+	if err != nil {
+		return fmt.Errorf("failed to get sensor history: %w", err)
+	}
+	if data != nil {
+		if err := json.Unmarshal(data, bucket); err != nil {
+			utils.LogWarn("SensorHistoryUseCase: Bucket %s corrupted, starting over: %v", key, err)
+			bucket.Codes = map[string]*entities.SensorRollup{}
+		}
+	}
+
+	recorded := false
+	for _, s := range status {
+		value, ok := numericValue(s.Value)
+		if !ok {
+			continue
+		}
+		rollup, exists := bucket.Codes[s.Code]
+		if !exists {
+			rollup = &entities.SensorRollup{Min: value, Max: value}
+			bucket.Codes[s.Code] = rollup
+		}
+		rollup.Count++
+		rollup.Sum += value
+		rollup.Last = value
+		if value < rollup.Min {
+			rollup.Min = value
+		}
+		if value > rollup.Max {
+			rollup.Max = value
+		}
+		recorded = true
+	}
+	if !recorded {
+		return nil
+	}
+
+	data, err = json.Marshal(bucket)
+	if err != nil {
+		return fmt.Errorf("failed to marshal sensor history: %w", err)
+	}
+	if err := uc.cache.SetPersistent(key, data); err != nil {
+		return fmt.Errorf("failed to save sensor history: %w", err)
+	}
+	return nil
+}
+
+// sensorHistoryKey builds the key of a bucket. The hour is zero-padded so keys of a device sort by time.
+func sensorHistoryKey(deviceID string, hour int64) string {
+	return fmt.Sprintf("%s%s:%012d", sensorHistoryPrefix, deviceID, hour)
+}
+
+// numericValue converts a status value to a float when it is numeric. Booleans count as 0 or 1.
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case bool:
+		if v {
+			return 1, true
+		}
+		return 0, true
+	default:
+		return 0, false
+	}
+}
+
+// GetBuckets returns the hourly buckets of a sensor overlapping [from, to), oldest first.
+//
+// param deviceID The device ID of the sensor.
+// param from The start of the range (inclusive).
+// param to The end of the range (exclusive).
+// return []entities.SensorHistoryBucket The buckets in the range; archived buckets are not included.
+// return error An error if the buckets cannot be listed.
+func (uc *SensorHistoryUseCase) GetBuckets(deviceID string, from, to time.Time) ([]entities.SensorHistoryBucket, error) {
+	if uc.cache == nil {
+		return nil, nil
+	}
+
+	keys, err := uc.cache.GetAllKeysWithPrefix(fmt.Sprintf("%s%s:", sensorHistoryPrefix, deviceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list sensor history: %w", err)
+	}
+
+	fromKey := sensorHistoryKey(deviceID, from.UTC().Truncate(time.Hour).Unix())
+	toKey := sensorHistoryKey(deviceID, to.Unix())
+	var buckets []entities.SensorHistoryBucket
+	for _, key := range keys {
+		if key < fromKey || key >= toKey {
+			continue
+		}
+		data, err := uc.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var bucket entities.SensorHistoryBucket
+		if err := json.Unmarshal(data, &bucket); err != nil {
+			utils.LogWarn("SensorHistoryUseCase: Skipping malformed bucket %s: %v", key, err)
+			continue
+		}
+		buckets = append(buckets, bucket)
+	}
+	return buckets, nil
+}
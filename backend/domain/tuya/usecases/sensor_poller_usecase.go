@@ -0,0 +1,417 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+	"time"
+)
+
+const (
+	defaultSensorPollInterval = 30 * time.Second
+
+	// sensorBatchSize is the maximum number of device IDs Tuya accepts per batch status call.
+	sensorBatchSize = 20
+	// sensorBatchWindow is how long the poller waits for more queued reads before calling Tuya.
+	sensorBatchWindow = 50 * time.Millisecond
+	// sensorQueueSize bounds the number of queued device IDs.
+	sensorQueueSize = 256
+)
+
+// sensorCategories lists the categories whose status is served from the shared snapshot.
+var sensorCategories = map[string]bool{
+	"wsdcg": true, // temperature and humidity
+	"sj":    true, // water leak
+	"ywbj":  true, // smoke
+}
+
+// sensorSnapshot is the last known status of a sensor.
+type sensorSnapshot struct {
+	category  string
+	online    bool
+	status    []dtos.TuyaDeviceStatusDTO
+	fetchedAt time.Time
+}
+
+// SensorPollerUseCase serves sensor reads from a shared status snapshot.
+// Known sensors are refreshed together through Tuya's batch status endpoint, and reads of stale sensors are
+// queued so concurrent requests are answered by a single batch call instead of one device call each.
+// Changed values and online/offline transitions seen by background refreshes are published as realtime
+// events, so subscribers are notified of polled sensors too.
+type SensorPollerUseCase struct {
+	service          *services.TuyaDeviceService
+	getDeviceUseCase *TuyaGetDeviceByIDUseCase
+	authUC           *TuyaAuthUseCase
+	automationUC     *AutomationUseCase
+	realtimeHub      *realtime_services.RealtimeHubService
+	baseInterval     time.Duration
+	queue            chan string
+	clock            utils.Clock
+
+	mu        sync.Mutex
+	interval  time.Duration
+	ticker    *time.Ticker
+	snapshots map[string]*sensorSnapshot
+	waiters   map[string][]chan error
+
+	startOnce  sync.Once
+	workerOnce sync.Once
+	workers    utils.WorkerGroup
+}
+
+// NewSensorPollerUseCase initializes a new SensorPollerUseCase.
+//
+// param service The TuyaDeviceService used for batch status calls.
+// param getDeviceUseCase The usecase used to learn the category of a sensor on its first read.
+// param authUC The TuyaAuthUseCase providing the server-managed token for batch calls.
+// param automationUC The usecase evaluating automation rules against fetched status (optional).
+// param realtimeHub The RealtimeHubService notified of changed values and online/offline transitions (optional).
+// param clock The Clock used for snapshot age.
+// return *SensorPollerUseCase A pointer to the initialized usecase.
+func NewSensorPollerUseCase(service *services.TuyaDeviceService, getDeviceUseCase *TuyaGetDeviceByIDUseCase, authUC *TuyaAuthUseCase, automationUC *AutomationUseCase, realtimeHub *realtime_services.RealtimeHubService, clock utils.Clock) *SensorPollerUseCase {
+	interval, err := time.ParseDuration(utils.GetConfig().SensorPollInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultSensorPollInterval
+	}
+
+	return &SensorPollerUseCase{
+		service:          service,
+		getDeviceUseCase: getDeviceUseCase,
+		authUC:           authUC,
+		automationUC:     automationUC,
+		realtimeHub:      realtimeHub,
+		baseInterval:     interval,
+		interval:         interval,
+		queue:            make(chan string, sensorQueueSize),
+		snapshots:        make(map[string]*sensorSnapshot),
+		waiters:          make(map[string][]chan error),
+		clock:            clock,
+	}
+}
+
+// Start refreshes all known sensors in the background at SENSOR_POLL_INTERVAL.
+func (uc *SensorPollerUseCase) Start() {
+	uc.startOnce.Do(func() {
+		uc.startWorker()
+		uc.mu.Lock()
+		uc.ticker = time.NewTicker(uc.interval)
+		ticker, interval := uc.ticker, uc.interval
+		uc.mu.Unlock()
+
+		uc.workers.Go(func(stop <-chan struct{}) {
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					uc.refreshAll()
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("SensorPollerUseCase: Started with interval %s", interval)
+	})
+}
+
+// Stop ends background refreshes and the batch worker, waiting for a batch call in progress to finish.
+func (uc *SensorPollerUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// SetInterval changes how often known sensors are refreshed (e.g., more often while the house is away).
+//
+// param interval The new interval; zero restores SENSOR_POLL_INTERVAL.
+func (uc *SensorPollerUseCase) SetInterval(interval time.Duration) {
+	if interval <= 0 {
+		interval = uc.baseInterval
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	if uc.interval == interval {
+		return
+	}
+	uc.interval = interval
+	if uc.ticker != nil {
+		uc.ticker.Reset(interval)
+	}
+	utils.LogInfo("SensorPollerUseCase: Interval changed to %s", interval)
+}
+
+// Read returns a sensor with its latest status.
+// The first read of a device fetches its details to learn the category; later reads are served from the
+// snapshot, waiting for the next batch call when the snapshot is older than the poll interval.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The caller's access token, used to fetch devices that are not known yet.
+// param deviceID The device ID of the sensor.
+// return *dtos.TuyaDeviceDTO The device with ID, category, online flag and status.
+// return error An error if the device cannot be fetched and no snapshot exists.
+func (uc *SensorPollerUseCase) Read(ctx context.Context, accessToken, deviceID string) (*dtos.TuyaDeviceDTO, error) {
+	snapshot := uc.snapshot(deviceID)
+	if snapshot == nil {
+		device, err := uc.getDeviceUseCase.GetDeviceByID(ctx, accessToken, deviceID)
+		if err != nil {
+			return nil, err
+		}
+		if sensorCategories[device.Category] {
+			uc.store(device.ID, device.Category, device.Online, device.Status)
+		}
+		return device, nil
+	}
+
+	if uc.clock.Now().Sub(snapshot.fetchedAt) >= uc.currentInterval() {
+		if err := uc.waitForPoll(ctx, deviceID); err != nil {
+			// A slightly old reading is more useful than an error
+			utils.LogWarn("SensorPollerUseCase: Serving stale snapshot for %s: %v", deviceID, err)
+		}
+		snapshot = uc.snapshot(deviceID)
+	}
+
+	return &dtos.TuyaDeviceDTO{
+		ID:       deviceID,
+		Category: snapshot.category,
+		Online:   snapshot.online,
+		Status:   snapshot.status,
+	}, nil
+}
+
+// waitForPoll queues a device for the next batch call and waits for its result.
+func (uc *SensorPollerUseCase) waitForPoll(ctx context.Context, deviceID string) error {
+	uc.startWorker()
+
+	done := make(chan error, 1)
+	uc.mu.Lock()
+	queued := len(uc.waiters[deviceID]) > 0
+	uc.waiters[deviceID] = append(uc.waiters[deviceID], done)
+	uc.mu.Unlock()
+
+	if !queued {
+		select {
+		case uc.queue <- deviceID:
+		case <-ctx.Done():
+			uc.release(deviceID, ctx.Err())
+			return ctx.Err()
+		}
+	}
+
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// startWorker launches the goroutine draining the queue into batch calls.
+func (uc *SensorPollerUseCase) startWorker() {
+	uc.workerOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			for {
+				var deviceID string
+				select {
+				case deviceID = <-uc.queue:
+				case <-stop:
+					return
+				}
+				batch := []string{deviceID}
+				timer := time.NewTimer(sensorBatchWindow)
+			collect:
+				for len(batch) < sensorBatchSize {
+					select {
+					case id := <-uc.queue:
+						if !containsString(batch, id) {
+							batch = append(batch, id)
+						}
+					case <-timer.C:
+						break collect
+					}
+				}
+				timer.Stop()
+				uc.poll(batch)
+			}
+		})
+	})
+}
+
+// refreshAll queues every known sensor. IDs that do not fit in the queue are picked up on the next tick.
+func (uc *SensorPollerUseCase) refreshAll() {
+	uc.mu.Lock()
+	var ids []string
+	for id := range uc.snapshots {
+		if len(uc.waiters[id]) == 0 {
+			ids = append(ids, id)
+		}
+	}
+	uc.mu.Unlock()
+
+	for _, id := range ids {
+		select {
+		case uc.queue <- id:
+		default:
+			utils.LogWarn("SensorPollerUseCase: Queue full, deferring %d sensors", len(ids))
+			return
+		}
+	}
+}
+
+// poll refreshes a batch of sensors and wakes the reads waiting on them.
+func (uc *SensorPollerUseCase) poll(deviceIDs []string) {
+	ctx := context.Background()
+	err := uc.fetchBatch(ctx, deviceIDs)
+	if err != nil {
+		utils.LogWarn("SensorPollerUseCase: Batch status for %d sensors failed: %v", len(deviceIDs), err)
+	} else {
+		utils.LogDebug("SensorPollerUseCase: Refreshed %d sensors with one batch call", len(deviceIDs))
+	}
+	for _, id := range deviceIDs {
+		uc.release(id, err)
+	}
+}
+
+// fetchBatch fetches the status of a batch of sensors with the server-managed token and updates their snapshots.
+//
+// Tuya API Documentation (Get Device Status in Bulk):
+// URL: /v1.0/iot-03/devices/status?device_ids={ids} (/v1.0/devices/status for Smart Home projects)
+// Method: GET
+func (uc *SensorPollerUseCase) fetchBatch(ctx context.Context, deviceIDs []string) error {
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	strategy := uc.service.Endpoints().Strategy()
+	urlPath := strategy.BatchStatusPath(deviceIDs)
+
+	resp, err := uc.service.FetchBatchDeviceStatus(ctx, urlPath, token.AccessToken)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return tuya_errors.FromTuya("failed to fetch batch status", resp.Code, resp.Msg)
+	}
+
+	for _, item := range resp.Result {
+		status := make([]dtos.TuyaDeviceStatusDTO, len(item.Status))
+		for i, s := range item.Status {
+			status[i] = dtos.TuyaDeviceStatusDTO{Code: s.Code, Value: s.Value}
+		}
+		online := item.IsOnline
+		uc.mu.Lock()
+		var previous *sensorSnapshot
+		if snapshot, ok := uc.snapshots[item.ID]; ok {
+			copied := *snapshot
+			previous = &copied
+			// Smart Home projects report no online state with the status, so the listed one is kept
+			if !strategy.StatusReportsOnline() {
+				online = snapshot.online
+			}
+			snapshot.online = online
+			snapshot.status = status
+			snapshot.fetchedAt = uc.clock.Now()
+		}
+		uc.mu.Unlock()
+
+		if previous != nil {
+			uc.publishChanges(item.ID, previous, online, status)
+		}
+
+		if uc.automationUC != nil {
+			uc.automationUC.HandleStatus(item.ID, status)
+		}
+	}
+	return nil
+}
+
+// publishChanges publishes an online/offline transition and the status values that differ from the previous snapshot.
+func (uc *SensorPollerUseCase) publishChanges(deviceID string, previous *sensorSnapshot, online bool, status []dtos.TuyaDeviceStatusDTO) {
+	if uc.realtimeHub == nil {
+		return
+	}
+	now := uc.clock.Now().Unix()
+	if online != previous.online {
+		eventType := "device_offline"
+		if online {
+			eventType = "device_online"
+		}
+		uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+			Type:      eventType,
+			DeviceID:  deviceID,
+			Category:  previous.category,
+			Timestamp: now,
+		})
+	}
+
+	old := make(map[string]string, len(previous.status))
+	for _, s := range previous.status {
+		old[s.Code] = fmt.Sprint(s.Value)
+	}
+	var changed []realtime_dtos.DeviceEventStatusDTO
+	for _, s := range status {
+		if value, ok := old[s.Code]; !ok || value != fmt.Sprint(s.Value) {
+			changed = append(changed, realtime_dtos.DeviceEventStatusDTO{Code: s.Code, Value: s.Value})
+		}
+	}
+	if len(changed) == 0 {
+		return
+	}
+	uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+		Type:      "device_state",
+		DeviceID:  deviceID,
+		Category:  previous.category,
+		Status:    changed,
+		Timestamp: now,
+	})
+}
+
+// currentInterval returns the refresh interval in effect.
+func (uc *SensorPollerUseCase) currentInterval() time.Duration {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	return uc.interval
+}
+
+// release wakes everyone waiting on a device.
+func (uc *SensorPollerUseCase) release(deviceID string, err error) {
+	uc.mu.Lock()
+	waiters := uc.waiters[deviceID]
+	delete(uc.waiters, deviceID)
+	uc.mu.Unlock()
+
+	for _, done := range waiters {
+		done <- err
+	}
+}
+
+// snapshot returns a copy of the snapshot of a device, or nil if the device is not known.
+func (uc *SensorPollerUseCase) snapshot(deviceID string) *sensorSnapshot {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	snapshot, ok := uc.snapshots[deviceID]
+	if !ok {
+		return nil
+	}
+	copied := *snapshot
+	return &copied
+}
+
+// store records the status of a sensor fetched outside a batch call.
+func (uc *SensorPollerUseCase) store(deviceID, category string, online bool, status []dtos.TuyaDeviceStatusDTO) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.snapshots[deviceID] = &sensorSnapshot{
+		category:  category,
+		online:    online,
+		status:    status,
+		fetchedAt: uc.clock.Now(),
+	}
+	if uc.automationUC != nil {
+		uc.automationUC.HandleStatus(deviceID, status)
+	}
+}
@@ -0,0 +1,395 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const defaultStandbyKillerInterval = time.Minute
+
+// ErrStandbyKillerRuleNotFound is returned when a plug has no standby killer rule.
+var ErrStandbyKillerRuleNotFound = tuya_errors.NotFound("standby killer rule not found")
+
+// standbyPowerCodes are the status codes reporting the current power draw of metered plugs.
+var standbyPowerCodes = []string{"cur_power", "power"}
+
+// StandbyKillerUseCase switches metered plugs off when their power draw stays below a per-plug threshold
+// for a per-plug duration. A periodic checker reads fresh power readings and tracks how long each plug
+// has been below its threshold.
+type StandbyKillerUseCase struct {
+	cache       persistence.CacheStore
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	categoryUC  *TuyaCategoryControlUseCase
+	authUC      *TuyaAuthUseCase
+	clock       utils.Clock
+
+	interval  time.Duration
+	checkMu   sync.Mutex
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewStandbyKillerUseCase initializes a new StandbyKillerUseCase.
+// The check interval is read from STANDBY_KILLER_INTERVAL.
+//
+// param cache The CacheStore used to persist rules and tracking state.
+// param getDeviceUC The usecase used to read power readings.
+// param controlUC The usecase used to switch plugs off.
+// param categoryUC The usecase providing cached device specifications.
+// param authUC The TuyaAuthUseCase used to obtain a server-side token for background checks.
+// param clock The Clock used to time standby periods.
+// return *StandbyKillerUseCase A pointer to the initialized usecase.
+func NewStandbyKillerUseCase(cache persistence.CacheStore, getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, categoryUC *TuyaCategoryControlUseCase, authUC *TuyaAuthUseCase, clock utils.Clock) *StandbyKillerUseCase {
+	interval, err := time.ParseDuration(utils.GetConfig().StandbyKillerInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultStandbyKillerInterval
+	}
+
+	return &StandbyKillerUseCase{
+		cache:       cache,
+		getDeviceUC: getDeviceUC,
+		controlUC:   controlUC,
+		categoryUC:  categoryUC,
+		authUC:      authUC,
+		interval:    interval,
+		clock:       clock,
+	}
+}
+
+// Start launches the periodic checker. Checks are no-ops while no rules are enabled.
+func (uc *StandbyKillerUseCase) Start() {
+	uc.startOnce.Do(func() {
+		utils.LogInfo("StandbyKillerUseCase: Checker started (interval %s)", uc.interval)
+		uc.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(uc.interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					uc.Check(context.Background())
+				case <-stop:
+					return
+				}
+			}
+		})
+	})
+}
+
+// Stop ends the checker and waits for a check in progress to finish.
+func (uc *StandbyKillerUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// ListRules returns all standby killer rules with their tracking state.
+//
+// return []dtos.StandbyKillerRuleDTO The rules ordered by creation time.
+// return error An error if the rules cannot be read.
+func (uc *StandbyKillerUseCase) ListRules() ([]dtos.StandbyKillerRuleDTO, error) {
+	rules, err := uc.loadRules()
+	if err != nil {
+		return nil, err
+	}
+
+	ruleDTOs := make([]dtos.StandbyKillerRuleDTO, 0, len(rules))
+	for _, rule := range rules {
+		ruleDTOs = append(ruleDTOs, toStandbyKillerRuleDTO(rule, uc.loadState(rule.DeviceID)))
+	}
+	return ruleDTOs, nil
+}
+
+// SetRule creates or replaces the standby killer rule of a metered plug.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The plug's device ID.
+// param req The rule settings.
+// return *dtos.StandbyKillerRuleDTO The stored rule.
+// return error A bad request error if the device cannot be metered or switched.
+func (uc *StandbyKillerUseCase) SetRule(ctx context.Context, accessToken, deviceID string, req dtos.StandbyKillerRuleRequestDTO) (*dtos.StandbyKillerRuleDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("standby killer storage not initialized")
+	}
+
+	spec, err := uc.categoryUC.getSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := findStatusFunction(spec, standbyPowerCodes); !ok {
+		return nil, tuya_errors.BadRequest("device %s does not report power consumption", deviceID)
+	}
+
+	switchCode := req.SwitchCode
+	if switchCode == "" {
+		fn, ok := findFunction(spec, []string{"switch_1", "switch"})
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s has no switch to turn off", deviceID)
+		}
+		switchCode = fn.Code
+	} else if _, ok := findFunction(spec, []string{switchCode}); !ok || !strings.HasPrefix(switchCode, "switch") {
+		return nil, tuya_errors.BadRequest("device %s has no switch %s", deviceID, switchCode)
+	}
+
+	rule := entities.StandbyKillerRule{
+		DeviceID:        deviceID,
+		Enabled:         req.Enabled == nil || *req.Enabled,
+		ThresholdWatts:  req.ThresholdWatts,
+		DurationMinutes: req.DurationMinutes,
+		SwitchCode:      switchCode,
+		CreatedAt:       uc.clock.Now().Unix(),
+	}
+	if existing, err := uc.loadRule(deviceID); err == nil {
+		rule.CreatedAt = existing.CreatedAt
+	}
+
+	jsonData, err := json.Marshal(rule)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal standby killer rule: %w", err)
+	}
+	if err := uc.cache.SetPersistent(standbyKillerKey(deviceID), jsonData); err != nil {
+		return nil, fmt.Errorf("failed to save standby killer rule: %w", err)
+	}
+
+	// Start tracking from scratch so a changed threshold does not inherit the old timer.
+	state := uc.loadState(deviceID)
+	state.BelowSince = 0
+	uc.saveState(deviceID, state)
+
+	utils.LogInfo("StandbyKillerUseCase: Saved rule for %s (< %.1f W for %d min)", deviceID, rule.ThresholdWatts, rule.DurationMinutes)
+	ruleDTO := toStandbyKillerRuleDTO(rule, state)
+	return &ruleDTO, nil
+}
+
+// DeleteRule removes the standby killer rule of a plug.
+//
+// param deviceID The plug's device ID.
+// return error ErrStandbyKillerRuleNotFound if the plug has no rule.
+func (uc *StandbyKillerUseCase) DeleteRule(deviceID string) error {
+	if _, err := uc.loadRule(deviceID); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(standbyKillerKey(deviceID)); err != nil {
+		return fmt.Errorf("failed to delete standby killer rule: %w", err)
+	}
+	_ = uc.cache.Delete(standbyKillerStateKey(deviceID))
+
+	utils.LogInfo("StandbyKillerUseCase: Deleted rule for %s", deviceID)
+	return nil
+}
+
+// Check evaluates every enabled rule once, switching off plugs that have been below their threshold long enough.
+// Failures are logged per plug and do not stop the other checks.
+//
+// param ctx The context of the check run.
+func (uc *StandbyKillerUseCase) Check(ctx context.Context) {
+	uc.checkMu.Lock()
+	defer uc.checkMu.Unlock()
+
+	rules, err := uc.loadRules()
+	if err != nil {
+		utils.LogWarn("StandbyKillerUseCase: Failed to load rules: %v", err)
+		return
+	}
+
+	var token string
+	for _, rule := range rules {
+		if !rule.Enabled {
+			continue
+		}
+		if token == "" {
+			auth, err := uc.authUC.GetServerToken(ctx)
+			if err != nil {
+				utils.LogWarn("StandbyKillerUseCase: Failed to obtain token: %v", err)
+				return
+			}
+			token = auth.AccessToken
+		}
+		if err := uc.checkRule(ctx, token, rule, uc.clock.Now()); err != nil {
+			utils.LogWarn("StandbyKillerUseCase: Check failed for %s: %v", rule.DeviceID, err)
+		}
+	}
+}
+
+// checkRule reads the plug's power and switch state, updates the below-threshold timer and switches the plug off when due.
+func (uc *StandbyKillerUseCase) checkRule(ctx context.Context, accessToken string, rule entities.StandbyKillerRule, now time.Time) error {
+	spec, err := uc.categoryUC.getSpecification(ctx, accessToken, rule.DeviceID)
+	if err != nil {
+		return err
+	}
+	powerFn, ok := findStatusFunction(spec, standbyPowerCodes)
+	if !ok {
+		return fmt.Errorf("device no longer reports power consumption")
+	}
+
+	// Power readings change constantly, so always read past the device cache.
+	if err := uc.cache.Delete(fmt.Sprintf("cache:tuya_device:%s", rule.DeviceID)); err != nil {
+		utils.LogDebug("StandbyKillerUseCase: Failed to invalidate cache for %s: %v", rule.DeviceID, err)
+	}
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, rule.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	var on bool
+	var watts float64
+	var hasPower bool
+	for _, status := range device.Status {
+		switch status.Code {
+		case rule.SwitchCode:
+			on, _ = status.Value.(bool)
+		case powerFn.Code:
+			if raw, ok := status.Value.(float64); ok {
+				watts = raw / math.Pow(10, valueOr(parseFunctionValues(powerFn).Scale, 0))
+				hasPower = true
+			}
+		}
+	}
+
+	state := uc.loadState(rule.DeviceID)
+	state.LastCheckedAt = now.Unix()
+	if hasPower {
+		state.LastWatts = watts
+	}
+	defer func() { uc.saveState(rule.DeviceID, state) }()
+
+	if !on || !hasPower || watts >= rule.ThresholdWatts {
+		state.BelowSince = 0
+		return nil
+	}
+
+	if state.BelowSince == 0 {
+		state.BelowSince = now.Unix()
+		utils.LogDebug("StandbyKillerUseCase: %s below %.1f W (%.1f W), timer started", rule.DeviceID, rule.ThresholdWatts, watts)
+		return nil
+	}
+	if now.Sub(time.Unix(state.BelowSince, 0)) < time.Duration(rule.DurationMinutes)*time.Minute {
+		return nil
+	}
+
+	commands := []dtos.TuyaCommandDTO{{Code: rule.SwitchCode, Value: false}}
+	if _, err := uc.controlUC.SendCommand(ctx, accessToken, rule.DeviceID, commands); err != nil {
+		return fmt.Errorf("failed to switch off: %w", err)
+	}
+
+	utils.LogInfo("StandbyKillerUseCase: Switched off %s after %d min below %.1f W (last %.1f W)", rule.DeviceID, rule.DurationMinutes, rule.ThresholdWatts, watts)
+	state.BelowSince = 0
+	state.LastKilledAt = now.Unix()
+	return nil
+}
+
+// loadRules reads all standby killer rules ordered by creation time.
+func (uc *StandbyKillerUseCase) loadRules() ([]entities.StandbyKillerRule, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("standby killer storage not initialized")
+	}
+	keys, err := uc.cache.GetAllKeysWithPrefix("standby_killer:rule:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list standby killer rules: %w", err)
+	}
+
+	rules := make([]entities.StandbyKillerRule, 0, len(keys))
+	for _, key := range keys {
+		rule, err := uc.loadRule(strings.TrimPrefix(key, "standby_killer:rule:"))
+		if err != nil {
+			utils.LogWarn("StandbyKillerUseCase: Skipping unreadable rule %s: %v", key, err)
+			continue
+		}
+		rules = append(rules, *rule)
+	}
+
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].CreatedAt < rules[j].CreatedAt
+	})
+	return rules, nil
+}
+
+// loadRule reads the rule of one plug.
+func (uc *StandbyKillerUseCase) loadRule(deviceID string) (*entities.StandbyKillerRule, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("standby killer storage not initialized")
+	}
+	jsonData, err := uc.cache.Get(standbyKillerKey(deviceID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get standby killer rule: %w", err)
+	}
+	if jsonData == nil {
+		return nil, ErrStandbyKillerRuleNotFound
+	}
+	var rule entities.StandbyKillerRule
+	if err := json.Unmarshal(jsonData, &rule); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal standby killer rule: %w", err)
+	}
+	return &rule, nil
+}
+
+// loadState reads the tracking state of a plug. Missing or unreadable state starts empty.
+func (uc *StandbyKillerUseCase) loadState(deviceID string) entities.StandbyKillerState {
+	var state entities.StandbyKillerState
+	if uc.cache == nil {
+		return state
+	}
+	if jsonData, err := uc.cache.Get(standbyKillerStateKey(deviceID)); err == nil && jsonData != nil {
+		_ = json.Unmarshal(jsonData, &state)
+	}
+	return state
+}
+
+// saveState persists the tracking state of a plug.
+func (uc *StandbyKillerUseCase) saveState(deviceID string, state entities.StandbyKillerState) {
+	jsonData, err := json.Marshal(state)
+	if err != nil {
+		return
+	}
+	if err := uc.cache.SetPersistent(standbyKillerStateKey(deviceID), jsonData); err != nil {
+		utils.LogWarn("StandbyKillerUseCase: Failed to save state for %s: %v", deviceID, err)
+	}
+}
+
+// standbyKillerKey builds the storage key of a plug's rule.
+func standbyKillerKey(deviceID string) string {
+	return fmt.Sprintf("standby_killer:rule:%s", deviceID)
+}
+
+// standbyKillerStateKey builds the storage key of a plug's tracking state.
+func standbyKillerStateKey(deviceID string) string {
+	return fmt.Sprintf("standby_killer:state:%s", deviceID)
+}
+
+// findStatusFunction returns the first specification status matching one of the candidate codes.
+func findStatusFunction(spec *entities.TuyaDeviceSpecification, codes []string) (entities.TuyaDeviceFunction, bool) {
+	for _, code := range codes {
+		for _, fn := range spec.Status {
+			if fn.Code == code {
+				return fn, true
+			}
+		}
+	}
+	return entities.TuyaDeviceFunction{}, false
+}
+
+// toStandbyKillerRuleDTO combines a rule and its tracking state into the response form.
+func toStandbyKillerRuleDTO(rule entities.StandbyKillerRule, state entities.StandbyKillerState) dtos.StandbyKillerRuleDTO {
+	return dtos.StandbyKillerRuleDTO{
+		DeviceID:        rule.DeviceID,
+		Enabled:         rule.Enabled,
+		ThresholdWatts:  rule.ThresholdWatts,
+		DurationMinutes: rule.DurationMinutes,
+		SwitchCode:      rule.SwitchCode,
+		CreatedAt:       rule.CreatedAt,
+		BelowSince:      state.BelowSince,
+		LastWatts:       state.LastWatts,
+		LastCheckedAt:   state.LastCheckedAt,
+		LastKilledAt:    state.LastKilledAt,
+	}
+}
@@ -0,0 +1,237 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+	"time"
+)
+
+// defaultStateRestoreDelay is the wait after startup before states are replayed, so devices that lost
+// power together with the server have reconnected.
+const defaultStateRestoreDelay = 2 * time.Minute
+
+// irACRestoreOrder is the order IR AC settings are replayed in; the AC must be on before modes apply.
+var irACRestoreOrder = []string{"power", "mode", "temp", "wind"}
+
+// irACDefaultState is the IR AC state assumed for settings that were never sent: off, cool, 24°C, auto fan.
+var irACDefaultState = map[string]int{"power": 0, "mode": 0, "temp": 24, "wind": 0}
+
+// StateRestoreUseCase brings whitelisted devices back to their last known settings after a power outage.
+// Device states are persisted on every command and status report, so they are captured when the usecase
+// is created, before devices rebooting into their defaults report new states. After RESTORE_ON_BOOT_DELAY
+// the captured settings that differ from the current status are sent again: writable codes for regular
+// devices, and power, mode, temperature and fan speed for IR ACs. Each device gets an audit entry.
+// The mode is off unless RESTORE_ON_BOOT_DEVICES lists devices.
+type StateRestoreUseCase struct {
+	deviceStateUC *DeviceStateUseCase
+	service       *services.TuyaDeviceService
+	specUC        *DeviceSpecificationUseCase
+	controlUC     *TuyaDeviceControlUseCase
+	authUC        *TuyaAuthUseCase
+	auditLogUC    *AuditLogUseCase
+
+	deviceIDs []string
+	delay     time.Duration
+	captured  map[string][]dtos.DeviceStateCommandDTO
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewStateRestoreUseCase initializes a new StateRestoreUseCase and captures the persisted states of the
+// whitelisted devices. It must be created before device events are processed.
+// The devices and delay are read from RESTORE_ON_BOOT_DEVICES and RESTORE_ON_BOOT_DELAY.
+//
+// param deviceStateUC The usecase holding the persisted device states.
+// param service The TuyaDeviceService reading the current status of a device, past the device cache.
+// param specUC The usecase providing the writable codes of a device.
+// param controlUC The usecase sending the restored settings.
+// param authUC The TuyaAuthUseCase used to obtain a server-side token.
+// param auditLogUC The AuditLogUseCase recording each restore (optional).
+// return *StateRestoreUseCase A pointer to the initialized usecase.
+func NewStateRestoreUseCase(deviceStateUC *DeviceStateUseCase, service *services.TuyaDeviceService, specUC *DeviceSpecificationUseCase, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, auditLogUC *AuditLogUseCase) *StateRestoreUseCase {
+	config := utils.GetConfig()
+	delay, err := time.ParseDuration(config.RestoreOnBootDelay)
+	if err != nil || delay < 0 {
+		delay = defaultStateRestoreDelay
+	}
+
+	uc := &StateRestoreUseCase{
+		deviceStateUC: deviceStateUC,
+		service:       service,
+		specUC:        specUC,
+		controlUC:     controlUC,
+		authUC:        authUC,
+		auditLogUC:    auditLogUC,
+		delay:         delay,
+		captured:      make(map[string][]dtos.DeviceStateCommandDTO),
+	}
+	for _, id := range strings.Split(config.RestoreOnBootDevices, ",") {
+		if id = strings.TrimSpace(id); id != "" {
+			uc.deviceIDs = append(uc.deviceIDs, id)
+		}
+	}
+
+	for _, deviceID := range uc.deviceIDs {
+		state, err := deviceStateUC.GetDeviceState(deviceID)
+		if err != nil {
+			utils.LogWarn("StateRestoreUseCase: Failed to capture state of %s: %v", deviceID, err)
+			continue
+		}
+		if state != nil && len(state.LastCommands) > 0 {
+			uc.captured[deviceID] = state.LastCommands
+		}
+	}
+	return uc
+}
+
+// Start replays the captured states once after the restore delay. It does nothing without whitelisted devices.
+func (uc *StateRestoreUseCase) Start() {
+	if len(uc.deviceIDs) == 0 {
+		return
+	}
+	uc.startOnce.Do(func() {
+		utils.LogInfo("StateRestoreUseCase: Restoring %d of %d devices in %s", len(uc.captured), len(uc.deviceIDs), uc.delay)
+		uc.workers.Go(func(stop <-chan struct{}) {
+			select {
+			case <-time.After(uc.delay):
+				uc.RestoreAll(context.Background())
+			case <-stop:
+			}
+		})
+	})
+}
+
+// Stop cancels a pending restore and waits for a restore in progress to finish.
+func (uc *StateRestoreUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// RestoreAll replays the captured state of every whitelisted device.
+//
+// param ctx The context used for the Tuya calls.
+func (uc *StateRestoreUseCase) RestoreAll(ctx context.Context) {
+	if len(uc.captured) == 0 {
+		return
+	}
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		utils.LogError("StateRestoreUseCase: Skipping restore, no server token: %v", err)
+		return
+	}
+
+	for _, deviceID := range uc.deviceIDs {
+		commands, ok := uc.captured[deviceID]
+		if !ok {
+			continue
+		}
+		restored, err := uc.restore(ctx, token.AccessToken, deviceID, commands)
+		uc.audit(deviceID, restored, err)
+		if err != nil {
+			utils.LogWarn("StateRestoreUseCase: Failed to restore %s: %v", deviceID, err)
+			continue
+		}
+		utils.LogInfo("StateRestoreUseCase: Restored %d settings of %s", len(restored), deviceID)
+	}
+}
+
+// restore sends the captured settings of a device that differ from its current status.
+// It returns the settings that were sent.
+func (uc *StateRestoreUseCase) restore(ctx context.Context, accessToken, deviceID string, captured []dtos.DeviceStateCommandDTO) ([]dtos.DeviceStateCommandDTO, error) {
+	// The cached details may still hold the pre-outage status, so read the device fresh
+	deviceResponse, err := uc.service.FetchDeviceByID(ctx, fmt.Sprintf("/v1.0/devices/%s", deviceID), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !deviceResponse.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch device", deviceResponse.Code, deviceResponse.Msg)
+	}
+	device := deviceResponse.Result
+	if !device.Online {
+		return nil, fmt.Errorf("device is offline")
+	}
+
+	if device.Category == "infrared_ac" {
+		return uc.restoreIRAC(ctx, accessToken, device.ID, device.GatewayID, captured)
+	}
+
+	spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	writable := make(map[string]bool, len(spec.Functions))
+	for _, fn := range spec.Functions {
+		writable[fn.Code] = true
+	}
+	current := make(map[string]interface{}, len(device.Status))
+	for _, status := range device.Status {
+		current[status.Code] = status.Value
+	}
+
+	var restored []dtos.DeviceStateCommandDTO
+	var commands []dtos.TuyaCommandDTO
+	for _, cmd := range captured {
+		if !writable[cmd.Code] {
+			continue
+		}
+		if value, ok := current[cmd.Code]; ok && reflect.DeepEqual(value, cmd.Value) {
+			continue
+		}
+		restored = append(restored, cmd)
+		commands = append(commands, dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value})
+	}
+	if len(commands) == 0 {
+		return nil, nil
+	}
+	if _, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands); err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// restoreIRAC replays the settings of an IR AC. IR ACs do not report their state, so every captured
+// setting is sent; a captured "off" only sends the power command.
+func (uc *StateRestoreUseCase) restoreIRAC(ctx context.Context, accessToken, remoteID, infraredID string, captured []dtos.DeviceStateCommandDTO) ([]dtos.DeviceStateCommandDTO, error) {
+	values := make(map[string]int, len(captured))
+	for _, cmd := range captured {
+		if value, ok := numericValue(cmd.Value); ok {
+			values[cmd.Code] = int(value)
+		}
+	}
+	if infraredID == "" {
+		infraredID = remoteID
+	}
+
+	var restored []dtos.DeviceStateCommandDTO
+	for _, code := range irACRestoreOrder {
+		value, ok := values[code]
+		if !ok {
+			continue
+		}
+		if _, err := uc.controlUC.SendIRACCommand(ctx, accessToken, infraredID, remoteID, code, value); err != nil {
+			return restored, err
+		}
+		restored = append(restored, dtos.DeviceStateCommandDTO{Code: code, Value: value})
+		if code == "power" && value == 0 {
+			break
+		}
+	}
+	return restored, nil
+}
+
+// audit records the outcome of restoring a device.
+func (uc *StateRestoreUseCase) audit(deviceID string, restored []dtos.DeviceStateCommandDTO, err error) {
+	if uc.auditLogUC == nil {
+		return
+	}
+	if auditErr := uc.auditLogUC.Record(AuditActionStateRestore, deviceID, restored, err); auditErr != nil {
+		utils.LogWarn("StateRestoreUseCase: Failed to record audit entry for %s: %v", deviceID, auditErr)
+	}
+}
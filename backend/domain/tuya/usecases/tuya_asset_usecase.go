@@ -0,0 +1,226 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+)
+
+const (
+	// maxAssetPageSize is the largest page of the asset list.
+	maxAssetPageSize = 100
+	// assetDevicePageSize is the page size used to walk the device IDs of an asset.
+	assetDevicePageSize = 100
+	// assetDeviceDetailBatchSize is the number of devices queried by ID in one call.
+	assetDeviceDetailBatchSize = 20
+)
+
+// TuyaAssetUseCase lists devices by asset. IoT Core projects group their devices in assets (buildings,
+// floors, rooms) and have no user UID to list devices by, so their devices are listed per asset instead.
+// The device list of an asset is cached like the user device list; metadata and visibility are applied
+// on every request.
+type TuyaAssetUseCase struct {
+	service   *services.TuyaAssetService
+	devicesUC *TuyaGetAllDevicesUseCase
+	cache     persistence.CacheStore
+	ttls      *persistence.CacheTTLPolicy
+}
+
+// NewTuyaAssetUseCase initializes a new TuyaAssetUseCase.
+//
+// param service The TuyaAssetService used for API communication.
+// param devicesUC The TuyaGetAllDevicesUseCase querying and converting the devices of an asset.
+// param cache The CacheStore used for caching the device lists of assets.
+// param ttls The CacheTTLPolicy deciding how long device lists stay cached.
+// return *TuyaAssetUseCase A pointer to the initialized usecase.
+func NewTuyaAssetUseCase(service *services.TuyaAssetService, devicesUC *TuyaGetAllDevicesUseCase, cache persistence.CacheStore, ttls *persistence.CacheTTLPolicy) *TuyaAssetUseCase {
+	return &TuyaAssetUseCase{
+		service:   service,
+		devicesUC: devicesUC,
+		cache:     cache,
+		ttls:      ttls,
+	}
+}
+
+// ListAssets returns one page of the project assets.
+//
+// Tuya API Documentation (Query Assets):
+// URL: /v1.0/iot-02/assets?asset_name={name}&page_no={page}&page_size={limit}
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param name Only list assets whose name contains it (empty for all).
+// param page The page number, starting at 1.
+// param limit The page size, at most 100.
+// return *dtos.TuyaAssetsResponseDTO The assets.
+// return error An error if the parameters are invalid or the API call fails.
+func (uc *TuyaAssetUseCase) ListAssets(ctx context.Context, accessToken, name string, page, limit int) (*dtos.TuyaAssetsResponseDTO, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > maxAssetPageSize {
+		return nil, tuya_errors.BadRequest("limit must be between 1 and %d", maxAssetPageSize)
+	}
+
+	query := url.Values{}
+	query.Set("asset_name", name)
+	query.Set("page_no", strconv.Itoa(page))
+	query.Set("page_size", strconv.Itoa(limit))
+	resp, err := uc.service.FetchAssets(ctx, "/v1.0/iot-02/assets?"+query.Encode(), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch assets", resp.Code, resp.Msg)
+	}
+
+	assets := make([]dtos.TuyaAssetDTO, len(resp.Result.List))
+	for i, a := range resp.Result.List {
+		assets[i] = dtos.TuyaAssetDTO{
+			AssetID:       a.AssetID,
+			Name:          a.AssetName,
+			ParentAssetID: a.ParentAssetID,
+		}
+	}
+	return &dtos.TuyaAssetsResponseDTO{
+		Assets:  assets,
+		Total:   resp.Result.Total,
+		Page:    page,
+		Limit:   limit,
+		HasMore: resp.Result.HasMore,
+	}, nil
+}
+
+// ListAssetDevices returns the devices of an asset, with their status and online state.
+//
+// Tuya API Documentation (Query Devices of an Asset, Query Devices by ID):
+// URL: /v1.0/iot-02/assets/{asset_id}/devices?page_size={n}&last_row_key={key}
+// URL: /v1.0/iot-03/devices?device_ids={ids}
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param assetID The asset ID.
+// param visible Limits the result to the devices the caller may see (nil for all).
+// return *dtos.TuyaAssetDevicesResponseDTO The devices of the asset.
+// return error An error if the asset ID is missing or an API call fails.
+func (uc *TuyaAssetUseCase) ListAssetDevices(ctx context.Context, accessToken, assetID string, visible DeviceFilter) (*dtos.TuyaAssetDevicesResponseDTO, error) {
+	assetID = strings.TrimSpace(assetID)
+	if assetID == "" {
+		return nil, tuya_errors.BadRequest("asset_id is required")
+	}
+
+	devices, err := uc.assetDevices(ctx, accessToken, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if uc.devicesUC.metadataUC != nil {
+		for i := range devices {
+			uc.devicesUC.metadataUC.ApplyMetadata(&devices[i])
+		}
+	}
+	devices = FilterDevices(devices, visible)
+
+	return &dtos.TuyaAssetDevicesResponseDTO{
+		AssetID:      assetID,
+		Devices:      devices,
+		TotalDevices: len(devices),
+	}, nil
+}
+
+// assetDevices returns the converted devices of an asset from the cache, or fetches and caches them.
+func (uc *TuyaAssetUseCase) assetDevices(ctx context.Context, accessToken, assetID string) ([]dtos.TuyaDeviceDTO, error) {
+	cacheKey := fmt.Sprintf("cache:assets:%s:devices", assetID)
+	if cachedData, err := uc.cache.WithContext(ctx).Get(cacheKey); err == nil && cachedData != nil {
+		var cached []dtos.TuyaDeviceDTO
+		if err := json.Unmarshal(cachedData, &cached); err == nil {
+			utils.RequestMetaFromContext(ctx).SetCache("hit")
+			return cached, nil
+		}
+	}
+
+	deviceIDs, err := uc.fetchAssetDeviceIDs(ctx, accessToken, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	devices := make([]dtos.TuyaDeviceDTO, 0, len(deviceIDs))
+	for start := 0; start < len(deviceIDs); start += assetDeviceDetailBatchSize {
+		end := start + assetDeviceDetailBatchSize
+		if end > len(deviceIDs) {
+			end = len(deviceIDs)
+		}
+		batch, err := uc.fetchDevicesByID(ctx, accessToken, deviceIDs[start:end])
+		if err != nil {
+			return nil, err
+		}
+		devices = append(devices, batch...)
+	}
+
+	if jsonData, err := json.Marshal(devices); err == nil {
+		uc.cache.WithContext(ctx).Set(cacheKey, jsonData, uc.ttls.TTL(persistence.CacheResourceDeviceList))
+	}
+	utils.RequestMetaFromContext(ctx).SetCache("miss")
+	return devices, nil
+}
+
+// fetchAssetDeviceIDs walks the device ID pages of an asset.
+func (uc *TuyaAssetUseCase) fetchAssetDeviceIDs(ctx context.Context, accessToken, assetID string) ([]string, error) {
+	var deviceIDs []string
+	cursor := ""
+	for {
+		query := url.Values{}
+		query.Set("page_size", strconv.Itoa(assetDevicePageSize))
+		if cursor != "" {
+			query.Set("last_row_key", cursor)
+		}
+		path := fmt.Sprintf("/v1.0/iot-02/assets/%s/devices?%s", url.PathEscape(assetID), query.Encode())
+		resp, err := uc.service.FetchAssetDevices(ctx, path, accessToken)
+		if err != nil {
+			return nil, err
+		}
+		if !resp.Success {
+			return nil, tuya_errors.FromTuya("failed to fetch asset devices", resp.Code, resp.Msg)
+		}
+
+		for _, d := range resp.Result.List {
+			deviceIDs = append(deviceIDs, d.DeviceID)
+		}
+		if !resp.Result.HasNext || resp.Result.LastRowKey == "" || len(resp.Result.List) == 0 {
+			return deviceIDs, nil
+		}
+		cursor = resp.Result.LastRowKey
+	}
+}
+
+// fetchDevicesByID queries the details of up to assetDeviceDetailBatchSize devices and converts them
+// like the user device list does.
+func (uc *TuyaAssetUseCase) fetchDevicesByID(ctx context.Context, accessToken string, deviceIDs []string) ([]dtos.TuyaDeviceDTO, error) {
+	resp, err := uc.devicesUC.service.FetchDevicePage(ctx, "/v1.0/iot-03/devices?device_ids="+strings.Join(deviceIDs, ","), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch asset devices", resp.Code, resp.Msg)
+	}
+
+	statusMap := uc.devicesUC.fetchOnlineStatus(ctx, accessToken, resp.Result.List)
+	devices := make([]dtos.TuyaDeviceDTO, 0, len(resp.Result.List))
+	for _, device := range resp.Result.List {
+		deviceDTO := uc.devicesUC.toDeviceDTO(device, statusMap)
+		if uc.devicesUC.channelUC != nil {
+			uc.devicesUC.channelUC.ApplyChannels(&deviceDTO)
+		}
+		devices = append(devices, deviceDTO)
+	}
+	return devices, nil
+}
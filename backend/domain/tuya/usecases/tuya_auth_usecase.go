@@ -1,90 +1,96 @@
 package usecases
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
+	"encoding/json"
 	"fmt"
-	"strconv"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
 	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
 	"teralux_app/domain/tuya/services"
 	"teralux_app/domain/common/utils"
-	tuya_utils "teralux_app/domain/tuya/utils"
 	"time"
 )
 
+// serverTokenKey is the persistent BadgerDB key holding the server-managed Tuya token.
+const serverTokenKey = "tuya_token:server"
+
 // TuyaAuthUseCase handles the core business logic for Tuya API authentication.
-// It orchestrates signature generation, timestamp creation, and service interaction.
+// Requests are signed by the TuyaClient behind the service.
+// It also manages a server-side token that is stored in BadgerDB and refreshed before it expires,
+// so background jobs and API-key-only clients do not request a new token for every call.
 type TuyaAuthUseCase struct {
-	service *services.TuyaAuthService
+	service   *services.TuyaAuthService
+	cache     persistence.CacheStore
+	mu        sync.Mutex
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+	clock     utils.Clock
 }
 
 // NewTuyaAuthUseCase creates a new instance of TuyaAuthUseCase.
 //
 // param service The TuyaAuthService used to perform the actual HTTP requests.
+// param cache The CacheStore used to store the server-managed token (optional).
+// param clock The Clock used for token expiry.
 // return *TuyaAuthUseCase A pointer to the initialized usecase.
-func NewTuyaAuthUseCase(service *services.TuyaAuthService) *TuyaAuthUseCase {
+func NewTuyaAuthUseCase(service *services.TuyaAuthService, cache persistence.CacheStore, clock utils.Clock) *TuyaAuthUseCase {
 	return &TuyaAuthUseCase{
 		service: service,
+		cache:   cache,
+		clock:   clock,
 	}
 }
 
 // Authenticate performs the full authentication flow to retrieve an access token.
-// It handles signature generation (HMAC-SHA256), timestamp creation, and header preparation.
 //
 // Tuya API Documentation (Get Token):
 // URL: https://openapi.tuyacn.com/v1.0/token?grant_type=1
 // Method: GET
 //
-// StringToSign Format:
-//   GET\n{content_hash}\n\n{url}
-//   (content_hash is SHA256 of empty string for GET)
-//
+// param ctx The request context, used for cancellation and timing metadata.
 // return *dtos.TuyaAuthResponseDTO The data transfer object containing the access token, refresh token, and expiration time.
-// return error An error if configuration is missing, signature generation fails, or the API call returns an error.
+// return error An error if configuration is missing or the API call returns an error.
 // @throws error if the API returns a non-success status code (e.g., invalid client ID).
-func (uc *TuyaAuthUseCase) Authenticate() (*dtos.TuyaAuthResponseDTO, error) {
-	// Get config
-	config := utils.GetConfig()
-
-	// Generate timestamp in milliseconds
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	signMethod := "HMAC-SHA256"
-
-	// Build URL path
-	urlPath := "/v1.0/token?grant_type=1"
-	fullURL := config.TuyaBaseURL + urlPath
-
-	// Calculate content hash (empty for GET request)
-	emptyContent := ""
-	h := sha256.New()
-	h.Write([]byte(emptyContent))
-	contentHash := hex.EncodeToString(h.Sum(nil))
+func (uc *TuyaAuthUseCase) Authenticate(ctx context.Context) (*dtos.TuyaAuthResponseDTO, error) {
+	return uc.requestToken(ctx, "/v1.0/token?grant_type=1")
+}
 
-	// Generate string to sign
-	stringToSign := tuya_utils.GenerateTuyaStringToSign("GET", contentHash, "", urlPath)
-	
-	utils.LogDebug("Authenticate: generating signature for clientId=%s", config.TuyaClientID)
+// RefreshToken exchanges a refresh token for a new access token (grant_type=2 refresh flow).
+//
+// Tuya API Documentation (Refresh Token):
+// URL: https://openapi.tuyacn.com/v1.0/token/{refresh_token}
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param refreshToken The refresh token issued together with the current access token.
+// return *dtos.TuyaAuthResponseDTO The renewed access token, refresh token, and expiration time.
+// return error An error if the refresh token is empty or rejected by the API.
+func (uc *TuyaAuthUseCase) RefreshToken(ctx context.Context, refreshToken string) (*dtos.TuyaAuthResponseDTO, error) {
+	if refreshToken == "" {
+		return nil, fmt.Errorf("refresh token is required")
+	}
+	return uc.requestToken(ctx, "/v1.0/token/"+refreshToken)
+}
 
-	// Generate signature
-	signature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, "", timestamp, stringToSign)
+// requestToken sends a token request. Token requests are signed without an access token.
+func (uc *TuyaAuthUseCase) requestToken(ctx context.Context, urlPath string) (*dtos.TuyaAuthResponseDTO, error) {
+	// Get config
+	config := utils.GetConfig()
 
-	// Prepare headers
-	headers := map[string]string{
-		"client_id":   config.TuyaClientID,
-		"sign":        signature,
-		"t":           timestamp,
-		"sign_method": signMethod,
-	}
+	utils.LogDebug("Authenticate: requesting token for clientId=%s", config.TuyaClientID)
 
 	// Call service to fetch token
-	authResponse, err := uc.service.FetchToken(fullURL, headers)
+	authResponse, err := uc.service.FetchToken(ctx, urlPath)
 	if err != nil {
 		return nil, err
 	}
 
 	// Validate response
 	if !authResponse.Success {
-		return nil, fmt.Errorf("tuya API authentication failed: %s (code: %d)", authResponse.Msg, authResponse.Code)
+		return nil, tuya_errors.FromTuya("authentication failed", authResponse.Code, authResponse.Msg)
 	}
 
 	// Transform entity to DTO
@@ -101,4 +107,161 @@ func (uc *TuyaAuthUseCase) Authenticate() (*dtos.TuyaAuthResponseDTO, error) {
 	}
 
 	return dto, nil
-}
\ No newline at end of file
+}
+
+// GetServerToken returns the server-managed Tuya token, renewing it when it is about to expire.
+// Renewal uses the stored refresh token first and falls back to a fresh grant when the refresh is rejected.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// return *dtos.TuyaAuthResponseDTO The valid token; ExpireTime holds the remaining lifetime in seconds.
+// return error An error if no token can be obtained from the API.
+func (uc *TuyaAuthUseCase) GetServerToken(ctx context.Context) (*dtos.TuyaAuthResponseDTO, error) {
+	if token := uc.loadServerToken(); token != nil && uc.clock.Now().Add(tokenRefreshMargin).Unix() < token.ExpiresAt {
+		return uc.serverTokenDTO(token), nil
+	}
+
+	// Serialize renewals so concurrent callers do not all hit the token endpoint
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	token := uc.loadServerToken()
+	if token != nil && uc.clock.Now().Add(tokenRefreshMargin).Unix() < token.ExpiresAt {
+		return uc.serverTokenDTO(token), nil
+	}
+
+	renewed, err := uc.renewServerToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+	return uc.serverTokenDTO(renewed), nil
+}
+
+// ServerAccessToken returns only the access token of the server-managed token.
+// It lets the auth middleware use the server token without depending on the DTO.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// return string The valid Tuya access token.
+// return error An error if no token can be obtained from the API.
+func (uc *TuyaAuthUseCase) ServerAccessToken(ctx context.Context) (string, error) {
+	token, err := uc.GetServerToken(ctx)
+	if err != nil {
+		return "", err
+	}
+	return token.AccessToken, nil
+}
+
+// Start keeps the server-managed token fresh in the background so requests never wait for a renewal.
+// It does nothing unless SERVER_MANAGED_TOKEN is enabled.
+func (uc *TuyaAuthUseCase) Start() {
+	if !utils.GetConfig().ServerManagedToken {
+		return
+	}
+
+	uc.startOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			for {
+				wait := time.Minute
+				token, err := uc.GetServerToken(context.Background())
+				if err != nil {
+					utils.LogError("TuyaAuthUseCase: Failed to renew server token: %v", err)
+				} else if remaining := time.Duration(token.ExpireTime)*time.Second - tokenRefreshMargin; remaining > 0 {
+					// Wake up just inside the refresh margin so the next call renews the token
+					wait = remaining + time.Second
+				}
+				select {
+				case <-time.After(wait):
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("TuyaAuthUseCase: Server-managed token refresher started")
+	})
+}
+
+// Stop ends the server-managed token refresher.
+func (uc *TuyaAuthUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// renewServerToken obtains a new token, preferring the refresh flow, and stores it.
+func (uc *TuyaAuthUseCase) renewServerToken(ctx context.Context, current *entities.TuyaServerToken) (*entities.TuyaServerToken, error) {
+	var (
+		token *dtos.TuyaAuthResponseDTO
+		err   error
+	)
+	if current != nil && current.RefreshToken != "" {
+		utils.LogInfo("TuyaAuthUseCase: Refreshing server token")
+		token, err = uc.RefreshToken(ctx, current.RefreshToken)
+		if err != nil {
+			utils.LogWarn("TuyaAuthUseCase: Refresh rejected, requesting a new token: %v", err)
+		}
+	}
+	if token == nil {
+		token, err = uc.Authenticate(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to obtain server token: %w", err)
+		}
+	}
+
+	now := uc.clock.Now()
+	stored := &entities.TuyaServerToken{
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		UID:          token.UID,
+		ExpiresAt:    now.Add(time.Duration(token.ExpireTime) * time.Second).Unix(),
+		UpdatedAt:    now.Unix(),
+	}
+	uc.saveServerToken(stored)
+	return stored, nil
+}
+
+// loadServerToken reads the stored server token. It returns nil when none is stored or storage is unavailable.
+func (uc *TuyaAuthUseCase) loadServerToken() *entities.TuyaServerToken {
+	if uc.cache == nil {
+		return nil
+	}
+	data, err := uc.cache.Get(serverTokenKey)
+	if err != nil || data == nil {
+		return nil
+	}
+	var token entities.TuyaServerToken
+	if err := json.Unmarshal(data, &token); err != nil {
+		utils.LogWarn("TuyaAuthUseCase: Ignoring malformed server token: %v", err)
+		return nil
+	}
+	return &token
+}
+
+// saveServerToken persists the server token. Failures are logged; the token is still returned to the caller.
+func (uc *TuyaAuthUseCase) saveServerToken(token *entities.TuyaServerToken) {
+	if uc.cache == nil {
+		return
+	}
+	data, err := json.Marshal(token)
+	if err != nil {
+		utils.LogWarn("TuyaAuthUseCase: Failed to encode server token: %v", err)
+		return
+	}
+	if err := uc.cache.SetPersistent(serverTokenKey, data); err != nil {
+		utils.LogWarn("TuyaAuthUseCase: Failed to store server token: %v", err)
+	}
+}
+
+// serverTokenDTO converts a stored token into the API shape with the remaining lifetime as ExpireTime.
+func (uc *TuyaAuthUseCase) serverTokenDTO(token *entities.TuyaServerToken) *dtos.TuyaAuthResponseDTO {
+	remaining := token.ExpiresAt - uc.clock.Now().Unix()
+	if remaining < 0 {
+		remaining = 0
+	}
+	uid := token.UID
+	if configUID := utils.GetConfig().TuyaUserID; configUID != "" {
+		uid = configUID
+	}
+	return &dtos.TuyaAuthResponseDTO{
+		AccessToken:  token.AccessToken,
+		ExpireTime:   int(remaining),
+		RefreshToken: token.RefreshToken,
+		UID:          uid,
+	}
+}
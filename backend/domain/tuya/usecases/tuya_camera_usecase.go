@@ -0,0 +1,136 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+	"time"
+)
+
+const (
+	// cameraCategory is the category of Tuya smart cameras (IPC).
+	cameraCategory = "sp"
+	// cameraStreamDefaultType is the stream protocol allocated when none is requested.
+	cameraStreamDefaultType = "hls"
+	// cameraMediaURLTTL is how long Tuya keeps an allocated stream or snapshot URL valid.
+	cameraMediaURLTTL = 10 * time.Minute
+)
+
+// cameraStreamTypes are the stream protocols Tuya can allocate.
+var cameraStreamTypes = []string{"hls", "rtsp", "flv", "rtmp"}
+
+// TuyaCameraUseCase allocates stream URLs and captures snapshots of Tuya smart cameras (category sp).
+// URLs are short-lived and allocated per request, so nothing is cached.
+type TuyaCameraUseCase struct {
+	service  *services.TuyaCameraService
+	deviceUC *TuyaGetDeviceByIDUseCase
+	clock    utils.Clock
+}
+
+// NewTuyaCameraUseCase initializes a new TuyaCameraUseCase.
+//
+// param service The TuyaCameraService used for API communication.
+// param deviceUC The TuyaGetDeviceByIDUseCase used to check that a device is a camera.
+// param clock The Clock used for URL expiry times.
+// return *TuyaCameraUseCase A pointer to the initialized usecase.
+func NewTuyaCameraUseCase(service *services.TuyaCameraService, deviceUC *TuyaGetDeviceByIDUseCase, clock utils.Clock) *TuyaCameraUseCase {
+	return &TuyaCameraUseCase{
+		service:  service,
+		deviceUC: deviceUC,
+		clock:    clock,
+	}
+}
+
+// GetStream allocates a stream URL of a camera.
+//
+// Tuya API Documentation (Allocate Stream):
+// URL: /v1.0/devices/{device_id}/stream/actions/allocate
+// Method: POST
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The camera ID.
+// param streamType The protocol: hls, rtsp, flv or rtmp (empty for hls).
+// return *dtos.CameraStreamDTO The stream URL and its expiry.
+// return error A bad request error for unknown protocols or non-camera devices.
+func (uc *TuyaCameraUseCase) GetStream(ctx context.Context, accessToken, deviceID, streamType string) (*dtos.CameraStreamDTO, error) {
+	if streamType == "" {
+		streamType = cameraStreamDefaultType
+	}
+	if !containsString(cameraStreamTypes, streamType) {
+		return nil, tuya_errors.BadRequest("type must be one of hls, rtsp, flv, rtmp")
+	}
+	if err := uc.requireCamera(ctx, accessToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(entities.TuyaStreamAllocateRequest{Type: streamType})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal stream request: %w", err)
+	}
+	resp, err := uc.service.AllocateStream(ctx, fmt.Sprintf("/v1.0/devices/%s/stream/actions/allocate", deviceID), accessToken, body)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya(fmt.Sprintf("failed to allocate %s stream", streamType), resp.Code, resp.Msg)
+	}
+
+	utils.LogDebug("GetStream: Allocated %s stream for camera %s", streamType, deviceID)
+	return &dtos.CameraStreamDTO{
+		DeviceID:  deviceID,
+		Type:      streamType,
+		URL:       resp.Result.URL,
+		ExpiresAt: uc.clock.Now().Add(cameraMediaURLTTL).Unix(),
+	}, nil
+}
+
+// GetSnapshot asks a camera to capture a picture and returns its URL.
+//
+// Tuya API Documentation (Capture Picture):
+// URL: /v1.0/devices/{device_id}/ipc/pic/capture
+// Method: POST
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The camera ID.
+// return *dtos.CameraSnapshotDTO The picture URL and its expiry.
+// return error A bad request error for non-camera devices.
+func (uc *TuyaCameraUseCase) GetSnapshot(ctx context.Context, accessToken, deviceID string) (*dtos.CameraSnapshotDTO, error) {
+	if err := uc.requireCamera(ctx, accessToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	resp, err := uc.service.CaptureSnapshot(ctx, fmt.Sprintf("/v1.0/devices/%s/ipc/pic/capture", deviceID), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to capture snapshot", resp.Code, resp.Msg)
+	}
+
+	now := uc.clock.Now()
+	return &dtos.CameraSnapshotDTO{
+		DeviceID:   deviceID,
+		URL:        resp.Result.URL,
+		CapturedAt: now.Unix(),
+		ExpiresAt:  now.Add(cameraMediaURLTTL).Unix(),
+	}, nil
+}
+
+// requireCamera rejects devices that are not cameras.
+func (uc *TuyaCameraUseCase) requireCamera(ctx context.Context, accessToken, deviceID string) error {
+	device, err := uc.deviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return err
+	}
+	if device.Category != cameraCategory {
+		return tuya_errors.BadRequest("device %s is category %s, not a camera", deviceID, device.Category)
+	}
+	return nil
+}
@@ -0,0 +1,256 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"strings"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// Categories handled by the normalized fan and dimmer endpoints.
+var (
+	fanCategories    = []string{"fs", "fskg"}
+	dimmerCategories = []string{"tgq"}
+)
+
+// DP code candidates per normalized capability, in order of preference.
+var (
+	fanPowerCodes       = []string{"switch", "fan_switch", "switch_fan"}
+	fanSpeedCodes       = []string{"fan_speed_percent", "fan_speed"}
+	fanOscillationCodes = []string{"switch_horizontal", "fan_horizontal", "switch_vertical"}
+	fanDirectionCodes   = []string{"fan_direction"}
+	dimmerPowerCodes    = []string{"switch_led_1", "switch_led", "switch_1"}
+	dimmerBrightCodes   = []string{"bright_value_1", "bright_value", "bright_value_v2"}
+)
+
+// TuyaCategoryControlUseCase translates normalized fan and dimmer requests into the DP codes a device actually exposes.
+// Values are validated against the device specification before anything is sent.
+type TuyaCategoryControlUseCase struct {
+	specUC    *DeviceSpecificationUseCase
+	controlUC *TuyaDeviceControlUseCase
+}
+
+// NewTuyaCategoryControlUseCase initializes a new TuyaCategoryControlUseCase.
+//
+// param specUC The DeviceSpecificationUseCase used to fetch (cached) device specifications.
+// param controlUC The TuyaDeviceControlUseCase used to send the translated commands.
+// return *TuyaCategoryControlUseCase A pointer to the initialized usecase.
+func NewTuyaCategoryControlUseCase(specUC *DeviceSpecificationUseCase, controlUC *TuyaDeviceControlUseCase) *TuyaCategoryControlUseCase {
+	return &TuyaCategoryControlUseCase{
+		specUC:    specUC,
+		controlUC: controlUC,
+	}
+}
+
+// ControlFan applies normalized fan settings to a fan (fs) or fan switch (fskg) device.
+// Speed is a level from 1 to the number of levels the device supports; percent-based fans map the level onto their range.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The ID of the fan.
+// param req The requested fan settings.
+// return *dtos.CategoryControlResponseDTO The DP commands that were sent.
+// return error A bad request error when the request does not fit the device.
+func (uc *TuyaCategoryControlUseCase) ControlFan(ctx context.Context, accessToken, deviceID string, req dtos.FanControlRequestDTO) (*dtos.CategoryControlResponseDTO, error) {
+	spec, err := uc.getSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(fanCategories, spec.Category) {
+		return nil, tuya_errors.BadRequest("device %s is category %s, not a fan", deviceID, spec.Category)
+	}
+
+	var commands []dtos.TuyaCommandDTO
+	if req.Power != nil {
+		fn, ok := findFunction(spec, fanPowerCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support power control", deviceID)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: *req.Power})
+	}
+	if req.Speed != nil {
+		fn, ok := findFunction(spec, fanSpeedCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support speed control", deviceID)
+		}
+		value, err := fanSpeedValue(fn, *req.Speed)
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: value})
+	}
+	if req.Oscillation != nil {
+		fn, ok := findFunction(spec, fanOscillationCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support oscillation", deviceID)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: *req.Oscillation})
+	}
+	if req.Direction != "" {
+		fn, ok := findFunction(spec, fanDirectionCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support direction control", deviceID)
+		}
+		values := parseFunctionValues(fn)
+		if !containsString(values.Range, req.Direction) {
+			return nil, tuya_errors.BadRequest("direction must be one of %s", strings.Join(values.Range, ", "))
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: req.Direction})
+	}
+
+	return uc.send(ctx, accessToken, deviceID, commands)
+}
+
+// ControlDimmer applies normalized dimmer settings to a dimmer (tgq) device.
+// Brightness is a percentage (1-100) scaled onto the device's raw brightness range.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The ID of the dimmer.
+// param req The requested dimmer settings.
+// return *dtos.CategoryControlResponseDTO The DP commands that were sent.
+// return error A bad request error when the request does not fit the device.
+func (uc *TuyaCategoryControlUseCase) ControlDimmer(ctx context.Context, accessToken, deviceID string, req dtos.DimmerControlRequestDTO) (*dtos.CategoryControlResponseDTO, error) {
+	spec, err := uc.getSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if !containsString(dimmerCategories, spec.Category) {
+		return nil, tuya_errors.BadRequest("device %s is category %s, not a dimmer", deviceID, spec.Category)
+	}
+
+	var commands []dtos.TuyaCommandDTO
+	if req.Power != nil {
+		fn, ok := findFunction(spec, dimmerPowerCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support power control", deviceID)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: *req.Power})
+	}
+	if req.Brightness != nil {
+		if *req.Brightness < 1 || *req.Brightness > 100 {
+			return nil, tuya_errors.BadRequest("brightness must be between 1 and 100")
+		}
+		fn, ok := findFunction(spec, dimmerBrightCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support brightness control", deviceID)
+		}
+		values := parseFunctionValues(fn)
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: scalePercent(*req.Brightness, values)})
+	}
+
+	return uc.send(ctx, accessToken, deviceID, commands)
+}
+
+// send forwards the translated commands through the standard control path (state saving, cache invalidation, events).
+func (uc *TuyaCategoryControlUseCase) send(ctx context.Context, accessToken, deviceID string, commands []dtos.TuyaCommandDTO) (*dtos.CategoryControlResponseDTO, error) {
+	if len(commands) == 0 {
+		return nil, tuya_errors.BadRequest("no settings provided")
+	}
+
+	utils.LogDebug("CategoryControl: Translated request for %s into %d commands", deviceID, len(commands))
+	success, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dtos.CategoryControlResponseDTO{
+		Success:  success,
+		Commands: commands,
+	}, nil
+}
+
+// getSpecification returns the device specification from the shared specification cache.
+// Other usecases building on the category control (groups, circadian, intents) resolve DP codes through it.
+func (uc *TuyaCategoryControlUseCase) getSpecification(ctx context.Context, accessToken, deviceID string) (*entities.TuyaDeviceSpecification, error) {
+	return uc.specUC.GetSpecification(ctx, accessToken, deviceID)
+}
+
+// functionValues is the parsed "values" JSON of a specification function.
+type functionValues struct {
+	Min   *float64 `json:"min"`
+	Max   *float64 `json:"max"`
+	Step  *float64 `json:"step"`
+	Scale *float64 `json:"scale"`
+	Range []string `json:"range"`
+}
+
+// parseFunctionValues decodes the values JSON of a specification function, ignoring malformed input.
+func parseFunctionValues(fn entities.TuyaDeviceFunction) functionValues {
+	var values functionValues
+	if fn.Values != "" {
+		_ = json.Unmarshal([]byte(fn.Values), &values)
+	}
+	return values
+}
+
+// findFunction returns the first specification function matching one of the candidate codes.
+func findFunction(spec *entities.TuyaDeviceSpecification, codes []string) (entities.TuyaDeviceFunction, bool) {
+	for _, code := range codes {
+		for _, fn := range spec.Functions {
+			if fn.Code == code {
+				return fn, true
+			}
+		}
+	}
+	return entities.TuyaDeviceFunction{}, false
+}
+
+// fanSpeedValue converts a speed level into the value expected by the device's speed function.
+// Enum speeds use the level as an index into the range, integer speeds accept the level directly when it fits
+// the range (stepped fans) and otherwise treat it as a percentage.
+func fanSpeedValue(fn entities.TuyaDeviceFunction, level int) (interface{}, error) {
+	values := parseFunctionValues(fn)
+
+	switch strings.ToLower(fn.Type) {
+	case "enum":
+		if level < 1 || level > len(values.Range) {
+			return nil, tuya_errors.BadRequest("speed must be between 1 and %d", len(values.Range))
+		}
+		return values.Range[level-1], nil
+	case "integer", "value":
+		if values.Min != nil && values.Max != nil && *values.Max <= 10 {
+			if float64(level) < *values.Min || float64(level) > *values.Max {
+				return nil, tuya_errors.BadRequest("speed must be between %d and %d", int(*values.Min), int(*values.Max))
+			}
+			return level, nil
+		}
+		if level < 1 || level > 100 {
+			return nil, tuya_errors.BadRequest("speed must be between 1 and 100")
+		}
+		return scalePercent(level, values), nil
+	default:
+		return nil, tuya_errors.BadRequest("unsupported speed type %s", fn.Type)
+	}
+}
+
+// scalePercent maps a 1-100 percentage onto a function's min/max range, honoring its step.
+func scalePercent(percent int, values functionValues) int {
+	min, max := 0.0, 100.0
+	if values.Min != nil {
+		min = *values.Min
+	}
+	if values.Max != nil {
+		max = *values.Max
+	}
+
+	raw := min + (max-min)*float64(percent)/100
+	if values.Step != nil && *values.Step > 1 {
+		raw = math.Round(raw / *values.Step) * *values.Step
+	}
+	return int(math.Round(math.Max(min, math.Min(max, raw))))
+}
+
+// containsString reports whether list contains value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,407 @@
+package usecases
+
+import (
+	"context"
+	"math"
+	"strings"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// Sources of a climate model.
+const (
+	ClimateSourceIR     = "ir"
+	ClimateSourceNative = "native"
+)
+
+// Normalized climate modes and fan speeds. The IR air conditioner API indexes them in this order
+// ("mode" 0-4, "wind" 0-3).
+var (
+	climateModes     = []string{"cool", "heat", "auto", "fan", "dry"}
+	climateFanSpeeds = []string{"auto", "low", "medium", "high"}
+)
+
+// nativeClimateCategories are the Wi-Fi air conditioners, AC controllers, thermostats and heaters with standard DPs.
+var nativeClimateCategories = []string{"kt", "ktkzq", "wk", "wkf", "qn"}
+
+// DP code candidates of native climate devices, in order of preference.
+var (
+	climatePowerCodes       = []string{"switch"}
+	climateModeCodes        = []string{"mode"}
+	climateTargetTempCodes  = []string{"temp_set", "set_temp"}
+	climateCurrentTempCodes = []string{"temp_current", "temp_indoor"}
+	climateFanSpeedCodes    = []string{"fan_speed_enum", "windspeed", "fan_speed"}
+	climateSwingCodes       = []string{"switch_vertical", "swing", "switch_horizontal"}
+)
+
+// Native DP values and the normalized mode or fan speed they stand for.
+var (
+	climateModeAliases = map[string]string{
+		"cold": "cool", "cool": "cool", "cooling": "cool",
+		"hot": "heat", "heat": "heat", "heating": "heat",
+		"auto": "auto", "smart": "auto",
+		"wind": "fan", "fan": "fan", "ventilation": "fan",
+		"wet": "dry", "dry": "dry", "dehumidification": "dry",
+	}
+	climateFanSpeedAliases = map[string]string{
+		"auto": "auto",
+		"low":  "low", "1": "low", "weak": "low",
+		"mid": "medium", "middle": "medium", "medium": "medium", "2": "medium",
+		"high": "high", "strong": "high", "3": "high",
+	}
+)
+
+// TuyaClimateUseCase exposes one climate model (power, mode, target temperature, fan speed, swing) for IR air
+// conditioners and native Wi-Fi ACs/thermostats, so clients need not know the IR command codes or each
+// device's DPs. IR air conditioners are controlled through SendIRACCommand, native devices through their DPs
+// (validated against the specification, like the fan and dimmer endpoints).
+type TuyaClimateUseCase struct {
+	deviceUC  *TuyaGetDeviceByIDUseCase
+	specUC    *DeviceSpecificationUseCase
+	controlUC *TuyaDeviceControlUseCase
+}
+
+// NewTuyaClimateUseCase initializes a new TuyaClimateUseCase.
+//
+// param deviceUC The TuyaGetDeviceByIDUseCase used to read the current status (IR ACs include their last sent state).
+// param specUC The DeviceSpecificationUseCase used to resolve the DPs of native devices.
+// param controlUC The TuyaDeviceControlUseCase used to send the translated commands.
+// return *TuyaClimateUseCase A pointer to the initialized usecase.
+func NewTuyaClimateUseCase(deviceUC *TuyaGetDeviceByIDUseCase, specUC *DeviceSpecificationUseCase, controlUC *TuyaDeviceControlUseCase) *TuyaClimateUseCase {
+	return &TuyaClimateUseCase{
+		deviceUC:  deviceUC,
+		specUC:    specUC,
+		controlUC: controlUC,
+	}
+}
+
+// GetClimate returns the normalized climate state of a device.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The ID of the IR air conditioner remote or native climate device.
+// return *dtos.ClimateStateDTO The climate state and the settings the device accepts.
+// return error A bad request error when the device is not a climate device.
+func (uc *TuyaClimateUseCase) GetClimate(ctx context.Context, accessToken, deviceID string) (*dtos.ClimateStateDTO, error) {
+	device, err := uc.deviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	if device.Category == "infrared_ac" {
+		return irClimateState(device), nil
+	}
+	if !containsString(nativeClimateCategories, device.Category) {
+		return nil, tuya_errors.BadRequest("device %s is category %s, not a climate device", deviceID, device.Category)
+	}
+	spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	return nativeClimateState(device, spec), nil
+}
+
+// ControlClimate applies normalized climate settings to a device.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The ID of the IR air conditioner remote or native climate device.
+// param req The requested settings.
+// return *dtos.CategoryControlResponseDTO The commands that were sent (IR codes or DPs).
+// return error A bad request error when the request does not fit the device.
+func (uc *TuyaClimateUseCase) ControlClimate(ctx context.Context, accessToken, deviceID string, req dtos.ClimateControlRequestDTO) (*dtos.CategoryControlResponseDTO, error) {
+	if req.Power == nil && req.Mode == "" && req.TargetTemp == nil && req.FanSpeed == "" && req.Swing == nil {
+		return nil, tuya_errors.BadRequest("no settings provided")
+	}
+	if req.Power != nil && !*req.Power && (req.Mode != "" || req.TargetTemp != nil || req.FanSpeed != "" || req.Swing != nil) {
+		return nil, tuya_errors.BadRequest("other settings cannot be combined with power off")
+	}
+
+	device, err := uc.deviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if device.Category == "infrared_ac" {
+		return uc.controlIR(ctx, accessToken, device, req)
+	}
+	if !containsString(nativeClimateCategories, device.Category) {
+		return nil, tuya_errors.BadRequest("device %s is category %s, not a climate device", deviceID, device.Category)
+	}
+	return uc.controlNative(ctx, accessToken, deviceID, req)
+}
+
+// controlIR sends the settings as IR air conditioner commands, powering on first and in the order ACs expect.
+func (uc *TuyaClimateUseCase) controlIR(ctx context.Context, accessToken string, device *dtos.TuyaDeviceDTO, req dtos.ClimateControlRequestDTO) (*dtos.CategoryControlResponseDTO, error) {
+	if req.Swing != nil {
+		return nil, tuya_errors.BadRequest("IR air conditioner %s does not support swing", device.ID)
+	}
+
+	var commands []dtos.TuyaCommandDTO
+	if req.Power != nil {
+		commands = append(commands, dtos.TuyaCommandDTO{Code: "power", Value: boolToInt(*req.Power)})
+	}
+	if req.Mode != "" {
+		commands = append(commands, dtos.TuyaCommandDTO{Code: "mode", Value: indexOf(climateModes, req.Mode)})
+	}
+	if req.TargetTemp != nil {
+		temp := int(math.Round(*req.TargetTemp))
+		if temp < mqttMinACTemp || temp > mqttMaxACTemp {
+			return nil, tuya_errors.BadRequest("target_temp must be between %d and %d", mqttMinACTemp, mqttMaxACTemp)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: "temp", Value: temp})
+	}
+	if req.FanSpeed != "" {
+		commands = append(commands, dtos.TuyaCommandDTO{Code: "wind", Value: indexOf(climateFanSpeeds, req.FanSpeed)})
+	}
+
+	infraredID := device.GatewayID
+	if infraredID == "" {
+		infraredID = device.ID
+	}
+	success := true
+	for _, command := range commands {
+		sent, err := uc.controlUC.SendIRACCommand(ctx, accessToken, infraredID, device.ID, command.Code, command.Value.(int))
+		if err != nil {
+			return nil, err
+		}
+		success = success && sent
+	}
+
+	utils.LogDebug("ClimateUseCase: Sent %d IR commands to %s", len(commands), device.ID)
+	return &dtos.CategoryControlResponseDTO{
+		Success:  success,
+		Commands: commands,
+	}, nil
+}
+
+// controlNative translates the settings into the DPs of a native climate device.
+func (uc *TuyaClimateUseCase) controlNative(ctx context.Context, accessToken, deviceID string, req dtos.ClimateControlRequestDTO) (*dtos.CategoryControlResponseDTO, error) {
+	spec, err := uc.specUC.GetSpecification(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var commands []dtos.TuyaCommandDTO
+	if req.Power != nil {
+		fn, ok := findFunction(spec, climatePowerCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support power control", deviceID)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: *req.Power})
+	}
+	if req.Mode != "" {
+		fn, value, err := nativeEnumValue(spec, climateModeCodes, climateModeAliases, req.Mode, "mode")
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: value})
+	}
+	if req.TargetTemp != nil {
+		fn, ok := findFunction(spec, climateTargetTempCodes)
+		if !ok {
+			return nil, tuya_errors.BadRequest("device %s does not support a target temperature", deviceID)
+		}
+		values := parseFunctionValues(fn)
+		factor := math.Pow(10, scaleOf(values))
+		raw := math.Round(*req.TargetTemp * factor)
+		if values.Step != nil && *values.Step > 1 {
+			raw = math.Round(raw / *values.Step) * *values.Step
+		}
+		if values.Min != nil && raw < *values.Min {
+			return nil, tuya_errors.BadRequest("target_temp must be at least %g", *values.Min/factor)
+		}
+		if values.Max != nil && raw > *values.Max {
+			return nil, tuya_errors.BadRequest("target_temp must be at most %g", *values.Max/factor)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: int(raw)})
+	}
+	if req.FanSpeed != "" {
+		fn, value, err := nativeEnumValue(spec, climateFanSpeedCodes, climateFanSpeedAliases, req.FanSpeed, "fan_speed")
+		if err != nil {
+			return nil, err
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: value})
+	}
+	if req.Swing != nil {
+		fn, ok := findFunction(spec, climateSwingCodes)
+		if !ok || !strings.EqualFold(fn.Type, "boolean") {
+			return nil, tuya_errors.BadRequest("device %s does not support swing", deviceID)
+		}
+		commands = append(commands, dtos.TuyaCommandDTO{Code: fn.Code, Value: *req.Swing})
+	}
+
+	utils.LogDebug("ClimateUseCase: Translated request for %s into %d commands", deviceID, len(commands))
+	success, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.CategoryControlResponseDTO{
+		Success:  success,
+		Commands: commands,
+	}, nil
+}
+
+// irClimateState builds the climate model from the IR air conditioner's last sent state.
+func irClimateState(device *dtos.TuyaDeviceDTO) *dtos.ClimateStateDTO {
+	minTemp, maxTemp := float64(mqttMinACTemp), float64(mqttMaxACTemp)
+	state := &dtos.ClimateStateDTO{
+		DeviceID: device.ID,
+		Source:   ClimateSourceIR,
+		Supports: dtos.ClimateCapabilityDTO{
+			Modes:     climateModes,
+			FanSpeeds: climateFanSpeeds,
+			MinTemp:   &minTemp,
+			MaxTemp:   &maxTemp,
+		},
+	}
+	for _, status := range device.Status {
+		value, ok := numericValue(status.Value)
+		if !ok {
+			continue
+		}
+		index := int(value)
+		switch status.Code {
+		case "power":
+			state.Power = value != 0
+		case "mode":
+			if index >= 0 && index < len(climateModes) {
+				state.Mode = climateModes[index]
+			}
+		case "temp":
+			temp := value
+			state.TargetTemp = &temp
+		case "wind":
+			if index >= 0 && index < len(climateFanSpeeds) {
+				state.FanSpeed = climateFanSpeeds[index]
+			}
+		}
+	}
+	return state
+}
+
+// nativeClimateState builds the climate model from a native device's status and specification.
+func nativeClimateState(device *dtos.TuyaDeviceDTO, spec *entities.TuyaDeviceSpecification) *dtos.ClimateStateDTO {
+	state := &dtos.ClimateStateDTO{
+		DeviceID: device.ID,
+		Source:   ClimateSourceNative,
+		Supports: dtos.ClimateCapabilityDTO{
+			Modes:     nativeEnumSupport(spec, climateModeCodes, climateModeAliases),
+			FanSpeeds: nativeEnumSupport(spec, climateFanSpeedCodes, climateFanSpeedAliases),
+		},
+	}
+	if fn, ok := findFunction(spec, climateTargetTempCodes); ok {
+		values := parseFunctionValues(fn)
+		factor := math.Pow(10, scaleOf(values))
+		if values.Min != nil && values.Max != nil {
+			minTemp, maxTemp := *values.Min/factor, *values.Max/factor
+			state.Supports.MinTemp, state.Supports.MaxTemp = &minTemp, &maxTemp
+		}
+	}
+	if fn, ok := findFunction(spec, climateSwingCodes); ok && strings.EqualFold(fn.Type, "boolean") {
+		state.Supports.Swing = true
+	}
+
+	status := make(map[string]interface{}, len(device.Status))
+	for _, item := range device.Status {
+		status[item.Code] = item.Value
+	}
+	if value, ok := firstStatus(status, climatePowerCodes).(bool); ok {
+		state.Power = value
+	}
+	if value, ok := firstStatus(status, climateModeCodes).(string); ok {
+		state.Mode = normalizedEnum(climateModeAliases, value)
+	}
+	if value, ok := firstStatus(status, climateFanSpeedCodes).(string); ok {
+		state.FanSpeed = normalizedEnum(climateFanSpeedAliases, value)
+	}
+	if value, ok := firstStatus(status, climateSwingCodes).(bool); ok {
+		state.Swing = &value
+	}
+	state.TargetTemp = scaledTemperature(spec, climateTargetTempCodes, status)
+	state.CurrentTemp = scaledTemperature(spec, climateCurrentTempCodes, status)
+	return state
+}
+
+// nativeEnumValue finds the device's enum value for a normalized mode or fan speed.
+func nativeEnumValue(spec *entities.TuyaDeviceSpecification, codes []string, aliases map[string]string, requested, field string) (entities.TuyaDeviceFunction, string, error) {
+	fn, ok := findFunction(spec, codes)
+	if !ok {
+		return fn, "", tuya_errors.BadRequest("device does not support %s", field)
+	}
+	for _, value := range parseFunctionValues(fn).Range {
+		if aliases[strings.ToLower(value)] == requested {
+			return fn, value, nil
+		}
+	}
+	return fn, "", tuya_errors.BadRequest("%s must be one of %s", field, strings.Join(nativeEnumSupport(spec, codes, aliases), ", "))
+}
+
+// nativeEnumSupport lists the normalized values a device's enum function maps to.
+func nativeEnumSupport(spec *entities.TuyaDeviceSpecification, codes []string, aliases map[string]string) []string {
+	supported := []string{}
+	fn, ok := findFunction(spec, codes)
+	if !ok {
+		return supported
+	}
+	for _, value := range parseFunctionValues(fn).Range {
+		if normalized, ok := aliases[strings.ToLower(value)]; ok && !containsString(supported, normalized) {
+			supported = append(supported, normalized)
+		}
+	}
+	return supported
+}
+
+// normalizedEnum maps a device enum value to its normalized name; unknown values are passed through.
+func normalizedEnum(aliases map[string]string, value string) string {
+	if normalized, ok := aliases[strings.ToLower(value)]; ok {
+		return normalized
+	}
+	return value
+}
+
+// scaledTemperature reads a temperature DP and applies the scale from its specification.
+// Read-only DPs (e.g., temp_current) are described in the specification's status list.
+func scaledTemperature(spec *entities.TuyaDeviceSpecification, codes []string, status map[string]interface{}) *float64 {
+	for _, code := range codes {
+		value, ok := numericValue(status[code])
+		if !ok {
+			continue
+		}
+		for _, fn := range append(append([]entities.TuyaDeviceFunction{}, spec.Functions...), spec.Status...) {
+			if fn.Code == code {
+				value /= math.Pow(10, scaleOf(parseFunctionValues(fn)))
+				break
+			}
+		}
+		return &value
+	}
+	return nil
+}
+
+// firstStatus returns the value of the first present status code.
+func firstStatus(status map[string]interface{}, codes []string) interface{} {
+	for _, code := range codes {
+		if value, ok := status[code]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// scaleOf returns the decimal scale of an integer function (0 when unspecified).
+func scaleOf(values functionValues) float64 {
+	if values.Scale != nil {
+		return *values.Scale
+	}
+	return 0
+}
+
+// boolToInt converts a power flag into the 0/1 value of IR air conditioner commands.
+func boolToInt(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
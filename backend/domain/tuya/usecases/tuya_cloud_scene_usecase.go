@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+)
+
+// Actions reported by CloudSceneActionResultDTO.
+const (
+	cloudSceneActionTrigger = "trigger"
+	cloudSceneActionEnable  = "enable"
+	cloudSceneActionDisable = "disable"
+)
+
+// TuyaCloudSceneUseCase exposes the scenes and automations configured in the Smart Life app, so existing
+// scenes can be triggered from Teralux without recreating them. It is a passthrough: nothing is stored.
+type TuyaCloudSceneUseCase struct {
+	service    *services.TuyaSceneService
+	auditLogUC *AuditLogUseCase
+}
+
+// NewTuyaCloudSceneUseCase initializes a new TuyaCloudSceneUseCase.
+//
+// param service The TuyaSceneService used for API communication.
+// param auditLogUC The usecase recording scene triggers and automation changes (optional).
+// return *TuyaCloudSceneUseCase A pointer to the initialized usecase.
+func NewTuyaCloudSceneUseCase(service *services.TuyaSceneService, auditLogUC *AuditLogUseCase) *TuyaCloudSceneUseCase {
+	return &TuyaCloudSceneUseCase{
+		service:    service,
+		auditLogUC: auditLogUC,
+	}
+}
+
+// ListHomes returns the homes of a user; scenes and automations are listed per home.
+//
+// Tuya API Documentation (Query Homes of a User):
+// URL: /v1.0/users/{uid}/homes
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param uid The Tuya User ID.
+// return []dtos.TuyaHomeDTO The homes.
+// return error An error if the API call fails.
+func (uc *TuyaCloudSceneUseCase) ListHomes(ctx context.Context, accessToken, uid string) ([]dtos.TuyaHomeDTO, error) {
+	resp, err := uc.service.FetchHomes(ctx, fmt.Sprintf("/v1.0/users/%s/homes", uid), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch homes", resp.Code, resp.Msg)
+	}
+
+	homes := make([]dtos.TuyaHomeDTO, len(resp.Result))
+	for i, h := range resp.Result {
+		homes[i] = dtos.TuyaHomeDTO{
+			HomeID:  h.HomeID,
+			Name:    h.Name,
+			GeoName: h.GeoName,
+			Role:    h.Role,
+		}
+	}
+	return homes, nil
+}
+
+// ListScenes returns the tap-to-run scenes of a home.
+//
+// Tuya API Documentation (Query Scene List):
+// URL: /v1.1/homes/{home_id}/scenes
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param homeID The home ID.
+// return *dtos.CloudScenesResponseDTO The scenes.
+// return error An error if the API call fails.
+func (uc *TuyaCloudSceneUseCase) ListScenes(ctx context.Context, accessToken, homeID string) (*dtos.CloudScenesResponseDTO, error) {
+	resp, err := uc.service.FetchScenes(ctx, fmt.Sprintf("/v1.1/homes/%s/scenes", homeID), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch scenes", resp.Code, resp.Msg)
+	}
+
+	scenes := make([]dtos.CloudSceneDTO, len(resp.Result))
+	for i, s := range resp.Result {
+		scenes[i] = dtos.CloudSceneDTO{
+			SceneID:     s.SceneID,
+			Name:        s.Name,
+			Background:  s.Background,
+			Enabled:     s.Status != "disable",
+			ActionCount: len(s.Actions),
+		}
+	}
+	return &dtos.CloudScenesResponseDTO{HomeID: homeID, Scenes: scenes}, nil
+}
+
+// TriggerScene runs a tap-to-run scene of a home.
+//
+// Tuya API Documentation (Trigger Scene):
+// URL: /v1.0/homes/{home_id}/scenes/{scene_id}/trigger
+// Method: POST
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param homeID The home ID.
+// param sceneID The scene ID.
+// return *dtos.CloudSceneActionResultDTO The triggered scene.
+// return error An error if the API call fails.
+func (uc *TuyaCloudSceneUseCase) TriggerScene(ctx context.Context, accessToken, homeID, sceneID string) (*dtos.CloudSceneActionResultDTO, error) {
+	result := &dtos.CloudSceneActionResultDTO{HomeID: homeID, SceneID: sceneID, Action: cloudSceneActionTrigger}
+	err := uc.send(ctx, accessToken, fmt.Sprintf("/v1.0/homes/%s/scenes/%s/trigger", homeID, sceneID), result, uc.service.TriggerScene)
+	if err != nil {
+		return nil, err
+	}
+	utils.LogInfo("TriggerScene: Triggered scene %s of home %s", sceneID, homeID)
+	return result, nil
+}
+
+// ListAutomations returns the automations of a home.
+//
+// Tuya API Documentation (Query Automation List):
+// URL: /v1.0/homes/{home_id}/automations
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param homeID The home ID.
+// return *dtos.CloudAutomationsResponseDTO The automations.
+// return error An error if the API call fails.
+func (uc *TuyaCloudSceneUseCase) ListAutomations(ctx context.Context, accessToken, homeID string) (*dtos.CloudAutomationsResponseDTO, error) {
+	resp, err := uc.service.FetchAutomations(ctx, fmt.Sprintf("/v1.0/homes/%s/automations", homeID), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch automations", resp.Code, resp.Msg)
+	}
+
+	automations := make([]dtos.CloudAutomationDTO, len(resp.Result))
+	for i, a := range resp.Result {
+		automations[i] = dtos.CloudAutomationDTO{
+			AutomationID: a.AutomationID,
+			Name:         a.Name,
+			Background:   a.Background,
+			Enabled:      a.Enabled,
+			ActionCount:  len(a.Actions),
+		}
+	}
+	return &dtos.CloudAutomationsResponseDTO{HomeID: homeID, Automations: automations}, nil
+}
+
+// SetAutomationEnabled enables or disables an automation of a home.
+// Tuya runs automations on their own conditions, so they cannot be triggered, only switched on or off.
+//
+// Tuya API Documentation (Enable/Disable Automation):
+// URL: /v1.0/homes/{home_id}/automations/{automation_id}/actions/enable (or /disable)
+// Method: PUT
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param homeID The home ID.
+// param automationID The automation ID.
+// param enabled True to enable the automation, false to disable it.
+// return *dtos.CloudSceneActionResultDTO The changed automation.
+// return error An error if the API call fails.
+func (uc *TuyaCloudSceneUseCase) SetAutomationEnabled(ctx context.Context, accessToken, homeID, automationID string, enabled bool) (*dtos.CloudSceneActionResultDTO, error) {
+	action := cloudSceneActionDisable
+	if enabled {
+		action = cloudSceneActionEnable
+	}
+	result := &dtos.CloudSceneActionResultDTO{HomeID: homeID, AutomationID: automationID, Action: action}
+	urlPath := fmt.Sprintf("/v1.0/homes/%s/automations/%s/actions/%s", homeID, automationID, action)
+	if err := uc.send(ctx, accessToken, urlPath, result, uc.service.SetAutomationState); err != nil {
+		return nil, err
+	}
+	utils.LogInfo("SetAutomationEnabled: %sd automation %s of home %s", action, automationID, homeID)
+	return result, nil
+}
+
+// send calls a scene or automation action endpoint and records the outcome in the audit log.
+func (uc *TuyaCloudSceneUseCase) send(ctx context.Context, accessToken, urlPath string, result *dtos.CloudSceneActionResultDTO, call func(context.Context, string, string) (*entities.TuyaCommandResponse, error)) error {
+	resp, err := call(ctx, urlPath, accessToken)
+	if err == nil && !resp.Success {
+		err = tuya_errors.FromTuya(fmt.Sprintf("failed to %s", result.Action), resp.Code, resp.Msg)
+	}
+
+	if uc.auditLogUC != nil {
+		target := result.SceneID
+		if target == "" {
+			target = result.AutomationID
+		}
+		if auditErr := uc.auditLogUC.Record(AuditActionCloudScene, target, result, err); auditErr != nil {
+			utils.LogWarn("Failed to record audit entry for %s: %v", target, auditErr)
+		}
+	}
+	return err
+}
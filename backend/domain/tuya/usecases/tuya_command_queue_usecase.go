@@ -0,0 +1,267 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"strconv"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	job_entities "teralux_app/domain/jobs/entities"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	// CommandJobType is the job type of queued device commands.
+	CommandJobType = "device_command"
+
+	defaultCommandMaxAttempts = 5
+
+	// commandIdempotencyPrefix maps an Idempotency-Key to the command it created.
+	commandIdempotencyPrefix = "command_idempotency:"
+	commandIdempotencyTTL    = 24 * time.Hour
+)
+
+// ErrCommandNotFound is returned when a queued command does not exist.
+var ErrCommandNotFound = tuya_errors.NotFound("command not found")
+
+// ErrIdempotencyKeyConflict is returned when an Idempotency-Key is reused for a different request.
+var ErrIdempotencyKeyConflict = tuya_errors.Conflict("idempotency key already used for a different request")
+
+// tuyaServerErrorPattern matches service errors for HTTP 5xx responses from Tuya.
+var tuyaServerErrorPattern = regexp.MustCompile(`API returned status 5\d\d`)
+
+// commandPayload is the persisted input of a queued command.
+type commandPayload struct {
+	DeviceID       string                `json:"device_id"`
+	Commands       []dtos.TuyaCommandDTO `json:"commands"`
+	IdempotencyKey string                `json:"idempotency_key,omitempty"`
+	// Caller is the API key or user that queued the command; it is authorized again before each attempt.
+	Caller utils.APIKeyIdentity `json:"caller"`
+}
+
+// commandIdempotencyRecord remembers the command created for an Idempotency-Key.
+type commandIdempotencyRecord struct {
+	CommandID   string `json:"command_id"`
+	RequestHash string `json:"request_hash"`
+}
+
+// TuyaCommandQueueUseCase queues device commands as persisted jobs. A worker sends them with the
+// server-managed token and retries transient Tuya failures (network errors, timeouts, HTTP 5xx) with
+// exponential backoff; rejected commands fail at once. Submissions carrying the same Idempotency-Key
+// return the command created by the first one instead of sending the command again.
+// Since the worker does not use the caller's token, only callers with an API key (or identity) of control
+// scope that may access the device can queue commands, and the key is checked again before every attempt.
+type TuyaCommandQueueUseCase struct {
+	jobRunner   *job_services.JobRunnerService
+	controlUC   *TuyaDeviceControlUseCase
+	authUC      *TuyaAuthUseCase
+	authz       CallerAuthorizer
+	cache       persistence.CacheStore
+	maxAttempts int
+
+	// idempotencyMu serializes key lookups and claims so concurrent retries create a single command
+	idempotencyMu sync.Mutex
+}
+
+// NewTuyaCommandQueueUseCase initializes a new TuyaCommandQueueUseCase and registers its job type.
+//
+// param jobRunner The JobRunnerService persisting and retrying commands.
+// param controlUC The usecase sending the commands.
+// param authUC The TuyaAuthUseCase providing the server-managed token used by the worker.
+// param authz The CallerAuthorizer checking the API key of the caller.
+// param cache The CacheStore storing Idempotency-Key records.
+// return *TuyaCommandQueueUseCase A pointer to the initialized usecase.
+func NewTuyaCommandQueueUseCase(jobRunner *job_services.JobRunnerService, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, authz CallerAuthorizer, cache persistence.CacheStore) *TuyaCommandQueueUseCase {
+	maxAttempts, err := strconv.Atoi(utils.GetConfig().CommandQueueMaxAttempts)
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = defaultCommandMaxAttempts
+	}
+
+	uc := &TuyaCommandQueueUseCase{
+		jobRunner:   jobRunner,
+		controlUC:   controlUC,
+		authUC:      authUC,
+		authz:       authz,
+		cache:       cache,
+		maxAttempts: maxAttempts,
+	}
+	jobRunner.Register(CommandJobType, uc.runCommand)
+	return uc
+}
+
+// Submit queues commands for a device.
+//
+// param ctx The request context carrying the caller.
+// param deviceID The device receiving the commands.
+// param commands The commands to send.
+// param idempotencyKey The client's Idempotency-Key (optional).
+// return *dtos.CommandStatusDTO The queued command, or the one created earlier with the same key.
+// return bool True if a new command was queued, false if an earlier one was returned.
+// return error A forbidden error if the caller may not control the device, ErrIdempotencyKeyConflict if the
+// key was used for a different request, or an error if the command cannot be queued.
+func (uc *TuyaCommandQueueUseCase) Submit(ctx context.Context, deviceID string, commands []dtos.TuyaCommandDTO, idempotencyKey string) (*dtos.CommandStatusDTO, bool, error) {
+	caller, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, deviceID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	payload := commandPayload{DeviceID: deviceID, Commands: commands, IdempotencyKey: idempotencyKey, Caller: caller}
+	if idempotencyKey == "" {
+		job, err := uc.jobRunner.Enqueue(ctx, CommandJobType, payload, uc.maxAttempts)
+		if err != nil {
+			return nil, false, err
+		}
+		return commandStatus(job), true, nil
+	}
+
+	requestHash, err := hashCommandRequest(deviceID, commands)
+	if err != nil {
+		return nil, false, err
+	}
+
+	uc.idempotencyMu.Lock()
+	defer uc.idempotencyMu.Unlock()
+
+	// Keys are per caller, so one caller cannot look up the commands of another through their key
+	key := commandIdempotencyPrefix + utils.HashString(caller.Actor()+":"+idempotencyKey)
+	if record := uc.loadIdempotencyRecord(key); record != nil {
+		if record.RequestHash != requestHash {
+			return nil, false, ErrIdempotencyKeyConflict
+		}
+		existing, err := uc.GetCommand(ctx, record.CommandID)
+		if err == nil {
+			return existing, false, nil
+		}
+		// The command expired from the job history; treat the key as unused
+	}
+
+	job, err := uc.jobRunner.Enqueue(ctx, CommandJobType, payload, uc.maxAttempts)
+	if err != nil {
+		return nil, false, err
+	}
+	if uc.cache != nil {
+		recordData, _ := json.Marshal(commandIdempotencyRecord{CommandID: job.ID, RequestHash: requestHash})
+		if err := uc.cache.Set(key, recordData, commandIdempotencyTTL); err != nil {
+			utils.LogWarn("TuyaCommandQueueUseCase: Failed to store Idempotency-Key for command %s: %v", job.ID, err)
+		}
+	}
+	return commandStatus(job), true, nil
+}
+
+// GetCommand returns the execution status of a queued command. Callers only see their own commands, admins all.
+//
+// param ctx The request context carrying the caller.
+// param id The command ID.
+// return *dtos.CommandStatusDTO The command status.
+// return error ErrCommandNotFound if the command does not exist or belongs to another caller.
+func (uc *TuyaCommandQueueUseCase) GetCommand(ctx context.Context, id string) (*dtos.CommandStatusDTO, error) {
+	job, err := uc.jobRunner.Get(id)
+	if err != nil {
+		if errors.Is(err, job_services.ErrJobNotFound) {
+			return nil, ErrCommandNotFound
+		}
+		return nil, err
+	}
+	if job.Type != CommandJobType {
+		return nil, ErrCommandNotFound
+	}
+	var payload commandPayload
+	if err := json.Unmarshal(job.Payload, &payload); err != nil || !isOwnerOrAdmin(ctx, payload.Caller) {
+		return nil, ErrCommandNotFound
+	}
+	return commandStatus(job), nil
+}
+
+// runCommand is the job handler sending one attempt of a queued command.
+func (uc *TuyaCommandQueueUseCase) runCommand(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	var payload commandPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return nil, job_services.Permanent(fmt.Errorf("invalid command payload: %w", err))
+	}
+	if err := authorizeCaller(uc.authz, payload.Caller, utils.APIKeyScopeControl, payload.DeviceID); err != nil {
+		return nil, job_services.Permanent(err)
+	}
+
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	success, err := uc.controlUC.SendCommand(ctx, token.AccessToken, payload.DeviceID, payload.Commands)
+	if err != nil {
+		if isTransientCommandError(err) {
+			return nil, err
+		}
+		return nil, job_services.Permanent(err)
+	}
+	return map[string]bool{"success": success}, nil
+}
+
+// loadIdempotencyRecord returns the record stored for an Idempotency-Key, or nil if there is none.
+func (uc *TuyaCommandQueueUseCase) loadIdempotencyRecord(key string) *commandIdempotencyRecord {
+	if uc.cache == nil {
+		return nil
+	}
+	jsonData, err := uc.cache.Get(key)
+	if err != nil || jsonData == nil {
+		return nil
+	}
+	var record commandIdempotencyRecord
+	if err := json.Unmarshal(jsonData, &record); err != nil {
+		return nil
+	}
+	return &record
+}
+
+// isTransientCommandError reports whether a failed command may succeed when retried:
+// timeouts, network errors and HTTP 5xx responses. Errors returned by Tuya in the response body are final.
+func isTransientCommandError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	return tuyaServerErrorPattern.MatchString(err.Error())
+}
+
+// hashCommandRequest fingerprints a request so a reused Idempotency-Key can be matched to its original body.
+func hashCommandRequest(deviceID string, commands []dtos.TuyaCommandDTO) (string, error) {
+	data, err := json.Marshal(commandPayload{DeviceID: deviceID, Commands: commands})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode command: %w", err)
+	}
+	return utils.HashString(string(data)), nil
+}
+
+// commandStatus maps a command job to its status DTO.
+func commandStatus(job *job_entities.Job) *dtos.CommandStatusDTO {
+	var payload commandPayload
+	if len(job.Payload) > 0 {
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			utils.LogWarn("TuyaCommandQueueUseCase: Failed to decode payload of command %s: %v", job.ID, err)
+		}
+	}
+	return &dtos.CommandStatusDTO{
+		ID:             job.ID,
+		DeviceID:       payload.DeviceID,
+		Commands:       payload.Commands,
+		Status:         job.Status,
+		Attempts:       job.Attempts,
+		MaxAttempts:    job.MaxAttempts,
+		Error:          job.Error,
+		IdempotencyKey: payload.IdempotencyKey,
+		CreatedAt:      job.CreatedAt,
+		UpdatedAt:      job.UpdatedAt,
+		FinishedAt:     job.FinishedAt,
+	}
+}
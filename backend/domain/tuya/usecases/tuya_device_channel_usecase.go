@@ -0,0 +1,90 @@
+package usecases
+
+import (
+	"context"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+)
+
+// TuyaDeviceChannelUseCase exposes channel naming and per-channel control of multi-gang switches.
+type TuyaDeviceChannelUseCase struct {
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	controlUC   *TuyaDeviceControlUseCase
+	channelUC   *DeviceChannelUseCase
+}
+
+// NewTuyaDeviceChannelUseCase initializes a new TuyaDeviceChannelUseCase.
+//
+// param getDeviceUC The usecase used to fetch the device with its channels.
+// param controlUC The usecase used to send channel commands.
+// param channelUC The usecase storing channel names.
+// return *TuyaDeviceChannelUseCase A pointer to the initialized usecase.
+func NewTuyaDeviceChannelUseCase(getDeviceUC *TuyaGetDeviceByIDUseCase, controlUC *TuyaDeviceControlUseCase, channelUC *DeviceChannelUseCase) *TuyaDeviceChannelUseCase {
+	return &TuyaDeviceChannelUseCase{
+		getDeviceUC: getDeviceUC,
+		controlUC:   controlUC,
+		channelUC:   channelUC,
+	}
+}
+
+// GetChannels returns the channels of a multi-gang switch.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// return []dtos.DeviceChannelDTO The channels with names and current values.
+// return error A bad request error if the device has no channels.
+func (uc *TuyaDeviceChannelUseCase) GetChannels(ctx context.Context, accessToken, deviceID string) ([]dtos.DeviceChannelDTO, error) {
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if len(device.Channels) == 0 {
+		return nil, tuya_errors.BadRequest("device %s is not a multi-gang switch", deviceID)
+	}
+	return device.Channels, nil
+}
+
+// RenameChannels stores channel names and returns the updated channels.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// param names The channel names keyed by channel code.
+// return []dtos.DeviceChannelDTO The updated channels.
+// return error A bad request error for unknown channels.
+func (uc *TuyaDeviceChannelUseCase) RenameChannels(ctx context.Context, accessToken, deviceID string, names map[string]string) ([]dtos.DeviceChannelDTO, error) {
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.channelUC.SaveChannelNames(device, names); err != nil {
+		return nil, err
+	}
+	uc.channelUC.ApplyChannels(device)
+	return device.Channels, nil
+}
+
+// SendChannelCommand switches a single channel on or off.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// param channel The channel reference (code, index or name).
+// param value The desired on/off state.
+// return bool True if the command was accepted.
+// return error A bad request error if the channel does not exist.
+func (uc *TuyaDeviceChannelUseCase) SendChannelCommand(ctx context.Context, accessToken, deviceID, channel string, value bool) (bool, error) {
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return false, err
+	}
+	ch, err := uc.channelUC.ResolveChannel(device, channel)
+	if err != nil {
+		return false, err
+	}
+
+	utils.LogDebug("SendChannelCommand: %s channel %s (%s) -> %v", deviceID, ch.Code, ch.Name, value)
+	return uc.controlUC.SendCommand(ctx, accessToken, deviceID, []dtos.TuyaCommandDTO{{Code: ch.Code, Value: value}})
+}
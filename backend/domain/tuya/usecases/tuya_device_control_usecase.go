@@ -1,19 +1,19 @@
 package usecases
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"teralux_app/domain/tuya/dtos"
 	"teralux_app/domain/tuya/entities"
 	"teralux_app/domain/common/infrastructure/persistence"
+	tuya_errors "teralux_app/domain/tuya/errors"
 	"teralux_app/domain/tuya/services"
 	"teralux_app/domain/common/utils"
-	tuya_utils "teralux_app/domain/tuya/utils"
-	"time"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
 	"strings"
+	"time"
 )
 
 // TuyaDeviceControlUseCase handles the business logic for controlling Tuya devices.
@@ -21,37 +21,90 @@ import (
 type TuyaDeviceControlUseCase struct {
 	service          *services.TuyaDeviceService
 	deviceStateUC    *DeviceStateUseCase
-	cache            *persistence.BadgerService
+	cache            persistence.CacheStore
+	realtimeHub      *realtime_services.RealtimeHubService
+	auditLogUC       *AuditLogUseCase
+	featureFlags     *FeatureFlagUseCase
+	cooldowns        *CommandCooldownUseCase
+	dedup            *CommandDedupUseCase
+	approvals        *CommandApprovalUseCase
+	clock            utils.Clock
 }
 
 // NewTuyaDeviceControlUseCase initializes a new TuyaDeviceControlUseCase.
 //
 // param service The TuyaDeviceService used for API communication.
 // param deviceStateUC The DeviceStateUseCase for saving device states.
-// param cache The BadgerService for cache invalidation.
+// param cache The CacheStore for cache invalidation.
+// param realtimeHub The RealtimeHubService notified after successful commands (optional).
+// param auditLogUC The AuditLogUseCase recording every command attempt (optional).
+// param featureFlags The FeatureFlagUseCase gating retry and fallback paths per device (optional).
+// param cooldowns The CommandCooldownUseCase spacing out commands to devices that reject rapid sequences (optional).
+// param dedup The CommandDedupUseCase coalescing identical IR commands from several clients (optional).
+// param clock The Clock used for event timestamps.
 // return *TuyaDeviceControlUseCase A pointer to the initialized usecase.
-func NewTuyaDeviceControlUseCase(service *services.TuyaDeviceService, deviceStateUC *DeviceStateUseCase, cache *persistence.BadgerService) *TuyaDeviceControlUseCase {
+func NewTuyaDeviceControlUseCase(service *services.TuyaDeviceService, deviceStateUC *DeviceStateUseCase, cache persistence.CacheStore, realtimeHub *realtime_services.RealtimeHubService, auditLogUC *AuditLogUseCase, featureFlags *FeatureFlagUseCase, cooldowns *CommandCooldownUseCase, dedup *CommandDedupUseCase, clock utils.Clock) *TuyaDeviceControlUseCase {
 	return &TuyaDeviceControlUseCase{
 		service:       service,
 		deviceStateUC: deviceStateUC,
 		cache:         cache,
+		realtimeHub:   realtimeHub,
+		auditLogUC:    auditLogUC,
+		featureFlags:  featureFlags,
+		cooldowns:     cooldowns,
+		dedup:         dedup,
+		clock:         clock,
 	}
 }
 
+// SetApprovalGate registers the usecase holding protected commands for two-person approval.
+//
+// param approvals The CommandApprovalUseCase, or nil to send every command directly.
+func (uc *TuyaDeviceControlUseCase) SetApprovalGate(approvals *CommandApprovalUseCase) {
+	uc.approvals = approvals
+}
+
 // SendIRACCommand sends a specific command to an Infrared (IR) controlled Air Conditioner.
 // It first attempts to resolve the correct gateway/infrared ID before sending the command.
 // If the primary IR command fails with specific error codes (e.g., 30100), it attempts a fallback to standard device control.
+// Identical commands arriving within the deduplication window (e.g., several family members turning the AC off)
+// are executed once and share the outcome.
 //
+// param ctx The request context, used for cancellation and timing metadata.
 // param accessToken The valid OAuth 2.0 access token.
-// param infraredID The ID of the IR blaster device (or virtual ID).
+// param infraredID The ID of the IR blaster device, or the remote ID when the hub is not known.
 // param remoteID The ID of the configured remote control for the AC.
 // param code The command code (e.g., "temp", "mode", "power", "wind").
 // param value The value for the command (e.g., 24 for temp, 1 for power on).
 // return bool True if the command was executed successfully.
 // return error An error if the command failed after all attempts.
 // @throws error If the API returns a failure code that cannot be handled by fallback logic.
-func (uc *TuyaDeviceControlUseCase) SendIRACCommand(accessToken, infraredID, remoteID, code string, value int) (bool, error) {
-	config := utils.GetConfig()
+func (uc *TuyaDeviceControlUseCase) SendIRACCommand(ctx context.Context, accessToken, infraredID, remoteID, code string, value int) (bool, error) {
+	var ok bool
+	key := fmt.Sprintf("ir_ac:%s:%s:%s:%d", infraredID, remoteID, code, value)
+	_, err := uc.dedup.Do(ctx, key, &ok, func() (interface{}, error) {
+		return uc.throttledIRACCommand(ctx, accessToken, infraredID, remoteID, code, value)
+	})
+	return ok, err
+}
+
+// throttledIRACCommand sends an IR AC command once the cooldown of the hub passed, and records its outcome.
+func (uc *TuyaDeviceControlUseCase) throttledIRACCommand(ctx context.Context, accessToken, infraredID, remoteID, code string, value int) (bool, error) {
+	var ok bool
+	err := uc.cooldowns.Throttle(ctx, infraredID, func() error {
+		var sendErr error
+		ok, sendErr = uc.sendIRACCommand(ctx, accessToken, infraredID, remoteID, code, value)
+		return sendErr
+	})
+	uc.audit(AuditActionIRACCommand, remoteID, []dtos.DeviceStateCommandDTO{{Code: code, Value: value}}, err)
+	if err != nil {
+		uc.publishCommandFailed(remoteID, "infrared_ac", []dtos.DeviceStateCommandDTO{{Code: code, Value: value}}, err)
+	}
+	return ok, err
+}
+
+// sendIRACCommand implements SendIRACCommand.
+func (uc *TuyaDeviceControlUseCase) sendIRACCommand(ctx context.Context, accessToken, infraredID, remoteID, code string, value int) (bool, error) {
 	forceLegacy := false
 	var gatewayID string
 
@@ -60,39 +113,20 @@ func (uc *TuyaDeviceControlUseCase) SendIRACCommand(accessToken, infraredID, rem
 	// Tuya API Documentation (Get Device Specification/Details):
 	// URL: /v1.0/iot-03/devices/{device_id}
 	// Method: GET
-	// Auth: Standard Header Signature
-	// Note: For GET requests, the content-hash in StringToSign must be the SHA256 of empty string.
 	deviceUrlPath := fmt.Sprintf("/v1.0/iot-03/devices/%s", remoteID)
-	deviceFullURL := config.TuyaBaseURL + deviceUrlPath
-	
-	// Generate timestamp for device fetch
-	deviceTimestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-
-	// Calculate content hash for empty body (GET request)
-	hEmpty := sha256.New()
-	hEmpty.Write([]byte(""))
-	deviceContentHash := hex.EncodeToString(hEmpty.Sum(nil))
-	
-	// Generate signature for device fetch
-	deviceStringToSign := tuya_utils.GenerateTuyaStringToSign("GET", deviceContentHash, "", deviceUrlPath)
-	deviceSignature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, deviceTimestamp, deviceStringToSign)
-	
-	// Prepare headers for device fetch
-	deviceHeaders := map[string]string{
-		"client_id":    config.TuyaClientID,
-		"sign":         deviceSignature,
-		"t":            deviceTimestamp,
-		"sign_method":  "HMAC-SHA256",
-		"access_token": accessToken,
-	}
 
 	// Call FetchDeviceByID
 	utils.LogDebug("SendIRACCommand: Fetching device details for RemoteID=%s", remoteID)
-	deviceResp, err := uc.service.FetchDeviceByID(deviceFullURL, deviceHeaders)
+	deviceResp, err := uc.service.FetchDeviceByID(ctx, deviceUrlPath, accessToken)
 	if err != nil {
 		utils.LogError("WARNING: Failed to fetch device details for IR command: %v. Continuing with provided infraredID.", err)
 	} else if deviceResp.Success {
-		// Check for GatewayID
+		// Check for GatewayID. A remote of another hub is rejected rather than sent through its own hub,
+		// since access to the hub in the request does not grant access to other hubs.
+		if deviceResp.Result.GatewayID != "" && infraredID != deviceResp.Result.GatewayID && infraredID != remoteID {
+			return false, tuya_errors.BadRequest("remote %s belongs to IR hub %s, not %s", remoteID, deviceResp.Result.GatewayID, infraredID).
+				WithHint("send the command through the IR hub of the remote")
+		}
 		if deviceResp.Result.GatewayID != "" {
 			utils.LogDebug("SendIRACCommand: Found GatewayID=%s for device %s. Using it as InfraredID.", deviceResp.Result.GatewayID, remoteID)
 			gatewayID = deviceResp.Result.GatewayID
@@ -114,6 +148,8 @@ func (uc *TuyaDeviceControlUseCase) SendIRACCommand(accessToken, infraredID, rem
 
 	// Helper function for Legacy/Fallback Call
 	sendLegacy := func() (bool, error) {
+		utils.RequestMetaFromContext(ctx).AddRetry()
+
 		// Map IR command to Standard DP
 		var fallbackCode string
 		var fallbackValue interface{}
@@ -153,46 +189,23 @@ func (uc *TuyaDeviceControlUseCase) SendIRACCommand(accessToken, infraredID, rem
 		}
 
 		// Use LEGACY endpoint explicitly
-		retryTimestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-		retrySignMethod := "HMAC-SHA256"
-		
 		fallbackUrlPath := fmt.Sprintf("/v1.0/devices/%s/commands", remoteID)
-		fallbackFullURL := config.TuyaBaseURL + fallbackUrlPath
 
-		// Generate fallback signature
-		fallbackReqBody := entities.TuyaCommandRequest{Commands: fallbackCommands}
-		fallbackJsonBody, _ := json.Marshal(fallbackReqBody)
-
-		hFallback := sha256.New()
-		hFallback.Write(fallbackJsonBody)
-		fallbackContentHash := hex.EncodeToString(hFallback.Sum(nil))
-
-		fallbackStringToSign := tuya_utils.GenerateTuyaStringToSign("POST", fallbackContentHash, "", fallbackUrlPath)
-		fallbackSignature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, retryTimestamp, fallbackStringToSign)
-
-		fallbackHeaders := map[string]string{
-			"client_id":    config.TuyaClientID,
-			"sign":         fallbackSignature,
-			"t":            retryTimestamp,
-			"sign_method":  retrySignMethod,
-			"access_token": accessToken,
-		}
-		
-		utils.LogDebug("Fallback Legacy Call: DeviceID=%s, URL=%s, Body=%s", remoteID, fallbackFullURL, string(fallbackJsonBody))
-		fallbackResp, fallbackErr := uc.service.SendCommand(fallbackFullURL, fallbackHeaders, fallbackCommands)
+		utils.LogDebug("Fallback Legacy Call: DeviceID=%s, Path=%s, Commands=%+v", remoteID, fallbackUrlPath, fallbackCommands)
+		fallbackResp, fallbackErr := uc.service.SendCommand(ctx, fallbackUrlPath, accessToken, fallbackCommands)
 		if fallbackErr != nil {
 			return false, fallbackErr
 		}
 		
 		if !fallbackResp.Success {
-			utils.LogError("Fallback Legacy API Failed. Code: %d, Msg: %s", fallbackResp.Code, fallbackResp.Msg)
+			utils.LoggerFromContext(ctx).WithFields(utils.Fields{"device_id": remoteID, "tuya_code": fallbackResp.Code}).Error("Fallback Legacy API Failed. Code: %d, Msg: %s", fallbackResp.Code, fallbackResp.Msg)
 			
 			// Handle code 1106 (Permission Deny) - usually means incorrect request body/parameters
 			if fallbackResp.Code == 1106 {
-				return false, fmt.Errorf("bad request: invalid input parameters. Please verify your request body matches the device's expected command format (code: %d)", fallbackResp.Code)
+				return false, tuya_errors.BadRequest("invalid input parameters. Please verify your request body matches the device's expected command format (code: %d)", fallbackResp.Code)
 			}
 			
-			return false, fmt.Errorf("tuya Legacy API failed: %s (code: %d)", fallbackResp.Msg, fallbackResp.Code)
+			return false, tuya_errors.FromTuya("failed to send legacy command", fallbackResp.Code, fallbackResp.Msg)
 		}
 		
 		return fallbackResp.Result, nil
@@ -204,104 +217,145 @@ func (uc *TuyaDeviceControlUseCase) SendIRACCommand(accessToken, infraredID, rem
 	}
 
 	// 3. Send IR Command (Default Path)
-	// Generate timestamp
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	signMethod := "HMAC-SHA256"
-
 	// Build URL path for IR AC control
 	urlPath := fmt.Sprintf("/v2.0/infrareds/%s/air-conditioners/%s/command", infraredID, remoteID)
-	fullURL := config.TuyaBaseURL + urlPath
 
 	// Create request body (single command, not array)
 	reqBody := map[string]interface{}{
 		"code":  code,
 		"value": value,
 	}
-	jsonBody, _ := json.Marshal(reqBody)
-
-	// Calculate content hash
-	h := sha256.New()
-	h.Write(jsonBody)
-	contentHash := hex.EncodeToString(h.Sum(nil))
+	stateCommands := []dtos.DeviceStateCommandDTO{{Code: code, Value: value}}
 
-	// Generate string to sign
-	stringToSign := tuya_utils.GenerateTuyaStringToSign("POST", contentHash, "", urlPath)
-
-	// Generate signature
-	signature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, timestamp, stringToSign)
-
-	// Prepare headers
-	headers := map[string]string{
-		"client_id":    config.TuyaClientID,
-		"sign":         signature,
-		"t":            timestamp,
-		"sign_method":  signMethod,
-		"access_token": accessToken,
+	// Models that only accept whole-state commands get the new value merged into the saved state,
+	// so changing the temperature does not reset the mode.
+	//
+	// Tuya API Documentation (Send Multiple-Key Commands to Air Conditioner):
+	// URL: /v2.0/infrareds/{infrared_id}/air-conditioners/{remote_id}/scenes/command
+	// Method: POST
+	// Body: {"power": 1, "mode": 0, "temp": 24, "wind": 0}
+	if uc.flagEnabled(FlagIRACFullState, remoteID) {
+		if fullState, ok := uc.irACFullState(remoteID, code, value); ok {
+			urlPath = fmt.Sprintf("/v2.0/infrareds/%s/air-conditioners/%s/scenes/command", infraredID, remoteID)
+			reqBody = make(map[string]interface{}, len(fullState))
+			stateCommands = make([]dtos.DeviceStateCommandDTO, 0, len(fullState))
+			for _, stateCode := range irACRestoreOrder {
+				reqBody[stateCode] = fullState[stateCode]
+				stateCommands = append(stateCommands, dtos.DeviceStateCommandDTO{Code: stateCode, Value: fullState[stateCode]})
+			}
+		}
 	}
+	jsonBody, _ := json.Marshal(reqBody)
 
 	// Call service
-	utils.LogDebug("SendIRACCommand: InfraredID=%s, RemoteID=%s, Code=%s, Value=%d, URL=%s, Body=%s", infraredID, remoteID, code, value, fullURL, string(jsonBody))
-	resp, err := uc.service.SendIRCommand(fullURL, headers, jsonBody)
+	utils.LogDebug("SendIRACCommand: InfraredID=%s, RemoteID=%s, Code=%s, Value=%d, Path=%s, Body=%s", infraredID, remoteID, code, value, urlPath, string(jsonBody))
+	resp, err := uc.service.SendIRCommand(ctx, urlPath, accessToken, jsonBody)
 	if err != nil {
 		return false, err
 	}
 
 	if !resp.Success {
-		utils.LogError("Tuya IR API Command Failed. Code: %d, Msg: %s", resp.Code, resp.Msg)
+		utils.LoggerFromContext(ctx).WithFields(utils.Fields{"device_id": remoteID, "infrared_id": infraredID, "tuya_code": resp.Code}).Error("Tuya IR API Command Failed. Code: %d, Msg: %s", resp.Code, resp.Msg)
 		
 		// 30100 = Custom Gateway/Device limitation?
 		// 1106 = Permission Deny (often instruction set mismatch)
-		if resp.Code == 30100 || resp.Code == 1106 {
+		if (resp.Code == 30100 || resp.Code == 1106) && uc.flagEnabled(FlagIRStandardFallback, remoteID) {
 			utils.LogWarn("Tuya IR API error %d detected. Attempting fallback to Standard Device Control for device %s...", resp.Code, infraredID)
 			return sendLegacy()
 		}
 		
-		return false, fmt.Errorf("tuya IR API failed: %s (code: %d)", resp.Msg, resp.Code)
+		return false, tuya_errors.FromTuya("failed to send IR command", resp.Code, resp.Msg)
 	}
 
 	// Save state after successful command
 	if uc.deviceStateUC != nil {
-		stateCommands := []dtos.DeviceStateCommandDTO{
-			{Code: code, Value: value},
-		}
 		if err := uc.deviceStateUC.SaveDeviceState(remoteID, stateCommands); err != nil {
 			utils.LogWarn("Failed to save device state for %s: %v", remoteID, err)
 		}
 	}
 
-	// Invalidate cache for this device
-	if uc.cache != nil {
-		cacheKey := fmt.Sprintf("cache:tuya_device:%s", remoteID)
-		if err := uc.cache.Delete(cacheKey); err != nil {
-			utils.LogWarn("Failed to invalidate cache for device %s: %v", remoteID, err)
-		} else {
-			utils.LogDebug("Cache invalidated for device %s", remoteID)
-		}
-	}
+	uc.publishDeviceEvent(remoteID, "infrared_ac", stateCommands)
+
+	// Reflect the new value in the cached device detail
+	uc.updateCachedStatus(remoteID, stateCommands)
 
 	return resp.Result, nil
 }
 
+// irACFullState merges an IR AC command into the saved state of the remote, returning the power, mode,
+// temp and wind values to send together. Settings never saved take the defaults of irACDefaultState,
+// except power, which is assumed on when another setting changes. It reports false for codes outside
+// the whole state (e.g., learned keys), which are sent on their own.
+func (uc *TuyaDeviceControlUseCase) irACFullState(remoteID, code string, value int) (map[string]int, bool) {
+	if _, ok := irACDefaultState[code]; !ok {
+		return nil, false
+	}
+
+	state := make(map[string]int, len(irACDefaultState))
+	for stateCode, defaultValue := range irACDefaultState {
+		state[stateCode] = defaultValue
+	}
+	state["power"] = 1
+	if uc.deviceStateUC != nil {
+		if saved, err := uc.deviceStateUC.GetDeviceState(remoteID); err != nil {
+			utils.LogWarn("SendIRACCommand: Failed to read saved state of %s, using defaults: %v", remoteID, err)
+		} else if saved != nil {
+			for _, cmd := range saved.LastCommands {
+				if _, ok := state[cmd.Code]; !ok {
+					continue
+				}
+				if number, ok := numericValue(cmd.Value); ok {
+					state[cmd.Code] = int(number)
+				}
+			}
+		}
+	}
+	state[code] = value
+	return state, true
+}
+
 // SendCommand sends a set of commands to a standard Tuya device.
-// It generates the necessary signatures and headers, then dispatches the request via the service layer.
+// It dispatches the request via the service layer, which signs it.
 //
+// param ctx The request context, used for cancellation and timing metadata.
 // param accessToken The valid OAuth 2.0 access token.
 // param deviceID The unique ID of the device to control.
 // param commands A list of TuyaCommandDTOs representing the instructions.
 // return bool True if the command was executed successfully.
-// return error An error if the API request fails or returns an error code.
+// return error An error if the API request fails or returns an error code, or an *ApprovalRequiredError
+// when the commands are protected by an approval rule and were held as a pending action.
 // @throws error If the command fails, including specific retry logic for legacy switch commands involving naming mismatch.
-func (uc *TuyaDeviceControlUseCase) SendCommand(accessToken, deviceID string, commands []dtos.TuyaCommandDTO) (bool, error) {
-	// Get config
-	config := utils.GetConfig()
-
-	// Generate timestamp
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	signMethod := "HMAC-SHA256"
+func (uc *TuyaDeviceControlUseCase) SendCommand(ctx context.Context, accessToken, deviceID string, commands []dtos.TuyaCommandDTO) (bool, error) {
+	if uc.approvals != nil {
+		action, err := uc.approvals.Hold(ctx, deviceID, commands)
+		if err != nil {
+			return false, err
+		}
+		if action != nil {
+			return false, &ApprovalRequiredError{Action: action}
+		}
+	}
+	var ok bool
+	err := uc.cooldowns.Throttle(ctx, deviceID, func() error {
+		var sendErr error
+		ok, sendErr = uc.sendCommand(ctx, accessToken, deviceID, commands)
+		return sendErr
+	})
+	uc.audit(AuditActionDeviceCommand, deviceID, commands, err)
+	if err != nil {
+		failed := make([]dtos.DeviceStateCommandDTO, len(commands))
+		for i, cmd := range commands {
+			failed[i] = dtos.DeviceStateCommandDTO{Code: cmd.Code, Value: cmd.Value}
+		}
+		uc.publishCommandFailed(deviceID, uc.cachedCategory(deviceID), failed, err)
+	}
+	return ok, err
+}
 
-	// Build URL path
-	urlPath := fmt.Sprintf("/v1.0/iot-03/devices/%s/commands", deviceID)
-	fullURL := config.TuyaBaseURL + urlPath
+// sendCommand implements SendCommand.
+func (uc *TuyaDeviceControlUseCase) sendCommand(ctx context.Context, accessToken, deviceID string, commands []dtos.TuyaCommandDTO) (bool, error) {
+	// Build URL path: /v1.0/iot-03/devices/{id}/commands, or /v1.0/devices/{id}/commands for Smart Home projects
+	urlPath := uc.service.Endpoints().Strategy().CommandsPath(deviceID)
 
 	// Convert DTOs to Entities
 	var entityCommands []entities.TuyaCommand
@@ -312,50 +366,23 @@ func (uc *TuyaDeviceControlUseCase) SendCommand(accessToken, deviceID string, co
 		})
 	}
 
-	// Create request body for signature calculation
-	reqBody := entities.TuyaCommandRequest{
-		Commands: entityCommands,
-	}
-	jsonBody, _ := json.Marshal(reqBody)
-
-	// Calculate content hash
-	h := sha256.New()
-	h.Write(jsonBody)
-	contentHash := hex.EncodeToString(h.Sum(nil))
-
-	// Generate string to sign
-	stringToSign := tuya_utils.GenerateTuyaStringToSign("POST", contentHash, "", urlPath)
-	// log.Printf("DEBUG: Command StringToSign: %s", stringToSign)
-
-	// Generate signature
-	signature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, timestamp, stringToSign)
-
-	// Prepare headers
-	headers := map[string]string{
-		"client_id":    config.TuyaClientID,
-		"sign":         signature,
-		"t":            timestamp,
-		"sign_method":  signMethod,
-		"access_token": accessToken,
-	}
-
 	// Call service
-	utils.LogDebug("SendCommand: DeviceID=%s, URL=%s, Body=%s", deviceID, fullURL, string(jsonBody))
-	resp, err := uc.service.SendCommand(fullURL, headers, entityCommands)
+	utils.LogDebug("SendCommand: DeviceID=%s, Path=%s, Commands=%+v", deviceID, urlPath, entityCommands)
+	resp, err := uc.service.SendCommand(ctx, urlPath, accessToken, entityCommands)
 	if err != nil {
 		return false, err
 	}
 
 	if !resp.Success {
-		utils.LogError("Tuya API Command Failed. Code: %d, Msg: %s", resp.Code, resp.Msg)
+		utils.LoggerFromContext(ctx).WithFields(utils.Fields{"device_id": deviceID, "tuya_code": resp.Code}).Error("Tuya API Command Failed. Code: %d, Msg: %s", resp.Code, resp.Msg)
 
 		// Handle code 1106 (Permission Deny) - usually means incorrect request body/parameters
 		if resp.Code == 1106 {
-			return false, fmt.Errorf("bad request: invalid input parameters. Please verify your request body matches the device's expected command format (code: %d)", resp.Code)
+			return false, tuya_errors.BadRequest("invalid input parameters. Please verify your request body matches the device's expected command format (code: %d)", resp.Code)
 		}
 
 		// RETRY LOGIC for "switch_" mismatch (switch_1 -> switch1)
-		if resp.Code == 2008 {
+		if resp.Code == 2008 && uc.flagEnabled(FlagSwitchCodeRetry, deviceID) {
 			var retryCommands []entities.TuyaCommand
 			shouldRetry := false
 			
@@ -376,44 +403,22 @@ func (uc *TuyaDeviceControlUseCase) SendCommand(accessToken, deviceID string, co
 				// Use LEGACY endpoint for DP instructions (v1.0/devices/{id}/commands) instead of iot-03
 				// This is crucial because iot-03 endpoint validates against Standard Instruction Set (which is empty here).
 				retryUrlPath := fmt.Sprintf("/v1.0/devices/%s/commands", deviceID)
-				retryFullURL := config.TuyaBaseURL + retryUrlPath
-
-				// Re-create request body
-				retryReqBody := entities.TuyaCommandRequest{Commands: retryCommands}
-				retryJsonBody, _ := json.Marshal(retryReqBody)
-
-				// Re-calculate content hash
-				hRetry := sha256.New()
-				hRetry.Write(retryJsonBody)
-				retryContentHash := hex.EncodeToString(hRetry.Sum(nil))
-
-				// Re-sign
-				retryStringToSign := tuya_utils.GenerateTuyaStringToSign("POST", retryContentHash, "", retryUrlPath)
-				retrySignature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, timestamp, retryStringToSign)
-
-				// Re-prepare headers
-				retryHeaders := map[string]string{
-					"client_id":    config.TuyaClientID,
-					"sign":         retrySignature,
-					"t":            timestamp,
-					"sign_method":  signMethod,
-					"access_token": accessToken,
-				}
-				
+
 				// Retry call
-				retryResp, retryErr := uc.service.SendCommand(retryFullURL, retryHeaders, retryCommands)
+				utils.RequestMetaFromContext(ctx).AddRetry()
+				retryResp, retryErr := uc.service.SendCommand(ctx, retryUrlPath, accessToken, retryCommands)
 				if retryErr == nil && retryResp.Success {
 					utils.LogInfo("Retry success with corrected commands!")
 					return retryResp.Result, nil
 				} else if retryErr != nil {
 					utils.LogError("Retry failed: %v", retryErr)
 				} else {
-					utils.LogError("Retry API failed: %d %s", retryResp.Code, retryResp.Msg)
+					utils.LoggerFromContext(ctx).WithFields(utils.Fields{"device_id": deviceID, "tuya_code": retryResp.Code}).Error("Retry API failed: %d %s", retryResp.Code, retryResp.Msg)
 				}
 			}
 		}
 		
-		return false, fmt.Errorf("tuya API failed: %s (code: %d)", resp.Msg, resp.Code)
+		return false, tuya_errors.FromTuya("failed to send command", resp.Code, resp.Msg)
 	}
 
 	// Save state after successful command
@@ -430,15 +435,163 @@ func (uc *TuyaDeviceControlUseCase) SendCommand(accessToken, deviceID string, co
 		}
 	}
 
-	// Invalidate cache for this device
-	if uc.cache != nil {
-		cacheKey := fmt.Sprintf("cache:tuya_device:%s", deviceID)
+	// Notify realtime subscribers (category is read from cache before invalidation)
+	eventCommands := make([]dtos.DeviceStateCommandDTO, len(commands))
+	for i, cmd := range commands {
+		eventCommands[i] = dtos.DeviceStateCommandDTO{Code: cmd.Code, Value: cmd.Value}
+	}
+	uc.publishDeviceEvent(deviceID, uc.cachedCategory(deviceID), eventCommands)
+
+	// Reflect the new values in the cached device detail
+	uc.updateCachedStatus(deviceID, eventCommands)
+
+	return resp.Result, nil
+}
+
+// flagEnabled reports whether a feature flag is enabled for a device, using the flag's default
+// rollout when no FeatureFlagUseCase is configured.
+func (uc *TuyaDeviceControlUseCase) flagEnabled(name, deviceID string) bool {
+	if uc.featureFlags == nil {
+		return featureFlagDefinitions[name].percentage >= 100
+	}
+	return uc.featureFlags.IsEnabled(name, deviceID)
+}
+
+// audit records a command attempt in the audit log.
+func (uc *TuyaDeviceControlUseCase) audit(action, deviceID string, detail interface{}, err error) {
+	if uc.auditLogUC == nil {
+		return
+	}
+	if auditErr := uc.auditLogUC.Record(action, deviceID, detail, err); auditErr != nil {
+		utils.LogWarn("Failed to record audit entry for %s: %v", deviceID, auditErr)
+	}
+}
+
+// publishDeviceEvent notifies realtime subscribers that a device received new state values.
+//
+// param deviceID The device whose state changed.
+// param category The device category, used by category subscription filters.
+// param commands The commands that were applied to the device.
+func (uc *TuyaDeviceControlUseCase) publishDeviceEvent(deviceID, category string, commands []dtos.DeviceStateCommandDTO) {
+	if uc.realtimeHub == nil {
+		return
+	}
+
+	status := make([]realtime_dtos.DeviceEventStatusDTO, len(commands))
+	for i, cmd := range commands {
+		status[i] = realtime_dtos.DeviceEventStatusDTO{Code: cmd.Code, Value: cmd.Value}
+	}
+
+	uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+		Type:      "device_state",
+		DeviceID:  deviceID,
+		Category:  category,
+		Status:    status,
+		Timestamp: uc.clock.Now().Unix(),
+	})
+}
+
+// publishCommandFailed notifies realtime subscribers (and webhooks) that a command could not be sent.
+//
+// param deviceID The device the command was sent to.
+// param category The device category, used by category subscription filters.
+// param commands The commands that failed.
+// param err The error returned for the commands.
+func (uc *TuyaDeviceControlUseCase) publishCommandFailed(deviceID, category string, commands []dtos.DeviceStateCommandDTO, err error) {
+	if uc.realtimeHub == nil {
+		return
+	}
+
+	status := make([]realtime_dtos.DeviceEventStatusDTO, len(commands))
+	for i, cmd := range commands {
+		status[i] = realtime_dtos.DeviceEventStatusDTO{Code: cmd.Code, Value: cmd.Value}
+	}
+
+	uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+		Type:      "command_failed",
+		DeviceID:  deviceID,
+		Category:  category,
+		Status:    status,
+		Error:     err.Error(),
+		Timestamp: uc.clock.Now().Unix(),
+	})
+}
+
+// updateCachedStatus merges applied commands into the cached device detail, like DeviceStateUseCase merges
+// saved states, so the cache stays warm and reflects the change immediately. The entry keeps its expiry;
+// nothing is written when the device is not cached.
+//
+// param deviceID The device the commands were applied to.
+// param commands The applied commands.
+func (uc *TuyaDeviceControlUseCase) updateCachedStatus(deviceID string, commands []dtos.DeviceStateCommandDTO) {
+	if uc.cache == nil {
+		return
+	}
+	cacheKey := fmt.Sprintf("cache:tuya_device:%s", deviceID)
+	cachedData, expiresAt, err := uc.cache.GetWithExpiry(cacheKey)
+	if err != nil || cachedData == nil {
+		return
+	}
+
+	var cachedDTO dtos.TuyaDeviceDTO
+	if err := json.Unmarshal(cachedData, &cachedDTO); err != nil {
+		// An unreadable entry cannot be patched; drop it so the next read refetches
 		if err := uc.cache.Delete(cacheKey); err != nil {
 			utils.LogWarn("Failed to invalidate cache for device %s: %v", deviceID, err)
-		} else {
-			utils.LogDebug("Cache invalidated for device %s", deviceID)
 		}
+		return
 	}
 
-	return resp.Result, nil
-}
\ No newline at end of file
+	for _, cmd := range commands {
+		merged := false
+		for i := range cachedDTO.Status {
+			if cachedDTO.Status[i].Code == cmd.Code {
+				cachedDTO.Status[i].Value = cmd.Value
+				merged = true
+				break
+			}
+		}
+		if !merged {
+			cachedDTO.Status = append(cachedDTO.Status, dtos.TuyaDeviceStatusDTO{Code: cmd.Code, Value: cmd.Value})
+		}
+	}
+
+	jsonData, err := json.Marshal(cachedDTO)
+	if err != nil {
+		utils.LogWarn("Failed to marshal cached device %s: %v", deviceID, err)
+		return
+	}
+	if expiresAt == 0 {
+		err = uc.cache.SetPersistent(cacheKey, jsonData)
+	} else {
+		ttl := time.Until(time.Unix(int64(expiresAt), 0))
+		if ttl <= 0 {
+			return
+		}
+		err = uc.cache.Set(cacheKey, jsonData, ttl)
+	}
+	if err != nil {
+		utils.LogWarn("Failed to update cache for device %s: %v", deviceID, err)
+		return
+	}
+	utils.LogDebug("Cache updated in place for device %s", deviceID)
+}
+
+// cachedCategory returns the category of a device from the device detail cache, if present.
+//
+// param deviceID The device to look up.
+// return string The cached category, or an empty string when unknown.
+func (uc *TuyaDeviceControlUseCase) cachedCategory(deviceID string) string {
+	if uc.cache == nil {
+		return ""
+	}
+	cachedData, err := uc.cache.Get(fmt.Sprintf("cache:tuya_device:%s", deviceID))
+	if err != nil || cachedData == nil {
+		return ""
+	}
+	var cachedDTO dtos.TuyaDeviceDTO
+	if err := json.Unmarshal(cachedData, &cachedDTO); err != nil {
+		return ""
+	}
+	return cachedDTO.Category
+}
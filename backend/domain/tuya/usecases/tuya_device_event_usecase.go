@@ -0,0 +1,164 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+)
+
+// TuyaDeviceEventUseCase applies device events pushed by Tuya's message service.
+// Status reports update the persisted device state, invalidate cached device data and are forwarded
+// to realtime subscribers, scene switch bindings and automation rules.
+type TuyaDeviceEventUseCase struct {
+	deviceStateUC *DeviceStateUseCase
+	cache         persistence.CacheStore
+	realtimeHub   *realtime_services.RealtimeHubService
+	sceneSwitchUC *SceneSwitchUseCase
+	automationUC  *AutomationUseCase
+	clock         utils.Clock
+}
+
+// NewTuyaDeviceEventUseCase initializes a new TuyaDeviceEventUseCase.
+//
+// param deviceStateUC The DeviceStateUseCase used to persist reported values.
+// param cache The CacheStore holding cached device data.
+// param realtimeHub The hub used to notify realtime subscribers (optional).
+// param sceneSwitchUC The usecase running scene switch bindings (optional).
+// param automationUC The usecase evaluating automation rules (optional).
+// param clock The Clock used to timestamp published events.
+// return *TuyaDeviceEventUseCase A pointer to the initialized usecase.
+func NewTuyaDeviceEventUseCase(deviceStateUC *DeviceStateUseCase, cache persistence.CacheStore, realtimeHub *realtime_services.RealtimeHubService, sceneSwitchUC *SceneSwitchUseCase, automationUC *AutomationUseCase, clock utils.Clock) *TuyaDeviceEventUseCase {
+	return &TuyaDeviceEventUseCase{
+		deviceStateUC: deviceStateUC,
+		cache:         cache,
+		realtimeHub:   realtimeHub,
+		sceneSwitchUC: sceneSwitchUC,
+		automationUC:  automationUC,
+		clock:         clock,
+	}
+}
+
+// HandleEvent applies a single device event.
+//
+// param event The decrypted device event.
+func (uc *TuyaDeviceEventUseCase) HandleEvent(event entities.TuyaDeviceEvent) {
+	if event.DevID == "" {
+		return
+	}
+
+	switch event.Protocol {
+	case entities.TuyaEventProtocolStatus:
+		uc.handleStatus(event)
+	case entities.TuyaEventProtocolDevice:
+		uc.handleLifecycle(event)
+	}
+}
+
+// handleStatus persists reported values and notifies subscribers.
+func (uc *TuyaDeviceEventUseCase) handleStatus(event entities.TuyaDeviceEvent) {
+	if len(event.Status) == 0 {
+		return
+	}
+	utils.LogDebug("TuyaDeviceEventUseCase: Status report for %s (%d values)", event.DevID, len(event.Status))
+
+	// Category is read before the cache is invalidated
+	category := uc.cachedCategory(event.DevID)
+
+	commands := make([]dtos.DeviceStateCommandDTO, len(event.Status))
+	for i, status := range event.Status {
+		commands[i] = dtos.DeviceStateCommandDTO{Code: status.Code, Value: status.Value}
+	}
+	if uc.deviceStateUC != nil && uc.cache != nil {
+		if err := uc.deviceStateUC.SaveDeviceState(event.DevID, commands); err != nil {
+			utils.LogWarn("TuyaDeviceEventUseCase: Failed to save state for %s: %v", event.DevID, err)
+		}
+	}
+	uc.invalidate(event.DevID)
+
+	if uc.realtimeHub != nil {
+		status := make([]realtime_dtos.DeviceEventStatusDTO, len(event.Status))
+		for i, s := range event.Status {
+			status[i] = realtime_dtos.DeviceEventStatusDTO{Code: s.Code, Value: s.Value}
+		}
+		uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+			Type:      "device_state",
+			DeviceID:  event.DevID,
+			Category:  category,
+			Status:    status,
+			Timestamp: uc.clock.Now().Unix(),
+		})
+	}
+
+	// Scene switches report button presses as status values; other codes are ignored by HandleEvent
+	if uc.sceneSwitchUC != nil {
+		for _, s := range event.Status {
+			if _, err := uc.sceneSwitchUC.HandleEvent(context.Background(), event.DevID, s.Code, s.Value); err != nil {
+				utils.LogWarn("TuyaDeviceEventUseCase: Scene switch %s event %s failed: %v", event.DevID, s.Code, err)
+			}
+		}
+	}
+
+	if uc.automationUC != nil {
+		status := make([]dtos.TuyaDeviceStatusDTO, len(event.Status))
+		for i, s := range event.Status {
+			status[i] = dtos.TuyaDeviceStatusDTO{Code: s.Code, Value: s.Value}
+		}
+		uc.automationUC.HandleStatus(event.DevID, status)
+	}
+}
+
+// handleLifecycle invalidates cached data on online/offline and other device lifecycle changes.
+func (uc *TuyaDeviceEventUseCase) handleLifecycle(event entities.TuyaDeviceEvent) {
+	utils.LogDebug("TuyaDeviceEventUseCase: %s event for %s", event.BizCode, event.DevID)
+
+	category := uc.cachedCategory(event.DevID)
+	uc.invalidate(event.DevID)
+
+	if uc.realtimeHub == nil {
+		return
+	}
+	switch event.BizCode {
+	case "online", "offline":
+		uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+			Type:      "device_" + event.BizCode,
+			DeviceID:  event.DevID,
+			Category:  category,
+			Timestamp: uc.clock.Now().Unix(),
+		})
+	}
+}
+
+// invalidate drops the cached detail of a device and all cached device lists, which may embed it.
+func (uc *TuyaDeviceEventUseCase) invalidate(deviceID string) {
+	if uc.cache == nil {
+		return
+	}
+	if err := uc.cache.Delete(fmt.Sprintf("cache:tuya_device:%s", deviceID)); err != nil {
+		utils.LogWarn("TuyaDeviceEventUseCase: Failed to invalidate cache for %s: %v", deviceID, err)
+	}
+	if err := uc.cache.ClearWithPrefix("cache:devices:"); err != nil {
+		utils.LogWarn("TuyaDeviceEventUseCase: Failed to invalidate device lists: %v", err)
+	}
+}
+
+// cachedCategory returns the category of a device from the device detail cache, if present.
+func (uc *TuyaDeviceEventUseCase) cachedCategory(deviceID string) string {
+	if uc.cache == nil {
+		return ""
+	}
+	cachedData, err := uc.cache.Get(fmt.Sprintf("cache:tuya_device:%s", deviceID))
+	if err != nil || cachedData == nil {
+		return ""
+	}
+	var cachedDTO dtos.TuyaDeviceDTO
+	if err := json.Unmarshal(cachedData, &cachedDTO); err != nil {
+		return ""
+	}
+	return cachedDTO.Category
+}
@@ -0,0 +1,132 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+)
+
+// TuyaDeviceMetadataUseCase renames devices in the Tuya cloud and edits their local metadata.
+type TuyaDeviceMetadataUseCase struct {
+	service     *services.TuyaDeviceService
+	cache       persistence.CacheStore
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	metadataUC  *DeviceMetadataUseCase
+}
+
+// NewTuyaDeviceMetadataUseCase initializes a new TuyaDeviceMetadataUseCase.
+//
+// param service The TuyaDeviceService used to rename devices.
+// param cache The CacheStore whose cached device details and lists are dropped after a rename.
+// param getDeviceUC The usecase used to fetch the device.
+// param metadataUC The usecase storing local metadata.
+// return *TuyaDeviceMetadataUseCase A pointer to the initialized usecase.
+func NewTuyaDeviceMetadataUseCase(service *services.TuyaDeviceService, cache persistence.CacheStore, getDeviceUC *TuyaGetDeviceByIDUseCase, metadataUC *DeviceMetadataUseCase) *TuyaDeviceMetadataUseCase {
+	return &TuyaDeviceMetadataUseCase{
+		service:     service,
+		cache:       cache,
+		getDeviceUC: getDeviceUC,
+		metadataUC:  metadataUC,
+	}
+}
+
+// RenameDevice changes the name of a device in the Tuya cloud, so the Smart Life app shows it too.
+// Cached device details and lists are dropped, since they embed the old name.
+//
+// Tuya API Documentation (Modify Device Name):
+// URL: /v1.0/devices/{device_id}
+// Method: PUT
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// param name The new name.
+// return *dtos.TuyaDeviceDTO The renamed device.
+// return error A bad request error for an empty name, or an API error.
+func (uc *TuyaDeviceMetadataUseCase) RenameDevice(ctx context.Context, accessToken, deviceID, name string) (*dtos.TuyaDeviceDTO, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, tuya_errors.BadRequest("name must not be empty")
+	}
+	if _, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(entities.TuyaRenameDeviceRequest{Name: name})
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rename request: %w", err)
+	}
+	resp, err := uc.service.RenameDevice(ctx, fmt.Sprintf("/v1.0/devices/%s", deviceID), accessToken, body)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to rename device", resp.Code, resp.Msg)
+	}
+	utils.LogInfo("RenameDevice: Renamed device %s to %q", deviceID, name)
+
+	if err := uc.cache.Delete(fmt.Sprintf("cache:tuya_device:%s", deviceID)); err != nil {
+		utils.LogWarn("RenameDevice: Failed to invalidate cache for %s: %v", deviceID, err)
+	}
+	if err := uc.cache.ClearWithPrefix("cache:devices:"); err != nil {
+		utils.LogWarn("RenameDevice: Failed to invalidate device lists: %v", err)
+	}
+	return uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+}
+
+// GetMetadata returns the local metadata of a device.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// return dtos.DeviceMetadataDTO The metadata; empty when none is stored.
+// return error An error if the device cannot be fetched.
+func (uc *TuyaDeviceMetadataUseCase) GetMetadata(ctx context.Context, accessToken, deviceID string) (dtos.DeviceMetadataDTO, error) {
+	if _, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID); err != nil {
+		return dtos.DeviceMetadataDTO{}, err
+	}
+	return uc.metadataUC.GetMetadata(deviceID), nil
+}
+
+// UpdateMetadata changes the local metadata of a device.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// param req The changes; omitted fields are kept.
+// return dtos.DeviceMetadataDTO The updated metadata.
+// return error An error if the device cannot be fetched, or a storage error.
+func (uc *TuyaDeviceMetadataUseCase) UpdateMetadata(ctx context.Context, accessToken, deviceID string, req dtos.UpdateDeviceMetadataRequestDTO) (dtos.DeviceMetadataDTO, error) {
+	if _, err := uc.getDeviceUC.GetDeviceByID(ctx, accessToken, deviceID); err != nil {
+		return dtos.DeviceMetadataDTO{}, err
+	}
+	return uc.metadataUC.UpdateMetadata(deviceID, req)
+}
+
+// SetFavorite marks or unmarks a device as favorite.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID.
+// param favorite True to mark the device as favorite.
+// return dtos.DeviceMetadataDTO The updated metadata.
+// return error An error if the device cannot be fetched, or a storage error.
+func (uc *TuyaDeviceMetadataUseCase) SetFavorite(ctx context.Context, accessToken, deviceID string, favorite bool) (dtos.DeviceMetadataDTO, error) {
+	return uc.UpdateMetadata(ctx, accessToken, deviceID, dtos.UpdateDeviceMetadataRequestDTO{Favorite: &favorite})
+}
+
+// SetOrder replaces the custom device order used by the custom and favorite sort orders.
+//
+// param deviceIDs The device IDs in display order.
+// return []string The saved order.
+// return error A bad request error for invalid input, or a storage error.
+func (uc *TuyaDeviceMetadataUseCase) SetOrder(deviceIDs []string) ([]string, error) {
+	return uc.metadataUC.SetOrder(deviceIDs)
+}
@@ -0,0 +1,298 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+	"time"
+)
+
+const (
+	// lockRecordsDefaultRange is the window of unlock records returned when no range is given.
+	lockRecordsDefaultRange    = 7 * 24 * time.Hour
+	lockRecordsDefaultPageSize = 20
+	lockRecordsMaxPageSize     = 100
+)
+
+// lockCategories are the smart lock categories served by the door lock endpoints.
+var lockCategories = []string{"ms", "jtmspro", "jtmsbh"}
+
+// tempPasswordPhases names the phase of a temporary password on the lock.
+var tempPasswordPhases = map[int]string{1: "pending", 2: "active", 3: "frozen", 4: "deleted"}
+
+// TuyaDoorLockUseCase reads the unlock records of Tuya smart locks and manages their temporary passwords.
+// Passwords are encrypted with a one-off ticket before they leave the backend, as the lock API requires;
+// they are never stored. Creating and deleting passwords is recorded in the audit log.
+type TuyaDoorLockUseCase struct {
+	service    *services.TuyaDoorLockService
+	deviceUC   *TuyaGetDeviceByIDUseCase
+	auditLogUC *AuditLogUseCase
+	clock      utils.Clock
+}
+
+// NewTuyaDoorLockUseCase initializes a new TuyaDoorLockUseCase.
+//
+// param service The TuyaDoorLockService used for API communication.
+// param deviceUC The TuyaGetDeviceByIDUseCase used to check that a device is a lock.
+// param auditLogUC The usecase recording password changes (optional).
+// param clock The Clock used for default time ranges.
+// return *TuyaDoorLockUseCase A pointer to the initialized usecase.
+func NewTuyaDoorLockUseCase(service *services.TuyaDoorLockService, deviceUC *TuyaGetDeviceByIDUseCase, auditLogUC *AuditLogUseCase, clock utils.Clock) *TuyaDoorLockUseCase {
+	return &TuyaDoorLockUseCase{
+		service:    service,
+		deviceUC:   deviceUC,
+		auditLogUC: auditLogUC,
+		clock:      clock,
+	}
+}
+
+// ListRecords returns one page of the unlock records of a lock.
+//
+// Tuya API Documentation (Query Unlock Records):
+// URL: /v1.0/devices/{device_id}/door-lock/open-logs?page_no={page}&page_size={size}&start_time={ms}&end_time={ms}
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The lock ID.
+// param from The start of the range in Unix seconds (0 for 7 days before to).
+// param to The end of the range in Unix seconds (0 for now).
+// param page The page number, starting at 1 (0 for the first page).
+// param pageSize The page size, up to 100 (0 for 20).
+// return *dtos.LockRecordsResponseDTO The unlock records.
+// return error A bad request error for invalid input or non-lock devices.
+func (uc *TuyaDoorLockUseCase) ListRecords(ctx context.Context, accessToken, deviceID string, from, to int64, page, pageSize int) (*dtos.LockRecordsResponseDTO, error) {
+	if to == 0 {
+		to = uc.clock.Now().Unix()
+	}
+	if from == 0 {
+		from = to - int64(lockRecordsDefaultRange/time.Second)
+	}
+	if from >= to {
+		return nil, tuya_errors.BadRequest("from must be before to")
+	}
+	if page == 0 {
+		page = 1
+	}
+	if pageSize == 0 {
+		pageSize = lockRecordsDefaultPageSize
+	}
+	if page < 1 || pageSize < 1 || pageSize > lockRecordsMaxPageSize {
+		return nil, tuya_errors.BadRequest("page must be at least 1 and page_size between 1 and %d", lockRecordsMaxPageSize)
+	}
+	if err := uc.requireLock(ctx, accessToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	urlPath := fmt.Sprintf("/v1.0/devices/%s/door-lock/open-logs?page_no=%d&page_size=%d&start_time=%d&end_time=%d", deviceID, page, pageSize, from*1000, to*1000)
+	resp, err := uc.service.FetchOpenLogs(ctx, urlPath, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch unlock records", resp.Code, resp.Msg)
+	}
+
+	records := make([]dtos.LockRecordDTO, len(resp.Result.Logs))
+	for i, log := range resp.Result.Logs {
+		user := log.NickName
+		if user == "" {
+			user = log.UnlockName
+		}
+		records[i] = dtos.LockRecordDTO{
+			Method:    log.Status.Code,
+			Value:     log.Status.Value,
+			User:      user,
+			Timestamp: log.UpdateTime / 1000,
+		}
+	}
+	return &dtos.LockRecordsResponseDTO{
+		DeviceID: deviceID,
+		From:     from,
+		To:       to,
+		Page:     page,
+		PageSize: pageSize,
+		Total:    resp.Result.Total,
+		Records:  records,
+	}, nil
+}
+
+// ListTempPasswords returns the temporary passwords of a lock.
+//
+// Tuya API Documentation (Query Temporary Passwords):
+// URL: /v1.0/devices/{device_id}/door-lock/temp-passwords
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The lock ID.
+// return *dtos.TempPasswordsResponseDTO The temporary passwords, without the passwords themselves.
+// return error A bad request error for non-lock devices.
+func (uc *TuyaDoorLockUseCase) ListTempPasswords(ctx context.Context, accessToken, deviceID string) (*dtos.TempPasswordsResponseDTO, error) {
+	if err := uc.requireLock(ctx, accessToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	resp, err := uc.service.FetchTempPasswords(ctx, fmt.Sprintf("/v1.0/devices/%s/door-lock/temp-passwords", deviceID), accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch temporary passwords", resp.Code, resp.Msg)
+	}
+
+	passwords := make([]dtos.TempPasswordDTO, len(resp.Result))
+	for i, p := range resp.Result {
+		passwords[i] = tempPasswordDTO(p)
+	}
+	return &dtos.TempPasswordsResponseDTO{DeviceID: deviceID, Passwords: passwords}, nil
+}
+
+// CreateTempPassword creates a temporary password on a lock. A password ticket is requested first,
+// and the password is encrypted with the ticket key before it is sent.
+//
+// Tuya API Documentation (Get Password Ticket / Create Temporary Password):
+// URL: /v1.0/devices/{device_id}/door-lock/password-ticket, then /v1.0/devices/{device_id}/door-lock/temp-password
+// Method: POST
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The lock ID.
+// param req The password and its validity period.
+// return *dtos.TempPasswordDTO The created temporary password.
+// return error A bad request error for invalid input or non-lock devices.
+func (uc *TuyaDoorLockUseCase) CreateTempPassword(ctx context.Context, accessToken, deviceID string, req dtos.TempPasswordRequestDTO) (*dtos.TempPasswordDTO, error) {
+	now := uc.clock.Now().Unix()
+	if req.EffectiveTime == 0 {
+		req.EffectiveTime = now
+	}
+	if req.InvalidTime <= req.EffectiveTime {
+		return nil, tuya_errors.BadRequest("invalid_time must be after effective_time")
+	}
+	if req.InvalidTime <= now {
+		return nil, tuya_errors.BadRequest("invalid_time must be in the future")
+	}
+	if err := uc.requireLock(ctx, accessToken, deviceID); err != nil {
+		return nil, err
+	}
+
+	result := &dtos.TempPasswordDTO{
+		Name:          req.Name,
+		Phone:         req.Phone,
+		EffectiveTime: req.EffectiveTime,
+		InvalidTime:   req.InvalidTime,
+	}
+	err := uc.createTempPassword(ctx, accessToken, deviceID, req, result)
+	uc.audit(deviceID, "create_temp_password", result, err)
+	if err != nil {
+		return nil, err
+	}
+	utils.LogInfo("CreateTempPassword: Created temporary password %d on lock %s", result.PasswordID, deviceID)
+	return result, nil
+}
+
+// createTempPassword runs the ticket flow and fills in the ID of the created password.
+func (uc *TuyaDoorLockUseCase) createTempPassword(ctx context.Context, accessToken, deviceID string, req dtos.TempPasswordRequestDTO, result *dtos.TempPasswordDTO) error {
+	ticketResp, err := uc.service.CreatePasswordTicket(ctx, fmt.Sprintf("/v1.0/devices/%s/door-lock/password-ticket", deviceID), accessToken)
+	if err != nil {
+		return err
+	}
+	if !ticketResp.Success {
+		return tuya_errors.FromTuya("failed to issue a password ticket", ticketResp.Code, ticketResp.Msg)
+	}
+	encrypted, err := uc.service.EncryptPassword(ticketResp.Result, req.Password)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt password: %w", err)
+	}
+
+	body, err := json.Marshal(entities.TuyaTempPasswordRequest{
+		Name:          req.Name,
+		Password:      encrypted,
+		PasswordType:  "ticket",
+		TicketID:      ticketResp.Result.TicketID,
+		EffectiveTime: req.EffectiveTime,
+		InvalidTime:   req.InvalidTime,
+		Phone:         req.Phone,
+		TimeZone:      req.TimeZone,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal temporary password: %w", err)
+	}
+	resp, err := uc.service.CreateTempPassword(ctx, fmt.Sprintf("/v1.0/devices/%s/door-lock/temp-password", deviceID), accessToken, body)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return tuya_errors.FromTuya("failed to create temporary password", resp.Code, resp.Msg)
+	}
+	result.PasswordID = resp.Result.ID
+	return nil
+}
+
+// DeleteTempPassword deletes a temporary password from a lock.
+//
+// Tuya API Documentation (Delete Temporary Password):
+// URL: /v1.0/devices/{device_id}/door-lock/temp-passwords/{password_id}
+// Method: DELETE
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The lock ID.
+// param passwordID The temporary password ID.
+// return error A bad request error for non-lock devices.
+func (uc *TuyaDoorLockUseCase) DeleteTempPassword(ctx context.Context, accessToken, deviceID string, passwordID int64) error {
+	if err := uc.requireLock(ctx, accessToken, deviceID); err != nil {
+		return err
+	}
+
+	resp, err := uc.service.DeleteTempPassword(ctx, fmt.Sprintf("/v1.0/devices/%s/door-lock/temp-passwords/%d", deviceID, passwordID), accessToken)
+	if err == nil && !resp.Success {
+		err = tuya_errors.FromTuya("failed to delete temporary password", resp.Code, resp.Msg)
+	}
+	uc.audit(deviceID, "delete_temp_password", dtos.TempPasswordDTO{PasswordID: passwordID}, err)
+	if err != nil {
+		return err
+	}
+	utils.LogInfo("DeleteTempPassword: Deleted temporary password %d from lock %s", passwordID, deviceID)
+	return nil
+}
+
+// requireLock rejects devices that are not smart locks.
+func (uc *TuyaDoorLockUseCase) requireLock(ctx context.Context, accessToken, deviceID string) error {
+	device, err := uc.deviceUC.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return err
+	}
+	if !containsString(lockCategories, device.Category) {
+		return tuya_errors.BadRequest("device %s is category %s, not a door lock", deviceID, device.Category)
+	}
+	return nil
+}
+
+// audit records a password change; the detail never contains the password.
+func (uc *TuyaDoorLockUseCase) audit(deviceID, operation string, detail interface{}, err error) {
+	if uc.auditLogUC == nil {
+		return
+	}
+	entry := map[string]interface{}{"operation": operation, "password": detail}
+	if auditErr := uc.auditLogUC.Record(AuditActionDoorLock, deviceID, entry, err); auditErr != nil {
+		utils.LogWarn("Failed to record audit entry for %s: %v", deviceID, auditErr)
+	}
+}
+
+// tempPasswordDTO converts a temporary password of the lock API.
+func tempPasswordDTO(p entities.TuyaTempPassword) dtos.TempPasswordDTO {
+	return dtos.TempPasswordDTO{
+		PasswordID:    p.ID,
+		Name:          p.Name,
+		Phone:         p.Phone,
+		EffectiveTime: p.EffectiveTime,
+		InvalidTime:   p.InvalidTime,
+		Phase:         tempPasswordPhases[p.Phase],
+	}
+}
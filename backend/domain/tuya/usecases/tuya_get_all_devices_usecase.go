@@ -1,265 +1,249 @@
 package usecases
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
+	"math"
 	"sort"
-	"strconv"
 	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
 	"teralux_app/domain/common/infrastructure/persistence"
+	tuya_errors "teralux_app/domain/tuya/errors"
 	"teralux_app/domain/tuya/services"
 	"teralux_app/domain/common/utils"
-	tuya_utils "teralux_app/domain/tuya/utils"
-	"time"
 )
 
+// maxTuyaDevicePageSize is the largest page the iot-03 device list returns; larger pages are cut in memory.
+const maxTuyaDevicePageSize = 100
+
+// Device list sort orders.
+const (
+	DeviceSortName     = "name"
+	DeviceSortFavorite = "favorite"
+	DeviceSortCustom   = "custom"
+	DeviceSortOnline   = "online"
+)
+
+// deviceSortOrders are the accepted values of the sort parameter.
+var deviceSortOrders = []string{DeviceSortName, DeviceSortFavorite, DeviceSortCustom, DeviceSortOnline}
+
+// deviceListSerializationPath names the device list in the serialization stats.
+const deviceListSerializationPath = "device_list"
+
 // TuyaGetAllDevicesUseCase orchestrates the retrieval and aggregation of device data.
-// It combines the user's device list, individual device specifications, and real-time status.
+// It combines the user's device list and real-time status.
+// Marshaled responses built from the cached list are kept in memory, so polling clients are served
+// without decoding and re-encoding the same list on every request.
 type TuyaGetAllDevicesUseCase struct {
 	service       *services.TuyaDeviceService
-	cache         *persistence.BadgerService
+	cache         persistence.CacheStore
+	ttls          *persistence.CacheTTLPolicy
 	deviceStateUC *DeviceStateUseCase
+	changeLogUC   *DeviceChangeLogUseCase
+	channelUC     *DeviceChannelUseCase
+	specUC        *DeviceSpecificationUseCase
+	categoryUC    *DeviceCategoryFilterUseCase
+	metadataUC    *DeviceMetadataUseCase
+	payloads      *utils.PayloadCache
 }
 
 // NewTuyaGetAllDevicesUseCase initializes a new TuyaGetAllDevicesUseCase.
 //
 // param service The TuyaDeviceService used for API interactions.
-// param cache The BadgerService used for caching device lists.
+// param cache The CacheStore used for caching device lists.
+// param ttls The CacheTTLPolicy deciding how long device lists stay cached.
 // param deviceStateUC The DeviceStateUseCase for cleaning up orphaned states.
+// param changeLogUC The DeviceChangeLogUseCase recording differences between refreshes.
+// param channelUC The DeviceChannelUseCase for exposing multi-gang switch channels (optional).
+// param specUC The DeviceSpecificationUseCase fetching specifications for debug logging (optional).
+// param categoryUC The DeviceCategoryFilterUseCase hiding filtered device categories (optional).
+// param metadataUC The DeviceMetadataUseCase merging local labels, icons and favorites (optional).
 // return *TuyaGetAllDevicesUseCase A pointer to the initialized usecase.
-func NewTuyaGetAllDevicesUseCase(service *services.TuyaDeviceService, cache *persistence.BadgerService, deviceStateUC *DeviceStateUseCase) *TuyaGetAllDevicesUseCase {
+func NewTuyaGetAllDevicesUseCase(service *services.TuyaDeviceService, cache persistence.CacheStore, ttls *persistence.CacheTTLPolicy, deviceStateUC *DeviceStateUseCase, changeLogUC *DeviceChangeLogUseCase, channelUC *DeviceChannelUseCase, specUC *DeviceSpecificationUseCase, categoryUC *DeviceCategoryFilterUseCase, metadataUC *DeviceMetadataUseCase) *TuyaGetAllDevicesUseCase {
 	return &TuyaGetAllDevicesUseCase{
 		service:       service,
 		cache:         cache,
+		ttls:          ttls,
 		deviceStateUC: deviceStateUC,
+		changeLogUC:   changeLogUC,
+		channelUC:     channelUC,
+		specUC:        specUC,
+		categoryUC:    categoryUC,
+		metadataUC:    metadataUC,
+		payloads:      utils.NewPayloadCache(deviceListSerializationPath),
+	}
+}
+
+// GetAllDevicesPayload returns the marshaled result of GetAllDevices.
+// When the device list is cached and the caller sees every device, the payload built from the same cached
+// bytes, response mode and query is reused; it is rebuilt as soon as the cached list, a channel name, device
+// metadata or the category filter changes.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param uid The Tuya User ID for whom to fetch devices.
+// param page Page number for pagination (optional, 0 to ignore).
+// param limit Items per page (optional, 0 to ignore).
+// param category Category to filter by (optional, empty to ignore).
+// param visible Devices the caller may see (optional, nil for all devices).
+// param sortBy The sort order: name, favorite, custom or online (empty for name).
+// return json.RawMessage The marshaled TuyaDevicesResponseDTO.
+// return error A bad request error for an unknown sort order, or if fetching or encoding
+// the device list fails.
+func (uc *TuyaGetAllDevicesUseCase) GetAllDevicesPayload(ctx context.Context, accessToken, uid string, page, limit int, category string, visible DeviceFilter, sortBy string) (json.RawMessage, error) {
+	if sortBy == "" {
+		sortBy = DeviceSortName
+	}
+	if !containsString(deviceSortOrders, sortBy) {
+		return nil, tuya_errors.BadRequest("sort must be one of name, favorite, custom, online")
+	}
+
+	// Tenant-filtered lists differ per caller, so only unfiltered payloads are shared
+	var payloadKey string
+	var version uint64
+	if visible == nil {
+		cacheKey := deviceListCacheKey(uid)
+		if cachedData, err := uc.cache.WithContext(ctx).Get(cacheKey); err == nil && cachedData != nil {
+			payloadKey = fmt.Sprintf("%s:%s:%d:%d:%s:%s", cacheKey, utils.GetConfig().GetAllDevicesResponseType, page, limit, category, sortBy)
+			version = utils.PayloadVersion(cachedData, uc.channelRevision(), uc.categoryRevision(), uc.metadataRevision())
+			if payload, ok := uc.payloads.Get(payloadKey, version); ok {
+				utils.RequestMetaFromContext(ctx).SetCache("hit")
+				return payload, nil
+			}
+		}
+	}
+
+	devices, err := uc.listDevices(ctx, accessToken, uid, page, limit, category, visible, sortBy)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.MarshalJSON(deviceListSerializationPath, devices)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode devices: %w", err)
+	}
+	if payloadKey != "" {
+		uc.payloads.Set(payloadKey, version, payload)
 	}
+	return payload, nil
 }
 
-// GetAllDevices retrieves the complete list of devices for a user, including statuses and specs.
-// It performs multiple API calls: fetching the device list, fetching specifications for each, and batch-fetching real-time status.
+// channelRevision returns the channel name revision, or 0 without a channel usecase.
+func (uc *TuyaGetAllDevicesUseCase) channelRevision() uint64 {
+	if uc.channelUC == nil {
+		return 0
+	}
+	return uc.channelUC.Revision()
+}
+
+// metadataRevision returns the device metadata revision, or 0 without a metadata usecase.
+func (uc *TuyaGetAllDevicesUseCase) metadataRevision() uint64 {
+	if uc.metadataUC == nil {
+		return 0
+	}
+	return uc.metadataUC.Revision()
+}
+
+// categoryRevision returns the category filter revision, or 0 without a category filter.
+func (uc *TuyaGetAllDevicesUseCase) categoryRevision() uint64 {
+	if uc.categoryUC == nil {
+		return 0
+	}
+	return uc.categoryUC.Revision()
+}
+
+// deviceListCacheKey builds the cache key of a user's full device list.
+func deviceListCacheKey(uid string) string {
+	return fmt.Sprintf("cache:devices:%s", uid)
+}
+
+// GetAllDevices retrieves the complete list of devices for a user, including statuses.
+// It performs multiple API calls: fetching the device list and batch-fetching real-time status.
+// With TUYA_DEVICE_LIST_PAGING and the flat response, a paged request on a cold cache fetches only its
+// page from Tuya (see getDevicePage); otherwise pagination and filtering run in memory on the full list.
+// Specifications are only fetched (concurrently, from the shared cache) when debug logging is on.
 // It also handles device categorization and grouping (e.g., grouping IR ACs under a Smart IR Hub).
 //
 // Tuya API Interactions:
-// 1. List Devices by User: GET /v1.0/users/{uid}/devices
-// 2. Get Device Specifications: GET /v1.0/iot-03/devices/{device_id}/specification
-// 3. Batch Get Device Status: GET /v1.0/iot-03/devices/status
+// 1. List Devices by User: GET /v1.0/users/{uid}/devices, or GET /v1.0/iot-03/devices for industry
+// projects (see fetchDeviceList) or a paged request
+// 2. Get Device Specifications: GET /v1.0/iot-03/devices/{device_id}/specification (debug logging only)
+// 3. Batch Get Device Status: GET /v1.0/iot-03/devices/status (skipped for Smart Home projects, whose
+// device list carries the online state)
 //
+// param ctx The request context, used for cancellation and timing metadata.
 // param accessToken The valid OAuth 2.0 access token.
 // param uid The Tuya User ID for whom to fetch devices.
 // param page Page number for pagination (optional, 0 to ignore).
 // param limit Items per page (optional, 0 to ignore).
 // param category Category to filter by (optional, empty to ignore).
+// param visible Devices the caller may see (optional, nil for all devices); applied before pagination.
 // return *dtos.TuyaDevicesResponseDTO The aggregated list of devices.
 // return error An error if fetching the device list fails.
 // @throws error If the API returns a failure (e.g., invalid token).
-func (uc *TuyaGetAllDevicesUseCase) GetAllDevices(accessToken, uid string, page, limit int, category string) (*dtos.TuyaDevicesResponseDTO, error) {
+func (uc *TuyaGetAllDevicesUseCase) GetAllDevices(ctx context.Context, accessToken, uid string, page, limit int, category string, visible DeviceFilter) (*dtos.TuyaDevicesResponseDTO, error) {
+	return uc.listDevices(ctx, accessToken, uid, page, limit, category, visible, DeviceSortName)
+}
+
+// listDevices implements GetAllDevices with a sort order (see sortDevices); only the name order may be
+// paginated upstream.
+func (uc *TuyaGetAllDevicesUseCase) listDevices(ctx context.Context, accessToken, uid string, page, limit int, category string, visible DeviceFilter, sortBy string) (*dtos.TuyaDevicesResponseDTO, error) {
 	// Get config
 	config := utils.GetConfig()
 
 	// 1. Try Cache First
-	cacheKey := fmt.Sprintf("cache:devices:%s", uid)
+	cacheKey := deviceListCacheKey(uid)
 	var deviceDTOs []dtos.TuyaDeviceDTO
 
-	cachedData, err := uc.cache.Get(cacheKey)
+	cachedData, err := uc.cache.WithContext(ctx).Get(cacheKey)
 	if err == nil && cachedData != nil {
-		if err := json.Unmarshal(cachedData, &deviceDTOs); err == nil {
+		if err := utils.UnmarshalJSON(deviceListSerializationPath, cachedData, &deviceDTOs); err == nil {
 			utils.LogDebug("GetAllDevices: Cache HIT for uid %s", uid)
+			utils.RequestMetaFromContext(ctx).SetCache("hit")
 		} else {
 			utils.LogWarn("GetAllDevices: Cache corrupted for uid %s, fetching fresh data", uid)
 			cachedData = nil // Force refresh
+			utils.RequestMetaFromContext(ctx).SetCache("miss")
 		}
 	} else {
 		utils.LogDebug("GetAllDevices: Cache MISS for uid %s (err: %v)", uid, err)
+		utils.RequestMetaFromContext(ctx).SetCache("miss")
 	}
 
-	// 2. If Cache Miss, Fetch from API
-	if cachedData == nil {
-		// Generate timestamp in milliseconds
-		timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-		signMethod := "HMAC-SHA256"
-
-		// Build URL path - using /v1.0/users/{uid}/devices endpoint
-		urlPath := fmt.Sprintf("/v1.0/users/%s/devices", uid)
-		fullURL := config.TuyaBaseURL + urlPath
-
-		// Calculate content hash (empty for GET request)
-		emptyContent := ""
-		h := sha256.New()
-		h.Write([]byte(emptyContent))
-		contentHash := hex.EncodeToString(h.Sum(nil))
-
-		// Generate string to sign
-		stringToSign := tuya_utils.GenerateTuyaStringToSign("GET", contentHash, "", urlPath)
-
-		// Generate signature
-		signature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, timestamp, stringToSign)
-
-		// Prepare headers with access token
-		headers := map[string]string{
-			"client_id":    config.TuyaClientID,
-			"sign":         signature,
-			"t":            timestamp,
-			"sign_method":  signMethod,
-			"access_token": accessToken,
+	// 2. Without a cached list, a paged request may fetch just its page
+	if cachedData == nil && sortBy == DeviceSortName && uc.canPageUpstream(limit, visible) {
+		pageResponse, err := uc.getDevicePage(ctx, accessToken, uid, page, limit, category)
+		if err == nil {
+			return pageResponse, nil
 		}
+		utils.LogWarn("GetAllDevices: Paged device list failed, fetching the full list: %v", err)
+	}
 
-		// Call service to fetch devices
-		devicesResponse, err := uc.service.FetchDevices(fullURL, headers)
+	// 3. If Cache Miss, Fetch from API
+	if cachedData == nil {
+		// Fetch the devices through the device list of the project type
+		devices, err := uc.fetchDeviceList(ctx, accessToken, uid)
 		if err != nil {
 			return nil, err
 		}
 
-		// Validate response
-		if !devicesResponse.Success {
-			return nil, fmt.Errorf("tuya API failed to fetch devices: %s (code: %d)", devicesResponse.Msg, devicesResponse.Code)
-		}
-
-		// DEBUG: Log device attributes and SPECIFICATIONS to find correct command values
-		for _, dev := range devicesResponse.Result {
-			utils.LogDebug("DEVICE DEBUG: ID=%s, Name=%s, Category=%s", dev.ID, dev.Name, dev.Category)
-			for _, st := range dev.Status {
-				utils.LogDebug("   STATUS: Code=%s, Value=%v (Type: %T)", st.Code, st.Value, st.Value)
-			}
-
-			// Fetch and Log Specifications
-			specTimestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-			specUrlPath := fmt.Sprintf("/v1.0/iot-03/devices/%s/specification", dev.ID)
-			specFullURL := config.TuyaBaseURL + specUrlPath
-
-			specEmptyContent := ""
-			hSpec := sha256.New()
-			hSpec.Write([]byte(specEmptyContent))
-			specContentHash := hex.EncodeToString(hSpec.Sum(nil))
-
-			specStringToSign := tuya_utils.GenerateTuyaStringToSign("GET", specContentHash, "", specUrlPath)
-			specSignature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, specTimestamp, specStringToSign)
-
-			specHeaders := map[string]string{
-				"client_id":    config.TuyaClientID,
-				"sign":         specSignature,
-				"t":            specTimestamp,
-				"sign_method":  signMethod,
-				"access_token": accessToken,
-			}
-
-			specResp, errSpec := uc.service.FetchDeviceSpecification(specFullURL, specHeaders)
-			if errSpec == nil && specResp.Success {
-				utils.LogDebug("   SPECIFICATION for ID=%s:", dev.ID)
-				for _, fn := range specResp.Result.Functions {
-					utils.LogDebug("      FUNCTION: Code=%s, Type=%s, Values=%s", fn.Code, fn.Type, fn.Values)
-				}
-			} else {
-				utils.LogError("   FAILED to fetch spec for ID=%s: %v", dev.ID, errSpec)
-			}
+		// DEBUG: Log device attributes and SPECIFICATIONS to find correct command values.
+		// No response mode embeds specifications, so they are only fetched when debug logging is on.
+		if utils.DebugEnabled() {
+			uc.logDeviceDetails(ctx, accessToken, devices)
 		}
 
-		// Transform entities to DTOs
-		var deviceIDs []string
-
-		// Collect IDs first
-		for _, device := range devicesResponse.Result {
-			deviceIDs = append(deviceIDs, device.ID)
+		// Transform entities to DTOs, with the real-time online state of a batch status call
+		statusMap := uc.fetchOnlineStatus(ctx, accessToken, devices)
+		for _, device := range devices {
+			deviceDTOs = append(deviceDTOs, uc.toDeviceDTO(device, statusMap))
 		}
 
-		// Fetch Real-time Status Batch
-		statusMap := make(map[string]bool)
-		if len(deviceIDs) > 0 {
-			// New timestamp/signature for status call
-			statusTimestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-			statusURLPath := "/v1.0/iot-03/devices/status"
-			statusFullURL := config.TuyaBaseURL + statusURLPath + "?device_ids=" + utils.JoinStrings(deviceIDs, ",")
-
-			statusEmptyContent := ""
-			hStatus := sha256.New()
-			hStatus.Write([]byte(statusEmptyContent))
-			statusContentHash := hex.EncodeToString(hStatus.Sum(nil))
-
-			statusStringToSign := tuya_utils.GenerateTuyaStringToSign("GET", statusContentHash, "", statusURLPath)
-			statusSignature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, statusTimestamp, statusStringToSign)
-
-			statusHeaders := map[string]string{
-				"client_id":    config.TuyaClientID,
-				"sign":         statusSignature,
-				"t":            statusTimestamp,
-				"sign_method":  signMethod,
-				"access_token": accessToken,
-			}
-
-			batchStatusResponse, err := uc.service.FetchBatchDeviceStatus(statusFullURL, statusHeaders)
-			if err == nil && batchStatusResponse.Success {
-				for _, s := range batchStatusResponse.Result {
-					statusMap[s.ID] = s.IsOnline
-				}
-			} else {
-				utils.LogWarn("WARN: Failed to fetch batch status: %v", err)
-			}
-		}
-
-		for _, device := range devicesResponse.Result {
-			// Use real-time status if available, fallback to list status
-			isOnline := device.Online
-			if val, ok := statusMap[device.ID]; ok {
-				isOnline = val
-			}
-
-			statusDTOs := make([]dtos.TuyaDeviceStatusDTO, len(device.Status))
-			for j, s := range device.Status {
-				statusDTOs[j] = dtos.TuyaDeviceStatusDTO{
-					Code:  s.Code,
-					Value: s.Value,
-				}
-			}
-
-			// For infrared_ac devices, populate status from saved state or use defaults
-			if device.Category == "infrared_ac" && uc.deviceStateUC != nil {
-				savedState, err := uc.deviceStateUC.GetDeviceState(device.ID)
-				if err == nil && savedState != nil && len(savedState.LastCommands) > 0 {
-					// Populate statusDTOs from saved state
-					utils.LogDebug("GetAllDevices: Populating infrared_ac status for device %s from saved state", device.ID)
-					statusDTOs = make([]dtos.TuyaDeviceStatusDTO, len(savedState.LastCommands))
-					for i, cmd := range savedState.LastCommands {
-						statusDTOs[i] = dtos.TuyaDeviceStatusDTO{
-							Code:  cmd.Code,
-							Value: cmd.Value,
-						}
-					}
-				} else {
-					// Use default values if no saved state
-					utils.LogDebug("GetAllDevices: Using default status for infrared_ac device %s (no saved state)", device.ID)
-					statusDTOs = []dtos.TuyaDeviceStatusDTO{
-						{Code: "power", Value: 0},
-						{Code: "temp", Value: 24},
-						{Code: "mode", Value: 0},
-						{Code: "wind", Value: 0},
-					}
-				}
-			}
-
-
-			// Determine display name (Use RemoteName if available)
-			displayName := device.Name
-			if device.RemoteName != "" {
-				displayName = device.RemoteName
+		// Record added/removed/renamed devices against the previous refresh
+		if uc.changeLogUC != nil {
+			if _, err := uc.changeLogUC.RecordRefresh(uid, deviceDTOs); err != nil {
+				utils.LogWarn("GetAllDevices: Failed to record device changes: %v", err)
 			}
-
-			deviceDTOs = append(deviceDTOs, dtos.TuyaDeviceDTO{
-				ID:          device.ID,
-				Name:        displayName,
-				ProductName: device.ProductName,
-				Category:    device.Category,
-				Icon:        device.Icon,
-				Online:      isOnline,
-				Status:      statusDTOs,
-				CustomName:  device.CustomName,
-				Model:       device.Model,
-				IP:          device.IP,
-				LocalKey:    device.LocalKey,
-				GatewayID:   device.GatewayID,
-				CreateTime:  device.CreateTime,
-				UpdateTime:  device.UpdateTime,
-			})
 		}
 
 		// Process devices based on response type configuration
@@ -275,15 +259,15 @@ func (uc *TuyaGetAllDevicesUseCase) GetAllDevices(accessToken, uid string, page,
 			deviceDTOs = uc.processResponseMode0(deviceDTOs)
 		}
 
-		// 3. Save to Cache
-		if jsonData, err := json.Marshal(deviceDTOs); err == nil {
-			uc.cache.Set(cacheKey, jsonData)
+		// 4. Save to Cache
+		if jsonData, err := utils.MarshalJSON(deviceListSerializationPath, deviceDTOs); err == nil {
+			uc.cache.WithContext(ctx).Set(cacheKey, jsonData, uc.ttls.TTL(persistence.CacheResourceDeviceList))
 			utils.LogDebug("GetAllDevices: Saved %d devices to cache for uid %s", len(deviceDTOs), uid)
 		} else {
 			utils.LogError("GetAllDevices: Failed to marshal devices for cache: %v", err)
 		}
 
-		// 4. Cleanup orphaned device states
+		// 5. Cleanup orphaned device states
 		if uc.deviceStateUC != nil {
 			var allDeviceIDs []string
 			for _, dev := range deviceDTOs {
@@ -303,6 +287,14 @@ func (uc *TuyaGetAllDevicesUseCase) GetAllDevices(accessToken, uid string, page,
 		}
 	}
 
+	// Hide devices assigned to other tenants (the cached list is shared)
+	deviceDTOs = FilterDevices(deviceDTOs, visible)
+
+	// Hide categories filtered out for the whole deployment, including nested collections
+	if uc.categoryUC != nil {
+		deviceDTOs = uc.categoryUC.FilterDevices(deviceDTOs)
+	}
+
 	// --- NEW: Filter by Category ---
 	if category != "" {
 		var filteredDevices []dtos.TuyaDeviceDTO
@@ -320,13 +312,25 @@ func (uc *TuyaGetAllDevicesUseCase) GetAllDevices(accessToken, uid string, page,
 		deviceDTOs = filteredDevices
 	}
 
+	// Expose multi-gang switch channels with their stored names
+	if uc.channelUC != nil {
+		for i := range deviceDTOs {
+			uc.channelUC.ApplyChannels(&deviceDTOs[i])
+		}
+	}
+
+	// Merge local labels, icons, sort order and favorite flags
+	if uc.metadataUC != nil {
+		for i := range deviceDTOs {
+			uc.metadataUC.ApplyMetadata(&deviceDTOs[i])
+		}
+	}
+
 	// Update Total after filtering
 	total := len(deviceDTOs)
 
-	// Sort devices by Name Ascending (Alphabetical)
-	sort.Slice(deviceDTOs, func(i, j int) bool {
-		return deviceDTOs[i].Name < deviceDTOs[j].Name
-	})
+	// Sort devices (alphabetically by Name unless another order is requested)
+	sortDevices(deviceDTOs, sortBy)
 
 	// --- NEW: Pagination ---
 	if limit > 0 {
@@ -354,6 +358,317 @@ func (uc *TuyaGetAllDevicesUseCase) GetAllDevices(accessToken, uid string, page,
 	}, nil
 }
 
+// sortDevices orders devices by name, or puts favorites, custom-ordered or online devices first.
+// Favorites and custom-ordered devices are ordered by their sort_order metadata; ties and the remaining
+// devices are ordered by name.
+func sortDevices(devices []dtos.TuyaDeviceDTO, sortBy string) {
+	rank := func(device dtos.TuyaDeviceDTO) (int, int) {
+		metadata := device.Metadata
+		switch sortBy {
+		case DeviceSortFavorite:
+			if metadata != nil && metadata.Favorite {
+				return 0, sortOrderOf(metadata)
+			}
+			return 1, 0
+		case DeviceSortCustom:
+			if metadata != nil && metadata.SortOrder != nil {
+				return 0, *metadata.SortOrder
+			}
+			return 1, 0
+		case DeviceSortOnline:
+			if device.Online {
+				return 0, 0
+			}
+			return 1, 0
+		}
+		return 0, 0
+	}
+
+	sort.SliceStable(devices, func(i, j int) bool {
+		groupI, orderI := rank(devices[i])
+		groupJ, orderJ := rank(devices[j])
+		if groupI != groupJ {
+			return groupI < groupJ
+		}
+		if orderI != orderJ {
+			return orderI < orderJ
+		}
+		return devices[i].Name < devices[j].Name
+	})
+}
+
+// sortOrderOf returns the custom position of a device, placing devices without one last.
+func sortOrderOf(metadata *dtos.DeviceMetadataDTO) int {
+	if metadata.SortOrder == nil {
+		return math.MaxInt
+	}
+	return *metadata.SortOrder
+}
+
+// canPageUpstream reports whether a request can be paginated by Tuya instead of in memory.
+// Grouped and merged responses pair IR remotes with hubs across the whole list, and tenant and category
+// filtering need every device, so they all keep the full list.
+func (uc *TuyaGetAllDevicesUseCase) canPageUpstream(limit int, visible DeviceFilter) bool {
+	config := utils.GetConfig()
+	return config.TuyaDeviceListPaging &&
+		uc.service.Endpoints().Strategy().PagedDeviceList() &&
+		config.GetAllDevicesResponseType == "1" &&
+		visible == nil &&
+		(uc.categoryUC == nil || !uc.categoryUC.Active()) &&
+		limit > 0 && limit <= maxTuyaDevicePageSize
+}
+
+// fetchDeviceList fetches all devices of a user through the device list of the project type
+// (TUYA_PROJECT_TYPE): one call to the per-user list, or a walk over every page of the IoT Core list.
+// In auto mode a per-user list denied by Tuya switches to the IoT Core list.
+//
+// Tuya API Interactions:
+// - Smart Home / auto: GET /v1.0/users/{uid}/devices
+// - Industry: GET /v1.0/iot-03/devices?source_type=tuyaUser&source_id={uid}&page_size=100&last_row_key={key}
+func (uc *TuyaGetAllDevicesUseCase) fetchDeviceList(ctx context.Context, accessToken, uid string) ([]entities.TuyaDevice, error) {
+	endpoints := uc.service.Endpoints()
+	strategy := endpoints.Strategy()
+	if strategy.UserDeviceList() {
+		devicesResponse, err := uc.service.FetchDevices(ctx, strategy.UserDevicesPath(uid), accessToken)
+		if err != nil {
+			return nil, err
+		}
+		if devicesResponse.Success {
+			return devicesResponse.Result, nil
+		}
+		if !endpoints.ReportListingDenied(devicesResponse.Code) {
+			return nil, tuya_errors.FromTuya("failed to fetch devices", devicesResponse.Code, devicesResponse.Msg)
+		}
+		strategy = endpoints.Strategy()
+	}
+
+	var devices []entities.TuyaDevice
+	cursor := ""
+	for {
+		pageResponse, err := uc.service.FetchDevicePage(ctx, strategy.DevicePagePath(uid, maxTuyaDevicePageSize, cursor, ""), accessToken)
+		if err != nil {
+			return nil, err
+		}
+		if !pageResponse.Success {
+			return nil, tuya_errors.FromTuya("failed to fetch devices", pageResponse.Code, pageResponse.Msg)
+		}
+		devices = append(devices, pageResponse.Result.List...)
+		// A repeated key would request the same page forever
+		next := pageResponse.Result.LastRowKey
+		if !pageResponse.Result.HasMore || next == "" || next == cursor {
+			return devices, nil
+		}
+		cursor = next
+	}
+}
+
+// getDevicePage fetches one page of the user's devices from the iot-03 device list, with the category
+// filter applied by Tuya. The list is cursor-based, so the last_row_key starting each page is cached:
+// page N is requested from the nearest known cursor and the pages in between are only walked once.
+// Pages are cached like the full list and follow Tuya's order rather than the name order.
+//
+// Tuya API Documentation (Query Device List):
+// URL: /v1.0/iot-03/devices?source_type=tuyaUser&source_id={uid}&page_size={n}&last_row_key={key}
+// Method: GET
+func (uc *TuyaGetAllDevicesUseCase) getDevicePage(ctx context.Context, accessToken, uid string, page, limit int, category string) (*dtos.TuyaDevicesResponseDTO, error) {
+	if page < 1 {
+		page = 1
+	}
+	pageKey := fmt.Sprintf("cache:devices:page:%s:%s:%d:%d", uid, category, limit, page)
+	if cachedData, err := uc.cache.WithContext(ctx).Get(pageKey); err == nil && cachedData != nil {
+		var cached dtos.TuyaDevicesResponseDTO
+		if err := json.Unmarshal(cachedData, &cached); err == nil {
+			utils.RequestMetaFromContext(ctx).SetCache("hit")
+			uc.applyPageMetadata(&cached)
+			return &cached, nil
+		}
+	}
+
+	// Start from the closest page whose cursor is known; page 1 starts without one
+	current, cursor := 1, ""
+	for p := page; p > 1; p-- {
+		if data, err := uc.cache.WithContext(ctx).Get(devicePageCursorKey(uid, category, limit, p)); err == nil && data != nil {
+			current, cursor = p, string(data)
+			break
+		}
+	}
+
+	strategy := uc.service.Endpoints().Strategy()
+	for {
+		pageResponse, err := uc.service.FetchDevicePage(ctx, strategy.DevicePagePath(uid, limit, cursor, category), accessToken)
+		if err != nil {
+			return nil, err
+		}
+		if !pageResponse.Success {
+			return nil, tuya_errors.FromTuya("failed to fetch device page", pageResponse.Code, pageResponse.Msg)
+		}
+
+		result := pageResponse.Result
+		if result.HasMore && result.LastRowKey != "" {
+			uc.cache.WithContext(ctx).Set(devicePageCursorKey(uid, category, limit, current+1), []byte(result.LastRowKey), uc.ttls.TTL(persistence.CacheResourceDeviceList))
+		}
+
+		if current == page || !result.HasMore || result.LastRowKey == "" {
+			deviceDTOs := []dtos.TuyaDeviceDTO{}
+			if current == page {
+				statusMap := uc.fetchOnlineStatus(ctx, accessToken, result.List)
+				for _, device := range result.List {
+					deviceDTO := uc.toDeviceDTO(device, statusMap)
+					if uc.channelUC != nil {
+						uc.channelUC.ApplyChannels(&deviceDTO)
+					}
+					deviceDTOs = append(deviceDTOs, deviceDTO)
+				}
+			}
+			response := &dtos.TuyaDevicesResponseDTO{
+				Devices:          deviceDTOs,
+				TotalDevices:     result.Total,
+				CurrentPageCount: len(deviceDTOs),
+			}
+			if jsonData, err := json.Marshal(response); err == nil {
+				uc.cache.WithContext(ctx).Set(pageKey, jsonData, uc.ttls.TTL(persistence.CacheResourceDeviceList))
+			}
+			utils.RequestMetaFromContext(ctx).SetCache("miss")
+			uc.applyPageMetadata(response)
+			return response, nil
+		}
+		current, cursor = current+1, result.LastRowKey
+	}
+}
+
+// applyPageMetadata merges device metadata into a page; pages are cached without it, since it changes independently.
+func (uc *TuyaGetAllDevicesUseCase) applyPageMetadata(response *dtos.TuyaDevicesResponseDTO) {
+	if uc.metadataUC == nil {
+		return
+	}
+	for i := range response.Devices {
+		uc.metadataUC.ApplyMetadata(&response.Devices[i])
+	}
+}
+
+// devicePageCursorKey builds the key of the last_row_key that starts a page of the device list.
+func devicePageCursorKey(uid, category string, limit, page int) string {
+	return fmt.Sprintf("cache:devices:cursor:%s:%s:%d:%d", uid, category, limit, page)
+}
+
+// fetchOnlineStatus reads the real-time online state of devices with one batch status call.
+// Devices missing from the result keep the online flag of the list, as all devices do when the batch
+// status of the project type has no online state (Smart Home projects).
+func (uc *TuyaGetAllDevicesUseCase) fetchOnlineStatus(ctx context.Context, accessToken string, devices []entities.TuyaDevice) map[string]bool {
+	statusMap := make(map[string]bool)
+	strategy := uc.service.Endpoints().Strategy()
+	if len(devices) == 0 || !strategy.StatusReportsOnline() {
+		return statusMap
+	}
+
+	deviceIDs := make([]string, len(devices))
+	for i, device := range devices {
+		deviceIDs[i] = device.ID
+	}
+	statusURLPath := strategy.BatchStatusPath(deviceIDs)
+
+	batchStatusResponse, err := uc.service.FetchBatchDeviceStatus(ctx, statusURLPath, accessToken)
+	if err == nil && batchStatusResponse.Success {
+		for _, s := range batchStatusResponse.Result {
+			statusMap[s.ID] = s.IsOnline
+		}
+	} else {
+		utils.LogWarn("WARN: Failed to fetch batch status: %v", err)
+	}
+	return statusMap
+}
+
+// toDeviceDTO converts a listed device, filling the status of IR ACs from their saved state.
+func (uc *TuyaGetAllDevicesUseCase) toDeviceDTO(device entities.TuyaDevice, statusMap map[string]bool) dtos.TuyaDeviceDTO {
+	// Use real-time status if available, fallback to list status
+	isOnline := device.Online
+	if val, ok := statusMap[device.ID]; ok {
+		isOnline = val
+	}
+
+	statusDTOs := make([]dtos.TuyaDeviceStatusDTO, len(device.Status))
+	for j, s := range device.Status {
+		statusDTOs[j] = dtos.TuyaDeviceStatusDTO{
+			Code:  s.Code,
+			Value: s.Value,
+		}
+	}
+
+	// For infrared_ac devices, populate status from saved state or use defaults
+	if device.Category == "infrared_ac" && uc.deviceStateUC != nil {
+		savedState, err := uc.deviceStateUC.GetDeviceState(device.ID)
+		if err == nil && savedState != nil && len(savedState.LastCommands) > 0 {
+			// Populate statusDTOs from saved state
+			utils.LogDebug("GetAllDevices: Populating infrared_ac status for device %s from saved state", device.ID)
+			statusDTOs = make([]dtos.TuyaDeviceStatusDTO, len(savedState.LastCommands))
+			for i, cmd := range savedState.LastCommands {
+				statusDTOs[i] = dtos.TuyaDeviceStatusDTO{
+					Code:  cmd.Code,
+					Value: cmd.Value,
+				}
+			}
+		} else {
+			// Use default values if no saved state
+			utils.LogDebug("GetAllDevices: Using default status for infrared_ac device %s (no saved state)", device.ID)
+			statusDTOs = []dtos.TuyaDeviceStatusDTO{
+				{Code: "power", Value: 0},
+				{Code: "temp", Value: 24},
+				{Code: "mode", Value: 0},
+				{Code: "wind", Value: 0},
+			}
+		}
+	}
+
+	// Determine display name (Use RemoteName if available)
+	displayName := device.Name
+	if device.RemoteName != "" {
+		displayName = device.RemoteName
+	}
+
+	return dtos.TuyaDeviceDTO{
+		ID:          device.ID,
+		Name:        displayName,
+		ProductName: device.ProductName,
+		Category:    device.Category,
+		Icon:        device.Icon,
+		Online:      isOnline,
+		Status:      statusDTOs,
+		CustomName:  device.CustomName,
+		Model:       device.Model,
+		IP:          device.IP,
+		LocalKey:    device.LocalKey,
+		GatewayID:   device.GatewayID,
+		CreateTime:  device.CreateTime,
+		UpdateTime:  device.UpdateTime,
+	}
+}
+
+// logDeviceDetails logs the status and specification functions of each device.
+// Specifications are fetched concurrently through the shared specification cache.
+func (uc *TuyaGetAllDevicesUseCase) logDeviceDetails(ctx context.Context, accessToken string, devices []entities.TuyaDevice) {
+	var specs map[string]*entities.TuyaDeviceSpecification
+	if uc.specUC != nil {
+		deviceIDs := make([]string, len(devices))
+		for i, dev := range devices {
+			deviceIDs[i] = dev.ID
+		}
+		specs = uc.specUC.GetSpecifications(ctx, accessToken, deviceIDs)
+	}
+
+	for _, dev := range devices {
+		utils.LogDebug("DEVICE DEBUG: ID=%s, Name=%s, Category=%s", dev.ID, dev.Name, dev.Category)
+		for _, st := range dev.Status {
+			utils.LogDebug("   STATUS: Code=%s, Value=%v (Type: %T)", st.Code, st.Value, st.Value)
+		}
+		if spec, ok := specs[dev.ID]; ok {
+			utils.LogDebug("   SPECIFICATION for ID=%s:", dev.ID)
+			for _, fn := range spec.Functions {
+				utils.LogDebug("      FUNCTION: Code=%s, Type=%s, Values=%s", fn.Code, fn.Type, fn.Values)
+			}
+		}
+	}
+}
+
 // processResponseMode0 handles nesting IR devices inside Smart IR Hubs
 func (uc *TuyaGetAllDevicesUseCase) processResponseMode0(deviceDTOs []dtos.TuyaDeviceDTO) []dtos.TuyaDeviceDTO {
 	var finalDevices []dtos.TuyaDeviceDTO
@@ -0,0 +1,204 @@
+package usecases
+
+import (
+	"fmt"
+	"testing"
+
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/internal/testutil"
+)
+
+// deviceListFixtures are realistic device lists covering the grouping edge cases that have regressed before.
+var deviceListFixtures = []string{
+	"orphan_ir_remotes",
+	"multiple_hubs",
+	"shared_local_keys",
+}
+
+func TestProcessResponseModesGolden(t *testing.T) {
+	uc := &TuyaGetAllDevicesUseCase{}
+	modes := []struct {
+		name    string
+		process func([]dtos.TuyaDeviceDTO) []dtos.TuyaDeviceDTO
+	}{
+		{"mode0", uc.processResponseMode0},
+		{"mode1", uc.processResponseMode1},
+		{"mode2", uc.processResponseMode2},
+	}
+
+	for _, fixture := range deviceListFixtures {
+		for _, mode := range modes {
+			t.Run(fixture+"/"+mode.name, func(t *testing.T) {
+				var devices []dtos.TuyaDeviceDTO
+				testutil.LoadFixture(t, fixture, &devices)
+
+				testutil.AssertGolden(t, fixture+"_"+mode.name, mode.process(devices))
+			})
+		}
+	}
+}
+
+func FuzzProcessResponseMode0(f *testing.F) {
+	addGroupingSeeds(f)
+	uc := &TuyaGetAllDevicesUseCase{}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		devices := devicesFromFuzzInput(data)
+		result := uc.processResponseMode0(cloneDevices(devices))
+
+		// Every input device appears exactly once, either at the top level or nested in a hub.
+		seen := make(map[string]int)
+		for _, d := range result {
+			seen[d.ID]++
+			for _, child := range d.Collections {
+				if child.Category != "infrared_ac" {
+					t.Fatalf("non-IR device %s nested in %s", child.ID, d.ID)
+				}
+				if d.Category != "wnykq" {
+					t.Fatalf("device %s nested in non-hub %s", child.ID, d.ID)
+				}
+				seen[child.ID]++
+			}
+		}
+		assertEachDeviceOnce(t, devices, seen)
+
+		// An IR device left at the top level must have no matching hub.
+		hubs := hubIndex(devices)
+		for _, d := range result {
+			if d.Category == "infrared_ac" && hubs.matches(d) {
+				t.Fatalf("IR device %s has a hub but was left as an orphan", d.ID)
+			}
+		}
+	})
+}
+
+func FuzzProcessResponseMode2(f *testing.F) {
+	addGroupingSeeds(f)
+	uc := &TuyaGetAllDevicesUseCase{}
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		devices := devicesFromFuzzInput(data)
+		result := uc.processResponseMode2(cloneDevices(devices))
+
+		// Merged entries count for their remote; standalone entries count for themselves.
+		seen := make(map[string]int)
+		mergedHubs := make(map[string]bool)
+		for _, d := range result {
+			if d.RemoteID != "" {
+				if d.Category != "wnykq" || d.RemoteCategory != "infrared_ac" {
+					t.Fatalf("merged entry %s/%s has categories %s/%s", d.ID, d.RemoteID, d.Category, d.RemoteCategory)
+				}
+				seen[d.RemoteID]++
+				mergedHubs[d.ID] = true
+				continue
+			}
+			seen[d.ID]++
+		}
+
+		// Hubs represented by merged entries must not also appear on their own.
+		for id := range mergedHubs {
+			if seen[id] > 0 {
+				t.Fatalf("hub %s appears both merged and standalone", id)
+			}
+			seen[id] = 1
+		}
+		assertEachDeviceOnce(t, devices, seen)
+
+		hubs := hubIndex(devices)
+		for _, d := range result {
+			if d.RemoteID == "" && d.Category == "infrared_ac" && hubs.matches(d) {
+				t.Fatalf("IR device %s has a hub but was not merged", d.ID)
+			}
+		}
+	})
+}
+
+// addGroupingSeeds seeds the fuzzers with inputs that exercise each matching strategy.
+func addGroupingSeeds(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 0, 0, 0, 1, 1, 0, 0})             // hub + remote matched by gateway
+	f.Add([]byte{0, 0, 0, 1, 1, 1, 3, 1})             // hub + remote matched by local key
+	f.Add([]byte{0, 0, 0, 1, 0, 1, 0, 1, 1, 2, 3, 1}) // two hubs sharing a local key
+	f.Add([]byte{1, 1, 2, 2, 2, 2, 3, 3})             // orphan remote + other device
+}
+
+// devicesFromFuzzInput builds a device list from fuzz bytes, four bytes per device:
+// kind (hub, IR remote, other), ID, gateway and local key. IDs and keys come from small pools
+// so collisions between hubs, gateways and local keys are common.
+func devicesFromFuzzInput(data []byte) []dtos.TuyaDeviceDTO {
+	categories := []string{"wnykq", "infrared_ac", "cz"}
+	ids := make(map[string]bool)
+
+	var devices []dtos.TuyaDeviceDTO
+	for i := 0; i+3 < len(data) && len(devices) < 32; i += 4 {
+		category := categories[int(data[i])%len(categories)]
+		id := fmt.Sprintf("dev-%d", data[i+1]%16)
+		if ids[id] {
+			// Tuya device IDs are unique; skip duplicates rather than testing impossible input.
+			continue
+		}
+		ids[id] = true
+
+		gateway := ""
+		if g := data[i+2] % 8; g < 6 {
+			gateway = fmt.Sprintf("dev-%d", g)
+		}
+		localKey := ""
+		if k := data[i+3] % 4; k > 0 {
+			localKey = fmt.Sprintf("lk-%d", k)
+		}
+
+		devices = append(devices, dtos.TuyaDeviceDTO{
+			ID:        id,
+			Name:      "Device " + id,
+			Category:  category,
+			LocalKey:  localKey,
+			GatewayID: gateway,
+		})
+	}
+	return devices
+}
+
+// cloneDevices copies the slice so the processors cannot mutate the input used for assertions.
+func cloneDevices(devices []dtos.TuyaDeviceDTO) []dtos.TuyaDeviceDTO {
+	return append([]dtos.TuyaDeviceDTO(nil), devices...)
+}
+
+// assertEachDeviceOnce fails unless every input device was counted exactly once.
+func assertEachDeviceOnce(t *testing.T, devices []dtos.TuyaDeviceDTO, seen map[string]int) {
+	t.Helper()
+	for _, d := range devices {
+		if seen[d.ID] != 1 {
+			t.Fatalf("device %s (%s) appears %d times, want 1", d.ID, d.Category, seen[d.ID])
+		}
+		delete(seen, d.ID)
+	}
+	for id, count := range seen {
+		t.Fatalf("unknown device %s appears %d times", id, count)
+	}
+}
+
+// hubLookup indexes hubs by ID and local key, mirroring the matching rules.
+type hubLookup struct {
+	ids       map[string]bool
+	localKeys map[string]bool
+}
+
+func hubIndex(devices []dtos.TuyaDeviceDTO) hubLookup {
+	lookup := hubLookup{ids: make(map[string]bool), localKeys: make(map[string]bool)}
+	for _, d := range devices {
+		if d.Category != "wnykq" {
+			continue
+		}
+		lookup.ids[d.ID] = true
+		if d.LocalKey != "" {
+			lookup.localKeys[d.LocalKey] = true
+		}
+	}
+	return lookup
+}
+
+// matches reports whether an IR device has a hub by gateway ID or local key.
+func (h hubLookup) matches(d dtos.TuyaDeviceDTO) bool {
+	return h.ids[d.GatewayID] || (d.LocalKey != "" && h.localKeys[d.LocalKey])
+}
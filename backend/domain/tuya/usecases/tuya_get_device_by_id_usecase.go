@@ -1,52 +1,82 @@
 package usecases
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"encoding/json"
 	"fmt"
-	"strconv"
 	"teralux_app/domain/tuya/dtos"
 	"teralux_app/domain/common/infrastructure/persistence"
+	tuya_errors "teralux_app/domain/tuya/errors"
 	"teralux_app/domain/tuya/services"
 	"teralux_app/domain/common/utils"
-	tuya_utils "teralux_app/domain/tuya/utils"
-	"time"
 )
 
+// deviceDetailSerializationPath names the device detail in the serialization stats.
+const deviceDetailSerializationPath = "device_detail"
+
 // TuyaGetDeviceByIDUseCase retrieves detailed information for a specific device.
 type TuyaGetDeviceByIDUseCase struct {
 	service       *services.TuyaDeviceService
-	cache         *persistence.BadgerService
+	cache         persistence.CacheStore
+	ttls          *persistence.CacheTTLPolicy
 	deviceStateUC *DeviceStateUseCase
+	channelUC     *DeviceChannelUseCase
+	metadataUC    *DeviceMetadataUseCase
 }
 
 // NewTuyaGetDeviceByIDUseCase initializes a new TuyaGetDeviceByIDUseCase.
 //
 // param service The TuyaDeviceService used regarding API requests.
-// param cache The BadgerService used for caching device details.
+// param cache The CacheStore used for caching device details.
+// param ttls The CacheTTLPolicy deciding how long device details (and sensors) stay cached.
 // param deviceStateUC The DeviceStateUseCase for populating infrared_ac status.
+// param channelUC The DeviceChannelUseCase for exposing multi-gang switch channels (optional).
+// param metadataUC The DeviceMetadataUseCase merging local labels, icons and favorites (optional).
 // return *TuyaGetDeviceByIDUseCase A pointer to the initialized usecase.
-func NewTuyaGetDeviceByIDUseCase(service *services.TuyaDeviceService, cache *persistence.BadgerService, deviceStateUC *DeviceStateUseCase) *TuyaGetDeviceByIDUseCase {
+func NewTuyaGetDeviceByIDUseCase(service *services.TuyaDeviceService, cache persistence.CacheStore, ttls *persistence.CacheTTLPolicy, deviceStateUC *DeviceStateUseCase, channelUC *DeviceChannelUseCase, metadataUC *DeviceMetadataUseCase) *TuyaGetDeviceByIDUseCase {
 	return &TuyaGetDeviceByIDUseCase{
 		service:       service,
 		cache:         cache,
+		ttls:          ttls,
 		deviceStateUC: deviceStateUC,
+		channelUC:     channelUC,
+		metadataUC:    metadataUC,
 	}
 }
 
+// GetDeviceByIDPayload returns the marshaled TuyaDeviceResponseDTO of GetDeviceByID, so the controller can
+// derive an ETag from the exact bytes it sends.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The unique ID of the device to fetch.
+// return json.RawMessage The marshaled TuyaDeviceResponseDTO.
+// return error An error if fetching or encoding the device fails.
+func (uc *TuyaGetDeviceByIDUseCase) GetDeviceByIDPayload(ctx context.Context, accessToken, deviceID string) (json.RawMessage, error) {
+	device, err := uc.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := utils.MarshalJSON(deviceDetailSerializationPath, dtos.TuyaDeviceResponseDTO{Device: *device})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode device: %w", err)
+	}
+	return payload, nil
+}
+
 // GetDeviceByID fetches the details of a single device from the Tuya API.
 //
 // Tuya API Documentation (Get Device):
 // URL: https://openapi.tuyacn.com/v1.0/devices/{device_id}
 // Method: GET
 //
+// param ctx The request context, used for cancellation and timing metadata.
 // param accessToken The valid OAuth 2.0 access token.
 // param deviceID The unique ID of the device to fetch.
 // return *dtos.TuyaDeviceDTO The detailed device information object.
 // return error An error if the request fails.
 // @throws error If the API returns a failure response.
-func (uc *TuyaGetDeviceByIDUseCase) GetDeviceByID(accessToken, deviceID string) (*dtos.TuyaDeviceDTO, error) {
+func (uc *TuyaGetDeviceByIDUseCase) GetDeviceByID(ctx context.Context, accessToken, deviceID string) (*dtos.TuyaDeviceDTO, error) {
 	// 1. Try Cache First
 	cacheKey := fmt.Sprintf("cache:tuya_device:%s", deviceID)
 	cachedData, err := uc.cache.Get(cacheKey)
@@ -54,56 +84,33 @@ func (uc *TuyaGetDeviceByIDUseCase) GetDeviceByID(accessToken, deviceID string)
 		var cachedDTO dtos.TuyaDeviceDTO
 		if err := json.Unmarshal(cachedData, &cachedDTO); err == nil {
 			utils.LogDebug("GetDeviceByID: Cache HIT for device %s", deviceID)
+			utils.RequestMetaFromContext(ctx).SetCache("hit")
+			if uc.channelUC != nil {
+				uc.channelUC.ApplyChannels(&cachedDTO)
+			}
+			if uc.metadataUC != nil {
+				uc.metadataUC.ApplyMetadata(&cachedDTO)
+			}
 			return &cachedDTO, nil
 		}
 		utils.LogError("GetDeviceByID: failed to unmarshal cached value: %v", err)
 	} else {
 		utils.LogDebug("GetDeviceByID: Cache MISS for device %s (err: %v)", deviceID, err)
 	}
-
-	// Get config
-	config := utils.GetConfig()
-
-	// Generate timestamp in milliseconds
-	timestamp := strconv.FormatInt(time.Now().UnixMilli(), 10)
-	signMethod := "HMAC-SHA256"
+	utils.RequestMetaFromContext(ctx).SetCache("miss")
 
 	// Build URL path - using /v1.0/devices/{device_id} endpoint
 	urlPath := fmt.Sprintf("/v1.0/devices/%s", deviceID)
-	fullURL := config.TuyaBaseURL + urlPath
-
-	// Calculate content hash (empty for GET request)
-	emptyContent := ""
-	h := sha256.New()
-	h.Write([]byte(emptyContent))
-	contentHash := hex.EncodeToString(h.Sum(nil))
-
-	// Generate string to sign
-	stringToSign := tuya_utils.GenerateTuyaStringToSign("GET", contentHash, "", urlPath)
-
-	utils.LogDebug("GetDeviceByID: generating signature for device=%s", deviceID)
-
-	// Generate signature
-	signature := tuya_utils.GenerateTuyaSignature(config.TuyaClientID, config.TuyaClientSecret, accessToken, timestamp, stringToSign)
-
-	// Prepare headers with access token
-	headers := map[string]string{
-		"client_id":    config.TuyaClientID,
-		"sign":         signature,
-		"t":            timestamp,
-		"sign_method":  signMethod,
-		"access_token": accessToken,
-	}
 
 	// Call service to fetch device
-	deviceResponse, err := uc.service.FetchDeviceByID(fullURL, headers)
+	deviceResponse, err := uc.service.FetchDeviceByID(ctx, urlPath, accessToken)
 	if err != nil {
 		return nil, err
 	}
 
 	// Validate response
 	if !deviceResponse.Success {
-		return nil, fmt.Errorf("tuya API failed to fetch device: %s (code: %d)", deviceResponse.Msg, deviceResponse.Code)
+		return nil, tuya_errors.FromTuya("failed to fetch device", deviceResponse.Code, deviceResponse.Msg)
 	}
 
 	// Transform status
@@ -161,15 +168,28 @@ func (uc *TuyaGetDeviceByIDUseCase) GetDeviceByID(accessToken, deviceID string)
 		LocalKey:     deviceResponse.Result.LocalKey,
 		CreateTime:   deviceResponse.Result.CreateTime,
 		UpdateTime:   deviceResponse.Result.UpdateTime,
+		GatewayID:    deviceResponse.Result.GatewayID,
 	}
 
-	// 2. Save to Cache
+	// 2. Save to Cache (sensor readings go stale sooner than other device details)
+	ttl := uc.ttls.TTL(persistence.CacheResourceDeviceDetail)
+	if sensorCategories[dto.Category] {
+		ttl = uc.ttls.TTL(persistence.CacheResourceSensor)
+	}
 	if jsonData, err := json.Marshal(dto); err == nil {
-		uc.cache.Set(cacheKey, jsonData)
+		uc.cache.Set(cacheKey, jsonData, ttl)
 		utils.LogDebug("GetDeviceByID: Saved device %s to cache", deviceID)
 	} else {
 		utils.LogError("GetDeviceByID: Failed to marshal device for cache: %v", err)
 	}
 
+	// Channel names and local metadata are applied after caching
+	if uc.channelUC != nil {
+		uc.channelUC.ApplyChannels(dto)
+	}
+	if uc.metadataUC != nil {
+		uc.metadataUC.ApplyMetadata(dto)
+	}
+
 	return dto, nil
 }
\ No newline at end of file
@@ -0,0 +1,206 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+	"time"
+)
+
+const (
+	// irLearningWindow is how long an IR hub stays in learning mode after it is started.
+	irLearningWindow = 30 * time.Second
+
+	// irDIYCategoryID is Tuya's category for custom (DIY) remotes, used when the client does not specify one.
+	irDIYCategoryID = 999
+)
+
+// TuyaIRLearningUseCase drives the IR code learning workflow: put a hub into learning mode,
+// poll for the captured code, and save it as a named key under a custom remote.
+type TuyaIRLearningUseCase struct {
+	service *services.TuyaDeviceService
+	clock   utils.Clock
+}
+
+// NewTuyaIRLearningUseCase initializes a new TuyaIRLearningUseCase.
+//
+// param service The TuyaDeviceService used for API communication.
+// param clock The Clock used for learning windows.
+// return *TuyaIRLearningUseCase A pointer to the initialized usecase.
+func NewTuyaIRLearningUseCase(service *services.TuyaDeviceService, clock utils.Clock) *TuyaIRLearningUseCase {
+	return &TuyaIRLearningUseCase{
+		service: service,
+		clock:   clock,
+	}
+}
+
+// StartLearning puts an IR hub into learning mode.
+// The returned learning time must be passed to GetLearnedCode so only codes captured in this session are returned.
+//
+// Tuya API Documentation (Set Learning State):
+// URL: /v2.0/infrareds/{infrared_id}/learning-state?state=true
+// Method: PUT
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param infraredID The ID of the IR hub.
+// return *dtos.IRLearningSessionDTO The learning session details.
+// return error An error if the hub cannot enter learning mode.
+func (uc *TuyaIRLearningUseCase) StartLearning(ctx context.Context, accessToken, infraredID string) (*dtos.IRLearningSessionDTO, error) {
+	learningTime := uc.clock.Now().UnixMilli()
+	if err := uc.setLearningState(ctx, accessToken, infraredID, true); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("StartLearning: IR hub %s entered learning mode", infraredID)
+	return &dtos.IRLearningSessionDTO{
+		InfraredID:   infraredID,
+		LearningTime: learningTime,
+		ExpiresAt:    time.UnixMilli(learningTime).Add(irLearningWindow).Unix(),
+	}, nil
+}
+
+// StopLearning takes an IR hub out of learning mode.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param infraredID The ID of the IR hub.
+// return error An error if the hub rejects the request.
+func (uc *TuyaIRLearningUseCase) StopLearning(ctx context.Context, accessToken, infraredID string) error {
+	if err := uc.setLearningState(ctx, accessToken, infraredID, false); err != nil {
+		return err
+	}
+	utils.LogInfo("StopLearning: IR hub %s left learning mode", infraredID)
+	return nil
+}
+
+// GetLearnedCode polls for a code captured since learning mode started.
+// Learned is false while the user has not pressed a button on the physical remote yet.
+//
+// Tuya API Documentation (Get Learned Code):
+// URL: /v2.0/infrareds/{infrared_id}/learning-codes?learning_time={learning_time}
+// Method: GET
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param infraredID The ID of the IR hub.
+// param learningTime The learning time returned by StartLearning (milliseconds).
+// return *dtos.IRLearnedCodeDTO The learned code, if any.
+// return error An error if the API call fails.
+func (uc *TuyaIRLearningUseCase) GetLearnedCode(ctx context.Context, accessToken, infraredID string, learningTime int64) (*dtos.IRLearnedCodeDTO, error) {
+	urlPath := fmt.Sprintf("/v2.0/infrareds/%s/learning-codes?learning_time=%d", infraredID, learningTime)
+
+	resp, err := uc.service.FetchIRLearnedCode(ctx, urlPath, accessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch learned code", resp.Code, resp.Msg)
+	}
+
+	if !resp.Result.Success || resp.Result.Code == "" {
+		return &dtos.IRLearnedCodeDTO{Learned: false}, nil
+	}
+	return &dtos.IRLearnedCodeDTO{Learned: true, Code: resp.Result.Code}, nil
+}
+
+// SaveLearnedKey saves a learned code as a named key.
+// The key is added to the given custom remote, or a new custom remote is created when no remote ID is given.
+//
+// Tuya API Documentation (Save Learned Codes):
+// URL: /v2.0/infrareds/{infrared_id}/learning-codes (new remote)
+// URL: /v2.0/infrareds/{infrared_id}/remotes/{remote_id}/learning-codes (existing remote)
+// Method: POST
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param infraredID The ID of the IR hub.
+// param req The key to save.
+// return *dtos.SaveIRLearnedKeyResponseDTO The saved key and its remote.
+// return error A bad request error for invalid input, or the API error.
+func (uc *TuyaIRLearningUseCase) SaveLearnedKey(ctx context.Context, accessToken, infraredID string, req dtos.SaveIRLearnedKeyRequestDTO) (*dtos.SaveIRLearnedKeyResponseDTO, error) {
+	if req.RemoteID == "" && strings.TrimSpace(req.RemoteName) == "" {
+		return nil, tuya_errors.BadRequest("remote_name is required when remote_id is empty")
+	}
+
+	categoryID := req.CategoryID
+	if categoryID == 0 {
+		categoryID = irDIYCategoryID
+	}
+
+	body := entities.TuyaIRSaveCodesRequest{
+		CategoryID: categoryID,
+		Codes: []entities.TuyaIRLearningCode{{
+			CategoryID: categoryID,
+			KeyName:    req.KeyName,
+			Key:        irKeyFromName(req.KeyName),
+			Code:       req.Code,
+		}},
+	}
+
+	var urlPath string
+	if req.RemoteID != "" {
+		urlPath = fmt.Sprintf("/v2.0/infrareds/%s/remotes/%s/learning-codes", infraredID, req.RemoteID)
+	} else {
+		urlPath = fmt.Sprintf("/v2.0/infrareds/%s/learning-codes", infraredID)
+		body.BrandName = "Teralux"
+		body.RemoteName = req.RemoteName
+	}
+
+	jsonBody, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal learning codes: %w", err)
+	}
+
+	resp, err := uc.service.SaveIRLearnedCodes(ctx, urlPath, accessToken, jsonBody)
+	if err != nil {
+		return nil, err
+	}
+	if !resp.Success {
+		if resp.Code == 1106 {
+			return nil, tuya_errors.BadRequest("%s (code: %d)", resp.Msg, resp.Code)
+		}
+		return nil, tuya_errors.FromTuya("failed to save learned key", resp.Code, resp.Msg)
+	}
+
+	remoteID := req.RemoteID
+	if remoteID == "" {
+		var created struct {
+			RemoteID string `json:"remote_id"`
+		}
+		if err := json.Unmarshal(resp.Result, &created); err == nil {
+			remoteID = created.RemoteID
+		}
+	}
+
+	utils.LogInfo("SaveLearnedKey: Saved key %q on IR hub %s (remote %s)", req.KeyName, infraredID, remoteID)
+	return &dtos.SaveIRLearnedKeyResponseDTO{
+		RemoteID: remoteID,
+		KeyName:  req.KeyName,
+	}, nil
+}
+
+// setLearningState toggles learning mode on the hub.
+func (uc *TuyaIRLearningUseCase) setLearningState(ctx context.Context, accessToken, infraredID string, state bool) error {
+	urlPath := fmt.Sprintf("/v2.0/infrareds/%s/learning-state?state=%t", infraredID, state)
+
+	resp, err := uc.service.SetIRLearningState(ctx, urlPath, accessToken)
+	if err != nil {
+		return err
+	}
+	if !resp.Success {
+		return tuya_errors.FromTuya("failed to set learning state", resp.Code, resp.Msg)
+	}
+	return nil
+}
+
+// irKeyFromName derives the key identifier Tuya stores for a learned key (e.g., "Volume Up" -> "volume_up").
+func irKeyFromName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), "_"))
+}
@@ -0,0 +1,314 @@
+package usecases
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	"teralux_app/domain/tuya/services"
+	"time"
+)
+
+// Permission check statuses.
+const (
+	PermissionStatusOK      = "ok"
+	PermissionStatusMissing = "missing"
+	PermissionStatusError   = "error"
+	PermissionStatusSkipped = "skipped"
+)
+
+// Tuya API families probed by the permission check.
+const (
+	PermissionCheckToken            = "token"
+	PermissionCheckDeviceManagement = "device_management"
+	PermissionCheckDeviceStatus     = "device_status"
+	PermissionCheckIRControl        = "ir_control"
+)
+
+const (
+	defaultPermissionCheckInterval = 6 * time.Hour
+	permissionCheckTimeout         = 30 * time.Second
+	// permissionProbePageSize is the page read when probing a paged device list.
+	permissionProbePageSize = 20
+	// irHubCategory is the Tuya category of IR blasters (universal remotes).
+	irHubCategory = "wnykq"
+)
+
+// tuyaPermissionDeniedCodes are the Tuya error codes meaning the cloud project lacks a permission or subscription.
+var tuyaPermissionDeniedCodes = map[int]string{
+	1106:     "permission deny",
+	28841002: "cloud development plan expired",
+	28841101: "API not subscribed",
+	28841105: "project not authorized to call this API",
+}
+
+// permissionHints tell the operator how to grant a missing permission in the Tuya IoT Platform.
+var permissionHints = map[string]string{
+	PermissionCheckToken:            "Check TUYA_CLIENT_ID, TUYA_ACCESS_SECRET and that TUYA_BASE_URL matches the project's data center",
+	PermissionCheckDeviceManagement: "Subscribe the cloud project to IoT Core and link the Tuya app account owning TUYA_USER_ID (Devices > Link Tuya App Account)",
+	PermissionCheckDeviceStatus:     "Subscribe the cloud project to IoT Core and authorize the Device Status API",
+	PermissionCheckIRControl:        "IR Control service not subscribed: subscribe the cloud project to the IR Control Hub Open Service",
+}
+
+// TuyaPermissionCheckUseCase exercises each Tuya API family the backend depends on and reports which
+// cloud project permissions are missing, so a missing subscription shows up at startup instead of as
+// a 1106 error on a user's first command. Checks run with the server-managed token at startup and every
+// TUYA_PERMISSION_CHECK_INTERVAL; missing permissions are logged as errors.
+type TuyaPermissionCheckUseCase struct {
+	service  *services.TuyaDeviceService
+	authUC   *TuyaAuthUseCase
+	interval time.Duration
+	clock    utils.Clock
+
+	mu     sync.Mutex
+	report *dtos.PermissionReportDTO
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewTuyaPermissionCheckUseCase initializes a new TuyaPermissionCheckUseCase.
+//
+// param service The TuyaDeviceService used for the probe calls.
+// param authUC The TuyaAuthUseCase providing the server-managed token.
+// param clock The Clock used for report timestamps.
+// return *TuyaPermissionCheckUseCase A pointer to the initialized usecase.
+func NewTuyaPermissionCheckUseCase(service *services.TuyaDeviceService, authUC *TuyaAuthUseCase, clock utils.Clock) *TuyaPermissionCheckUseCase {
+	interval, err := time.ParseDuration(utils.GetConfig().TuyaPermissionCheckInterval)
+	if err != nil || interval <= 0 {
+		interval = defaultPermissionCheckInterval
+	}
+
+	return &TuyaPermissionCheckUseCase{
+		service:  service,
+		authUC:   authUC,
+		interval: interval,
+		clock:    clock,
+	}
+}
+
+// Start runs the check once in the background and then every TUYA_PERMISSION_CHECK_INTERVAL.
+func (uc *TuyaPermissionCheckUseCase) Start() {
+	uc.startOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(uc.interval)
+			defer ticker.Stop()
+			for {
+				ctx, cancel := context.WithTimeout(context.Background(), permissionCheckTimeout)
+				uc.Check(ctx)
+				cancel()
+
+				select {
+				case <-ticker.C:
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("TuyaPermissionCheckUseCase: Checking Tuya API permissions every %s", uc.interval)
+	})
+}
+
+// Stop ends the periodic check and waits for a check in progress to finish.
+func (uc *TuyaPermissionCheckUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// GetReport returns the latest report, running the check first when there is none yet.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// return *dtos.PermissionReportDTO The latest report.
+func (uc *TuyaPermissionCheckUseCase) GetReport(ctx context.Context) *dtos.PermissionReportDTO {
+	uc.mu.Lock()
+	report := uc.report
+	uc.mu.Unlock()
+	if report != nil {
+		return report
+	}
+	return uc.Check(ctx)
+}
+
+// Check probes every Tuya API family and stores the report. Families that need a device (status, IR)
+// are probed with the first suitable device of TUYA_USER_ID and skipped when there is none.
+//
+// param ctx The context bounding the probe calls.
+// return *dtos.PermissionReportDTO The new report.
+func (uc *TuyaPermissionCheckUseCase) Check(ctx context.Context) *dtos.PermissionReportDTO {
+	report := &dtos.PermissionReportDTO{CheckedAt: uc.clock.Now().Unix(), Healthy: true}
+	add := func(check dtos.PermissionCheckDTO) {
+		if check.Status == PermissionStatusMissing || check.Status == PermissionStatusError {
+			report.Healthy = false
+			check.Hint = permissionHints[check.Name]
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	tokenCheck := dtos.PermissionCheckDTO{Name: PermissionCheckToken, Description: "Obtain a project access token", Endpoint: "GET /v1.0/token", Status: PermissionStatusOK}
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		tokenCheck.Status = PermissionStatusError
+		tokenCheck.Message = err.Error()
+	}
+	add(tokenCheck)
+
+	// The device endpoints depend on the project type (TUYA_PROJECT_TYPE)
+	endpoints := uc.service.Endpoints()
+	strategy := endpoints.Strategy()
+	deviceCheck := dtos.PermissionCheckDTO{Name: PermissionCheckDeviceManagement, Description: "List the devices of TUYA_USER_ID", Endpoint: deviceListEndpoint(strategy)}
+	statusCheck := dtos.PermissionCheckDTO{Name: PermissionCheckDeviceStatus, Description: "Read device status in bulk", Endpoint: batchStatusEndpoint(strategy)}
+	irCheck := dtos.PermissionCheckDTO{Name: PermissionCheckIRControl, Description: "List the remotes of an IR hub", Endpoint: "GET /v2.0/infrareds/{id}/remotes"}
+
+	var devices []entities.TuyaDevice
+	uid := utils.GetConfig().TuyaUserID
+	switch {
+	case token == nil:
+		skipPermissionCheck(&deviceCheck, "no access token")
+	case uid == "":
+		skipPermissionCheck(&deviceCheck, "TUYA_USER_ID is not set")
+	default:
+		var outcome permissionOutcome
+		devices, outcome, err = uc.probeDeviceList(ctx, strategy, uid, token.AccessToken)
+		// In auto mode a denied per-user list switches to the industry endpoints, which are probed instead
+		if outcome.received && !outcome.success && endpoints.ReportListingDenied(outcome.code) {
+			strategy = endpoints.Strategy()
+			deviceCheck.Endpoint = deviceListEndpoint(strategy)
+			statusCheck.Endpoint = batchStatusEndpoint(strategy)
+			devices, outcome, err = uc.probeDeviceList(ctx, strategy, uid, token.AccessToken)
+		}
+		classifyPermissionResult(&deviceCheck, err, outcome)
+	}
+	add(deviceCheck)
+
+	switch {
+	case len(devices) == 0:
+		skipPermissionCheck(&statusCheck, "no device to probe")
+	default:
+		resp, err := uc.service.FetchBatchDeviceStatus(ctx, strategy.BatchStatusPath([]string{devices[0].ID}), token.AccessToken)
+		var outcome permissionOutcome
+		if resp != nil {
+			outcome = permissionOutcome{received: true, success: resp.Success, code: resp.Code, msg: resp.Msg}
+		}
+		classifyPermissionResult(&statusCheck, err, outcome)
+	}
+	add(statusCheck)
+
+	hubID := findIRHub(devices)
+	switch {
+	case hubID == "":
+		skipPermissionCheck(&irCheck, "no IR hub to probe")
+	default:
+		urlPath := fmt.Sprintf("/v2.0/infrareds/%s/remotes", hubID)
+		resp, err := uc.service.FetchIRRemotes(ctx, urlPath, token.AccessToken)
+		var outcome permissionOutcome
+		if resp != nil {
+			outcome = permissionOutcome{received: true, success: resp.Success, code: resp.Code, msg: resp.Msg}
+		}
+		classifyPermissionResult(&irCheck, err, outcome)
+	}
+	add(irCheck)
+
+	for _, check := range report.Checks {
+		switch check.Status {
+		case PermissionStatusMissing:
+			utils.LogError("TuyaPermissionCheckUseCase: %s is not permitted (%s). %s", check.Name, check.Message, check.Hint)
+		case PermissionStatusError:
+			utils.LogWarn("TuyaPermissionCheckUseCase: %s check failed: %s", check.Name, check.Message)
+		}
+	}
+	if report.Healthy {
+		utils.LogDebug("TuyaPermissionCheckUseCase: All Tuya API permissions are in place")
+	}
+
+	uc.mu.Lock()
+	uc.report = report
+	uc.mu.Unlock()
+	return report
+}
+
+// probeDeviceList lists the devices of a user through the device list of the project type; for a paged
+// list only the first page is read.
+func (uc *TuyaPermissionCheckUseCase) probeDeviceList(ctx context.Context, strategy services.TuyaEndpointStrategy, uid, accessToken string) ([]entities.TuyaDevice, permissionOutcome, error) {
+	if strategy.UserDeviceList() {
+		resp, err := uc.service.FetchDevices(ctx, strategy.UserDevicesPath(uid), accessToken)
+		if resp == nil {
+			return nil, permissionOutcome{}, err
+		}
+		return resp.Result, permissionOutcome{received: true, success: resp.Success, code: resp.Code, msg: resp.Msg}, err
+	}
+	resp, err := uc.service.FetchDevicePage(ctx, strategy.DevicePagePath(uid, permissionProbePageSize, "", ""), accessToken)
+	if resp == nil {
+		return nil, permissionOutcome{}, err
+	}
+	return resp.Result.List, permissionOutcome{received: true, success: resp.Success, code: resp.Code, msg: resp.Msg}, err
+}
+
+// deviceListEndpoint describes the device list endpoint of a project type.
+func deviceListEndpoint(strategy services.TuyaEndpointStrategy) string {
+	if strategy.UserDeviceList() {
+		return services.EndpointLabel("GET", strategy.UserDevicesPath("{uid}"))
+	}
+	return services.EndpointLabel("GET", strategy.DevicePagePath("{uid}", permissionProbePageSize, "", ""))
+}
+
+// batchStatusEndpoint describes the batch status endpoint of a project type.
+func batchStatusEndpoint(strategy services.TuyaEndpointStrategy) string {
+	return services.EndpointLabel("GET", strategy.BatchStatusPath([]string{"{ids}"}))
+}
+
+// permissionOutcome is the part of a Tuya response the permission check looks at.
+type permissionOutcome struct {
+	received bool
+	success  bool
+	code     int
+	msg      string
+}
+
+// classifyPermissionResult sets the status of a check from the probe outcome.
+// Tuya permission and subscription codes mean a missing permission; anything else is a plain error.
+func classifyPermissionResult(check *dtos.PermissionCheckDTO, err error, outcome permissionOutcome) {
+	switch {
+	case err != nil:
+		check.Status = PermissionStatusError
+		check.Message = err.Error()
+	case !outcome.received:
+		check.Status = PermissionStatusError
+		check.Message = "empty response"
+	case outcome.success:
+		check.Status = PermissionStatusOK
+	default:
+		check.TuyaCode = outcome.code
+		check.Message = outcome.msg
+		if reason, ok := tuyaPermissionDeniedCodes[outcome.code]; ok {
+			check.Status = PermissionStatusMissing
+			if check.Message == "" {
+				check.Message = reason
+			}
+		} else {
+			check.Status = PermissionStatusError
+		}
+	}
+}
+
+// skipPermissionCheck marks a check that could not run.
+func skipPermissionCheck(check *dtos.PermissionCheckDTO, reason string) {
+	check.Status = PermissionStatusSkipped
+	check.Message = reason
+}
+
+// findIRHub returns the ID of an IR hub among the devices: a universal remote, or the gateway of an IR remote.
+func findIRHub(devices []entities.TuyaDevice) string {
+	for _, device := range devices {
+		if device.Category == irHubCategory {
+			return device.ID
+		}
+	}
+	for _, device := range devices {
+		if strings.HasPrefix(device.Category, "infrared_") && device.GatewayID != "" {
+			return device.GatewayID
+		}
+	}
+	return ""
+}
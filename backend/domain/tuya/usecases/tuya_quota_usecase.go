@@ -0,0 +1,411 @@
+package usecases
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+// Quota statuses, from least to most severe.
+const (
+	QuotaStatusOK       = "ok"
+	QuotaStatusWarning  = "warning"
+	QuotaStatusExceeded = "exceeded"
+)
+
+const (
+	quotaKeyPrefix     = "quota:"
+	quotaDateLayout    = "2006-01-02"
+	quotaRetention     = 31 * 24 * time.Hour
+	quotaFlushInterval = 30 * time.Second
+	// quotaMinProjectionWindow is how much of the day must pass before usage is extrapolated to the whole day.
+	quotaMinProjectionWindow = time.Hour
+	// quotaTotalEndpoint names the daily total in alerts.
+	quotaTotalEndpoint = "total"
+)
+
+// quotaStatusRank orders statuses so each endpoint is alerted once per status and day.
+var quotaStatusRank = map[string]int{
+	QuotaStatusOK:       0,
+	QuotaStatusWarning:  1,
+	QuotaStatusExceeded: 2,
+}
+
+// quotaAlert is a status change to be logged and published outside the lock.
+type quotaAlert struct {
+	endpoint  string
+	calls     int64
+	budget    int64
+	projected int64
+	status    string
+}
+
+// TuyaQuotaUseCase counts Tuya API calls per endpoint and UTC day against the configured budgets.
+// Counts are kept in memory and flushed to BadgerDB periodically and on shutdown, so they survive restarts.
+// When a day's usage is projected to exceed a budget a warning is logged and published to realtime
+// subscribers, and again once the budget is actually exceeded.
+type TuyaQuotaUseCase struct {
+	cache           persistence.CacheStore
+	realtimeHub     *realtime_services.RealtimeHubService
+	dailyBudget     int64
+	endpointBudgets map[string]int64
+	clock           utils.Clock
+
+	mu      sync.Mutex
+	day     entities.TuyaQuotaDay
+	dirty   bool
+	alerted map[string]string
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewTuyaQuotaUseCase initializes a new TuyaQuotaUseCase from TUYA_QUOTA_DAILY_BUDGET and TUYA_QUOTA_ENDPOINT_BUDGETS.
+//
+// param cache The CacheStore used to persist daily counts.
+// param realtimeHub The hub used to publish quota warnings (optional).
+// param clock The Clock deciding the current day and the projection.
+// return *TuyaQuotaUseCase A pointer to the initialized usecase.
+func NewTuyaQuotaUseCase(cache persistence.CacheStore, realtimeHub *realtime_services.RealtimeHubService, clock utils.Clock) *TuyaQuotaUseCase {
+	config := utils.GetConfig()
+
+	dailyBudget, err := strconv.ParseInt(strings.TrimSpace(config.TuyaQuotaDailyBudget), 10, 64)
+	if err != nil || dailyBudget < 0 {
+		dailyBudget = 0
+	}
+
+	uc := &TuyaQuotaUseCase{
+		cache:           cache,
+		realtimeHub:     realtimeHub,
+		dailyBudget:     dailyBudget,
+		endpointBudgets: parseQuotaEndpointBudgets(config.TuyaQuotaEndpointBudgets),
+		clock:           clock,
+		alerted:         make(map[string]string),
+	}
+	uc.day = uc.loadDay(uc.today())
+	return uc
+}
+
+// Start flushes the counts to BadgerDB in the background.
+func (uc *TuyaQuotaUseCase) Start() {
+	if uc.cache == nil {
+		return
+	}
+
+	uc.startOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(quotaFlushInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					uc.flush()
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("TuyaQuotaUseCase: Tracking Tuya API calls (daily budget %d, %d endpoint budgets)", uc.dailyBudget, len(uc.endpointBudgets))
+	})
+}
+
+// Stop ends the background flush and persists the latest counts.
+func (uc *TuyaQuotaUseCase) Stop() {
+	uc.workers.Stop()
+	uc.flush()
+}
+
+// RecordCall counts one call to a Tuya endpoint. IDs in the path are collapsed, so calls to different
+// devices count against the same endpoint.
+//
+// param method The HTTP method of the call.
+// param path The URL path of the call, without the query string.
+func (uc *TuyaQuotaUseCase) RecordCall(method, path string) {
+	endpoint := normalizeQuotaEndpoint(method, path)
+	now := uc.clock.Now().UTC()
+
+	uc.mu.Lock()
+	if date := now.Format(quotaDateLayout); date != uc.day.Date {
+		uc.persistLocked()
+		uc.day = entities.TuyaQuotaDay{Date: date, Calls: make(map[string]int64)}
+		uc.alerted = make(map[string]string)
+	}
+	uc.day.Calls[endpoint]++
+	uc.dirty = true
+
+	var alerts []quotaAlert
+	if budget, ok := uc.endpointBudgets[endpoint]; ok {
+		if alert, ok := uc.checkLocked(endpoint, uc.day.Calls[endpoint], budget, now); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	if uc.dailyBudget > 0 {
+		if alert, ok := uc.checkLocked(quotaTotalEndpoint, sumQuotaCalls(uc.day.Calls), uc.dailyBudget, now); ok {
+			alerts = append(alerts, alert)
+		}
+	}
+	uc.mu.Unlock()
+
+	for _, alert := range alerts {
+		uc.emit(alert, now)
+	}
+}
+
+// GetUsage reports the calls of a day against the budgets.
+//
+// param date The UTC day as YYYY-MM-DD; empty means today.
+// return *dtos.QuotaUsageDTO The usage per endpoint, sorted by calls.
+// return error An error if the date is invalid.
+func (uc *TuyaQuotaUseCase) GetUsage(date string) (*dtos.QuotaUsageDTO, error) {
+	now := uc.clock.Now().UTC()
+	today := now.Format(quotaDateLayout)
+	if date == "" {
+		date = today
+	}
+	if _, err := time.Parse(quotaDateLayout, date); err != nil {
+		return nil, tuya_errors.BadRequest("date must be formatted as YYYY-MM-DD")
+	}
+
+	var calls map[string]int64
+	if date == today {
+		uc.mu.Lock()
+		calls = make(map[string]int64, len(uc.day.Calls))
+		if uc.day.Date == today {
+			for endpoint, count := range uc.day.Calls {
+				calls[endpoint] = count
+			}
+		}
+		uc.mu.Unlock()
+	} else {
+		calls = uc.loadDay(date).Calls
+	}
+
+	project := func(count int64) int64 {
+		if date != today {
+			return count
+		}
+		return projectQuotaCalls(count, now)
+	}
+
+	// Endpoints with a budget are listed even before their first call
+	for endpoint := range uc.endpointBudgets {
+		if _, ok := calls[endpoint]; !ok {
+			calls[endpoint] = 0
+		}
+	}
+
+	endpoints := make([]dtos.QuotaEndpointDTO, 0, len(calls))
+	for endpoint, count := range calls {
+		budget := uc.endpointBudgets[endpoint]
+		projected := project(count)
+		endpoints = append(endpoints, dtos.QuotaEndpointDTO{
+			Endpoint:  endpoint,
+			Calls:     count,
+			Budget:    budget,
+			Projected: projected,
+			Status:    quotaStatus(count, projected, budget),
+		})
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		if endpoints[i].Calls != endpoints[j].Calls {
+			return endpoints[i].Calls > endpoints[j].Calls
+		}
+		return endpoints[i].Endpoint < endpoints[j].Endpoint
+	})
+
+	total := sumQuotaCalls(calls)
+	projectedTotal := project(total)
+	status := quotaStatus(total, projectedTotal, uc.dailyBudget)
+	for _, endpoint := range endpoints {
+		if quotaStatusRank[endpoint.Status] > quotaStatusRank[status] {
+			status = endpoint.Status
+		}
+	}
+
+	return &dtos.QuotaUsageDTO{
+		Date:           date,
+		TotalCalls:     total,
+		DailyBudget:    uc.dailyBudget,
+		ProjectedTotal: projectedTotal,
+		Status:         status,
+		Endpoints:      endpoints,
+	}, nil
+}
+
+// checkLocked returns an alert when an endpoint reached a more severe status than already alerted today.
+// The caller must hold mu.
+func (uc *TuyaQuotaUseCase) checkLocked(endpoint string, calls, budget int64, now time.Time) (quotaAlert, bool) {
+	projected := projectQuotaCalls(calls, now)
+	status := quotaStatus(calls, projected, budget)
+	if quotaStatusRank[status] <= quotaStatusRank[uc.alerted[endpoint]] {
+		return quotaAlert{}, false
+	}
+	uc.alerted[endpoint] = status
+	return quotaAlert{endpoint: endpoint, calls: calls, budget: budget, projected: projected, status: status}, true
+}
+
+// emit logs a quota alert and publishes it to realtime subscribers.
+func (uc *TuyaQuotaUseCase) emit(alert quotaAlert, now time.Time) {
+	if alert.status == QuotaStatusExceeded {
+		utils.LogError("TuyaQuotaUseCase: %s exceeded its daily budget (%d of %d calls)", alert.endpoint, alert.calls, alert.budget)
+	} else {
+		utils.LogWarn("TuyaQuotaUseCase: %s is projected to exceed its daily budget (%d calls so far, %d projected, budget %d)", alert.endpoint, alert.calls, alert.projected, alert.budget)
+	}
+
+	if uc.realtimeHub == nil {
+		return
+	}
+	uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+		Type: "quota_" + alert.status,
+		Status: []realtime_dtos.DeviceEventStatusDTO{
+			{Code: "endpoint", Value: alert.endpoint},
+			{Code: "calls", Value: alert.calls},
+			{Code: "projected", Value: alert.projected},
+			{Code: "budget", Value: alert.budget},
+		},
+		Timestamp: now.Unix(),
+	})
+}
+
+// flush persists the counts of the current day if they changed.
+func (uc *TuyaQuotaUseCase) flush() {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+	uc.persistLocked()
+}
+
+// persistLocked writes the current day to BadgerDB. The caller must hold mu.
+func (uc *TuyaQuotaUseCase) persistLocked() {
+	if uc.cache == nil || !uc.dirty {
+		return
+	}
+	jsonData, err := json.Marshal(uc.day)
+	if err != nil {
+		utils.LogWarn("TuyaQuotaUseCase: Failed to encode counts: %v", err)
+		return
+	}
+	if err := uc.cache.Set(quotaKeyPrefix+uc.day.Date, jsonData, quotaRetention); err != nil {
+		utils.LogWarn("TuyaQuotaUseCase: Failed to persist counts: %v", err)
+		return
+	}
+	uc.dirty = false
+}
+
+// loadDay reads the persisted counts of a day, returning an empty day when there are none.
+func (uc *TuyaQuotaUseCase) loadDay(date string) entities.TuyaQuotaDay {
+	day := entities.TuyaQuotaDay{Date: date, Calls: make(map[string]int64)}
+	if uc.cache == nil {
+		return day
+	}
+	jsonData, err := uc.cache.Get(quotaKeyPrefix + date)
+	if err != nil || jsonData == nil {
+		return day
+	}
+	var stored entities.TuyaQuotaDay
+	if err := json.Unmarshal(jsonData, &stored); err != nil {
+		utils.LogWarn("TuyaQuotaUseCase: Ignoring unreadable counts for %s: %v", date, err)
+		return day
+	}
+	for endpoint, count := range stored.Calls {
+		day.Calls[endpoint] = count
+	}
+	return day
+}
+
+// today returns the current UTC day.
+func (uc *TuyaQuotaUseCase) today() string {
+	return uc.clock.Now().UTC().Format(quotaDateLayout)
+}
+
+// projectQuotaCalls extrapolates the calls made so far today to the whole day.
+// Early in the day the sample is too small, so the count itself is returned.
+func projectQuotaCalls(calls int64, now time.Time) int64 {
+	now = now.UTC()
+	elapsed := now.Sub(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC))
+	if elapsed < quotaMinProjectionWindow {
+		return calls
+	}
+	return int64(float64(calls) * float64(24*time.Hour) / float64(elapsed))
+}
+
+// quotaStatus classifies usage against a budget; a zero budget is never exceeded.
+func quotaStatus(calls, projected, budget int64) string {
+	switch {
+	case budget <= 0:
+		return QuotaStatusOK
+	case calls >= budget:
+		return QuotaStatusExceeded
+	case projected >= budget:
+		return QuotaStatusWarning
+	default:
+		return QuotaStatusOK
+	}
+}
+
+// sumQuotaCalls returns the calls made to all endpoints.
+func sumQuotaCalls(calls map[string]int64) int64 {
+	var total int64
+	for _, count := range calls {
+		total += count
+	}
+	return total
+}
+
+// normalizeQuotaEndpoint builds the "METHOD /path" key of a call, replacing IDs in the path with {id}.
+// Segments holding a digit are IDs, except API versions (v1.0) and product prefixes (iot-03).
+func normalizeQuotaEndpoint(method, path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		if segment == "" || strings.Contains(segment, "-") || isQuotaVersionSegment(segment) {
+			continue
+		}
+		if strings.ContainsAny(segment, "0123456789") {
+			segments[i] = "{id}"
+		}
+	}
+	return strings.ToUpper(method) + " " + strings.Join(segments, "/")
+}
+
+// isQuotaVersionSegment reports whether a path segment is an API version such as v1.0 or v2.0.
+func isQuotaVersionSegment(segment string) bool {
+	if len(segment) < 2 || segment[0] != 'v' {
+		return false
+	}
+	_, err := strconv.ParseFloat(segment[1:], 64)
+	return err == nil
+}
+
+// parseQuotaEndpointBudgets parses TUYA_QUOTA_ENDPOINT_BUDGETS, e.g. "GET /v1.0/devices/{id}=500,POST /v1.0/devices/{id}/commands=2000".
+// Paths use the same {id} placeholders as GET /api/admin/quota.
+func parseQuotaEndpointBudgets(value string) map[string]int64 {
+	budgets := make(map[string]int64)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		idx := strings.LastIndex(pair, "=")
+		if idx < 0 {
+			utils.LogWarn("TuyaQuotaUseCase: Ignoring TUYA_QUOTA_ENDPOINT_BUDGETS entry %q without a budget", pair)
+			continue
+		}
+		method, path, found := strings.Cut(strings.TrimSpace(pair[:idx]), " ")
+		budget, err := strconv.ParseInt(strings.TrimSpace(pair[idx+1:]), 10, 64)
+		if !found || err != nil || budget <= 0 {
+			utils.LogWarn("TuyaQuotaUseCase: Ignoring invalid TUYA_QUOTA_ENDPOINT_BUDGETS entry %q", pair)
+			continue
+		}
+		budgets[strings.ToUpper(method)+" "+strings.TrimSpace(path)] = budget
+	}
+	return budgets
+}
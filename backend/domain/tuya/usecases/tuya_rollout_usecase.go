@@ -0,0 +1,302 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	job_services "teralux_app/domain/jobs/services"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+)
+
+const (
+	// RolloutJobType is the job type of staged rollouts.
+	RolloutJobType = "staged_rollout"
+
+	defaultRolloutWaveSize     = 10
+	defaultRolloutMaxErrorRate = 0.2
+	// rolloutMaxAttempts lets a rollout interrupted by a token failure or a restart resume from its checkpoint.
+	rolloutMaxAttempts = 3
+	// rolloutConcurrency bounds the commands in flight within a wave.
+	rolloutConcurrency = 5
+
+	// rolloutCheckpointPrefix stores the per-device results of a running rollout: "rollout:{job_id}".
+	rolloutCheckpointPrefix = "rollout:"
+	rolloutCheckpointTTL    = 7 * 24 * time.Hour
+)
+
+// rolloutPayload is the persisted input of a rollout, with defaults applied.
+type rolloutPayload struct {
+	DeviceIDs        []string              `json:"device_ids"`
+	Commands         []dtos.TuyaCommandDTO `json:"commands"`
+	WaveSize         int                   `json:"wave_size"`
+	CanarySize       int                   `json:"canary_size"`
+	WaveDelaySeconds int                   `json:"wave_delay_seconds"`
+	MaxErrorRate     float64               `json:"max_error_rate"`
+	// Caller is the API key or user that started the rollout; its device grants are checked again before each device.
+	Caller utils.APIKeyIdentity `json:"caller"`
+}
+
+// TuyaRolloutUseCase sends the same commands to a fleet of devices in waves (canary control).
+// Rollouts run as background jobs: progress is reported through the job API after every wave, and the
+// rollout aborts when the share of failed devices so far exceeds the configured error rate, leaving the
+// remaining devices untouched. Completed devices are checkpointed, so a resumed rollout never repeats them.
+// Rollouts are sent with the server-managed token, so they require an API key (or identity) of control scope
+// that may access every device; devices the key lost access to meanwhile fail without being sent the commands.
+type TuyaRolloutUseCase struct {
+	jobRunner *job_services.JobRunnerService
+	controlUC *TuyaDeviceControlUseCase
+	authUC    *TuyaAuthUseCase
+	authz     CallerAuthorizer
+	cache     persistence.CacheStore
+}
+
+// NewTuyaRolloutUseCase initializes a new TuyaRolloutUseCase and registers its job type.
+//
+// param jobRunner The JobRunnerService executing rollouts.
+// param controlUC The usecase sending the commands.
+// param authUC The TuyaAuthUseCase providing the server-managed token used by the job.
+// param authz The CallerAuthorizer checking the API key of the caller.
+// param cache The CacheStore storing rollout checkpoints.
+// return *TuyaRolloutUseCase A pointer to the initialized usecase.
+func NewTuyaRolloutUseCase(jobRunner *job_services.JobRunnerService, controlUC *TuyaDeviceControlUseCase, authUC *TuyaAuthUseCase, authz CallerAuthorizer, cache persistence.CacheStore) *TuyaRolloutUseCase {
+	uc := &TuyaRolloutUseCase{
+		jobRunner: jobRunner,
+		controlUC: controlUC,
+		authUC:    authUC,
+		authz:     authz,
+		cache:     cache,
+	}
+	jobRunner.Register(RolloutJobType, uc.runRollout)
+	return uc
+}
+
+// StartRollout validates a rollout and queues it as a job.
+//
+// param ctx The request context carrying the caller.
+// param req The devices, commands and wave settings.
+// return *dtos.RolloutStartedDTO The job executing the rollout and the effective settings.
+// return error A bad request error for invalid input, a forbidden error if the caller may not control every
+// device, or an error if the job cannot be queued.
+func (uc *TuyaRolloutUseCase) StartRollout(ctx context.Context, req dtos.StartRolloutRequestDTO) (*dtos.RolloutStartedDTO, error) {
+	payload := rolloutPayload{
+		Commands:         req.Commands,
+		WaveSize:         req.WaveSize,
+		CanarySize:       req.CanarySize,
+		WaveDelaySeconds: req.WaveDelaySeconds,
+		MaxErrorRate:     defaultRolloutMaxErrorRate,
+	}
+	seen := make(map[string]bool, len(req.DeviceIDs))
+	for _, deviceID := range req.DeviceIDs {
+		deviceID = strings.TrimSpace(deviceID)
+		if deviceID == "" || seen[deviceID] {
+			continue
+		}
+		seen[deviceID] = true
+		payload.DeviceIDs = append(payload.DeviceIDs, deviceID)
+	}
+	if len(payload.DeviceIDs) == 0 {
+		return nil, tuya_errors.BadRequest("device_ids must contain at least one device")
+	}
+	caller, err := authorizeRequest(ctx, uc.authz, utils.APIKeyScopeControl, payload.DeviceIDs...)
+	if err != nil {
+		return nil, err
+	}
+	payload.Caller = caller
+	if payload.WaveSize == 0 {
+		payload.WaveSize = defaultRolloutWaveSize
+	}
+	if payload.CanarySize == 0 {
+		payload.CanarySize = payload.WaveSize
+	}
+	if req.MaxErrorRate != nil {
+		payload.MaxErrorRate = *req.MaxErrorRate
+	}
+
+	job, err := uc.jobRunner.Enqueue(ctx, RolloutJobType, payload, rolloutMaxAttempts)
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.RolloutStartedDTO{
+		JobID:        job.ID,
+		Status:       job.Status,
+		Devices:      len(payload.DeviceIDs),
+		Waves:        len(rolloutWaves(payload.DeviceIDs, payload.CanarySize, payload.WaveSize)),
+		WaveSize:     payload.WaveSize,
+		CanarySize:   payload.CanarySize,
+		MaxErrorRate: payload.MaxErrorRate,
+	}, nil
+}
+
+// runRollout is the job handler executing a rollout wave by wave.
+func (uc *TuyaRolloutUseCase) runRollout(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	var payload rolloutPayload
+	if err := job.DecodePayload(&payload); err != nil {
+		return nil, job_services.Permanent(fmt.Errorf("invalid rollout payload: %w", err))
+	}
+	if err := authorizeCaller(uc.authz, payload.Caller, utils.APIKeyScopeControl); err != nil {
+		return nil, job_services.Permanent(err)
+	}
+
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	waves := rolloutWaves(payload.DeviceIDs, payload.CanarySize, payload.WaveSize)
+	result := &dtos.RolloutResultDTO{
+		Total:   len(payload.DeviceIDs),
+		Waves:   len(waves),
+		Devices: make([]dtos.RolloutDeviceResultDTO, 0, len(payload.DeviceIDs)),
+	}
+	done := make(map[string]dtos.RolloutDeviceResultDTO)
+	for _, device := range uc.loadCheckpoint(job.ID()) {
+		done[device.DeviceID] = device
+	}
+
+	for i, wave := range waves {
+		number := i + 1
+		var pending []string
+		for _, deviceID := range wave {
+			if device, ok := done[deviceID]; ok {
+				addRolloutDevice(result, device)
+			} else {
+				pending = append(pending, deviceID)
+			}
+		}
+
+		if len(pending) > 0 {
+			for _, device := range uc.runWave(ctx, token.AccessToken, payload.Caller, pending, number, payload.Commands) {
+				addRolloutDevice(result, device)
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			uc.saveCheckpoint(job.ID(), result.Devices)
+		}
+		result.WavesCompleted = number
+
+		processed := result.Succeeded + result.Failed
+		result.ErrorRate = float64(result.Failed) / float64(processed)
+		job.ReportProgress(processed*100/result.Total, fmt.Sprintf("Wave %d/%d done: %d of %d devices, %d failed",
+			number, len(waves), processed, result.Total, result.Failed))
+
+		if result.ErrorRate > payload.MaxErrorRate {
+			result.Aborted = true
+			result.Skipped = result.Total - processed
+			result.AbortReason = fmt.Sprintf("error rate %.0f%% after wave %d exceeds %.0f%%",
+				result.ErrorRate*100, number, payload.MaxErrorRate*100)
+			utils.LogWarn("TuyaRolloutUseCase: Rollout %s aborted: %s", job.ID(), result.AbortReason)
+			return result, job_services.Permanent(fmt.Errorf("rollout aborted: %s", result.AbortReason))
+		}
+
+		if number < len(waves) && payload.WaveDelaySeconds > 0 && len(pending) > 0 {
+			select {
+			case <-time.After(time.Duration(payload.WaveDelaySeconds) * time.Second):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		}
+	}
+
+	utils.LogInfo("TuyaRolloutUseCase: Rollout %s finished: %d succeeded, %d failed", job.ID(), result.Succeeded, result.Failed)
+	return result, nil
+}
+
+// runWave sends the commands to the devices of one wave, a few at a time, and returns their results in wave order.
+// Devices the caller may no longer access fail without being sent the commands.
+func (uc *TuyaRolloutUseCase) runWave(ctx context.Context, accessToken string, caller utils.APIKeyIdentity, deviceIDs []string, wave int, commands []dtos.TuyaCommandDTO) []dtos.RolloutDeviceResultDTO {
+	results := make([]dtos.RolloutDeviceResultDTO, len(deviceIDs))
+	slots := make(chan struct{}, rolloutConcurrency)
+	var wg sync.WaitGroup
+	for i, deviceID := range deviceIDs {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, deviceID string) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			device := dtos.RolloutDeviceResultDTO{DeviceID: deviceID, Wave: wave}
+			if err := authorizeCaller(uc.authz, caller, utils.APIKeyScopeControl, deviceID); err != nil {
+				device.Error = err.Error()
+				results[i] = device
+				return
+			}
+			success, err := uc.controlUC.SendCommand(ctx, accessToken, deviceID, commands)
+			switch {
+			case err != nil:
+				device.Error = err.Error()
+			case !success:
+				device.Error = "command was not accepted"
+			default:
+				device.Success = true
+			}
+			results[i] = device
+		}(i, deviceID)
+	}
+	wg.Wait()
+	return results
+}
+
+// loadCheckpoint returns the device results recorded by earlier attempts of a rollout.
+func (uc *TuyaRolloutUseCase) loadCheckpoint(jobID string) []dtos.RolloutDeviceResultDTO {
+	if uc.cache == nil {
+		return nil
+	}
+	jsonData, err := uc.cache.Get(rolloutCheckpointPrefix + jobID)
+	if err != nil || jsonData == nil {
+		return nil
+	}
+	var devices []dtos.RolloutDeviceResultDTO
+	if err := json.Unmarshal(jsonData, &devices); err != nil {
+		utils.LogWarn("TuyaRolloutUseCase: Ignoring unreadable checkpoint of rollout %s: %v", jobID, err)
+		return nil
+	}
+	return devices
+}
+
+// saveCheckpoint records the device results of a rollout so far.
+func (uc *TuyaRolloutUseCase) saveCheckpoint(jobID string, devices []dtos.RolloutDeviceResultDTO) {
+	if uc.cache == nil {
+		return
+	}
+	jsonData, err := json.Marshal(devices)
+	if err != nil {
+		return
+	}
+	if err := uc.cache.Set(rolloutCheckpointPrefix+jobID, jsonData, rolloutCheckpointTTL); err != nil {
+		utils.LogWarn("TuyaRolloutUseCase: Failed to save checkpoint of rollout %s: %v", jobID, err)
+	}
+}
+
+// addRolloutDevice appends a device result and updates the counters.
+func addRolloutDevice(result *dtos.RolloutResultDTO, device dtos.RolloutDeviceResultDTO) {
+	result.Devices = append(result.Devices, device)
+	if device.Success {
+		result.Succeeded++
+	} else {
+		result.Failed++
+	}
+}
+
+// rolloutWaves splits devices into a canary wave followed by waves of waveSize.
+func rolloutWaves(deviceIDs []string, canarySize, waveSize int) [][]string {
+	var waves [][]string
+	size := canarySize
+	for start := 0; start < len(deviceIDs); start += size {
+		if start > 0 {
+			size = waveSize
+		}
+		end := start + size
+		if end > len(deviceIDs) {
+			end = len(deviceIDs)
+		}
+		waves = append(waves, deviceIDs[start:end])
+	}
+	return waves
+}
@@ -0,0 +1,480 @@
+package usecases
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"strconv"
+	"strings"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
+	"unicode"
+)
+
+const (
+	sensorChartWidth        = 800
+	sensorChartHeight       = 360
+	sensorChartMarginLeft   = 64
+	sensorChartMarginRight  = 24
+	sensorChartMarginTop    = 48
+	sensorChartMarginBottom = 40
+
+	// sensorChartMaxPoints bounds the resolution of a chart; longer ranges are aggregated into wider intervals.
+	sensorChartMaxPoints = 200
+	// sensorChartMaxXTicks bounds the number of time labels on the x axis.
+	sensorChartMaxXTicks = 8
+	maxSensorChartRange  = 90 * 24 * time.Hour
+)
+
+// sensorChartMetric describes a chartable sensor reading.
+type sensorChartMetric struct {
+	label string
+	unit  string
+}
+
+// sensorChartMetrics lists the metrics that can be charted, keyed by the metric query value.
+var sensorChartMetrics = map[string]sensorChartMetric{
+	"temperature": {label: "Temperature", unit: "°C"},
+	"humidity":    {label: "Humidity", unit: "%"},
+	"battery":     {label: "Battery", unit: "%"},
+}
+
+// sensorChartIntervals are the aggregation intervals a chart may use, finest first.
+var sensorChartIntervals = []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour}
+
+// sensorChartTickSteps are the spacings of the time labels, finest first.
+var sensorChartTickSteps = []time.Duration{time.Hour, 2 * time.Hour, 3 * time.Hour, 6 * time.Hour, 12 * time.Hour, 24 * time.Hour, 48 * time.Hour, 7 * 24 * time.Hour, 14 * 24 * time.Hour}
+
+var (
+	sensorChartBackground = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	sensorChartGrid       = color.RGBA{0xe5, 0xe7, 0xeb, 0xff}
+	sensorChartAxis       = color.RGBA{0x9c, 0xa3, 0xaf, 0xff}
+	sensorChartText       = color.RGBA{0x37, 0x41, 0x51, 0xff}
+	sensorChartLine       = color.RGBA{0x25, 0x63, 0xeb, 0xff}
+	sensorChartBand       = color.RGBA{0xd6, 0xe4, 0xfd, 0xff}
+)
+
+// GetSensorChart renders the recorded readings of a sensor as a line chart image, for channels that cannot draw
+// charts themselves (e-mail, Telegram). The line shows the average of each interval and the shaded band its
+// minimum and maximum. The interval is picked from the range so a chart has at most a few hundred points.
+//
+// param deviceID The device ID of the sensor.
+// param metric The reading to chart: temperature (default), humidity or battery.
+// param rangeParam How far back the chart reaches, in hours or days (e.g., "24h", "7d"; default 24h, max 90d).
+// param format The image format: png (default) or svg.
+// param timezone The IANA time zone of the time labels (empty = deployment TIMEZONE).
+// return *dtos.SensorChartDTO The rendered image and its content type.
+// return error A bad request error if a parameter is invalid.
+func (uc *TuyaSensorUseCase) GetSensorChart(deviceID, metric, rangeParam, format, timezone string) (*dtos.SensorChartDTO, error) {
+	if metric == "" {
+		metric = "temperature"
+	}
+	info, ok := sensorChartMetrics[metric]
+	if !ok {
+		return nil, tuya_errors.BadRequest("metric must be temperature, humidity or battery")
+	}
+	if format == "" {
+		format = "png"
+	}
+	if format != "png" && format != "svg" {
+		return nil, tuya_errors.BadRequest("format must be png or svg")
+	}
+	if rangeParam == "" {
+		rangeParam = "24h"
+	}
+	span, err := parseSensorChartRange(rangeParam)
+	if err != nil {
+		return nil, err
+	}
+
+	interval := sensorChartIntervals[len(sensorChartIntervals)-1]
+	for _, candidate := range sensorChartIntervals {
+		if span/candidate <= sensorChartMaxPoints {
+			interval = candidate
+			break
+		}
+	}
+
+	to := uc.clock.Now().Unix()
+	history, err := uc.GetSensorHistory(deviceID, to-int64(span.Seconds()), to, interval.String(), timezone)
+	if err != nil {
+		return nil, err
+	}
+	location, err := utils.LoadLocation(history.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+
+	chart := newSensorChart(history, metric, fmt.Sprintf("%s (%s) - last %s", info.label, info.unit, rangeParam), location)
+	if format == "svg" {
+		return &dtos.SensorChartDTO{ContentType: "image/svg+xml", Image: chart.renderSVG()}, nil
+	}
+	data, err := chart.renderPNG()
+	if err != nil {
+		return nil, err
+	}
+	return &dtos.SensorChartDTO{ContentType: "image/png", Image: data}, nil
+}
+
+// parseSensorChartRange parses a chart range in hours ("24h") or days ("7d").
+func parseSensorChartRange(value string) (time.Duration, error) {
+	invalid := tuya_errors.BadRequest("range must be a number of hours or days (e.g., 24h, 7d) up to 90d")
+	if len(value) < 2 {
+		return 0, invalid
+	}
+	count, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || count <= 0 {
+		return 0, invalid
+	}
+	var span time.Duration
+	switch value[len(value)-1] {
+	case 'h':
+		span = time.Duration(count) * time.Hour
+	case 'd':
+		span = time.Duration(count) * 24 * time.Hour
+	default:
+		return 0, invalid
+	}
+	if span > maxSensorChartRange {
+		return 0, invalid
+	}
+	return span, nil
+}
+
+// sensorChartPoint is one interval of the charted metric, positioned at the middle of the interval.
+type sensorChartPoint struct {
+	timestamp     int64
+	min, max, avg float64
+}
+
+// sensorChart is the layout of a chart, shared by the SVG and PNG renderers.
+type sensorChart struct {
+	title    string
+	from, to int64
+	location *time.Location
+	runs     [][]sensorChartPoint
+
+	yMin, yMax, yStep float64
+	xTicks            []int64
+	xStep             time.Duration
+}
+
+// newSensorChart lays out a chart of one metric of a sensor history.
+func newSensorChart(history *dtos.SensorHistoryDTO, metric, title string, location *time.Location) *sensorChart {
+	chart := &sensorChart{
+		title:    title,
+		from:     history.From,
+		to:       history.To,
+		location: location,
+	}
+
+	// Intervals without samples break the line instead of being bridged
+	var run []sensorChartPoint
+	lastStart := int64(0)
+	for _, point := range history.Points {
+		var stats *dtos.SensorStatsDTO
+		switch metric {
+		case "temperature":
+			stats = point.Temperature
+		case "humidity":
+			stats = point.Humidity
+		case "battery":
+			stats = point.Battery
+		}
+		if stats == nil {
+			continue
+		}
+		if len(run) > 0 && point.Timestamp-lastStart > history.Interval {
+			chart.runs = append(chart.runs, run)
+			run = nil
+		}
+		lastStart = point.Timestamp
+		middle := point.Timestamp + history.Interval/2
+		if middle < chart.from {
+			middle = chart.from
+		}
+		if middle > chart.to {
+			middle = chart.to
+		}
+		run = append(run, sensorChartPoint{timestamp: middle, min: stats.Min, max: stats.Max, avg: stats.Avg})
+	}
+	if len(run) > 0 {
+		chart.runs = append(chart.runs, run)
+	}
+
+	chart.scaleY()
+	chart.scaleX()
+	return chart
+}
+
+// scaleY picks a y range with round tick values covering every reading.
+func (c *sensorChart) scaleY() {
+	if len(c.runs) == 0 {
+		c.yMin, c.yMax, c.yStep = 0, 4, 1
+		return
+	}
+	low, high := math.Inf(1), math.Inf(-1)
+	for _, run := range c.runs {
+		for _, point := range run {
+			low = math.Min(low, point.min)
+			high = math.Max(high, point.max)
+		}
+	}
+	if high-low < 1e-9 {
+		low, high = low-1, high+1
+	}
+	c.yStep = niceSensorChartStep((high - low) / 4)
+	c.yMin = math.Floor(low/c.yStep) * c.yStep
+	c.yMax = math.Ceil(high/c.yStep) * c.yStep
+	if c.yMax-c.yMin < c.yStep/2 {
+		c.yMax += c.yStep
+	}
+}
+
+// niceSensorChartStep rounds a raw tick step up to 1, 2 or 5 times a power of ten.
+func niceSensorChartStep(raw float64) float64 {
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, factor := range []float64{1, 2, 5} {
+		if raw <= factor*magnitude {
+			return factor * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// scaleX places time labels at local clock boundaries, e.g. every 3 hours for a day.
+func (c *sensorChart) scaleX() {
+	span := time.Duration(c.to-c.from) * time.Second
+	c.xStep = sensorChartTickSteps[len(sensorChartTickSteps)-1]
+	for _, step := range sensorChartTickSteps {
+		if span/step <= sensorChartMaxXTicks {
+			c.xStep = step
+			break
+		}
+	}
+	stepSeconds := int64(c.xStep.Seconds())
+	tick := sensorIntervalStart(c.from, stepSeconds, c.location)
+	if tick < c.from {
+		tick += stepSeconds
+	}
+	for ; tick <= c.to; tick += stepSeconds {
+		c.xTicks = append(c.xTicks, tick)
+	}
+}
+
+// x returns the horizontal position of a timestamp.
+func (c *sensorChart) x(timestamp int64) float64 {
+	plotWidth := float64(sensorChartWidth - sensorChartMarginLeft - sensorChartMarginRight)
+	return sensorChartMarginLeft + float64(timestamp-c.from)/float64(c.to-c.from)*plotWidth
+}
+
+// y returns the vertical position of a value.
+func (c *sensorChart) y(value float64) float64 {
+	plotHeight := float64(sensorChartHeight - sensorChartMarginTop - sensorChartMarginBottom)
+	return sensorChartMarginTop + (c.yMax-value)/(c.yMax-c.yMin)*plotHeight
+}
+
+// yTicks returns the values of the horizontal grid lines.
+func (c *sensorChart) yTicks() []float64 {
+	var ticks []float64
+	for i := 0; ; i++ {
+		value := c.yMin + float64(i)*c.yStep
+		if value > c.yMax+c.yStep/1000 {
+			return ticks
+		}
+		ticks = append(ticks, value)
+	}
+}
+
+// yLabel formats a y tick with as many decimals as the tick step needs.
+func (c *sensorChart) yLabel(value float64) string {
+	decimals := 0
+	if c.yStep < 1 {
+		decimals = int(math.Ceil(-math.Log10(c.yStep)))
+	}
+	return strconv.FormatFloat(value, 'f', decimals, 64)
+}
+
+// xLabel formats a time tick: clock time for sub-day steps, month and day otherwise.
+func (c *sensorChart) xLabel(timestamp int64) string {
+	layout := "15:04"
+	if c.xStep >= 24*time.Hour {
+		layout = "01-02"
+	}
+	return time.Unix(timestamp, 0).In(c.location).Format(layout)
+}
+
+// renderSVG draws the chart as an SVG document.
+func (c *sensorChart) renderSVG() []byte {
+	var b strings.Builder
+	hex := func(col color.RGBA) string { return fmt.Sprintf("#%02x%02x%02x", col.R, col.G, col.B) }
+	left, right := sensorChartMarginLeft, sensorChartWidth-sensorChartMarginRight
+	top, bottom := sensorChartMarginTop, sensorChartHeight-sensorChartMarginBottom
+
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" font-family="sans-serif" font-size="12">`,
+		sensorChartWidth, sensorChartHeight, sensorChartWidth, sensorChartHeight)
+	fmt.Fprintf(&b, `<rect width="100%%" height="100%%" fill="%s"/>`, hex(sensorChartBackground))
+	fmt.Fprintf(&b, `<text x="%d" y="28" font-size="16" font-weight="bold" fill="%s">%s</text>`, left, hex(sensorChartText), html.EscapeString(c.title))
+
+	for _, value := range c.yTicks() {
+		y := c.y(value)
+		fmt.Fprintf(&b, `<line x1="%d" y1="%.1f" x2="%d" y2="%.1f" stroke="%s"/>`, left, y, right, y, hex(sensorChartGrid))
+		if len(c.runs) > 0 {
+			fmt.Fprintf(&b, `<text x="%d" y="%.1f" text-anchor="end" fill="%s">%s</text>`, left-8, y+4, hex(sensorChartText), c.yLabel(value))
+		}
+	}
+	for _, tick := range c.xTicks {
+		x := c.x(tick)
+		fmt.Fprintf(&b, `<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="%s"/>`, x, top, x, bottom, hex(sensorChartGrid))
+		fmt.Fprintf(&b, `<text x="%.1f" y="%d" text-anchor="middle" fill="%s">%s</text>`, x, bottom+20, hex(sensorChartText), c.xLabel(tick))
+	}
+	fmt.Fprintf(&b, `<rect x="%d" y="%d" width="%d" height="%d" fill="none" stroke="%s"/>`, left, top, right-left, bottom-top, hex(sensorChartAxis))
+
+	if len(c.runs) == 0 {
+		fmt.Fprintf(&b, `<text x="%d" y="%d" text-anchor="middle" font-size="14" fill="%s">No data</text>`, (left+right)/2, (top+bottom)/2, hex(sensorChartAxis))
+	}
+	for _, run := range c.runs {
+		if len(run) == 1 {
+			point := run[0]
+			fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="%s" stroke-width="6"/>`,
+				c.x(point.timestamp), c.y(point.max), c.x(point.timestamp), c.y(point.min), hex(sensorChartBand))
+			fmt.Fprintf(&b, `<circle cx="%.1f" cy="%.1f" r="3" fill="%s"/>`, c.x(point.timestamp), c.y(point.avg), hex(sensorChartLine))
+			continue
+		}
+		band := make([]string, 0, 2*len(run))
+		line := make([]string, 0, len(run))
+		for _, point := range run {
+			band = append(band, fmt.Sprintf("%.1f,%.1f", c.x(point.timestamp), c.y(point.max)))
+			line = append(line, fmt.Sprintf("%.1f,%.1f", c.x(point.timestamp), c.y(point.avg)))
+		}
+		for i := len(run) - 1; i >= 0; i-- {
+			band = append(band, fmt.Sprintf("%.1f,%.1f", c.x(run[i].timestamp), c.y(run[i].min)))
+		}
+		fmt.Fprintf(&b, `<polygon points="%s" fill="%s"/>`, strings.Join(band, " "), hex(sensorChartBand))
+		fmt.Fprintf(&b, `<polyline points="%s" fill="none" stroke="%s" stroke-width="2" stroke-linejoin="round"/>`, strings.Join(line, " "), hex(sensorChartLine))
+	}
+	b.WriteString(`</svg>`)
+	return []byte(b.String())
+}
+
+// renderPNG draws the chart as a PNG image. Labels use a built-in bitmap font, so they are upper case.
+func (c *sensorChart) renderPNG() ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, sensorChartWidth, sensorChartHeight))
+	draw.Draw(img, img.Bounds(), &image.Uniform{C: sensorChartBackground}, image.Point{}, draw.Src)
+	left, right := sensorChartMarginLeft, sensorChartWidth-sensorChartMarginRight
+	top, bottom := sensorChartMarginTop, sensorChartHeight-sensorChartMarginBottom
+
+	drawChartText(img, c.title, left, 16, 2, -1, sensorChartText)
+	for _, value := range c.yTicks() {
+		y := int(math.Round(c.y(value)))
+		fillChartRect(img, left, y, right, y+1, sensorChartGrid)
+		if len(c.runs) > 0 {
+			drawChartText(img, c.yLabel(value), left-8, y-3, 1, 1, sensorChartText)
+		}
+	}
+	for _, tick := range c.xTicks {
+		x := int(math.Round(c.x(tick)))
+		fillChartRect(img, x, top, x+1, bottom, sensorChartGrid)
+		drawChartText(img, c.xLabel(tick), x, bottom+10, 1, 0, sensorChartText)
+	}
+
+	if len(c.runs) == 0 {
+		drawChartText(img, "No data", (left+right)/2, (top+bottom)/2-7, 2, 0, sensorChartAxis)
+	}
+	for _, run := range c.runs {
+		if len(run) == 1 {
+			point := run[0]
+			x := int(math.Round(c.x(point.timestamp)))
+			fillChartRect(img, x-3, int(c.y(point.max)), x+3, int(c.y(point.min))+1, sensorChartBand)
+			fillChartRect(img, x-2, int(c.y(point.avg))-2, x+3, int(c.y(point.avg))+3, sensorChartLine)
+			continue
+		}
+		for i := 1; i < len(run); i++ {
+			fillChartBand(img, c.x(run[i-1].timestamp), c.y(run[i-1].max), c.y(run[i-1].min),
+				c.x(run[i].timestamp), c.y(run[i].max), c.y(run[i].min), sensorChartBand)
+		}
+		for i := 1; i < len(run); i++ {
+			drawChartLine(img, c.x(run[i-1].timestamp), c.y(run[i-1].avg), c.x(run[i].timestamp), c.y(run[i].avg), sensorChartLine)
+		}
+	}
+
+	// Frame last, so bands touching the y range limits do not cover it
+	fillChartRect(img, left, top, right+1, top+1, sensorChartAxis)
+	fillChartRect(img, left, bottom, right+1, bottom+1, sensorChartAxis)
+	fillChartRect(img, left, top, left+1, bottom+1, sensorChartAxis)
+	fillChartRect(img, right, top, right+1, bottom+1, sensorChartAxis)
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("failed to encode chart: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// fillChartRect fills the rectangle [x0,x1) x [y0,y1).
+func fillChartRect(img *image.RGBA, x0, y0, x1, y1 int, col color.RGBA) {
+	draw.Draw(img, image.Rect(x0, y0, x1, y1).Intersect(img.Bounds()), &image.Uniform{C: col}, image.Point{}, draw.Src)
+}
+
+// fillChartBand fills the area between two interpolated edges, column by column.
+func fillChartBand(img *image.RGBA, x0, top0, bottom0, x1, top1, bottom1 float64, col color.RGBA) {
+	start, end := int(math.Round(x0)), int(math.Round(x1))
+	for x := start; x <= end; x++ {
+		t := 0.0
+		if end > start {
+			t = float64(x-start) / float64(end-start)
+		}
+		top := top0 + (top1-top0)*t
+		bottom := bottom0 + (bottom1-bottom0)*t
+		fillChartRect(img, x, int(math.Round(top)), x+1, int(math.Round(bottom))+1, col)
+	}
+}
+
+// drawChartLine draws a two pixel wide line.
+func drawChartLine(img *image.RGBA, x0, y0, x1, y1 float64, col color.RGBA) {
+	steps := int(math.Ceil(math.Max(math.Abs(x1-x0), math.Abs(y1-y0))))
+	if steps == 0 {
+		steps = 1
+	}
+	for i := 0; i <= steps; i++ {
+		t := float64(i) / float64(steps)
+		x := int(math.Round(x0 + (x1-x0)*t))
+		y := int(math.Round(y0 + (y1-y0)*t))
+		fillChartRect(img, x-1, y-1, x+1, y+1, col)
+	}
+}
+
+// drawChartText draws text with the bitmap font. align is -1 to start at x, 0 to center on x and 1 to end at x;
+// y is the top of the glyphs.
+func drawChartText(img *image.RGBA, text string, x, y, scale, align int, col color.RGBA) {
+	runes := []rune(strings.ToUpper(text))
+	width := (len(runes)*(chartGlyphWidth+1) - 1) * scale
+	switch align {
+	case 0:
+		x -= width / 2
+	case 1:
+		x -= width
+	}
+	for _, r := range runes {
+		glyph, ok := chartFont[r]
+		if !ok {
+			glyph = chartFont[unicode.ReplacementChar]
+		}
+		for row, bits := range glyph {
+			for column := 0; column < chartGlyphWidth; column++ {
+				if bits&(1<<(chartGlyphWidth-1-column)) != 0 {
+					px, py := x+column*scale, y+row*scale
+					fillChartRect(img, px, py, px+scale, py+scale, col)
+				}
+			}
+		}
+		x += (chartGlyphWidth + 1) * scale
+	}
+}
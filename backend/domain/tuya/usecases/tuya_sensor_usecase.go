@@ -1,38 +1,142 @@
 package usecases
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
 	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"time"
 )
 
+// Alarm sensor types keyed by Tuya category.
+var alarmSensorTypes = map[string]string{
+	"sj":   "water_leak",
+	"ywbj": "smoke",
+}
+
+// alarmStatusCodes lists the DP codes carrying the alarm state, in order of preference.
+var alarmStatusCodes = []string{"watersensor_state", "smoke_sensor_status", "smoke_sensor_state"}
+
+const (
+	defaultSensorSampleInterval = 5 * time.Minute
+
+	// sampledSensorsKey holds the IDs of the sensors recorded by the sampler.
+	sampledSensorsKey = "sensor_sampler:devices"
+	// maxSensorHistoryPoints bounds the number of points returned by one history query.
+	maxSensorHistoryPoints = 1000
+)
+
+// sampledStatusCodes lists the DP codes recorded into the sensor history.
+var sampledStatusCodes = map[string]bool{
+	"va_temperature":     true,
+	"va_humidity":        true,
+	"battery_percentage": true,
+	"cur_power":          true, // metered plugs and ACs, for energy reports
+}
+
 // TuyaSensorUseCase handles retrieval and interpretation of sensor data.
-// It parses raw device status values (like temperature, humidity) into formatted DTOs.
+// It parses raw device status values (like temperature, humidity) into formatted DTOs,
+// and tracks alarm sensors (water leak, smoke) so alarm transitions are pushed as high-priority events.
+// Every sensor read through the API is enrolled in a background sampler that records its readings
+// into the sensor history at SENSOR_SAMPLE_INTERVAL.
 type TuyaSensorUseCase struct {
 	getDeviceUseCase *TuyaGetDeviceByIDUseCase
+	sensorPoller     *SensorPollerUseCase
+	historyUC        *SensorHistoryUseCase
+	authUC           *TuyaAuthUseCase
+	cache            persistence.CacheStore
+	realtimeHub      *realtime_services.RealtimeHubService
+	sampleInterval   time.Duration
+	clock            utils.Clock
+	samplerMu        sync.Mutex
+	startOnce        sync.Once
+	workers          utils.WorkerGroup
 }
 
 // NewTuyaSensorUseCase initializes a new TuyaSensorUseCase.
 //
 // param getDeviceUseCase The usecase dependency for fetching raw device data.
+// param sensorPoller The SensorPollerUseCase serving sensor status from the shared batch snapshot.
+// param historyUC The SensorHistoryUseCase the sampler records readings into.
+// param authUC The TuyaAuthUseCase providing the server-managed token for sampling.
+// param cache The CacheStore used to remember the last alarm state per device.
+// param realtimeHub The RealtimeHubService notified of alarm transitions (optional).
+// param clock The Clock used to timestamp alarm events.
 // return *TuyaSensorUseCase A pointer to the initialized usecase.
-func NewTuyaSensorUseCase(getDeviceUseCase *TuyaGetDeviceByIDUseCase) *TuyaSensorUseCase {
+func NewTuyaSensorUseCase(getDeviceUseCase *TuyaGetDeviceByIDUseCase, sensorPoller *SensorPollerUseCase, historyUC *SensorHistoryUseCase, authUC *TuyaAuthUseCase, cache persistence.CacheStore, realtimeHub *realtime_services.RealtimeHubService, clock utils.Clock) *TuyaSensorUseCase {
+	sampleInterval, err := time.ParseDuration(utils.GetConfig().SensorSampleInterval)
+	if err != nil || sampleInterval <= 0 {
+		sampleInterval = defaultSensorSampleInterval
+	}
+
 	return &TuyaSensorUseCase{
 		getDeviceUseCase: getDeviceUseCase,
+		sensorPoller:     sensorPoller,
+		historyUC:        historyUC,
+		authUC:           authUC,
+		cache:            cache,
+		realtimeHub:      realtimeHub,
+		sampleInterval:   sampleInterval,
+		clock:            clock,
 	}
 }
 
+// Start records the readings of enrolled sensors in the background at SENSOR_SAMPLE_INTERVAL.
+func (uc *TuyaSensorUseCase) Start() {
+	if uc.cache == nil || uc.historyUC == nil {
+		return
+	}
+
+	uc.startOnce.Do(func() {
+		uc.workers.Go(func(stop <-chan struct{}) {
+			ticker := time.NewTicker(uc.sampleInterval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					uc.sample()
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("TuyaSensorUseCase: Sampling sensor history every %s", uc.sampleInterval)
+	})
+}
+
+// Stop ends history sampling and waits for a sample in progress to finish.
+func (uc *TuyaSensorUseCase) Stop() {
+	uc.workers.Stop()
+}
+
 // GetSensorData retrieves, interprets, and formats sensor readings for a specific device.
+// Readings come from the shared sensor snapshot, which is refreshed with batch status calls.
 // It converts raw values (often integers scaled by 10) into human-readable floats and generates descriptive status text.
 //
+// param ctx The request context, used for cancellation and timing metadata.
 // param accessToken The valid OAuth 2.0 access token.
 // param deviceID The device ID of the sensor.
 // return *dtos.SensorDataDTO The structured sensor data containing temperature, humidity, and status.
 // return error An error if fetching the device data fails.
-func (uc *TuyaSensorUseCase) GetSensorData(accessToken, deviceID string) (*dtos.SensorDataDTO, error) {
-	device, err := uc.getDeviceUseCase.GetDeviceByID(accessToken, deviceID)
+func (uc *TuyaSensorUseCase) GetSensorData(ctx context.Context, accessToken, deviceID string) (*dtos.SensorDataDTO, error) {
+	device, err := uc.sensorPoller.Read(ctx, accessToken, deviceID)
 	if err != nil {
 		return nil, err
 	}
+	uc.enrollSampledSensor(device)
+
+	if alarmType, ok := alarmSensorTypes[device.Category]; ok {
+		return uc.buildAlarmSensorData(device, alarmType), nil
+	}
 
 	var temperature float64
 	var humidity int
@@ -89,4 +193,373 @@ func (uc *TuyaSensorUseCase) GetSensorData(accessToken, deviceID string) (*dtos.
 	}
 
 	return response, nil
+}
+
+// GetSensorHistory returns the recorded readings of a sensor aggregated into fixed intervals for charting.
+// Temperatures are converted to °C; intervals without samples are omitted.
+//
+// param deviceID The device ID of the sensor.
+// param from The start of the range as a Unix timestamp (0 = 24 hours before to).
+// param to The end of the range as a Unix timestamp (0 = now).
+// param interval The aggregation interval (e.g., "1h", "6h", "24h"); a whole number of hours, default 1h.
+// param timezone The IANA time zone whose midnight intervals are aligned to (empty = deployment TIMEZONE).
+// return *dtos.SensorHistoryDTO The aggregated time series, oldest first.
+// return error A bad request error if the range, interval or time zone is invalid.
+func (uc *TuyaSensorUseCase) GetSensorHistory(deviceID string, from, to int64, interval, timezone string) (*dtos.SensorHistoryDTO, error) {
+	location, err := utils.LoadLocation(timezone)
+	if err != nil {
+		return nil, tuya_errors.BadRequest("unknown timezone %q", timezone)
+	}
+	step := time.Hour
+	if interval != "" {
+		parsed, err := time.ParseDuration(interval)
+		if err != nil || parsed < time.Hour || parsed%time.Hour != 0 {
+			return nil, tuya_errors.BadRequest("interval must be a whole number of hours (e.g., 1h, 24h)")
+		}
+		step = parsed
+	}
+	if to == 0 {
+		to = uc.clock.Now().Unix()
+	}
+	if from == 0 {
+		from = to - int64((24 * time.Hour).Seconds())
+	}
+	if from >= to {
+		return nil, tuya_errors.BadRequest("from must be before to")
+	}
+	stepSeconds := int64(step.Seconds())
+	if (to-from)/stepSeconds > maxSensorHistoryPoints {
+		return nil, tuya_errors.BadRequest("range too large for interval %s (max %d points)", step, maxSensorHistoryPoints)
+	}
+
+	buckets, err := uc.historyUC.GetBuckets(deviceID, time.Unix(from, 0), time.Unix(to, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	points := make(map[int64]*dtos.SensorHistoryPointDTO)
+	for _, bucket := range buckets {
+		start := sensorIntervalStart(bucket.Hour, stepSeconds, location)
+		point := points[start]
+		if point == nil {
+			point = &dtos.SensorHistoryPointDTO{Timestamp: start}
+			points[start] = point
+		}
+		for code, rollup := range bucket.Codes {
+			switch code {
+			case "va_temperature":
+				point.Temperature = mergeSensorStats(point.Temperature, rollup, 10)
+			case "va_humidity":
+				point.Humidity = mergeSensorStats(point.Humidity, rollup, 1)
+			case "battery_percentage":
+				point.Battery = mergeSensorStats(point.Battery, rollup, 1)
+			}
+		}
+	}
+
+	history := &dtos.SensorHistoryDTO{
+		DeviceID: deviceID,
+		From:     from,
+		To:       to,
+		Interval: stepSeconds,
+		Timezone: location.String(),
+		Points:   make([]dtos.SensorHistoryPointDTO, 0, len(points)),
+	}
+	for _, point := range points {
+		history.Points = append(history.Points, *point)
+	}
+	sort.Slice(history.Points, func(i, j int) bool {
+		return history.Points[i].Timestamp < history.Points[j].Timestamp
+	})
+	return history, nil
+}
+
+// sensorIntervalStart returns the start of the interval containing an hourly bucket. Intervals are aligned
+// to local midnight of the time zone, so 24h intervals are calendar days of the building, not UTC days.
+func sensorIntervalStart(hour, stepSeconds int64, location *time.Location) int64 {
+	_, offset := time.Unix(hour, 0).In(location).Zone()
+	local := hour + int64(offset)
+	remainder := local % stepSeconds
+	if remainder < 0 {
+		remainder += stepSeconds
+	}
+	return hour - remainder
+}
+
+// mergeSensorStats folds an hourly rollup into the stats of an interval. Buckets are merged oldest first,
+// so the last value of the latest bucket wins. Raw values are divided by scale.
+func mergeSensorStats(stats *dtos.SensorStatsDTO, rollup *entities.SensorRollup, scale float64) *dtos.SensorStatsDTO {
+	if rollup == nil || rollup.Count == 0 {
+		return stats
+	}
+	low, high, sum, last := rollup.Min/scale, rollup.Max/scale, rollup.Sum/scale, rollup.Last/scale
+	if stats == nil {
+		return &dtos.SensorStatsDTO{Min: low, Max: high, Avg: sum / float64(rollup.Count), Last: last, Samples: rollup.Count}
+	}
+
+	total := stats.Avg*float64(stats.Samples) + sum
+	stats.Samples += rollup.Count
+	stats.Avg = total / float64(stats.Samples)
+	if low < stats.Min {
+		stats.Min = low
+	}
+	if high > stats.Max {
+		stats.Max = high
+	}
+	stats.Last = last
+	return stats
+}
+
+// sample records the current readings of every enrolled sensor.
+func (uc *TuyaSensorUseCase) sample() {
+	deviceIDs := uc.sampledSensors()
+	if len(deviceIDs) == 0 {
+		return
+	}
+
+	ctx := context.Background()
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		utils.LogWarn("TuyaSensorUseCase: Skipping history sample, no server token: %v", err)
+		return
+	}
+
+	for _, deviceID := range deviceIDs {
+		device, err := uc.sensorPoller.Read(ctx, token.AccessToken, deviceID)
+		if err != nil {
+			utils.LogWarn("TuyaSensorUseCase: Failed to sample %s: %v", deviceID, err)
+			continue
+		}
+
+		var readings []dtos.TuyaDeviceStatusDTO
+		for _, status := range device.Status {
+			if sampledStatusCodes[status.Code] {
+				readings = append(readings, status)
+			}
+		}
+		if err := uc.historyUC.Record(deviceID, readings); err != nil {
+			utils.LogWarn("TuyaSensorUseCase: Failed to record history for %s: %v", deviceID, err)
+		}
+	}
+	utils.LogDebug("TuyaSensorUseCase: Sampled %d sensors", len(deviceIDs))
+}
+
+// enrollSampledSensor adds a sensor reporting sampled codes to the sampler, once.
+func (uc *TuyaSensorUseCase) enrollSampledSensor(device *dtos.TuyaDeviceDTO) {
+	if uc.cache == nil {
+		return
+	}
+	sampled := false
+	for _, status := range device.Status {
+		if sampledStatusCodes[status.Code] {
+			sampled = true
+			break
+		}
+	}
+	if !sampled {
+		return
+	}
+
+	uc.samplerMu.Lock()
+	defer uc.samplerMu.Unlock()
+
+	deviceIDs := uc.sampledSensors()
+	for _, id := range deviceIDs {
+		if id == device.ID {
+			return
+		}
+	}
+	data, err := json.Marshal(append(deviceIDs, device.ID))
+	if err != nil {
+		return
+	}
+	if err := uc.cache.SetPersistent(sampledSensorsKey, data); err != nil {
+		utils.LogWarn("TuyaSensorUseCase: Failed to enroll %s in history sampling: %v", device.ID, err)
+		return
+	}
+	utils.LogInfo("TuyaSensorUseCase: Recording history for sensor %s", device.ID)
+}
+
+// sampledSensors returns the IDs of the sensors enrolled in the sampler.
+func (uc *TuyaSensorUseCase) sampledSensors() []string {
+	data, err := uc.cache.Get(sampledSensorsKey)
+	if err != nil || data == nil {
+		return nil
+	}
+	var deviceIDs []string
+	if err := json.Unmarshal(data, &deviceIDs); err != nil {
+		utils.LogWarn("TuyaSensorUseCase: Ignoring malformed sampled sensor list: %v", err)
+		return nil
+	}
+	return deviceIDs
+}
+
+// TestAlarm publishes a simulated alarm event for an alarm sensor, so clients can verify their alarm handling
+// end to end without triggering the physical device. The stored alarm state is not changed.
+//
+// param ctx The request context, used for cancellation and timing metadata.
+// param accessToken The valid OAuth 2.0 access token.
+// param deviceID The device ID of the alarm sensor.
+// return *dtos.AlarmTestResponseDTO The simulated alarm that was published.
+// return error A bad request error if the device is not an alarm sensor.
+func (uc *TuyaSensorUseCase) TestAlarm(ctx context.Context, accessToken, deviceID string) (*dtos.AlarmTestResponseDTO, error) {
+	device, err := uc.getDeviceUseCase.GetDeviceByID(ctx, accessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	alarmType, ok := alarmSensorTypes[device.Category]
+	if !ok {
+		return nil, tuya_errors.BadRequest("device %s is category %s, not an alarm sensor", deviceID, device.Category)
+	}
+
+	utils.LogInfo("TestAlarm: Publishing simulated %s alarm for device %s", alarmType, deviceID)
+	uc.publishAlarmEvent(deviceID, device.Category, alarmType, true, true)
+
+	return &dtos.AlarmTestResponseDTO{
+		DeviceID: deviceID,
+		Type:     alarmType,
+	}, nil
+}
+
+// buildAlarmSensorData interprets an alarm sensor and publishes an event when its alarm state changed.
+func (uc *TuyaSensorUseCase) buildAlarmSensorData(device *dtos.TuyaDeviceDTO, alarmType string) *dtos.SensorDataDTO {
+	alarm := &dtos.SensorAlarmDTO{Type: alarmType}
+	var battery int
+
+	for _, code := range alarmStatusCodes {
+		for _, status := range device.Status {
+			if status.Code == code && alarm.Code == "" {
+				alarm.Code = status.Code
+				alarm.Value = status.Value
+				alarm.Active = isAlarmValue(status.Value)
+			}
+		}
+	}
+	for _, status := range device.Status {
+		if status.Code == "battery_percentage" {
+			if val, ok := status.Value.(float64); ok {
+				battery = int(val)
+			}
+		}
+	}
+
+	uc.trackAlarmState(device.ID, device.Category, alarmType, alarm.Active)
+
+	var statusText string
+	switch {
+	case alarmType == "water_leak" && alarm.Active:
+		statusText = "Water leak detected"
+	case alarmType == "water_leak":
+		statusText = "No water leak"
+	case alarm.Active:
+		statusText = "Smoke detected"
+	default:
+		statusText = "No smoke"
+	}
+
+	return &dtos.SensorDataDTO{
+		BatteryPercentage: battery,
+		StatusText:        statusText,
+		Category:          device.Category,
+		Alarm:             alarm,
+	}
+}
+
+// trackAlarmState compares the alarm state with the last known one and publishes on transitions.
+// The first observation of a device only records its state.
+func (uc *TuyaSensorUseCase) trackAlarmState(deviceID, category, alarmType string, active bool) {
+	if uc.cache == nil {
+		return
+	}
+
+	state := "normal"
+	if active {
+		state = "alarm"
+	}
+
+	key := fmt.Sprintf("alarm_state:%s", deviceID)
+	previous, err := uc.cache.Get(key)
+	if err != nil {
+		utils.LogWarn("TuyaSensorUseCase: Failed to read alarm state for %s: %v", deviceID, err)
+		return
+	}
+	if string(previous) == state {
+		return
+	}
+	if err := uc.cache.SetPersistent(key, []byte(state)); err != nil {
+		utils.LogWarn("TuyaSensorUseCase: Failed to save alarm state for %s: %v", deviceID, err)
+	}
+	if previous == nil {
+		return
+	}
+
+	utils.LogWarn("TuyaSensorUseCase: %s sensor %s changed to %s", alarmType, deviceID, state)
+	uc.publishAlarmEvent(deviceID, category, alarmType, active, false)
+}
+
+// ActiveAlarms returns the sensors whose leak or smoke alarm is currently active.
+//
+// return []string The device IDs, ordered by ID.
+// return error An error if the alarm states cannot be read.
+func (uc *TuyaSensorUseCase) ActiveAlarms() ([]string, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("alarm storage not initialized")
+	}
+	keys, err := uc.cache.GetAllKeysWithPrefix("alarm_state:")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list alarm states: %w", err)
+	}
+
+	deviceIDs := []string{}
+	for _, key := range keys {
+		state, err := uc.cache.Get(key)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read alarm state: %w", err)
+		}
+		if string(state) == "alarm" {
+			deviceIDs = append(deviceIDs, strings.TrimPrefix(key, "alarm_state:"))
+		}
+	}
+	return deviceIDs, nil
+}
+
+// publishAlarmEvent pushes a high-priority alarm event to realtime subscribers.
+func (uc *TuyaSensorUseCase) publishAlarmEvent(deviceID, category, alarmType string, active, simulated bool) {
+	if uc.realtimeHub == nil {
+		return
+	}
+
+	eventType := "alarm"
+	if !active {
+		eventType = "alarm_cleared"
+	}
+
+	uc.realtimeHub.Publish(realtime_dtos.DeviceEventDTO{
+		Type:      eventType,
+		DeviceID:  deviceID,
+		Category:  category,
+		Priority:  realtime_dtos.EventPriorityHigh,
+		Simulated: simulated,
+		Status: []realtime_dtos.DeviceEventStatusDTO{
+			{Code: "alarm_type", Value: alarmType},
+			{Code: "alarm_active", Value: active},
+		},
+		Timestamp: uc.clock.Now().Unix(),
+	})
+}
+
+// isAlarmValue interprets the raw alarm DP value. Water sensors report "alarm"/"normal",
+// smoke sensors report "alarm"/"normal" or "1" (alarm) / "2" (normal).
+func isAlarmValue(value interface{}) bool {
+	switch v := value.(type) {
+	case string:
+		return v == "alarm" || v == "1"
+	case bool:
+		return v
+	case float64:
+		return v == 1
+	default:
+		return false
+	}
 }
\ No newline at end of file
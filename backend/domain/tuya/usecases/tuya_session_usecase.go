@@ -0,0 +1,297 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	"time"
+)
+
+const (
+	// SessionIDPrefix marks bearer values that are server-side session IDs rather than raw Tuya tokens.
+	SessionIDPrefix = "sess_"
+
+	defaultSessionTTL = 30 * 24 * time.Hour
+
+	// tokenRefreshMargin refreshes the Tuya token slightly before it actually expires.
+	tokenRefreshMargin = 60 * time.Second
+
+	// appTokenIssuer is the iss claim of the app JWTs issued for sessions.
+	appTokenIssuer = "teralux"
+)
+
+// TuyaSessionUseCase manages server-side sessions that hold Tuya tokens on behalf of clients.
+// Clients receive an opaque session ID, and the backend transparently renews expired Tuya tokens.
+// When JWT_SECRET is set, the session ID is handed out inside a signed app JWT instead.
+type TuyaSessionUseCase struct {
+	cache     persistence.CacheStore
+	authUC    *TuyaAuthUseCase
+	ttl       time.Duration
+	jwtSecret []byte
+	mu        sync.Mutex
+	clock     utils.Clock
+	ids       utils.IDGenerator
+}
+
+// NewTuyaSessionUseCase initializes a new TuyaSessionUseCase.
+//
+// param cache The CacheStore used to persist sessions.
+// param authUC The TuyaAuthUseCase used to obtain fresh Tuya tokens.
+// param clock The Clock used for session and token expiry.
+// param ids The IDGenerator used for session IDs.
+// return *TuyaSessionUseCase A pointer to the initialized usecase.
+func NewTuyaSessionUseCase(cache persistence.CacheStore, authUC *TuyaAuthUseCase, clock utils.Clock, ids utils.IDGenerator) *TuyaSessionUseCase {
+	ttl, err := time.ParseDuration(utils.GetConfig().SessionTTL)
+	if err != nil || ttl <= 0 {
+		ttl = defaultSessionTTL
+	}
+
+	return &TuyaSessionUseCase{
+		cache:     cache,
+		authUC:    authUC,
+		ttl:       ttl,
+		jwtSecret: []byte(utils.GetConfig().JWTSecret),
+		clock:     clock,
+		ids:       ids,
+	}
+}
+
+// AppTokensEnabled reports whether clients authenticate with signed app JWTs (JWT_SECRET is set).
+//
+// return bool True if bearer values must be app JWTs.
+func (uc *TuyaSessionUseCase) AppTokensEnabled() bool {
+	return len(uc.jwtSecret) > 0
+}
+
+// VerifyAppToken checks the signature and expiry of an app JWT and returns the session ID it carries.
+//
+// param token The bearer value sent by the client.
+// return string The session ID encoded in the token.
+// return error An error if app tokens are disabled or the token is invalid or expired.
+func (uc *TuyaSessionUseCase) VerifyAppToken(token string) (string, error) {
+	if !uc.AppTokensEnabled() {
+		return "", fmt.Errorf("app tokens are not enabled")
+	}
+	claims, err := utils.ParseJWT(token, uc.jwtSecret, uc.clock.Now())
+	if err != nil {
+		return "", err
+	}
+	if claims.Issuer != appTokenIssuer || !uc.IsSessionID(claims.SessionID) {
+		return "", utils.ErrInvalidJWT
+	}
+	return claims.SessionID, nil
+}
+
+// IsSessionID reports whether a bearer value refers to a server-side session.
+//
+// param token The bearer value sent by the client.
+// return bool True if the value carries the session prefix.
+func (uc *TuyaSessionUseCase) IsSessionID(token string) bool {
+	return strings.HasPrefix(token, SessionIDPrefix)
+}
+
+// CreateSession stores a Tuya token server-side and returns the opaque session handle.
+//
+// param token The Tuya token obtained from Authenticate.
+// param apiKey The API key or identity provider user creating the session (empty for none); the session keeps its scope and grants.
+// return *dtos.TuyaSessionResponseDTO The session handle for the client.
+// return error An error if the session ID cannot be generated or persisted.
+func (uc *TuyaSessionUseCase) CreateSession(token *dtos.TuyaAuthResponseDTO, apiKey utils.APIKeyIdentity) (*dtos.TuyaSessionResponseDTO, error) {
+	if uc.cache == nil {
+		return nil, fmt.Errorf("session storage not initialized")
+	}
+
+	randomID, err := uc.ids.NewID(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate session id: %w", err)
+	}
+
+	now := uc.clock.Now()
+	session := entities.TuyaSession{
+		ID:           SessionIDPrefix + randomID,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+		UID:          token.UID,
+		Scope:        apiKey.Scope,
+		APIKeyID:     apiKey.ID,
+		Subject:      apiKey.Subject,
+		ExpiresAt:    now.Add(time.Duration(token.ExpireTime) * time.Second).Unix(),
+		CreatedAt:    now.Unix(),
+	}
+
+	if err := uc.saveSession(&session, uc.ttl); err != nil {
+		return nil, err
+	}
+
+	utils.LogInfo("TuyaSessionUseCase: Created session %s for uid %s", maskSessionID(session.ID), session.UID)
+	response := &dtos.TuyaSessionResponseDTO{
+		SessionID: session.ID,
+		ExpiresAt: now.Add(uc.ttl).Unix(),
+		UID:       session.UID,
+	}
+
+	if uc.AppTokensEnabled() {
+		// The JWT expires together with the session, so a verified token always refers to a live session
+		appToken, err := utils.SignJWT(utils.JWTClaims{
+			Issuer:    appTokenIssuer,
+			Subject:   session.UID,
+			SessionID: session.ID,
+			IssuedAt:  now.Unix(),
+			ExpiresAt: response.ExpiresAt,
+		}, uc.jwtSecret)
+		if err != nil {
+			return nil, err
+		}
+		response.Token = appToken
+		response.TokenType = "Bearer"
+	}
+	return response, nil
+}
+
+// ResolveSession returns the Tuya access token stored for a session, renewing it when it is about to expire.
+//
+// param sessionID The opaque session ID presented by the client.
+// return string The valid Tuya access token.
+// return utils.APIKeyIdentity The API key the session was created with (empty for none).
+// return error An error if the session is unknown, expired, or the token cannot be renewed.
+func (uc *TuyaSessionUseCase) ResolveSession(sessionID string) (string, utils.APIKeyIdentity, error) {
+	if uc.cache == nil {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("session storage not initialized")
+	}
+
+	session, err := uc.getSession(sessionID)
+	if err != nil {
+		return "", utils.APIKeyIdentity{}, err
+	}
+	if session == nil {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("session not found or expired")
+	}
+
+	if uc.clock.Now().Add(tokenRefreshMargin).Unix() < session.ExpiresAt {
+		return session.AccessToken, sessionAPIKey(session), nil
+	}
+
+	// Serialize renewals so concurrent requests do not all hit the token endpoint
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	session, err = uc.getSession(sessionID)
+	if err != nil || session == nil {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("session not found or expired")
+	}
+	if uc.clock.Now().Add(tokenRefreshMargin).Unix() < session.ExpiresAt {
+		return session.AccessToken, sessionAPIKey(session), nil
+	}
+
+	utils.LogInfo("TuyaSessionUseCase: Renewing Tuya token for session %s", maskSessionID(sessionID))
+	token, err := uc.authUC.RefreshToken(context.Background(), session.RefreshToken)
+	if err != nil {
+		utils.LogDebug("TuyaSessionUseCase: Refresh failed, requesting a new token: %v", err)
+		token, err = uc.authUC.Authenticate(context.Background())
+		if err != nil {
+			return "", utils.APIKeyIdentity{}, fmt.Errorf("failed to renew session token: %w", err)
+		}
+	}
+
+	session.AccessToken = token.AccessToken
+	session.RefreshToken = token.RefreshToken
+	session.ExpiresAt = uc.clock.Now().Add(time.Duration(token.ExpireTime) * time.Second).Unix()
+
+	remaining := time.Unix(session.CreatedAt, 0).Add(uc.ttl).Sub(uc.clock.Now())
+	if remaining <= 0 {
+		return "", utils.APIKeyIdentity{}, fmt.Errorf("session not found or expired")
+	}
+	if err := uc.saveSession(session, remaining); err != nil {
+		return "", utils.APIKeyIdentity{}, err
+	}
+
+	return session.AccessToken, sessionAPIKey(session), nil
+}
+
+// sessionAPIKey returns the API key a session was created with.
+func sessionAPIKey(session *entities.TuyaSession) utils.APIKeyIdentity {
+	return utils.APIKeyIdentity{ID: session.APIKeyID, Scope: session.Scope, Subject: session.Subject}
+}
+
+// SessionExpiry returns when a session expires, regardless of Tuya token renewals.
+//
+// param sessionID The session ID.
+// return int64 The Unix time after which the session ID is rejected.
+// return error An error if the session is unknown or cannot be read.
+func (uc *TuyaSessionUseCase) SessionExpiry(sessionID string) (int64, error) {
+	if uc.cache == nil {
+		return 0, fmt.Errorf("session storage not initialized")
+	}
+	session, err := uc.getSession(sessionID)
+	if err != nil {
+		return 0, err
+	}
+	if session == nil {
+		return 0, fmt.Errorf("session not found or expired")
+	}
+	return time.Unix(session.CreatedAt, 0).Add(uc.ttl).Unix(), nil
+}
+
+// RevokeSession deletes a session so its ID can no longer be used.
+//
+// param sessionID The session ID to revoke.
+// return error An error if the delete operation fails.
+func (uc *TuyaSessionUseCase) RevokeSession(sessionID string) error {
+	if uc.cache == nil {
+		return fmt.Errorf("session storage not initialized")
+	}
+	if err := uc.cache.Delete(sessionKey(sessionID)); err != nil {
+		return fmt.Errorf("failed to revoke session: %w", err)
+	}
+	utils.LogInfo("TuyaSessionUseCase: Revoked session %s", maskSessionID(sessionID))
+	return nil
+}
+
+// getSession loads a session from storage, returning nil if it does not exist.
+func (uc *TuyaSessionUseCase) getSession(sessionID string) (*entities.TuyaSession, error) {
+	jsonData, err := uc.cache.Get(sessionKey(sessionID))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get session: %w", err)
+	}
+	if jsonData == nil {
+		return nil, nil
+	}
+
+	var session entities.TuyaSession
+	if err := json.Unmarshal(jsonData, &session); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal session: %w", err)
+	}
+	return &session, nil
+}
+
+// saveSession persists a session with the given remaining lifetime.
+func (uc *TuyaSessionUseCase) saveSession(session *entities.TuyaSession, ttl time.Duration) error {
+	jsonData, err := json.Marshal(session)
+	if err != nil {
+		return fmt.Errorf("failed to marshal session: %w", err)
+	}
+	if err := uc.cache.Set(sessionKey(session.ID), jsonData, ttl); err != nil {
+		return fmt.Errorf("failed to save session: %w", err)
+	}
+	return nil
+}
+
+// sessionKey builds the storage key for a session. Sessions are not under the "cache:" prefix
+// so flushing the cache does not log every client out.
+func sessionKey(sessionID string) string {
+	return fmt.Sprintf("session:%s", sessionID)
+}
+
+// maskSessionID shortens a session ID for logging.
+func maskSessionID(sessionID string) string {
+	if len(sessionID) <= len(SessionIDPrefix)+6 {
+		return sessionID
+	}
+	return sessionID[:len(SessionIDPrefix)+6] + "..."
+}
@@ -0,0 +1,253 @@
+package usecases
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+	"teralux_app/domain/tuya/entities"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	"teralux_app/domain/tuya/services"
+)
+
+// swaggerExamplesKey is the persistent storage key for the active example set.
+const swaggerExamplesKey = "swagger_examples"
+
+// swaggerCommandPath is the Swagger path whose examples are replaced with device-specific payloads.
+const swaggerCommandPath = "/api/tuya/devices/{id}/commands/switch"
+
+// swaggerExampleDeviceID is the placeholder shown instead of the real device ID, since the Swagger document is public.
+const swaggerExampleDeviceID = "your-device-id"
+
+// TuyaSwaggerExamplesUseCase generates control payload examples from a real device's specification
+// and injects them into the served Swagger document, so testers send codes the device accepts.
+type TuyaSwaggerExamplesUseCase struct {
+	service     *services.TuyaDeviceService
+	getDeviceUC *TuyaGetDeviceByIDUseCase
+	authUC      *TuyaAuthUseCase
+	cache       persistence.CacheStore
+	clock       utils.Clock
+}
+
+// NewTuyaSwaggerExamplesUseCase initializes a new TuyaSwaggerExamplesUseCase.
+//
+// param service The TuyaDeviceService used to fetch device specifications.
+// param getDeviceUC The usecase used to resolve device name and category.
+// param authUC The TuyaAuthUseCase used to obtain a server-side token for admin calls.
+// param cache The CacheStore used to persist the generated examples.
+// param clock The Clock used for generation timestamps.
+// return *TuyaSwaggerExamplesUseCase A pointer to the initialized usecase.
+func NewTuyaSwaggerExamplesUseCase(service *services.TuyaDeviceService, getDeviceUC *TuyaGetDeviceByIDUseCase, authUC *TuyaAuthUseCase, cache persistence.CacheStore, clock utils.Clock) *TuyaSwaggerExamplesUseCase {
+	return &TuyaSwaggerExamplesUseCase{
+		service:     service,
+		getDeviceUC: getDeviceUC,
+		authUC:      authUC,
+		cache:       cache,
+		clock:       clock,
+	}
+}
+
+// GenerateExamples builds valid example commands for every function in the device specification.
+//
+// Tuya API Documentation (Get Device Specification):
+// URL: /v1.0/iot-03/devices/{device_id}/specification
+// Method: GET
+//
+// param ctx The request context.
+// param deviceID The device whose specification drives the examples.
+// return *dtos.SwaggerExamplesDTO The generated examples.
+// return error An error if the token, device or specification cannot be fetched.
+func (uc *TuyaSwaggerExamplesUseCase) GenerateExamples(ctx context.Context, deviceID string) (*dtos.SwaggerExamplesDTO, error) {
+	token, err := uc.authUC.GetServerToken(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to obtain admin token: %w", err)
+	}
+
+	device, err := uc.getDeviceUC.GetDeviceByID(ctx, token.AccessToken, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	urlPath := fmt.Sprintf("/v1.0/iot-03/devices/%s/specification", deviceID)
+
+	specResp, err := uc.service.FetchDeviceSpecification(ctx, urlPath, token.AccessToken)
+	if err != nil {
+		return nil, err
+	}
+	if !specResp.Success {
+		return nil, tuya_errors.FromTuya("failed to fetch specification", specResp.Code, specResp.Msg)
+	}
+
+	var commands []entities.TuyaCommand
+	for _, fn := range specResp.Result.Functions {
+		value, ok := exampleValueForFunction(fn)
+		if !ok {
+			utils.LogDebug("GenerateExamples: skipping function %s with unsupported type %s", fn.Code, fn.Type)
+			continue
+		}
+		commands = append(commands, entities.TuyaCommand{Code: fn.Code, Value: value})
+	}
+	if len(commands) == 0 {
+		return nil, fmt.Errorf("device %s exposes no controllable functions in its specification", deviceID)
+	}
+
+	set := entities.SwaggerExampleSet{
+		DeviceID:    deviceID,
+		DeviceName:  device.Name,
+		Category:    device.Category,
+		Commands:    commands,
+		GeneratedAt: uc.clock.Now().Unix(),
+	}
+
+	jsonData, err := json.Marshal(set)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal swagger examples: %w", err)
+	}
+	if err := uc.cache.SetPersistent(swaggerExamplesKey, jsonData); err != nil {
+		return nil, fmt.Errorf("failed to save swagger examples: %w", err)
+	}
+
+	utils.LogInfo("GenerateExamples: Generated %d Swagger examples from device %s (%s)", len(commands), deviceID, device.Name)
+	return toSwaggerExamplesDTO(&set), nil
+}
+
+// GetExamples returns the currently active example set.
+//
+// return *dtos.SwaggerExamplesDTO The active examples, or nil if none were generated yet.
+// return error An error if the stored examples cannot be read.
+func (uc *TuyaSwaggerExamplesUseCase) GetExamples() (*dtos.SwaggerExamplesDTO, error) {
+	set, err := uc.loadExamples()
+	if err != nil || set == nil {
+		return nil, err
+	}
+	return toSwaggerExamplesDTO(set), nil
+}
+
+// ApplyToSwaggerDoc injects the active examples into a Swagger JSON document.
+// The command DTO definition receives the first example, the path ID parameter receives a placeholder ID,
+// and the command endpoint description lists every valid command with the device category. The document is
+// public, so the ID and name of the real device are never included. The original document is returned on any failure.
+//
+// param doc The Swagger JSON document.
+// return string The document with examples applied.
+func (uc *TuyaSwaggerExamplesUseCase) ApplyToSwaggerDoc(doc string) string {
+	if uc.cache == nil {
+		return doc
+	}
+	set, err := uc.loadExamples()
+	if err != nil || set == nil || len(set.Commands) == 0 {
+		return doc
+	}
+
+	var spec map[string]interface{}
+	if err := json.Unmarshal([]byte(doc), &spec); err != nil {
+		utils.LogWarn("ApplyToSwaggerDoc: failed to parse swagger doc: %v", err)
+		return doc
+	}
+
+	if definitions, ok := spec["definitions"].(map[string]interface{}); ok {
+		for name, def := range definitions {
+			if strings.HasSuffix(name, "TuyaCommandDTO") {
+				if defMap, ok := def.(map[string]interface{}); ok {
+					defMap["example"] = set.Commands[0]
+				}
+			}
+		}
+	}
+
+	if paths, ok := spec["paths"].(map[string]interface{}); ok {
+		if path, ok := paths[swaggerCommandPath].(map[string]interface{}); ok {
+			if op, ok := path["post"].(map[string]interface{}); ok {
+				var lines []string
+				for _, cmd := range set.Commands {
+					payload, _ := json.Marshal(cmd)
+					lines = append(lines, fmt.Sprintf("- `%s`", string(payload)))
+				}
+				description, _ := op["description"].(string)
+				op["description"] = fmt.Sprintf("%s\n\nValid commands for devices of category `%s`:\n%s",
+					description, set.Category, strings.Join(lines, "\n"))
+
+				if params, ok := op["parameters"].([]interface{}); ok {
+					for _, p := range params {
+						if param, ok := p.(map[string]interface{}); ok && param["name"] == "id" {
+							param["x-example"] = swaggerExampleDeviceID
+						}
+					}
+				}
+			}
+		}
+	}
+
+	patched, err := json.Marshal(spec)
+	if err != nil {
+		return doc
+	}
+	return string(patched)
+}
+
+// loadExamples reads the persisted example set.
+func (uc *TuyaSwaggerExamplesUseCase) loadExamples() (*entities.SwaggerExampleSet, error) {
+	jsonData, err := uc.cache.Get(swaggerExamplesKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get swagger examples: %w", err)
+	}
+	if jsonData == nil {
+		return nil, nil
+	}
+	var set entities.SwaggerExampleSet
+	if err := json.Unmarshal(jsonData, &set); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal swagger examples: %w", err)
+	}
+	return &set, nil
+}
+
+// exampleValueForFunction derives a valid example value from a specification function.
+// Integer values use the minimum of the range, which the specification already gives as the raw DP value
+// (the scale only applies to displayed values); enums use the first option.
+func exampleValueForFunction(fn entities.TuyaDeviceFunction) (interface{}, bool) {
+	var values struct {
+		Min   *float64 `json:"min"`
+		Max   *float64 `json:"max"`
+		Range []string `json:"range"`
+	}
+	if fn.Values != "" {
+		_ = json.Unmarshal([]byte(fn.Values), &values)
+	}
+
+	switch strings.ToLower(fn.Type) {
+	case "boolean":
+		return true, true
+	case "integer":
+		if values.Min != nil {
+			return int(*values.Min), true
+		}
+		return 0, true
+	case "enum":
+		if len(values.Range) > 0 {
+			return values.Range[0], true
+		}
+		return nil, false
+	case "string":
+		return "", true
+	default:
+		return nil, false
+	}
+}
+
+// toSwaggerExamplesDTO converts the stored example set into its DTO.
+func toSwaggerExamplesDTO(set *entities.SwaggerExampleSet) *dtos.SwaggerExamplesDTO {
+	commands := make([]dtos.TuyaCommandDTO, len(set.Commands))
+	for i, cmd := range set.Commands {
+		commands[i] = dtos.TuyaCommandDTO{Code: cmd.Code, Value: cmd.Value}
+	}
+	return &dtos.SwaggerExamplesDTO{
+		DeviceID:    set.DeviceID,
+		DeviceName:  set.DeviceName,
+		Category:    set.Category,
+		Commands:    commands,
+		GeneratedAt: set.GeneratedAt,
+	}
+}
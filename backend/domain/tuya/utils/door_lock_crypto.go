@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// DecryptTicketKey decrypts the ticket_key of a door lock password ticket.
+// Tuya encrypts the ticket key with AES-256-ECB, using the client secret as the key, and returns it hex-encoded.
+//
+// param ticketKey The hex-encoded ticket_key from the password-ticket endpoint.
+// param clientSecret The Tuya Client Secret (32 characters).
+// return []byte The decrypted ticket key, used to encrypt lock passwords.
+// return error An error if the ticket key is malformed or cannot be decrypted.
+func DecryptTicketKey(ticketKey, clientSecret string) ([]byte, error) {
+	ciphertext, err := hex.DecodeString(ticketKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ticket key: %w", err)
+	}
+	block, err := aes.NewCipher([]byte(clientSecret))
+	if err != nil {
+		return nil, fmt.Errorf("invalid client secret for ticket decryption: %w", err)
+	}
+	if len(ciphertext) == 0 || len(ciphertext)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("invalid ticket key length %d", len(ciphertext))
+	}
+
+	plaintext := make([]byte, len(ciphertext))
+	for i := 0; i < len(ciphertext); i += block.BlockSize() {
+		block.Decrypt(plaintext[i:], ciphertext[i:i+block.BlockSize()])
+	}
+	padding := int(plaintext[len(plaintext)-1])
+	if padding == 0 || padding > block.BlockSize() || !bytes.Equal(plaintext[len(plaintext)-padding:], bytes.Repeat([]byte{byte(padding)}, padding)) {
+		return nil, fmt.Errorf("invalid ticket key padding")
+	}
+	return plaintext[:len(plaintext)-padding], nil
+}
+
+// EncryptLockPassword encrypts a door lock password with a decrypted ticket key (AES-128-ECB, PKCS7 padding),
+// as the temporary password endpoints expect.
+//
+// param password The plain numeric password.
+// param key The decrypted ticket key (16 bytes).
+// return string The uppercased hexadecimal ciphertext.
+// return error An error if the key is not a valid AES-128 key.
+func EncryptLockPassword(password string, key []byte) (string, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return "", fmt.Errorf("invalid ticket key: %w", err)
+	}
+	padding := block.BlockSize() - len(password)%block.BlockSize()
+	plaintext := append([]byte(password), bytes.Repeat([]byte{byte(padding)}, padding)...)
+
+	ciphertext := make([]byte, len(plaintext))
+	for i := 0; i < len(plaintext); i += block.BlockSize() {
+		block.Encrypt(ciphertext[i:], plaintext[i:i+block.BlockSize()])
+	}
+	return strings.ToUpper(hex.EncodeToString(ciphertext)), nil
+}
@@ -8,19 +8,20 @@ import (
 )
 
 // GenerateTuyaSignature calculates the HMAC-SHA256 signature required for Tuya API requests.
-// It constructs the message by concatenating clientID, accessToken, timestamp, and the stringToSign.
+// It constructs the message by concatenating clientID, accessToken, timestamp, nonce, and the stringToSign.
 //
-// Message Structure: clientID + accessToken + timestamp + stringToSign
+// Message Structure: clientID + accessToken + timestamp + nonce + stringToSign
 //
 // param clientID The Tuya Client ID.
 // param clientSecret The Tuya Client Secret (used as the HMAC key).
 // param accessToken The current access token (can be empty for token retrieval).
 // param timestamp The current timestamp in milliseconds.
+// param nonce The random request identifier sent in the nonce header (can be empty).
 // param stringToSign The constructed string representing request details (method, hash, url).
 // return string The uppercased hexadecimal signature.
-func GenerateTuyaSignature(clientID, clientSecret, accessToken, timestamp, stringToSign string) string {
-	// Concatenate: client_id + access_token + t + stringToSign
-	message := clientID + accessToken + timestamp + stringToSign
+func GenerateTuyaSignature(clientID, clientSecret, accessToken, timestamp, nonce, stringToSign string) string {
+	// Concatenate: client_id + access_token + t + nonce + stringToSign
+	message := clientID + accessToken + timestamp + nonce + stringToSign
 
 	// Create HMAC-SHA256 hash
 	h := hmac.New(sha256.New, []byte(clientSecret))
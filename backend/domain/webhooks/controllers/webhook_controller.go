@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"net/http"
+	"teralux_app/domain/common/dtos"
+	"teralux_app/domain/common/middlewares"
+	"teralux_app/domain/common/utils"
+	webhook_dtos "teralux_app/domain/webhooks/dtos"
+	"teralux_app/domain/webhooks/usecases"
+
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController handles the webhooks of the caller
+type WebhookController struct {
+	useCase *usecases.WebhookUseCase
+}
+
+// NewWebhookController creates a new WebhookController instance
+func NewWebhookController(useCase *usecases.WebhookUseCase) *WebhookController {
+	return &WebhookController{
+		useCase: useCase,
+	}
+}
+
+// CreateWebhook handles POST /api/webhooks endpoint
+// @Summary      Create Webhook
+// @Description  Registers a callback URL for device events (device_offline, device_online, sensor_threshold, command_failed, alarm). Deliveries are JSON POSTs signed with X-Teralux-Signature: sha256=HMAC-SHA256(secret, X-Teralux-Timestamp + "." + body), retried with backoff on failure. The secret is only returned here. Requires an API key (or identity token) of control scope, which owns the webhook: events are only delivered for devices the key may access, and stop when it is revoked. Non-admin keys only receive events of the devices claimed by their tenant (X-TUYA-UID if allowlisted for the key, else the default user).
+// @Tags         14. Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        request  body  webhook_dtos.WebhookRequestDTO  true  "Webhook"
+// @Success      201  {object}  dtos.StandardResponse{data=webhook_dtos.WebhookDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      403  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/webhooks [post]
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	var req webhook_dtos.WebhookRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	uid, ok := resolveTenantUID(ctx)
+	if !ok {
+		return
+	}
+
+	webhook, err := c.useCase.CreateWebhook(ctx.Request.Context(), uid, req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "CreateWebhook", err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, dtos.StandardResponse{
+		Status:  true,
+		Message: "Webhook created",
+		Data:    webhook,
+	})
+}
+
+// ListWebhooks handles GET /api/webhooks endpoint
+// @Summary      List Webhooks
+// @Description  Lists the caller's webhooks with the outcome of their latest delivery.
+// @Tags         14. Webhooks
+// @Produce      json
+// @Success      200  {object}  dtos.StandardResponse{data=[]webhook_dtos.WebhookDTO}
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/webhooks [get]
+func (c *WebhookController) ListWebhooks(ctx *gin.Context) {
+	webhooks, err := c.useCase.ListWebhooks(webhookOwner(ctx))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "ListWebhooks", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Webhooks fetched successfully",
+		Data:    webhooks,
+	})
+}
+
+// GetWebhook handles GET /api/webhooks/{id} endpoint
+// @Summary      Get Webhook
+// @Description  Returns a webhook of the caller.
+// @Tags         14. Webhooks
+// @Produce      json
+// @Param        id  path  string  true  "Webhook ID"
+// @Success      200  {object}  dtos.StandardResponse{data=webhook_dtos.WebhookDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/webhooks/{id} [get]
+func (c *WebhookController) GetWebhook(ctx *gin.Context) {
+	webhook, err := c.useCase.GetWebhook(webhookOwner(ctx), ctx.Param("id"))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "GetWebhook", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Webhook fetched successfully",
+		Data:    webhook,
+	})
+}
+
+// UpdateWebhook handles PUT /api/webhooks/{id} endpoint
+// @Summary      Update Webhook
+// @Description  Replaces the URL, events and filters of a webhook. The signing secret is kept.
+// @Tags         14. Webhooks
+// @Accept       json
+// @Produce      json
+// @Param        id       path  string                          true  "Webhook ID"
+// @Param        request  body  webhook_dtos.WebhookRequestDTO  true  "Webhook"
+// @Success      200  {object}  dtos.StandardResponse{data=webhook_dtos.WebhookDTO}
+// @Failure      400  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/webhooks/{id} [put]
+func (c *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	var req webhook_dtos.WebhookRequestDTO
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.JSON(http.StatusBadRequest, dtos.StandardResponse{
+			Status:  false,
+			Message: err.Error(),
+			Data:    nil,
+		})
+		return
+	}
+	webhook, err := c.useCase.UpdateWebhook(ctx.Request.Context(), webhookOwner(ctx), ctx.Param("id"), req)
+	if err != nil {
+		middlewares.AbortWithError(ctx, "UpdateWebhook", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Webhook updated",
+		Data:    webhook,
+	})
+}
+
+// DeleteWebhook handles DELETE /api/webhooks/{id} endpoint
+// @Summary      Delete Webhook
+// @Description  Removes a webhook. Deliveries still queued for it are dropped.
+// @Tags         14. Webhooks
+// @Produce      json
+// @Param        id  path  string  true  "Webhook ID"
+// @Success      200  {object}  dtos.StandardResponse
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/webhooks/{id} [delete]
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	if err := c.useCase.DeleteWebhook(webhookOwner(ctx), ctx.Param("id")); err != nil {
+		middlewares.AbortWithError(ctx, "DeleteWebhook", err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  true,
+		Message: "Webhook deleted",
+		Data:    nil,
+	})
+}
+
+// TestWebhook handles POST /api/webhooks/{id}/test endpoint
+// @Summary      Test Webhook
+// @Description  Sends a signed ping event to the webhook right away, without retries, and reports the response.
+// @Tags         14. Webhooks
+// @Produce      json
+// @Param        id  path  string  true  "Webhook ID"
+// @Success      200  {object}  dtos.StandardResponse{data=webhook_dtos.WebhookTestResultDTO}
+// @Failure      404  {object}  dtos.StandardResponse
+// @Failure      500  {object}  dtos.StandardResponse
+// @Security     ApiKeyAuth
+// @Router       /api/webhooks/{id}/test [post]
+func (c *WebhookController) TestWebhook(ctx *gin.Context) {
+	result, err := c.useCase.TestWebhook(ctx.Request.Context(), webhookOwner(ctx), ctx.Param("id"))
+	if err != nil {
+		middlewares.AbortWithError(ctx, "TestWebhook", err)
+		return
+	}
+
+	message := "Test event delivered"
+	if !result.Delivered {
+		message = "Test event could not be delivered"
+	}
+	ctx.JSON(http.StatusOK, dtos.StandardResponse{
+		Status:  result.Delivered,
+		Message: message,
+		Data:    result,
+	})
+}
+
+// webhookOwner returns the caller owning the webhooks of the request: the API key or identity provider user
+// verified by ApiKeyMiddleware.
+func webhookOwner(ctx *gin.Context) string {
+	return utils.APIKeyIdentityFromContext(ctx.Request.Context()).Actor()
+}
+
+// resolveTenantUID returns the Tuya UID whose claimed devices a new webhook receives events for: none for admin
+// keys, which see every device, else the X-TUYA-UID allowlisted for the caller, or the default user.
+// It answers 403 for a UID that is not allowlisted, and 500 when no default user is configured.
+func resolveTenantUID(ctx *gin.Context) (string, bool) {
+	if ctx.GetString("api_key_scope") == utils.APIKeyScopeAdmin {
+		return "", true
+	}
+	if uid := ctx.GetHeader("X-TUYA-UID"); uid != "" {
+		if !utils.GetConfig().IsUIDAllowed(utils.APIKeyIdentityFromContext(ctx.Request.Context()), uid) {
+			ctx.JSON(http.StatusForbidden, dtos.StandardResponse{
+				Status:  false,
+				Message: "X-TUYA-UID is not allowed for this API key",
+				Data:    nil,
+			})
+			return "", false
+		}
+		return uid, true
+	}
+
+	uid := utils.GetConfig().TuyaUserID
+	if uid == "" {
+		utils.LogError("TUYA_USER_ID is not set in environment")
+		ctx.JSON(http.StatusInternalServerError, dtos.StandardResponse{
+			Status:  false,
+			Message: "Server configuration error: TUYA_USER_ID missing",
+			Data:    nil,
+		})
+		return "", false
+	}
+	return uid, true
+}
@@ -0,0 +1,58 @@
+package dtos
+
+// WebhookRequestDTO registers or replaces a webhook.
+// Events are device_offline, device_online, sensor_threshold, command_failed and alarm
+type WebhookRequestDTO struct {
+	Name       string               `json:"name" binding:"max=100" example:"n8n alerts"`
+	URL        string               `json:"url" binding:"required,url" example:"https://n8n.example.com/webhook/teralux"`
+	Events     []string             `json:"events" binding:"required,min=1" example:"device_offline,command_failed"`
+	DeviceIDs  []string             `json:"device_ids"`
+	Thresholds []SensorThresholdDTO `json:"thresholds" binding:"dive"`
+	Enabled    *bool                `json:"enabled"`
+}
+
+// SensorThresholdDTO fires a sensor_threshold event when a raw DP value (e.g., va_temperature 235 = 23.5 °C)
+// rises above Above or falls below Below. An empty device_id applies to every device reporting the code
+type SensorThresholdDTO struct {
+	DeviceID string   `json:"device_id"`
+	Code     string   `json:"code" binding:"required" example:"va_temperature"`
+	Above    *float64 `json:"above" example:"300"`
+	Below    *float64 `json:"below"`
+}
+
+// WebhookDTO is a registered webhook. The signing secret is only returned when the webhook is created
+type WebhookDTO struct {
+	ID             string               `json:"id"`
+	Name           string               `json:"name"`
+	URL            string               `json:"url"`
+	Secret         string               `json:"secret,omitempty"`
+	Events         []string             `json:"events"`
+	DeviceIDs      []string             `json:"device_ids,omitempty"`
+	Thresholds     []SensorThresholdDTO `json:"thresholds,omitempty"`
+	Enabled        bool                 `json:"enabled"`
+	CreatedAt      int64                `json:"created_at"`
+	UpdatedAt      int64                `json:"updated_at"`
+	LastDeliveryAt int64                `json:"last_delivery_at,omitempty"`
+	LastStatusCode int                  `json:"last_status_code,omitempty"`
+	LastError      string               `json:"last_error,omitempty"`
+}
+
+// WebhookPayloadDTO is the JSON body posted to a webhook. It is signed with the webhook secret:
+// X-Teralux-Signature is "sha256=" + hex(HMAC-SHA256(secret, X-Teralux-Timestamp + "." + body))
+type WebhookPayloadDTO struct {
+	ID        string      `json:"id"`
+	Event     string      `json:"event"`
+	WebhookID string      `json:"webhook_id"`
+	DeviceID  string      `json:"device_id,omitempty"`
+	Category  string      `json:"category,omitempty"`
+	Data      interface{} `json:"data,omitempty"`
+	Timestamp int64       `json:"timestamp"`
+}
+
+// WebhookTestResultDTO is the outcome of a test delivery
+type WebhookTestResultDTO struct {
+	Delivered  bool   `json:"delivered"`
+	StatusCode int    `json:"status_code,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Error      string `json:"error,omitempty"`
+}
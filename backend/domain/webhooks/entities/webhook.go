@@ -0,0 +1,43 @@
+package entities
+
+import "teralux_app/domain/common/utils"
+
+// Webhook event types.
+const (
+	WebhookEventDeviceOffline   = "device_offline"
+	WebhookEventDeviceOnline    = "device_online"
+	WebhookEventSensorThreshold = "sensor_threshold"
+	WebhookEventCommandFailed   = "command_failed"
+	WebhookEventAlarm           = "alarm"
+	WebhookEventPing            = "ping"
+)
+
+// Webhook is a callback URL receiving signed JSON payloads for the events it subscribed to.
+// Owner is the API key or user that registered it; OwnerUID is the tenant whose claimed devices it receives
+// events for (empty for admins, who receive events of every device).
+type Webhook struct {
+	ID             string               `json:"id"`
+	Owner          utils.APIKeyIdentity `json:"owner"`
+	OwnerUID       string               `json:"owner_uid"`
+	Name           string               `json:"name"`
+	URL            string               `json:"url"`
+	Secret         string               `json:"secret"`
+	Events         []string             `json:"events"`
+	DeviceIDs      []string             `json:"device_ids,omitempty"`
+	Thresholds     []SensorThreshold    `json:"thresholds,omitempty"`
+	Enabled        bool                 `json:"enabled"`
+	CreatedAt      int64                `json:"created_at"`
+	UpdatedAt      int64                `json:"updated_at"`
+	LastDeliveryAt int64                `json:"last_delivery_at,omitempty"`
+	LastStatusCode int                  `json:"last_status_code,omitempty"`
+	LastError      string               `json:"last_error,omitempty"`
+}
+
+// SensorThreshold fires a sensor_threshold event when a DP value rises above Above or falls below Below.
+// An empty DeviceID applies the threshold to every device reporting the code.
+type SensorThreshold struct {
+	DeviceID string   `json:"device_id,omitempty"`
+	Code     string   `json:"code"`
+	Above    *float64 `json:"above,omitempty"`
+	Below    *float64 `json:"below,omitempty"`
+}
@@ -0,0 +1,42 @@
+package routes
+
+import (
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/webhooks/controllers"
+
+	"github.com/gin-gonic/gin"
+)
+
+// SetupWebhookRoutes registers endpoints for managing the caller's webhooks.
+//
+// param router The Gin router interface.
+// param controller The controller handling webhooks.
+func SetupWebhookRoutes(router gin.IRouter, controller *controllers.WebhookController) {
+	utils.LogDebug("SetupWebhookRoutes initialized")
+	api := router.Group("/api/webhooks")
+	{
+		// POST /api/webhooks
+		// Registers a callback URL for device events.
+		api.POST("", controller.CreateWebhook)
+
+		// GET /api/webhooks
+		// Lists the caller's webhooks.
+		api.GET("", controller.ListWebhooks)
+
+		// GET /api/webhooks/:id
+		// Returns a webhook.
+		api.GET("/:id", controller.GetWebhook)
+
+		// PUT /api/webhooks/:id
+		// Replaces the URL, events and filters of a webhook.
+		api.PUT("/:id", controller.UpdateWebhook)
+
+		// DELETE /api/webhooks/:id
+		// Removes a webhook.
+		api.DELETE("/:id", controller.DeleteWebhook)
+
+		// POST /api/webhooks/:id/test
+		// Sends a signed ping event to a webhook.
+		api.POST("/:id/test", controller.TestWebhook)
+	}
+}
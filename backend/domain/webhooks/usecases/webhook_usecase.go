@@ -0,0 +1,806 @@
+package usecases
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/common/infrastructure/outbound"
+	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/utils"
+	job_services "teralux_app/domain/jobs/services"
+	realtime_dtos "teralux_app/domain/realtime/dtos"
+	realtime_services "teralux_app/domain/realtime/services"
+	tuya_errors "teralux_app/domain/tuya/errors"
+	tuya_usecases "teralux_app/domain/tuya/usecases"
+	"teralux_app/domain/webhooks/dtos"
+	"teralux_app/domain/webhooks/entities"
+	"time"
+)
+
+const (
+	// WebhookDeliveryJobType is the job type of webhook deliveries.
+	WebhookDeliveryJobType = "webhook_delivery"
+
+	// webhookPrefix stores webhooks: "webhook:{id}".
+	webhookPrefix = "webhook:"
+	// maxWebhooksPerOwner bounds the webhooks a single user can register.
+	maxWebhooksPerOwner = 20
+	// defaultWebhookMaxAttempts is used when WEBHOOK_MAX_ATTEMPTS is not set.
+	defaultWebhookMaxAttempts = 5
+	// defaultWebhookTimeout is used when WEBHOOK_TIMEOUT is not set.
+	defaultWebhookTimeout = 10 * time.Second
+	// thresholdNormal is the state of a value within its thresholds.
+	thresholdNormal = "normal"
+)
+
+// ErrWebhookNotFound is returned when a webhook does not exist or belongs to another user.
+var ErrWebhookNotFound = tuya_errors.NotFound("webhook not found")
+
+// subscribableEvents are the events a webhook can subscribe to.
+var subscribableEvents = []string{
+	entities.WebhookEventDeviceOffline,
+	entities.WebhookEventDeviceOnline,
+	entities.WebhookEventSensorThreshold,
+	entities.WebhookEventCommandFailed,
+	entities.WebhookEventAlarm,
+}
+
+// webhookDeliveryPayload is the persisted input of a delivery job.
+type webhookDeliveryPayload struct {
+	WebhookID string                 `json:"webhook_id"`
+	Payload   dtos.WebhookPayloadDTO `json:"payload"`
+}
+
+// webhookDeliveryResult is the result stored on a delivered job.
+type webhookDeliveryResult struct {
+	StatusCode int `json:"status_code"`
+}
+
+// WebhookUseCase lets users register callback URLs (e.g., n8n or Home Assistant) for device events.
+// It listens on the realtime hub and turns device_offline/device_online, command_failed, alarm and
+// threshold crossings of reported values into JSON payloads, which are delivered as background jobs:
+// failed deliveries are retried with exponential backoff, and every request is signed with the
+// webhook's secret (HMAC-SHA256). Destinations pass the outbound guard. Webhooks belong to the API key or
+// user that registered them, and only receive events of devices that key may still access and that are
+// visible to its tenant (see DEVICE_CLAIMS_ENABLED).
+type WebhookUseCase struct {
+	cache       persistence.CacheStore
+	jobRunner   *job_services.JobRunnerService
+	realtimeHub *realtime_services.RealtimeHubService
+	guard       *outbound.Guard
+	claimUC     *tuya_usecases.DeviceClaimUseCase
+	authz       tuya_usecases.CallerAuthorizer
+	client      *http.Client
+	maxAttempts int
+	clock       utils.Clock
+	ids         utils.IDGenerator
+
+	mu       sync.Mutex
+	webhooks map[string]*entities.Webhook
+	loaded   bool
+	// thresholds remembers the state of each threshold per device: "{webhook_id}|{index}|{device_id}".
+	thresholds map[string]string
+
+	startOnce sync.Once
+	workers   utils.WorkerGroup
+}
+
+// NewWebhookUseCase initializes a new WebhookUseCase and registers its job type.
+// Delivery attempts and timeouts are read from WEBHOOK_MAX_ATTEMPTS and WEBHOOK_TIMEOUT.
+//
+// param cache The CacheStore used to persist webhooks.
+// param jobRunner The JobRunnerService persisting and retrying deliveries.
+// param realtimeHub The RealtimeHubService the events are read from.
+// param guard The outbound Guard validating and dialing webhook URLs.
+// param claimUC The DeviceClaimUseCase limiting events to the devices of the owner (optional).
+// param authz The CallerAuthorizer limiting events to the devices the owner's API key may access.
+// param clock The Clock used for timestamps and signatures.
+// param ids The IDGenerator used for webhook IDs, secrets and delivery IDs.
+// return *WebhookUseCase A pointer to the initialized usecase.
+func NewWebhookUseCase(cache persistence.CacheStore, jobRunner *job_services.JobRunnerService, realtimeHub *realtime_services.RealtimeHubService, guard *outbound.Guard, claimUC *tuya_usecases.DeviceClaimUseCase, authz tuya_usecases.CallerAuthorizer, clock utils.Clock, ids utils.IDGenerator) *WebhookUseCase {
+	config := utils.GetConfig()
+	maxAttempts, err := strconv.Atoi(config.WebhookMaxAttempts)
+	if err != nil || maxAttempts <= 0 {
+		maxAttempts = defaultWebhookMaxAttempts
+	}
+	timeout, err := time.ParseDuration(config.WebhookTimeout)
+	if err != nil || timeout <= 0 {
+		timeout = defaultWebhookTimeout
+	}
+
+	uc := &WebhookUseCase{
+		cache:       cache,
+		jobRunner:   jobRunner,
+		realtimeHub: realtimeHub,
+		guard:       guard,
+		claimUC:     claimUC,
+		authz:       authz,
+		client:      guard.HTTPClient(timeout),
+		maxAttempts: maxAttempts,
+		clock:       clock,
+		ids:         ids,
+		webhooks:    make(map[string]*entities.Webhook),
+		thresholds:  make(map[string]string),
+	}
+	jobRunner.Register(WebhookDeliveryJobType, uc.runDelivery)
+	return uc
+}
+
+// Start subscribes to the realtime hub and dispatches matching events to webhooks in the background.
+func (uc *WebhookUseCase) Start() {
+	uc.startOnce.Do(func() {
+		if err := uc.load(); err != nil {
+			utils.LogError("WebhookUseCase: Failed to load webhooks: %v", err)
+		}
+		client := uc.realtimeHub.Register(realtime_dtos.SubscriptionFilterDTO{})
+		uc.workers.Go(func(stop <-chan struct{}) {
+			defer uc.realtimeHub.Unregister(client)
+			for {
+				select {
+				case payload, ok := <-client.Send:
+					if !ok {
+						return
+					}
+					var event realtime_dtos.DeviceEventDTO
+					if err := json.Unmarshal(payload, &event); err != nil {
+						utils.LogWarn("WebhookUseCase: Skipping unreadable event: %v", err)
+						continue
+					}
+					uc.HandleEvent(event)
+				case <-stop:
+					return
+				}
+			}
+		})
+		utils.LogInfo("WebhookUseCase: Started")
+	})
+}
+
+// Stop ends event dispatching. Queued deliveries stay with the job runner.
+func (uc *WebhookUseCase) Stop() {
+	uc.workers.Stop()
+}
+
+// CreateWebhook registers a webhook for the caller. The signing secret is only returned here.
+//
+// param ctx The request context carrying the caller (see utils.APIKeyIdentityFromContext), bounding URL validation.
+// param uid The Tuya UID of the tenant whose claimed devices the webhook receives events for (empty for all devices).
+// param req The URL, events and filters.
+// return *dtos.WebhookDTO The webhook including its secret.
+// return error A bad request error for invalid input or refused destinations, a forbidden error without a caller, or a storage error.
+func (uc *WebhookUseCase) CreateWebhook(ctx context.Context, uid string, req dtos.WebhookRequestDTO) (*dtos.WebhookDTO, error) {
+	owner := utils.APIKeyIdentityFromContext(ctx)
+	if owner.Actor() == "" {
+		return nil, tuya_errors.Forbidden("webhooks can only be registered with an API key or identity token")
+	}
+	webhook, err := uc.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	count := 0
+	for _, existing := range uc.webhooks {
+		if existing.Owner.Actor() == owner.Actor() {
+			count++
+		}
+	}
+	if count >= maxWebhooksPerOwner {
+		return nil, tuya_errors.BadRequest("at most %d webhooks can be registered", maxWebhooksPerOwner)
+	}
+
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook ID: %w", err)
+	}
+	secret, err := uc.ids.NewID(32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	now := uc.clock.Now().Unix()
+	webhook.ID = id
+	webhook.Owner = owner
+	webhook.OwnerUID = uid
+	webhook.Secret = secret
+	webhook.CreatedAt = now
+	webhook.UpdatedAt = now
+	if err := uc.save(webhook); err != nil {
+		return nil, err
+	}
+	utils.LogInfo("WebhookUseCase: Webhook %s registered by %s for %v", id, owner.Actor(), webhook.Events)
+	dto := webhookToDTO(webhook)
+	dto.Secret = secret
+	return &dto, nil
+}
+
+// ListWebhooks returns the webhooks of the caller, newest first.
+//
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// return []dtos.WebhookDTO The webhooks, without secrets.
+// return error An error if the webhooks cannot be read.
+func (uc *WebhookUseCase) ListWebhooks(owner string) ([]dtos.WebhookDTO, error) {
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	result := make([]dtos.WebhookDTO, 0)
+	for _, webhook := range uc.webhooks {
+		if owner != "" && webhook.Owner.Actor() == owner {
+			result = append(result, webhookToDTO(webhook))
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CreatedAt > result[j].CreatedAt
+	})
+	return result, nil
+}
+
+// GetWebhook returns a webhook of the caller.
+//
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The webhook ID.
+// return *dtos.WebhookDTO The webhook, without its secret.
+// return error ErrWebhookNotFound, or a storage error.
+func (uc *WebhookUseCase) GetWebhook(owner, id string) (*dtos.WebhookDTO, error) {
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	webhook, err := uc.owned(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	dto := webhookToDTO(webhook)
+	return &dto, nil
+}
+
+// UpdateWebhook replaces the URL, events and filters of a webhook. The secret is kept.
+//
+// param ctx The request context, bounding URL validation.
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The webhook ID.
+// param req The new URL, events and filters.
+// return *dtos.WebhookDTO The updated webhook, without its secret.
+// return error ErrWebhookNotFound, a bad request error for invalid input, or a storage error.
+func (uc *WebhookUseCase) UpdateWebhook(ctx context.Context, owner, id string, req dtos.WebhookRequestDTO) (*dtos.WebhookDTO, error) {
+	updated, err := uc.validate(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	webhook, err := uc.owned(owner, id)
+	if err != nil {
+		return nil, err
+	}
+	updated.ID = webhook.ID
+	updated.Owner = webhook.Owner
+	updated.OwnerUID = webhook.OwnerUID
+	updated.Secret = webhook.Secret
+	updated.CreatedAt = webhook.CreatedAt
+	updated.UpdatedAt = uc.clock.Now().Unix()
+	updated.LastDeliveryAt = webhook.LastDeliveryAt
+	updated.LastStatusCode = webhook.LastStatusCode
+	updated.LastError = webhook.LastError
+	if err := uc.save(updated); err != nil {
+		return nil, err
+	}
+	uc.resetThresholds(id)
+	dto := webhookToDTO(updated)
+	return &dto, nil
+}
+
+// DeleteWebhook removes a webhook of the caller. Queued deliveries to it are dropped.
+//
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The webhook ID.
+// return error ErrWebhookNotFound, or a storage error.
+func (uc *WebhookUseCase) DeleteWebhook(owner, id string) error {
+	if err := uc.load(); err != nil {
+		return err
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if _, err := uc.owned(owner, id); err != nil {
+		return err
+	}
+	if err := uc.cache.Delete(webhookPrefix + id); err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	delete(uc.webhooks, id)
+	uc.resetThresholds(id)
+	utils.LogInfo("WebhookUseCase: Webhook %s deleted by %s", id, owner)
+	return nil
+}
+
+// TestWebhook sends a signed ping event to a webhook right away, without retries.
+//
+// param ctx The request context.
+// param owner The caller (see utils.APIKeyIdentity.Actor).
+// param id The webhook ID.
+// return *dtos.WebhookTestResultDTO The outcome of the delivery.
+// return error ErrWebhookNotFound, or a storage error.
+func (uc *WebhookUseCase) TestWebhook(ctx context.Context, owner, id string) (*dtos.WebhookTestResultDTO, error) {
+	if err := uc.load(); err != nil {
+		return nil, err
+	}
+
+	uc.mu.Lock()
+	webhook, err := uc.owned(owner, id)
+	var copied entities.Webhook
+	if err == nil {
+		copied = *webhook
+	}
+	uc.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	payload, err := uc.newPayload(&copied, entities.WebhookEventPing, "", "", map[string]interface{}{"message": "Webhook test from Teralux"})
+	if err != nil {
+		return nil, err
+	}
+	start := uc.clock.Now()
+	statusCode, deliverErr := uc.deliver(ctx, &copied, payload)
+	uc.recordDelivery(id, statusCode, deliverErr)
+
+	result := &dtos.WebhookTestResultDTO{
+		Delivered:  deliverErr == nil,
+		StatusCode: statusCode,
+		DurationMs: uc.clock.Now().Sub(start).Milliseconds(),
+	}
+	if deliverErr != nil {
+		result.Error = deliverErr.Error()
+	}
+	return result, nil
+}
+
+// HandleEvent matches a realtime event against the registered webhooks and queues the deliveries.
+//
+// param event The device event published on the realtime hub.
+func (uc *WebhookUseCase) HandleEvent(event realtime_dtos.DeviceEventDTO) {
+	if event.DeviceID == "" {
+		return
+	}
+
+	uc.mu.Lock()
+	var pending []*dtos.WebhookPayloadDTO
+	var targets []*entities.Webhook
+	visibility := make(map[string]tuya_usecases.DeviceFilter)
+	for _, webhook := range uc.webhooks {
+		if !webhook.Enabled || !matchesDevice(webhook, event.DeviceID) || !uc.ownerMayAccess(webhook.Owner, event.DeviceID) {
+			continue
+		}
+		visible, ok := visibility[webhook.OwnerUID]
+		if !ok && uc.claimUC != nil && webhook.OwnerUID != "" {
+			visible = uc.claimUC.VisibleTo(webhook.OwnerUID)
+			visibility[webhook.OwnerUID] = visible
+		}
+		if visible != nil && !visible(event.DeviceID) {
+			continue
+		}
+
+		for _, match := range uc.match(webhook, event) {
+			payload, err := uc.newPayload(webhook, match.event, event.DeviceID, event.Category, match.data)
+			if err != nil {
+				utils.LogWarn("WebhookUseCase: Failed to build %s payload for webhook %s: %v", match.event, webhook.ID, err)
+				continue
+			}
+			pending = append(pending, payload)
+			targets = append(targets, webhook)
+		}
+	}
+	uc.mu.Unlock()
+
+	for i, payload := range pending {
+		if _, err := uc.jobRunner.Enqueue(context.Background(), WebhookDeliveryJobType, webhookDeliveryPayload{WebhookID: targets[i].ID, Payload: *payload}, uc.maxAttempts); err != nil {
+			utils.LogError("WebhookUseCase: Failed to queue %s delivery for webhook %s: %v", payload.Event, targets[i].ID, err)
+		}
+	}
+}
+
+// webhookMatch is an event to deliver to a webhook.
+type webhookMatch struct {
+	event string
+	data  interface{}
+}
+
+// match returns the webhook events triggered by a realtime event. The caller holds uc.mu.
+func (uc *WebhookUseCase) match(webhook *entities.Webhook, event realtime_dtos.DeviceEventDTO) []webhookMatch {
+	switch event.Type {
+	case entities.WebhookEventDeviceOffline, entities.WebhookEventDeviceOnline:
+		if containsString(webhook.Events, event.Type) {
+			return []webhookMatch{{event: event.Type}}
+		}
+	case entities.WebhookEventCommandFailed:
+		if containsString(webhook.Events, event.Type) {
+			return []webhookMatch{{event: event.Type, data: map[string]interface{}{
+				"commands": event.Status,
+				"error":    event.Error,
+			}}}
+		}
+	case "alarm", "alarm_cleared":
+		if containsString(webhook.Events, entities.WebhookEventAlarm) {
+			data := map[string]interface{}{"simulated": event.Simulated}
+			for _, status := range event.Status {
+				data[status.Code] = status.Value
+			}
+			return []webhookMatch{{event: entities.WebhookEventAlarm, data: data}}
+		}
+	case "device_state":
+		if containsString(webhook.Events, entities.WebhookEventSensorThreshold) {
+			return uc.matchThresholds(webhook, event)
+		}
+	}
+	return nil
+}
+
+// matchThresholds evaluates the thresholds of a webhook against reported values and returns the crossings,
+// including the return to normal. The first value seen within the thresholds is not reported. The caller holds uc.mu.
+func (uc *WebhookUseCase) matchThresholds(webhook *entities.Webhook, event realtime_dtos.DeviceEventDTO) []webhookMatch {
+	var matches []webhookMatch
+	for i, threshold := range webhook.Thresholds {
+		if threshold.DeviceID != "" && threshold.DeviceID != event.DeviceID {
+			continue
+		}
+		for _, status := range event.Status {
+			if status.Code != threshold.Code {
+				continue
+			}
+			value, ok := numericValue(status.Value)
+			if !ok {
+				continue
+			}
+			state := thresholdNormal
+			if threshold.Above != nil && value > *threshold.Above {
+				state = "above"
+			} else if threshold.Below != nil && value < *threshold.Below {
+				state = "below"
+			}
+
+			key := fmt.Sprintf("%s|%d|%s", webhook.ID, i, event.DeviceID)
+			previous, seen := uc.thresholds[key]
+			if !seen {
+				previous = thresholdNormal
+			}
+			uc.thresholds[key] = state
+			if state == previous {
+				continue
+			}
+			matches = append(matches, webhookMatch{event: entities.WebhookEventSensorThreshold, data: map[string]interface{}{
+				"code":           threshold.Code,
+				"value":          value,
+				"state":          state,
+				"previous_state": previous,
+				"above":          threshold.Above,
+				"below":          threshold.Below,
+			}})
+		}
+	}
+	return matches
+}
+
+// runDelivery is the job handler posting one payload to its webhook.
+func (uc *WebhookUseCase) runDelivery(ctx context.Context, job *job_services.JobContext) (interface{}, error) {
+	var delivery webhookDeliveryPayload
+	if err := job.DecodePayload(&delivery); err != nil {
+		return nil, job_services.Permanent(err)
+	}
+
+	uc.mu.Lock()
+	webhook, ok := uc.webhooks[delivery.WebhookID]
+	var copied entities.Webhook
+	if ok {
+		copied = *webhook
+	}
+	uc.mu.Unlock()
+	if !ok || !copied.Enabled {
+		return nil, job_services.Permanent(ErrWebhookNotFound)
+	}
+
+	statusCode, err := uc.deliver(ctx, &copied, &delivery.Payload)
+	uc.recordDelivery(copied.ID, statusCode, err)
+	if err != nil {
+		// Client errors other than timeouts and rate limits will not change on retry
+		if statusCode >= 400 && statusCode < 500 && statusCode != http.StatusRequestTimeout && statusCode != http.StatusTooManyRequests {
+			return webhookDeliveryResult{StatusCode: statusCode}, job_services.Permanent(err)
+		}
+		if errors.Is(err, outbound.ErrDestinationNotAllowed) {
+			return nil, job_services.Permanent(err)
+		}
+		return webhookDeliveryResult{StatusCode: statusCode}, err
+	}
+	return webhookDeliveryResult{StatusCode: statusCode}, nil
+}
+
+// deliver posts a signed payload to a webhook.
+func (uc *WebhookUseCase) deliver(ctx context.Context, webhook *entities.Webhook, payload *dtos.WebhookPayloadDTO) (int, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal webhook payload: %w", err)
+	}
+	timestamp := strconv.FormatInt(uc.clock.Now().Unix(), 10)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Teralux-Webhook/1.0")
+	req.Header.Set("X-Teralux-Event", payload.Event)
+	req.Header.Set("X-Teralux-Delivery", payload.ID)
+	req.Header.Set("X-Teralux-Timestamp", timestamp)
+	req.Header.Set("X-Teralux-Signature", "sha256="+signWebhookPayload(webhook.Secret, timestamp, body))
+
+	resp, err := uc.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// recordDelivery stores the outcome of the latest delivery on the webhook.
+func (uc *WebhookUseCase) recordDelivery(id string, statusCode int, deliverErr error) {
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	webhook, ok := uc.webhooks[id]
+	if !ok {
+		return
+	}
+	webhook.LastDeliveryAt = uc.clock.Now().Unix()
+	webhook.LastStatusCode = statusCode
+	webhook.LastError = ""
+	if deliverErr != nil {
+		webhook.LastError = deliverErr.Error()
+	}
+	if err := uc.save(webhook); err != nil {
+		utils.LogWarn("WebhookUseCase: Failed to record delivery of webhook %s: %v", id, err)
+	}
+}
+
+// newPayload builds the body of a delivery.
+func (uc *WebhookUseCase) newPayload(webhook *entities.Webhook, event, deviceID, category string, data interface{}) (*dtos.WebhookPayloadDTO, error) {
+	id, err := uc.ids.NewID(8)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate delivery ID: %w", err)
+	}
+	return &dtos.WebhookPayloadDTO{
+		ID:        id,
+		Event:     event,
+		WebhookID: webhook.ID,
+		DeviceID:  deviceID,
+		Category:  category,
+		Data:      data,
+		Timestamp: uc.clock.Now().Unix(),
+	}, nil
+}
+
+// validate checks a webhook request and converts it into an entity without ID, owner and secret.
+func (uc *WebhookUseCase) validate(ctx context.Context, req dtos.WebhookRequestDTO) (*entities.Webhook, error) {
+	if err := uc.guard.ValidateURL(ctx, req.URL, "https", "http"); err != nil {
+		return nil, tuya_errors.BadRequest("%w", err)
+	}
+
+	events := make([]string, 0, len(req.Events))
+	for _, event := range req.Events {
+		event = strings.TrimSpace(event)
+		if !containsString(subscribableEvents, event) {
+			return nil, tuya_errors.BadRequest("unknown event %q (supported: %s)", event, strings.Join(subscribableEvents, ", "))
+		}
+		if !containsString(events, event) {
+			events = append(events, event)
+		}
+	}
+
+	thresholds := make([]entities.SensorThreshold, 0, len(req.Thresholds))
+	for _, threshold := range req.Thresholds {
+		if threshold.Above == nil && threshold.Below == nil {
+			return nil, tuya_errors.BadRequest("threshold for %s needs above or below", threshold.Code)
+		}
+		if threshold.Above != nil && threshold.Below != nil && *threshold.Below > *threshold.Above {
+			return nil, tuya_errors.BadRequest("threshold for %s has below greater than above", threshold.Code)
+		}
+		thresholds = append(thresholds, entities.SensorThreshold{
+			DeviceID: strings.TrimSpace(threshold.DeviceID),
+			Code:     strings.TrimSpace(threshold.Code),
+			Above:    threshold.Above,
+			Below:    threshold.Below,
+		})
+	}
+	if containsString(events, entities.WebhookEventSensorThreshold) != (len(thresholds) > 0) {
+		return nil, tuya_errors.BadRequest("thresholds are required for, and only allowed with, the sensor_threshold event")
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+	return &entities.Webhook{
+		Name:       strings.TrimSpace(req.Name),
+		URL:        req.URL,
+		Events:     events,
+		DeviceIDs:  req.DeviceIDs,
+		Thresholds: thresholds,
+		Enabled:    enabled,
+	}, nil
+}
+
+// owned returns a webhook of the caller. The caller holds uc.mu.
+func (uc *WebhookUseCase) owned(owner, id string) (*entities.Webhook, error) {
+	webhook, ok := uc.webhooks[id]
+	if !ok || owner == "" || webhook.Owner.Actor() != owner {
+		return nil, ErrWebhookNotFound
+	}
+	return webhook, nil
+}
+
+// ownerMayAccess reports whether the owner of a webhook still holds control scope and may access a device.
+// Webhooks of revoked keys stop receiving events.
+func (uc *WebhookUseCase) ownerMayAccess(owner utils.APIKeyIdentity, deviceID string) bool {
+	current, ok := uc.authz.CurrentScope(owner)
+	if !ok || !utils.APIKeyScopeAllows(current, utils.APIKeyScopeControl) {
+		return false
+	}
+	return owner.ID == "" || uc.authz.CanAccessDevice(owner.ID, deviceID)
+}
+
+// resetThresholds forgets the threshold states of a webhook. The caller holds uc.mu.
+func (uc *WebhookUseCase) resetThresholds(id string) {
+	for key := range uc.thresholds {
+		if strings.HasPrefix(key, id+"|") {
+			delete(uc.thresholds, key)
+		}
+	}
+}
+
+// load reads the stored webhooks once.
+func (uc *WebhookUseCase) load() error {
+	if uc.cache == nil {
+		return fmt.Errorf("webhook storage not initialized")
+	}
+
+	uc.mu.Lock()
+	defer uc.mu.Unlock()
+
+	if uc.loaded {
+		return nil
+	}
+	keys, err := uc.cache.GetAllKeysWithPrefix(webhookPrefix)
+	if err != nil {
+		return fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	for _, key := range keys {
+		data, err := uc.cache.Get(key)
+		if err != nil || data == nil {
+			continue
+		}
+		var webhook entities.Webhook
+		if err := json.Unmarshal(data, &webhook); err != nil {
+			utils.LogWarn("WebhookUseCase: Skipping unreadable webhook %s: %v", key, err)
+			continue
+		}
+		uc.webhooks[webhook.ID] = &webhook
+	}
+	uc.loaded = true
+	return nil
+}
+
+// save persists a webhook and updates the in-memory copy. The caller holds uc.mu.
+func (uc *WebhookUseCase) save(webhook *entities.Webhook) error {
+	data, err := json.Marshal(webhook)
+	if err != nil {
+		return fmt.Errorf("failed to marshal webhook: %w", err)
+	}
+	if err := uc.cache.SetPersistent(webhookPrefix+webhook.ID, data); err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+	uc.webhooks[webhook.ID] = webhook
+	return nil
+}
+
+// signWebhookPayload returns the hex HMAC-SHA256 of "{timestamp}.{body}" keyed with the webhook secret.
+func signWebhookPayload(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// matchesDevice reports whether a webhook receives events of a device.
+func matchesDevice(webhook *entities.Webhook, deviceID string) bool {
+	return len(webhook.DeviceIDs) == 0 || containsString(webhook.DeviceIDs, deviceID)
+}
+
+// numericValue converts a reported DP value into a number.
+func numericValue(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
+
+// containsString reports whether value is present in list.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// webhookToDTO converts a webhook into its API representation, without the secret.
+func webhookToDTO(webhook *entities.Webhook) dtos.WebhookDTO {
+	thresholds := make([]dtos.SensorThresholdDTO, len(webhook.Thresholds))
+	for i, threshold := range webhook.Thresholds {
+		thresholds[i] = dtos.SensorThresholdDTO{
+			DeviceID: threshold.DeviceID,
+			Code:     threshold.Code,
+			Above:    threshold.Above,
+			Below:    threshold.Below,
+		}
+	}
+	return dtos.WebhookDTO{
+		ID:             webhook.ID,
+		Name:           webhook.Name,
+		URL:            webhook.URL,
+		Events:         webhook.Events,
+		DeviceIDs:      webhook.DeviceIDs,
+		Thresholds:     thresholds,
+		Enabled:        webhook.Enabled,
+		CreatedAt:      webhook.CreatedAt,
+		UpdatedAt:      webhook.UpdatedAt,
+		LastDeliveryAt: webhook.LastDeliveryAt,
+		LastStatusCode: webhook.LastStatusCode,
+		LastError:      webhook.LastError,
+	}
+}
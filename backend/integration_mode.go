@@ -0,0 +1,37 @@
+//go:build integration
+
+package main
+
+import (
+	"os"
+	"teralux_app/domain/common/utils"
+	"teralux_app/internal/faketuya"
+)
+
+// integrationTestAPIKey is the API key accepted in the integration test mode when API_KEY is not set.
+const integrationTestAPIKey = "integration-test-key"
+
+// startIntegrationTestMode prepares INTEGRATION_TEST_MODE: it starts the fake Tuya OpenAPI on a loopback
+// port and points the Tuya configuration at it, so the full router can be tested black-box (in CI, or with
+// "INTEGRATION_TEST_MODE=true go run -tags integration .") without a Tuya cloud project. run also skips MySQL
+// and opens BadgerDB in memory, so every start is clean. The harness is only compiled with the integration
+// build tag, so a production binary cannot be pointed at the fake cloud.
+// With INTEGRATION_TEST_FRESH_INSTALL=true, TUYA_USER_ID and API_KEY are left unset, so the install starts
+// in setup.
+//
+// param config The loaded configuration, updated in place.
+// return func() Stops the fake Tuya OpenAPI.
+func startIntegrationTestMode(config *utils.Config) func() {
+	fake := faketuya.NewServer()
+	config.TuyaBaseURL = fake.URL()
+	config.TuyaClientID = faketuya.ClientID
+	config.TuyaClientSecret = faketuya.ClientSecret
+	if os.Getenv("INTEGRATION_TEST_FRESH_INSTALL") != "true" {
+		config.TuyaUserID = faketuya.UID
+		if config.ApiKey == "" {
+			config.ApiKey = integrationTestAPIKey
+		}
+	}
+	utils.LogWarn("INTEGRATION_TEST_MODE: fake Tuya OpenAPI at %s, in-memory BadgerDB, no MySQL, API key %q", fake.URL(), config.ApiKey)
+	return fake.Close
+}
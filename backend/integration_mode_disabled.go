@@ -0,0 +1,14 @@
+//go:build !integration
+
+package main
+
+import "teralux_app/domain/common/utils"
+
+// startIntegrationTestMode is not available without the integration build tag: the configuration never enables
+// INTEGRATION_TEST_MODE in such a build, so run does not call it.
+//
+// param config The loaded configuration.
+// return func() A no-op.
+func startIntegrationTestMode(config *utils.Config) func() {
+	return func() {}
+}
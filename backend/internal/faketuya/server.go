@@ -0,0 +1,517 @@
+// Package faketuya provides an in-process fake of the Tuya OpenAPI for integration tests.
+// It serves the token, device, asset, status, specification, command and IR endpoints the backend uses,
+// keeps device status in memory (commands change it), and answers any other path like Tuya answers an
+// unknown URI, so black-box tests can run the real wiring without a cloud project.
+package faketuya
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"teralux_app/domain/tuya/entities"
+	"time"
+)
+
+// Credentials and identifiers issued by the fake.
+const (
+	ClientID     = "fake-client-id"
+	ClientSecret = "fake-client-secret"
+	UID          = "fake-uid"
+	AssetID      = "fake-asset"
+	AccessToken  = "fake-access-token"
+	RefreshToken = "fake-refresh-token"
+	LearnedCode  = "fake-learned-ir-code"
+)
+
+// Tuya error codes returned by the fake.
+const (
+	codeTokenInvalid = 1010
+	codeURIInvalid   = 1108
+	codeDeviceAbsent = 2001
+	codeParamInvalid = 1109
+)
+
+// Server is a running fake Tuya OpenAPI.
+type Server struct {
+	server *httptest.Server
+
+	mu       sync.Mutex
+	devices  map[string]*entities.TuyaDevice
+	order    []string
+	commands map[string][]entities.TuyaCommand
+}
+
+// NewServer starts a fake Tuya OpenAPI on a loopback port, seeded with DefaultDevices.
+//
+// return *Server A pointer to the running server; Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		devices:  make(map[string]*entities.TuyaDevice),
+		commands: make(map[string][]entities.TuyaCommand),
+	}
+	for _, device := range DefaultDevices() {
+		device := device
+		s.devices[device.ID] = &device
+		s.order = append(s.order, device.ID)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1.0/token", s.handleToken)
+	mux.HandleFunc("GET /v1.0/token/{refresh}", s.handleToken)
+	mux.HandleFunc("GET /v1.0/users/{uid}/devices", s.authorized(s.handleListDevices))
+	mux.HandleFunc("GET /v1.0/devices/{id}", s.authorized(s.handleGetDevice))
+	mux.HandleFunc("GET /v1.0/iot-03/devices/{id}", s.authorized(s.handleGetDevice))
+	mux.HandleFunc("GET /v1.0/iot-03/devices", s.authorized(s.handleDevicePage))
+	mux.HandleFunc("GET /v1.0/iot-03/devices/status", s.authorized(s.handleBatchStatus))
+	mux.HandleFunc("GET /v1.0/devices/status", s.authorized(s.handleBatchStatus))
+	mux.HandleFunc("GET /v1.0/iot-02/assets", s.authorized(s.handleAssets))
+	mux.HandleFunc("GET /v1.0/iot-02/assets/{id}/devices", s.authorized(s.handleAssetDevices))
+	mux.HandleFunc("GET /v1.0/iot-03/devices/{id}/specification", s.authorized(s.handleSpecification))
+	mux.HandleFunc("POST /v1.0/devices/{id}/commands", s.authorized(s.handleCommands))
+	mux.HandleFunc("POST /v1.0/iot-03/devices/{id}/commands", s.authorized(s.handleCommands))
+	mux.HandleFunc("GET /v2.0/infrareds/{id}/remotes", s.authorized(s.handleIRRemotes))
+	mux.HandleFunc("POST /v2.0/infrareds/{id}/air-conditioners/{remote}/command", s.authorized(s.handleIRACCommand))
+	mux.HandleFunc("POST /v2.0/infrareds/{id}/air-conditioners/{remote}/scenes/command", s.authorized(s.handleIRACScene))
+	mux.HandleFunc("GET /v2.0/infrareds/{id}/categories", s.authorized(s.handleIRCategories))
+	mux.HandleFunc("GET /v2.0/infrareds/{id}/categories/{category}/brands", s.authorized(s.handleIRBrands))
+	mux.HandleFunc("GET /v2.0/infrareds/{id}/categories/{category}/brands/{brand}/remote-indexs", s.authorized(s.handleIRRemoteIndexes))
+	mux.HandleFunc("POST /v2.0/infrareds/{id}/normal/add-remote", s.authorized(s.handleIRAddRemote))
+	mux.HandleFunc("PUT /v2.0/infrareds/{id}/learning-state", s.authorized(s.handleLearningState))
+	mux.HandleFunc("GET /v2.0/infrareds/{id}/learning-codes", s.authorized(s.handleLearnedCode))
+	mux.HandleFunc("POST /v1.0/infrareds/{id}/learning-codes", s.authorized(s.handleSendLearnedCode))
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeFailure(w, codeURIInvalid, "uri path invalid")
+	})
+
+	s.server = httptest.NewServer(mux)
+	return s
+}
+
+// URL returns the base URL to use as TUYA_BASE_URL.
+//
+// return string The base URL, e.g. http://127.0.0.1:41234.
+func (s *Server) URL() string {
+	return s.server.URL
+}
+
+// Close stops the server.
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// Commands returns the commands a device received, in order. IR AC commands are recorded on the remote.
+//
+// param deviceID The device ID.
+// return []entities.TuyaCommand The received commands.
+func (s *Server) Commands(deviceID string) []entities.TuyaCommand {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]entities.TuyaCommand(nil), s.commands[deviceID]...)
+}
+
+// DefaultDevices returns the devices the fake starts with: a switch, a dimmable light, and an IR hub with
+// an air conditioner remote.
+//
+// return []entities.TuyaDevice The devices.
+func DefaultDevices() []entities.TuyaDevice {
+	now := time.Now().Unix()
+	return []entities.TuyaDevice{
+		{
+			ID: "fake-switch-1", Name: "Living Room Switch", UID: UID, Category: "kg", ProductName: "Wi-Fi Switch",
+			Online: true, ActiveTime: now, CreateTime: now, UpdateTime: now, LocalKey: "fakelocalkey0001", IP: "203.0.113.10",
+			Status: []entities.TuyaDeviceStatus{{Code: "switch_1", Value: false}, {Code: "countdown_1", Value: 0}},
+			Functions: []entities.TuyaDeviceFunction{
+				{Code: "switch_1", Type: "Boolean", Values: "{}"},
+				{Code: "countdown_1", Type: "Integer", Values: `{"unit":"s","min":0,"max":86400,"scale":0,"step":1}`},
+			},
+		},
+		{
+			ID: "fake-light-1", Name: "Bedroom Light", UID: UID, Category: "dj", ProductName: "Smart Bulb",
+			Online: true, ActiveTime: now, CreateTime: now, UpdateTime: now, LocalKey: "fakelocalkey0002", IP: "203.0.113.11",
+			Status: []entities.TuyaDeviceStatus{{Code: "switch_led", Value: false}, {Code: "bright_value_v2", Value: 500}},
+			Functions: []entities.TuyaDeviceFunction{
+				{Code: "switch_led", Type: "Boolean", Values: "{}"},
+				{Code: "bright_value_v2", Type: "Integer", Values: `{"min":10,"max":1000,"scale":0,"step":1}`},
+			},
+		},
+		{
+			ID: "fake-ir-hub-1", Name: "IR Hub", UID: UID, Category: "wnykq", ProductName: "IR Remote Hub",
+			Online: true, ActiveTime: now, CreateTime: now, UpdateTime: now, LocalKey: "fakelocalkey0003", IP: "203.0.113.12",
+		},
+		{
+			ID: "fake-ir-ac-1", Name: "Bedroom AC", RemoteName: "Bedroom AC", UID: UID, Category: "infrared_ac",
+			ProductName: "Air Conditioner", Sub: true, GatewayID: "fake-ir-hub-1",
+			Online: true, ActiveTime: now, CreateTime: now, UpdateTime: now, LocalKey: "fakelocalkey0003",
+			Status: []entities.TuyaDeviceStatus{{Code: "power", Value: 0}, {Code: "temp", Value: 24}, {Code: "mode", Value: 0}},
+		},
+	}
+}
+
+// authorized rejects requests without the issued access token, like Tuya rejects an expired token.
+func (s *Server) authorized(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("access_token") != AccessToken || r.Header.Get("client_id") != ClientID {
+			writeFailure(w, codeTokenInvalid, "token invalid")
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (s *Server) handleToken(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("client_id") != ClientID {
+		writeFailure(w, codeTokenInvalid, "clientId is invalid")
+		return
+	}
+	writeResult(w, entities.TuyaAuthResult{
+		AccessToken:  AccessToken,
+		ExpireTime:   7200,
+		RefreshToken: RefreshToken,
+		UID:          UID,
+	})
+}
+
+func (s *Server) handleListDevices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	devices := make([]entities.TuyaDevice, 0, len(s.order))
+	if r.PathValue("uid") == UID {
+		for _, id := range s.order {
+			devices = append(devices, *s.devices[id])
+		}
+	}
+	writeResult(w, devices)
+}
+
+// handleDevicePage serves the IoT Core device list of UID, or the devices named by device_ids;
+// last_row_key is the index of the next device.
+func (s *Server) handleDevicePage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	query := r.URL.Query()
+	var ids []string
+	if deviceIDs := query.Get("device_ids"); deviceIDs != "" {
+		for _, id := range strings.Split(deviceIDs, ",") {
+			if _, ok := s.devices[id]; ok {
+				ids = append(ids, id)
+			}
+		}
+	} else if query.Get("source_type") == "tuyaUser" && query.Get("source_id") == UID {
+		for _, id := range s.order {
+			if category := query.Get("category"); category == "" || s.devices[id].Category == category {
+				ids = append(ids, id)
+			}
+		}
+	}
+	pageSize, err := strconv.Atoi(query.Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 20
+	}
+	start, _ := strconv.Atoi(query.Get("last_row_key"))
+	if start < 0 || start > len(ids) {
+		start = len(ids)
+	}
+	end := start + pageSize
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	page := entities.TuyaDevicePage{List: []entities.TuyaDevice{}, Total: len(ids), HasMore: end < len(ids)}
+	for _, id := range ids[start:end] {
+		page.List = append(page.List, *s.devices[id])
+	}
+	if page.HasMore {
+		page.LastRowKey = strconv.Itoa(end)
+	}
+	writeResult(w, page)
+}
+
+// handleAssets serves the asset list: AssetID, holding all devices.
+func (s *Server) handleAssets(w http.ResponseWriter, r *http.Request) {
+	assets := []entities.TuyaAsset{}
+	if name := r.URL.Query().Get("asset_name"); strings.Contains("Fake Building", name) {
+		assets = append(assets, entities.TuyaAsset{AssetID: AssetID, AssetName: "Fake Building"})
+	}
+	writeResult(w, entities.TuyaAssetPage{List: assets, Total: len(assets)})
+}
+
+// handleAssetDevices serves the device IDs of AssetID; last_row_key is the index of the next device.
+func (s *Server) handleAssetDevices(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var ids []string
+	if r.PathValue("id") == AssetID {
+		ids = s.order
+	}
+	pageSize, err := strconv.Atoi(r.URL.Query().Get("page_size"))
+	if err != nil || pageSize < 1 {
+		pageSize = 20
+	}
+	start, _ := strconv.Atoi(r.URL.Query().Get("last_row_key"))
+	if start < 0 || start > len(ids) {
+		start = len(ids)
+	}
+	end := start + pageSize
+	if end > len(ids) {
+		end = len(ids)
+	}
+
+	page := entities.TuyaAssetDevicePage{List: []entities.TuyaAssetDevice{}, HasNext: end < len(ids), PageSize: pageSize}
+	for _, id := range ids[start:end] {
+		page.List = append(page.List, entities.TuyaAssetDevice{AssetID: AssetID, DeviceID: id})
+	}
+	if page.HasNext {
+		page.LastRowKey = strconv.Itoa(end)
+	}
+	writeResult(w, page)
+}
+
+func (s *Server) handleGetDevice(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[r.PathValue("id")]
+	if !ok {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, *device)
+}
+
+func (s *Server) handleBatchStatus(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var items []entities.TuyaDeviceStatusItem
+	for _, id := range strings.Split(r.URL.Query().Get("device_ids"), ",") {
+		if device, ok := s.devices[id]; ok {
+			items = append(items, entities.TuyaDeviceStatusItem{ID: id, IsOnline: device.Online, Status: device.Status})
+		}
+	}
+	writeResult(w, items)
+}
+
+func (s *Server) handleSpecification(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[r.PathValue("id")]
+	if !ok {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, entities.TuyaDeviceSpecification{
+		Category:  device.Category,
+		Functions: device.Functions,
+		Status:    device.Functions,
+	})
+}
+
+func (s *Server) handleCommands(w http.ResponseWriter, r *http.Request) {
+	var req entities.TuyaCommandRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || len(req.Commands) == 0 {
+		writeFailure(w, codeParamInvalid, "param is illegal")
+		return
+	}
+	if !s.apply(r.PathValue("id"), req.Commands) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, true)
+}
+
+func (s *Server) handleIRRemotes(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	remotes := []entities.TuyaIRRemote{}
+	for _, id := range s.order {
+		device := s.devices[id]
+		if device.GatewayID == r.PathValue("id") {
+			remotes = append(remotes, entities.TuyaIRRemote{RemoteID: device.ID, RemoteName: device.RemoteName, CategoryID: 5})
+		}
+	}
+	writeResult(w, remotes)
+}
+
+func (s *Server) handleIRACCommand(w http.ResponseWriter, r *http.Request) {
+	var command entities.TuyaCommand
+	if err := json.NewDecoder(r.Body).Decode(&command); err != nil || command.Code == "" {
+		writeFailure(w, codeParamInvalid, "param is illegal")
+		return
+	}
+	if !s.apply(r.PathValue("remote"), []entities.TuyaCommand{command}) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, true)
+}
+
+func (s *Server) handleIRACScene(w http.ResponseWriter, r *http.Request) {
+	var state map[string]int
+	if err := json.NewDecoder(r.Body).Decode(&state); err != nil || len(state) == 0 {
+		writeFailure(w, codeParamInvalid, "param is illegal")
+		return
+	}
+	commands := make([]entities.TuyaCommand, 0, len(state))
+	for _, code := range []string{"power", "mode", "temp", "wind"} {
+		if value, ok := state[code]; ok {
+			commands = append(commands, entities.TuyaCommand{Code: code, Value: value})
+		}
+	}
+	if !s.apply(r.PathValue("remote"), commands) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, true)
+}
+
+// handleIRCategories lists a TV and an AC category, with string IDs like the real API.
+func (s *Server) handleIRCategories(w http.ResponseWriter, r *http.Request) {
+	if !s.exists(r.PathValue("id")) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, []map[string]string{
+		{"category_id": "2", "category_name": "TV"},
+		{"category_id": "5", "category_name": "Air Conditioner"},
+	})
+}
+
+func (s *Server) handleIRBrands(w http.ResponseWriter, r *http.Request) {
+	if !s.exists(r.PathValue("id")) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, []map[string]string{
+		{"brand_id": "33", "brand_name": "Samsung"},
+		{"brand_id": "97", "brand_name": "LG"},
+	})
+}
+
+func (s *Server) handleIRRemoteIndexes(w http.ResponseWriter, r *http.Request) {
+	if !s.exists(r.PathValue("id")) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, []map[string]string{{"remote_index": "1234"}, {"remote_index": "1235"}})
+}
+
+// handleIRAddRemote adds the remote as a sub-device of the hub, like the real cloud does.
+func (s *Server) handleIRAddRemote(w http.ResponseWriter, r *http.Request) {
+	var req entities.TuyaIRAddRemoteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.RemoteName == "" || req.RemoteIndex == 0 {
+		writeFailure(w, codeParamInvalid, "param is illegal")
+		return
+	}
+	hubID := r.PathValue("id")
+	if !s.exists(hubID) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+
+	s.mu.Lock()
+	now := time.Now().Unix()
+	remoteID := "fake-remote-" + strconv.Itoa(len(s.order)+1)
+	s.devices[remoteID] = &entities.TuyaDevice{
+		ID: remoteID, Name: req.RemoteName, RemoteName: req.RemoteName, UID: UID, Category: "infrared_tv",
+		ProductName: req.BrandName, Sub: true, GatewayID: hubID,
+		Online: true, ActiveTime: now, CreateTime: now, UpdateTime: now,
+	}
+	s.order = append(s.order, remoteID)
+	s.mu.Unlock()
+	writeResult(w, map[string]string{"remote_id": remoteID})
+}
+
+func (s *Server) handleLearningState(w http.ResponseWriter, r *http.Request) {
+	if !s.exists(r.PathValue("id")) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, true)
+}
+
+// handleLearnedCode reports LearnedCode as captured, as if a button was pressed right after learning started.
+func (s *Server) handleLearnedCode(w http.ResponseWriter, r *http.Request) {
+	if !s.exists(r.PathValue("id")) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	writeResult(w, entities.TuyaIRLearnedCode{Success: true, Code: LearnedCode})
+}
+
+// handleSendLearnedCode records a sent raw code as a "learned_code" command of the hub.
+func (s *Server) handleSendLearnedCode(w http.ResponseWriter, r *http.Request) {
+	var req entities.TuyaIRSendCodeRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Code == "" {
+		writeFailure(w, codeParamInvalid, "param is illegal")
+		return
+	}
+	id := r.PathValue("id")
+	if !s.exists(id) {
+		writeFailure(w, codeDeviceAbsent, "device is offline")
+		return
+	}
+	s.mu.Lock()
+	s.commands[id] = append(s.commands[id], entities.TuyaCommand{Code: "learned_code", Value: req.Code})
+	s.mu.Unlock()
+	writeResult(w, true)
+}
+
+// exists reports whether a device is known.
+func (s *Server) exists(deviceID string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, ok := s.devices[deviceID]
+	return ok
+}
+
+// apply records commands and updates the device status; it reports false for unknown devices.
+func (s *Server) apply(deviceID string, commands []entities.TuyaCommand) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[deviceID]
+	if !ok {
+		return false
+	}
+	for _, command := range commands {
+		updated := false
+		for i := range device.Status {
+			if device.Status[i].Code == command.Code {
+				device.Status[i].Value = command.Value
+				updated = true
+			}
+		}
+		if !updated {
+			device.Status = append(device.Status, entities.TuyaDeviceStatus{Code: command.Code, Value: command.Value})
+		}
+	}
+	device.UpdateTime = time.Now().Unix()
+	s.commands[deviceID] = append(s.commands[deviceID], commands...)
+	return true
+}
+
+// writeResult writes a successful Tuya response.
+func writeResult(w http.ResponseWriter, result interface{}) {
+	writeJSON(w, map[string]interface{}{
+		"result":  result,
+		"success": true,
+		"t":       time.Now().UnixMilli(),
+		"tid":     "fake-tuya",
+	})
+}
+
+// writeFailure writes a Tuya error response; like Tuya, errors are returned with HTTP 200.
+func writeFailure(w http.ResponseWriter, code int, msg string) {
+	writeJSON(w, map[string]interface{}{
+		"success": false,
+		"code":    code,
+		"msg":     msg,
+		"t":       time.Now().UnixMilli(),
+		"tid":     "fake-tuya",
+	})
+}
+
+func writeJSON(w http.ResponseWriter, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(body)
+}
@@ -0,0 +1,67 @@
+// Package testutil provides helpers shared by the backend's tests: fixture loading and golden-file comparison.
+package testutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// update rewrites golden files with the current output instead of comparing against them.
+// Run "go test ./... -update" after an intentional change and review the diff.
+var update = flag.Bool("update", false, "rewrite golden files with the current output")
+
+// LoadFixture decodes testdata/fixtures/<name>.json into v.
+//
+// param t The running test.
+// param name The fixture name without extension.
+// param v The value to decode into.
+func LoadFixture(t testing.TB, name string, v interface{}) {
+	t.Helper()
+
+	data, err := os.ReadFile(filepath.Join("testdata", "fixtures", name+".json"))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	if err := json.Unmarshal(data, v); err != nil {
+		t.Fatalf("failed to decode fixture %s: %v", name, err)
+	}
+}
+
+// AssertGolden compares the indented JSON encoding of got with testdata/golden/<name>.json.
+// With -update the golden file is rewritten instead.
+//
+// param t The running test.
+// param name The golden file name without extension.
+// param got The value to compare.
+func AssertGolden(t testing.TB, name string, got interface{}) {
+	t.Helper()
+
+	actual, err := json.MarshalIndent(got, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to encode %s: %v", name, err)
+	}
+	actual = append(actual, '\n')
+
+	path := filepath.Join("testdata", "golden", name+".json")
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatalf("failed to create golden directory: %v", err)
+		}
+		if err := os.WriteFile(path, actual, 0o644); err != nil {
+			t.Fatalf("failed to write golden file %s: %v", path, err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read golden file %s (run with -update to create it): %v", path, err)
+	}
+	if !bytes.Equal(expected, actual) {
+		t.Errorf("%s does not match golden file %s\n--- expected\n%s\n--- actual\n%s", name, path, expected, actual)
+	}
+}
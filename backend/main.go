@@ -1,19 +1,59 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
 	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
 	common_controllers "teralux_app/domain/common/controllers"
 	tuya_controllers "teralux_app/domain/tuya/controllers"
 	"teralux_app/domain/common/infrastructure"
 	"teralux_app/domain/common/middlewares"
+	"teralux_app/domain/common/infrastructure/outbound"
 	common_routes "teralux_app/domain/common/routes"
+	job_controllers "teralux_app/domain/jobs/controllers"
+	job_routes "teralux_app/domain/jobs/routes"
+	job_services "teralux_app/domain/jobs/services"
+	apikey_controllers "teralux_app/domain/apikeys/controllers"
+	apikey_routes "teralux_app/domain/apikeys/routes"
+	apikey_usecases "teralux_app/domain/apikeys/usecases"
+	identity_controllers "teralux_app/domain/identity/controllers"
+	identity_routes "teralux_app/domain/identity/routes"
+	identity_services "teralux_app/domain/identity/services"
+	identity_usecases "teralux_app/domain/identity/usecases"
+	notification_controllers "teralux_app/domain/notifications/controllers"
+	notification_routes "teralux_app/domain/notifications/routes"
+	notification_services "teralux_app/domain/notifications/services"
+	notification_usecases "teralux_app/domain/notifications/usecases"
+	realtime_controllers "teralux_app/domain/realtime/controllers"
+	realtime_routes "teralux_app/domain/realtime/routes"
+	realtime_services "teralux_app/domain/realtime/services"
+	realtime_usecases "teralux_app/domain/realtime/usecases"
+	"teralux_app/domain/teralux/migrations"
+	"teralux_app/domain/tuya/repositories"
 	tuya_routes "teralux_app/domain/tuya/routes"
 	"teralux_app/domain/common/infrastructure/persistence"
+	"teralux_app/domain/common/infrastructure/tracing"
 	"teralux_app/domain/tuya/services"
 	"teralux_app/domain/tuya/usecases"
 	"teralux_app/domain/common/utils"
+	setup_controllers "teralux_app/domain/setup/controllers"
+	setup_routes "teralux_app/domain/setup/routes"
+	setup_usecases "teralux_app/domain/setup/usecases"
+	webhook_controllers "teralux_app/domain/webhooks/controllers"
+	webhook_routes "teralux_app/domain/webhooks/routes"
+	webhook_usecases "teralux_app/domain/webhooks/usecases"
+	"time"
+	// Embedded zone database so TIMEZONE resolves on slim images without tzdata
+	_ "time/tzdata"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 
 	"teralux_app/docs"
 
@@ -61,8 +101,80 @@ import (
 
 // @tag.name 06. Health
 // @tag.description Health check endpoints
+
+// @tag.name 07. Realtime
+// @tag.description Realtime device event subscriptions
+
+// @tag.name 08. Admin
+// @tag.description Administrative endpoints (API key)
+
+// @tag.name 09. Jobs
+// @tag.description Background job status and cancellation
+
+// @tag.name 10. Rooms
+// @tag.description Rooms and room-wide device commands
+
+// @tag.name 11. Automations
+// @tag.description Automation rules triggered by device states
+
+// @tag.name 12. House Mode
+// @tag.description Global house mode (home, away, sleep, holiday)
+
+// @tag.name 13. Bootstrap
+// @tag.description Cold start payload for the app
+
+// @tag.name 17. Identity
+// @tag.description Login through OIDC or LDAP identity providers
+
+// @tag.name 18. Door Locks
+// @tag.description Unlock records and temporary passwords of smart locks
+
+// @tag.name 19. Cameras
+// @tag.description Live streams and snapshots of smart cameras
+
+// @tag.name 21. Scenes
+// @tag.description Whole-house scenes composed across devices and stored by Teralux
 func main() {
 	utils.LoadConfig()
+	if err := utils.ValidateConfig(utils.AppConfig); err != nil {
+		utils.LogError("%v", err)
+		os.Exit(1)
+	}
+
+	listener, err := net.Listen("tcp", ":8080")
+	if err != nil {
+		utils.LogInfo("Failed to start server: %v", err)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		sig := <-quit
+		utils.LogInfo("Received %s, shutting down", sig)
+		cancel()
+	}()
+
+	run(ctx, listener)
+}
+
+// run wires the application and serves it on listener until ctx is cancelled, then shuts down gracefully.
+// The integration tests call it with their own listener, so they exercise exactly this wiring.
+//
+// param ctx The context whose cancellation starts the shutdown.
+// param listener The listener to serve HTTP on.
+func run(ctx context.Context, listener net.Listener) {
+	// INTEGRATION_TEST_MODE runs against a fake Tuya cloud, an in-memory BadgerDB and no MySQL
+	if utils.AppConfig.IntegrationTestMode {
+		stopFakeTuya := startIntegrationTestMode(utils.AppConfig)
+		defer stopFakeTuya()
+	} else if os.Getenv("INTEGRATION_TEST_MODE") == "true" {
+		utils.LogWarn("INTEGRATION_TEST_MODE is ignored: this binary was built without the integration build tag")
+	}
+
+	utils.LogInfo("Using time zone %s for schedules and reports", utils.Location())
 
 	if swaggerURL := utils.AppConfig.SwaggerBaseURL; swaggerURL != "" {
 		parsedURL, err := url.Parse(swaggerURL)
@@ -75,71 +187,498 @@ func main() {
 	}
 
 	// Initialize database connection
-	_, err := infrastructure.InitDB()
+	var db *gorm.DB
+	if !utils.AppConfig.IntegrationTestMode {
+		var err error
+		db, err = infrastructure.InitDB()
+		if err != nil {
+			utils.LogInfo("Warning: Failed to initialize database: %v", err)
+		} else {
+			defer infrastructure.CloseDB()
+			utils.LogInfo("Database initialized successfully")
+			if utils.GetConfig().DBMigrateOnStart {
+				if from, to, err := infrastructure.MigrateUp(db, migrations.Files); err != nil {
+					utils.LogError("Failed to migrate database (schema version %d): %v", to, err)
+				} else if to != from {
+					utils.LogInfo("Database schema migrated from version %d to %d", from, to)
+				}
+			}
+		}
+	}
+
+	// Requests are logged by RequestIDMiddleware through utils, so they follow LOG_FORMAT
+	router := gin.New()
+	router.Use(gin.Recovery())
+	router.Use(middlewares.RequestIDMiddleware())
+	// Traces requests, Tuya calls and BadgerDB operations to OTEL_EXPORTER_OTLP_ENDPOINT when it is set
+	tracing.Init()
+	router.Use(middlewares.TracingMiddleware())
+	// Compresses the final body, so it wraps the middlewares that rewrite responses
+	router.Use(middlewares.CompressionMiddleware())
+	// Strips local_key, ip, uuid and lat/lon from responses when SENSITIVE_FIELDS_MODE asks for it
+	router.Use(middlewares.SensitiveFieldsMiddleware(utils.GetConfig().SensitiveFieldsMode))
+	// Routes are registered under /api; /api/v1 reaches them through VersionedPaths, the unversioned paths are legacy aliases
+	router.Use(middlewares.APIVersionMiddleware())
+	router.Use(middlewares.ResponseMetaMiddleware())
+	router.Use(middlewares.ResponseTransformMiddleware())
+
+	// Sheds low-priority endpoints (history, charts, exports) with 503 while the server is overloaded
+	loadShedder := middlewares.NewLoadShedder()
+	router.Use(loadShedder.Middleware())
+	// Answers the typed errors handlers record with c.Error (Tuya error codes, bad requests, missing resources)
+	router.Use(middlewares.ErrorHandlerMiddleware(tuya_controllers.TuyaErrorResponse))
+
+	// A store that cannot be opened is replaced by an in-memory one, reported as degraded by the health check
+	cacheStore, cacheHealth, err := openCacheStore()
 	if err != nil {
-		utils.LogInfo("Warning: Failed to initialize database: %v", err)
+		utils.LogInfo("Warning: Failed to initialize cache store: %v", err)
 	} else {
-		defer infrastructure.CloseDB()
-		utils.LogInfo("Database initialized successfully")
+		defer cacheStore.Close()
+	}
+	// Replication and value log GC only apply to BadgerDB; a Redis server manages its own storage.
+	// The in-memory fallback is never replicated, so it cannot overwrite the backups of the real store.
+	badgerService, _ := cacheStore.(*persistence.BadgerService)
+	if cacheHealth.Fallback {
+		badgerService = nil
 	}
-
-	router := gin.Default()
 
 	// Health check endpoint
-	healthController := common_controllers.NewHealthController()
+	healthController := common_controllers.NewHealthController(cacheHealth)
 	router.GET("/health", healthController.CheckHealth)
 
-	router.GET("/swagger/*any", func(c *gin.Context) {
-		if c.Param("any") == "" || c.Param("any") == "/" || c.Param("any") == "/index.html" {
-			c.Header("Content-Type", "text/html; charset=utf-8")
-			c.String(200, docs.CustomSwaggerHTML)
-		} else {
-			ginSwagger.WrapHandler(swaggerFiles.Handler)(c)
-		}
-	})
+	replicationService := persistence.NewReplicationService(badgerService)
+	if restored, err := replicationService.RestoreOnStart(); err != nil {
+		utils.LogError("Failed to restore replicated data: %v", err)
+	} else if restored > 0 {
+		utils.LogInfo("Restored %d replicated backups", restored)
+	}
+
+	// Value log garbage collection (BADGER_GC_INTERVAL, on demand via POST /api/cache/gc)
+	badgerMaintenanceService := persistence.NewBadgerMaintenanceService(badgerService)
 
-	badgerService, err := persistence.NewBadgerService("./tmp/badger")
+	// Device states, automations and the audit log: CacheStore keys or SQL tables (PERSISTENCE_BACKEND)
+	repos, err := repositories.New(cacheStore, db)
 	if err != nil {
-		utils.LogInfo("Warning: Failed to initialize BadgerDB: %v", err)
-	} else {
-		defer badgerService.Close()
+		utils.LogError("Failed to initialize repositories: %v", err)
+		repos = &repositories.Repositories{}
 	}
 
-	tuyaAuthService := services.NewTuyaAuthService()
-	tuyaAuthUseCase := usecases.NewTuyaAuthUseCase(tuyaAuthService)
+	// Per-resource cache TTLs (env defaults, runtime overrides via PUT /api/cache/config)
+	cacheTTLPolicy := persistence.NewCacheTTLPolicy(cacheStore)
+
+	clock := utils.NewSystemClock()
+	idGenerator := utils.NewRandomIDGenerator()
+
+	// SSRF guard for destinations admins register (webhooks, MQTT brokers)
+	outboundGuard := outbound.NewGuard()
+
+	// Signs, sends and retries every Tuya OpenAPI request
+	tuyaClient := services.NewTuyaClient(clock, idGenerator)
+
+	tuyaAuthService := services.NewTuyaAuthService(tuyaClient)
+	tuyaAuthUseCase := usecases.NewTuyaAuthUseCase(tuyaAuthService, cacheStore, clock)
+	tuyaSessionUseCase := usecases.NewTuyaSessionUseCase(cacheStore, tuyaAuthUseCase, clock, idGenerator)
 
-	tuyaDeviceService := services.NewTuyaDeviceService()
+	tuyaDeviceService := services.NewTuyaDeviceService(tuyaClient)
+	tuyaSceneService := services.NewTuyaSceneService(tuyaClient)
+	tuyaAssetService := services.NewTuyaAssetService(tuyaClient)
+	tuyaDoorLockService := services.NewTuyaDoorLockService(tuyaClient)
+	tuyaCameraService := services.NewTuyaCameraService(tuyaClient)
+	tuyaEventService := services.NewTuyaEventService()
+
+	// Realtime hub shared by event publishers and websocket subscribers
+	realtimeHub := realtime_services.NewRealtimeHubService()
+
+	// Count every Tuya API call against the daily quota budgets
+	tuyaQuotaUseCase := usecases.NewTuyaQuotaUseCase(cacheStore, realtimeHub, clock)
+	tuyaClient.SetCallRecorder(tuyaQuotaUseCase)
+
+	// Probe each Tuya API family so missing cloud project permissions show up before the first command
+	tuyaPermissionCheckUseCase := usecases.NewTuyaPermissionCheckUseCase(tuyaDeviceService, tuyaAuthUseCase, clock)
+
+	// Managed API keys; work queued on behalf of a caller is authorized against them again when it runs
+	apiKeyUseCase := apikey_usecases.NewAPIKeyUseCase(db, clock, idGenerator)
+	if err := apiKeyUseCase.Init(); err != nil {
+		utils.LogInfo("Warning: Managed API keys unavailable, only API_KEY is accepted: %v", err)
+	}
+
+	// Background job runner for long operations; job types register before Start
+	jobRunner := job_services.NewJobRunnerService(cacheStore, clock, idGenerator)
+	loadShedder.SetQueueDepth(jobRunner.QueueDepth)
 
 	// Initialize Device State UseCase (needed by other use cases)
-	deviceStateUseCase := usecases.NewDeviceStateUseCase(badgerService)
+	deviceStateUseCase := usecases.NewDeviceStateUseCase(repos.DeviceStates, clock)
+
+	deviceChangeLogUseCase := usecases.NewDeviceChangeLogUseCase(cacheStore, clock)
+	auditLogUseCase := usecases.NewAuditLogUseCase(repos.AuditLog, clock, idGenerator)
+	sensorHistoryUseCase := usecases.NewSensorHistoryUseCase(cacheStore, clock)
+	historyArchiveUseCase := usecases.NewHistoryArchiveUseCase(cacheStore, jobRunner, auditLogUseCase, clock)
+	featureFlagUseCase := usecases.NewFeatureFlagUseCase(cacheStore, clock)
+	deviceChannelUseCase := usecases.NewDeviceChannelUseCase(cacheStore)
+	deviceMetadataUseCase := usecases.NewDeviceMetadataUseCase(cacheStore, clock)
+	deviceSpecificationUseCase := usecases.NewDeviceSpecificationUseCase(tuyaDeviceService, cacheStore, cacheTTLPolicy)
+	deviceCategoryFilterUseCase := usecases.NewDeviceCategoryFilterUseCase(cacheStore, clock)
+	tuyaGetAllDevicesUseCase := usecases.NewTuyaGetAllDevicesUseCase(tuyaDeviceService, cacheStore, cacheTTLPolicy, deviceStateUseCase, deviceChangeLogUseCase, deviceChannelUseCase, deviceSpecificationUseCase, deviceCategoryFilterUseCase, deviceMetadataUseCase)
+	tuyaGetDeviceByIDUseCase := usecases.NewTuyaGetDeviceByIDUseCase(tuyaDeviceService, cacheStore, cacheTTLPolicy, deviceStateUseCase, deviceChannelUseCase, deviceMetadataUseCase)
+	commandCooldownUseCase := usecases.NewCommandCooldownUseCase(cacheStore, clock)
+	commandDedupUseCase := usecases.NewCommandDedupUseCase(cacheStore, clock)
+	tuyaDeviceControlUseCase := usecases.NewTuyaDeviceControlUseCase(tuyaDeviceService, deviceStateUseCase, cacheStore, realtimeHub, auditLogUseCase, featureFlagUseCase, commandCooldownUseCase, commandDedupUseCase, clock)
+	commandApprovalUseCase := usecases.NewCommandApprovalUseCase(cacheStore, tuyaDeviceControlUseCase, tuyaGetDeviceByIDUseCase, tuyaAuthUseCase, auditLogUseCase, apiKeyUseCase, clock, idGenerator)
+	tuyaDeviceControlUseCase.SetApprovalGate(commandApprovalUseCase)
+	houseModeUseCase := usecases.NewHouseModeUseCase(cacheStore, realtimeHub, clock)
+	automationUseCase := usecases.NewAutomationUseCase(repos.Automations, tuyaDeviceControlUseCase, tuyaAuthUseCase, apiKeyUseCase, houseModeUseCase, clock, idGenerator)
+	sensorPollerUseCase := usecases.NewSensorPollerUseCase(tuyaDeviceService, tuyaGetDeviceByIDUseCase, tuyaAuthUseCase, automationUseCase, realtimeHub, clock)
+	sensorPollerUseCase.SetInterval(houseModeUseCase.PollInterval(houseModeUseCase.Mode()))
+	houseModeUseCase.OnChange(automationUseCase.HandleHouseModeChange)
+	houseModeUseCase.OnChange(func(_, current string) {
+		sensorPollerUseCase.SetInterval(houseModeUseCase.PollInterval(current))
+	})
+	tuyaSensorUseCase := usecases.NewTuyaSensorUseCase(tuyaGetDeviceByIDUseCase, sensorPollerUseCase, sensorHistoryUseCase, tuyaAuthUseCase, cacheStore, realtimeHub, clock)
+	tuyaIRLearningUseCase := usecases.NewTuyaIRLearningUseCase(tuyaDeviceService, clock)
+	tuyaCloudSceneUseCase := usecases.NewTuyaCloudSceneUseCase(tuyaSceneService, auditLogUseCase)
+	tuyaAssetUseCase := usecases.NewTuyaAssetUseCase(tuyaAssetService, tuyaGetAllDevicesUseCase, cacheStore, cacheTTLPolicy)
+	tuyaDoorLockUseCase := usecases.NewTuyaDoorLockUseCase(tuyaDoorLockService, tuyaGetDeviceByIDUseCase, auditLogUseCase, clock)
+	tuyaCameraUseCase := usecases.NewTuyaCameraUseCase(tuyaCameraService, tuyaGetDeviceByIDUseCase, clock)
+	tuyaIRRemoteUseCase := usecases.NewTuyaIRRemoteUseCase(tuyaDeviceService, cacheStore, cacheTTLPolicy, auditLogUseCase, commandCooldownUseCase, commandDedupUseCase)
+	irLearnedCodeUseCase := usecases.NewIRLearnedCodeUseCase(tuyaDeviceService, tuyaIRLearningUseCase, cacheStore, auditLogUseCase, commandCooldownUseCase, clock)
+	tuyaCommandQueueUseCase := usecases.NewTuyaCommandQueueUseCase(jobRunner, tuyaDeviceControlUseCase, tuyaAuthUseCase, apiKeyUseCase, cacheStore)
+	deviceMacroUseCase := usecases.NewDeviceMacroUseCase(cacheStore, tuyaDeviceControlUseCase, clock)
+	devicePresetUseCase := usecases.NewDevicePresetUseCase(cacheStore, tuyaDeviceControlUseCase, clock)
+	localSceneUseCase := usecases.NewLocalSceneUseCase(cacheStore, jobRunner, devicePresetUseCase, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, tuyaAuthUseCase, apiKeyUseCase, clock, idGenerator)
+	deviceTimerUseCase := usecases.NewDeviceTimerUseCase(cacheStore, jobRunner, deviceSpecificationUseCase, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, tuyaAuthUseCase, clock, idGenerator)
+	tuyaRolloutUseCase := usecases.NewTuyaRolloutUseCase(jobRunner, tuyaDeviceControlUseCase, tuyaAuthUseCase, apiKeyUseCase, cacheStore)
+	tuyaCategoryControlUseCase := usecases.NewTuyaCategoryControlUseCase(deviceSpecificationUseCase, tuyaDeviceControlUseCase)
+	tuyaClimateUseCase := usecases.NewTuyaClimateUseCase(tuyaGetDeviceByIDUseCase, deviceSpecificationUseCase, tuyaDeviceControlUseCase)
+	tuyaDeviceChannelUseCase := usecases.NewTuyaDeviceChannelUseCase(tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, deviceChannelUseCase)
+	tuyaDeviceMetadataUseCase := usecases.NewTuyaDeviceMetadataUseCase(tuyaDeviceService, cacheStore, tuyaGetDeviceByIDUseCase, deviceMetadataUseCase)
+	lightGroupUseCase := usecases.NewLightGroupUseCase(cacheStore, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, tuyaCategoryControlUseCase, clock, idGenerator)
+	circadianUseCase := usecases.NewCircadianUseCase(cacheStore, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, tuyaCategoryControlUseCase, tuyaAuthUseCase, clock)
+	standbyKillerUseCase := usecases.NewStandbyKillerUseCase(cacheStore, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, tuyaCategoryControlUseCase, tuyaAuthUseCase, clock)
+	deviceClaimUseCase := usecases.NewDeviceClaimUseCase(cacheStore, tuyaGetDeviceByIDUseCase, clock, idGenerator)
+	roomUseCase := usecases.NewRoomUseCase(db, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, clock, idGenerator)
+	backupUseCase := usecases.NewBackupUseCase(cacheStore, jobRunner, roomUseCase, clock)
+	cacheWarmUseCase := usecases.NewCacheWarmUseCase(jobRunner, tuyaGetAllDevicesUseCase, deviceSpecificationUseCase, tuyaAuthUseCase)
+	stateRestoreUseCase := usecases.NewStateRestoreUseCase(deviceStateUseCase, tuyaDeviceService, deviceSpecificationUseCase, tuyaDeviceControlUseCase, tuyaAuthUseCase, auditLogUseCase)
+	deviceStateUndoUseCase := usecases.NewDeviceStateUndoUseCase(deviceStateUseCase, tuyaGetDeviceByIDUseCase, deviceSpecificationUseCase, tuyaDeviceControlUseCase)
+	deviceComparisonUseCase := usecases.NewDeviceComparisonUseCase(tuyaDeviceService, deviceSpecificationUseCase, auditLogUseCase, sensorHistoryUseCase, clock)
+	deviceStatusSnapshotUseCase := usecases.NewDeviceStatusSnapshotUseCase(tuyaGetAllDevicesUseCase)
+	acUsageReportUseCase := usecases.NewACUsageReportUseCase(auditLogUseCase, roomUseCase, tuyaGetDeviceByIDUseCase, deviceSpecificationUseCase, deviceStateUseCase, sensorHistoryUseCase, tuyaAuthUseCase, clock)
+	if err := roomUseCase.Init(); err != nil {
+		utils.LogInfo("Warning: Rooms unavailable: %v", err)
+	} else {
+		realtimeHub.SetRoomResolver(roomUseCase.RoomsForDevice)
+	}
+	sceneSwitchUseCase := usecases.NewSceneSwitchUseCase(cacheStore, tuyaGetDeviceByIDUseCase, tuyaDeviceControlUseCase, tuyaAuthUseCase, apiKeyUseCase)
+	sceneSwitchUseCase.RegisterActionHandler("automation", automationUseCase.RunRule)
+	sceneSwitchUseCase.RegisterActionHandler("scene", localSceneUseCase.TriggerScene)
+	tuyaDeviceEventUseCase := usecases.NewTuyaDeviceEventUseCase(deviceStateUseCase, cacheStore, realtimeHub, sceneSwitchUseCase, automationUseCase, clock)
+	favoriteUseCase := usecases.NewFavoriteUseCase(cacheStore)
+	intentUseCase := usecases.NewIntentUseCase(tuyaGetAllDevicesUseCase, roomUseCase, tuyaCategoryControlUseCase, tuyaDeviceControlUseCase)
+	bootstrapUseCase := usecases.NewBootstrapUseCase(tuyaGetAllDevicesUseCase, tuyaSessionUseCase, roomUseCase, favoriteUseCase, tuyaSensorUseCase, houseModeUseCase, clock)
+	mqttBridgeUseCase := usecases.NewMQTTBridgeUseCase(tuyaGetAllDevicesUseCase, tuyaDeviceControlUseCase, tuyaAuthUseCase, realtimeHub, clock)
+	webhookUseCase := webhook_usecases.NewWebhookUseCase(cacheStore, jobRunner, realtimeHub, outboundGuard, deviceClaimUseCase, apiKeyUseCase, clock, idGenerator)
+	notificationSender := notification_services.NewNotificationSenderService(outboundGuard)
+	notificationUseCase := notification_usecases.NewNotificationUseCase(cacheStore, jobRunner, realtimeHub, outboundGuard, deviceClaimUseCase, apiKeyUseCase, notificationSender, clock, idGenerator)
+	realtimeTicketUseCase := realtime_usecases.NewRealtimeTicketUseCase(cacheStore, apiKeyUseCase, idGenerator)
+	tuyaSwaggerExamplesUseCase := usecases.NewTuyaSwaggerExamplesUseCase(tuyaDeviceService, tuyaGetDeviceByIDUseCase, tuyaAuthUseCase, cacheStore, clock)
+	apiKeyUseCase.SetRoomResolver(roomUseCase.RoomsForDevice)
+	var identityProviders []identity_services.AuthProvider
+	if utils.AppConfig.OIDCIssuer != "" {
+		identityProviders = append(identityProviders, identity_services.NewOIDCAuthProvider(utils.AppConfig.OIDCIssuer, utils.AppConfig.OIDCClientID, utils.AppConfig.OIDCGroupsClaim, clock))
+	}
+	if utils.AppConfig.LDAPURL != "" {
+		identityProviders = append(identityProviders, identity_services.NewLDAPAuthProvider(identity_services.LDAPAuthProviderConfig{
+			URL:            utils.AppConfig.LDAPURL,
+			BindDN:         utils.AppConfig.LDAPBindDN,
+			BindPassword:   utils.AppConfig.LDAPBindPassword,
+			BaseDN:         utils.AppConfig.LDAPBaseDN,
+			UserFilter:     utils.AppConfig.LDAPUserFilter,
+			GroupAttribute: utils.AppConfig.LDAPGroupAttribute,
+		}))
+	}
+	identityUseCase := identity_usecases.NewIdentityUseCase(identityProviders, clock)
+	setupUseCase := setup_usecases.NewSetupUseCase(tuyaClient, apiKeyUseCase, idGenerator)
+	if setupUseCase.Required() && setupUseCase.GeneratedToken() {
+		utils.LogWarn("No admin API key exists: complete setup with POST /api/setup and header X-Setup-Token: %s", setupUseCase.SetupToken())
+	} else if setupUseCase.Required() {
+		utils.LogWarn("No admin API key exists: complete setup with POST /api/setup and header X-Setup-Token set to SETUP_TOKEN")
+	}
+
+	// Swagger docs per API version under /swagger/v{N}/; /swagger/ shows the latest version
+	router.GET("/swagger/*any", func(c *gin.Context) {
+		page := c.Param("any")
+		version := utils.APIVersionLatest
+		if segment, rest, found := strings.Cut(strings.TrimPrefix(page, "/"), "/"); found {
+			if requested, ok := utils.ParseAPIVersion(segment); ok && strings.HasPrefix(segment, "v") {
+				if !utils.IsSupportedAPIVersion(requested) {
+					c.String(http.StatusNotFound, "API version %s is not supported", segment)
+					return
+				}
+				version, page = requested, "/"+rest
+			}
+		}
 
-	tuyaGetAllDevicesUseCase := usecases.NewTuyaGetAllDevicesUseCase(tuyaDeviceService, badgerService, deviceStateUseCase)
-	tuyaGetDeviceByIDUseCase := usecases.NewTuyaGetDeviceByIDUseCase(tuyaDeviceService, badgerService, deviceStateUseCase)
-	tuyaDeviceControlUseCase := usecases.NewTuyaDeviceControlUseCase(tuyaDeviceService, deviceStateUseCase, badgerService)
-	tuyaSensorUseCase := usecases.NewTuyaSensorUseCase(tuyaGetDeviceByIDUseCase)
+		if page == "" || page == "/" || page == "/index.html" {
+			c.Header("Content-Type", "text/html; charset=utf-8")
+			c.String(200, docs.CustomSwaggerHTML)
+		} else if page == "/doc.json" {
+			// Serve the doc with device-derived control examples applied, with the paths of the requested version
+			c.Header("Content-Type", "application/json; charset=utf-8")
+			c.String(200, utils.VersionSwaggerDoc(tuyaSwaggerExamplesUseCase.ApplyToSwaggerDoc(docs.SwaggerInfo.ReadDoc()), version))
+		} else {
+			ginSwagger.WrapHandler(swaggerFiles.Handler)(c)
+		}
+	})
 
-	tuyaAuthController := tuya_controllers.NewTuyaAuthController(tuyaAuthUseCase)
-	tuyaGetAllDevicesController := tuya_controllers.NewTuyaGetAllDevicesController(tuyaGetAllDevicesUseCase)
-	tuyaGetDeviceByIDController := tuya_controllers.NewTuyaGetDeviceByIDController(tuyaGetDeviceByIDUseCase)
+	tuyaAuthController := tuya_controllers.NewTuyaAuthController(tuyaAuthUseCase, tuyaSessionUseCase)
+	tuyaGetAllDevicesController := tuya_controllers.NewTuyaGetAllDevicesController(tuyaGetAllDevicesUseCase, deviceClaimUseCase)
+	tuyaGetDeviceByIDController := tuya_controllers.NewTuyaGetDeviceByIDController(tuyaGetDeviceByIDUseCase, deviceClaimUseCase)
 	tuyaDeviceControlController := tuya_controllers.NewTuyaDeviceControlController(tuyaDeviceControlUseCase)
 	tuyaSensorController := tuya_controllers.NewTuyaSensorController(tuyaSensorUseCase)
-	cacheController := common_controllers.NewCacheController(badgerService)
+	tuyaIRLearningController := tuya_controllers.NewTuyaIRLearningController(tuyaIRLearningUseCase)
+	tuyaIRRemoteController := tuya_controllers.NewTuyaIRRemoteController(tuyaIRRemoteUseCase)
+	tuyaIRLearnedCodeController := tuya_controllers.NewTuyaIRLearnedCodeController(irLearnedCodeUseCase)
+	tuyaCloudSceneController := tuya_controllers.NewTuyaCloudSceneController(tuyaCloudSceneUseCase)
+	tuyaAssetController := tuya_controllers.NewTuyaAssetController(tuyaAssetUseCase, deviceClaimUseCase)
+	tuyaDoorLockController := tuya_controllers.NewTuyaDoorLockController(tuyaDoorLockUseCase)
+	tuyaCameraController := tuya_controllers.NewTuyaCameraController(tuyaCameraUseCase)
+	tuyaCommandQueueController := tuya_controllers.NewTuyaCommandQueueController(tuyaCommandQueueUseCase)
+	tuyaDeviceMacroController := tuya_controllers.NewTuyaDeviceMacroController(deviceMacroUseCase)
+	tuyaDevicePresetController := tuya_controllers.NewTuyaDevicePresetController(devicePresetUseCase)
+	tuyaLocalSceneController := tuya_controllers.NewTuyaLocalSceneController(localSceneUseCase)
+	tuyaDeviceTimerController := tuya_controllers.NewTuyaDeviceTimerController(deviceTimerUseCase)
+	tuyaRolloutController := tuya_controllers.NewTuyaRolloutController(tuyaRolloutUseCase)
+	tuyaCategoryControlController := tuya_controllers.NewTuyaCategoryControlController(tuyaCategoryControlUseCase)
+	tuyaClimateController := tuya_controllers.NewTuyaClimateController(tuyaClimateUseCase)
+	tuyaDeviceChannelController := tuya_controllers.NewTuyaDeviceChannelController(tuyaDeviceChannelUseCase)
+	tuyaDeviceMetadataController := tuya_controllers.NewTuyaDeviceMetadataController(tuyaDeviceMetadataUseCase)
+	tuyaLightGroupController := tuya_controllers.NewTuyaLightGroupController(lightGroupUseCase)
+	tuyaCircadianController := tuya_controllers.NewTuyaCircadianController(circadianUseCase)
+	tuyaStandbyKillerController := tuya_controllers.NewTuyaStandbyKillerController(standbyKillerUseCase)
+	tuyaSceneSwitchController := tuya_controllers.NewTuyaSceneSwitchController(sceneSwitchUseCase)
+	tuyaRoomController := tuya_controllers.NewTuyaRoomController(roomUseCase, deviceClaimUseCase)
+	tuyaACUsageReportController := tuya_controllers.NewTuyaACUsageReportController(acUsageReportUseCase)
+	tuyaAutomationController := tuya_controllers.NewTuyaAutomationController(automationUseCase)
+	tuyaHouseModeController := tuya_controllers.NewTuyaHouseModeController(houseModeUseCase)
+	tuyaBootstrapController := tuya_controllers.NewTuyaBootstrapController(bootstrapUseCase, favoriteUseCase, deviceClaimUseCase)
+	tuyaDeviceClaimController := tuya_controllers.NewTuyaDeviceClaimController(deviceClaimUseCase)
+	tuyaIntentController := tuya_controllers.NewTuyaIntentController(intentUseCase, deviceClaimUseCase)
+	tuyaCommandApprovalController := tuya_controllers.NewTuyaCommandApprovalController(commandApprovalUseCase, deviceClaimUseCase)
+	tuyaDeviceChangeLogController := tuya_controllers.NewTuyaDeviceChangeLogController(deviceChangeLogUseCase)
+	tuyaDeviceComparisonController := tuya_controllers.NewTuyaDeviceComparisonController(deviceComparisonUseCase, deviceClaimUseCase)
+	tuyaDeviceStatusSnapshotController := tuya_controllers.NewTuyaDeviceStatusSnapshotController(deviceStatusSnapshotUseCase, deviceClaimUseCase)
+	tuyaDeviceStateController := tuya_controllers.NewTuyaDeviceStateController(deviceStateUndoUseCase, deviceClaimUseCase)
+	cacheController := common_controllers.NewCacheController(cacheStore, cacheTTLPolicy, badgerMaintenanceService)
+	replicationController := common_controllers.NewReplicationController(replicationService)
+	outboundController := common_controllers.NewOutboundController(outboundGuard)
+	loadController := common_controllers.NewLoadController(loadShedder)
+	configController := common_controllers.NewConfigController()
+	serializationController := common_controllers.NewSerializationController()
+	tuyaArchiveController := tuya_controllers.NewTuyaArchiveController(historyArchiveUseCase)
+	tuyaBackupController := tuya_controllers.NewTuyaBackupController(backupUseCase)
+	tuyaCacheWarmController := tuya_controllers.NewTuyaCacheWarmController(cacheWarmUseCase)
+	tuyaFeatureFlagController := tuya_controllers.NewTuyaFeatureFlagController(featureFlagUseCase)
+	tuyaDeviceCategoryFilterController := tuya_controllers.NewTuyaDeviceCategoryFilterController(deviceCategoryFilterUseCase)
+	tuyaCommandCooldownController := tuya_controllers.NewTuyaCommandCooldownController(commandCooldownUseCase)
+	tuyaQuotaController := tuya_controllers.NewTuyaQuotaController(tuyaQuotaUseCase)
+	tuyaPermissionCheckController := tuya_controllers.NewTuyaPermissionCheckController(tuyaPermissionCheckUseCase)
+	tuyaMQTTBridgeController := tuya_controllers.NewTuyaMQTTBridgeController(mqttBridgeUseCase)
+	realtimeController := realtime_controllers.NewRealtimeController(realtimeHub, realtimeTicketUseCase)
+	socketIOController := realtime_controllers.NewSocketIOController(realtimeHub, idGenerator)
+	tuyaSwaggerExamplesController := tuya_controllers.NewTuyaSwaggerExamplesController(tuyaSwaggerExamplesUseCase)
+	jobController := job_controllers.NewJobController(jobRunner)
+	webhookController := webhook_controllers.NewWebhookController(webhookUseCase)
+	notificationController := notification_controllers.NewNotificationController(notificationUseCase)
+	setupController := setup_controllers.NewSetupController(setupUseCase)
+	apiKeyController := apikey_controllers.NewAPIKeyController(apiKeyUseCase)
+	identityController := identity_controllers.NewIdentityController(identityUseCase)
+
+	setup_routes.SetupSetupRoutes(router, setupController)
+	identity_routes.SetupIdentityRoutes(router, identityController)
+
+	// Any valid API key may authenticate; sessions keep the scope of the key that created them
+	keyGroup := router.Group("/")
+	keyGroup.Use(middlewares.ApiKeyMiddleware(apiKeyUseCase, identityUseCase, utils.APIKeyScopeReadOnly))
+	tuya_routes.SetupTuyaAuthRoutes(keyGroup, tuyaAuthController)
 
 	authGroup := router.Group("/")
-	authGroup.Use(middlewares.ApiKeyMiddleware())
-	tuya_routes.SetupTuyaAuthRoutes(authGroup, tuyaAuthController)
+	authGroup.Use(middlewares.ApiKeyMiddleware(apiKeyUseCase, identityUseCase, utils.APIKeyScopeAdmin))
+	apikey_routes.SetupAPIKeyRoutes(authGroup, apiKeyController)
+	tuya_routes.SetupTuyaAdminRoutes(authGroup, tuyaSwaggerExamplesController)
+	common_routes.SetupReplicationRoutes(authGroup, replicationController)
+	common_routes.SetupOutboundRoutes(authGroup, outboundController)
+	common_routes.SetupLoadRoutes(authGroup, loadController)
+	common_routes.SetupConfigRoutes(authGroup, configController)
+	common_routes.SetupHealthRoutes(authGroup, healthController)
+	common_routes.SetupSerializationRoutes(authGroup, serializationController)
+	common_routes.SetupCacheAdminRoutes(authGroup, cacheController)
+	tuya_routes.SetupTuyaArchiveRoutes(authGroup, tuyaArchiveController)
+	tuya_routes.SetupTuyaBackupRoutes(authGroup, tuyaBackupController)
+	tuya_routes.SetupTuyaCacheWarmRoutes(authGroup, tuyaCacheWarmController)
+	tuya_routes.SetupTuyaFeatureFlagRoutes(authGroup, tuyaFeatureFlagController)
+	tuya_routes.SetupTuyaDeviceCategoryFilterRoutes(authGroup, tuyaDeviceCategoryFilterController)
+	tuya_routes.SetupTuyaCommandCooldownRoutes(authGroup, tuyaCommandCooldownController)
+	tuya_routes.SetupTuyaQuotaRoutes(authGroup, tuyaQuotaController)
+	tuya_routes.SetupTuyaPermissionCheckRoutes(authGroup, tuyaPermissionCheckController)
+	tuya_routes.SetupDeviceClaimAdminRoutes(authGroup, tuyaDeviceClaimController)
+	tuya_routes.SetupCommandApprovalAdminRoutes(authGroup, tuyaCommandApprovalController)
+	tuya_routes.SetupTuyaMQTTBridgeRoutes(authGroup, tuyaMQTTBridgeController)
+	tuya_routes.SetupTuyaACUsageReportRoutes(authGroup, tuyaACUsageReportController)
+
+	// Webhooks and notification rules receive device events outside any request, so they belong to a verified API key or user
+	controlGroup := router.Group("/")
+	controlGroup.Use(middlewares.ApiKeyMiddleware(apiKeyUseCase, identityUseCase, utils.APIKeyScopeControl))
+	webhook_routes.SetupWebhookRoutes(controlGroup, webhookController)
+	notification_routes.SetupNotificationRoutes(controlGroup, notificationController)
 
 	protected := router.Group("/")
-	protected.Use(middlewares.AuthMiddleware())
-	protected.Use(middlewares.TuyaErrorMiddleware())
+	protected.Use(middlewares.AuthMiddleware(tuyaSessionUseCase, tuyaAuthUseCase, apiKeyUseCase, realtimeTicketUseCase))
+	protected.Use(middlewares.DeviceAccessMiddleware(apiKeyUseCase))
 	{
-		tuya_routes.SetupTuyaDeviceRoutes(protected, tuyaGetAllDevicesController, tuyaGetDeviceByIDController, tuyaSensorController)
+		tuya_routes.SetupTuyaSessionRoutes(protected, tuyaAuthController)
+		tuya_routes.SetupTuyaDeviceRoutes(protected, tuyaGetAllDevicesController, tuyaGetDeviceByIDController, tuyaSensorController, tuyaDeviceChangeLogController, tuyaDeviceComparisonController, tuyaDeviceStatusSnapshotController)
 		tuya_routes.SetupTuyaControlRoutes(protected, tuyaDeviceControlController)
+		tuya_routes.SetupTuyaIRLearningRoutes(protected, tuyaIRLearningController)
+		tuya_routes.SetupTuyaIRRemoteRoutes(protected, tuyaIRRemoteController)
+		tuya_routes.SetupTuyaIRLearnedCodeRoutes(protected, tuyaIRLearnedCodeController)
+		tuya_routes.SetupTuyaCloudSceneRoutes(protected, tuyaCloudSceneController)
+		tuya_routes.SetupTuyaAssetRoutes(protected, tuyaAssetController)
+		tuya_routes.SetupTuyaDoorLockRoutes(protected, tuyaDoorLockController)
+		tuya_routes.SetupTuyaCameraRoutes(protected, tuyaCameraController)
+		tuya_routes.SetupTuyaCommandQueueRoutes(protected, tuyaCommandQueueController)
+		tuya_routes.SetupTuyaDeviceMacroRoutes(protected, tuyaDeviceMacroController)
+		tuya_routes.SetupTuyaDevicePresetRoutes(protected, tuyaDevicePresetController)
+		tuya_routes.SetupTuyaLocalSceneRoutes(protected, tuyaLocalSceneController)
+		tuya_routes.SetupTuyaDeviceTimerRoutes(protected, tuyaDeviceTimerController)
+		tuya_routes.SetupTuyaDeviceStateRoutes(protected, tuyaDeviceStateController)
+		tuya_routes.SetupTuyaRolloutRoutes(protected, tuyaRolloutController)
+		tuya_routes.SetupTuyaCategoryControlRoutes(protected, tuyaCategoryControlController)
+		tuya_routes.SetupTuyaClimateRoutes(protected, tuyaClimateController)
+		tuya_routes.SetupTuyaSceneSwitchRoutes(protected, tuyaSceneSwitchController)
+		tuya_routes.SetupTuyaDeviceChannelRoutes(protected, tuyaDeviceChannelController)
+		tuya_routes.SetupTuyaDeviceMetadataRoutes(protected, tuyaDeviceMetadataController)
+		tuya_routes.SetupTuyaLightGroupRoutes(protected, tuyaLightGroupController)
+		tuya_routes.SetupTuyaCircadianRoutes(protected, tuyaCircadianController)
+		tuya_routes.SetupTuyaStandbyKillerRoutes(protected, tuyaStandbyKillerController)
+		tuya_routes.SetupTuyaRoomRoutes(protected, tuyaRoomController)
+		tuya_routes.SetupTuyaAutomationRoutes(protected, tuyaAutomationController)
+		tuya_routes.SetupTuyaHouseModeRoutes(protected, tuyaHouseModeController)
+		tuya_routes.SetupTuyaBootstrapRoutes(protected, tuyaBootstrapController)
+		tuya_routes.SetupTuyaDeviceClaimRoutes(protected, tuyaDeviceClaimController)
+		tuya_routes.SetupTuyaIntentRoutes(protected, tuyaIntentController)
+		tuya_routes.SetupTuyaCommandApprovalRoutes(protected, tuyaCommandApprovalController)
 		common_routes.SetupCacheRoutes(protected, cacheController)
+		realtime_routes.SetupRealtimeRoutes(protected, realtimeController)
+		realtime_routes.SetupSocketIORoutes(protected, socketIOController)
+		job_routes.SetupJobRoutes(protected, jobController)
 	}
+
+	jobRunner.Start()
+	tuyaAuthUseCase.Start()
+	replicationService.Start()
+	badgerMaintenanceService.Start()
+	circadianUseCase.Start()
+	standbyKillerUseCase.Start()
+	sensorPollerUseCase.Start()
+	tuyaSensorUseCase.Start()
+	historyArchiveUseCase.Start()
+	automationUseCase.Start()
+	tuyaQuotaUseCase.Start()
+	tuyaPermissionCheckUseCase.Start()
+	webhookUseCase.Start()
+	notificationUseCase.Start()
+	mqttBridgeUseCase.Start()
+	stateRestoreUseCase.Start()
+	tuyaEventService.Start(tuyaDeviceEventUseCase.HandleEvent)
 	
-	utils.LogInfo("Server starting on :8080")
-	if err := router.Run(":8080"); err != nil {
+	server := &http.Server{
+		Handler: middlewares.VersionedPaths(router),
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		utils.LogInfo("Server starting on %s", listener.Addr())
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	select {
+	case <-ctx.Done():
+	case err := <-serverErr:
 		utils.LogInfo("Failed to start server: %v", err)
 	}
-}
\ No newline at end of file
+
+	shutdownTimeout, err := time.ParseDuration(utils.GetConfig().ShutdownTimeout)
+	if err != nil || shutdownTimeout <= 0 {
+		shutdownTimeout = defaultShutdownTimeout
+	}
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	// Stop accepting requests first, then stop the producers of device events before the consumers,
+	// so nothing writes to BadgerDB once the deferred closes run.
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		utils.LogWarn("HTTP server did not shut down cleanly: %v", err)
+	}
+	stopWorkers(shutdownCtx,
+		tuyaEventService.Stop,
+		sensorPollerUseCase.Stop,
+		tuyaSensorUseCase.Stop,
+		circadianUseCase.Stop,
+		standbyKillerUseCase.Stop,
+		automationUseCase.Stop,
+		webhookUseCase.Stop,
+		notificationUseCase.Stop,
+		mqttBridgeUseCase.Stop,
+		stateRestoreUseCase.Stop,
+		jobRunner.Stop,
+		historyArchiveUseCase.Stop,
+		replicationService.Stop,
+		badgerMaintenanceService.Stop,
+		tuyaPermissionCheckUseCase.Stop,
+		tuyaAuthUseCase.Stop,
+		tuyaQuotaUseCase.Stop,
+		tracing.Stop,
+	)
+	utils.LogInfo("Server stopped")
+}
+
+// defaultShutdownTimeout bounds graceful shutdown when SHUTDOWN_TIMEOUT is not set.
+const defaultShutdownTimeout = 15 * time.Second
+
+// stopWorkers stops background workers in the given order.
+// It gives up once ctx expires, so a stuck worker cannot keep the process from closing its stores.
+//
+// param ctx The shutdown context bounding how long to wait.
+// param stops The Stop functions of the workers, in teardown order.
+func stopWorkers(ctx context.Context, stops ...func()) {
+	done := make(chan struct{})
+	go func() {
+		for _, stop := range stops {
+			stop()
+		}
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		utils.LogWarn("Timed out waiting for background workers to stop")
+	}
+}
+
+// openCacheStore opens the cache store: BadgerDB in memory in the integration test mode, else the backend
+// selected by CACHE_BACKEND (BadgerDB under ./tmp/badger by default), falling back to memory when it
+// cannot be opened.
+func openCacheStore() (persistence.CacheStore, persistence.CacheStoreHealth, error) {
+	if utils.GetConfig().IntegrationTestMode {
+		health := persistence.CacheStoreHealth{Backend: persistence.CacheBackendBadger}
+		store, err := persistence.NewInMemoryBadgerService()
+		if err != nil {
+			return nil, health, err
+		}
+		return store, health, nil
+	}
+	return persistence.OpenCacheStoreWithFallback("./tmp/badger")
+}
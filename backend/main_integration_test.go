@@ -0,0 +1,228 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"teralux_app/domain/common/utils"
+	"teralux_app/domain/tuya/dtos"
+)
+
+// integrationServer boots the application exactly as main does, in INTEGRATION_TEST_MODE, on a free port.
+// Run with: go test -tags integration -run Integration .
+func integrationServer(t *testing.T) string {
+	t.Helper()
+
+	os.Setenv("INTEGRATION_TEST_MODE", "true")
+	os.Setenv("API_KEY", integrationTestAPIKey)
+	return startIntegrationServer(t)
+}
+
+// startIntegrationServer loads the configuration from the environment and boots the application.
+func startIntegrationServer(t *testing.T) string {
+	t.Helper()
+
+	utils.LoadConfig()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		run(ctx, listener)
+		close(done)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	baseURL := "http://" + listener.Addr().String()
+	for deadline := time.Now().Add(10 * time.Second); ; {
+		resp, err := http.Get(baseURL + "/health")
+		if err == nil {
+			resp.Body.Close()
+			return baseURL
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("server did not become healthy: %v", err)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
+
+// call sends a request with the API key (and the access token, when given) and decodes the response data.
+func call(t *testing.T, method, url, token string, body interface{}, data interface{}) int {
+	t.Helper()
+
+	var reader *bytes.Reader
+	if body != nil {
+		encoded, _ := json.Marshal(body)
+		reader = bytes.NewReader(encoded)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, _ := http.NewRequest(method, url, reader)
+	req.Header.Set("X-API-KEY", integrationTestAPIKey)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	var envelope struct {
+		Status  bool            `json:"status"`
+		Message string          `json:"message"`
+		Data    json.RawMessage `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&envelope); err != nil {
+		t.Fatalf("%s %s returned an undecodable body: %v", method, url, err)
+	}
+	if data != nil && len(envelope.Data) > 0 {
+		if err := json.Unmarshal(envelope.Data, data); err != nil {
+			t.Fatalf("%s %s returned unexpected data: %v", method, url, err)
+		}
+	}
+	return resp.StatusCode
+}
+
+func TestIntegrationAuthListControlState(t *testing.T) {
+	baseURL := integrationServer(t)
+
+	var auth dtos.TuyaAuthResponseDTO
+	if status := call(t, http.MethodGet, baseURL+"/api/v1/tuya/auth", "", nil, &auth); status != http.StatusOK || auth.AccessToken == "" {
+		t.Fatalf("auth: status %d, token %q", status, auth.AccessToken)
+	}
+
+	var list dtos.TuyaDevicesResponseDTO
+	if status := call(t, http.MethodGet, baseURL+"/api/v1/tuya/devices", auth.AccessToken, nil, &list); status != http.StatusOK {
+		t.Fatalf("list: status %d", status)
+	}
+	if !containsDevice(list.Devices, "fake-switch-1") {
+		t.Fatalf("list: fake-switch-1 missing from %d devices", len(list.Devices))
+	}
+
+	command := dtos.TuyaCommandDTO{Code: "switch_1", Value: true}
+	if status := call(t, http.MethodPost, baseURL+"/api/v1/tuya/devices/fake-switch-1/commands/switch", auth.AccessToken, command, nil); status != http.StatusOK {
+		t.Fatalf("control: status %d", status)
+	}
+
+	var detail dtos.TuyaDeviceResponseDTO
+	if status := call(t, http.MethodGet, baseURL+"/api/v1/tuya/devices/fake-switch-1", auth.AccessToken, nil, &detail); status != http.StatusOK {
+		t.Fatalf("state: status %d", status)
+	}
+	if value := statusValue(detail.Device, "switch_1"); value != true {
+		t.Fatalf("state: switch_1 is %v after switching on", value)
+	}
+}
+
+func TestIntegrationAPIVersions(t *testing.T) {
+	baseURL := integrationServer(t)
+
+	var auth dtos.TuyaAuthResponseDTO
+	if status := call(t, http.MethodGet, baseURL+"/api/tuya/auth", "", nil, &auth); status != http.StatusOK || auth.AccessToken == "" {
+		t.Fatalf("legacy alias: status %d, token %q", status, auth.AccessToken)
+	}
+	if status := call(t, http.MethodGet, baseURL+"/api/v2/tuya/auth", "", nil, nil); status != http.StatusNotFound {
+		t.Fatalf("unsupported version: expected 404, got %d", status)
+	}
+}
+
+func TestIntegrationRejectsMissingAPIKey(t *testing.T) {
+	baseURL := integrationServer(t)
+
+	resp, err := http.Get(baseURL + "/api/tuya/auth")
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Fatalf("expected 401 without an API key, got %d", resp.StatusCode)
+	}
+}
+
+func TestIntegrationSetupThenListDevices(t *testing.T) {
+	t.Chdir(t.TempDir())
+	t.Setenv("INTEGRATION_TEST_MODE", "true")
+	t.Setenv("INTEGRATION_TEST_FRESH_INSTALL", "true")
+	t.Setenv("API_KEY", "")
+	t.Setenv("TUYA_USER_ID", "")
+	t.Setenv("SETUP_TOKEN", "integration-setup-token")
+	baseURL := startIntegrationServer(t)
+
+	request := func(method, url string, headers map[string]string, body interface{}, data interface{}) int {
+		t.Helper()
+		encoded, _ := json.Marshal(body)
+		req, _ := http.NewRequest(method, url, bytes.NewReader(encoded))
+		req.Header.Set("Content-Type", "application/json")
+		for name, value := range headers {
+			req.Header.Set(name, value)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", method, url, err)
+		}
+		defer resp.Body.Close()
+		var envelope struct {
+			Data json.RawMessage `json:"data"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&envelope); err == nil && data != nil && len(envelope.Data) > 0 {
+			json.Unmarshal(envelope.Data, data)
+		}
+		return resp.StatusCode
+	}
+
+	var setup struct {
+		ApiKey string `json:"api_key"`
+	}
+	if status := request(http.MethodPost, baseURL+"/api/setup", map[string]string{"X-Setup-Token": "integration-setup-token"}, map[string]string{}, &setup); status != http.StatusCreated || setup.ApiKey == "" {
+		t.Fatalf("setup: status %d", status)
+	}
+
+	var auth dtos.TuyaAuthResponseDTO
+	if status := request(http.MethodGet, baseURL+"/api/tuya/auth", map[string]string{"X-API-KEY": setup.ApiKey}, nil, &auth); status != http.StatusOK || auth.AccessToken == "" {
+		t.Fatalf("auth: status %d, token %q", status, auth.AccessToken)
+	}
+
+	var list dtos.TuyaDevicesResponseDTO
+	headers := map[string]string{"X-API-KEY": setup.ApiKey, "Authorization": "Bearer " + auth.AccessToken}
+	if status := request(http.MethodGet, baseURL+"/api/tuya/devices", headers, nil, &list); status != http.StatusOK {
+		t.Fatalf("list after setup: status %d", status)
+	}
+	if !containsDevice(list.Devices, "fake-switch-1") {
+		t.Fatalf("list after setup: fake-switch-1 missing from %d devices", len(list.Devices))
+	}
+}
+
+func containsDevice(devices []dtos.TuyaDeviceDTO, id string) bool {
+	for _, device := range devices {
+		if device.ID == id || containsDevice(device.Collections, id) {
+			return true
+		}
+	}
+	return false
+}
+
+func statusValue(device dtos.TuyaDeviceDTO, code string) interface{} {
+	for _, status := range device.Status {
+		if status.Code == code {
+			return status.Value
+		}
+	}
+	return fmt.Sprintf("<no %s status>", code)
+}